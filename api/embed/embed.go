@@ -11,9 +11,12 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/export"
 	"github.com/jeff/oaks/api/internal/handlers"
 )
 
@@ -22,6 +25,7 @@ type Server struct {
 	server     *handlers.Server
 	httpServer *http.Server
 	listener   net.Listener
+	socketDir  string // Private directory holding the unix socket, if any
 	url        string
 	apiKey     string
 	logger     *slog.Logger
@@ -35,10 +39,31 @@ type Config struct {
 
 	// Quiet suppresses server startup/shutdown messages.
 	Quiet bool
+
+	// ExportMappingsDir is an optional directory of YAML export mappings,
+	// as with the server's OAK_EXPORT_MAPPINGS.
+	ExportMappingsDir string
+
+	// MediaDir holds uploaded species photos, as with the server's
+	// OAK_MEDIA_DIR. Defaults to media beside the database.
+	MediaDir string
+
+	// UnixSocket serves on a unix socket in a private directory instead of
+	// a loopback TCP port, so other users on the machine cannot connect.
+	// Clients connect through Transport.
+	UnixSocket bool
 }
 
-// Start creates and starts an embedded API server on a random localhost port.
+// socketHost is the placeholder host in the URL of a unix-socket server.
+// Transport dials the socket whatever the host.
+const socketHost = "oak-embedded"
+
+// Start creates and starts an embedded API server on a random localhost port,
+// or on a unix socket with Config.UnixSocket.
 // Returns the server instance which provides the URL and API key for connecting.
+//
+// The API key is generated for this server and held only in memory; it is
+// never written to disk or logged, and dies with the process.
 func Start(cfg Config) (*Server, error) {
 	// Generate a session-specific API key
 	apiKey, err := generateSessionKey()
@@ -54,11 +79,19 @@ func Start(cfg Config) (*Server, error) {
 		logger = slog.Default()
 	}
 
+	var mappings map[string]*export.Mapping
+	if cfg.ExportMappingsDir != "" {
+		if mappings, err = export.LoadMappings(cfg.ExportMappingsDir); err != nil {
+			return nil, err
+		}
+	}
+
 	// Open database connection
 	database, err := db.New(cfg.DBPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	database.SetLogger(logger)
 
 	// Create server with embedded-friendly configuration
 	versionInfo := handlers.VersionInfo{
@@ -67,30 +100,38 @@ func Start(cfg Config) (*Server, error) {
 	}
 
 	// Use minimal middleware for embedded mode (skip rate limiting, logging, etc.)
-	server := handlers.New(database, apiKey, logger, versionInfo, handlers.WithoutMiddleware())
-
-	// Listen on a random localhost port
-	listener, err := net.Listen("tcp", "127.0.0.1:0")
-	if err != nil {
-		database.Close()
-		return nil, fmt.Errorf("failed to listen on localhost: %w", err)
+	// The session key is new each run, so its usage is not worth counting
+	mediaDir := cfg.MediaDir
+	if mediaDir == "" {
+		mediaDir = filepath.Join(filepath.Dir(cfg.DBPath), "media")
 	}
-
-	addr := listener.Addr().(*net.TCPAddr)
-	url := fmt.Sprintf("http://127.0.0.1:%d", addr.Port)
+	server := handlers.New(database, apiKey, logger, versionInfo, handlers.WithoutMiddleware(),
+		handlers.WithoutKeyUsage(), handlers.WithExportMappings(mappings), handlers.WithMediaDir(mediaDir))
 
 	embedded := &Server{
-		server:   server,
-		listener: listener,
-		url:      url,
-		apiKey:   apiKey,
-		logger:   logger,
-		errChan:  make(chan error, 1),
+		server:  server,
+		apiKey:  apiKey,
+		logger:  logger,
+		errChan: make(chan error, 1),
+	}
+
+	var handler http.Handler = server.Router()
+	if cfg.UnixSocket {
+		err = embedded.listenUnix()
+	} else {
+		err = embedded.listenLoopback()
+		// Refuse anything that reaches the port from off the machine
+		handler = loopbackOnly(handler)
+	}
+	if err != nil {
+		embedded.removeSocketDir()
+		database.Close()
+		return nil, err
 	}
 
 	// Create HTTP server
 	embedded.httpServer = &http.Server{
-		Handler:      server.Router(),
+		Handler:      handler,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -98,7 +139,7 @@ func Start(cfg Config) (*Server, error) {
 
 	// Start serving in background
 	go func() {
-		if err := embedded.httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+		if err := embedded.httpServer.Serve(embedded.listener); err != nil && err != http.ErrServerClosed {
 			embedded.errChan <- err
 		}
 	}()
@@ -112,7 +153,69 @@ func Start(cfg Config) (*Server, error) {
 	return embedded, nil
 }
 
-// URL returns the localhost URL for connecting to the embedded server.
+// listenLoopback listens on a random loopback TCP port.
+func (s *Server) listenLoopback() error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("failed to listen on localhost: %w", err)
+	}
+	if err := checkLoopback(listener.Addr()); err != nil {
+		listener.Close()
+		return err
+	}
+
+	s.listener = listener
+	s.url = fmt.Sprintf("http://%s", listener.Addr())
+	return nil
+}
+
+// listenUnix listens on a unix socket in a new directory only the current
+// user can enter.
+func (s *Server) listenUnix() error {
+	dir, err := os.MkdirTemp("", "oak-embed-")
+	if err != nil {
+		return fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	s.socketDir = dir
+	// MkdirTemp creates 0700 directories, but make sure under any umask
+	if err := os.Chmod(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to restrict socket directory: %w", err)
+	}
+
+	listener, err := net.Listen("unix", filepath.Join(dir, "api.sock"))
+	if err != nil {
+		return fmt.Errorf("failed to listen on unix socket: %w", err)
+	}
+
+	s.listener = listener
+	s.url = "http://" + socketHost
+	return nil
+}
+
+// checkLoopback returns an error unless addr is a loopback TCP address.
+func checkLoopback(addr net.Addr) error {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok || !tcp.IP.IsLoopback() {
+		return fmt.Errorf("embedded server must listen on loopback, not %s", addr)
+	}
+	return nil
+}
+
+// loopbackOnly rejects requests from non-loopback addresses. The listener is
+// bound to loopback already; this guards against that ever changing.
+func loopbackOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if ip := net.ParseIP(host); err != nil || ip == nil || !ip.IsLoopback() {
+			handlers.RespondForbidden(w, "The embedded server only accepts loopback connections")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// URL returns the URL for connecting to the embedded server. For a unix
+// socket the host is a placeholder; requests must go through Transport.
 func (s *Server) URL() string {
 	return s.url
 }
@@ -122,10 +225,33 @@ func (s *Server) APIKey() string {
 	return s.apiKey
 }
 
+// Transport returns an HTTP transport that connects to the server's unix
+// socket, or nil (the default transport) when it serves on TCP.
+func (s *Server) Transport() http.RoundTripper {
+	if s.socketDir == "" {
+		return nil
+	}
+	socket := s.listener.Addr().String()
+	return &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		},
+	}
+}
+
+// removeSocketDir removes the unix socket and its directory, if any.
+func (s *Server) removeSocketDir() {
+	if s.socketDir != "" {
+		os.RemoveAll(s.socketDir)
+	}
+}
+
 // Shutdown gracefully shuts down the embedded server.
 func (s *Server) Shutdown() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	defer s.removeSocketDir()
 
 	// Shutdown the HTTP server
 	if s.httpServer != nil {
@@ -144,7 +270,7 @@ func (s *Server) Shutdown() error {
 
 // waitForReady polls the health endpoint until the server is ready.
 func (s *Server) waitForReady() error {
-	client := &http.Client{Timeout: time.Second}
+	client := &http.Client{Timeout: time.Second, Transport: s.Transport()}
 
 	for i := 0; i < 50; i++ { // 50 * 10ms = 500ms max wait
 		resp, err := client.Get(s.url + "/health")
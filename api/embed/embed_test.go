@@ -1,9 +1,13 @@
 package embed
 
 import (
+	"bytes"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -123,3 +127,156 @@ func TestGenerateSessionKey(t *testing.T) {
 		t.Errorf("expected key length 44, got %d", len(key1))
 	}
 }
+
+func TestUnixSocket(t *testing.T) {
+	tmpDir := t.TempDir()
+	server, err := Start(Config{
+		DBPath:     filepath.Join(tmpDir, "test.db"),
+		Quiet:      true,
+		UnixSocket: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start embedded server: %v", err)
+	}
+
+	// The socket directory must be private to the current user
+	info, err := os.Stat(server.socketDir)
+	if err != nil {
+		t.Fatalf("failed to stat socket directory: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o700 {
+		t.Errorf("expected socket directory mode 0700, got %o", perm)
+	}
+
+	client := &http.Client{Transport: server.Transport()}
+	resp, err := client.Get(server.URL() + "/health")
+	if err != nil {
+		t.Fatalf("failed to call health endpoint over socket: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	if err := server.Shutdown(); err != nil {
+		t.Errorf("failed to shutdown server: %v", err)
+	}
+	if _, err := os.Stat(server.socketDir); !os.IsNotExist(err) {
+		t.Error("expected socket directory to be removed on shutdown")
+	}
+}
+
+func TestLoopbackBind(t *testing.T) {
+	server, err := Start(Config{DBPath: filepath.Join(t.TempDir(), "test.db"), Quiet: true})
+	if err != nil {
+		t.Fatalf("failed to start embedded server: %v", err)
+	}
+	defer server.Shutdown()
+
+	if err := checkLoopback(server.listener.Addr()); err != nil {
+		t.Errorf("expected loopback listener: %v", err)
+	}
+	if !strings.HasPrefix(server.URL(), "http://127.0.0.1:") {
+		t.Errorf("expected loopback URL, got %s", server.URL())
+	}
+	if server.Transport() != nil {
+		t.Error("expected default transport for TCP server")
+	}
+}
+
+func TestCheckLoopback(t *testing.T) {
+	tests := []struct {
+		addr net.Addr
+		ok   bool
+	}{
+		{&net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 8080}, true},
+		{&net.TCPAddr{IP: net.ParseIP("::1"), Port: 8080}, true},
+		{&net.TCPAddr{IP: net.ParseIP("0.0.0.0"), Port: 8080}, false},
+		{&net.TCPAddr{IP: net.ParseIP("192.168.1.5"), Port: 8080}, false},
+		{&net.UnixAddr{Name: "/tmp/x.sock", Net: "unix"}, false},
+	}
+	for _, tt := range tests {
+		if err := checkLoopback(tt.addr); (err == nil) != tt.ok {
+			t.Errorf("checkLoopback(%s) error = %v, want ok %v", tt.addr, err, tt.ok)
+		}
+	}
+}
+
+func TestLoopbackOnly(t *testing.T) {
+	handler := loopbackOnly(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		remoteAddr string
+		want       int
+	}{
+		{"127.0.0.1:51000", http.StatusOK},
+		{"[::1]:51000", http.StatusOK},
+		{"10.0.0.7:51000", http.StatusForbidden},
+		{"garbage", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/health", nil)
+		req.RemoteAddr = tt.remoteAddr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("remote %s: expected %d, got %d", tt.remoteAddr, tt.want, w.Code)
+		}
+		if tt.want == http.StatusForbidden && !strings.Contains(w.Body.String(), `"code":"FORBIDDEN"`) {
+			t.Errorf("remote %s: expected a FORBIDDEN error body, got %s", tt.remoteAddr, w.Body.String())
+		}
+	}
+}
+
+func TestAPIKeyNotWrittenToDisk(t *testing.T) {
+	tmpDir := t.TempDir()
+	server, err := Start(Config{DBPath: filepath.Join(tmpDir, "test.db"), Quiet: true, UnixSocket: true})
+	if err != nil {
+		t.Fatalf("failed to start embedded server: %v", err)
+	}
+	key := server.APIKey()
+	socketDir := server.socketDir
+
+	// An authenticated write touches the database
+	req, _ := http.NewRequest("POST", server.URL()+"/api/v1/sources",
+		strings.NewReader(`{"source_type":"Book","name":"Key Test"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+key)
+	resp, err := (&http.Client{Transport: server.Transport()}).Do(req)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	resp.Body.Close()
+
+	// Check while running, when the database and socket directory exist
+	assertKeyAbsent(t, tmpDir, key)
+	assertKeyAbsent(t, socketDir, key)
+
+	if err := server.Shutdown(); err != nil {
+		t.Errorf("failed to shutdown server: %v", err)
+	}
+	assertKeyAbsent(t, tmpDir, key)
+}
+
+// assertKeyAbsent fails if any file under dir contains key
+func assertKeyAbsent(t *testing.T, dir, key string) {
+	t.Helper()
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.Contains(data, []byte(key)) {
+			t.Errorf("API key found in %s", path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to scan %s: %v", dir, err)
+	}
+}
@@ -6,11 +6,18 @@ require (
 	github.com/go-chi/chi/v5 v5.2.3
 	github.com/go-chi/cors v1.2.2
 	github.com/go-chi/httprate v0.15.0
+	github.com/jeff/oaks/pkg/apierror v0.0.0
+	github.com/jeff/oaks/pkg/migrate v0.0.0
 	github.com/mattn/go-sqlite3 v1.14.32
+	golang.org/x/sys v0.30.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/klauspost/cpuid/v2 v2.2.10 // indirect
 	github.com/zeebo/xxh3 v1.0.2 // indirect
-	golang.org/x/sys v0.30.0 // indirect
 )
+
+replace github.com/jeff/oaks/pkg/apierror => ../pkg/apierror
+
+replace github.com/jeff/oaks/pkg/migrate => ../pkg/migrate
@@ -0,0 +1,139 @@
+// Package claims reads structured values, such as a maximum height or how
+// long acorns take to mature, out of a species source's description text so
+// that sources can be compared. Like the features package it is rule-based:
+// a source makes a claim only when its wording is unambiguous, and two
+// sources whose claims disagree are left for a curator to resolve.
+package claims
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/api/internal/units"
+)
+
+// Claimed properties
+const (
+	MaxHeight       = "max_height"       // Tallest height in growth_habit, in meters
+	AcornMaturation = "acorn_maturation" // "annual" or "biennial"
+	LeafPersistence = "leaf_persistence" // "evergreen", "semi-evergreen", or "deciduous"
+)
+
+// Properties lists the claimed properties
+var Properties = []string{MaxHeight, AcornMaturation, LeafPersistence}
+
+// heightTolerance is how far apart two maximum heights may be, as a fraction
+// of the smaller, before they conflict. Sources round heights differently.
+const heightTolerance = 0.25
+
+// Claim is a value a source states for a property
+type Claim struct {
+	Property string
+	Value    string   // As compared and shown, e.g. "30 m" or "biennial"
+	Number   *float64 // Numeric value, for properties compared with a tolerance
+	Field    string   // Text field it was read from
+	Evidence string   // The words it was read from
+}
+
+// Conflicts reports whether two claims for the same property disagree
+func Conflicts(a, b Claim) bool {
+	if a.Number != nil && b.Number != nil {
+		lo, hi := math.Min(*a.Number, *b.Number), math.Max(*a.Number, *b.Number)
+		return hi > lo*(1+heightTolerance)
+	}
+	return a.Value != b.Value
+}
+
+var (
+	biennialPattern = regexp.MustCompile(`(?i)\b(?:biennial|matur\w*\s+(?:in\s+)?(?:the\s+)?(?:second|2nd)\s+(?:year|season)|matur\w*\s+in\s+(?:two|2)\s+(?:years|seasons)|(?:two|2)\s+(?:years|seasons)\s+to\s+mature)\b`)
+	annualPattern   = regexp.MustCompile(`(?i)\b(?:annual|matur\w*\s+(?:in\s+)?(?:the\s+)?(?:first|1st|same)\s+(?:year|season)|matur\w*\s+in\s+(?:one|1|a single)\s+(?:year|season)|(?:one|1|a single)\s+(?:year|season)\s+to\s+mature)\b`)
+
+	semiEvergreenPattern = regexp.MustCompile(`(?i)\b(?:semi-?\s?evergreen|half-?\s?evergreen|tardily\s+deciduous|late\s+deciduous|briefly\s+deciduous|subevergreen)\b`)
+	evergreenPattern     = regexp.MustCompile(`(?i)\bevergreen\b`)
+	deciduousPattern     = regexp.MustCompile(`(?i)\bdeciduous\b`)
+)
+
+// Extract returns the claims a species source's text makes, at most one per
+// property
+func Extract(ss *models.SpeciesSource) []Claim {
+	var found []Claim
+	if c, ok := maxHeight(ss.GrowthHabit); ok {
+		found = append(found, c)
+	}
+	if c, ok := acornMaturation(ss.Fruits); ok {
+		found = append(found, c)
+	}
+	for _, f := range []models.TextField{{Name: "leaves", Value: ss.Leaves}, {Name: "growth_habit", Value: ss.GrowthHabit}} {
+		if c, ok := leafPersistence(f); ok {
+			found = append(found, c)
+			break
+		}
+	}
+	return found
+}
+
+// maxHeight claims the largest length in meters or feet in growth_habit text
+func maxHeight(text *string) (Claim, bool) {
+	if text == nil {
+		return Claim{}, false
+	}
+	var tallest *units.Measurement
+	var meters float64
+	for _, m := range units.Find(*text) {
+		if m.Unit != "m" && m.Unit != "ft" {
+			continue
+		}
+		if v := m.To("m").Max; tallest == nil || v > meters {
+			tallest, meters = &m, v
+		}
+	}
+	if tallest == nil {
+		return Claim{}, false
+	}
+	meters = math.Round(meters*10) / 10
+	return Claim{
+		Property: MaxHeight,
+		Value:    strconv.FormatFloat(meters, 'f', -1, 64) + " m",
+		Number:   &meters,
+		Field:    "growth_habit",
+		Evidence: (*text)[tallest.Start:tallest.End],
+	}, true
+}
+
+// acornMaturation claims annual or biennial acorns from fruits text that
+// says only one of them
+func acornMaturation(text *string) (Claim, bool) {
+	if text == nil {
+		return Claim{}, false
+	}
+	annual, biennial := annualPattern.FindString(*text), biennialPattern.FindString(*text)
+	switch {
+	case annual != "" && biennial == "":
+		return Claim{Property: AcornMaturation, Value: "annual", Field: "fruits", Evidence: annual}, true
+	case biennial != "" && annual == "":
+		return Claim{Property: AcornMaturation, Value: "biennial", Field: "fruits", Evidence: biennial}, true
+	}
+	return Claim{}, false
+}
+
+// leafPersistence claims evergreen, semi-evergreen, or deciduous leaves from
+// text that is not ambiguous about it
+func leafPersistence(f models.TextField) (Claim, bool) {
+	if f.Value == nil {
+		return Claim{}, false
+	}
+	text := *f.Value
+	if semi := semiEvergreenPattern.FindString(text); semi != "" {
+		return Claim{Property: LeafPersistence, Value: "semi-evergreen", Field: f.Name, Evidence: semi}, true
+	}
+	evergreen, deciduous := evergreenPattern.FindString(text), deciduousPattern.FindString(text)
+	switch {
+	case evergreen != "" && deciduous == "":
+		return Claim{Property: LeafPersistence, Value: "evergreen", Field: f.Name, Evidence: evergreen}, true
+	case deciduous != "" && evergreen == "":
+		return Claim{Property: LeafPersistence, Value: "deciduous", Field: f.Name, Evidence: deciduous}, true
+	}
+	return Claim{}, false
+}
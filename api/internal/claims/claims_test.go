@@ -0,0 +1,59 @@
+package claims
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestExtract(t *testing.T) {
+	growth := "Tree to 25 m, trunk to 1.5 m diameter; shrubby forms 3-5 m."
+	fruits := "Acorns biennial, 2-3 cm; cup saucer-shaped."
+	leaves := "Leaves tardily deciduous, 5-12 cm."
+	ss := &models.SpeciesSource{GrowthHabit: &growth, Fruits: &fruits, Leaves: &leaves}
+
+	got := map[string]Claim{}
+	for _, c := range Extract(ss) {
+		got[c.Property] = c
+	}
+	if c := got[MaxHeight]; c.Value != "25 m" || c.Evidence != "to 25 m" {
+		t.Errorf("max height = %+v", c)
+	}
+	if c := got[AcornMaturation]; c.Value != "biennial" || c.Field != "fruits" {
+		t.Errorf("acorn maturation = %+v", c)
+	}
+	if c := got[LeafPersistence]; c.Value != "semi-evergreen" || c.Evidence != "tardily deciduous" {
+		t.Errorf("leaf persistence = %+v", c)
+	}
+
+	// Feet convert to meters; text naming both states claims nothing
+	growth = "Shrub or tree up to 60 ft tall"
+	fruits = "Acorns maturing the first year, unlike the biennial red oaks."
+	leaves = "Deciduous."
+	got = map[string]Claim{}
+	for _, c := range Extract(ss) {
+		got[c.Property] = c
+	}
+	if c := got[MaxHeight]; c.Value != "18.3 m" {
+		t.Errorf("max height in feet = %+v", c)
+	}
+	if c, ok := got[AcornMaturation]; ok {
+		t.Errorf("ambiguous maturation claimed %+v", c)
+	}
+	if c := got[LeafPersistence]; c.Value != "deciduous" {
+		t.Errorf("leaf persistence = %+v", c)
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	h := func(v float64) Claim { return Claim{Property: MaxHeight, Number: &v} }
+	if Conflicts(h(25), h(30)) {
+		t.Error("25 m and 30 m conflict; want within tolerance")
+	}
+	if !Conflicts(h(20), h(30)) {
+		t.Error("20 m and 30 m do not conflict")
+	}
+	if !Conflicts(Claim{Value: "annual"}, Claim{Value: "biennial"}) || Conflicts(Claim{Value: "annual"}, Claim{Value: "annual"}) {
+		t.Error("Conflicts compared values wrongly")
+	}
+}
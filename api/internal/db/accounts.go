@@ -0,0 +1,59 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// GetSpeciesAccount gets the markdown account for a species, or nil if it has none.
+// Only the stored fields (ScientificName, Markdown, UpdatedAt) are populated.
+func (db *Database) GetSpeciesAccount(scientificName string) (*models.SpeciesAccount, error) {
+	var a models.SpeciesAccount
+	err := db.conn.QueryRow(
+		`SELECT scientific_name, body, updated_at FROM species_accounts WHERE scientific_name = ?`,
+		scientificName,
+	).Scan(&a.ScientificName, &a.Markdown, &a.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get species account: %w", err)
+	}
+	return &a, nil
+}
+
+// SaveSpeciesAccount creates or replaces a species account and sets its UpdatedAt.
+// Returns true if the account was created.
+func (db *Database) SaveSpeciesAccount(a *models.SpeciesAccount) (bool, error) {
+	existing, err := db.GetSpeciesAccount(a.ScientificName)
+	if err != nil {
+		return false, err
+	}
+
+	a.UpdatedAt = time.Now().UTC().Format(timestampFormat)
+	_, err = db.conn.Exec(
+		`INSERT INTO species_accounts (scientific_name, body, updated_at) VALUES (?, ?, ?)
+		 ON CONFLICT(scientific_name) DO UPDATE SET body = excluded.body, updated_at = excluded.updated_at`,
+		a.ScientificName, a.Markdown, a.UpdatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to save species account: %w", err)
+	}
+	return existing == nil, nil
+}
+
+// DeleteSpeciesAccount deletes a species account. Returns false if there was none.
+func (db *Database) DeleteSpeciesAccount(scientificName string) (bool, error) {
+	result, err := db.conn.Exec(`DELETE FROM species_accounts WHERE scientific_name = ?`, scientificName)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete species account: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
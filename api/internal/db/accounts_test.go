@@ -0,0 +1,56 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestSpeciesAccounts(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if err := db.SaveOakEntry(&models.OakEntry{ScientificName: "rubra"}); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+
+	got, err := db.GetSpeciesAccount("rubra")
+	if err != nil || got != nil {
+		t.Fatalf("GetSpeciesAccount before save = %v, %v; want nil, nil", got, err)
+	}
+
+	created, err := db.SaveSpeciesAccount(&models.SpeciesAccount{ScientificName: "rubra", Markdown: "# Rubra"})
+	if err != nil || !created {
+		t.Fatalf("SaveSpeciesAccount = %v, %v; want created", created, err)
+	}
+	created, err = db.SaveSpeciesAccount(&models.SpeciesAccount{ScientificName: "rubra", Markdown: "See [[alba]]."})
+	if err != nil || created {
+		t.Fatalf("SaveSpeciesAccount replace = %v, %v; want replaced", created, err)
+	}
+
+	got, err = db.GetSpeciesAccount("rubra")
+	if err != nil || got == nil {
+		t.Fatalf("GetSpeciesAccount = %v, %v", got, err)
+	}
+	if got.Markdown != "See [[alba]]." || got.UpdatedAt == "" {
+		t.Errorf("account = %+v", got)
+	}
+
+	// Deleting the species drops its account
+	if err := db.DeleteOakEntry("rubra"); err != nil {
+		t.Fatalf("DeleteOakEntry failed: %v", err)
+	}
+	if got, _ := db.GetSpeciesAccount("rubra"); got != nil {
+		t.Errorf("account survived species delete: %+v", got)
+	}
+
+	if _, err := db.SaveSpeciesAccount(&models.SpeciesAccount{ScientificName: "alba", Markdown: "x"}); err != nil {
+		t.Fatalf("SaveSpeciesAccount failed: %v", err)
+	}
+	if found, err := db.DeleteSpeciesAccount("alba"); err != nil || !found {
+		t.Errorf("DeleteSpeciesAccount = %v, %v; want found", found, err)
+	}
+	if found, _ := db.DeleteSpeciesAccount("alba"); found {
+		t.Error("DeleteSpeciesAccount found an already deleted account")
+	}
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// API key roles, from least to most permitted. Each role may do everything
+// the roles before it may.
+const (
+	RoleRead   = "read"   // Authenticated reads: drafts, internal fields, curator queues
+	RoleEditor = "editor" // Reads and writes to the data
+	RoleAdmin  = "admin"  // Everything, including /admin and managing keys
+)
+
+// Roles lists the API key roles from least to most permitted.
+var Roles = []string{RoleRead, RoleEditor, RoleAdmin}
+
+// RoleAtLeast reports whether role permits everything min does
+func RoleAtLeast(role, min string) bool {
+	rank := slices.Index(Roles, role)
+	return rank >= 0 && rank >= slices.Index(Roles, min)
+}
+
+// APIKey is a key given to a collaborator. The key itself is shown once,
+// when it is created; only its hash is stored.
+type APIKey struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateAPIKey stores a key by its ID and hash
+func (db *Database) CreateAPIKey(key *APIKey, hash string) error {
+	_, err := db.conn.Exec(
+		`INSERT INTO api_keys (id, key_hash, name, role, created_at) VALUES (?, ?, ?, ?, ?)`,
+		key.ID, hash, key.Name, key.Role, key.CreatedAt.UTC().Format(timestampFormat),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+	return nil
+}
+
+// GetAPIKeyByHash returns the key with this hash, or nil if there is none
+func (db *Database) GetAPIKeyByHash(hash string) (*APIKey, error) {
+	key, err := scanAPIKey(db.conn.QueryRow(
+		`SELECT id, name, role, created_at FROM api_keys WHERE key_hash = ?`, hash,
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return key, err
+}
+
+// GetAPIKey returns the key with this ID, or nil if there is none
+func (db *Database) GetAPIKey(id string) (*APIKey, error) {
+	key, err := scanAPIKey(db.conn.QueryRow(
+		`SELECT id, name, role, created_at FROM api_keys WHERE id = ?`, id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return key, err
+}
+
+// ListAPIKeys returns every stored key, oldest first
+func (db *Database) ListAPIKeys() ([]*APIKey, error) {
+	rows, err := db.conn.Query(`SELECT id, name, role, created_at FROM api_keys ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// DeleteAPIKey revokes a key. Its usage stays recorded. Returns false if
+// there was no such key.
+func (db *Database) DeleteAPIKey(id string) (bool, error) {
+	result, err := db.conn.Exec(`DELETE FROM api_keys WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete API key: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete API key: %w", err)
+	}
+	return n > 0, nil
+}
+
+func scanAPIKey(row rowScanner) (*APIKey, error) {
+	var key APIKey
+	var createdAt string
+	if err := row.Scan(&key.ID, &key.Name, &key.Role, &createdAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan API key: %w", err)
+	}
+	var err error
+	if key.CreatedAt, err = time.Parse(timestampFormat, createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at for API key %s: %w", key.ID, err)
+	}
+	return &key, nil
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAPIKeys(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	created := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	for i, role := range Roles {
+		key := &APIKey{ID: "key" + role, Name: "Key " + role, Role: role, CreatedAt: created.Add(time.Duration(i) * time.Minute)}
+		if err := db.CreateAPIKey(key, "hash-"+role); err != nil {
+			t.Fatalf("CreateAPIKey(%s) failed: %v", role, err)
+		}
+	}
+	if err := db.CreateAPIKey(&APIKey{ID: "bad", Name: "Bad", Role: "owner", CreatedAt: created}, "hash-bad"); err == nil {
+		t.Error("CreateAPIKey with an unknown role succeeded")
+	}
+
+	key, err := db.GetAPIKeyByHash("hash-editor")
+	if err != nil || key == nil || key.ID != "keyeditor" || key.Role != RoleEditor || !key.CreatedAt.Equal(created.Add(time.Minute)) {
+		t.Fatalf("GetAPIKeyByHash() = %+v, %v", key, err)
+	}
+	if key, err := db.GetAPIKeyByHash("missing"); err != nil || key != nil {
+		t.Errorf("GetAPIKeyByHash(missing) = %+v, %v; want nil", key, err)
+	}
+
+	if deleted, err := db.DeleteAPIKey("keyeditor"); err != nil || !deleted {
+		t.Errorf("DeleteAPIKey() = %v, %v; want deleted", deleted, err)
+	}
+	if deleted, _ := db.DeleteAPIKey("keyeditor"); deleted {
+		t.Error("DeleteAPIKey() deleted a missing key")
+	}
+	keys, err := db.ListAPIKeys()
+	if err != nil || len(keys) != 2 || keys[0].Role != RoleRead || keys[1].Role != RoleAdmin {
+		t.Errorf("ListAPIKeys() = %+v, %v; want read then admin", keys, err)
+	}
+}
+
+func TestRoleAtLeast(t *testing.T) {
+	tests := []struct {
+		role, min string
+		want      bool
+	}{
+		{RoleAdmin, RoleEditor, true},
+		{RoleEditor, RoleEditor, true},
+		{RoleRead, RoleEditor, false},
+		{"", RoleRead, false},
+		{"owner", RoleRead, false},
+	}
+	for _, tt := range tests {
+		if got := RoleAtLeast(tt.role, tt.min); got != tt.want {
+			t.Errorf("RoleAtLeast(%q, %q) = %v, want %v", tt.role, tt.min, got, tt.want)
+		}
+	}
+}
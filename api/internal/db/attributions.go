@@ -0,0 +1,70 @@
+package db
+
+import "fmt"
+
+// SourceAttribution is a source's citation details and the species it contributed data for
+type SourceAttribution struct {
+	SourceID   int64    `json:"source_id"`
+	SourceType string   `json:"source_type"`
+	Name       string   `json:"name"`
+	Author     *string  `json:"author,omitempty"`
+	Year       *int     `json:"year,omitempty"`
+	URL        *string  `json:"url,omitempty"`
+	License    *string  `json:"license,omitempty"`
+	LicenseURL *string  `json:"license_url,omitempty"`
+	Species    []string `json:"species"`
+	// Pages locates each species' data in the source, for those with pages set
+	Pages map[string]string `json:"pages,omitempty"`
+}
+
+// ListSourceAttributions returns every source, ordered by name, with the species
+// it has data for in alphabetical order
+func (db *Database) ListSourceAttributions() ([]*SourceAttribution, error) {
+	sources, err := db.ListSources(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	attributions := make([]*SourceAttribution, 0, len(sources))
+	byID := make(map[int64]*SourceAttribution, len(sources))
+	for _, s := range sources {
+		a := &SourceAttribution{
+			SourceID:   s.ID,
+			SourceType: s.SourceType,
+			Name:       s.Name,
+			Author:     s.Author,
+			Year:       s.Year,
+			URL:        s.URL,
+			License:    s.License,
+			LicenseURL: s.LicenseURL,
+			Species:    []string{},
+		}
+		attributions = append(attributions, a)
+		byID[s.ID] = a
+	}
+
+	rows, err := db.conn.Query(`SELECT source_id, scientific_name, pages FROM species_sources ORDER BY scientific_name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attributed species: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sourceID int64
+		var name string
+		var pages *string
+		if err := rows.Scan(&sourceID, &name, &pages); err != nil {
+			return nil, fmt.Errorf("failed to scan attributed species: %w", err)
+		}
+		if a, ok := byID[sourceID]; ok {
+			a.Species = append(a.Species, name)
+			if pages != nil && *pages != "" {
+				if a.Pages == nil {
+					a.Pages = make(map[string]string)
+				}
+				a.Pages[name] = *pages
+			}
+		}
+	}
+	return attributions, rows.Err()
+}
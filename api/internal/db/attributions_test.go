@@ -0,0 +1,97 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestListSourceAttributions(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	for _, name := range []string{"rubra", "alba"} {
+		if err := db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatalf("SaveOakEntry(%s) failed: %v", name, err)
+		}
+	}
+
+	license := "CC BY 4.0"
+	usedID, err := db.InsertSource(&models.Source{SourceType: "website", Name: "Oaks Online", License: &license})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+	if _, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Unused Flora"}); err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+	for _, name := range []string{"rubra", "alba"} {
+		if err := db.SaveSpeciesSource(models.NewSpeciesSource(name, usedID)); err != nil {
+			t.Fatalf("SaveSpeciesSource(%s) failed: %v", name, err)
+		}
+	}
+
+	attributions, err := db.ListSourceAttributions()
+	if err != nil {
+		t.Fatalf("ListSourceAttributions failed: %v", err)
+	}
+	if len(attributions) != 2 {
+		t.Fatalf("got %d attributions, want 2", len(attributions))
+	}
+
+	used := attributions[0]
+	if used.Name != "Oaks Online" || used.License == nil || *used.License != license {
+		t.Errorf("first attribution = %+v, want Oaks Online with license", used)
+	}
+	if len(used.Species) != 2 || used.Species[0] != "alba" || used.Species[1] != "rubra" {
+		t.Errorf("Species = %v, want [alba rubra]", used.Species)
+	}
+	if unused := attributions[1]; len(unused.Species) != 0 {
+		t.Errorf("Unused Flora species = %v, want none", unused.Species)
+	}
+}
+
+func TestSpeciesSourcePages(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if err := db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	author, year := "Sargent, C.S.", 1905
+	sourceID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Manual of the Trees", Author: &author, Year: &year})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+	pages := "pp. 230-232"
+	ss := models.NewSpeciesSource("alba", sourceID)
+	ss.Pages = &pages
+	ss.FieldPages = map[string]string{"fruits": "pl. 14"}
+	if err := db.SaveSpeciesSource(ss); err != nil {
+		t.Fatalf("SaveSpeciesSource failed: %v", err)
+	}
+
+	got, err := db.GetSpeciesSourceBySourceID("alba", sourceID)
+	if err != nil || got == nil {
+		t.Fatalf("GetSpeciesSourceBySourceID = %v, %v", got, err)
+	}
+	if got.Locator("fruits") != "pl. 14" || got.Locator("leaves") != pages {
+		t.Errorf("locators = %q, %q; want pl. 14, %s", got.Locator("fruits"), got.Locator("leaves"), pages)
+	}
+
+	full, err := db.GetOakEntryWithSources("alba")
+	if err != nil {
+		t.Fatalf("GetOakEntryWithSources failed: %v", err)
+	}
+	want := "Sargent, C.S. (1905). Manual of the Trees, pp. 230-232"
+	if len(full.Sources) != 1 || full.Sources[0].Citation != want {
+		t.Errorf("sources = %+v, want citation %q", full.Sources, want)
+	}
+
+	attributions, err := db.ListSourceAttributions()
+	if err != nil {
+		t.Fatalf("ListSourceAttributions failed: %v", err)
+	}
+	if len(attributions) != 1 || attributions[0].Pages["alba"] != pages {
+		t.Errorf("attribution pages = %v, want alba: %s", attributions[0].Pages, pages)
+	}
+}
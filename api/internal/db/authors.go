@@ -0,0 +1,156 @@
+package db
+
+import (
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// bundledAuthors is the starter author table, seeded on startup
+//
+//go:embed authors.tsv
+var bundledAuthors string
+
+var (
+	// authorSeparatorPattern splits an author citation such as
+	// "(Münchh.) Sarg. ex Trel. & Bonpl., 1890" into its authors
+	authorSeparatorPattern = regexp.MustCompile(`[(),&]|\s(?:ex|in|et)\s`)
+	yearPattern            = regexp.MustCompile(`\b\d{4}\b`)
+)
+
+const authorsSelect = `SELECT abbreviation, full_name, dates, ipni_id FROM authors`
+
+// seedAuthors adds the bundled authors that are not already in the table.
+// Rows refreshed from IPNI are left alone.
+func (db *Database) seedAuthors() error {
+	for _, line := range strings.Split(bundledAuthors, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 4 || strings.HasPrefix(line, "#") || fields[0] == "abbreviation" {
+			continue
+		}
+		_, err := db.conn.Exec(
+			`INSERT OR IGNORE INTO authors (abbreviation, full_name, dates, ipni_id) VALUES (?, ?, NULLIF(?, ''), NULLIF(?, ''))`,
+			fields[0], fields[1], fields[2], strings.TrimSpace(fields[3]),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to seed authors: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListAuthors returns every author ordered by abbreviation
+func (db *Database) ListAuthors() ([]*models.Author, error) {
+	rows, err := db.conn.Query(authorsSelect + ` ORDER BY abbreviation`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list authors: %w", err)
+	}
+	defer rows.Close()
+
+	var authors []*models.Author
+	for rows.Next() {
+		a, err := scanAuthor(rows)
+		if err != nil {
+			return nil, err
+		}
+		authors = append(authors, a)
+	}
+	return authors, rows.Err()
+}
+
+// GetAuthor gets an author by abbreviation, or nil if unknown.
+// Spaces are ignored, so "A. Camus" finds "A.Camus".
+func (db *Database) GetAuthor(abbreviation string) (*models.Author, error) {
+	key := strings.ReplaceAll(abbreviation, " ", "")
+	a, err := scanAuthor(db.conn.QueryRow(
+		authorsSelect+` WHERE abbreviation = ? OR REPLACE(abbreviation, ' ', '') = ? ORDER BY abbreviation = ? DESC LIMIT 1`,
+		abbreviation, key, abbreviation,
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return a, err
+}
+
+// UpsertAuthors creates or replaces the given authors in a single transaction
+// and reports how many were new
+func (db *Database) UpsertAuthors(authors []*models.Author) (created, updated int, err error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, a := range authors {
+		var exists bool
+		if err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM authors WHERE abbreviation = ?)`, a.Abbreviation).Scan(&exists); err != nil {
+			return 0, 0, fmt.Errorf("failed to check author: %w", err)
+		}
+		_, err := tx.Exec(
+			`INSERT INTO authors (abbreviation, full_name, dates, ipni_id) VALUES (?, ?, ?, ?)
+			 ON CONFLICT(abbreviation) DO UPDATE SET full_name = excluded.full_name, dates = excluded.dates, ipni_id = excluded.ipni_id`,
+			a.Abbreviation, a.FullName, a.Dates, a.IPNIID,
+		)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to save author: %w", err)
+		}
+		if exists {
+			updated++
+		} else {
+			created++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit authors: %w", err)
+	}
+	return created, updated, nil
+}
+
+// ExpandAuthor looks up each abbreviation in an author citation, such as
+// "(Münchh.) Sarg.", returning the authors found in order of appearance
+func (db *Database) ExpandAuthor(citation string) ([]models.Author, error) {
+	authors := []models.Author{}
+	for _, abbr := range authorAbbreviations(citation) {
+		a, err := db.GetAuthor(abbr)
+		if err != nil {
+			return nil, err
+		}
+		if a != nil {
+			authors = append(authors, *a)
+		}
+	}
+	return authors, nil
+}
+
+// authorAbbreviations splits an author citation into distinct abbreviations,
+// dropping publication years
+func authorAbbreviations(citation string) []string {
+	var abbrs []string
+	seen := map[string]bool{}
+	for _, part := range authorSeparatorPattern.Split(citation, -1) {
+		part = strings.TrimSpace(yearPattern.ReplaceAllString(part, ""))
+		if part == "" || seen[part] {
+			continue
+		}
+		seen[part] = true
+		abbrs = append(abbrs, part)
+	}
+	return abbrs
+}
+
+// scanAuthor scans one row of authorsSelect
+func scanAuthor(row interface{ Scan(...interface{}) error }) (*models.Author, error) {
+	var a models.Author
+	if err := row.Scan(&a.Abbreviation, &a.FullName, &a.Dates, &a.IPNIID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan author: %w", err)
+	}
+	return &a, nil
+}
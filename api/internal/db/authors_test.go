@@ -0,0 +1,72 @@
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestAuthorAbbreviations(t *testing.T) {
+	tests := []struct {
+		citation string
+		want     []string
+	}{
+		{"L. 1753", []string{"L."}},
+		{"(Münchh.) Sarg.", []string{"Münchh.", "Sarg."}},
+		{"Humb. & Bonpl.", []string{"Humb.", "Bonpl."}},
+		{"Buckley ex Sarg. in Trel., 1924", []string{"Buckley", "Sarg.", "Trel."}},
+		{"A. Camus", []string{"A. Camus"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := authorAbbreviations(tt.citation); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("authorAbbreviations(%q) = %q, want %q", tt.citation, got, tt.want)
+		}
+	}
+}
+
+func TestAuthors(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	// The bundled dataset is seeded on open
+	a, err := db.GetAuthor("Münchh.")
+	if err != nil || a == nil {
+		t.Fatalf("GetAuthor(Münchh.) = %v, %v", a, err)
+	}
+	if a.FullName != "Otto von Münchhausen" || a.Dates == nil || *a.Dates != "1716-1774" {
+		t.Errorf("author = %+v", a)
+	}
+	if a, err := db.GetAuthor("A. Camus"); err != nil || a == nil || a.Abbreviation != "A.Camus" {
+		t.Errorf("GetAuthor(A. Camus) = %+v, %v; want A.Camus", a, err)
+	}
+	if a, err := db.GetAuthor("Nobody"); err != nil || a != nil {
+		t.Errorf("GetAuthor(Nobody) = %+v, %v; want nil, nil", a, err)
+	}
+
+	dates := "1851-1931"
+	created, updated, err := db.UpsertAuthors([]*models.Author{
+		{Abbreviation: "Sarg.", FullName: "C. S. Sargent"},
+		{Abbreviation: "Lemmon", FullName: "John Gill Lemmon", Dates: &dates},
+	})
+	if err != nil || created != 1 || updated != 1 {
+		t.Fatalf("UpsertAuthors = %d, %d, %v; want 1 created, 1 updated", created, updated, err)
+	}
+
+	got, err := db.ExpandAuthor("(Münchh.) Sarg. ex Unknown")
+	if err != nil {
+		t.Fatalf("ExpandAuthor failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Abbreviation != "Münchh." || got[1].FullName != "C. S. Sargent" || got[1].Dates != nil {
+		t.Errorf("ExpandAuthor = %+v", got)
+	}
+
+	// Reopening doesn't overwrite refreshed rows with the bundled ones
+	if err := db.seedAuthors(); err != nil {
+		t.Fatalf("seedAuthors failed: %v", err)
+	}
+	if a, _ := db.GetAuthor("Sarg."); a == nil || a.FullName != "C. S. Sargent" {
+		t.Errorf("Sarg. after reseed = %+v", a)
+	}
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// GetCommonNames returns a species' common names, by language and then in
+// the order they were given
+func (db *Database) GetCommonNames(scientificName string) ([]*models.CommonName, error) {
+	all, err := db.listCommonNames(`WHERE scientific_name = ?`, scientificName)
+	if err != nil {
+		return nil, err
+	}
+	names := all[scientificName]
+	if names == nil {
+		names = []*models.CommonName{}
+	}
+	return names, nil
+}
+
+// ListAllCommonNames returns every species' common names, keyed by
+// scientific name, ordered as GetCommonNames orders them
+func (db *Database) ListAllCommonNames() (map[string][]*models.CommonName, error) {
+	return db.listCommonNames("")
+}
+
+func (db *Database) listCommonNames(where string, args ...interface{}) (map[string][]*models.CommonName, error) {
+	rows, err := db.conn.Query(
+		`SELECT scientific_name, language, name, source_id FROM common_names `+where+`
+		 ORDER BY scientific_name, language, position`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list common names: %w", err)
+	}
+	defer rows.Close()
+
+	names := make(map[string][]*models.CommonName)
+	for rows.Next() {
+		var scientificName string
+		var n models.CommonName
+		if err := rows.Scan(&scientificName, &n.Language, &n.Name, &n.SourceID); err != nil {
+			return nil, fmt.Errorf("failed to scan common name: %w", err)
+		}
+		names[scientificName] = append(names[scientificName], &n)
+	}
+	return names, rows.Err()
+}
+
+// SetCommonNames replaces a species' common names. Names keep their order
+// within each language, and a name repeated in the same language is stored once.
+func (db *Database) SetCommonNames(scientificName string, names []*models.CommonName) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM common_names WHERE scientific_name = ?`, scientificName); err != nil {
+		return fmt.Errorf("failed to clear common names: %w", err)
+	}
+	for i, n := range names {
+		if _, err := tx.Exec(
+			`INSERT OR IGNORE INTO common_names (scientific_name, language, name, source_id, position) VALUES (?, ?, ?, ?, ?)`,
+			scientificName, n.Language, n.Name, n.SourceID, i,
+		); err != nil {
+			return fmt.Errorf("failed to save common name %q: %w", n.Name, err)
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestCommonNames(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if err := db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	sourceID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Flora"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+
+	names := []*models.CommonName{
+		{Name: "White oak", Language: "en", SourceID: &sourceID},
+		{Name: "Chêne blanc", Language: "fr"},
+		{Name: "Stave oak", Language: "en"},
+		{Name: "White oak", Language: "en"}, // Repeated: stored once
+	}
+	if err := db.SetCommonNames("alba", names); err != nil {
+		t.Fatalf("SetCommonNames failed: %v", err)
+	}
+
+	got, err := db.GetCommonNames("alba")
+	if err != nil {
+		t.Fatalf("GetCommonNames failed: %v", err)
+	}
+	want := []string{"en:White oak", "en:Stave oak", "fr:Chêne blanc"}
+	if len(got) != len(want) {
+		t.Fatalf("GetCommonNames returned %d names, want %d", len(got), len(want))
+	}
+	for i, n := range got {
+		if n.Language+":"+n.Name != want[i] {
+			t.Errorf("names[%d] = %s:%s, want %s", i, n.Language, n.Name, want[i])
+		}
+	}
+	if got[0].SourceID == nil || *got[0].SourceID != sourceID {
+		t.Errorf("names[0].SourceID = %v, want %d", got[0].SourceID, sourceID)
+	}
+
+	// Deleting the source keeps the name without its citation
+	if err := db.DeleteSource(sourceID); err != nil {
+		t.Fatalf("DeleteSource failed: %v", err)
+	}
+	got, _ = db.GetCommonNames("alba")
+	if got[0].SourceID != nil {
+		t.Errorf("SourceID after source delete = %d, want nil", *got[0].SourceID)
+	}
+
+	all, err := db.ListAllCommonNames()
+	if err != nil {
+		t.Fatalf("ListAllCommonNames failed: %v", err)
+	}
+	if len(all) != 1 || len(all["alba"]) != 3 {
+		t.Errorf("ListAllCommonNames = %v, want 3 names for alba", all)
+	}
+
+	// Deleting the species deletes its names
+	if err := db.DeleteOakEntry("alba"); err != nil {
+		t.Fatalf("DeleteOakEntry failed: %v", err)
+	}
+	got, err = db.GetCommonNames("alba")
+	if err != nil || len(got) != 0 {
+		t.Errorf("GetCommonNames after delete = %v, %v; want none", got, err)
+	}
+}
@@ -0,0 +1,299 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/claims"
+)
+
+// Conflict states
+const (
+	ConflictOpen     = "open"
+	ConflictResolved = "resolved"
+)
+
+// Conflict is a pair of sources making contradictory claims about a species
+// property (see the claims package). SourceA is the lower source ID.
+type Conflict struct {
+	ID                int64      `json:"id"`
+	ScientificName    string     `json:"scientific_name"`
+	Property          string     `json:"property"`
+	SourceA           int64      `json:"source_a"`
+	ValueA            string     `json:"value_a"`
+	EvidenceA         string     `json:"evidence_a"` // Words the value was read from
+	SourceB           int64      `json:"source_b"`
+	ValueB            string     `json:"value_b"`
+	EvidenceB         string     `json:"evidence_b"`
+	Status            string     `json:"status"`
+	PreferredSourceID *int64     `json:"preferred_source_id,omitempty"` // Source the curator sided with, if either
+	Resolution        *string    `json:"resolution,omitempty"`          // Value the curator settled on
+	Justification     *string    `json:"justification,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ResolvedAt        *time.Time `json:"resolved_at,omitempty"`
+}
+
+// ConflictReport counts what DetectConflicts did
+type ConflictReport struct {
+	Detected int `json:"detected"` // New conflicts
+	Reopened int `json:"reopened"` // Resolved conflicts whose values changed
+	Cleared  int `json:"cleared"`  // Open conflicts whose sources now agree
+}
+
+const conflictColumns = `id, scientific_name, property, source_a, value_a, evidence_a, source_b, value_b, evidence_b,
+	status, preferred_source_id, resolution, justification, created_at, resolved_at`
+
+// DetectConflicts compares the claims of the given species' sources, or
+// every species' when none are given, and records the pairs that disagree.
+// Open conflicts whose sources now agree are removed. A resolved conflict is
+// kept, and reopened if either value has changed since it was resolved.
+func (db *Database) DetectConflicts(scientificNames ...string) (*ConflictReport, error) {
+	if len(scientificNames) == 0 {
+		rows, err := db.conn.Query(`SELECT DISTINCT scientific_name FROM species_sources ORDER BY scientific_name`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list species: %w", err)
+		}
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			scientificNames = append(scientificNames, name)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &ConflictReport{}
+	for _, name := range scientificNames {
+		if err := db.detectConflicts(name, report); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// detectConflicts records one species' conflicts, adding to report
+func (db *Database) detectConflicts(name string, report *ConflictReport) error {
+	sources, err := db.GetSpeciesSources(name)
+	if err != nil {
+		return err
+	}
+	type sourceClaim struct {
+		sourceID int64
+		claim    claims.Claim
+	}
+	byProperty := make(map[string][]sourceClaim)
+	for _, ss := range sources {
+		for _, c := range claims.Extract(ss) {
+			byProperty[c.Property] = append(byProperty[c.Property], sourceClaim{ss.SourceID, c})
+		}
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	type pairKey struct {
+		property string
+		a, b     int64
+	}
+	current := make(map[pairKey]bool)
+	now := time.Now().UTC().Format(timestampFormat)
+	for property, found := range byProperty {
+		for i := range found {
+			for j := i + 1; j < len(found); j++ {
+				a, b := found[i], found[j]
+				if a.sourceID > b.sourceID {
+					a, b = b, a
+				}
+				if !claims.Conflicts(a.claim, b.claim) {
+					continue
+				}
+				current[pairKey{property, a.sourceID, b.sourceID}] = true
+
+				var id int64
+				var status, valueA, valueB string
+				err := tx.QueryRow(
+					`SELECT id, status, value_a, value_b FROM conflicts
+					 WHERE scientific_name = ? AND property = ? AND source_a = ? AND source_b = ?`,
+					name, property, a.sourceID, b.sourceID,
+				).Scan(&id, &status, &valueA, &valueB)
+				switch {
+				case err == sql.ErrNoRows:
+					if _, err := tx.Exec(
+						`INSERT INTO conflicts (scientific_name, property, source_a, value_a, evidence_a,
+							source_b, value_b, evidence_b, status, created_at)
+						 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+						name, property, a.sourceID, a.claim.Value, a.claim.Evidence,
+						b.sourceID, b.claim.Value, b.claim.Evidence, ConflictOpen, now,
+					); err != nil {
+						return fmt.Errorf("failed to record conflict: %w", err)
+					}
+					report.Detected++
+					continue
+				case err != nil:
+					return fmt.Errorf("failed to get conflict: %w", err)
+				}
+				if valueA == a.claim.Value && valueB == b.claim.Value {
+					continue
+				}
+				if _, err := tx.Exec(
+					`UPDATE conflicts SET value_a = ?, evidence_a = ?, value_b = ?, evidence_b = ?, status = ?,
+						preferred_source_id = NULL, resolution = NULL, justification = NULL, resolved_at = NULL
+					 WHERE id = ?`,
+					a.claim.Value, a.claim.Evidence, b.claim.Value, b.claim.Evidence, ConflictOpen, id,
+				); err != nil {
+					return fmt.Errorf("failed to update conflict: %w", err)
+				}
+				if status == ConflictResolved {
+					report.Reopened++
+				}
+			}
+		}
+	}
+
+	// Open conflicts no longer found have been settled by edits
+	rows, err := tx.Query(
+		`SELECT id, property, source_a, source_b FROM conflicts WHERE scientific_name = ? AND status = ?`,
+		name, ConflictOpen,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list conflicts: %w", err)
+	}
+	var stale []int64
+	for rows.Next() {
+		var id int64
+		var k pairKey
+		if err := rows.Scan(&id, &k.property, &k.a, &k.b); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan conflict: %w", err)
+		}
+		if !current[k] {
+			stale = append(stale, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, id := range stale {
+		if _, err := tx.Exec(`DELETE FROM conflicts WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("failed to clear conflict: %w", err)
+		}
+	}
+	report.Cleared += len(stale)
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit conflicts: %w", err)
+	}
+	return nil
+}
+
+// rebuildConflicts re-detects every species' conflicts and returns how many are open
+func (db *Database) rebuildConflicts() (int, error) {
+	if _, err := db.DetectConflicts(); err != nil {
+		return 0, err
+	}
+	var open int
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM conflicts WHERE status = ?`, ConflictOpen).Scan(&open); err != nil {
+		return 0, fmt.Errorf("failed to count conflicts: %w", err)
+	}
+	return open, nil
+}
+
+// GetConflict returns a conflict, or nil if it does not exist
+func (db *Database) GetConflict(id int64) (*Conflict, error) {
+	rows, err := db.conn.Query(`SELECT `+conflictColumns+` FROM conflicts WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conflict: %w", err)
+	}
+	defer rows.Close()
+
+	conflicts, err := scanConflicts(rows)
+	if err != nil || len(conflicts) == 0 {
+		return nil, err
+	}
+	return conflicts[0], nil
+}
+
+// ListConflicts returns conflicts ordered by species, property, and id.
+// Empty status, scientificName, or property matches any.
+func (db *Database) ListConflicts(status, scientificName, property string) ([]*Conflict, error) {
+	query := `SELECT ` + conflictColumns + ` FROM conflicts WHERE 1 = 1`
+	var args []interface{}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	if scientificName != "" {
+		query += ` AND scientific_name = ?`
+		args = append(args, scientificName)
+	}
+	if property != "" {
+		query += ` AND property = ?`
+		args = append(args, property)
+	}
+	query += ` ORDER BY scientific_name, property, id`
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanConflicts(rows)
+}
+
+// ResolveConflict records a curator's resolution of an open conflict: the
+// source sided with (nil for neither), the settled value, and why. Returns
+// false if no open conflict has that id.
+func (db *Database) ResolveConflict(id int64, preferredSourceID *int64, resolution, justification string) (bool, error) {
+	var resolutionValue *string
+	if resolution != "" {
+		resolutionValue = &resolution
+	}
+	result, err := db.conn.Exec(
+		`UPDATE conflicts SET status = ?, preferred_source_id = ?, resolution = ?, justification = ?, resolved_at = ?
+		 WHERE id = ? AND status = ?`,
+		ConflictResolved, preferredSourceID, resolutionValue, justification,
+		time.Now().UTC().Format(timestampFormat), id, ConflictOpen,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve conflict: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+func scanConflicts(rows *sql.Rows) ([]*Conflict, error) {
+	var conflicts []*Conflict
+	for rows.Next() {
+		var c Conflict
+		var createdAt string
+		var resolvedAt sql.NullString
+		if err := rows.Scan(&c.ID, &c.ScientificName, &c.Property, &c.SourceA, &c.ValueA, &c.EvidenceA,
+			&c.SourceB, &c.ValueB, &c.EvidenceB, &c.Status, &c.PreferredSourceID, &c.Resolution,
+			&c.Justification, &createdAt, &resolvedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan conflict: %w", err)
+		}
+		var err error
+		if c.CreatedAt, err = time.Parse(timestampFormat, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at for conflict %d: %w", c.ID, err)
+		}
+		if c.ResolvedAt, err = parseOptionalTimestamp(resolvedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse resolved_at for conflict %d: %w", c.ID, err)
+		}
+		conflicts = append(conflicts, &c)
+	}
+	return conflicts, rows.Err()
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestDetectConflicts(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if err := db.SaveOakEntry(&models.OakEntry{ScientificName: "texana"}); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	heights := []string{"Tree to 20 m.", "Tree to 35 m.", "Tree up to 22 m tall."}
+	fruits := []string{"Acorns biennial.", "Acorns biennial.", "Acorns maturing the first year."}
+	var sources []*models.SpeciesSource
+	for i := range heights {
+		sourceID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Source " + string(rune('A'+i))})
+		if err != nil {
+			t.Fatalf("InsertSource failed: %v", err)
+		}
+		ss := models.NewSpeciesSource("texana", sourceID)
+		ss.GrowthHabit, ss.Fruits = &heights[i], &fruits[i]
+		if err := db.SaveSpeciesSource(ss); err != nil {
+			t.Fatalf("SaveSpeciesSource failed: %v", err)
+		}
+		sources = append(sources, ss)
+	}
+
+	// Heights: 20 vs 35 and 35 vs 22 conflict, 20 vs 22 is within tolerance.
+	// Maturation: the third source disagrees with both others.
+	report, err := db.DetectConflicts()
+	if err != nil {
+		t.Fatalf("DetectConflicts failed: %v", err)
+	}
+	if report.Detected != 4 {
+		t.Errorf("detected = %d, want 4", report.Detected)
+	}
+	if report, _ := db.DetectConflicts("texana"); report.Detected != 0 {
+		t.Errorf("rerun detected %d, want 0", report.Detected)
+	}
+
+	open, err := db.ListConflicts(ConflictOpen, "texana", "max_height")
+	if err != nil || len(open) != 2 {
+		t.Fatalf("ListConflicts() = %d, %v", len(open), err)
+	}
+	if c := open[0]; c.SourceA >= c.SourceB || c.ValueA != "20 m" || c.ValueB != "35 m" || c.EvidenceB != "to 35 m" {
+		t.Errorf("conflict = %+v", c)
+	}
+
+	preferred := open[0].SourceA
+	if ok, err := db.ResolveConflict(open[0].ID, &preferred, "20 m", "Source B measured a planted tree"); !ok || err != nil {
+		t.Fatalf("ResolveConflict() = %v, %v", ok, err)
+	}
+	if ok, _ := db.ResolveConflict(open[0].ID, nil, "", "again"); ok {
+		t.Error("resolved a resolved conflict")
+	}
+
+	// Agreeing clears the other open height conflict; changing a value reopens the resolved one
+	heights[1] = "Tree to 24 m."
+	if err := db.SaveSpeciesSource(sources[1]); err != nil {
+		t.Fatalf("SaveSpeciesSource failed: %v", err)
+	}
+	report, _ = db.DetectConflicts("texana")
+	if report.Cleared != 1 || report.Reopened != 0 {
+		t.Errorf("after agreeing report = %+v, want 1 cleared", report)
+	}
+	c, _ := db.GetConflict(open[0].ID)
+	if c == nil || c.Status != ConflictResolved || c.Justification == nil {
+		t.Errorf("resolved conflict after other edits = %+v", c)
+	}
+	heights[0] = "Tree to 12 m."
+	if err := db.SaveSpeciesSource(sources[0]); err != nil {
+		t.Fatalf("SaveSpeciesSource failed: %v", err)
+	}
+	report, _ = db.DetectConflicts("texana")
+	if report.Reopened != 1 {
+		t.Errorf("reopened = %d, want 1", report.Reopened)
+	}
+	if c, _ := db.GetConflict(open[0].ID); c == nil || c.Status != ConflictOpen || c.ValueA != "12 m" || c.Resolution != nil {
+		t.Errorf("reopened conflict = %+v", c)
+	}
+
+	if err := db.DeleteSpeciesSource("texana", sources[2].SourceID); err != nil {
+		t.Fatalf("DeleteSpeciesSource failed: %v", err)
+	}
+	if all, _ := db.ListConflicts("", "texana", "acorn_maturation"); len(all) != 0 {
+		t.Errorf("conflicts with a deleted source remain: %d", len(all))
+	}
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// GetSpeciesSourceContentHash returns the content hash recorded for a species-source,
+// or "" if none is recorded. The hash is cleared by any SaveSpeciesSource call, so
+// it only matches while the row is unchanged since the hashed write.
+func (db *Database) GetSpeciesSourceContentHash(scientificName string, sourceID int64) (string, error) {
+	var hash sql.NullString
+	err := db.conn.QueryRow(
+		`SELECT content_hash FROM species_sources WHERE scientific_name = ? AND source_id = ?`,
+		scientificName, sourceID,
+	).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get content hash: %w", err)
+	}
+	return hash.String, nil
+}
+
+// SetSpeciesSourceContentHash records the client-supplied content hash for a species-source
+func (db *Database) SetSpeciesSourceContentHash(scientificName string, sourceID int64, hash string) error {
+	_, err := db.conn.Exec(
+		`UPDATE species_sources SET content_hash = ? WHERE scientific_name = ? AND source_id = ?`,
+		hash, scientificName, sourceID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set content hash: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// coverageFields are the species_sources columns reported by SourceCoverage, in display order
+var coverageFields = []string{
+	"local_names", "range", "growth_habit", "leaves", "flowers", "fruits",
+	"bark", "twigs", "buds", "hardiness_habitat", "miscellaneous", "distinguishing_features", "url",
+}
+
+// FieldCoverage is the number of a source's species records that populate a field
+type FieldCoverage struct {
+	Field string `json:"field"`
+	Count int    `json:"count"`
+}
+
+// SourceCoverage reports how completely a source describes the species in its scope.
+// A source's scope is every section it describes at least one species in.
+type SourceCoverage struct {
+	SourceID       int64           `json:"source_id"`
+	SpeciesCount   int             `json:"species_count"`
+	Fields         []FieldCoverage `json:"fields"`
+	ScopeSections  []string        `json:"scope_sections"`
+	ScopeCount     int             `json:"scope_count"` // Species in the scope sections, described or not
+	MissingSpecies []string        `json:"missing_species"`
+}
+
+// GetSourceCoverage computes the coverage report for a source
+func (db *Database) GetSourceCoverage(sourceID int64) (*SourceCoverage, error) {
+	cov := &SourceCoverage{
+		SourceID:       sourceID,
+		Fields:         make([]FieldCoverage, 0, len(coverageFields)),
+		ScopeSections:  []string{},
+		MissingSpecies: []string{},
+	}
+
+	// One pass over the source's rows counts species and every populated field
+	counts := make([]string, len(coverageFields))
+	for i, f := range coverageFields {
+		if f == "local_names" {
+			counts[i] = `COALESCE(SUM(local_names IS NOT NULL AND local_names NOT IN ('', '[]', 'null')), 0)`
+		} else {
+			counts[i] = fmt.Sprintf(`COALESCE(SUM(%s IS NOT NULL AND %s != ''), 0)`, f, f)
+		}
+	}
+	query := `SELECT COUNT(*), ` + strings.Join(counts, ", ") + ` FROM species_sources WHERE source_id = ?`
+
+	fieldCounts := make([]int, len(coverageFields))
+	dest := []interface{}{&cov.SpeciesCount}
+	for i := range fieldCounts {
+		dest = append(dest, &fieldCounts[i])
+	}
+	if err := db.conn.QueryRow(query, sourceID).Scan(dest...); err != nil {
+		return nil, fmt.Errorf("failed to count source fields: %w", err)
+	}
+	for i, f := range coverageFields {
+		cov.Fields = append(cov.Fields, FieldCoverage{Field: f, Count: fieldCounts[i]})
+	}
+
+	rows, err := db.conn.Query(
+		`SELECT DISTINCT o.section FROM oak_entries o
+		 JOIN species_sources ss ON ss.scientific_name = o.scientific_name
+		 WHERE ss.source_id = ? AND o.section IS NOT NULL AND o.section != ''
+		 ORDER BY o.section`,
+		sourceID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source scope: %w", err)
+	}
+	for rows.Next() {
+		var section string
+		if err := rows.Scan(&section); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan source scope: %w", err)
+		}
+		cov.ScopeSections = append(cov.ScopeSections, section)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(cov.ScopeSections) == 0 {
+		return cov, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(cov.ScopeSections)), ",")
+	args := make([]interface{}, 0, len(cov.ScopeSections)+1)
+	for _, s := range cov.ScopeSections {
+		args = append(args, s)
+	}
+
+	if err := db.conn.QueryRow(
+		`SELECT COUNT(*) FROM oak_entries WHERE section IN (`+placeholders+`)`, args...,
+	).Scan(&cov.ScopeCount); err != nil {
+		return nil, fmt.Errorf("failed to count scope species: %w", err)
+	}
+
+	args = append(args, sourceID)
+	rows, err = db.conn.Query(
+		`SELECT scientific_name FROM oak_entries
+		 WHERE section IN (`+placeholders+`)
+		   AND scientific_name NOT IN (SELECT scientific_name FROM species_sources WHERE source_id = ?)
+		 ORDER BY scientific_name`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get missing species: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan missing species: %w", err)
+		}
+		cov.MissingSpecies = append(cov.MissingSpecies, name)
+	}
+
+	return cov, rows.Err()
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestGetSourceCoverage(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	lobatae, quercus := "Lobatae", "Quercus"
+	for name, section := range map[string]*string{
+		"rubra": &lobatae, "velutina": &lobatae, "coccinea": &lobatae,
+		"alba": &quercus, "chinquapin": nil,
+	} {
+		entry := models.NewOakEntry(name)
+		entry.Section = section
+		if err := db.SaveOakEntry(entry); err != nil {
+			t.Fatalf("SaveOakEntry(%s) failed: %v", name, err)
+		}
+	}
+
+	sourceID, err := db.InsertSource(&models.Source{SourceType: "website", Name: "Red Oaks"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+
+	leaves := "Bristle-tipped lobes"
+	rubra := models.NewSpeciesSource("rubra", sourceID)
+	rubra.Leaves = &leaves
+	rubra.LocalNames = []string{"northern red oak"}
+	if err := db.SaveSpeciesSource(rubra); err != nil {
+		t.Fatalf("SaveSpeciesSource failed: %v", err)
+	}
+	if err := db.SaveSpeciesSource(models.NewSpeciesSource("velutina", sourceID)); err != nil {
+		t.Fatalf("SaveSpeciesSource failed: %v", err)
+	}
+
+	cov, err := db.GetSourceCoverage(sourceID)
+	if err != nil {
+		t.Fatalf("GetSourceCoverage failed: %v", err)
+	}
+
+	if cov.SpeciesCount != 2 {
+		t.Errorf("SpeciesCount = %d, want 2", cov.SpeciesCount)
+	}
+	fields := make(map[string]int)
+	for _, f := range cov.Fields {
+		fields[f.Field] = f.Count
+	}
+	if fields["leaves"] != 1 || fields["local_names"] != 1 || fields["bark"] != 0 {
+		t.Errorf("fields = %v, want leaves=1 local_names=1 bark=0", fields)
+	}
+	if len(cov.ScopeSections) != 1 || cov.ScopeSections[0] != "Lobatae" {
+		t.Errorf("ScopeSections = %v, want [Lobatae]", cov.ScopeSections)
+	}
+	if cov.ScopeCount != 3 {
+		t.Errorf("ScopeCount = %d, want 3", cov.ScopeCount)
+	}
+	if len(cov.MissingSpecies) != 1 || cov.MissingSpecies[0] != "coccinea" {
+		t.Errorf("MissingSpecies = %v, want [coccinea]", cov.MissingSpecies)
+	}
+}
+
+func TestGetSourceCoverageEmpty(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	cov, err := db.GetSourceCoverage(42)
+	if err != nil {
+		t.Fatalf("GetSourceCoverage failed: %v", err)
+	}
+	if cov.SpeciesCount != 0 || len(cov.MissingSpecies) != 0 || len(cov.Fields) == 0 {
+		t.Errorf("coverage = %+v, want empty counts with field list", cov)
+	}
+}
@@ -0,0 +1,200 @@
+package db
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/jeff/oaks/api/internal/markdown"
+	"github.com/jeff/oaks/api/internal/mentions"
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// wikiLinkPattern matches [[name]] and [[name|label]] account links, capturing the name
+var wikiLinkPattern = regexp.MustCompile(`\[\[\s*([^\]|]*?)\s*(?:\|[^\]]*)?\]\]`)
+
+// MentionResolver returns a resolver for every species in the database
+func (db *Database) MentionResolver() (*mentions.Resolver, error) {
+	rows, err := db.conn.Query(`SELECT scientific_name, genus FROM oak_entries`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list species for mentions: %w", err)
+	}
+	defer rows.Close()
+
+	var species []mentions.Species
+	for rows.Next() {
+		var s mentions.Species
+		if err := rows.Scan(&s.Name, &s.Genus); err != nil {
+			return nil, err
+		}
+		species = append(species, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return mentions.NewResolver(species), nil
+}
+
+// ListMentions returns the cross-references to a species (its backlinks),
+// ordered by the mentioning species and location
+func (db *Database) ListMentions(mentionedName string) ([]*models.CrossReference, error) {
+	rows, err := db.conn.Query(
+		`SELECT scientific_name, mentioned_name, location, context FROM cross_references
+		 WHERE mentioned_name = ? ORDER BY scientific_name, location`,
+		mentionedName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mentions: %w", err)
+	}
+	defer rows.Close()
+
+	var refs []*models.CrossReference
+	for rows.Next() {
+		var ref models.CrossReference
+		if err := rows.Scan(&ref.ScientificName, &ref.MentionedName, &ref.Location, &ref.Context); err != nil {
+			return nil, err
+		}
+		refs = append(refs, &ref)
+	}
+	return refs, rows.Err()
+}
+
+// RefreshCrossReferences re-detects the species mentioned in the given species'
+// accounts and source notes and replaces their stored cross-references.
+// Call it after writing either; mentions of species created later are picked
+// up by the next refresh or by Reindex.
+func (db *Database) RefreshCrossReferences(scientificNames ...string) error {
+	resolver, err := db.MentionResolver()
+	if err != nil {
+		return err
+	}
+	for _, name := range scientificNames {
+		if _, err := db.refreshCrossReferences(resolver, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebuildCrossReferences re-detects mentions for every species.
+// Returns the number of cross-references stored.
+func (db *Database) rebuildCrossReferences() (int, error) {
+	resolver, err := db.MentionResolver()
+	if err != nil {
+		return 0, err
+	}
+	var names []string
+	rows, err := db.conn.Query(`SELECT scientific_name FROM oak_entries ORDER BY scientific_name`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list species: %w", err)
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if _, err := db.conn.Exec(`DELETE FROM cross_references`); err != nil {
+		return 0, fmt.Errorf("failed to clear cross references: %w", err)
+	}
+
+	total := 0
+	for _, name := range names {
+		n, err := db.refreshCrossReferences(resolver, name)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// refreshCrossReferences replaces one species' cross-references and returns how many were stored
+func (db *Database) refreshCrossReferences(resolver *mentions.Resolver, scientificName string) (int, error) {
+	refs, err := db.detectCrossReferences(resolver, scientificName)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM cross_references WHERE scientific_name = ?`, scientificName); err != nil {
+		return 0, fmt.Errorf("failed to clear cross references: %w", err)
+	}
+	for _, ref := range refs {
+		_, err := tx.Exec(
+			`INSERT INTO cross_references (scientific_name, mentioned_name, location, context)
+			 VALUES (?, ?, ?, ?)`,
+			ref.ScientificName, ref.MentionedName, ref.Location, ref.Context,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to save cross reference: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit cross references: %w", err)
+	}
+	return len(refs), nil
+}
+
+// detectCrossReferences finds the species mentioned in one species' account
+// and source notes. Self-mentions are skipped.
+func (db *Database) detectCrossReferences(resolver *mentions.Resolver, scientificName string) ([]*models.CrossReference, error) {
+	var refs []*models.CrossReference
+	seen := map[[2]string]bool{}
+	add := func(location, text string, spans []markdown.Span) {
+		for _, span := range spans {
+			key := [2]string{span.Name, location}
+			if span.Name == scientificName || seen[key] {
+				continue
+			}
+			seen[key] = true
+			refs = append(refs, &models.CrossReference{
+				ScientificName: scientificName,
+				MentionedName:  span.Name,
+				Location:       location,
+				Context:        mentions.Context(text, span),
+			})
+		}
+	}
+
+	account, err := db.GetSpeciesAccount(scientificName)
+	if err != nil {
+		return nil, err
+	}
+	if account != nil {
+		add("account", account.Markdown, resolver.Find(account.Markdown))
+
+		var spans []markdown.Span
+		for _, m := range wikiLinkPattern.FindAllStringSubmatchIndex(account.Markdown, -1) {
+			if name := account.Markdown[m[2]:m[3]]; resolver.Known(name) {
+				spans = append(spans, markdown.Span{Start: m[0], End: m[1], Name: name})
+			}
+		}
+		add("account", account.Markdown, spans)
+	}
+
+	sources, err := db.GetSpeciesSources(scientificName)
+	if err != nil {
+		return nil, err
+	}
+	for _, ss := range sources {
+		for _, f := range ss.TextFields() {
+			if f.Value == nil || *f.Value == "" {
+				continue
+			}
+			location := models.TextLocation(ss.SourceID, f.Name)
+			add(location, *f.Value, resolver.Find(*f.Value))
+		}
+	}
+	return refs, nil
+}
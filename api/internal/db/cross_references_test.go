@@ -0,0 +1,69 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestCrossReferences(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	for _, name := range []string{"alba", "stellata", "rubra"} {
+		if err := db.SaveOakEntry(&models.OakEntry{ScientificName: name}); err != nil {
+			t.Fatalf("SaveOakEntry(%s) failed: %v", name, err)
+		}
+	}
+	sourceID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Oaks of North America"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+
+	leaves := "Closely resembles Q. stellata, but the lobes are deeper. Unlike Q. alba itself."
+	ss := models.NewSpeciesSource("alba", sourceID)
+	ss.Leaves = &leaves
+	if err := db.SaveSpeciesSource(ss); err != nil {
+		t.Fatalf("SaveSpeciesSource failed: %v", err)
+	}
+	if _, err := db.SaveSpeciesAccount(&models.SpeciesAccount{ScientificName: "rubra", Markdown: "Compare [[stellata|post oak]] and Quercus stellata."}); err != nil {
+		t.Fatalf("SaveSpeciesAccount failed: %v", err)
+	}
+	if err := db.RefreshCrossReferences("alba", "rubra"); err != nil {
+		t.Fatalf("RefreshCrossReferences failed: %v", err)
+	}
+
+	refs, err := db.ListMentions("stellata")
+	if err != nil {
+		t.Fatalf("ListMentions failed: %v", err)
+	}
+	if len(refs) != 2 {
+		t.Fatalf("got %d mentions of stellata, want 2: %+v", len(refs), refs)
+	}
+	if refs[0].ScientificName != "alba" || refs[0].Location != "sources/1/leaves" || refs[0].Context == "" {
+		t.Errorf("first mention = %+v", refs[0])
+	}
+	if refs[1].ScientificName != "rubra" || refs[1].Location != "account" {
+		t.Errorf("second mention = %+v", refs[1])
+	}
+
+	// Self-mentions are skipped
+	if refs, _ := db.ListMentions("alba"); len(refs) != 0 {
+		t.Errorf("alba mentions = %+v, want none", refs)
+	}
+
+	// Reindex rebuilds from scratch, and deleting a species drops its mentions
+	if _, err := db.conn.Exec(`DELETE FROM cross_references`); err != nil {
+		t.Fatal(err)
+	}
+	items, err := db.rebuildCrossReferences()
+	if err != nil || items != 2 {
+		t.Fatalf("rebuildCrossReferences = %d, %v; want 2", items, err)
+	}
+	if err := db.DeleteOakEntry("rubra"); err != nil {
+		t.Fatalf("DeleteOakEntry failed: %v", err)
+	}
+	if refs, _ := db.ListMentions("stellata"); len(refs) != 1 {
+		t.Errorf("stellata mentions after delete = %+v, want 1", refs)
+	}
+}
@@ -0,0 +1,297 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Custom field types
+const (
+	CustomFieldText    = "text"
+	CustomFieldNumber  = "number"
+	CustomFieldBoolean = "boolean"
+	CustomFieldDate    = "date" // YYYY-MM-DD
+	CustomFieldURL     = "url"  // An absolute http or https URL
+)
+
+// CustomFieldTypes lists the custom field types
+var CustomFieldTypes = []string{CustomFieldText, CustomFieldNumber, CustomFieldBoolean, CustomFieldDate, CustomFieldURL}
+
+// Records custom fields apply to
+const (
+	CustomEntitySpecies = "species"
+	CustomEntitySource  = "source"
+)
+
+// ErrCustomFieldInUse is returned when changing the type or entity of a
+// field that has values
+var ErrCustomFieldInUse = errors.New("custom field has values")
+
+// customFieldKey is the form of a custom field key
+var customFieldKey = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// CustomField is a field a deployment defines for its own metadata
+type CustomField struct {
+	Key       string    `json:"key"`
+	Type      string    `json:"type"`
+	Label     string    `json:"label"`
+	AppliesTo string    `json:"applies_to"`
+	Export    bool      `json:"export"` // Included in /export
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ValidCustomFieldKey reports whether key is lowercase letters, digits, and
+// underscores, starting with a letter
+func ValidCustomFieldKey(key string) bool {
+	return customFieldKey.MatchString(key)
+}
+
+// ListCustomFields returns the custom fields applying to entity, or all of
+// them if entity is empty, by key
+func (db *Database) ListCustomFields(entity string) ([]*CustomField, error) {
+	rows, err := db.conn.Query(
+		`SELECT key, type, label, applies_to, export, created_at FROM custom_fields
+		 WHERE ?1 = '' OR applies_to = ?1 ORDER BY key`, entity,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list custom fields: %w", err)
+	}
+	defer rows.Close()
+
+	fields := []*CustomField{}
+	for rows.Next() {
+		f, err := scanCustomField(rows)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	return fields, rows.Err()
+}
+
+// GetCustomField returns the field with this key, or nil if there is none
+func (db *Database) GetCustomField(key string) (*CustomField, error) {
+	f, err := scanCustomField(db.conn.QueryRow(
+		`SELECT key, type, label, applies_to, export, created_at FROM custom_fields WHERE key = ?`, key,
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return f, err
+}
+
+// PutCustomField creates a field or updates its label and export flag. Its
+// type and entity can only change while no record has a value for it;
+// otherwise ErrCustomFieldInUse is returned.
+func (db *Database) PutCustomField(field *CustomField) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	var typ, appliesTo, createdAt string
+	err = tx.QueryRow(`SELECT type, applies_to, created_at FROM custom_fields WHERE key = ?`, field.Key).Scan(&typ, &appliesTo, &createdAt)
+	switch {
+	case err == sql.ErrNoRows:
+		field.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	case err != nil:
+		return fmt.Errorf("failed to get custom field: %w", err)
+	default:
+		if typ != field.Type || appliesTo != field.AppliesTo {
+			var used bool
+			if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM custom_attributes WHERE key = ?)`, field.Key).Scan(&used); err != nil {
+				return fmt.Errorf("failed to check custom field use: %w", err)
+			}
+			if used {
+				return ErrCustomFieldInUse
+			}
+		}
+		if field.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return fmt.Errorf("failed to parse created_at: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO custom_fields (key, type, label, applies_to, export, created_at) VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (key) DO UPDATE SET type = excluded.type, label = excluded.label,
+			applies_to = excluded.applies_to, export = excluded.export`,
+		field.Key, field.Type, field.Label, field.AppliesTo, field.Export, field.CreatedAt.Format(timestampFormat),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save custom field: %w", err)
+	}
+	return tx.Commit()
+}
+
+// DeleteCustomField deletes a field and every value of it. Reports whether
+// the field existed.
+func (db *Database) DeleteCustomField(key string) (bool, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	if _, err := tx.Exec(`DELETE FROM custom_attributes WHERE key = ?`, key); err != nil {
+		return false, fmt.Errorf("failed to delete custom field values: %w", err)
+	}
+	res, err := tx.Exec(`DELETE FROM custom_fields WHERE key = ?`, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete custom field: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, tx.Commit()
+}
+
+// CustomValue checks a value against its field's type and returns it as
+// stored. Values are as decoded from JSON: numbers are float64 and booleans
+// bool, and the other types are strings.
+func CustomValue(field *CustomField, value any) (string, error) {
+	switch field.Type {
+	case CustomFieldNumber:
+		if n, ok := value.(float64); ok {
+			return strconv.FormatFloat(n, 'g', -1, 64), nil
+		}
+		return "", errors.New("must be a number")
+	case CustomFieldBoolean:
+		if b, ok := value.(bool); ok {
+			return strconv.FormatBool(b), nil
+		}
+		return "", errors.New("must be true or false")
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return "", errors.New("must be a string")
+	}
+	switch field.Type {
+	case CustomFieldDate:
+		if _, err := time.Parse(time.DateOnly, s); err != nil {
+			return "", errors.New("must be a date as YYYY-MM-DD")
+		}
+	case CustomFieldURL:
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", errors.New("must be an http or https URL")
+		}
+	}
+	return s, nil
+}
+
+// customValue converts a stored value back to its type
+func customValue(typ, stored string) any {
+	switch typ {
+	case CustomFieldNumber:
+		if n, err := strconv.ParseFloat(stored, 64); err == nil {
+			return n
+		}
+	case CustomFieldBoolean:
+		if b, err := strconv.ParseBool(stored); err == nil {
+			return b
+		}
+	}
+	return stored
+}
+
+// GetCustomAttributes returns a record's custom field values by key. The
+// record is a species name or a source ID.
+func (db *Database) GetCustomAttributes(entity, id string) (map[string]any, error) {
+	all, err := db.customAttributes(entity, id, false)
+	if err != nil {
+		return nil, err
+	}
+	if values := all[id]; values != nil {
+		return values, nil
+	}
+	return map[string]any{}, nil
+}
+
+// ExportedCustomAttributes returns the values of exported custom fields of
+// every record of entity, by record and then key
+func (db *Database) ExportedCustomAttributes(entity string) (map[string]map[string]any, error) {
+	return db.customAttributes(entity, "", true)
+}
+
+// customAttributes returns values by record and key, for one record or,
+// if id is empty, all of entity
+func (db *Database) customAttributes(entity, id string, exportedOnly bool) (map[string]map[string]any, error) {
+	rows, err := db.conn.Query(
+		`SELECT a.entity_id, a.key, a.value, f.type
+		   FROM custom_attributes a JOIN custom_fields f ON f.key = a.key AND f.applies_to = a.entity
+		  WHERE a.entity = ?1 AND (?2 = '' OR a.entity_id = ?2) AND (?3 = 0 OR f.export = 1)`,
+		entity, id, exportedOnly,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom attributes: %w", err)
+	}
+	defer rows.Close()
+
+	values := make(map[string]map[string]any)
+	for rows.Next() {
+		var recordID, key, value, typ string
+		if err := rows.Scan(&recordID, &key, &value, &typ); err != nil {
+			return nil, fmt.Errorf("failed to scan custom attribute: %w", err)
+		}
+		if values[recordID] == nil {
+			values[recordID] = make(map[string]any)
+		}
+		values[recordID][key] = customValue(typ, value)
+	}
+	return values, rows.Err()
+}
+
+// SetCustomAttributes sets a record's values for the given keys, already
+// checked with CustomValue; a nil value removes the key. Other keys are
+// left as they are.
+func (db *Database) SetCustomAttributes(entity, id string, values map[string]*string) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	for _, key := range keys {
+		if values[key] == nil {
+			_, err = tx.Exec(`DELETE FROM custom_attributes WHERE entity = ? AND entity_id = ? AND key = ?`, entity, id, key)
+		} else {
+			_, err = tx.Exec(
+				`INSERT INTO custom_attributes (entity, entity_id, key, value) VALUES (?, ?, ?, ?)
+				 ON CONFLICT (entity, entity_id, key) DO UPDATE SET value = excluded.value`,
+				entity, id, key, *values[key],
+			)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to set custom attribute %s: %w", key, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func scanCustomField(row rowScanner) (*CustomField, error) {
+	var f CustomField
+	var createdAt string
+	if err := row.Scan(&f.Key, &f.Type, &f.Label, &f.AppliesTo, &f.Export, &createdAt); err != nil {
+		return nil, err
+	}
+	var err error
+	if f.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	return &f, nil
+}
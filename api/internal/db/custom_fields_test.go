@@ -0,0 +1,79 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestCustomFields(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	accession := &CustomField{Key: "accession_policy", Type: CustomFieldText, Label: "Accession policy", AppliesTo: CustomEntitySpecies, Export: true}
+	height := &CustomField{Key: "max_height_m", Type: CustomFieldNumber, Label: "Maximum height (m)", AppliesTo: CustomEntitySpecies}
+	for _, f := range []*CustomField{accession, height} {
+		if err := db.PutCustomField(f); err != nil {
+			t.Fatalf("PutCustomField(%s) failed: %v", f.Key, err)
+		}
+	}
+	if fields, err := db.ListCustomFields(CustomEntitySource); err != nil || len(fields) != 0 {
+		t.Errorf("ListCustomFields(source) = %v, %v; want none", fields, err)
+	}
+
+	if err := db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := CustomValue(accession, "Wild-collected only")
+	if err != nil {
+		t.Fatal(err)
+	}
+	meters, err := CustomValue(height, 30.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CustomValue(height, "tall"); err == nil {
+		t.Error("CustomValue(number, string) succeeded, want an error")
+	}
+	if err := db.SetCustomAttributes(CustomEntitySpecies, "alba", map[string]*string{"accession_policy": &policy, "max_height_m": &meters}); err != nil {
+		t.Fatalf("SetCustomAttributes failed: %v", err)
+	}
+
+	values, err := db.GetCustomAttributes(CustomEntitySpecies, "alba")
+	if err != nil {
+		t.Fatalf("GetCustomAttributes failed: %v", err)
+	}
+	if values["accession_policy"] != "Wild-collected only" || values["max_height_m"] != 30.5 {
+		t.Errorf("values = %v", values)
+	}
+	exported, err := db.ExportedCustomAttributes(CustomEntitySpecies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exported["alba"]) != 1 || exported["alba"]["accession_policy"] == nil {
+		t.Errorf("exported = %v, want only accession_policy", exported)
+	}
+
+	// A field with values keeps its type
+	changed := *height
+	changed.Type = CustomFieldText
+	if err := db.PutCustomField(&changed); !errors.Is(err, ErrCustomFieldInUse) {
+		t.Errorf("PutCustomField(type change) = %v, want ErrCustomFieldInUse", err)
+	}
+
+	// Values go with their record
+	if err := db.DeleteOakEntry("alba"); err != nil {
+		t.Fatal(err)
+	}
+	if values, _ := db.GetCustomAttributes(CustomEntitySpecies, "alba"); len(values) != 0 {
+		t.Errorf("values after deleting alba = %v, want none", values)
+	}
+
+	if found, err := db.DeleteCustomField("max_height_m"); err != nil || !found {
+		t.Errorf("DeleteCustomField = %v, %v; want true", found, err)
+	}
+	if f, _ := db.GetCustomField("max_height_m"); f != nil {
+		t.Error("max_height_m still exists after deleting it")
+	}
+}
@@ -0,0 +1,156 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// DataError is a stored JSON column that does not decode to its expected shape
+type DataError struct {
+	Table  string `json:"table"`
+	Key    string `json:"key"` // Row's scientific name
+	Column string `json:"column"`
+	Error  string `json:"error"`
+	Value  string `json:"value"` // Raw stored value, kept so it can be fixed by hand
+}
+
+// SetLogger sets where read-time data problems and slow queries are logged.
+// The default discards them.
+func (db *Database) SetLogger(logger *slog.Logger) {
+	db.logger = logger
+	db.queries.mu.Lock()
+	db.queries.logger = logger
+	db.queries.mu.Unlock()
+}
+
+// decodeJSONList decodes a JSON array column; NULL decodes to an empty list.
+// On error the list is empty rather than partially decoded.
+func decodeJSONList[T any](raw sql.NullString) ([]T, error) {
+	list := []T{}
+	if !raw.Valid {
+		return list, nil
+	}
+	var decoded []T
+	if err := json.Unmarshal([]byte(raw.String), &decoded); err != nil {
+		return list, err
+	}
+	if decoded != nil {
+		list = decoded
+	}
+	return list, nil
+}
+
+// oakEntryJSON holds an oak entry's JSON columns as read from the database
+type oakEntryJSON struct {
+	hybrids, related, subspecies, synonyms, externalLinks sql.NullString
+}
+
+// decodeOakEntryJSON fills an entry's list fields from their JSON columns. A
+// column that fails to decode is left empty and quarantined: it is logged and
+// named in entry.DataError, and the read goes on, so one corrupted row cannot
+// fail a whole list. RepairJSONColumns resets such columns.
+func (db *Database) decodeOakEntryJSON(entry *models.OakEntry, cols *oakEntryJSON) {
+	var problems []string
+	quarantine := func(column string, err error) {
+		if err == nil {
+			return
+		}
+		problems = append(problems, column+": "+err.Error())
+		db.logger.Warn("quarantined malformed JSON column",
+			"table", "oak_entries", "key", entry.ScientificName, "column", column, "error", err)
+	}
+
+	var err error
+	entry.Hybrids, err = decodeJSONList[string](cols.hybrids)
+	quarantine("hybrids", err)
+	entry.CloselyRelatedTo, err = decodeJSONList[string](cols.related)
+	quarantine("closely_related_to", err)
+	entry.SubspeciesVarieties, err = decodeJSONList[string](cols.subspecies)
+	quarantine("subspecies_varieties", err)
+	entry.Synonyms, err = decodeJSONList[string](cols.synonyms)
+	quarantine("synonyms", err)
+	entry.ExternalLinks, err = decodeJSONList[models.ExternalLink](cols.externalLinks)
+	quarantine("external_links", err)
+
+	if len(problems) > 0 {
+		entry.DataError = strings.Join(problems, "; ")
+	}
+}
+
+// findOakEntryDataErrors checks every oak entry's JSON columns
+func findOakEntryDataErrors(q interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}) ([]DataError, error) {
+	rows, err := q.Query(
+		`SELECT scientific_name, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links
+		 FROM oak_entries ORDER BY scientific_name`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check oak entry JSON columns: %w", err)
+	}
+	defer rows.Close()
+
+	found := []DataError{}
+	for rows.Next() {
+		var name string
+		var cols oakEntryJSON
+		if err := rows.Scan(&name, &cols.hybrids, &cols.related, &cols.subspecies, &cols.synonyms, &cols.externalLinks); err != nil {
+			return nil, fmt.Errorf("failed to scan oak entry JSON columns: %w", err)
+		}
+
+		check := func(column string, raw sql.NullString, err error) {
+			if err != nil {
+				found = append(found, DataError{Table: "oak_entries", Key: name, Column: column, Error: err.Error(), Value: raw.String})
+			}
+		}
+		_, err := decodeJSONList[string](cols.hybrids)
+		check("hybrids", cols.hybrids, err)
+		_, err = decodeJSONList[string](cols.related)
+		check("closely_related_to", cols.related, err)
+		_, err = decodeJSONList[string](cols.subspecies)
+		check("subspecies_varieties", cols.subspecies, err)
+		_, err = decodeJSONList[string](cols.synonyms)
+		check("synonyms", cols.synonyms, err)
+		_, err = decodeJSONList[models.ExternalLink](cols.externalLinks)
+		check("external_links", cols.externalLinks, err)
+	}
+	return found, rows.Err()
+}
+
+// FindDataErrors lists stored JSON columns that do not decode
+func (db *Database) FindDataErrors() ([]DataError, error) {
+	return findOakEntryDataErrors(db.conn)
+}
+
+// RepairJSONColumns resets every JSON column that does not decode to an empty
+// list, in one transaction. Returns what was reset, with the original values.
+func (db *Database) RepairJSONColumns() ([]DataError, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	found, err := findOakEntryDataErrors(tx)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range found {
+		// Column names come from findOakEntryDataErrors, never from input
+		if _, err := tx.Exec(
+			`UPDATE oak_entries SET `+e.Column+` = '[]' WHERE scientific_name = ?`, e.Key,
+		); err != nil {
+			return nil, fmt.Errorf("failed to repair %s for %s: %w", e.Column, e.Key, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit JSON column repair: %w", err)
+	}
+	return found, nil
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestQuarantineMalformedJSON(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	for _, name := range []string{"alba", "rubra"} {
+		entry := models.NewOakEntry(name)
+		entry.Synonyms = []string{name + " syn"}
+		if err := db.SaveOakEntry(entry); err != nil {
+			t.Fatalf("SaveOakEntry(%s) failed: %v", name, err)
+		}
+	}
+	if _, err := db.conn.Exec(`UPDATE oak_entries SET closely_related_to = '["velutina"' WHERE scientific_name = 'alba'`); err != nil {
+		t.Fatal(err)
+	}
+
+	// A corrupt row no longer fails the whole list
+	entries, err := db.ListOakEntries()
+	if err != nil {
+		t.Fatalf("ListOakEntries failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("ListOakEntries returned %d entries, want 2", len(entries))
+	}
+	alba, rubra := entries[0], entries[1]
+	if alba.DataError == "" || len(alba.CloselyRelatedTo) != 0 {
+		t.Errorf("alba = data_error %q, closely_related_to %v, want marker and empty list", alba.DataError, alba.CloselyRelatedTo)
+	}
+	if len(alba.Synonyms) != 1 {
+		t.Errorf("alba synonyms = %v, want the intact column still decoded", alba.Synonyms)
+	}
+	if rubra.DataError != "" {
+		t.Errorf("rubra data_error = %q, want none", rubra.DataError)
+	}
+
+	got, err := db.GetOakEntry("alba")
+	if err != nil || got.DataError == "" {
+		t.Errorf("GetOakEntry = %v, %v, want the entry with a data_error", got, err)
+	}
+
+	found, err := db.FindDataErrors()
+	if err != nil {
+		t.Fatalf("FindDataErrors failed: %v", err)
+	}
+	if len(found) != 1 || found[0].Key != "alba" || found[0].Column != "closely_related_to" || found[0].Value != `["velutina"` {
+		t.Fatalf("FindDataErrors = %+v, want alba closely_related_to", found)
+	}
+
+	repaired, err := db.RepairJSONColumns()
+	if err != nil {
+		t.Fatalf("RepairJSONColumns failed: %v", err)
+	}
+	if len(repaired) != 1 {
+		t.Errorf("repaired = %+v, want one column", repaired)
+	}
+	if got, _ := db.GetOakEntry("alba"); got.DataError != "" {
+		t.Errorf("data_error after repair = %q, want none", got.DataError)
+	}
+	if found, _ := db.FindDataErrors(); len(found) != 0 {
+		t.Errorf("FindDataErrors after repair = %+v, want none", found)
+	}
+}
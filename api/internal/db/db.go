@@ -3,12 +3,17 @@ package db
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/jeff/oaks/api/internal/models"
-
-	_ "github.com/mattn/go-sqlite3" // SQLite driver
+	"github.com/jeff/oaks/api/internal/names"
+	"github.com/jeff/oaks/pkg/migrate"
 )
 
 // escapeLike escapes special characters in SQL LIKE patterns.
@@ -31,30 +36,28 @@ func sliceContains(slice []string, value string) bool {
 	return false
 }
 
-// sliceRemove removes a value from a string slice, returning the new slice
-func sliceRemove(slice []string, value string) []string {
-	result := make([]string, 0, len(slice))
-	for _, v := range slice {
-		if v != value {
-			result = append(result, v)
-		}
-	}
-	return result
-}
-
 // Database wraps the SQLite connection
 type Database struct {
-	conn *sql.DB
+	conn     *sql.DB
+	logger   *slog.Logger
+	queries  *queryTimer
+	revision *atomic.Uint64
+	fullText bool // FTS5 is compiled in; see initFullText
+	migrator *migrate.Migrator
+	replicas *replicaSet // Read-only copies for ForReads; see AttachReplicas
 }
 
-// New creates a new database connection and initializes schema
+// New creates a new database connection and initializes schema.
+// Transactions begin IMMEDIATE, taking the write lock up front, so two
+// writers queue on the busy timeout instead of both reading, then
+// deadlocking when one tries to write. Every statement is timed; see
+// SetSlowQueryThreshold.
 func New(dbPath string) (*Database, error) {
-	conn, err := sql.Open("sqlite3", dbPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
-	}
+	queries := newQueryTimer()
+	revision := new(atomic.Uint64)
+	conn := sql.OpenDB(&timedConnector{dsn: withDSNParam(dbPath, "_txlock=immediate"), timer: queries, revision: revision})
 
-	db := &Database{conn: conn}
+	db := &Database{conn: conn, logger: slog.New(slog.NewTextHandler(io.Discard, nil)), queries: queries, revision: revision}
 	if err := db.initializeSchema(); err != nil {
 		conn.Close()
 		return nil, err
@@ -63,8 +66,19 @@ func New(dbPath string) (*Database, error) {
 	return db, nil
 }
 
+// withDSNParam adds a query parameter to a SQLite DSN.
+func withDSNParam(dsn, param string) string {
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + param
+	}
+	return dsn + "?" + param
+}
+
 // Close closes the database connection
 func (db *Database) Close() error {
+	if db.replicas != nil {
+		db.replicas.close()
+	}
 	return db.conn.Close()
 }
 
@@ -73,22 +87,100 @@ func (db *Database) Ping() error {
 	return db.conn.Ping()
 }
 
+// legacyColumns are the columns added to the schema before it was versioned.
+// The CREATE TABLE statements include them; older databases get them here.
+var legacyColumns = []string{
+	`ALTER TABLE oak_entries ADD COLUMN external_links TEXT`,
+	`ALTER TABLE species_sources ADD COLUMN content_hash TEXT`,
+	`ALTER TABLE sources ADD COLUMN superseded_by INTEGER REFERENCES sources(id)`,
+	`ALTER TABLE oak_entries ADD COLUMN visibility TEXT NOT NULL DEFAULT 'published'`,
+	`ALTER TABLE oak_entries ADD COLUMN genus TEXT NOT NULL DEFAULT 'Quercus'`,
+	`ALTER TABLE taxa ADD COLUMN genus TEXT NOT NULL DEFAULT 'Quercus'`,
+	`ALTER TABLE oak_entries ADD COLUMN pronunciation TEXT`,
+	`ALTER TABLE oak_entries ADD COLUMN updated_at TEXT`,
+	`ALTER TABLE sources ADD COLUMN updated_at TEXT`,
+	`ALTER TABLE taxa ADD COLUMN updated_at TEXT`,
+	`ALTER TABLE species_sources ADD COLUMN distinguishing_features TEXT`,
+	`ALTER TABLE oak_entries ADD COLUMN needs_review INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE species_sources ADD COLUMN pages TEXT`,
+	`ALTER TABLE species_sources ADD COLUMN field_pages TEXT`,
+	`ALTER TABLE species_sources ADD COLUMN transcriber TEXT`,
+	`ALTER TABLE species_sources ADD COLUMN transcription_method TEXT`,
+	`ALTER TABLE species_sources ADD COLUMN ocr_confidence REAL`,
+	`ALTER TABLE species_sources ADD COLUMN verified_at TEXT`,
+	`ALTER TABLE species_sources ADD COLUMN verified_by TEXT`,
+	`ALTER TABLE species_sources ADD COLUMN field_visibility TEXT`,
+}
+
 func (db *Database) initializeSchema() error {
+	// Refuse a schema migrated by a newer build before changing anything
+	db.migrator = migrate.New(db.conn, migrate.Schema)
+	version, err := db.migrator.Check()
+	if err != nil {
+		return err
+	}
+
 	statements := []string{
+		// Genera tracked by the database; every entry and taxon belongs to one
+		`CREATE TABLE IF NOT EXISTS genera (
+			name TEXT PRIMARY KEY,
+			common_name TEXT,
+			subgenera TEXT
+		)`,
+		`INSERT OR IGNORE INTO genera (name, common_name, subgenera)
+			VALUES ('Quercus', 'Oaks', '["Quercus","Cerris","Cyclobalanopsis"]')`,
+
+		// Ordered taxonomic ranks below genus. entry_field names the oak_entries
+		// column that places a species at that rank (NULL for ranks species don't record).
+		`CREATE TABLE IF NOT EXISTS taxon_levels (
+			name TEXT PRIMARY KEY,
+			rank INTEGER NOT NULL UNIQUE,
+			plural TEXT,
+			entry_field TEXT
+		)`,
+		`INSERT OR IGNORE INTO taxon_levels (name, rank, plural, entry_field) VALUES
+			('subgenus', 1, 'subgenera', 'subgenus'),
+			('section', 2, 'sections', 'section'),
+			('subsection', 3, 'subsections', 'subsection'),
+			('complex', 4, 'complexes', 'complex')`,
+
 		// Taxa reference table for validation
-		// Hierarchy: Genus (Quercus) -> Subgenus -> Section -> Subsection -> Complex -> Species
+		// Default hierarchy: Genus -> Subgenus -> Section -> Subsection -> Complex -> Species
 		`CREATE TABLE IF NOT EXISTS taxa (
 			name TEXT NOT NULL,
-			level TEXT NOT NULL CHECK(level IN ('subgenus', 'section', 'subsection', 'complex')),
+			level TEXT NOT NULL,
 			parent TEXT,
 			author TEXT,
 			notes TEXT,
 			links TEXT,
+			genus TEXT NOT NULL DEFAULT 'Quercus',
+			updated_at TEXT,
 			PRIMARY KEY (name, level)
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_taxa_level ON taxa(level)`,
 		`CREATE INDEX IF NOT EXISTS idx_taxa_parent ON taxa(parent)`,
 
+		// Species count per taxon, kept current by triggers (see taxa_counts.go)
+		`CREATE TABLE IF NOT EXISTS taxa_counts (
+			name TEXT NOT NULL,
+			level TEXT NOT NULL,
+			species_count INTEGER NOT NULL,
+			PRIMARY KEY (name, level)
+		)`,
+
+		// Entry templates per taxonomic group (JSON arrays for source_ids and required_fields)
+		`CREATE TABLE IF NOT EXISTS templates (
+			name TEXT PRIMARY KEY,
+			description TEXT,
+			genus TEXT NOT NULL DEFAULT 'Quercus',
+			subgenus TEXT,
+			section TEXT,
+			subsection TEXT,
+			complex TEXT,
+			source_ids TEXT,
+			required_fields TEXT
+		)`,
+
 		// Sources table
 		`CREATE TABLE IF NOT EXISTS sources (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -102,7 +194,9 @@ func (db *Database) initializeSchema() error {
 			doi TEXT,
 			notes TEXT,
 			license TEXT,
-			license_url TEXT
+			license_url TEXT,
+			superseded_by INTEGER REFERENCES sources(id),
+			updated_at TEXT
 		)`,
 
 		// Oak entries with taxonomy and hybrid support
@@ -121,11 +215,17 @@ func (db *Database) initializeSchema() error {
 			closely_related_to TEXT,
 			subspecies_varieties TEXT,
 			synonyms TEXT,
-			external_links TEXT
+			external_links TEXT,
+			visibility TEXT NOT NULL DEFAULT 'published',
+			genus TEXT NOT NULL DEFAULT 'Quercus',
+			pronunciation TEXT,
+			updated_at TEXT,
+			needs_review INTEGER NOT NULL DEFAULT 0
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_oak_entries_subgenus ON oak_entries(subgenus)`,
 		`CREATE INDEX IF NOT EXISTS idx_oak_entries_section ON oak_entries(section)`,
 		`CREATE INDEX IF NOT EXISTS idx_oak_entries_hybrid ON oak_entries(is_hybrid)`,
+		`CREATE INDEX IF NOT EXISTS idx_oak_entries_conservation ON oak_entries(conservation_status)`,
 
 		// Species-source junction table for source-attributed descriptive data
 		// One row = everything source X says about species Y
@@ -144,20 +244,330 @@ func (db *Database) initializeSchema() error {
 			buds TEXT,
 			hardiness_habitat TEXT,
 			miscellaneous TEXT,
+			distinguishing_features TEXT,
 			url TEXT,
 			is_preferred INTEGER NOT NULL DEFAULT 0,
+			content_hash TEXT,
+			pages TEXT,
+			field_pages TEXT, -- JSON object of text field name to locator
+			field_visibility TEXT, -- JSON object of text field name to visibility, internal ones only
+			transcriber TEXT,
+			transcription_method TEXT, -- manual, ocr, or import
+			ocr_confidence REAL,
+			verified_at TEXT,
+			verified_by TEXT,
 			FOREIGN KEY (scientific_name) REFERENCES oak_entries(scientific_name) ON DELETE CASCADE,
 			FOREIGN KEY (source_id) REFERENCES sources(id),
 			UNIQUE(scientific_name, source_id)
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_species_sources_name ON species_sources(scientific_name)`,
 		`CREATE INDEX IF NOT EXISTS idx_species_sources_source ON species_sources(source_id)`,
+		// At most one preferred source per species. Writes through SaveSpeciesSource
+		// clear the previous preference first; these guard direct SQL edits.
+		`CREATE TRIGGER IF NOT EXISTS trg_species_sources_one_preferred_insert
+			BEFORE INSERT ON species_sources
+			WHEN NEW.is_preferred = 1 AND EXISTS (
+				SELECT 1 FROM species_sources
+				WHERE scientific_name = NEW.scientific_name AND source_id != NEW.source_id AND is_preferred = 1
+			)
+			BEGIN SELECT RAISE(ABORT, 'species already has a preferred source'); END`,
+		`CREATE TRIGGER IF NOT EXISTS trg_species_sources_one_preferred_update
+			BEFORE UPDATE OF is_preferred ON species_sources
+			WHEN NEW.is_preferred = 1 AND EXISTS (
+				SELECT 1 FROM species_sources
+				WHERE scientific_name = NEW.scientific_name AND source_id != NEW.source_id AND is_preferred = 1
+			)
+			BEGIN SELECT RAISE(ABORT, 'species already has a preferred source'); END`,
+
+		// Long-form markdown accounts, one per species
+		`CREATE TABLE IF NOT EXISTS species_accounts (
+			scientific_name TEXT PRIMARY KEY,
+			body TEXT NOT NULL,
+			updated_at TEXT NOT NULL,
+			FOREIGN KEY (scientific_name) REFERENCES oak_entries(scientific_name) ON DELETE CASCADE
+		)`,
+		// Foreign keys are not enforced on this connection, so drop accounts explicitly
+		`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_delete_account
+			AFTER DELETE ON oak_entries
+			BEGIN DELETE FROM species_accounts WHERE scientific_name = OLD.scientific_name; END`,
+
+		// Vernacular names by BCP 47 language tag, optionally attributed to a source
+		`CREATE TABLE IF NOT EXISTS common_names (
+			scientific_name TEXT NOT NULL,
+			language TEXT NOT NULL,
+			name TEXT NOT NULL,
+			source_id INTEGER REFERENCES sources(id),
+			position INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (scientific_name, language, name)
+		)`,
+		`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_delete_common_names
+			AFTER DELETE ON oak_entries
+			BEGIN DELETE FROM common_names WHERE scientific_name = OLD.scientific_name; END`,
+		// A name outlives the source it was taken from, unattributed
+		`CREATE TRIGGER IF NOT EXISTS trg_sources_delete_common_names
+			AFTER DELETE ON sources
+			BEGIN UPDATE common_names SET source_id = NULL WHERE source_id = OLD.id; END`,
+
+		// Species mentioned in other species' accounts and source notes, derived
+		// by RefreshCrossReferences. location is "account" or "sources/{id}/{field}".
+		`CREATE TABLE IF NOT EXISTS cross_references (
+			scientific_name TEXT NOT NULL,
+			mentioned_name TEXT NOT NULL,
+			location TEXT NOT NULL,
+			context TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (scientific_name, mentioned_name, location)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_cross_references_mentioned ON cross_references(mentioned_name)`,
+		`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_delete_cross_references
+			AFTER DELETE ON oak_entries
+			BEGIN
+				DELETE FROM cross_references
+				WHERE scientific_name = OLD.scientific_name OR mentioned_name = OLD.scientific_name;
+			END`,
+
+		// Lengths parsed from species-source text ("5-12 cm"), derived by
+		// RefreshMeasurements. min_value is NULL for open-ended values ("up to 30 m").
+		`CREATE TABLE IF NOT EXISTS measurements (
+			scientific_name TEXT NOT NULL,
+			source_id INTEGER NOT NULL,
+			field TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			text TEXT NOT NULL,
+			min_value REAL,
+			max_value REAL NOT NULL,
+			unit TEXT NOT NULL,
+			PRIMARY KEY (scientific_name, source_id, field, position)
+		)`,
+		`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_delete_measurements
+			AFTER DELETE ON oak_entries
+			BEGIN
+				DELETE FROM measurements WHERE scientific_name = OLD.scientific_name;
+			END`,
+		`CREATE TRIGGER IF NOT EXISTS trg_species_sources_delete_measurements
+			AFTER DELETE ON species_sources
+			BEGIN
+				DELETE FROM measurements
+				WHERE scientific_name = OLD.scientific_name AND source_id = OLD.source_id;
+			END`,
+
+		// Candidate distinguishing-feature sentences awaiting curator review.
+		// Rejected rows are kept so the same sentence is not suggested again.
+		`CREATE TABLE IF NOT EXISTS feature_suggestions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			scientific_name TEXT NOT NULL,
+			source_id INTEGER NOT NULL,
+			field TEXT NOT NULL,
+			sentence TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			created_at TEXT NOT NULL,
+			reviewed_at TEXT,
+			UNIQUE(scientific_name, source_id, sentence)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_feature_suggestions_status ON feature_suggestions(status, scientific_name)`,
+		`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_delete_feature_suggestions
+			AFTER DELETE ON oak_entries
+			BEGIN
+				DELETE FROM feature_suggestions WHERE scientific_name = OLD.scientific_name;
+			END`,
+		`CREATE TRIGGER IF NOT EXISTS trg_species_sources_delete_feature_suggestions
+			AFTER DELETE ON species_sources
+			BEGIN
+				DELETE FROM feature_suggestions
+				WHERE scientific_name = OLD.scientific_name AND source_id = OLD.source_id;
+			END`,
+
+		// Geocoded localities from species source range text, for review
+		`CREATE TABLE IF NOT EXISTS range_localities (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			scientific_name TEXT NOT NULL,
+			source_id INTEGER NOT NULL,
+			locality TEXT NOT NULL,
+			latitude REAL NOT NULL,
+			longitude REAL NOT NULL,
+			region TEXT,
+			provider TEXT NOT NULL,
+			machine_derived INTEGER NOT NULL DEFAULT 1,
+			status TEXT NOT NULL DEFAULT 'pending',
+			created_at TEXT NOT NULL,
+			reviewed_at TEXT,
+			UNIQUE(scientific_name, source_id, locality)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_range_localities_status ON range_localities(status, scientific_name)`,
+		`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_delete_range_localities
+			AFTER DELETE ON oak_entries
+			BEGIN
+				DELETE FROM range_localities WHERE scientific_name = OLD.scientific_name;
+			END`,
+		`CREATE TRIGGER IF NOT EXISTS trg_species_sources_delete_range_localities
+			AFTER DELETE ON species_sources
+			BEGIN
+				DELETE FROM range_localities
+				WHERE scientific_name = OLD.scientific_name AND source_id = OLD.source_id;
+			END`,
+
+		// Geocoder answers by provider and locality (see geocode.Key), misses included
+		`CREATE TABLE IF NOT EXISTS geocode_cache (
+			provider TEXT NOT NULL,
+			query TEXT NOT NULL,
+			found INTEGER NOT NULL,
+			latitude REAL,
+			longitude REAL,
+			region TEXT,
+			created_at TEXT NOT NULL,
+			PRIMARY KEY (provider, query)
+		)`,
+
+		// Contradictory structured claims (see the claims package) between two
+		// sources for a species, source_a < source_b. Resolved conflicts keep
+		// the curator's resolution and reopen if either value changes.
+		`CREATE TABLE IF NOT EXISTS conflicts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			scientific_name TEXT NOT NULL,
+			property TEXT NOT NULL,
+			source_a INTEGER NOT NULL,
+			value_a TEXT NOT NULL,
+			evidence_a TEXT NOT NULL,
+			source_b INTEGER NOT NULL,
+			value_b TEXT NOT NULL,
+			evidence_b TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'open',
+			preferred_source_id INTEGER,
+			resolution TEXT,
+			justification TEXT,
+			created_at TEXT NOT NULL,
+			resolved_at TEXT,
+			UNIQUE(scientific_name, property, source_a, source_b)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_conflicts_status ON conflicts(status, scientific_name)`,
+		`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_delete_conflicts
+			AFTER DELETE ON oak_entries
+			BEGIN
+				DELETE FROM conflicts WHERE scientific_name = OLD.scientific_name;
+			END`,
+		`CREATE TRIGGER IF NOT EXISTS trg_species_sources_delete_conflicts
+			AFTER DELETE ON species_sources
+			BEGIN
+				DELETE FROM conflicts WHERE scientific_name = OLD.scientific_name
+					AND (source_a = OLD.source_id OR source_b = OLD.source_id);
+			END`,
+
+		// Opt-in per-species daily view counts. No request details are kept.
+		`CREATE TABLE IF NOT EXISTS species_views (
+			scientific_name TEXT NOT NULL,
+			day TEXT NOT NULL,
+			views INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (scientific_name, day)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_species_views_day ON species_views(day)`,
+		`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_delete_species_views
+			AFTER DELETE ON oak_entries
+			BEGIN
+				DELETE FROM species_views WHERE scientific_name = OLD.scientific_name;
+			END`,
+
+		// Authenticated requests per API key (by fingerprint) per day
+		`CREATE TABLE IF NOT EXISTS api_key_usage (
+			key_id TEXT NOT NULL,
+			day TEXT NOT NULL,
+			requests INTEGER NOT NULL DEFAULT 0,
+			last_used_at TEXT NOT NULL,
+			PRIMARY KEY (key_id, day)
+		)`,
+
+		// Scheduled publication of draft species
+		`CREATE TABLE IF NOT EXISTS publish_queue (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			species TEXT NOT NULL,
+			publish_at TEXT NOT NULL,
+			note TEXT,
+			status TEXT NOT NULL DEFAULT 'pending',
+			created_at TEXT NOT NULL,
+			published_at TEXT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_publish_queue_due ON publish_queue(status, publish_at)`,
+
+		// Background jobs run by the server's worker pool
+		`CREATE TABLE IF NOT EXISTS jobs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			kind TEXT NOT NULL,
+			payload TEXT,
+			status TEXT NOT NULL DEFAULT 'queued',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			max_attempts INTEGER NOT NULL DEFAULT 3,
+			run_after TEXT NOT NULL,
+			last_error TEXT,
+			log TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL,
+			started_at TEXT,
+			finished_at TEXT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_jobs_queue ON jobs(status, run_after)`,
+
+		// Multi-request imports: batches are staged here and applied together on commit
+		`CREATE TABLE IF NOT EXISTS import_sessions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			status TEXT NOT NULL DEFAULT 'open',
+			batches INTEGER NOT NULL DEFAULT 0,
+			items INTEGER NOT NULL DEFAULT 0,
+			created_at TEXT NOT NULL,
+			closed_at TEXT
+		)`,
+		`CREATE TABLE IF NOT EXISTS import_session_batches (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			session_id INTEGER NOT NULL REFERENCES import_sessions(id) ON DELETE CASCADE,
+			source_id INTEGER NOT NULL,
+			rows TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_import_session_batches_session ON import_session_batches(session_id, id)`,
+
+		// Standard author abbreviations (IPNI), used to expand species authorities
+		`CREATE TABLE IF NOT EXISTS authors (
+			abbreviation TEXT PRIMARY KEY,
+			full_name TEXT NOT NULL,
+			dates TEXT,
+			ipni_id TEXT
+		)`,
+
+		// Content hashes of entities, keyed by kind and entity key. Rows are
+		// dropped by triggers when the entity is written (see entity_hashes.go).
+		`CREATE TABLE IF NOT EXISTS entity_hashes (
+			kind TEXT NOT NULL,
+			key TEXT NOT NULL,
+			hash TEXT NOT NULL,
+			PRIMARY KEY (kind, key)
+		)`,
 
 		// Import metadata for tracking incremental imports
 		`CREATE TABLE IF NOT EXISTS import_metadata (
 			key TEXT PRIMARY KEY,
 			value TEXT
 		)`,
+
+		// Saved read-only queries (columns is a JSON array of {name, title})
+		`CREATE TABLE IF NOT EXISTS reports (
+			name TEXT PRIMARY KEY,
+			title TEXT NOT NULL,
+			description TEXT,
+			query TEXT NOT NULL,
+			columns TEXT,
+			format TEXT NOT NULL DEFAULT 'csv'
+		)`,
+
+		// Managed vocabularies; one bound to a field restricts its values
+		`CREATE TABLE IF NOT EXISTS vocabularies (
+			name TEXT PRIMARY KEY,
+			description TEXT,
+			field TEXT
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_vocabularies_field ON vocabularies(field) WHERE field IS NOT NULL`,
+		`CREATE TABLE IF NOT EXISTS vocabulary_terms (
+			vocabulary TEXT NOT NULL,
+			term TEXT NOT NULL,
+			definition TEXT,
+			position INTEGER NOT NULL,
+			PRIMARY KEY (vocabulary, term)
+		)`,
 	}
 
 	for _, stmt := range statements {
@@ -165,25 +575,183 @@ func (db *Database) initializeSchema() error {
 			return fmt.Errorf("failed to execute schema statement: %w", err)
 		}
 	}
+	if err := db.seedAuthors(); err != nil {
+		return err
+	}
+	if err := db.seedReports(); err != nil {
+		return err
+	}
+	if err := db.seedVocabularies(); err != nil {
+		return err
+	}
 
-	// Run migrations for new columns (ignore errors if column already exists)
-	migrations := []string{
-		`ALTER TABLE oak_entries ADD COLUMN external_links TEXT`,
+	// Databases created before schema versioning may lack columns added since
+	// (ignore errors if column already exists). Later changes are migrations.
+	if version == 0 {
+		for _, stmt := range legacyColumns {
+			_, _ = db.conn.Exec(stmt) // Ignore error - column may already exist
+		}
 	}
-	for _, stmt := range migrations {
-		_, _ = db.conn.Exec(stmt) // Ignore error - column may already exist
+	if _, err := db.migrator.Up(); err != nil {
+		return err
+	}
+	if err := db.normalizeSourceTypes(); err != nil {
+		return err
 	}
 
+	if err := db.dropTaxaLevelCheck(); err != nil {
+		return err
+	}
+	triggers := concatTriggers(taxaLevelTriggers, updatedAtTriggers, entityHashTriggers, taxaCountTriggers)
+	for _, stmt := range triggers {
+		if _, err := db.conn.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to execute schema statement: %w", err)
+		}
+	}
+	if err := db.fillTaxaCounts(); err != nil {
+		return err
+	}
+	if err := db.initFullText(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// normalizeSourceTypes rewrites stored source types in their canonical form
+// ("Website" becomes website). Types that match none are left as they are.
+func (db *Database) normalizeSourceTypes() error {
+	rows, err := db.conn.Query(`SELECT DISTINCT source_type FROM sources`)
+	if err != nil {
+		return fmt.Errorf("failed to list source types: %w", err)
+	}
+	var types []string
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan source type: %w", err)
+		}
+		types = append(types, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, t := range types {
+		if canonical, ok := models.NormalizeSourceType(t); ok && canonical != t {
+			if _, err := db.conn.Exec(`UPDATE sources SET source_type = ? WHERE source_type = ?`, canonical, t); err != nil {
+				return fmt.Errorf("failed to normalize source type %q: %w", t, err)
+			}
+		}
+	}
 	return nil
 }
 
+// updatedAtTriggers stamp updated_at on species, sources, and taxa when a row
+// is written, for sitemap lastmod. Writing a species' source data or common
+// names touches the species. Updates that set updated_at themselves are left alone.
+var updatedAtTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_updated_insert
+		AFTER INSERT ON oak_entries
+		BEGIN UPDATE oak_entries SET updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE scientific_name = NEW.scientific_name; END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_updated_update
+		AFTER UPDATE ON oak_entries WHEN NEW.updated_at IS OLD.updated_at
+		BEGIN UPDATE oak_entries SET updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE scientific_name = NEW.scientific_name; END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_species_sources_updated_insert
+		AFTER INSERT ON species_sources
+		BEGIN UPDATE oak_entries SET updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE scientific_name = NEW.scientific_name; END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_species_sources_updated_update
+		AFTER UPDATE ON species_sources
+		BEGIN UPDATE oak_entries SET updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE scientific_name = NEW.scientific_name; END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_species_sources_updated_delete
+		AFTER DELETE ON species_sources
+		BEGIN UPDATE oak_entries SET updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE scientific_name = OLD.scientific_name; END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_common_names_updated_insert
+		AFTER INSERT ON common_names
+		BEGIN UPDATE oak_entries SET updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE scientific_name = NEW.scientific_name; END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_common_names_updated_delete
+		AFTER DELETE ON common_names
+		BEGIN UPDATE oak_entries SET updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE scientific_name = OLD.scientific_name; END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_sources_updated_insert
+		AFTER INSERT ON sources
+		BEGIN UPDATE sources SET updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE id = NEW.id; END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_sources_updated_update
+		AFTER UPDATE ON sources WHEN NEW.updated_at IS OLD.updated_at
+		BEGIN UPDATE sources SET updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE id = NEW.id; END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_taxa_updated_insert
+		AFTER INSERT ON taxa
+		BEGIN UPDATE taxa SET updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE name = NEW.name AND level = NEW.level; END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_taxa_updated_update
+		AFTER UPDATE ON taxa WHEN NEW.updated_at IS OLD.updated_at
+		BEGIN UPDATE taxa SET updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE name = NEW.name AND level = NEW.level; END`,
+}
+
+// taxaLevelTriggers restrict taxa.level to the names in taxon_levels.
+var taxaLevelTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS trg_taxa_level_insert
+		BEFORE INSERT ON taxa
+		WHEN NOT EXISTS (SELECT 1 FROM taxon_levels WHERE name = NEW.level)
+		BEGIN SELECT RAISE(ABORT, 'unknown taxon level'); END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_taxa_level_update
+		BEFORE UPDATE OF level ON taxa
+		WHEN NOT EXISTS (SELECT 1 FROM taxon_levels WHERE name = NEW.level)
+		BEGIN SELECT RAISE(ABORT, 'unknown taxon level'); END`,
+}
+
+// dropTaxaLevelCheck rebuilds a taxa table created with the original
+// CHECK(level IN (...)) constraint so that levels come from taxon_levels.
+func (db *Database) dropTaxaLevelCheck() error {
+	var tableSQL string
+	err := db.conn.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'taxa'`).Scan(&tableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to read taxa schema: %w", err)
+	}
+	if !strings.Contains(tableSQL, "CHECK(level IN") {
+		return nil
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmts := []string{
+		`CREATE TABLE taxa_new (
+			name TEXT NOT NULL,
+			level TEXT NOT NULL,
+			parent TEXT,
+			author TEXT,
+			notes TEXT,
+			links TEXT,
+			genus TEXT NOT NULL DEFAULT 'Quercus',
+			updated_at TEXT,
+			PRIMARY KEY (name, level)
+		)`,
+		`INSERT INTO taxa_new (name, level, parent, author, notes, links, genus, updated_at)
+			SELECT name, level, parent, author, notes, links, genus, updated_at FROM taxa`,
+		`DROP TABLE taxa`,
+		`ALTER TABLE taxa_new RENAME TO taxa`,
+		`CREATE INDEX IF NOT EXISTS idx_taxa_level ON taxa(level)`,
+		`CREATE INDEX IF NOT EXISTS idx_taxa_parent ON taxa(parent)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to rebuild taxa table: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
 // InsertSource inserts a new source and returns its ID
 func (db *Database) InsertSource(source *models.Source) (int64, error) {
 	result, err := db.conn.Exec(
-		`INSERT INTO sources (source_type, name, description, author, year, url, isbn, doi, notes, license, license_url)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		`INSERT INTO sources (source_type, name, description, author, year, url, isbn, doi, notes, license, license_url, superseded_by)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		source.SourceType, source.Name, source.Description,
-		source.Author, source.Year, source.URL, source.ISBN, source.DOI, source.Notes, source.License, source.LicenseURL,
+		source.Author, source.Year, source.URL, source.ISBN, source.DOI, source.Notes, source.License, source.LicenseURL, source.SupersededBy,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("failed to insert source: %w", err)
@@ -199,13 +767,13 @@ func (db *Database) InsertSource(source *models.Source) (int64, error) {
 // GetSource gets a source by ID
 func (db *Database) GetSource(id int64) (*models.Source, error) {
 	row := db.conn.QueryRow(
-		`SELECT id, source_type, name, description, author, year, url, isbn, doi, notes, license, license_url
+		`SELECT id, source_type, name, description, author, year, url, isbn, doi, notes, license, license_url, superseded_by
 		 FROM sources WHERE id = ?`,
 		id,
 	)
 
 	var s models.Source
-	err := row.Scan(&s.ID, &s.SourceType, &s.Name, &s.Description, &s.Author, &s.Year, &s.URL, &s.ISBN, &s.DOI, &s.Notes, &s.License, &s.LicenseURL)
+	err := row.Scan(&s.ID, &s.SourceType, &s.Name, &s.Description, &s.Author, &s.Year, &s.URL, &s.ISBN, &s.DOI, &s.Notes, &s.License, &s.LicenseURL, &s.SupersededBy)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -217,25 +785,88 @@ func (db *Database) GetSource(id int64) (*models.Source, error) {
 
 // UpdateSource updates an existing source
 func (db *Database) UpdateSource(source *models.Source) error {
-	_, err := db.conn.Exec(
+	return db.UpdateSourceIfHash(source, "")
+}
+
+// UpdateSourceIfHash updates an existing source only if hash is still its
+// content hash, and fails with ErrEntityChanged otherwise. An empty hash
+// updates unconditionally.
+func (db *Database) UpdateSourceIfHash(source *models.Source, hash string) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkEntityHashTx(tx, HashKindSources, strconv.FormatInt(source.ID, 10), hash); err != nil {
+		return err
+	}
+	_, err = tx.Exec(
 		`UPDATE sources
-		 SET source_type = ?, name = ?, description = ?, author = ?, year = ?, url = ?, isbn = ?, doi = ?, notes = ?, license = ?, license_url = ?
+		 SET source_type = ?, name = ?, description = ?, author = ?, year = ?, url = ?, isbn = ?, doi = ?, notes = ?, license = ?, license_url = ?, superseded_by = ?
 		 WHERE id = ?`,
 		source.SourceType, source.Name, source.Description, source.Author, source.Year,
-		source.URL, source.ISBN, source.DOI, source.Notes, source.License, source.LicenseURL, source.ID,
+		source.URL, source.ISBN, source.DOI, source.Notes, source.License, source.LicenseURL, source.SupersededBy, source.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update source: %w", err)
 	}
-	return nil
+	return tx.Commit()
 }
 
-// ListSources lists all sources
-func (db *Database) ListSources() ([]*models.Source, error) {
-	rows, err := db.conn.Query(
-		`SELECT id, source_type, name, description, author, year, url, isbn, doi, notes, license, license_url
-		 FROM sources ORDER BY name`,
-	)
+// SourceListParams filters and pages ListSources
+type SourceListParams struct {
+	SourceType *string
+	Year       *int
+
+	// Limit caps the number of sources returned after skipping Offset; 0
+	// returns all. CountSources ignores both.
+	Limit  int
+	Offset int
+}
+
+// where builds the WHERE clause for the params' filters
+func (params *SourceListParams) where() (string, []interface{}) {
+	if params == nil {
+		return "", nil
+	}
+	var conditions []string
+	var args []interface{}
+	if params.SourceType != nil {
+		conditions = append(conditions, "source_type = ?")
+		args = append(args, *params.SourceType)
+	}
+	if params.Year != nil {
+		conditions = append(conditions, "year = ?")
+		args = append(args, *params.Year)
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// CountSources counts the sources matching params' filters
+func (db *Database) CountSources(params *SourceListParams) (int, error) {
+	where, args := params.where()
+	var count int
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM sources`+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count sources: %w", err)
+	}
+	return count, nil
+}
+
+// ListSources lists sources by name, optionally filtered and paged; nil params lists all
+func (db *Database) ListSources(params *SourceListParams) ([]*models.Source, error) {
+	where, args := params.where()
+	query := `SELECT id, source_type, name, description, author, year, url, isbn, doi, notes, license, license_url, superseded_by
+		 FROM sources` + where + ` ORDER BY name`
+	if params != nil && params.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, params.Limit, params.Offset)
+	}
+
+	rows, err := db.conn.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list sources: %w", err)
 	}
@@ -244,7 +875,7 @@ func (db *Database) ListSources() ([]*models.Source, error) {
 	var sources []*models.Source
 	for rows.Next() {
 		var s models.Source
-		if err := rows.Scan(&s.ID, &s.SourceType, &s.Name, &s.Description, &s.Author, &s.Year, &s.URL, &s.ISBN, &s.DOI, &s.Notes, &s.License, &s.LicenseURL); err != nil {
+		if err := rows.Scan(&s.ID, &s.SourceType, &s.Name, &s.Description, &s.Author, &s.Year, &s.URL, &s.ISBN, &s.DOI, &s.Notes, &s.License, &s.LicenseURL, &s.SupersededBy); err != nil {
 			return nil, fmt.Errorf("failed to scan source: %w", err)
 		}
 		sources = append(sources, &s)
@@ -280,9 +911,14 @@ func (db *Database) InsertTaxon(taxon *models.Taxon) error {
 		linksJSON = &s
 	}
 
+	genus := taxon.Genus
+	if genus == "" {
+		genus = models.DefaultGenus
+	}
+
 	_, err := db.conn.Exec(
-		`INSERT INTO taxa (name, level, parent, author, notes, links) VALUES (?, ?, ?, ?, ?, ?)`,
-		taxon.Name, string(taxon.Level), taxon.Parent, taxon.Author, taxon.Notes, linksJSON,
+		`INSERT INTO taxa (name, level, parent, author, notes, links, genus) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		taxon.Name, string(taxon.Level), taxon.Parent, taxon.Author, taxon.Notes, linksJSON, genus,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert taxon: %w", err)
@@ -292,6 +928,13 @@ func (db *Database) InsertTaxon(taxon *models.Taxon) error {
 
 // UpdateTaxon updates an existing taxon
 func (db *Database) UpdateTaxon(taxon *models.Taxon) error {
+	return db.UpdateTaxonIfHash(taxon, "")
+}
+
+// UpdateTaxonIfHash updates an existing taxon only if hash is still its
+// content hash, and fails with ErrEntityChanged otherwise. An empty hash
+// updates unconditionally.
+func (db *Database) UpdateTaxonIfHash(taxon *models.Taxon, hash string) error {
 	var linksJSON *string
 	if len(taxon.Links) > 0 {
 		data, err := json.Marshal(taxon.Links)
@@ -302,26 +945,40 @@ func (db *Database) UpdateTaxon(taxon *models.Taxon) error {
 		linksJSON = &s
 	}
 
-	_, err := db.conn.Exec(
-		`UPDATE taxa SET parent = ?, author = ?, notes = ?, links = ? WHERE name = ? AND level = ?`,
-		taxon.Parent, taxon.Author, taxon.Notes, linksJSON, taxon.Name, string(taxon.Level),
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkEntityHashTx(tx, HashKindTaxa, string(taxon.Level)+"/"+taxon.Name, hash); err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`UPDATE taxa SET parent = ?, author = ?, notes = ?, links = ?, genus = COALESCE(NULLIF(?, ''), genus)
+		 WHERE name = ? AND level = ?`,
+		taxon.Parent, taxon.Author, taxon.Notes, linksJSON, taxon.Genus, taxon.Name, string(taxon.Level),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update taxon: %w", err)
 	}
-	return nil
+	return tx.Commit()
 }
 
 // GetTaxon gets a taxon by name and level
 func (db *Database) GetTaxon(name string, level models.TaxonLevel) (*models.Taxon, error) {
+	return db.getTaxon(name, level, false)
+}
+
+// GetTaxonFresh gets a taxon as GetTaxon does, counting its species live
+// rather than reading the stored count
+func (db *Database) GetTaxonFresh(name string, level models.TaxonLevel) (*models.Taxon, error) {
+	return db.getTaxon(name, level, true)
+}
+
+func (db *Database) getTaxon(name string, level models.TaxonLevel, fresh bool) (*models.Taxon, error) {
 	row := db.conn.QueryRow(
-		`SELECT t.name, t.level, t.parent, t.author, t.notes, t.links,
-		        (SELECT COUNT(*) FROM oak_entries o WHERE
-		            (t.level = 'subgenus' AND o.subgenus = t.name) OR
-		            (t.level = 'section' AND o.section = t.name) OR
-		            (t.level = 'subsection' AND o.subsection = t.name) OR
-		            (t.level = 'complex' AND o.complex = t.name)
-		        ) as species_count
+		`SELECT t.name, t.level, t.parent, t.author, t.notes, t.links, t.genus, `+speciesCountColumn(fresh)+` as species_count
 		 FROM taxa t WHERE t.name = ? AND t.level = ?`,
 		name, string(level),
 	)
@@ -329,7 +986,7 @@ func (db *Database) GetTaxon(name string, level models.TaxonLevel) (*models.Taxo
 	var t models.Taxon
 	var levelStr string
 	var linksJSON sql.NullString
-	err := row.Scan(&t.Name, &levelStr, &t.Parent, &t.Author, &t.Notes, &linksJSON, &t.SpeciesCount)
+	err := row.Scan(&t.Name, &levelStr, &t.Parent, &t.Author, &t.Notes, &linksJSON, &t.Genus, &t.SpeciesCount)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -354,40 +1011,67 @@ func (db *Database) GetTaxon(name string, level models.TaxonLevel) (*models.Taxo
 type TaxaListParams struct {
 	Level  *models.TaxonLevel
 	Parent *string
-}
+	Genus  *string
 
-// ListTaxa lists all taxa, optionally filtered by level and parent
-func (db *Database) ListTaxa(params *TaxaListParams) ([]*models.Taxon, error) {
-	var rows *sql.Rows
-	var err error
-	var args []interface{}
+	// Fresh counts species live instead of reading the stored counts
+	Fresh bool
 
-	// Base query with species count subquery
-	baseQuery := `SELECT t.name, t.level, t.parent, t.author, t.notes, t.links,
-	                     (SELECT COUNT(*) FROM oak_entries o WHERE
-	                         (t.level = 'subgenus' AND o.subgenus = t.name) OR
-	                         (t.level = 'section' AND o.section = t.name) OR
-	                         (t.level = 'subsection' AND o.subsection = t.name) OR
-	                         (t.level = 'complex' AND o.complex = t.name)
-	                     ) as species_count
-	              FROM taxa t`
-
-	// Build WHERE clause
+	// Limit caps the number of taxa returned after skipping Offset; 0 returns
+	// all. CountTaxa ignores both.
+	Limit  int
+	Offset int
+}
+
+// where builds the WHERE clause for the params' filters
+func (params *TaxaListParams) where() (string, []interface{}) {
+	if params == nil {
+		return "", nil
+	}
 	var conditions []string
-	if params != nil && params.Level != nil {
+	var args []interface{}
+	if params.Level != nil {
 		conditions = append(conditions, "t.level = ?")
 		args = append(args, string(*params.Level))
 	}
-	if params != nil && params.Parent != nil {
+	if params.Parent != nil {
 		conditions = append(conditions, "t.parent = ?")
 		args = append(args, *params.Parent)
 	}
+	if params.Genus != nil {
+		conditions = append(conditions, "t.genus = ?")
+		args = append(args, *params.Genus)
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
 
-	query := baseQuery
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+// CountTaxa counts the taxa matching params' filters
+func (db *Database) CountTaxa(params *TaxaListParams) (int, error) {
+	where, args := params.where()
+	var count int
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM taxa t`+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count taxa: %w", err)
+	}
+	return count, nil
+}
+
+// ListTaxa lists all taxa, optionally filtered by level and parent
+func (db *Database) ListTaxa(params *TaxaListParams) ([]*models.Taxon, error) {
+	var rows *sql.Rows
+	var err error
+
+	// Base query with species count
+	baseQuery := `SELECT t.name, t.level, t.parent, t.author, t.notes, t.links, t.genus, ` +
+		speciesCountColumn(params != nil && params.Fresh) + ` as species_count FROM taxa t`
+
+	where, args := params.where()
+	query := baseQuery + where + " ORDER BY t.name"
+	if params != nil && params.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, params.Limit, params.Offset)
 	}
-	query += " ORDER BY t.name"
 
 	rows, err = db.conn.Query(query, args...)
 	if err != nil {
@@ -400,7 +1084,7 @@ func (db *Database) ListTaxa(params *TaxaListParams) ([]*models.Taxon, error) {
 		var t models.Taxon
 		var levelStr string
 		var linksJSON sql.NullString
-		if err := rows.Scan(&t.Name, &levelStr, &t.Parent, &t.Author, &t.Notes, &linksJSON, &t.SpeciesCount); err != nil {
+		if err := rows.Scan(&t.Name, &levelStr, &t.Parent, &t.Author, &t.Notes, &linksJSON, &t.Genus, &t.SpeciesCount); err != nil {
 			return nil, fmt.Errorf("failed to scan taxon: %w", err)
 		}
 		t.Level = models.TaxonLevel(levelStr)
@@ -462,10 +1146,10 @@ func (db *Database) DeleteTaxon(name string, level models.TaxonLevel) error {
 
 // SearchTaxa searches taxa by name pattern (case-insensitive)
 func (db *Database) SearchTaxa(query string) ([]*models.Taxon, error) {
-	pattern := "%" + escapeLike(query) + "%"
+	pattern := likeKey(query)
 	rows, err := db.conn.Query(
-		`SELECT name, level, parent, author, notes, links FROM taxa
-		 WHERE name LIKE ? ESCAPE '\' ORDER BY level, name`,
+		`SELECT name, level, parent, author, notes, links, genus FROM taxa
+		 WHERE name_key(name) LIKE ? ESCAPE '\' ORDER BY level, name`,
 		pattern,
 	)
 	if err != nil {
@@ -478,7 +1162,7 @@ func (db *Database) SearchTaxa(query string) ([]*models.Taxon, error) {
 		var t models.Taxon
 		var levelStr string
 		var linksJSON sql.NullString
-		if err := rows.Scan(&t.Name, &levelStr, &t.Parent, &t.Author, &t.Notes, &linksJSON); err != nil {
+		if err := rows.Scan(&t.Name, &levelStr, &t.Parent, &t.Author, &t.Notes, &linksJSON, &t.Genus); err != nil {
 			return nil, fmt.Errorf("failed to scan taxon: %w", err)
 		}
 		t.Level = models.TaxonLevel(levelStr)
@@ -500,7 +1184,15 @@ func (db *Database) SearchTaxa(query string) ([]*models.Taxon, error) {
 // SaveOakEntry saves or updates a complete oak entry.
 // It also maintains bidirectional parent-child relationships:
 // when a hybrid's parents are set/changed, the parents' hybrids lists are updated.
+// The version it replaces is kept as a revision (see ListOakEntryRevisions).
 func (db *Database) SaveOakEntry(entry *models.OakEntry) error {
+	return db.SaveOakEntryIfHash(entry, "")
+}
+
+// SaveOakEntryIfHash saves an oak entry as SaveOakEntry does, only if hash is
+// still its content hash, and fails with ErrEntityChanged otherwise. An empty
+// hash saves unconditionally.
+func (db *Database) SaveOakEntryIfHash(entry *models.OakEntry, hash string) error {
 	// Start transaction for atomic updates
 	tx, err := db.conn.Begin()
 	if err != nil {
@@ -508,11 +1200,18 @@ func (db *Database) SaveOakEntry(entry *models.OakEntry) error {
 	}
 	defer tx.Rollback()
 
+	if err := checkEntityHashTx(tx, HashKindSpecies, entry.ScientificName, hash); err != nil {
+		return err
+	}
+
 	// Get existing entry to compare parents (for bidirectional relationship updates)
 	existingEntry, err := db.getOakEntryTx(tx, entry.ScientificName)
 	if err != nil {
 		return fmt.Errorf("failed to get existing entry: %w", err)
 	}
+	if err := saveOakEntryRevisionTx(tx, existingEntry, entry); err != nil {
+		return err
+	}
 
 	// Compute parent changes
 	oldParents := make(map[string]bool)
@@ -565,7 +1264,7 @@ func (db *Database) getOakEntryTx(tx *sql.Tx, scientificName string) (*models.Oa
 	row := tx.QueryRow(
 		`SELECT scientific_name, author, is_hybrid, conservation_status,
 		        subgenus, section, subsection, complex,
-		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links
+		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links, visibility, genus, pronunciation
 		 FROM oak_entries WHERE scientific_name = ?`,
 		scientificName,
 	)
@@ -577,7 +1276,7 @@ func (db *Database) getOakEntryTx(tx *sql.Tx, scientificName string) (*models.Oa
 	if err := row.Scan(
 		&entry.ScientificName, &entry.Author, &isHybrid, &entry.ConservationStatus,
 		&entry.Subgenus, &entry.Section, &entry.Subsection, &entry.Complex,
-		&entry.Parent1, &entry.Parent2, &hybridsJSON, &relatedJSON, &subspeciesJSON, &synonymsJSON, &externalLinksJSON,
+		&entry.Parent1, &entry.Parent2, &hybridsJSON, &relatedJSON, &subspeciesJSON, &synonymsJSON, &externalLinksJSON, &entry.Visibility, &entry.Genus, &entry.Pronunciation,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -636,84 +1335,43 @@ func (db *Database) getOakEntryTx(tx *sql.Tx, scientificName string) (*models.Oa
 	return &entry, nil
 }
 
-// removeHybridFromParentTx removes a hybrid from a parent's hybrids list within a transaction
-func (db *Database) removeHybridFromParentTx(tx *sql.Tx, parentName, hybridName string) error {
-	// Get parent's current hybrids list
-	var hybridsJSON sql.NullString
-	err := tx.QueryRow(
-		`SELECT hybrids FROM oak_entries WHERE scientific_name = ?`,
-		parentName,
-	).Scan(&hybridsJSON)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			// Parent doesn't exist, nothing to do
-			return nil
-		}
-		return fmt.Errorf("failed to get parent hybrids: %w", err)
-	}
+// hybridsList is the hybrids column as a JSON array, treating NULL and a
+// marshaled nil slice as empty.
+const hybridsList = `COALESCE(NULLIF(hybrids, 'null'), '[]')`
 
-	var hybrids []string
-	if hybridsJSON.Valid {
-		if err := json.Unmarshal([]byte(hybridsJSON.String), &hybrids); err != nil {
-			return fmt.Errorf("failed to unmarshal hybrids: %w", err)
-		}
-	}
-
-	// Remove the hybrid from the list
-	hybrids = sliceRemove(hybrids, hybridName)
-
-	// Save updated list
-	updatedJSON, err := json.Marshal(hybrids)
+// removeHybridFromParentTx removes a hybrid from a parent's hybrids list within a transaction.
+// The list is rewritten in a single statement rather than read into Go and
+// written back, so a concurrent writer's change to the same list isn't lost.
+// A parent that doesn't exist matches no row, and there is nothing to do.
+func (db *Database) removeHybridFromParentTx(tx *sql.Tx, parentName, hybridName string) error {
+	_, err := tx.Exec(
+		`UPDATE oak_entries SET hybrids = (
+			SELECT json_group_array(value) FROM (
+				SELECT value FROM json_each(`+hybridsList+`) WHERE value != ? ORDER BY key
+			)
+		 )
+		 WHERE scientific_name = ?`,
+		hybridName, parentName,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to marshal hybrids: %w", err)
+		return fmt.Errorf("failed to update parent hybrids: %w", err)
 	}
-
-	_, err = tx.Exec(
-		`UPDATE oak_entries SET hybrids = ? WHERE scientific_name = ?`,
-		string(updatedJSON), parentName,
-	)
-	return err
+	return nil
 }
 
-// addHybridToParentTx adds a hybrid to a parent's hybrids list within a transaction
+// addHybridToParentTx adds a hybrid to a parent's hybrids list within a
+// transaction, in a single statement as removeHybridFromParentTx does.
 func (db *Database) addHybridToParentTx(tx *sql.Tx, parentName, hybridName string) error {
-	// Get parent's current hybrids list
-	var hybridsJSON sql.NullString
-	err := tx.QueryRow(
-		`SELECT hybrids FROM oak_entries WHERE scientific_name = ?`,
-		parentName,
-	).Scan(&hybridsJSON)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			// Parent doesn't exist, nothing to do
-			return nil
-		}
-		return fmt.Errorf("failed to get parent hybrids: %w", err)
-	}
-
-	var hybrids []string
-	if hybridsJSON.Valid {
-		if err := json.Unmarshal([]byte(hybridsJSON.String), &hybrids); err != nil {
-			return fmt.Errorf("failed to unmarshal hybrids: %w", err)
-		}
-	}
-
-	// Add the hybrid if not already present
-	if !sliceContains(hybrids, hybridName) {
-		hybrids = append(hybrids, hybridName)
-	}
-
-	// Save updated list
-	updatedJSON, err := json.Marshal(hybrids)
+	_, err := tx.Exec(
+		`UPDATE oak_entries SET hybrids = json_insert(`+hybridsList+`, '$[#]', ?)
+		 WHERE scientific_name = ?
+		   AND NOT EXISTS (SELECT 1 FROM json_each(`+hybridsList+`) WHERE value = ?)`,
+		hybridName, parentName, hybridName,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to marshal hybrids: %w", err)
+		return fmt.Errorf("failed to update parent hybrids: %w", err)
 	}
-
-	_, err = tx.Exec(
-		`UPDATE oak_entries SET hybrids = ? WHERE scientific_name = ?`,
-		string(updatedJSON), parentName,
-	)
-	return err
+	return nil
 }
 
 // saveOakEntryTx saves an oak entry within a transaction
@@ -750,12 +1408,18 @@ func (db *Database) saveOakEntryTx(tx *sql.Tx, entry *models.OakEntry) error {
 		`INSERT OR REPLACE INTO oak_entries (
 			scientific_name, author, is_hybrid, conservation_status,
 			subgenus, section, subsection, complex,
-			parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links, visibility, genus, pronunciation
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?,
+			COALESCE(NULLIF(?, ''), (SELECT visibility FROM oak_entries WHERE scientific_name = ?), 'published'),
+			COALESCE(NULLIF(?, ''), (SELECT genus FROM oak_entries WHERE scientific_name = ?), 'Quercus'),
+			?)`,
 		entry.ScientificName, entry.Author, isHybrid, entry.ConservationStatus,
 		entry.Subgenus, entry.Section, entry.Subsection, entry.Complex,
 		entry.Parent1, entry.Parent2, string(hybridsJSON), string(relatedJSON),
 		string(subspeciesJSON), string(synonymsJSON), string(externalLinksJSON),
+		entry.Visibility, entry.ScientificName, // An empty visibility keeps the stored one
+		entry.Genus, entry.ScientificName, // Likewise an empty genus
+		entry.Pronunciation,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert oak entry: %w", err)
@@ -769,19 +1433,19 @@ func (db *Database) GetOakEntry(scientificName string) (*models.OakEntry, error)
 	row := db.conn.QueryRow(
 		`SELECT scientific_name, author, is_hybrid, conservation_status,
 		        subgenus, section, subsection, complex,
-		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links
+		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links, visibility, genus, pronunciation
 		 FROM oak_entries WHERE scientific_name = ?`,
 		scientificName,
 	)
 
 	var entry models.OakEntry
 	var isHybrid int
-	var hybridsJSON, relatedJSON, subspeciesJSON, synonymsJSON, externalLinksJSON sql.NullString
+	var cols oakEntryJSON
 
 	if err := row.Scan(
 		&entry.ScientificName, &entry.Author, &isHybrid, &entry.ConservationStatus,
 		&entry.Subgenus, &entry.Section, &entry.Subsection, &entry.Complex,
-		&entry.Parent1, &entry.Parent2, &hybridsJSON, &relatedJSON, &subspeciesJSON, &synonymsJSON, &externalLinksJSON,
+		&entry.Parent1, &entry.Parent2, &cols.hybrids, &cols.related, &cols.subspecies, &cols.synonyms, &cols.externalLinks, &entry.Visibility, &entry.Genus, &entry.Pronunciation,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -791,51 +1455,8 @@ func (db *Database) GetOakEntry(scientificName string) (*models.OakEntry, error)
 
 	entry.IsHybrid = isHybrid != 0
 
-	// Unmarshal JSON arrays
-	if hybridsJSON.Valid {
-		if err := json.Unmarshal([]byte(hybridsJSON.String), &entry.Hybrids); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal hybrids for %s: %w", entry.ScientificName, err)
-		}
-	}
-	if entry.Hybrids == nil {
-		entry.Hybrids = []string{}
-	}
-
-	if relatedJSON.Valid {
-		if err := json.Unmarshal([]byte(relatedJSON.String), &entry.CloselyRelatedTo); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal closely_related_to for %s: %w", entry.ScientificName, err)
-		}
-	}
-	if entry.CloselyRelatedTo == nil {
-		entry.CloselyRelatedTo = []string{}
-	}
-
-	if subspeciesJSON.Valid {
-		if err := json.Unmarshal([]byte(subspeciesJSON.String), &entry.SubspeciesVarieties); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal subspecies_varieties for %s: %w", entry.ScientificName, err)
-		}
-	}
-	if entry.SubspeciesVarieties == nil {
-		entry.SubspeciesVarieties = []string{}
-	}
-
-	if synonymsJSON.Valid {
-		if err := json.Unmarshal([]byte(synonymsJSON.String), &entry.Synonyms); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal synonyms for %s: %w", entry.ScientificName, err)
-		}
-	}
-	if entry.Synonyms == nil {
-		entry.Synonyms = []string{}
-	}
-
-	if externalLinksJSON.Valid {
-		if err := json.Unmarshal([]byte(externalLinksJSON.String), &entry.ExternalLinks); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal external_links for %s: %w", entry.ScientificName, err)
-		}
-	}
-	if entry.ExternalLinks == nil {
-		entry.ExternalLinks = []models.ExternalLink{}
-	}
+	db.decodeOakEntryJSON(&entry, &cols)
+	entry.Slug = names.Slug(entry.ScientificName)
 
 	return &entry, nil
 }
@@ -854,10 +1475,10 @@ func (db *Database) DeleteOakEntry(scientificName string) error {
 
 // SearchOakEntries searches for oak entries by name pattern
 func (db *Database) SearchOakEntries(query string) ([]string, error) {
-	pattern := "%" + escapeLike(query) + "%"
+	pattern := likeKey(query)
 	rows, err := db.conn.Query(
 		`SELECT scientific_name FROM oak_entries
-		 WHERE scientific_name LIKE ? ESCAPE '\' ORDER BY scientific_name`,
+		 WHERE name_key(scientific_name) LIKE ? ESCAPE '\' ORDER BY scientific_name`,
 		pattern,
 	)
 	if err != nil {
@@ -878,12 +1499,48 @@ func (db *Database) SearchOakEntries(query string) ([]string, error) {
 
 // OakEntryFilter contains filter criteria for listing oak entries
 type OakEntryFilter struct {
+	Genus      *string
 	Subgenus   *string
 	Section    *string
 	Subsection *string
 	Complex    *string
 	Hybrid     *bool
 	SourceID   *int64
+
+	// ConservationStatus limits results to species with any of these IUCN codes
+	ConservationStatus []string
+
+	// HasSource restricts results to species with (true) or without (false)
+	// species_sources data; combined with SourceID it applies to that source only.
+	HasSource *bool
+
+	// After restricts results to names sorting after this one (keyset pagination).
+	// Ignored by CountOakEntries so totals cover the whole filtered set.
+	After *string
+
+	// IncludeDrafts returns draft entries too; by default only published entries are listed
+	IncludeDrafts bool
+}
+
+// sourceJoin returns the species_sources join needed for the SourceID and
+// HasSource criteria, its arguments, and any WHERE condition it requires.
+// The join is empty when neither criterion is set.
+func (f *OakEntryFilter) sourceJoin() (join string, args []interface{}, condition string) {
+	if f.SourceID == nil && f.HasSource == nil {
+		return "", nil, ""
+	}
+
+	on := "oak_entries.scientific_name = species_sources.scientific_name"
+	if f.SourceID != nil {
+		on += " AND species_sources.source_id = ?"
+		args = append(args, *f.SourceID)
+	}
+
+	// Species without matching rows are found with an anti-join
+	if f.HasSource != nil && !*f.HasSource {
+		return " LEFT JOIN species_sources ON " + on, args, "species_sources.scientific_name IS NULL"
+	}
+	return " INNER JOIN species_sources ON " + on, args, ""
 }
 
 // ListOakEntriesPaginated returns a paginated list of oak entries with optional filters
@@ -891,7 +1548,7 @@ func (db *Database) ListOakEntriesPaginated(limit, offset int, filter *OakEntryF
 	// Base SELECT - use DISTINCT when joining with species_sources
 	selectClause := `SELECT scientific_name, author, is_hybrid, conservation_status,
 		        subgenus, section, subsection, complex,
-		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links
+		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links, visibility, genus, pronunciation
 		 FROM oak_entries`
 
 	var args []interface{}
@@ -900,17 +1557,26 @@ func (db *Database) ListOakEntriesPaginated(limit, offset int, filter *OakEntryF
 
 	if filter != nil {
 		// Check if we need to join with species_sources
-		if filter.SourceID != nil {
+		if join, joinArgs, condition := filter.sourceJoin(); join != "" {
 			needsJoin = true
 			selectClause = `SELECT DISTINCT oak_entries.scientific_name, oak_entries.author, oak_entries.is_hybrid, oak_entries.conservation_status,
 				oak_entries.subgenus, oak_entries.section, oak_entries.subsection, oak_entries.complex,
-				oak_entries.parent1, oak_entries.parent2, oak_entries.hybrids, oak_entries.closely_related_to, oak_entries.subspecies_varieties, oak_entries.synonyms, oak_entries.external_links
-			 FROM oak_entries
-			 INNER JOIN species_sources ON oak_entries.scientific_name = species_sources.scientific_name`
-			conditions = append(conditions, "species_sources.source_id = ?")
-			args = append(args, *filter.SourceID)
+				oak_entries.parent1, oak_entries.parent2, oak_entries.hybrids, oak_entries.closely_related_to, oak_entries.subspecies_varieties, oak_entries.synonyms, oak_entries.external_links, oak_entries.visibility, oak_entries.genus, oak_entries.pronunciation
+			 FROM oak_entries` + join
+			args = append(args, joinArgs...)
+			if condition != "" {
+				conditions = append(conditions, condition)
+			}
 		}
 
+		if filter.Genus != nil {
+			if needsJoin {
+				conditions = append(conditions, "oak_entries.genus = ?")
+			} else {
+				conditions = append(conditions, "genus = ?")
+			}
+			args = append(args, *filter.Genus)
+		}
 		if filter.Subgenus != nil {
 			if needsJoin {
 				conditions = append(conditions, "oak_entries.subgenus = ?")
@@ -955,6 +1621,33 @@ func (db *Database) ListOakEntriesPaginated(limit, offset int, filter *OakEntryF
 				args = append(args, 0)
 			}
 		}
+		if len(filter.ConservationStatus) > 0 {
+			column := "conservation_status"
+			if needsJoin {
+				column = "oak_entries.conservation_status"
+			}
+			conditions = append(conditions, column+" IN (?"+strings.Repeat(", ?", len(filter.ConservationStatus)-1)+")")
+			for _, status := range filter.ConservationStatus {
+				args = append(args, status)
+			}
+		}
+		if filter.After != nil {
+			if needsJoin {
+				conditions = append(conditions, "oak_entries.scientific_name > ?")
+			} else {
+				conditions = append(conditions, "scientific_name > ?")
+			}
+			args = append(args, *filter.After)
+		}
+	}
+
+	if filter == nil || !filter.IncludeDrafts {
+		if needsJoin {
+			conditions = append(conditions, "oak_entries.visibility = ?")
+		} else {
+			conditions = append(conditions, "visibility = ?")
+		}
+		args = append(args, models.VisibilityPublished)
 	}
 
 	query := selectClause
@@ -975,7 +1668,7 @@ func (db *Database) ListOakEntriesPaginated(limit, offset int, filter *OakEntryF
 	}
 	defer rows.Close()
 
-	return scanOakEntries(rows)
+	return db.scanOakEntries(rows)
 }
 
 // CountOakEntries returns the total count of oak entries matching the filter
@@ -988,14 +1681,23 @@ func (db *Database) CountOakEntries(filter *OakEntryFilter) (int, error) {
 
 	if filter != nil {
 		// Check if we need to join with species_sources
-		if filter.SourceID != nil {
+		if join, joinArgs, condition := filter.sourceJoin(); join != "" {
 			needsJoin = true
-			baseQuery = `SELECT COUNT(DISTINCT oak_entries.scientific_name) FROM oak_entries
-			 INNER JOIN species_sources ON oak_entries.scientific_name = species_sources.scientific_name`
-			conditions = append(conditions, "species_sources.source_id = ?")
-			args = append(args, *filter.SourceID)
+			baseQuery = `SELECT COUNT(DISTINCT oak_entries.scientific_name) FROM oak_entries` + join
+			args = append(args, joinArgs...)
+			if condition != "" {
+				conditions = append(conditions, condition)
+			}
 		}
 
+		if filter.Genus != nil {
+			if needsJoin {
+				conditions = append(conditions, "oak_entries.genus = ?")
+			} else {
+				conditions = append(conditions, "genus = ?")
+			}
+			args = append(args, *filter.Genus)
+		}
 		if filter.Subgenus != nil {
 			if needsJoin {
 				conditions = append(conditions, "oak_entries.subgenus = ?")
@@ -1040,6 +1742,25 @@ func (db *Database) CountOakEntries(filter *OakEntryFilter) (int, error) {
 				args = append(args, 0)
 			}
 		}
+		if len(filter.ConservationStatus) > 0 {
+			column := "conservation_status"
+			if needsJoin {
+				column = "oak_entries.conservation_status"
+			}
+			conditions = append(conditions, column+" IN (?"+strings.Repeat(", ?", len(filter.ConservationStatus)-1)+")")
+			for _, status := range filter.ConservationStatus {
+				args = append(args, status)
+			}
+		}
+	}
+
+	if filter == nil || !filter.IncludeDrafts {
+		if needsJoin {
+			conditions = append(conditions, "oak_entries.visibility = ?")
+		} else {
+			conditions = append(conditions, "visibility = ?")
+		}
+		args = append(args, models.VisibilityPublished)
 	}
 
 	query := baseQuery
@@ -1055,23 +1776,24 @@ func (db *Database) CountOakEntries(filter *OakEntryFilter) (int, error) {
 }
 
 // SearchOakEntriesFull searches for oak entries by name pattern and returns full entries
-func (db *Database) SearchOakEntriesFull(query string, limit int) ([]*models.OakEntry, error) {
-	pattern := "%" + escapeLike(query) + "%"
+// Drafts are only included when includeDrafts is set.
+func (db *Database) SearchOakEntriesFull(query string, limit int, includeDrafts bool) ([]*models.OakEntry, error) {
+	pattern := likeKey(query)
 	rows, err := db.conn.Query(
 		`SELECT scientific_name, author, is_hybrid, conservation_status,
 		        subgenus, section, subsection, complex,
-		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links
+		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links, visibility, genus, pronunciation
 		 FROM oak_entries
-		 WHERE scientific_name LIKE ? ESCAPE '\'
+		 WHERE name_key(scientific_name) LIKE ? ESCAPE '\' AND (? OR visibility = ?)
 		 ORDER BY scientific_name LIMIT ?`,
-		pattern, limit,
+		pattern, includeDrafts, models.VisibilityPublished, limit,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search oak entries: %w", err)
 	}
 	defer rows.Close()
 
-	return scanOakEntries(rows)
+	return db.scanOakEntries(rows)
 }
 
 // OakEntryExists checks if an oak entry exists by scientific name
@@ -1087,69 +1809,27 @@ func (db *Database) OakEntryExists(scientificName string) (bool, error) {
 	return count > 0, nil
 }
 
-// scanOakEntries is a helper that scans rows into OakEntry objects
-func scanOakEntries(rows *sql.Rows) ([]*models.OakEntry, error) {
+// scanOakEntries is a helper that scans rows into OakEntry objects.
+// Rows with malformed JSON columns are quarantined, not fatal (see decodeOakEntryJSON).
+func (db *Database) scanOakEntries(rows *sql.Rows) ([]*models.OakEntry, error) {
 	var entries []*models.OakEntry
 	for rows.Next() {
 		var entry models.OakEntry
 		var isHybrid int
-		var hybridsJSON, relatedJSON, subspeciesJSON, synonymsJSON, externalLinksJSON sql.NullString
+		var cols oakEntryJSON
 
 		if err := rows.Scan(
 			&entry.ScientificName, &entry.Author, &isHybrid, &entry.ConservationStatus,
 			&entry.Subgenus, &entry.Section, &entry.Subsection, &entry.Complex,
-			&entry.Parent1, &entry.Parent2, &hybridsJSON, &relatedJSON, &subspeciesJSON, &synonymsJSON, &externalLinksJSON,
+			&entry.Parent1, &entry.Parent2, &cols.hybrids, &cols.related, &cols.subspecies, &cols.synonyms, &cols.externalLinks, &entry.Visibility, &entry.Genus, &entry.Pronunciation,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan oak entry: %w", err)
 		}
 
 		entry.IsHybrid = isHybrid != 0
 
-		// Unmarshal JSON arrays
-		if hybridsJSON.Valid {
-			if err := json.Unmarshal([]byte(hybridsJSON.String), &entry.Hybrids); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal hybrids for %s: %w", entry.ScientificName, err)
-			}
-		}
-		if entry.Hybrids == nil {
-			entry.Hybrids = []string{}
-		}
-
-		if relatedJSON.Valid {
-			if err := json.Unmarshal([]byte(relatedJSON.String), &entry.CloselyRelatedTo); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal closely_related_to for %s: %w", entry.ScientificName, err)
-			}
-		}
-		if entry.CloselyRelatedTo == nil {
-			entry.CloselyRelatedTo = []string{}
-		}
-
-		if subspeciesJSON.Valid {
-			if err := json.Unmarshal([]byte(subspeciesJSON.String), &entry.SubspeciesVarieties); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal subspecies_varieties for %s: %w", entry.ScientificName, err)
-			}
-		}
-		if entry.SubspeciesVarieties == nil {
-			entry.SubspeciesVarieties = []string{}
-		}
-
-		if synonymsJSON.Valid {
-			if err := json.Unmarshal([]byte(synonymsJSON.String), &entry.Synonyms); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal synonyms for %s: %w", entry.ScientificName, err)
-			}
-		}
-		if entry.Synonyms == nil {
-			entry.Synonyms = []string{}
-		}
-
-		if externalLinksJSON.Valid {
-			if err := json.Unmarshal([]byte(externalLinksJSON.String), &entry.ExternalLinks); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal external_links for %s: %w", entry.ScientificName, err)
-			}
-		}
-		if entry.ExternalLinks == nil {
-			entry.ExternalLinks = []models.ExternalLink{}
-		}
+		db.decodeOakEntryJSON(&entry, &cols)
+		entry.Slug = names.Slug(entry.ScientificName)
 
 		entries = append(entries, &entry)
 	}
@@ -1159,10 +1839,10 @@ func scanOakEntries(rows *sql.Rows) ([]*models.OakEntry, error) {
 
 // SearchSources searches for sources by name pattern
 func (db *Database) SearchSources(query string) ([]int64, error) {
-	pattern := "%" + escapeLike(query) + "%"
+	pattern := likeKey(query)
 	rows, err := db.conn.Query(
 		`SELECT id FROM sources
-		 WHERE name LIKE ? ESCAPE '\' ORDER BY name`,
+		 WHERE name_key(name) LIKE ? ESCAPE '\' ORDER BY name`,
 		pattern,
 	)
 	if err != nil {
@@ -1191,7 +1871,7 @@ func (db *Database) ListOakEntries() ([]*models.OakEntry, error) {
 	rows, err := db.conn.Query(
 		`SELECT scientific_name, author, is_hybrid, conservation_status,
 		        subgenus, section, subsection, complex,
-		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links
+		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links, visibility, genus, pronunciation
 		 FROM oak_entries ORDER BY scientific_name`,
 	)
 	if err != nil {
@@ -1203,91 +1883,106 @@ func (db *Database) ListOakEntries() ([]*models.OakEntry, error) {
 	for rows.Next() {
 		var entry models.OakEntry
 		var isHybrid int
-		var hybridsJSON, relatedJSON, subspeciesJSON, synonymsJSON, externalLinksJSON sql.NullString
+		var cols oakEntryJSON
 
 		if err := rows.Scan(
 			&entry.ScientificName, &entry.Author, &isHybrid, &entry.ConservationStatus,
 			&entry.Subgenus, &entry.Section, &entry.Subsection, &entry.Complex,
-			&entry.Parent1, &entry.Parent2, &hybridsJSON, &relatedJSON, &subspeciesJSON, &synonymsJSON, &externalLinksJSON,
+			&entry.Parent1, &entry.Parent2, &cols.hybrids, &cols.related, &cols.subspecies, &cols.synonyms, &cols.externalLinks, &entry.Visibility, &entry.Genus, &entry.Pronunciation,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan oak entry: %w", err)
 		}
 
 		entry.IsHybrid = isHybrid != 0
 
-		// Unmarshal JSON arrays
-		if hybridsJSON.Valid {
-			if err := json.Unmarshal([]byte(hybridsJSON.String), &entry.Hybrids); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal hybrids for %s: %w", entry.ScientificName, err)
-			}
-		}
-		if entry.Hybrids == nil {
-			entry.Hybrids = []string{}
-		}
+		db.decodeOakEntryJSON(&entry, &cols)
+		entry.Slug = names.Slug(entry.ScientificName)
 
-		if relatedJSON.Valid {
-			if err := json.Unmarshal([]byte(relatedJSON.String), &entry.CloselyRelatedTo); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal closely_related_to for %s: %w", entry.ScientificName, err)
-			}
-		}
-		if entry.CloselyRelatedTo == nil {
-			entry.CloselyRelatedTo = []string{}
-		}
+		entries = append(entries, &entry)
+	}
 
-		if subspeciesJSON.Valid {
-			if err := json.Unmarshal([]byte(subspeciesJSON.String), &entry.SubspeciesVarieties); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal subspecies_varieties for %s: %w", entry.ScientificName, err)
-			}
-		}
-		if entry.SubspeciesVarieties == nil {
-			entry.SubspeciesVarieties = []string{}
-		}
+	return entries, rows.Err()
+}
 
-		if synonymsJSON.Valid {
-			if err := json.Unmarshal([]byte(synonymsJSON.String), &entry.Synonyms); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal synonyms for %s: %w", entry.ScientificName, err)
-			}
-		}
-		if entry.Synonyms == nil {
-			entry.Synonyms = []string{}
-		}
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
 
-		if externalLinksJSON.Valid {
-			if err := json.Unmarshal([]byte(externalLinksJSON.String), &entry.ExternalLinks); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal external_links for %s: %w", entry.ScientificName, err)
-			}
-		}
-		if entry.ExternalLinks == nil {
-			entry.ExternalLinks = []models.ExternalLink{}
-		}
+// SaveSpeciesSource saves or updates a species-source record.
+// Saving a preferred record clears the preference from the species' other sources.
+func (db *Database) SaveSpeciesSource(ss *models.SpeciesSource) error {
+	return db.SaveSpeciesSourceIfHash(ss, "")
+}
 
-		entries = append(entries, &entry)
+// SaveSpeciesSourceIfHash saves a species-source record only if hash is still
+// its content hash, and fails with ErrEntityChanged otherwise. An empty hash
+// saves unconditionally.
+func (db *Database) SaveSpeciesSourceIfHash(ss *models.SpeciesSource, hash string) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	return entries, rows.Err()
+	key := ss.ScientificName + "/" + strconv.FormatInt(ss.SourceID, 10)
+	if err := checkEntityHashTx(tx, HashKindSpeciesSources, key, hash); err != nil {
+		return err
+	}
+	if err := saveSpeciesSource(tx, ss); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-// SaveSpeciesSource saves or updates a species-source record
-func (db *Database) SaveSpeciesSource(ss *models.SpeciesSource) error {
+// saveSpeciesSource saves a species-source record within a transaction
+func saveSpeciesSource(conn execer, ss *models.SpeciesSource) error {
 	localNamesJSON, err := json.Marshal(ss.LocalNames)
 	if err != nil {
 		return fmt.Errorf("failed to marshal local_names: %w", err)
 	}
+	var fieldPagesJSON *string
+	if len(ss.FieldPages) > 0 {
+		data, err := json.Marshal(ss.FieldPages)
+		if err != nil {
+			return fmt.Errorf("failed to marshal field_pages: %w", err)
+		}
+		fieldPagesJSON = new(string)
+		*fieldPagesJSON = string(data)
+	}
+	var fieldVisibilityJSON *string
+	if len(ss.FieldVisibility) > 0 {
+		data, err := json.Marshal(ss.FieldVisibility)
+		if err != nil {
+			return fmt.Errorf("failed to marshal field_visibility: %w", err)
+		}
+		fieldVisibilityJSON = new(string)
+		*fieldVisibilityJSON = string(data)
+	}
 
 	isPreferred := 0
 	if ss.IsPreferred {
 		isPreferred = 1
+		if _, err := conn.Exec(
+			`UPDATE species_sources SET is_preferred = 0
+			 WHERE scientific_name = ? AND source_id != ? AND is_preferred = 1`,
+			ss.ScientificName, ss.SourceID,
+		); err != nil {
+			return fmt.Errorf("failed to clear preferred source: %w", err)
+		}
 	}
 
-	result, err := db.conn.Exec(
+	result, err := conn.Exec(
 		`INSERT OR REPLACE INTO species_sources (
 			scientific_name, source_id, local_names, range, growth_habit,
 			leaves, flowers, fruits, bark, twigs, buds, hardiness_habitat,
-			miscellaneous, url, is_preferred
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			miscellaneous, distinguishing_features, url, is_preferred, pages, field_pages, field_visibility,
+			transcriber, transcription_method, ocr_confidence, verified_at, verified_by
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		ss.ScientificName, ss.SourceID, string(localNamesJSON), ss.Range, ss.GrowthHabit,
 		ss.Leaves, ss.Flowers, ss.Fruits, ss.Bark, ss.Twigs, ss.Buds, ss.HardinessHabitat,
-		ss.Miscellaneous, ss.URL, isPreferred,
+		ss.Miscellaneous, ss.DistinguishingFeatures, ss.URL, isPreferred, ss.Pages, fieldPagesJSON, fieldVisibilityJSON,
+		ss.Transcriber, ss.TranscriptionMethod, ss.OCRConfidence, formatOptionalTimestamp(ss.VerifiedAt), ss.VerifiedBy,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to save species source: %w", err)
@@ -1303,12 +1998,16 @@ func (db *Database) SaveSpeciesSource(ss *models.SpeciesSource) error {
 	return nil
 }
 
+// speciesSourceColumns are the species_sources columns scanSpeciesSource reads
+const speciesSourceColumns = `id, scientific_name, source_id, local_names, range, growth_habit,
+	leaves, flowers, fruits, bark, twigs, buds, hardiness_habitat,
+	miscellaneous, distinguishing_features, url, is_preferred, pages, field_pages, field_visibility,
+	transcriber, transcription_method, ocr_confidence, verified_at, verified_by`
+
 // GetSpeciesSources returns all source data for a species
 func (db *Database) GetSpeciesSources(scientificName string) ([]*models.SpeciesSource, error) {
 	rows, err := db.conn.Query(
-		`SELECT id, scientific_name, source_id, local_names, range, growth_habit,
-		        leaves, flowers, fruits, bark, twigs, buds, hardiness_habitat,
-		        miscellaneous, url, is_preferred
+		`SELECT `+speciesSourceColumns+`
 		 FROM species_sources WHERE scientific_name = ? ORDER BY is_preferred DESC, source_id`,
 		scientificName,
 	)
@@ -1331,93 +2030,59 @@ func (db *Database) GetSpeciesSources(scientificName string) ([]*models.SpeciesS
 // GetSpeciesSourceBySourceID returns source data for a specific species+source combination
 func (db *Database) GetSpeciesSourceBySourceID(scientificName string, sourceID int64) (*models.SpeciesSource, error) {
 	row := db.conn.QueryRow(
-		`SELECT id, scientific_name, source_id, local_names, range, growth_habit,
-		        leaves, flowers, fruits, bark, twigs, buds, hardiness_habitat,
-		        miscellaneous, url, is_preferred
+		`SELECT `+speciesSourceColumns+`
 		 FROM species_sources WHERE scientific_name = ? AND source_id = ?`,
 		scientificName, sourceID,
 	)
 
-	ss := &models.SpeciesSource{}
-	var localNamesJSON sql.NullString
-	var isPreferred int
-
-	err := row.Scan(
-		&ss.ID, &ss.ScientificName, &ss.SourceID, &localNamesJSON, &ss.Range, &ss.GrowthHabit,
-		&ss.Leaves, &ss.Flowers, &ss.Fruits, &ss.Bark, &ss.Twigs, &ss.Buds, &ss.HardinessHabitat,
-		&ss.Miscellaneous, &ss.URL, &isPreferred,
-	)
-	if err == sql.ErrNoRows {
+	ss, err := scanSpeciesSource(row)
+	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get species source: %w", err)
 	}
-
-	ss.IsPreferred = isPreferred != 0
-	if localNamesJSON.Valid {
-		if err := json.Unmarshal([]byte(localNamesJSON.String), &ss.LocalNames); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal local_names for %s: %w", ss.ScientificName, err)
-		}
-	}
-	if ss.LocalNames == nil {
-		ss.LocalNames = []string{}
-	}
-
 	return ss, nil
 }
 
 // GetPreferredSpeciesSource returns the preferred source data for a species
 func (db *Database) GetPreferredSpeciesSource(scientificName string) (*models.SpeciesSource, error) {
 	row := db.conn.QueryRow(
-		`SELECT id, scientific_name, source_id, local_names, range, growth_habit,
-		        leaves, flowers, fruits, bark, twigs, buds, hardiness_habitat,
-		        miscellaneous, url, is_preferred
+		`SELECT `+speciesSourceColumns+`
 		 FROM species_sources WHERE scientific_name = ? ORDER BY is_preferred DESC LIMIT 1`,
 		scientificName,
 	)
 
-	ss := &models.SpeciesSource{}
-	var localNamesJSON sql.NullString
-	var isPreferred int
-
-	err := row.Scan(
-		&ss.ID, &ss.ScientificName, &ss.SourceID, &localNamesJSON, &ss.Range, &ss.GrowthHabit,
-		&ss.Leaves, &ss.Flowers, &ss.Fruits, &ss.Bark, &ss.Twigs, &ss.Buds, &ss.HardinessHabitat,
-		&ss.Miscellaneous, &ss.URL, &isPreferred,
-	)
-	if err == sql.ErrNoRows {
+	ss, err := scanSpeciesSource(row)
+	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get preferred species source: %w", err)
 	}
-
-	ss.IsPreferred = isPreferred != 0
-	if localNamesJSON.Valid {
-		if err := json.Unmarshal([]byte(localNamesJSON.String), &ss.LocalNames); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal local_names for %s: %w", ss.ScientificName, err)
-		}
-	}
-	if ss.LocalNames == nil {
-		ss.LocalNames = []string{}
-	}
-
 	return ss, nil
 }
 
-// scanSpeciesSource scans a row into a SpeciesSource
-func scanSpeciesSource(rows *sql.Rows) (*models.SpeciesSource, error) {
+// rowScanner is a *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSpeciesSource scans speciesSourceColumns, then extra, into a
+// SpeciesSource. A *sql.Row with no result gives an error wrapping
+// sql.ErrNoRows.
+func scanSpeciesSource(row rowScanner, extra ...interface{}) (*models.SpeciesSource, error) {
 	ss := &models.SpeciesSource{}
-	var localNamesJSON sql.NullString
+	var localNamesJSON, fieldPagesJSON, fieldVisibilityJSON, verifiedAt sql.NullString
 	var isPreferred int
 
-	err := rows.Scan(
+	dest := []interface{}{
 		&ss.ID, &ss.ScientificName, &ss.SourceID, &localNamesJSON, &ss.Range, &ss.GrowthHabit,
 		&ss.Leaves, &ss.Flowers, &ss.Fruits, &ss.Bark, &ss.Twigs, &ss.Buds, &ss.HardinessHabitat,
-		&ss.Miscellaneous, &ss.URL, &isPreferred,
-	)
-	if err != nil {
+		&ss.Miscellaneous, &ss.DistinguishingFeatures, &ss.URL, &isPreferred, &ss.Pages, &fieldPagesJSON, &fieldVisibilityJSON,
+		&ss.Transcriber, &ss.TranscriptionMethod, &ss.OCRConfidence, &verifiedAt, &ss.VerifiedBy,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
 		return nil, fmt.Errorf("failed to scan species source: %w", err)
 	}
 
@@ -1430,16 +2095,49 @@ func scanSpeciesSource(rows *sql.Rows) (*models.SpeciesSource, error) {
 	if ss.LocalNames == nil {
 		ss.LocalNames = []string{}
 	}
+	var err error
+	if ss.FieldPages, err = unmarshalFieldPages(fieldPagesJSON, ss.ScientificName); err != nil {
+		return nil, err
+	}
+	if fieldVisibilityJSON.Valid && fieldVisibilityJSON.String != "" {
+		if err := json.Unmarshal([]byte(fieldVisibilityJSON.String), &ss.FieldVisibility); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal field_visibility for %s: %w", ss.ScientificName, err)
+		}
+	}
+	if ss.VerifiedAt, err = parseOptionalTimestamp(verifiedAt); err != nil {
+		return nil, fmt.Errorf("failed to parse verified_at for %s: %w", ss.ScientificName, err)
+	}
+	ss.Verified = ss.VerifiedAt != nil
 
 	return ss, nil
 }
 
+// qualifiedColumns prefixes each of a comma-separated list of columns with a
+// table alias
+func qualifiedColumns(alias, columns string) string {
+	parts := strings.Split(columns, ",")
+	for i, c := range parts {
+		parts[i] = alias + "." + strings.TrimSpace(c)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// unmarshalFieldPages decodes a species source's field_pages column
+func unmarshalFieldPages(data sql.NullString, scientificName string) (map[string]string, error) {
+	if !data.Valid || data.String == "" {
+		return nil, nil
+	}
+	var pages map[string]string
+	if err := json.Unmarshal([]byte(data.String), &pages); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal field_pages for %s: %w", scientificName, err)
+	}
+	return pages, nil
+}
+
 // ListAllSpeciesSources returns all species_sources records (for export)
 func (db *Database) ListAllSpeciesSources() ([]*models.SpeciesSource, error) {
 	rows, err := db.conn.Query(
-		`SELECT id, scientific_name, source_id, local_names, range, growth_habit,
-		        leaves, flowers, fruits, bark, twigs, buds, hardiness_habitat,
-		        miscellaneous, url, is_preferred
+		`SELECT ` + speciesSourceColumns + `
 		 FROM species_sources ORDER BY scientific_name, is_preferred DESC`,
 	)
 	if err != nil {
@@ -1534,10 +2232,8 @@ func (db *Database) GetOakEntryWithSources(scientificName string) (*models.Speci
 
 	// Get sources with source metadata via join
 	rows, err := db.conn.Query(
-		`SELECT ss.id, ss.scientific_name, ss.source_id, ss.local_names, ss.range, ss.growth_habit,
-		        ss.leaves, ss.flowers, ss.fruits, ss.bark, ss.twigs, ss.buds, ss.hardiness_habitat,
-		        ss.miscellaneous, ss.url, ss.is_preferred,
-		        s.name, s.url
+		`SELECT `+qualifiedColumns("ss", speciesSourceColumns)+`,
+		        s.name, s.url, s.author, s.year
 		 FROM species_sources ss
 		 JOIN sources s ON ss.source_id = s.id
 		 WHERE ss.scientific_name = ?
@@ -1552,28 +2248,19 @@ func (db *Database) GetOakEntryWithSources(scientificName string) (*models.Speci
 	var sources []models.SpeciesSourceWithMeta
 	for rows.Next() {
 		var ssm models.SpeciesSourceWithMeta
-		var localNamesJSON sql.NullString
-		var isPreferred int
-
-		err := rows.Scan(
-			&ssm.ID, &ssm.ScientificName, &ssm.SourceID, &localNamesJSON, &ssm.Range, &ssm.GrowthHabit,
-			&ssm.Leaves, &ssm.Flowers, &ssm.Fruits, &ssm.Bark, &ssm.Twigs, &ssm.Buds, &ssm.HardinessHabitat,
-			&ssm.Miscellaneous, &ssm.URL, &isPreferred,
-			&ssm.SourceName, &ssm.SourceURL,
-		)
+		var author *string
+		var year *int
+		ss, err := scanSpeciesSource(rows, &ssm.SourceName, &ssm.SourceURL, &author, &year)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan species source with metadata: %w", err)
+			return nil, err
 		}
+		ssm.SpeciesSource = *ss
 
-		ssm.IsPreferred = isPreferred != 0
-		if localNamesJSON.Valid {
-			if err := json.Unmarshal([]byte(localNamesJSON.String), &ssm.LocalNames); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal local_names: %w", err)
-			}
-		}
-		if ssm.LocalNames == nil {
-			ssm.LocalNames = []string{}
+		var pages string
+		if ssm.Pages != nil {
+			pages = *ssm.Pages
 		}
+		ssm.Citation = models.Citation(author, year, ssm.SourceName, pages)
 
 		sources = append(sources, ssm)
 	}
@@ -1587,8 +2274,9 @@ func (db *Database) GetOakEntryWithSources(scientificName string) (*models.Speci
 	}
 
 	return &models.SpeciesWithSources{
-		OakEntry: *entry,
-		Sources:  sources,
+		OakEntry:      *entry,
+		Sources:       sources,
+		AuthorDetails: []models.Author{},
 	}, nil
 }
 
@@ -1598,6 +2286,8 @@ type Stats struct {
 	HybridCount  int `json:"hybrid_count"`
 	TaxaCount    int `json:"taxa_count"`
 	SourceCount  int `json:"source_count"`
+
+	SourcesByType map[string]int `json:"sources_by_type"`
 }
 
 // GetStats returns aggregate counts for species, hybrids, taxa, and sources
@@ -1619,10 +2309,27 @@ func (db *Database) GetStats() (*Stats, error) {
 		return nil, fmt.Errorf("failed to count taxa: %w", err)
 	}
 
-	// Count sources
+	// Count sources, in total and by type
 	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM sources`).Scan(&stats.SourceCount); err != nil {
 		return nil, fmt.Errorf("failed to count sources: %w", err)
 	}
+	rows, err := db.conn.Query(`SELECT source_type, COUNT(*) FROM sources GROUP BY source_type`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count sources by type: %w", err)
+	}
+	defer rows.Close()
+	stats.SourcesByType = make(map[string]int)
+	for rows.Next() {
+		var sourceType string
+		var count int
+		if err := rows.Scan(&sourceType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan source type count: %w", err)
+		}
+		stats.SourcesByType[sourceType] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	return stats, nil
 }
@@ -1655,7 +2362,8 @@ func (db *Database) GetHybridsReferencingParent(scientificName string) ([]string
 // Species are searched by: scientific_name, author, synonyms, local_names (from species_sources)
 // Taxa are searched by: name
 // Sources are searched by: name, author
-func (db *Database) UnifiedSearch(query string, limit int) (*models.UnifiedSearchResults, error) {
+// Draft species are only included when includeDrafts is set.
+func (db *Database) UnifiedSearch(query string, limit int, includeDrafts bool) (*models.UnifiedSearchResults, error) {
 	result := &models.UnifiedSearchResults{
 		Query:   query,
 		Species: []models.OakEntry{},
@@ -1663,28 +2371,29 @@ func (db *Database) UnifiedSearch(query string, limit int) (*models.UnifiedSearc
 		Sources: []models.Source{},
 	}
 
-	pattern := "%" + escapeLike(query) + "%"
+	pattern := likeKey(query)
 
 	// Search species: scientific_name, author, synonyms (JSON), local_names (via species_sources)
 	speciesRows, err := db.conn.Query(
 		`SELECT DISTINCT o.scientific_name, o.author, o.is_hybrid, o.conservation_status,
 		        o.subgenus, o.section, o.subsection, o.complex,
-		        o.parent1, o.parent2, o.hybrids, o.closely_related_to, o.subspecies_varieties, o.synonyms, o.external_links
+		        o.parent1, o.parent2, o.hybrids, o.closely_related_to, o.subspecies_varieties, o.synonyms, o.external_links, o.visibility, o.genus, o.pronunciation
 		 FROM oak_entries o
 		 LEFT JOIN species_sources ss ON o.scientific_name = ss.scientific_name
-		 WHERE o.scientific_name LIKE ? ESCAPE '\'
-		    OR o.author LIKE ? ESCAPE '\'
-		    OR o.synonyms LIKE ? ESCAPE '\'
-		    OR ss.local_names LIKE ? ESCAPE '\'
+		 WHERE (name_key(o.scientific_name) LIKE ? ESCAPE '\'
+		    OR name_key(o.author) LIKE ? ESCAPE '\'
+		    OR name_key(o.synonyms) LIKE ? ESCAPE '\'
+		    OR name_key(ss.local_names) LIKE ? ESCAPE '\')
+		   AND (? OR o.visibility = ?)
 		 ORDER BY o.scientific_name LIMIT ?`,
-		pattern, pattern, pattern, pattern, limit,
+		pattern, pattern, pattern, pattern, includeDrafts, models.VisibilityPublished, limit,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search species: %w", err)
 	}
 	defer speciesRows.Close()
 
-	entries, err := scanOakEntries(speciesRows)
+	entries, err := db.scanOakEntries(speciesRows)
 	if err != nil {
 		return nil, err
 	}
@@ -1694,15 +2403,9 @@ func (db *Database) UnifiedSearch(query string, limit int) (*models.UnifiedSearc
 
 	// Search taxa by name
 	taxaRows, err := db.conn.Query(
-		`SELECT t.name, t.level, t.parent, t.author, t.notes, t.links,
-		        (SELECT COUNT(*) FROM oak_entries o WHERE
-		            (t.level = 'subgenus' AND o.subgenus = t.name) OR
-		            (t.level = 'section' AND o.section = t.name) OR
-		            (t.level = 'subsection' AND o.subsection = t.name) OR
-		            (t.level = 'complex' AND o.complex = t.name)
-		        ) as species_count
+		`SELECT t.name, t.level, t.parent, t.author, t.notes, t.links, t.genus, `+cachedSpeciesCount+` as species_count
 		 FROM taxa t
-		 WHERE t.name LIKE ? ESCAPE '\'
+		 WHERE name_key(t.name) LIKE ? ESCAPE '\'
 		 ORDER BY t.level, t.name LIMIT ?`,
 		pattern, limit,
 	)
@@ -1715,7 +2418,7 @@ func (db *Database) UnifiedSearch(query string, limit int) (*models.UnifiedSearc
 		var t models.Taxon
 		var levelStr string
 		var linksJSON sql.NullString
-		if err := taxaRows.Scan(&t.Name, &levelStr, &t.Parent, &t.Author, &t.Notes, &linksJSON, &t.SpeciesCount); err != nil {
+		if err := taxaRows.Scan(&t.Name, &levelStr, &t.Parent, &t.Author, &t.Notes, &linksJSON, &t.Genus, &t.SpeciesCount); err != nil {
 			return nil, fmt.Errorf("failed to scan taxon: %w", err)
 		}
 		t.Level = models.TaxonLevel(levelStr)
@@ -1737,9 +2440,9 @@ func (db *Database) UnifiedSearch(query string, limit int) (*models.UnifiedSearc
 
 	// Search sources by name and author
 	sourceRows, err := db.conn.Query(
-		`SELECT id, source_type, name, description, author, year, url, isbn, doi, notes, license, license_url
+		`SELECT id, source_type, name, description, author, year, url, isbn, doi, notes, license, license_url, superseded_by
 		 FROM sources
-		 WHERE name LIKE ? ESCAPE '\' OR author LIKE ? ESCAPE '\'
+		 WHERE name_key(name) LIKE ? ESCAPE '\' OR name_key(author) LIKE ? ESCAPE '\'
 		 ORDER BY name LIMIT ?`,
 		pattern, pattern, limit,
 	)
@@ -1750,7 +2453,7 @@ func (db *Database) UnifiedSearch(query string, limit int) (*models.UnifiedSearc
 
 	for sourceRows.Next() {
 		var s models.Source
-		if err := sourceRows.Scan(&s.ID, &s.SourceType, &s.Name, &s.Description, &s.Author, &s.Year, &s.URL, &s.ISBN, &s.DOI, &s.Notes, &s.License, &s.LicenseURL); err != nil {
+		if err := sourceRows.Scan(&s.ID, &s.SourceType, &s.Name, &s.Description, &s.Author, &s.Year, &s.URL, &s.ISBN, &s.DOI, &s.Notes, &s.License, &s.LicenseURL, &s.SupersededBy); err != nil {
 			return nil, fmt.Errorf("failed to scan source: %w", err)
 		}
 		result.Sources = append(result.Sources, s)
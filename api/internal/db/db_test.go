@@ -1,8 +1,11 @@
 package db
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/jeff/oaks/api/internal/models"
@@ -401,6 +404,72 @@ func TestBidirectionalHybridWithNonExistentParent(t *testing.T) {
 	}
 }
 
+func TestConcurrentHybridSavesLoseNoUpdates(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	for _, name := range []string{"alba", "rubra"} {
+		if err := db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatalf("SaveOakEntry(%s) failed: %v", name, err)
+		}
+	}
+
+	// Each hybrid adds itself to both parents' lists; half then move from
+	// rubra to macrocarpa, which removes them from rubra's list again.
+	const n = 40
+	parent1, parent2, moved := "alba", "rubra", "macrocarpa"
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			hybrid := models.NewOakEntry(fmt.Sprintf("× hybrid%02d", i))
+			hybrid.IsHybrid = true
+			hybrid.Parent1, hybrid.Parent2 = &parent1, &parent2
+			if err := db.SaveOakEntry(hybrid); err != nil {
+				errs <- err
+				return
+			}
+			if i%2 == 0 {
+				hybrid.Parent2 = &moved
+				if err := db.SaveOakEntry(hybrid); err != nil {
+					errs <- err
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent SaveOakEntry failed: %v", err)
+	}
+
+	alba, err := db.GetOakEntry("alba")
+	if err != nil {
+		t.Fatalf("GetOakEntry(alba) failed: %v", err)
+	}
+	rubra, err := db.GetOakEntry("rubra")
+	if err != nil {
+		t.Fatalf("GetOakEntry(rubra) failed: %v", err)
+	}
+	if len(alba.Hybrids) != n {
+		t.Errorf("alba has %d hybrids, want %d: %v", len(alba.Hybrids), n, alba.Hybrids)
+	}
+	if len(rubra.Hybrids) != n/2 {
+		t.Errorf("rubra has %d hybrids, want %d: %v", len(rubra.Hybrids), n/2, rubra.Hybrids)
+	}
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("× hybrid%02d", i)
+		if !sliceContains(alba.Hybrids, name) {
+			t.Errorf("alba is missing %s", name)
+		}
+		if got, want := sliceContains(rubra.Hybrids, name), i%2 == 1; got != want {
+			t.Errorf("rubra lists %s = %v, want %v", name, got, want)
+		}
+	}
+}
+
 func TestSearchOakEntries(t *testing.T) {
 	db, cleanup := testDB(t)
 	defer cleanup()
@@ -463,6 +532,70 @@ func TestListOakEntries(t *testing.T) {
 	}
 }
 
+func TestListOakEntriesBySource(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	for _, name := range []string{"alba", "rubra", "velutina"} {
+		if err := db.SaveOakEntry(&models.OakEntry{ScientificName: name}); err != nil {
+			t.Fatalf("SaveOakEntry failed: %v", err)
+		}
+	}
+	bookID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Flora"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+	siteID, err := db.InsertSource(&models.Source{SourceType: "website", Name: "Oaks Online"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+	for _, ss := range []*models.SpeciesSource{
+		models.NewSpeciesSource("alba", bookID),
+		models.NewSpeciesSource("alba", siteID),
+		models.NewSpeciesSource("rubra", siteID),
+	} {
+		if err := db.SaveSpeciesSource(ss); err != nil {
+			t.Fatalf("SaveSpeciesSource failed: %v", err)
+		}
+	}
+
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		filter OakEntryFilter
+		want   []string
+	}{
+		{"by source", OakEntryFilter{SourceID: &siteID}, []string{"alba", "rubra"}},
+		{"not covered by source", OakEntryFilter{SourceID: &bookID, HasSource: &no}, []string{"rubra", "velutina"}},
+		{"any source", OakEntryFilter{HasSource: &yes}, []string{"alba", "rubra"}},
+		{"no source", OakEntryFilter{HasSource: &no}, []string{"velutina"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.IncludeDrafts = true
+			entries, err := db.ListOakEntriesPaginated(10, 0, &tt.filter)
+			if err != nil {
+				t.Fatalf("ListOakEntriesPaginated failed: %v", err)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.ScientificName)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+
+			count, err := db.CountOakEntries(&tt.filter)
+			if err != nil {
+				t.Fatalf("CountOakEntries failed: %v", err)
+			}
+			if count != len(tt.want) {
+				t.Errorf("count = %d, want %d", count, len(tt.want))
+			}
+		})
+	}
+}
+
 // Transaction tests
 
 func TestBeginTx(t *testing.T) {
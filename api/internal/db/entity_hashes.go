@@ -0,0 +1,284 @@
+package db
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Entity kinds with content hashes
+const (
+	HashKindSpecies        = "species"
+	HashKindSpeciesSources = "species_sources"
+	HashKindTaxa           = "taxa"
+	HashKindSources        = "sources"
+	HashKindGenera         = "genera"
+)
+
+// HashKinds lists every entity kind with content hashes
+var HashKinds = []string{HashKindSpecies, HashKindSpeciesSources, HashKindTaxa, HashKindSources, HashKindGenera}
+
+// entityHashKeys select the key of every entity of a kind. Keys are the
+// species name, "species/source_id", "level/name", the source ID, and the
+// genus name.
+var entityHashKeys = map[string]string{
+	HashKindSpecies:        `SELECT scientific_name AS key FROM oak_entries`,
+	HashKindSpeciesSources: `SELECT scientific_name || '/' || source_id AS key FROM species_sources`,
+	HashKindTaxa:           `SELECT level || '/' || name AS key FROM taxa`,
+	HashKindSources:        `SELECT CAST(id AS TEXT) AS key FROM sources`,
+	HashKindGenera:         `SELECT name AS key FROM genera`,
+}
+
+// draftHashKeys select the keys hidden from the public with draft species
+var draftHashKeys = map[string]string{
+	HashKindSpecies: `SELECT scientific_name FROM oak_entries WHERE visibility = 'draft'`,
+	HashKindSpeciesSources: `SELECT s.scientific_name || '/' || s.source_id FROM species_sources s
+		 JOIN oak_entries o ON o.scientific_name = s.scientific_name WHERE o.visibility = 'draft'`,
+}
+
+// entityHashTriggers drop an entity's stored hash whenever any path writes
+// the entity, so a stale hash is never served. Hashes are computed again on
+// the next read.
+var entityHashTriggers = concatTriggers(
+	entityHashTrigger("oak_entries", HashKindSpecies, "%s.scientific_name"),
+	entityHashTrigger("species_sources", HashKindSpeciesSources, "%[1]s.scientific_name || '/' || %[1]s.source_id"),
+	entityHashTrigger("taxa", HashKindTaxa, "%[1]s.level || '/' || %[1]s.name"),
+	entityHashTrigger("sources", HashKindSources, "CAST(%s.id AS TEXT)"),
+	entityHashTrigger("genera", HashKindGenera, "%s.name"),
+)
+
+// entityHashTrigger builds the insert, update, and delete triggers dropping
+// the hash of a table's rows. key formats the key expression for a row alias.
+func entityHashTrigger(table, kind, key string) []string {
+	drop := func(row string) string {
+		return fmt.Sprintf(`DELETE FROM entity_hashes WHERE kind = '%s' AND key = %s;`, kind, fmt.Sprintf(key, row))
+	}
+	return []string{
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS trg_%[1]s_entity_hash_insert
+			AFTER INSERT ON %[1]s BEGIN %[2]s END`, table, drop("NEW")),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS trg_%[1]s_entity_hash_update
+			AFTER UPDATE ON %[1]s BEGIN %[2]s %[3]s END`, table, drop("OLD"), drop("NEW")),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS trg_%[1]s_entity_hash_delete
+			AFTER DELETE ON %[1]s BEGIN %[2]s END`, table, drop("OLD")),
+	}
+}
+
+func concatTriggers(groups ...[]string) []string {
+	var all []string
+	for _, g := range groups {
+		all = append(all, g...)
+	}
+	return all
+}
+
+// EntityHash returns the content hash of an entity: the hex SHA-256 of its
+// canonical JSON, with object keys sorted and the named derived fields
+// (counts, row IDs) left out so equal content hashes equally on any server.
+func EntityHash(v interface{}, omit ...string) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal entity: %w", err)
+	}
+	var canonical map[string]interface{}
+	if err := json.Unmarshal(data, &canonical); err != nil {
+		return "", fmt.Errorf("failed to canonicalize entity: %w", err)
+	}
+	for _, field := range omit {
+		delete(canonical, field)
+	}
+	if data, err = json.Marshal(canonical); err != nil {
+		return "", fmt.Errorf("failed to marshal entity: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ErrEntityChanged is returned by a conditional write when the entity is no
+// longer the version its content hash named
+var ErrEntityChanged = errors.New("entity changed since it was hashed")
+
+// checkEntityHashTx fails with ErrEntityChanged unless hash is still the
+// entity's stored content hash. Every write to an entity drops its stored
+// hash (see entityHashTriggers), so checked inside the write's transaction
+// this proves nothing else wrote the entity since hash was read. An empty
+// hash always passes.
+func checkEntityHashTx(tx *sql.Tx, kind, key, hash string) error {
+	if hash == "" {
+		return nil
+	}
+	var n int
+	if err := tx.QueryRow(
+		`SELECT COUNT(*) FROM entity_hashes WHERE kind = ? AND key = ? AND hash = ?`, kind, key, hash,
+	).Scan(&n); err != nil {
+		return fmt.Errorf("failed to check entity hash: %w", err)
+	}
+	if n == 0 {
+		return ErrEntityChanged
+	}
+	return nil
+}
+
+// GetEntityHash returns the content hash of one entity, or "" if it does not exist
+func (db *Database) GetEntityHash(kind, key string) (string, error) {
+	if err := db.fillEntityHashes(kind); err != nil {
+		return "", err
+	}
+	var hash string
+	err := db.conn.QueryRow(`SELECT hash FROM entity_hashes WHERE kind = ? AND key = ? AND hash != ''`, kind, key).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get entity hash: %w", err)
+	}
+	return hash, nil
+}
+
+// ListEntityHashes returns the content hash of every entity of a kind by key.
+// Species and source data of draft species are left out unless includeDrafts.
+func (db *Database) ListEntityHashes(kind string, includeDrafts bool) (map[string]string, error) {
+	if err := db.fillEntityHashes(kind); err != nil {
+		return nil, err
+	}
+
+	// Rows written before the triggers existed may outlive their entity
+	query := `SELECT key, hash FROM entity_hashes WHERE kind = ? AND hash != '' AND key IN (` + entityHashKeys[kind] + `)`
+	if drafts, ok := draftHashKeys[kind]; ok && !includeDrafts {
+		query += ` AND key NOT IN (` + drafts + `)`
+	}
+	rows, err := db.conn.Query(query, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entity hashes: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := map[string]string{}
+	for rows.Next() {
+		var key, hash string
+		if err := rows.Scan(&key, &hash); err != nil {
+			return nil, fmt.Errorf("failed to scan entity hash: %w", err)
+		}
+		hashes[key] = hash
+	}
+	return hashes, rows.Err()
+}
+
+// fillEntityHashes computes and stores the hashes of a kind's entities that
+// have none, because they are new or were written since last hashed.
+//
+// Missing keys first get an empty placeholder. A write while the hashes are
+// computed deletes its placeholder through the triggers, so only hashes whose
+// placeholder survived are stored and a hash of replaced content never is.
+func (db *Database) fillEntityHashes(kind string) error {
+	keysQuery, ok := entityHashKeys[kind]
+	if !ok {
+		return fmt.Errorf("unknown entity kind %q", kind)
+	}
+
+	if _, err := db.conn.Exec(
+		`INSERT OR IGNORE INTO entity_hashes (kind, key, hash)
+		 SELECT ?, key, '' FROM (`+keysQuery+` EXCEPT SELECT key FROM entity_hashes WHERE kind = ? AND hash != '')`,
+		kind, kind,
+	); err != nil {
+		return fmt.Errorf("failed to mark unhashed entities: %w", err)
+	}
+	var pending int
+	if err := db.conn.QueryRow(
+		`SELECT COUNT(*) FROM entity_hashes WHERE kind = ? AND hash = ''`, kind,
+	).Scan(&pending); err != nil {
+		return fmt.Errorf("failed to count unhashed entities: %w", err)
+	}
+	if pending == 0 {
+		return nil
+	}
+
+	hashes, err := db.computeEntityHashes(kind)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+	for key, hash := range hashes {
+		if _, err := tx.Exec(
+			`UPDATE entity_hashes SET hash = ? WHERE kind = ? AND key = ? AND hash = ''`, hash, kind, key,
+		); err != nil {
+			return fmt.Errorf("failed to store entity hash: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// computeEntityHashes hashes every entity of a kind by key
+func (db *Database) computeEntityHashes(kind string) (map[string]string, error) {
+	hashes := map[string]string{}
+	add := func(key string, v interface{}, omit ...string) error {
+		hash, err := EntityHash(v, omit...)
+		if err != nil {
+			return err
+		}
+		hashes[key] = hash
+		return nil
+	}
+
+	switch kind {
+	case HashKindSpecies:
+		entries, err := db.ListOakEntries()
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if err := add(e.ScientificName, e, "data_error"); err != nil {
+				return nil, err
+			}
+		}
+	case HashKindSpeciesSources:
+		all, err := db.ListAllSpeciesSources()
+		if err != nil {
+			return nil, err
+		}
+		for _, ss := range all {
+			if err := add(ss.ScientificName+"/"+strconv.FormatInt(ss.SourceID, 10), ss, "id"); err != nil {
+				return nil, err
+			}
+		}
+	case HashKindTaxa:
+		taxa, err := db.ListTaxa(nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range taxa {
+			if err := add(string(t.Level)+"/"+t.Name, t, "species_count"); err != nil {
+				return nil, err
+			}
+		}
+	case HashKindSources:
+		sources, err := db.ListSources(nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, src := range sources {
+			if err := add(strconv.FormatInt(src.ID, 10), src); err != nil {
+				return nil, err
+			}
+		}
+	case HashKindGenera:
+		genera, err := db.ListGenera()
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range genera {
+			if err := add(g.Name, g, "species_count"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return hashes, nil
+}
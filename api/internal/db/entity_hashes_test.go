@@ -0,0 +1,123 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestEntityHashes(t *testing.T) {
+	first, cleanup := testDB(t)
+	defer cleanup()
+	second, cleanup2 := testDB(t)
+	defer cleanup2()
+
+	// The same content hashes equally on both databases; the second has an extra source
+	for i, database := range []*Database{first, second} {
+		for n := 0; n <= i; n++ {
+			if _, err := database.InsertSource(&models.Source{SourceType: "book", Name: "Flora"}); err != nil {
+				t.Fatalf("InsertSource failed: %v", err)
+			}
+		}
+		if err := database.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+			t.Fatalf("SaveOakEntry failed: %v", err)
+		}
+		leaves := "lobed"
+		if err := database.SaveSpeciesSource(&models.SpeciesSource{ScientificName: "alba", SourceID: 1, Leaves: &leaves}); err != nil {
+			t.Fatalf("SaveSpeciesSource failed: %v", err)
+		}
+	}
+
+	hash, err := first.GetEntityHash(HashKindSpeciesSources, "alba/1")
+	if err != nil || hash == "" {
+		t.Fatalf("GetEntityHash = %q, %v", hash, err)
+	}
+	if other, _ := second.GetEntityHash(HashKindSpeciesSources, "alba/1"); other != hash {
+		t.Errorf("equal source data hashed %s and %s", hash, other)
+	}
+	if missing, err := first.GetEntityHash(HashKindSpecies, "rubra"); err != nil || missing != "" {
+		t.Errorf("GetEntityHash(missing) = %q, %v; want empty", missing, err)
+	}
+
+	// A write by any path drops the stored hash, so the next read sees the change
+	species, _ := first.GetEntityHash(HashKindSpecies, "alba")
+	if _, err := first.conn.Exec(`UPDATE oak_entries SET author = 'L.' WHERE scientific_name = 'alba'`); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := first.GetEntityHash(HashKindSpecies, "alba"); changed == species || changed == "" {
+		t.Errorf("species hash after update = %q, was %q", changed, species)
+	}
+	if before, _ := second.ListEntityHashes(HashKindSources, false); len(before) != 2 {
+		t.Fatalf("ListEntityHashes(sources) = %v, want 2", before)
+	}
+	if _, err := second.conn.Exec(`DELETE FROM sources WHERE id = 2`); err != nil {
+		t.Fatal(err)
+	}
+	sources, err := second.ListEntityHashes(HashKindSources, false)
+	if err != nil || len(sources) != 1 || sources["1"] == "" {
+		t.Errorf("ListEntityHashes(sources) = %v, %v; want source 1 only", sources, err)
+	}
+}
+
+func TestEntityHashOmitsFields(t *testing.T) {
+	a, _ := EntityHash(map[string]interface{}{"name": "alba", "species_count": 3})
+	b, _ := EntityHash(map[string]interface{}{"species_count": 3, "name": "alba"})
+	c, _ := EntityHash(map[string]interface{}{"name": "alba", "species_count": 9}, "species_count")
+	d, _ := EntityHash(map[string]interface{}{"name": "alba"})
+	if a != b {
+		t.Error("key order changed the hash")
+	}
+	if c != d {
+		t.Error("omitted field changed the hash")
+	}
+}
+
+func TestConditionalWrites(t *testing.T) {
+	database, cleanup := testDB(t)
+	defer cleanup()
+
+	id, err := database.InsertSource(&models.Source{SourceType: "book", Name: "Flora"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+	if err := database.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+
+	// An editor reads the hash, then another write lands before the editor's save
+	hash, err := database.GetEntityHash(HashKindSpecies, "alba")
+	if err != nil || hash == "" {
+		t.Fatalf("GetEntityHash = %q, %v", hash, err)
+	}
+	author := "L."
+	other := models.NewOakEntry("alba")
+	other.Author = &author
+	if err := database.SaveOakEntry(other); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	if err := database.SaveOakEntryIfHash(models.NewOakEntry("alba"), hash); !errors.Is(err, ErrEntityChanged) {
+		t.Fatalf("SaveOakEntryIfHash(stale) = %v, want ErrEntityChanged", err)
+	}
+	if entry, _ := database.GetOakEntry("alba"); entry.Author == nil || *entry.Author != "L." {
+		t.Errorf("author = %v, want the other write's L. kept", entry.Author)
+	}
+
+	current, _ := database.GetEntityHash(HashKindSpecies, "alba")
+	if err := database.SaveOakEntryIfHash(models.NewOakEntry("alba"), current); err != nil {
+		t.Errorf("SaveOakEntryIfHash(current) = %v", err)
+	}
+
+	source := &models.Source{ID: id, SourceType: "book", Name: "Flora"}
+	hash, _ = database.GetEntityHash(HashKindSources, "1")
+	source.Name = "Flora of Texas"
+	if err := database.UpdateSource(source); err != nil {
+		t.Fatalf("UpdateSource failed: %v", err)
+	}
+	if err := database.UpdateSourceIfHash(source, hash); !errors.Is(err, ErrEntityChanged) {
+		t.Errorf("UpdateSourceIfHash(stale) = %v, want ErrEntityChanged", err)
+	}
+	if err := database.UpdateSourceIfHash(source, ""); err != nil {
+		t.Errorf("UpdateSourceIfHash(\"\") = %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// FeatureFlagOverrides returns the flags set at runtime, by name
+func (db *Database) FeatureFlagOverrides() (map[string]bool, error) {
+	rows, err := db.conn.Query(`SELECT name, enabled FROM feature_flags`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feature flags: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		var enabled bool
+		if err := rows.Scan(&name, &enabled); err != nil {
+			return nil, fmt.Errorf("failed to scan feature flag: %w", err)
+		}
+		overrides[name] = enabled
+	}
+	return overrides, rows.Err()
+}
+
+// SetFeatureFlag turns a flag on or off, overriding its configured value
+func (db *Database) SetFeatureFlag(name string, enabled bool) error {
+	_, err := db.conn.Exec(
+		`INSERT INTO feature_flags (name, enabled, updated_at) VALUES (?, ?, ?)
+		 ON CONFLICT (name) DO UPDATE SET enabled = excluded.enabled, updated_at = excluded.updated_at`,
+		name, enabled, time.Now().UTC().Format(timestampFormat),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set feature flag: %w", err)
+	}
+	return nil
+}
+
+// ClearFeatureFlag removes a flag's override, returning it to its configured
+// value
+func (db *Database) ClearFeatureFlag(name string) error {
+	if _, err := db.conn.Exec(`DELETE FROM feature_flags WHERE name = ?`, name); err != nil {
+		return fmt.Errorf("failed to clear feature flag: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package db
+
+import "testing"
+
+func TestFeatureFlags(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if err := db.SetFeatureFlag("api_v2", false); err != nil {
+		t.Fatalf("SetFeatureFlag failed: %v", err)
+	}
+	if err := db.SetFeatureFlag("api_v2", true); err != nil {
+		t.Fatal(err)
+	}
+	overrides, err := db.FeatureFlagOverrides()
+	if err != nil {
+		t.Fatalf("FeatureFlagOverrides failed: %v", err)
+	}
+	if len(overrides) != 1 || !overrides["api_v2"] {
+		t.Errorf("overrides = %v, want api_v2 on", overrides)
+	}
+
+	if err := db.ClearFeatureFlag("api_v2"); err != nil {
+		t.Fatalf("ClearFeatureFlag failed: %v", err)
+	}
+	if overrides, _ := db.FeatureFlagOverrides(); len(overrides) != 0 {
+		t.Errorf("overrides after clearing = %v, want none", overrides)
+	}
+}
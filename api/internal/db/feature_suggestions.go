@@ -0,0 +1,215 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/features"
+)
+
+// Review states of feature suggestions and range localities
+const (
+	SuggestionPending  = "pending"
+	SuggestionAccepted = "accepted"
+	SuggestionRejected = "rejected"
+)
+
+// FeatureSuggestion is a sentence from a species' source text that may state
+// its distinguishing features, awaiting a curator's review
+type FeatureSuggestion struct {
+	ID             int64      `json:"id"`
+	ScientificName string     `json:"scientific_name"`
+	SourceID       int64      `json:"source_id"`
+	Field          string     `json:"field"` // Text field the sentence came from, e.g. "leaves"
+	Sentence       string     `json:"sentence"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// GenerateFeatureSuggestions scans the given species' source text, or every
+// species' when none are given, and queues candidate distinguishing-feature
+// sentences for review. Sentences already suggested (including rejected ones)
+// or already in distinguishing_features are skipped. Returns how many were queued.
+func (db *Database) GenerateFeatureSuggestions(scientificNames ...string) (int, error) {
+	if len(scientificNames) == 0 {
+		rows, err := db.conn.Query(`SELECT DISTINCT scientific_name FROM species_sources ORDER BY scientific_name`)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list species: %w", err)
+		}
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				return 0, err
+			}
+			scientificNames = append(scientificNames, name)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	now := time.Now().UTC().Format(timestampFormat)
+	queued := 0
+	for _, name := range scientificNames {
+		sources, err := db.GetSpeciesSources(name)
+		if err != nil {
+			return 0, err
+		}
+		for _, ss := range sources {
+			confirmed := ""
+			if ss.DistinguishingFeatures != nil {
+				confirmed = *ss.DistinguishingFeatures
+			}
+			for _, f := range ss.TextFields() {
+				if f.Value == nil || f.Name == "distinguishing_features" {
+					continue
+				}
+				for _, sentence := range features.Candidates(*f.Value) {
+					if strings.Contains(confirmed, sentence) {
+						continue
+					}
+					result, err := db.conn.Exec(
+						`INSERT OR IGNORE INTO feature_suggestions (scientific_name, source_id, field, sentence, status, created_at)
+						 VALUES (?, ?, ?, ?, ?, ?)`,
+						name, ss.SourceID, f.Name, sentence, SuggestionPending, now,
+					)
+					if err != nil {
+						return 0, fmt.Errorf("failed to queue feature suggestion: %w", err)
+					}
+					if n, _ := result.RowsAffected(); n > 0 {
+						queued++
+					}
+				}
+			}
+		}
+	}
+	return queued, nil
+}
+
+// GetFeatureSuggestion returns a suggestion, or nil if it does not exist
+func (db *Database) GetFeatureSuggestion(id int64) (*FeatureSuggestion, error) {
+	rows, err := db.conn.Query(
+		`SELECT id, scientific_name, source_id, field, sentence, status, created_at, reviewed_at
+		 FROM feature_suggestions WHERE id = ?`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature suggestion: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions, err := scanFeatureSuggestions(rows)
+	if err != nil || len(suggestions) == 0 {
+		return nil, err
+	}
+	return suggestions[0], nil
+}
+
+// ListFeatureSuggestions returns suggestions ordered by species, source, and id.
+// Empty status or scientificName matches any.
+func (db *Database) ListFeatureSuggestions(status, scientificName string) ([]*FeatureSuggestion, error) {
+	query := `SELECT id, scientific_name, source_id, field, sentence, status, created_at, reviewed_at
+		FROM feature_suggestions WHERE 1 = 1`
+	var args []interface{}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	if scientificName != "" {
+		query += ` AND scientific_name = ?`
+		args = append(args, scientificName)
+	}
+	query += ` ORDER BY scientific_name, source_id, id`
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feature suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanFeatureSuggestions(rows)
+}
+
+// ReviewFeatureSuggestion accepts or rejects a pending suggestion. Accepting
+// appends the sentence to the species source's distinguishing_features.
+// Returns false if no pending suggestion has that id.
+func (db *Database) ReviewFeatureSuggestion(id int64, accept bool) (bool, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var name, sentence string
+	var sourceID int64
+	err = tx.QueryRow(
+		`SELECT scientific_name, source_id, sentence FROM feature_suggestions WHERE id = ? AND status = ?`,
+		id, SuggestionPending,
+	).Scan(&name, &sourceID, &sentence)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get feature suggestion: %w", err)
+	}
+
+	status := SuggestionRejected
+	if accept {
+		status = SuggestionAccepted
+		var current sql.NullString
+		err := tx.QueryRow(
+			`SELECT distinguishing_features FROM species_sources WHERE scientific_name = ? AND source_id = ?`,
+			name, sourceID,
+		).Scan(&current)
+		if err != nil {
+			return false, fmt.Errorf("failed to get distinguishing features: %w", err)
+		}
+		updated := sentence
+		if strings.TrimSpace(current.String) != "" {
+			updated = strings.TrimSpace(current.String) + " " + sentence
+		}
+		if _, err := tx.Exec(
+			`UPDATE species_sources SET distinguishing_features = ? WHERE scientific_name = ? AND source_id = ?`,
+			updated, name, sourceID,
+		); err != nil {
+			return false, fmt.Errorf("failed to update distinguishing features: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(
+		`UPDATE feature_suggestions SET status = ?, reviewed_at = ? WHERE id = ?`,
+		status, time.Now().UTC().Format(timestampFormat), id,
+	); err != nil {
+		return false, fmt.Errorf("failed to review feature suggestion: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit feature suggestion review: %w", err)
+	}
+	return true, nil
+}
+
+func scanFeatureSuggestions(rows *sql.Rows) ([]*FeatureSuggestion, error) {
+	var suggestions []*FeatureSuggestion
+	for rows.Next() {
+		var fs FeatureSuggestion
+		var createdAt string
+		var reviewedAt sql.NullString
+		if err := rows.Scan(&fs.ID, &fs.ScientificName, &fs.SourceID, &fs.Field, &fs.Sentence, &fs.Status, &createdAt, &reviewedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan feature suggestion: %w", err)
+		}
+		var err error
+		if fs.CreatedAt, err = time.Parse(timestampFormat, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at for feature suggestion %d: %w", fs.ID, err)
+		}
+		if fs.ReviewedAt, err = parseOptionalTimestamp(reviewedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse reviewed_at for feature suggestion %d: %w", fs.ID, err)
+		}
+		suggestions = append(suggestions, &fs)
+	}
+	return suggestions, rows.Err()
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestFeatureSuggestions(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if err := db.SaveOakEntry(&models.OakEntry{ScientificName: "shumardii"}); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	sourceID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Oaks of North America"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+	leaves := "Blades deeply lobed. Distinguished from Q. texana by its larger acorns."
+	fruits := "Cups shallow. Unlike Q. rubra, the cup scales are tight."
+	ss := models.NewSpeciesSource("shumardii", sourceID)
+	ss.Leaves = &leaves
+	ss.Fruits = &fruits
+	if err := db.SaveSpeciesSource(ss); err != nil {
+		t.Fatalf("SaveSpeciesSource failed: %v", err)
+	}
+
+	n, err := db.GenerateFeatureSuggestions()
+	if err != nil || n != 2 {
+		t.Fatalf("GenerateFeatureSuggestions() = %d, %v; want 2", n, err)
+	}
+	// Re-running queues nothing new
+	if n, _ := db.GenerateFeatureSuggestions("shumardii"); n != 0 {
+		t.Errorf("second run queued %d, want 0", n)
+	}
+
+	pending, err := db.ListFeatureSuggestions(SuggestionPending, "")
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("ListFeatureSuggestions() = %d, %v", len(pending), err)
+	}
+	if pending[0].Field != "leaves" || pending[0].Sentence != "Distinguished from Q. texana by its larger acorns." {
+		t.Errorf("suggestion = %+v", pending[0])
+	}
+
+	for _, s := range pending {
+		if ok, err := db.ReviewFeatureSuggestion(s.ID, true); !ok || err != nil {
+			t.Fatalf("ReviewFeatureSuggestion() = %v, %v", ok, err)
+		}
+	}
+	if ok, _ := db.ReviewFeatureSuggestion(pending[0].ID, false); ok {
+		t.Error("reviewing an accepted suggestion should fail")
+	}
+
+	got, _ := db.GetSpeciesSourceBySourceID("shumardii", sourceID)
+	want := "Distinguished from Q. texana by its larger acorns. Unlike Q. rubra, the cup scales are tight."
+	if got.DistinguishingFeatures == nil || *got.DistinguishingFeatures != want {
+		t.Errorf("distinguishing_features = %v, want %q", got.DistinguishingFeatures, want)
+	}
+	if s, _ := db.GetFeatureSuggestion(pending[0].ID); s.Status != SuggestionAccepted || s.ReviewedAt == nil {
+		t.Errorf("reviewed suggestion = %+v", s)
+	}
+
+	// Deleting the species source clears its suggestions
+	if err := db.DeleteSpeciesSource("shumardii", sourceID); err != nil {
+		t.Fatalf("DeleteSpeciesSource failed: %v", err)
+	}
+	if all, _ := db.ListFeatureSuggestions("", "shumardii"); len(all) != 0 {
+		t.Errorf("suggestions left after delete: %d", len(all))
+	}
+}
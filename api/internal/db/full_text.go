@@ -0,0 +1,283 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// ErrFullTextUnavailable is returned by full-text search when SQLite was
+// built without FTS5 (the sqlite_fts5 build tag)
+var ErrFullTextUnavailable = errors.New("full-text search needs SQLite with FTS5; build with -tags sqlite_fts5")
+
+// Snippet markers around the matched terms, stripped into TextSpans
+const (
+	fullTextMatchStart = "\x02"
+	fullTextMatchEnd   = "\x03"
+)
+
+// fullTextSnippetTokens is how many tokens a full-text snippet has at most
+const fullTextSnippetTokens = 24
+
+// fullTextTable indexes species source text fields, one row per field, and
+// each entry's synonyms as field "synonyms" with no source_id. Diacritics are
+// folded so "Mexico" finds "México".
+const fullTextTable = `CREATE VIRTUAL TABLE IF NOT EXISTS fulltext_index USING fts5(
+	scientific_name UNINDEXED,
+	source_id UNINDEXED,
+	field UNINDEXED,
+	text,
+	tokenize = 'unicode61 remove_diacritics 2'
+)`
+
+// indexSourceText inserts the non-empty text fields of a species source row
+// (NEW, or species_sources when from is a FROM clause) into fulltext_index
+func indexSourceText(row, from string) string {
+	var b strings.Builder
+	for _, f := range (&models.SpeciesSource{}).TextFields() {
+		fmt.Fprintf(&b, `INSERT INTO fulltext_index (scientific_name, source_id, field, text)
+			SELECT %[1]s.scientific_name, %[1]s.source_id, '%[2]s', %[1]s.%[2]s %[3]s WHERE %[1]s.%[2]s != '';
+		`, row, f.Name, from)
+	}
+	return b.String()
+}
+
+// indexSynonyms inserts an entry row's synonyms, one per line, into fulltext_index
+func indexSynonyms(row, from string) string {
+	return fmt.Sprintf(`INSERT INTO fulltext_index (scientific_name, source_id, field, text)
+		SELECT %[1]s.scientific_name, NULL, 'synonyms', (SELECT group_concat(value, char(10)) FROM json_each(%[1]s.synonyms))
+		%[2]s WHERE json_valid(%[1]s.synonyms) AND json_array_length(%[1]s.synonyms) > 0;`, row, from)
+}
+
+// unindexSource removes a species source row's text from fulltext_index
+func unindexSource(row string) string {
+	return fmt.Sprintf(`DELETE FROM fulltext_index WHERE scientific_name = %[1]s.scientific_name AND source_id = %[1]s.source_id;`, row)
+}
+
+// unindexSynonyms removes an entry row's synonyms from fulltext_index
+func unindexSynonyms(row string) string {
+	return fmt.Sprintf(`DELETE FROM fulltext_index WHERE scientific_name = %[1]s.scientific_name AND field = 'synonyms';`, row)
+}
+
+// sourceTextColumns lists the species_sources columns fulltext_index copies
+func sourceTextColumns() string {
+	fields := (&models.SpeciesSource{}).TextFields()
+	columns := make([]string, 0, len(fields)+2)
+	columns = append(columns, "scientific_name", "source_id")
+	for _, f := range fields {
+		columns = append(columns, f.Name)
+	}
+	return strings.Join(columns, ", ")
+}
+
+// fullTextTriggers keep fulltext_index current on every path that writes
+// species sources or entries. Species sources and entries are saved with
+// INSERT OR REPLACE, which removes the old row without firing delete
+// triggers, so the insert triggers drop the replaced row's text first.
+var fullTextTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS trg_species_sources_fulltext_replace
+		BEFORE INSERT ON species_sources
+		BEGIN ` + unindexSource("NEW") + ` END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_species_sources_fulltext_insert
+		AFTER INSERT ON species_sources
+		BEGIN ` + indexSourceText("NEW", "") + ` END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_species_sources_fulltext_update
+		AFTER UPDATE OF ` + sourceTextColumns() + ` ON species_sources
+		BEGIN ` + unindexSource("OLD") + ` ` + indexSourceText("NEW", "") + ` END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_species_sources_fulltext_delete
+		AFTER DELETE ON species_sources
+		BEGIN ` + unindexSource("OLD") + ` END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_fulltext_replace
+		BEFORE INSERT ON oak_entries
+		BEGIN ` + unindexSynonyms("NEW") + ` END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_fulltext_insert
+		AFTER INSERT ON oak_entries
+		BEGIN ` + indexSynonyms("NEW", "") + ` END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_fulltext_update
+		AFTER UPDATE OF scientific_name, synonyms ON oak_entries
+		BEGIN ` + unindexSynonyms("OLD") + ` ` + indexSynonyms("NEW", "") + ` END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_fulltext_delete
+		AFTER DELETE ON oak_entries
+		BEGIN ` + unindexSynonyms("OLD") + ` END`,
+}
+
+// initFullText creates fulltext_index and its triggers, filling the index
+// when it is new. Without FTS5 compiled in, full-text search is left off
+// rather than failing startup.
+func (db *Database) initFullText() error {
+	if _, err := db.conn.Exec(fullTextTable); err != nil {
+		if strings.Contains(err.Error(), "no such module: fts5") {
+			return nil
+		}
+		return fmt.Errorf("failed to create full-text index: %w", err)
+	}
+	db.fullText = true
+
+	for _, stmt := range fullTextTriggers {
+		if _, err := db.conn.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to execute schema statement: %w", err)
+		}
+	}
+
+	var empty bool
+	if err := db.conn.QueryRow(`SELECT NOT EXISTS (SELECT 1 FROM fulltext_index)`).Scan(&empty); err != nil {
+		return fmt.Errorf("failed to check full-text index: %w", err)
+	}
+	if empty {
+		if _, err := db.rebuildFullText(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FullTextAvailable reports whether SearchFullText can be used
+func (db *Database) FullTextAvailable() bool {
+	return db.fullText
+}
+
+// rebuildFullText refills fulltext_index from species sources and entries and
+// returns the number of rows indexed
+func (db *Database) rebuildFullText() (int, error) {
+	if !db.fullText {
+		return 0, nil
+	}
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	if _, err := tx.Exec(`DELETE FROM fulltext_index`); err != nil {
+		return 0, fmt.Errorf("failed to clear full-text index: %w", err)
+	}
+	if _, err := tx.Exec(indexSourceText("species_sources", "FROM species_sources") + indexSynonyms("oak_entries", "FROM oak_entries")); err != nil {
+		return 0, fmt.Errorf("failed to fill full-text index: %w", err)
+	}
+	var n int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM fulltext_index`).Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, tx.Commit()
+}
+
+// fullTextQuery turns a search into an FTS5 query: every word must appear,
+// "quoted phrases" must appear as written, and a trailing * matches any word
+// starting with the rest. Everything else is taken literally, so no input is
+// an FTS5 syntax error. Returns "" if there are no words.
+func fullTextQuery(query string) string {
+	var terms []string
+	for i, part := range strings.Split(query, `"`) {
+		if i%2 == 1 {
+			if words := strings.Fields(part); len(words) > 0 {
+				terms = append(terms, `"`+strings.Join(words, " ")+`"`)
+			}
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			prefix := strings.HasSuffix(word, "*")
+			if word = strings.Trim(word, "*"); word == "" {
+				continue
+			}
+			term := `"` + word + `"`
+			if prefix {
+				term += "*"
+			}
+			terms = append(terms, term)
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
+// fullTextVisible limits fulltext_index rows to published entries and public
+// fields unless the first argument is true
+const fullTextVisible = `(? OR (o.visibility = ? AND (ss.field_visibility IS NULL
+	OR json_extract(ss.field_visibility, '$.' || fulltext_index.field) IS NOT ?)))`
+
+// SearchFullText ranks species source text fields and synonyms by relevance
+// (BM25) to query, as parsed by fullTextQuery. Each match is a snippet of the
+// field around the matched terms. At most limit are returned, with the number
+// found. Draft species and internal fields are searched only if includeHidden.
+func (db *Database) SearchFullText(query string, limit int, includeHidden bool) ([]*models.FullTextMatch, int, error) {
+	if !db.fullText {
+		return nil, 0, ErrFullTextUnavailable
+	}
+	match := fullTextQuery(query)
+	if match == "" {
+		return []*models.FullTextMatch{}, 0, nil
+	}
+
+	const from = ` FROM fulltext_index
+		JOIN oak_entries o ON o.scientific_name = fulltext_index.scientific_name
+		LEFT JOIN species_sources ss ON ss.scientific_name = fulltext_index.scientific_name AND ss.source_id = fulltext_index.source_id
+		WHERE fulltext_index MATCH ? AND ` + fullTextVisible
+	args := []interface{}{match, includeHidden, models.VisibilityPublished, models.FieldInternal}
+
+	var total int
+	if err := db.conn.QueryRow(`SELECT COUNT(*)`+from, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count full-text matches: %w", err)
+	}
+
+	rows, err := db.conn.Query(
+		`SELECT fulltext_index.scientific_name, fulltext_index.source_id, fulltext_index.field,
+		        snippet(fulltext_index, 3, ?, ?, ?, ?), bm25(fulltext_index)`+from+`
+		 ORDER BY bm25(fulltext_index), fulltext_index.scientific_name LIMIT ?`,
+		append([]interface{}{fullTextMatchStart, fullTextMatchEnd, textSearchEllipsis, fullTextSnippetTokens}, append(args, limit)...)...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search full text: %w", err)
+	}
+	defer rows.Close()
+
+	matches := []*models.FullTextMatch{}
+	for rows.Next() {
+		var m models.FullTextMatch
+		var sourceID sql.NullInt64
+		var snippet string
+		var rank float64
+		if err := rows.Scan(&m.ScientificName, &sourceID, &m.Field, &snippet, &rank); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan full-text match: %w", err)
+		}
+		m.Location = m.Field
+		if sourceID.Valid {
+			m.Location = models.TextLocation(sourceID.Int64, m.Field)
+		}
+		m.Text, m.Matches = splitSnippet(snippet)
+		m.Score = math.Round(-rank*1000) / 1000
+		matches = append(matches, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return matches, total, nil
+}
+
+// splitSnippet removes the match markers from a snippet, returning the text
+// and where the marked terms are in it
+func splitSnippet(snippet string) (string, []models.TextSpan) {
+	var b strings.Builder
+	spans := []models.TextSpan{}
+	for {
+		start := strings.Index(snippet, fullTextMatchStart)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(snippet[start:], fullTextMatchEnd)
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(snippet[:start])
+		span := models.TextSpan{Start: b.Len()}
+		b.WriteString(snippet[start+len(fullTextMatchStart) : end])
+		span.End = b.Len()
+		spans = append(spans, span)
+		snippet = snippet[end+len(fullTextMatchEnd):]
+	}
+	b.WriteString(snippet)
+	return b.String(), spans
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestSearchFullText(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+	if !db.FullTextAvailable() {
+		if _, _, err := db.SearchFullText("bark", 10, false); err != ErrFullTextUnavailable {
+			t.Errorf("SearchFullText without FTS5 error = %v, want ErrFullTextUnavailable", err)
+		}
+		t.Skip("SQLite built without FTS5; run with -tags sqlite_fts5")
+	}
+
+	stellata := models.NewOakEntry("stellata")
+	stellata.Synonyms = []string{"Quercus obtusiloba", "Quercus minor"}
+	draft := models.NewOakEntry("draftii")
+	draft.Visibility = models.VisibilityDraft
+	for _, e := range []*models.OakEntry{models.NewOakEntry("alba"), stellata, draft} {
+		if err := db.SaveOakEntry(e); err != nil {
+			t.Fatalf("SaveOakEntry failed: %v", err)
+		}
+	}
+	sourceID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Flora"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+
+	save := func(name, leaves, bark string, visibility map[string]string) {
+		ss := models.NewSpeciesSource(name, sourceID)
+		ss.Leaves = &leaves
+		ss.Bark = &bark
+		ss.FieldVisibility = visibility
+		if err := db.SaveSpeciesSource(ss); err != nil {
+			t.Fatalf("SaveSpeciesSource failed: %v", err)
+		}
+	}
+	save("stellata", "Blades cruciform, with stellate hairs beneath; hairs dense.", "Bark gray, scaly.", nil)
+	save("alba", "Lobed, glabrous, rarely with stellate hairs when young.", "Bark pale gray, in flat plates. Hairs none.", map[string]string{"bark": models.FieldInternal})
+	save("draftii", "Stellate hairs.", "", nil)
+
+	search := func(query string, includeHidden bool) []string {
+		t.Helper()
+		matches, total, err := db.SearchFullText(query, 10, includeHidden)
+		if err != nil {
+			t.Fatalf("SearchFullText(%q) failed: %v", query, err)
+		}
+		if total != len(matches) {
+			t.Errorf("SearchFullText(%q) total = %d, returned %d", query, total, len(matches))
+		}
+		got := make([]string, len(matches))
+		for i, m := range matches {
+			got[i] = m.ScientificName + ":" + m.Location
+		}
+		return got
+	}
+	equal := func(got []string, want ...string) bool {
+		if len(got) != len(want) {
+			return false
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	// The field mentioning hairs most often ranks first; drafts and internal
+	// fields are left out
+	if got := search("stellate hairs", false); !equal(got, "stellata:sources/1/leaves", "alba:sources/1/leaves") {
+		t.Errorf("search stellate hairs = %v", got)
+	}
+	if got := search("hairs", true); !equal(got[len(got)-1:], "alba:sources/1/bark") || len(got) != 4 {
+		t.Errorf("search hairs with hidden = %v, want 4 with alba's bark", got)
+	}
+	if got := search(`"gray scaly"`, false); !equal(got, "stellata:sources/1/bark") {
+		t.Errorf("phrase search = %v", got)
+	}
+	if got := search("obtusi*", false); !equal(got, "stellata:synonyms") {
+		t.Errorf("prefix search of synonyms = %v", got)
+	}
+	if got := search(`cruciform AND OR "`, false); len(got) != 0 {
+		t.Errorf("operators are taken as words: %v", got)
+	}
+
+	matches, _, _ := db.SearchFullText("CRUCIFORM", 10, false)
+	if len(matches) != 1 || len(matches[0].Matches) != 1 || matches[0].Score <= 0 {
+		t.Fatalf("search cruciform = %+v", matches)
+	}
+	if m := matches[0]; m.Text[m.Matches[0].Start:m.Matches[0].End] != "cruciform" {
+		t.Errorf("match span = %+v in %q", m.Matches[0], m.Text)
+	}
+
+	// Edits, synonym changes, and deletes keep the index current
+	leaves := "Blades entire."
+	if _, err := db.conn.Exec(`UPDATE species_sources SET leaves = ? WHERE scientific_name = 'stellata'`, leaves); err != nil {
+		t.Fatal(err)
+	}
+	stellata.Synonyms = nil
+	if err := db.SaveOakEntry(stellata); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	if got := search("cruciform", false); len(got) != 0 {
+		t.Errorf("search after update = %v", got)
+	}
+	if got := search("obtusiloba", false); len(got) != 0 {
+		t.Errorf("search after removing synonyms = %v", got)
+	}
+	if err := db.DeleteSpeciesSource("alba", sourceID); err != nil {
+		t.Fatalf("DeleteSpeciesSource failed: %v", err)
+	}
+	if got := search("glabrous", true); len(got) != 0 {
+		t.Errorf("search after delete = %v", got)
+	}
+
+	// A rebuild indexes the same rows
+	if n, err := db.rebuildFullText(); err != nil || n != 3 {
+		t.Errorf("rebuildFullText() = %d, %v; want 3 rows", n, err)
+	}
+}
+
+func TestFullTextQuery(t *testing.T) {
+	tests := []struct{ query, want string }{
+		{"stellate hairs", `"stellate" "hairs"`},
+		{`"gray  scaly" bark`, `"gray scaly" "bark"`},
+		{"obtus* * **", `"obtus"*`},
+		{`NEAR(a b) OR -c`, `"NEAR(a" "b)" "OR" "-c"`},
+		{`unclosed "phrase`, `"unclosed" "phrase"`},
+		{"  ", ""},
+	}
+	for _, tt := range tests {
+		if got := fullTextQuery(tt.query); got != tt.want {
+			t.Errorf("fullTextQuery(%q) = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+const generaSelect = `SELECT g.name, g.common_name, g.subgenera,
+	       (SELECT COUNT(*) FROM oak_entries o WHERE o.genus = g.name) as species_count
+	FROM genera g`
+
+// ListGenera returns every genus with its species count, ordered by name
+func (db *Database) ListGenera() ([]*models.Genus, error) {
+	rows, err := db.conn.Query(generaSelect + ` ORDER BY g.name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list genera: %w", err)
+	}
+	defer rows.Close()
+
+	var genera []*models.Genus
+	for rows.Next() {
+		g, err := scanGenus(rows)
+		if err != nil {
+			return nil, err
+		}
+		genera = append(genera, g)
+	}
+	return genera, rows.Err()
+}
+
+// GetGenus gets a genus by name, or nil if it does not exist
+func (db *Database) GetGenus(name string) (*models.Genus, error) {
+	g, err := scanGenus(db.conn.QueryRow(generaSelect+` WHERE g.name = ?`, name))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return g, err
+}
+
+// SaveGenus creates or replaces a genus
+func (db *Database) SaveGenus(genus *models.Genus) error {
+	subgenera := genus.Subgenera
+	if subgenera == nil {
+		subgenera = []string{}
+	}
+	subgeneraJSON, err := json.Marshal(subgenera)
+	if err != nil {
+		return fmt.Errorf("failed to marshal subgenera: %w", err)
+	}
+
+	_, err = db.conn.Exec(
+		`INSERT INTO genera (name, common_name, subgenera) VALUES (?, ?, ?)
+		 ON CONFLICT(name) DO UPDATE SET common_name = excluded.common_name, subgenera = excluded.subgenera`,
+		genus.Name, genus.CommonName, string(subgeneraJSON),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save genus: %w", err)
+	}
+	return nil
+}
+
+// GenusUsage counts the oak entries and taxa that belong to a genus
+func (db *Database) GenusUsage(name string) (entries, taxa int, err error) {
+	err = db.conn.QueryRow(
+		`SELECT (SELECT COUNT(*) FROM oak_entries WHERE genus = ?),
+		        (SELECT COUNT(*) FROM taxa WHERE genus = ?)`,
+		name, name,
+	).Scan(&entries, &taxa)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count genus usage: %w", err)
+	}
+	return entries, taxa, nil
+}
+
+// DeleteGenus deletes a genus. Returns false if it does not exist.
+// Callers should check GenusUsage first; entries are not reassigned.
+func (db *Database) DeleteGenus(name string) (bool, error) {
+	result, err := db.conn.Exec(`DELETE FROM genera WHERE name = ?`, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete genus: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// scanGenus scans one row of generaSelect
+func scanGenus(row interface{ Scan(...interface{}) error }) (*models.Genus, error) {
+	var g models.Genus
+	var subgeneraJSON sql.NullString
+	if err := row.Scan(&g.Name, &g.CommonName, &subgeneraJSON, &g.SpeciesCount); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan genus: %w", err)
+	}
+	if subgeneraJSON.Valid && subgeneraJSON.String != "" {
+		if err := json.Unmarshal([]byte(subgeneraJSON.String), &g.Subgenera); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal subgenera for %s: %w", g.Name, err)
+		}
+	}
+	if g.Subgenera == nil {
+		g.Subgenera = []string{}
+	}
+	return &g, nil
+}
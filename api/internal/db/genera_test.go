@@ -0,0 +1,90 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestGenera(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	// Quercus is seeded with its subgenera
+	quercus, err := db.GetGenus(models.DefaultGenus)
+	if err != nil || quercus == nil {
+		t.Fatalf("GetGenus(Quercus) = %v, %v", quercus, err)
+	}
+	if len(quercus.Subgenera) != 3 {
+		t.Errorf("Quercus subgenera = %v, want 3", quercus.Subgenera)
+	}
+
+	common := "Hickories"
+	if err := db.SaveGenus(&models.Genus{Name: "Carya", CommonName: &common}); err != nil {
+		t.Fatalf("SaveGenus failed: %v", err)
+	}
+
+	// Entries default to Quercus; an explicit genus is kept across resaves
+	if err := db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	ovata := models.NewOakEntry("ovata")
+	ovata.Genus = "Carya"
+	if err := db.SaveOakEntry(ovata); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	if err := db.SaveOakEntry(models.NewOakEntry("ovata")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	got, err := db.GetOakEntry("ovata")
+	if err != nil {
+		t.Fatalf("GetOakEntry failed: %v", err)
+	}
+	if got.Genus != "Carya" {
+		t.Errorf("genus after resave = %q, want Carya", got.Genus)
+	}
+
+	genus := "Carya"
+	list, err := db.ListOakEntriesPaginated(10, 0, &OakEntryFilter{Genus: &genus})
+	if err != nil {
+		t.Fatalf("ListOakEntriesPaginated failed: %v", err)
+	}
+	if len(list) != 1 || list[0].ScientificName != "ovata" {
+		t.Errorf("Carya entries = %d, want only ovata", len(list))
+	}
+	if n, _ := db.CountOakEntries(&OakEntryFilter{Genus: &genus}); n != 1 {
+		t.Errorf("Carya count = %d, want 1", n)
+	}
+
+	// Taxa belong to a genus and only count that genus' species
+	if err := db.InsertTaxon(&models.Taxon{Name: "Carya", Level: models.TaxonLevelSection, Genus: "Carya"}); err != nil {
+		t.Fatalf("InsertTaxon failed: %v", err)
+	}
+	if err := db.InsertTaxon(&models.Taxon{Name: "Quercus", Level: models.TaxonLevelSubgenus}); err != nil {
+		t.Fatalf("InsertTaxon failed: %v", err)
+	}
+	taxa, err := db.ListTaxa(&TaxaListParams{Genus: &genus})
+	if err != nil {
+		t.Fatalf("ListTaxa failed: %v", err)
+	}
+	if len(taxa) != 1 || taxa[0].Genus != "Carya" {
+		t.Errorf("Carya taxa = %+v, want one Carya section", taxa)
+	}
+
+	entries, taxaCount, err := db.GenusUsage("Carya")
+	if err != nil || entries != 1 || taxaCount != 1 {
+		t.Errorf("GenusUsage = %d, %d, %v; want 1, 1", entries, taxaCount, err)
+	}
+
+	genera, err := db.ListGenera()
+	if err != nil {
+		t.Fatalf("ListGenera failed: %v", err)
+	}
+	if len(genera) != 2 || genera[0].Name != "Carya" || genera[0].SpeciesCount != 1 {
+		t.Errorf("ListGenera = %+v", genera)
+	}
+
+	if found, err := db.DeleteGenus("Acer"); err != nil || found {
+		t.Errorf("DeleteGenus(missing) = %v, %v", found, err)
+	}
+}
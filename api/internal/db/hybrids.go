@@ -0,0 +1,125 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// HybridsDiscrepancy is a species whose stored hybrids list differs from the
+// one derived from the parent1/parent2 columns of every entry.
+type HybridsDiscrepancy struct {
+	ScientificName string   `json:"scientific_name"`
+	Hybrids        []string `json:"hybrids"`           // The derived list
+	Missing        []string `json:"missing,omitempty"` // Derived but not stored
+	Extra          []string `json:"extra,omitempty"`   // Stored but not derived
+	Error          string   `json:"error,omitempty"`   // Why the stored list could not be read
+}
+
+// FindHybridsDiscrepancies compares every species' stored hybrids list with
+// the one derived from parent links, changing nothing
+func (db *Database) FindHybridsDiscrepancies() ([]HybridsDiscrepancy, error) {
+	return findHybridsDiscrepancies(db.conn)
+}
+
+// RebuildHybrids rewrites every hybrids list that differs from the one
+// derived from parent links, in one transaction, and returns the
+// differences. Unlike the reindex step, hybrid names with no entry of their
+// own are dropped: the lists are derived purely from parent1/parent2, which
+// recovers databases imported before SaveOakEntry kept both sides in step.
+func (db *Database) RebuildHybrids() ([]HybridsDiscrepancy, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	found, err := findHybridsDiscrepancies(tx)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range found {
+		data, err := json.Marshal(d.Hybrids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal hybrids: %w", err)
+		}
+		if _, err := tx.Exec(`UPDATE oak_entries SET hybrids = ? WHERE scientific_name = ?`, string(data), d.ScientificName); err != nil {
+			return nil, fmt.Errorf("failed to update hybrids for %s: %w", d.ScientificName, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit hybrids rebuild: %w", err)
+	}
+	return found, nil
+}
+
+// findHybridsDiscrepancies derives each species' hybrids from the entries
+// naming it as a parent and compares them, as sets, with the stored lists
+func findHybridsDiscrepancies(q interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}) ([]HybridsDiscrepancy, error) {
+	rows, err := q.Query(
+		`SELECT scientific_name, parent1, parent2, hybrids FROM oak_entries ORDER BY scientific_name`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oak entries: %w", err)
+	}
+	defer rows.Close()
+
+	type entry struct {
+		name    string
+		hybrids sql.NullString
+	}
+	var entries []entry
+	derived := make(map[string][]string)
+	for rows.Next() {
+		var e entry
+		var parent1, parent2 sql.NullString
+		if err := rows.Scan(&e.name, &parent1, &parent2, &e.hybrids); err != nil {
+			return nil, fmt.Errorf("failed to scan oak entry: %w", err)
+		}
+		entries = append(entries, e)
+		for _, p := range []sql.NullString{parent1, parent2} {
+			if p.Valid && p.String != "" && !sliceContains(derived[p.String], e.name) {
+				derived[p.String] = append(derived[p.String], e.name)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	found := []HybridsDiscrepancy{}
+	for _, e := range entries {
+		want := derived[e.name]
+		if want == nil {
+			want = []string{}
+		}
+		slices.Sort(want)
+
+		d := HybridsDiscrepancy{ScientificName: e.name, Hybrids: want}
+		stored, err := decodeJSONList[string](e.hybrids)
+		if err != nil {
+			d.Error = err.Error()
+			d.Missing = want
+			found = append(found, d)
+			continue
+		}
+		for _, h := range want {
+			if !slices.Contains(stored, h) {
+				d.Missing = append(d.Missing, h)
+			}
+		}
+		for _, h := range stored {
+			if !slices.Contains(want, h) && !slices.Contains(d.Extra, h) {
+				d.Extra = append(d.Extra, h)
+			}
+		}
+		if len(d.Missing) > 0 || len(d.Extra) > 0 {
+			found = append(found, d)
+		}
+	}
+	return found, nil
+}
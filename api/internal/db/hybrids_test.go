@@ -0,0 +1,72 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestRebuildHybrids(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	for _, name := range []string{"alba", "macrocarpa", "robur"} {
+		if err := db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatalf("SaveOakEntry(%s) failed: %v", name, err)
+		}
+	}
+	parent1, parent2 := "alba", "macrocarpa"
+	hybrid := models.NewOakEntry("× bebbiana")
+	hybrid.IsHybrid = true
+	hybrid.Parent1 = &parent1
+	hybrid.Parent2 = &parent2
+	if err := db.SaveOakEntry(hybrid); err != nil {
+		t.Fatalf("SaveOakEntry(hybrid) failed: %v", err)
+	}
+
+	if found, err := db.FindHybridsDiscrepancies(); err != nil || len(found) != 0 {
+		t.Fatalf("FindHybridsDiscrepancies() on consistent data = %+v, %v", found, err)
+	}
+
+	// An import that predates bidirectional maintenance: alba's list is
+	// empty, macrocarpa lists a hybrid with no entry, robur's list is corrupt
+	for name, hybrids := range map[string]string{"alba": `[]`, "macrocarpa": `["× bebbiana","× unlisted"]`, "robur": `["× bebb`} {
+		if _, err := db.conn.Exec(`UPDATE oak_entries SET hybrids = ? WHERE scientific_name = ?`, hybrids, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, err := db.FindHybridsDiscrepancies()
+	if err != nil {
+		t.Fatalf("FindHybridsDiscrepancies failed: %v", err)
+	}
+	if len(found) != 3 {
+		t.Fatalf("FindHybridsDiscrepancies() = %+v, want alba, macrocarpa, and robur", found)
+	}
+	if d := found[0]; d.ScientificName != "alba" || !stringSlicesEqual(d.Missing, []string{"× bebbiana"}) || d.Extra != nil {
+		t.Errorf("alba discrepancy = %+v", d)
+	}
+	if d := found[1]; d.ScientificName != "macrocarpa" || d.Missing != nil || !stringSlicesEqual(d.Extra, []string{"× unlisted"}) {
+		t.Errorf("macrocarpa discrepancy = %+v", d)
+	}
+	if d := found[2]; d.ScientificName != "robur" || d.Error == "" || len(d.Hybrids) != 0 {
+		t.Errorf("robur discrepancy = %+v", d)
+	}
+
+	rebuilt, err := db.RebuildHybrids()
+	if err != nil || len(rebuilt) != 3 {
+		t.Fatalf("RebuildHybrids() = %+v, %v; want 3 species", rebuilt, err)
+	}
+	for name, want := range map[string][]string{"alba": {"× bebbiana"}, "macrocarpa": {"× bebbiana"}, "robur": {}} {
+		entry, err := db.GetOakEntry(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !stringSlicesEqual(entry.Hybrids, want) || entry.DataError != "" {
+			t.Errorf("%s hybrids after rebuild = %v (%s), want %v", name, entry.Hybrids, entry.DataError, want)
+		}
+	}
+	if found, err := db.FindHybridsDiscrepancies(); err != nil || len(found) != 0 {
+		t.Errorf("FindHybridsDiscrepancies() after rebuild = %+v, %v", found, err)
+	}
+}
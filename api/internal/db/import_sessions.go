@@ -0,0 +1,262 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// Import session states
+const (
+	ImportSessionOpen      = "open"
+	ImportSessionCommitted = "committed"
+	ImportSessionDiscarded = "discarded"
+)
+
+// ImportSession groups bulk uploads made over several requests so they are
+// applied all together on commit, or not at all
+type ImportSession struct {
+	ID        int64      `json:"id"`
+	Status    string     `json:"status"`
+	Batches   int        `json:"batches"` // Uploads staged
+	Items     int        `json:"items"`   // Rows across all staged uploads
+	CreatedAt time.Time  `json:"created_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+}
+
+// ImportBatchResult is the outcome of one staged upload when a session is committed
+type ImportBatchResult struct {
+	Batch    int                         `json:"batch"` // 1-based, in upload order
+	SourceID int64                       `json:"source_id"`
+	Results  []SpeciesSourceUpsertResult `json:"results"`
+}
+
+// stagedUpsert is the stored form of a SpeciesSourceUpsert
+type stagedUpsert struct {
+	SpeciesSource *models.SpeciesSource `json:"species_source"`
+	ContentHash   string                `json:"content_hash,omitempty"`
+}
+
+// CreateImportSession opens a new import session
+func (db *Database) CreateImportSession() (*ImportSession, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	result, err := db.conn.Exec(
+		`INSERT INTO import_sessions (status, created_at) VALUES (?, ?)`,
+		ImportSessionOpen, now.Format(timestampFormat),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create import session: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import session id: %w", err)
+	}
+	return &ImportSession{ID: id, Status: ImportSessionOpen, CreatedAt: now}, nil
+}
+
+// GetImportSession returns a session with its staged counts, or nil if it does not exist
+func (db *Database) GetImportSession(id int64) (*ImportSession, error) {
+	var s ImportSession
+	var createdAt string
+	var closedAt sql.NullString
+	err := db.conn.QueryRow(
+		`SELECT id, status, batches, items, created_at, closed_at FROM import_sessions WHERE id = ?`, id,
+	).Scan(&s.ID, &s.Status, &s.Batches, &s.Items, &createdAt, &closedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import session: %w", err)
+	}
+	if s.CreatedAt, err = time.Parse(timestampFormat, createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at for import session %d: %w", id, err)
+	}
+	if s.ClosedAt, err = parseOptionalTimestamp(closedAt); err != nil {
+		return nil, fmt.Errorf("failed to parse closed_at for import session %d: %w", id, err)
+	}
+	return &s, nil
+}
+
+// StageSpeciesSources adds a bulk species-source upload for one source to an
+// open session without applying it. Returns the batch number, or 0 if the
+// session is not open.
+func (db *Database) StageSpeciesSources(sessionID, sourceID int64, upserts []SpeciesSourceUpsert) (int, error) {
+	staged := make([]stagedUpsert, len(upserts))
+	for i, u := range upserts {
+		u.SpeciesSource.SourceID = sourceID
+		staged[i] = stagedUpsert{SpeciesSource: u.SpeciesSource, ContentHash: u.ContentHash}
+	}
+	data, err := json.Marshal(staged)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal import batch: %w", err)
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if open, err := importSessionOpen(tx, sessionID); err != nil || !open {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO import_session_batches (session_id, source_id, rows, created_at) VALUES (?, ?, ?, ?)`,
+		sessionID, sourceID, string(data), time.Now().UTC().Format(timestampFormat),
+	); err != nil {
+		return 0, fmt.Errorf("failed to stage import batch: %w", err)
+	}
+	if _, err := tx.Exec(
+		`UPDATE import_sessions SET batches = batches + 1, items = items + ? WHERE id = ?`,
+		len(staged), sessionID,
+	); err != nil {
+		return 0, fmt.Errorf("failed to update import session: %w", err)
+	}
+	var batch int
+	if err := tx.QueryRow(`SELECT batches FROM import_sessions WHERE id = ?`, sessionID).Scan(&batch); err != nil {
+		return 0, fmt.Errorf("failed to get import session: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit import batch: %w", err)
+	}
+	return batch, nil
+}
+
+// CommitImportSession applies every batch staged in an open session, in upload
+// order, in a single transaction. If any row fails, nothing is written, the
+// session stays open, and applied is false; results report which rows failed.
+// Returns nil results and applied false if the session is not open.
+func (db *Database) CommitImportSession(id int64) (results []ImportBatchResult, applied bool, err error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if open, err := importSessionOpen(tx, id); err != nil || !open {
+		return nil, false, err
+	}
+
+	batches, err := stagedBatches(tx, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	results = []ImportBatchResult{}
+	failed := false
+	for i, b := range batches {
+		result := ImportBatchResult{Batch: i + 1, SourceID: b.sourceID, Results: make([]SpeciesSourceUpsertResult, len(b.rows))}
+		for j, row := range b.rows {
+			ss := row.SpeciesSource
+			ss.SourceID = b.sourceID
+			result.Results[j] = SpeciesSourceUpsertResult{ScientificName: ss.ScientificName}
+
+			status, rowErr, err := upsertSpeciesSourceTx(tx, ss, row.ContentHash)
+			if err != nil {
+				return nil, false, err
+			}
+			if rowErr != "" {
+				result.Results[j].Status = UpsertFailed
+				result.Results[j].Error = rowErr
+				failed = true
+				continue
+			}
+			result.Results[j].Status = status
+		}
+		results = append(results, result)
+	}
+	if failed {
+		return results, false, nil
+	}
+
+	if err := closeImportSession(tx, id, ImportSessionCommitted); err != nil {
+		return nil, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit import session: %w", err)
+	}
+	return results, true, nil
+}
+
+// DiscardImportSession drops an open session's staged batches without applying
+// them. Returns false if the session is not open.
+func (db *Database) DiscardImportSession(id int64) (bool, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if open, err := importSessionOpen(tx, id); err != nil || !open {
+		return false, err
+	}
+
+	if err := closeImportSession(tx, id, ImportSessionDiscarded); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit import session discard: %w", err)
+	}
+	return true, nil
+}
+
+// importSessionOpen reports whether a session exists and is open
+func importSessionOpen(tx *sql.Tx, id int64) (bool, error) {
+	var status string
+	err := tx.QueryRow(`SELECT status FROM import_sessions WHERE id = ?`, id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get import session: %w", err)
+	}
+	return status == ImportSessionOpen, nil
+}
+
+// stagedBatch is one upload read back from import_session_batches
+type stagedBatch struct {
+	sourceID int64
+	rows     []stagedUpsert
+}
+
+func stagedBatches(tx *sql.Tx, sessionID int64) ([]stagedBatch, error) {
+	rows, err := tx.Query(
+		`SELECT source_id, rows FROM import_session_batches WHERE session_id = ? ORDER BY id`, sessionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import session batches: %w", err)
+	}
+	defer rows.Close()
+
+	var batches []stagedBatch
+	for rows.Next() {
+		var b stagedBatch
+		var data string
+		if err := rows.Scan(&b.sourceID, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan import session batch: %w", err)
+		}
+		if err := json.Unmarshal([]byte(data), &b.rows); err != nil {
+			return nil, fmt.Errorf("failed to parse import session batch: %w", err)
+		}
+		batches = append(batches, b)
+	}
+	return batches, rows.Err()
+}
+
+// closeImportSession marks a session committed or discarded and drops its staged batches
+func closeImportSession(tx *sql.Tx, id int64, status string) error {
+	if _, err := tx.Exec(`DELETE FROM import_session_batches WHERE session_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete import session batches: %w", err)
+	}
+	if _, err := tx.Exec(
+		`UPDATE import_sessions SET status = ?, closed_at = ? WHERE id = ?`,
+		status, time.Now().UTC().Format(timestampFormat), id,
+	); err != nil {
+		return fmt.Errorf("failed to close import session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestImportSession(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if err := db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	sourceID, err := db.InsertSource(&models.Source{SourceType: "website", Name: "Scraped"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+
+	session, err := db.CreateImportSession()
+	if err != nil {
+		t.Fatalf("CreateImportSession failed: %v", err)
+	}
+
+	// A batch with a missing species stages fine but fails the commit
+	for _, name := range []string{"alba", "missing"} {
+		rows := []SpeciesSourceUpsert{{SpeciesSource: models.NewSpeciesSource(name, 0), ContentHash: "h1"}}
+		if _, err := db.StageSpeciesSources(session.ID, sourceID, rows); err != nil {
+			t.Fatalf("StageSpeciesSources(%s) failed: %v", name, err)
+		}
+	}
+	got, err := db.GetImportSession(session.ID)
+	if err != nil {
+		t.Fatalf("GetImportSession failed: %v", err)
+	}
+	if got.Status != ImportSessionOpen || got.Batches != 2 || got.Items != 2 {
+		t.Fatalf("session = %+v, want open with 2 batches, 2 items", got)
+	}
+
+	results, applied, err := db.CommitImportSession(session.ID)
+	if err != nil {
+		t.Fatalf("CommitImportSession failed: %v", err)
+	}
+	if applied || len(results) != 2 || results[1].Results[0].Status != UpsertFailed {
+		t.Fatalf("commit = %+v, applied %v, want batch 2 failed and nothing applied", results, applied)
+	}
+	if ss, _ := db.GetSpeciesSources("alba"); len(ss) != 0 {
+		t.Errorf("failed commit wrote %d species sources, want 0", len(ss))
+	}
+	if got, _ := db.GetImportSession(session.ID); got.Status != ImportSessionOpen {
+		t.Errorf("status after failed commit = %s, want open", got.Status)
+	}
+
+	// Discarding closes the session; later uploads and commits are refused
+	discarded, err := db.DiscardImportSession(session.ID)
+	if err != nil || !discarded {
+		t.Fatalf("DiscardImportSession = %v, %v, want true", discarded, err)
+	}
+	if batch, err := db.StageSpeciesSources(session.ID, sourceID, nil); err != nil || batch != 0 {
+		t.Errorf("StageSpeciesSources after discard = %d, %v, want 0", batch, err)
+	}
+	if _, applied, err := db.CommitImportSession(session.ID); err != nil || applied {
+		t.Errorf("CommitImportSession after discard = %v, %v, want false", applied, err)
+	}
+
+	// A clean session applies every batch at once
+	session, err = db.CreateImportSession()
+	if err != nil {
+		t.Fatalf("CreateImportSession failed: %v", err)
+	}
+	rows := []SpeciesSourceUpsert{{SpeciesSource: models.NewSpeciesSource("alba", 0), ContentHash: "h1"}}
+	if batch, err := db.StageSpeciesSources(session.ID, sourceID, rows); err != nil || batch != 1 {
+		t.Fatalf("StageSpeciesSources = %d, %v, want batch 1", batch, err)
+	}
+	results, applied, err = db.CommitImportSession(session.ID)
+	if err != nil || !applied {
+		t.Fatalf("CommitImportSession = %v, %v, want applied", applied, err)
+	}
+	if results[0].Results[0].Status != UpsertCreated {
+		t.Errorf("status = %s, want created", results[0].Results[0].Status)
+	}
+	got, err = db.GetImportSession(session.ID)
+	if err != nil {
+		t.Fatalf("GetImportSession failed: %v", err)
+	}
+	if got.Status != ImportSessionCommitted || got.ClosedAt == nil || got.Batches != 1 {
+		t.Errorf("session = %+v, want committed with closed_at and 1 batch", got)
+	}
+}
@@ -0,0 +1,264 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Job states
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed" // Out of attempts
+	JobCanceled  = "canceled"
+)
+
+// Job is a unit of background work and its captured log
+type Job struct {
+	ID          int64           `json:"id"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAfter    time.Time       `json:"run_after"`
+	LastError   *string         `json:"last_error,omitempty"`
+	Log         string          `json:"log"`
+	CreatedAt   time.Time       `json:"created_at"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+}
+
+const jobColumns = `id, kind, payload, status, attempts, max_attempts, run_after, last_error, log, created_at, started_at, finished_at`
+
+// EnqueueJob queues a job to run as soon as a worker is free
+func (db *Database) EnqueueJob(kind string, payload json.RawMessage, maxAttempts int) (*Job, error) {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	var payloadArg interface{}
+	if len(payload) > 0 {
+		payloadArg = string(payload)
+	}
+
+	now := time.Now().UTC().Format(timestampFormat)
+	result, err := db.conn.Exec(
+		`INSERT INTO jobs (kind, payload, status, max_attempts, run_after, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		kind, payloadArg, JobQueued, maxAttempts, now, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job id: %w", err)
+	}
+	return db.GetJob(id)
+}
+
+// GetJob returns a job by ID, or nil if it does not exist
+func (db *Database) GetJob(id int64) (*Job, error) {
+	rows, err := db.conn.Query(`SELECT `+jobColumns+` FROM jobs WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	defer rows.Close()
+
+	jobs, err := scanJobs(rows)
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return jobs[0], nil
+}
+
+// ListJobs returns the most recent jobs first, optionally filtered by status
+func (db *Database) ListJobs(status string, limit int) ([]*Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs`
+	var args []interface{}
+	if status != "" {
+		query += ` WHERE status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanJobs(rows)
+}
+
+// ClaimNextJob marks the oldest runnable queued job as running and returns it,
+// or nil if none is due. Claiming is atomic, so concurrent workers never share a job.
+func (db *Database) ClaimNextJob(now time.Time) (*Job, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ts := now.UTC().Format(timestampFormat)
+	var id int64
+	err = tx.QueryRow(
+		`SELECT id FROM jobs WHERE status = ? AND run_after <= ? ORDER BY run_after, id LIMIT 1`,
+		JobQueued, ts,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find queued job: %w", err)
+	}
+
+	result, err := tx.Exec(
+		`UPDATE jobs SET status = ?, attempts = attempts + 1, started_at = ?, finished_at = NULL
+		 WHERE id = ? AND status = ?`,
+		JobRunning, ts, id, JobQueued,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim job: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit job claim: %w", err)
+	}
+	return db.GetJob(id)
+}
+
+// CompleteJob records a job as succeeded
+func (db *Database) CompleteJob(id int64) error {
+	_, err := db.conn.Exec(
+		`UPDATE jobs SET status = ?, last_error = NULL, finished_at = ? WHERE id = ?`,
+		JobSucceeded, time.Now().UTC().Format(timestampFormat), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+	return nil
+}
+
+// FailJob records a failed attempt. The job is queued again to run at retryAt
+// if it has attempts left; otherwise it is marked failed. Returns the new status.
+func (db *Database) FailJob(id int64, jobErr error, retryAt time.Time) (string, error) {
+	var attempts, maxAttempts int
+	if err := db.conn.QueryRow(
+		`SELECT attempts, max_attempts FROM jobs WHERE id = ?`, id,
+	).Scan(&attempts, &maxAttempts); err != nil {
+		return "", fmt.Errorf("failed to get job attempts: %w", err)
+	}
+
+	status := JobQueued
+	if attempts >= maxAttempts {
+		status = JobFailed
+	}
+	now := time.Now().UTC().Format(timestampFormat)
+	_, err := db.conn.Exec(
+		`UPDATE jobs SET status = ?, last_error = ?, run_after = ?, finished_at = ? WHERE id = ?`,
+		status, jobErr.Error(), retryAt.UTC().Format(timestampFormat), now, id,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to record job failure: %w", err)
+	}
+	return status, nil
+}
+
+// CancelJob cancels a queued job. Returns false if no queued job has that ID.
+func (db *Database) CancelJob(id int64) (bool, error) {
+	result, err := db.conn.Exec(
+		`UPDATE jobs SET status = ?, finished_at = ? WHERE id = ? AND status = ?`,
+		JobCanceled, time.Now().UTC().Format(timestampFormat), id, JobQueued,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel job: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// AppendJobLog adds a line to a job's captured log
+func (db *Database) AppendJobLog(id int64, line string) error {
+	_, err := db.conn.Exec(`UPDATE jobs SET log = log || ? WHERE id = ?`, line+"\n", id)
+	if err != nil {
+		return fmt.Errorf("failed to append job log: %w", err)
+	}
+	return nil
+}
+
+// RequeueRunningJobs returns jobs left running by a previous process to the queue.
+// Call it once at startup, before workers begin claiming.
+func (db *Database) RequeueRunningJobs() (int, error) {
+	result, err := db.conn.Exec(`UPDATE jobs SET status = ? WHERE status = ?`, JobQueued, JobRunning)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue running jobs: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(n), nil
+}
+
+func scanJobs(rows *sql.Rows) ([]*Job, error) {
+	var jobs []*Job
+	for rows.Next() {
+		var job Job
+		var payload, startedAt, finishedAt sql.NullString
+		var runAfter, createdAt string
+		if err := rows.Scan(
+			&job.ID, &job.Kind, &payload, &job.Status, &job.Attempts, &job.MaxAttempts,
+			&runAfter, &job.LastError, &job.Log, &createdAt, &startedAt, &finishedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		if payload.Valid {
+			job.Payload = json.RawMessage(payload.String)
+		}
+		var err error
+		if job.RunAfter, err = time.Parse(timestampFormat, runAfter); err != nil {
+			return nil, fmt.Errorf("failed to parse run_after for job %d: %w", job.ID, err)
+		}
+		if job.CreatedAt, err = time.Parse(timestampFormat, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at for job %d: %w", job.ID, err)
+		}
+		if job.StartedAt, err = parseOptionalTimestamp(startedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse started_at for job %d: %w", job.ID, err)
+		}
+		if job.FinishedAt, err = parseOptionalTimestamp(finishedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse finished_at for job %d: %w", job.ID, err)
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, rows.Err()
+}
+
+// parseOptionalTimestamp parses a nullable timestampFormat column
+func parseOptionalTimestamp(s sql.NullString) (*time.Time, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	t, err := time.Parse(timestampFormat, s.String)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// formatOptionalTimestamp formats t for a nullable timestampFormat column
+func formatOptionalTimestamp(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.UTC().Format(timestampFormat)
+	return &s
+}
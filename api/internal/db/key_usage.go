@@ -0,0 +1,112 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// KeyUsage is an API key's authenticated request count and when it was
+// last used. Days, when requested, breaks the count down by UTC day.
+type KeyUsage struct {
+	KeyID      string           `json:"id"`
+	Requests   int              `json:"requests"`
+	FirstUsed  string           `json:"first_used"` // UTC day, e.g. "2026-10-16"
+	LastUsedAt time.Time        `json:"last_used_at"`
+	Days       []*DailyKeyUsage `json:"days,omitempty"`
+}
+
+// DailyKeyUsage is an API key's request count on one UTC day
+type DailyKeyUsage struct {
+	Day      string `json:"day"`
+	Requests int    `json:"requests"`
+}
+
+// RecordKeyUsage adds one request by the key to the count for at's UTC day
+// and moves its last-used time to at if that is later
+func (db *Database) RecordKeyUsage(keyID string, at time.Time) error {
+	_, err := db.conn.Exec(
+		`INSERT INTO api_key_usage (key_id, day, requests, last_used_at) VALUES (?, ?, 1, ?)
+		 ON CONFLICT(key_id, day) DO UPDATE SET requests = requests + 1, last_used_at = MAX(last_used_at, excluded.last_used_at)`,
+		keyID, at.UTC().Format(dayFormat), at.UTC().Format(timestampFormat),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record key usage: %w", err)
+	}
+	return nil
+}
+
+// ListKeyUsage returns the all-time usage of every key that has made a
+// request, most recently used first
+func (db *Database) ListKeyUsage() ([]*KeyUsage, error) {
+	rows, err := db.conn.Query(
+		`SELECT key_id, SUM(requests), MIN(day), MAX(last_used_at) FROM api_key_usage
+		 GROUP BY key_id
+		 ORDER BY MAX(last_used_at) DESC, key_id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list key usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []*KeyUsage
+	for rows.Next() {
+		u, err := scanKeyUsage(rows)
+		if err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
+
+// GetKeyUsage returns a key's all-time usage with its daily counts from
+// since's UTC day onward, or nil if the key has never been used
+func (db *Database) GetKeyUsage(keyID string, since time.Time) (*KeyUsage, error) {
+	u, err := scanKeyUsage(db.conn.QueryRow(
+		`SELECT key_id, SUM(requests), MIN(day), MAX(last_used_at) FROM api_key_usage
+		 WHERE key_id = ? GROUP BY key_id`,
+		keyID,
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.conn.Query(
+		`SELECT day, requests FROM api_key_usage WHERE key_id = ? AND day >= ? ORDER BY day`,
+		keyID, since.UTC().Format(dayFormat),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily key usage: %w", err)
+	}
+	defer rows.Close()
+
+	u.Days = []*DailyKeyUsage{}
+	for rows.Next() {
+		var d DailyKeyUsage
+		if err := rows.Scan(&d.Day, &d.Requests); err != nil {
+			return nil, fmt.Errorf("failed to scan daily key usage: %w", err)
+		}
+		u.Days = append(u.Days, &d)
+	}
+	return u, rows.Err()
+}
+
+func scanKeyUsage(row rowScanner) (*KeyUsage, error) {
+	var u KeyUsage
+	var lastUsedAt string
+	if err := row.Scan(&u.KeyID, &u.Requests, &u.FirstUsed, &lastUsedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan key usage: %w", err)
+	}
+	var err error
+	if u.LastUsedAt, err = time.Parse(timestampFormat, lastUsedAt); err != nil {
+		return nil, fmt.Errorf("failed to parse last_used_at for key %s: %w", u.KeyID, err)
+	}
+	return &u, nil
+}
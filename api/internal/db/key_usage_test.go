@@ -0,0 +1,63 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyUsage(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	today := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+	uses := []struct {
+		key string
+		at  time.Time
+	}{
+		{"current", today}, {"current", today.Add(-time.Hour)}, {"current", today.AddDate(0, 0, -1)},
+		{"current", today.AddDate(0, -1, 0)},
+		{"retired", today.AddDate(0, -2, 0)},
+	}
+	for _, u := range uses {
+		if err := db.RecordKeyUsage(u.key, u.at); err != nil {
+			t.Fatalf("RecordKeyUsage(%s) failed: %v", u.key, err)
+		}
+	}
+
+	all, err := db.ListKeyUsage()
+	if err != nil {
+		t.Fatalf("ListKeyUsage failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("ListKeyUsage returned %d keys, want 2", len(all))
+	}
+	if all[0].KeyID != "current" || all[0].Requests != 4 || !all[0].LastUsedAt.Equal(today) || all[0].FirstUsed != "2026-09-16" {
+		t.Errorf("all[0] = %+v, want current with 4 requests, first used 2026-09-16, last used %v", all[0], today)
+	}
+	if all[1].KeyID != "retired" || all[1].Requests != 1 {
+		t.Errorf("all[1] = %+v, want retired with 1 request", all[1])
+	}
+
+	// Daily counts cover the period; the totals are all-time
+	usage, err := db.GetKeyUsage("current", today.AddDate(0, 0, -6))
+	if err != nil {
+		t.Fatalf("GetKeyUsage failed: %v", err)
+	}
+	if usage == nil || usage.Requests != 4 {
+		t.Fatalf("GetKeyUsage = %+v, want 4 requests", usage)
+	}
+	want := []DailyKeyUsage{{Day: "2026-10-15", Requests: 1}, {Day: "2026-10-16", Requests: 2}}
+	if len(usage.Days) != len(want) {
+		t.Fatalf("got %d days, want %d", len(usage.Days), len(want))
+	}
+	for i, w := range want {
+		if *usage.Days[i] != w {
+			t.Errorf("days[%d] = %+v, want %+v", i, *usage.Days[i], w)
+		}
+	}
+
+	usage, err = db.GetKeyUsage("unknown", today)
+	if err != nil || usage != nil {
+		t.Errorf("GetKeyUsage(unknown) = %+v, %v; want nil, nil", usage, err)
+	}
+}
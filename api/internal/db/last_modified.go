@@ -0,0 +1,49 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// SpeciesUpdatedAt returns when a species or its source data last changed.
+// Returns nil for a missing species or one written before updated_at was tracked.
+func (db *Database) SpeciesUpdatedAt(name string) (*time.Time, error) {
+	return db.queryUpdatedAt(`SELECT updated_at FROM oak_entries WHERE scientific_name = ?`, name)
+}
+
+// TaxonUpdatedAt returns when a taxon last changed. Its species count moves
+// with the species in it, so any species change in the taxon's genus counts.
+func (db *Database) TaxonUpdatedAt(name string, level models.TaxonLevel) (*time.Time, error) {
+	return db.queryUpdatedAt(
+		`SELECT MAX(COALESCE(t.updated_at, ''), COALESCE((SELECT MAX(o.updated_at) FROM oak_entries o WHERE o.genus = t.genus), ''))
+		 FROM taxa t WHERE t.name = ? AND t.level = ?`,
+		name, level,
+	)
+}
+
+// SourceUpdatedAt returns when a source last changed
+func (db *Database) SourceUpdatedAt(id int64) (*time.Time, error) {
+	return db.queryUpdatedAt(`SELECT updated_at FROM sources WHERE id = ?`, id)
+}
+
+func (db *Database) queryUpdatedAt(query string, args ...interface{}) (*time.Time, error) {
+	var updatedAt sql.NullString
+	if err := db.conn.QueryRow(query, args...).Scan(&updatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get updated_at: %w", err)
+	}
+	if !updatedAt.Valid || updatedAt.String == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(timestampFormat, updatedAt.String)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse updated_at %q: %w", updatedAt.String, err)
+	}
+	return &t, nil
+}
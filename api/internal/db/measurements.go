@@ -0,0 +1,119 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/api/internal/units"
+)
+
+// ListMeasurements returns the lengths parsed from a species' source text,
+// ordered by source, field, and position in the text
+func (db *Database) ListMeasurements(scientificName string) ([]*models.Measurement, error) {
+	rows, err := db.conn.Query(
+		`SELECT scientific_name, source_id, field, text, min_value, max_value, unit FROM measurements
+		 WHERE scientific_name = ? ORDER BY source_id, field, position`,
+		scientificName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list measurements: %w", err)
+	}
+	defer rows.Close()
+
+	var measurements []*models.Measurement
+	for rows.Next() {
+		var m models.Measurement
+		if err := rows.Scan(&m.ScientificName, &m.SourceID, &m.Field, &m.Text, &m.Min, &m.Max, &m.Unit); err != nil {
+			return nil, fmt.Errorf("failed to scan measurement: %w", err)
+		}
+		measurements = append(measurements, &m)
+	}
+	return measurements, rows.Err()
+}
+
+// RefreshMeasurements re-parses the given species' source text and replaces
+// their stored measurements. Call it after writing species-source data.
+func (db *Database) RefreshMeasurements(scientificNames ...string) error {
+	for _, name := range scientificNames {
+		if _, err := db.refreshMeasurements(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebuildMeasurements re-parses every species' source text.
+// Returns the number of measurements stored.
+func (db *Database) rebuildMeasurements() (int, error) {
+	rows, err := db.conn.Query(`SELECT DISTINCT scientific_name FROM species_sources ORDER BY scientific_name`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list species: %w", err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if _, err := db.conn.Exec(`DELETE FROM measurements`); err != nil {
+		return 0, fmt.Errorf("failed to clear measurements: %w", err)
+	}
+
+	total := 0
+	for _, name := range names {
+		n, err := db.refreshMeasurements(name)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// refreshMeasurements replaces one species' measurements and returns how many were stored
+func (db *Database) refreshMeasurements(scientificName string) (int, error) {
+	sources, err := db.GetSpeciesSources(scientificName)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM measurements WHERE scientific_name = ?`, scientificName); err != nil {
+		return 0, fmt.Errorf("failed to clear measurements: %w", err)
+	}
+	count := 0
+	for _, ss := range sources {
+		for _, f := range ss.TextFields() {
+			if f.Value == nil {
+				continue
+			}
+			for i, m := range units.Find(*f.Value) {
+				_, err := tx.Exec(
+					`INSERT INTO measurements (scientific_name, source_id, field, position, text, min_value, max_value, unit)
+					 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+					scientificName, ss.SourceID, f.Name, i, (*f.Value)[m.Start:m.End], m.Min, m.Max, m.Unit,
+				)
+				if err != nil {
+					return 0, fmt.Errorf("failed to save measurement: %w", err)
+				}
+				count++
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit measurements: %w", err)
+	}
+	return count, nil
+}
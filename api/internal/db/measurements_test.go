@@ -0,0 +1,60 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestMeasurements(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if err := db.SaveOakEntry(&models.OakEntry{ScientificName: "alba"}); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	sourceID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Oaks of North America"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+
+	leaves := "Blades 10-22 cm long, petioles 1-2.5 cm."
+	habit := "Trees up to 30 m."
+	ss := models.NewSpeciesSource("alba", sourceID)
+	ss.Leaves = &leaves
+	ss.GrowthHabit = &habit
+	if err := db.SaveSpeciesSource(ss); err != nil {
+		t.Fatalf("SaveSpeciesSource failed: %v", err)
+	}
+	if err := db.RefreshMeasurements("alba"); err != nil {
+		t.Fatalf("RefreshMeasurements failed: %v", err)
+	}
+
+	got, err := db.ListMeasurements("alba")
+	if err != nil {
+		t.Fatalf("ListMeasurements failed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d measurements, want 3: %+v", len(got), got)
+	}
+	if got[0].Field != "growth_habit" || got[0].Min != nil || got[0].Max != 30 || got[0].Unit != "m" {
+		t.Errorf("first measurement = %+v", got[0])
+	}
+	if got[1].Text != "10-22 cm" || got[1].Min == nil || *got[1].Min != 10 || got[1].Max != 22 {
+		t.Errorf("second measurement = %+v", got[1])
+	}
+
+	// Reindex rebuilds from scratch, and deleting the species source drops them
+	if _, err := db.conn.Exec(`DELETE FROM measurements`); err != nil {
+		t.Fatal(err)
+	}
+	if items, err := db.rebuildMeasurements(); err != nil || items != 3 {
+		t.Errorf("rebuildMeasurements = %d, %v; want 3", items, err)
+	}
+	if err := db.DeleteSpeciesSource("alba", sourceID); err != nil {
+		t.Fatalf("DeleteSpeciesSource failed: %v", err)
+	}
+	if got, _ := db.ListMeasurements("alba"); len(got) != 0 {
+		t.Errorf("measurements after delete = %+v, want none", got)
+	}
+}
@@ -0,0 +1,183 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Media origins
+const (
+	MediaOriginINaturalist = "inaturalist" // Photo URLs cached from iNaturalist
+	MediaOriginUpload      = "upload"      // Files uploaded to the server's media directory
+	MediaOriginLink        = "link"        // Media kept elsewhere, attached by URL
+)
+
+// MediaKinds are what a species photo can show
+var MediaKinds = []string{"leaf", "bark", "acorn", "twig", "bud", "flower", "habit", "specimen", "other"}
+
+// ErrMediaExists is returned when adding a photo already attached to the
+// species
+var ErrMediaExists = errors.New("media already attached to species")
+
+// mediaColumns are the species_media columns scanMedia reads
+const mediaColumns = `id, scientific_name, url, thumbnail_url, caption, license, attribution, origin, origin_id,
+	file, thumbnail_file, filename, width, height, captured_at, latitude, longitude, kind, source_id, created_at`
+
+// Media is a photo or other media of a species, kept as a URL
+type Media struct {
+	ID             int64     `json:"id"`
+	ScientificName string    `json:"scientific_name"`
+	URL            string    `json:"url"`
+	ThumbnailURL   *string   `json:"thumbnail_url,omitempty"`
+	Caption        *string   `json:"caption,omitempty"`
+	License        *string   `json:"license,omitempty"`
+	Attribution    *string   `json:"attribution,omitempty"`
+	Origin         string    `json:"origin"`
+	OriginID       *string   `json:"origin_id,omitempty"` // The media's ID at its origin
+	File           *string   `json:"file,omitempty"`      // Uploads: the file in the media directory
+	ThumbnailFile  *string   `json:"thumbnail_file,omitempty"`
+	Filename       *string   `json:"filename,omitempty"` // Uploads: the name it was uploaded under
+	Width          *int      `json:"width,omitempty"`
+	Height         *int      `json:"height,omitempty"`
+	CapturedAt     *string   `json:"captured_at,omitempty"` // From EXIF, as the camera recorded it
+	Latitude       *float64  `json:"latitude,omitempty"`    // From EXIF
+	Longitude      *float64  `json:"longitude,omitempty"`
+	Kind           *string   `json:"kind,omitempty"`      // One of MediaKinds
+	SourceID       *int64    `json:"source_id,omitempty"` // The source the media comes from
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Generalized is the grid cell size in degrees the coordinates were
+	// moved to the center of for the public; 0 if they are exact
+	Generalized float64 `json:"generalized,omitempty"`
+}
+
+// ListSpeciesMedia returns a species' media from origin, or from every
+// origin if origin is empty, in order
+func (db *Database) ListSpeciesMedia(scientificName, origin string) ([]*Media, error) {
+	rows, err := db.conn.Query(
+		`SELECT `+mediaColumns+`
+		   FROM species_media WHERE scientific_name = ?1 AND (?2 = '' OR origin = ?2)
+		  ORDER BY position, id`,
+		scientificName, origin,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list species media: %w", err)
+	}
+	defer rows.Close()
+
+	media := []*Media{}
+	for rows.Next() {
+		m, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		media = append(media, m)
+	}
+	return media, rows.Err()
+}
+
+// ReplaceOriginMedia replaces a species' media from one origin with media,
+// in order, leaving media from other origins alone
+func (db *Database) ReplaceOriginMedia(scientificName, origin string, media []*Media) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	if _, err := tx.Exec(`DELETE FROM species_media WHERE scientific_name = ? AND origin = ?`, scientificName, origin); err != nil {
+		return fmt.Errorf("failed to clear species media: %w", err)
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, m := range media {
+		res, err := tx.Exec(
+			`INSERT INTO species_media (scientific_name, url, thumbnail_url, caption, license, attribution, origin, origin_id, position, created_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			scientificName, m.URL, m.ThumbnailURL, m.Caption, m.License, m.Attribution, origin, m.OriginID, i, now.Format(timestampFormat),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save species media: %w", err)
+		}
+		if m.ID, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		m.ScientificName, m.Origin, m.CreatedAt = scientificName, origin, now
+	}
+	return tx.Commit()
+}
+
+// AddSpeciesMedia attaches media to a species after its other media,
+// setting m's ID and CreatedAt. Returns ErrMediaExists if media with the same
+// origin and origin ID is already attached.
+func (db *Database) AddSpeciesMedia(m *Media) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	res, err := db.conn.Exec(
+		`INSERT INTO species_media (scientific_name, url, thumbnail_url, caption, license, attribution, origin, origin_id,
+		     file, thumbnail_file, filename, width, height, captured_at, latitude, longitude, kind, source_id, position, created_at)
+		 VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12, ?13, ?14, ?15, ?16, ?17, ?18,
+		     (SELECT COALESCE(MAX(position) + 1, 0) FROM species_media WHERE scientific_name = ?1), ?19)`,
+		m.ScientificName, m.URL, m.ThumbnailURL, m.Caption, m.License, m.Attribution, m.Origin, m.OriginID,
+		m.File, m.ThumbnailFile, m.Filename, m.Width, m.Height, m.CapturedAt, m.Latitude, m.Longitude,
+		m.Kind, m.SourceID, now.Format(timestampFormat),
+	)
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return ErrMediaExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save species media: %w", err)
+	}
+	if m.ID, err = res.LastInsertId(); err != nil {
+		return err
+	}
+	m.CreatedAt = now
+	return nil
+}
+
+// GetSpeciesMedia gets media by ID, or nil if there is none
+func (db *Database) GetSpeciesMedia(id int64) (*Media, error) {
+	m, err := scanMedia(db.conn.QueryRow(`SELECT `+mediaColumns+` FROM species_media WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return m, err
+}
+
+// DeleteSpeciesMedia deletes media by ID, reporting whether it existed. The
+// caller removes an uploaded file no other media uses (see MediaFileInUse).
+func (db *Database) DeleteSpeciesMedia(id int64) (bool, error) {
+	res, err := db.conn.Exec(`DELETE FROM species_media WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete species media: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// MediaFileInUse reports whether any media is the uploaded file
+func (db *Database) MediaFileInUse(file string) (bool, error) {
+	var n int
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM species_media WHERE file = ?`, file).Scan(&n); err != nil {
+		return false, fmt.Errorf("failed to check media file: %w", err)
+	}
+	return n > 0, nil
+}
+
+func scanMedia(row rowScanner) (*Media, error) {
+	var m Media
+	var createdAt string
+	if err := row.Scan(&m.ID, &m.ScientificName, &m.URL, &m.ThumbnailURL, &m.Caption, &m.License,
+		&m.Attribution, &m.Origin, &m.OriginID, &m.File, &m.ThumbnailFile, &m.Filename, &m.Width, &m.Height,
+		&m.CapturedAt, &m.Latitude, &m.Longitude, &m.Kind, &m.SourceID, &createdAt); err != nil {
+		return nil, fmt.Errorf("failed to scan species media: %w", err)
+	}
+	var err error
+	if m.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	return &m, nil
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestSpeciesMedia(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if err := db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatal(err)
+	}
+	strPtr := func(s string) *string { return &s }
+	photos := []*Media{
+		{URL: "https://example.org/1/medium.jpg", License: strPtr("cc-by"), OriginID: strPtr("1")},
+		{URL: "https://example.org/2/medium.jpg", OriginID: strPtr("2")},
+	}
+	if err := db.ReplaceOriginMedia("alba", MediaOriginINaturalist, photos); err != nil {
+		t.Fatalf("ReplaceOriginMedia failed: %v", err)
+	}
+	if err := db.ReplaceOriginMedia("alba", MediaOriginINaturalist, photos[1:]); err != nil {
+		t.Fatalf("ReplaceOriginMedia again failed: %v", err)
+	}
+
+	media, err := db.ListSpeciesMedia("alba", MediaOriginINaturalist)
+	if err != nil {
+		t.Fatalf("ListSpeciesMedia failed: %v", err)
+	}
+	if len(media) != 1 || media[0].URL != "https://example.org/2/medium.jpg" || media[0].Origin != MediaOriginINaturalist {
+		t.Errorf("media = %+v, want only the second photo", media)
+	}
+
+	// Uploads go after the cached photos, once each
+	upload := &Media{ScientificName: "alba", URL: "/api/v1/media/abc.jpg", Origin: MediaOriginUpload, OriginID: strPtr("abc"),
+		CapturedAt: strPtr("2024-05-03T14:22:10")}
+	if err := db.AddSpeciesMedia(upload); err != nil {
+		t.Fatalf("AddSpeciesMedia failed: %v", err)
+	}
+	if err := db.AddSpeciesMedia(&Media{ScientificName: "alba", URL: "/api/v1/media/abc.jpg", Origin: MediaOriginUpload, OriginID: strPtr("abc")}); err != ErrMediaExists {
+		t.Errorf("AddSpeciesMedia duplicate error = %v, want ErrMediaExists", err)
+	}
+	media, err = db.ListSpeciesMedia("alba", "")
+	if err != nil {
+		t.Fatalf("ListSpeciesMedia failed: %v", err)
+	}
+	if len(media) != 2 || media[1].ID != upload.ID || media[1].CapturedAt == nil || *media[1].CapturedAt != "2024-05-03T14:22:10" {
+		t.Errorf("media = %+v, want the upload last with its capture time", media)
+	}
+
+	// Media cites its source until the source is deleted
+	sourceID, err := db.InsertSource(models.NewSource(models.SourceTypeWebsite, "Field photos"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bark := &Media{ScientificName: "alba", URL: "https://example.org/bark.jpg", Origin: MediaOriginLink,
+		OriginID: strPtr("https://example.org/bark.jpg"), Kind: strPtr("bark"), SourceID: &sourceID, File: strPtr("f.jpg")}
+	if err := db.AddSpeciesMedia(bark); err != nil {
+		t.Fatalf("AddSpeciesMedia failed: %v", err)
+	}
+	if err := db.DeleteSource(sourceID); err != nil {
+		t.Fatal(err)
+	}
+	got, err := db.GetSpeciesMedia(bark.ID)
+	if err != nil || got == nil || *got.Kind != "bark" || got.SourceID != nil {
+		t.Errorf("GetSpeciesMedia = %+v, %v; want bark with no source", got, err)
+	}
+	if inUse, err := db.MediaFileInUse("f.jpg"); err != nil || !inUse {
+		t.Errorf("MediaFileInUse = %v, %v; want true", inUse, err)
+	}
+	if found, err := db.DeleteSpeciesMedia(bark.ID); err != nil || !found {
+		t.Errorf("DeleteSpeciesMedia = %v, %v; want found", found, err)
+	}
+	if got, err := db.GetSpeciesMedia(bark.ID); err != nil || got != nil {
+		t.Errorf("GetSpeciesMedia after delete = %+v, %v; want nil", got, err)
+	}
+	if inUse, err := db.MediaFileInUse("f.jpg"); err != nil || inUse {
+		t.Errorf("MediaFileInUse after delete = %v, %v; want false", inUse, err)
+	}
+
+	// Deleting the species deletes its media
+	if err := db.DeleteOakEntry("alba"); err != nil {
+		t.Fatal(err)
+	}
+	if media, err := db.ListSpeciesMedia("alba", ""); err != nil || len(media) != 0 {
+		t.Errorf("media after delete = %v, %v; want none", media, err)
+	}
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/api/internal/names"
+)
+
+// sqliteDriver is the SQLite driver with the name_key(text) function, which
+// compares names as names.Key does: ignoring case, diacritics, and spacing,
+// and name_slug(text), a name's URL slug (names.Slug). Only queries call
+// them; no index, trigger, or view does, so the database file still opens
+// in tools that lack them (the sqlite3 shell, the CLI).
+var sqliteDriver = &sqlite3.SQLiteDriver{
+	ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+		if err := conn.RegisterFunc("name_key", nameKey, true); err != nil {
+			return err
+		}
+		return conn.RegisterFunc("name_slug", nameSlug, true)
+	},
+}
+
+// nameKey is name_key in SQL. NULL and non-text values give NULL.
+func nameKey(v interface{}) interface{} {
+	switch s := v.(type) {
+	case string:
+		return names.Key(s)
+	case []byte:
+		return names.Key(string(s))
+	}
+	return nil
+}
+
+// nameSlug is name_slug in SQL. NULL and non-text values give NULL.
+func nameSlug(v interface{}) interface{} {
+	switch s := v.(type) {
+	case string:
+		return names.Slug(s)
+	case []byte:
+		return names.Slug(string(s))
+	}
+	return nil
+}
+
+// likeKey returns a LIKE pattern matching values whose name_key contains
+// query's key.
+func likeKey(query string) string {
+	return "%" + escapeLike(names.Key(query)) + "%"
+}
+
+// OakEntryNamesLike returns the stored species names that differ from name
+// only in case, diacritics, or spacing, name itself included if stored.
+func (db *Database) OakEntryNamesLike(name string) ([]string, error) {
+	rows, err := db.conn.Query(
+		`SELECT scientific_name FROM oak_entries WHERE name_key(scientific_name) = ? ORDER BY scientific_name`,
+		names.Key(name),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up species name: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []string
+	for rows.Next() {
+		var stored string
+		if err := rows.Scan(&stored); err != nil {
+			return nil, fmt.Errorf("failed to scan species name: %w", err)
+		}
+		matches = append(matches, stored)
+	}
+	return matches, rows.Err()
+}
+
+// TaxonNamesLike returns the stored names of taxa at level that differ from
+// name only in case, diacritics, or spacing, name itself included if stored.
+func (db *Database) TaxonNamesLike(name string, level models.TaxonLevel) ([]string, error) {
+	rows, err := db.conn.Query(
+		`SELECT name FROM taxa WHERE level = ? AND name_key(name) = ? ORDER BY name`,
+		string(level), names.Key(name),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up taxon name: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []string
+	for rows.Next() {
+		var stored string
+		if err := rows.Scan(&stored); err != nil {
+			return nil, fmt.Errorf("failed to scan taxon name: %w", err)
+		}
+		matches = append(matches, stored)
+	}
+	return matches, rows.Err()
+}
+
+// ResolveOakEntryName returns the stored name of the species name refers to:
+// name itself if a species has exactly that name, otherwise the one species
+// whose name differs only in case, diacritics, or spacing. It returns "" if
+// there is none, or more than one (duplicates stored before they were
+// refused; see FindNonconformingNames).
+func (db *Database) ResolveOakEntryName(name string) (string, error) {
+	exists, err := db.OakEntryExists(name)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return name, nil
+	}
+
+	matches, err := db.OakEntryNamesLike(name)
+	if err != nil || len(matches) != 1 {
+		return "", err
+	}
+	return matches[0], nil
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestResolveOakEntryName(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	// Written directly, as data from before names were validated would be
+	for _, name := range []string{"alba", "rubra", "Rubrà"} {
+		if err := db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatalf("SaveOakEntry(%q) failed: %v", name, err)
+		}
+	}
+
+	tests := map[string]string{
+		"alba":      "alba",
+		"Alba":      "alba",
+		" ALBÀ ":    "alba",
+		"Rubrà":     "Rubrà", // Exact match wins over the ambiguous key
+		"RUBRA":     "",      // rubra and Rubrà both match
+		"palustris": "",
+	}
+	for in, want := range tests {
+		got, err := db.ResolveOakEntryName(in)
+		if err != nil {
+			t.Fatalf("ResolveOakEntryName(%q) failed: %v", in, err)
+		}
+		if got != want {
+			t.Errorf("ResolveOakEntryName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSearchIgnoresCaseAndDiacritics(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if err := db.SaveOakEntry(models.NewOakEntry("garryana")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	if err := db.InsertTaxon(&models.Taxon{Name: "Quercus", Level: models.TaxonLevelSubgenus, Genus: "Quercus"}); err != nil {
+		t.Fatalf("InsertTaxon failed: %v", err)
+	}
+
+	results, err := db.SearchOakEntries("GARRYÀ")
+	if err != nil {
+		t.Fatalf("SearchOakEntries failed: %v", err)
+	}
+	if !slices.Equal(results, []string{"garryana"}) {
+		t.Errorf("SearchOakEntries = %v, want [garryana]", results)
+	}
+
+	taxa, err := db.SearchTaxa("quer")
+	if err != nil {
+		t.Fatalf("SearchTaxa failed: %v", err)
+	}
+	if len(taxa) != 1 || taxa[0].Name != "Quercus" {
+		t.Errorf("SearchTaxa = %v, want [Quercus]", taxa)
+	}
+
+	similar, err := db.TaxonNamesLike("QUERCUS", models.TaxonLevelSubgenus)
+	if err != nil {
+		t.Fatalf("TaxonNamesLike failed: %v", err)
+	}
+	if !slices.Equal(similar, []string{"Quercus"}) {
+		t.Errorf("TaxonNamesLike = %v, want [Quercus]", similar)
+	}
+	similar, err = db.TaxonNamesLike("Quercus", models.TaxonLevelSection)
+	if err != nil {
+		t.Fatalf("TaxonNamesLike failed: %v", err)
+	}
+	if len(similar) != 0 {
+		t.Errorf("TaxonNamesLike at another level = %v, want none", similar)
+	}
+}
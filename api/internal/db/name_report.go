@@ -0,0 +1,56 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/jeff/oaks/api/internal/names"
+)
+
+// NameProblem is a stored species name that is not in canonical form (see
+// package names), typically written before names were validated.
+type NameProblem struct {
+	ScientificName string `json:"scientific_name"`
+	Problem        string `json:"problem"`
+	Suggestion     string `json:"suggestion,omitempty"` // Canonical form, when canonicalizing fixes the name
+	Conflicts      bool   `json:"conflicts,omitempty"`  // Suggestion is already another species' name
+}
+
+// FindNonconformingNames lists species, drafts included, whose names are not
+// in canonical form. Nothing is changed; renaming is left to a curator.
+func (db *Database) FindNonconformingNames() ([]NameProblem, error) {
+	rows, err := db.conn.Query(`SELECT scientific_name FROM oak_entries ORDER BY scientific_name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list species names: %w", err)
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	var all []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan species name: %w", err)
+		}
+		existing[name] = true
+		all = append(all, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	found := []NameProblem{}
+	for _, name := range all {
+		problem := names.Problem(name)
+		if problem == "" {
+			continue
+		}
+		suggestion := names.Suggest(name)
+		found = append(found, NameProblem{
+			ScientificName: name,
+			Problem:        problem,
+			Suggestion:     suggestion,
+			Conflicts:      suggestion != "" && existing[suggestion],
+		})
+	}
+	return found, nil
+}
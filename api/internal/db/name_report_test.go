@@ -0,0 +1,43 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestFindNonconformingNames(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	// Written directly, as data from before names were validated would be
+	for _, name := range []string{"alba", " rubra", "Alba", "x bebbiana", "DROP TABLE"} {
+		if err := db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatalf("SaveOakEntry(%q) failed: %v", name, err)
+		}
+	}
+
+	found, err := db.FindNonconformingNames()
+	if err != nil {
+		t.Fatalf("FindNonconformingNames failed: %v", err)
+	}
+	want := map[string]NameProblem{
+		" rubra":     {Suggestion: "rubra"},
+		"Alba":       {Suggestion: "alba", Conflicts: true},
+		"x bebbiana": {Suggestion: "× bebbiana"},
+		"DROP TABLE": {},
+	}
+	if len(found) != len(want) {
+		t.Fatalf("FindNonconformingNames = %+v, want %d names", found, len(want))
+	}
+	for _, p := range found {
+		w, ok := want[p.ScientificName]
+		if !ok {
+			t.Errorf("unexpected name %q", p.ScientificName)
+			continue
+		}
+		if p.Problem == "" || p.Suggestion != w.Suggestion || p.Conflicts != w.Conflicts {
+			t.Errorf("%q = %+v, want suggestion %q, conflicts %v", p.ScientificName, p, w.Suggestion, w.Conflicts)
+		}
+	}
+}
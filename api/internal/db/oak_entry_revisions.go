@@ -0,0 +1,158 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// OakEntryRevision is an earlier version of an oak entry, kept by
+// SaveOakEntry when a change replaced it.
+type OakEntryRevision struct {
+	ID             int64            `json:"id"`
+	ScientificName string           `json:"scientific_name"`
+	Entry          *models.OakEntry `json:"entry"`
+	CreatedAt      time.Time        `json:"created_at"` // When the change replaced it
+}
+
+// ListOakEntryRevisions returns a species' earlier versions, newest first.
+// Revisions outlive their entry, so a deleted species still has them.
+func (db *Database) ListOakEntryRevisions(scientificName string) ([]*OakEntryRevision, error) {
+	rows, err := db.conn.Query(
+		`SELECT id, scientific_name, entry, created_at FROM oak_entry_revisions
+		 WHERE scientific_name = ? ORDER BY id DESC`,
+		scientificName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oak entry revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []*OakEntryRevision{}
+	for rows.Next() {
+		rev, err := scanOakEntryRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+// GetOakEntryRevision returns one of a species' earlier versions, or nil if
+// it has no revision with this ID
+func (db *Database) GetOakEntryRevision(scientificName string, id int64) (*OakEntryRevision, error) {
+	rev, err := scanOakEntryRevision(db.conn.QueryRow(
+		`SELECT id, scientific_name, entry, created_at FROM oak_entry_revisions
+		 WHERE scientific_name = ? AND id = ?`,
+		scientificName, id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return rev, err
+}
+
+// RestoreOakEntryRevision saves an earlier version of a species over the
+// current one, which is itself kept as a revision so the restore can be
+// undone too. The current hybrids list and visibility are kept: the one is
+// derived from other entries' parents and the other is set on its own.
+// Returns nil if the species has no revision with this ID.
+func (db *Database) RestoreOakEntryRevision(scientificName string, id int64) (*models.OakEntry, error) {
+	rev, err := db.GetOakEntryRevision(scientificName, id)
+	if err != nil || rev == nil {
+		return nil, err
+	}
+	current, err := db.GetOakEntry(scientificName)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := rev.Entry
+	if current != nil {
+		entry.Hybrids = current.Hybrids
+		entry.Visibility = current.Visibility
+	}
+	if err := db.SaveOakEntry(entry); err != nil {
+		return nil, err
+	}
+	return db.GetOakEntry(scientificName)
+}
+
+// saveOakEntryRevisionTx keeps existing as a revision if saving entry over it
+// changes anything
+func saveOakEntryRevisionTx(tx *sql.Tx, existing, entry *models.OakEntry) error {
+	if existing == nil {
+		return nil
+	}
+	// Empty visibility and genus keep the stored ones (see saveOakEntryTx)
+	next := *entry
+	if next.Visibility == "" {
+		next.Visibility = existing.Visibility
+	}
+	if next.Genus == "" {
+		next.Genus = existing.Genus
+	}
+	before, err := revisionJSON(existing)
+	if err != nil {
+		return err
+	}
+	after, err := revisionJSON(&next)
+	if err != nil {
+		return err
+	}
+	if before == after {
+		return nil
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO oak_entry_revisions (scientific_name, entry, created_at) VALUES (?, ?, ?)`,
+		existing.ScientificName, before, time.Now().UTC().Format(timestampFormat),
+	); err != nil {
+		return fmt.Errorf("failed to save oak entry revision: %w", err)
+	}
+	return nil
+}
+
+// revisionJSON is an entry as a revision stores it: the fields that are
+// written, with empty lists in place of nil ones so equal entries compare
+// equal
+func revisionJSON(entry *models.OakEntry) (string, error) {
+	e := *entry
+	e.Slug, e.DataError = "", ""
+	for _, list := range []*[]string{&e.Hybrids, &e.CloselyRelatedTo, &e.SubspeciesVarieties, &e.Synonyms} {
+		if *list == nil {
+			*list = []string{}
+		}
+	}
+	if e.ExternalLinks == nil {
+		e.ExternalLinks = []models.ExternalLink{}
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal oak entry revision: %w", err)
+	}
+	return string(data), nil
+}
+
+func scanOakEntryRevision(row rowScanner) (*OakEntryRevision, error) {
+	var rev OakEntryRevision
+	var entry, createdAt string
+	if err := row.Scan(&rev.ID, &rev.ScientificName, &entry, &createdAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan oak entry revision: %w", err)
+	}
+	if err := json.Unmarshal([]byte(entry), &rev.Entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal oak entry revision %d: %w", rev.ID, err)
+	}
+	var err error
+	if rev.CreatedAt, err = time.Parse(timestampFormat, createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at for oak entry revision %d: %w", rev.ID, err)
+	}
+	return &rev, nil
+}
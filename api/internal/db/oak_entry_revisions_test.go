@@ -0,0 +1,76 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestOakEntryRevisions(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	entry := models.NewOakEntry("alba")
+	author := "L."
+	entry.Author = &author
+	if err := db.SaveOakEntry(entry); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	// Saving it unchanged, as the API does with nil lists, keeps no revision
+	unchanged := *entry
+	unchanged.Synonyms = nil
+	if err := db.SaveOakEntry(&unchanged); err != nil {
+		t.Fatal(err)
+	}
+	if revs, err := db.ListOakEntryRevisions("alba"); err != nil || len(revs) != 0 {
+		t.Fatalf("ListOakEntryRevisions() after unchanged save = %d revisions, %v; want none", len(revs), err)
+	}
+
+	// An accidental edit, and a hybrid added since
+	edited := *entry
+	wrong := "Michx."
+	edited.Author = &wrong
+	edited.Synonyms = []string{"Quercus alba var. repanda"}
+	if err := db.SaveOakEntry(&edited); err != nil {
+		t.Fatal(err)
+	}
+	parent1, parent2 := "alba", "robur"
+	hybrid := models.NewOakEntry("× bebbiana")
+	hybrid.IsHybrid = true
+	hybrid.Parent1, hybrid.Parent2 = &parent1, &parent2
+	if err := db.SaveOakEntry(hybrid); err != nil {
+		t.Fatal(err)
+	}
+
+	revs, err := db.ListOakEntryRevisions("alba")
+	if err != nil {
+		t.Fatalf("ListOakEntryRevisions failed: %v", err)
+	}
+	if len(revs) != 1 || *revs[0].Entry.Author != "L." || len(revs[0].Entry.Synonyms) != 0 {
+		t.Fatalf("ListOakEntryRevisions() = %+v, want the entry before the edit", revs)
+	}
+
+	restored, err := db.RestoreOakEntryRevision("alba", revs[0].ID)
+	if err != nil {
+		t.Fatalf("RestoreOakEntryRevision failed: %v", err)
+	}
+	if restored == nil || *restored.Author != "L." || len(restored.Synonyms) != 0 {
+		t.Errorf("restored entry = %+v, want author L. and no synonyms", restored)
+	}
+	if !stringSlicesEqual(restored.Hybrids, []string{"× bebbiana"}) {
+		t.Errorf("restored hybrids = %v, want the current ones", restored.Hybrids)
+	}
+
+	// The restore is itself undoable
+	revs, _ = db.ListOakEntryRevisions("alba")
+	if len(revs) != 2 || *revs[0].Entry.Author != "Michx." {
+		t.Errorf("revisions after restore = %d, newest by %v; want the edit kept", len(revs), revs[0].Entry.Author)
+	}
+
+	if rev, err := db.GetOakEntryRevision("robur", revs[0].ID); err != nil || rev != nil {
+		t.Errorf("GetOakEntryRevision(robur, %d) = %v, %v; want nil for another species' revision", revs[0].ID, rev, err)
+	}
+	if entry, err := db.RestoreOakEntryRevision("alba", 999); err != nil || entry != nil {
+		t.Errorf("RestoreOakEntryRevision(alba, 999) = %v, %v; want nil", entry, err)
+	}
+}
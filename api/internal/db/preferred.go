@@ -0,0 +1,101 @@
+package db
+
+import (
+	"fmt"
+)
+
+// SetPreferredSource makes sourceID the single preferred source for a species,
+// clearing the flag on all others. Returns false if the species has no data
+// from that source.
+func (db *Database) SetPreferredSource(scientificName string, sourceID int64) (bool, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	err = tx.QueryRow(
+		`SELECT COUNT(*) FROM species_sources WHERE scientific_name = ? AND source_id = ?`,
+		scientificName, sourceID,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check species source: %w", err)
+	}
+	if exists == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(
+		`UPDATE species_sources SET is_preferred = 0 WHERE scientific_name = ? AND is_preferred = 1`,
+		scientificName,
+	); err != nil {
+		return false, fmt.Errorf("failed to clear preferred source: %w", err)
+	}
+	if _, err := tx.Exec(
+		`UPDATE species_sources SET is_preferred = 1 WHERE scientific_name = ? AND source_id = ?`,
+		scientificName, sourceID,
+	); err != nil {
+		return false, fmt.Errorf("failed to set preferred source: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit preferred source: %w", err)
+	}
+	return true, nil
+}
+
+// RepairPreferredSources fixes species with more than one preferred source,
+// keeping the earliest-added preferred record. Returns the repaired species names.
+func (db *Database) RepairPreferredSources() ([]string, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(
+		`SELECT scientific_name, MIN(id) FROM species_sources
+		 WHERE is_preferred = 1
+		 GROUP BY scientific_name HAVING COUNT(*) > 1
+		 ORDER BY scientific_name`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find preferred source violations: %w", err)
+	}
+
+	type keep struct {
+		name string
+		id   int64
+	}
+	var violations []keep
+	for rows.Next() {
+		var k keep
+		if err := rows.Scan(&k.name, &k.id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan preferred source violation: %w", err)
+		}
+		violations = append(violations, k)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	repaired := make([]string, 0, len(violations))
+	for _, v := range violations {
+		if _, err := tx.Exec(
+			`UPDATE species_sources SET is_preferred = 0
+			 WHERE scientific_name = ? AND is_preferred = 1 AND id != ?`,
+			v.name, v.id,
+		); err != nil {
+			return nil, fmt.Errorf("failed to repair preferred sources for %s: %w", v.name, err)
+		}
+		repaired = append(repaired, v.name)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit preferred source repair: %w", err)
+	}
+	return repaired, nil
+}
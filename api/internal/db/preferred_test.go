@@ -0,0 +1,137 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// preferredFixture creates "alba" with data from two sources, the first preferred
+func preferredFixture(t *testing.T, db *Database) (first, second int64) {
+	t.Helper()
+
+	if err := db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	var ids [2]int64
+	for i := range ids {
+		id, err := db.InsertSource(&models.Source{SourceType: "website", Name: "Source"})
+		if err != nil {
+			t.Fatalf("InsertSource failed: %v", err)
+		}
+		ids[i] = id
+		ss := models.NewSpeciesSource("alba", id)
+		ss.IsPreferred = i == 0
+		if err := db.SaveSpeciesSource(ss); err != nil {
+			t.Fatalf("SaveSpeciesSource failed: %v", err)
+		}
+	}
+	return ids[0], ids[1]
+}
+
+func preferredSourceIDs(t *testing.T, db *Database, name string) []int64 {
+	t.Helper()
+
+	sources, err := db.GetSpeciesSources(name)
+	if err != nil {
+		t.Fatalf("GetSpeciesSources failed: %v", err)
+	}
+	var ids []int64
+	for _, ss := range sources {
+		if ss.IsPreferred {
+			ids = append(ids, ss.SourceID)
+		}
+	}
+	return ids
+}
+
+func TestSaveSpeciesSourceKeepsSinglePreferred(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	_, second := preferredFixture(t, db)
+
+	ss := models.NewSpeciesSource("alba", second)
+	ss.IsPreferred = true
+	if err := db.SaveSpeciesSource(ss); err != nil {
+		t.Fatalf("SaveSpeciesSource failed: %v", err)
+	}
+
+	if ids := preferredSourceIDs(t, db, "alba"); len(ids) != 1 || ids[0] != second {
+		t.Errorf("preferred sources = %v, want [%d]", ids, second)
+	}
+}
+
+func TestSetPreferredSource(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	_, second := preferredFixture(t, db)
+
+	found, err := db.SetPreferredSource("alba", second)
+	if err != nil {
+		t.Fatalf("SetPreferredSource failed: %v", err)
+	}
+	if !found {
+		t.Fatal("SetPreferredSource found = false, want true")
+	}
+	if ids := preferredSourceIDs(t, db, "alba"); len(ids) != 1 || ids[0] != second {
+		t.Errorf("preferred sources = %v, want [%d]", ids, second)
+	}
+
+	found, err = db.SetPreferredSource("alba", 999)
+	if err != nil {
+		t.Fatalf("SetPreferredSource failed: %v", err)
+	}
+	if found {
+		t.Error("SetPreferredSource found = true for unknown source, want false")
+	}
+}
+
+func TestPreferredTriggerRejectsSecondPreferred(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	_, second := preferredFixture(t, db)
+
+	_, err := db.conn.Exec(`UPDATE species_sources SET is_preferred = 1 WHERE source_id = ?`, second)
+	if err == nil {
+		t.Error("expected trigger to reject a second preferred source")
+	}
+}
+
+func TestRepairPreferredSources(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	first, second := preferredFixture(t, db)
+
+	// Simulate data written before the invariant was enforced
+	for _, stmt := range []string{
+		`DROP TRIGGER trg_species_sources_one_preferred_update`,
+		`UPDATE species_sources SET is_preferred = 1`,
+	} {
+		if _, err := db.conn.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repaired, err := db.RepairPreferredSources()
+	if err != nil {
+		t.Fatalf("RepairPreferredSources failed: %v", err)
+	}
+	if len(repaired) != 1 || repaired[0] != "alba" {
+		t.Errorf("repaired = %v, want [alba]", repaired)
+	}
+	if ids := preferredSourceIDs(t, db, "alba"); len(ids) != 1 || ids[0] != first {
+		t.Errorf("preferred sources = %v, want [%d] (second was %d)", ids, first, second)
+	}
+
+	repaired, err = db.RepairPreferredSources()
+	if err != nil {
+		t.Fatalf("RepairPreferredSources failed: %v", err)
+	}
+	if len(repaired) != 0 {
+		t.Errorf("second repair = %v, want none", repaired)
+	}
+}
@@ -0,0 +1,203 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// Scheduled publication states
+const (
+	PublicationPending   = "pending"
+	PublicationPublished = "published"
+	PublicationCanceled  = "canceled"
+)
+
+// timestampFormat stores times as fixed-width UTC strings so they compare lexically
+const timestampFormat = "2006-01-02T15:04:05Z"
+
+// ScheduledPublication is a set of draft species to publish together at a set time
+type ScheduledPublication struct {
+	ID          int64      `json:"id"`
+	Species     []string   `json:"species"`
+	PublishAt   time.Time  `json:"publish_at"`
+	Note        *string    `json:"note,omitempty"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+// CreateScheduledPublication queues species to be published at publishAt and returns the new entry
+func (db *Database) CreateScheduledPublication(species []string, publishAt time.Time, note *string) (*ScheduledPublication, error) {
+	speciesJSON, err := json.Marshal(species)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal species: %w", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	publishAt = publishAt.UTC().Truncate(time.Second)
+	result, err := db.conn.Exec(
+		`INSERT INTO publish_queue (species, publish_at, note, status, created_at) VALUES (?, ?, ?, ?, ?)`,
+		string(speciesJSON), publishAt.Format(timestampFormat), note, PublicationPending, now.Format(timestampFormat),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule publication: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get publication id: %w", err)
+	}
+
+	return &ScheduledPublication{
+		ID:        id,
+		Species:   species,
+		PublishAt: publishAt,
+		Note:      note,
+		Status:    PublicationPending,
+		CreatedAt: now,
+	}, nil
+}
+
+// GetScheduledPublication returns a queued publication, or nil if it does not exist
+func (db *Database) GetScheduledPublication(id int64) (*ScheduledPublication, error) {
+	rows, err := db.conn.Query(
+		`SELECT id, species, publish_at, note, status, created_at, published_at
+		 FROM publish_queue WHERE id = ?`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled publication: %w", err)
+	}
+	defer rows.Close()
+
+	pubs, err := scanScheduledPublications(rows)
+	if err != nil || len(pubs) == 0 {
+		return nil, err
+	}
+	return pubs[0], nil
+}
+
+// ListScheduledPublications returns queued publications, soonest first.
+// With pendingOnly, published and canceled entries are left out.
+func (db *Database) ListScheduledPublications(pendingOnly bool) ([]*ScheduledPublication, error) {
+	query := `SELECT id, species, publish_at, note, status, created_at, published_at FROM publish_queue`
+	var args []interface{}
+	if pendingOnly {
+		query += ` WHERE status = ?`
+		args = append(args, PublicationPending)
+	}
+	query += ` ORDER BY publish_at, id`
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled publications: %w", err)
+	}
+	defer rows.Close()
+
+	return scanScheduledPublications(rows)
+}
+
+// CancelScheduledPublication cancels a pending publication.
+// Returns false if no pending publication has that id.
+func (db *Database) CancelScheduledPublication(id int64) (bool, error) {
+	result, err := db.conn.Exec(
+		`UPDATE publish_queue SET status = ? WHERE id = ? AND status = ?`,
+		PublicationCanceled, id, PublicationPending,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel scheduled publication: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// PublishDue publishes every pending publication due at or before now, each in its
+// own transaction, and returns the ones it published. Species deleted since they
+// were scheduled are skipped.
+func (db *Database) PublishDue(now time.Time) ([]*ScheduledPublication, error) {
+	rows, err := db.conn.Query(
+		`SELECT id, species, publish_at, note, status, created_at, published_at
+		 FROM publish_queue WHERE status = ? AND publish_at <= ? ORDER BY publish_at, id`,
+		PublicationPending, now.UTC().Format(timestampFormat),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due publications: %w", err)
+	}
+	due, err := scanScheduledPublications(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var published []*ScheduledPublication
+	for _, pub := range due {
+		if err := db.publishScheduled(pub, now); err != nil {
+			return published, err
+		}
+		published = append(published, pub)
+	}
+	return published, nil
+}
+
+// publishScheduled marks a publication's species published and records it as done
+func (db *Database) publishScheduled(pub *ScheduledPublication, now time.Time) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, name := range pub.Species {
+		if _, err := db.setOakEntryVisibilityTx(tx, name, models.VisibilityPublished); err != nil {
+			return fmt.Errorf("failed to publish %s: %w", name, err)
+		}
+	}
+
+	publishedAt := now.UTC().Truncate(time.Second)
+	if _, err := tx.Exec(
+		`UPDATE publish_queue SET status = ?, published_at = ? WHERE id = ?`,
+		PublicationPublished, publishedAt.Format(timestampFormat), pub.ID,
+	); err != nil {
+		return fmt.Errorf("failed to mark publication %d published: %w", pub.ID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit publication %d: %w", pub.ID, err)
+	}
+	pub.Status = PublicationPublished
+	pub.PublishedAt = &publishedAt
+	return nil
+}
+
+func scanScheduledPublications(rows *sql.Rows) ([]*ScheduledPublication, error) {
+	var pubs []*ScheduledPublication
+	for rows.Next() {
+		var pub ScheduledPublication
+		var speciesJSON, publishAt, createdAt string
+		var publishedAt sql.NullString
+		if err := rows.Scan(&pub.ID, &speciesJSON, &publishAt, &pub.Note, &pub.Status, &createdAt, &publishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled publication: %w", err)
+		}
+		if err := json.Unmarshal([]byte(speciesJSON), &pub.Species); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal species for publication %d: %w", pub.ID, err)
+		}
+		var err error
+		if pub.PublishAt, err = time.Parse(timestampFormat, publishAt); err != nil {
+			return nil, fmt.Errorf("failed to parse publish_at for publication %d: %w", pub.ID, err)
+		}
+		if pub.CreatedAt, err = time.Parse(timestampFormat, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at for publication %d: %w", pub.ID, err)
+		}
+		if pub.PublishedAt, err = parseOptionalTimestamp(publishedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse published_at for publication %d: %w", pub.ID, err)
+		}
+		pubs = append(pubs, &pub)
+	}
+	return pubs, rows.Err()
+}
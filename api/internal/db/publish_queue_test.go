@@ -0,0 +1,77 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestPublishDue(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	for _, name := range []string{"alba", "rubra", "velutina"} {
+		entry := models.NewOakEntry(name)
+		entry.Visibility = models.VisibilityDraft
+		if err := db.SaveOakEntry(entry); err != nil {
+			t.Fatalf("SaveOakEntry(%s) failed: %v", name, err)
+		}
+	}
+
+	launch := time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC)
+	note := "Red oak blog post"
+	pub, err := db.CreateScheduledPublication([]string{"alba", "rubra"}, launch, &note)
+	if err != nil {
+		t.Fatalf("CreateScheduledPublication failed: %v", err)
+	}
+	later, err := db.CreateScheduledPublication([]string{"velutina"}, launch.Add(24*time.Hour), nil)
+	if err != nil {
+		t.Fatalf("CreateScheduledPublication failed: %v", err)
+	}
+
+	// Nothing is due before the scheduled time
+	done, err := db.PublishDue(launch.Add(-time.Minute))
+	if err != nil || len(done) != 0 {
+		t.Fatalf("PublishDue before launch = %d, %v; want none", len(done), err)
+	}
+
+	done, err = db.PublishDue(launch)
+	if err != nil {
+		t.Fatalf("PublishDue failed: %v", err)
+	}
+	if len(done) != 1 || done[0].ID != pub.ID || done[0].PublishedAt == nil {
+		t.Fatalf("PublishDue = %+v, want publication %d", done, pub.ID)
+	}
+	for name, want := range map[string]string{
+		"alba": models.VisibilityPublished, "rubra": models.VisibilityPublished, "velutina": models.VisibilityDraft,
+	} {
+		if got, _ := db.GetOakEntryVisibility(name); got != want {
+			t.Errorf("%s visibility = %q, want %q", name, got, want)
+		}
+	}
+
+	// Published entries are not run again, and canceled ones never run
+	if ok, err := db.CancelScheduledPublication(later.ID); err != nil || !ok {
+		t.Fatalf("CancelScheduledPublication = %v, %v", ok, err)
+	}
+	if ok, _ := db.CancelScheduledPublication(pub.ID); ok {
+		t.Error("canceled an already published publication")
+	}
+	if done, _ := db.PublishDue(launch.Add(48 * time.Hour)); len(done) != 0 {
+		t.Errorf("PublishDue after cancel = %d, want none", len(done))
+	}
+
+	pending, err := db.ListScheduledPublications(true)
+	if err != nil || len(pending) != 0 {
+		t.Errorf("pending = %d, %v; want none", len(pending), err)
+	}
+	all, _ := db.ListScheduledPublications(false)
+	if len(all) != 2 || all[0].Status != PublicationPublished || all[1].Status != PublicationCanceled {
+		t.Errorf("all publications = %+v", all)
+	}
+	got, _ := db.GetScheduledPublication(pub.ID)
+	if got == nil || got.Note == nil || *got.Note != note || len(got.Species) != 2 {
+		t.Errorf("GetScheduledPublication = %+v", got)
+	}
+}
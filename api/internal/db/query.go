@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Bounds on ad-hoc queries run with ReadOnlyQuery
+const (
+	DefaultQueryRowLimit = 1000
+	MaxQueryRowLimit     = 10000
+	DefaultQueryTimeout  = 5 * time.Second
+	MaxQueryTimeout      = 30 * time.Second
+)
+
+// sqliteRecursive is SQLITE_RECURSIVE, the authorizer code for a WITH
+// RECURSIVE clause, which the driver does not export.
+const sqliteRecursive = 33
+
+// QueryResult is the outcome of an ad-hoc read-only query
+type QueryResult struct {
+	Columns    []string        `json:"columns"`
+	Rows       [][]interface{} `json:"rows"`
+	Truncated  bool            `json:"truncated"` // More rows matched than the limit allowed
+	DurationMs float64         `json:"duration_ms"`
+}
+
+// QueryError is a problem with the SQL given to ReadOnlyQuery rather than
+// with the database: a syntax error, a refused write, or a timeout.
+type QueryError struct {
+	Err error
+}
+
+func (e *QueryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// readOnlyAuthorizer lets a statement read tables and call functions and
+// refuses everything else, so SQLite rejects writes, schema changes, ATTACH,
+// PRAGMA, and transaction control when the statement is prepared.
+func readOnlyAuthorizer(op int, _, _, _ string) int {
+	switch op {
+	case sqlite3.SQLITE_SELECT, sqlite3.SQLITE_READ, sqlite3.SQLITE_FUNCTION, sqliteRecursive:
+		return sqlite3.SQLITE_OK
+	}
+	return sqlite3.SQLITE_DENY
+}
+
+// ReadOnlyQuery runs ad-hoc SQL on a connection that may only read,
+// returning at most limit rows and giving up after timeout. Every statement
+// in query is checked before any runs. Problems with the SQL itself come
+// back as *QueryError.
+func (db *Database) ReadOnlyQuery(ctx context.Context, query string, limit int, timeout time.Duration) (*QueryResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := db.conn.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	type authorizer interface {
+		RegisterAuthorizer(func(int, string, string, string) int)
+	}
+	setAuthorizer := func(f func(int, string, string, string) int) error {
+		return conn.Raw(func(driverConn interface{}) error {
+			c, ok := driverConn.(authorizer)
+			if !ok {
+				return errors.New("driver does not support authorizers")
+			}
+			c.RegisterAuthorizer(f)
+			return nil
+		})
+	}
+	if err := setAuthorizer(readOnlyAuthorizer); err != nil {
+		return nil, err
+	}
+	// The connection goes back to the pool, so lift the restriction on the way out
+	defer setAuthorizer(nil) //nolint:errcheck // set above, so the driver supports it
+
+	start := time.Now()
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	result := &QueryResult{Columns: columns, Rows: [][]interface{}{}}
+	for rows.Next() {
+		if len(result.Rows) == limit {
+			result.Truncated = true
+			break
+		}
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, queryError(ctx, err)
+		}
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, queryError(ctx, err)
+	}
+	result.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+	return result, nil
+}
+
+// queryError wraps an error from running ad-hoc SQL, naming timeouts and
+// refused statements as such.
+func queryError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &QueryError{Err: errors.New("query timed out")}
+	}
+	var serr sqlite3.Error
+	if errors.As(err, &serr) && serr.Code == sqlite3.ErrAuth {
+		return &QueryError{Err: errors.New("only reads are allowed (SELECT and WITH)")}
+	}
+	return &QueryError{Err: err}
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestReadOnlyQuery(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	for _, name := range []string{"alba", "rubra", "velutina"} {
+		if err := db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatalf("SaveOakEntry(%q) failed: %v", name, err)
+		}
+	}
+	ctx := context.Background()
+
+	result, err := db.ReadOnlyQuery(ctx,
+		`WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 2)
+		 SELECT scientific_name, length(scientific_name) AS len FROM oak_entries ORDER BY scientific_name`,
+		2, time.Second)
+	if err != nil {
+		t.Fatalf("ReadOnlyQuery failed: %v", err)
+	}
+	if len(result.Columns) != 2 || result.Columns[1] != "len" {
+		t.Errorf("columns = %v", result.Columns)
+	}
+	if len(result.Rows) != 2 || !result.Truncated || result.Rows[0][0] != "alba" || result.Rows[0][1] != int64(4) {
+		t.Errorf("rows = %v (truncated %v), want the first two", result.Rows, result.Truncated)
+	}
+
+	// Writes are refused when prepared, even after a harmless statement
+	for _, query := range []string{
+		`DELETE FROM oak_entries`,
+		`SELECT 1; DELETE FROM oak_entries`,
+		`UPDATE oak_entries SET author = 'x'`,
+		`CREATE TABLE scratch (x)`,
+		`PRAGMA query_only = OFF`,
+		`ATTACH DATABASE ':memory:' AS other`,
+	} {
+		_, err := db.ReadOnlyQuery(ctx, query, 10, time.Second)
+		var qerr *QueryError
+		if !errors.As(err, &qerr) {
+			t.Errorf("ReadOnlyQuery(%q) error = %v, want a QueryError", query, err)
+		}
+	}
+	if entries, err := db.ListOakEntries(); err != nil || len(entries) != 3 {
+		t.Fatalf("after refused writes: %d entries, err %v; want all 3", len(entries), err)
+	}
+
+	// The connection is usable for writes again afterwards
+	if err := db.SaveOakEntry(models.NewOakEntry("palustris")); err != nil {
+		t.Errorf("SaveOakEntry after a read-only query failed: %v", err)
+	}
+
+	_, err = db.ReadOnlyQuery(ctx,
+		`WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n) SELECT count(*) FROM n`,
+		10, 50*time.Millisecond)
+	var qerr *QueryError
+	if !errors.As(err, &qerr) || qerr.Error() != "query timed out" {
+		t.Errorf("endless query error = %v, want a timeout", err)
+	}
+}
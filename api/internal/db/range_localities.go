@@ -0,0 +1,300 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/geocode"
+)
+
+// RangeLocality is a place named in a species source's range, with the
+// approximate coordinates a geocoder found for it. Machine-derived ones await
+// a curator's review (the Suggestion* states); a curator who corrects the
+// coordinates when accepting makes them no longer machine-derived.
+type RangeLocality struct {
+	ID             int64      `json:"id"`
+	ScientificName string     `json:"scientific_name"`
+	SourceID       int64      `json:"source_id"`
+	Locality       string     `json:"locality"` // As written in the range text
+	Latitude       float64    `json:"latitude"`
+	Longitude      float64    `json:"longitude"`
+	Region         *string    `json:"region,omitempty"`
+	Provider       string     `json:"provider"` // Geocoder that found it, e.g. "nominatim"
+	MachineDerived bool       `json:"machine_derived"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+
+	// Generalized is the grid cell size in degrees the coordinates were
+	// coarsened to for the public; set by the API, never stored
+	Generalized float64 `json:"generalized,omitempty"`
+}
+
+// GeocodeReport counts what GeocodeRanges did
+type GeocodeReport struct {
+	Localities int `json:"localities"` // Localities found in range text
+	Added      int `json:"added"`      // New range localities queued for review
+	Lookups    int `json:"lookups"`    // Provider requests; the rest came from the cache
+	Unresolved int `json:"unresolved"` // Localities the provider did not know
+	Removed    int `json:"removed"`    // Pending localities no longer in their range text
+}
+
+const rangeLocalityColumns = `id, scientific_name, source_id, locality, latitude, longitude, region, provider,
+	machine_derived, status, created_at, reviewed_at`
+
+// GeocodeRanges geocodes the localities in the range text of the given
+// species' sources, or every species' when none are given, and queues those
+// found for review. Localities already queued (including rejected ones) are
+// skipped, and pending ones no longer in their range text are removed.
+// Lookups are cached per provider, misses included, so a rerun after a
+// provider error resumes where it stopped. logf, if set, reports progress.
+func (db *Database) GeocodeRanges(ctx context.Context, provider geocode.Provider, scientificNames []string, logf func(format string, args ...interface{})) (*GeocodeReport, error) {
+	if logf == nil {
+		logf = func(string, ...interface{}) {}
+	}
+	if len(scientificNames) == 0 {
+		rows, err := db.conn.Query(`SELECT DISTINCT scientific_name FROM species_sources
+			WHERE range IS NOT NULL AND range != '' ORDER BY scientific_name`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list species: %w", err)
+		}
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			scientificNames = append(scientificNames, name)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &GeocodeReport{}
+	for _, name := range scientificNames {
+		sources, err := db.GetSpeciesSources(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ss := range sources {
+			var localities []string
+			if ss.Range != nil {
+				localities = geocode.Localities(*ss.Range)
+			}
+			removed, err := db.removeStaleLocalities(name, ss.SourceID, localities)
+			if err != nil {
+				return nil, err
+			}
+			report.Removed += removed
+			report.Localities += len(localities)
+
+			for _, locality := range localities {
+				var exists bool
+				if err := db.conn.QueryRow(
+					`SELECT EXISTS (SELECT 1 FROM range_localities WHERE scientific_name = ? AND source_id = ? AND locality = ?)`,
+					name, ss.SourceID, locality,
+				).Scan(&exists); err != nil {
+					return nil, fmt.Errorf("failed to check range locality: %w", err)
+				}
+				if exists {
+					continue
+				}
+
+				place, looked, err := db.cachedGeocode(ctx, provider, locality)
+				if err != nil {
+					return nil, err
+				}
+				if looked {
+					report.Lookups++
+				}
+				if place == nil {
+					report.Unresolved++
+					logf("%s (source %d): %q not found", name, ss.SourceID, locality)
+					continue
+				}
+
+				var region *string
+				if place.Region != "" {
+					region = &place.Region
+				}
+				if _, err := db.conn.Exec(
+					`INSERT OR IGNORE INTO range_localities
+						(scientific_name, source_id, locality, latitude, longitude, region, provider, machine_derived, status, created_at)
+					 VALUES (?, ?, ?, ?, ?, ?, ?, 1, ?, ?)`,
+					name, ss.SourceID, locality, place.Latitude, place.Longitude, region, provider.Name(),
+					SuggestionPending, time.Now().UTC().Format(timestampFormat),
+				); err != nil {
+					return nil, fmt.Errorf("failed to queue range locality: %w", err)
+				}
+				report.Added++
+			}
+		}
+	}
+	return report, nil
+}
+
+// removeStaleLocalities deletes a species source's pending localities that
+// are not in localities. Reviewed ones are kept.
+func (db *Database) removeStaleLocalities(name string, sourceID int64, localities []string) (int, error) {
+	query := `DELETE FROM range_localities WHERE scientific_name = ? AND source_id = ? AND status = ?`
+	args := []interface{}{name, sourceID, SuggestionPending}
+	if len(localities) > 0 {
+		query += ` AND locality NOT IN (?` + strings.Repeat(", ?", len(localities)-1) + `)`
+		for _, l := range localities {
+			args = append(args, l)
+		}
+	}
+	result, err := db.conn.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove stale range localities: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+// cachedGeocode looks a locality up in the geocode cache, asking the
+// provider and caching its answer on a miss. looked reports whether the
+// provider was asked.
+func (db *Database) cachedGeocode(ctx context.Context, provider geocode.Provider, locality string) (place *geocode.Place, looked bool, err error) {
+	key := geocode.Key(locality)
+	var found bool
+	var lat, lon sql.NullFloat64
+	var region sql.NullString
+	err = db.conn.QueryRow(
+		`SELECT found, latitude, longitude, region FROM geocode_cache WHERE provider = ? AND query = ?`,
+		provider.Name(), key,
+	).Scan(&found, &lat, &lon, &region)
+	switch {
+	case err == nil:
+		if !found {
+			return nil, false, nil
+		}
+		return &geocode.Place{Latitude: lat.Float64, Longitude: lon.Float64, Region: region.String}, false, nil
+	case err != sql.ErrNoRows:
+		return nil, false, fmt.Errorf("failed to read geocode cache: %w", err)
+	}
+
+	place, err = provider.Geocode(ctx, locality)
+	if err != nil {
+		return nil, true, err
+	}
+	var args []interface{}
+	if place == nil {
+		args = []interface{}{provider.Name(), key, false, nil, nil, nil}
+	} else {
+		args = []interface{}{provider.Name(), key, true, place.Latitude, place.Longitude, place.Region}
+	}
+	if _, err := db.conn.Exec(
+		`INSERT OR REPLACE INTO geocode_cache (provider, query, found, latitude, longitude, region, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		append(args, time.Now().UTC().Format(timestampFormat))...,
+	); err != nil {
+		return nil, true, fmt.Errorf("failed to cache geocode result: %w", err)
+	}
+	return place, true, nil
+}
+
+// GetRangeLocality returns a range locality, or nil if it does not exist
+func (db *Database) GetRangeLocality(id int64) (*RangeLocality, error) {
+	rows, err := db.conn.Query(`SELECT `+rangeLocalityColumns+` FROM range_localities WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get range locality: %w", err)
+	}
+	defer rows.Close()
+
+	localities, err := scanRangeLocalities(rows)
+	if err != nil || len(localities) == 0 {
+		return nil, err
+	}
+	return localities[0], nil
+}
+
+// ListRangeLocalities returns range localities ordered by species, source,
+// and id. Empty status or scientificName matches any.
+func (db *Database) ListRangeLocalities(status, scientificName string) ([]*RangeLocality, error) {
+	query := `SELECT ` + rangeLocalityColumns + ` FROM range_localities WHERE 1 = 1`
+	var args []interface{}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	if scientificName != "" {
+		query += ` AND scientific_name = ?`
+		args = append(args, scientificName)
+	}
+	query += ` ORDER BY scientific_name, source_id, id`
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list range localities: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRangeLocalities(rows)
+}
+
+// ReviewRangeLocality accepts or rejects a pending range locality. Accepting
+// with a corrected place replaces the coordinates and clears
+// machine_derived. Returns false if no pending locality has that id.
+func (db *Database) ReviewRangeLocality(id int64, accept bool, corrected *geocode.Place) (bool, error) {
+	status := SuggestionRejected
+	if accept {
+		status = SuggestionAccepted
+	}
+	now := time.Now().UTC().Format(timestampFormat)
+
+	var result sql.Result
+	var err error
+	if accept && corrected != nil {
+		var region *string
+		if corrected.Region != "" {
+			region = &corrected.Region
+		}
+		result, err = db.conn.Exec(
+			`UPDATE range_localities SET status = ?, reviewed_at = ?, latitude = ?, longitude = ?,
+				region = COALESCE(?, region), machine_derived = 0
+			 WHERE id = ? AND status = ?`,
+			status, now, corrected.Latitude, corrected.Longitude, region, id, SuggestionPending,
+		)
+	} else {
+		result, err = db.conn.Exec(
+			`UPDATE range_localities SET status = ?, reviewed_at = ? WHERE id = ? AND status = ?`,
+			status, now, id, SuggestionPending,
+		)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to review range locality: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+func scanRangeLocalities(rows *sql.Rows) ([]*RangeLocality, error) {
+	var localities []*RangeLocality
+	for rows.Next() {
+		var rl RangeLocality
+		var createdAt string
+		var reviewedAt sql.NullString
+		if err := rows.Scan(&rl.ID, &rl.ScientificName, &rl.SourceID, &rl.Locality, &rl.Latitude, &rl.Longitude,
+			&rl.Region, &rl.Provider, &rl.MachineDerived, &rl.Status, &createdAt, &reviewedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan range locality: %w", err)
+		}
+		var err error
+		if rl.CreatedAt, err = time.Parse(timestampFormat, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at for range locality %d: %w", rl.ID, err)
+		}
+		if rl.ReviewedAt, err = parseOptionalTimestamp(reviewedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse reviewed_at for range locality %d: %w", rl.ID, err)
+		}
+		localities = append(localities, &rl)
+	}
+	return localities, rows.Err()
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/geocode"
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// countingProvider counts lookups made of a wrapped provider
+type countingProvider struct {
+	geocode.Provider
+	lookups int
+}
+
+func (p *countingProvider) Geocode(ctx context.Context, locality string) (*geocode.Place, error) {
+	p.lookups++
+	return p.Provider.Geocode(ctx, locality)
+}
+
+func TestGeocodeRanges(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if err := db.SaveOakEntry(&models.OakEntry{ScientificName: "texana"}); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	sourceID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Oaks of North America"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+	rangeText := "Edwards Plateau, Texas; Coahuila; Atlantis"
+	ss := models.NewSpeciesSource("texana", sourceID)
+	ss.Range = &rangeText
+	if err := db.SaveSpeciesSource(ss); err != nil {
+		t.Fatalf("SaveSpeciesSource failed: %v", err)
+	}
+
+	provider := &countingProvider{Provider: geocode.NewGazetteer(map[string]geocode.Place{
+		"Edwards Plateau, Texas": {Latitude: 30.5, Longitude: -100},
+		"Coahuila":               {Latitude: 27.3, Longitude: -102},
+	})}
+	report, err := db.GeocodeRanges(t.Context(), provider, nil, nil)
+	if err != nil {
+		t.Fatalf("GeocodeRanges failed: %v", err)
+	}
+	if report.Localities != 3 || report.Added != 2 || report.Unresolved != 1 || report.Lookups != 3 {
+		t.Errorf("report = %+v", report)
+	}
+
+	// A rerun skips queued localities and answers misses from the cache
+	report, _ = db.GeocodeRanges(t.Context(), provider, nil, nil)
+	if report.Added != 0 || report.Lookups != 0 || provider.lookups != 3 {
+		t.Errorf("rerun report = %+v after %d lookups", report, provider.lookups)
+	}
+
+	pending, err := db.ListRangeLocalities(SuggestionPending, "texana")
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("ListRangeLocalities() = %d, %v", len(pending), err)
+	}
+	if ok, err := db.ReviewRangeLocality(pending[0].ID, true, nil); !ok || err != nil {
+		t.Fatalf("ReviewRangeLocality() = %v, %v", ok, err)
+	}
+	if ok, _ := db.ReviewRangeLocality(pending[0].ID, false, nil); ok {
+		t.Error("reviewed an accepted locality again")
+	}
+
+	// Pending localities dropped from the range text are removed; reviewed ones stay
+	rangeText = "Sonora"
+	if err := db.SaveSpeciesSource(ss); err != nil {
+		t.Fatalf("SaveSpeciesSource failed: %v", err)
+	}
+	report, _ = db.GeocodeRanges(t.Context(), provider, []string{"texana"}, nil)
+	if report.Removed != 1 {
+		t.Errorf("removed = %d, want 1", report.Removed)
+	}
+	all, _ := db.ListRangeLocalities("", "texana")
+	if len(all) != 1 || all[0].Status != SuggestionAccepted {
+		t.Errorf("localities after range edit = %+v", all)
+	}
+}
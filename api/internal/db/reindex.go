@@ -0,0 +1,216 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ReindexStep describes one derived structure rebuilt by Reindex
+type ReindexStep struct {
+	Name       string `json:"name"`
+	Items      int    `json:"items"` // Number of rows touched by the step
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// ReindexReport summarizes a full rebuild of derived data
+type ReindexReport struct {
+	Steps      []ReindexStep `json:"steps"`
+	DurationMs int64         `json:"duration_ms"`
+}
+
+// reindexStepFunc rebuilds a single derived structure and returns the number of items touched
+type reindexStepFunc func(db *Database) (int, error)
+
+// reindexSteps lists the derived structures rebuilt by Reindex, in order.
+// New derived data (caches, search indexes) should register a step here.
+var reindexSteps = []struct {
+	name string
+	run  reindexStepFunc
+}{
+	{"hybrids", (*Database).rebuildHybridLists},
+	{"taxa_counts", (*Database).rebuildTaxaCounts},
+	{"cross_references", (*Database).rebuildCrossReferences},
+	{"measurements", (*Database).rebuildMeasurements},
+	{"conflicts", (*Database).rebuildConflicts},
+	{"fulltext", (*Database).rebuildFullText},
+	{"indexes", (*Database).rebuildIndexes},
+	{"statistics", (*Database).analyze},
+}
+
+// Reindex rebuilds all derived data from the source-of-truth columns.
+// Intended for recovery after manual database surgery or bulk imports.
+// If progress is non-nil it is called after each step completes.
+func (db *Database) Reindex(progress func(ReindexStep)) (*ReindexReport, error) {
+	start := time.Now()
+	report := &ReindexReport{Steps: make([]ReindexStep, 0, len(reindexSteps))}
+
+	for _, step := range reindexSteps {
+		stepStart := time.Now()
+		items, err := step.run(db)
+		if err != nil {
+			return nil, fmt.Errorf("reindex step %s failed: %w", step.name, err)
+		}
+		result := ReindexStep{
+			Name:       step.name,
+			Items:      items,
+			DurationMs: time.Since(stepStart).Milliseconds(),
+		}
+		report.Steps = append(report.Steps, result)
+		if progress != nil {
+			progress(result)
+		}
+	}
+
+	report.DurationMs = time.Since(start).Milliseconds()
+	return report, nil
+}
+
+// rebuildHybridLists recomputes every species' hybrids list from the parent1/parent2
+// columns of hybrid entries. Hybrid names that have no entry of their own are kept,
+// since scraped data lists hybrids that were never imported as separate entries.
+// Returns the number of species whose hybrids list changed.
+func (db *Database) rebuildHybridLists() (int, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Map parent -> hybrids that name it, plus the set of names that exist as hybrid entries
+	derived := make(map[string][]string)
+	hybridEntries := make(map[string]bool)
+
+	rows, err := tx.Query(`SELECT scientific_name, parent1, parent2 FROM oak_entries WHERE is_hybrid = 1`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list hybrids: %w", err)
+	}
+	for rows.Next() {
+		var name string
+		var parent1, parent2 sql.NullString
+		if err := rows.Scan(&name, &parent1, &parent2); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan hybrid: %w", err)
+		}
+		hybridEntries[name] = true
+		for _, p := range []sql.NullString{parent1, parent2} {
+			if p.Valid && p.String != "" && !sliceContains(derived[p.String], name) {
+				derived[p.String] = append(derived[p.String], name)
+			}
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	// Compare against the stored lists
+	type update struct {
+		name    string
+		hybrids []string
+	}
+	var updates []update
+
+	rows, err = tx.Query(`SELECT scientific_name, hybrids FROM oak_entries`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list oak entries: %w", err)
+	}
+	for rows.Next() {
+		var name string
+		var hybridsJSON sql.NullString
+		if err := rows.Scan(&name, &hybridsJSON); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan oak entry: %w", err)
+		}
+
+		var stored []string
+		if hybridsJSON.Valid && hybridsJSON.String != "" {
+			if err := json.Unmarshal([]byte(hybridsJSON.String), &stored); err != nil {
+				rows.Close()
+				return 0, fmt.Errorf("failed to unmarshal hybrids for %s: %w", name, err)
+			}
+		}
+
+		// Keep names without an entry; drop hybrid entries that no longer reference this parent
+		rebuilt := make([]string, 0, len(stored))
+		for _, h := range stored {
+			if !hybridEntries[h] && !sliceContains(rebuilt, h) {
+				rebuilt = append(rebuilt, h)
+			}
+		}
+		for _, h := range derived[name] {
+			if !sliceContains(rebuilt, h) {
+				rebuilt = append(rebuilt, h)
+			}
+		}
+		sort.Strings(rebuilt)
+
+		current := append([]string(nil), stored...)
+		sort.Strings(current)
+		if !stringSlicesEqual(current, rebuilt) {
+			updates = append(updates, update{name: name, hybrids: rebuilt})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, u := range updates {
+		data, err := json.Marshal(u.hybrids)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal hybrids: %w", err)
+		}
+		if _, err := tx.Exec(`UPDATE oak_entries SET hybrids = ? WHERE scientific_name = ?`, string(data), u.name); err != nil {
+			return 0, fmt.Errorf("failed to update hybrids for %s: %w", u.name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit hybrids rebuild: %w", err)
+	}
+	return len(updates), nil
+}
+
+// rebuildIndexes rebuilds all SQLite indexes and returns the number of user-defined indexes
+func (db *Database) rebuildIndexes() (int, error) {
+	if _, err := db.conn.Exec(`REINDEX`); err != nil {
+		return 0, fmt.Errorf("failed to reindex: %w", err)
+	}
+	var count int
+	if err := db.conn.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND sql IS NOT NULL`,
+	).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count indexes: %w", err)
+	}
+	return count, nil
+}
+
+// analyze refreshes the query planner statistics and returns the number of tables analyzed
+func (db *Database) analyze() (int, error) {
+	if _, err := db.conn.Exec(`ANALYZE`); err != nil {
+		return 0, fmt.Errorf("failed to analyze: %w", err)
+	}
+	var count int
+	if err := db.conn.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`,
+	).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count tables: %w", err)
+	}
+	return count, nil
+}
+
+// stringSlicesEqual reports whether two string slices have identical contents in order
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestReindexRebuildsHybridLists(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	for _, name := range []string{"alba", "macrocarpa"} {
+		if err := db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatalf("SaveOakEntry(%s) failed: %v", name, err)
+		}
+	}
+
+	parent1 := "alba"
+	parent2 := "macrocarpa"
+	hybrid := models.NewOakEntry("× bebbiana")
+	hybrid.IsHybrid = true
+	hybrid.Parent1 = &parent1
+	hybrid.Parent2 = &parent2
+	if err := db.SaveOakEntry(hybrid); err != nil {
+		t.Fatalf("SaveOakEntry(hybrid) failed: %v", err)
+	}
+
+	// Simulate drift: wipe alba's list, and give macrocarpa a stale hybrid entry
+	// plus a name that has no entry of its own (which must be preserved)
+	stale := models.NewOakEntry("× stale")
+	stale.IsHybrid = true
+	if err := db.SaveOakEntry(stale); err != nil {
+		t.Fatalf("SaveOakEntry(stale) failed: %v", err)
+	}
+	if _, err := db.conn.Exec(`UPDATE oak_entries SET hybrids = '[]' WHERE scientific_name = 'alba'`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.conn.Exec(`UPDATE oak_entries SET hybrids = '["× bebbiana","× stale","× unlisted"]' WHERE scientific_name = 'macrocarpa'`); err != nil {
+		t.Fatal(err)
+	}
+
+	var steps []string
+	report, err := db.Reindex(func(step ReindexStep) {
+		steps = append(steps, step.Name)
+	})
+	if err != nil {
+		t.Fatalf("Reindex failed: %v", err)
+	}
+	if len(steps) != len(report.Steps) || len(steps) != 8 {
+		t.Fatalf("progress reported %v, report has %d steps", steps, len(report.Steps))
+	}
+	if report.Steps[0].Name != "hybrids" || report.Steps[0].Items != 2 {
+		t.Errorf("hybrids step = %+v, want 2 items", report.Steps[0])
+	}
+
+	gotAlba, err := db.GetOakEntry("alba")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gotAlba.Hybrids) != 1 || gotAlba.Hybrids[0] != "× bebbiana" {
+		t.Errorf("alba hybrids = %v, want [× bebbiana]", gotAlba.Hybrids)
+	}
+
+	gotMacrocarpa, err := db.GetOakEntry("macrocarpa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"× bebbiana", "× unlisted"}
+	if !stringSlicesEqual(gotMacrocarpa.Hybrids, want) {
+		t.Errorf("macrocarpa hybrids = %v, want %v", gotMacrocarpa.Hybrids, want)
+	}
+
+	// A second pass has nothing left to fix
+	report, err = db.Reindex(nil)
+	if err != nil {
+		t.Fatalf("second Reindex failed: %v", err)
+	}
+	if report.Steps[0].Items != 0 {
+		t.Errorf("second pass hybrids items = %d, want 0", report.Steps[0].Items)
+	}
+}
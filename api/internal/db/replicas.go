@@ -0,0 +1,158 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jeff/oaks/pkg/migrate"
+)
+
+// replicaCheckInterval is how often a replica's file is checked for
+// replacement, as when a backup is restored over it
+var replicaCheckInterval = 5 * time.Second
+
+// replica is a read-only copy of the database kept current outside the
+// server, by WAL shipping or by restoring backups over it
+type replica struct {
+	path string
+
+	mu      sync.Mutex
+	conn    *sql.DB
+	file    os.FileInfo // The file conn has open
+	checked time.Time
+}
+
+// replicaSet spreads reads over the replicas in turn
+type replicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+// AttachReplicas opens read-only replicas of the database for ForReads to
+// serve reads from. Each must be a copy of this database, so it has the
+// same schema.
+func (db *Database) AttachReplicas(paths []string) error {
+	set := &replicaSet{}
+	for _, path := range paths {
+		r := &replica{path: path}
+		if err := db.openReplica(r); err != nil {
+			set.close()
+			return err
+		}
+		set.replicas = append(set.replicas, r)
+	}
+	if db.replicas != nil {
+		db.replicas.close()
+	}
+	if len(set.replicas) > 0 {
+		db.replicas = set
+	}
+	return nil
+}
+
+// ReplicaPaths lists the attached replicas
+func (db *Database) ReplicaPaths() []string {
+	if db.replicas == nil {
+		return nil
+	}
+	paths := make([]string, len(db.replicas.replicas))
+	for i, r := range db.replicas.replicas {
+		paths[i] = r.path
+	}
+	return paths
+}
+
+// ForReads returns the database to serve a read from: the next replica in
+// turn, or db itself when there are none. Replicas lag the primary, so
+// reads that must see a write just made should use db. The returned
+// Database shares db's logger and timers; it must not be closed or
+// written to.
+func (db *Database) ForReads() *Database {
+	if db.replicas == nil {
+		return db
+	}
+	set := db.replicas
+	r := set.replicas[int(set.next.Add(1)-1)%len(set.replicas)]
+	conn, err := db.replicaConn(r)
+	if err != nil {
+		db.logger.Warn("replica unavailable, reading from the primary", "path", r.path, "error", err)
+		return db
+	}
+
+	reader := *db
+	reader.conn = conn
+	reader.replicas = nil
+	return &reader
+}
+
+// replicaConn returns r's connection, reopening it if its file has been
+// replaced since it was opened. SQLite sees changes written to the open
+// file, but a connection keeps reading a file that was renamed over.
+func (db *Database) replicaConn(r *replica) (*sql.DB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.checked) < replicaCheckInterval {
+		return r.conn, nil
+	}
+	r.checked = time.Now()
+
+	file, err := os.Stat(r.path)
+	if err != nil {
+		return nil, err
+	}
+	if os.SameFile(file, r.file) {
+		return r.conn, nil
+	}
+
+	old := r.conn
+	if err := db.openReplicaLocked(r); err != nil {
+		return nil, err
+	}
+	db.logger.Info("reopened replaced replica", "path", r.path)
+	// Close waits for queries still reading the old file
+	go old.Close()
+	return r.conn, nil
+}
+
+func (db *Database) openReplica(r *replica) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return db.openReplicaLocked(r)
+}
+
+// openReplicaLocked opens r's file read-only and checks it is a copy of
+// this database
+func (db *Database) openReplicaLocked(r *replica) error {
+	file, err := os.Stat(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to open replica: %w", err)
+	}
+	dsn := withDSNParam(withDSNParam("file:"+r.path, "mode=ro"), "_query_only=true")
+	conn := sql.OpenDB(&timedConnector{dsn: dsn, timer: db.queries, revision: db.revision})
+
+	if err := conn.QueryRow(`SELECT COUNT(*) FROM oak_entries`).Scan(new(int)); err != nil {
+		conn.Close()
+		return fmt.Errorf("replica %s is not an oak database: %w", r.path, err)
+	}
+	version, err := migrate.New(conn, migrate.Schema).Version()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to read replica %s schema version: %w", r.path, err)
+	}
+	if primary, err := db.migrator.Version(); err == nil && version != primary {
+		db.logger.Warn("replica schema differs from the primary", "path", r.path, "version", version, "primary", primary)
+	}
+
+	r.conn, r.file, r.checked = conn, file, time.Now()
+	return nil
+}
+
+func (s *replicaSet) close() {
+	for _, r := range s.replicas {
+		r.conn.Close()
+	}
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestReplicas(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if db.ForReads() != db {
+		t.Fatal("ForReads() without replicas is not the primary")
+	}
+
+	if err := db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	replicaPath := filepath.Join(dir, "replica.db")
+	if _, err := db.conn.Exec(`VACUUM INTO ?`, replicaPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AttachReplicas([]string{filepath.Join(dir, "missing.db")}); err == nil {
+		t.Error("AttachReplicas(missing file) succeeded, want an error")
+	}
+	if err := db.AttachReplicas([]string{replicaPath}); err != nil {
+		t.Fatalf("AttachReplicas failed: %v", err)
+	}
+
+	// The replica serves reads but has not seen a write since it was copied
+	if err := db.SaveOakEntry(models.NewOakEntry("robur")); err != nil {
+		t.Fatal(err)
+	}
+	reader := db.ForReads()
+	if reader == db {
+		t.Fatal("ForReads() returned the primary, want a replica")
+	}
+	if entry, err := reader.GetOakEntry("alba"); err != nil || entry == nil {
+		t.Errorf("replica GetOakEntry(alba) = %v, %v; want the entry", entry, err)
+	}
+	if entry, _ := reader.GetOakEntry("robur"); entry != nil {
+		t.Error("replica has robur, written after it was copied")
+	}
+	if err := reader.SaveOakEntry(models.NewOakEntry("rubra")); err == nil {
+		t.Error("writing to a replica succeeded, want an error")
+	}
+
+	// A backup restored over the replica is picked up
+	saved := replicaCheckInterval
+	replicaCheckInterval = 0
+	defer func() { replicaCheckInterval = saved }()
+	restored := filepath.Join(dir, "restored.db")
+	if _, err := db.conn.Exec(`VACUUM INTO ?`, restored); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(restored, replicaPath); err != nil {
+		t.Fatal(err)
+	}
+	if entry, err := db.ForReads().GetOakEntry("robur"); err != nil || entry == nil {
+		t.Errorf("GetOakEntry(robur) after restoring the replica = %v, %v; want the entry", entry, err)
+	}
+}
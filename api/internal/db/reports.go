@@ -0,0 +1,169 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// bundledReports are the starter reports, seeded on startup
+//
+//go:embed reports.yaml
+var bundledReports []byte
+
+const reportsSelect = `SELECT name, title, description, query, columns, format FROM reports`
+
+// seedReports adds the bundled reports that are not already in the table.
+// Edited ones are left alone; deleted ones come back on the next start.
+func (db *Database) seedReports() error {
+	var reports []*models.Report
+	if err := yaml.Unmarshal(bundledReports, &reports); err != nil {
+		return fmt.Errorf("failed to parse bundled reports: %w", err)
+	}
+	for _, r := range reports {
+		if err := db.saveReport(r, `INSERT OR IGNORE`); err != nil {
+			return fmt.Errorf("failed to seed reports: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListReports returns every saved report, ordered by name
+func (db *Database) ListReports() ([]*models.Report, error) {
+	rows, err := db.conn.Query(reportsSelect + ` ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []*models.Report
+	for rows.Next() {
+		r, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, r)
+	}
+	return reports, rows.Err()
+}
+
+// GetReport gets a report by name, or nil if it does not exist
+func (db *Database) GetReport(name string) (*models.Report, error) {
+	r, err := scanReport(db.conn.QueryRow(reportsSelect+` WHERE name = ?`, name))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return r, err
+}
+
+// SaveReport creates or replaces a report
+func (db *Database) SaveReport(r *models.Report) error {
+	if err := db.saveReport(r, `INSERT OR REPLACE`); err != nil {
+		return fmt.Errorf("failed to save report: %w", err)
+	}
+	return nil
+}
+
+func (db *Database) saveReport(r *models.Report, insert string) error {
+	columns := r.Columns
+	if columns == nil {
+		columns = []models.ReportColumn{}
+	}
+	columnsJSON, err := json.Marshal(columns)
+	if err != nil {
+		return fmt.Errorf("failed to marshal report columns: %w", err)
+	}
+	format := r.Format
+	if format == "" {
+		format = models.ReportFormatCSV
+	}
+
+	_, err = db.conn.Exec(
+		insert+` INTO reports (name, title, description, query, columns, format) VALUES (?, ?, ?, ?, ?, ?)`,
+		r.Name, r.Title, r.Description, r.Query, string(columnsJSON), format,
+	)
+	return err
+}
+
+// DeleteReport deletes a report. Returns false if it does not exist.
+func (db *Database) DeleteReport(name string) (bool, error) {
+	result, err := db.conn.Exec(`DELETE FROM reports WHERE name = ?`, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete report: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// RunReport runs a report's query read-only, as ReadOnlyQuery does, and
+// keeps the report's columns in order under their titles. A column the
+// query does not return is a *QueryError.
+func (db *Database) RunReport(ctx context.Context, r *models.Report, limit int, timeout time.Duration) (*QueryResult, error) {
+	result, err := db.ReadOnlyQuery(ctx, r.Query, limit, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Columns) == 0 {
+		return result, nil
+	}
+
+	index := make(map[string]int, len(result.Columns))
+	for i, name := range result.Columns {
+		index[name] = i
+	}
+	picked := make([]int, len(r.Columns))
+	titles := make([]string, len(r.Columns))
+	for i, c := range r.Columns {
+		j, ok := index[c.Name]
+		if !ok {
+			return nil, &QueryError{Err: fmt.Errorf("column %q is not in the query's results", c.Name)}
+		}
+		picked[i] = j
+		titles[i] = c.Title
+		if titles[i] == "" {
+			titles[i] = c.Name
+		}
+	}
+
+	rows := make([][]interface{}, len(result.Rows))
+	for i, row := range result.Rows {
+		rows[i] = make([]interface{}, len(picked))
+		for k, j := range picked {
+			rows[i][k] = row[j]
+		}
+	}
+	result.Columns = titles
+	result.Rows = rows
+	return result, nil
+}
+
+// scanReport scans one row of reportsSelect
+func scanReport(row interface{ Scan(...interface{}) error }) (*models.Report, error) {
+	var r models.Report
+	var columnsJSON sql.NullString
+	if err := row.Scan(&r.Name, &r.Title, &r.Description, &r.Query, &columnsJSON, &r.Format); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan report: %w", err)
+	}
+	if columnsJSON.Valid && columnsJSON.String != "" {
+		if err := json.Unmarshal([]byte(columnsJSON.String), &r.Columns); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal columns for report %s: %w", r.Name, err)
+		}
+	}
+	if r.Columns == nil {
+		r.Columns = []models.ReportColumn{}
+	}
+	return &r, nil
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestBundledReports(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	reports, err := db.ListReports()
+	if err != nil {
+		t.Fatalf("ListReports failed: %v", err)
+	}
+	if len(reports) < 2 {
+		t.Fatalf("got %d bundled reports, want at least 2", len(reports))
+	}
+
+	alba := models.NewOakEntry("alba")
+	hybrid := models.NewOakEntry("× bebbiana")
+	hybrid.IsHybrid = true
+	parent1, parent2 := "alba", "macrocarpa"
+	hybrid.Parent1, hybrid.Parent2 = &parent1, &parent2
+	for _, e := range []*models.OakEntry{alba, hybrid} {
+		if err := db.SaveOakEntry(e); err != nil {
+			t.Fatalf("SaveOakEntry(%q) failed: %v", e.ScientificName, err)
+		}
+	}
+
+	// Every bundled report runs on the current schema
+	for _, r := range reports {
+		if _, err := db.RunReport(context.Background(), r, DefaultQueryRowLimit, time.Second); err != nil {
+			t.Errorf("report %s failed: %v", r.Name, err)
+		}
+	}
+
+	r, err := db.GetReport("hybrids-missing-parents")
+	if err != nil || r == nil {
+		t.Fatalf("GetReport = %v, %v", r, err)
+	}
+	result, err := db.RunReport(context.Background(), r, DefaultQueryRowLimit, time.Second)
+	if err != nil {
+		t.Fatalf("RunReport failed: %v", err)
+	}
+	if result.Columns[0] != "Hybrid" || len(result.Rows) != 1 || result.Rows[0][0] != "× bebbiana" {
+		t.Fatalf("result = %+v, want × bebbiana under the Hybrid heading", result)
+	}
+	if row := result.Rows[0]; row[3] != int64(0) || row[4] != int64(1) {
+		t.Errorf("missing flags = %v, %v; want only parent 2 missing", row[3], row[4])
+	}
+}
+
+func TestSaveReport(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	r := &models.Report{
+		Name:    "names",
+		Title:   "Names",
+		Query:   "SELECT scientific_name, author FROM oak_entries",
+		Columns: []models.ReportColumn{{Name: "author"}, {Name: "scientific_name", Title: "Name"}},
+	}
+	if err := db.SaveReport(r); err != nil {
+		t.Fatalf("SaveReport failed: %v", err)
+	}
+	got, err := db.GetReport("names")
+	if err != nil || got == nil {
+		t.Fatalf("GetReport = %v, %v", got, err)
+	}
+	if got.Format != models.ReportFormatCSV || len(got.Columns) != 2 || got.Columns[1].Title != "Name" {
+		t.Errorf("saved report = %+v", got)
+	}
+
+	result, err := db.RunReport(context.Background(), got, 10, time.Second)
+	if err != nil {
+		t.Fatalf("RunReport failed: %v", err)
+	}
+	if len(result.Columns) != 2 || result.Columns[0] != "author" || result.Columns[1] != "Name" {
+		t.Errorf("columns = %v, want author then Name", result.Columns)
+	}
+
+	got.Columns = append(got.Columns, models.ReportColumn{Name: "missing"})
+	var qerr *QueryError
+	if _, err := db.RunReport(context.Background(), got, 10, time.Second); !errors.As(err, &qerr) {
+		t.Errorf("unknown column error = %v, want a QueryError", err)
+	}
+
+	if found, err := db.DeleteReport("names"); err != nil || !found {
+		t.Errorf("DeleteReport = %v, %v", found, err)
+	}
+	if got, _ := db.GetReport("names"); got != nil {
+		t.Errorf("report still exists after delete")
+	}
+}
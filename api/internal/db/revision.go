@@ -0,0 +1,41 @@
+package db
+
+import (
+	"sync/atomic"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// revisionTables are the content tables whose writes advance Revision.
+// Derived and bookkeeping tables (hashes, counts, views, jobs) are left out,
+// since reads fill some of them.
+var revisionTables = map[string]bool{
+	"oak_entries":       true,
+	"species_sources":   true,
+	"sources":           true,
+	"species_accounts":  true,
+	"range_localities":  true,
+	"common_names":      true,
+	"taxa":              true,
+	"genera":            true,
+	"custom_fields":     true,
+	"custom_attributes": true,
+}
+
+// Revision returns a counter that advances whenever this process writes a
+// row of species, source, account, range locality, taxon, genus, or custom
+// field content, by any path. It starts at zero on each open, so it only
+// orders changes within a run; caches built from content compare it to tell
+// when they are stale.
+func (db *Database) Revision() uint64 {
+	return db.revision.Load()
+}
+
+// countWrites advances revision on every write to a content table of conn
+func countWrites(conn *sqlite3.SQLiteConn, revision *atomic.Uint64) {
+	conn.RegisterUpdateHook(func(op int, database, table string, rowid int64) {
+		if revisionTables[table] {
+			revision.Add(1)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestRevision(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	start := db.Revision()
+	if err := db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	afterWrite := db.Revision()
+	if afterWrite <= start {
+		t.Fatalf("Revision = %d after a species write, want past %d", afterWrite, start)
+	}
+
+	// Bookkeeping writes and reads leave it alone
+	if err := db.RecordSpeciesView("alba", time.Now()); err != nil {
+		t.Fatalf("RecordSpeciesView failed: %v", err)
+	}
+	if _, err := db.GetEntityHash(HashKindSpecies, "alba"); err != nil {
+		t.Fatalf("GetEntityHash failed: %v", err)
+	}
+	if got := db.Revision(); got != afterWrite {
+		t.Errorf("Revision = %d after bookkeeping writes, want %d", got, afterWrite)
+	}
+}
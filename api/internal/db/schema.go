@@ -0,0 +1,31 @@
+package db
+
+import "github.com/jeff/oaks/pkg/migrate"
+
+// SchemaStatus is the database's schema version and every migration
+type SchemaStatus struct {
+	Version    int              `json:"version"`
+	Latest     int              `json:"latest"`
+	Migrations []migrate.Status `json:"migrations"`
+}
+
+// SchemaStatus reports the schema version and which migrations are applied.
+// New applies pending migrations, so Version is normally Latest.
+func (db *Database) SchemaStatus() (*SchemaStatus, error) {
+	version, err := db.migrator.Version()
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := db.migrator.Status()
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaStatus{Version: version, Latest: db.migrator.Latest(), Migrations: migrations}, nil
+}
+
+// MigrateSchema upgrades or reverts the schema to version and returns the
+// migrations run. Reverting is for going back to an older build; the next
+// New of this build applies the migrations again.
+func (db *Database) MigrateSchema(version int) ([]migrate.Migration, error) {
+	return db.migrator.To(version)
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jeff/oaks/pkg/migrate"
+)
+
+func TestSchemaMigrations(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	latest := len(migrate.Schema)
+
+	status, err := db.SchemaStatus()
+	if err != nil {
+		t.Fatalf("SchemaStatus failed: %v", err)
+	}
+	if status.Version != latest || status.Latest != latest || len(status.Migrations) != latest {
+		t.Fatalf("SchemaStatus() = %+v, want every migration applied", status)
+	}
+	for _, m := range status.Migrations {
+		if m.AppliedAt == "" {
+			t.Errorf("migration %d not applied", m.Version)
+		}
+	}
+
+	indexExists := func() bool {
+		var n int
+		if err := db.conn.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = 'idx_oak_entries_genus'`).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n == 1
+	}
+	if !indexExists() {
+		t.Error("migration 1 index missing")
+	}
+	reverted, err := db.MigrateSchema(0)
+	if err != nil || len(reverted) != latest {
+		t.Fatalf("MigrateSchema(0) = %v, %v", reverted, err)
+	}
+	if indexExists() {
+		t.Error("index still present after reverting migration 1")
+	}
+	db.Close()
+
+	// Reopening applies the migrations again
+	db, err = New(dbPath)
+	if err != nil {
+		t.Fatalf("New after revert failed: %v", err)
+	}
+	if status, _ := db.SchemaStatus(); status.Version != latest || !indexExists() {
+		t.Errorf("reopened schema at version %d, want %d", status.Version, latest)
+	}
+
+	// A schema from a newer build is refused
+	if _, err := db.conn.Exec(`INSERT INTO schema_version (version, name, applied_at) VALUES (?, 'future', '2030-01-01T00:00:00Z')`, latest+1); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	if _, err := New(dbPath); !migrate.IsNewerSchema(err) {
+		t.Errorf("New on a newer schema error = %v, want NewerSchemaError", err)
+	}
+}
@@ -0,0 +1,83 @@
+package db
+
+import "fmt"
+
+// Sitemap page kinds
+const (
+	SitemapSpecies = "species"
+	SitemapTaxon   = "taxon"
+	SitemapSource  = "source"
+)
+
+// SitemapEntry is a public page for the sitemap. Level is set for taxa and
+// SourceID for sources; Name is the species or taxon name or the source name.
+type SitemapEntry struct {
+	Kind         string
+	Name         string
+	Level        string
+	SourceID     int64
+	LastModified *string // Nil for rows written before updated_at was tracked
+}
+
+// ListSitemapEntries returns every published species, taxon, and source, in
+// that order. A species' lastmod also covers its source data and account.
+func (db *Database) ListSitemapEntries() ([]*SitemapEntry, error) {
+	var entries []*SitemapEntry
+
+	rows, err := db.conn.Query(
+		`SELECT o.scientific_name, CASE
+			WHEN a.updated_at IS NOT NULL AND (o.updated_at IS NULL OR a.updated_at > o.updated_at) THEN a.updated_at
+			ELSE o.updated_at END
+		 FROM oak_entries o LEFT JOIN species_accounts a ON a.scientific_name = o.scientific_name
+		 WHERE o.visibility != 'draft' ORDER BY o.scientific_name`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list species: %w", err)
+	}
+	for rows.Next() {
+		e := &SitemapEntry{Kind: SitemapSpecies}
+		if err := rows.Scan(&e.Name, &e.LastModified); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan species: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.conn.Query(
+		`SELECT t.name, t.level, t.updated_at FROM taxa t
+		 JOIN taxon_levels l ON l.name = t.level ORDER BY l.rank, t.name`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list taxa: %w", err)
+	}
+	for rows.Next() {
+		e := &SitemapEntry{Kind: SitemapTaxon}
+		if err := rows.Scan(&e.Name, &e.Level, &e.LastModified); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan taxon: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.conn.Query(`SELECT id, name, updated_at FROM sources ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sources: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		e := &SitemapEntry{Kind: SitemapSource}
+		if err := rows.Scan(&e.SourceID, &e.Name, &e.LastModified); err != nil {
+			return nil, fmt.Errorf("failed to scan source: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestListSitemapEntries(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if err := db.SaveOakEntry(&models.OakEntry{ScientificName: "alba"}); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	if err := db.SaveOakEntry(&models.OakEntry{ScientificName: "rubra", Visibility: models.VisibilityDraft}); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	if err := db.InsertTaxon(&models.Taxon{Name: "Quercus", Level: models.TaxonLevelSection}); err != nil {
+		t.Fatalf("InsertTaxon failed: %v", err)
+	}
+	sourceID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Oaks of North America"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+
+	entries, err := db.ListSitemapEntries()
+	if err != nil {
+		t.Fatalf("ListSitemapEntries failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3 (drafts excluded): %+v", len(entries), entries)
+	}
+	want := []SitemapEntry{
+		{Kind: SitemapSpecies, Name: "alba"},
+		{Kind: SitemapTaxon, Name: "Quercus", Level: "section"},
+		{Kind: SitemapSource, Name: "Oaks of North America", SourceID: sourceID},
+	}
+	for i, e := range entries {
+		if e.Kind != want[i].Kind || e.Name != want[i].Name || e.Level != want[i].Level || e.SourceID != want[i].SourceID {
+			t.Errorf("entry %d = %+v, want %+v", i, e, want[i])
+		}
+		if e.LastModified == nil || len(*e.LastModified) != len(timestampFormat) {
+			t.Errorf("entry %d lastmod = %v", i, e.LastModified)
+		}
+	}
+
+	// Writing source data touches the species; the account's timestamp wins when newer
+	if _, err := db.conn.Exec(`UPDATE oak_entries SET updated_at = '2020-01-01T00:00:00Z'`); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveSpeciesSource(models.NewSpeciesSource("alba", sourceID)); err != nil {
+		t.Fatalf("SaveSpeciesSource failed: %v", err)
+	}
+	entries, _ = db.ListSitemapEntries()
+	if *entries[0].LastModified == "2020-01-01T00:00:00Z" {
+		t.Error("saving species source data should update the species' lastmod")
+	}
+
+	if _, err := db.conn.Exec(`UPDATE oak_entries SET updated_at = '2020-01-01T00:00:00Z'`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.SaveSpeciesAccount(&models.SpeciesAccount{ScientificName: "alba", Markdown: "White oak."}); err != nil {
+		t.Fatalf("SaveSpeciesAccount failed: %v", err)
+	}
+	entries, _ = db.ListSitemapEntries()
+	if *entries[0].LastModified == "2020-01-01T00:00:00Z" {
+		t.Error("species lastmod should include its account")
+	}
+}
@@ -0,0 +1,171 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// maxSlowStatements caps how many distinct statements SlowQueries tracks;
+// past it, slow queries are still logged and counted in the total.
+const maxSlowStatements = 100
+
+// SlowQuery counts the times one statement ran past the slow-query threshold
+type SlowQuery struct {
+	Statement string    `json:"statement"` // SQL text with placeholders; parameter values are never kept
+	Count     int       `json:"count"`
+	MaxMs     float64   `json:"max_ms"`
+	TotalMs   float64   `json:"total_ms"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// SlowQueryReport is what SlowQueries returns
+type SlowQueryReport struct {
+	ThresholdMs float64      `json:"threshold_ms"` // 0 when slow queries are not tracked
+	Total       int          `json:"total"`        // All slow queries since startup
+	Queries     []*SlowQuery `json:"queries"`      // Slowest first by max duration
+}
+
+// queryTimer times every statement run on the database's connections and
+// logs and counts those slower than its threshold.
+type queryTimer struct {
+	mu        sync.Mutex
+	threshold time.Duration // 0 disables
+	logger    *slog.Logger
+	slow      map[string]*SlowQuery
+	total     int
+}
+
+func newQueryTimer() *queryTimer {
+	return &queryTimer{
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		slow:   make(map[string]*SlowQuery),
+	}
+}
+
+// observe records a statement that took elapsed. Only the number of
+// parameters is logged, never their values.
+func (t *queryTimer) observe(query string, params int, elapsed time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.threshold <= 0 || elapsed < t.threshold {
+		return
+	}
+
+	statement := strings.Join(strings.Fields(query), " ")
+	ms := float64(elapsed.Microseconds()) / 1000
+	t.logger.Warn("slow query", "statement", statement, "params", params, "duration_ms", ms)
+
+	t.total++
+	sq, ok := t.slow[statement]
+	if !ok {
+		if len(t.slow) >= maxSlowStatements {
+			return
+		}
+		sq = &SlowQuery{Statement: statement}
+		t.slow[statement] = sq
+	}
+	sq.Count++
+	sq.TotalMs += ms
+	sq.MaxMs = max(sq.MaxMs, ms)
+	sq.LastSeen = time.Now().UTC()
+}
+
+// SetSlowQueryThreshold sets how long a statement may run before it is logged
+// and counted as slow. Zero, the default, turns tracking off.
+func (db *Database) SetSlowQueryThreshold(d time.Duration) {
+	db.queries.mu.Lock()
+	defer db.queries.mu.Unlock()
+	db.queries.threshold = d
+}
+
+// SlowQueries reports the statements that ran past the slow-query threshold
+// since the database was opened.
+func (db *Database) SlowQueries() SlowQueryReport {
+	db.queries.mu.Lock()
+	defer db.queries.mu.Unlock()
+
+	report := SlowQueryReport{
+		ThresholdMs: float64(db.queries.threshold.Microseconds()) / 1000,
+		Total:       db.queries.total,
+		Queries:     []*SlowQuery{},
+	}
+	for _, sq := range db.queries.slow {
+		c := *sq
+		report.Queries = append(report.Queries, &c)
+	}
+	sort.Slice(report.Queries, func(i, j int) bool {
+		return report.Queries[i].MaxMs > report.Queries[j].MaxMs
+	})
+	return report
+}
+
+// timedConnector opens sqliteDriver connections that report to a queryTimer
+// and count content writes toward Revision
+type timedConnector struct {
+	dsn      string
+	timer    *queryTimer
+	revision *atomic.Uint64
+}
+
+func (c *timedConnector) Connect(context.Context) (driver.Conn, error) {
+	conn, err := sqliteDriver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	sqliteConn := conn.(*sqlite3.SQLiteConn)
+	countWrites(sqliteConn, c.revision)
+	return &timedConn{SQLiteConn: sqliteConn, timer: c.timer}, nil
+}
+
+func (c *timedConnector) Driver() driver.Driver {
+	return sqliteDriver
+}
+
+// timedConn times statements run directly on the connection, which is how
+// database/sql runs every Exec and Query here; nothing prepares statements.
+type timedConn struct {
+	*sqlite3.SQLiteConn
+	timer *queryTimer
+}
+
+func (c *timedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	res, err := c.SQLiteConn.ExecContext(ctx, query, args)
+	c.timer.observe(query, len(args), time.Since(start))
+	return res, err
+}
+
+func (c *timedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := c.SQLiteConn.QueryContext(ctx, query, args)
+	if err != nil {
+		c.timer.observe(query, len(args), time.Since(start))
+		return nil, err
+	}
+	return &timedRows{SQLiteRows: rows.(*sqlite3.SQLiteRows), query: query, params: len(args), start: start, timer: c.timer}, nil
+}
+
+// timedRows stops the clock when the rows are closed, since SQLite does
+// most of a query's work while the rows are read.
+type timedRows struct {
+	*sqlite3.SQLiteRows
+	query  string
+	params int
+	start  time.Time
+	timer  *queryTimer
+}
+
+func (r *timedRows) Close() error {
+	err := r.SQLiteRows.Close()
+	r.timer.observe(r.query, r.params, time.Since(r.start))
+	return err
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestSlowQueriesAreLoggedWithoutParameters(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	var logged bytes.Buffer
+	db.SetLogger(slog.New(slog.NewTextHandler(&logged, nil)))
+
+	// Off by default
+	if err := db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	if report := db.SlowQueries(); report.Total != 0 || logged.Len() != 0 {
+		t.Fatalf("with no threshold got %d slow queries, log %q", report.Total, logged.String())
+	}
+
+	// Every statement is slower than a nanosecond
+	db.SetSlowQueryThreshold(time.Nanosecond)
+	if _, err := db.GetOakEntry("secret-name"); err != nil {
+		t.Fatalf("GetOakEntry failed: %v", err)
+	}
+
+	report := db.SlowQueries()
+	if report.Total == 0 || len(report.Queries) == 0 {
+		t.Fatalf("report = %+v, want the lookup counted", report)
+	}
+	var found bool
+	for _, q := range report.Queries {
+		// Whitespace is collapsed so each statement logs on one line
+		if strings.Contains(q.Statement, "FROM oak_entries") && !strings.Contains(q.Statement, "\n") && q.Count > 0 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("queries = %+v, want the oak_entries lookup on one line", report.Queries)
+	}
+
+	out := logged.String()
+	if !strings.Contains(out, "slow query") || !strings.Contains(out, "params=1") {
+		t.Errorf("log = %q, want slow query lines with a parameter count", out)
+	}
+	if strings.Contains(out, "secret-name") {
+		t.Errorf("log contains a parameter value: %q", out)
+	}
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/names"
+)
+
+// SpeciesNameBySlug returns the name of the species whose slug (names.Slug)
+// is slug, or "" if there is none, or more than one (names that differ only
+// in punctuation, stored before names were validated).
+func (db *Database) SpeciesNameBySlug(slug string) (string, error) {
+	rows, err := db.conn.Query(`SELECT scientific_name FROM oak_entries WHERE name_slug(scientific_name) = ? LIMIT 2`, slug)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up species slug: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return "", fmt.Errorf("failed to scan species name: %w", err)
+		}
+		matches = append(matches, name)
+	}
+	if err := rows.Err(); err != nil || len(matches) != 1 {
+		return "", err
+	}
+	return matches[0], nil
+}
+
+// SpeciesRedirect returns the species an old slug leads to since its species
+// was renamed or merged, or "" if the slug was never redirected.
+func (db *Database) SpeciesRedirect(slug string) (string, error) {
+	var name string
+	err := db.conn.QueryRow(`SELECT scientific_name FROM species_redirects WHERE slug = ?`, slug).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up species redirect: %w", err)
+	}
+	return name, nil
+}
+
+// DeleteOakEntryRedirecting deletes a species renamed or merged into target,
+// redirecting its slug, and the slugs already redirected to it, to target.
+func (db *Database) DeleteOakEntryRedirecting(name, target string) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	if _, err := tx.Exec(`DELETE FROM oak_entries WHERE scientific_name = ?`, name); err != nil {
+		return fmt.Errorf("failed to delete oak entry: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE species_redirects SET scientific_name = ? WHERE scientific_name = ?`, target, name); err != nil {
+		return fmt.Errorf("failed to move species redirects: %w", err)
+	}
+	if _, err := tx.Exec(
+		`INSERT OR REPLACE INTO species_redirects (slug, scientific_name, created_at) VALUES (?, ?, ?)`,
+		names.Slug(name), target, time.Now().UTC().Format(timestampFormat),
+	); err != nil {
+		return fmt.Errorf("failed to add species redirect: %w", err)
+	}
+	// A species merged back into one it was merged from takes its slug back
+	if _, err := tx.Exec(`DELETE FROM species_redirects WHERE slug = ?`, names.Slug(target)); err != nil {
+		return fmt.Errorf("failed to remove species redirect: %w", err)
+	}
+	return tx.Commit()
+}
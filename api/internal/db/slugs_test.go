@@ -0,0 +1,44 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestSpeciesSlugs(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+	for _, name := range []string{"alba × macrocarpa", "alba", "robur"} {
+		if err := db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatalf("SaveOakEntry failed: %v", err)
+		}
+	}
+
+	if name, err := db.SpeciesNameBySlug("alba-x-macrocarpa"); err != nil || name != "alba × macrocarpa" {
+		t.Errorf("SpeciesNameBySlug(alba-x-macrocarpa) = %q, %v", name, err)
+	}
+	if entry, _ := db.GetOakEntry("alba × macrocarpa"); entry.Slug != "alba-x-macrocarpa" {
+		t.Errorf("entry slug = %q", entry.Slug)
+	}
+
+	// alba is merged into robur, then robur back into alba
+	if err := db.DeleteOakEntryRedirecting("alba", "robur"); err != nil {
+		t.Fatalf("DeleteOakEntryRedirecting failed: %v", err)
+	}
+	if name, _ := db.SpeciesRedirect("alba"); name != "robur" {
+		t.Errorf("SpeciesRedirect(alba) = %q, want robur", name)
+	}
+	if err := db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeleteOakEntryRedirecting("robur", "alba"); err != nil {
+		t.Fatalf("DeleteOakEntryRedirecting failed: %v", err)
+	}
+	if name, _ := db.SpeciesRedirect("alba"); name != "" {
+		t.Errorf("SpeciesRedirect(alba) = %q after alba took its slug back", name)
+	}
+	if name, _ := db.SpeciesRedirect("robur"); name != "alba" {
+		t.Errorf("SpeciesRedirect(robur) = %q, want alba", name)
+	}
+}
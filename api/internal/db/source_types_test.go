@@ -0,0 +1,31 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeSourceTypes(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	for _, sourceType := range []string{"Website", "website", "Book", "Journal", "Personal Observation", "Scroll"} {
+		if _, err := db.conn.Exec(`INSERT INTO sources (source_type, name) VALUES (?, ?)`, sourceType, sourceType+" source"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Runs on every open, so an older database is normalized on upgrade
+	if err := db.initializeSchema(); err != nil {
+		t.Fatalf("initializeSchema failed: %v", err)
+	}
+
+	stats, err := db.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	want := map[string]int{"website": 2, "book": 1, "paper": 1, "personal-observation": 1, "Scroll": 1}
+	if !reflect.DeepEqual(stats.SourcesByType, want) {
+		t.Errorf("SourcesByType = %v, want %v", stats.SourcesByType, want)
+	}
+}
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// SourceUsage lists what refers to a source. Foreign keys are not enforced,
+// so deleting a source in use would leave these references dangling.
+type SourceUsage struct {
+	SourceID          int64    `json:"source_id"`
+	Species           []string `json:"species"`            // Species with data from the source
+	SupersededSources []int64  `json:"superseded_sources"` // Sources superseded by this one
+	Templates         []string `json:"templates"`          // Entry templates listing the source
+}
+
+// InUse reports whether anything refers to the source
+func (u *SourceUsage) InUse() bool {
+	return len(u.Species) > 0 || len(u.SupersededSources) > 0 || len(u.Templates) > 0
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// GetSourceUsage reports the species, sources, and templates that refer to a source
+func (db *Database) GetSourceUsage(id int64) (*SourceUsage, error) {
+	return getSourceUsage(db.conn, id)
+}
+
+func getSourceUsage(q queryer, id int64) (*SourceUsage, error) {
+	usage := &SourceUsage{SourceID: id, Species: []string{}, SupersededSources: []int64{}, Templates: []string{}}
+
+	rows, err := q.Query(`SELECT scientific_name FROM species_sources WHERE source_id = ? ORDER BY scientific_name`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list species using source: %w", err)
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan species name: %w", err)
+		}
+		usage.Species = append(usage.Species, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = q.Query(`SELECT id FROM sources WHERE superseded_by = ? ORDER BY id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list superseded sources: %w", err)
+	}
+	for rows.Next() {
+		var sourceID int64
+		if err := rows.Scan(&sourceID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan source id: %w", err)
+		}
+		usage.SupersededSources = append(usage.SupersededSources, sourceID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = q.Query(
+		`SELECT name FROM templates
+		 WHERE EXISTS (SELECT 1 FROM json_each(templates.source_ids) WHERE value = ?)
+		 ORDER BY name`, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates using source: %w", err)
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan template name: %w", err)
+		}
+		usage.Templates = append(usage.Templates, name)
+	}
+	rows.Close()
+	return usage, rows.Err()
+}
+
+// DeleteSourceCascade deletes a source along with everything that refers to
+// it, in one transaction: its species data is deleted, sources it supersedes
+// are no longer superseded, and templates stop listing it. Returns what was
+// removed.
+func (db *Database) DeleteSourceCascade(id int64) (*SourceUsage, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	usage, err := getSourceUsage(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM species_sources WHERE source_id = ?`, id); err != nil {
+		return nil, fmt.Errorf("failed to delete species data for source: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE sources SET superseded_by = NULL WHERE superseded_by = ?`, id); err != nil {
+		return nil, fmt.Errorf("failed to clear superseded_by: %w", err)
+	}
+	if _, err := tx.Exec(
+		`UPDATE templates
+		 SET source_ids = (SELECT json_group_array(value) FROM json_each(templates.source_ids) WHERE value != ?)
+		 WHERE EXISTS (SELECT 1 FROM json_each(templates.source_ids) WHERE value = ?)`, id, id,
+	); err != nil {
+		return nil, fmt.Errorf("failed to remove source from templates: %w", err)
+	}
+
+	result, err := tx.Exec(`DELETE FROM sources WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete source: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	} else if n == 0 {
+		return nil, fmt.Errorf("source not found: %d", id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit source deletion: %w", err)
+	}
+	return usage, nil
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestSourceUsageAndCascade(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	id, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Flora, 2nd ed."})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+	oldID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Flora, 1st ed.", SupersededBy: &id})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+	for _, name := range []string{"rubra", "alba"} {
+		if err := db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatalf("SaveOakEntry failed: %v", err)
+		}
+		if err := db.SaveSpeciesSource(&models.SpeciesSource{ScientificName: name, SourceID: id}); err != nil {
+			t.Fatalf("SaveSpeciesSource failed: %v", err)
+		}
+	}
+	if err := db.SaveTemplate(&models.Template{Name: "red-oaks", SourceIDs: []int64{oldID, id}}); err != nil {
+		t.Fatalf("SaveTemplate failed: %v", err)
+	}
+
+	usage, err := db.GetSourceUsage(id)
+	if err != nil {
+		t.Fatalf("GetSourceUsage failed: %v", err)
+	}
+	want := &SourceUsage{SourceID: id, Species: []string{"alba", "rubra"}, SupersededSources: []int64{oldID}, Templates: []string{"red-oaks"}}
+	if !reflect.DeepEqual(usage, want) {
+		t.Errorf("GetSourceUsage = %+v, want %+v", usage, want)
+	}
+
+	removed, err := db.DeleteSourceCascade(id)
+	if err != nil {
+		t.Fatalf("DeleteSourceCascade failed: %v", err)
+	}
+	if !reflect.DeepEqual(removed, want) {
+		t.Errorf("DeleteSourceCascade = %+v, want %+v", removed, want)
+	}
+
+	if source, _ := db.GetSource(id); source != nil {
+		t.Error("source still exists after cascade delete")
+	}
+	if ss, _ := db.GetSpeciesSources("alba"); len(ss) != 0 {
+		t.Errorf("alba still has %d species sources", len(ss))
+	}
+	if old, _ := db.GetSource(oldID); old.SupersededBy != nil {
+		t.Errorf("superseded_by = %d, want cleared", *old.SupersededBy)
+	}
+	if tmpl, _ := db.GetTemplate("red-oaks"); !reflect.DeepEqual(tmpl.SourceIDs, []int64{oldID}) {
+		t.Errorf("template source_ids = %v, want [%d]", tmpl.SourceIDs, oldID)
+	}
+
+	if usage, _ := db.GetSourceUsage(oldID); len(usage.Species)+len(usage.SupersededSources) != 0 || len(usage.Templates) != 1 {
+		t.Errorf("GetSourceUsage(old) = %+v, want only the template", usage)
+	}
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// Statuses reported for each row of a bulk species-source upsert
+const (
+	UpsertCreated   = "created"
+	UpsertUpdated   = "updated"
+	UpsertUnchanged = "unchanged"
+	UpsertFailed    = "failed"
+)
+
+// SpeciesSourceUpsert is one row of a bulk species-source upsert.
+// ContentHash is optional; when it matches the stored hash the row is left unchanged.
+type SpeciesSourceUpsert struct {
+	SpeciesSource *models.SpeciesSource
+	ContentHash   string
+}
+
+// SpeciesSourceUpsertResult is the outcome for one row of a bulk upsert
+type SpeciesSourceUpsertResult struct {
+	ScientificName string `json:"scientific_name"`
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+}
+
+// BulkUpsertSpeciesSources creates or replaces the given species' data for one source
+// in a single transaction. Every row's SourceID is set to sourceID. If any row fails,
+// nothing is written and applied is false; results report which rows failed.
+func (db *Database) BulkUpsertSpeciesSources(sourceID int64, rows []SpeciesSourceUpsert) (results []SpeciesSourceUpsertResult, applied bool, err error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	results = make([]SpeciesSourceUpsertResult, len(rows))
+	failed := false
+	for i, row := range rows {
+		ss := row.SpeciesSource
+		ss.SourceID = sourceID
+		results[i] = SpeciesSourceUpsertResult{ScientificName: ss.ScientificName}
+
+		status, rowErr, err := upsertSpeciesSourceTx(tx, ss, row.ContentHash)
+		if err != nil {
+			return nil, false, err
+		}
+		if rowErr != "" {
+			results[i].Status = UpsertFailed
+			results[i].Error = rowErr
+			failed = true
+			continue
+		}
+		results[i].Status = status
+	}
+
+	if failed {
+		return results, false, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit bulk upsert: %w", err)
+	}
+	return results, true, nil
+}
+
+// upsertSpeciesSourceTx writes one row of a bulk upsert. A non-empty rowErr
+// reports a problem with the row itself rather than a database failure.
+func upsertSpeciesSourceTx(tx *sql.Tx, ss *models.SpeciesSource, contentHash string) (status, rowErr string, err error) {
+	var exists int
+	err = tx.QueryRow(`SELECT 1 FROM oak_entries WHERE scientific_name = ?`, ss.ScientificName).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return "", fmt.Sprintf("species '%s' not found", ss.ScientificName), nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to check species existence: %w", err)
+	}
+
+	var storedHash, storedVisibility sql.NullString
+	err = tx.QueryRow(
+		`SELECT content_hash, field_visibility FROM species_sources WHERE scientific_name = ? AND source_id = ?`,
+		ss.ScientificName, ss.SourceID,
+	).Scan(&storedHash, &storedVisibility)
+	switch {
+	case err == sql.ErrNoRows:
+		status = UpsertCreated
+	case err != nil:
+		return "", "", fmt.Errorf("failed to get existing species source: %w", err)
+	case contentHash != "" && storedHash.String == contentHash:
+		return UpsertUnchanged, "", nil
+	default:
+		status = UpsertUpdated
+	}
+
+	// Rows that don't say which fields are internal keep them so, rather
+	// than publishing them by omission
+	if ss.FieldVisibility == nil && storedVisibility.String != "" {
+		if err := json.Unmarshal([]byte(storedVisibility.String), &ss.FieldVisibility); err != nil {
+			return "", "", fmt.Errorf("failed to unmarshal field_visibility for %s: %w", ss.ScientificName, err)
+		}
+	}
+
+	if err := saveSpeciesSource(tx, ss); err != nil {
+		return "", "", err
+	}
+	if contentHash != "" {
+		if _, err := tx.Exec(
+			`UPDATE species_sources SET content_hash = ? WHERE scientific_name = ? AND source_id = ?`,
+			contentHash, ss.ScientificName, ss.SourceID,
+		); err != nil {
+			return "", "", fmt.Errorf("failed to set content hash: %w", err)
+		}
+	}
+	return status, "", nil
+}
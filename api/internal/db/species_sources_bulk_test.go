@@ -0,0 +1,53 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestBulkUpsertSpeciesSources(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if err := db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	sourceID, err := db.InsertSource(&models.Source{SourceType: "website", Name: "Scraped"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+
+	rows := []SpeciesSourceUpsert{{SpeciesSource: models.NewSpeciesSource("alba", 0), ContentHash: "h1"}}
+	results, applied, err := db.BulkUpsertSpeciesSources(sourceID, rows)
+	if err != nil {
+		t.Fatalf("BulkUpsertSpeciesSources failed: %v", err)
+	}
+	if !applied || results[0].Status != UpsertCreated {
+		t.Fatalf("applied = %v, status = %s, want true, created", applied, results[0].Status)
+	}
+
+	hash, err := db.GetSpeciesSourceContentHash("alba", sourceID)
+	if err != nil {
+		t.Fatalf("GetSpeciesSourceContentHash failed: %v", err)
+	}
+	if hash != "h1" {
+		t.Errorf("content hash = %q, want h1", hash)
+	}
+
+	// Same hash is a no-op; a missing species fails the batch
+	rows = []SpeciesSourceUpsert{
+		{SpeciesSource: models.NewSpeciesSource("alba", 0), ContentHash: "h1"},
+		{SpeciesSource: models.NewSpeciesSource("missing", 0)},
+	}
+	results, applied, err = db.BulkUpsertSpeciesSources(sourceID, rows)
+	if err != nil {
+		t.Fatalf("BulkUpsertSpeciesSources failed: %v", err)
+	}
+	if applied {
+		t.Error("applied = true, want false for batch with a missing species")
+	}
+	if results[0].Status != UpsertUnchanged || results[1].Status != UpsertFailed {
+		t.Errorf("statuses = %s, %s, want unchanged, failed", results[0].Status, results[1].Status)
+	}
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// dayFormat is the UTC calendar day view counts are bucketed by
+const dayFormat = "2006-01-02"
+
+// SpeciesViews is a species' view count over a period
+type SpeciesViews struct {
+	ScientificName string `json:"scientific_name"`
+	Views          int    `json:"views"`
+	LastViewed     string `json:"last_viewed"` // UTC day, e.g. "2026-10-16"
+}
+
+// RecordSpeciesView adds one view of a species to the count for at's UTC day
+func (db *Database) RecordSpeciesView(scientificName string, at time.Time) error {
+	_, err := db.conn.Exec(
+		`INSERT INTO species_views (scientific_name, day, views) VALUES (?, ?, 1)
+		 ON CONFLICT(scientific_name, day) DO UPDATE SET views = views + 1`,
+		scientificName, at.UTC().Format(dayFormat),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record species view: %w", err)
+	}
+	return nil
+}
+
+// PopularSpecies returns the most-viewed species from since's UTC day onward,
+// most views first. A limit of 0 or less returns every viewed species.
+func (db *Database) PopularSpecies(since time.Time, limit int) ([]*SpeciesViews, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := db.conn.Query(
+		`SELECT scientific_name, SUM(views), MAX(day) FROM species_views
+		 WHERE day >= ?
+		 GROUP BY scientific_name
+		 ORDER BY SUM(views) DESC, scientific_name
+		 LIMIT ?`,
+		since.UTC().Format(dayFormat), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list popular species: %w", err)
+	}
+	defer rows.Close()
+
+	var popular []*SpeciesViews
+	for rows.Next() {
+		var v SpeciesViews
+		if err := rows.Scan(&v.ScientificName, &v.Views, &v.LastViewed); err != nil {
+			return nil, fmt.Errorf("failed to scan species views: %w", err)
+		}
+		popular = append(popular, &v)
+	}
+	return popular, rows.Err()
+}
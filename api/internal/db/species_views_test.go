@@ -0,0 +1,76 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestPopularSpecies(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	for _, name := range []string{"alba", "rubra", "velutina"} {
+		if err := db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatalf("SaveOakEntry(%s) failed: %v", name, err)
+		}
+	}
+
+	today := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+	lastMonth := today.AddDate(0, -1, 0)
+	views := []struct {
+		name string
+		at   time.Time
+	}{
+		{"alba", today}, {"alba", today.Add(-time.Hour)}, {"alba", today.AddDate(0, 0, -1)},
+		{"rubra", today}, {"rubra", lastMonth}, {"rubra", lastMonth}, {"rubra", lastMonth},
+		{"velutina", today.AddDate(0, 0, -2)},
+	}
+	for _, v := range views {
+		if err := db.RecordSpeciesView(v.name, v.at); err != nil {
+			t.Fatalf("RecordSpeciesView(%s) failed: %v", v.name, err)
+		}
+	}
+
+	popular, err := db.PopularSpecies(today.AddDate(0, 0, -6), 0)
+	if err != nil {
+		t.Fatalf("PopularSpecies failed: %v", err)
+	}
+	want := []SpeciesViews{
+		{ScientificName: "alba", Views: 3, LastViewed: "2026-10-16"},
+		{ScientificName: "rubra", Views: 1, LastViewed: "2026-10-16"},
+		{ScientificName: "velutina", Views: 1, LastViewed: "2026-10-14"},
+	}
+	if len(popular) != len(want) {
+		t.Fatalf("PopularSpecies returned %d species, want %d", len(popular), len(want))
+	}
+	for i, w := range want {
+		if *popular[i] != w {
+			t.Errorf("popular[%d] = %+v, want %+v", i, *popular[i], w)
+		}
+	}
+
+	// Older views count over a longer period; limit keeps the top entries
+	popular, err = db.PopularSpecies(lastMonth, 1)
+	if err != nil {
+		t.Fatalf("PopularSpecies failed: %v", err)
+	}
+	if len(popular) != 1 || popular[0].ScientificName != "rubra" || popular[0].Views != 4 {
+		t.Errorf("PopularSpecies(last month, 1) = %+v, want rubra with 4 views", popular)
+	}
+
+	// Deleting a species drops its counts
+	if err := db.DeleteOakEntry("alba"); err != nil {
+		t.Fatalf("DeleteOakEntry failed: %v", err)
+	}
+	popular, err = db.PopularSpecies(lastMonth, 0)
+	if err != nil {
+		t.Fatalf("PopularSpecies failed: %v", err)
+	}
+	for _, p := range popular {
+		if p.ScientificName == "alba" {
+			t.Errorf("deleted species alba still has views")
+		}
+	}
+}
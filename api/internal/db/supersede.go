@@ -0,0 +1,109 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// Actions reported for each species by MigrateSpeciesSources
+const (
+	MigrateCopy = "copy"
+	MigrateMove = "move"
+	MigrateSkip = "skip" // The target source already has data for the species
+)
+
+// SourceMigrationItem is the planned or applied action for one species
+type SourceMigrationItem struct {
+	ScientificName string `json:"scientific_name"`
+	Action         string `json:"action"`
+}
+
+// MigrateSpeciesSources copies (or, with move, moves) every species_sources row of
+// source from to source to. Species the target already describes are skipped and
+// left untouched. With dryRun the plan is returned without writing anything.
+func (db *Database) MigrateSpeciesSources(from, to int64, move, dryRun bool) ([]SourceMigrationItem, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	inTarget := make(map[string]bool)
+	targetRows, err := tx.Query(`SELECT scientific_name FROM species_sources WHERE source_id = ?`, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list target species: %w", err)
+	}
+	for targetRows.Next() {
+		var name string
+		if err := targetRows.Scan(&name); err != nil {
+			targetRows.Close()
+			return nil, fmt.Errorf("failed to scan target species: %w", err)
+		}
+		inTarget[name] = true
+	}
+	targetRows.Close()
+	if err := targetRows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(
+		`SELECT `+speciesSourceColumns+`
+		 FROM species_sources WHERE source_id = ? ORDER BY scientific_name`,
+		from,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list species sources: %w", err)
+	}
+
+	action := MigrateCopy
+	if move {
+		action = MigrateMove
+	}
+
+	items := []SourceMigrationItem{}
+	var pending []*models.SpeciesSource
+	for rows.Next() {
+		ss, err := scanSpeciesSource(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if inTarget[ss.ScientificName] {
+			items = append(items, SourceMigrationItem{ScientificName: ss.ScientificName, Action: MigrateSkip})
+			continue
+		}
+		items = append(items, SourceMigrationItem{ScientificName: ss.ScientificName, Action: action})
+		pending = append(pending, ss)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return items, nil
+	}
+
+	for _, ss := range pending {
+		if move {
+			if _, err := tx.Exec(
+				`DELETE FROM species_sources WHERE scientific_name = ? AND source_id = ?`,
+				ss.ScientificName, from,
+			); err != nil {
+				return nil, fmt.Errorf("failed to remove %s from source %d: %w", ss.ScientificName, from, err)
+			}
+		}
+		// A preferred row stays preferred under the new source
+		ss.ID = 0
+		ss.SourceID = to
+		if err := saveSpeciesSource(tx, ss); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit source migration: %w", err)
+	}
+	return items, nil
+}
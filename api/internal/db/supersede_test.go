@@ -0,0 +1,91 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestSourceSupersededBy(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	oldID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Flora, 1st ed."})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+	newID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Flora, 2nd ed."})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+
+	old, err := db.GetSource(oldID)
+	if err != nil {
+		t.Fatalf("GetSource failed: %v", err)
+	}
+	old.SupersededBy = &newID
+	if err := db.UpdateSource(old); err != nil {
+		t.Fatalf("UpdateSource failed: %v", err)
+	}
+
+	got, err := db.GetSource(oldID)
+	if err != nil {
+		t.Fatalf("GetSource failed: %v", err)
+	}
+	if got.SupersededBy == nil || *got.SupersededBy != newID {
+		t.Errorf("SupersededBy = %v, want %d", got.SupersededBy, newID)
+	}
+}
+
+func TestMigrateSpeciesSources(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	for _, name := range []string{"alba", "rubra"} {
+		if err := db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatalf("SaveOakEntry failed: %v", err)
+		}
+	}
+	oldID, _ := db.InsertSource(&models.Source{SourceType: "book", Name: "Old"})
+	newID, _ := db.InsertSource(&models.Source{SourceType: "book", Name: "New"})
+
+	leaves := "Lobed"
+	alba := models.NewSpeciesSource("alba", oldID)
+	alba.Leaves = &leaves
+	alba.IsPreferred = true
+	for _, ss := range []*models.SpeciesSource{alba, models.NewSpeciesSource("rubra", oldID), models.NewSpeciesSource("rubra", newID)} {
+		if err := db.SaveSpeciesSource(ss); err != nil {
+			t.Fatalf("SaveSpeciesSource failed: %v", err)
+		}
+	}
+
+	// Dry run writes nothing
+	items, err := db.MigrateSpeciesSources(oldID, newID, true, true)
+	if err != nil {
+		t.Fatalf("MigrateSpeciesSources(dry run) failed: %v", err)
+	}
+	if len(items) != 2 || items[0].Action != MigrateMove || items[1].Action != MigrateSkip {
+		t.Fatalf("plan = %+v, want alba move, rubra skip", items)
+	}
+	if ss, _ := db.GetSpeciesSourceBySourceID("alba", newID); ss != nil {
+		t.Fatal("dry run wrote to the target source")
+	}
+
+	if _, err := db.MigrateSpeciesSources(oldID, newID, true, false); err != nil {
+		t.Fatalf("MigrateSpeciesSources failed: %v", err)
+	}
+
+	moved, err := db.GetSpeciesSourceBySourceID("alba", newID)
+	if err != nil {
+		t.Fatalf("GetSpeciesSourceBySourceID failed: %v", err)
+	}
+	if moved == nil || moved.Leaves == nil || *moved.Leaves != "Lobed" || !moved.IsPreferred {
+		t.Errorf("moved = %+v, want alba's data, preferred", moved)
+	}
+	if ss, _ := db.GetSpeciesSourceBySourceID("alba", oldID); ss != nil {
+		t.Error("move left alba on the old source")
+	}
+	if ss, _ := db.GetSpeciesSourceBySourceID("rubra", oldID); ss == nil {
+		t.Error("skipped species should stay on the old source")
+	}
+}
@@ -0,0 +1,123 @@
+package db
+
+import "fmt"
+
+// liveSpeciesCount counts the species placed in taxon t: entries of t's genus
+// whose column for t's level (taxon_levels.entry_field) names it. It scans
+// oak_entries, so reads use the counts stored in taxa_counts instead.
+const liveSpeciesCount = `(SELECT COUNT(*) FROM oak_entries o JOIN taxon_levels l ON l.name = t.level WHERE o.genus = t.genus AND (
+	(l.entry_field = 'subgenus' AND o.subgenus = t.name) OR
+	(l.entry_field = 'section' AND o.section = t.name) OR
+	(l.entry_field = 'subsection' AND o.subsection = t.name) OR
+	(l.entry_field = 'complex' AND o.complex = t.name)
+))`
+
+// cachedSpeciesCount reads taxon t's stored count, computing it live for a
+// taxon that has none.
+const cachedSpeciesCount = `COALESCE((SELECT c.species_count FROM taxa_counts c WHERE c.name = t.name AND c.level = t.level), ` + liveSpeciesCount + `)`
+
+// speciesCountColumn returns the species_count expression for taxa queries:
+// the stored count, or the live one when fresh.
+func speciesCountColumn(fresh bool) string {
+	if fresh {
+		return liveSpeciesCount
+	}
+	return cachedSpeciesCount
+}
+
+// recountTaxa stores the live count of the taxa matching a condition on t
+func recountTaxa(where string) string {
+	return `INSERT OR REPLACE INTO taxa_counts (name, level, species_count)
+		SELECT t.name, t.level, ` + liveSpeciesCount + ` FROM taxa t WHERE ` + where + `;`
+}
+
+// recountEntryTaxa recounts the taxa an entry row (NEW or OLD) is placed in
+func recountEntryTaxa(row string) string {
+	return recountTaxa(fmt.Sprintf(`t.genus = %[1]s.genus AND t.name IN (%[1]s.subgenus, %[1]s.section, %[1]s.subsection, %[1]s.complex)`, row))
+}
+
+// fillMissingTaxaCounts stores the live count of taxa that have none
+var fillMissingTaxaCounts = recountTaxa(`NOT EXISTS (SELECT 1 FROM taxa_counts c WHERE c.name = t.name AND c.level = t.level)`)
+
+// taxaCountTriggers keep taxa_counts current on every path that writes
+// entries, taxa, or taxon levels. Only the taxa a write can affect are
+// recounted.
+//
+// SaveOakEntry writes with INSERT OR REPLACE, which removes the old row
+// without firing delete triggers, so the insert triggers drop the counts of
+// the replaced row's taxa first and fill them in afterwards.
+var taxaCountTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_taxa_counts_replace
+		BEFORE INSERT ON oak_entries
+		BEGIN
+			DELETE FROM taxa_counts WHERE EXISTS (SELECT 1 FROM oak_entries o WHERE o.scientific_name = NEW.scientific_name
+				AND taxa_counts.name IN (o.subgenus, o.section, o.subsection, o.complex));
+		END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_taxa_counts_insert
+		AFTER INSERT ON oak_entries
+		BEGIN ` + recountEntryTaxa("NEW") + ` ` + fillMissingTaxaCounts + ` END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_taxa_counts_update
+		AFTER UPDATE OF genus, subgenus, section, subsection, complex ON oak_entries
+		BEGIN ` + recountEntryTaxa("OLD") + ` ` + recountEntryTaxa("NEW") + ` END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_oak_entries_taxa_counts_delete
+		AFTER DELETE ON oak_entries
+		BEGIN ` + recountEntryTaxa("OLD") + ` END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_taxa_taxa_counts_insert
+		AFTER INSERT ON taxa
+		BEGIN ` + recountTaxa(`t.name = NEW.name AND t.level = NEW.level`) + ` END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_taxa_taxa_counts_update
+		AFTER UPDATE OF name, level, genus ON taxa
+		BEGIN
+			DELETE FROM taxa_counts WHERE name = OLD.name AND level = OLD.level;
+			` + recountTaxa(`t.name = NEW.name AND t.level = NEW.level`) + `
+		END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_taxa_taxa_counts_delete
+		AFTER DELETE ON taxa
+		BEGIN DELETE FROM taxa_counts WHERE name = OLD.name AND level = OLD.level; END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_taxon_levels_taxa_counts_insert
+		AFTER INSERT ON taxon_levels
+		BEGIN ` + recountTaxa(`t.level = NEW.name`) + ` END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_taxon_levels_taxa_counts_update
+		AFTER UPDATE OF name, entry_field ON taxon_levels
+		BEGIN
+			DELETE FROM taxa_counts WHERE level = OLD.name;
+			` + recountTaxa(`t.level = NEW.name`) + `
+		END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_taxon_levels_taxa_counts_delete
+		AFTER DELETE ON taxon_levels
+		BEGIN DELETE FROM taxa_counts WHERE level = OLD.name; END`,
+}
+
+// fillTaxaCounts stores counts for taxa that have none, such as those
+// written before the triggers existed
+func (db *Database) fillTaxaCounts() error {
+	if _, err := db.conn.Exec(fillMissingTaxaCounts); err != nil {
+		return fmt.Errorf("failed to fill taxa counts: %w", err)
+	}
+	return nil
+}
+
+// rebuildTaxaCounts recounts the species in every taxon. Returns the number
+// of taxa whose stored count was missing or wrong.
+func (db *Database) rebuildTaxaCounts() (int, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var stale int
+	if err := tx.QueryRow(
+		`SELECT COUNT(*) FROM taxa t WHERE ` + liveSpeciesCount + ` IS NOT
+		 (SELECT c.species_count FROM taxa_counts c WHERE c.name = t.name AND c.level = t.level)`,
+	).Scan(&stale); err != nil {
+		return 0, fmt.Errorf("failed to check taxa counts: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM taxa_counts`); err != nil {
+		return 0, fmt.Errorf("failed to clear taxa counts: %w", err)
+	}
+	if _, err := tx.Exec(recountTaxa(`1`)); err != nil {
+		return 0, fmt.Errorf("failed to recount taxa: %w", err)
+	}
+	return stale, tx.Commit()
+}
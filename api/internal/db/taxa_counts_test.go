@@ -0,0 +1,78 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestTaxaCounts(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	for _, name := range []string{"Quercus", "Lobatae"} {
+		if err := db.InsertTaxon(&models.Taxon{Name: name, Level: models.TaxonLevelSection}); err != nil {
+			t.Fatalf("InsertTaxon(%s) failed: %v", name, err)
+		}
+	}
+	section := "Quercus"
+	for _, name := range []string{"alba", "macrocarpa"} {
+		e := models.NewOakEntry(name)
+		e.Section = &section
+		if err := db.SaveOakEntry(e); err != nil {
+			t.Fatalf("SaveOakEntry(%s) failed: %v", name, err)
+		}
+	}
+
+	stored := func(name string) int {
+		t.Helper()
+		var n int
+		if err := db.conn.QueryRow(`SELECT species_count FROM taxa_counts WHERE name = ? AND level = 'section'`, name).Scan(&n); err != nil {
+			t.Fatalf("stored count of %s: %v", name, err)
+		}
+		return n
+	}
+	if got := stored("Quercus"); got != 2 {
+		t.Errorf("Quercus count = %d, want 2", got)
+	}
+
+	// Moving an entry (saved by replacing its row) recounts both sections
+	red := "Lobatae"
+	moved := models.NewOakEntry("macrocarpa")
+	moved.Section = &red
+	if err := db.SaveOakEntry(moved); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	if got, want := [2]int{stored("Quercus"), stored("Lobatae")}, [2]int{1, 1}; got != want {
+		t.Errorf("counts after move = %v, want %v", got, want)
+	}
+	if err := db.DeleteOakEntry("alba"); err != nil {
+		t.Fatalf("DeleteOakEntry failed: %v", err)
+	}
+	if got := stored("Quercus"); got != 0 {
+		t.Errorf("Quercus count after delete = %d, want 0", got)
+	}
+
+	// Reads use the stored count; fresh ones count live
+	if _, err := db.conn.Exec(`UPDATE taxa_counts SET species_count = 9 WHERE name = 'Lobatae'`); err != nil {
+		t.Fatal(err)
+	}
+	level := models.TaxonLevelSection
+	taxa, err := db.ListTaxa(&TaxaListParams{Level: &level})
+	if err != nil || len(taxa) != 2 || taxa[0].Name != "Lobatae" || taxa[0].SpeciesCount != 9 {
+		t.Fatalf("ListTaxa = %v, %v; want the stored count 9 for Lobatae", taxa, err)
+	}
+	fresh, err := db.GetTaxonFresh("Lobatae", level)
+	if err != nil || fresh.SpeciesCount != 1 {
+		t.Fatalf("GetTaxonFresh = %+v, %v; want a live count of 1", fresh, err)
+	}
+
+	// Reindex repairs the stored count
+	stale, err := db.rebuildTaxaCounts()
+	if err != nil {
+		t.Fatalf("rebuildTaxaCounts failed: %v", err)
+	}
+	if stale != 1 || stored("Lobatae") != 1 {
+		t.Errorf("rebuild fixed %d taxa, Lobatae = %d; want 1 and 1", stale, stored("Lobatae"))
+	}
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+const taxonLevelsSelect = `SELECT l.name, l.rank, l.plural, l.entry_field,
+	       (SELECT COUNT(*) FROM taxa t WHERE t.level = l.name) as taxa_count
+	FROM taxon_levels l`
+
+// ListTaxonLevels returns the configured taxon levels ordered by rank
+func (db *Database) ListTaxonLevels() ([]*models.TaxonLevelDef, error) {
+	rows, err := db.conn.Query(taxonLevelsSelect + ` ORDER BY l.rank`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list taxon levels: %w", err)
+	}
+	defer rows.Close()
+
+	var levels []*models.TaxonLevelDef
+	for rows.Next() {
+		l, err := scanTaxonLevel(rows)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, l)
+	}
+	return levels, rows.Err()
+}
+
+// GetTaxonLevel gets a taxon level by name, or nil if it is not configured
+func (db *Database) GetTaxonLevel(name string) (*models.TaxonLevelDef, error) {
+	l, err := scanTaxonLevel(db.conn.QueryRow(taxonLevelsSelect+` WHERE l.name = ?`, name))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return l, err
+}
+
+// SaveTaxonLevel creates or replaces a taxon level. Ranks must be unique.
+func (db *Database) SaveTaxonLevel(level *models.TaxonLevelDef) error {
+	_, err := db.conn.Exec(
+		`INSERT INTO taxon_levels (name, rank, plural, entry_field) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(name) DO UPDATE SET rank = excluded.rank, plural = excluded.plural, entry_field = excluded.entry_field`,
+		level.Name, level.Rank, level.Plural, level.EntryField,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save taxon level: %w", err)
+	}
+	return nil
+}
+
+// DeleteTaxonLevel deletes a taxon level. Returns false if it does not exist.
+// Callers should check TaxaCount first; taxa at the level are not removed.
+func (db *Database) DeleteTaxonLevel(name string) (bool, error) {
+	result, err := db.conn.Exec(`DELETE FROM taxon_levels WHERE name = ?`, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete taxon level: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// scanTaxonLevel scans one row of taxonLevelsSelect
+func scanTaxonLevel(row interface{ Scan(...interface{}) error }) (*models.TaxonLevelDef, error) {
+	var l models.TaxonLevelDef
+	if err := row.Scan(&l.Name, &l.Rank, &l.Plural, &l.EntryField, &l.TaxaCount); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan taxon level: %w", err)
+	}
+	return &l, nil
+}
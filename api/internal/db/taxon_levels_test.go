@@ -0,0 +1,127 @@
+package db
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestTaxonLevels(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	// The four default levels are seeded in rank order
+	levels, err := db.ListTaxonLevels()
+	if err != nil {
+		t.Fatalf("ListTaxonLevels failed: %v", err)
+	}
+	want := []string{"subgenus", "section", "subsection", "complex"}
+	if len(levels) != len(want) {
+		t.Fatalf("got %d levels, want %d", len(levels), len(want))
+	}
+	for i, l := range levels {
+		if l.Name != want[i] {
+			t.Errorf("level[%d] = %s, want %s", i, l.Name, want[i])
+		}
+	}
+
+	// Unknown levels are rejected until configured
+	series := &models.Taxon{Name: "Glandulosae", Level: "series"}
+	if err := db.InsertTaxon(series); err == nil {
+		t.Error("expected insert at unknown level to fail")
+	}
+	plural := "series"
+	if err := db.SaveTaxonLevel(&models.TaxonLevelDef{Name: "series", Rank: 5, Plural: &plural}); err != nil {
+		t.Fatalf("SaveTaxonLevel failed: %v", err)
+	}
+	if err := db.InsertTaxon(series); err != nil {
+		t.Fatalf("InsertTaxon at configured level failed: %v", err)
+	}
+
+	// Ranks are unique
+	if err := db.SaveTaxonLevel(&models.TaxonLevelDef{Name: "grex", Rank: 5}); err == nil {
+		t.Error("expected duplicate rank to fail")
+	}
+
+	got, err := db.GetTaxonLevel("series")
+	if err != nil || got == nil {
+		t.Fatalf("GetTaxonLevel = %v, %v", got, err)
+	}
+	if got.TaxaCount != 1 || got.EntryField != nil {
+		t.Errorf("series = %+v, want 1 taxon and no entry field", got)
+	}
+
+	// Species counts follow entry_field; levels without one count nothing
+	entry := models.NewOakEntry("alba")
+	section := "Quercus"
+	entry.Section = &section
+	if err := db.SaveOakEntry(entry); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	if err := db.InsertTaxon(&models.Taxon{Name: "Quercus", Level: models.TaxonLevelSection}); err != nil {
+		t.Fatalf("InsertTaxon failed: %v", err)
+	}
+	taxon, err := db.GetTaxon("Quercus", models.TaxonLevelSection)
+	if err != nil {
+		t.Fatalf("GetTaxon failed: %v", err)
+	}
+	if taxon.SpeciesCount != 1 {
+		t.Errorf("section species count = %d, want 1", taxon.SpeciesCount)
+	}
+
+	if ok, err := db.DeleteTaxonLevel("grex"); err != nil || ok {
+		t.Errorf("DeleteTaxonLevel(missing) = %v, %v", ok, err)
+	}
+	if ok, err := db.DeleteTaxonLevel("series"); err != nil || !ok {
+		t.Errorf("DeleteTaxonLevel(series) = %v, %v", ok, err)
+	}
+}
+
+func TestTaxaLevelCheckMigration(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy.db")
+
+	// A taxa table from before levels were configurable
+	conn, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	stmts := []string{
+		`CREATE TABLE taxa (
+			name TEXT NOT NULL,
+			level TEXT NOT NULL CHECK(level IN ('subgenus', 'section', 'subsection', 'complex')),
+			parent TEXT,
+			author TEXT,
+			notes TEXT,
+			links TEXT,
+			PRIMARY KEY (name, level)
+		)`,
+		`INSERT INTO taxa (name, level) VALUES ('Quercus', 'subgenus')`,
+	}
+	for _, stmt := range stmts {
+		if _, err := conn.Exec(stmt); err != nil {
+			t.Fatalf("failed to create legacy schema: %v", err)
+		}
+	}
+	conn.Close()
+
+	db, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer db.Close()
+
+	if taxon, err := db.GetTaxon("Quercus", models.TaxonLevelSubgenus); err != nil || taxon == nil {
+		t.Fatalf("legacy taxon lost: %v, %v", taxon, err)
+	}
+	if err := db.SaveTaxonLevel(&models.TaxonLevelDef{Name: "series", Rank: 5}); err != nil {
+		t.Fatalf("SaveTaxonLevel failed: %v", err)
+	}
+	if err := db.InsertTaxon(&models.Taxon{Name: "Glandulosae", Level: "series"}); err != nil {
+		t.Errorf("insert at new level after migration failed: %v", err)
+	}
+	if err := db.InsertTaxon(&models.Taxon{Name: "Bogus", Level: "tribe"}); err == nil {
+		t.Error("expected insert at unknown level to fail after migration")
+	}
+}
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+const templatesSelect = `SELECT name, description, genus, subgenus, section, subsection, complex,
+	       source_ids, required_fields
+	FROM templates`
+
+// ListTemplates returns every entry template, ordered by name
+func (db *Database) ListTemplates() ([]*models.Template, error) {
+	rows, err := db.conn.Query(templatesSelect + ` ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*models.Template
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+// GetTemplate gets a template by name, or nil if it does not exist
+func (db *Database) GetTemplate(name string) (*models.Template, error) {
+	t, err := scanTemplate(db.conn.QueryRow(templatesSelect+` WHERE name = ?`, name))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return t, err
+}
+
+// SaveTemplate creates or replaces a template
+func (db *Database) SaveTemplate(t *models.Template) error {
+	sourceIDs := t.SourceIDs
+	if sourceIDs == nil {
+		sourceIDs = []int64{}
+	}
+	sourceIDsJSON, err := json.Marshal(sourceIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal source ids: %w", err)
+	}
+	required := t.RequiredFields
+	if required == nil {
+		required = []string{}
+	}
+	requiredJSON, err := json.Marshal(required)
+	if err != nil {
+		return fmt.Errorf("failed to marshal required fields: %w", err)
+	}
+
+	genus := t.Genus
+	if genus == "" {
+		genus = models.DefaultGenus
+	}
+
+	_, err = db.conn.Exec(
+		`INSERT OR REPLACE INTO templates
+		 (name, description, genus, subgenus, section, subsection, complex, source_ids, required_fields)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.Name, t.Description, genus, t.Subgenus, t.Section, t.Subsection, t.Complex,
+		string(sourceIDsJSON), string(requiredJSON),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save template: %w", err)
+	}
+	return nil
+}
+
+// DeleteTemplate deletes a template. Returns false if it does not exist.
+func (db *Database) DeleteTemplate(name string) (bool, error) {
+	result, err := db.conn.Exec(`DELETE FROM templates WHERE name = ?`, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete template: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// scanTemplate scans one row of templatesSelect
+func scanTemplate(row interface{ Scan(...interface{}) error }) (*models.Template, error) {
+	var t models.Template
+	var sourceIDsJSON, requiredJSON sql.NullString
+	if err := row.Scan(&t.Name, &t.Description, &t.Genus, &t.Subgenus, &t.Section, &t.Subsection, &t.Complex,
+		&sourceIDsJSON, &requiredJSON); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan template: %w", err)
+	}
+	if sourceIDsJSON.Valid && sourceIDsJSON.String != "" {
+		if err := json.Unmarshal([]byte(sourceIDsJSON.String), &t.SourceIDs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal source ids for %s: %w", t.Name, err)
+		}
+	}
+	if requiredJSON.Valid && requiredJSON.String != "" {
+		if err := json.Unmarshal([]byte(requiredJSON.String), &t.RequiredFields); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal required fields for %s: %w", t.Name, err)
+		}
+	}
+	if t.SourceIDs == nil {
+		t.SourceIDs = []int64{}
+	}
+	if t.RequiredFields == nil {
+		t.RequiredFields = []string{}
+	}
+	return &t, nil
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestTemplates(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	subgenus, section := "Quercus", "Lobatae"
+	tmpl := &models.Template{
+		Name:           "lobatae",
+		Subgenus:       &subgenus,
+		Section:        &section,
+		SourceIDs:      []int64{2, 1},
+		RequiredFields: []string{"author"},
+	}
+	if err := db.SaveTemplate(tmpl); err != nil {
+		t.Fatalf("SaveTemplate failed: %v", err)
+	}
+
+	got, err := db.GetTemplate("lobatae")
+	if err != nil || got == nil {
+		t.Fatalf("GetTemplate = %v, %v", got, err)
+	}
+	if got.Genus != models.DefaultGenus {
+		t.Errorf("genus = %q, want %q", got.Genus, models.DefaultGenus)
+	}
+	if got.Section == nil || *got.Section != "Lobatae" {
+		t.Errorf("section = %v, want Lobatae", got.Section)
+	}
+	if len(got.SourceIDs) != 2 || got.SourceIDs[0] != 2 {
+		t.Errorf("source ids = %v, want [2 1]", got.SourceIDs)
+	}
+	if len(got.RequiredFields) != 1 || got.RequiredFields[0] != "author" {
+		t.Errorf("required fields = %v, want [author]", got.RequiredFields)
+	}
+
+	// Saving again replaces the template
+	tmpl.SourceIDs = nil
+	tmpl.Section = nil
+	if err := db.SaveTemplate(tmpl); err != nil {
+		t.Fatalf("SaveTemplate failed: %v", err)
+	}
+	got, err = db.GetTemplate("lobatae")
+	if err != nil {
+		t.Fatalf("GetTemplate failed: %v", err)
+	}
+	if got.Section != nil || len(got.SourceIDs) != 0 {
+		t.Errorf("replaced template = %+v", got)
+	}
+
+	list, err := db.ListTemplates()
+	if err != nil || len(list) != 1 {
+		t.Fatalf("ListTemplates = %d, %v", len(list), err)
+	}
+
+	if ok, err := db.DeleteTemplate("lobatae"); err != nil || !ok {
+		t.Errorf("DeleteTemplate = %v, %v", ok, err)
+	}
+	if got, _ := db.GetTemplate("lobatae"); got != nil {
+		t.Error("expected template to be deleted")
+	}
+}
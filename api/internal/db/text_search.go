@@ -0,0 +1,218 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/api/internal/names"
+)
+
+// textSearchContext is how many bytes of a long line a text match keeps on
+// each side of its first occurrence
+const textSearchContext = 80
+
+// textSearchEllipsis marks where a long line was shortened
+const textSearchEllipsis = "…"
+
+// SearchText returns the lines of species accounts and source text that
+// contain query, ignoring case, diacritics, and runs of whitespace like name
+// search does. Matches are ordered by species, account before sources, then
+// by source, field, and line. At most limit are returned, with the number found.
+// Draft species and internal fields are searched only if includeHidden.
+func (db *Database) SearchText(query string, limit int, includeHidden bool) ([]*models.TextMatch, int, error) {
+	key, _, _ := foldText(query)
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return []*models.TextMatch{}, 0, nil
+	}
+	pattern := likeKey(query)
+
+	bySpecies := make(map[string][]*models.TextMatch)
+	add := func(scientificName, location, text string) {
+		for i, line := range strings.Split(text, "\n") {
+			if m := matchLine(strings.TrimRight(line, "\r"), key); m != nil {
+				m.ScientificName = scientificName
+				m.Location = location
+				m.Line = i + 1
+				bySpecies[scientificName] = append(bySpecies[scientificName], m)
+			}
+		}
+	}
+
+	accountRows, err := db.conn.Query(
+		`SELECT a.scientific_name, a.body FROM species_accounts a
+		 JOIN oak_entries o ON o.scientific_name = a.scientific_name
+		 WHERE name_key(a.body) LIKE ? ESCAPE '\' AND (? OR o.visibility = ?)`,
+		pattern, includeHidden, models.VisibilityPublished,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search accounts: %w", err)
+	}
+	defer accountRows.Close()
+	for accountRows.Next() {
+		var name, body string
+		if err := accountRows.Scan(&name, &body); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan account: %w", err)
+		}
+		add(name, "account", body)
+	}
+	if err := accountRows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	fields := (&models.SpeciesSource{}).TextFields()
+	columns := make([]string, len(fields))
+	conditions := make([]string, len(fields))
+	args := make([]interface{}, 0, len(fields)+2)
+	for i, f := range fields {
+		columns[i] = "ss." + f.Name
+		conditions[i] = "name_key(ss." + f.Name + `) LIKE ? ESCAPE '\'`
+		args = append(args, pattern)
+	}
+	args = append(args, includeHidden, models.VisibilityPublished)
+
+	sourceRows, err := db.conn.Query(
+		`SELECT ss.scientific_name, ss.source_id, ss.field_visibility, `+strings.Join(columns, ", ")+` FROM species_sources ss
+		 JOIN oak_entries o ON o.scientific_name = ss.scientific_name
+		 WHERE (`+strings.Join(conditions, " OR ")+`) AND (? OR o.visibility = ?)
+		 ORDER BY ss.scientific_name, ss.source_id`,
+		args...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search source text: %w", err)
+	}
+	defer sourceRows.Close()
+	for sourceRows.Next() {
+		var name string
+		var sourceID int64
+		var visibilityJSON sql.NullString
+		values := make([]sql.NullString, len(fields))
+		dest := []interface{}{&name, &sourceID, &visibilityJSON}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := sourceRows.Scan(dest...); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan source text: %w", err)
+		}
+		var visibility map[string]string
+		if visibilityJSON.String != "" {
+			if err := json.Unmarshal([]byte(visibilityJSON.String), &visibility); err != nil {
+				return nil, 0, fmt.Errorf("failed to unmarshal field_visibility for %s: %w", name, err)
+			}
+		}
+		for i, f := range fields {
+			if values[i].Valid && (includeHidden || visibility[f.Name] != models.FieldInternal) {
+				add(name, models.TextLocation(sourceID, f.Name), values[i].String)
+			}
+		}
+	}
+	if err := sourceRows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	speciesNames := make([]string, 0, len(bySpecies))
+	for name := range bySpecies {
+		speciesNames = append(speciesNames, name)
+	}
+	sort.Strings(speciesNames)
+
+	matches := []*models.TextMatch{}
+	total := 0
+	for _, name := range speciesNames {
+		for _, m := range bySpecies[name] {
+			if total < limit {
+				matches = append(matches, m)
+			}
+			total++
+		}
+	}
+	return matches, total, nil
+}
+
+// matchLine finds the occurrences of key, a folded query, in line. It
+// returns nil if there are none.
+func matchLine(line, key string) *models.TextMatch {
+	folded, starts, ends := foldText(line)
+	var spans []models.TextSpan
+	for from := 0; from <= len(folded)-len(key); {
+		i := strings.Index(folded[from:], key)
+		if i < 0 {
+			break
+		}
+		i += from
+		spans = append(spans, models.TextSpan{Start: starts[i], End: ends[i+len(key)-1]})
+		from = i + len(key)
+	}
+	if spans == nil {
+		return nil
+	}
+
+	// Keep a window around the first occurrence of a long line
+	start, end := 0, len(line)
+	if end > spans[0].End-spans[0].Start+2*textSearchContext {
+		start = max(0, spans[0].Start-textSearchContext)
+		end = min(len(line), spans[0].End+textSearchContext)
+		for start > 0 && !utf8.RuneStart(line[start]) {
+			start++
+		}
+		for end < len(line) && !utf8.RuneStart(line[end]) {
+			end++
+		}
+	}
+	text := line[start:end]
+	shift := -start
+	if start > 0 {
+		text = textSearchEllipsis + text
+		shift += len(textSearchEllipsis)
+	}
+	if end < len(line) {
+		text += textSearchEllipsis
+	}
+
+	m := &models.TextMatch{Text: text, Matches: []models.TextSpan{}}
+	for _, s := range spans {
+		if s.Start >= start && s.End <= end {
+			m.Matches = append(m.Matches, models.TextSpan{Start: s.Start + shift, End: s.End + shift})
+		}
+	}
+	return m
+}
+
+// foldText returns s folded for text search: lowercased, diacritics removed
+// as names.Key removes them, and whitespace runs collapsed to one space. For
+// each byte of the result, starts and ends give the byte range in s of the
+// character it came from.
+func foldText(s string) (folded string, starts, ends []int) {
+	var b strings.Builder
+	space := false
+	for i, r := range s {
+		var f string
+		switch {
+		case unicode.IsSpace(r):
+			if space {
+				continue
+			}
+			f = " "
+		case r < utf8.RuneSelf:
+			f = string(unicode.ToLower(r))
+		default:
+			f = names.Key(string(r))
+		}
+		if f == "" {
+			continue // A combining mark, removed with its diacritic
+		}
+		space = f == " "
+		b.WriteString(f)
+		for range len(f) {
+			starts = append(starts, i)
+			ends = append(ends, i+utf8.RuneLen(r))
+		}
+	}
+	return b.String(), starts, ends
+}
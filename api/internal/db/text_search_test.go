@@ -0,0 +1,92 @@
+package db
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestSearchText(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	draft := models.NewOakEntry("draftii")
+	draft.Visibility = models.VisibilityDraft
+	for _, e := range []*models.OakEntry{models.NewOakEntry("alba"), models.NewOakEntry("stellata"), draft} {
+		if err := db.SaveOakEntry(e); err != nil {
+			t.Fatalf("SaveOakEntry failed: %v", err)
+		}
+	}
+	sourceID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Flora"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+
+	save := func(name, leaves, twigs string) {
+		ss := models.NewSpeciesSource(name, sourceID)
+		ss.Leaves = &leaves
+		ss.Twigs = &twigs
+		if err := db.SaveSpeciesSource(ss); err != nil {
+			t.Fatalf("SaveSpeciesSource failed: %v", err)
+		}
+	}
+	save("stellata", "Blades cruciform.\nAbaxially with STELLATE  hairs, stellate hairs on veins.", "Twigs with stellate hairs.")
+	save("alba", "Glabrous.", "Glabrous.")
+	save("draftii", "Stellate hairs.", "")
+	if _, err := db.SaveSpeciesAccount(&models.SpeciesAccount{ScientificName: "alba", Markdown: "Unlike [[stellata]], it lacks stellate hairs."}); err != nil {
+		t.Fatalf("SaveSpeciesAccount failed: %v", err)
+	}
+
+	matches, total, err := db.SearchText("stellate hairs", 10, false)
+	if err != nil {
+		t.Fatalf("SearchText failed: %v", err)
+	}
+	if total != 3 || len(matches) != 3 {
+		t.Fatalf("SearchText found %d (%d returned), want 3", total, len(matches))
+	}
+	want := []string{"alba:account:1", "stellata:sources/1/leaves:2", "stellata:sources/1/twigs:1"}
+	for i, m := range matches {
+		if got := m.ScientificName + ":" + m.Location + ":" + strconv.Itoa(m.Line); got != want[i] {
+			t.Errorf("matches[%d] = %s, want %s", i, got, want[i])
+		}
+	}
+
+	// Both occurrences are located, the first despite its case and spacing
+	leaves := matches[1]
+	if len(leaves.Matches) != 2 {
+		t.Fatalf("leaves matches = %+v, want 2", leaves.Matches)
+	}
+	for _, span := range leaves.Matches {
+		if got := strings.ToLower(leaves.Text[span.Start:span.End]); got != "stellate  hairs" && got != "stellate hairs" {
+			t.Errorf("span %+v = %q, want stellate hairs", span, got)
+		}
+	}
+
+	// Drafts are searched only when asked for
+	if _, total, _ := db.SearchText("stellate hairs", 10, true); total != 4 {
+		t.Errorf("SearchText with drafts found %d, want 4", total)
+	}
+	// The limit caps what is returned, not what is counted
+	if matches, total, _ := db.SearchText("stellate", 1, false); len(matches) != 1 || total != 3 {
+		t.Errorf("SearchText limit 1 = %d of %d, want 1 of 3", len(matches), total)
+	}
+	if matches, _, _ := db.SearchText("glábrous", 10, false); len(matches) != 2 {
+		t.Errorf("diacritic-insensitive search found %d, want 2", len(matches))
+	}
+}
+
+func TestMatchLineWindow(t *testing.T) {
+	line := strings.Repeat("long text ", 30) + "the néedle" + strings.Repeat(" more text", 30)
+	m := matchLine(line, "needle")
+	if m == nil || len(m.Matches) != 1 {
+		t.Fatalf("matchLine = %+v, want one match", m)
+	}
+	if !strings.HasPrefix(m.Text, textSearchEllipsis) || !strings.HasSuffix(m.Text, textSearchEllipsis) {
+		t.Errorf("Text = %q, want shortened at both ends", m.Text)
+	}
+	if got := m.Text[m.Matches[0].Start:m.Matches[0].End]; got != "néedle" {
+		t.Errorf("match = %q, want néedle", got)
+	}
+}
@@ -0,0 +1,275 @@
+package db
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// Timeline event kinds
+const (
+	EventEdited               = "edited"                // Fields of the entry changed (a revision)
+	EventConservationStatus   = "conservation_status"   // The conservation status changed
+	EventPublished            = "published"             // Made public, directly or as scheduled
+	EventUnpublished          = "unpublished"           // Returned to draft
+	EventPublicationScheduled = "publication_scheduled" // Queued to be published at a set time
+	EventSourceAdded          = "source_added"          // Source data first added
+	EventSourceVerified       = "source_verified"       // Transcribed source data verified
+	EventAccountUpdated       = "account_updated"       // The long-form account last saved
+	EventFeatureSuggested     = "feature_suggested"     // A distinguishing feature proposed
+	EventFeatureReviewed      = "feature_reviewed"      // A proposed feature accepted or rejected
+	EventLocalityProposed     = "locality_proposed"     // A geocoded range locality proposed
+	EventLocalityReviewed     = "locality_reviewed"     // A proposed locality accepted or rejected
+	EventConflictDetected     = "conflict_detected"     // Two sources found to contradict each other
+	EventConflictResolved     = "conflict_resolved"     // A contradiction resolved
+)
+
+// TimelineEvent is one thing that happened to a species
+type TimelineEvent struct {
+	At       time.Time `json:"at"`
+	Kind     string    `json:"kind"`
+	Summary  string    `json:"summary"`
+	RefID    *int64    `json:"ref_id,omitempty"`    // The revision, suggestion, locality, conflict, or publication
+	SourceID *int64    `json:"source_id,omitempty"` // The source it concerns, if any
+	Fields   []string  `json:"fields,omitempty"`    // For edits, the fields changed
+}
+
+// timelineQuery lists the events recorded in other tables as rows of
+// (at, kind, ref_id, source_id, detail), all for the species ?1
+const timelineQuery = `
+	SELECT a.created_at, 'source_added', NULL, a.source_id, COALESCE(s.name, '')
+	  FROM species_source_additions a LEFT JOIN sources s ON s.id = a.source_id
+	 WHERE a.scientific_name = ?1
+	UNION ALL
+	SELECT verified_at, 'source_verified', NULL, source_id, COALESCE(verified_by, '')
+	  FROM species_sources WHERE scientific_name = ?1 AND verified_at IS NOT NULL
+	UNION ALL
+	SELECT updated_at, 'account_updated', NULL, NULL, ''
+	  FROM species_accounts WHERE scientific_name = ?1
+	UNION ALL
+	SELECT created_at, 'feature_suggested', id, source_id, sentence
+	  FROM feature_suggestions WHERE scientific_name = ?1
+	UNION ALL
+	SELECT reviewed_at, 'feature_reviewed', id, source_id, status
+	  FROM feature_suggestions WHERE scientific_name = ?1 AND reviewed_at IS NOT NULL
+	UNION ALL
+	SELECT created_at, 'locality_proposed', id, source_id, locality
+	  FROM range_localities WHERE scientific_name = ?1
+	UNION ALL
+	SELECT reviewed_at, 'locality_reviewed', id, source_id, status || ' ' || locality
+	  FROM range_localities WHERE scientific_name = ?1 AND reviewed_at IS NOT NULL
+	UNION ALL
+	SELECT created_at, 'conflict_detected', id, NULL, property
+	  FROM conflicts WHERE scientific_name = ?1
+	UNION ALL
+	SELECT resolved_at, 'conflict_resolved', id, preferred_source_id, property
+	  FROM conflicts WHERE scientific_name = ?1 AND resolved_at IS NOT NULL
+	UNION ALL
+	SELECT created_at, 'publication_scheduled', id, NULL, publish_at
+	  FROM publish_queue WHERE EXISTS (SELECT 1 FROM json_each(species) WHERE value = ?1)`
+
+// SpeciesTimeline returns everything recorded as happening to a species,
+// oldest first: edits and status changes from its revisions, source data
+// added and verified, account saves, proposals and their review, conflicts,
+// and scheduled publication. Events recorded before their table existed, such
+// as sources added before additions were tracked, are not included. Returns
+// nil if the species does not exist.
+func (db *Database) SpeciesTimeline(scientificName string) ([]*TimelineEvent, error) {
+	current, err := db.GetOakEntry(scientificName)
+	if err != nil || current == nil {
+		return nil, err
+	}
+
+	events, err := db.revisionEvents(current)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []*TimelineEvent{}
+	}
+
+	rows, err := db.conn.Query(timelineQuery, scientificName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list timeline events: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var at, detail string
+		var refID, sourceID sql.NullInt64
+		e := &TimelineEvent{}
+		if err := rows.Scan(&at, &e.Kind, &refID, &sourceID, &detail); err != nil {
+			return nil, fmt.Errorf("failed to scan timeline event: %w", err)
+		}
+		if e.At, err = time.Parse(time.RFC3339, at); err != nil {
+			return nil, fmt.Errorf("failed to parse time of %s event: %w", e.Kind, err)
+		}
+		if refID.Valid {
+			e.RefID = &refID.Int64
+		}
+		if sourceID.Valid {
+			e.SourceID = &sourceID.Int64
+		}
+		e.Summary = timelineSummary(e, detail)
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+	return events, nil
+}
+
+// revisionEvents compares each revision of an entry with the version that
+// replaced it, the newest with current
+func (db *Database) revisionEvents(current *models.OakEntry) ([]*TimelineEvent, error) {
+	revisions, err := db.ListOakEntryRevisions(current.ScientificName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revisions are newest first; events go oldest first
+	var events []*TimelineEvent
+	next := current
+	for _, rev := range revisions {
+		changed, err := changedFields(rev.Entry, next)
+		if err != nil {
+			return nil, err
+		}
+		var group []*TimelineEvent
+		var edited []string
+		for _, field := range changed {
+			e := &TimelineEvent{At: rev.CreatedAt, RefID: &rev.ID}
+			switch field {
+			case "visibility":
+				e.Kind, e.Summary = EventPublished, "Published"
+				if next.Visibility == models.VisibilityDraft {
+					e.Kind, e.Summary = EventUnpublished, "Returned to draft"
+				}
+			case "conservation_status":
+				e.Kind = EventConservationStatus
+				e.Summary = fmt.Sprintf("Conservation status %s → %s", statusOrNone(rev.Entry.ConservationStatus), statusOrNone(next.ConservationStatus))
+			default:
+				edited = append(edited, field)
+				continue
+			}
+			group = append(group, e)
+		}
+		if len(edited) > 0 {
+			group = append(group, &TimelineEvent{
+				At: rev.CreatedAt, Kind: EventEdited, RefID: &rev.ID, Fields: edited,
+				Summary: "Edited " + strings.Join(edited, ", "),
+			})
+		}
+		events = append(group, events...)
+		next = rev.Entry
+	}
+	return events, nil
+}
+
+// changedFields lists the top-level fields that differ between two versions
+// of an entry, in JSON field order. The hybrids list is left out: it changes
+// with other entries, not with edits to this one.
+func changedFields(before, after *models.OakEntry) ([]string, error) {
+	fields := func(e *models.OakEntry) (map[string]json.RawMessage, error) {
+		data, err := revisionJSON(e)
+		if err != nil {
+			return nil, err
+		}
+		var m map[string]json.RawMessage
+		return m, json.Unmarshal([]byte(data), &m)
+	}
+	a, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	b, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	// Known fields in their usual order, then any others
+	order := append([]string{}, oakEntryFields...)
+	var others []string
+	for field := range a {
+		if !slices.Contains(order, field) {
+			others = append(others, field)
+		}
+	}
+	for field := range b {
+		if !slices.Contains(order, field) && !slices.Contains(others, field) {
+			others = append(others, field)
+		}
+	}
+	sort.Strings(others)
+
+	var changed []string
+	for _, field := range append(order, others...) {
+		if field != "hybrids" && !bytes.Equal(a[field], b[field]) {
+			changed = append(changed, field)
+		}
+	}
+	return changed, nil
+}
+
+// oakEntryFields are models.OakEntry's JSON field names, in order
+var oakEntryFields = []string{
+	"scientific_name", "author", "pronunciation", "is_hybrid", "conservation_status", "genus",
+	"subgenus", "section", "subsection", "complex", "parent1", "parent2",
+	"hybrids", "closely_related_to", "subspecies_varieties", "synonyms", "external_links", "visibility",
+}
+
+func statusOrNone(status *string) string {
+	if status == nil || *status == "" {
+		return "none"
+	}
+	return *status
+}
+
+// timelineSummary describes an event from another table, given the detail
+// column timelineQuery selects for it
+func timelineSummary(e *TimelineEvent, detail string) string {
+	source := func() string {
+		if e.SourceID == nil {
+			return ""
+		}
+		return fmt.Sprintf(" (source %d)", *e.SourceID)
+	}
+	switch e.Kind {
+	case EventSourceAdded:
+		if detail == "" {
+			return fmt.Sprintf("Added data from source %d", *e.SourceID)
+		}
+		return fmt.Sprintf("Added data from %s", detail) + source()
+	case EventSourceVerified:
+		if detail == "" {
+			return "Verified transcription" + source()
+		}
+		return fmt.Sprintf("Verified transcription by %s", detail) + source()
+	case EventAccountUpdated:
+		return "Account last saved"
+	case EventFeatureSuggested:
+		return fmt.Sprintf("Distinguishing feature proposed: %q", detail) + source()
+	case EventFeatureReviewed:
+		return fmt.Sprintf("Distinguishing feature %d %s", *e.RefID, detail)
+	case EventLocalityProposed:
+		return fmt.Sprintf("Range locality proposed: %s", detail) + source()
+	case EventLocalityReviewed:
+		status, locality, _ := strings.Cut(detail, " ")
+		return fmt.Sprintf("Range locality %s: %s", status, locality)
+	case EventConflictDetected:
+		return fmt.Sprintf("Sources disagree on %s", detail)
+	case EventConflictResolved:
+		return fmt.Sprintf("Disagreement on %s resolved", detail) + source()
+	case EventPublicationScheduled:
+		return fmt.Sprintf("Scheduled to publish at %s", detail)
+	}
+	return e.Kind
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestSpeciesTimeline(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if events, err := db.SpeciesTimeline("texana"); err != nil || events != nil {
+		t.Fatalf("SpeciesTimeline(missing) = %v, %v; want nil", events, err)
+	}
+
+	entry := models.NewOakEntry("texana")
+	entry.Visibility = models.VisibilityDraft
+	if err := db.SaveOakEntry(entry); err != nil {
+		t.Fatal(err)
+	}
+	sourceID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Oaks of North America"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaves := "Blades 7-lobed. Readily separated from Q. shumardii by its smaller acorns."
+	if err := db.SaveSpeciesSource(&models.SpeciesSource{ScientificName: "texana", SourceID: sourceID, Leaves: &leaves}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.GenerateFeatureSuggestions("texana"); err != nil {
+		t.Fatal(err)
+	}
+
+	author, status := "Buckley", "NT"
+	entry.Author, entry.ConservationStatus = &author, &status
+	if err := db.SaveOakEntry(entry); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.CreateScheduledPublication([]string{"texana"}, time.Now().Add(-time.Minute), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.PublishDue(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := db.SpeciesTimeline("texana")
+	if err != nil {
+		t.Fatalf("SpeciesTimeline failed: %v", err)
+	}
+	kinds := map[string]*TimelineEvent{}
+	for i, e := range events {
+		kinds[e.Kind] = e
+		if i > 0 && e.At.Before(events[i-1].At) {
+			t.Errorf("event %d (%s) is before the one preceding it", i, e.Kind)
+		}
+	}
+	for _, kind := range []string{EventSourceAdded, EventFeatureSuggested, EventEdited, EventConservationStatus, EventPublicationScheduled, EventPublished} {
+		if kinds[kind] == nil {
+			t.Errorf("no %s event in %d events", kind, len(events))
+		}
+	}
+	if e := kinds[EventEdited]; e != nil && !stringSlicesEqual(e.Fields, []string{"author"}) {
+		t.Errorf("edited fields = %v, want [author]", e.Fields)
+	}
+	if e := kinds[EventConservationStatus]; e != nil && e.Summary != "Conservation status none → NT" {
+		t.Errorf("status summary = %q", e.Summary)
+	}
+	if e := kinds[EventSourceAdded]; e != nil && (e.SourceID == nil || *e.SourceID != sourceID) {
+		t.Errorf("source added event = %+v, want source %d", e, sourceID)
+	}
+
+	// Saving the source data again is not another addition
+	if err := db.SaveSpeciesSource(&models.SpeciesSource{ScientificName: "texana", SourceID: sourceID, Leaves: &leaves}); err != nil {
+		t.Fatal(err)
+	}
+	again, _ := db.SpeciesTimeline("texana")
+	if len(again) != len(events) {
+		t.Errorf("timeline after resaving source data has %d events, want %d", len(again), len(events))
+	}
+}
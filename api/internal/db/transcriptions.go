@@ -0,0 +1,102 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Transcription is the provenance of a species source's transcribed text
+type Transcription struct {
+	ScientificName string     `json:"scientific_name"`
+	SourceID       int64      `json:"source_id"`
+	SourceName     string     `json:"source_name"`
+	Pages          *string    `json:"pages,omitempty"`
+	Transcriber    *string    `json:"transcriber,omitempty"`
+	Method         string     `json:"transcription_method"`
+	OCRConfidence  *float64   `json:"ocr_confidence,omitempty"`
+	Verified       bool       `json:"verified"`
+	VerifiedAt     *time.Time `json:"verified_at,omitempty"`
+	VerifiedBy     *string    `json:"verified_by,omitempty"`
+}
+
+// TranscriptionFilter narrows ListTranscriptions. Zero values match any.
+type TranscriptionFilter struct {
+	Verified      *bool
+	Method        string
+	SourceID      int64
+	MaxConfidence *float64 // Only OCR'd text at or below this confidence
+}
+
+// ListTranscriptions returns the species sources with a transcription
+// method, least confident OCR first, then by species and source
+func (db *Database) ListTranscriptions(filter TranscriptionFilter) ([]*Transcription, error) {
+	query := `SELECT ss.scientific_name, ss.source_id, s.name, ss.pages, ss.transcriber, ss.transcription_method,
+			ss.ocr_confidence, ss.verified_at, ss.verified_by
+		FROM species_sources ss JOIN sources s ON s.id = ss.source_id
+		WHERE ss.transcription_method IS NOT NULL`
+	var args []interface{}
+	if filter.Verified != nil {
+		if *filter.Verified {
+			query += ` AND ss.verified_at IS NOT NULL`
+		} else {
+			query += ` AND ss.verified_at IS NULL`
+		}
+	}
+	if filter.Method != "" {
+		query += ` AND ss.transcription_method = ?`
+		args = append(args, filter.Method)
+	}
+	if filter.SourceID != 0 {
+		query += ` AND ss.source_id = ?`
+		args = append(args, filter.SourceID)
+	}
+	if filter.MaxConfidence != nil {
+		query += ` AND ss.ocr_confidence <= ?`
+		args = append(args, *filter.MaxConfidence)
+	}
+	query += ` ORDER BY ss.ocr_confidence IS NULL, ss.ocr_confidence, ss.scientific_name, ss.source_id`
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transcriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var transcriptions []*Transcription
+	for rows.Next() {
+		var t Transcription
+		var verifiedAt sql.NullString
+		if err := rows.Scan(&t.ScientificName, &t.SourceID, &t.SourceName, &t.Pages, &t.Transcriber, &t.Method,
+			&t.OCRConfidence, &verifiedAt, &t.VerifiedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan transcription: %w", err)
+		}
+		if t.VerifiedAt, err = parseOptionalTimestamp(verifiedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse verified_at for %s: %w", t.ScientificName, err)
+		}
+		t.Verified = t.VerifiedAt != nil
+		transcriptions = append(transcriptions, &t)
+	}
+	return transcriptions, rows.Err()
+}
+
+// VerifySpeciesSource marks a species source's text as checked against the
+// original, by verifiedBy if set. Returns false if the record does not exist.
+func (db *Database) VerifySpeciesSource(scientificName string, sourceID int64, verifiedBy string) (bool, error) {
+	var by *string
+	if verifiedBy != "" {
+		by = &verifiedBy
+	}
+	result, err := db.conn.Exec(
+		`UPDATE species_sources SET verified_at = ?, verified_by = ? WHERE scientific_name = ? AND source_id = ?`,
+		time.Now().UTC().Format(timestampFormat), by, scientificName, sourceID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify species source: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestTranscriptions(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	sourceID, err := db.InsertSource(&models.Source{SourceType: "book", Name: "Flora"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+	ocr, manual := models.TranscriptionOCR, models.TranscriptionManual
+	high, low := 0.92, 0.61
+	for name, ss := range map[string]*models.SpeciesSource{
+		"alba":     {TranscriptionMethod: &ocr, OCRConfidence: &high},
+		"rubra":    {TranscriptionMethod: &ocr, OCRConfidence: &low},
+		"velutina": {TranscriptionMethod: &manual},
+		"nigra":    {}, // Not transcribed; never queued
+	} {
+		if err := db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatalf("SaveOakEntry(%s) failed: %v", name, err)
+		}
+		ss.ScientificName, ss.SourceID = name, sourceID
+		if err := db.SaveSpeciesSource(ss); err != nil {
+			t.Fatalf("SaveSpeciesSource(%s) failed: %v", name, err)
+		}
+	}
+
+	unverified := false
+	queue, err := db.ListTranscriptions(TranscriptionFilter{Verified: &unverified})
+	if err != nil {
+		t.Fatalf("ListTranscriptions failed: %v", err)
+	}
+	var order []string
+	for _, tr := range queue {
+		order = append(order, tr.ScientificName)
+	}
+	if len(order) != 3 || order[0] != "rubra" || order[1] != "alba" || order[2] != "velutina" {
+		t.Errorf("queue = %v, want [rubra alba velutina]", order)
+	}
+
+	found, err := db.VerifySpeciesSource("rubra", sourceID, "jeff")
+	if err != nil || !found {
+		t.Fatalf("VerifySpeciesSource = %v, %v", found, err)
+	}
+	ss, err := db.GetSpeciesSourceBySourceID("rubra", sourceID)
+	if err != nil {
+		t.Fatalf("GetSpeciesSourceBySourceID failed: %v", err)
+	}
+	if !ss.Verified || ss.VerifiedAt == nil || ss.VerifiedBy == nil || *ss.VerifiedBy != "jeff" {
+		t.Errorf("rubra = verified %v at %v by %v, want verified by jeff", ss.Verified, ss.VerifiedAt, ss.VerifiedBy)
+	}
+
+	threshold := 0.95
+	queue, err = db.ListTranscriptions(TranscriptionFilter{Verified: &unverified, Method: ocr, MaxConfidence: &threshold})
+	if err != nil {
+		t.Fatalf("ListTranscriptions failed: %v", err)
+	}
+	if len(queue) != 1 || queue[0].ScientificName != "alba" {
+		t.Errorf("unverified OCR queue = %+v, want alba only", queue)
+	}
+
+	if found, _ := db.VerifySpeciesSource("nigra", sourceID+1, ""); found {
+		t.Error("VerifySpeciesSource found a missing record")
+	}
+}
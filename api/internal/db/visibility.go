@@ -0,0 +1,60 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// GetOakEntryVisibility returns a species' visibility, or "" if it does not exist
+func (db *Database) GetOakEntryVisibility(scientificName string) (string, error) {
+	var visibility string
+	err := db.conn.QueryRow(
+		`SELECT visibility FROM oak_entries WHERE scientific_name = ?`,
+		scientificName,
+	).Scan(&visibility)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get oak entry visibility: %w", err)
+	}
+	return visibility, nil
+}
+
+// SetOakEntryVisibility sets a species to draft or published, keeping the
+// entry as it was as a revision. Returns false if the species does not exist.
+func (db *Database) SetOakEntryVisibility(scientificName, visibility string) (bool, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	found, err := db.setOakEntryVisibilityTx(tx, scientificName, visibility)
+	if err != nil || !found {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// setOakEntryVisibilityTx sets a species' visibility within a transaction.
+// Returns false if the species does not exist.
+func (db *Database) setOakEntryVisibilityTx(tx *sql.Tx, scientificName, visibility string) (bool, error) {
+	existing, err := db.getOakEntryTx(tx, scientificName)
+	if err != nil || existing == nil {
+		return false, err
+	}
+	entry := *existing
+	entry.Visibility = visibility
+	if err := saveOakEntryRevisionTx(tx, existing, &entry); err != nil {
+		return false, err
+	}
+
+	if _, err := tx.Exec(
+		`UPDATE oak_entries SET visibility = ? WHERE scientific_name = ?`,
+		visibility, scientificName,
+	); err != nil {
+		return false, fmt.Errorf("failed to set oak entry visibility: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestDraftVisibility(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	draft := models.NewOakEntry("draftii")
+	draft.Visibility = models.VisibilityDraft
+	if err := db.SaveOakEntry(draft); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	if err := db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+
+	// Saving without a visibility keeps the stored one
+	if err := db.SaveOakEntry(models.NewOakEntry("draftii")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	if v, _ := db.GetOakEntryVisibility("draftii"); v != models.VisibilityDraft {
+		t.Errorf("visibility after resave = %q, want draft", v)
+	}
+	if v, _ := db.GetOakEntryVisibility("alba"); v != models.VisibilityPublished {
+		t.Errorf("default visibility = %q, want published", v)
+	}
+
+	public, err := db.ListOakEntriesPaginated(10, 0, nil)
+	if err != nil {
+		t.Fatalf("ListOakEntriesPaginated failed: %v", err)
+	}
+	if len(public) != 1 || public[0].ScientificName != "alba" {
+		t.Errorf("public list = %d entries, want only alba", len(public))
+	}
+	count, err := db.CountOakEntries(&OakEntryFilter{IncludeDrafts: true})
+	if err != nil {
+		t.Fatalf("CountOakEntries failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("count with drafts = %d, want 2", count)
+	}
+
+	found, _ := db.SearchOakEntriesFull("draft", 10, false)
+	if len(found) != 0 {
+		t.Errorf("public search found %d drafts, want 0", len(found))
+	}
+	found, _ = db.SearchOakEntriesFull("draft", 10, true)
+	if len(found) != 1 || found[0].Visibility != models.VisibilityDraft {
+		t.Errorf("curator search = %+v, want draftii as draft", found)
+	}
+
+	ok, err := db.SetOakEntryVisibility("draftii", models.VisibilityPublished)
+	if err != nil || !ok {
+		t.Fatalf("SetOakEntryVisibility = %v, %v", ok, err)
+	}
+	if count, _ := db.CountOakEntries(nil); count != 2 {
+		t.Errorf("public count after publish = %d, want 2", count)
+	}
+	if ok, _ := db.SetOakEntryVisibility("missing", models.VisibilityDraft); ok {
+		t.Error("SetOakEntryVisibility on missing species reported found")
+	}
+}
@@ -0,0 +1,208 @@
+package db
+
+import (
+	"database/sql"
+	_ "embed"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// bundledVocabularies are the starter vocabularies, seeded on startup
+//
+//go:embed vocabularies.yaml
+var bundledVocabularies []byte
+
+// vocabularyFieldUsage counts the values stored in each field a vocabulary
+// can be bound to
+var vocabularyFieldUsage = map[string]string{
+	models.VocabularyFieldConservationStatus: `SELECT conservation_status, COUNT(*) FROM oak_entries
+		WHERE conservation_status IS NOT NULL AND conservation_status != '' GROUP BY conservation_status`,
+	models.VocabularyFieldSourceType: `SELECT source_type, COUNT(*) FROM sources GROUP BY source_type`,
+}
+
+// seedVocabularies adds the bundled vocabularies that are not already in the
+// table. Edited ones are left alone, and so are their terms: a term deleted
+// from a bundled vocabulary stays deleted.
+func (db *Database) seedVocabularies() error {
+	var vocabularies []*models.Vocabulary
+	if err := yaml.Unmarshal(bundledVocabularies, &vocabularies); err != nil {
+		return fmt.Errorf("failed to parse bundled vocabularies: %w", err)
+	}
+	for _, v := range vocabularies {
+		result, err := db.conn.Exec(
+			`INSERT OR IGNORE INTO vocabularies (name, description, field) VALUES (?, ?, ?)`,
+			v.Name, v.Description, v.Field,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to seed vocabularies: %w", err)
+		}
+		if added, _ := result.RowsAffected(); added == 0 {
+			continue
+		}
+		for i, term := range v.Terms {
+			if _, err := db.conn.Exec(
+				`INSERT INTO vocabulary_terms (vocabulary, term, definition, position) VALUES (?, ?, ?, ?)`,
+				v.Name, term.Term, term.Definition, i,
+			); err != nil {
+				return fmt.Errorf("failed to seed vocabulary terms: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// ListVocabularies returns every vocabulary with its terms, ordered by name
+func (db *Database) ListVocabularies() ([]*models.Vocabulary, error) {
+	rows, err := db.conn.Query(`SELECT name, description, field FROM vocabularies ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vocabularies: %w", err)
+	}
+	defer rows.Close()
+
+	var vocabularies []*models.Vocabulary
+	for rows.Next() {
+		var v models.Vocabulary
+		if err := rows.Scan(&v.Name, &v.Description, &v.Field); err != nil {
+			return nil, fmt.Errorf("failed to scan vocabulary: %w", err)
+		}
+		vocabularies = append(vocabularies, &v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for _, v := range vocabularies {
+		if v.Terms, err = db.vocabularyTerms(v.Name); err != nil {
+			return nil, err
+		}
+	}
+	return vocabularies, nil
+}
+
+// GetVocabulary gets a vocabulary and its terms by name, or nil if it does
+// not exist
+func (db *Database) GetVocabulary(name string) (*models.Vocabulary, error) {
+	return db.getVocabulary(`name = ?`, name)
+}
+
+// FieldVocabulary gets the vocabulary bound to a field (one of
+// models.VocabularyFields), or nil if none is
+func (db *Database) FieldVocabulary(field string) (*models.Vocabulary, error) {
+	return db.getVocabulary(`field = ?`, field)
+}
+
+func (db *Database) getVocabulary(where string, arg string) (*models.Vocabulary, error) {
+	var v models.Vocabulary
+	err := db.conn.QueryRow(`SELECT name, description, field FROM vocabularies WHERE `+where, arg).
+		Scan(&v.Name, &v.Description, &v.Field)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vocabulary: %w", err)
+	}
+	if v.Terms, err = db.vocabularyTerms(v.Name); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (db *Database) vocabularyTerms(name string) ([]models.VocabularyTerm, error) {
+	rows, err := db.conn.Query(
+		`SELECT term, definition FROM vocabulary_terms WHERE vocabulary = ? ORDER BY position`, name,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vocabulary terms: %w", err)
+	}
+	defer rows.Close()
+
+	terms := []models.VocabularyTerm{}
+	for rows.Next() {
+		var term models.VocabularyTerm
+		if err := rows.Scan(&term.Term, &term.Definition); err != nil {
+			return nil, fmt.Errorf("failed to scan vocabulary term: %w", err)
+		}
+		terms = append(terms, term)
+	}
+	return terms, rows.Err()
+}
+
+// SaveVocabulary creates or replaces a vocabulary and all of its terms
+func (db *Database) SaveVocabulary(v *models.Vocabulary) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		`INSERT OR REPLACE INTO vocabularies (name, description, field) VALUES (?, ?, ?)`,
+		v.Name, v.Description, v.Field,
+	); err != nil {
+		return fmt.Errorf("failed to save vocabulary: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM vocabulary_terms WHERE vocabulary = ?`, v.Name); err != nil {
+		return fmt.Errorf("failed to clear vocabulary terms: %w", err)
+	}
+	for i, term := range v.Terms {
+		if _, err := tx.Exec(
+			`INSERT INTO vocabulary_terms (vocabulary, term, definition, position) VALUES (?, ?, ?, ?)`,
+			v.Name, term.Term, term.Definition, i,
+		); err != nil {
+			return fmt.Errorf("failed to save vocabulary term %q: %w", term.Term, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteVocabulary deletes a vocabulary and its terms. Returns false if it
+// does not exist.
+func (db *Database) DeleteVocabulary(name string) (bool, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM vocabulary_terms WHERE vocabulary = ?`, name); err != nil {
+		return false, fmt.Errorf("failed to delete vocabulary terms: %w", err)
+	}
+	result, err := tx.Exec(`DELETE FROM vocabularies WHERE name = ?`, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete vocabulary: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, tx.Commit()
+}
+
+// VocabularyFieldUsage counts the records using each value of a field (one
+// of models.VocabularyFields), keyed by value
+func (db *Database) VocabularyFieldUsage(field string) (map[string]int, error) {
+	query, ok := vocabularyFieldUsage[field]
+	if !ok {
+		return nil, fmt.Errorf("no vocabulary field %q", field)
+	}
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count %s values: %w", field, err)
+	}
+	defer rows.Close()
+
+	usage := make(map[string]int)
+	for rows.Next() {
+		var value string
+		var count int
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan %s value: %w", field, err)
+		}
+		usage[value] = count
+	}
+	return usage, rows.Err()
+}
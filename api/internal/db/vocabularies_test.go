@@ -0,0 +1,55 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestVocabularies(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	v, err := db.FieldVocabulary(models.VocabularyFieldSourceType)
+	if err != nil || v == nil {
+		t.Fatalf("FieldVocabulary = %v, %v", v, err)
+	}
+	if v.Name != "source_type" || len(v.Terms) != len(models.SourceTypes) {
+		t.Fatalf("source type vocabulary = %+v, want the %d source types", v, len(models.SourceTypes))
+	}
+	for i, term := range v.Terms {
+		if term.Term != models.SourceTypes[i] || term.Definition == nil {
+			t.Errorf("term %d = %+v, want %s with a definition", i, term, models.SourceTypes[i])
+		}
+	}
+
+	// Edited terms survive reseeding; a term removed stays removed
+	v.Terms = v.Terms[:2]
+	if err := db.SaveVocabulary(v); err != nil {
+		t.Fatalf("SaveVocabulary failed: %v", err)
+	}
+	if err := db.seedVocabularies(); err != nil {
+		t.Fatalf("seedVocabularies failed: %v", err)
+	}
+	if v, _ := db.GetVocabulary("source_type"); len(v.Terms) != 2 {
+		t.Errorf("got %d terms after reseeding, want 2", len(v.Terms))
+	}
+
+	if _, err := db.InsertSource(models.NewSource(models.SourceTypeBook, "Oaks of the World")); err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+	usage, err := db.VocabularyFieldUsage(models.VocabularyFieldSourceType)
+	if err != nil {
+		t.Fatalf("VocabularyFieldUsage failed: %v", err)
+	}
+	if len(usage) != 1 || usage[models.SourceTypeBook] != 1 {
+		t.Errorf("usage = %v, want one book", usage)
+	}
+
+	if found, err := db.DeleteVocabulary("source_type"); err != nil || !found {
+		t.Fatalf("DeleteVocabulary = %v, %v", found, err)
+	}
+	if v, err := db.FieldVocabulary(models.VocabularyFieldSourceType); err != nil || v != nil {
+		t.Errorf("FieldVocabulary after delete = %v, %v; want nil", v, err)
+	}
+}
@@ -2,22 +2,127 @@ package export
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/markdown"
+	"github.com/jeff/oaks/api/internal/mentions"
 	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/api/internal/rangemap"
+	"github.com/jeff/oaks/api/internal/units"
 )
 
+// Options selects what an export includes
+type Options struct {
+	Genus    string // Limits the export to this genus' species when non-empty
+	Accounts bool   // Embeds each species' rendered long-form account
+
+	// ConservationStatus limits the export to species with any of these
+	// IUCN codes when non-empty.
+	ConservationStatus []string
+
+	// Units rewrites measurements in source text for a unit system.
+	// Empty keeps them as written.
+	Units units.System
+
+	// Languages limits common names to these BCP 47 language ranges (see
+	// models.LanguageMatches) when non-empty.
+	Languages []string
+
+	// Generalize coarsens the localities of threatened species.
+	Generalize rangemap.Generalization
+}
+
+// commonNames groups a species' names by language, keeping the languages
+// opts.Languages asks for
+func (opts Options) commonNames(names []*models.CommonName) map[string][]string {
+	grouped := make(map[string][]string)
+	for _, n := range names {
+		if opts.wantsLanguage(n.Language) {
+			grouped[n.Language] = append(grouped[n.Language], n.Name)
+		}
+	}
+	return grouped
+}
+
+// wantsLanguage reports whether a language passes the Languages filter
+func (opts Options) wantsLanguage(tag string) bool {
+	if len(opts.Languages) == 0 {
+		return true
+	}
+	for _, want := range opts.Languages {
+		if models.LanguageMatches(tag, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasConservationStatus reports whether an entry passes the ConservationStatus filter
+func (opts Options) hasConservationStatus(e *models.OakEntry) bool {
+	if len(opts.ConservationStatus) == 0 {
+		return true
+	}
+	if e.ConservationStatus == nil {
+		return false
+	}
+	for _, status := range opts.ConservationStatus {
+		if *e.ConservationStatus == status {
+			return true
+		}
+	}
+	return false
+}
+
 // Build creates an export File from the database.
-func Build(database *db.Database) (*File, error) {
+func Build(database *db.Database, opts Options) (*File, error) {
 	// Get all oak entries
-	entries, err := database.ListOakEntries()
+	all, err := database.ListOakEntries()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list oak entries: %w", err)
 	}
 
+	// Drafts are work in progress and never published to the web app
+	entries := make([]*models.OakEntry, 0, len(all))
+	for _, e := range all {
+		if e.Visibility != models.VisibilityDraft && (opts.Genus == "" || e.Genus == opts.Genus) && opts.hasConservationStatus(e) {
+			entries = append(entries, e)
+		}
+	}
+
+	var resolver *mentions.Resolver
+	if opts.Accounts {
+		if resolver, err = database.MentionResolver(); err != nil {
+			return nil, fmt.Errorf("failed to load species names: %w", err)
+		}
+	}
+
+	commonNames, err := database.ListAllCommonNames()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list common names: %w", err)
+	}
+
+	speciesCustom, err := database.ExportedCustomAttributes(db.CustomEntitySpecies)
+	if err != nil {
+		return nil, err
+	}
+	sourceCustom, err := database.ExportedCustomAttributes(db.CustomEntitySource)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted, err := database.ListRangeLocalities(db.SuggestionAccepted, "")
+	if err != nil {
+		return nil, err
+	}
+	localities := make(map[string][]*db.RangeLocality)
+	for _, l := range accepted {
+		localities[l.ScientificName] = append(localities[l.ScientificName], l)
+	}
+
 	// Get all sources for lookup
-	sources, err := database.ListSources()
+	sources, err := database.ListSources(nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list sources: %w", err)
 	}
@@ -41,42 +146,42 @@ func Build(database *db.Database) (*File, error) {
 	// Build top-level sources array with full metadata
 	for _, s := range sources {
 		exportData.Sources = append(exportData.Sources, Source{
-			ID:          s.ID,
-			SourceType:  s.SourceType,
-			Name:        s.Name,
-			Description: s.Description,
-			Author:      s.Author,
-			Year:        s.Year,
-			URL:         s.URL,
-			ISBN:        s.ISBN,
-			DOI:         s.DOI,
-			Notes:       s.Notes,
-			License:     s.License,
-			LicenseURL:  s.LicenseURL,
+			ID:           s.ID,
+			SourceType:   s.SourceType,
+			Name:         s.Name,
+			Description:  s.Description,
+			Author:       s.Author,
+			Year:         s.Year,
+			URL:          s.URL,
+			ISBN:         s.ISBN,
+			DOI:          s.DOI,
+			Notes:        s.Notes,
+			License:      s.License,
+			LicenseURL:   s.LicenseURL,
+			SupersededBy: s.SupersededBy,
+			Custom:       sourceCustom[strconv.FormatInt(s.ID, 10)],
 		})
 	}
 
 	for _, entry := range entries {
 		// Convert external links to export format
-		var exportLinks []ExternalLink
-		if len(entry.ExternalLinks) > 0 {
-			exportLinks = make([]ExternalLink, len(entry.ExternalLinks))
-			for i, link := range entry.ExternalLinks {
-				exportLinks[i] = ExternalLink{
-					Name: link.Name,
-					URL:  link.URL,
-					Logo: link.Logo,
-				}
+		exportLinks := make([]ExternalLink, len(entry.ExternalLinks))
+		for i, link := range entry.ExternalLinks {
+			exportLinks[i] = ExternalLink{
+				Name: link.Name,
+				URL:  link.URL,
+				Logo: link.Logo,
 			}
 		}
 
 		species := Species{
 			Name:               entry.ScientificName,
 			Author:             entry.Author,
+			Pronunciation:      entry.Pronunciation,
 			IsHybrid:           entry.IsHybrid,
 			ConservationStatus: entry.ConservationStatus,
 			Taxonomy: Taxonomy{
-				Genus:      "Quercus",
+				Genus:      entry.Genus,
 				Subgenus:   entry.Subgenus,
 				Section:    entry.Section,
 				Subsection: entry.Subsection,
@@ -84,12 +189,14 @@ func Build(database *db.Database) (*File, error) {
 			},
 			Parent1:             entry.Parent1,
 			Parent2:             entry.Parent2,
-			Hybrids:             nonEmptySlice(entry.Hybrids),
-			CloselyRelatedTo:    nonEmptySlice(entry.CloselyRelatedTo),
-			SubspeciesVarieties: nonEmptySlice(entry.SubspeciesVarieties),
-			Synonyms:            nonEmptySlice(entry.Synonyms),
+			Hybrids:             nonNilSlice(entry.Hybrids),
+			CloselyRelatedTo:    nonNilSlice(entry.CloselyRelatedTo),
+			SubspeciesVarieties: nonNilSlice(entry.SubspeciesVarieties),
+			Synonyms:            nonNilSlice(entry.Synonyms),
+			CommonNames:         opts.commonNames(commonNames[entry.ScientificName]),
 			ExternalLinks:       exportLinks,
 			Sources:             []SourceData{},
+			Custom:              speciesCustom[entry.ScientificName],
 		}
 
 		// Get species_sources data for this entry
@@ -99,23 +206,40 @@ func Build(database *db.Database) (*File, error) {
 		}
 
 		// Convert species_sources to export format
+		internalRange := make(map[int64]bool)
 		for _, ss := range speciesSources {
+			internalRange[ss.SourceID] = ss.IsInternal("range")
+			ss.Redact() // Exports are published; internal fields never are
+			if opts.Units != "" {
+				units.ConvertSpeciesSource(ss, opts.Units)
+			}
 			sd := SourceData{
-				SourceID:         ss.SourceID,
-				SourceName:       fmt.Sprintf("Source %d", ss.SourceID),
-				IsPreferred:      ss.IsPreferred,
-				LocalNames:       nonEmptySlice(ss.LocalNames),
-				Range:            ss.Range,
-				GrowthHabit:      ss.GrowthHabit,
-				Leaves:           ss.Leaves,
-				Flowers:          ss.Flowers,
-				Fruits:           ss.Fruits,
-				Bark:             ss.Bark,
-				Twigs:            ss.Twigs,
-				Buds:             ss.Buds,
-				HardinessHabitat: ss.HardinessHabitat,
-				Miscellaneous:    ss.Miscellaneous,
-				URL:              ss.URL,
+				SourceID:               ss.SourceID,
+				SourceName:             fmt.Sprintf("Source %d", ss.SourceID),
+				IsPreferred:            ss.IsPreferred,
+				LocalNames:             nonNilSlice(ss.LocalNames),
+				Range:                  ss.Range,
+				GrowthHabit:            ss.GrowthHabit,
+				Leaves:                 ss.Leaves,
+				Flowers:                ss.Flowers,
+				Fruits:                 ss.Fruits,
+				Bark:                   ss.Bark,
+				Twigs:                  ss.Twigs,
+				Buds:                   ss.Buds,
+				HardinessHabitat:       ss.HardinessHabitat,
+				Miscellaneous:          ss.Miscellaneous,
+				DistinguishingFeatures: ss.DistinguishingFeatures,
+				URL:                    ss.URL,
+				Pages:                  ss.Pages,
+				FieldPages:             ss.FieldPages,
+				WithheldFields:         ss.InternalFields(),
+				TranscriptionMethod:    ss.TranscriptionMethod,
+				OCRConfidence:          ss.OCRConfidence,
+			}
+
+			if ss.TranscriptionMethod != nil {
+				verified := ss.Verified
+				sd.Verified = &verified
 			}
 
 			if source, ok := sourceMap[ss.SourceID]; ok {
@@ -128,15 +252,56 @@ func Build(database *db.Database) (*File, error) {
 			species.Sources = append(species.Sources, sd)
 		}
 
+		species.Localities = opts.localities(entry, localities[entry.ScientificName], internalRange)
+
+		if opts.Accounts {
+			account, err := database.GetSpeciesAccount(entry.ScientificName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get account for %s: %w", entry.ScientificName, err)
+			}
+			if account != nil {
+				species.Account = &Account{
+					HTML:      markdown.Render(account.Markdown, markdown.Options{Linkify: resolver.Find}).HTML,
+					UpdatedAt: account.UpdatedAt,
+				}
+			}
+		}
+
 		exportData.Species = append(exportData.Species, species)
 	}
 
 	return exportData, nil
 }
 
-func nonEmptySlice(s []string) []string {
-	if len(s) == 0 {
-		return nil
+// localities converts a species' reviewed range localities, leaving out those
+// from internal range text and generalizing them if the species is threatened
+func (opts Options) localities(entry *models.OakEntry, all []*db.RangeLocality, internalRange map[int64]bool) []Locality {
+	generalize := opts.Generalize.Applies(entry.ConservationStatus)
+	var out []Locality
+	for _, l := range all {
+		if internalRange[l.SourceID] {
+			continue
+		}
+		loc := Locality{
+			SourceID:  l.SourceID,
+			Locality:  l.Locality,
+			Latitude:  l.Latitude,
+			Longitude: l.Longitude,
+			Region:    l.Region,
+		}
+		if generalize {
+			loc.Latitude, loc.Longitude = opts.Generalize.Point(l.Latitude, l.Longitude)
+			loc.Generalized = opts.Generalize.Grid
+		}
+		out = append(out, loc)
+	}
+	return out
+}
+
+// nonNilSlice returns s, or an empty slice if s is nil, so it encodes as []
+func nonNilSlice(s []string) []string {
+	if s == nil {
+		return []string{}
 	}
 	return s
 }
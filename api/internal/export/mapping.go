@@ -0,0 +1,231 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mapping tailors the export payload for a frontend other than the web app.
+// Each section selects, drops, and renames the keys of one object in the export.
+//
+//	species:
+//	  include: [name, author, taxonomy, sources]
+//	  rename: {name: scientific_name}
+//	species_sources:
+//	  exclude: [license, license_url]
+//	sources:
+//	  include: [id, name, url]
+type Mapping struct {
+	Root           FieldMap `yaml:"root"`            // Top-level keys: metadata, sources, species
+	Species        FieldMap `yaml:"species"`         // Each species
+	SpeciesSources FieldMap `yaml:"species_sources"` // Each species' source-attributed data
+	Sources        FieldMap `yaml:"sources"`         // Each entry of the top-level sources array
+}
+
+// FieldMap selects and renames an object's keys. Keys are the export's JSON
+// names; renames apply after include and exclude.
+type FieldMap struct {
+	Include []string          `yaml:"include"` // Keys to keep; empty keeps all
+	Exclude []string          `yaml:"exclude"` // Keys to drop
+	Rename  map[string]string `yaml:"rename"`  // Old key to new key
+}
+
+// ParseMapping parses and validates a YAML export mapping
+func ParseMapping(data []byte) (*Mapping, error) {
+	var m Mapping
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil && !errors.Is(err, io.EOF) { // An empty mapping changes nothing
+		return nil, fmt.Errorf("invalid mapping: %w", err)
+	}
+
+	sections := []struct {
+		name   string
+		fields FieldMap
+		of     interface{}
+	}{
+		{"root", m.Root, File{}},
+		{"species", m.Species, Species{}},
+		{"species_sources", m.SpeciesSources, SourceData{}},
+		{"sources", m.Sources, Source{}},
+	}
+	for _, s := range sections {
+		if err := s.fields.validate(jsonKeys(s.of)); err != nil {
+			return nil, fmt.Errorf("invalid mapping: %s: %w", s.name, err)
+		}
+	}
+	return &m, nil
+}
+
+// LoadMapping reads an export mapping from a YAML file
+func LoadMapping(path string) (*Mapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping: %w", err)
+	}
+	m, err := ParseMapping(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// LoadMappings reads every *.yaml and *.yml file in dir as a mapping named
+// after the file, e.g. mobile.yaml is "mobile"
+func LoadMappings(dir string) (map[string]*Mapping, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping directory: %w", err)
+	}
+
+	mappings := make(map[string]*Mapping)
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		name := strings.TrimSuffix(e.Name(), ext)
+		if _, ok := mappings[name]; ok {
+			return nil, fmt.Errorf("duplicate mapping %q in %s", name, dir)
+		}
+		m, err := LoadMapping(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		mappings[name] = m
+	}
+	return mappings, nil
+}
+
+// Apply returns the export reshaped by the mapping, ready to marshal as JSON
+func (m *Mapping) Apply(f *File) (map[string]interface{}, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal export: %w", err)
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal export: %w", err)
+	}
+
+	for _, item := range objects(root["sources"]) {
+		m.Sources.apply(item)
+	}
+	for _, species := range objects(root["species"]) {
+		for _, sd := range objects(species["sources"]) {
+			m.SpeciesSources.apply(sd)
+		}
+		m.Species.apply(species)
+	}
+	m.Root.apply(root)
+	return root, nil
+}
+
+// validate checks that every key the field map names exists in keys
+func (fm FieldMap) validate(keys map[string]bool) error {
+	check := func(key string) error {
+		if !keys[key] {
+			return fmt.Errorf("unknown field %q", key)
+		}
+		return nil
+	}
+	for _, key := range append(append([]string{}, fm.Include...), fm.Exclude...) {
+		if err := check(key); err != nil {
+			return err
+		}
+	}
+
+	renamed := make(map[string]string)
+	for from, to := range fm.Rename {
+		if err := check(from); err != nil {
+			return err
+		}
+		if to == "" {
+			return fmt.Errorf("rename of %q is empty", from)
+		}
+		if other, ok := renamed[to]; ok {
+			first, second := other, from
+			if second < first {
+				first, second = second, first
+			}
+			return fmt.Errorf("%q and %q are both renamed to %q", first, second, to)
+		}
+		renamed[to] = from
+	}
+	return nil
+}
+
+// apply filters and renames obj's keys in place
+func (fm FieldMap) apply(obj map[string]interface{}) {
+	if len(fm.Include) > 0 {
+		keep := make(map[string]bool, len(fm.Include))
+		for _, key := range fm.Include {
+			keep[key] = true
+		}
+		for key := range obj {
+			if !keep[key] {
+				delete(obj, key)
+			}
+		}
+	}
+	for _, key := range fm.Exclude {
+		delete(obj, key)
+	}
+
+	// Collect first so a rename chain such as a→b, b→c doesn't clobber values
+	values := make(map[string]interface{}, len(fm.Rename))
+	for from := range fm.Rename {
+		if v, ok := obj[from]; ok {
+			values[from] = v
+			delete(obj, from)
+		}
+	}
+	for from, v := range values {
+		obj[fm.Rename[from]] = v
+	}
+}
+
+// objects returns the JSON objects in an array value
+func objects(v interface{}) []map[string]interface{} {
+	items, _ := v.([]interface{})
+	out := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(map[string]interface{}); ok {
+			out = append(out, obj)
+		}
+	}
+	return out
+}
+
+// jsonKeys returns the JSON key names of a struct's fields
+func jsonKeys(v interface{}) map[string]bool {
+	keys := make(map[string]bool)
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// MappingNames returns the names of mappings in sorted order
+func MappingNames(mappings map[string]*Mapping) []string {
+	names := make([]string, 0, len(mappings))
+	for name := range mappings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMappingApply(t *testing.T) {
+	m, err := ParseMapping([]byte(`
+root:
+  exclude: [metadata]
+  rename: {species: taxa}
+species:
+  include: [name, author, sources]
+  rename: {name: scientific_name}
+species_sources:
+  exclude: [license, license_url]
+sources:
+  include: [id, name]
+`))
+	if err != nil {
+		t.Fatalf("ParseMapping() error = %v", err)
+	}
+
+	author, license := "L.", "CC BY"
+	f := &File{
+		Sources: []Source{{ID: 1, Name: "Flora", License: &license}},
+		Species: []Species{{
+			Name:     "alba",
+			Author:   &author,
+			IsHybrid: false,
+			Sources:  []SourceData{{SourceID: 1, SourceName: "Flora", License: &license}},
+		}},
+	}
+	out, err := m.Apply(f)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if _, ok := out["metadata"]; ok {
+		t.Error("metadata should be excluded")
+	}
+	taxa, ok := out["taxa"].([]interface{})
+	if !ok || len(taxa) != 1 {
+		t.Fatalf("taxa = %v", out["taxa"])
+	}
+	species := taxa[0].(map[string]interface{})
+	if species["scientific_name"] != "alba" || species["author"] != "L." || len(species) != 3 {
+		t.Errorf("species = %v", species)
+	}
+	sd := species["sources"].([]interface{})[0].(map[string]interface{})
+	if _, ok := sd["license"]; ok || sd["source_name"] != "Flora" {
+		t.Errorf("species source = %v", sd)
+	}
+	source := out["sources"].([]interface{})[0].(map[string]interface{})
+	if len(source) != 2 || source["name"] != "Flora" {
+		t.Errorf("source = %v", source)
+	}
+}
+
+func TestParseMappingErrors(t *testing.T) {
+	tests := []struct {
+		yaml string
+		want string
+	}{
+		{"species:\n  include: [nmae]\n", `species: unknown field "nmae"`},
+		{"sources:\n  rename: {id: key, name: key}\n", `"id" and "name" are both renamed to "key"`},
+		{"specis:\n  include: [name]\n", "field specis not found"},
+	}
+	for _, tt := range tests {
+		_, err := ParseMapping([]byte(tt.yaml))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseMapping(%q) error = %v, want %q", tt.yaml, err, tt.want)
+		}
+	}
+
+	if _, err := ParseMapping(nil); err != nil {
+		t.Errorf("ParseMapping(empty) error = %v", err)
+	}
+}
+
+func TestLoadMappings(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "mobile.yaml"), []byte("species:\n  include: [name]\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "print.yml"), []byte(""), 0o644)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a mapping"), 0o644)
+
+	mappings, err := LoadMappings(dir)
+	if err != nil {
+		t.Fatalf("LoadMappings() error = %v", err)
+	}
+	if names := MappingNames(mappings); strings.Join(names, ",") != "mobile,print" {
+		t.Errorf("names = %v", names)
+	}
+}
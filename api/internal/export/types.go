@@ -4,8 +4,8 @@ package export
 // Taxonomy represents the nested taxonomy in export format.
 type Taxonomy struct {
 	Genus      string  `json:"genus"`
-	Subgenus   *string `json:"subgenus"`
-	Section    *string `json:"section"`
+	Subgenus   *string `json:"subgenus,omitempty"`
+	Section    *string `json:"section,omitempty"`
 	Subsection *string `json:"subsection,omitempty"`
 	Complex    *string `json:"complex,omitempty"`
 }
@@ -19,41 +19,80 @@ type ExternalLink struct {
 
 // SourceData represents source-attributed data for a species.
 type SourceData struct {
-	SourceID         int64    `json:"source_id"`
-	SourceName       string   `json:"source_name"`
-	SourceURL        *string  `json:"source_url,omitempty"`
-	License          *string  `json:"license,omitempty"`
-	LicenseURL       *string  `json:"license_url,omitempty"`
-	IsPreferred      bool     `json:"is_preferred"`
-	LocalNames       []string `json:"local_names,omitempty"`
-	Range            *string  `json:"range,omitempty"`
-	GrowthHabit      *string  `json:"growth_habit,omitempty"`
-	Leaves           *string  `json:"leaves,omitempty"`
-	Flowers          *string  `json:"flowers,omitempty"`
-	Fruits           *string  `json:"fruits,omitempty"`
-	Bark             *string  `json:"bark,omitempty"`
-	Twigs            *string  `json:"twigs,omitempty"`
-	Buds             *string  `json:"buds,omitempty"`
-	HardinessHabitat *string  `json:"hardiness_habitat,omitempty"`
-	Miscellaneous    *string  `json:"miscellaneous,omitempty"`
-	URL              *string  `json:"url,omitempty"` // Source's page for this species
+	SourceID               int64    `json:"source_id"`
+	SourceName             string   `json:"source_name"`
+	SourceURL              *string  `json:"source_url,omitempty"`
+	License                *string  `json:"license,omitempty"`
+	LicenseURL             *string  `json:"license_url,omitempty"`
+	IsPreferred            bool     `json:"is_preferred"`
+	LocalNames             []string `json:"local_names"`
+	Range                  *string  `json:"range,omitempty"`
+	GrowthHabit            *string  `json:"growth_habit,omitempty"`
+	Leaves                 *string  `json:"leaves,omitempty"`
+	Flowers                *string  `json:"flowers,omitempty"`
+	Fruits                 *string  `json:"fruits,omitempty"`
+	Bark                   *string  `json:"bark,omitempty"`
+	Twigs                  *string  `json:"twigs,omitempty"`
+	Buds                   *string  `json:"buds,omitempty"`
+	HardinessHabitat       *string  `json:"hardiness_habitat,omitempty"`
+	Miscellaneous          *string  `json:"miscellaneous,omitempty"`
+	DistinguishingFeatures *string  `json:"distinguishing_features,omitempty"`
+	URL                    *string  `json:"url,omitempty"` // Source's page for this species
+	// Pages and FieldPages locate the data in a printed source
+	Pages      *string           `json:"pages,omitempty"`
+	FieldPages map[string]string `json:"field_pages,omitempty"`
+	// WithheldFields are text fields recorded but internal, so left out
+	WithheldFields []string `json:"withheld_fields,omitempty"`
+	// Transcription provenance, for transcribed text. Verified is false
+	// until a curator has checked the text against the original.
+	TranscriptionMethod *string  `json:"transcription_method,omitempty"`
+	OCRConfidence       *float64 `json:"ocr_confidence,omitempty"`
+	Verified            *bool    `json:"verified,omitempty"`
 }
 
 // Species represents a species in export format.
 type Species struct {
-	Name                string         `json:"name"`
-	Author              *string        `json:"author,omitempty"`
-	IsHybrid            bool           `json:"is_hybrid"`
-	ConservationStatus  *string        `json:"conservation_status,omitempty"`
-	Taxonomy            Taxonomy       `json:"taxonomy"`
-	Parent1             *string        `json:"parent1,omitempty"`
-	Parent2             *string        `json:"parent2,omitempty"`
-	Hybrids             []string       `json:"hybrids,omitempty"`
-	CloselyRelatedTo    []string       `json:"closely_related_to,omitempty"`
-	SubspeciesVarieties []string       `json:"subspecies_varieties,omitempty"`
-	Synonyms            []string       `json:"synonyms,omitempty"`
-	ExternalLinks       []ExternalLink `json:"external_links,omitempty"`
-	Sources             []SourceData   `json:"sources,omitempty"`
+	Name                string   `json:"name"`
+	Author              *string  `json:"author,omitempty"`
+	Pronunciation       *string  `json:"pronunciation,omitempty"`
+	IsHybrid            bool     `json:"is_hybrid"`
+	ConservationStatus  *string  `json:"conservation_status,omitempty"`
+	Taxonomy            Taxonomy `json:"taxonomy"`
+	Parent1             *string  `json:"parent1,omitempty"`
+	Parent2             *string  `json:"parent2,omitempty"`
+	Hybrids             []string `json:"hybrids"`
+	CloselyRelatedTo    []string `json:"closely_related_to"`
+	SubspeciesVarieties []string `json:"subspecies_varieties"`
+	Synonyms            []string `json:"synonyms"`
+	// CommonNames groups vernacular names by BCP 47 language tag, e.g.
+	// {"en": ["White oak"], "fr": ["Chêne blanc"]}; "und" holds names in an
+	// unknown language
+	CommonNames   map[string][]string `json:"common_names"`
+	ExternalLinks []ExternalLink      `json:"external_links"`
+	Sources       []SourceData        `json:"sources"`
+	Localities    []Locality          `json:"localities,omitempty"` // Reviewed places in the range
+	Account       *Account            `json:"account,omitempty"`    // Only with Options.Accounts
+	// Custom holds the values of this deployment's exported custom fields
+	Custom map[string]any `json:"custom,omitempty"`
+}
+
+// Locality is a reviewed, geocoded place named in a source's range. For
+// threatened species its coordinates are generalized to the center of a
+// grid cell whose size in degrees is Generalized.
+type Locality struct {
+	SourceID    int64   `json:"source_id"`
+	Locality    string  `json:"locality"` // As written in the range text
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Region      *string `json:"region,omitempty"`
+	Generalized float64 `json:"generalized,omitempty"`
+}
+
+// Account is a species' long-form account rendered for the web app.
+// Cross-links point at /species/{name}/.
+type Account struct {
+	HTML      string `json:"html"`
+	UpdatedAt string `json:"updated_at"`
 }
 
 // Metadata contains version info for cache invalidation.
@@ -65,18 +104,21 @@ type Metadata struct {
 
 // Source represents full source metadata at top level.
 type Source struct {
-	ID          int64   `json:"id"`
-	SourceType  string  `json:"source_type"`
-	Name        string  `json:"name"`
-	Description *string `json:"description,omitempty"`
-	Author      *string `json:"author,omitempty"`
-	Year        *int    `json:"year,omitempty"`
-	URL         *string `json:"url,omitempty"`
-	ISBN        *string `json:"isbn,omitempty"`
-	DOI         *string `json:"doi,omitempty"`
-	Notes       *string `json:"notes,omitempty"`
-	License     *string `json:"license,omitempty"`
-	LicenseURL  *string `json:"license_url,omitempty"`
+	ID           int64   `json:"id"`
+	SourceType   string  `json:"source_type"`
+	Name         string  `json:"name"`
+	Description  *string `json:"description,omitempty"`
+	Author       *string `json:"author,omitempty"`
+	Year         *int    `json:"year,omitempty"`
+	URL          *string `json:"url,omitempty"`
+	ISBN         *string `json:"isbn,omitempty"`
+	DOI          *string `json:"doi,omitempty"`
+	Notes        *string `json:"notes,omitempty"`
+	License      *string `json:"license,omitempty"`
+	LicenseURL   *string `json:"license_url,omitempty"`
+	SupersededBy *int64  `json:"superseded_by,omitempty"`
+	// Custom holds the values of this deployment's exported custom fields
+	Custom map[string]any `json:"custom,omitempty"`
 }
 
 // File represents the complete export format.
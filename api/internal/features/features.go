@@ -0,0 +1,71 @@
+// Package features picks out sentences in description text that likely state
+// what distinguishes a species, such as "Distinguished from Q. alba by its
+// bristle-tipped lobes." It is rule-based: a sentence is a candidate when it
+// uses comparative or diagnostic wording. Curators confirm candidates before
+// they become a species' distinguishing features.
+package features
+
+import (
+	"regexp"
+	"strings"
+)
+
+// diagnosticPattern matches wording that compares a species with others or
+// calls a trait diagnostic
+var diagnosticPattern = regexp.MustCompile(`(?i)\b(?:` + strings.Join([]string{
+	`distinguish(?:ed|es|ing|able)?`,
+	`differ(?:s|ing)?\s+from`,
+	`unlike`,
+	`in contrast (?:to|with)`,
+	`distinctive(?:ly)?`,
+	`diagnostic`,
+	`characteri[sz]ed by`,
+	`(?:readily|easily)\s+(?:told|identified|recognized|recognised|separated)`,
+	`separated from`,
+	`(?:key|best|surest) (?:feature|character|field mark)s?`,
+	`only (?:oak|species) (?:in|with|to)`,
+}, "|") + `)\b`)
+
+// sentenceEnd splits text after ., !, or ? followed by whitespace and a
+// capital letter, digit, or opening quote or parenthesis
+var sentenceEnd = regexp.MustCompile(`[.!?]["')\]]?\s+["'(]?[A-Z0-9]`)
+
+// abbreviations end with a period without ending the sentence, as in
+// "Q. alba" or "var. texana"
+var abbreviation = regexp.MustCompile(`(?:\b[A-Z]|\b(?:var|subsp|ssp|cf|e\.g|i\.e|approx|ca|vs|sect|Mt|St))\.$`)
+
+// Sentences splits text into sentences, keeping abbreviations such as
+// "Q. alba" within their sentence
+func Sentences(text string) []string {
+	var sentences []string
+	start := 0
+	for _, m := range sentenceEnd.FindAllStringIndex(text, -1) {
+		end := m[0] + 1
+		if abbreviation.MatchString(text[start:end]) {
+			continue
+		}
+		// Include a closing quote or bracket after the punctuation
+		for end < m[1] && strings.ContainsRune(`"')]`, rune(text[end])) {
+			end++
+		}
+		if s := strings.TrimSpace(text[start:end]); s != "" {
+			sentences = append(sentences, s)
+		}
+		start = end
+	}
+	if s := strings.TrimSpace(text[start:]); s != "" {
+		sentences = append(sentences, s)
+	}
+	return sentences
+}
+
+// Candidates returns the sentences in text that likely state distinguishing features
+func Candidates(text string) []string {
+	var found []string
+	for _, s := range Sentences(text) {
+		if diagnosticPattern.MatchString(s) {
+			found = append(found, s)
+		}
+	}
+	return found
+}
@@ -0,0 +1,39 @@
+package features
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSentences(t *testing.T) {
+	text := `Leaves 10-20 cm, lobes bristle-tipped. Close to Q. rubra var. ambigua, "acorns large." (Cups shallow.) 3 lobes per side`
+	got := Sentences(text)
+	want := []string{
+		"Leaves 10-20 cm, lobes bristle-tipped.",
+		`Close to Q. rubra var. ambigua, "acorns large."`,
+		"(Cups shallow.)",
+		"3 lobes per side",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Sentences() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	text := "Leaves glossy above. Distinguished from Q. velutina by its pale inner bark. " +
+		"Cups cover one-third of the nut. Unlike most red oaks, the acorns mature in one year! " +
+		"Differs from Q. shumardii in its shallower cups."
+	got := Candidates(text)
+	want := []string{
+		"Distinguished from Q. velutina by its pale inner bark.",
+		"Unlike most red oaks, the acorns mature in one year!",
+		"Differs from Q. shumardii in its shallower cups.",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Candidates() =\n%q\nwant\n%q", got, want)
+	}
+
+	if got := Candidates("A large tree with gray bark."); len(got) != 0 {
+		t.Errorf("Candidates() = %q, want none", got)
+	}
+}
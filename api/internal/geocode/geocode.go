@@ -0,0 +1,99 @@
+// Package geocode turns the textual localities in species range descriptions
+// ("Edwards Plateau, Texas") into approximate coordinates.
+//
+// Lookups go through a Provider: Nominatim (OpenStreetMap's geocoder, or a
+// self-hosted instance) or a Gazetteer read from a local file. The provider is
+// chosen from the environment (see ProviderFromEnv); with none configured,
+// geocoding is off. Results are approximate and machine-derived, so callers
+// store them for a curator to review rather than treating them as data.
+package geocode
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Place is where a locality was found
+type Place struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Region    string  `json:"region,omitempty"` // The provider's name for the place, e.g. "Edwards Plateau, Texas, United States"
+}
+
+// Provider looks up localities. Geocode returns nil, nil for a locality it
+// does not know.
+type Provider interface {
+	Name() string
+	Geocode(ctx context.Context, locality string) (*Place, error)
+}
+
+// Environment variables read by ProviderFromEnv
+const (
+	EnvGeocoder  = "OAK_GEOCODER"           // nominatim or gazetteer; unset turns geocoding off
+	EnvURL       = "OAK_GEOCODER_URL"       // Nominatim base URL (default DefaultNominatimURL)
+	EnvGazetteer = "OAK_GEOCODER_GAZETTEER" // Gazetteer file, for gazetteer
+)
+
+// ProviderFromEnv builds the provider OAK_GEOCODER names, or returns nil
+// when it is unset.
+func ProviderFromEnv(userAgent string) (Provider, error) {
+	switch name := strings.ToLower(strings.TrimSpace(os.Getenv(EnvGeocoder))); name {
+	case "":
+		return nil, nil
+	case "nominatim":
+		baseURL := strings.TrimSpace(os.Getenv(EnvURL))
+		if baseURL == "" {
+			baseURL = DefaultNominatimURL
+		}
+		return NewNominatim(baseURL, userAgent), nil
+	case "gazetteer":
+		path := strings.TrimSpace(os.Getenv(EnvGazetteer))
+		if path == "" {
+			return nil, fmt.Errorf("%s=gazetteer requires %s", EnvGeocoder, EnvGazetteer)
+		}
+		return LoadGazetteer(path)
+	default:
+		return nil, fmt.Errorf("unknown %s %q (valid: nominatim, gazetteer)", EnvGeocoder, name)
+	}
+}
+
+// localitySplit separates the localities of a range description: at
+// semicolons, line breaks, and the ends of sentences
+var localitySplit = regexp.MustCompile(`[;\n]|\.\s+`)
+
+// parenthetical matches asides such as "(rare)" or "(type locality)"
+var parenthetical = regexp.MustCompile(`\s*\([^)]*\)`)
+
+// leadingWords are phrasing before a locality ("Native to central Texas")
+var leadingWords = regexp.MustCompile(`(?i)^(?:(?:native|endemic|restricted|confined|limited) to|(?:found|occurs|occurring|known|common|scattered|rare|frequent) (?:in|on|from|throughout)|(?:also )?(?:in|on|from|throughout))\s+`)
+
+// maxLocalityLength skips phrases long enough to be prose rather than places
+const maxLocalityLength = 80
+
+// Localities splits a range description into the phrases worth geocoding:
+// "Native to the Edwards Plateau, Texas; Coahuila (rare)." gives "the
+// Edwards Plateau, Texas" and "Coahuila". Duplicates are dropped.
+func Localities(rangeText string) []string {
+	var localities []string
+	seen := make(map[string]bool)
+	for _, part := range localitySplit.Split(rangeText, -1) {
+		part = parenthetical.ReplaceAllString(part, "")
+		part = strings.Trim(strings.TrimSpace(part), ".,:")
+		part = strings.TrimSpace(leadingWords.ReplaceAllString(part, ""))
+		if part == "" || len(part) > maxLocalityLength || seen[Key(part)] {
+			continue
+		}
+		seen[Key(part)] = true
+		localities = append(localities, part)
+	}
+	return localities
+}
+
+// Key is the form a locality is cached under: lowercase, with runs of
+// spaces collapsed
+func Key(locality string) string {
+	return strings.Join(strings.Fields(strings.ToLower(locality)), " ")
+}
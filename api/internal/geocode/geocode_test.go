@@ -0,0 +1,74 @@
+package geocode
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLocalities(t *testing.T) {
+	text := "Native to the Edwards Plateau, Texas; Coahuila (rare). Found in Nuevo León\nthe edwards  plateau, texas"
+	want := []string{"the Edwards Plateau, Texas", "Coahuila", "Nuevo León"}
+	if got := Localities(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("Localities() = %q, want %q", got, want)
+	}
+
+	long := "Dry limestone slopes and canyons with other evergreen oaks, junipers, and pinyon pines at middle elevations"
+	if got := Localities(long); len(got) != 0 {
+		t.Errorf("Localities(prose) = %q, want none", got)
+	}
+}
+
+func TestGazetteer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "places.tsv")
+	data := "# locality\tlat\tlon\tregion\nEdwards Plateau, Texas\t30.5\t-100.0\tEdwards Plateau\nCoahuila\t27.3\t-102.0\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g, err := LoadGazetteer(path)
+	if err != nil {
+		t.Fatalf("LoadGazetteer failed: %v", err)
+	}
+
+	p, err := g.Geocode(t.Context(), "edwards  plateau, TEXAS")
+	if err != nil || p == nil || p.Latitude != 30.5 || p.Region != "Edwards Plateau" {
+		t.Errorf("Geocode = %+v, %v", p, err)
+	}
+	if p, err := g.Geocode(t.Context(), "Sonora"); p != nil || err != nil {
+		t.Errorf("Geocode(unknown) = %+v, %v; want nil", p, err)
+	}
+
+	if err := os.WriteFile(path, []byte("Coahuila\t127\t-102\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadGazetteer(path); err == nil {
+		t.Error("LoadGazetteer accepted a latitude of 127")
+	}
+}
+
+func TestNominatim(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" || r.Header.Get("User-Agent") != "oak-api test" {
+			t.Errorf("request = %s (User-Agent %q)", r.URL, r.Header.Get("User-Agent"))
+		}
+		if r.URL.Query().Get("q") == "Coahuila" {
+			w.Write([]byte(`[{"lat":"27.3","lon":"-102.0","display_name":"Coahuila, México"}]`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	n := NewNominatim(server.URL+"/", "oak-api test")
+	n.interval = 0
+	p, err := n.Geocode(t.Context(), "Coahuila")
+	if err != nil || p == nil || p.Longitude != -102.0 || p.Region != "Coahuila, México" {
+		t.Errorf("Geocode = %+v, %v", p, err)
+	}
+	if p, err := n.Geocode(t.Context(), "Atlantis"); p != nil || err != nil {
+		t.Errorf("Geocode(unknown) = %+v, %v; want nil", p, err)
+	}
+}
@@ -0,0 +1,169 @@
+package geocode
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultNominatimURL is OpenStreetMap's public Nominatim instance
+const DefaultNominatimURL = "https://nominatim.openstreetmap.org"
+
+// nominatimInterval spaces requests as the public instance's usage policy
+// asks (at most one per second)
+const nominatimInterval = time.Second
+
+// Nominatim looks localities up with a Nominatim search API.
+type Nominatim struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+	interval  time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewNominatim creates a provider for the Nominatim instance at baseURL.
+// userAgent identifies the application, as the usage policy requires.
+func NewNominatim(baseURL, userAgent string) *Nominatim {
+	return &Nominatim{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		userAgent: userAgent,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		interval:  nominatimInterval,
+	}
+}
+
+// Name implements Provider.
+func (n *Nominatim) Name() string { return "nominatim" }
+
+// Geocode implements Provider, taking the best match.
+func (n *Nominatim) Geocode(ctx context.Context, locality string) (*Place, error) {
+	if err := n.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"q": {locality}, "format": {"jsonv2"}, "limit": {"1"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.baseURL+"/search?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create geocoding request: %w", err)
+	}
+	req.Header.Set("User-Agent", n.userAgent)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to geocode %q: %w", locality, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("nominatim returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var results []struct {
+		Lat         string `json:"lat"`
+		Lon         string `json:"lon"`
+		DisplayName string `json:"display_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode geocoding response: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+	lat, errLat := strconv.ParseFloat(results[0].Lat, 64)
+	lon, errLon := strconv.ParseFloat(results[0].Lon, 64)
+	if errLat != nil || errLon != nil {
+		return nil, fmt.Errorf("nominatim returned bad coordinates %q, %q", results[0].Lat, results[0].Lon)
+	}
+	return &Place{Latitude: lat, Longitude: lon, Region: results[0].DisplayName}, nil
+}
+
+// wait blocks until interval has passed since the previous request
+func (n *Nominatim) wait(ctx context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if delay := n.interval - time.Since(n.last); delay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	n.last = time.Now()
+	return nil
+}
+
+// Gazetteer looks localities up in a fixed list of places, matched whole
+// and ignoring case.
+type Gazetteer struct {
+	places map[string]Place
+}
+
+// NewGazetteer creates a gazetteer from places keyed by locality.
+func NewGazetteer(places map[string]Place) *Gazetteer {
+	g := &Gazetteer{places: make(map[string]Place, len(places))}
+	for locality, p := range places {
+		g.places[Key(locality)] = p
+	}
+	return g
+}
+
+// LoadGazetteer reads a gazetteer file: one place per line as
+// locality<TAB>latitude<TAB>longitude[<TAB>region]. Blank lines and lines
+// starting with # are skipped.
+func LoadGazetteer(path string) (*Gazetteer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gazetteer: %w", err)
+	}
+	defer f.Close()
+
+	places := make(map[string]Place)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("%s:%d: want locality, latitude, and longitude separated by tabs", path, line)
+		}
+		lat, errLat := strconv.ParseFloat(strings.TrimSpace(fields[1]), 64)
+		lon, errLon := strconv.ParseFloat(strings.TrimSpace(fields[2]), 64)
+		if errLat != nil || errLon != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			return nil, fmt.Errorf("%s:%d: bad coordinates", path, line)
+		}
+		p := Place{Latitude: lat, Longitude: lon}
+		if len(fields) > 3 {
+			p.Region = strings.TrimSpace(fields[3])
+		}
+		places[strings.TrimSpace(fields[0])] = p
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read gazetteer: %w", err)
+	}
+	return NewGazetteer(places), nil
+}
+
+// Name implements Provider.
+func (g *Gazetteer) Name() string { return "gazetteer" }
+
+// Geocode implements Provider.
+func (g *Gazetteer) Geocode(_ context.Context, locality string) (*Place, error) {
+	if p, ok := g.places[Key(locality)]; ok {
+		return &p, nil
+	}
+	return nil, nil
+}
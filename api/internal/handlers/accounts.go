@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/markdown"
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// AccountRequest is the request body for saving a species account.
+type AccountRequest struct {
+	Markdown string `json:"markdown"`
+}
+
+// maxAccountBytes caps the size of a species account's markdown
+const maxAccountBytes = 256 * 1024
+
+// handleGetSpeciesAccount handles GET /api/v1/species/{name}/account
+// Returns the markdown with its rendered HTML, cross-links, and image references.
+func (s *Server) handleGetSpeciesAccount(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+
+	visible, err := s.speciesVisible(r, name)
+	if err != nil {
+		s.logger.Error("failed to check species existence", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !visible {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+
+	account, err := s.db.GetSpeciesAccount(name)
+	if err != nil {
+		s.logger.Error("failed to get species account", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if account == nil {
+		RespondNotFound(w, "Account", name)
+		return
+	}
+
+	if err := s.renderAccount(account); err != nil {
+		s.logger.Error("failed to check account links", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	RespondJSON(w, http.StatusOK, account)
+}
+
+// handlePutSpeciesAccount handles PUT /api/v1/species/{name}/account
+// Creates the account if it does not exist (201) or replaces it (200).
+// Cross-links to unknown species are saved and reported in missing_links.
+func (s *Server) handlePutSpeciesAccount(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req AccountRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+	if len(req.Markdown) > maxAccountBytes {
+		RespondValidationError(w, []ValidationError{{Field: "markdown", Message: "must be at most 256 KiB"}})
+		return
+	}
+
+	exists, err := s.db.OakEntryExists(name)
+	if err != nil {
+		s.logger.Error("failed to check species existence", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !exists {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+
+	account := &models.SpeciesAccount{ScientificName: name, Markdown: req.Markdown}
+	created, err := s.db.SaveSpeciesAccount(account)
+	if err != nil {
+		s.logger.Error("failed to save species account", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	s.refreshMentions(name)
+	if err := s.renderAccount(account); err != nil {
+		s.logger.Error("failed to check account links", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	RespondJSON(w, status, account)
+}
+
+// handleDeleteSpeciesAccount handles DELETE /api/v1/species/{name}/account
+func (s *Server) handleDeleteSpeciesAccount(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+
+	found, err := s.db.DeleteSpeciesAccount(name)
+	if err != nil {
+		s.logger.Error("failed to delete species account", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !found {
+		RespondNotFound(w, "Account", name)
+		return
+	}
+	s.refreshMentions(name)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// speciesNameParam reads the species name URL parameter, responding with
+// 400 if it is missing or badly encoded
+func speciesNameParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	nameEncoded := chi.URLParam(r, "name")
+	if nameEncoded == "" {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "species name is required")
+		return "", false
+	}
+	name, err := url.PathUnescape(nameEncoded)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid species name encoding")
+		return "", false
+	}
+	return name, true
+}
+
+// renderAccount fills in the account's HTML and references, and lists
+// cross-links to species that are not in the database. Species mentioned by
+// name ("Q. stellata") are linked too.
+func (s *Server) renderAccount(account *models.SpeciesAccount) error {
+	resolver, err := s.db.MentionResolver()
+	if err != nil {
+		return err
+	}
+	res := markdown.Render(account.Markdown, markdown.Options{Linkify: resolver.Find})
+	account.HTML = res.HTML
+	account.Links = res.Links
+	account.Images = res.Images
+	if account.Links == nil {
+		account.Links = []string{}
+	}
+	if account.Images == nil {
+		account.Images = []string{}
+	}
+
+	account.MissingLinks = nil
+	for _, link := range account.Links {
+		if !resolver.Known(link) {
+			account.MissingLinks = append(account.MissingLinks, link)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/jeff/oaks/api/internal/db"
+)
+
+// handleReindex handles POST /api/v1/admin/reindex
+// Rebuilds all derived data (hybrid back-references, indexes, planner statistics)
+// and returns a per-step report.
+func (s *Server) handleReindex(w http.ResponseWriter, r *http.Request) {
+	report, err := s.db.Reindex(func(step db.ReindexStep) {
+		s.logger.Info("reindex step complete", "step", step.Name, "items", step.Items, "duration_ms", step.DurationMs)
+	})
+	if err != nil {
+		s.logger.Error("failed to reindex", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, report)
+}
+
+// RepairPreferredResponse lists species whose duplicate preferred flags were cleared.
+type RepairPreferredResponse struct {
+	Repaired []string `json:"repaired"`
+}
+
+// handleRepairPreferredSources handles POST /api/v1/admin/repair-preferred-sources
+// Clears extra is_preferred flags left by data written before the single-preferred
+// invariant was enforced.
+func (s *Server) handleRepairPreferredSources(w http.ResponseWriter, r *http.Request) {
+	repaired, err := s.db.RepairPreferredSources()
+	if err != nil {
+		s.logger.Error("failed to repair preferred sources", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if len(repaired) > 0 {
+		s.logger.Info("repaired preferred sources", "species", len(repaired))
+	}
+
+	RespondJSON(w, http.StatusOK, RepairPreferredResponse{Repaired: repaired})
+}
+
+// DataErrorsResponse lists stored JSON columns that do not decode.
+type DataErrorsResponse struct {
+	Errors []db.DataError `json:"errors"`
+}
+
+// handleListDataErrors handles GET /api/v1/admin/data-errors
+// Reads return such rows with a data_error marker instead of failing; this
+// lists them all.
+func (s *Server) handleListDataErrors(w http.ResponseWriter, r *http.Request) {
+	found, err := s.db.FindDataErrors()
+	if err != nil {
+		s.logger.Error("failed to check JSON columns", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, DataErrorsResponse{Errors: found})
+}
+
+// handleRepairJSONColumns handles POST /api/v1/admin/repair-json
+// Resets corrupt JSON columns to empty lists and returns what was reset,
+// with the original values.
+func (s *Server) handleRepairJSONColumns(w http.ResponseWriter, r *http.Request) {
+	repaired, err := s.db.RepairJSONColumns()
+	if err != nil {
+		s.logger.Error("failed to repair JSON columns", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if len(repaired) > 0 {
+		s.logger.Info("repaired JSON columns", "columns", len(repaired))
+	}
+
+	RespondJSON(w, http.StatusOK, DataErrorsResponse{Errors: repaired})
+}
+
+// HybridsResponse lists species whose stored hybrids list differs from the
+// one derived from parent links.
+type HybridsResponse struct {
+	Species []db.HybridsDiscrepancy `json:"species"`
+}
+
+// handleCheckHybrids handles GET /api/v1/admin/hybrids
+// Compares every stored hybrids list with the one derived from parent links
+// without changing anything.
+func (s *Server) handleCheckHybrids(w http.ResponseWriter, r *http.Request) {
+	found, err := s.db.FindHybridsDiscrepancies()
+	if err != nil {
+		s.logger.Error("failed to check hybrids lists", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, HybridsResponse{Species: found})
+}
+
+// handleRebuildHybrids handles POST /api/v1/admin/rebuild-hybrids
+// Rewrites every hybrids list from parent links and returns the differences
+// it fixed.
+func (s *Server) handleRebuildHybrids(w http.ResponseWriter, r *http.Request) {
+	rebuilt, err := s.db.RebuildHybrids()
+	if err != nil {
+		s.logger.Error("failed to rebuild hybrids lists", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if len(rebuilt) > 0 {
+		s.logger.Info("rebuilt hybrids lists", "species", len(rebuilt))
+	}
+
+	RespondJSON(w, http.StatusOK, HybridsResponse{Species: rebuilt})
+}
+
+// NameReportResponse lists species whose names are not in canonical form.
+type NameReportResponse struct {
+	Names []db.NameProblem `json:"names"`
+}
+
+// handleNameReport handles GET /api/v1/admin/name-report
+// New names are validated on create; this lists the ones stored before that,
+// with their canonical form where one exists, so they can be renamed.
+func (s *Server) handleNameReport(w http.ResponseWriter, r *http.Request) {
+	found, err := s.db.FindNonconformingNames()
+	if err != nil {
+		s.logger.Error("failed to check species names", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, NameReportResponse{Names: found})
+}
+
+// handleSlowQueries handles GET /api/v1/admin/slow-queries
+// Statements past OAK_SLOW_QUERY_MS are logged as they happen; this counts
+// them per statement since startup, slowest first. Parameter values are never
+// recorded.
+func (s *Server) handleSlowQueries(w http.ResponseWriter, r *http.Request) {
+	RespondJSON(w, http.StatusOK, s.db.SlowQueries())
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// queryPath is exempt from the maintenance gate since it only reads.
+const queryPath = "/api/v1/admin/query"
+
+// QueryRequest is the request body for an ad-hoc read-only query.
+type QueryRequest struct {
+	SQL       string `json:"sql"`
+	Limit     int    `json:"limit,omitempty"`      // Rows to return (default 1000, max 10000)
+	TimeoutMs int    `json:"timeout_ms,omitempty"` // Give up after this long (default 5000, max 30000)
+}
+
+// handleQuery handles POST /api/v1/admin/query
+// Runs SQL on a connection that SQLite only lets read, for reports the API
+// has no endpoint for. Anything else (writes, schema changes, ATTACH, PRAGMA)
+// is refused with 400 before it runs, as are syntax errors and timeouts.
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	var req QueryRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+
+	var errs []ValidationError
+	if strings.TrimSpace(req.SQL) == "" {
+		errs = append(errs, ValidationError{Field: "sql", Message: "sql is required"})
+	}
+	if req.Limit < 0 || req.Limit > db.MaxQueryRowLimit {
+		errs = append(errs, ValidationError{Field: "limit", Message: "limit must be between 1 and " + strconv.Itoa(db.MaxQueryRowLimit)})
+	}
+	maxTimeoutMs := int(db.MaxQueryTimeout.Milliseconds())
+	if req.TimeoutMs < 0 || req.TimeoutMs > maxTimeoutMs {
+		errs = append(errs, ValidationError{Field: "timeout_ms", Message: "timeout_ms must be between 1 and " + strconv.Itoa(maxTimeoutMs)})
+	}
+	if len(errs) > 0 {
+		RespondValidationError(w, errs)
+		return
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = db.DefaultQueryRowLimit
+	}
+	timeout := db.DefaultQueryTimeout
+	if req.TimeoutMs > 0 {
+		timeout = time.Duration(req.TimeoutMs) * time.Millisecond
+	}
+
+	result, err := s.db.ReadOnlyQuery(r.Context(), req.SQL, limit, timeout)
+	var qerr *db.QueryError
+	if errors.As(err, &qerr) {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, qerr.Error())
+		return
+	}
+	if err != nil {
+		s.logger.Error("failed to run query", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	s.logger.Info("ran ad-hoc query", "rows", len(result.Rows), "truncated", result.Truncated, "duration_ms", result.DurationMs)
+
+	RespondJSON(w, http.StatusOK, result)
+}
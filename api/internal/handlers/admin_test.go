@@ -0,0 +1,513 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+func TestReindex(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	// Requires auth
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/reindex", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/admin/reindex", nil)
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var report db.ReindexReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(report.Steps) == 0 {
+		t.Error("expected at least one reindex step")
+	}
+}
+
+func TestRepairPreferredSources(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/repair-preferred-sources", nil)
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var resp RepairPreferredResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Repaired) != 0 {
+		t.Errorf("repaired = %v, want none on a clean database", resp.Repaired)
+	}
+}
+
+func TestDataErrors(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	// Reads need auth: the report includes raw stored values
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/data-errors", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+
+	for _, path := range []string{"/api/v1/admin/data-errors", "/api/v1/admin/repair-json"} {
+		method := http.MethodGet
+		if strings.HasSuffix(path, "repair-json") {
+			method = http.MethodPost
+		}
+		w := send(method, path)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s status = %d, want %d. Body: %s", path, w.Code, http.StatusOK, w.Body.String())
+		}
+		var resp DataErrorsResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.Errors == nil || len(resp.Errors) != 0 {
+			t.Errorf("%s errors = %v, want an empty list on a clean database", path, resp.Errors)
+		}
+	}
+}
+
+func TestRebuildHybrids(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/hybrids", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+
+	if err := server.db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatal(err)
+	}
+	parent := "alba"
+	hybrid := models.NewOakEntry("× bebbiana")
+	hybrid.IsHybrid = true
+	hybrid.Parent1 = &parent
+	if err := server.db.SaveOakEntry(hybrid); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Saving alba without its hybrids leaves the list out of step
+	for _, step := range []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodGet, "/api/v1/admin/hybrids", 1},
+		{http.MethodPost, "/api/v1/admin/rebuild-hybrids", 1},
+		{http.MethodGet, "/api/v1/admin/hybrids", 0},
+	} {
+		w := send(step.method, step.path)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s %s status = %d, want %d. Body: %s", step.method, step.path, w.Code, http.StatusOK, w.Body.String())
+		}
+		var resp HybridsResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(resp.Species) != step.want {
+			t.Errorf("%s %s species = %+v, want %d", step.method, step.path, resp.Species, step.want)
+		}
+	}
+}
+
+func TestSlowQueries(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	// Reads need auth: the report includes SQL text
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/slow-queries", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+
+	server.db.SetSlowQueryThreshold(time.Nanosecond)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/slow-queries", nil)
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	server.Router().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/species", nil))
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var report db.SlowQueryReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if report.Total == 0 || len(report.Queries) == 0 {
+		t.Errorf("report = %+v, want the species list counted", report)
+	}
+}
+
+func TestAdminQuery(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/query", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	for _, name := range []string{"alba", "rubra"} {
+		if err := server.db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := send(`{"sql":"SELECT scientific_name FROM oak_entries ORDER BY scientific_name","limit":1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var result db.QueryResult
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(result.Rows) != 1 || result.Rows[0][0] != "alba" || !result.Truncated {
+		t.Errorf("result = %+v, want alba and truncated", result)
+	}
+
+	tests := map[string]string{
+		`{"sql":"DELETE FROM oak_entries"}`:  "only reads are allowed",
+		`{"sql":"SELEC 1"}`:                  "syntax error",
+		`{"sql":" "}`:                        "sql is required",
+		`{"sql":"SELECT 1","limit":100000}`:  "limit must be",
+		`{"sql":"SELECT 1","timeout_ms":-1}`: "timeout_ms must be",
+	}
+	for body, want := range tests {
+		w := send(body)
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), want) {
+			t.Errorf("%s: status %d, body %s; want 400 mentioning %q", body, w.Code, w.Body.String(), want)
+		}
+	}
+
+	// Still allowed during maintenance since it only reads
+	server.maintenance = MaintenanceStatus{Enabled: true}
+	if w := send(`{"sql":"SELECT 1"}`); w.Code != http.StatusOK {
+		t.Errorf("status during maintenance = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestAPIKeyUsage(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	get := func(path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get("/api/v1/admin/keys", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+
+	// Anonymous and wrong-key requests are not counted
+	get("/api/v1/species", "")
+	get("/api/v1/species", "wrong-key")
+	get("/api/v1/species", "test-api-key")
+
+	// A key that no longer authenticates stays listed with its usage
+	if err := server.db.RecordKeyUsage("0123456789ab", time.Now().AddDate(0, -6, 0)); err != nil {
+		t.Fatalf("RecordKeyUsage failed: %v", err)
+	}
+
+	w := get("/api/v1/admin/keys", "test-api-key")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var list ListResponse[APIKeyInfo]
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	currentID := APIKeyID("test-api-key")
+	if len(list.Data) != 2 {
+		t.Fatalf("got %d keys, want 2: %+v", len(list.Data), list.Data)
+	}
+	// The species read and the listing itself count
+	if k := list.Data[0]; k.ID != currentID || !k.Current || k.Requests != 2 || k.LastUsedAt == nil {
+		t.Errorf("keys[0] = %+v, want the current key with 2 requests", k)
+	}
+	if k := list.Data[1]; k.ID != "0123456789ab" || k.Current || k.Requests != 1 {
+		t.Errorf("keys[1] = %+v, want the retired key with 1 request", k)
+	}
+
+	w = get("/api/v1/admin/keys/"+currentID+"/usage?days=7", "test-api-key")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var usage APIKeyInfo
+	if err := json.NewDecoder(w.Body).Decode(&usage); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if usage.Requests != 3 || len(usage.Days) != 1 || usage.Days[0].Requests != 3 {
+		t.Errorf("usage = %+v, want 3 requests today", usage)
+	}
+
+	if w := get("/api/v1/admin/keys/0123456789ab/usage", "test-api-key"); w.Code != http.StatusOK {
+		t.Errorf("retired key status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w := get("/api/v1/admin/keys/ffffffffffff/usage", "test-api-key"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown key status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := get("/api/v1/admin/keys/"+currentID+"/usage?days=0", "test-api-key"); w.Code != http.StatusBadRequest {
+		t.Errorf("days=0 status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestMaintenanceMode(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	health := func() HealthResponse {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+		var resp HealthResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode health: %v", err)
+		}
+		return resp
+	}
+
+	if w := send(http.MethodPost, "/api/v1/admin/maintenance", `{"mode":"maybe"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid mode status = %d, want 400", w.Code)
+	}
+	if h := health(); h.Maintenance != nil {
+		t.Errorf("health maintenance = %+v before enabling, want none", h.Maintenance)
+	}
+
+	w := send(http.MethodPost, "/api/v1/admin/maintenance", `{"mode":"on","message":"Restoring backup"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("enable status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	// Writes are refused with the message; reads still work
+	w = send(http.MethodPost, "/api/v1/species", `{"scientific_name":"alba"}`)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("write during maintenance status = %d, want 503", w.Code)
+	}
+	var errResp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+		t.Fatalf("failed to decode error: %v", err)
+	}
+	if errResp.Error.Code != apierror.CodeMaintenance || errResp.Error.Message != "Restoring backup" {
+		t.Errorf("error = %+v, want MAINTENANCE with message", errResp.Error)
+	}
+	if w := send(http.MethodGet, "/api/v1/species", ""); w.Code != http.StatusOK {
+		t.Errorf("read during maintenance status = %d, want 200", w.Code)
+	}
+
+	h := health()
+	if h.Status != "ok" || h.Maintenance == nil || !h.Maintenance.Enabled || h.Maintenance.Message != "Restoring backup" || h.Maintenance.Since == nil {
+		t.Errorf("health = %+v, want maintenance with message", h)
+	}
+
+	// Scheduled publishing waits for maintenance to end
+	draft := models.NewOakEntry("velutina")
+	draft.Visibility = models.VisibilityDraft
+	if err := server.db.SaveOakEntry(draft); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	if _, err := server.db.CreateScheduledPublication([]string{"velutina"}, time.Now().Add(-time.Minute), nil); err != nil {
+		t.Fatalf("CreateScheduledPublication failed: %v", err)
+	}
+	server.publishDue(time.Now())
+	if entry, _ := server.db.GetOakEntry("velutina"); entry.Visibility != models.VisibilityDraft {
+		t.Errorf("visibility = %q during maintenance, want draft", entry.Visibility)
+	}
+
+	if w := send(http.MethodPost, "/api/v1/admin/maintenance", `{"mode":"off"}`); w.Code != http.StatusOK {
+		t.Fatalf("disable status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodPost, "/api/v1/species", `{"scientific_name":"alba"}`); w.Code != http.StatusCreated {
+		t.Errorf("write after maintenance status = %d, want 201. Body: %s", w.Code, w.Body.String())
+	}
+	if h := health(); h.Maintenance != nil {
+		t.Errorf("health maintenance = %+v after disabling, want none", h.Maintenance)
+	}
+	server.publishDue(time.Now())
+	if entry, _ := server.db.GetOakEntry("velutina"); entry.Visibility != models.VisibilityPublished {
+		t.Errorf("visibility = %q after maintenance, want published", entry.Visibility)
+	}
+}
+
+func TestSchemaMigration(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, body string, auth bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/admin/schema", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if auth {
+			req.Header.Set("Authorization", "Bearer test-api-key")
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := send(http.MethodGet, "", false); w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	w := send(http.MethodGet, "", true)
+	var status db.SchemaStatus
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatalf("failed to decode status: %v", err)
+	}
+	if status.Latest == 0 || status.Version != status.Latest || len(status.Migrations) != status.Latest {
+		t.Fatalf("schema status = %+v, want every migration applied", status)
+	}
+
+	migrate := func(body string) SchemaMigrateResponse {
+		t.Helper()
+		w := send(http.MethodPost, body, true)
+		if w.Code != http.StatusOK {
+			t.Fatalf("migrate %s status = %d. Body: %s", body, w.Code, w.Body.String())
+		}
+		var resp SchemaMigrateResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp
+	}
+	if resp := migrate(`{"version": 0}`); resp.From != status.Latest || resp.To != 0 || len(resp.Reverted) != status.Latest || len(resp.Applied) != 0 {
+		t.Errorf("revert to 0 = %+v", resp)
+	}
+	if resp := migrate(`{}`); resp.From != 0 || resp.To != status.Latest || len(resp.Applied) != status.Latest || resp.Applied[0] != "0001_oak_entries_genus_index" {
+		t.Errorf("upgrade to latest = %+v", resp)
+	}
+	if w := send(http.MethodPost, `{"version": 999}`, true); w.Code != http.StatusBadRequest {
+		t.Errorf("out-of-range version status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestFeatureFlags(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	features, err := ParseFeatures("species_timeline=off, api_v2")
+	if err != nil {
+		t.Fatalf("ParseFeatures failed: %v", err)
+	}
+	server.features = features
+	if _, err := ParseFeatures("graph=on"); err == nil {
+		t.Error("ParseFeatures(unknown feature) succeeded, want an error")
+	}
+	if err := server.db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatal(err)
+	}
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	timeline := func() FeatureFlag {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/features", nil))
+		var resp FeaturesResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode features: %v", err)
+		}
+		for _, f := range resp.Features {
+			if f.Name == FlagSpeciesTimeline {
+				return f
+			}
+		}
+		t.Fatalf("no %s in %+v", FlagSpeciesTimeline, resp.Features)
+		return FeatureFlag{}
+	}
+
+	if f := timeline(); f.Enabled || f.Source != FlagSourceEnv {
+		t.Errorf("timeline flag = %+v, want off from env", f)
+	}
+	if w := send(http.MethodGet, "/api/v1/species/alba/timeline", ""); w.Code != http.StatusNotFound {
+		t.Errorf("timeline while off status = %d, want 404", w.Code)
+	}
+
+	// An admin override takes precedence
+	if w := send(http.MethodPut, "/api/v1/admin/features/species_timeline", `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("override without enabled status = %d, want 400", w.Code)
+	}
+	if w := send(http.MethodPut, "/api/v1/admin/features/graph", `{"enabled":true}`); w.Code != http.StatusNotFound {
+		t.Errorf("override of unknown flag status = %d, want 404", w.Code)
+	}
+	if w := send(http.MethodPut, "/api/v1/admin/features/species_timeline", `{"enabled":true}`); w.Code != http.StatusOK {
+		t.Fatalf("override status = %d: %s", w.Code, w.Body)
+	}
+	if f := timeline(); !f.Enabled || f.Source != FlagSourceOverride {
+		t.Errorf("timeline flag = %+v, want on by override", f)
+	}
+	if w := send(http.MethodGet, "/api/v1/species/alba/timeline", ""); w.Code != http.StatusOK {
+		t.Errorf("timeline while on status = %d, want 200", w.Code)
+	}
+
+	if w := send(http.MethodDelete, "/api/v1/admin/features/species_timeline", ""); w.Code != http.StatusOK {
+		t.Fatalf("clear override status = %d: %s", w.Code, w.Body)
+	}
+	if f := timeline(); f.Enabled || f.Source != FlagSourceEnv {
+		t.Errorf("timeline flag after clearing = %+v, want off from env", f)
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+const (
+	// defaultPopularDays is the period /stats/popular covers without ?days=
+	defaultPopularDays = 30
+	// maxPopularDays bounds ?days= on /stats/popular
+	maxPopularDays = 366
+)
+
+// PopularSpeciesResponse is the response for GET /api/v1/stats/popular.
+type PopularSpeciesResponse struct {
+	AnalyticsEnabled bool               `json:"analytics_enabled"`
+	Since            string             `json:"since"` // First UTC day counted
+	Days             int                `json:"days"`
+	Data             []*db.SpeciesViews `json:"data"`
+}
+
+// recordSpeciesView counts a public view of a species when analytics are
+// enabled. Curator (authenticated) reads and drafts are not counted, and a
+// failure to count is logged without failing the request.
+func (s *Server) recordSpeciesView(r *http.Request, entry *models.OakEntry) {
+	if !s.analytics || entry.Visibility == models.VisibilityDraft || s.isAuthenticated(r) {
+		return
+	}
+	if err := s.db.RecordSpeciesView(entry.ScientificName, time.Now()); err != nil {
+		s.logger.Warn("failed to record species view", "name", entry.ScientificName, "error", err)
+	}
+}
+
+// handlePopularSpecies handles GET /api/v1/stats/popular
+// Lists the most-viewed species over the last ?days= days (default 30), up to ?limit=.
+func (s *Server) handlePopularSpecies(w http.ResponseWriter, r *http.Request) {
+	var errors []ValidationError
+
+	days := defaultPopularDays
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed < 1 || parsed > maxPopularDays {
+			errors = append(errors, ValidationError{
+				Field:   "days",
+				Message: "must be an integer from 1 to " + strconv.Itoa(maxPopularDays),
+			})
+		} else {
+			days = parsed
+		}
+	}
+
+	limit := defaultLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 {
+			errors = append(errors, ValidationError{
+				Field:   "limit",
+				Message: "must be a positive integer",
+			})
+		} else if parsed > maxLimit {
+			limit = maxLimit
+		} else {
+			limit = parsed
+		}
+	}
+
+	if len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+
+	// Today counts as the first of the days
+	since := time.Now().UTC().AddDate(0, 0, 1-days)
+	popular, err := s.db.PopularSpecies(since, limit)
+	if err != nil {
+		s.logger.Error("failed to list popular species", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if popular == nil {
+		popular = []*db.SpeciesViews{}
+	}
+
+	RespondJSON(w, http.StatusOK, PopularSpeciesResponse{
+		AnalyticsEnabled: s.analytics,
+		Since:            since.Format("2006-01-02"),
+		Days:             days,
+		Data:             popular,
+	})
+}
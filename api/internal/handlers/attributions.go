@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/jeff/oaks/api/internal/db"
+)
+
+// Attribution page formats accepted by ?format=
+const (
+	AttributionFormatJSON     = "json"
+	AttributionFormatMarkdown = "markdown"
+	AttributionFormatHTML     = "html"
+)
+
+// handleGetAttributions handles GET /api/v1/attributions
+// Lists every source with its license, URL, and the species it contributed to.
+// ?format=markdown or ?format=html renders the website's attribution page.
+func (s *Server) handleGetAttributions(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = AttributionFormatJSON
+	}
+	if format != AttributionFormatJSON && format != AttributionFormatMarkdown && format != AttributionFormatHTML {
+		RespondValidationError(w, []ValidationError{{
+			Field:   "format",
+			Message: "format must be json, markdown, or html",
+		}})
+		return
+	}
+
+	attributions, err := s.db.ListSourceAttributions()
+	if err != nil {
+		s.logger.Error("failed to list attributions", "error", err)
+		RespondInternalError(w, "Failed to retrieve attributions")
+		return
+	}
+
+	switch format {
+	case AttributionFormatMarkdown:
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(renderAttributionsMarkdown(attributions)))
+	case AttributionFormatHTML:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(renderAttributionsHTML(attributions)))
+	default:
+		RespondJSON(w, http.StatusOK, attributions)
+	}
+}
+
+// attributionCitation formats the author and year line for a source, or "" if neither is set
+func attributionCitation(a *db.SourceAttribution) string {
+	switch {
+	case a.Author != nil && a.Year != nil:
+		return fmt.Sprintf("%s (%d)", *a.Author, *a.Year)
+	case a.Author != nil:
+		return *a.Author
+	case a.Year != nil:
+		return fmt.Sprintf("%d", *a.Year)
+	}
+	return ""
+}
+
+func renderAttributionsMarkdown(attributions []*db.SourceAttribution) string {
+	var b strings.Builder
+	b.WriteString("# Sources and Attributions\n")
+	for _, a := range attributions {
+		b.WriteString("\n## ")
+		if a.URL != nil {
+			fmt.Fprintf(&b, "[%s](%s)\n\n", a.Name, *a.URL)
+		} else {
+			b.WriteString(a.Name + "\n\n")
+		}
+		if citation := attributionCitation(a); citation != "" {
+			b.WriteString(citation + "\n\n")
+		}
+		if a.License != nil {
+			if a.LicenseURL != nil {
+				fmt.Fprintf(&b, "License: [%s](%s)\n\n", *a.License, *a.LicenseURL)
+			} else {
+				fmt.Fprintf(&b, "License: %s\n\n", *a.License)
+			}
+		}
+		if len(a.Species) == 0 {
+			b.WriteString("No species data.\n")
+			continue
+		}
+		fmt.Fprintf(&b, "Species (%d): ", len(a.Species))
+		for i, name := range a.Species {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString("*" + name + "*")
+			if pages := a.Pages[name]; pages != "" {
+				b.WriteString(" (" + pages + ")")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func renderAttributionsHTML(attributions []*db.SourceAttribution) string {
+	var b strings.Builder
+	b.WriteString("<section class=\"attributions\">\n<h1>Sources and Attributions</h1>\n")
+	for _, a := range attributions {
+		b.WriteString("<article>\n<h2>")
+		if a.URL != nil {
+			fmt.Fprintf(&b, "<a href=\"%s\">%s</a>", html.EscapeString(*a.URL), html.EscapeString(a.Name))
+		} else {
+			b.WriteString(html.EscapeString(a.Name))
+		}
+		b.WriteString("</h2>\n")
+		if citation := attributionCitation(a); citation != "" {
+			fmt.Fprintf(&b, "<p>%s</p>\n", html.EscapeString(citation))
+		}
+		if a.License != nil {
+			license := html.EscapeString(*a.License)
+			if a.LicenseURL != nil {
+				license = fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(*a.LicenseURL), license)
+			}
+			fmt.Fprintf(&b, "<p>License: %s</p>\n", license)
+		}
+		if len(a.Species) == 0 {
+			b.WriteString("<p>No species data.</p>\n")
+		} else {
+			fmt.Fprintf(&b, "<p>Species (%d):</p>\n<ul>\n", len(a.Species))
+			for _, name := range a.Species {
+				if pages := a.Pages[name]; pages != "" {
+					fmt.Fprintf(&b, "<li><em>%s</em> (%s)</li>\n", html.EscapeString(name), html.EscapeString(pages))
+				} else {
+					fmt.Fprintf(&b, "<li><em>%s</em></li>\n", html.EscapeString(name))
+				}
+			}
+			b.WriteString("</ul>\n")
+		}
+		b.WriteString("</article>\n")
+	}
+	b.WriteString("</section>\n")
+	return b.String()
+}
@@ -9,12 +9,17 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/jeff/oaks/api/internal/db"
 )
 
 const (
 	// APIKeyEnvVar is the environment variable name for the API key.
 	APIKeyEnvVar = "OAK_API_KEY"
 
+	// APIKeyFileEnvVar names a file holding the API key, such as a mounted secret.
+	APIKeyFileEnvVar = "OAK_API_KEY_FILE"
+
 	// DefaultAPIKeyPath is the default path for the API key file.
 	DefaultAPIKeyPath = "~/.oak/api_key"
 
@@ -22,50 +27,118 @@ const (
 	apiKeyBytes = 32
 )
 
+// Where an API key came from, in order of precedence
+const (
+	APIKeySourceEnv       = "env"       // OAK_API_KEY
+	APIKeySourceKeyFile   = "key_file"  // File named by OAK_API_KEY_FILE
+	APIKeySourceFile      = "file"      // Default key file
+	APIKeySourceGenerated = "generated" // New key saved to the default key file
+)
+
+// APIKeySource describes where the server's API key came from.
+type APIKeySource struct {
+	Kind string // One of the APIKeySource constants
+	Path string // File the key was read from or saved to; empty for APIKeySourceEnv
+}
+
 // RequireAuth returns middleware that requires Bearer token authentication.
-// It only applies to write methods (POST, PUT, DELETE, PATCH).
+// It only applies to write methods (POST, PUT, DELETE, PATCH), which need a
+// key with the editor role or above.
 // Read methods (GET, HEAD, OPTIONS) pass through without authentication.
 func (s *Server) RequireAuth(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Read methods are public - no auth required
-		if !isWriteMethod(r.Method) {
-			next.ServeHTTP(w, r)
-			return
-		}
-
-		// Write methods require authentication
-		token := extractBearerToken(r)
-		if token == "" {
-			RespondUnauthorized(w, "Missing authorization header")
-			return
+	return s.authorize(func(method string) string {
+		if isWriteMethod(method) {
+			return db.RoleEditor
 		}
-
-		if !ValidateAPIKey(token, s.apiKey) {
-			RespondUnauthorized(w, "Invalid API key")
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
+		return ""
+	})(next)
 }
 
 // ForceAuth returns middleware that requires authentication for ALL methods.
 // Use this for endpoints that need auth but are read-only (e.g., auth verify).
+// Any key may read; writes need the editor role or above.
 func (s *Server) ForceAuth(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		token := extractBearerToken(r)
-		if token == "" {
-			RespondUnauthorized(w, "Missing authorization header")
-			return
+	return s.authorize(func(method string) string {
+		if isWriteMethod(method) {
+			return db.RoleEditor
 		}
+		return db.RoleRead
+	})(next)
+}
 
-		if !ValidateAPIKey(token, s.apiKey) {
-			RespondUnauthorized(w, "Invalid API key")
-			return
+// RequireAdmin is RequireAuth for administration: writes need a key with the
+// admin role, and reads pass through.
+func (s *Server) RequireAdmin(next http.Handler) http.Handler {
+	return s.authorize(func(method string) string {
+		if isWriteMethod(method) {
+			return db.RoleAdmin
 		}
+		return ""
+	})(next)
+}
 
-		next.ServeHTTP(w, r)
-	})
+// ForceAdmin is ForceAuth for administration: every method needs a key with
+// the admin role.
+func (s *Server) ForceAdmin(next http.Handler) http.Handler {
+	return s.authorize(func(string) string { return db.RoleAdmin })(next)
+}
+
+// authorize returns middleware that requires a key whose role is at least
+// minRole(method). Requests for which minRole is "" pass through.
+func (s *Server) authorize(minRole func(method string) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			need := minRole(r.Method)
+			if need == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token := extractBearerToken(r)
+			if token == "" {
+				RespondUnauthorized(w, "Missing authorization header")
+				return
+			}
+
+			role, _, err := s.keyRole(token)
+			if err != nil {
+				s.logger.Error("failed to look up API key", "error", err)
+				RespondInternalError(w, "")
+				return
+			}
+			if role == "" {
+				RespondUnauthorized(w, "Invalid API key")
+				return
+			}
+			if !db.RoleAtLeast(role, need) {
+				RespondForbidden(w, fmt.Sprintf("This API key has the %s role; this needs %s", role, need))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// keyRole returns the role and ID (see APIKeyID) of an API key: admin for
+// the server's configured key, otherwise the role a key was created with.
+// The role is "" if the key is not valid.
+func (s *Server) keyRole(token string) (role, id string, err error) {
+	if ValidateAPIKey(token, s.apiKey) {
+		return db.RoleAdmin, APIKeyID(token), nil
+	}
+	key, err := s.db.GetAPIKeyByHash(apiKeyHash(token))
+	if err != nil || key == nil {
+		return "", "", err
+	}
+	return key.Role, key.ID, nil
+}
+
+// isAuthenticated reports whether the request carries a valid API key, of
+// any role. Public read routes use it to show curators data hidden from
+// everyone else.
+func (s *Server) isAuthenticated(r *http.Request) bool {
+	return s.requestKeyID(r) != ""
 }
 
 // extractBearerToken extracts the token from the Authorization header.
@@ -109,26 +182,39 @@ func GenerateAPIKey() (string, error) {
 	return base64.URLEncoding.EncodeToString(bytes), nil
 }
 
-// LoadAPIKey loads the API key from environment variable or file.
-// Environment variable takes precedence over file.
-// Returns empty string if no key is configured.
-func LoadAPIKey(path string) (string, error) {
-	// Check environment variable first
-	if key := os.Getenv(APIKeyEnvVar); key != "" {
-		return key, nil
+// LoadAPIKey loads the API key from, in order: the OAK_API_KEY environment
+// variable, the file named by OAK_API_KEY_FILE, or the file at path.
+// Returns an empty key if none is configured. A file named by
+// OAK_API_KEY_FILE must exist and hold a key, so a missing secret is an
+// error rather than a reason to generate a new key.
+func LoadAPIKey(path string) (string, APIKeySource, error) {
+	if key := strings.TrimSpace(os.Getenv(APIKeyEnvVar)); key != "" {
+		return key, APIKeySource{Kind: APIKeySourceEnv}, nil
 	}
 
-	// Fall back to file
-	expandedPath := expandPath(path)
-	data, err := os.ReadFile(expandedPath)
+	if keyFile := os.Getenv(APIKeyFileEnvVar); keyFile != "" {
+		source := APIKeySource{Kind: APIKeySourceKeyFile, Path: keyFile}
+		data, err := os.ReadFile(expandPath(keyFile))
+		if err != nil {
+			return "", source, fmt.Errorf("failed to read %s: %w", APIKeyFileEnvVar, err)
+		}
+		key := strings.TrimSpace(string(data))
+		if key == "" {
+			return "", source, fmt.Errorf("%s %s is empty", APIKeyFileEnvVar, keyFile)
+		}
+		return key, source, nil
+	}
+
+	source := APIKeySource{Kind: APIKeySourceFile, Path: path}
+	data, err := os.ReadFile(expandPath(path))
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", nil // No key configured
+			return "", source, nil // No key configured
 		}
-		return "", fmt.Errorf("failed to read API key file: %w", err)
+		return "", source, fmt.Errorf("failed to read API key file: %w", err)
 	}
 
-	return strings.TrimSpace(string(data)), nil
+	return strings.TrimSpace(string(data)), source, nil
 }
 
 // SaveAPIKey saves the API key to the specified file path.
@@ -162,28 +248,25 @@ func expandPath(path string) string {
 	return path
 }
 
-// EnsureAPIKey loads an existing API key or generates a new one if not found.
-// If generated, the key is saved to the specified path.
-func EnsureAPIKey(path string) (string, error) {
-	key, err := LoadAPIKey(path)
-	if err != nil {
-		return "", err
-	}
-
-	if key != "" {
-		return key, nil
+// EnsureAPIKey loads an existing API key (see LoadAPIKey) or, if none is
+// configured, generates one and saves it to path. The source's Kind is
+// APIKeySourceGenerated when a new key was created.
+func EnsureAPIKey(path string) (key string, source APIKeySource, err error) {
+	key, source, err = LoadAPIKey(path)
+	if err != nil || key != "" {
+		return key, source, err
 	}
 
 	// Generate new key
 	key, err = GenerateAPIKey()
 	if err != nil {
-		return "", err
+		return "", source, err
 	}
 
 	// Save to file
 	if err := SaveAPIKey(path, key); err != nil {
-		return "", err
+		return "", source, err
 	}
 
-	return key, nil
+	return key, APIKeySource{Kind: APIKeySourceGenerated, Path: path}, nil
 }
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/notify"
+)
+
+// recordingSender keeps the notifications sent through it
+type recordingSender struct {
+	mu     sync.Mutex
+	events []notify.Event
+}
+
+func (s *recordingSender) Name() string { return "recording" }
+
+func (s *recordingSender) Send(_ context.Context, ev notify.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, ev)
+	return nil
+}
+
+func TestEnsureAPIKeyPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	defaultPath := filepath.Join(dir, "api_key")
+	secretPath := filepath.Join(dir, "secret")
+	if err := os.WriteFile(secretPath, []byte("secret-key\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing configured: a key is generated and saved
+	t.Setenv(APIKeyEnvVar, "")
+	t.Setenv(APIKeyFileEnvVar, "")
+	generated, source, err := EnsureAPIKey(defaultPath)
+	if err != nil {
+		t.Fatalf("EnsureAPIKey() error = %v", err)
+	}
+	if source.Kind != APIKeySourceGenerated || source.Path != defaultPath || generated == "" {
+		t.Errorf("source = %+v, want generated at %s", source, defaultPath)
+	}
+
+	// The saved key is reused
+	key, source, err := EnsureAPIKey(defaultPath)
+	if err != nil || key != generated || source.Kind != APIKeySourceFile {
+		t.Errorf("EnsureAPIKey() = %q, %+v, %v; want saved key from file", key, source, err)
+	}
+
+	// OAK_API_KEY_FILE overrides the default file
+	t.Setenv(APIKeyFileEnvVar, secretPath)
+	key, source, err = EnsureAPIKey(defaultPath)
+	if err != nil || key != "secret-key" || source.Kind != APIKeySourceKeyFile || source.Path != secretPath {
+		t.Errorf("EnsureAPIKey() = %q, %+v, %v; want secret-key from %s", key, source, err, secretPath)
+	}
+
+	// OAK_API_KEY overrides both
+	t.Setenv(APIKeyEnvVar, "env-key")
+	key, source, err = EnsureAPIKey(defaultPath)
+	if err != nil || key != "env-key" || source.Kind != APIKeySourceEnv {
+		t.Errorf("EnsureAPIKey() = %q, %+v, %v; want env-key from env", key, source, err)
+	}
+}
+
+func TestEnsureAPIKeyMissingKeyFile(t *testing.T) {
+	dir := t.TempDir()
+	defaultPath := filepath.Join(dir, "api_key")
+	t.Setenv(APIKeyEnvVar, "")
+
+	// A missing or empty secret is an error, not a reason to generate a key
+	t.Setenv(APIKeyFileEnvVar, filepath.Join(dir, "missing"))
+	if _, _, err := EnsureAPIKey(defaultPath); err == nil {
+		t.Error("EnsureAPIKey() with missing OAK_API_KEY_FILE succeeded, want error")
+	}
+
+	empty := filepath.Join(dir, "empty")
+	if err := os.WriteFile(empty, []byte("\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(APIKeyFileEnvVar, empty)
+	if _, _, err := EnsureAPIKey(defaultPath); err == nil {
+		t.Error("EnsureAPIKey() with empty OAK_API_KEY_FILE succeeded, want error")
+	}
+
+	if _, err := os.Stat(defaultPath); !os.IsNotExist(err) {
+		t.Errorf("default key file was created: %v", err)
+	}
+}
+
+func TestAPIKeyRoles(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	sent := &recordingSender{}
+	WithNotifier(notify.NewWithSenders([]notify.Sender{sent}, nil, nil))(server)
+
+	send := func(method, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	create := func(key, name, role string) CreateAPIKeyResponse {
+		t.Helper()
+		w := send(http.MethodPost, "/api/v1/keys", key, `{"name": "`+name+`", "role": "`+role+`"}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create %s key status = %d, want %d. Body: %s", role, w.Code, http.StatusCreated, w.Body.String())
+		}
+		var resp CreateAPIKeyResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.Key == "" || resp.ID != APIKeyID(resp.Key) || resp.Name != name || resp.Role != role {
+			t.Errorf("created key = %+v", resp)
+		}
+		return resp
+	}
+
+	reader := create("test-api-key", "Reviewer", "read")
+	editor := create("test-api-key", "Collaborator", "editor")
+	admin := create("test-api-key", "Co-maintainer", "admin")
+
+	// Each key created alerts, naming it without revealing it
+	server.notifier.Wait()
+	if len(sent.events) != 3 {
+		t.Fatalf("sent %d notifications, want 3", len(sent.events))
+	}
+	named := false
+	for _, ev := range sent.events {
+		if ev.Type != notify.EventAPIKeyCreated || strings.Contains(ev.Message, reader.Key) ||
+			strings.Contains(ev.Message, editor.Key) || strings.Contains(ev.Message, admin.Key) {
+			t.Errorf("notification = %+v", ev)
+		}
+		named = named || strings.Contains(ev.Message, editor.ID+" (Collaborator)") && strings.Contains(ev.Message, "editor role")
+	}
+	if !named {
+		t.Errorf("notifications = %+v, want one naming the editor key's ID and role", sent.events)
+	}
+
+	if w := send(http.MethodPost, "/api/v1/keys", "test-api-key", `{"name": " ", "role": "owner"}`); w.Code != http.StatusBadRequest ||
+		!strings.Contains(w.Body.String(), "name") || !strings.Contains(w.Body.String(), "read, editor, admin") {
+		t.Errorf("invalid key status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	species := `{"scientific_name": "alba"}`
+	report := `{"title": "Count", "query": "SELECT COUNT(*) AS n FROM oak_entries"}`
+	tests := []struct {
+		name, method, path, key, body string
+		want                          int
+	}{
+		{"read key reads drafts and queues", http.MethodGet, "/api/v1/jobs", reader.Key, "", http.StatusOK},
+		{"read key cannot write", http.MethodPost, "/api/v1/species", reader.Key, species, http.StatusForbidden},
+		{"editor key writes", http.MethodPost, "/api/v1/species", editor.Key, species, http.StatusCreated},
+		{"editor key cannot administer", http.MethodGet, "/api/v1/admin/keys", editor.Key, "", http.StatusForbidden},
+		{"editor key cannot create keys", http.MethodPost, "/api/v1/keys", editor.Key, `{"name": "x", "role": "admin"}`, http.StatusForbidden},
+		{"admin key administers", http.MethodGet, "/api/v1/admin/keys", admin.Key, "", http.StatusOK},
+		{"editor key cannot save report SQL", http.MethodPut, "/api/v1/reports/count", editor.Key, report, http.StatusForbidden},
+		{"admin key saves report SQL", http.MethodPut, "/api/v1/reports/count", admin.Key, report, http.StatusCreated},
+		{"read key runs saved reports", http.MethodGet, "/api/v1/reports/count/run", reader.Key, "", http.StatusOK},
+		{"editor key cannot delete reports", http.MethodDelete, "/api/v1/reports/count", editor.Key, "", http.StatusForbidden},
+		{"unknown key", http.MethodGet, "/api/v1/jobs", "not-a-key", "", http.StatusUnauthorized},
+		{"configured key cannot be revoked", http.MethodDelete, "/api/v1/keys/" + APIKeyID("test-api-key"), admin.Key, "", http.StatusConflict},
+		{"revoke", http.MethodDelete, "/api/v1/keys/" + editor.ID, admin.Key, "", http.StatusNoContent},
+		{"revoked key no longer authenticates", http.MethodDelete, "/api/v1/species/alba", editor.Key, "", http.StatusUnauthorized},
+		{"revoke again", http.MethodDelete, "/api/v1/keys/" + editor.ID, admin.Key, "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if w := send(tt.method, tt.path, tt.key, tt.body); w.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d. Body: %s", tt.name, w.Code, tt.want, w.Body.String())
+		}
+	}
+
+	w := send(http.MethodGet, "/api/v1/auth/verify", reader.Key, "")
+	var verify AuthVerifyResponse
+	if err := json.NewDecoder(w.Body).Decode(&verify); err != nil || verify.Role != "read" {
+		t.Errorf("auth verify = %+v, %v; want role read", verify, err)
+	}
+
+	// The list has the configured key first, then the keys used, then those
+	// created and never used; the revoked key keeps its usage but no role
+	w = send(http.MethodGet, "/api/v1/admin/keys", "test-api-key", "")
+	var list struct{ Data []APIKeyInfo }
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	roles := make(map[string]string)
+	for _, k := range list.Data {
+		roles[k.ID] = k.Role
+	}
+	if len(list.Data) != 4 || !list.Data[0].Current || roles[reader.ID] != "read" || roles[admin.ID] != "admin" || roles[editor.ID] != "" {
+		t.Errorf("keys = %+v", list.Data)
+	}
+}
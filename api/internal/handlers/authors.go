@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// AuthorsUpsertResponse reports the outcome of a bulk author upsert.
+type AuthorsUpsertResponse struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// handleListAuthors handles GET /api/v1/authors
+func (s *Server) handleListAuthors(w http.ResponseWriter, r *http.Request) {
+	authors, err := s.db.ListAuthors()
+	if err != nil {
+		s.logger.Error("failed to list authors", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if authors == nil {
+		authors = []*models.Author{}
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(authors, len(authors), len(authors), 0))
+}
+
+// handleGetAuthor handles GET /api/v1/authors/{abbreviation}
+func (s *Server) handleGetAuthor(w http.ResponseWriter, r *http.Request) {
+	abbreviation, err := url.PathUnescape(chi.URLParam(r, "abbreviation"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid abbreviation encoding")
+		return
+	}
+
+	author, err := s.db.GetAuthor(abbreviation)
+	if err != nil {
+		s.logger.Error("failed to get author", "abbreviation", abbreviation, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if author == nil {
+		RespondNotFound(w, "Author", abbreviation)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, author)
+}
+
+// handlePutAuthors handles PUT /api/v1/authors
+// Creates or replaces every author in the body, as when refreshing from an IPNI export.
+// Authors not in the body are kept.
+func (s *Server) handlePutAuthors(w http.ResponseWriter, r *http.Request) {
+	var authors []*models.Author
+	if err := decodeRequestBody(r, &authors); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+
+	var errors []ValidationError
+	seen := make(map[string]bool, len(authors))
+	for i, a := range authors {
+		field := fmt.Sprintf("items[%d]", i)
+		switch {
+		case a == nil || a.Abbreviation == "":
+			errors = append(errors, ValidationError{Field: field + ".abbreviation", Message: "abbreviation is required"})
+			continue
+		case seen[a.Abbreviation]:
+			errors = append(errors, ValidationError{Field: field + ".abbreviation", Message: fmt.Sprintf("duplicate author '%s'", a.Abbreviation)})
+		}
+		seen[a.Abbreviation] = true
+		if a.FullName == "" {
+			errors = append(errors, ValidationError{Field: field + ".full_name", Message: "full_name is required"})
+		}
+	}
+	if len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+
+	created, updated, err := s.db.UpsertAuthors(authors)
+	if err != nil {
+		s.logger.Error("failed to upsert authors", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, AuthorsUpsertResponse{Created: created, Updated: updated})
+}
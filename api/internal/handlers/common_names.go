@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// CommonNamesRequest is the request body for replacing a species' common names
+type CommonNamesRequest struct {
+	CommonNames []*models.CommonName `json:"common_names"`
+}
+
+// handleListSpeciesCommonNames handles GET /api/v1/species/{name}/common-names
+// Returns the species' common names, by language and then in the order given.
+func (s *Server) handleListSpeciesCommonNames(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+
+	visible, err := s.speciesVisible(r, name)
+	if err != nil {
+		s.logger.Error("failed to check species existence", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !visible {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+
+	names, err := s.db.GetCommonNames(name)
+	if err != nil {
+		s.logger.Error("failed to get common names", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(names, len(names), len(names), 0))
+}
+
+// handlePutSpeciesCommonNames handles PUT /api/v1/species/{name}/common-names
+// Replaces the species' common names. Languages are BCP 47 tags, stored in
+// canonical case; an empty language is stored as "und" (undetermined).
+func (s *Server) handlePutSpeciesCommonNames(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req CommonNamesRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+
+	var validationErrors []ValidationError
+	for i, n := range req.CommonNames {
+		field := "common_names[" + strconv.Itoa(i) + "]"
+		if n == nil {
+			validationErrors = append(validationErrors, ValidationError{Field: field, Message: "is required"})
+			continue
+		}
+		n.Name = strings.TrimSpace(n.Name)
+		if n.Name == "" {
+			validationErrors = append(validationErrors, ValidationError{Field: field + ".name", Message: "is required"})
+		}
+		if strings.TrimSpace(n.Language) == "" {
+			n.Language = models.LanguageUndetermined
+		} else if tag, ok := models.CanonicalLanguageTag(n.Language); ok {
+			n.Language = tag
+		} else {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   field + ".language",
+				Message: fmt.Sprintf("%q is not a BCP 47 language tag", n.Language),
+			})
+		}
+		if n.SourceID != nil {
+			source, err := s.db.GetSource(*n.SourceID)
+			if err != nil {
+				s.logger.Error("failed to get source", "id", *n.SourceID, "error", err)
+				RespondInternalError(w, "")
+				return
+			}
+			if source == nil {
+				validationErrors = append(validationErrors, ValidationError{
+					Field:   field + ".source_id",
+					Message: fmt.Sprintf("source %d does not exist", *n.SourceID),
+				})
+			}
+		}
+	}
+	if len(validationErrors) > 0 {
+		RespondValidationError(w, validationErrors)
+		return
+	}
+
+	visibility, err := s.db.GetOakEntryVisibility(name)
+	if err != nil {
+		s.logger.Error("failed to check species existence", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if visibility == "" {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+
+	if err := s.db.SetCommonNames(name, req.CommonNames); err != nil {
+		s.logger.Error("failed to set common names", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	names, err := s.db.GetCommonNames(name)
+	if err != nil {
+		s.logger.Error("failed to get common names", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(names, len(names), len(names), 0))
+}
+
+// exportLanguages returns the language ranges ?lang= asks the export for:
+// a comma-separated list such as "en,fr-CA", or "auto" for the request's
+// Accept-Language. negotiated reports whether Accept-Language was used.
+// An empty result means every language.
+func exportLanguages(r *http.Request) (ranges []string, negotiated bool, errs []ValidationError) {
+	lang := strings.TrimSpace(r.URL.Query().Get("lang"))
+	if lang == "" {
+		return nil, false, nil
+	}
+	if lang == "auto" {
+		return acceptedLanguages(r.Header.Get("Accept-Language")), true, nil
+	}
+
+	for _, part := range strings.Split(lang, ",") {
+		part = strings.TrimSpace(part)
+		if part == "*" {
+			return nil, false, nil
+		}
+		tag, ok := models.CanonicalLanguageTag(part)
+		if !ok {
+			errs = append(errs, ValidationError{
+				Field:   "lang",
+				Message: fmt.Sprintf("%q is not a BCP 47 language tag", part),
+			})
+			continue
+		}
+		ranges = append(ranges, tag)
+	}
+	return ranges, false, errs
+}
+
+// acceptedLanguages returns the language ranges an Accept-Language header
+// accepts, most preferred first. Ranges with q=0 and malformed ones are
+// dropped, and a "*" range means every language, returned as none.
+func acceptedLanguages(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var accepted []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		if tag == "*" {
+			return nil
+		}
+		if canonical, ok := models.CanonicalLanguageTag(tag); ok {
+			accepted = append(accepted, weighted{canonical, q})
+		}
+	}
+
+	sort.SliceStable(accepted, func(i, j int) bool { return accepted[i].q > accepted[j].q })
+	ranges := make([]string, len(accepted))
+	for i, a := range accepted {
+		ranges[i] = a.tag
+	}
+	return ranges
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// notModified sets Last-Modified from updatedAt and reports whether the
+// request's If-Modified-Since shows the client already has this version, in
+// which case a 304 has been written and the handler should return. A nil
+// updatedAt (rows written before it was tracked) skips both.
+func notModified(w http.ResponseWriter, r *http.Request, updatedAt *time.Time) bool {
+	if updatedAt == nil {
+		return false
+	}
+	w.Header().Set("Last-Modified", updatedAt.UTC().Format(http.TimeFormat))
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || updatedAt.Truncate(time.Second).After(since) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// setETag sets the ETag of a detail response to the entity's content hash,
+// which editors send back in If-Match when they save. Returns false if the
+// hash could not be read, in which case a 500 has been written.
+func (s *Server) setETag(w http.ResponseWriter, kind, key string) bool {
+	hash, err := s.db.GetEntityHash(kind, key)
+	if err != nil {
+		s.logger.Error("failed to get entity hash", "kind", kind, "key", key, "error", err)
+		RespondInternalError(w, "")
+		return false
+	}
+	if hash != "" {
+		w.Header().Set("ETag", `"`+hash+`"`)
+	}
+	return true
+}
+
+// checkIfMatch checks a write's If-Match against the entity's current content
+// hash. It returns the matched hash, which the handler passes to the
+// conditional write so the check is repeated inside the write's transaction,
+// and whether the write must not go ahead, in which case a 412 (or a 500) has
+// been written. Requests without If-Match, or with If-Match: *, match any
+// version and get ""; handlers call this once they know the entity exists.
+func (s *Server) checkIfMatch(w http.ResponseWriter, r *http.Request, kind, key string) (string, bool) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return "", false
+	}
+
+	hash, err := s.db.GetEntityHash(kind, key)
+	if err != nil {
+		s.logger.Error("failed to get entity hash", "kind", kind, "key", key, "error", err)
+		RespondInternalError(w, "")
+		return "", true
+	}
+	if etagMatches(ifMatch, hash) {
+		return hash, false
+	}
+	if hash != "" {
+		w.Header().Set("ETag", `"`+hash+`"`)
+	}
+	RespondPreconditionFailed(w, recordChangedMessage)
+	return "", true
+}
+
+// recordChangedMessage is the message of a 412 for a stale If-Match
+const recordChangedMessage = "The record changed since it was read; fetch it again and reapply your changes"
+
+// respondRecordChanged writes the 412 for a conditional write that failed
+// with db.ErrEntityChanged: the entity changed after checkIfMatch passed.
+func (s *Server) respondRecordChanged(w http.ResponseWriter, kind, key string) {
+	if s.setETag(w, kind, key) {
+		RespondPreconditionFailed(w, recordChangedMessage)
+	}
+}
+
+// etagMatches reports whether an If-Match value (a comma-separated list of
+// entity tags, or *) names the current hash. Weak tags compare by value, as
+// the hashes are of content.
+func etagMatches(ifMatch, hash string) bool {
+	if hash == "" {
+		return false
+	}
+	for _, tag := range strings.Split(ifMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || strings.Trim(tag, `"`) == hash {
+			return true
+		}
+	}
+	return false
+}
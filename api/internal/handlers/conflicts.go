@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/claims"
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// DetectConflictsRequest is the request body for comparing sources' claims.
+// Empty Species compares every species' sources.
+type DetectConflictsRequest struct {
+	Species []string `json:"species,omitempty"`
+}
+
+// ResolveConflictRequest is the request body for resolving a conflict. At
+// least one of PreferredSourceID and Resolution is required, with a justification.
+type ResolveConflictRequest struct {
+	PreferredSourceID *int64 `json:"preferred_source_id,omitempty"` // source_a or source_b
+	Resolution        string `json:"resolution,omitempty"`          // Settled value, e.g. "30 m"
+	Justification     string `json:"justification"`
+}
+
+// handleListConflicts handles GET /api/v1/conflicts
+// Lists open conflicts; ?status=resolved|all, ?species=, and ?property= filter.
+func (s *Server) handleListConflicts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var errors []ValidationError
+	status := query.Get("status")
+	switch status {
+	case "":
+		status = db.ConflictOpen
+	case "all":
+		status = ""
+	case db.ConflictOpen, db.ConflictResolved:
+	default:
+		errors = append(errors, ValidationError{Field: "status", Message: "status must be open, resolved, or all"})
+	}
+	property := query.Get("property")
+	if property != "" && !slices.Contains(claims.Properties, property) {
+		errors = append(errors, ValidationError{
+			Field:   "property",
+			Message: "property must be one of: " + strings.Join(claims.Properties, ", "),
+		})
+	}
+	if len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+
+	conflicts, err := s.db.ListConflicts(status, query.Get("species"), property)
+	if err != nil {
+		s.logger.Error("failed to list conflicts", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if conflicts == nil {
+		conflicts = []*db.Conflict{}
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(conflicts, len(conflicts), len(conflicts), 0))
+}
+
+// handleGetConflict handles GET /api/v1/conflicts/{id}
+func (s *Server) handleGetConflict(w http.ResponseWriter, r *http.Request) {
+	conflict, ok := s.conflictParam(w, r)
+	if !ok {
+		return
+	}
+	RespondJSON(w, http.StatusOK, conflict)
+}
+
+// handleDetectConflicts handles POST /api/v1/conflicts/detect
+// Compares sources' structured claims and records the ones that disagree.
+// Detection also runs whenever species-source text is saved.
+func (s *Server) handleDetectConflicts(w http.ResponseWriter, r *http.Request) {
+	var req DetectConflictsRequest
+	if r.ContentLength != 0 {
+		if err := decodeRequestBody(r, &req); err != nil {
+			RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+			return
+		}
+	}
+
+	var errors []ValidationError
+	for i, name := range req.Species {
+		exists, err := s.db.OakEntryExists(name)
+		if err != nil {
+			s.logger.Error("failed to check species existence", "name", name, "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+		if !exists {
+			errors = append(errors, ValidationError{Field: fmt.Sprintf("species[%d]", i), Message: "species not found: " + name})
+		}
+	}
+	if len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+
+	report, err := s.db.DetectConflicts(req.Species...)
+	if err != nil {
+		s.logger.Error("failed to detect conflicts", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, report)
+}
+
+// handleResolveConflict handles POST /api/v1/conflicts/{id}/resolve
+// Records which source the curator sided with and/or the settled value, and why.
+func (s *Server) handleResolveConflict(w http.ResponseWriter, r *http.Request) {
+	var req ResolveConflictRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+
+	conflict, ok := s.conflictParam(w, r)
+	if !ok {
+		return
+	}
+
+	req.Resolution = strings.TrimSpace(req.Resolution)
+	req.Justification = strings.TrimSpace(req.Justification)
+	var errors []ValidationError
+	if req.Justification == "" {
+		errors = append(errors, ValidationError{Field: "justification", Message: "justification is required"})
+	}
+	if req.PreferredSourceID == nil && req.Resolution == "" {
+		errors = append(errors, ValidationError{Field: "resolution", Message: "give preferred_source_id, resolution, or both"})
+	}
+	if p := req.PreferredSourceID; p != nil && *p != conflict.SourceA && *p != conflict.SourceB {
+		errors = append(errors, ValidationError{
+			Field:   "preferred_source_id",
+			Message: fmt.Sprintf("preferred_source_id must be %d or %d", conflict.SourceA, conflict.SourceB),
+		})
+	}
+	if len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+	if conflict.Status != db.ConflictOpen {
+		RespondConflict(w, fmt.Sprintf("conflict %d is already resolved", conflict.ID))
+		return
+	}
+
+	resolved, err := s.db.ResolveConflict(conflict.ID, req.PreferredSourceID, req.Resolution, req.Justification)
+	if err != nil {
+		s.logger.Error("failed to resolve conflict", "id", conflict.ID, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !resolved {
+		RespondConflict(w, fmt.Sprintf("conflict %d was resolved concurrently", conflict.ID))
+		return
+	}
+
+	if conflict, err = s.db.GetConflict(conflict.ID); err != nil {
+		s.logger.Error("failed to get conflict", "id", conflict.ID, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	RespondJSON(w, http.StatusOK, conflict)
+}
+
+// conflictParam loads the conflict named by the {id} URL parameter,
+// responding with 400 or 404 if there is none
+func (s *Server) conflictParam(w http.ResponseWriter, r *http.Request) (*db.Conflict, bool) {
+	idParam := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "Invalid conflict ID")
+		return nil, false
+	}
+
+	conflict, err := s.db.GetConflict(id)
+	if err != nil {
+		s.logger.Error("failed to get conflict", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return nil, false
+	}
+	if conflict == nil {
+		RespondNotFound(w, "Conflict", idParam)
+		return nil, false
+	}
+	return conflict, true
+}
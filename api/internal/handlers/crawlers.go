@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// crawlerCacheMaxAge is how long shared caches may keep a crawler's read
+const crawlerCacheMaxAge = 3600
+
+// knownCrawlers are user agent tokens of common crawlers, lowercased. Each
+// crawler shares one rate limit however many addresses it crawls from.
+var knownCrawlers = []string{
+	"googlebot", "bingbot", "slurp", "duckduckbot", "baiduspider", "yandexbot",
+	"applebot", "facebookexternalhit", "twitterbot", "linkedinbot", "ahrefsbot",
+	"semrushbot", "mj12bot", "dotbot", "petalbot", "bytespider", "gptbot", "ccbot",
+	"amazonbot", "dataforseobot",
+}
+
+// genericCrawlerMarks catch crawlers not in knownCrawlers, which are
+// limited per address instead
+var genericCrawlerMarks = []string{"bot/", "bot;", "crawler", "spider"}
+
+// robotsAllowed are the reads the public site renders its pages from. The
+// rest of the API, writes included, is off limits to crawlers.
+var robotsAllowed = []string{"/api/v1/species", "/api/v1/taxa", "/api/v1/sources", "/api/v1/stats"}
+
+// robotsDisallowed carves exceptions out of robotsAllowed
+var robotsDisallowed = []string{"/api/v1/species/search"}
+
+// crawlerName returns the crawler a user agent belongs to: a knownCrawlers
+// token, "other" for other crawlers, or "" for everything else
+func crawlerName(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	for _, name := range knownCrawlers {
+		if strings.Contains(ua, name) {
+			return name
+		}
+	}
+	for _, mark := range genericCrawlerMarks {
+		if strings.Contains(ua, mark) {
+			return "other"
+		}
+	}
+	return ""
+}
+
+// crawlerCacheMiddleware lets shared caches keep anonymous crawlers'
+// successful reads for an hour, so repeat crawls are answered without
+// reaching the server. Errors, including 429s, stay uncached, and handlers
+// that set their own Cache-Control keep it.
+func crawlerCacheMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if (r.Method == http.MethodGet || r.Method == http.MethodHead) &&
+			r.Header.Get("Authorization") == "" && crawlerName(r.UserAgent()) != "" {
+			w = &crawlerCacheWriter{ResponseWriter: w}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// crawlerCacheWriter replaces the default no-store Cache-Control on
+// successful responses
+type crawlerCacheWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (cw *crawlerCacheWriter) WriteHeader(code int) {
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		h := cw.Header()
+		if (code == http.StatusOK || code == http.StatusNotModified) && h.Get("Cache-Control") == noStoreCacheControl {
+			h.Set("Cache-Control", "public, max-age="+strconv.Itoa(crawlerCacheMaxAge))
+		}
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *crawlerCacheWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+func (cw *crawlerCacheWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// handleRobots handles GET /robots.txt
+// Allows crawlers only the reads the public site is rendered from, with a
+// crawl delay. The site's own robots.txt points at /sitemap.xml.
+func (s *Server) handleRobots(w http.ResponseWriter, r *http.Request) {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	for _, path := range robotsDisallowed {
+		b.WriteString("Disallow: " + path + "\n")
+	}
+	for _, path := range robotsAllowed {
+		b.WriteString("Allow: " + path + "\n")
+	}
+	b.WriteString("Disallow: /\n")
+	b.WriteString("Crawl-delay: 1\n")
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(b.String()))
+}
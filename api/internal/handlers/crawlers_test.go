@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const googlebotUA = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+
+func TestCrawlerName(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want string
+	}{
+		{googlebotUA, "googlebot"},
+		{"Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)", "bingbot"},
+		{"Mozilla/5.0 (compatible; SomeNewBot/0.1; +https://example.com)", "other"},
+		{"WebCrawler 1.0", "other"},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36", ""},
+		{"oak-cli/1.4.0", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := crawlerName(tt.ua); got != tt.want {
+			t.Errorf("crawlerName(%q) = %q, want %q", tt.ua, got, tt.want)
+		}
+	}
+}
+
+func TestRobots(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/robots.txt", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+	body := w.Body.String()
+	for _, line := range []string{"User-agent: *", "Allow: /api/v1/species", "Disallow: /api/v1/species/search", "Disallow: /", "Crawl-delay: 1"} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("robots.txt lacks %q:\n%s", line, body)
+		}
+	}
+}
+
+func TestCrawlerCaching(t *testing.T) {
+	server, cleanup := testServerWithMiddleware(t)
+	defer cleanup()
+
+	get := func(path, ua, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("User-Agent", ua)
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	if got := get("/api/v1/species", googlebotUA, "").Header().Get("Cache-Control"); got != "public, max-age=3600" {
+		t.Errorf("crawler read Cache-Control = %q, want public, max-age=3600", got)
+	}
+	if got := get("/api/v1/species", "Mozilla/5.0 Firefox/131.0", "").Header().Get("Cache-Control"); got != noStoreCacheControl {
+		t.Errorf("browser read Cache-Control = %q, want %q", got, noStoreCacheControl)
+	}
+	if got := get("/api/v1/species", googlebotUA, "test-api-key").Header().Get("Cache-Control"); got != noStoreCacheControl {
+		t.Errorf("authenticated crawler Cache-Control = %q, want %q", got, noStoreCacheControl)
+	}
+	w := get("/api/v1/species/nonexistent", googlebotUA, "")
+	if w.Code != http.StatusNotFound || w.Header().Get("Cache-Control") != noStoreCacheControl {
+		t.Errorf("crawler 404: status = %d, Cache-Control = %q; want uncached", w.Code, w.Header().Get("Cache-Control"))
+	}
+}
+
+func TestCrawlerRateLimit(t *testing.T) {
+	config := RateLimitConfig{ReadLimit: 100, WriteLimit: 100, BackupLimit: 1, Window: time.Minute, BackupWindow: time.Minute,
+		CrawlerLimit: 1, CrawlerWindow: time.Minute}
+	handler := conditionalRateLimitMiddleware(config, nil)(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }))
+
+	get := func(ua, ip string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/species", nil)
+		req.Header.Set("User-Agent", ua)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), ClientIPKey, ip)))
+		return w.Code
+	}
+
+	// A known crawler shares its limit across addresses
+	if code := get(googlebotUA, "66.249.66.1"); code != http.StatusOK {
+		t.Fatalf("first Googlebot status = %d", code)
+	}
+	if code := get(googlebotUA, "66.249.66.2"); code != http.StatusTooManyRequests {
+		t.Errorf("Googlebot from another address status = %d, want %d", code, http.StatusTooManyRequests)
+	}
+
+	// Unknown crawlers are limited per address; browsers keep the read limit
+	other := "Mozilla/5.0 (compatible; SomeNewBot/0.1)"
+	if get(other, "10.0.0.1") != http.StatusOK || get(other, "10.0.0.2") != http.StatusOK {
+		t.Error("unknown crawlers from different addresses limited together")
+	}
+	if code := get(other, "10.0.0.1"); code != http.StatusTooManyRequests {
+		t.Errorf("second request from an unknown crawler status = %d, want %d", code, http.StatusTooManyRequests)
+	}
+	for range 3 {
+		if code := get("Mozilla/5.0 Firefox/131.0", "10.0.0.1"); code != http.StatusOK {
+			t.Fatalf("browser status = %d, want %d", code, http.StatusOK)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// CustomFieldsResponse lists custom fields by key
+type CustomFieldsResponse struct {
+	Fields []*db.CustomField `json:"fields"`
+}
+
+// CustomFieldRequest is the request body for defining a custom field
+type CustomFieldRequest struct {
+	Type      string `json:"type"`
+	Label     string `json:"label"`
+	AppliesTo string `json:"applies_to"`
+	Export    bool   `json:"export"`
+}
+
+// AttributesRequest sets a record's custom field values; a null value
+// removes one and keys not given are left as they are
+type AttributesRequest struct {
+	Attributes map[string]any `json:"attributes"`
+}
+
+// AttributesResponse is a record's custom field values by key
+type AttributesResponse struct {
+	Attributes map[string]any `json:"attributes"`
+}
+
+// handleListCustomFields handles GET /api/v1/custom-fields
+// ?applies_to=species|source limits the list to one kind of record.
+func (s *Server) handleListCustomFields(w http.ResponseWriter, r *http.Request) {
+	entity := r.URL.Query().Get("applies_to")
+	if entity != "" && entity != db.CustomEntitySpecies && entity != db.CustomEntitySource {
+		RespondValidationError(w, []ValidationError{{Field: "applies_to", Message: "must be species or source"}})
+		return
+	}
+
+	fields, err := s.db.ListCustomFields(entity)
+	if err != nil {
+		s.logger.Error("failed to list custom fields", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	RespondJSON(w, http.StatusOK, CustomFieldsResponse{Fields: fields})
+}
+
+// handlePutCustomField handles PUT /api/v1/custom-fields/{key}
+// Creates the field or updates its label and export flag. Its type and
+// applies_to can't change once records have values for it (409).
+func (s *Server) handlePutCustomField(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	var req CustomFieldRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+
+	var validationErrors []ValidationError
+	if !db.ValidCustomFieldKey(key) {
+		validationErrors = append(validationErrors, ValidationError{Field: "key", Message: "must be lowercase letters, digits, and underscores, starting with a letter"})
+	}
+	if !slices.Contains(db.CustomFieldTypes, req.Type) {
+		validationErrors = append(validationErrors, ValidationError{Field: "type", Message: "must be one of " + strings.Join(db.CustomFieldTypes, ", ")})
+	}
+	req.Label = strings.TrimSpace(req.Label)
+	if req.Label == "" {
+		validationErrors = append(validationErrors, ValidationError{Field: "label", Message: "is required"})
+	}
+	if req.AppliesTo != db.CustomEntitySpecies && req.AppliesTo != db.CustomEntitySource {
+		validationErrors = append(validationErrors, ValidationError{Field: "applies_to", Message: "must be species or source"})
+	}
+	if len(validationErrors) > 0 {
+		RespondValidationError(w, validationErrors)
+		return
+	}
+
+	field := &db.CustomField{Key: key, Type: req.Type, Label: req.Label, AppliesTo: req.AppliesTo, Export: req.Export}
+	if err := s.db.PutCustomField(field); err != nil {
+		if errors.Is(err, db.ErrCustomFieldInUse) {
+			RespondError(w, http.StatusConflict, apierror.CodeConflict, "Records have values for "+key+"; its type and applies_to can't change")
+			return
+		}
+		s.logger.Error("failed to save custom field", "key", key, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	RespondJSON(w, http.StatusOK, field)
+}
+
+// handleDeleteCustomField handles DELETE /api/v1/custom-fields/{key}
+// Deletes the field and every record's value for it.
+func (s *Server) handleDeleteCustomField(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	found, err := s.db.DeleteCustomField(key)
+	if err != nil {
+		s.logger.Error("failed to delete custom field", "key", key, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !found {
+		RespondNotFound(w, "Custom field", key)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetSpeciesAttributes handles GET /api/v1/species/{name}/attributes
+func (s *Server) handleGetSpeciesAttributes(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+	visible, err := s.speciesVisible(r, name)
+	if err != nil {
+		s.logger.Error("failed to check species existence", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !visible {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+	s.respondAttributes(w, db.CustomEntitySpecies, name)
+}
+
+// handlePutSpeciesAttributes handles PUT /api/v1/species/{name}/attributes
+func (s *Server) handlePutSpeciesAttributes(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+	exists, err := s.db.OakEntryExists(name)
+	if err != nil {
+		s.logger.Error("failed to check species existence", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !exists {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+	s.setAttributes(w, r, db.CustomEntitySpecies, name)
+}
+
+// sourceAttributesID returns the {id} of a source that exists, or writes an
+// error and returns false
+func (s *Server) sourceAttributesID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	idParam := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "Invalid source ID")
+		return "", false
+	}
+	source, err := s.db.GetSource(id)
+	if err != nil {
+		s.logger.Error("failed to get source", "error", err, "id", id)
+		RespondInternalError(w, "")
+		return "", false
+	}
+	if source == nil {
+		RespondNotFound(w, "Source", idParam)
+		return "", false
+	}
+	return strconv.FormatInt(id, 10), true
+}
+
+// handleGetSourceAttributes handles GET /api/v1/sources/{id}/attributes
+func (s *Server) handleGetSourceAttributes(w http.ResponseWriter, r *http.Request) {
+	if id, ok := s.sourceAttributesID(w, r); ok {
+		s.respondAttributes(w, db.CustomEntitySource, id)
+	}
+}
+
+// handlePutSourceAttributes handles PUT /api/v1/sources/{id}/attributes
+func (s *Server) handlePutSourceAttributes(w http.ResponseWriter, r *http.Request) {
+	if id, ok := s.sourceAttributesID(w, r); ok {
+		s.setAttributes(w, r, db.CustomEntitySource, id)
+	}
+}
+
+// respondAttributes writes a record's custom field values
+func (s *Server) respondAttributes(w http.ResponseWriter, entity, id string) {
+	values, err := s.db.GetCustomAttributes(entity, id)
+	if err != nil {
+		s.logger.Error("failed to get custom attributes", "entity", entity, "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	RespondJSON(w, http.StatusOK, AttributesResponse{Attributes: values})
+}
+
+// setAttributes checks the request's values against the custom fields for
+// entity and saves them, answering with the record's values
+func (s *Server) setAttributes(w http.ResponseWriter, r *http.Request, entity, id string) {
+	var req AttributesRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+
+	fields, err := s.db.ListCustomFields(entity)
+	if err != nil {
+		s.logger.Error("failed to list custom fields", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	byKey := make(map[string]*db.CustomField, len(fields))
+	for _, f := range fields {
+		byKey[f.Key] = f
+	}
+
+	keys := make([]string, 0, len(req.Attributes))
+	for key := range req.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := make(map[string]*string, len(keys))
+	var validationErrors []ValidationError
+	for _, key := range keys {
+		field := "attributes." + key
+		f := byKey[key]
+		if f == nil {
+			validationErrors = append(validationErrors, ValidationError{Field: field, Message: "is not a custom field of " + entity})
+			continue
+		}
+		if req.Attributes[key] == nil {
+			values[key] = nil
+			continue
+		}
+		stored, err := db.CustomValue(f, req.Attributes[key])
+		if err != nil {
+			validationErrors = append(validationErrors, ValidationError{Field: field, Message: err.Error()})
+			continue
+		}
+		values[key] = &stored
+	}
+	if len(validationErrors) > 0 {
+		RespondValidationError(w, validationErrors)
+		return
+	}
+
+	if err := s.db.SetCustomAttributes(entity, id, values); err != nil {
+		s.logger.Error("failed to set custom attributes", "entity", entity, "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	s.respondAttributes(w, entity, id)
+}
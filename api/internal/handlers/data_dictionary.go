@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/jeff/oaks/api/internal/export"
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// DataDictionary describes the published dataset: every entity of the export
+// (plus taxa, served by /api/v1/taxa), its fields, and the controlled
+// vocabularies some fields draw from.
+type DataDictionary struct {
+	Title        string             `json:"title"`
+	Description  string             `json:"description"`
+	Entities     []DictionaryEntity `json:"entities"`
+	Vocabularies []Vocabulary       `json:"vocabularies"`
+}
+
+// DictionaryEntity is one kind of record and its fields
+type DictionaryEntity struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Fields      []DictionaryField `json:"fields"`
+}
+
+// DictionaryField describes one field. Nested fields are dotted
+// (taxonomy.genus); fields of list items end the list's name with []
+// (external_links[].url). Required fields are always present and never null.
+type DictionaryField struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // string, integer, number, boolean, object, or an entity name; a [] suffix is a list
+	Required    bool   `json:"required"`
+	Description string `json:"description"`
+	Vocabulary  string `json:"vocabulary,omitempty"` // Name of the vocabulary the values come from
+}
+
+// Vocabulary is a controlled list of values
+type Vocabulary struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Values      []VocabularyValue `json:"values"`
+}
+
+// VocabularyValue is one permitted value and what it means
+type VocabularyValue struct {
+	Value   string `json:"value"`
+	Meaning string `json:"meaning,omitempty"`
+}
+
+// dictionaryEntities are the entities described, in order. Their fields are
+// read from the structs, so a new field shows up here; TestDataDictionary
+// fails until it has a description.
+var dictionaryEntities = []struct {
+	name        string
+	description string
+	typ         reflect.Type
+}{
+	{"export", "The full dataset, as downloaded from /api/v1/export", reflect.TypeOf(export.File{})},
+	{"metadata", "When the export was made", reflect.TypeOf(export.Metadata{})},
+	{"species", "An oak species or hybrid and its species-level data. Draft species are never exported.", reflect.TypeOf(export.Species{})},
+	{"species_source", "What one source says about one species. A species has one per source that describes it.", reflect.TypeOf(export.SourceData{})},
+	{"source", "A book, paper, website, or other source of species data", reflect.TypeOf(export.Source{})},
+	{"taxon", "A rank of the taxonomy below genus (subgenus, section, ...), from /api/v1/taxa", reflect.TypeOf(models.Taxon{})},
+}
+
+// dictionaryDescriptions are the meanings of the fields, by entity and field name
+var dictionaryDescriptions = map[string]string{
+	"export.metadata": "When the export was made",
+	"export.sources":  "Every source, whether or not an exported species cites it",
+	"export.species":  "Every published species",
+
+	"metadata.version":       "Timestamp version of the export (UTC), for cache invalidation",
+	"metadata.exported_at":   "When the export was made (RFC 3339)",
+	"metadata.species_count": "Number of species in the export",
+
+	"species.name":                     "Species epithet (alba) or hybrid name (× bebbiana); unique within the dataset. The genus is in taxonomy.genus.",
+	"species.author":                   "Taxonomic authority, such as L. 1753",
+	"species.pronunciation":            "Pronunciation guide for the full name, such as KWER-kus AL-buh",
+	"species.is_hybrid":                "Whether the entry is a hybrid",
+	"species.conservation_status":      "IUCN Red List category",
+	"species.taxonomy":                 "Where the species sits in the taxonomy",
+	"species.taxonomy.genus":           "Genus",
+	"species.taxonomy.subgenus":        "Subgenus",
+	"species.taxonomy.section":         "Section",
+	"species.taxonomy.subsection":      "Subsection",
+	"species.taxonomy.complex":         "Species complex",
+	"species.parent1":                  "First parent species (hybrids only)",
+	"species.parent2":                  "Second parent species (hybrids only)",
+	"species.hybrids":                  "Hybrids this species is a parent of",
+	"species.closely_related_to":       "Closely related species",
+	"species.subspecies_varieties":     "Subspecies and varieties",
+	"species.synonyms":                 "Other names the species has been published under",
+	"species.common_names":             "Common names keyed by BCP 47 language tag (en, fr-CA), in preferred order; und holds names of unknown language. ?lang= limits the languages exported.",
+	"species.external_links":           "Links to the species on other sites",
+	"species.external_links[].name":    "Display label, such as Wikipedia",
+	"species.external_links[].url":     "Link to the species on the site",
+	"species.external_links[].logo":    "Icon shown with the link",
+	"species.sources":                  "What each source says about the species",
+	"species.account":                  "Long-form account; only in exports made with ?accounts=true",
+	"species.account.html":             "The account rendered as HTML, with links to other species",
+	"species.account.updated_at":       "When the account was last edited",
+	"species.custom":                   "Values of this deployment's exported custom fields, by key",
+	"species.localities":               "Reviewed places named in the sources' ranges, with coordinates",
+	"species.localities[].source_id":   "Source whose range names the place (source.id)",
+	"species.localities[].locality":    "The place as written in the range text",
+	"species.localities[].latitude":    "Latitude in decimal degrees (WGS 84)",
+	"species.localities[].longitude":   "Longitude in decimal degrees (WGS 84)",
+	"species.localities[].region":      "The geocoder's full name for the place, such as Edwards Plateau, Texas, United States",
+	"species.localities[].generalized": "For threatened species, the grid cell size in degrees the coordinates were moved to the center of; absent when exact",
+
+	"species_source.source_id":               "ID of the source (source.id)",
+	"species_source.source_name":             "Name of the source",
+	"species_source.source_url":              "The source's home page",
+	"species_source.license":                 "License the source's text is under, such as CC-BY-4.0",
+	"species_source.license_url":             "Link to the license",
+	"species_source.is_preferred":            "Whether this is the species' preferred source; at most one per species",
+	"species_source.local_names":             "Common or local names given by the source",
+	"species_source.range":                   "Geographic range",
+	"species_source.growth_habit":            "Growth habit and size",
+	"species_source.leaves":                  "Leaf description",
+	"species_source.flowers":                 "Flower description",
+	"species_source.fruits":                  "Acorn and cup description",
+	"species_source.bark":                    "Bark description",
+	"species_source.twigs":                   "Twig description",
+	"species_source.buds":                    "Bud description",
+	"species_source.hardiness_habitat":       "Hardiness zones and habitat",
+	"species_source.miscellaneous":           "Other notes",
+	"species_source.distinguishing_features": "What sets the species apart from similar ones",
+	"species_source.url":                     "The source's page for this species",
+	"species_source.pages":                   "Where the data is in a printed source, such as pp. 112-114",
+	"species_source.field_pages":             "Locators for text fields found on other pages than the rest, keyed by field name",
+	"species_source.withheld_fields":         "Text fields recorded but withheld from the public, so left out of this export",
+	"species_source.transcription_method":    "How the text was digitized: manual, ocr, or import",
+	"species_source.ocr_confidence":          "The OCR engine's confidence in the text, from 0 to 1",
+	"species_source.verified":                "Whether a curator has checked the transcribed text against the original; only on transcribed sources",
+
+	"source.id":            "Source ID, referenced by species_source.source_id",
+	"source.source_type":   "Kind of source",
+	"source.name":          "Title of the source",
+	"source.description":   "What the source covers",
+	"source.author":        "Author or authors",
+	"source.year":          "Publication year",
+	"source.url":           "The source's home page",
+	"source.isbn":          "ISBN (books)",
+	"source.doi":           "DOI (papers)",
+	"source.notes":         "Curator notes",
+	"source.license":       "License the source's text is under, such as CC-BY-4.0",
+	"source.license_url":   "Link to the license",
+	"source.superseded_by": "ID of the source that replaces this one, such as a newer edition",
+	"source.custom":        "Values of this deployment's exported custom fields, by key",
+
+	"taxon.name":          "Name of the taxon",
+	"taxon.level":         "Rank of the taxon",
+	"taxon.genus":         "Genus the taxon belongs to",
+	"taxon.parent":        "Name of the taxon one rank up",
+	"taxon.author":        "Taxonomic authority",
+	"taxon.notes":         "Curator notes",
+	"taxon.links":         "Links to the taxon on other sites",
+	"taxon.links[].label": "Display label, such as iNaturalist",
+	"taxon.links[].url":   "Link to the taxon on the site",
+	"taxon.species_count": "Number of species placed in the taxon",
+}
+
+// dictionaryVocabularies names the built-in vocabulary each field draws
+// from. Fields bound to managed vocabularies are looked up in the database.
+var dictionaryVocabularies = map[string]string{
+	"species.taxonomy.genus":        "genus",
+	"species.external_links[].logo": "external_link_logo",
+	"taxon.level":                   "taxon_level",
+	"taxon.genus":                   "genus",
+}
+
+// buildDataDictionary describes the dataset. Genera, taxon levels, and the
+// managed vocabularies are configurable, so they are read from the database.
+func (s *Server) buildDataDictionary() (*DataDictionary, error) {
+	dict := &DataDictionary{
+		Title: "Oak Compendium data dictionary",
+		Description: "Fields of the dataset published at /api/v1/export and /api/v1/taxa. " +
+			"Fields that are not required may be missing or null.",
+	}
+
+	entityNames := make(map[reflect.Type]string, len(dictionaryEntities))
+	for _, e := range dictionaryEntities {
+		entityNames[e.typ] = e.name
+	}
+	vocabularies, err := s.db.ListVocabularies()
+	if err != nil {
+		return nil, err
+	}
+	bound := make(map[string]string, len(vocabularies))
+	for _, v := range vocabularies {
+		if v.Field != nil {
+			bound[*v.Field] = v.Name
+		}
+	}
+	for _, e := range dictionaryEntities {
+		entity := DictionaryEntity{Name: e.name, Description: e.description}
+		addDictionaryFields(&entity, "", e.typ, entityNames)
+		for i, f := range entity.Fields {
+			if name, ok := bound[e.name+"."+f.Name]; ok {
+				entity.Fields[i].Vocabulary = name
+			}
+		}
+		dict.Entities = append(dict.Entities, entity)
+	}
+
+	genera, err := s.db.ListGenera()
+	if err != nil {
+		return nil, err
+	}
+	genusValues := make([]VocabularyValue, len(genera))
+	for i, g := range genera {
+		genusValues[i] = VocabularyValue{Value: g.Name}
+		if g.CommonName != nil {
+			genusValues[i].Meaning = *g.CommonName
+		}
+	}
+	levels, err := s.db.ListTaxonLevels()
+	if err != nil {
+		return nil, err
+	}
+	levelValues := make([]VocabularyValue, len(levels))
+	for i, l := range levels {
+		levelValues[i] = VocabularyValue{Value: l.Name, Meaning: fmt.Sprintf("Rank %d below genus", l.Rank)}
+	}
+
+	dict.Vocabularies = []Vocabulary{
+		{Name: "genus", Description: "Genera in the dataset", Values: genusValues},
+		{Name: "taxon_level", Description: "Ranks below genus, in order", Values: levelValues},
+		{Name: "external_link_logo", Description: "Icons for external links", Values: vocabularyValues(models.ExternalLinkLogos)},
+	}
+	for _, v := range vocabularies {
+		vocabulary := Vocabulary{Name: v.Name, Values: make([]VocabularyValue, len(v.Terms))}
+		if v.Description != nil {
+			vocabulary.Description = *v.Description
+		}
+		for i, t := range v.Terms {
+			vocabulary.Values[i] = VocabularyValue{Value: t.Term}
+			if t.Definition != nil {
+				vocabulary.Values[i].Meaning = *t.Definition
+			}
+		}
+		dict.Vocabularies = append(dict.Vocabularies, vocabulary)
+	}
+	return dict, nil
+}
+
+// addDictionaryFields adds a struct's JSON fields to entity. Nested structs
+// that are not entities of their own are expanded under prefix.
+func addDictionaryFields(entity *DictionaryEntity, prefix string, typ reflect.Type, entityNames map[reflect.Type]string) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := f.Tag.Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		name = prefix + name
+		key := entity.Name + "." + name
+
+		entity.Fields = append(entity.Fields, DictionaryField{
+			Name:        name,
+			Type:        dictionaryType(f.Type, entityNames),
+			Required:    f.Type.Kind() != reflect.Ptr && opts != "omitempty",
+			Description: dictionaryDescriptions[key],
+			Vocabulary:  dictionaryVocabularies[key],
+		})
+
+		inner := f.Type
+		suffix := "."
+		for inner.Kind() == reflect.Ptr || inner.Kind() == reflect.Slice {
+			if inner.Kind() == reflect.Slice {
+				suffix = "[]."
+			}
+			inner = inner.Elem()
+		}
+		if _, isEntity := entityNames[inner]; inner.Kind() == reflect.Struct && !isEntity {
+			addDictionaryFields(entity, name+suffix, inner, entityNames)
+		}
+	}
+}
+
+// dictionaryType names a Go type as a dictionary type
+func dictionaryType(t reflect.Type, entityNames map[reflect.Type]string) string {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return dictionaryType(t.Elem(), entityNames)
+	case reflect.Slice:
+		return dictionaryType(t.Elem(), entityNames) + "[]"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Struct:
+		if name, ok := entityNames[t]; ok {
+			return name
+		}
+		return "object"
+	default:
+		return "string"
+	}
+}
+
+func vocabularyValues(values []string) []VocabularyValue {
+	out := make([]VocabularyValue, len(values))
+	for i, v := range values {
+		out[i] = VocabularyValue{Value: v}
+	}
+	return out
+}
+
+// handleGetDataDictionary handles GET /api/v1/schema/data-dictionary
+func (s *Server) handleGetDataDictionary(w http.ResponseWriter, r *http.Request) {
+	dict, err := s.buildDataDictionary()
+	if err != nil {
+		s.logger.Error("failed to build data dictionary", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, dict)
+}
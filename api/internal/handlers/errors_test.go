@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// decodeError checks that w holds an error response in the shared model,
+// with a code matching its status, and returns the error.
+func decodeError(t *testing.T, label string, w *httptest.ResponseRecorder) apierror.Error {
+	t.Helper()
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("%s: Content-Type = %q, want application/json", label, ct)
+	}
+	var resp apierror.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%s: body is not an error response: %v: %s", label, err, w.Body.String())
+	}
+	if resp.Error.Code == "" || resp.Error.Message == "" {
+		t.Errorf("%s: error lacks a code or message: %s", label, w.Body.String())
+	}
+	if got := apierror.Status(resp.Error.Code); got != w.Code {
+		t.Errorf("%s: status %d does not match code %s (%d)", label, w.Code, resp.Error.Code, got)
+	}
+	return resp.Error
+}
+
+// TestErrorResponses sends requests that fail in each way the API can fail,
+// through the full middleware stack, and checks every failure comes back in
+// the shared error model.
+func TestErrorResponses(t *testing.T) {
+	server, cleanup := testServerWithMiddleware(t)
+	defer cleanup()
+
+	send := func(method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != "" {
+			reader = strings.NewReader(body)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	auth := map[string]string{"Authorization": "Bearer test-api-key"}
+
+	if w := send(http.MethodPost, "/api/v1/species", `{"scientific_name":"alba"}`, auth); w.Code != http.StatusCreated {
+		t.Fatalf("setup: create species = %d: %s", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		header   map[string]string
+		wantCode string
+	}{
+		{"unknown route", http.MethodGet, "/api/v1/nope", "", nil, apierror.CodeNotFound},
+		{"unknown record", http.MethodGet, "/api/v1/species/nosuch", "", nil, apierror.CodeNotFound},
+		{"wrong method", http.MethodPatch, "/api/v1/species/alba", "", auth, apierror.CodeMethodNotAllowed},
+		{"missing key", http.MethodPost, "/api/v1/species", `{"scientific_name":"robur"}`, nil, apierror.CodeUnauthorized},
+		{"malformed body", http.MethodPost, "/api/v1/species", `{`, auth, apierror.CodeValidation},
+		{"invalid field", http.MethodGet, "/api/v1/species?limit=0", "", nil, apierror.CodeValidation},
+		{"duplicate", http.MethodPost, "/api/v1/species", `{"scientific_name":"alba"}`, auth, apierror.CodeConflict},
+		{"stale write", http.MethodPut, "/api/v1/species/alba", `{"scientific_name":"alba"}`,
+			map[string]string{"Authorization": "Bearer test-api-key", "If-Match": `"stale"`}, apierror.CodePreconditionFailed},
+		{"old client", http.MethodGet, "/api/v1/species", "", map[string]string{ClientVersionHeader: "oak-cli/0.0.1"}, apierror.CodeClientTooOld},
+	}
+	for _, tt := range tests {
+		w := send(tt.method, tt.path, tt.body, tt.header)
+		apiErr := decodeError(t, tt.name, w)
+		if apiErr.Code != tt.wantCode {
+			t.Errorf("%s: code = %s, want %s (%s)", tt.name, apiErr.Code, tt.wantCode, w.Body.String())
+		}
+	}
+
+	t.Run("not found names the record", func(t *testing.T) {
+		apiErr := decodeError(t, "unknown record", send(http.MethodGet, "/api/v1/species/nosuch", "", nil))
+		if apiErr.Entity != "species" || apiErr.ID != "nosuch" {
+			t.Errorf("entity, id = %q, %q, want species, nosuch", apiErr.Entity, apiErr.ID)
+		}
+	})
+
+	t.Run("single validation error names the field", func(t *testing.T) {
+		apiErr := decodeError(t, "invalid field", send(http.MethodGet, "/api/v1/species?limit=0", "", nil))
+		if apiErr.Field != "limit" {
+			t.Errorf("field = %q, want limit", apiErr.Field)
+		}
+	})
+
+	t.Run("maintenance", func(t *testing.T) {
+		if w := send(http.MethodPost, "/api/v1/admin/maintenance", `{"mode":"on"}`, auth); w.Code != http.StatusOK {
+			t.Fatalf("enable maintenance = %d: %s", w.Code, w.Body.String())
+		}
+		defer send(http.MethodPost, "/api/v1/admin/maintenance", `{"mode":"off"}`, auth)
+		apiErr := decodeError(t, "maintenance", send(http.MethodPost, "/api/v1/species", `{"scientific_name":"robur"}`, auth))
+		if apiErr.Code != apierror.CodeMaintenance {
+			t.Errorf("code = %s, want %s", apiErr.Code, apierror.CodeMaintenance)
+		}
+	})
+}
+
+func TestErrorResponses_Middleware(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("panic", func(t *testing.T) {
+		handler := recoverMiddleware(logger)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic("boom")
+		}))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/species", nil))
+		if apiErr := decodeError(t, "panic", w); apiErr.Code != apierror.CodeInternal {
+			t.Errorf("code = %s, want %s", apiErr.Code, apierror.CodeInternal)
+		}
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		handler := conditionalRateLimitMiddleware(RateLimitConfig{ReadLimit: 1, WriteLimit: 1, BackupLimit: 1, Window: time.Minute, BackupWindow: time.Minute}, nil)(
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }))
+		var w *httptest.ResponseRecorder
+		for range 2 {
+			w = httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/species", nil))
+		}
+		if apiErr := decodeError(t, "rate limit", w); apiErr.Code != apierror.CodeRateLimited {
+			t.Errorf("code = %s, want %s", apiErr.Code, apierror.CodeRateLimited)
+		}
+		if w.Header().Get("Retry-After") == "" {
+			t.Error("rate limited response lacks Retry-After")
+		}
+	})
+}
@@ -5,24 +5,90 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/jeff/oaks/api/internal/export"
+	"github.com/jeff/oaks/api/internal/models"
 )
 
 // handleExport handles GET /api/v1/export
-// Returns the full database export as JSON.
+// Returns the full database export as JSON, or one genus with ?genus=.
+// ?accounts=true embeds each species' rendered long-form account.
+// ?units=metric|imperial|dual converts measurements in source text.
+// ?mapping=<name> reshapes the payload with a configured export mapping.
+// ?conservation_status=EN,CR or ?threatened=true limits the species exported.
+// ?lang=en,fr limits common names to those languages, and ?lang=auto to
+// the request's Accept-Language.
+// Without any of these it is served from the cached export (see export_cache.go).
 func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	system, ok := unitsParam(w, r)
+	if !ok {
+		return
+	}
+
+	var mapping *export.Mapping
+	if name := r.URL.Query().Get("mapping"); name != "" {
+		if mapping = s.exportMappings[name]; mapping == nil {
+			RespondNotFound(w, "Export mapping", name)
+			return
+		}
+	}
+
+	terms, err := s.fieldTerms(models.VocabularyFieldConservationStatus)
+	if err != nil {
+		s.logger.Error("failed to get conservation status vocabulary", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	statuses, validationErrors := parseConservationStatusParams(r.URL.Query(), terms)
+	if len(validationErrors) > 0 {
+		RespondValidationError(w, validationErrors)
+		return
+	}
+
+	languages, negotiated, langErrors := exportLanguages(r)
+	if len(langErrors) > 0 {
+		RespondValidationError(w, langErrors)
+		return
+	}
+	if negotiated {
+		w.Header().Add("Vary", "Accept-Language")
+	}
+
+	opts := export.Options{
+		Genus:    r.URL.Query().Get("genus"),
+		Accounts: r.URL.Query().Get("accounts") == "true",
+		Units:    system,
+
+		ConservationStatus: statuses,
+		Languages:          languages,
+		Generalize:         s.generalize,
+	}
+	if mapping == nil && opts.Genus == "" && !opts.Accounts && opts.Units == "" && len(opts.ConservationStatus) == 0 && len(opts.Languages) == 0 {
+		s.serveCachedExport(w, r)
+		return
+	}
+
 	// Build export data
-	exportData, err := export.Build(s.db)
+	exportData, err := export.Build(s.db, opts)
 	if err != nil {
 		s.logger.Error("failed to build export", "error", err)
 		RespondInternalError(w, "")
 		return
 	}
 
+	var payload interface{} = exportData
+	if mapping != nil {
+		if payload, err = mapping.Apply(exportData); err != nil {
+			s.logger.Error("failed to apply export mapping", "mapping", r.URL.Query().Get("mapping"), "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+	}
+
 	// Marshal to JSON
-	jsonData, err := json.Marshal(exportData)
+	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		s.logger.Error("failed to marshal export JSON", "error", err)
 		RespondInternalError(w, "")
@@ -42,6 +108,9 @@ func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
 	// Set response headers
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("ETag", etag)
+	if len(opts.Languages) > 0 {
+		w.Header().Set("Content-Language", strings.Join(opts.Languages, ", "))
+	}
 	w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
 	w.Header().Set("Cache-Control", "public, max-age=300") // 5 minute cache
 
@@ -50,3 +119,10 @@ func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
 		s.logger.Error("failed to write export response", "error", err)
 	}
 }
+
+// handleListExportMappings handles GET /api/v1/export/mappings
+// Returns the names of the configured export mappings.
+func (s *Server) handleListExportMappings(w http.ResponseWriter, r *http.Request) {
+	names := export.MappingNames(s.exportMappings)
+	RespondJSON(w, http.StatusOK, NewListResponse(names, len(names), len(names), 0))
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/export"
+)
+
+// ExportCacheDelay is how long writes must pause before the cached export
+// is regenerated, so a burst of writes (an import) rebuilds it once.
+const ExportCacheDelay = 2 * time.Second
+
+// exportSnapshot is the full export, built once and gzip-compressed
+type exportSnapshot struct {
+	gz       []byte
+	etag     string
+	revision uint64 // db.Revision when the build started
+	builtAt  time.Time
+}
+
+// exportCache holds the latest snapshot of the full export. mu is held while
+// building, so concurrent requests for a stale export wait for one build.
+type exportCache struct {
+	mu       sync.Mutex
+	snapshot *exportSnapshot
+}
+
+// cachedExport returns the full export as of the database's current
+// revision, building it if anything was written since the last build.
+func (s *Server) cachedExport() (*exportSnapshot, error) {
+	s.exportCache.mu.Lock()
+	defer s.exportCache.mu.Unlock()
+
+	revision := s.db.Revision()
+	if snap := s.exportCache.snapshot; snap != nil && snap.revision == revision {
+		return snap, nil
+	}
+
+	// Writes during the build advance the revision past this one, so the
+	// snapshot is never taken for newer than it is
+	exportData, err := export.Build(s.db, export.Options{Generalize: s.generalize})
+	if err != nil {
+		return nil, err
+	}
+	jsonData, err := json.Marshal(exportData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal export JSON: %w", err)
+	}
+	var gz bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&gz, gzip.BestCompression)
+	if _, err := zw.Write(jsonData); err != nil {
+		return nil, fmt.Errorf("failed to compress export: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress export: %w", err)
+	}
+
+	hash := sha256.Sum256(jsonData)
+	snap := &exportSnapshot{
+		gz:       gz.Bytes(),
+		etag:     `"` + hex.EncodeToString(hash[:16]) + `"`,
+		revision: revision,
+		builtAt:  time.Now().UTC(),
+	}
+	s.exportCache.snapshot = snap
+	return snap, nil
+}
+
+// RunExportCache builds the cached export now, so the first request after
+// startup is served from it, then rebuilds it whenever writes have paused
+// for delay, until ctx is canceled. Requests that arrive before a rebuild
+// build it themselves, so a stale export is never served.
+func (s *Server) RunExportCache(ctx context.Context, delay time.Duration) {
+	s.warmExportCache()
+
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+	last := s.db.Revision()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		revision := s.db.Revision()
+		if revision == last {
+			s.warmExportCache() // Returns at once if nothing changed
+		}
+		last = revision
+	}
+}
+
+func (s *Server) warmExportCache() {
+	start := time.Now()
+	snap, err := s.cachedExport()
+	if err != nil {
+		s.logger.Error("failed to build cached export", "error", err)
+		return
+	}
+	if snap.builtAt.After(start) {
+		s.logger.Info("built cached export", "bytes", len(snap.gz), "duration_ms", time.Since(start).Milliseconds())
+	}
+}
+
+// serveCachedExport writes the cached full export, gzip-compressed for
+// clients that accept it. Age is the seconds since it was built and
+// X-Export-Revision the database revision it was built at.
+func (s *Server) serveCachedExport(w http.ResponseWriter, r *http.Request) {
+	snap, err := s.cachedExport()
+	if err != nil {
+		s.logger.Error("failed to build export", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	w.Header().Set("ETag", snap.etag)
+	w.Header().Set("Last-Modified", snap.builtAt.Format(http.TimeFormat))
+	w.Header().Set("Cache-Control", "public, max-age=300") // 5 minute cache
+	w.Header().Set("Age", strconv.Itoa(int(time.Since(snap.builtAt).Seconds())))
+	w.Header().Set("X-Export-Revision", strconv.FormatUint(snap.revision, 10))
+	w.Header().Set("Vary", "Accept-Encoding")
+	if r.Header.Get("If-None-Match") == snap.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Length", strconv.Itoa(len(snap.gz)))
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(snap.gz); err != nil {
+			s.logger.Error("failed to write export response", "error", err)
+		}
+		return
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(snap.gz))
+	if err != nil {
+		s.logger.Error("failed to read cached export", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, zr); err != nil {
+		s.logger.Error("failed to write export response", "error", err)
+	}
+}
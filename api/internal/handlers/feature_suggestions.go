@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// GenerateFeatureSuggestionsRequest is the request body for scanning source
+// text for distinguishing-feature sentences. Empty Species scans every species.
+type GenerateFeatureSuggestionsRequest struct {
+	Species []string `json:"species,omitempty"`
+}
+
+// GenerateFeatureSuggestionsResponse reports how many suggestions were queued.
+type GenerateFeatureSuggestionsResponse struct {
+	Queued int `json:"queued"`
+}
+
+// handleListFeatureSuggestions handles GET /api/v1/feature-suggestions
+// Lists pending suggestions; ?status=accepted|rejected|all and ?species= filter.
+func (s *Server) handleListFeatureSuggestions(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = db.SuggestionPending
+	case "all":
+		status = ""
+	case db.SuggestionPending, db.SuggestionAccepted, db.SuggestionRejected:
+	default:
+		RespondValidationError(w, []ValidationError{{
+			Field:   "status",
+			Message: "status must be pending, accepted, rejected, or all",
+		}})
+		return
+	}
+
+	suggestions, err := s.db.ListFeatureSuggestions(status, r.URL.Query().Get("species"))
+	if err != nil {
+		s.logger.Error("failed to list feature suggestions", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if suggestions == nil {
+		suggestions = []*db.FeatureSuggestion{}
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(suggestions, len(suggestions), len(suggestions), 0))
+}
+
+// handleGenerateFeatureSuggestions handles POST /api/v1/feature-suggestions/generate
+// Scans source text for sentences that likely state distinguishing features and queues them for review.
+func (s *Server) handleGenerateFeatureSuggestions(w http.ResponseWriter, r *http.Request) {
+	var req GenerateFeatureSuggestionsRequest
+	if r.ContentLength != 0 {
+		if err := decodeRequestBody(r, &req); err != nil {
+			RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+			return
+		}
+	}
+
+	var errors []ValidationError
+	for i, name := range req.Species {
+		exists, err := s.db.OakEntryExists(name)
+		if err != nil {
+			s.logger.Error("failed to check species existence", "name", name, "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+		if !exists {
+			errors = append(errors, ValidationError{Field: fmt.Sprintf("species[%d]", i), Message: "species not found: " + name})
+		}
+	}
+	if len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+
+	queued, err := s.db.GenerateFeatureSuggestions(req.Species...)
+	if err != nil {
+		s.logger.Error("failed to generate feature suggestions", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, GenerateFeatureSuggestionsResponse{Queued: queued})
+}
+
+// handleAcceptFeatureSuggestion handles POST /api/v1/feature-suggestions/{id}/accept
+// Appends the sentence to the species source's distinguishing_features.
+func (s *Server) handleAcceptFeatureSuggestion(w http.ResponseWriter, r *http.Request) {
+	s.reviewFeatureSuggestion(w, r, true)
+}
+
+// handleRejectFeatureSuggestion handles POST /api/v1/feature-suggestions/{id}/reject
+func (s *Server) handleRejectFeatureSuggestion(w http.ResponseWriter, r *http.Request) {
+	s.reviewFeatureSuggestion(w, r, false)
+}
+
+// reviewFeatureSuggestion accepts or rejects a pending suggestion and responds with it
+func (s *Server) reviewFeatureSuggestion(w http.ResponseWriter, r *http.Request, accept bool) {
+	idParam := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "Invalid suggestion ID")
+		return
+	}
+
+	suggestion, err := s.db.GetFeatureSuggestion(id)
+	if err != nil {
+		s.logger.Error("failed to get feature suggestion", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if suggestion == nil {
+		RespondNotFound(w, "Feature suggestion", idParam)
+		return
+	}
+	if suggestion.Status != db.SuggestionPending {
+		RespondConflict(w, fmt.Sprintf("feature suggestion %d is already %s", id, suggestion.Status))
+		return
+	}
+
+	ok, err := s.db.ReviewFeatureSuggestion(id, accept)
+	if err != nil {
+		s.logger.Error("failed to review feature suggestion", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !ok {
+		RespondConflict(w, fmt.Sprintf("feature suggestion %d was reviewed concurrently", id))
+		return
+	}
+	if accept {
+		s.refreshMentions(suggestion.ScientificName)
+		s.refreshMeasurements(suggestion.ScientificName)
+	}
+
+	if suggestion, err = s.db.GetFeatureSuggestion(id); err != nil {
+		s.logger.Error("failed to get feature suggestion", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	RespondJSON(w, http.StatusOK, suggestion)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// redactSpeciesSources clears the internal fields of species sources read
+// without an API key. Curators see everything.
+func (s *Server) redactSpeciesSources(r *http.Request, sources ...*models.SpeciesSource) {
+	if s.isAuthenticated(r) {
+		return
+	}
+	for _, ss := range sources {
+		ss.Redact()
+	}
+}
+
+// withheldLocations returns the locations ("sources/{id}/{field}") of a
+// species' internal fields when r has no API key, so data derived from them
+// (mentions, measurements, localities) can be left out too. It is nil for
+// curators.
+func (s *Server) withheldLocations(r *http.Request, name string) (map[string]bool, error) {
+	if s.isAuthenticated(r) {
+		return nil, nil
+	}
+	sources, err := s.db.GetSpeciesSources(name)
+	if err != nil {
+		return nil, err
+	}
+	var withheld map[string]bool
+	for _, ss := range sources {
+		for field := range ss.FieldVisibility {
+			if !ss.IsInternal(field) {
+				continue
+			}
+			if withheld == nil {
+				withheld = make(map[string]bool)
+			}
+			withheld[models.TextLocation(ss.SourceID, field)] = true
+		}
+	}
+	return withheld, nil
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// Feature flags gating experimental endpoints and response shapes, so each
+// deployment can turn them on or off. The endpoints flagged so far shipped
+// before the flags did and default on, so deployments keep them. A new
+// experimental endpoint gets a flag here that defaults off, and its route is
+// wrapped in requireFeature, so it ships dark.
+const (
+	FlagAPIV2           = "api_v2"           // The /api/v2 response shapes
+	FlagFullTextSearch  = "fulltext_search"  // GET /search/fulltext
+	FlagRangeGeoJSON    = "range_geojson"    // GET /species/{name}/range.geojson
+	FlagSpeciesTimeline = "species_timeline" // GET /species/{name}/timeline
+)
+
+// featureFlag is a flag's description and default
+type featureFlag struct {
+	Description string
+	Default     bool
+}
+
+// featureFlags are the flags the server knows
+var featureFlags = map[string]featureFlag{
+	FlagAPIV2:           {"API v2 with cursor pagination and structured synonyms and parents", true},
+	FlagFullTextSearch:  {"Ranked full-text search of source data", true},
+	FlagRangeGeoJSON:    {"Species range maps as GeoJSON", true},
+	FlagSpeciesTimeline: {"Per-species activity timeline", true},
+}
+
+// Where a flag's value came from
+const (
+	FlagSourceDefault  = "default"  // The flag's built-in default
+	FlagSourceEnv      = "env"      // OAK_FEATURES, see WithFeatures
+	FlagSourceOverride = "override" // Set by an admin at runtime, kept in the database
+)
+
+// FeatureFlag is a flag's current state
+type FeatureFlag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	Source      string `json:"source"`
+}
+
+// FeaturesResponse lists every feature flag by name
+type FeaturesResponse struct {
+	Features []FeatureFlag `json:"features"`
+}
+
+// FeatureFlagRequest is the request body for overriding a flag
+type FeatureFlagRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// ParseFeatures parses an OAK_FEATURES value: comma-separated name=on or
+// name=off settings, or a bare name to turn it on. Unknown names are an
+// error so a typo doesn't silently leave a feature in its default state.
+func ParseFeatures(value string) (map[string]bool, error) {
+	features := make(map[string]bool)
+	for _, setting := range strings.Split(value, ",") {
+		setting = strings.TrimSpace(setting)
+		if setting == "" {
+			continue
+		}
+		name, state, hasState := strings.Cut(setting, "=")
+		name = strings.TrimSpace(name)
+		if _, ok := featureFlags[name]; !ok {
+			return nil, fmt.Errorf("unknown feature %q", name)
+		}
+		enabled := true
+		if hasState {
+			switch strings.ToLower(strings.TrimSpace(state)) {
+			case "on", "true", "1":
+			case "off", "false", "0":
+				enabled = false
+			default:
+				return nil, fmt.Errorf("feature %s: want on or off, got %q", name, state)
+			}
+		}
+		features[name] = enabled
+	}
+	return features, nil
+}
+
+// WithFeatures sets feature flags for this deployment, overriding their
+// defaults. Runtime overrides stored in the database take precedence.
+func WithFeatures(features map[string]bool) ServerOption {
+	return func(s *Server) {
+		s.features = features
+	}
+}
+
+// featureFlag returns a flag's current state
+func (s *Server) featureFlag(name string, overrides map[string]bool) FeatureFlag {
+	def := featureFlags[name]
+	flag := FeatureFlag{Name: name, Description: def.Description, Enabled: def.Default, Source: FlagSourceDefault}
+	if enabled, ok := s.features[name]; ok {
+		flag.Enabled, flag.Source = enabled, FlagSourceEnv
+	}
+	if enabled, ok := overrides[name]; ok {
+		flag.Enabled, flag.Source = enabled, FlagSourceOverride
+	}
+	return flag
+}
+
+// requireFeature answers 404 for the routes it wraps while the flag is
+// off, as if they did not exist
+func (s *Server) requireFeature(name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			overrides, err := s.db.FeatureFlagOverrides()
+			if err != nil {
+				s.logger.Error("failed to read feature flags", "error", err)
+				RespondInternalError(w, "")
+				return
+			}
+			if !s.featureFlag(name, overrides).Enabled {
+				s.handleNotFound(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// handleListFeatures handles GET /api/v1/features
+func (s *Server) handleListFeatures(w http.ResponseWriter, r *http.Request) {
+	overrides, err := s.db.FeatureFlagOverrides()
+	if err != nil {
+		s.logger.Error("failed to read feature flags", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	names := make([]string, 0, len(featureFlags))
+	for name := range featureFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	resp := FeaturesResponse{Features: make([]FeatureFlag, 0, len(names))}
+	for _, name := range names {
+		resp.Features = append(resp.Features, s.featureFlag(name, overrides))
+	}
+	RespondJSON(w, http.StatusOK, resp)
+}
+
+// featureParam returns the {name} URL parameter, or writes 404 and returns
+// false if it is not a known flag
+func featureParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	name := chi.URLParam(r, "name")
+	if _, ok := featureFlags[name]; !ok {
+		RespondNotFound(w, "Feature", name)
+		return "", false
+	}
+	return name, true
+}
+
+// handleSetFeature handles PUT /api/v1/admin/features/{name}
+// Turns the flag on or off for this deployment until the override is cleared.
+func (s *Server) handleSetFeature(w http.ResponseWriter, r *http.Request) {
+	name, ok := featureParam(w, r)
+	if !ok {
+		return
+	}
+	var req FeatureFlagRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+	if req.Enabled == nil {
+		RespondValidationError(w, []ValidationError{{Field: "enabled", Message: "enabled is required"}})
+		return
+	}
+
+	if err := s.db.SetFeatureFlag(name, *req.Enabled); err != nil {
+		s.logger.Error("failed to set feature flag", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	s.logger.Info("feature flag set", "name", name, "enabled", *req.Enabled)
+	RespondJSON(w, http.StatusOK, s.featureFlag(name, map[string]bool{name: *req.Enabled}))
+}
+
+// handleClearFeature handles DELETE /api/v1/admin/features/{name}
+// Returns the flag to its OAK_FEATURES or default value.
+func (s *Server) handleClearFeature(w http.ResponseWriter, r *http.Request) {
+	name, ok := featureParam(w, r)
+	if !ok {
+		return
+	}
+	if err := s.db.ClearFeatureFlag(name); err != nil {
+		s.logger.Error("failed to clear feature flag", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	s.logger.Info("feature flag override cleared", "name", name)
+	RespondJSON(w, http.StatusOK, s.featureFlag(name, nil))
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// GenusRequest is the request body for creating or updating a genus.
+type GenusRequest struct {
+	Name       string   `json:"name"`
+	CommonName *string  `json:"common_name,omitempty"`
+	Subgenera  []string `json:"subgenera,omitempty"` // Valid subgenus names; empty allows any
+}
+
+// validateGenusName checks that a genus name is a single capitalized word.
+func validateGenusName(name string) []ValidationError {
+	switch {
+	case name == "":
+		return []ValidationError{{Field: "name", Message: "is required"}}
+	case len(name) > 100 || strings.ContainsAny(name, " \t/") || strings.ToUpper(name[:1]) != name[:1]:
+		return []ValidationError{{Field: "name", Message: "must be a single capitalized word (e.g. Carya)"}}
+	}
+	return nil
+}
+
+// checkGenus validates a genus and optional subgenus for a species write.
+// An unknown genus, or a subgenus the genus does not list, is a validation error.
+func (s *Server) checkGenus(genusName string, subgenus *string) ([]ValidationError, error) {
+	genus, err := s.db.GetGenus(genusName)
+	if err != nil {
+		return nil, err
+	}
+	if genus == nil {
+		return []ValidationError{{Field: "genus", Message: "unknown genus: " + genusName}}, nil
+	}
+	if subgenus == nil || *subgenus == "" || len(genus.Subgenera) == 0 {
+		return nil, nil
+	}
+	for _, sg := range genus.Subgenera {
+		if sg == *subgenus {
+			return nil, nil
+		}
+	}
+	return []ValidationError{{
+		Field:   "subgenus",
+		Message: "must be one of: " + strings.Join(genus.Subgenera, ", "),
+	}}, nil
+}
+
+// genusParam reads the optional ?genus= filter.
+func genusParam(r *http.Request) *string {
+	if genus := r.URL.Query().Get("genus"); genus != "" {
+		return &genus
+	}
+	return nil
+}
+
+// handleListGenera handles GET /api/v1/genera
+func (s *Server) handleListGenera(w http.ResponseWriter, r *http.Request) {
+	genera, err := s.db.ListGenera()
+	if err != nil {
+		s.logger.Error("failed to list genera", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if genera == nil {
+		genera = []*models.Genus{}
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(genera, len(genera), len(genera), 0))
+}
+
+// handleGetGenus handles GET /api/v1/genera/{name}
+func (s *Server) handleGetGenus(w http.ResponseWriter, r *http.Request) {
+	name, err := url.PathUnescape(chi.URLParam(r, "name"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid genus name encoding")
+		return
+	}
+
+	genus, err := s.db.GetGenus(name)
+	if err != nil {
+		s.logger.Error("failed to get genus", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if genus == nil {
+		RespondNotFound(w, "Genus", name)
+		return
+	}
+	if s.respondHashOnly(w, r, db.HashKindGenera, name) {
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, genus)
+}
+
+// handleCreateGenus handles POST /api/v1/genera
+func (s *Server) handleCreateGenus(w http.ResponseWriter, r *http.Request) {
+	var req GenusRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+	if errors := validateGenusName(req.Name); len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+
+	existing, err := s.db.GetGenus(req.Name)
+	if err != nil {
+		s.logger.Error("failed to check genus existence", "name", req.Name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if existing != nil {
+		RespondConflict(w, "genus already exists: "+req.Name)
+		return
+	}
+
+	genus := &models.Genus{Name: req.Name, CommonName: req.CommonName, Subgenera: req.Subgenera}
+	if err := s.db.SaveGenus(genus); err != nil {
+		s.logger.Error("failed to create genus", "name", req.Name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if genus.Subgenera == nil {
+		genus.Subgenera = []string{}
+	}
+
+	RespondJSON(w, http.StatusCreated, genus)
+}
+
+// handleUpdateGenus handles PUT /api/v1/genera/{name}
+// Replaces the common name and subgenus list; the name itself cannot change.
+func (s *Server) handleUpdateGenus(w http.ResponseWriter, r *http.Request) {
+	name, err := url.PathUnescape(chi.URLParam(r, "name"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid genus name encoding")
+		return
+	}
+
+	var req GenusRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+
+	genus, err := s.db.GetGenus(name)
+	if err != nil {
+		s.logger.Error("failed to get genus for update", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if genus == nil {
+		RespondNotFound(w, "Genus", name)
+		return
+	}
+
+	genus.CommonName = req.CommonName
+	genus.Subgenera = req.Subgenera
+	if err := s.db.SaveGenus(genus); err != nil {
+		s.logger.Error("failed to update genus", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if genus.Subgenera == nil {
+		genus.Subgenera = []string{}
+	}
+
+	RespondJSON(w, http.StatusOK, genus)
+}
+
+// handleDeleteGenus handles DELETE /api/v1/genera/{name}
+// Refuses for the default genus and while any species or taxa still belong to the genus.
+func (s *Server) handleDeleteGenus(w http.ResponseWriter, r *http.Request) {
+	name, err := url.PathUnescape(chi.URLParam(r, "name"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid genus name encoding")
+		return
+	}
+	if name == models.DefaultGenus {
+		RespondConflict(w, "the default genus cannot be deleted: "+name)
+		return
+	}
+
+	entries, taxa, err := s.db.GenusUsage(name)
+	if err != nil {
+		s.logger.Error("failed to check genus usage", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if entries > 0 || taxa > 0 {
+		RespondConflict(w, fmt.Sprintf("genus %s still has %d species and %d taxa", name, entries, taxa))
+		return
+	}
+
+	found, err := s.db.DeleteGenus(name)
+	if err != nil {
+		s.logger.Error("failed to delete genus", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !found {
+		RespondNotFound(w, "Genus", name)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
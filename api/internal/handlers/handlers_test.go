@@ -3,17 +3,33 @@ package handlers
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
 	"io"
 	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/export"
+	"github.com/jeff/oaks/api/internal/geocode"
+	"github.com/jeff/oaks/api/internal/inaturalist"
+	"github.com/jeff/oaks/api/internal/media"
 	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/api/internal/rangemap"
+	"github.com/jeff/oaks/pkg/apierror"
 )
 
 // testServer creates a test server with an in-memory database
@@ -88,6 +104,69 @@ func TestHealth(t *testing.T) {
 	}
 }
 
+func TestListenPickedPort(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	listener, err := server.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	go server.Serve(listener)
+	defer server.Shutdown(context.Background())
+
+	if server.Addr() == "" || strings.HasSuffix(server.Addr(), ":0") {
+		t.Fatalf("Addr() = %q, want the chosen port", server.Addr())
+	}
+
+	resp, err := http.Get("http://" + server.Addr() + "/health")
+	if err != nil {
+		t.Fatalf("GET /health failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var health HealthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if health.Addr != server.Addr() {
+		t.Errorf("health addr = %q, want %q", health.Addr, server.Addr())
+	}
+}
+
+func TestListenReusePort(t *testing.T) {
+	if !reusePortSupported {
+		t.Skip("SO_REUSEPORT is not supported on this platform")
+	}
+	database, err := db.New(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	defer database.Close()
+	version := VersionInfo{API: "1.0.0", MinClient: "1.0.0"}
+
+	first := New(database, "test-api-key", nil, version, WithoutMiddleware(), WithReusePort())
+	ln1, err := first.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("first Listen failed: %v", err)
+	}
+	defer ln1.Close()
+
+	// A second server may bind the same port only with SO_REUSEPORT
+	if ln, err := New(database, "test-api-key", nil, version, WithoutMiddleware()).Listen(first.Addr()); err == nil {
+		ln.Close()
+		t.Fatalf("Listen without reuse port on %s succeeded, want address in use", first.Addr())
+	}
+	second := New(database, "test-api-key", nil, version, WithoutMiddleware(), WithReusePort())
+	ln2, err := second.Listen(first.Addr())
+	if err != nil {
+		t.Fatalf("second Listen with reuse port failed: %v", err)
+	}
+	defer ln2.Close()
+	if second.Addr() != first.Addr() {
+		t.Errorf("second Addr() = %q, want %q", second.Addr(), first.Addr())
+	}
+}
+
 func TestSpeciesCRUD(t *testing.T) {
 	server, cleanup := testServer(t)
 	defer cleanup()
@@ -330,58 +409,3368 @@ func TestSpeciesSourcesCRUD(t *testing.T) {
 	req = httptest.NewRequest(http.MethodPost, "/api/v1/sources", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer test-api-key")
-	w = httptest.NewRecorder()
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create source status = %d, want %d", w.Code, http.StatusCreated)
+	}
+
+	// Create a species-source record
+	leaves := "Large lobed leaves"
+	ss := models.SpeciesSource{
+		ScientificName: "alba",
+		SourceID:       1,
+		Leaves:         &leaves,
+		LocalNames:     []string{"white oak"},
+		IsPreferred:    true,
+	}
+	body, _ = json.Marshal(ss)
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/species/alba/sources", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create species-source status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	// List species sources
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/species/alba/sources", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("list status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// Get specific species source
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/species/alba/sources/1", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("get status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// Delete species source
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/species/alba/sources/1", nil)
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+func TestSpeciesSourceContentHash(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, hash string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		if hash != "" {
+			req.Header.Set(ContentHashHeader, hash)
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", "", models.OakEntry{ScientificName: "alba"})
+	send(http.MethodPost, "/api/v1/sources", "", models.Source{SourceType: "website", Name: "Scraped"})
+
+	leaves := "Lobed"
+	ss := SpeciesSourceRequest{SourceID: 1, Leaves: &leaves}
+
+	w := send(http.MethodPost, "/api/v1/species/alba/sources", "h1", ss)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	if got := w.Header().Get(ContentHashHeader); got != "h1" {
+		t.Errorf("%s = %q, want h1", ContentHashHeader, got)
+	}
+
+	// Same hash: write is skipped, for both POST and PUT
+	w = send(http.MethodPost, "/api/v1/species/alba/sources", "h1", ss)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("repeat create status = %d, want %d", w.Code, http.StatusNotModified)
+	}
+	changed := "Entire"
+	w = send(http.MethodPut, "/api/v1/species/alba/sources/1", "h1", SpeciesSourceRequest{SourceID: 1, Leaves: &changed})
+	if w.Code != http.StatusNotModified {
+		t.Errorf("unchanged update status = %d, want %d", w.Code, http.StatusNotModified)
+	}
+
+	// Without a hash, a duplicate create still conflicts
+	w = send(http.MethodPost, "/api/v1/species/alba/sources", "", ss)
+	if w.Code != http.StatusConflict {
+		t.Errorf("create without hash status = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	// New hash: write goes through
+	w = send(http.MethodPut, "/api/v1/species/alba/sources/1", "h2", SpeciesSourceRequest{SourceID: 1, Leaves: &changed})
+	if w.Code != http.StatusOK {
+		t.Fatalf("changed update status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var updated models.SpeciesSource
+	if err := json.NewDecoder(w.Body).Decode(&updated); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if updated.Leaves == nil || *updated.Leaves != "Entire" {
+		t.Errorf("leaves = %v, want Entire", updated.Leaves)
+	}
+
+	// An unhashed edit clears the stored hash, so the next hashed write applies
+	w = send(http.MethodPut, "/api/v1/species/alba/sources/1", "", SpeciesSourceRequest{SourceID: 1, Leaves: &leaves})
+	if w.Code != http.StatusOK {
+		t.Fatalf("manual update status = %d, want %d", w.Code, http.StatusOK)
+	}
+	w = send(http.MethodPut, "/api/v1/species/alba/sources/1", "h2", SpeciesSourceRequest{SourceID: 1, Leaves: &changed})
+	if w.Code != http.StatusOK {
+		t.Errorf("update after manual edit status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestBulkUpsertSpeciesSources(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", models.OakEntry{ScientificName: "alba"})
+	send(http.MethodPost, "/api/v1/species", models.OakEntry{ScientificName: "rubra"})
+	send(http.MethodPost, "/api/v1/sources", models.Source{SourceType: "website", Name: "Scraped"})
+
+	leaves := "Lobed"
+	items := []BulkSpeciesSourceItem{
+		{ScientificName: "alba", ContentHash: "a1", SpeciesSourceRequest: SpeciesSourceRequest{Leaves: &leaves}},
+		{ScientificName: "rubra", ContentHash: "r1"},
+	}
+	w := send(http.MethodPut, "/api/v1/sources/1/species-sources", items)
+	if w.Code != http.StatusOK {
+		t.Fatalf("bulk status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp BulkSpeciesSourcesResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Created != 2 || len(resp.Results) != 2 {
+		t.Errorf("created = %d, results = %d, want 2, 2", resp.Created, len(resp.Results))
+	}
+
+	// Re-sync: one unchanged, one updated
+	items[1].ContentHash = "r2"
+	w = send(http.MethodPut, "/api/v1/sources/1/species-sources", items)
+	if w.Code != http.StatusOK {
+		t.Fatalf("resync status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	resp = BulkSpeciesSourcesResponse{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Unchanged != 1 || resp.Updated != 1 || resp.Created != 0 {
+		t.Errorf("got created=%d updated=%d unchanged=%d, want 0/1/1", resp.Created, resp.Updated, resp.Unchanged)
+	}
+
+	// A bad row rolls back the whole batch
+	changed := "Entire"
+	w = send(http.MethodPut, "/api/v1/sources/1/species-sources", []BulkSpeciesSourceItem{
+		{ScientificName: "alba", SpeciesSourceRequest: SpeciesSourceRequest{Leaves: &changed}},
+		{ScientificName: "missing"},
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("bad batch status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if !strings.Contains(w.Body.String(), "items[1]") {
+		t.Errorf("body = %s, want error for items[1]", w.Body.String())
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/species/alba/sources/1", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	var ss models.SpeciesSource
+	if err := json.NewDecoder(w.Body).Decode(&ss); err != nil {
+		t.Fatalf("failed to decode species source: %v", err)
+	}
+	if ss.Leaves == nil || *ss.Leaves != "Lobed" {
+		t.Errorf("leaves = %v, want Lobed (batch should have rolled back)", ss.Leaves)
+	}
+
+	// Unknown source
+	w = send(http.MethodPut, "/api/v1/sources/99/species-sources", items)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown source status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	// Duplicate species in one batch
+	w = send(http.MethodPut, "/api/v1/sources/1/species-sources", []BulkSpeciesSourceItem{{ScientificName: "alba"}, {ScientificName: "alba"}})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("duplicate status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	// Items are validated like single species source writes
+	confidence := 2.0
+	w = send(http.MethodPut, "/api/v1/sources/1/species-sources", []BulkSpeciesSourceItem{
+		{ScientificName: "alba"},
+		{ScientificName: "rubra", SpeciesSourceRequest: SpeciesSourceRequest{OCRConfidence: &confidence}},
+	})
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "items[1].ocr_confidence") {
+		t.Errorf("invalid item = %d %s, want 400 for items[1].ocr_confidence", w.Code, w.Body.String())
+	}
+}
+
+func TestBulkUpsertSpeciesSourcesBodyLimit(t *testing.T) {
+	server, cleanup := testServerWithMiddleware(t)
+	defer cleanup()
+
+	send := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	bulk := func(size int) []byte {
+		leaves := strings.Repeat("a", size)
+		body, _ := json.Marshal([]BulkSpeciesSourceItem{{ScientificName: "alba", SpeciesSourceRequest: SpeciesSourceRequest{Leaves: &leaves}}})
+		return body
+	}
+
+	species, _ := json.Marshal(models.OakEntry{ScientificName: "alba"})
+	send(http.MethodPost, "/api/v1/species", species)
+	source, _ := json.Marshal(models.Source{SourceType: "website", Name: "Scraped"})
+	send(http.MethodPost, "/api/v1/sources", source)
+
+	// Larger than other writes may be
+	if w := send(http.MethodPut, "/api/v1/sources/1/species-sources", bulk(2*maxBodySize)); w.Code != http.StatusOK {
+		t.Fatalf("2MB bulk status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	leaves := strings.Repeat("a", 2*maxBodySize)
+	single, _ := json.Marshal(SpeciesSourceRequest{Leaves: &leaves})
+	if w := send(http.MethodPut, "/api/v1/species/alba/sources/1", single); w.Code != http.StatusBadRequest {
+		t.Errorf("2MB single write status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	w := send(http.MethodPut, "/api/v1/sources/1/species-sources", bulk(maxBulkBodySize))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized bulk status = %d, want %d. Body: %s", w.Code, http.StatusRequestEntityTooLarge, w.Body.String())
+	}
+}
+
+func TestImportSession(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, session string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		if session != "" {
+			req.Header.Set(ImportSessionHeader, session)
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", "", models.OakEntry{ScientificName: "alba"})
+	send(http.MethodPost, "/api/v1/species", "", models.OakEntry{ScientificName: "rubra"})
+	send(http.MethodPost, "/api/v1/sources", "", models.Source{SourceType: "website", Name: "Scraped"})
+
+	w := send(http.MethodPost, "/api/v1/import/sessions", "", nil)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create session status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	// Uploads are staged, not applied
+	for _, name := range []string{"alba", "missing"} {
+		w = send(http.MethodPut, "/api/v1/sources/1/species-sources", "1", []BulkSpeciesSourceItem{{ScientificName: name}})
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("staged upload status = %d, want %d. Body: %s", w.Code, http.StatusAccepted, w.Body.String())
+		}
+	}
+	if w = send(http.MethodGet, "/api/v1/species/alba/sources/1", "", nil); w.Code != http.StatusNotFound {
+		t.Errorf("staged species source status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	// A failing row in any batch fails the commit and leaves the session open
+	w = send(http.MethodPost, "/api/v1/import/sessions/1/commit", "", nil)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "batches[1].items[0]") {
+		t.Fatalf("bad commit = %d %s, want 400 for batches[1].items[0]", w.Code, w.Body.String())
+	}
+	if w = send(http.MethodPost, "/api/v1/import/sessions/1/discard", "", nil); w.Code != http.StatusOK {
+		t.Fatalf("discard status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if w = send(http.MethodPut, "/api/v1/sources/1/species-sources", "1", []BulkSpeciesSourceItem{{ScientificName: "alba"}}); w.Code != http.StatusConflict {
+		t.Errorf("upload to discarded session status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w = send(http.MethodPut, "/api/v1/sources/1/species-sources", "99", []BulkSpeciesSourceItem{{ScientificName: "alba"}}); w.Code != http.StatusNotFound {
+		t.Errorf("upload to unknown session status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	// A clean session applies every batch on commit
+	send(http.MethodPost, "/api/v1/import/sessions", "", nil)
+	for _, name := range []string{"alba", "rubra"} {
+		send(http.MethodPut, "/api/v1/sources/1/species-sources", "2", []BulkSpeciesSourceItem{{ScientificName: name}})
+	}
+	w = send(http.MethodPost, "/api/v1/import/sessions/2/commit", "", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("commit status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp ImportSessionCommitResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Created != 2 || len(resp.Batches) != 2 || resp.Session.Status != "committed" {
+		t.Errorf("commit = created %d, %d batches, status %s, want 2, 2, committed", resp.Created, len(resp.Batches), resp.Session.Status)
+	}
+	if w = send(http.MethodGet, "/api/v1/species/rubra/sources/1", "", nil); w.Code != http.StatusOK {
+		t.Errorf("committed species source status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w = send(http.MethodPost, "/api/v1/import/sessions/2/commit", "", nil); w.Code != http.StatusConflict {
+		t.Errorf("second commit status = %d, want %d", w.Code, http.StatusConflict)
+	}
+}
+
+func TestSetPreferredSource(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", models.OakEntry{ScientificName: "alba"})
+	send(http.MethodPost, "/api/v1/sources", models.Source{SourceType: "website", Name: "One"})
+	send(http.MethodPost, "/api/v1/sources", models.Source{SourceType: "website", Name: "Two"})
+	send(http.MethodPost, "/api/v1/species/alba/sources", SpeciesSourceRequest{SourceID: 1, IsPreferred: true})
+	send(http.MethodPost, "/api/v1/species/alba/sources", SpeciesSourceRequest{SourceID: 2})
+
+	w := send(http.MethodPut, "/api/v1/species/alba/preferred-source", PreferredSourceRequest{SourceID: 2})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var sources []models.SpeciesSource
+	if err := json.NewDecoder(w.Body).Decode(&sources); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	preferred := 0
+	for _, ss := range sources {
+		if ss.IsPreferred {
+			preferred++
+			if ss.SourceID != 2 {
+				t.Errorf("preferred source = %d, want 2", ss.SourceID)
+			}
+		}
+	}
+	if preferred != 1 {
+		t.Errorf("preferred count = %d, want 1", preferred)
+	}
+
+	w = send(http.MethodPut, "/api/v1/species/alba/preferred-source", PreferredSourceRequest{SourceID: 3})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown source status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	w = send(http.MethodPut, "/api/v1/species/missing/preferred-source", PreferredSourceRequest{SourceID: 1})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown species status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestListPagination(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	list := func(path string) ListResponse[map[string]interface{}] {
+		t.Helper()
+		w := send(http.MethodGet, path, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", path, w.Code, w.Body.String())
+		}
+		var resp ListResponse[map[string]interface{}]
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		return resp
+	}
+
+	for _, body := range []string{
+		`{"source_type":"website","name":"A site"}`,
+		`{"source_type":"book","name":"B book","year":1990}`,
+		`{"source_type":"book","name":"C book","year":2004}`,
+	} {
+		if w := send(http.MethodPost, "/api/v1/sources", body); w.Code != http.StatusCreated {
+			t.Fatalf("create source = %d: %s", w.Code, w.Body.String())
+		}
+	}
+	for _, name := range []string{"Lobatae", "Protobalanus", "Quercus", "Virentes"} {
+		if w := send(http.MethodPost, "/api/v1/taxa", `{"name":"`+name+`","level":"section"}`); w.Code != http.StatusCreated {
+			t.Fatalf("create taxon = %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	// Without a limit everything is returned
+	if resp := list("/api/v1/sources"); len(resp.Data) != 3 || resp.Pagination.Total != 3 || resp.Pagination.HasMore {
+		t.Errorf("sources = %d items, pagination %+v, want all 3", len(resp.Data), resp.Pagination)
+	}
+
+	resp := list("/api/v1/sources?limit=1&offset=1")
+	if len(resp.Data) != 1 || resp.Data[0]["name"] != "B book" || resp.Pagination.Total != 3 || !resp.Pagination.HasMore {
+		t.Errorf("sources page = %v, pagination %+v, want B book of 3 with more", resp.Data, resp.Pagination)
+	}
+
+	resp = list("/api/v1/sources?source_type=book&year=2004")
+	if len(resp.Data) != 1 || resp.Data[0]["name"] != "C book" || resp.Pagination.Total != 1 {
+		t.Errorf("filtered sources = %v, pagination %+v, want C book", resp.Data, resp.Pagination)
+	}
+
+	resp = list("/api/v1/taxa?level=section&limit=3")
+	if len(resp.Data) != 3 || resp.Pagination.Total != 4 || !resp.Pagination.HasMore {
+		t.Errorf("taxa page = %d items, pagination %+v, want 3 of 4", len(resp.Data), resp.Pagination)
+	}
+	resp = list("/api/v1/taxa?level=section&limit=3&offset=3")
+	if len(resp.Data) != 1 || resp.Data[0]["name"] != "Virentes" || resp.Pagination.HasMore {
+		t.Errorf("taxa last page = %v, pagination %+v, want Virentes", resp.Data, resp.Pagination)
+	}
+
+	if resp := list("/api/v1/taxa?level=section&fresh=true"); len(resp.Data) != 4 || resp.Data[0]["species_count"] != float64(0) {
+		t.Errorf("fresh taxa = %v, want 4 with live counts", resp.Data)
+	}
+
+	for _, path := range []string{"/api/v1/sources?limit=0", "/api/v1/sources?year=recent", "/api/v1/taxa?offset=-1", "/api/v1/taxa?fresh=maybe"} {
+		if w := send(http.MethodGet, path, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 400", path, w.Code)
+		}
+	}
+}
+
+func TestConservationStatusFilter(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	for _, body := range []string{
+		`{"scientific_name":"alba","conservation_status":"LC"}`,
+		`{"scientific_name":"boyntonii","conservation_status":"CR"}`,
+		`{"scientific_name":"georgiana","conservation_status":"EN"}`,
+		`{"scientific_name":"oglethorpensis","conservation_status":"VU"}`,
+		`{"scientific_name":"rubra"}`,
+	} {
+		if w := send(http.MethodPost, "/api/v1/species", body); w.Code != http.StatusCreated {
+			t.Fatalf("create species = %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	names := func(path string) []string {
+		t.Helper()
+		w := send(http.MethodGet, path, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", path, w.Code, w.Body.String())
+		}
+		var resp ListResponse[map[string]interface{}]
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		var got []string
+		for _, e := range resp.Data {
+			got = append(got, e["scientific_name"].(string))
+		}
+		return got
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/api/v1/species?conservation_status=EN,CR", []string{"boyntonii", "georgiana"}},
+		{"/api/v1/species?conservation_status=lc", []string{"alba"}},
+		{"/api/v1/species?threatened=true", []string{"boyntonii", "georgiana", "oglethorpensis"}},
+		{"/api/v1/species?threatened=true&conservation_status=LC", []string{"alba", "boyntonii", "georgiana", "oglethorpensis"}},
+	}
+	for _, tt := range tests {
+		if got := names(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GET %s = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	for _, path := range []string{
+		"/api/v1/species?conservation_status=EN,XX",
+		"/api/v1/species?threatened=false",
+		"/api/v1/export?threatened=yes",
+	} {
+		if w := send(http.MethodGet, path, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 400", path, w.Code)
+		}
+	}
+
+	w := send(http.MethodGet, "/api/v1/export?threatened=true", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("export = %d: %s", w.Code, w.Body.String())
+	}
+	var exported struct {
+		Species []struct {
+			Name string `json:"name"`
+		} `json:"species"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &exported); err != nil {
+		t.Fatalf("decode export: %v", err)
+	}
+	if len(exported.Species) != 3 {
+		t.Errorf("export species = %+v, want the 3 threatened species", exported.Species)
+	}
+}
+
+func TestSpeciesExternalLinks(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodPost, "/api/v1/species", `{"scientific_name": "alba", "external_links": [
+		{"name": "Wikipedia", "url": "https://en.wikipedia.org/wiki/Quercus_alba", "logo": "wikipedia"}]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	// Updates without external_links keep them
+	if w := send(http.MethodPut, "/api/v1/species/alba", `{"scientific_name": "alba", "author": "L."}`); w.Code != http.StatusOK {
+		t.Fatalf("update status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	var entry models.OakEntry
+	w = send(http.MethodGet, "/api/v1/species/alba", "")
+	if err := json.NewDecoder(w.Body).Decode(&entry); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(entry.ExternalLinks) != 1 || entry.ExternalLinks[0].Logo != "wikipedia" {
+		t.Errorf("ExternalLinks = %+v, want the Wikipedia link", entry.ExternalLinks)
+	}
+
+	w = send(http.MethodPut, "/api/v1/species/alba", `{"scientific_name": "alba", "external_links": [
+		{"name": "", "url": "en.wikipedia.org/wiki/Quercus_alba", "logo": "myspace"}]}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("invalid links status = %d, want %d. Body: %s", w.Code, http.StatusBadRequest, w.Body.String())
+	}
+	for _, field := range []string{"external_links[0].name", "external_links[0].url", "external_links[0].logo"} {
+		if !strings.Contains(w.Body.String(), field) {
+			t.Errorf("expected a validation error for %s, got %s", field, w.Body.String())
+		}
+	}
+}
+
+func TestSpeciesNameValidation(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	for _, name := range []string{"alba", "× bebbiana", "alba × macrocarpa", "novae-angliae"} {
+		if w := send(http.MethodPost, "/api/v1/species", `{"scientific_name": "`+name+`"}`); w.Code != http.StatusCreated {
+			t.Errorf("create %q status = %d, want %d. Body: %s", name, w.Code, http.StatusCreated, w.Body.String())
+		}
+	}
+
+	tests := []struct {
+		name        string
+		wantMessage string
+	}{
+		{" rubra", `did you mean \"rubra\"`},
+		{"Rubra", `did you mean \"rubra\"`},
+		{"x velutina", `did you mean \"× velutina\"`},
+		{"DROP TABLE", "must be lowercase"},
+		{"rubra;--", "must be a specific epithet"},
+	}
+	for _, tt := range tests {
+		w := send(http.MethodPost, "/api/v1/species", `{"scientific_name": "`+tt.name+`"}`)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("create %q status = %d, want %d", tt.name, w.Code, http.StatusBadRequest)
+			continue
+		}
+		if body := w.Body.String(); !strings.Contains(body, `"field":"scientific_name"`) || !strings.Contains(body, tt.wantMessage) {
+			t.Errorf("create %q body = %s, want scientific_name error containing %s", tt.name, body, tt.wantMessage)
+		}
+	}
+
+	// Names stored before validation show up in the report
+	if err := server.db.SaveOakEntry(models.NewOakEntry("Alba")); err != nil {
+		t.Fatal(err)
+	}
+	w := send(http.MethodGet, "/api/v1/admin/name-report", "")
+	var report NameReportResponse
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode name report: %v", err)
+	}
+	if len(report.Names) != 1 || report.Names[0].ScientificName != "Alba" || !report.Names[0].Conflicts {
+		t.Errorf("name report = %+v, want Alba conflicting with alba", report.Names)
+	}
+}
+
+func TestSpeciesNameLookupIgnoresCaseAndDiacritics(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := send(http.MethodPost, "/api/v1/species", `{"scientific_name": "alba"}`); w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	for _, path := range []string{"/api/v1/species/Alba", "/api/v1/species/alb%C3%A0", "/api/v1/species/ALBA/full", "/api/v2/species/Alba"} {
+		w := send(http.MethodGet, path, "")
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s status = %d, want %d", path, w.Code, http.StatusOK)
+			continue
+		}
+		if !strings.Contains(w.Body.String(), `"scientific_name":"alba"`) {
+			t.Errorf("GET %s body = %s, want alba", path, w.Body.String())
+		}
+	}
+
+	// A name stored before validation blocks its canonical twin
+	if err := server.db.SaveOakEntry(models.NewOakEntry("rubrà")); err != nil {
+		t.Fatal(err)
+	}
+	w := send(http.MethodPost, "/api/v1/species", `{"scientific_name": "rubra"}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("create rubra status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if !strings.Contains(w.Body.String(), "rubrà") {
+		t.Errorf("conflict body = %s, want it to name rubrà", w.Body.String())
+	}
+
+	w = send(http.MethodGet, "/api/v1/species/search?q=RUBRA", "")
+	if !strings.Contains(w.Body.String(), "rubrà") {
+		t.Errorf("search body = %s, want rubrà", w.Body.String())
+	}
+}
+
+func TestSourceTypes(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	// Other spellings are stored in canonical form
+	w := send(http.MethodPost, "/api/v1/sources", `{"source_type":"Personal Observation","name":"Field notes"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", w.Code, w.Body.String())
+	}
+	var created models.Source
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.SourceType != models.SourceTypePersonalObservation {
+		t.Errorf("source_type = %q, want %q", created.SourceType, models.SourceTypePersonalObservation)
+	}
+	if w := send(http.MethodPost, "/api/v1/sources", `{"source_type":"Book","name":"Oaks of Asia"}`); w.Code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := send(http.MethodPost, "/api/v1/sources", `{"source_type":"Scroll","name":"Dead Sea"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("create with unknown type = %d, want 400", w.Code)
+	}
+	if w := send(http.MethodPut, "/api/v1/sources/1", `{"source_type":"Scroll","name":"Field notes"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("update with unknown type = %d, want 400", w.Code)
+	}
+
+	w = send(http.MethodGet, "/api/v1/sources?type=BOOK", "")
+	var list ListResponse[models.Source]
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Data) != 1 || list.Data[0].Name != "Oaks of Asia" {
+		t.Errorf("?type=BOOK = %+v, want Oaks of Asia", list.Data)
+	}
+	if w := send(http.MethodGet, "/api/v1/sources?type=scroll", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("?type=scroll = %d, want 400", w.Code)
+	}
+
+	w = send(http.MethodGet, "/api/v1/stats", "")
+	var stats StatsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"book": 1, "personal-observation": 1}
+	if !reflect.DeepEqual(stats.SourcesByType, want) {
+		t.Errorf("sources_by_type = %v, want %v", stats.SourcesByType, want)
+	}
+}
+
+func TestDeleteSourceInUse(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	for _, setup := range []struct{ method, path, body string }{
+		{http.MethodPost, "/api/v1/sources", `{"source_type":"book","name":"Flora"}`},
+		{http.MethodPost, "/api/v1/species", `{"scientific_name":"alba"}`},
+		{http.MethodPost, "/api/v1/species/alba/sources", `{"source_id":1}`},
+	} {
+		if w := send(setup.method, setup.path, setup.body); w.Code >= 300 {
+			t.Fatalf("setup %s %s = %d: %s", setup.method, setup.path, w.Code, w.Body.String())
+		}
+	}
+
+	w := send(http.MethodGet, "/api/v1/sources/1/usage", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"species":["alba"]`) {
+		t.Errorf("usage = %d %s, want alba", w.Code, w.Body.String())
+	}
+
+	w = send(http.MethodDelete, "/api/v1/sources/1", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("delete in use = %d, want 409", w.Code)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	details, _ := resp.Error.Details.(map[string]interface{})
+	if species, _ := details["species"].([]interface{}); len(species) != 1 || species[0] != "alba" {
+		t.Errorf("conflict details = %v, want species [alba]", resp.Error.Details)
+	}
+
+	if w := send(http.MethodDelete, "/api/v1/sources/1?force=true", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("forced delete = %d, want 204: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodGet, "/api/v1/species/alba/sources/1", ""); w.Code != http.StatusNotFound {
+		t.Errorf("species source after forced delete = %d, want 404", w.Code)
+	}
+}
+
+func TestSourceCoverage(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	section := "Quercus"
+	send(http.MethodPost, "/api/v1/species", models.OakEntry{ScientificName: "alba", Section: &section})
+	send(http.MethodPost, "/api/v1/species", models.OakEntry{ScientificName: "macrocarpa", Section: &section})
+	send(http.MethodPost, "/api/v1/sources", models.Source{SourceType: "website", Name: "Scraped"})
+	send(http.MethodPost, "/api/v1/species/alba/sources", SpeciesSourceRequest{SourceID: 1})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/sources/1/coverage", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var cov db.SourceCoverage
+	if err := json.NewDecoder(w.Body).Decode(&cov); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if cov.SpeciesCount != 1 || len(cov.MissingSpecies) != 1 || cov.MissingSpecies[0] != "macrocarpa" {
+		t.Errorf("coverage = %+v, want 1 species and macrocarpa missing", cov)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/sources/99/coverage", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown source status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestSourceSuperseding(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", models.OakEntry{ScientificName: "alba"})
+	send(http.MethodPost, "/api/v1/sources", SourceRequest{SourceType: "book", Name: "Flora, 1st ed."})
+	send(http.MethodPost, "/api/v1/species/alba/sources", SpeciesSourceRequest{SourceID: 1})
+
+	newer := int64(2)
+	w := send(http.MethodPost, "/api/v1/sources", SourceRequest{SourceType: "book", Name: "Flora, 2nd ed.", SupersededBy: &newer})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("self-superseding create status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	w = send(http.MethodPost, "/api/v1/sources", SourceRequest{SourceType: "book", Name: "Flora, 2nd ed."})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want %d", w.Code, http.StatusCreated)
+	}
+
+	w = send(http.MethodPut, "/api/v1/sources/1", SourceRequest{SourceType: "book", Name: "Flora, 1st ed.", SupersededBy: &newer})
+	if w.Code != http.StatusOK {
+		t.Fatalf("supersede status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	older := int64(1)
+	w = send(http.MethodPut, "/api/v1/sources/2", SourceRequest{SourceType: "book", Name: "Flora, 2nd ed.", SupersededBy: &older})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("cyclic supersede status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	// Writing to the superseded source warns
+	leaves := "Lobed"
+	w = send(http.MethodPut, "/api/v1/species/alba/sources/1", SpeciesSourceRequest{SourceID: 1, Leaves: &leaves})
+	if w.Code != http.StatusOK {
+		t.Fatalf("update status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if !strings.Contains(w.Header().Get("Warning"), "superseded by source 2") {
+		t.Errorf("Warning = %q, want superseded warning", w.Header().Get("Warning"))
+	}
+
+	// Dry-run migration reports the plan without writing
+	w = send(http.MethodPost, "/api/v1/sources/1/migrate", MigrateSourceRequest{TargetID: 2, Move: true, DryRun: true})
+	if w.Code != http.StatusOK {
+		t.Fatalf("dry run status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var plan MigrateSourceResponse
+	if err := json.NewDecoder(w.Body).Decode(&plan); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(plan.Items) != 1 || plan.Items[0].Action != db.MigrateMove {
+		t.Errorf("plan = %+v, want alba move", plan.Items)
+	}
+
+	w = send(http.MethodPost, "/api/v1/sources/1/migrate", MigrateSourceRequest{TargetID: 2, Move: true})
+	if w.Code != http.StatusOK {
+		t.Fatalf("migrate status = %d, want %d", w.Code, http.StatusOK)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/species/alba/sources/2", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("migrated record status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	w = send(http.MethodPost, "/api/v1/sources/1/migrate", MigrateSourceRequest{TargetID: 1})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("migrate to self status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAttributions(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	url, license := "https://example.org/oaks", "CC BY <4.0>"
+	send(http.MethodPost, "/api/v1/species", models.OakEntry{ScientificName: "alba"})
+	send(http.MethodPost, "/api/v1/sources", models.Source{SourceType: "website", Name: "Oaks & Co", URL: &url, License: &license})
+	send(http.MethodPost, "/api/v1/species/alba/sources", SpeciesSourceRequest{SourceID: 1})
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/attributions"+query, nil)
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var attributions []db.SourceAttribution
+	if err := json.NewDecoder(w.Body).Decode(&attributions); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(attributions) != 1 || len(attributions[0].Species) != 1 || attributions[0].Species[0] != "alba" {
+		t.Errorf("attributions = %+v, want one source crediting alba", attributions)
+	}
+
+	w = get("?format=markdown")
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/markdown") {
+		t.Errorf("markdown Content-Type = %q", ct)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "## [Oaks & Co](https://example.org/oaks)") || !strings.Contains(body, "*alba*") {
+		t.Errorf("markdown body = %q", body)
+	}
+
+	w = get("?format=html")
+	if body := w.Body.String(); !strings.Contains(body, "Oaks &amp; Co") || !strings.Contains(body, "CC BY &lt;4.0&gt;") {
+		t.Errorf("html body not escaped: %q", body)
+	}
+
+	if w = get("?format=pdf"); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown format status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestSpeciesSourcePages(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", models.OakEntry{ScientificName: "alba"})
+	send(http.MethodPost, "/api/v1/sources", models.Source{SourceType: "book", Name: "Flora"})
+
+	pages := "pp. 12-13"
+	w := send(http.MethodPost, "/api/v1/species/alba/sources", SpeciesSourceRequest{
+		SourceID: 1, Pages: &pages, FieldPages: map[string]string{"acorns": "p. 14"},
+	})
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "field_pages.acorns") {
+		t.Errorf("unknown field locator: status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	w = send(http.MethodPost, "/api/v1/species/alba/sources", SpeciesSourceRequest{
+		SourceID: 1, Pages: &pages, FieldPages: map[string]string{"fruits": "p. 14", "bark": "p. 15"},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	w = send(http.MethodPut, "/api/v1/species/alba/sources/1", SpeciesSourceRequest{
+		SourceID: 1, FieldPages: map[string]string{"bark": ""},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("update status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var ss models.SpeciesSource
+	if err := json.Unmarshal(w.Body.Bytes(), &ss); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if ss.Pages == nil || *ss.Pages != pages || len(ss.FieldPages) != 1 || ss.FieldPages["fruits"] != "p. 14" {
+		t.Errorf("after update pages = %v, field_pages = %v; want %s and only fruits", ss.Pages, ss.FieldPages, pages)
+	}
+
+	w = send(http.MethodGet, "/api/v1/species/alba/full", nil)
+	var full models.SpeciesWithSources
+	if err := json.Unmarshal(w.Body.Bytes(), &full); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(full.Sources) != 1 || full.Sources[0].Citation != "Flora, pp. 12-13" {
+		t.Errorf("full sources = %+v, want citation \"Flora, pp. 12-13\"", full.Sources)
+	}
+
+	w = send(http.MethodGet, "/api/v1/attributions?format=markdown", nil)
+	if body := w.Body.String(); !strings.Contains(body, "*alba* (pp. 12-13)") {
+		t.Errorf("markdown body = %q, want alba's pages", body)
+	}
+}
+
+func TestTranscriptions(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", models.OakEntry{ScientificName: "alba"})
+	send(http.MethodPost, "/api/v1/sources", models.Source{SourceType: "book", Name: "Flora"})
+
+	ocr, bad, confidence := models.TranscriptionOCR, "scanner", 0.7
+	w := send(http.MethodPost, "/api/v1/species/alba/sources", SpeciesSourceRequest{SourceID: 1, TranscriptionMethod: &bad})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unknown method status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	w = send(http.MethodPost, "/api/v1/species/alba/sources", SpeciesSourceRequest{
+		SourceID: 1, TranscriptionMethod: &ocr, OCRConfidence: &confidence,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	queue := func(query string) []db.Transcription {
+		t.Helper()
+		w := send(http.MethodGet, "/api/v1/transcriptions"+query, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("list status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+		}
+		var resp ListResponse[db.Transcription]
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp.Data
+	}
+	if got := queue(""); len(got) != 1 || got[0].ScientificName != "alba" || got[0].Verified {
+		t.Errorf("unverified queue = %+v, want alba", got)
+	}
+
+	w = send(http.MethodPost, "/api/v1/species/alba/sources/1/verify", VerifySpeciesSourceRequest{VerifiedBy: "jeff"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("verify status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got := queue(""); len(got) != 0 {
+		t.Errorf("unverified queue after verifying = %+v, want empty", got)
+	}
+
+	// Edits keeping the transcription keep the verification; a new OCR run drops it
+	leaves := "Lobed"
+	send(http.MethodPut, "/api/v1/species/alba/sources/1", SpeciesSourceRequest{SourceID: 1, Leaves: &leaves, TranscriptionMethod: &ocr})
+	if got := queue("?verified=true"); len(got) != 1 {
+		t.Errorf("verified after edit = %+v, want alba", got)
+	}
+	rerun := 0.8
+	send(http.MethodPut, "/api/v1/species/alba/sources/1", SpeciesSourceRequest{SourceID: 1, OCRConfidence: &rerun})
+	if got := queue(""); len(got) != 1 {
+		t.Errorf("unverified queue after new OCR = %+v, want alba", got)
+	}
+
+	if w = send(http.MethodPost, "/api/v1/species/alba/sources/9/verify", nil); w.Code != http.StatusNotFound {
+		t.Errorf("verify missing status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w = send(http.MethodGet, "/api/v1/transcriptions?max_confidence=2", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("bad max_confidence status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestCommonNames(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "alba"})
+	send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "rubra"})
+
+	w := send(http.MethodPut, "/api/v1/species/alba/common-names", CommonNamesRequest{CommonNames: []*models.CommonName{
+		{Name: "White oak", Language: "EN-us"},
+		{Name: " Chêne blanc ", Language: "fr"},
+		{Name: "Eastern white oak", Language: "en"},
+		{Name: "Quercus blanc"},
+	}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("put status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	var names ListResponse[models.CommonName]
+	json.NewDecoder(w.Body).Decode(&names)
+	got := make([]string, len(names.Data))
+	for i, n := range names.Data {
+		got[i] = n.Language + ":" + n.Name
+	}
+	want := "en:Eastern white oak,en-US:White oak,fr:Chêne blanc,und:Quercus blanc"
+	if strings.Join(got, ",") != want {
+		t.Errorf("names = %s, want %s", strings.Join(got, ","), want)
+	}
+
+	for _, bad := range []models.CommonName{{Name: "x", Language: "english!"}, {Name: " ", Language: "en"}} {
+		if w := send(http.MethodPut, "/api/v1/species/alba/common-names", CommonNamesRequest{CommonNames: []*models.CommonName{&bad}}); w.Code != http.StatusBadRequest {
+			t.Errorf("put %+v status = %d, want %d", bad, w.Code, http.StatusBadRequest)
+		}
+	}
+	if w := send(http.MethodPut, "/api/v1/species/nonexistent/common-names", CommonNamesRequest{}); w.Code != http.StatusNotFound {
+		t.Errorf("put for missing species status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	type exported struct {
+		Species []struct {
+			Name        string              `json:"name"`
+			CommonNames map[string][]string `json:"common_names"`
+		} `json:"species"`
+	}
+	exportWith := func(query, acceptLanguage string) (*httptest.ResponseRecorder, map[string]map[string][]string) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/export"+query, nil)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		var file exported
+		json.NewDecoder(w.Body).Decode(&file)
+		bySpecies := make(map[string]map[string][]string)
+		for _, sp := range file.Species {
+			bySpecies[sp.Name] = sp.CommonNames
+		}
+		return w, bySpecies
+	}
+
+	_, all := exportWith("", "")
+	if en := all["alba"]["en"]; len(en) != 1 || en[0] != "Eastern white oak" {
+		t.Errorf("export en names = %v, want [Eastern white oak]", en)
+	}
+	if len(all["alba"]) != 4 {
+		t.Errorf("export languages = %v, want en, en-US, fr, und", all["alba"])
+	}
+	if all["rubra"] == nil || len(all["rubra"]) != 0 {
+		t.Errorf("export names for rubra = %v, want empty object", all["rubra"])
+	}
+
+	w, filtered := exportWith("?lang=en", "")
+	if len(filtered["alba"]) != 2 || filtered["alba"]["fr"] != nil {
+		t.Errorf("?lang=en names = %v, want en and en-US only", filtered["alba"])
+	}
+	if got := w.Header().Get("Content-Language"); got != "en" {
+		t.Errorf("Content-Language = %q, want en", got)
+	}
+
+	w, negotiated := exportWith("?lang=auto", "fr-CH, fr;q=0.9, en;q=0")
+	if len(negotiated["alba"]) != 1 || negotiated["alba"]["fr"] == nil {
+		t.Errorf("?lang=auto names = %v, want fr only", negotiated["alba"])
+	}
+	if got := w.Header().Get("Vary"); !strings.Contains(got, "Accept-Language") {
+		t.Errorf("Vary = %q, want Accept-Language", got)
+	}
+
+	if w, _ := exportWith("?lang=not_a_tag", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("?lang=not_a_tag status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestTextSearch(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "stellata"})
+	send(http.MethodPost, "/api/v1/sources", models.Source{SourceType: "book", Name: "Flora"})
+	leaves := "Abaxially with stellate hairs."
+	if w := send(http.MethodPost, "/api/v1/species/stellata/sources", models.SpeciesSource{SourceID: 1, Leaves: &leaves}); w.Code != http.StatusCreated {
+		t.Fatalf("create species-source status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search/text?q=Stellate+Hairs", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	var result ListResponse[models.TextMatch]
+	json.NewDecoder(w.Body).Decode(&result)
+	if result.Pagination.Total != 1 || len(result.Data) != 1 {
+		t.Fatalf("result = %+v, want one match", result)
+	}
+	m := result.Data[0]
+	if m.ScientificName != "stellata" || m.Location != "sources/1/leaves" || m.Text[m.Matches[0].Start:m.Matches[0].End] != "stellate hairs" {
+		t.Errorf("match = %+v", m)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/search/text?q=+", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("blank query status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestFullTextSearch(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	public := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	if !server.db.FullTextAvailable() {
+		if w := public("/api/v1/search/fulltext?q=bark"); w.Code != http.StatusBadRequest {
+			t.Errorf("status without FTS5 = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+		t.Skip("SQLite built without FTS5; run with -tags sqlite_fts5")
+	}
+
+	send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "stellata", Synonyms: []string{"Quercus obtusiloba"}})
+	send(http.MethodPost, "/api/v1/sources", models.Source{SourceType: "book", Name: "Flora"})
+	leaves, bark := "Abaxially with stellate hairs.", "Bark gray with stellate scales."
+	if w := send(http.MethodPost, "/api/v1/species/stellata/sources", SpeciesSourceRequest{
+		SourceID: 1, Leaves: &leaves, Bark: &bark,
+		FieldVisibility: map[string]string{"bark": models.FieldInternal},
+	}); w.Code != http.StatusCreated {
+		t.Fatalf("create species-source status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	w := public("/api/v1/search/fulltext?q=stellate")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	var result ListResponse[models.FullTextMatch]
+	json.NewDecoder(w.Body).Decode(&result)
+	if result.Pagination.Total != 1 || len(result.Data) != 1 || result.Data[0].Location != "sources/1/leaves" {
+		t.Fatalf("public result = %+v, want leaves only", result)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search/fulltext?q=stellate", nil)
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	json.NewDecoder(w.Body).Decode(&result)
+	if result.Pagination.Total != 2 {
+		t.Errorf("curator result = %+v, want leaves and bark", result)
+	}
+
+	json.NewDecoder(public("/api/v1/search/fulltext?q=obtusiloba").Body).Decode(&result)
+	if len(result.Data) != 1 || result.Data[0].Location != "synonyms" {
+		t.Errorf("synonym result = %+v", result)
+	}
+
+	if w := public("/api/v1/search/fulltext?q=+"); w.Code != http.StatusBadRequest {
+		t.Errorf("blank query status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestFieldVisibility(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	public := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "tomentella"})
+	send(http.MethodPost, "/api/v1/sources", models.Source{SourceType: "book", Name: "Flora"})
+	rangeText, leaves := "Guadalupe Island, Baja California", "Blades 3-5 cm long."
+	w := send(http.MethodPost, "/api/v1/species/tomentella/sources", SpeciesSourceRequest{
+		SourceID: 1, Range: &rangeText, Leaves: &leaves,
+		FieldPages:      map[string]string{"range": "p. 4"},
+		FieldVisibility: map[string]string{"range": models.FieldInternal, "leaves": models.FieldPublic},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create species-source status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	var ss models.SpeciesSource
+	json.NewDecoder(public("/api/v1/species/tomentella/sources/1").Body).Decode(&ss)
+	if ss.Range != nil || ss.FieldPages["range"] != "" || ss.Leaves == nil || !ss.IsInternal("range") {
+		t.Errorf("public species source = %+v, want range withheld and leaves shown", ss)
+	}
+	ss = models.SpeciesSource{}
+	json.NewDecoder(send(http.MethodGet, "/api/v1/species/tomentella/sources/1", nil).Body).Decode(&ss)
+	if ss.Range == nil || ss.FieldPages["range"] != "p. 4" || len(ss.FieldVisibility) != 1 {
+		t.Errorf("curator species source = %+v, want range", ss)
+	}
+
+	var full models.SpeciesWithSources
+	json.NewDecoder(public("/api/v1/species/tomentella/full").Body).Decode(&full)
+	if len(full.Sources) != 1 || full.Sources[0].Range != nil {
+		t.Errorf("public full species = %+v, want range withheld", full.Sources)
+	}
+
+	var search ListResponse[models.TextMatch]
+	json.NewDecoder(public("/api/v1/search/text?q=guadalupe").Body).Decode(&search)
+	if search.Pagination.Total != 0 {
+		t.Errorf("public text search found %d in an internal field", search.Pagination.Total)
+	}
+	json.NewDecoder(send(http.MethodGet, "/api/v1/search/text?q=guadalupe", nil).Body).Decode(&search)
+	if search.Pagination.Total != 1 {
+		t.Errorf("curator text search found %d, want 1", search.Pagination.Total)
+	}
+
+	// Exports are published, so withhold internal fields even from curators
+	var exported struct {
+		Species []struct {
+			Sources []struct {
+				Range          *string  `json:"range"`
+				Leaves         *string  `json:"leaves"`
+				WithheldFields []string `json:"withheld_fields"`
+			} `json:"sources"`
+		} `json:"species"`
+	}
+	json.NewDecoder(send(http.MethodGet, "/api/v1/export", nil).Body).Decode(&exported)
+	if len(exported.Species) != 1 || len(exported.Species[0].Sources) != 1 {
+		t.Fatalf("export = %+v", exported)
+	}
+	if src := exported.Species[0].Sources[0]; src.Range != nil || src.Leaves == nil || len(src.WithheldFields) != 1 || src.WithheldFields[0] != "range" {
+		t.Errorf("exported source = %+v, want range withheld", src)
+	}
+
+	w = send(http.MethodPut, "/api/v1/species/tomentella/sources/1", SpeciesSourceRequest{
+		SourceID: 1, FieldVisibility: map[string]string{"bark": "secret", "color": models.FieldInternal},
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid visibility status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	// A bulk upsert that doesn't mention visibility keeps the range internal
+	w = send(http.MethodPut, "/api/v1/sources/1/species-sources", []BulkSpeciesSourceItem{
+		{ScientificName: "tomentella", SpeciesSourceRequest: SpeciesSourceRequest{Range: &rangeText}},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("bulk upsert status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	ss = models.SpeciesSource{}
+	json.NewDecoder(public("/api/v1/species/tomentella/sources/1").Body).Decode(&ss)
+	if ss.Range != nil {
+		t.Error("bulk upsert without field_visibility published the internal range")
+	}
+
+	send(http.MethodPut, "/api/v1/species/tomentella/sources/1", SpeciesSourceRequest{
+		SourceID: 1, FieldVisibility: map[string]string{"range": models.FieldPublic},
+	})
+	ss = models.SpeciesSource{}
+	json.NewDecoder(public("/api/v1/species/tomentella/sources/1").Body).Decode(&ss)
+	if ss.Range == nil || len(ss.FieldVisibility) != 0 {
+		t.Errorf("species source made public = %+v", ss)
+	}
+}
+
+func TestDraftSpecies(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	public := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "draftii", Visibility: "draft"}); w.Code != http.StatusCreated {
+		t.Fatalf("create draft status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "alba"})
+
+	if w := public("/api/v1/species/draftii"); w.Code != http.StatusNotFound {
+		t.Errorf("public get draft status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := public("/api/v1/species/draftii/sources"); w.Code != http.StatusNotFound {
+		t.Errorf("public get draft sources status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := send(http.MethodGet, "/api/v1/species/draftii", nil); w.Code != http.StatusOK {
+		t.Errorf("curator get draft status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var list ListResponse[models.OakEntry]
+	json.NewDecoder(public("/api/v1/species").Body).Decode(&list)
+	if list.Pagination.Total != 1 || list.Data[0].ScientificName != "alba" {
+		t.Errorf("public list = %+v, want only alba", list)
+	}
+	json.NewDecoder(send(http.MethodGet, "/api/v1/species", nil).Body).Decode(&list)
+	if list.Pagination.Total != 2 {
+		t.Errorf("curator list total = %d, want 2", list.Pagination.Total)
+	}
+
+	var export struct {
+		Species []struct {
+			Name string `json:"name"`
+		} `json:"species"`
+	}
+	json.NewDecoder(send(http.MethodGet, "/api/v1/export", nil).Body).Decode(&export)
+	if len(export.Species) != 1 {
+		t.Errorf("export species = %d, want 1 (drafts excluded)", len(export.Species))
+	}
+
+	// Editing a draft without a visibility leaves it a draft
+	send(http.MethodPut, "/api/v1/species/draftii", SpeciesRequest{Synonyms: []string{"old name"}})
+	if w := public("/api/v1/species/draftii"); w.Code != http.StatusNotFound {
+		t.Errorf("draft became public after edit: status = %d", w.Code)
+	}
+
+	if w := send(http.MethodPut, "/api/v1/species/draftii/visibility", VisibilityRequest{Visibility: "published"}); w.Code != http.StatusOK {
+		t.Fatalf("publish status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	if w := public("/api/v1/species/draftii"); w.Code != http.StatusOK {
+		t.Errorf("public get after publish status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w := send(http.MethodPut, "/api/v1/species/draftii/visibility", VisibilityRequest{Visibility: "hidden"}); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid visibility status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestScheduledPublications(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "draftii", Visibility: "draft"})
+
+	publishAt := time.Now().Add(time.Hour).UTC()
+	w := send(http.MethodPost, "/api/v1/publications", SchedulePublicationRequest{
+		Species:   []string{"draftii"},
+		PublishAt: publishAt.Format(time.RFC3339),
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("schedule status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	w = send(http.MethodPost, "/api/v1/publications", SchedulePublicationRequest{
+		Species:   []string{"missing"},
+		PublishAt: time.Now().Add(-time.Hour).Format(time.RFC3339),
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid schedule status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	// The queue holds unpublished plans, so even reads need a key
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/publications", nil)
+	rec := httptest.NewRecorder()
+	server.Router().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated list status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	var pending []db.ScheduledPublication
+	json.NewDecoder(send(http.MethodGet, "/api/v1/publications", nil).Body).Decode(&pending)
+	if len(pending) != 1 {
+		t.Fatalf("pending publications = %d, want 1", len(pending))
+	}
+
+	// A scheduler pass after the publish time makes the draft public
+	server.publishDue(publishAt.Add(time.Minute))
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/species/draftii", nil)
+	rec = httptest.NewRecorder()
+	server.Router().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("public get after scheduled publish status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if w := send(http.MethodDelete, fmt.Sprintf("/api/v1/publications/%d", pending[0].ID), nil); w.Code != http.StatusConflict {
+		t.Errorf("cancel published status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w := send(http.MethodDelete, "/api/v1/publications/99", nil); w.Code != http.StatusNotFound {
+		t.Errorf("cancel missing status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestJobs(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodPost, "/api/v1/jobs", EnqueueJobRequest{Kind: "unknown"})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unknown kind status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	// Workers are not started, so the job stays queued
+	w = send(http.MethodPost, "/api/v1/jobs", EnqueueJobRequest{Kind: JobKindReindex})
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("enqueue status = %d, want %d. Body: %s", w.Code, http.StatusAccepted, w.Body.String())
+	}
+	var job struct {
+		ID     int64  `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&job); err != nil {
+		t.Fatalf("decode job: %v", err)
+	}
+	if job.Status != "queued" {
+		t.Errorf("status = %q, want queued", job.Status)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs", nil)
+	rec := httptest.NewRecorder()
+	server.Router().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated list status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	if w := send(http.MethodGet, "/api/v1/jobs?status=bogus", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("bad status filter = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	path := fmt.Sprintf("/api/v1/jobs/%d", job.ID)
+	if w := send(http.MethodGet, path, nil); w.Code != http.StatusOK {
+		t.Errorf("get status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w := send(http.MethodDelete, path, nil); w.Code != http.StatusNoContent {
+		t.Errorf("cancel status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w := send(http.MethodDelete, path, nil); w.Code != http.StatusConflict {
+		t.Errorf("second cancel status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w := send(http.MethodGet, "/api/v1/jobs/9999", nil); w.Code != http.StatusNotFound {
+		t.Errorf("missing job status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestGenera(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := send(http.MethodPost, "/api/v1/genera", GenusRequest{Name: "Carya"}); w.Code != http.StatusCreated {
+		t.Fatalf("create genus status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	if w := send(http.MethodPost, "/api/v1/genera", GenusRequest{Name: "Carya"}); w.Code != http.StatusConflict {
+		t.Errorf("duplicate genus status = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	// Species default to Quercus, whose subgenera are still enforced
+	invalid := "Carya"
+	if w := send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "alba", Subgenus: &invalid}); w.Code != http.StatusBadRequest {
+		t.Errorf("bad Quercus subgenus status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "saccharum", Genus: "Acer"}); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown genus status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "alba"})
+	if w := send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "ovata", Genus: "Carya", Subgenus: &invalid}); w.Code != http.StatusCreated {
+		t.Fatalf("Carya species status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	w := send(http.MethodGet, "/api/v1/species?genus=Carya", nil)
+	var list struct {
+		Data       []models.OakEntry `json:"data"`
+		Pagination struct {
+			Total int `json:"total"`
+		} `json:"pagination"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if list.Pagination.Total != 1 || len(list.Data) != 1 || list.Data[0].Genus != "Carya" {
+		t.Errorf("Carya list = %+v", list)
+	}
+
+	// Unfiltered requests still cover every genus
+	w = send(http.MethodGet, "/api/v1/species", nil)
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if list.Pagination.Total != 2 {
+		t.Errorf("total = %d, want 2", list.Pagination.Total)
+	}
+
+	w = send(http.MethodGet, "/api/v1/export?genus=Carya", nil)
+	var exported struct {
+		Species []struct {
+			Name     string `json:"name"`
+			Taxonomy struct {
+				Genus string `json:"genus"`
+			} `json:"taxonomy"`
+		} `json:"species"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&exported); err != nil {
+		t.Fatalf("decode export: %v", err)
+	}
+	if len(exported.Species) != 1 || exported.Species[0].Taxonomy.Genus != "Carya" {
+		t.Errorf("Carya export = %+v", exported.Species)
+	}
+
+	if w := send(http.MethodDelete, "/api/v1/genera/Carya", nil); w.Code != http.StatusConflict {
+		t.Errorf("delete genus in use status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w := send(http.MethodDelete, "/api/v1/genera/Quercus", nil); w.Code != http.StatusConflict {
+		t.Errorf("delete default genus status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	send(http.MethodDelete, "/api/v1/species/ovata", nil)
+	if w := send(http.MethodDelete, "/api/v1/genera/Carya", nil); w.Code != http.StatusNoContent {
+		t.Errorf("delete genus status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+func TestTaxonLevels(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	// Levels outside the configuration are rejected
+	if w := send(http.MethodPost, "/api/v1/taxa", TaxonRequest{Name: "Glandulosae", Level: "series"}); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown level status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	if w := send(http.MethodPut, "/api/v1/taxon-levels/series", TaxonLevelRequest{Rank: 0}); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid rank status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := send(http.MethodPut, "/api/v1/taxon-levels/series", TaxonLevelRequest{Rank: 2}); w.Code != http.StatusConflict {
+		t.Errorf("duplicate rank status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w := send(http.MethodPut, "/api/v1/taxon-levels/series", TaxonLevelRequest{Rank: 5}); w.Code != http.StatusCreated {
+		t.Fatalf("create level status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	w := send(http.MethodGet, "/api/v1/taxon-levels", nil)
+	var list struct {
+		Data []models.TaxonLevelDef `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(list.Data) != 5 || list.Data[4].Name != "series" {
+		t.Errorf("levels = %+v, want series last of 5", list.Data)
+	}
+
+	if w := send(http.MethodPost, "/api/v1/taxa", TaxonRequest{Name: "Glandulosae", Level: "Series"}); w.Code != http.StatusCreated {
+		t.Fatalf("create taxon status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	if w := send(http.MethodGet, "/api/v1/taxa/series/Glandulosae", nil); w.Code != http.StatusOK {
+		t.Errorf("get taxon status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	if w := send(http.MethodDelete, "/api/v1/taxon-levels/series", nil); w.Code != http.StatusConflict {
+		t.Errorf("delete level in use status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	send(http.MethodDelete, "/api/v1/taxa/series/Glandulosae", nil)
+	if w := send(http.MethodDelete, "/api/v1/taxon-levels/series", nil); w.Code != http.StatusNoContent {
+		t.Errorf("delete level status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w := send(http.MethodDelete, "/api/v1/taxon-levels/series", nil); w.Code != http.StatusNotFound {
+		t.Errorf("delete missing level status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodPost, "/api/v1/sources", SourceRequest{SourceType: "book", Name: "Oaks of the World"})
+	var source models.Source
+	if err := json.NewDecoder(w.Body).Decode(&source); err != nil {
+		t.Fatalf("decode source: %v", err)
+	}
+
+	subgenus, section := "Quercus", "Lobatae"
+	req := TemplateRequest{
+		Subgenus:       &subgenus,
+		Section:        &section,
+		SourceIDs:      []int64{source.ID},
+		RequiredFields: []string{"author", "leaves"},
+	}
+	if w := send(http.MethodPut, "/api/v1/templates/lobatae", req); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown required field status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	req.RequiredFields = []string{"author"}
+	req.SourceIDs = []int64{source.ID + 100}
+	if w := send(http.MethodPut, "/api/v1/templates/lobatae", req); w.Code != http.StatusBadRequest {
+		t.Errorf("missing source status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	req.SourceIDs = []int64{source.ID}
+	if w := send(http.MethodPut, "/api/v1/templates/Lobatae", req); w.Code != http.StatusCreated {
+		t.Fatalf("create template status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	if w := send(http.MethodPut, "/api/v1/templates/lobatae", req); w.Code != http.StatusOK {
+		t.Errorf("replace template status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// Templates are readable without a key
+	httpReq := httptest.NewRequest(http.MethodGet, "/api/v1/templates/lobatae", nil)
+	rec := httptest.NewRecorder()
+	server.Router().ServeHTTP(rec, httpReq)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get template status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var tmpl models.Template
+	if err := json.NewDecoder(rec.Body).Decode(&tmpl); err != nil {
+		t.Fatalf("decode template: %v", err)
+	}
+	if tmpl.Genus != models.DefaultGenus || tmpl.Section == nil || *tmpl.Section != "Lobatae" || len(tmpl.SourceIDs) != 1 {
+		t.Errorf("template = %+v", tmpl)
+	}
+
+	if w := send(http.MethodDelete, "/api/v1/templates/lobatae", nil); w.Code != http.StatusNoContent {
+		t.Errorf("delete template status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w := send(http.MethodGet, "/api/v1/templates/lobatae", nil); w.Code != http.StatusNotFound {
+		t.Errorf("get deleted template status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestReports(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	for _, name := range []string{"alba", "rubra"} {
+		if err := server.db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Reports read drafts, so even listing them needs a key
+	rec := httptest.NewRecorder()
+	server.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/reports", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated list status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	definition := `title: Names
+query: SELECT scientific_name, author FROM oak_entries ORDER BY scientific_name
+columns:
+  - {name: scientific_name, title: Species}
+`
+	if w := send(http.MethodPut, "/api/v1/reports/names", "application/yaml", definition); w.Code != http.StatusCreated {
+		t.Fatalf("create report status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	tests := map[string]string{
+		"title: Bad\nquery: DELETE FROM oak_entries\n":                 "only reads are allowed",
+		"title: Bad\nquery: SELECT 1 AS one\ncolumns: [{name: two}]\n": "not in the query's results",
+		"title: Bad\nquery: SELECT 1\nformat: xml\n":                   "format must be csv or json",
+		"query: SELECT 1\n": "title is required",
+	}
+	for body, want := range tests {
+		w := send(http.MethodPut, "/api/v1/reports/bad", "application/yaml", body)
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), want) {
+			t.Errorf("%q: status %d, body %s; want 400 mentioning %q", body, w.Code, w.Body.String(), want)
+		}
+	}
+
+	w := send(http.MethodGet, "/api/v1/reports/names/run", "", "")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/csv; charset=utf-8" {
+		t.Fatalf("run status = %d, content type %q. Body: %s", w.Code, w.Header().Get("Content-Type"), w.Body.String())
+	}
+	if got, want := w.Body.String(), "Species\nalba\nrubra\n"; got != want {
+		t.Errorf("csv = %q, want %q", got, want)
+	}
+
+	w = send(http.MethodGet, "/api/v1/reports/names/run?format=json&limit=1", "", "")
+	var run ReportRunResponse
+	if err := json.NewDecoder(w.Body).Decode(&run); err != nil {
+		t.Fatalf("decode run: %v", err)
+	}
+	if run.Report != "names" || len(run.Rows) != 1 || !run.Truncated || run.Columns[0] != "Species" {
+		t.Errorf("json run = %+v", run)
+	}
+
+	// Bundled reports are seeded alongside
+	w = send(http.MethodGet, "/api/v1/reports", "", "")
+	var list struct {
+		Data []models.Report `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	var names []string
+	for _, r := range list.Data {
+		names = append(names, r.Name)
+	}
+	if !slices.Contains(names, "names") || !slices.Contains(names, "hybrids-missing-parents") {
+		t.Errorf("reports = %v, want names and the bundled ones", names)
+	}
+
+	if w := send(http.MethodDelete, "/api/v1/reports/names", "", ""); w.Code != http.StatusNoContent {
+		t.Errorf("delete report status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w := send(http.MethodGet, "/api/v1/reports/names/run", "", ""); w.Code != http.StatusNotFound {
+		t.Errorf("run deleted report status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestCustomFields(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", `{"scientific_name":"alba"}`)
+	if w := send(http.MethodPut, "/api/v1/custom-fields/hardiness_zone", `{"type":"number","label":"USDA zone","applies_to":"species","export":true}`); w.Code != http.StatusOK {
+		t.Fatalf("define status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	send(http.MethodPut, "/api/v1/custom-fields/herbarium_sheet", `{"type":"url","label":"Herbarium sheet","applies_to":"species"}`)
+	send(http.MethodPut, "/api/v1/custom-fields/digitized", `{"type":"boolean","label":"Digitized","applies_to":"source","export":true}`)
+
+	for _, body := range []string{
+		`{"type":"color","label":"X","applies_to":"species"}`,
+		`{"type":"text","label":" ","applies_to":"species"}`,
+		`{"type":"text","label":"X","applies_to":"genus"}`,
+	} {
+		if w := send(http.MethodPut, "/api/v1/custom-fields/bad", body); w.Code != http.StatusBadRequest {
+			t.Errorf("define %s status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+	if w := send(http.MethodPut, "/api/v1/custom-fields/Bad-Key", `{"type":"text","label":"X","applies_to":"species"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("define Bad-Key status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	w := send(http.MethodGet, "/api/v1/custom-fields?applies_to=species", "")
+	var fields CustomFieldsResponse
+	json.NewDecoder(w.Body).Decode(&fields)
+	if len(fields.Fields) != 2 || fields.Fields[0].Key != "hardiness_zone" {
+		t.Errorf("species fields = %+v, want hardiness_zone and herbarium_sheet", fields.Fields)
+	}
+
+	w = send(http.MethodPut, "/api/v1/species/alba/attributes", `{"attributes":{"hardiness_zone":3,"herbarium_sheet":"https://example.org/sheet/1"}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("set attributes status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	var attrs AttributesResponse
+	json.NewDecoder(w.Body).Decode(&attrs)
+	if attrs.Attributes["hardiness_zone"] != 3.0 || attrs.Attributes["herbarium_sheet"] != "https://example.org/sheet/1" {
+		t.Errorf("attributes = %v", attrs.Attributes)
+	}
+
+	for _, body := range []string{
+		`{"attributes":{"hardiness_zone":"three"}}`,
+		`{"attributes":{"herbarium_sheet":"sheet 1"}}`,
+		`{"attributes":{"digitized":true}}`,
+		`{"attributes":{"undefined":"x"}}`,
+	} {
+		if w := send(http.MethodPut, "/api/v1/species/alba/attributes", body); w.Code != http.StatusBadRequest {
+			t.Errorf("set %s status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+	if w := send(http.MethodPut, "/api/v1/species/nonexistent/attributes", `{"attributes":{}}`); w.Code != http.StatusNotFound {
+		t.Errorf("set for missing species status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	// A field with values keeps its type
+	if w := send(http.MethodPut, "/api/v1/custom-fields/hardiness_zone", `{"type":"text","label":"USDA zone","applies_to":"species"}`); w.Code != http.StatusConflict {
+		t.Errorf("retype field in use status = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	// null removes a value and leaves the others
+	w = send(http.MethodPut, "/api/v1/species/alba/attributes", `{"attributes":{"herbarium_sheet":null}}`)
+	attrs = AttributesResponse{}
+	json.NewDecoder(w.Body).Decode(&attrs)
+	if len(attrs.Attributes) != 1 || attrs.Attributes["hardiness_zone"] != 3.0 {
+		t.Errorf("attributes after removing herbarium_sheet = %v", attrs.Attributes)
+	}
+
+	w = send(http.MethodPost, "/api/v1/sources", `{"source_type":"Book","name":"Oaks of the World"}`)
+	var source models.Source
+	json.NewDecoder(w.Body).Decode(&source)
+	sourcePath := fmt.Sprintf("/api/v1/sources/%d/attributes", source.ID)
+	if w := send(http.MethodPut, sourcePath, `{"attributes":{"digitized":true}}`); w.Code != http.StatusOK {
+		t.Fatalf("set source attributes status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodGet, "/api/v1/sources/999/attributes", ""); w.Code != http.StatusNotFound {
+		t.Errorf("get for missing source status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	// Only exported fields are in the export
+	w = send(http.MethodGet, "/api/v1/export", "")
+	var file struct {
+		Sources []struct {
+			Custom map[string]any `json:"custom"`
+		} `json:"sources"`
+		Species []struct {
+			Custom map[string]any `json:"custom"`
+		} `json:"species"`
+	}
+	json.NewDecoder(w.Body).Decode(&file)
+	if len(file.Species) != 1 || len(file.Species[0].Custom) != 1 || file.Species[0].Custom["hardiness_zone"] != 3.0 {
+		t.Errorf("exported species = %+v, want custom hardiness_zone 3", file.Species)
+	}
+	if len(file.Sources) != 1 || file.Sources[0].Custom["digitized"] != true {
+		t.Errorf("exported sources = %+v, want custom digitized true", file.Sources)
+	}
+
+	if w := send(http.MethodDelete, "/api/v1/custom-fields/hardiness_zone", ""); w.Code != http.StatusNoContent {
+		t.Errorf("delete status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w := send(http.MethodDelete, "/api/v1/custom-fields/hardiness_zone", ""); w.Code != http.StatusNotFound {
+		t.Errorf("second delete status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	w = send(http.MethodGet, "/api/v1/species/alba/attributes", "")
+	attrs = AttributesResponse{}
+	json.NewDecoder(w.Body).Decode(&attrs)
+	if len(attrs.Attributes) != 0 {
+		t.Errorf("attributes after deleting field = %v, want none", attrs.Attributes)
+	}
+}
+
+func TestCustomFieldsInvalidateExport(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	exported := func() map[string]any {
+		var file struct {
+			Species []struct {
+				Custom map[string]any `json:"custom"`
+			} `json:"species"`
+		}
+		json.NewDecoder(send(http.MethodGet, "/api/v1/export", "").Body).Decode(&file)
+		if len(file.Species) != 1 {
+			t.Fatalf("exported species = %+v, want alba", file.Species)
+		}
+		return file.Species[0].Custom
+	}
+
+	send(http.MethodPost, "/api/v1/species", `{"scientific_name":"alba"}`)
+	send(http.MethodPut, "/api/v1/custom-fields/hardiness_zone", `{"type":"number","label":"USDA zone","applies_to":"species","export":true}`)
+	if custom := exported(); len(custom) != 0 {
+		t.Errorf("custom before setting = %v, want none", custom)
+	}
+
+	// The cached full export is rebuilt after each attribute or field write
+	if w := send(http.MethodPut, "/api/v1/species/alba/attributes", `{"attributes":{"hardiness_zone":3}}`); w.Code != http.StatusOK {
+		t.Fatalf("set attributes status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	if custom := exported(); custom["hardiness_zone"] != 3.0 {
+		t.Errorf("custom after setting = %v, want hardiness_zone 3", custom)
+	}
+	if w := send(http.MethodPut, "/api/v1/custom-fields/hardiness_zone", `{"type":"number","label":"USDA zone","applies_to":"species"}`); w.Code != http.StatusOK {
+		t.Fatalf("unexport status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	if custom := exported(); len(custom) != 0 {
+		t.Errorf("custom after unexporting = %v, want none", custom)
+	}
+}
+
+func TestSpeciesINaturalist(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	inat := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/taxa" && r.URL.Query().Get("q") == "Quercus alba":
+			fmt.Fprint(w, `{"results": [{"id": 54779, "name": "Quercus alba", "rank": "species", "preferred_common_name": "white oak"}]}`)
+		case r.URL.Path == "/taxa":
+			fmt.Fprint(w, `{"results": []}`)
+		case r.URL.Path == "/taxa/54779":
+			fmt.Fprint(w, `{"results": [{"id": 54779, "taxon_photos": [
+				{"photo": {"id": 1, "medium_url": "https://example.org/1/medium.jpg", "square_url": "https://example.org/1/square.jpg", "attribution": "(c) A", "license_code": "cc-by"}},
+				{"photo": {"id": 2, "medium_url": "https://example.org/2/medium.jpg"}}]}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer inat.Close()
+	server.inaturalist = inaturalist.New(inat.URL, "test")
+	server.inaturalist.Interval = 0
+
+	send := func(method, path string) (*httptest.ResponseRecorder, INaturalistResponse) {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		var resp INaturalistResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp
+	}
+	for _, name := range []string{"alba", "nowhere"} {
+		if err := server.db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w, resp := send(http.MethodPost, "/api/v1/species/alba/inaturalist?photos=1&dry_run=true")
+	if w.Code != http.StatusOK || resp.TaxonID == nil || *resp.TaxonID != 54779 || !resp.LinkChanged || len(resp.Photos) != 1 {
+		t.Fatalf("dry run = %d %+v", w.Code, resp)
+	}
+	if entry, _ := server.db.GetOakEntry("alba"); len(entry.ExternalLinks) != 0 {
+		t.Errorf("dry run saved links %v", entry.ExternalLinks)
+	}
+
+	w, resp = send(http.MethodPost, "/api/v1/species/alba/inaturalist?photos=5")
+	if w.Code != http.StatusOK || resp.CommonName != "white oak" || len(resp.Photos) != 2 {
+		t.Fatalf("enrich = %d %+v", w.Code, resp)
+	}
+	if p := resp.Photos[0]; p.License == nil || *p.License != "cc-by" || p.ThumbnailURL == nil || p.ID == 0 {
+		t.Errorf("photo = %+v", p)
+	}
+
+	// A second lookup finds the link in place and keeps the photos
+	if _, resp := send(http.MethodPost, "/api/v1/species/alba/inaturalist"); resp.LinkChanged || len(resp.Photos) != 2 {
+		t.Errorf("second enrich = %+v, want no change and the cached photos", resp)
+	}
+	w, resp = send(http.MethodGet, "/api/v1/species/alba/inaturalist")
+	if w.Code != http.StatusOK || resp.TaxonID == nil || *resp.TaxonID != 54779 || *resp.URL != "https://www.inaturalist.org/taxa/54779" || len(resp.Photos) != 2 {
+		t.Errorf("get = %d %+v", w.Code, resp)
+	}
+	entry, _ := server.db.GetOakEntry("alba")
+	if len(entry.ExternalLinks) != 1 || entry.ExternalLinks[0].Logo != "inaturalist" {
+		t.Errorf("links = %+v, want the iNaturalist link", entry.ExternalLinks)
+	}
+
+	if w, resp := send(http.MethodPost, "/api/v1/species/nowhere/inaturalist"); w.Code != http.StatusOK || resp.TaxonID != nil {
+		t.Errorf("unmatched = %d %+v, want a null taxon_id", w.Code, resp)
+	}
+	if w, _ := send(http.MethodPost, "/api/v1/species/alba/inaturalist?photos=100"); w.Code != http.StatusBadRequest {
+		t.Errorf("?photos=100 status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w, _ := send(http.MethodPost, "/api/v1/species/missing/inaturalist"); w.Code != http.StatusNotFound {
+		t.Errorf("missing species status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestUploadSpeciesMedia(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	if err := server.db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatal(err)
+	}
+
+	var img bytes.Buffer
+	png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 800, 400)))
+	upload := func(name string, content []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, _ := form.CreateFormFile("file", "alba-bark.png")
+		part.Write(content)
+		form.WriteField("license", "CC BY 4.0")
+		form.Close()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/species/"+name+"/media", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := upload("alba", img.Bytes()); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("upload without a media directory = %d, want 503", w.Code)
+	}
+	server.media = media.NewStore(t.TempDir())
+
+	w := upload("alba", img.Bytes())
+	if w.Code != http.StatusCreated {
+		t.Fatalf("upload = %d: %s", w.Code, w.Body.String())
+	}
+	var m db.Media
+	json.Unmarshal(w.Body.Bytes(), &m)
+	if m.Origin != db.MediaOriginUpload || m.License == nil || *m.License != "CC BY 4.0" || m.Filename == nil || *m.Filename != "alba-bark.png" ||
+		m.Width == nil || *m.Width != 800 || m.ThumbnailURL == nil {
+		t.Errorf("media = %+v", m)
+	}
+	if w := upload("alba", img.Bytes()); w.Code != http.StatusConflict {
+		t.Errorf("second upload = %d, want 409", w.Code)
+	}
+	if w := upload("alba", []byte("not an image")); w.Code != http.StatusBadRequest {
+		t.Errorf("upload of text = %d, want 400", w.Code)
+	}
+	if w := upload("nowhere", img.Bytes()); w.Code != http.StatusNotFound {
+		t.Errorf("upload to unknown species = %d, want 404", w.Code)
+	}
+
+	for path, wantType := range map[string]string{m.URL: "image/png", *m.ThumbnailURL: "image/jpeg"} {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != wantType {
+			t.Errorf("GET %s = %d %s, want 200 %s", path, w.Code, w.Header().Get("Content-Type"), wantType)
+		}
+	}
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/media/..%2Foak.db", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GET outside the media directory = %d, want 404", w.Code)
+	}
+}
+
+func TestSpeciesMedia(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	server.media = media.NewStore(t.TempDir())
+	server.generalize = rangemap.Generalization{MinStatus: "VU", Grid: 0.1}
+	entry := models.NewOakEntry("alba")
+	status := "EN"
+	entry.ConservationStatus = &status
+	for _, e := range []*models.OakEntry{entry, models.NewOakEntry("rubra")} {
+		if err := server.db.SaveOakEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	send := func(method, path, body string, auth bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if auth {
+			req.Header.Set("Authorization", "Bearer test-api-key")
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	list := func(path string, auth bool) []db.Media {
+		w := send(http.MethodGet, path, "", auth)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", path, w.Code, w.Body.String())
+		}
+		var resp struct{ Data []db.Media }
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp.Data
+	}
+
+	w := send(http.MethodPost, "/api/v1/species/alba/media", `{"url": "https://example.org/bark.jpg", "kind": "bark", "caption": "Bark"}`, true)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("link = %d: %s", w.Code, w.Body.String())
+	}
+	for body, want := range map[string]int{
+		`{"url": "https://example.org/bark.jpg"}`:               http.StatusConflict,
+		`{"url": "https://example.org/a.jpg", "kind": "root"}`:  http.StatusBadRequest,
+		`{"url": "https://example.org/a.jpg", "source_id": 99}`: http.StatusBadRequest,
+		`{"url": "ftp://example.org/a.jpg"}`:                    http.StatusBadRequest,
+	} {
+		if w := send(http.MethodPost, "/api/v1/species/alba/media", body, true); w.Code != want {
+			t.Errorf("POST %s = %d, want %d", body, w.Code, want)
+		}
+	}
+
+	// A photo's coordinates are generalized for the public
+	lat, lon := 38.8977, -77.0365
+	acorn := "acorn"
+	server.db.AddSpeciesMedia(&db.Media{ScientificName: "alba", URL: "https://example.org/acorn.jpg", Origin: db.MediaOriginLink,
+		Kind: &acorn, Latitude: &lat, Longitude: &lon})
+	items := list("/api/v1/species/alba/media?kind=acorn", false)
+	if len(items) != 1 || *items[0].Latitude != 38.85 || items[0].Generalized != 0.1 {
+		t.Errorf("public acorn media = %+v, want one generalized to 38.85", items)
+	}
+	if items := list("/api/v1/species/alba/media", true); len(items) != 2 || *items[1].Latitude != lat || items[1].Generalized != 0 {
+		t.Errorf("curator media = %+v, want two, exact", items)
+	}
+
+	// Deleting an upload removes its files once no media uses them
+	var img bytes.Buffer
+	png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 8, 8)))
+	saved, err := server.media.Save(img.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, name := range []string{"alba", "rubra"} {
+		m := uploadedMedia(name, saved)
+		if err := server.db.AddSpeciesMedia(m); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, m.ID)
+	}
+	path, _ := server.media.Path(saved.File)
+	if w := send(http.MethodDelete, fmt.Sprintf("/api/v1/species/rubra/media/%d", ids[0]), "", true); w.Code != http.StatusNotFound {
+		t.Errorf("DELETE another species' media = %d, want 404", w.Code)
+	}
+	if w := send(http.MethodDelete, fmt.Sprintf("/api/v1/species/alba/media/%d", ids[0]), "", true); w.Code != http.StatusNoContent {
+		t.Fatalf("DELETE = %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("file after deleting one of two uses: %v, want kept", err)
+	}
+	if w := send(http.MethodDelete, fmt.Sprintf("/api/v1/species/rubra/media/%d", ids[1]), "", true); w.Code != http.StatusNoContent {
+		t.Fatalf("DELETE = %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("file after deleting its last use: %v, want removed", err)
+	}
+	if items := list("/api/v1/species/rubra/media", true); len(items) != 0 {
+		t.Errorf("rubra media after delete = %+v, want none", items)
+	}
+}
+
+func TestVocabularies(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	// The bundled vocabularies are bound to their fields
+	w := send(http.MethodGet, "/api/v1/vocabularies/conservation_status", "")
+	var status models.Vocabulary
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Field == nil || *status.Field != models.VocabularyFieldConservationStatus || len(status.Terms) != 9 {
+		t.Fatalf("conservation_status = %+v", status)
+	}
+
+	// Species writes are validated against the bound vocabulary
+	if w := send(http.MethodPost, "/api/v1/species", `{"scientific_name":"alba","conservation_status":"RE"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("create with unknown status = %d, want 400", w.Code)
+	}
+	added := `{"field":"species.conservation_status","terms":[{"term":"LC"},{"term":"RE","definition":"Regionally Extinct"}]}`
+	if w := send(http.MethodPut, "/api/v1/vocabularies/conservation_status", added); w.Code != http.StatusOK {
+		t.Fatalf("replace vocabulary = %d: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodPost, "/api/v1/species", `{"scientific_name":"alba","conservation_status":"RE"}`); w.Code != http.StatusCreated {
+		t.Fatalf("create with added status = %d: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodGet, "/api/v1/species?conservation_status=re", ""); w.Code != http.StatusOK {
+		t.Errorf("?conservation_status=re = %d, want 200", w.Code)
+	}
+	if w := send(http.MethodGet, "/api/v1/species?conservation_status=EN", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("?conservation_status=EN = %d, want 400 once EN is not a term", w.Code)
+	}
+
+	// Terms in use cannot be removed, and a field takes one vocabulary
+	if w := send(http.MethodPut, "/api/v1/vocabularies/conservation_status", `{"field":"species.conservation_status","terms":[{"term":"LC"}]}`); w.Code != http.StatusConflict {
+		t.Errorf("remove term in use = %d, want 409", w.Code)
+	}
+	if w := send(http.MethodPut, "/api/v1/vocabularies/iucn", `{"field":"species.conservation_status","terms":[{"term":"RE"}]}`); w.Code != http.StatusConflict {
+		t.Errorf("bind bound field = %d, want 409", w.Code)
+	}
+	if w := send(http.MethodPut, "/api/v1/vocabularies/genus", `{"terms":[{"term":"Quercus"}]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("built-in name = %d, want 400", w.Code)
+	}
+	if w := send(http.MethodDelete, "/api/v1/vocabularies/conservation_status", ""); w.Code != http.StatusConflict {
+		t.Errorf("delete bound vocabulary = %d, want 409", w.Code)
+	}
+
+	// Source types are stored in key form, and aliases still map onto terms
+	types := `{"field":"source.source_type","terms":[{"term":"book"},{"term":"paper"},{"term":"Herbarium Sheet"}]}`
+	if w := send(http.MethodPut, "/api/v1/vocabularies/source_type", types); w.Code != http.StatusOK {
+		t.Fatalf("replace source_type = %d: %s", w.Code, w.Body.String())
+	}
+	w = send(http.MethodPost, "/api/v1/sources", `{"source_type":"herbarium_sheet","name":"Kew sheets"}`)
+	var source models.Source
+	if err := json.Unmarshal(w.Body.Bytes(), &source); err != nil || w.Code != http.StatusCreated {
+		t.Fatalf("create source = %d: %s", w.Code, w.Body.String())
+	}
+	if source.SourceType != "herbarium-sheet" {
+		t.Errorf("source_type = %q, want herbarium-sheet", source.SourceType)
+	}
+	if w := send(http.MethodPost, "/api/v1/sources", `{"source_type":"journal","name":"Madroño"}`); w.Code != http.StatusCreated {
+		t.Errorf("create with alias = %d: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodPost, "/api/v1/sources", `{"source_type":"website","name":"Oaks of the World"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("create with removed type = %d, want 400", w.Code)
+	}
+
+	// The schemas follow the vocabularies
+	w = send(http.MethodGet, "/api/v1/schemas/source", "")
+	if !strings.Contains(w.Body.String(), `"enum":["book","paper","herbarium-sheet"]`) {
+		t.Errorf("source schema does not list the source_type terms: %s", w.Body.String())
+	}
+
+	// Unbound vocabularies document terms and can be deleted
+	if w := send(http.MethodPut, "/api/v1/vocabularies/leaf-shape", `{"terms":[{"term":"obovate","definition":"Widest above the middle"}]}`); w.Code != http.StatusCreated {
+		t.Fatalf("create vocabulary = %d: %s", w.Code, w.Body.String())
+	}
+	w = send(http.MethodGet, "/api/v1/vocabularies", "")
+	var list ListResponse[models.Vocabulary]
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Data) != 3 {
+		t.Errorf("got %d vocabularies, want 3", len(list.Data))
+	}
+	if w := send(http.MethodDelete, "/api/v1/vocabularies/leaf-shape", ""); w.Code != http.StatusNoContent {
+		t.Errorf("delete vocabulary = %d, want 204", w.Code)
+	}
+	if w := send(http.MethodGet, "/api/v1/vocabularies/leaf-shape", ""); w.Code != http.StatusNotFound {
+		t.Errorf("get deleted vocabulary = %d, want 404", w.Code)
+	}
+}
+
+func TestSchemas(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/v1/schemas")
+	var list struct {
+		Data []SchemaInfo `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(list.Data) != 4 {
+		t.Fatalf("schemas = %+v, want 4", list.Data)
+	}
+
+	// Every listed schema is served
+	for _, info := range list.Data {
+		if w := get(info.URL); w.Code != http.StatusOK {
+			t.Errorf("GET %s status = %d, want %d", info.URL, w.Code, http.StatusOK)
+		}
+	}
+	if w := get("/api/v1/schemas/genus"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown schema status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	var schema struct {
+		Schema     string `json:"$schema"`
+		Required   []string
+		Properties map[string]struct {
+			Enum []interface{} `json:"enum"`
+		} `json:"properties"`
+	}
+	if err := json.NewDecoder(get("/api/v1/schemas/oak-entry").Body).Decode(&schema); err != nil {
+		t.Fatalf("decode oak-entry schema: %v", err)
+	}
+	if schema.Schema != jsonSchemaDraft || len(schema.Required) != 1 || schema.Required[0] != "scientific_name" {
+		t.Errorf("oak-entry schema = %+v", schema)
+	}
+	found := false
+	for _, v := range schema.Properties["conservation_status"].Enum {
+		if v == "VU" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("conservation_status enum = %v, want VU", schema.Properties["conservation_status"].Enum)
+	}
+
+	// Taxon levels follow the configuration
+	plural := "series"
+	if err := server.db.SaveTaxonLevel(&models.TaxonLevelDef{Name: "series", Rank: 5, Plural: &plural}); err != nil {
+		t.Fatalf("SaveTaxonLevel failed: %v", err)
+	}
+	if err := json.NewDecoder(get("/api/v1/schemas/taxon").Body).Decode(&schema); err != nil {
+		t.Fatalf("decode taxon schema: %v", err)
+	}
+	levels := schema.Properties["level"].Enum
+	if len(levels) != 5 || levels[4] != "series" {
+		t.Errorf("level enum = %v, want 5 levels ending in series", levels)
+	}
+}
+
+func TestDataDictionary(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/schema/data-dictionary", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("data dictionary = %d: %s", w.Code, w.Body.String())
+	}
+	var dict DataDictionary
+	if err := json.NewDecoder(w.Body).Decode(&dict); err != nil {
+		t.Fatalf("decode data dictionary: %v", err)
+	}
+
+	// Every field read from the structs is described, and every vocabulary a field names exists
+	vocabularies := map[string]bool{}
+	for _, v := range dict.Vocabularies {
+		vocabularies[v.Name] = len(v.Values) > 0
+	}
+	fields := map[string]DictionaryField{}
+	for _, e := range dict.Entities {
+		for _, f := range e.Fields {
+			fields[e.Name+"."+f.Name] = f
+			if f.Description == "" {
+				t.Errorf("%s.%s has no description in dictionaryDescriptions", e.Name, f.Name)
+			}
+			if f.Vocabulary != "" && !vocabularies[f.Vocabulary] {
+				t.Errorf("%s.%s names vocabulary %q, which is missing or empty", e.Name, f.Name, f.Vocabulary)
+			}
+		}
+	}
+	for key := range dictionaryDescriptions {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("dictionaryDescriptions has %s, which no struct has", key)
+		}
+	}
+	for _, key := range models.VocabularyFields {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("vocabulary field %s is not a dictionary field", key)
+		}
+	}
+
+	for key, want := range map[string]DictionaryField{
+		"species.name":                  {Type: "string", Required: true},
+		"species.sources":               {Type: "species_source[]", Required: true},
+		"species.taxonomy.section":      {Type: "string"},
+		"species.external_links[].logo": {Type: "string", Required: true, Vocabulary: "external_link_logo"},
+		"source.year":                   {Type: "integer"},
+		"source.source_type":            {Type: "string", Required: true, Vocabulary: "source_type"},
+		"species.conservation_status":   {Type: "string", Vocabulary: "conservation_status"},
+	} {
+		got := fields[key]
+		if got.Type != want.Type || got.Required != want.Required || got.Vocabulary != want.Vocabulary {
+			t.Errorf("%s = %+v, want type %s, required %v, vocabulary %q", key, got, want.Type, want.Required, want.Vocabulary)
+		}
+	}
+
+}
+
+func TestSpeciesAccounts(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	for _, name := range []string{"alba", "rubra"} {
+		if w := send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: name}); w.Code != http.StatusCreated {
+			t.Fatalf("create %s status = %d", name, w.Code)
+		}
+	}
+
+	req := AccountRequest{Markdown: "## Identification\n\nCompare with [[alba]] and [[nigra]].\n\n![Leaf](images/rubra-leaf.jpg)"}
+	if w := send(http.MethodPut, "/api/v1/species/missing/account", req); w.Code != http.StatusNotFound {
+		t.Errorf("account for missing species status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	w := send(http.MethodPut, "/api/v1/species/rubra/account", req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create account status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	var account models.SpeciesAccount
+	if err := json.NewDecoder(w.Body).Decode(&account); err != nil {
+		t.Fatalf("decode account: %v", err)
+	}
+	if len(account.MissingLinks) != 1 || account.MissingLinks[0] != "nigra" {
+		t.Errorf("missing_links = %v, want [nigra]", account.MissingLinks)
+	}
+	if w := send(http.MethodPut, "/api/v1/species/rubra/account", req); w.Code != http.StatusOK {
+		t.Errorf("replace account status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// Accounts are readable without a key
+	w = get("/api/v1/species/rubra/account")
+	if w.Code != http.StatusOK {
+		t.Fatalf("get account status = %d, want %d", w.Code, http.StatusOK)
+	}
+	account = models.SpeciesAccount{}
+	if err := json.NewDecoder(w.Body).Decode(&account); err != nil {
+		t.Fatalf("decode account: %v", err)
+	}
+	if !strings.Contains(account.HTML, `<a class="species-link" href="/species/alba/">alba</a>`) ||
+		len(account.Links) != 2 || len(account.Images) != 1 || account.Images[0] != "images/rubra-leaf.jpg" {
+		t.Errorf("account = %+v", account)
+	}
+	if w := get("/api/v1/species/alba/account"); w.Code != http.StatusNotFound {
+		t.Errorf("get absent account status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	// Export embeds accounts only when asked
+	if w := get("/api/v1/export"); strings.Contains(w.Body.String(), "species-link") {
+		t.Error("export includes accounts without ?accounts=true")
+	}
+	if w := get("/api/v1/export?accounts=true"); !strings.Contains(w.Body.String(), "species-link") {
+		t.Error("export with ?accounts=true is missing the rendered account")
+	}
+
+	// Drafts hide their account from the public
+	if w := send(http.MethodPut, "/api/v1/species/rubra/visibility", VisibilityRequest{Visibility: models.VisibilityDraft}); w.Code != http.StatusOK {
+		t.Fatalf("unpublish status = %d", w.Code)
+	}
+	if w := get("/api/v1/species/rubra/account"); w.Code != http.StatusNotFound {
+		t.Errorf("draft account status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	if w := send(http.MethodDelete, "/api/v1/species/rubra/account", nil); w.Code != http.StatusNoContent {
+		t.Errorf("delete account status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w := send(http.MethodDelete, "/api/v1/species/rubra/account", nil); w.Code != http.StatusNotFound {
+		t.Errorf("delete absent account status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestSpeciesMentions(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	for _, name := range []string{"alba", "stellata"} {
+		if w := send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: name}); w.Code != http.StatusCreated {
+			t.Fatalf("create %s status = %d", name, w.Code)
+		}
+	}
+
+	w := send(http.MethodPut, "/api/v1/species/alba/account", AccountRequest{Markdown: "Closely resembles Q. stellata."})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create account status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	var account models.SpeciesAccount
+	if err := json.NewDecoder(w.Body).Decode(&account); err != nil {
+		t.Fatalf("decode account: %v", err)
+	}
+	if !strings.Contains(account.HTML, `<a class="species-link" href="/species/stellata/">Q. stellata</a>`) {
+		t.Errorf("mention not linked: %s", account.HTML)
+	}
+
+	w = get("/api/v1/species/stellata/mentions")
+	if w.Code != http.StatusOK {
+		t.Fatalf("mentions status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var mentions []models.CrossReference
+	if err := json.NewDecoder(w.Body).Decode(&mentions); err != nil {
+		t.Fatalf("decode mentions: %v", err)
+	}
+	if len(mentions) != 1 || mentions[0].ScientificName != "alba" || mentions[0].Location != "account" {
+		t.Errorf("mentions = %+v", mentions)
+	}
+
+	// Mentions from drafts are hidden from the public
+	if w := send(http.MethodPut, "/api/v1/species/alba/visibility", VisibilityRequest{Visibility: models.VisibilityDraft}); w.Code != http.StatusOK {
+		t.Fatalf("unpublish status = %d", w.Code)
+	}
+	if w := get("/api/v1/species/stellata/mentions"); strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("public mentions with draft source = %s, want []", w.Body.String())
+	}
+	if w := send(http.MethodGet, "/api/v1/species/stellata/mentions", nil); !strings.Contains(w.Body.String(), "alba") {
+		t.Errorf("authenticated mentions = %s, want alba's mention", w.Body.String())
+	}
+
+	if w := get("/api/v1/species/missing/mentions"); w.Code != http.StatusNotFound {
+		t.Errorf("missing species mentions status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestAuthors(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get("/api/v1/authors/" + url.PathEscape("Münchh."))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Otto von Münchhausen") {
+		t.Fatalf("get bundled author status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	if w := get("/api/v1/authors/Nobody"); w.Code != http.StatusNotFound {
+		t.Errorf("missing author status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	w = send(http.MethodPut, "/api/v1/authors", []models.Author{{Abbreviation: "Lemmon"}})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("author without full_name status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	w = send(http.MethodPut, "/api/v1/authors", []models.Author{{Abbreviation: "Lemmon", FullName: "John Gill Lemmon"}})
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"created":1`) {
+		t.Fatalf("upsert authors status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	pronunciation := "KWER-kus LEM-on-ee-eye"
+	author := "(Münchh.) Lemmon"
+	req := SpeciesRequest{ScientificName: "lemmonii", Author: &author, Pronunciation: &pronunciation}
+	if w := send(http.MethodPost, "/api/v1/species", req); w.Code != http.StatusCreated {
+		t.Fatalf("create species status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	w = get("/api/v1/species/lemmonii/full")
+	if w.Code != http.StatusOK {
+		t.Fatalf("full species status = %d", w.Code)
+	}
+	var full models.SpeciesWithSources
+	if err := json.NewDecoder(w.Body).Decode(&full); err != nil {
+		t.Fatalf("decode species: %v", err)
+	}
+	if full.Pronunciation == nil || *full.Pronunciation != pronunciation {
+		t.Errorf("pronunciation = %v, want %q", full.Pronunciation, pronunciation)
+	}
+	if len(full.AuthorDetails) != 2 || full.AuthorDetails[0].Dates == nil || *full.AuthorDetails[0].Dates != "1716-1774" ||
+		full.AuthorDetails[1].FullName != "John Gill Lemmon" {
+		t.Errorf("author_details = %+v", full.AuthorDetails)
+	}
+}
+
+func TestSpeciesMeasurements(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "alba"})
+	send(http.MethodPost, "/api/v1/sources", SourceRequest{SourceType: "book", Name: "Oaks of North America"})
+	leaves := "Blades 10-20 cm long."
+	if w := send(http.MethodPost, "/api/v1/species/alba/sources", SpeciesSourceRequest{SourceID: 1, Leaves: &leaves}); w.Code != http.StatusCreated {
+		t.Fatalf("create species source status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	w := get("/api/v1/species/alba/measurements")
+	if w.Code != http.StatusOK {
+		t.Fatalf("measurements status = %d", w.Code)
+	}
+	var measurements []models.Measurement
+	if err := json.NewDecoder(w.Body).Decode(&measurements); err != nil {
+		t.Fatalf("decode measurements: %v", err)
+	}
+	if len(measurements) != 1 || measurements[0].Field != "leaves" || measurements[0].Min == nil ||
+		*measurements[0].Min != 10 || measurements[0].Max != 20 || measurements[0].Unit != "cm" {
+		t.Errorf("measurements = %+v", measurements)
+	}
+
+	w = get("/api/v1/species/alba/sources/1?units=imperial")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Blades 3.9–7.9 in long.") {
+		t.Errorf("imperial species source status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	w = get("/api/v1/species/alba/full?units=dual")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Blades 10-20 cm (3.9–7.9 in) long.") {
+		t.Errorf("dual full species status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	if w := get("/api/v1/species/alba/sources?units=cubits"); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid units status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestFeatureSuggestions(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "texana"})
+	send(http.MethodPost, "/api/v1/sources", SourceRequest{SourceType: "book", Name: "Oaks of North America"})
+	leaves := "Blades 7-lobed. Readily separated from Q. shumardii by its smaller acorns."
+	if w := send(http.MethodPost, "/api/v1/species/texana/sources", SpeciesSourceRequest{SourceID: 1, Leaves: &leaves}); w.Code != http.StatusCreated {
+		t.Fatalf("create species source status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/feature-suggestions", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated list status = %d, want 401", w.Code)
+	}
+
+	if w := send(http.MethodPost, "/api/v1/feature-suggestions/generate", GenerateFeatureSuggestionsRequest{Species: []string{"nope"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown species status = %d, want 400", w.Code)
+	}
+	w = send(http.MethodPost, "/api/v1/feature-suggestions/generate", nil)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"queued":1`) {
+		t.Fatalf("generate status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	w = send(http.MethodGet, "/api/v1/feature-suggestions?species=texana", nil)
+	var list ListResponse[db.FeatureSuggestion]
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Data) != 1 {
+		t.Fatalf("list = %s", w.Body.String())
+	}
+	id := list.Data[0].ID
+
+	w = send(http.MethodPost, fmt.Sprintf("/api/v1/feature-suggestions/%d/accept", id), nil)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"status":"accepted"`) {
+		t.Fatalf("accept status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodPost, fmt.Sprintf("/api/v1/feature-suggestions/%d/reject", id), nil); w.Code != http.StatusConflict {
+		t.Errorf("reject after accept status = %d, want 409", w.Code)
+	}
+	if w := send(http.MethodPost, "/api/v1/feature-suggestions/99/reject", nil); w.Code != http.StatusNotFound {
+		t.Errorf("missing suggestion status = %d, want 404", w.Code)
+	}
+
+	w = send(http.MethodGet, "/api/v1/species/texana/sources/1", nil)
+	if !strings.Contains(w.Body.String(), `"distinguishing_features":"Readily separated from Q. shumardii by its smaller acorns."`) {
+		t.Errorf("species source = %s", w.Body.String())
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "texana"})
+	for i, growth := range []string{"Tree to 12 m.", "Tree to 30 m."} {
+		send(http.MethodPost, "/api/v1/sources", SourceRequest{SourceType: "book", Name: fmt.Sprintf("Source %d", i+1)})
+		if w := send(http.MethodPost, "/api/v1/species/texana/sources", SpeciesSourceRequest{SourceID: int64(i + 1), GrowthHabit: &growth}); w.Code != http.StatusCreated {
+			t.Fatalf("create species source status = %d. Body: %s", w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/conflicts", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated list status = %d, want 401", w.Code)
+	}
+	if w := send(http.MethodGet, "/api/v1/conflicts?property=color", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown property status = %d, want 400", w.Code)
+	}
+
+	// Saving the second source detected the conflict
+	w = send(http.MethodGet, "/api/v1/conflicts?species=texana", nil)
+	var list ListResponse[db.Conflict]
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Data) != 1 {
+		t.Fatalf("list = %s", w.Body.String())
+	}
+	c := list.Data[0]
+	if c.Property != "max_height" || c.ValueA != "12 m" || c.ValueB != "30 m" {
+		t.Errorf("conflict = %+v", c)
+	}
+	if w := send(http.MethodPost, "/api/v1/conflicts/detect", DetectConflictsRequest{}); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"detected":0`) {
+		t.Errorf("detect status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	path := fmt.Sprintf("/api/v1/conflicts/%d/resolve", c.ID)
+	other := int64(9)
+	if w := send(http.MethodPost, path, ResolveConflictRequest{PreferredSourceID: &other, Justification: "x"}); w.Code != http.StatusBadRequest {
+		t.Errorf("foreign preferred source status = %d, want 400", w.Code)
+	}
+	if w := send(http.MethodPost, path, ResolveConflictRequest{Resolution: "30 m"}); w.Code != http.StatusBadRequest {
+		t.Errorf("missing justification status = %d, want 400", w.Code)
+	}
+	preferred := c.SourceB
+	w = send(http.MethodPost, path, ResolveConflictRequest{PreferredSourceID: &preferred, Justification: "Source 1 describes a shrubby form"})
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"status":"resolved"`) {
+		t.Fatalf("resolve status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodPost, path, ResolveConflictRequest{Resolution: "30 m", Justification: "again"}); w.Code != http.StatusConflict {
+		t.Errorf("second resolve status = %d, want 409", w.Code)
+	}
+	if w := send(http.MethodGet, "/api/v1/conflicts/99", nil); w.Code != http.StatusNotFound {
+		t.Errorf("missing conflict status = %d, want 404", w.Code)
+	}
+	if w := send(http.MethodGet, "/api/v1/conflicts", nil); !strings.Contains(w.Body.String(), `"data":[]`) {
+		t.Errorf("open conflicts after resolving = %s", w.Body.String())
+	}
+}
+
+func TestRangeLocalities(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "texana"})
+	send(http.MethodPost, "/api/v1/sources", SourceRequest{SourceType: "book", Name: "Oaks of North America"})
+	rangeText := "Edwards Plateau, Texas; Coahuila (rare); Atlantis"
+	if w := send(http.MethodPost, "/api/v1/species/texana/sources", SpeciesSourceRequest{SourceID: 1, Range: &rangeText}); w.Code != http.StatusCreated {
+		t.Fatalf("create species source status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	if w := send(http.MethodPost, "/api/v1/range-localities/geocode", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("geocode without a geocoder status = %d, want 400", w.Code)
+	}
+
+	geocoder := geocode.NewGazetteer(map[string]geocode.Place{
+		"Edwards Plateau, Texas": {Latitude: 30.5, Longitude: -100},
+		"Coahuila":               {Latitude: 27.3, Longitude: -102, Region: "Coahuila, México"},
+	})
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	server = New(server.db, "test-api-key", logger, server.version, WithoutMiddleware(), WithGeocoder(geocoder))
+	if !server.jobs.Has(JobKindGeocodeRanges) {
+		t.Fatal("geocode-ranges job not registered")
+	}
+
+	if w := send(http.MethodPost, "/api/v1/range-localities/geocode", GeocodeRangesRequest{Species: []string{"nope"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown species status = %d, want 400", w.Code)
+	}
+	if w := send(http.MethodPost, "/api/v1/range-localities/geocode", GeocodeRangesRequest{Species: []string{"texana"}}); w.Code != http.StatusAccepted {
+		t.Fatalf("geocode status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	// Workers are not started; run what the job would
+	report, err := server.db.GeocodeRanges(t.Context(), geocoder, []string{"texana"}, nil)
+	if err != nil {
+		t.Fatalf("GeocodeRanges failed: %v", err)
+	}
+	if report.Added != 2 || report.Unresolved != 1 {
+		t.Errorf("report = %+v, want 2 added and 1 unresolved", report)
+	}
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/range-localities", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated list status = %d, want 401", w.Code)
+	}
+	if w := send(http.MethodGet, "/api/v1/range-localities?status=bogus", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("bad status filter = %d, want 400", w.Code)
+	}
+
+	w = send(http.MethodGet, "/api/v1/range-localities?species=texana", nil)
+	var list ListResponse[db.RangeLocality]
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Data) != 2 {
+		t.Fatalf("list = %s", w.Body.String())
+	}
+	if !list.Data[0].MachineDerived || list.Data[0].Provider != "gazetteer" {
+		t.Errorf("locality = %+v, want machine-derived from the gazetteer", list.Data[0])
+	}
+	edwards, coahuila := list.Data[0].ID, list.Data[1].ID
+	lat, lon, badLat := 30.4, -99.8, 95.0
+
+	if w := send(http.MethodPost, fmt.Sprintf("/api/v1/range-localities/%d/accept", edwards), ReviewRangeLocalityRequest{Latitude: &badLat}); w.Code != http.StatusBadRequest {
+		t.Errorf("bad correction status = %d, want 400", w.Code)
+	}
+	w = send(http.MethodPost, fmt.Sprintf("/api/v1/range-localities/%d/accept", edwards), ReviewRangeLocalityRequest{Latitude: &lat, Longitude: &lon})
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"latitude":30.4`) || !strings.Contains(w.Body.String(), `"machine_derived":false`) {
+		t.Fatalf("accept status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodPost, fmt.Sprintf("/api/v1/range-localities/%d/reject", edwards), nil); w.Code != http.StatusConflict {
+		t.Errorf("reject after accept status = %d, want 409", w.Code)
+	}
+	if w := send(http.MethodPost, fmt.Sprintf("/api/v1/range-localities/%d/reject", coahuila), nil); w.Code != http.StatusOK {
+		t.Errorf("reject status = %d", w.Code)
+	}
+	if w := send(http.MethodPost, "/api/v1/range-localities/99/reject", nil); w.Code != http.StatusNotFound {
+		t.Errorf("missing locality status = %d, want 404", w.Code)
+	}
+
+	// The public list leaves out rejected localities
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/species/texana/localities", nil))
+	var localities []db.RangeLocality
+	if err := json.Unmarshal(w.Body.Bytes(), &localities); err != nil || len(localities) != 1 || localities[0].ID != edwards {
+		t.Errorf("species localities = %s", w.Body.String())
+	}
+}
+
+func TestSpeciesRangeGeoJSON(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	get := func(path string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if len(header) == 2 {
+			req.Header.Set(header[0], header[1])
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	if err := server.db.SaveOakEntry(models.NewOakEntry("texana")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	sourceID, err := server.db.InsertSource(&models.Source{SourceType: "book", Name: "Oaks of North America"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+	rangeText := "Edwards Plateau, Texas; Coahuila; Tamaulipas"
+	ss := models.NewSpeciesSource("texana", sourceID)
+	ss.Range = &rangeText
+	if err := server.db.SaveSpeciesSource(ss); err != nil {
+		t.Fatalf("SaveSpeciesSource failed: %v", err)
+	}
+	geocoder := geocode.NewGazetteer(map[string]geocode.Place{
+		"Edwards Plateau, Texas": {Latitude: 30.5, Longitude: -100},
+		"Coahuila":               {Latitude: 27.3, Longitude: -102},
+		"Tamaulipas":             {Latitude: 24.3, Longitude: -98.8},
+	})
+	if _, err := server.db.GeocodeRanges(t.Context(), geocoder, nil, nil); err != nil {
+		t.Fatalf("GeocodeRanges failed: %v", err)
+	}
+	if _, err := server.db.ReviewRangeLocality(1, true, nil); err != nil {
+		t.Fatalf("ReviewRangeLocality failed: %v", err)
+	}
+
+	w := get("/api/v1/species/texana/range.geojson")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/geo+json" {
+		t.Fatalf("status = %d, Content-Type %q. Body: %s", w.Code, w.Header().Get("Content-Type"), w.Body.String())
+	}
+	var fc rangemap.FeatureCollection
+	if err := json.Unmarshal(w.Body.Bytes(), &fc); err != nil || len(fc.Features) != 4 || fc.Features[0].Geometry.Type != "Polygon" {
+		t.Errorf("range map = %s", w.Body.String())
+	}
+	if w := get("/api/v1/species/texana/range.geojson", "If-None-Match", w.Header().Get("ETag")); w.Code != http.StatusNotModified {
+		t.Errorf("conditional status = %d, want 304", w.Code)
+	}
+
+	w = get("/api/v1/species/texana/range.geojson?detail=low")
+	if err := json.Unmarshal(w.Body.Bytes(), &fc); err != nil || len(fc.Features) != 1 {
+		t.Errorf("low detail = %s", w.Body.String())
+	}
+	// Only the accepted locality: a point and no outline
+	w = get("/api/v1/species/texana/range.geojson?reviewed=true")
+	if err := json.Unmarshal(w.Body.Bytes(), &fc); err != nil || len(fc.Features) != 1 || fc.Features[0].Geometry.Type != "Point" {
+		t.Errorf("reviewed only = %s", w.Body.String())
+	}
+
+	if w := get("/api/v1/species/texana/range.geojson?detail=huge"); w.Code != http.StatusBadRequest {
+		t.Errorf("bad detail status = %d, want 400", w.Code)
+	}
+	if w := get("/api/v1/species/nope/range.geojson"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown species status = %d, want 404", w.Code)
+	}
+}
+
+func TestLocalityGeneralization(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	server.generalize = rangemap.Generalization{MinStatus: "VU", Grid: 0.1}
+
+	get := func(path string, auth bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if auth {
+			req.Header.Set("Authorization", "Bearer test-api-key")
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	endangered, concern := "EN", "LC"
+	tomentella := models.NewOakEntry("tomentella")
+	tomentella.ConservationStatus = &endangered
+	alba := models.NewOakEntry("alba")
+	alba.ConservationStatus = &concern
+	sourceID, err := server.db.InsertSource(&models.Source{SourceType: "book", Name: "Flora"})
+	if err != nil {
+		t.Fatalf("InsertSource failed: %v", err)
+	}
+	for _, e := range []*models.OakEntry{tomentella, alba} {
+		if err := server.db.SaveOakEntry(e); err != nil {
+			t.Fatalf("SaveOakEntry failed: %v", err)
+		}
+		rangeText := "Guadalupe Island"
+		ss := models.NewSpeciesSource(e.ScientificName, sourceID)
+		ss.Range = &rangeText
+		if err := server.db.SaveSpeciesSource(ss); err != nil {
+			t.Fatalf("SaveSpeciesSource failed: %v", err)
+		}
+	}
+	geocoder := geocode.NewGazetteer(map[string]geocode.Place{"Guadalupe Island": {Latitude: 28.8931, Longitude: -118.2876}})
+	if _, err := server.db.GeocodeRanges(t.Context(), geocoder, nil, nil); err != nil {
+		t.Fatalf("GeocodeRanges failed: %v", err)
+	}
+
+	// The cached full export leaves out pending localities, and reviewing
+	// them must invalidate it
+	var file export.File
+	json.NewDecoder(get("/api/v1/export", false).Body).Decode(&file)
+	for _, sp := range file.Species {
+		if len(sp.Localities) != 0 {
+			t.Errorf("export localities of %s before review = %+v, want none", sp.Name, sp.Localities)
+		}
+	}
+	for _, id := range []int64{1, 2} {
+		if _, err := server.db.ReviewRangeLocality(id, true, nil); err != nil {
+			t.Fatalf("ReviewRangeLocality failed: %v", err)
+		}
+	}
+
+	var localities []db.RangeLocality
+	json.NewDecoder(get("/api/v1/species/tomentella/localities", false).Body).Decode(&localities)
+	if len(localities) != 1 || localities[0].Latitude != 28.85 || localities[0].Longitude != -118.25 || localities[0].Generalized != 0.1 {
+		t.Errorf("public localities = %+v, want generalized to 0.1°", localities)
+	}
+	localities = nil
+	json.NewDecoder(get("/api/v1/species/tomentella/localities", true).Body).Decode(&localities)
+	if len(localities) != 1 || localities[0].Latitude != 28.8931 || localities[0].Generalized != 0 {
+		t.Errorf("curator localities = %+v, want exact", localities)
+	}
+	localities = nil
+	json.NewDecoder(get("/api/v1/species/alba/localities", false).Body).Decode(&localities)
+	if len(localities) != 1 || localities[0].Latitude != 28.8931 {
+		t.Errorf("least-concern localities = %+v, want exact", localities)
+	}
+
+	var fc rangemap.FeatureCollection
+	json.NewDecoder(get("/api/v1/species/tomentella/range.geojson", false).Body).Decode(&fc)
+	if len(fc.Features) != 1 || fmt.Sprint(fc.Features[0].Geometry.Coordinates) != "[-118.25 28.85]" || fc.Features[0].Properties["generalized"] != 0.1 {
+		t.Errorf("public range map = %+v", fc.Features)
+	}
+
+	// Exports are public, with or without a key
+	file = export.File{}
+	json.NewDecoder(get("/api/v1/export", true).Body).Decode(&file)
+	if len(file.Species) != 2 {
+		t.Fatalf("export species = %d, want 2", len(file.Species))
+	}
+	for _, sp := range file.Species {
+		l := sp.Localities
+		if len(l) != 1 || (sp.Name == "tomentella") != (l[0].Generalized == 0.1) || (sp.Name == "tomentella") != (l[0].Latitude == 28.85) {
+			t.Errorf("export localities of %s = %+v", sp.Name, l)
+		}
+	}
+}
+
+func TestPopularSpecies(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	get := func(path string, auth bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if auth {
+			req.Header.Set("Authorization", "Bearer test-api-key")
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	for _, name := range []string{"alba", "rubra"} {
+		if err := server.db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatalf("SaveOakEntry(%s) failed: %v", name, err)
+		}
+	}
+	draft := models.NewOakEntry("velutina")
+	draft.Visibility = models.VisibilityDraft
+	if err := server.db.SaveOakEntry(draft); err != nil {
+		t.Fatalf("SaveOakEntry(velutina) failed: %v", err)
+	}
+
+	// Nothing is counted until analytics are enabled
+	get("/api/v1/species/alba/full", false)
+	server.analytics = true
+	get("/api/v1/species/alba/full", false)
+	get("/api/v1/species/alba/full", false)
+	get("/api/v1/species/rubra/full", false)
+	get("/api/v1/species/rubra/full", true)    // Curator reads are not counted
+	get("/api/v1/species/velutina/full", true) // Nor are drafts
+	get("/api/v1/species/alba", false)         // Only the full species page counts
+
+	if w := get("/api/v1/stats/popular", false); w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated status = %d, want 401", w.Code)
+	}
+	if w := get("/api/v1/stats/popular?days=0", true); w.Code != http.StatusBadRequest {
+		t.Errorf("days=0 status = %d, want 400", w.Code)
+	}
+
+	w := get("/api/v1/stats/popular?days=7", true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	var resp PopularSpeciesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !resp.AnalyticsEnabled || resp.Days != 7 {
+		t.Errorf("response = %+v, want analytics enabled over 7 days", resp)
+	}
+	if len(resp.Data) != 2 ||
+		resp.Data[0].ScientificName != "alba" || resp.Data[0].Views != 2 ||
+		resp.Data[1].ScientificName != "rubra" || resp.Data[1].Views != 1 {
+		t.Errorf("popular = %s, want alba 2, rubra 1", w.Body.String())
+	}
+
+	if w := get("/api/v1/stats/popular?limit=1", true); !strings.Contains(w.Body.String(), `"alba"`) || strings.Contains(w.Body.String(), `"rubra"`) {
+		t.Errorf("limit=1 body = %s, want only alba", w.Body.String())
+	}
+}
+
+func TestSitemap(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	body, _ := json.Marshal(models.OakEntry{ScientificName: "× bebbiana"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/species", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
 	server.Router().ServeHTTP(w, req)
 	if w.Code != http.StatusCreated {
-		t.Fatalf("create source status = %d, want %d", w.Code, http.StatusCreated)
+		t.Fatalf("create species status = %d: %s", w.Code, w.Body.String())
 	}
 
-	// Create a species-source record
-	leaves := "Large lobed leaves"
-	ss := models.SpeciesSource{
-		ScientificName: "alba",
-		SourceID:       1,
-		Leaves:         &leaves,
-		LocalNames:     []string{"white oak"},
-		IsPreferred:    true,
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sitemap.xml?base_url=https://staging.example.org/", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("sitemap status = %d: %s", w.Code, w.Body.String())
 	}
-	body, _ = json.Marshal(ss)
-	req = httptest.NewRequest(http.MethodPost, "/api/v1/species/alba/sources", bytes.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer test-api-key")
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/xml") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	out := w.Body.String()
+	for _, want := range []string{
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`,
+		"<loc>https://staging.example.org/</loc>",
+		"<loc>https://staging.example.org/species/x-bebbiana/</loc>",
+		"<lastmod>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("sitemap missing %q:\n%s", want, out)
+		}
+	}
+
 	w = httptest.NewRecorder()
-	server.Router().ServeHTTP(w, req)
+	server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sitemap.xml?base_url=staging", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("relative base_url status = %d, want 400", w.Code)
+	}
+}
 
-	if w.Code != http.StatusCreated {
-		t.Fatalf("create species-source status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+func TestExportMapping(t *testing.T) {
+	database, err := db.New(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
 	}
+	defer database.Close()
 
-	// List species sources
-	req = httptest.NewRequest(http.MethodGet, "/api/v1/species/alba/sources", nil)
-	w = httptest.NewRecorder()
-	server.Router().ServeHTTP(w, req)
+	mapping, err := export.ParseMapping([]byte("species:\n  include: [name]\n  rename: {name: scientific_name}\n"))
+	if err != nil {
+		t.Fatalf("ParseMapping() error = %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	server := New(database, "test-api-key", logger, VersionInfo{API: "1.0.0", MinClient: "1.0.0"},
+		WithoutMiddleware(), WithExportMappings(map[string]*export.Mapping{"mobile": mapping}))
 
-	if w.Code != http.StatusOK {
-		t.Fatalf("list status = %d, want %d", w.Code, http.StatusOK)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
 	}
 
-	// Get specific species source
-	req = httptest.NewRequest(http.MethodGet, "/api/v1/species/alba/sources/1", nil)
-	w = httptest.NewRecorder()
+	body, _ := json.Marshal(models.OakEntry{ScientificName: "alba"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/species", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
 	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create species status = %d", w.Code)
+	}
 
+	w = get("/api/v1/export?mapping=mobile")
 	if w.Code != http.StatusOK {
-		t.Fatalf("get status = %d, want %d", w.Code, http.StatusOK)
+		t.Fatalf("export status = %d: %s", w.Code, w.Body.String())
+	}
+	var out struct {
+		Species []map[string]interface{} `json:"species"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("failed to decode export: %v", err)
+	}
+	if len(out.Species) != 1 || len(out.Species[0]) != 1 || out.Species[0]["scientific_name"] != "alba" {
+		t.Errorf("species = %v", out.Species)
 	}
 
-	// Delete species source
-	req = httptest.NewRequest(http.MethodDelete, "/api/v1/species/alba/sources/1", nil)
-	req.Header.Set("Authorization", "Bearer test-api-key")
-	w = httptest.NewRecorder()
-	server.Router().ServeHTTP(w, req)
+	if w := get("/api/v1/export?mapping=print"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown mapping status = %d, want 404", w.Code)
+	}
 
-	if w.Code != http.StatusNoContent {
-		t.Fatalf("delete status = %d, want %d", w.Code, http.StatusNoContent)
+	w = get("/api/v1/export/mappings")
+	var list ListResponse[string]
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Data) != 1 || list.Data[0] != "mobile" {
+		t.Errorf("mappings = %s", w.Body.String())
 	}
 }
 
@@ -421,6 +3810,364 @@ func TestExport(t *testing.T) {
 	}
 }
 
+func TestExportCache(t *testing.T) {
+	server, cleanup := testServerWithMiddleware(t)
+	defer cleanup()
+
+	send := func(method, path, body string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	fetchExport := func() (*httptest.ResponseRecorder, string) {
+		t.Helper()
+		w := send(http.MethodGet, "/api/v1/export", "", "Accept-Encoding", "gzip")
+		if w.Code != http.StatusOK || w.Header().Get("Content-Encoding") != "gzip" {
+			t.Fatalf("export = %d, encoding %q", w.Code, w.Header().Get("Content-Encoding"))
+		}
+		zr, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatalf("export is not gzip: %v", err)
+		}
+		data, err := io.ReadAll(zr)
+		if err != nil || !json.Valid(data) {
+			t.Fatalf("export body is not gzip-compressed JSON (err %v)", err)
+		}
+		return w, string(data)
+	}
+
+	if w := send(http.MethodPost, "/api/v1/species", `{"scientific_name":"alba"}`); w.Code != http.StatusCreated {
+		t.Fatalf("create species = %d: %s", w.Code, w.Body.String())
+	}
+	first, body := fetchExport()
+	if !strings.Contains(body, `"alba"`) || first.Header().Get("X-Export-Revision") == "" || first.Header().Get("Age") == "" {
+		t.Fatalf("first export headers %v, body %s", first.Header(), body)
+	}
+
+	// Served from the cache until something is written
+	again, _ := fetchExport()
+	if again.Header().Get("ETag") != first.Header().Get("ETag") {
+		t.Errorf("second export ETag = %s, want the cached %s", again.Header().Get("ETag"), first.Header().Get("ETag"))
+	}
+	if w := send(http.MethodGet, "/api/v1/export", "", "If-None-Match", first.Header().Get("ETag")); w.Code != http.StatusNotModified {
+		t.Errorf("conditional export = %d, want 304", w.Code)
+	}
+
+	if w := send(http.MethodPost, "/api/v1/species", `{"scientific_name":"rubra"}`); w.Code != http.StatusCreated {
+		t.Fatalf("create species = %d: %s", w.Code, w.Body.String())
+	}
+	after, body := fetchExport()
+	if !strings.Contains(body, `"rubra"`) || after.Header().Get("ETag") == first.Header().Get("ETag") ||
+		after.Header().Get("X-Export-Revision") == first.Header().Get("X-Export-Revision") {
+		t.Errorf("export after a write has ETag %s, revision %s; want a rebuild with rubra",
+			after.Header().Get("ETag"), after.Header().Get("X-Export-Revision"))
+	}
+
+	// Clients without gzip get plain JSON
+	w := send(http.MethodGet, "/api/v1/export", "")
+	if w.Header().Get("Content-Encoding") != "" || !strings.Contains(w.Body.String(), `"rubra"`) {
+		t.Errorf("uncompressed export encoding %q, body %.60s", w.Header().Get("Content-Encoding"), w.Body.String())
+	}
+}
+
+func TestHeadAndOptions(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/species", bytes.NewReader([]byte(`{"scientific_name":"alba"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create species = %d: %s", w.Code, w.Body.String())
+	}
+
+	// HEAD mirrors GET without the body
+	for _, path := range []string{"/api/v1/species", "/api/v1/species/alba", "/api/v1/export", "/health"} {
+		get := send(http.MethodGet, path)
+		head := send(http.MethodHead, path)
+		if head.Code != get.Code {
+			t.Errorf("HEAD %s = %d, GET = %d", path, head.Code, get.Code)
+		}
+		if head.Body.Len() != 0 {
+			t.Errorf("HEAD %s wrote a %d-byte body", path, head.Body.Len())
+		}
+		if got, want := head.Header().Get("Content-Length"), strconv.Itoa(get.Body.Len()); got != want {
+			t.Errorf("HEAD %s Content-Length = %q, want %q", path, got, want)
+		}
+	}
+	if w := send(http.MethodHead, "/api/v1/species/quercus-nonexistent"); w.Code != http.StatusNotFound {
+		t.Errorf("HEAD missing species = %d, want 404", w.Code)
+	}
+
+	// OPTIONS and 405s list the allowed methods
+	w = send(http.MethodOptions, "/api/v1/species/alba")
+	if w.Code != http.StatusNoContent {
+		t.Errorf("OPTIONS = %d, want 204", w.Code)
+	}
+	if got, want := w.Header().Get("Allow"), "GET, HEAD, PUT, DELETE, OPTIONS"; got != want {
+		t.Errorf("OPTIONS Allow = %q, want %q", got, want)
+	}
+
+	w = send(http.MethodPatch, "/api/v1/export")
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("PATCH export = %d, want 405", w.Code)
+	}
+	if got, want := w.Header().Get("Allow"), "GET, HEAD, OPTIONS"; got != want {
+		t.Errorf("405 Allow = %q, want %q", got, want)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Error.Code != apierror.CodeMethodNotAllowed {
+		t.Errorf("405 body = %s, want %s error", w.Body.String(), apierror.CodeMethodNotAllowed)
+	}
+}
+
+func TestConditionalGet(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	for path, body := range map[string]string{
+		"/api/v1/species": `{"scientific_name":"alba"}`,
+		"/api/v1/sources": `{"source_type":"book","name":"Flora"}`,
+		"/api/v1/taxa":    `{"name":"Quercus","level":"section"}`,
+	} {
+		if w := send(http.MethodPost, path, body, nil); w.Code != http.StatusCreated {
+			t.Fatalf("POST %s = %d: %s", path, w.Code, w.Body.String())
+		}
+	}
+
+	for _, path := range []string{"/api/v1/species/alba", "/api/v1/sources/1", "/api/v1/taxa/section/Quercus"} {
+		w := send(http.MethodGet, path, "", nil)
+		lastModified := w.Header().Get("Last-Modified")
+		if w.Code != http.StatusOK || lastModified == "" {
+			t.Fatalf("GET %s = %d with Last-Modified %q", path, w.Code, lastModified)
+		}
+
+		w = send(http.MethodGet, path, "", http.Header{"If-Modified-Since": {lastModified}})
+		if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+			t.Errorf("GET %s since Last-Modified = %d with %d-byte body, want 304", path, w.Code, w.Body.Len())
+		}
+
+		earlier := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+		if w = send(http.MethodGet, path, "", http.Header{"If-Modified-Since": {earlier}}); w.Code != http.StatusOK {
+			t.Errorf("GET %s since an hour ago = %d, want 200", path, w.Code)
+		}
+	}
+
+	if w := send(http.MethodGet, "/api/v1/species/rubra", "", http.Header{"If-Modified-Since": {time.Now().UTC().Format(http.TimeFormat)}}); w.Code != http.StatusNotFound {
+		t.Errorf("GET missing species with If-Modified-Since = %d, want 404", w.Code)
+	}
+}
+
+func TestConditionalPut(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	// In order: the species source needs the species and the source
+	for _, post := range [][2]string{
+		{"/api/v1/species", `{"scientific_name":"alba"}`},
+		{"/api/v1/sources", `{"source_type":"book","name":"Flora"}`},
+		{"/api/v1/taxa", `{"name":"Quercus","level":"section"}`},
+		{"/api/v1/species/alba/sources", `{"source_id":1,"leaves":"Lobed"}`},
+	} {
+		if w := send(http.MethodPost, post[0], post[1], nil); w.Code != http.StatusCreated {
+			t.Fatalf("POST %s = %d: %s", post[0], w.Code, w.Body.String())
+		}
+	}
+
+	for path, body := range map[string]string{
+		"/api/v1/species/alba":           `{"author":"L."}`,
+		"/api/v1/sources/1":              `{"source_type":"book","name":"Flora of Oaks"}`,
+		"/api/v1/taxa/section/Quercus":   `{"author":"Oerst."}`,
+		"/api/v1/species/alba/sources/1": `{"leaves":"Deeply lobed"}`,
+	} {
+		w := send(http.MethodGet, path, "", nil)
+		etag := w.Header().Get("ETag")
+		if w.Code != http.StatusOK || etag == "" {
+			t.Fatalf("GET %s = %d with ETag %q", path, w.Code, etag)
+		}
+
+		// Someone else saves in between: the read version no longer matches
+		stale := `"` + strings.Repeat("0", 64) + `"`
+		w = send(http.MethodPut, path, body, http.Header{"If-Match": {stale}})
+		if w.Code != http.StatusPreconditionFailed {
+			t.Fatalf("PUT %s with a stale If-Match = %d, want 412: %s", path, w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("ETag"); got != etag {
+			t.Errorf("412 ETag = %q, want the current %q", got, etag)
+		}
+		var resp ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Error.Code != apierror.CodePreconditionFailed {
+			t.Errorf("412 body = %s, want %s error", w.Body.String(), apierror.CodePreconditionFailed)
+		}
+
+		if w = send(http.MethodPut, path, body, http.Header{"If-Match": {etag}}); w.Code != http.StatusOK {
+			t.Fatalf("PUT %s with the current If-Match = %d: %s", path, w.Code, w.Body.String())
+		}
+		if w = send(http.MethodPut, path, body, http.Header{"If-Match": {etag}}); w.Code != http.StatusPreconditionFailed {
+			t.Errorf("PUT %s with the replaced If-Match = %d, want 412", path, w.Code)
+		}
+		if w = send(http.MethodPut, path, body, nil); w.Code != http.StatusOK {
+			t.Errorf("PUT %s without If-Match = %d, want 200", path, w.Code)
+		}
+	}
+}
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		ifMatch, hash string
+		want          bool
+	}{
+		{`"abc"`, "abc", true},
+		{`W/"abc"`, "abc", true},
+		{`"xyz", "abc"`, "abc", true},
+		{`*`, "abc", true},
+		{`"xyz"`, "abc", false},
+		{`*`, "", false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.ifMatch, tt.hash); got != tt.want {
+			t.Errorf("etagMatches(%q, %q) = %v, want %v", tt.ifMatch, tt.hash, got, tt.want)
+		}
+	}
+}
+
+func TestEntityHashes(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path, body string, auth bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		if auth {
+			req.Header.Set("Authorization", "Bearer test-api-key")
+		}
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	hashes := func(auth bool) map[string]map[string]string {
+		w := send(http.MethodGet, "/api/v1/hashes", "", auth)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET /hashes = %d: %s", w.Code, w.Body.String())
+		}
+		var result map[string]map[string]string
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	hashOf := func(path string) string {
+		w := send(http.MethodGet, path+"?fields=hash", "", true)
+		var h EntityHash
+		if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &h) != nil || h.Hash == "" {
+			t.Fatalf("GET %s?fields=hash = %d: %s", path, w.Code, w.Body.String())
+		}
+		return h.Hash
+	}
+
+	for _, req := range []struct{ path, body string }{
+		{"/api/v1/species", `{"scientific_name":"alba"}`},
+		{"/api/v1/species", `{"scientific_name":"unfinished","visibility":"draft"}`},
+		{"/api/v1/sources", `{"source_type":"book","name":"Flora"}`},
+		{"/api/v1/taxa", `{"name":"Quercus","level":"section"}`},
+		{"/api/v1/species/alba/sources", `{"source_id":1,"leaves":"lobed"}`},
+	} {
+		if w := send(http.MethodPost, req.path, req.body, true); w.Code != http.StatusCreated {
+			t.Fatalf("POST %s = %d: %s", req.path, w.Code, w.Body.String())
+		}
+	}
+
+	all := hashes(true)
+	for kind, key := range map[string]string{
+		"species": "alba", "species_sources": "alba/1", "sources": "1", "taxa": "section/Quercus", "genera": "Quercus",
+	} {
+		if all[kind][key] == "" {
+			t.Errorf("hashes[%s][%s] missing in %v", kind, key, all[kind])
+		}
+	}
+	if all["species"]["unfinished"] == "" {
+		t.Error("authenticated hashes omit the draft species")
+	}
+	if _, ok := hashes(false)["species"]["unfinished"]; ok {
+		t.Error("public hashes include the draft species")
+	}
+
+	for path, want := range map[string]string{
+		"/api/v1/species/alba":           all["species"]["alba"],
+		"/api/v1/species/alba/sources/1": all["species_sources"]["alba/1"],
+		"/api/v1/sources/1":              all["sources"]["1"],
+		"/api/v1/taxa/section/Quercus":   all["taxa"]["section/Quercus"],
+		"/api/v1/genera/Quercus":         all["genera"]["Quercus"],
+	} {
+		if got := hashOf(path); got != want {
+			t.Errorf("%s hash = %s, want %s from /hashes", path, got, want)
+		}
+	}
+
+	// Writes change the hash of what they touch, and nothing else
+	if w := send(http.MethodPut, "/api/v1/species/alba/sources/1", `{"leaves":"deeply lobed"}`, true); w.Code != http.StatusOK {
+		t.Fatalf("PUT species source = %d: %s", w.Code, w.Body.String())
+	}
+	after := hashes(true)
+	if after["species_sources"]["alba/1"] == all["species_sources"]["alba/1"] {
+		t.Error("species source hash unchanged after update")
+	}
+	if after["species"]["alba"] != all["species"]["alba"] || after["sources"]["1"] != all["sources"]["1"] {
+		t.Error("updating source data changed the species or source hash")
+	}
+
+	if w := send(http.MethodGet, "/api/v1/hashes?kind=species,bogus", "", false); w.Code != http.StatusBadRequest {
+		t.Errorf("GET /hashes?kind=bogus = %d, want 400", w.Code)
+	}
+	if w := send(http.MethodGet, "/api/v1/species/alba?fields=name", "", false); w.Code != http.StatusBadRequest {
+		t.Errorf("GET ?fields=name = %d, want 400", w.Code)
+	}
+	if w := send(http.MethodGet, "/api/v1/hashes?kind=sources", "", false); !strings.Contains(w.Body.String(), `"sources"`) ||
+		strings.Contains(w.Body.String(), `"species"`) {
+		t.Errorf("GET /hashes?kind=sources = %s", w.Body.String())
+	}
+}
+
 func TestAuthRequired(t *testing.T) {
 	server, cleanup := testServer(t)
 	defer cleanup()
@@ -672,8 +4419,8 @@ func TestDeleteCascadeProtection(t *testing.T) {
 		t.Fatalf("failed to decode error response: %v", err)
 	}
 
-	if errResp.Error.Code != ErrCodeConflict {
-		t.Errorf("error code = %s, want %s", errResp.Error.Code, ErrCodeConflict)
+	if errResp.Error.Code != apierror.CodeConflict {
+		t.Errorf("error code = %s, want %s", errResp.Error.Code, apierror.CodeConflict)
 	}
 
 	// Check that details contains blocking hybrids
@@ -721,7 +4468,7 @@ func TestGzipCompression(t *testing.T) {
 	author := "L."
 	for i := 0; i < 50; i++ {
 		species := models.OakEntry{
-			ScientificName: "species" + strings.Repeat("x", 20) + string(rune('A'+i%26)) + string(rune('a'+i/26)),
+			ScientificName: "species" + strings.Repeat("x", 20) + string(rune('a'+i%26)) + string(rune('a'+i/26)),
 			Author:         &author,
 			IsHybrid:       false,
 		}
@@ -802,7 +4549,7 @@ func TestGzipCompressionNotRequestedNotCompressed(t *testing.T) {
 	author := "L."
 	for i := 0; i < 50; i++ {
 		species := models.OakEntry{
-			ScientificName: "species" + strings.Repeat("y", 20) + string(rune('A'+i%26)) + string(rune('a'+i/26)),
+			ScientificName: "species" + strings.Repeat("y", 20) + string(rune('a'+i%26)) + string(rune('a'+i/26)),
 			Author:         &author,
 			IsHybrid:       false,
 		}
@@ -838,3 +4585,122 @@ func TestGzipCompressionNotRequestedNotCompressed(t *testing.T) {
 		t.Fatalf("failed to decode response: %v", err)
 	}
 }
+
+func TestSpeciesBySlug(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	for _, name := range []string{"× bebbiana", "alba", "macrocarpa"} {
+		if err := server.db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatalf("SaveOakEntry failed: %v", err)
+		}
+	}
+
+	send := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+	redirect := func(slug, want string) {
+		t.Helper()
+		w := send(http.MethodGet, "/api/v1/species/by-slug/"+slug)
+		if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != "/api/v1/species/by-slug/"+want {
+			t.Errorf("by-slug %s = %d to %q, want 301 to %s", slug, w.Code, w.Header().Get("Location"), want)
+		}
+	}
+
+	w := send(http.MethodGet, "/api/v1/species/by-slug/x-bebbiana")
+	var entry models.OakEntry
+	if err := json.NewDecoder(w.Body).Decode(&entry); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("by-slug x-bebbiana = %d, %v", w.Code, err)
+	}
+	if entry.ScientificName != "× bebbiana" || entry.Slug != "x-bebbiana" {
+		t.Errorf("by-slug x-bebbiana = %s (slug %s)", entry.ScientificName, entry.Slug)
+	}
+	redirect("%C3%97%20bebbiana", "x-bebbiana")
+	redirect("Alba", "alba")
+
+	// Deleting a renamed or merged species redirects its slug
+	if w := send(http.MethodDelete, "/api/v1/species/alba?redirect_to=missing"); w.Code != http.StatusBadRequest {
+		t.Errorf("delete redirecting to a missing species = %d, want 400", w.Code)
+	}
+	if w := send(http.MethodDelete, "/api/v1/species/alba?redirect_to=macrocarpa"); w.Code != http.StatusNoContent {
+		t.Fatalf("delete alba = %d: %s", w.Code, w.Body.String())
+	}
+	redirect("alba", "macrocarpa")
+	if w := send(http.MethodDelete, "/api/v1/species/macrocarpa?redirect_to=%C3%97%20bebbiana"); w.Code != http.StatusNoContent {
+		t.Fatalf("delete macrocarpa = %d: %s", w.Code, w.Body.String())
+	}
+	redirect("alba", "x-bebbiana")
+	redirect("macrocarpa", "x-bebbiana")
+
+	// A new species takes its slug back from a redirect
+	if err := server.db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	if w := send(http.MethodGet, "/api/v1/species/by-slug/alba"); w.Code != http.StatusOK {
+		t.Errorf("by-slug alba after recreating = %d, want 200", w.Code)
+	}
+	if w := send(http.MethodGet, "/api/v1/species/by-slug/rubra"); w.Code != http.StatusNotFound {
+		t.Errorf("by-slug rubra = %d, want 404", w.Code)
+	}
+}
+
+func TestSpeciesRevisions(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, v interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	author, wrong := "L.", "Michx."
+	send(http.MethodPost, "/api/v1/species", SpeciesRequest{ScientificName: "alba", Author: &author})
+	if w := send(http.MethodPut, "/api/v1/species/alba", SpeciesRequest{ScientificName: "alba", Author: &wrong}); w.Code != http.StatusOK {
+		t.Fatalf("update status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/species/alba/revisions", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated list status = %d, want 401", w.Code)
+	}
+
+	w = send(http.MethodGet, "/api/v1/species/alba/revisions", nil)
+	var list RevisionsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Revisions) != 1 || *list.Revisions[0].Entry.Author != "L." {
+		t.Fatalf("list status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	id := list.Revisions[0].ID
+
+	w = send(http.MethodPost, fmt.Sprintf("/api/v1/species/alba/revisions/%d/restore", id), nil)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"author":"L."`) {
+		t.Fatalf("restore status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodPost, "/api/v1/species/alba/revisions/99/restore", nil); w.Code != http.StatusNotFound {
+		t.Errorf("missing revision status = %d, want 404", w.Code)
+	}
+	if w := send(http.MethodPost, "/api/v1/species/alba/revisions/latest/restore", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid revision ID status = %d, want 400", w.Code)
+	}
+	if w := send(http.MethodGet, "/api/v1/species/nope/revisions", nil); w.Code != http.StatusNotFound {
+		t.Errorf("unknown species status = %d, want 404", w.Code)
+	}
+
+	// The edit and the restore are both on the timeline
+	w = send(http.MethodGet, "/api/v1/species/alba/timeline", nil)
+	var timeline TimelineResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &timeline); err != nil || len(timeline.Events) != 2 || timeline.Events[0].Kind != db.EventEdited {
+		t.Errorf("timeline status = %d. Body: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodGet, "/api/v1/species/nope/timeline", nil); w.Code != http.StatusNotFound {
+		t.Errorf("unknown species timeline status = %d, want 404", w.Code)
+	}
+}
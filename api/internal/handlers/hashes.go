@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jeff/oaks/api/internal/db"
+)
+
+// EntityHash is the content hash of one entity, returned for ?fields=hash
+type EntityHash struct {
+	Kind string `json:"kind"`
+	Key  string `json:"key"`
+	Hash string `json:"hash"`
+}
+
+// handleListHashes handles GET /api/v1/hashes
+// Returns the content hash of every entity by kind and key, so sync tools can
+// find what changed without fetching records. ?kind= limits the kinds
+// (comma-separated). Draft species are included only for authenticated requests.
+func (s *Server) handleListHashes(w http.ResponseWriter, r *http.Request) {
+	kinds := db.HashKinds
+	if value := r.URL.Query().Get("kind"); value != "" {
+		kinds = nil
+		for _, part := range strings.Split(value, ",") {
+			kind := strings.TrimSpace(part)
+			if !validHashKind(kind) {
+				RespondValidationError(w, []ValidationError{{
+					Field:   "kind",
+					Message: "must be comma-separated kinds: " + strings.Join(db.HashKinds, ", "),
+				}})
+				return
+			}
+			kinds = append(kinds, kind)
+		}
+	}
+
+	includeDrafts := s.isAuthenticated(r)
+	result := make(map[string]map[string]string, len(kinds))
+	for _, kind := range kinds {
+		hashes, err := s.db.ListEntityHashes(kind, includeDrafts)
+		if err != nil {
+			s.logger.Error("failed to list entity hashes", "kind", kind, "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+		result[kind] = hashes
+	}
+
+	RespondJSON(w, http.StatusOK, result)
+}
+
+func validHashKind(kind string) bool {
+	for _, k := range db.HashKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// respondHashOnly answers ?fields=hash on a detail route with the entity's
+// content hash in place of the record. Returns false if the request did not
+// ask for it; any other fields value is rejected.
+func (s *Server) respondHashOnly(w http.ResponseWriter, r *http.Request, kind, key string) bool {
+	fields := r.URL.Query().Get("fields")
+	if fields == "" {
+		return false
+	}
+	if fields != "hash" {
+		RespondValidationError(w, []ValidationError{{Field: "fields", Message: "must be hash"}})
+		return true
+	}
+
+	hash, err := s.db.GetEntityHash(kind, key)
+	if err != nil {
+		s.logger.Error("failed to get entity hash", "kind", kind, "key", key, "error", err)
+		RespondInternalError(w, "")
+		return true
+	}
+	RespondJSON(w, http.StatusOK, EntityHash{Kind: kind, Key: key, Hash: hash})
+	return true
+}
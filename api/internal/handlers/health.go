@@ -9,6 +9,10 @@ import (
 type HealthResponse struct {
 	Status  string      `json:"status"`
 	Version VersionInfo `json:"version"`
+	Addr    string      `json:"addr,omitempty"` // Listening address, e.g. when started with port 0
+
+	// Maintenance is set while writes are disabled, so clients can show its message.
+	Maintenance *MaintenanceStatus `json:"maintenance,omitempty"`
 }
 
 // ReadyResponse represents the response for readiness check.
@@ -22,17 +26,24 @@ type ReadyResponse struct {
 type AuthVerifyResponse struct {
 	Status  string `json:"status"`
 	Profile string `json:"profile,omitempty"`
+	Role    string `json:"role,omitempty"` // The key's role: read, editor, or admin
 }
 
 // handleHealth handles liveness check - immediate 200 if server is running.
 // GET /health or GET /api/v1/health
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(HealthResponse{
+	resp := HealthResponse{
 		Status:  "ok",
 		Version: s.version,
-	})
+		Addr:    s.addr,
+	}
+	if status := s.maintenanceStatus(); status.Enabled {
+		resp.Maintenance = &status
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
 }
 
 // handleHealthReady handles readiness check - verifies DB connection.
@@ -73,9 +84,11 @@ func (s *Server) handleHealthReady(w http.ResponseWriter, r *http.Request) {
 // GET /api/v1/auth/verify (requires authentication)
 func (s *Server) handleAuthVerify(w http.ResponseWriter, r *http.Request) {
 	// If we get here, the ForceAuth middleware already validated the key
+	role, _, _ := s.keyRole(extractBearerToken(r))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(AuthVerifyResponse{
 		Status: "authenticated",
+		Role:   role,
 	})
 }
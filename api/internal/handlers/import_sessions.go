@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// ImportSessionHeader attaches a bulk species-source upload to an open import
+// session. The upload is validated and staged, and applied only when the
+// session is committed.
+const ImportSessionHeader = "X-Import-Session"
+
+// StagedImportResponse is the response for a bulk upload staged in an import session.
+type StagedImportResponse struct {
+	SessionID int64 `json:"session_id"`
+	Batch     int   `json:"batch"`
+	Staged    int   `json:"staged"`
+}
+
+// ImportSessionCommitResponse reports the outcome of committing an import session.
+type ImportSessionCommitResponse struct {
+	Session   *db.ImportSession      `json:"session"`
+	Created   int                    `json:"created"`
+	Updated   int                    `json:"updated"`
+	Unchanged int                    `json:"unchanged"`
+	Batches   []db.ImportBatchResult `json:"batches"`
+}
+
+// loadOpenImportSession looks up a session by ID and checks it is still open.
+// Writes an error response and returns nil if it is missing or closed.
+func (s *Server) loadOpenImportSession(w http.ResponseWriter, idParam string) *db.ImportSession {
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid import session ID")
+		return nil
+	}
+
+	session, err := s.db.GetImportSession(id)
+	if err != nil {
+		s.logger.Error("failed to get import session", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return nil
+	}
+	if session == nil {
+		RespondNotFound(w, "Import session", idParam)
+		return nil
+	}
+	if session.Status != db.ImportSessionOpen {
+		RespondConflict(w, fmt.Sprintf("import session %d is already %s", id, session.Status))
+		return nil
+	}
+	return session
+}
+
+// handleCreateImportSession handles POST /api/v1/import/sessions
+func (s *Server) handleCreateImportSession(w http.ResponseWriter, r *http.Request) {
+	session, err := s.db.CreateImportSession()
+	if err != nil {
+		s.logger.Error("failed to create import session", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	RespondJSON(w, http.StatusCreated, session)
+}
+
+// handleGetImportSession handles GET /api/v1/import/sessions/{id}
+func (s *Server) handleGetImportSession(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid import session ID")
+		return
+	}
+
+	session, err := s.db.GetImportSession(id)
+	if err != nil {
+		s.logger.Error("failed to get import session", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if session == nil {
+		RespondNotFound(w, "Import session", idParam)
+		return
+	}
+	RespondJSON(w, http.StatusOK, session)
+}
+
+// stageSpeciesSources stages a validated bulk upload in the session named by
+// the X-Import-Session header instead of applying it.
+func (s *Server) stageSpeciesSources(w http.ResponseWriter, r *http.Request, sourceID int64, rows []db.SpeciesSourceUpsert) {
+	session := s.loadOpenImportSession(w, r.Header.Get(ImportSessionHeader))
+	if session == nil {
+		return
+	}
+
+	batch, err := s.db.StageSpeciesSources(session.ID, sourceID, rows)
+	if err != nil {
+		s.logger.Error("failed to stage species sources", "session", session.ID, "sourceId", sourceID, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if batch == 0 {
+		RespondConflict(w, fmt.Sprintf("import session %d was closed concurrently", session.ID))
+		return
+	}
+
+	RespondJSON(w, http.StatusAccepted, StagedImportResponse{SessionID: session.ID, Batch: batch, Staged: len(rows)})
+}
+
+// handleCommitImportSession handles POST /api/v1/import/sessions/{id}/commit
+// Applies every staged upload in one transaction. If any row fails nothing is
+// written, the failures are reported, and the session stays open to be discarded.
+func (s *Server) handleCommitImportSession(w http.ResponseWriter, r *http.Request) {
+	session := s.loadOpenImportSession(w, chi.URLParam(r, "id"))
+	if session == nil {
+		return
+	}
+
+	batches, applied, err := s.db.CommitImportSession(session.ID)
+	if err != nil {
+		s.logger.Error("failed to commit import session", "id", session.ID, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if batches == nil {
+		RespondConflict(w, fmt.Sprintf("import session %d was closed concurrently", session.ID))
+		return
+	}
+
+	if !applied {
+		var errors []ValidationError
+		for i, batch := range batches {
+			for j, res := range batch.Results {
+				if res.Status == db.UpsertFailed {
+					errors = append(errors, ValidationError{Field: fmt.Sprintf("batches[%d].items[%d]", i, j), Message: res.Error})
+				}
+			}
+		}
+		RespondValidationError(w, errors)
+		return
+	}
+
+	resp := ImportSessionCommitResponse{Batches: batches}
+	var changed []string
+	for _, batch := range batches {
+		for _, res := range batch.Results {
+			switch res.Status {
+			case db.UpsertCreated:
+				resp.Created++
+				changed = append(changed, res.ScientificName)
+			case db.UpsertUpdated:
+				resp.Updated++
+				changed = append(changed, res.ScientificName)
+			case db.UpsertUnchanged:
+				resp.Unchanged++
+			}
+		}
+	}
+	s.refreshMentions(changed...)
+	s.refreshMeasurements(changed...)
+
+	if resp.Session, err = s.db.GetImportSession(session.ID); err != nil {
+		s.logger.Error("failed to get import session", "id", session.ID, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	s.logger.Info("import session committed", "id", session.ID, "batches", len(batches),
+		"created", resp.Created, "updated", resp.Updated, "unchanged", resp.Unchanged)
+
+	RespondJSON(w, http.StatusOK, resp)
+}
+
+// handleDiscardImportSession handles POST /api/v1/import/sessions/{id}/discard
+func (s *Server) handleDiscardImportSession(w http.ResponseWriter, r *http.Request) {
+	session := s.loadOpenImportSession(w, chi.URLParam(r, "id"))
+	if session == nil {
+		return
+	}
+
+	discarded, err := s.db.DiscardImportSession(session.ID)
+	if err != nil {
+		s.logger.Error("failed to discard import session", "id", session.ID, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !discarded {
+		RespondConflict(w, fmt.Sprintf("import session %d was closed concurrently", session.ID))
+		return
+	}
+
+	if session, err = s.db.GetImportSession(session.ID); err != nil {
+		s.logger.Error("failed to get import session", "id", session.ID, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	RespondJSON(w, http.StatusOK, session)
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/inaturalist"
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// maxINaturalistPhotos is the most photos ?photos= caches for a species
+const maxINaturalistPhotos = 20
+
+// INaturalistResponse is a species' iNaturalist taxon and cached photos
+type INaturalistResponse struct {
+	TaxonID     *int64      `json:"taxon_id"` // null when not linked or not matched
+	URL         *string     `json:"url,omitempty"`
+	Name        string      `json:"name,omitempty"`        // iNaturalist's name; only from a lookup
+	CommonName  string      `json:"common_name,omitempty"` // iNaturalist's preferred common name; only from a lookup
+	LinkChanged bool        `json:"link_changed"`          // The lookup added or changed the species' link
+	Photos      []*db.Media `json:"photos"`
+}
+
+// WithINaturalist looks species up with c instead of the public
+// iNaturalist API.
+func WithINaturalist(c *inaturalist.Client) ServerOption {
+	return func(s *Server) {
+		s.inaturalist = c
+	}
+}
+
+// inaturalistLink returns the index of an entry's iNaturalist link, or -1
+func inaturalistLink(links []models.ExternalLink) int {
+	return slices.IndexFunc(links, func(l models.ExternalLink) bool {
+		return l.Logo == "inaturalist" || strings.EqualFold(l.Name, "iNaturalist")
+	})
+}
+
+// inaturalistTaxonID reads the taxon ID from an iNaturalist link such as
+// https://www.inaturalist.org/taxa/54779-Quercus-alba
+func inaturalistTaxonID(link string) *int64 {
+	base := path.Base(strings.TrimRight(link, "/"))
+	digits, _, _ := strings.Cut(base, "-")
+	id, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// handleGetSpeciesINaturalist handles GET /api/v1/species/{name}/inaturalist
+// Returns the taxon the species is linked to and its cached photos, without
+// contacting iNaturalist.
+func (s *Server) handleGetSpeciesINaturalist(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+	entry, err := s.db.GetOakEntry(name)
+	if err != nil {
+		s.logger.Error("failed to get species", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if entry == nil || s.hiddenDraft(r, entry.Visibility) {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+
+	resp := INaturalistResponse{}
+	if i := inaturalistLink(entry.ExternalLinks); i >= 0 {
+		resp.URL = &entry.ExternalLinks[i].URL
+		resp.TaxonID = inaturalistTaxonID(entry.ExternalLinks[i].URL)
+	}
+	if resp.Photos, err = s.db.ListSpeciesMedia(name, db.MediaOriginINaturalist); err != nil {
+		s.logger.Error("failed to list species media", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	RespondJSON(w, http.StatusOK, resp)
+}
+
+// handleEnrichSpeciesINaturalist handles POST /api/v1/species/{name}/inaturalist
+// Matches the species against iNaturalist's taxa and stores the taxon as the
+// species' iNaturalist link. ?photos=N also caches up to N of the taxon's
+// photo URLs, replacing those cached before; ?dry_run=true stores nothing.
+// A species iNaturalist doesn't know gets a null taxon_id.
+func (s *Server) handleEnrichSpeciesINaturalist(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+	photoLimit := 0
+	if v := r.URL.Query().Get("photos"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > maxINaturalistPhotos {
+			RespondValidationError(w, []ValidationError{{Field: "photos", Message: "must be a number from 0 to " + strconv.Itoa(maxINaturalistPhotos)}})
+			return
+		}
+		photoLimit = n
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	entry, err := s.db.GetOakEntry(name)
+	if err != nil {
+		s.logger.Error("failed to get species", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if entry == nil {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+
+	genus := entry.Genus
+	if genus == "" {
+		genus = models.DefaultGenus
+	}
+	taxon, err := s.inaturalist.Match(r.Context(), genus+" "+entry.ScientificName)
+	if err != nil {
+		s.logger.Error("iNaturalist lookup failed", "name", name, "error", err)
+		RespondError(w, http.StatusBadGateway, apierror.CodeInternal, "iNaturalist lookup failed")
+		return
+	}
+	if taxon == nil {
+		RespondJSON(w, http.StatusOK, INaturalistResponse{Photos: []*db.Media{}})
+		return
+	}
+
+	taxonURL := inaturalist.TaxonURL(taxon.ID)
+	resp := INaturalistResponse{TaxonID: &taxon.ID, URL: &taxonURL, Name: taxon.Name, CommonName: taxon.CommonName}
+	link := models.ExternalLink{Name: "iNaturalist", URL: taxonURL, Logo: "inaturalist"}
+	switch i := inaturalistLink(entry.ExternalLinks); {
+	case i < 0:
+		entry.ExternalLinks = append(entry.ExternalLinks, link)
+		resp.LinkChanged = true
+	case entry.ExternalLinks[i].URL != taxonURL:
+		entry.ExternalLinks[i] = link
+		resp.LinkChanged = true
+	}
+
+	if photoLimit > 0 {
+		photos, err := s.inaturalist.Photos(r.Context(), taxon.ID, photoLimit)
+		if err != nil {
+			s.logger.Error("iNaturalist lookup failed", "name", name, "error", err)
+			RespondError(w, http.StatusBadGateway, apierror.CodeInternal, "iNaturalist lookup failed")
+			return
+		}
+		resp.Photos = make([]*db.Media, len(photos))
+		for i, p := range photos {
+			resp.Photos[i] = inaturalistMedia(name, p)
+		}
+	}
+
+	if !dryRun {
+		if resp.LinkChanged {
+			if err := s.db.SaveOakEntry(entry); err != nil {
+				s.logger.Error("failed to update species", "name", name, "error", err)
+				RespondInternalError(w, "")
+				return
+			}
+		}
+		if photoLimit > 0 {
+			if err := s.db.ReplaceOriginMedia(name, db.MediaOriginINaturalist, resp.Photos); err != nil {
+				s.logger.Error("failed to cache iNaturalist photos", "name", name, "error", err)
+				RespondInternalError(w, "")
+				return
+			}
+		}
+	}
+	if resp.Photos == nil {
+		if resp.Photos, err = s.db.ListSpeciesMedia(name, db.MediaOriginINaturalist); err != nil {
+			s.logger.Error("failed to list species media", "name", name, "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+	}
+	RespondJSON(w, http.StatusOK, resp)
+}
+
+// inaturalistMedia is the species media row for an iNaturalist photo
+func inaturalistMedia(name string, p inaturalist.Photo) *db.Media {
+	m := &db.Media{ScientificName: name, URL: p.URL, Origin: db.MediaOriginINaturalist}
+	id := strconv.FormatInt(p.ID, 10)
+	m.OriginID = &id
+	if p.SquareURL != "" {
+		m.ThumbnailURL = &p.SquareURL
+	}
+	if p.Attribution != "" {
+		m.Attribution = &p.Attribution
+	}
+	if p.License != "" {
+		m.License = &p.License
+	}
+	return m
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/jobs"
+	"github.com/jeff/oaks/api/internal/notify"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// Job kinds provided by the server
+const (
+	JobKindReindex = "reindex"
+)
+
+// registerJobs registers the server's built-in job handlers.
+// Features that need background work register their kinds here.
+func (s *Server) registerJobs() {
+	s.jobs.Register(JobKindReindex, func(ctx context.Context, job *db.Job, log *jobs.Log) error {
+		report, err := s.db.Reindex(func(step db.ReindexStep) {
+			log.Printf("%s: %d items in %dms", step.Name, step.Items, step.DurationMs)
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("reindex complete in %dms", report.DurationMs)
+		return nil
+	})
+	if s.geocoder != nil {
+		s.registerGeocodeJob()
+	}
+}
+
+// notifyJobFailed alerts when a job has exhausted its retries.
+func (s *Server) notifyJobFailed(job *db.Job, err error) {
+	s.notifier.Notify(notify.Event{
+		Type:    notify.EventJobFailed,
+		Subject: fmt.Sprintf("Job %d (%s) failed", job.ID, job.Kind),
+		Message: fmt.Sprintf("Job %d of kind %s failed after %d attempts: %v", job.ID, job.Kind, job.Attempts, err),
+	})
+}
+
+// EnqueueJobRequest is the request body for queuing a background job.
+type EnqueueJobRequest struct {
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	MaxAttempts int             `json:"max_attempts,omitempty"` // Defaults to jobs.DefaultMaxAttempts
+}
+
+// Valid job status filters for listing
+var validJobStatuses = map[string]bool{
+	db.JobQueued: true, db.JobRunning: true, db.JobSucceeded: true, db.JobFailed: true, db.JobCanceled: true,
+}
+
+// handleListJobs handles GET /api/v1/jobs
+// Returns the most recent jobs first; ?status= filters and ?limit= caps the list.
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status != "" && !validJobStatuses[status] {
+		RespondValidationError(w, []ValidationError{{
+			Field:   "status",
+			Message: "must be one of: queued, running, succeeded, failed, canceled",
+		}})
+		return
+	}
+
+	limit := defaultLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 && parsed <= maxLimit {
+			limit = parsed
+		}
+	}
+
+	list, err := s.db.ListJobs(status, limit)
+	if err != nil {
+		s.logger.Error("failed to list jobs", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if list == nil {
+		list = []*db.Job{}
+	}
+
+	RespondJSON(w, http.StatusOK, list)
+}
+
+// handleGetJob handles GET /api/v1/jobs/{id}
+// Includes the job's captured log.
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "Invalid job ID")
+		return
+	}
+
+	job, err := s.db.GetJob(id)
+	if err != nil {
+		s.logger.Error("failed to get job", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if job == nil {
+		RespondNotFound(w, "Job", idParam)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, job)
+}
+
+// handleEnqueueJob handles POST /api/v1/jobs
+// Queues a job of a registered kind and returns 202 with the queued job.
+func (s *Server) handleEnqueueJob(w http.ResponseWriter, r *http.Request) {
+	var req EnqueueJobRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+	if !s.jobs.Has(req.Kind) {
+		RespondValidationError(w, []ValidationError{{
+			Field:   "kind",
+			Message: fmt.Sprintf("must be one of: %s", strings.Join(s.jobs.Kinds(), ", ")),
+		}})
+		return
+	}
+
+	var payload interface{}
+	if len(req.Payload) > 0 {
+		payload = req.Payload
+	}
+	job, err := s.jobs.Enqueue(req.Kind, payload, req.MaxAttempts)
+	if err != nil {
+		s.logger.Error("failed to enqueue job", "kind", req.Kind, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusAccepted, job)
+}
+
+// handleCancelJob handles DELETE /api/v1/jobs/{id}
+// Only queued jobs can be canceled; running jobs finish their current attempt.
+func (s *Server) handleCancelJob(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "Invalid job ID")
+		return
+	}
+
+	job, err := s.db.GetJob(id)
+	if err != nil {
+		s.logger.Error("failed to get job", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if job == nil {
+		RespondNotFound(w, "Job", idParam)
+		return
+	}
+
+	canceled, err := s.db.CancelJob(id)
+	if err != nil {
+		s.logger.Error("failed to cancel job", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !canceled {
+		RespondConflict(w, fmt.Sprintf("job %d is %s and cannot be canceled", id, job.Status))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
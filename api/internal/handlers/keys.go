@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/notify"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+const (
+	// keyIDLength is the number of hex digits of a key's SHA-256 used as its ID
+	keyIDLength = 12
+	// defaultKeyUsageDays is the period daily key usage covers without ?days=
+	defaultKeyUsageDays = 30
+)
+
+// APIKeyInfo is an API key, identified by a fingerprint that does not reveal
+// it, with its usage. Revoked keys, and keys no longer configured on the
+// server, stay listed without a role while their usage is kept, so stale
+// ones can be spotted.
+type APIKeyInfo struct {
+	ID         string              `json:"id"`
+	Current    bool                `json:"current"`        // The server's configured key, which has the admin role
+	Name       string              `json:"name,omitempty"` // Given when the key was created
+	Role       string              `json:"role,omitempty"` // Empty if the key no longer authenticates
+	CreatedAt  *time.Time          `json:"created_at,omitempty"`
+	Requests   int                 `json:"requests"`
+	FirstUsed  *string             `json:"first_used,omitempty"` // UTC day
+	LastUsedAt *time.Time          `json:"last_used_at,omitempty"`
+	Days       []*db.DailyKeyUsage `json:"days,omitempty"`
+}
+
+// CreateAPIKeyRequest names a new key and gives its role.
+type CreateAPIKeyRequest struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// CreateAPIKeyResponse is a new key. Key is the only time the key itself
+// is returned.
+type CreateAPIKeyResponse struct {
+	db.APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyID returns the ID an API key is listed and tracked under: the
+// start of its SHA-256, which identifies it without revealing it.
+func APIKeyID(key string) string {
+	return apiKeyHash(key)[:keyIDLength]
+}
+
+// apiKeyHash returns the hex SHA-256 a created key is stored as
+func apiKeyHash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// requestKeyID returns the ID of the request's API key if it is valid, or ""
+func (s *Server) requestKeyID(r *http.Request) string {
+	token := extractBearerToken(r)
+	if token == "" {
+		return ""
+	}
+	_, id, err := s.keyRole(token)
+	if err != nil {
+		s.logger.Warn("failed to look up API key", "error", err)
+	}
+	return id
+}
+
+// trackKeyUsage counts each request that carries a valid API key against
+// that key. A failure to count is logged without failing the request.
+func (s *Server) trackKeyUsage(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := s.requestKeyID(r); id != "" {
+			if err := s.db.RecordKeyUsage(id, time.Now()); err != nil {
+				s.logger.Warn("failed to record key usage", "error", err)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleListAPIKeys handles GET /api/v1/admin/keys
+// Lists the current key, every created key, and every key with recorded
+// usage: current first, then most recently used, then created keys never
+// used.
+func (s *Server) handleListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	usage, err := s.db.ListKeyUsage()
+	if err != nil {
+		s.logger.Error("failed to list key usage", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	createdList, err := s.db.ListAPIKeys()
+	if err != nil {
+		s.logger.Error("failed to list API keys", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	created := make(map[string]*db.APIKey, len(createdList))
+	for _, k := range createdList {
+		created[k.ID] = k
+	}
+
+	currentID := APIKeyID(s.apiKey)
+	keys := []*APIKeyInfo{{ID: currentID, Current: true, Role: db.RoleAdmin}}
+	listed := map[string]bool{currentID: true}
+	for _, u := range usage {
+		info := newAPIKeyInfo(u, u.KeyID == currentID, created[u.KeyID])
+		if info.Current {
+			keys[0] = info
+			continue
+		}
+		keys = append(keys, info)
+		listed[u.KeyID] = true
+	}
+	for _, k := range createdList {
+		if !listed[k.ID] {
+			keys = append(keys, newAPIKeyInfo(&db.KeyUsage{KeyID: k.ID}, false, k))
+		}
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(keys, len(keys), len(keys), 0))
+}
+
+// handleGetAPIKeyUsage handles GET /api/v1/admin/keys/{id}/usage
+// Returns the key's all-time request count and last use, with daily counts
+// over the last ?days= days (default 30).
+func (s *Server) handleGetAPIKeyUsage(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	days := defaultKeyUsageDays
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed < 1 || parsed > maxPopularDays {
+			RespondValidationError(w, []ValidationError{{
+				Field:   "days",
+				Message: "must be an integer from 1 to " + strconv.Itoa(maxPopularDays),
+			}})
+			return
+		}
+		days = parsed
+	}
+
+	// Today counts as the first of the days
+	since := time.Now().UTC().AddDate(0, 0, 1-days)
+	usage, err := s.db.GetKeyUsage(id, since)
+	if err != nil {
+		s.logger.Error("failed to get key usage", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	created, err := s.db.GetAPIKey(id)
+	if err != nil {
+		s.logger.Error("failed to get API key", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	current := id == APIKeyID(s.apiKey)
+	switch {
+	case usage != nil:
+		RespondJSON(w, http.StatusOK, newAPIKeyInfo(usage, current, created))
+	case current:
+		RespondJSON(w, http.StatusOK, &APIKeyInfo{ID: id, Current: true, Role: db.RoleAdmin})
+	case created != nil:
+		RespondJSON(w, http.StatusOK, newAPIKeyInfo(&db.KeyUsage{KeyID: id}, false, created))
+	default:
+		RespondNotFound(w, "API key", id)
+	}
+}
+
+// handleCreateAPIKey handles POST /api/v1/keys
+// Creates a key with a name and role for a collaborator and returns it. The
+// key itself is never shown again.
+func (s *Server) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req CreateAPIKeyRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	var errs []ValidationError
+	if req.Name == "" || len(req.Name) > 100 {
+		errs = append(errs, ValidationError{Field: "name", Message: "is required and at most 100 characters"})
+	}
+	if !slices.Contains(db.Roles, req.Role) {
+		errs = append(errs, ValidationError{Field: "role", Message: "must be one of " + strings.Join(db.Roles, ", ")})
+	}
+	if len(errs) > 0 {
+		RespondValidationError(w, errs)
+		return
+	}
+
+	secret, err := GenerateAPIKey()
+	if err != nil {
+		s.logger.Error("failed to generate API key", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	key := db.APIKey{
+		ID:        APIKeyID(secret),
+		Name:      req.Name,
+		Role:      req.Role,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if err := s.db.CreateAPIKey(&key, apiKeyHash(secret)); err != nil {
+		s.logger.Error("failed to create API key", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	s.logger.Info("created API key", "id", key.ID, "name", key.Name, "role", key.Role)
+	s.notifier.Notify(notify.Event{
+		Type:    notify.EventAPIKeyCreated,
+		Subject: "New API key created",
+		Message: fmt.Sprintf("API key %s (%s) was created through the API with the %s role.", key.ID, key.Name, key.Role),
+	})
+
+	RespondJSON(w, http.StatusCreated, CreateAPIKeyResponse{APIKey: key, Key: secret})
+}
+
+// handleDeleteAPIKey handles DELETE /api/v1/keys/{id}
+// Revokes a created key; its usage stays listed. The server's configured key
+// can only be changed where the server reads it.
+func (s *Server) handleDeleteAPIKey(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == APIKeyID(s.apiKey) {
+		RespondConflict(w, "The server's configured key cannot be revoked; replace "+APIKeyEnvVar+" or the key file instead")
+		return
+	}
+
+	deleted, err := s.db.DeleteAPIKey(id)
+	if err != nil {
+		s.logger.Error("failed to delete API key", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !deleted {
+		RespondNotFound(w, "API key", id)
+		return
+	}
+	s.logger.Info("revoked API key", "id", id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// newAPIKeyInfo describes a key's usage, with its name and role if created
+// is not nil. A zero usage (no Requests) was never used.
+func newAPIKeyInfo(u *db.KeyUsage, current bool, created *db.APIKey) *APIKeyInfo {
+	info := &APIKeyInfo{
+		ID:       u.KeyID,
+		Current:  current,
+		Requests: u.Requests,
+		Days:     u.Days,
+	}
+	if u.Requests > 0 {
+		lastUsedAt := u.LastUsedAt
+		info.FirstUsed = &u.FirstUsed
+		info.LastUsedAt = &lastUsedAt
+	}
+	if current {
+		info.Role = db.RoleAdmin
+	}
+	if created != nil {
+		createdAt := created.CreatedAt
+		info.Name, info.Role, info.CreatedAt = created.Name, created.Role, &createdAt
+	}
+	return info
+}
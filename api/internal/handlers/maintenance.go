@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// maintenancePath is exempt from the maintenance gate so maintenance can be turned off.
+const maintenancePath = "/api/v1/admin/maintenance"
+
+// defaultMaintenanceMessage is returned for refused writes when no message was given.
+const defaultMaintenanceMessage = "The Oak Compendium is undergoing maintenance; changes are temporarily disabled"
+
+// MaintenanceStatus reports whether writes are disabled for maintenance.
+type MaintenanceStatus struct {
+	Enabled bool       `json:"enabled"`
+	Message string     `json:"message,omitempty"`
+	Since   *time.Time `json:"since,omitempty"`
+}
+
+// MaintenanceRequest is the request body for turning maintenance mode on or off.
+type MaintenanceRequest struct {
+	Mode    string `json:"mode"` // "on" or "off"
+	Message string `json:"message,omitempty"`
+}
+
+// maintenanceStatus returns the current maintenance state.
+func (s *Server) maintenanceStatus() MaintenanceStatus {
+	s.maintenanceMu.RLock()
+	defer s.maintenanceMu.RUnlock()
+	return s.maintenance
+}
+
+// maintenanceGate refuses writes with 503 while maintenance mode is on.
+// Reads, the maintenance endpoint itself, and read-only queries pass through.
+func (s *Server) maintenanceGate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+		status := s.maintenanceStatus()
+		if !status.Enabled || r.URL.Path == maintenancePath || r.URL.Path == queryPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+		RespondMaintenance(w, status.Message)
+	})
+}
+
+// handleGetMaintenance handles GET /api/v1/admin/maintenance
+func (s *Server) handleGetMaintenance(w http.ResponseWriter, r *http.Request) {
+	RespondJSON(w, http.StatusOK, s.maintenanceStatus())
+}
+
+// handleSetMaintenance handles POST /api/v1/admin/maintenance
+// Turns maintenance mode on (writes return 503 with the message) or off.
+// The state is held in memory and clears when the server restarts.
+func (s *Server) handleSetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req MaintenanceRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+
+	var status MaintenanceStatus
+	switch req.Mode {
+	case "on":
+		message := req.Message
+		if message == "" {
+			message = defaultMaintenanceMessage
+		}
+		now := time.Now().UTC().Truncate(time.Second)
+		status = MaintenanceStatus{Enabled: true, Message: message, Since: &now}
+	case "off":
+	default:
+		RespondValidationError(w, []ValidationError{{Field: "mode", Message: "mode must be on or off"}})
+		return
+	}
+
+	s.maintenanceMu.Lock()
+	s.maintenance = status
+	s.maintenanceMu.Unlock()
+	s.logger.Info("maintenance mode changed", "enabled", status.Enabled, "message", status.Message)
+
+	RespondJSON(w, http.StatusOK, status)
+}
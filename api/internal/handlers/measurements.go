@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/api/internal/units"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// handleListSpeciesMeasurements handles GET /api/v1/species/{name}/measurements
+// Returns the lengths parsed from the species' source text with structured min/max values.
+func (s *Server) handleListSpeciesMeasurements(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+
+	visible, err := s.speciesVisible(r, name)
+	if err != nil {
+		s.logger.Error("failed to check species existence", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !visible {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+
+	all, err := s.db.ListMeasurements(name)
+	if err != nil {
+		s.logger.Error("failed to list measurements", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	withheld, err := s.withheldLocations(r, name)
+	if err != nil {
+		s.logger.Error("failed to get internal fields", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	measurements := []*models.Measurement{}
+	for _, m := range all {
+		if !withheld[models.TextLocation(m.SourceID, m.Field)] {
+			measurements = append(measurements, m)
+		}
+	}
+
+	RespondJSON(w, http.StatusOK, measurements)
+}
+
+// refreshMeasurements re-parses the measurements of species whose source text
+// just changed, and re-detects conflicts between their sources' claims. Like
+// refreshMentions, failures are logged; reindex rebuilds both.
+func (s *Server) refreshMeasurements(names ...string) {
+	if len(names) == 0 {
+		return
+	}
+	if err := s.db.RefreshMeasurements(names...); err != nil {
+		s.logger.Error("failed to refresh measurements", "species", names, "error", err)
+	}
+	if _, err := s.db.DetectConflicts(names...); err != nil {
+		s.logger.Error("failed to detect conflicts", "species", names, "error", err)
+	}
+}
+
+// unitsParam reads the ?units= query parameter (metric, imperial, or dual),
+// responding with 400 if it is invalid. The system is empty when the
+// parameter is absent, meaning measurements are left as written.
+func unitsParam(w http.ResponseWriter, r *http.Request) (units.System, bool) {
+	value := r.URL.Query().Get("units")
+	if value == "" {
+		return "", true
+	}
+	system, ok := units.ParseSystem(value)
+	if !ok {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "units must be metric, imperial, or dual")
+		return "", false
+	}
+	return system, true
+}
@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/media"
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// maxMediaSize is the largest photo POST /species/{name}/media accepts (25MB)
+const maxMediaSize = 25 << 20
+
+// mediaURLPrefix is where GET /media/{file} serves stored files
+const mediaURLPrefix = "/api/v1/media/"
+
+// WithMediaDir stores uploaded photos and their thumbnails in dir. Without
+// it uploads are refused.
+func WithMediaDir(dir string) ServerOption {
+	return func(s *Server) {
+		s.media = media.NewStore(dir)
+	}
+}
+
+// MediaLinkRequest attaches media kept elsewhere by its URL
+type MediaLinkRequest struct {
+	URL          string  `json:"url"`
+	ThumbnailURL *string `json:"thumbnail_url,omitempty"`
+	Kind         *string `json:"kind,omitempty"` // One of db.MediaKinds
+	Caption      *string `json:"caption,omitempty"`
+	License      *string `json:"license,omitempty"`
+	Attribution  *string `json:"attribution,omitempty"`
+	SourceID     *int64  `json:"source_id,omitempty"`
+}
+
+// handleListSpeciesMedia handles GET /api/v1/species/{name}/media
+// Lists a species' media in order; ?kind= and ?origin= filter. Photo
+// coordinates of threatened species are generalized for the public, as
+// range localities are.
+func (s *Server) handleListSpeciesMedia(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+	visible, err := s.speciesVisible(r, name)
+	if err != nil {
+		s.logger.Error("failed to check species existence", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !visible {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+
+	all, err := s.db.ListSpeciesMedia(name, r.URL.Query().Get("origin"))
+	if err != nil {
+		s.logger.Error("failed to list species media", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	generalize, err := s.generalization(r, name)
+	if err != nil {
+		s.logger.Error("failed to get conservation status", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	kind := r.URL.Query().Get("kind")
+	items := []*db.Media{}
+	for _, m := range all {
+		if kind != "" && (m.Kind == nil || *m.Kind != kind) {
+			continue
+		}
+		if generalize != nil && m.Latitude != nil && m.Longitude != nil {
+			lat, lon := generalize.Point(*m.Latitude, *m.Longitude)
+			m.Latitude, m.Longitude, m.Generalized = &lat, &lon, generalize.Grid
+		}
+		items = append(items, m)
+	}
+	RespondJSON(w, http.StatusOK, NewListResponse(items, len(items), len(items), 0))
+}
+
+// handleAddSpeciesMedia handles POST /api/v1/species/{name}/media
+// A multipart/form-data body uploads a photo; a JSON body (MediaLinkRequest)
+// attaches media kept elsewhere by URL. Media already attached to the
+// species is a conflict.
+func (s *Server) handleAddSpeciesMedia(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		s.uploadSpeciesMedia(w, r)
+	} else {
+		s.linkSpeciesMedia(w, r)
+	}
+}
+
+// uploadSpeciesMedia takes a multipart form with the photo as "file" and
+// optional "kind", "source_id", "license", "caption", and "attribution"
+// fields. Stores the photo with a thumbnail and what its EXIF data says of
+// when and where it was taken.
+func (s *Server) uploadSpeciesMedia(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+	if s.media == nil {
+		RespondError(w, http.StatusServiceUnavailable, apierror.CodeInternal, "Media uploads are not configured")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxMediaSize+1<<20) // Room for the other fields
+	file, header, err := r.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		RespondValidationError(w, []ValidationError{{Field: "file", Message: "must be at most 25MB"}})
+		return
+	case err != nil:
+		RespondValidationError(w, []ValidationError{{Field: "file", Message: "is required as a multipart/form-data file"}})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxMediaSize+1))
+	if err != nil {
+		RespondValidationError(w, []ValidationError{{Field: "file", Message: "could not be read"}})
+		return
+	}
+	if len(data) > maxMediaSize {
+		RespondValidationError(w, []ValidationError{{Field: "file", Message: "must be at most 25MB"}})
+		return
+	}
+
+	var sourceID *int64
+	if v := r.FormValue("source_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			RespondValidationError(w, []ValidationError{{Field: "source_id", Message: "must be a number"}})
+			return
+		}
+		sourceID = &id
+	}
+	kind := formValue(r.FormValue("kind"))
+	entry, ok := s.mediaSpecies(w, name, kind, sourceID)
+	if !ok {
+		return
+	}
+
+	saved, err := s.media.Save(data)
+	if errors.Is(err, media.ErrUnsupported) {
+		RespondValidationError(w, []ValidationError{{Field: "file", Message: "must be a JPEG, PNG, or GIF image"}})
+		return
+	}
+	if err != nil {
+		s.logger.Error("failed to store media", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	m := uploadedMedia(entry.ScientificName, saved)
+	m.Filename = formValue(header.Filename)
+	m.License = formValue(r.FormValue("license"))
+	m.Caption = formValue(r.FormValue("caption"))
+	m.Attribution = formValue(r.FormValue("attribution"))
+	m.Kind, m.SourceID = kind, sourceID
+	s.saveSpeciesMedia(w, m)
+}
+
+// linkSpeciesMedia attaches media kept elsewhere by URL
+func (s *Server) linkSpeciesMedia(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+	var req MediaLinkRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+	var errs []ValidationError
+	if !isHTTPURL(req.URL) {
+		errs = append(errs, ValidationError{Field: "url", Message: "must be an absolute http or https URL"})
+	}
+	if req.ThumbnailURL != nil && !isHTTPURL(*req.ThumbnailURL) {
+		errs = append(errs, ValidationError{Field: "thumbnail_url", Message: "must be an absolute http or https URL"})
+	}
+	if len(errs) > 0 {
+		RespondValidationError(w, errs)
+		return
+	}
+	entry, ok := s.mediaSpecies(w, name, req.Kind, req.SourceID)
+	if !ok {
+		return
+	}
+
+	s.saveSpeciesMedia(w, &db.Media{
+		ScientificName: entry.ScientificName,
+		URL:            req.URL,
+		ThumbnailURL:   req.ThumbnailURL,
+		Caption:        req.Caption,
+		License:        req.License,
+		Attribution:    req.Attribution,
+		Origin:         db.MediaOriginLink,
+		OriginID:       &req.URL,
+		Kind:           req.Kind,
+		SourceID:       req.SourceID,
+	})
+}
+
+// mediaSpecies gets the species media is being added to, checking the
+// media's kind and source. Writes the error response and returns false if
+// they are invalid or the species doesn't exist.
+func (s *Server) mediaSpecies(w http.ResponseWriter, name string, kind *string, sourceID *int64) (*models.OakEntry, bool) {
+	if kind != nil && !slices.Contains(db.MediaKinds, *kind) {
+		RespondValidationError(w, []ValidationError{{Field: "kind", Message: "must be one of: " + strings.Join(db.MediaKinds, ", ")}})
+		return nil, false
+	}
+	if sourceID != nil {
+		source, err := s.db.GetSource(*sourceID)
+		if err != nil {
+			s.logger.Error("failed to get source", "id", *sourceID, "error", err)
+			RespondInternalError(w, "")
+			return nil, false
+		}
+		if source == nil {
+			RespondValidationError(w, []ValidationError{{Field: "source_id", Message: fmt.Sprintf("source %d does not exist", *sourceID)}})
+			return nil, false
+		}
+	}
+	entry, err := s.db.GetOakEntry(name)
+	if err != nil {
+		s.logger.Error("failed to get species", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return nil, false
+	}
+	if entry == nil {
+		RespondNotFound(w, "Species", name)
+		return nil, false
+	}
+	return entry, true
+}
+
+// saveSpeciesMedia adds media and responds with it
+func (s *Server) saveSpeciesMedia(w http.ResponseWriter, m *db.Media) {
+	if err := s.db.AddSpeciesMedia(m); err != nil {
+		if errors.Is(err, db.ErrMediaExists) {
+			RespondConflict(w, "This media is already attached to "+m.ScientificName)
+			return
+		}
+		s.logger.Error("failed to save species media", "name", m.ScientificName, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	RespondJSON(w, http.StatusCreated, m)
+}
+
+// handleDeleteSpeciesMedia handles DELETE /api/v1/species/{name}/media/{id}
+// Removes an uploaded file and its thumbnail once no media uses them.
+func (s *Server) handleDeleteSpeciesMedia(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+	idParam := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		RespondValidationError(w, []ValidationError{{Field: "id", Message: "must be a number"}})
+		return
+	}
+	m, err := s.db.GetSpeciesMedia(id)
+	if err != nil {
+		s.logger.Error("failed to get species media", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if m == nil || m.ScientificName != name {
+		RespondNotFound(w, "Media", idParam)
+		return
+	}
+	if _, err := s.db.DeleteSpeciesMedia(id); err != nil {
+		s.logger.Error("failed to delete species media", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	if m.File != nil && s.media != nil {
+		inUse, err := s.db.MediaFileInUse(*m.File)
+		if err == nil && !inUse {
+			files := []string{*m.File}
+			if m.ThumbnailFile != nil {
+				files = append(files, *m.ThumbnailFile)
+			}
+			err = s.media.Remove(files...)
+		}
+		if err != nil {
+			// The media is gone; a file left behind only takes space
+			s.logger.Error("failed to remove media file", "file", *m.File, "error", err)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetMediaFile handles GET /api/v1/media/{file}
+// Serves a stored photo or thumbnail. Files are named by their content, so
+// they can be cached forever.
+func (s *Server) handleGetMediaFile(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "file")
+	var path string
+	ok := s.media != nil
+	if ok {
+		path, ok = s.media.Path(name)
+	}
+	if !ok {
+		RespondNotFound(w, "Media file", name)
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		RespondNotFound(w, "Media file", name)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		RespondNotFound(w, "Media file", name)
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeContent(w, r, name, info.ModTime(), f)
+}
+
+// uploadedMedia is the species media row for a stored photo
+func uploadedMedia(scientificName string, saved *media.Saved) *db.Media {
+	fileURL, thumbnailURL := mediaURLPrefix+saved.File, mediaURLPrefix+saved.ThumbnailFile
+	m := &db.Media{
+		ScientificName: scientificName,
+		URL:            fileURL,
+		ThumbnailURL:   &thumbnailURL,
+		Origin:         db.MediaOriginUpload,
+		OriginID:       &saved.Hash,
+		File:           &saved.File,
+		ThumbnailFile:  &saved.ThumbnailFile,
+		Width:          &saved.Width,
+		Height:         &saved.Height,
+		Latitude:       saved.EXIF.Latitude,
+		Longitude:      saved.EXIF.Longitude,
+	}
+	if saved.EXIF.CapturedAt != "" {
+		m.CapturedAt = &saved.EXIF.CapturedAt
+	}
+	return m
+}
+
+// isHTTPURL reports whether s is an absolute http or https URL
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// formValue returns a trimmed form value, or nil if it is empty
+func formValue(v string) *string {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// handleListSpeciesMentions handles GET /api/v1/species/{name}/mentions
+// Returns the species whose accounts or source notes mention this one (backlinks).
+func (s *Server) handleListSpeciesMentions(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+
+	visible, err := s.speciesVisible(r, name)
+	if err != nil {
+		s.logger.Error("failed to check species existence", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !visible {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+
+	refs, err := s.db.ListMentions(name)
+	if err != nil {
+		s.logger.Error("failed to list mentions", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	// Mentions from drafts and internal fields are hidden from the public
+	// along with them
+	mentions := make([]*models.CrossReference, 0, len(refs))
+	for _, ref := range refs {
+		withheld, err := s.withheldLocations(r, ref.ScientificName)
+		if err != nil {
+			s.logger.Error("failed to get internal fields", "name", ref.ScientificName, "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+		if withheld[ref.Location] {
+			continue
+		}
+		visible, err := s.speciesVisible(r, ref.ScientificName)
+		if err != nil {
+			s.logger.Error("failed to check species existence", "name", ref.ScientificName, "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+		if visible {
+			mentions = append(mentions, ref)
+		}
+	}
+
+	RespondJSON(w, http.StatusOK, mentions)
+}
+
+// refreshMentions re-detects the cross-references of species whose text just
+// changed. Failures are logged rather than returned: the write itself
+// succeeded, and POST /api/v1/admin/reindex rebuilds every cross-reference.
+func (s *Server) refreshMentions(names ...string) {
+	if len(names) == 0 {
+		return
+	}
+	if err := s.db.RefreshCrossReferences(names...); err != nil {
+		s.logger.Error("failed to refresh cross references", "species", names, "error", err)
+	}
+}
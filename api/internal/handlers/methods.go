@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// routedMethods are the methods routes are registered with. HEAD and OPTIONS
+// are answered for every route and never registered directly.
+var routedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// headRequests serves HEAD requests with the GET handler for the route,
+// discarding the body but reporting its Content-Length.
+func headRequests(next http.Handler) http.Handler {
+	get := middleware.GetHead(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		hw := &headResponseWriter{ResponseWriter: w}
+		get.ServeHTTP(hw, r)
+		hw.finish()
+	})
+}
+
+// headResponseWriter counts the body a GET handler writes instead of sending
+// it, and holds the status until the handler returns so Content-Length can be
+// set before the headers go out.
+type headResponseWriter struct {
+	http.ResponseWriter
+	status int
+	length int
+}
+
+func (hw *headResponseWriter) WriteHeader(code int) {
+	if hw.status == 0 {
+		hw.status = code
+	}
+}
+
+func (hw *headResponseWriter) Write(b []byte) (int, error) {
+	if hw.status == 0 {
+		hw.status = http.StatusOK
+	}
+	hw.length += len(b)
+	return len(b), nil
+}
+
+// finish sends the held status with the counted Content-Length
+func (hw *headResponseWriter) finish() {
+	if hw.status == 0 {
+		hw.status = http.StatusOK
+	}
+	header := hw.ResponseWriter.Header()
+	if header.Get("Content-Length") == "" && hw.status != http.StatusNoContent && hw.status != http.StatusNotModified {
+		header.Set("Content-Length", strconv.Itoa(hw.length))
+	}
+	hw.ResponseWriter.WriteHeader(hw.status)
+}
+
+// allowedMethods returns the methods the router accepts for a path, including
+// HEAD for GET routes and OPTIONS for every route.
+func (s *Server) allowedMethods(r *http.Request) []string {
+	path := r.URL.RawPath
+	if path == "" {
+		path = r.URL.Path
+	}
+
+	var allowed []string
+	for _, method := range routedMethods {
+		if !s.router.Match(chi.NewRouteContext(), method, path) {
+			continue
+		}
+		allowed = append(allowed, method)
+		if method == http.MethodGet {
+			allowed = append(allowed, http.MethodHead)
+		}
+	}
+	return append(allowed, http.MethodOptions)
+}
+
+// handleMethodNotAllowed answers requests whose path matches a route but whose
+// method does not. OPTIONS gets 204 listing the allowed methods; anything
+// else gets 405 with the same Allow header.
+func (s *Server) handleMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	allowed := s.allowedMethods(r)
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	RespondError(w, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed,
+		"Method "+r.Method+" is not allowed; use "+strings.Join(allowed, ", "))
+}
+
+// handleNotFound answers requests for paths no route matches.
+func (s *Server) handleNotFound(w http.ResponseWriter, r *http.Request) {
+	RespondError(w, http.StatusNotFound, apierror.CodeNotFound, "No route for "+r.Method+" "+r.URL.Path)
+}
@@ -26,23 +26,37 @@ const (
 	ClientIPKey contextKey = "client_ip"
 )
 
-// RateLimitConfig holds rate limiting configuration
+// RateLimitConfig holds rate limiting configuration. Requests without a
+// valid API key are limited per IP, or per crawler for crawlers; those with
+// one draw from a token bucket per key. A zero KeyBurst limits every request
+// per IP.
 type RateLimitConfig struct {
-	ReadLimit    int           // requests per window for GET
-	WriteLimit   int           // requests per window for POST/PUT/DELETE
-	BackupLimit  int           // requests per window for backup endpoints
-	Window       time.Duration // rate limit window duration
-	BackupWindow time.Duration // backup endpoint window duration
+	ReadLimit       int           // requests per window for GET
+	WriteLimit      int           // requests per window for POST/PUT/DELETE
+	BackupLimit     int           // requests per window for backup endpoints
+	Window          time.Duration // rate limit window duration
+	BackupWindow    time.Duration // backup endpoint window duration
+	KeyRate         float64       // tokens per second refilled for each API key
+	KeyBurst        int           // requests an API key can make at once
+	TrustedKeyBurst int           // KeyBurst for keys in TrustedKeys
+	TrustedKeys     []string      // IDs (see APIKeyID) of keys given TrustedKeyBurst
+	CrawlerLimit    int           // requests per CrawlerWindow for each anonymous crawler; 0 treats them as anyone else
+	CrawlerWindow   time.Duration // crawler window duration
 }
 
 // DefaultRateLimitConfig returns the default rate limiting configuration
 func DefaultRateLimitConfig() RateLimitConfig {
 	return RateLimitConfig{
-		ReadLimit:    10, // 10 req/sec
-		WriteLimit:   5,  // 5 req/sec
-		BackupLimit:  1,  // 1 req/min
-		Window:       time.Second,
-		BackupWindow: time.Minute,
+		ReadLimit:       10, // 10 req/sec
+		WriteLimit:      5,  // 5 req/sec
+		BackupLimit:     1,  // 1 req/min
+		Window:          time.Second,
+		BackupWindow:    time.Minute,
+		KeyRate:         20, // 20 req/sec sustained
+		KeyBurst:        100,
+		TrustedKeyBurst: 1000,
+		CrawlerLimit:    60, // 60 req/min shared by all of a crawler's addresses
+		CrawlerWindow:   time.Minute,
 	}
 }
 
@@ -223,9 +237,7 @@ func recoverMiddleware(logger *slog.Logger) func(next http.Handler) http.Handler
 						"client_ip", GetClientIP(r.Context()),
 					)
 
-					w.Header().Set("Content-Type", "application/json")
-					w.WriteHeader(http.StatusInternalServerError)
-					_, _ = w.Write([]byte(`{"error":"internal server error"}`))
+					RespondInternalError(w, "")
 				}
 			}()
 
@@ -249,17 +261,43 @@ func timeoutMiddleware(timeout time.Duration) func(next http.Handler) http.Handl
 // maxBodySize is the maximum allowed request body size (1MB)
 const maxBodySize = 1 << 20 // 1MB
 
-// bodySizeLimitMiddleware limits the size of request bodies to prevent memory exhaustion
+// unlimitedBodyKey is the context key for the request body as received,
+// before bodySizeLimitMiddleware limited it
+type unlimitedBodyKey struct{}
+
+// bodySizeLimitMiddleware limits the size of request bodies to prevent memory exhaustion.
+// Multipart uploads are left to their handlers, which set their own limit
+// (see maxMediaSize); routes that take larger bodies use withBodyLimit.
 func bodySizeLimitMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Only limit body size for methods that may have a body
-		if r.Method == "POST" || r.Method == "PUT" || r.Method == "PATCH" {
+		if (r.Method == "POST" || r.Method == "PUT" || r.Method == "PATCH") &&
+			!strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			r = r.WithContext(context.WithValue(r.Context(), unlimitedBodyKey{}, r.Body))
 			r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
 		}
 		next.ServeHTTP(w, r)
 	})
 }
 
+// withBodyLimit replaces maxBodySize with limit for the routes it wraps
+func withBodyLimit(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := r.Body
+			if unlimited, ok := r.Context().Value(unlimitedBodyKey{}).(io.ReadCloser); ok {
+				body = unlimited
+			}
+			r.Body = http.MaxBytesReader(w, body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// noStoreCacheControl is the Cache-Control API responses get unless a
+// handler sets their own
+const noStoreCacheControl = "no-store, no-cache, must-revalidate, private"
+
 // securityHeadersMiddleware adds security headers to all responses
 func securityHeadersMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -276,7 +314,7 @@ func securityHeadersMiddleware(next http.Handler) http.Handler {
 		w.Header().Set("X-XSS-Protection", "1; mode=block")
 
 		// Disable caching for API responses (security-sensitive data)
-		w.Header().Set("Cache-Control", "no-store, no-cache, must-revalidate, private")
+		w.Header().Set("Cache-Control", noStoreCacheControl)
 
 		next.ServeHTTP(w, r)
 	})
@@ -298,9 +336,9 @@ func corsMiddleware(config CORSConfig) func(next http.Handler) http.Handler {
 			}
 			return false
 		},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-API-Key", "X-Request-ID"},
-		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowedMethods:   []string{"GET", "HEAD", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-API-Key", "X-Request-ID", ClientVersionHeader, ContentHashHeader},
+		ExposedHeaders:   []string{"X-Request-ID", "Deprecation", "Sunset", "Link", "Warning", ContentHashHeader},
 		AllowCredentials: false,
 		MaxAge:           300, // 5 minutes
 	})
@@ -308,7 +346,7 @@ func corsMiddleware(config CORSConfig) func(next http.Handler) http.Handler {
 
 // isHealthEndpoint returns true if the path is a health check endpoint
 func isHealthEndpoint(path string) bool {
-	return path == "/health" || path == "/health/ready" || path == "/api/v1/health"
+	return path == "/health" || path == "/health/ready" || path == "/api/v1/health" || path == "/api/v2/health"
 }
 
 // isWriteMethod returns true if the method modifies data
@@ -321,15 +359,16 @@ func isBackupEndpoint(path string) bool {
 	return strings.HasPrefix(path, "/api/v1/backup")
 }
 
-// conditionalRateLimitMiddleware applies different rate limits based on request type
-func conditionalRateLimitMiddleware(config RateLimitConfig) func(next http.Handler) http.Handler {
+// conditionalRateLimitMiddleware applies different rate limits based on
+// request type. keyID, if set, returns the ID of the request's valid API
+// key, or "" for anonymous requests; keyed requests draw from their key's
+// token bucket rather than their IP's limits.
+func conditionalRateLimitMiddleware(config RateLimitConfig, keyID func(*http.Request) string) func(next http.Handler) http.Handler {
 	// Create rate limit handlers for each type with Retry-After header
 	makeLimitHandler := func(window time.Duration) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Content-Type", "application/json")
 			w.Header().Set("Retry-After", fmt.Sprintf("%d", int(window.Seconds())))
-			w.WriteHeader(http.StatusTooManyRequests)
-			_, _ = w.Write([]byte(`{"error":"rate limit exceeded"}`))
+			RespondRateLimited(w)
 		}
 	}
 
@@ -351,6 +390,7 @@ func conditionalRateLimitMiddleware(config RateLimitConfig) func(next http.Handl
 		httprate.WithLimitHandler(makeLimitHandler(config.Window)),
 	)
 
+	// Backups stay limited however they are authenticated
 	backupLimitMiddleware := httprate.Limit(
 		config.BackupLimit,
 		config.BackupWindow,
@@ -360,28 +400,65 @@ func conditionalRateLimitMiddleware(config RateLimitConfig) func(next http.Handl
 		httprate.WithLimitHandler(makeLimitHandler(config.BackupWindow)),
 	)
 
+	// Known crawlers share a limit across addresses; others are limited by address
+	var crawlerLimitMiddleware func(http.Handler) http.Handler
+	if config.CrawlerLimit > 0 {
+		crawlerLimitMiddleware = httprate.Limit(
+			config.CrawlerLimit,
+			config.CrawlerWindow,
+			httprate.WithKeyFuncs(func(r *http.Request) (string, error) {
+				name := crawlerName(r.UserAgent())
+				if name == "other" {
+					return name + ":" + GetClientIP(r.Context()), nil
+				}
+				return name, nil
+			}),
+			httprate.WithLimitHandler(makeLimitHandler(config.CrawlerWindow)),
+		)
+	}
+
+	perKey := newKeyLimiter(config)
+	if keyID == nil {
+		perKey = nil
+	}
+
 	return func(next http.Handler) http.Handler {
+		readLimited := readLimitMiddleware(next)
+		writeLimited := writeLimitMiddleware(next)
+		backupLimited := backupLimitMiddleware(next)
+		var crawlerLimited http.Handler
+		if crawlerLimitMiddleware != nil {
+			crawlerLimited = crawlerLimitMiddleware(next)
+		}
+
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Health endpoints are exempt from rate limiting
 			if isHealthEndpoint(r.URL.Path) {
 				next.ServeHTTP(w, r)
 				return
 			}
+			if isBackupEndpoint(r.URL.Path) {
+				backupLimited.ServeHTTP(w, r)
+				return
+			}
+			if perKey != nil {
+				if id := keyID(r); id != "" {
+					perKey.serve(w, r, next, id)
+					return
+				}
+			}
 
-			// Select the appropriate rate limiter based on request type
-			var limiterMiddleware func(http.Handler) http.Handler
-
-			switch {
-			case isBackupEndpoint(r.URL.Path):
-				limiterMiddleware = backupLimitMiddleware
-			case isWriteMethod(r.Method):
-				limiterMiddleware = writeLimitMiddleware
-			default:
-				limiterMiddleware = readLimitMiddleware
+			if crawlerLimited != nil && crawlerName(r.UserAgent()) != "" {
+				crawlerLimited.ServeHTTP(w, r)
+				return
 			}
 
-			// Apply the selected rate limiter
-			limiterMiddleware(next).ServeHTTP(w, r)
+			// Select the appropriate per-IP limiter based on request type
+			if isWriteMethod(r.Method) {
+				writeLimited.ServeHTTP(w, r)
+			} else {
+				readLimited.ServeHTTP(w, r)
+			}
 		})
 	}
 }
@@ -421,6 +498,15 @@ func (grw *gzipResponseWriter) Write(b []byte) (int, error) {
 		grw.statusCode = http.StatusOK
 	}
 
+	// Bodies the handler encoded itself (the cached export) pass through
+	if !grw.compressed && len(grw.buffer) == 0 && grw.Header().Get("Content-Encoding") != "" {
+		if !grw.wroteHeader {
+			grw.ResponseWriter.WriteHeader(grw.statusCode)
+			grw.wroteHeader = true
+		}
+		return grw.ResponseWriter.Write(b)
+	}
+
 	// If not yet decided on compression, buffer the data
 	if !grw.compressed && len(grw.buffer) < gzipMinSize {
 		grw.buffer = append(grw.buffer, b...)
@@ -490,8 +576,9 @@ func (grw *gzipResponseWriter) Close() error {
 // gzipMiddleware compresses JSON responses above the minimum size threshold
 func gzipMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Check if client accepts gzip
-		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		// Check if client accepts gzip. HEAD responses have no body to
+		// compress and report the uncompressed Content-Length.
+		if r.Method == http.MethodHead || !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -516,6 +603,9 @@ func (s *Server) SetupMiddleware(config MiddlewareConfig) {
 	// 1. Security headers - add to all responses
 	r.Use(securityHeadersMiddleware)
 
+	// 1a. Crawler caching - let shared caches keep crawlers' reads
+	r.Use(crawlerCacheMiddleware)
+
 	// 2. Body size limit - prevent memory exhaustion
 	r.Use(bodySizeLimitMiddleware)
 
@@ -535,8 +625,9 @@ func (s *Server) SetupMiddleware(config MiddlewareConfig) {
 	// 7. Timeout - request timeout
 	r.Use(timeoutMiddleware(config.Timeout))
 
-	// 8. RateLimit - per-IP rate limiting (health endpoints exempt)
-	r.Use(conditionalRateLimitMiddleware(config.RateLimit))
+	// 8. RateLimit - per-key token buckets for authenticated requests, a
+	// stricter class for crawlers, per-IP limits otherwise (health endpoints exempt)
+	r.Use(conditionalRateLimitMiddleware(config.RateLimit, s.requestKeyID))
 
 	// 9. CORS - cross-origin support
 	r.Use(corsMiddleware(config.CORS))
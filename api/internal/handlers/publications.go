@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// PublishSchedulerInterval is how often the scheduler checks for due publications.
+const PublishSchedulerInterval = time.Minute
+
+// SchedulePublicationRequest is the request body for scheduling draft species to go live.
+type SchedulePublicationRequest struct {
+	Species   []string `json:"species"`
+	PublishAt string   `json:"publish_at"` // RFC 3339
+	Note      *string  `json:"note,omitempty"`
+}
+
+// handleListPublications handles GET /api/v1/publications
+// Lists pending publications; ?all=true includes published and canceled ones.
+func (s *Server) handleListPublications(w http.ResponseWriter, r *http.Request) {
+	pendingOnly := r.URL.Query().Get("all") != "true"
+
+	pubs, err := s.db.ListScheduledPublications(pendingOnly)
+	if err != nil {
+		s.logger.Error("failed to list scheduled publications", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if pubs == nil {
+		pubs = []*db.ScheduledPublication{}
+	}
+
+	RespondJSON(w, http.StatusOK, pubs)
+}
+
+// handleSchedulePublication handles POST /api/v1/publications
+// Queues a set of species to be published together at a future time.
+func (s *Server) handleSchedulePublication(w http.ResponseWriter, r *http.Request) {
+	var req SchedulePublicationRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+
+	var errors []ValidationError
+	if len(req.Species) == 0 {
+		errors = append(errors, ValidationError{Field: "species", Message: "at least one species is required"})
+	}
+	publishAt, err := time.Parse(time.RFC3339, req.PublishAt)
+	if err != nil {
+		errors = append(errors, ValidationError{Field: "publish_at", Message: "must be an RFC 3339 time, e.g. 2026-11-01T09:00:00Z"})
+	} else if !publishAt.After(time.Now()) {
+		errors = append(errors, ValidationError{Field: "publish_at", Message: "must be in the future"})
+	}
+	for i, name := range req.Species {
+		exists, err := s.db.OakEntryExists(name)
+		if err != nil {
+			s.logger.Error("failed to check species existence", "name", name, "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+		if !exists {
+			errors = append(errors, ValidationError{Field: fmt.Sprintf("species[%d]", i), Message: "species not found: " + name})
+		}
+	}
+	if len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+
+	pub, err := s.db.CreateScheduledPublication(req.Species, publishAt, req.Note)
+	if err != nil {
+		s.logger.Error("failed to schedule publication", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusCreated, pub)
+}
+
+// handleCancelPublication handles DELETE /api/v1/publications/{id}
+func (s *Server) handleCancelPublication(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "Invalid publication ID")
+		return
+	}
+
+	pub, err := s.db.GetScheduledPublication(id)
+	if err != nil {
+		s.logger.Error("failed to get scheduled publication", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if pub == nil {
+		RespondNotFound(w, "Publication", idParam)
+		return
+	}
+	if pub.Status != db.PublicationPending {
+		RespondConflict(w, fmt.Sprintf("publication %d is already %s", id, pub.Status))
+		return
+	}
+
+	if _, err := s.db.CancelScheduledPublication(id); err != nil {
+		s.logger.Error("failed to cancel scheduled publication", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RunPublishScheduler publishes due scheduled publications every interval until
+// ctx is canceled. It checks once immediately so publications that came due
+// while the server was down go out at startup.
+func (s *Server) RunPublishScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.publishDue(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishDue runs one scheduler pass and logs what it published.
+// Passes are skipped during maintenance; due publications go out afterwards.
+func (s *Server) publishDue(now time.Time) {
+	if s.maintenanceStatus().Enabled {
+		return
+	}
+	published, err := s.db.PublishDue(now)
+	for _, pub := range published {
+		s.logger.Info("published scheduled species", "publication", pub.ID, "species", len(pub.Species))
+	}
+	if err != nil {
+		s.logger.Error("failed to publish scheduled species", "error", err)
+	}
+}
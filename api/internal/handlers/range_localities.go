@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/geocode"
+	"github.com/jeff/oaks/api/internal/jobs"
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/api/internal/rangemap"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// JobKindGeocodeRanges geocodes the localities in species sources' range
+// text; registered when a geocoder is configured
+const JobKindGeocodeRanges = "geocode-ranges"
+
+// GeocodeRangesRequest is the request body, and job payload, for geocoding
+// range localities. Empty Species geocodes every species.
+type GeocodeRangesRequest struct {
+	Species []string `json:"species,omitempty"`
+}
+
+// ReviewRangeLocalityRequest is the optional body for accepting a range
+// locality with corrected coordinates.
+type ReviewRangeLocalityRequest struct {
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	Region    string   `json:"region,omitempty"`
+}
+
+// registerGeocodeJob registers JobKindGeocodeRanges
+func (s *Server) registerGeocodeJob() {
+	s.jobs.Register(JobKindGeocodeRanges, func(ctx context.Context, job *db.Job, log *jobs.Log) error {
+		var req GeocodeRangesRequest
+		if len(job.Payload) > 0 {
+			if err := json.Unmarshal(job.Payload, &req); err != nil {
+				return fmt.Errorf("invalid payload: %w", err)
+			}
+		}
+		report, err := s.db.GeocodeRanges(ctx, s.geocoder, req.Species, log.Printf)
+		if err != nil {
+			return err
+		}
+		log.Printf("%d localities: %d queued for review, %d not found, %d provider lookups, %d stale removed",
+			report.Localities, report.Added, report.Unresolved, report.Lookups, report.Removed)
+		return nil
+	})
+}
+
+// handleListSpeciesLocalities handles GET /api/v1/species/{name}/localities
+// Returns the geocoded places in the species' ranges, rejected ones
+// excluded. Pending ones are machine-derived and not yet reviewed.
+func (s *Server) handleListSpeciesLocalities(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+
+	visible, err := s.speciesVisible(r, name)
+	if err != nil {
+		s.logger.Error("failed to check species existence", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !visible {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+
+	all, err := s.db.ListRangeLocalities("", name)
+	if err != nil {
+		s.logger.Error("failed to list range localities", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	withheld, err := s.withheldLocations(r, name)
+	if err != nil {
+		s.logger.Error("failed to get internal fields", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	generalize, err := s.generalization(r, name)
+	if err != nil {
+		s.logger.Error("failed to get conservation status", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	localities := []*db.RangeLocality{}
+	for _, l := range all {
+		if l.Status == db.SuggestionRejected || withheld[models.TextLocation(l.SourceID, "range")] {
+			continue
+		}
+		if generalize != nil {
+			l.Latitude, l.Longitude = generalize.Point(l.Latitude, l.Longitude)
+			l.Generalized = generalize.Grid
+		}
+		localities = append(localities, l)
+	}
+
+	RespondJSON(w, http.StatusOK, localities)
+}
+
+// generalization returns how to coarsen a species' localities for r, or nil
+// to show them as stored: for curators, and for species not threatened
+// enough to be generalized.
+func (s *Server) generalization(r *http.Request, name string) (*rangemap.Generalization, error) {
+	if s.generalize.MinStatus == "" || s.isAuthenticated(r) {
+		return nil, nil
+	}
+	entry, err := s.db.GetOakEntry(name)
+	if err != nil || entry == nil || !s.generalize.Applies(entry.ConservationStatus) {
+		return nil, err
+	}
+	return &s.generalize, nil
+}
+
+// handleListRangeLocalities handles GET /api/v1/range-localities
+// Lists localities awaiting review; ?status=accepted|rejected|all and ?species= filter.
+func (s *Server) handleListRangeLocalities(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = db.SuggestionPending
+	case "all":
+		status = ""
+	case db.SuggestionPending, db.SuggestionAccepted, db.SuggestionRejected:
+	default:
+		RespondValidationError(w, []ValidationError{{
+			Field:   "status",
+			Message: "status must be pending, accepted, rejected, or all",
+		}})
+		return
+	}
+
+	localities, err := s.db.ListRangeLocalities(status, r.URL.Query().Get("species"))
+	if err != nil {
+		s.logger.Error("failed to list range localities", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if localities == nil {
+		localities = []*db.RangeLocality{}
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(localities, len(localities), len(localities), 0))
+}
+
+// handleGeocodeRanges handles POST /api/v1/range-localities/geocode
+// Queues a geocode-ranges job and returns 202 with it. Fails with 400 when
+// no geocoder is configured.
+func (s *Server) handleGeocodeRanges(w http.ResponseWriter, r *http.Request) {
+	var req GeocodeRangesRequest
+	if r.ContentLength != 0 {
+		if err := decodeRequestBody(r, &req); err != nil {
+			RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+			return
+		}
+	}
+	if s.geocoder == nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "geocoding is off; set "+geocode.EnvGeocoder+" on the server to enable it")
+		return
+	}
+
+	var errors []ValidationError
+	for i, name := range req.Species {
+		exists, err := s.db.OakEntryExists(name)
+		if err != nil {
+			s.logger.Error("failed to check species existence", "name", name, "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+		if !exists {
+			errors = append(errors, ValidationError{Field: fmt.Sprintf("species[%d]", i), Message: "species not found: " + name})
+		}
+	}
+	if len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+
+	job, err := s.jobs.Enqueue(JobKindGeocodeRanges, req, 0)
+	if err != nil {
+		s.logger.Error("failed to enqueue geocoding job", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusAccepted, job)
+}
+
+// handleAcceptRangeLocality handles POST /api/v1/range-localities/{id}/accept
+// A body with latitude and longitude corrects the coordinates as it accepts them.
+func (s *Server) handleAcceptRangeLocality(w http.ResponseWriter, r *http.Request) {
+	var req ReviewRangeLocalityRequest
+	if r.ContentLength != 0 {
+		if err := decodeRequestBody(r, &req); err != nil {
+			RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+			return
+		}
+	}
+
+	var corrected *geocode.Place
+	if req.Latitude != nil || req.Longitude != nil {
+		var errors []ValidationError
+		if req.Latitude == nil || *req.Latitude < -90 || *req.Latitude > 90 {
+			errors = append(errors, ValidationError{Field: "latitude", Message: "must be between -90 and 90"})
+		}
+		if req.Longitude == nil || *req.Longitude < -180 || *req.Longitude > 180 {
+			errors = append(errors, ValidationError{Field: "longitude", Message: "must be between -180 and 180"})
+		}
+		if len(errors) > 0 {
+			RespondValidationError(w, errors)
+			return
+		}
+		corrected = &geocode.Place{Latitude: *req.Latitude, Longitude: *req.Longitude, Region: req.Region}
+	}
+	s.reviewRangeLocality(w, r, true, corrected)
+}
+
+// handleRejectRangeLocality handles POST /api/v1/range-localities/{id}/reject
+func (s *Server) handleRejectRangeLocality(w http.ResponseWriter, r *http.Request) {
+	s.reviewRangeLocality(w, r, false, nil)
+}
+
+// reviewRangeLocality accepts or rejects a pending locality and responds with it
+func (s *Server) reviewRangeLocality(w http.ResponseWriter, r *http.Request, accept bool, corrected *geocode.Place) {
+	idParam := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "Invalid locality ID")
+		return
+	}
+
+	locality, err := s.db.GetRangeLocality(id)
+	if err != nil {
+		s.logger.Error("failed to get range locality", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if locality == nil {
+		RespondNotFound(w, "Range locality", idParam)
+		return
+	}
+	if locality.Status != db.SuggestionPending {
+		RespondConflict(w, fmt.Sprintf("range locality %d is already %s", id, locality.Status))
+		return
+	}
+
+	ok, err := s.db.ReviewRangeLocality(id, accept, corrected)
+	if err != nil {
+		s.logger.Error("failed to review range locality", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !ok {
+		RespondConflict(w, fmt.Sprintf("range locality %d was reviewed concurrently", id))
+		return
+	}
+
+	if locality, err = s.db.GetRangeLocality(id); err != nil {
+		s.logger.Error("failed to get range locality", "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	RespondJSON(w, http.StatusOK, locality)
+}
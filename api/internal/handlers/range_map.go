@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/api/internal/rangemap"
+)
+
+// handleSpeciesRangeGeoJSON handles GET /api/v1/species/{name}/range.geojson
+// Returns a GeoJSON FeatureCollection of the species' geocoded range
+// localities and their outline, for the web map.
+// Query parameters:
+//   - detail: full (default), medium, or low; lower levels round and simplify
+//     for small-scale maps, and low drops the locality points
+//   - reviewed: true to leave out pending, machine-derived localities
+func (s *Server) handleSpeciesRangeGeoJSON(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+
+	var errors []ValidationError
+	detail := rangemap.Full
+	if v := r.URL.Query().Get("detail"); v != "" {
+		if detail, ok = rangemap.ParseDetail(v); !ok {
+			errors = append(errors, ValidationError{Field: "detail", Message: "detail must be full, medium, or low"})
+		}
+	}
+	reviewedOnly := false
+	if v := r.URL.Query().Get("reviewed"); v != "" {
+		var err error
+		if reviewedOnly, err = strconv.ParseBool(v); err != nil {
+			errors = append(errors, ValidationError{Field: "reviewed", Message: "reviewed must be true or false"})
+		}
+	}
+	if len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+
+	visible, err := s.speciesVisible(r, name)
+	if err != nil {
+		s.logger.Error("failed to check species existence", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !visible {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+
+	localities, err := s.db.ListRangeLocalities("", name)
+	if err != nil {
+		s.logger.Error("failed to list range localities", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	withheld, err := s.withheldLocations(r, name)
+	if err != nil {
+		s.logger.Error("failed to get internal fields", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	generalize, err := s.generalization(r, name)
+	if err != nil {
+		s.logger.Error("failed to get conservation status", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	var points []rangemap.Point
+	for _, l := range localities {
+		if l.Status == db.SuggestionRejected || (reviewedOnly && l.Status != db.SuggestionAccepted) ||
+			withheld[models.TextLocation(l.SourceID, "range")] {
+			continue
+		}
+		props := map[string]interface{}{
+			"id":              l.ID,
+			"locality":        l.Locality,
+			"source_id":       l.SourceID,
+			"status":          l.Status,
+			"machine_derived": l.MachineDerived,
+		}
+		if l.Region != nil {
+			props["region"] = *l.Region
+		}
+		latitude, longitude := l.Latitude, l.Longitude
+		if generalize != nil {
+			latitude, longitude = generalize.Point(latitude, longitude)
+			props["generalized"] = generalize.Grid
+		}
+		points = append(points, rangemap.Point{Longitude: longitude, Latitude: latitude, Properties: props})
+	}
+
+	data, err := json.Marshal(rangemap.Build(points, detail, map[string]interface{}{"scientific_name": name}))
+	if err != nil {
+		s.logger.Error("failed to encode range map", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	hash := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.Header().Set("ETag", etag)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// keyLimiter is a token bucket per API key. Each key's bucket holds up to
+// its burst and refills at rate tokens per second; a request spends one.
+// Trusted keys get a larger burst at the same rate.
+type keyLimiter struct {
+	rate         float64
+	burst        float64
+	trustedBurst float64
+	trusted      map[string]bool
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newKeyLimiter returns a limiter for config's per-key settings, or nil
+// when KeyBurst is zero
+func newKeyLimiter(config RateLimitConfig) *keyLimiter {
+	if config.KeyBurst <= 0 || config.KeyRate <= 0 {
+		return nil
+	}
+	l := &keyLimiter{
+		rate:         config.KeyRate,
+		burst:        float64(config.KeyBurst),
+		trustedBurst: float64(max(config.TrustedKeyBurst, config.KeyBurst)),
+		trusted:      make(map[string]bool, len(config.TrustedKeys)),
+		buckets:      make(map[string]*tokenBucket),
+		now:          time.Now,
+	}
+	for _, id := range config.TrustedKeys {
+		l.trusted[id] = true
+	}
+	return l
+}
+
+// allow spends a token from the key's bucket, reporting whether there was
+// one, the bucket's size and whole tokens left, and when refused, how long
+// until the next token
+func (l *keyLimiter) allow(keyID string) (ok bool, limit, remaining int, retryAfter time.Duration) {
+	size := l.burst
+	if l.trusted[keyID] {
+		size = l.trustedBurst
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, found := l.buckets[keyID]
+	if !found {
+		b = &tokenBucket{tokens: size, last: now}
+		l.buckets[keyID] = b
+	}
+	b.tokens = math.Min(size, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+		return false, int(size), 0, wait
+	}
+	b.tokens--
+	return true, int(size), int(b.tokens), 0
+}
+
+// serve passes the request to next if the key has a token to spend,
+// otherwise responds 429 with Retry-After
+func (l *keyLimiter) serve(w http.ResponseWriter, r *http.Request, next http.Handler, keyID string) {
+	ok, limit, remaining, retryAfter := l.allow(keyID)
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	if !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		RespondRateLimited(w)
+		return
+	}
+	next.ServeHTTP(w, r)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestKeyLimiter(t *testing.T) {
+	l := newKeyLimiter(RateLimitConfig{KeyRate: 2, KeyBurst: 3, TrustedKeyBurst: 10, TrustedKeys: []string{"trusted"}})
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	for i := range 3 {
+		if ok, limit, remaining, _ := l.allow("key"); !ok || limit != 3 || remaining != 2-i {
+			t.Fatalf("request %d: ok = %v, limit = %d, remaining = %d", i+1, ok, limit, remaining)
+		}
+	}
+	ok, _, _, retryAfter := l.allow("key")
+	if ok || retryAfter != 500*time.Millisecond {
+		t.Errorf("past the burst: ok = %v, retryAfter = %v; want refused for 500ms", ok, retryAfter)
+	}
+
+	// Tokens refill at the rate, up to the burst
+	now = now.Add(time.Second)
+	for i := range 2 {
+		if ok, _, _, _ := l.allow("key"); !ok {
+			t.Errorf("request %d after a second refused", i+1)
+		}
+	}
+	if ok, _, _, _ := l.allow("key"); ok {
+		t.Error("third request after a second allowed; want 2 tokens refilled")
+	}
+	now = now.Add(time.Hour)
+	if _, _, remaining, _ := l.allow("key"); remaining != 2 {
+		t.Errorf("remaining after an hour = %d, want the burst less one", remaining)
+	}
+
+	// Each key has its own bucket; trusted keys a larger one
+	if _, limit, remaining, _ := l.allow("trusted"); limit != 10 || remaining != 9 {
+		t.Errorf("trusted key: limit = %d, remaining = %d; want 10, 9", limit, remaining)
+	}
+
+	if newKeyLimiter(RateLimitConfig{KeyRate: 1}) != nil {
+		t.Error("zero KeyBurst built a limiter")
+	}
+}
+
+func TestRateLimitByKey(t *testing.T) {
+	config := RateLimitConfig{ReadLimit: 1, WriteLimit: 1, BackupLimit: 1, Window: time.Minute, BackupWindow: time.Minute,
+		KeyRate: 1, KeyBurst: 5}
+	keyID := func(r *http.Request) string { return r.Header.Get("X-Test-Key") }
+	handler := conditionalRateLimitMiddleware(config, keyID)(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }))
+
+	get := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/species", nil)
+		if key != "" {
+			req.Header.Set("X-Test-Key", key)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// Anonymous reads keep the per-IP limit
+	if w := get(""); w.Code != http.StatusOK {
+		t.Fatalf("first anonymous status = %d", w.Code)
+	}
+	if w := get(""); w.Code != http.StatusTooManyRequests {
+		t.Errorf("second anonymous status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+
+	// A key from the same IP gets its burst
+	for i := range 5 {
+		w := get("abc")
+		if w.Code != http.StatusOK {
+			t.Fatalf("keyed request %d status = %d", i+1, w.Code)
+		}
+		if w.Header().Get("X-RateLimit-Limit") != "5" {
+			t.Errorf("X-RateLimit-Limit = %q, want 5", w.Header().Get("X-RateLimit-Limit"))
+		}
+	}
+	w := get("abc")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" {
+		t.Errorf("past the burst: status = %d, Retry-After = %q; want 429 after 1s", w.Code, w.Header().Get("Retry-After"))
+	}
+}
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// ReportRequest is the request body for creating or replacing a report,
+// in JSON or YAML.
+type ReportRequest struct {
+	Title       string                `json:"title" yaml:"title"`
+	Description *string               `json:"description,omitempty" yaml:"description,omitempty"`
+	Query       string                `json:"query" yaml:"query"`
+	Columns     []models.ReportColumn `json:"columns,omitempty" yaml:"columns,omitempty"`
+	Format      string                `json:"format,omitempty" yaml:"format,omitempty"` // Defaults to csv
+}
+
+// ReportRunResponse is a report's rows, returned by run with ?format=json.
+type ReportRunResponse struct {
+	Report string `json:"report"`
+	*db.QueryResult
+}
+
+// reportNamePattern matches report names such as "species-by-section".
+var reportNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// handleListReports handles GET /api/v1/reports
+func (s *Server) handleListReports(w http.ResponseWriter, r *http.Request) {
+	reports, err := s.db.ListReports()
+	if err != nil {
+		s.logger.Error("failed to list reports", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if reports == nil {
+		reports = []*models.Report{}
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(reports, len(reports), len(reports), 0))
+}
+
+// handleGetReport handles GET /api/v1/reports/{name}
+func (s *Server) handleGetReport(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(chi.URLParam(r, "name"))
+
+	report, err := s.db.GetReport(name)
+	if err != nil {
+		s.logger.Error("failed to get report", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if report == nil {
+		RespondNotFound(w, "Report", name)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, report)
+}
+
+// handlePutReport handles PUT /api/v1/reports/{name}
+// Creates the report if it does not exist (201) or replaces it (200). The
+// query is run once, for a single row, so SQL that is not read-only, does
+// not parse, or lacks a listed column is refused when saved.
+func (s *Server) handlePutReport(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(chi.URLParam(r, "name"))
+
+	var req ReportRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+	if req.Format == "" {
+		req.Format = models.ReportFormatCSV
+	}
+
+	var errs []ValidationError
+	if !reportNamePattern.MatchString(name) {
+		errs = append(errs, ValidationError{Field: "name", Message: "must be lowercase letters, digits, '-' or '_' (e.g. species-by-section)"})
+	}
+	if strings.TrimSpace(req.Title) == "" {
+		errs = append(errs, ValidationError{Field: "title", Message: "title is required"})
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		errs = append(errs, ValidationError{Field: "query", Message: "query is required"})
+	}
+	if req.Format != models.ReportFormatCSV && req.Format != models.ReportFormatJSON {
+		errs = append(errs, ValidationError{Field: "format", Message: "format must be csv or json"})
+	}
+	seen := make(map[string]bool)
+	for _, c := range req.Columns {
+		switch {
+		case c.Name == "":
+			errs = append(errs, ValidationError{Field: "columns", Message: "every column needs a name"})
+		case seen[c.Name]:
+			errs = append(errs, ValidationError{Field: "columns", Message: fmt.Sprintf("column %q is listed twice", c.Name)})
+		}
+		seen[c.Name] = true
+	}
+	if len(errs) > 0 {
+		RespondValidationError(w, errs)
+		return
+	}
+
+	report := &models.Report{
+		Name:        name,
+		Title:       req.Title,
+		Description: req.Description,
+		Query:       req.Query,
+		Columns:     req.Columns,
+		Format:      req.Format,
+	}
+	if report.Columns == nil {
+		report.Columns = []models.ReportColumn{}
+	}
+	_, err := s.db.RunReport(r.Context(), report, 1, db.DefaultQueryTimeout)
+	var qerr *db.QueryError
+	if errors.As(err, &qerr) {
+		RespondValidationError(w, []ValidationError{{Field: "query", Message: qerr.Error()}})
+		return
+	}
+	if err != nil {
+		s.logger.Error("failed to check report query", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	existing, err := s.db.GetReport(name)
+	if err != nil {
+		s.logger.Error("failed to check report existence", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if err := s.db.SaveReport(report); err != nil {
+		s.logger.Error("failed to save report", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	status := http.StatusCreated
+	if existing != nil {
+		status = http.StatusOK
+	}
+	RespondJSON(w, status, report)
+}
+
+// handleDeleteReport handles DELETE /api/v1/reports/{name}
+func (s *Server) handleDeleteReport(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(chi.URLParam(r, "name"))
+
+	found, err := s.db.DeleteReport(name)
+	if err != nil {
+		s.logger.Error("failed to delete report", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !found {
+		RespondNotFound(w, "Report", name)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRunReport handles GET /api/v1/reports/{name}/run
+// ?format=csv|json overrides the report's format; ?limit= caps the rows
+// (default and most 10000). Truncated CSV is marked with an
+// X-Report-Truncated: true header.
+func (s *Server) handleRunReport(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(chi.URLParam(r, "name"))
+
+	report, err := s.db.GetReport(name)
+	if err != nil {
+		s.logger.Error("failed to get report", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if report == nil {
+		RespondNotFound(w, "Report", name)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = report.Format
+	}
+	var errs []ValidationError
+	if format != models.ReportFormatCSV && format != models.ReportFormatJSON {
+		errs = append(errs, ValidationError{Field: "format", Message: "format must be csv or json"})
+	}
+	limit := db.MaxQueryRowLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > db.MaxQueryRowLimit {
+			errs = append(errs, ValidationError{Field: "limit", Message: "limit must be between 1 and " + strconv.Itoa(db.MaxQueryRowLimit)})
+		}
+		limit = n
+	}
+	if len(errs) > 0 {
+		RespondValidationError(w, errs)
+		return
+	}
+
+	result, err := s.db.RunReport(r.Context(), report, limit, db.DefaultQueryTimeout)
+	var qerr *db.QueryError
+	if errors.As(err, &qerr) {
+		// A timeout, or a schema change since the report was saved and checked
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "report "+name+" failed: "+qerr.Error())
+		return
+	}
+	if err != nil {
+		s.logger.Error("failed to run report", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	if format == models.ReportFormatJSON {
+		RespondJSON(w, http.StatusOK, ReportRunResponse{Report: name, QueryResult: result})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`.csv"`)
+	if result.Truncated {
+		w.Header().Set("X-Report-Truncated", "true")
+	}
+	w.WriteHeader(http.StatusOK)
+	cw := csv.NewWriter(w)
+	_ = cw.Write(result.Columns)
+	record := make([]string, len(result.Columns))
+	for _, row := range result.Rows {
+		for i, v := range row {
+			record[i] = csvValue(v)
+		}
+		_ = cw.Write(record)
+	}
+	cw.Flush()
+}
+
+// csvValue formats a query result value for CSV; NULL is empty.
+func csvValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlContentTypes are request media types decoded as YAML
+var yamlContentTypes = map[string]bool{
+	"application/yaml":   true,
+	"application/x-yaml": true,
+	"text/yaml":          true,
+	"text/x-yaml":        true,
+}
+
+// isYAMLRequest reports whether the request body is declared as YAML
+func isYAMLRequest(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && yamlContentTypes[mediaType]
+}
+
+// decodeRequestBody decodes a JSON or YAML request body into v, based on the
+// Content-Type header. YAML is converted to JSON first so the request structs'
+// json tags apply to both formats.
+func decodeRequestBody(r *http.Request, v interface{}) error {
+	if isYAMLRequest(r) {
+		return decodeYAML(r.Body, v)
+	}
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// invalidBodyMessage is the error message for a request body that failed to decode
+func invalidBodyMessage(r *http.Request) string {
+	if isYAMLRequest(r) {
+		return "invalid YAML body"
+	}
+	return "invalid JSON body"
+}
+
+// decodeYAML decodes a single YAML document into v via its JSON representation
+func decodeYAML(body io.Reader, v interface{}) error {
+	var doc interface{}
+	if err := yaml.NewDecoder(body).Decode(&doc); err != nil {
+		return err
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
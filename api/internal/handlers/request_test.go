@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestYAMLRequestBodies(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	post := func(path, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/api/v1/taxa", "application/yaml", "name: Quercus\nlevel: subgenus\n")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("taxon status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	w = post("/api/v1/sources", "application/x-yaml; charset=utf-8", "source_type: Book\nname: Oaks of the World\nyear: 2020\n")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("source status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	w = post("/api/v1/species", "text/yaml", `scientific_name: alba
+author: L.
+subgenus: Quercus
+synonyms:
+  - candida
+`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("species status = %d, want %d. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	var entry models.OakEntry
+	if err := json.NewDecoder(w.Body).Decode(&entry); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if entry.Author == nil || *entry.Author != "L." {
+		t.Errorf("author = %v, want L.", entry.Author)
+	}
+	if len(entry.Synonyms) != 1 || entry.Synonyms[0] != "candida" {
+		t.Errorf("synonyms = %v, want [candida]", entry.Synonyms)
+	}
+}
+
+func TestYAMLRequestBodyInvalid(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/species", strings.NewReader("scientific_name: [unclosed\n"))
+	req.Header.Set("Content-Type", "application/yaml")
+	req.Header.Set("Authorization", "Bearer test-api-key")
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if !strings.Contains(w.Body.String(), "invalid YAML body") {
+		t.Errorf("body = %s, want invalid YAML body message", w.Body.String())
+	}
+}
+
+func TestInvalidSourceBodyMessage(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	tests := []struct {
+		contentType string
+		want        string
+	}{
+		{"application/json", `"Invalid JSON body"`},
+		{"application/yaml", `"Invalid YAML body"`},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/sources", strings.NewReader("{[unclosed\n"))
+		req.Header.Set("Content-Type", tt.contentType)
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want %d", tt.contentType, w.Code, http.StatusBadRequest)
+		}
+		if !strings.Contains(w.Body.String(), tt.want) {
+			t.Errorf("%s: body = %s, want %s", tt.contentType, w.Body.String(), tt.want)
+		}
+	}
+}
@@ -4,6 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jeff/oaks/pkg/apierror"
 )
 
 // Pagination contains pagination metadata for list responses.
@@ -34,6 +39,36 @@ func NewListResponse[T any](data []T, total, limit, offset int) ListResponse[T]
 	}
 }
 
+// parsePageParams reads ?limit and ?offset. A missing limit is def (0 means
+// no limit); limits above maxLimit are capped.
+func parsePageParams(query url.Values, def int) (limit, offset int, errors []ValidationError) {
+	limit = def
+	if limitStr := query.Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 {
+			errors = append(errors, ValidationError{
+				Field:   "limit",
+				Message: "must be a positive integer",
+			})
+		} else {
+			limit = min(parsed, maxLimit)
+		}
+	}
+
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		parsed, err := strconv.Atoi(offsetStr)
+		if err != nil || parsed < 0 {
+			errors = append(errors, ValidationError{
+				Field:   "offset",
+				Message: "must be a non-negative integer",
+			})
+		} else {
+			offset = parsed
+		}
+	}
+	return limit, offset, errors
+}
+
 // RespondJSON writes a JSON response with the given status code and data.
 func RespondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -56,22 +91,26 @@ func RespondError(w http.ResponseWriter, status int, code, message string) {
 	RespondJSON(w, status, resp)
 }
 
-// RespondValidationError writes a validation error response with field-level errors.
+// RespondValidationError writes a validation error response with field-level
+// errors. A single error also names its field at the top level.
 func RespondValidationError(w http.ResponseWriter, errors []ValidationError) {
-	resp := ErrorResponse{
-		Error: NewAPIErrorWithDetails(
-			ErrCodeValidation,
-			"Validation failed",
-			ValidationErrors{Errors: errors},
-		),
+	apiErr := NewAPIErrorWithDetails(
+		apierror.CodeValidation,
+		"Validation failed",
+		ValidationErrors{Errors: errors},
+	)
+	if len(errors) == 1 {
+		apiErr.Field = errors[0].Field
 	}
-	RespondJSON(w, http.StatusBadRequest, resp)
+	RespondJSON(w, http.StatusBadRequest, ErrorResponse{Error: apiErr})
 }
 
 // RespondNotFound writes a not found error response for the given resource and ID.
 func RespondNotFound(w http.ResponseWriter, resource, id string) {
-	message := fmt.Sprintf("%s '%s' not found", resource, id)
-	RespondError(w, http.StatusNotFound, ErrCodeNotFound, message)
+	apiErr := NewAPIError(apierror.CodeNotFound, fmt.Sprintf("%s '%s' not found", resource, id))
+	apiErr.Entity = strings.ToLower(resource)
+	apiErr.ID = id
+	RespondJSON(w, http.StatusNotFound, ErrorResponse{Error: apiErr})
 }
 
 // RespondUnauthorized writes an unauthorized error response.
@@ -79,17 +118,41 @@ func RespondUnauthorized(w http.ResponseWriter, message string) {
 	if message == "" {
 		message = "Authentication required"
 	}
-	RespondError(w, http.StatusUnauthorized, ErrCodeUnauthorized, message)
+	RespondError(w, http.StatusUnauthorized, apierror.CodeUnauthorized, message)
+}
+
+// RespondForbidden writes a forbidden error response.
+func RespondForbidden(w http.ResponseWriter, message string) {
+	RespondError(w, http.StatusForbidden, apierror.CodeForbidden, message)
 }
 
 // RespondConflict writes a conflict error response.
 func RespondConflict(w http.ResponseWriter, message string) {
-	RespondError(w, http.StatusConflict, ErrCodeConflict, message)
+	RespondError(w, http.StatusConflict, apierror.CodeConflict, message)
+}
+
+// RespondPreconditionFailed writes a precondition failed error response.
+func RespondPreconditionFailed(w http.ResponseWriter, message string) {
+	RespondError(w, http.StatusPreconditionFailed, apierror.CodePreconditionFailed, message)
 }
 
 // RespondRateLimited writes a rate limited error response.
 func RespondRateLimited(w http.ResponseWriter) {
-	RespondError(w, http.StatusTooManyRequests, ErrCodeRateLimited, "Rate limit exceeded")
+	RespondError(w, http.StatusTooManyRequests, apierror.CodeRateLimited, "Rate limit exceeded")
+}
+
+// RespondUpgradeRequired writes a 426 response telling an outdated client how to upgrade.
+func RespondUpgradeRequired(w http.ResponseWriter, clientVersion, minClient string) {
+	message := fmt.Sprintf(
+		"Client version %s is no longer supported (requires >= %s). Run: go install github.com/jeff/oaks/cli@latest",
+		clientVersion, minClient,
+	)
+	RespondError(w, http.StatusUpgradeRequired, apierror.CodeClientTooOld, message)
+}
+
+// RespondMaintenance writes a 503 response for a write refused during maintenance.
+func RespondMaintenance(w http.ResponseWriter, message string) {
+	RespondError(w, http.StatusServiceUnavailable, apierror.CodeMaintenance, message)
 }
 
 // RespondInternalError writes an internal server error response.
@@ -98,7 +161,7 @@ func RespondInternalError(w http.ResponseWriter, message string) {
 	if message == "" {
 		message = "An internal error occurred"
 	}
-	RespondError(w, http.StatusInternalServerError, ErrCodeInternal, message)
+	RespondError(w, http.StatusInternalServerError, apierror.CodeInternal, message)
 }
 
 // CascadeConflictDetails contains details about blocking references
@@ -117,7 +180,7 @@ func RespondCascadeConflict(w http.ResponseWriter, blockingHybrids []string) {
 
 	resp := ErrorResponse{
 		Error: NewAPIErrorWithDetails(
-			ErrCodeConflict,
+			apierror.CodeConflict,
 			message,
 			CascadeConflictDetails{BlockingHybrids: blockingHybrids},
 		),
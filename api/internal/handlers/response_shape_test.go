@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// findNulls returns the JSON paths of every null in v, except for fields
+// named in nullable
+func findNulls(v interface{}, path string, nullable map[string]bool) []string {
+	switch x := v.(type) {
+	case nil:
+		if nullable[path[strings.LastIndex(path, ".")+1:]] {
+			return nil
+		}
+		return []string{path}
+	case map[string]interface{}:
+		var nulls []string
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			nulls = append(nulls, findNulls(x[k], path+"."+k, nullable)...)
+		}
+		return nulls
+	case []interface{}:
+		var nulls []string
+		for i, e := range x {
+			nulls = append(nulls, findNulls(e, fmt.Sprintf("%s[%d]", path, i), nullable)...)
+		}
+		return nulls
+	}
+	return nil
+}
+
+// v2Nullable lists the v2 fields that are explicitly null when unset
+var v2Nullable = map[string]bool{
+	"author": true, "pronunciation": true, "conservation_status": true,
+	"subgenus": true, "section": true, "subsection": true, "complex": true,
+	"next_cursor": true,
+}
+
+// TestResponseShapes checks the response contract: list fields are [] when
+// empty, and optional fields are omitted when unset (v1) or null (v2 only).
+func TestResponseShapes(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	send := func(method, path string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer test-api-key")
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	// Bare-minimum records, so every optional field is unset
+	for _, setup := range []struct{ method, path, body string }{
+		{http.MethodPost, "/api/v1/species", `{"scientific_name":"alba"}`},
+		{http.MethodPost, "/api/v1/species", `{"scientific_name":"× bebbiana","is_hybrid":true,"parent1":"alba"}`},
+		{http.MethodPost, "/api/v1/sources", `{"source_type":"website","name":"Scraped"}`},
+		{http.MethodPost, "/api/v1/species/alba/sources", `{"source_id":1}`},
+		{http.MethodPost, "/api/v1/taxa", `{"name":"Quercus","level":"subgenus"}`},
+		{http.MethodPut, "/api/v1/authors", `[{"abbreviation":"L.","full_name":"Linnaeus"}]`},
+		{http.MethodPost, "/api/v1/import/sessions", ``},
+	} {
+		if w := send(setup.method, setup.path, setup.body); w.Code >= 300 {
+			t.Fatalf("setup %s %s = %d: %s", setup.method, setup.path, w.Code, w.Body.String())
+		}
+	}
+
+	paths := []string{
+		"/api/v1/species",
+		"/api/v1/species/alba",
+		"/api/v1/species/alba/full",
+		"/api/v1/species/%C3%97%20bebbiana/full",
+		"/api/v1/species/alba/sources",
+		"/api/v1/species/alba/sources/1",
+		"/api/v1/species/alba/mentions",
+		"/api/v1/species/alba/measurements",
+		"/api/v1/species/alba/revisions",
+		"/api/v1/species/alba/timeline",
+		"/api/v1/species/search?q=zzz",
+		"/api/v1/species/search?q=alba",
+		"/api/v1/search?q=zzz",
+		"/api/v1/search?q=alba",
+		"/api/v1/sources",
+		"/api/v1/sources/1",
+		"/api/v1/sources/1/coverage",
+		"/api/v1/sources/1/usage",
+		"/api/v1/taxa",
+		"/api/v1/taxa/subgenus/Quercus",
+		"/api/v1/taxon-levels",
+		"/api/v1/genera",
+		"/api/v1/genera/Quercus",
+		"/api/v1/authors",
+		"/api/v1/authors/L.",
+		"/api/v1/templates",
+		"/api/v1/schemas",
+		"/api/v1/export",
+		"/api/v1/export/mappings",
+		"/api/v1/attributions",
+		"/api/v1/stats",
+		"/api/v1/stats/popular",
+		"/api/v1/jobs",
+		"/api/v1/publications",
+		"/api/v1/feature-suggestions",
+		"/api/v1/import/sessions/1",
+		"/api/v1/admin/maintenance",
+		"/api/v1/admin/data-errors",
+		"/api/v1/admin/hybrids",
+		"/api/v1/admin/schema",
+		"/api/v1/health",
+		"/api/v2/species",
+		"/api/v2/species/alba",
+	}
+
+	for _, path := range paths {
+		w := send(http.MethodGet, path, "")
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s = %d, want 200: %s", path, w.Code, w.Body.String())
+			continue
+		}
+		if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+			continue
+		}
+		var body interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Errorf("GET %s: invalid JSON: %v", path, err)
+			continue
+		}
+		var nullable map[string]bool
+		if strings.HasPrefix(path, "/api/v2/") {
+			nullable = v2Nullable
+		}
+		if nulls := findNulls(body, "", nullable); len(nulls) > 0 {
+			t.Errorf("GET %s has null at %s", path, strings.Join(nulls, ", "))
+		}
+	}
+}
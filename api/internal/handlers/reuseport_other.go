@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package handlers
+
+import (
+	"errors"
+	"syscall"
+)
+
+// reusePortSupported reports whether WithReusePort works on this platform.
+const reusePortSupported = false
+
+// reusePortControl fails: SO_REUSEPORT is not available on this platform.
+func reusePortControl(network, address string, c syscall.RawConn) error {
+	return errors.New("SO_REUSEPORT is not supported on this platform")
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// RevisionsResponse lists a species' earlier versions, newest first
+type RevisionsResponse struct {
+	Revisions []*db.OakEntryRevision `json:"revisions"`
+}
+
+// handleListSpeciesRevisions handles GET /api/v1/species/{name}/revisions
+func (s *Server) handleListSpeciesRevisions(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+
+	revisions, err := s.db.ListOakEntryRevisions(name)
+	if err != nil {
+		s.logger.Error("failed to list species revisions", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	// A deleted species keeps its revisions so it can be restored
+	if len(revisions) == 0 {
+		exists, err := s.db.OakEntryExists(name)
+		if err != nil {
+			s.logger.Error("failed to check species existence", "name", name, "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+		if !exists {
+			RespondNotFound(w, "Species", name)
+			return
+		}
+	}
+
+	RespondJSON(w, http.StatusOK, RevisionsResponse{Revisions: revisions})
+}
+
+// handleRestoreSpeciesRevision handles
+// POST /api/v1/species/{name}/revisions/{id}/restore
+func (s *Server) handleRestoreSpeciesRevision(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "Invalid revision ID")
+		return
+	}
+
+	entry, err := s.db.RestoreOakEntryRevision(name, id)
+	if err != nil {
+		s.logger.Error("failed to restore species revision", "name", name, "id", id, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if entry == nil {
+		RespondNotFound(w, "Revision", strconv.FormatInt(id, 10))
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, entry)
+}
+
+// TimelineResponse lists what happened to a species, oldest first
+type TimelineResponse struct {
+	Events []*db.TimelineEvent `json:"events"`
+}
+
+// handleSpeciesTimeline handles GET /api/v1/species/{name}/timeline
+func (s *Server) handleSpeciesTimeline(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+
+	events, err := s.db.SpeciesTimeline(name)
+	if err != nil {
+		s.logger.Error("failed to get species timeline", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if events == nil {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, TimelineResponse{Events: events})
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jeff/oaks/pkg/apierror"
+	"github.com/jeff/oaks/pkg/migrate"
+)
+
+// SchemaMigrateRequest is the request body for POST /api/v1/admin/schema.
+// Version defaults to the latest.
+type SchemaMigrateRequest struct {
+	Version *int `json:"version"`
+}
+
+// SchemaMigrateResponse lists the migrations run, named as on disk
+// (0001_oak_entries_genus_index), in the order run.
+type SchemaMigrateResponse struct {
+	From     int      `json:"from"`
+	To       int      `json:"to"`
+	Applied  []string `json:"applied"`
+	Reverted []string `json:"reverted"`
+}
+
+// handleSchemaStatus handles GET /api/v1/admin/schema
+// Returns the schema version and every migration, applied or pending.
+func (s *Server) handleSchemaStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := s.db.SchemaStatus()
+	if err != nil {
+		s.logger.Error("failed to read schema status", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	RespondJSON(w, http.StatusOK, status)
+}
+
+// handleMigrateSchema handles POST /api/v1/admin/schema
+// Upgrades or reverts the schema to a version. The server applies pending
+// migrations when it starts, so this is mostly for reverting before going
+// back to an older build.
+func (s *Server) handleMigrateSchema(w http.ResponseWriter, r *http.Request) {
+	var req SchemaMigrateRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+	status, err := s.db.SchemaStatus()
+	if err != nil {
+		s.logger.Error("failed to read schema status", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	target := status.Latest
+	if req.Version != nil {
+		target = *req.Version
+	}
+	if target < 0 || target > status.Latest {
+		RespondValidationError(w, []ValidationError{{Field: "version", Message: fmt.Sprintf("version must be from 0 to %d", status.Latest)}})
+		return
+	}
+
+	migrations, err := s.db.MigrateSchema(target)
+	resp := SchemaMigrateResponse{From: status.Version, To: status.Version, Applied: []string{}, Reverted: []string{}}
+	for _, m := range migrations {
+		if target >= status.Version {
+			resp.Applied = append(resp.Applied, m.String())
+			resp.To = m.Version
+		} else {
+			resp.Reverted = append(resp.Reverted, m.String())
+			resp.To = m.Version - 1
+		}
+	}
+	if len(migrations) > 0 {
+		s.logger.Info("schema migrated", "from", resp.From, "to", resp.To, "applied", resp.Applied, "reverted", resp.Reverted)
+	}
+	switch {
+	case errors.Is(err, migrate.ErrIrreversible):
+		RespondError(w, http.StatusConflict, apierror.CodeConflict, err.Error())
+		return
+	case err != nil:
+		s.logger.Error("failed to migrate schema", "error", err, "from", resp.From, "to", resp.To)
+		RespondInternalError(w, "")
+		return
+	}
+	RespondJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect of the published schemas.
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// SchemaInfo describes one published JSON Schema.
+type SchemaInfo struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// schemaTitles lists the published schemas in display order.
+var schemaTitles = []SchemaInfo{
+	{Name: "oak-entry", Title: "Oak Entry"},
+	{Name: "species-source", Title: "Species Source"},
+	{Name: "source", Title: "Source"},
+	{Name: "taxon", Title: "Taxon"},
+}
+
+// schemaObject is a JSON Schema fragment.
+type schemaObject map[string]interface{}
+
+// nullable returns a schema for an optional scalar; the editors write empty
+// values as null.
+func nullable(typ, description string) schemaObject {
+	return schemaObject{"type": []string{typ, "null"}, "description": description}
+}
+
+// nullableEnum returns a schema for an optional value restricted to values.
+func nullableEnum(values []string, description string) schemaObject {
+	enum := make([]interface{}, 0, len(values)+1)
+	for _, v := range values {
+		enum = append(enum, v)
+	}
+	return schemaObject{"enum": append(enum, nil), "description": description}
+}
+
+// vocabularyEnum returns a schema for an optional value of a field bound to
+// a vocabulary: one of its terms, or any string when none is bound.
+func vocabularyEnum(terms []string, description string) schemaObject {
+	if terms == nil {
+		return nullable("string", description)
+	}
+	return nullableEnum(terms, description)
+}
+
+// stringList returns a schema for an optional list of strings.
+func stringList(description string) schemaObject {
+	return schemaObject{"type": []string{"array", "null"}, "items": schemaObject{"type": "string"}, "description": description}
+}
+
+// linkList returns a schema for a list of link objects with the given required keys.
+func linkList(description string, properties schemaObject, required []string) schemaObject {
+	return schemaObject{
+		"type":        []string{"array", "null"},
+		"description": description,
+		"items": schemaObject{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		},
+	}
+}
+
+// buildSchema builds the named schema. Enumerations that are configurable
+// (genera, taxon levels, vocabulary terms) are read from the database. Returns nil for an
+// unknown name.
+func (s *Server) buildSchema(name string) (schemaObject, error) {
+	var title string
+	for _, info := range schemaTitles {
+		if info.Name == name {
+			title = info.Title
+		}
+	}
+	if title == "" {
+		return nil, nil
+	}
+
+	schema := schemaObject{
+		"$schema":              jsonSchemaDraft,
+		"title":                title,
+		"type":                 "object",
+		"additionalProperties": false,
+	}
+
+	switch name {
+	case "oak-entry":
+		genera, err := s.db.ListGenera()
+		if err != nil {
+			return nil, err
+		}
+		genusNames := make([]string, len(genera))
+		for i, g := range genera {
+			genusNames[i] = g.Name
+		}
+		statuses, err := s.fieldTerms(models.VocabularyFieldConservationStatus)
+		if err != nil {
+			return nil, err
+		}
+
+		schema["description"] = "Species-intrinsic data for an oak entry, as edited by 'oak new' and 'oak edit'"
+		schema["required"] = []string{"scientific_name"}
+		schema["properties"] = schemaObject{
+			"scientific_name":      schemaObject{"type": "string", "minLength": 1, "description": "Species epithet (e.g. alba) or hybrid name (e.g. × bebbiana)"},
+			"author":               nullable("string", "Taxonomic authority (e.g. L. 1753)"),
+			"pronunciation":        nullable("string", "Pronunciation guide for the name (e.g. KWER-kus AL-buh)"),
+			"is_hybrid":            schemaObject{"type": "boolean"},
+			"conservation_status":  vocabularyEnum(statuses, "IUCN Red List category"),
+			"genus":                nullableEnum(genusNames, "Genus; defaults to "+models.DefaultGenus),
+			"subgenus":             nullable("string", "Subgenus name"),
+			"section":              nullable("string", "Section name"),
+			"subsection":           nullable("string", "Subsection name"),
+			"complex":              nullable("string", "Species complex name"),
+			"parent1":              nullable("string", "First parent (hybrids only)"),
+			"parent2":              nullable("string", "Second parent (hybrids only)"),
+			"hybrids":              stringList("Hybrids this species is a parent of"),
+			"closely_related_to":   stringList("Closely related species"),
+			"subspecies_varieties": stringList("Subspecies and varieties"),
+			"synonyms":             stringList("Synonyms"),
+			"external_links": linkList("External reference links", schemaObject{
+				"name": schemaObject{"type": "string"},
+				"url":  schemaObject{"type": "string", "format": "uri"},
+				"logo": nullableEnum(models.ExternalLinkLogos, "Bundled icon id"),
+			}, []string{"name", "url"}),
+			"visibility": nullableEnum([]string{models.VisibilityPublished, models.VisibilityDraft}, "Drafts are hidden from public reads"),
+		}
+
+	case "species-source":
+		schema["description"] = "What one source says about one species, as edited by 'oak note'. " +
+			"Range, leaves, bark, and the other text fields are edited as markdown sections below the front matter."
+		schema["properties"] = schemaObject{
+			"id":                      schemaObject{"type": "integer"},
+			"scientific_name":         schemaObject{"type": "string"},
+			"source_id":               schemaObject{"type": "integer"},
+			"species":                 schemaObject{"type": "string", "description": "Shown in the editor; not editable"},
+			"source":                  schemaObject{"type": "string", "description": "Shown in the editor; not editable"},
+			"local_names":             stringList("Common or local names"),
+			"range":                   nullable("string", "Geographic range"),
+			"growth_habit":            nullable("string", ""),
+			"leaves":                  nullable("string", ""),
+			"flowers":                 nullable("string", ""),
+			"fruits":                  nullable("string", ""),
+			"bark":                    nullable("string", ""),
+			"twigs":                   nullable("string", ""),
+			"buds":                    nullable("string", ""),
+			"hardiness_habitat":       nullable("string", ""),
+			"miscellaneous":           nullable("string", ""),
+			"distinguishing_features": nullable("string", "What sets the species apart from similar ones"),
+			"url":                     nullable("string", "Source page for this species"),
+			"pages":                   nullable("string", "Where the data is in a printed source, e.g. pp. 112-114"),
+			"field_pages":             schemaObject{"type": "object", "description": "Locators for text fields on other pages, keyed by field name", "additionalProperties": schemaObject{"type": "string"}},
+			"field_visibility":        schemaObject{"type": "object", "description": "Text fields withheld from the public, keyed by field name", "additionalProperties": schemaObject{"type": "string", "enum": models.FieldVisibilities}},
+			"is_preferred":            schemaObject{"type": "boolean", "description": "At most one preferred source per species"},
+		}
+
+	case "source":
+		sourceTypes, err := s.fieldTerms(models.VocabularyFieldSourceType)
+		if err != nil {
+			return nil, err
+		}
+		sourceType := schemaObject{"type": "string"}
+		if sourceTypes != nil {
+			sourceType["enum"] = sourceTypes
+		}
+
+		schema["description"] = "A data source, as edited by 'oak source new' and 'oak source edit'. " +
+			"Description and notes are edited as markdown sections below the front matter."
+		schema["required"] = []string{"source_type", "name"}
+		schema["properties"] = schemaObject{
+			"id":            schemaObject{"type": "integer"},
+			"source_type":   sourceType,
+			"name":          schemaObject{"type": "string", "minLength": 1},
+			"description":   nullable("string", ""),
+			"author":        nullable("string", ""),
+			"year":          nullable("integer", "Publication year"),
+			"url":           nullable("string", ""),
+			"isbn":          nullable("string", ""),
+			"doi":           nullable("string", ""),
+			"notes":         nullable("string", ""),
+			"license":       nullable("string", "e.g. CC-BY-4.0"),
+			"license_url":   nullable("string", ""),
+			"superseded_by": nullable("integer", "ID of the source that replaces this one"),
+		}
+
+	case "taxon":
+		levels, err := s.db.ListTaxonLevels()
+		if err != nil {
+			return nil, err
+		}
+		levelNames := make([]string, len(levels))
+		for i, l := range levels {
+			levelNames[i] = l.Name
+		}
+
+		schema["description"] = "A taxon in the reference hierarchy, as edited by 'oak taxa new' and 'oak taxa edit'. " +
+			"Notes are edited as a markdown section below the front matter."
+		schema["required"] = []string{"name", "level"}
+		schema["properties"] = schemaObject{
+			"name":          schemaObject{"type": "string", "minLength": 1},
+			"level":         schemaObject{"enum": levelNames, "description": "A configured taxon level"},
+			"genus":         nullable("string", "Genus; defaults to "+models.DefaultGenus),
+			"parent":        nullable("string", "Name of the parent taxon"),
+			"author":        nullable("string", "Taxonomic authority"),
+			"notes":         nullable("string", ""),
+			"species_count": schemaObject{"type": "integer", "description": "Read-only"},
+			"links": linkList("External reference links", schemaObject{
+				"label": schemaObject{"type": "string"},
+				"url":   schemaObject{"type": "string", "format": "uri"},
+			}, []string{"label", "url"}),
+		}
+	}
+
+	return schema, nil
+}
+
+// handleListSchemas handles GET /api/v1/schemas
+func (s *Server) handleListSchemas(w http.ResponseWriter, r *http.Request) {
+	data := make([]SchemaInfo, len(schemaTitles))
+	for i, info := range schemaTitles {
+		info.URL = "/api/v1/schemas/" + info.Name
+		data[i] = info
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(data, len(data), len(data), 0))
+}
+
+// handleGetSchema handles GET /api/v1/schemas/{name}
+func (s *Server) handleGetSchema(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	schema, err := s.buildSchema(name)
+	if err != nil {
+		s.logger.Error("failed to build schema", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if schema == nil {
+		RespondNotFound(w, "Schema", name)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, schema)
+}
@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/pkg/apierror"
 )
 
 // handleUnifiedSearch handles GET /api/v1/search?q=
@@ -10,7 +15,7 @@ import (
 func (s *Server) handleUnifiedSearch(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 	if query == "" {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "query parameter 'q' is required")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "query parameter 'q' is required")
 		return
 	}
 
@@ -22,7 +27,7 @@ func (s *Server) handleUnifiedSearch(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	results, err := s.db.UnifiedSearch(query, limit)
+	results, err := s.readDB(r).UnifiedSearch(query, limit, s.isAuthenticated(r))
 	if err != nil {
 		s.logger.Error("failed to perform unified search", "query", query, "error", err)
 		RespondInternalError(w, "")
@@ -31,3 +36,62 @@ func (s *Server) handleUnifiedSearch(w http.ResponseWriter, r *http.Request) {
 
 	RespondJSON(w, http.StatusOK, results)
 }
+
+// handleTextSearch handles GET /api/v1/search/text?q=
+// Finds the lines of species accounts and source text containing q, ignoring
+// case, diacritics, and spacing, each with where the matches are in it.
+func (s *Server) handleTextSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "query parameter 'q' is required")
+		return
+	}
+
+	limit := defaultLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 && parsed <= maxLimit {
+			limit = parsed
+		}
+	}
+
+	matches, total, err := s.readDB(r).SearchText(query, limit, s.isAuthenticated(r))
+	if err != nil {
+		s.logger.Error("failed to search text", "query", query, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(matches, total, limit, 0))
+}
+
+// handleFullTextSearch handles GET /api/v1/search/fulltext?q=
+// Ranks species source text fields and synonyms by relevance to q: every word
+// must appear, "quoted phrases" as written, and word* matches by prefix.
+// Fails with 400 when the server's SQLite has no FTS5.
+func (s *Server) handleFullTextSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "query parameter 'q' is required")
+		return
+	}
+
+	limit := defaultLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 && parsed <= maxLimit {
+			limit = parsed
+		}
+	}
+
+	matches, total, err := s.readDB(r).SearchFullText(query, limit, s.isAuthenticated(r))
+	if errors.Is(err, db.ErrFullTextUnavailable) {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "full-text search is off; the server's SQLite was built without FTS5")
+		return
+	}
+	if err != nil {
+		s.logger.Error("failed to search full text", "query", query, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(matches, total, limit, 0))
+}
@@ -2,13 +2,24 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/export"
+	"github.com/jeff/oaks/api/internal/geocode"
+	"github.com/jeff/oaks/api/internal/inaturalist"
+	"github.com/jeff/oaks/api/internal/media"
+	"github.com/jeff/oaks/api/internal/jobs"
+	"github.com/jeff/oaks/api/internal/notify"
+	"github.com/jeff/oaks/api/internal/rangemap"
 )
 
 // VersionInfo contains version information for the API.
@@ -27,6 +38,23 @@ type Server struct {
 	version          VersionInfo
 	middlewareConfig *MiddlewareConfig
 	skipMiddleware   bool
+	jobs             *jobs.Runner
+	notifier         *notify.Notifier
+	exportMappings   map[string]*export.Mapping
+	siteURL          string
+	analytics        bool
+	reusePort        bool
+	addr             string // Address the server is listening on, once Listen succeeds
+	maintenanceMu    sync.RWMutex
+	maintenance      MaintenanceStatus
+	exportCache      exportCache
+	geocoder         geocode.Provider
+	generalize       rangemap.Generalization
+	skipKeyUsage     bool
+	trustedKeys      []string
+	features         map[string]bool // Feature flags set by OAK_FEATURES; see flags.go
+	inaturalist      *inaturalist.Client
+	media            *media.Store // Uploaded photos; nil refuses uploads
 }
 
 // ServerOption is a functional option for configuring the server.
@@ -46,6 +74,75 @@ func WithoutMiddleware() ServerOption {
 	}
 }
 
+// WithNotifier sends alerts for server events through n.
+func WithNotifier(n *notify.Notifier) ServerOption {
+	return func(s *Server) {
+		s.notifier = n
+	}
+}
+
+// WithExportMappings makes named export mappings available as ?mapping= on the export.
+func WithExportMappings(mappings map[string]*export.Mapping) ServerOption {
+	return func(s *Server) {
+		s.exportMappings = mappings
+	}
+}
+
+// WithSiteURL sets the public website the sitemap links to (default DefaultSiteURL).
+func WithSiteURL(siteURL string) ServerOption {
+	return func(s *Server) {
+		s.siteURL = siteURL
+	}
+}
+
+// WithAnalytics counts public species page views per day for /stats/popular.
+func WithAnalytics() ServerOption {
+	return func(s *Server) {
+		s.analytics = true
+	}
+}
+
+// WithReusePort sets SO_REUSEPORT on the listening socket so several server
+// processes can share a port, with the kernel balancing connections between them.
+func WithReusePort() ServerOption {
+	return func(s *Server) {
+		s.reusePort = true
+	}
+}
+
+// WithGeocoder enables the geocode-ranges job, which looks range
+// localities up with p.
+func WithGeocoder(p geocode.Provider) ServerOption {
+	return func(s *Server) {
+		s.geocoder = p
+	}
+}
+
+// WithLocalityGeneralization coarsens the range localities of threatened
+// species in exports and in reads without an API key.
+func WithLocalityGeneralization(g rangemap.Generalization) ServerOption {
+	return func(s *Server) {
+		s.generalize = g
+	}
+}
+
+// WithTrustedKeys gives the API keys with these IDs (see APIKeyID) the
+// trusted burst in rate limiting, for clients such as the web app's
+// server-side renderer that make many requests at once.
+func WithTrustedKeys(ids []string) ServerOption {
+	return func(s *Server) {
+		s.trustedKeys = ids
+	}
+}
+
+// WithoutKeyUsage stops counting requests per API key, for servers whose
+// key is generated per session and would only clutter /admin/keys.
+func WithoutKeyUsage() ServerOption {
+	return func(s *Server) {
+		s.skipKeyUsage = true
+	}
+}
+
 // New creates a new API server with the given database, API key, logger, and version info.
 func New(database *db.Database, apiKey string, logger *slog.Logger, version VersionInfo, opts ...ServerOption) *Server {
 	if logger == nil {
@@ -58,12 +155,17 @@ func New(database *db.Database, apiKey string, logger *slog.Logger, version Vers
 		apiKey:  apiKey,
 		logger:  logger,
 		version: version,
+		siteURL: DefaultSiteURL,
 	}
+	s.jobs = jobs.NewRunner(database, logger)
+	s.inaturalist = inaturalist.New(inaturalist.DefaultURL, "oak-api/"+version.API)
 
 	// Apply options
 	for _, opt := range opts {
 		opt(s)
 	}
+	s.registerJobs()
+	s.jobs.OnFailed = s.notifyJobFailed
 
 	s.setupRoutes()
 	return s
@@ -80,20 +182,53 @@ func (s *Server) setupRoutes() {
 			defaultConfig := DefaultMiddlewareConfig(s.logger)
 			config = &defaultConfig
 		}
-		s.SetupMiddleware(*config)
+		c := *config
+		c.RateLimit.TrustedKeys = append(slices.Clone(c.RateLimit.TrustedKeys), s.trustedKeys...)
+		s.SetupMiddleware(c)
 	}
 
+	// Refuse writes while maintenance mode is on
+	r.Use(s.maintenanceGate)
+
+	// Count requests per API key for /admin/keys
+	if !s.skipKeyUsage {
+		r.Use(s.trackKeyUsage)
+	}
+
+	// HEAD runs the GET handler without a body; OPTIONS and unsupported
+	// methods are answered with an Allow header
+	r.Use(headRequests)
+	r.MethodNotAllowed(s.handleMethodNotAllowed)
+	r.NotFound(s.handleNotFound)
+
 	// Health check endpoints (no auth, rate limiting exempt via middleware)
 	r.Get("/health", s.handleHealth)
 	r.Get("/health/ready", s.handleHealthReady)
 
+	// Sitemap for the public site (no auth)
+	r.Get("/sitemap.xml", s.handleSitemap)
+
+	// Crawler rules for the API origin (no auth)
+	r.Get("/robots.txt", s.handleRobots)
+
 	// API v1 routes
 	r.Route("/api/v1", func(r chi.Router) {
+		// Reject CLI versions older than MinClient
+		r.Use(s.clientVersionMiddleware)
+
 		// Health endpoint also at /api/v1/health per spec
 		r.Get("/health", s.handleHealth)
 
-		// Unified search endpoint (public)
+		// Search endpoints (public): names across entities, and account and source text
 		r.Get("/search", s.handleUnifiedSearch)
+		r.Get("/search/text", s.handleTextSearch)
+		r.With(s.requireFeature(FlagFullTextSearch)).Get("/search/fulltext", s.handleFullTextSearch)
+
+		// Feature flags (public, so clients can adapt to what is enabled)
+		r.Get("/features", s.handleListFeatures)
+
+		// Uploaded photos and thumbnails (public; see WithMediaDir)
+		r.Get("/media/{file}", s.handleGetMediaFile)
 
 		// Auth verification endpoint (requires auth, read-only)
 		r.Group(func(r chi.Router) {
@@ -101,20 +236,189 @@ func (s *Server) setupRoutes() {
 			r.Get("/auth/verify", s.handleAuthVerify)
 		})
 
-		// Species endpoints (read - public)
+		// Background jobs (requires auth, including reads)
+		r.Group(func(r chi.Router) {
+			r.Use(s.ForceAuth)
+			r.Get("/jobs", s.handleListJobs)
+			r.Post("/jobs", s.handleEnqueueJob)
+			r.Get("/jobs/{id}", s.handleGetJob)
+			r.Delete("/jobs/{id}", s.handleCancelJob)
+		})
+
+		// Scheduled publication of drafts (requires auth, including reads)
+		r.Group(func(r chi.Router) {
+			r.Use(s.ForceAuth)
+			r.Get("/publications", s.handleListPublications)
+			r.Post("/publications", s.handleSchedulePublication)
+			r.Delete("/publications/{id}", s.handleCancelPublication)
+		})
+
+		// Distinguishing-feature suggestions for curator review (requires auth, including reads)
+		r.Group(func(r chi.Router) {
+			r.Use(s.ForceAuth)
+			r.Get("/feature-suggestions", s.handleListFeatureSuggestions)
+			r.Post("/feature-suggestions/generate", s.handleGenerateFeatureSuggestions)
+			r.Post("/feature-suggestions/{id}/accept", s.handleAcceptFeatureSuggestion)
+			r.Post("/feature-suggestions/{id}/reject", s.handleRejectFeatureSuggestion)
+		})
+
+		// Contradictions between sources' structured claims (requires auth, including reads)
+		r.Group(func(r chi.Router) {
+			r.Use(s.ForceAuth)
+			r.Get("/conflicts", s.handleListConflicts)
+			r.Post("/conflicts/detect", s.handleDetectConflicts)
+			r.Get("/conflicts/{id}", s.handleGetConflict)
+			r.Post("/conflicts/{id}/resolve", s.handleResolveConflict)
+		})
+
+		// Transcribed species sources awaiting verification (requires auth, including reads)
+		r.Group(func(r chi.Router) {
+			r.Use(s.ForceAuth)
+			r.Get("/transcriptions", s.handleListTranscriptions)
+		})
+
+		// Geocoded range localities for curator review (requires auth, including reads)
+		r.Group(func(r chi.Router) {
+			r.Use(s.ForceAuth)
+			r.Get("/range-localities", s.handleListRangeLocalities)
+			r.Post("/range-localities/geocode", s.handleGeocodeRanges)
+			r.Post("/range-localities/{id}/accept", s.handleAcceptRangeLocality)
+			r.Post("/range-localities/{id}/reject", s.handleRejectRangeLocality)
+		})
+
+		// Species endpoints (read - public). {name} matches regardless of
+		// case and diacritics (see resolveSpeciesName).
 		r.Get("/species", s.handleListSpecies)
-		r.Get("/species/search", s.handleSearchSpecies)   // Must be before {name} route
-		r.Get("/species/{name}/full", s.handleGetSpeciesFull) // Must be before {name} route
-		r.Get("/species/{name}", s.handleGetSpecies)
+		r.With(deprecated(speciesSearchSunset, "/api/v1/search")).Get("/species/search", s.handleSearchSpecies) // Must be before {name} route
+		r.Get("/species/by-slug/{slug}", s.handleGetSpeciesBySlug)
+		r.Group(func(r chi.Router) {
+			r.Use(s.resolveSpeciesName)
+			r.Get("/species/{name}/full", s.handleGetSpeciesFull) // Must be before {name} route
+			r.Get("/species/{name}/account", s.handleGetSpeciesAccount)
+			r.Get("/species/{name}/mentions", s.handleListSpeciesMentions)
+			r.Get("/species/{name}/measurements", s.handleListSpeciesMeasurements)
+			r.Get("/species/{name}/common-names", s.handleListSpeciesCommonNames)
+			r.Get("/species/{name}/localities", s.handleListSpeciesLocalities)
+			r.Get("/species/{name}/attributes", s.handleGetSpeciesAttributes)
+			r.Get("/species/{name}/inaturalist", s.handleGetSpeciesINaturalist)
+			r.Get("/species/{name}/media", s.handleListSpeciesMedia)
+			r.With(s.requireFeature(FlagRangeGeoJSON)).Get("/species/{name}/range.geojson", s.handleSpeciesRangeGeoJSON)
+			r.Get("/species/{name}", s.handleGetSpecies)
+			r.Get("/species/{name}/sources", s.handleListSpeciesSources)
+			r.Get("/species/{name}/sources/{sourceId}", s.handleGetSpeciesSource)
+		})
 
 		// Species endpoints (write - auth required)
 		r.Group(func(r chi.Router) {
 			r.Use(s.RequireAuth)
+			r.Use(s.resolveSpeciesName)
 			r.Post("/species", s.handleCreateSpecies)
 			r.Put("/species/{name}", s.handleUpdateSpecies)
+			r.Put("/species/{name}/visibility", s.handleSetSpeciesVisibility)
+			r.Put("/species/{name}/account", s.handlePutSpeciesAccount)
+			r.Put("/species/{name}/common-names", s.handlePutSpeciesCommonNames)
+			r.Put("/species/{name}/attributes", s.handlePutSpeciesAttributes)
+			r.Post("/species/{name}/inaturalist", s.handleEnrichSpeciesINaturalist)
+			r.Post("/species/{name}/media", s.handleAddSpeciesMedia)
+			r.Delete("/species/{name}/media/{id}", s.handleDeleteSpeciesMedia)
+			r.Delete("/species/{name}/account", s.handleDeleteSpeciesAccount)
 			r.Delete("/species/{name}", s.handleDeleteSpecies)
 		})
 
+		// Species history: earlier versions, to undo edits, and a timeline of
+		// everything that happened (requires auth, including reads)
+		r.Group(func(r chi.Router) {
+			r.Use(s.ForceAuth)
+			r.Use(s.resolveSpeciesName)
+			r.With(s.requireFeature(FlagSpeciesTimeline)).Get("/species/{name}/timeline", s.handleSpeciesTimeline)
+			r.Get("/species/{name}/revisions", s.handleListSpeciesRevisions)
+			r.Post("/species/{name}/revisions/{id}/restore", s.handleRestoreSpeciesRevision)
+		})
+
+		// Genera endpoints (read - public)
+		r.Get("/genera", s.handleListGenera)
+		r.Get("/genera/{name}", s.handleGetGenus)
+
+		// Genera endpoints (write - auth required)
+		r.Group(func(r chi.Router) {
+			r.Use(s.RequireAuth)
+			r.Post("/genera", s.handleCreateGenus)
+			r.Put("/genera/{name}", s.handleUpdateGenus)
+			r.Delete("/genera/{name}", s.handleDeleteGenus)
+		})
+
+		// JSON Schemas for editor integration (public)
+		r.Get("/schemas", s.handleListSchemas)
+		r.Get("/schemas/{name}", s.handleGetSchema)
+
+		// Data dictionary for researchers using the dataset (public)
+		r.Get("/schema/data-dictionary", s.handleGetDataDictionary)
+
+		// Entry template endpoints (read - public)
+		r.Get("/templates", s.handleListTemplates)
+		r.Get("/templates/{name}", s.handleGetTemplate)
+
+		// Entry template endpoints (write - auth required)
+		r.Group(func(r chi.Router) {
+			r.Use(s.RequireAuth)
+			r.Put("/templates/{name}", s.handlePutTemplate)
+			r.Delete("/templates/{name}", s.handleDeleteTemplate)
+		})
+
+		// Vocabulary endpoints (read - public)
+		r.Get("/vocabularies", s.handleListVocabularies)
+		r.Get("/vocabularies/{name}", s.handleGetVocabulary)
+
+		// Vocabulary endpoints (write - auth required)
+		r.Group(func(r chi.Router) {
+			r.Use(s.RequireAuth)
+			r.Put("/vocabularies/{name}", s.handlePutVocabulary)
+			r.Delete("/vocabularies/{name}", s.handleDeleteVocabulary)
+		})
+
+		// Custom field endpoints (defining fields requires an admin key)
+		r.Get("/custom-fields", s.handleListCustomFields)
+		r.Group(func(r chi.Router) {
+			r.Use(s.RequireAdmin)
+			r.Put("/custom-fields/{key}", s.handlePutCustomField)
+			r.Delete("/custom-fields/{key}", s.handleDeleteCustomField)
+		})
+
+		// Saved reports run arbitrary read-only SQL, drafts included (requires
+		// auth, including reads). Defining one writes SQL the server will run,
+		// so like /admin/query it requires an admin key.
+		r.Group(func(r chi.Router) {
+			r.Use(s.ForceAuth)
+			r.Get("/reports", s.handleListReports)
+			r.Get("/reports/{name}", s.handleGetReport)
+			r.Get("/reports/{name}/run", s.handleRunReport)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(s.RequireAdmin)
+			r.Put("/reports/{name}", s.handlePutReport)
+			r.Delete("/reports/{name}", s.handleDeleteReport)
+		})
+
+		// Author abbreviation endpoints (read - public)
+		r.Get("/authors", s.handleListAuthors)
+		r.Get("/authors/{abbreviation}", s.handleGetAuthor)
+
+		// Author abbreviation endpoints (write - auth required)
+		r.Group(func(r chi.Router) {
+			r.Use(s.RequireAuth)
+			r.Put("/authors", s.handlePutAuthors)
+		})
+
+		// Taxon level endpoints (read - public)
+		r.Get("/taxon-levels", s.handleListTaxonLevels)
+
+		// Taxon level endpoints (write - auth required)
+		r.Group(func(r chi.Router) {
+			r.Use(s.RequireAuth)
+			r.Put("/taxon-levels/{name}", s.handlePutTaxonLevel)
+			r.Delete("/taxon-levels/{name}", s.handleDeleteTaxonLevel)
+		})
+
 		// Taxa endpoints (read - public)
 		r.Get("/taxa", s.handleListTaxa)
 		r.Get("/taxa/{level}/{name}", s.handleGetTaxon)
@@ -130,6 +434,8 @@ func (s *Server) setupRoutes() {
 		// Sources endpoints (read - public)
 		r.Get("/sources", s.handleListSources)
 		r.Get("/sources/{id}", s.handleGetSource)
+		r.Get("/sources/{id}/coverage", s.handleGetSourceCoverage)
+		r.Get("/sources/{id}/attributes", s.handleGetSourceAttributes)
 
 		// Sources endpoints (write - auth required)
 		r.Group(func(r chi.Router) {
@@ -137,40 +443,136 @@ func (s *Server) setupRoutes() {
 			r.Post("/sources", s.handleCreateSource)
 			r.Put("/sources/{id}", s.handleUpdateSource)
 			r.Delete("/sources/{id}", s.handleDeleteSource)
+			r.Post("/sources/{id}/migrate", s.handleMigrateSource)
+			r.Put("/sources/{id}/attributes", s.handlePutSourceAttributes)
 		})
 
-		// Species-sources endpoints (read - public)
-		r.Get("/species/{name}/sources", s.handleListSpeciesSources)
-		r.Get("/species/{name}/sources/{sourceId}", s.handleGetSpeciesSource)
+		// What refers to a source, draft species included (requires auth, including reads)
+		r.Group(func(r chi.Router) {
+			r.Use(s.ForceAuth)
+			r.Get("/sources/{id}/usage", s.handleGetSourceUsage)
+		})
+
+		// Species-sources endpoints (read - public) are registered with the
+		// species reads above
 
 		// Species-sources endpoints (write - auth required)
 		r.Group(func(r chi.Router) {
 			r.Use(s.RequireAuth)
+			r.Use(s.resolveSpeciesName)
 			r.Post("/species/{name}/sources", s.handleCreateSpeciesSource)
 			r.Put("/species/{name}/sources/{sourceId}", s.handleUpdateSpeciesSource)
 			r.Delete("/species/{name}/sources/{sourceId}", s.handleDeleteSpeciesSource)
+			r.Post("/species/{name}/sources/{sourceId}/verify", s.handleVerifySpeciesSource)
+			r.Put("/species/{name}/preferred-source", s.handleSetPreferredSource)
+			r.With(withBodyLimit(maxBulkBodySize)).Put("/sources/{id}/species-sources", s.handleBulkUpsertSpeciesSources)
+		})
+
+		// Import sessions: multi-request bulk uploads applied together (requires auth, including reads)
+		r.Group(func(r chi.Router) {
+			r.Use(s.ForceAuth)
+			r.Post("/import/sessions", s.handleCreateImportSession)
+			r.Get("/import/sessions/{id}", s.handleGetImportSession)
+			r.Post("/import/sessions/{id}/commit", s.handleCommitImportSession)
+			r.Post("/import/sessions/{id}/discard", s.handleDiscardImportSession)
 		})
 
 		// Export endpoint
 		r.Get("/export", s.handleExport)
+		r.Get("/hashes", s.handleListHashes)
+		r.Get("/export/mappings", s.handleListExportMappings)
+		r.Get("/attributions", s.handleGetAttributions)
 
 		// Stats endpoint (public, read-only)
 		r.Get("/stats", s.handleStats)
+
+		// Species view counts (require auth, including reads)
+		r.Group(func(r chi.Router) {
+			r.Use(s.ForceAuth)
+			r.Get("/stats/popular", s.handlePopularSpecies)
+		})
+
+		// Admin endpoints (writes require an admin key)
+		r.Group(func(r chi.Router) {
+			r.Use(s.RequireAdmin)
+			r.Post("/admin/reindex", s.handleReindex)
+			r.Post("/admin/repair-preferred-sources", s.handleRepairPreferredSources)
+			r.Post("/admin/repair-json", s.handleRepairJSONColumns)
+			r.Post("/admin/rebuild-hybrids", s.handleRebuildHybrids)
+			r.Post("/admin/query", s.handleQuery)
+			r.Post("/admin/schema", s.handleMigrateSchema)
+			r.Get("/admin/maintenance", s.handleGetMaintenance)
+			r.Post("/admin/maintenance", s.handleSetMaintenance)
+			r.Put("/admin/features/{name}", s.handleSetFeature)
+			r.Delete("/admin/features/{name}", s.handleClearFeature)
+		})
+
+		// Corrupt JSON columns, with their raw values, non-canonical species
+		// names, drafts included, API key usage, and the schema version
+		// (requires an admin key, including reads)
+		r.Group(func(r chi.Router) {
+			r.Use(s.ForceAdmin)
+			r.Get("/admin/data-errors", s.handleListDataErrors)
+			r.Get("/admin/hybrids", s.handleCheckHybrids)
+			r.Get("/admin/name-report", s.handleNameReport)
+			r.Get("/admin/slow-queries", s.handleSlowQueries)
+			r.Get("/admin/keys", s.handleListAPIKeys)
+			r.Get("/admin/keys/{id}/usage", s.handleGetAPIKeyUsage)
+			r.Get("/admin/schema", s.handleSchemaStatus)
+		})
+
+		// API keys for collaborators, each with a role (requires an admin key)
+		r.Group(func(r chi.Router) {
+			r.Use(s.ForceAdmin)
+			r.Post("/keys", s.handleCreateAPIKey)
+			r.Delete("/keys/{id}", s.handleDeleteAPIKey)
+		})
 	})
+
+	// API v2 routes (coexist with v1, same store)
+	r.Route("/api/v2", s.setupV2Routes)
 }
 
-// Start starts the HTTP server on the given address.
+// Start listens on the given address and serves until Shutdown.
 func (s *Server) Start(addr string) error {
+	listener, err := s.Listen(addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Listen opens the server's listening socket on addr. A port of 0 picks a
+// free port; Addr reports the one chosen.
+func (s *Server) Listen(addr string) (net.Listener, error) {
+	var lc net.ListenConfig
+	if s.reusePort {
+		lc.Control = reusePortControl
+	}
+	listener, err := lc.Listen(context.Background(), "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	s.addr = listener.Addr().String()
+	return listener, nil
+}
+
+// Serve serves HTTP requests on listener until Shutdown.
+func (s *Server) Serve(listener net.Listener) error {
 	s.httpServer = &http.Server{
-		Addr:         addr,
 		Handler:      s.router,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
 
-	s.logger.Info("starting API server", "addr", addr)
-	return s.httpServer.ListenAndServe()
+	s.logger.Info("starting API server", "addr", listener.Addr().String(), "reuse_port", s.reusePort)
+	return s.httpServer.Serve(listener)
+}
+
+// Addr returns the address the server is listening on, or "" before Listen.
+func (s *Server) Addr() string {
+	return s.addr
 }
 
 // Shutdown gracefully shuts down the server with the given context.
@@ -182,6 +584,12 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return s.httpServer.Shutdown(ctx)
 }
 
+// Jobs returns the server's background job runner. The caller starts and stops
+// its workers; jobs can be queued before it is started.
+func (s *Server) Jobs() *jobs.Runner {
+	return s.jobs
+}
+
 // Router returns the chi router for testing purposes.
 func (s *Server) Router() chi.Router {
 	return s.router
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/names"
+)
+
+// DefaultSiteURL is the public website the sitemap links to
+const DefaultSiteURL = "https://oakcompendium.org"
+
+// sitemapIndexPages are the site's fixed pages, listed before the data pages
+var sitemapIndexPages = []string{"/", "/list/", "/taxonomy/", "/sources/", "/about/"}
+
+// sitemapURLSet is the <urlset> document of the sitemaps.org protocol
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// handleSitemap handles GET /sitemap.xml
+// Lists the public site's species, taxon, and source pages with lastmod from
+// updated_at. ?base_url= links to another deployment, such as staging.
+func (s *Server) handleSitemap(w http.ResponseWriter, r *http.Request) {
+	base := s.siteURL
+	if value := r.URL.Query().Get("base_url"); value != "" {
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			RespondValidationError(w, []ValidationError{{
+				Field:   "base_url",
+				Message: "base_url must be an absolute http or https URL",
+			}})
+			return
+		}
+		base = value
+	}
+	base = strings.TrimSuffix(base, "/")
+
+	entries, err := s.db.ListSitemapEntries()
+	if err != nil {
+		s.logger.Error("failed to list sitemap entries", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	set := sitemapURLSet{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for _, page := range sitemapIndexPages {
+		set.URLs = append(set.URLs, sitemapURL{Loc: base + page})
+	}
+	for _, e := range entries {
+		u := sitemapURL{Loc: base + sitemapPath(e)}
+		if e.LastModified != nil {
+			u.LastMod = *e.LastModified
+		}
+		set.URLs = append(set.URLs, u)
+	}
+
+	data, err := xml.MarshalIndent(set, "", "  ")
+	if err != nil {
+		s.logger.Error("failed to marshal sitemap", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(data)
+	_, _ = w.Write([]byte("\n"))
+}
+
+// sitemapPath returns the web app route for a sitemap entry
+func sitemapPath(e *db.SitemapEntry) string {
+	switch e.Kind {
+	case db.SitemapTaxon:
+		return "/taxonomy/" + url.PathEscape(e.Level) + "/" + url.PathEscape(e.Name) + "/"
+	case db.SitemapSource:
+		return "/sources/" + strconv.FormatInt(e.SourceID, 10) + "/"
+	default:
+		return "/species/" + names.Slug(e.Name) + "/"
+	}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/names"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// speciesBySlugPath is where a species' permalink resolves
+const speciesBySlugPath = "/api/v1/species/by-slug/"
+
+// handleGetSpeciesBySlug handles GET /api/v1/species/by-slug/{slug}
+// Returns the species whose slug is slug, as GET /species/{name} does. A
+// name, or a slug in another form, redirects (301) to the canonical slug,
+// and the old slug of a renamed or merged species redirects to the species
+// it became, so permalinks keep working.
+func (s *Server) handleGetSpeciesBySlug(w http.ResponseWriter, r *http.Request) {
+	param, err := url.PathUnescape(chi.URLParam(r, "slug"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid slug encoding")
+		return
+	}
+	slug := names.Slug(param)
+	if slug == "" {
+		RespondNotFound(w, "Species", param)
+		return
+	}
+
+	name, err := s.db.SpeciesNameBySlug(slug)
+	if err != nil {
+		s.logger.Error("failed to look up species slug", "slug", slug, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if name == "" {
+		name, err = s.db.SpeciesRedirect(slug)
+		if err != nil {
+			s.logger.Error("failed to look up species redirect", "slug", slug, "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+		if name == "" {
+			RespondNotFound(w, "Species", param)
+			return
+		}
+		slug = names.Slug(name)
+	}
+	if slug != param {
+		http.Redirect(w, r, speciesBySlugPath+url.PathEscape(slug), http.StatusMovedPermanently)
+		return
+	}
+	s.respondSpecies(w, r, name)
+}
@@ -1,13 +1,18 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/jeff/oaks/api/internal/db"
 	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/pkg/apierror"
 )
 
 // SourceRequest represents the request body for creating/updating a source.
@@ -23,10 +28,14 @@ type SourceRequest struct {
 	Notes       *string `json:"notes,omitempty"`
 	License     *string `json:"license,omitempty"`
 	LicenseURL  *string `json:"license_url,omitempty"`
+	// SupersededBy marks this source as replaced by another source
+	SupersededBy *int64 `json:"superseded_by,omitempty"`
 }
 
-// validateSourceRequest validates a source request and returns validation errors.
-func validateSourceRequest(req SourceRequest) []ValidationError {
+// validateSourceRequest validates a source request and returns validation
+// errors. types are the source type terms; with none bound, any type is
+// accepted. A valid source_type is rewritten in its canonical form.
+func validateSourceRequest(req *SourceRequest, types []string) []ValidationError {
 	var errors []ValidationError
 
 	if req.SourceType == "" {
@@ -34,6 +43,13 @@ func validateSourceRequest(req SourceRequest) []ValidationError {
 			Field:   "source_type",
 			Message: "source_type is required",
 		})
+	} else if sourceType, ok := normalizeSourceType(req.SourceType, types); ok {
+		req.SourceType = sourceType
+	} else {
+		errors = append(errors, ValidationError{
+			Field:   "source_type",
+			Message: "must be one of: " + strings.Join(types, ", "),
+		})
 	}
 
 	if req.Name == "" {
@@ -46,9 +62,71 @@ func validateSourceRequest(req SourceRequest) []ValidationError {
 	return errors
 }
 
+// normalizeSourceType returns the term of types that s names, compared as
+// models.SourceTypeKey and through the older spellings NormalizeSourceType
+// knows. With types nil, any s is accepted in its key form.
+func normalizeSourceType(s string, types []string) (string, bool) {
+	key := models.SourceTypeKey(s)
+	if types == nil || slices.Contains(types, key) {
+		return key, true
+	}
+	if canonical, ok := models.NormalizeSourceType(s); ok && slices.Contains(types, canonical) {
+		return canonical, true
+	}
+	return s, false
+}
+
 // handleListSources handles GET /api/v1/sources
+// Filters by ?source_type (or ?type) and ?year; paged with ?limit and ?offset. Without a
+// limit, every matching source is returned.
 func (s *Server) handleListSources(w http.ResponseWriter, r *http.Request) {
-	sources, err := s.db.ListSources()
+	query := r.URL.Query()
+	params := &db.SourceListParams{}
+	var errors []ValidationError
+	params.Limit, params.Offset, errors = parsePageParams(query, 0)
+
+	// ?type is short for ?source_type
+	sourceType := query.Get("source_type")
+	if sourceType == "" {
+		sourceType = query.Get("type")
+	}
+	if sourceType != "" {
+		types, err := s.fieldTerms(models.VocabularyFieldSourceType)
+		if err != nil {
+			s.logger.Error("failed to get source type vocabulary", "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+		if canonical, ok := normalizeSourceType(sourceType, types); ok {
+			params.SourceType = &canonical
+		} else {
+			errors = append(errors, ValidationError{
+				Field:   "source_type",
+				Message: "must be one of: " + strings.Join(types, ", "),
+			})
+		}
+	}
+	if yearStr := query.Get("year"); yearStr != "" {
+		year, err := strconv.Atoi(yearStr)
+		if err != nil {
+			errors = append(errors, ValidationError{Field: "year", Message: "must be an integer"})
+		} else {
+			params.Year = &year
+		}
+	}
+	if len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+
+	total, err := s.db.CountSources(params)
+	if err != nil {
+		s.logger.Error("failed to count sources", "error", err)
+		RespondInternalError(w, "Failed to retrieve sources")
+		return
+	}
+
+	sources, err := s.db.ListSources(params)
 	if err != nil {
 		s.logger.Error("failed to list sources", "error", err)
 		RespondInternalError(w, "Failed to retrieve sources")
@@ -60,15 +138,20 @@ func (s *Server) handleListSources(w http.ResponseWriter, r *http.Request) {
 		sources = []*models.Source{}
 	}
 
-	RespondJSON(w, http.StatusOK, sources)
+	limit := params.Limit
+	if limit == 0 {
+		limit = len(sources)
+	}
+	RespondJSON(w, http.StatusOK, NewListResponse(sources, total, limit, params.Offset))
 }
 
 // handleGetSource handles GET /api/v1/sources/{id}
+// Sends Last-Modified and honors If-Modified-Since.
 func (s *Server) handleGetSource(w http.ResponseWriter, r *http.Request) {
 	idParam := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid source ID")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "Invalid source ID")
 		return
 	}
 
@@ -84,34 +167,102 @@ func (s *Server) handleGetSource(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	updatedAt, err := s.db.SourceUpdatedAt(id)
+	if err != nil {
+		s.logger.Error("failed to get source updated_at", "error", err, "id", id)
+		RespondInternalError(w, "Failed to retrieve source")
+		return
+	}
+	if !s.setETag(w, db.HashKindSources, strconv.FormatInt(id, 10)) {
+		return
+	}
+	if notModified(w, r, updatedAt) {
+		return
+	}
+	if s.respondHashOnly(w, r, db.HashKindSources, strconv.FormatInt(id, 10)) {
+		return
+	}
+
 	RespondJSON(w, http.StatusOK, source)
 }
 
+// handleGetSourceCoverage handles GET /api/v1/sources/{id}/coverage
+// Reports how many species the source describes, which fields it fills in,
+// and which species in the sections it covers it has no data for.
+func (s *Server) handleGetSourceCoverage(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "Invalid source ID")
+		return
+	}
+
+	source, err := s.db.GetSource(id)
+	if err != nil {
+		s.logger.Error("failed to get source", "error", err, "id", id)
+		RespondInternalError(w, "Failed to retrieve source")
+		return
+	}
+	if source == nil {
+		RespondNotFound(w, "Source", idParam)
+		return
+	}
+
+	coverage, err := s.db.GetSourceCoverage(id)
+	if err != nil {
+		s.logger.Error("failed to get source coverage", "error", err, "id", id)
+		RespondInternalError(w, "Failed to compute source coverage")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, coverage)
+}
+
+// sourceBodyMessage is invalidBodyMessage in the capitalized form the
+// sources endpoints have always answered with, which clients match on
+func sourceBodyMessage(r *http.Request) string {
+	if isYAMLRequest(r) {
+		return "Invalid YAML body"
+	}
+	return "Invalid JSON body"
+}
+
 // handleCreateSource handles POST /api/v1/sources
 func (s *Server) handleCreateSource(w http.ResponseWriter, r *http.Request) {
 	var req SourceRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid JSON body")
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, sourceBodyMessage(r))
 		return
 	}
 
-	if errors := validateSourceRequest(req); len(errors) > 0 {
+	types, err := s.fieldTerms(models.VocabularyFieldSourceType)
+	if err != nil {
+		s.logger.Error("failed to get source type vocabulary", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if errors := validateSourceRequest(&req, types); len(errors) > 0 {
 		RespondValidationError(w, errors)
 		return
 	}
 
+	if !s.validateSupersededBy(w, 0, req.SupersededBy) {
+		return
+	}
+
 	source := &models.Source{
-		SourceType:  req.SourceType,
-		Name:        req.Name,
-		Description: req.Description,
-		Author:      req.Author,
-		Year:        req.Year,
-		URL:         req.URL,
-		ISBN:        req.ISBN,
-		DOI:         req.DOI,
-		Notes:       req.Notes,
-		License:     req.License,
-		LicenseURL:  req.LicenseURL,
+		SourceType:   req.SourceType,
+		Name:         req.Name,
+		Description:  req.Description,
+		Author:       req.Author,
+		Year:         req.Year,
+		URL:          req.URL,
+		ISBN:         req.ISBN,
+		DOI:          req.DOI,
+		Notes:        req.Notes,
+		License:      req.License,
+		LicenseURL:   req.LicenseURL,
+		SupersededBy: req.SupersededBy,
 	}
 
 	id, err := s.db.InsertSource(source)
@@ -130,7 +281,7 @@ func (s *Server) handleUpdateSource(w http.ResponseWriter, r *http.Request) {
 	idParam := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid source ID")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "Invalid source ID")
 		return
 	}
 
@@ -145,34 +296,54 @@ func (s *Server) handleUpdateSource(w http.ResponseWriter, r *http.Request) {
 		RespondNotFound(w, "Source", idParam)
 		return
 	}
+	hashKey := strconv.FormatInt(id, 10)
+	hash, failed := s.checkIfMatch(w, r, db.HashKindSources, hashKey)
+	if failed {
+		return
+	}
 
 	var req SourceRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid JSON body")
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, sourceBodyMessage(r))
 		return
 	}
 
-	if errors := validateSourceRequest(req); len(errors) > 0 {
+	types, err := s.fieldTerms(models.VocabularyFieldSourceType)
+	if err != nil {
+		s.logger.Error("failed to get source type vocabulary", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if errors := validateSourceRequest(&req, types); len(errors) > 0 {
 		RespondValidationError(w, errors)
 		return
 	}
 
+	if !s.validateSupersededBy(w, id, req.SupersededBy) {
+		return
+	}
+
 	source := &models.Source{
-		ID:          id,
-		SourceType:  req.SourceType,
-		Name:        req.Name,
-		Description: req.Description,
-		Author:      req.Author,
-		Year:        req.Year,
-		URL:         req.URL,
-		ISBN:        req.ISBN,
-		DOI:         req.DOI,
-		Notes:       req.Notes,
-		License:     req.License,
-		LicenseURL:  req.LicenseURL,
-	}
-
-	if err := s.db.UpdateSource(source); err != nil {
+		ID:           id,
+		SourceType:   req.SourceType,
+		Name:         req.Name,
+		Description:  req.Description,
+		Author:       req.Author,
+		Year:         req.Year,
+		URL:          req.URL,
+		ISBN:         req.ISBN,
+		DOI:          req.DOI,
+		Notes:        req.Notes,
+		License:      req.License,
+		LicenseURL:   req.LicenseURL,
+		SupersededBy: req.SupersededBy,
+	}
+
+	if err := s.db.UpdateSourceIfHash(source, hash); err != nil {
+		if errors.Is(err, db.ErrEntityChanged) {
+			s.respondRecordChanged(w, db.HashKindSources, hashKey)
+			return
+		}
 		s.logger.Error("failed to update source", "error", err, "id", id)
 		RespondInternalError(w, "Failed to update source")
 		return
@@ -181,15 +352,173 @@ func (s *Server) handleUpdateSource(w http.ResponseWriter, r *http.Request) {
 	RespondJSON(w, http.StatusOK, source)
 }
 
+// MigrateSourceRequest is the request body for moving species data between sources.
+type MigrateSourceRequest struct {
+	TargetID int64 `json:"target_id"`
+	Move     bool  `json:"move"`    // Remove rows from the old source after copying
+	DryRun   bool  `json:"dry_run"` // Report the plan without writing
+}
+
+// MigrateSourceResponse reports the planned or applied migration.
+type MigrateSourceResponse struct {
+	SourceID int64                    `json:"source_id"`
+	TargetID int64                    `json:"target_id"`
+	Move     bool                     `json:"move"`
+	DryRun   bool                     `json:"dry_run"`
+	Items    []db.SourceMigrationItem `json:"items"`
+}
+
+// handleMigrateSource handles POST /api/v1/sources/{id}/migrate
+// Copies or moves a source's species data to another source, typically the one
+// that supersedes it. Species the target already describes are skipped.
+func (s *Server) handleMigrateSource(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "Invalid source ID")
+		return
+	}
+
+	var req MigrateSourceRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+	if req.TargetID <= 0 || req.TargetID == id {
+		RespondValidationError(w, []ValidationError{{Field: "target_id", Message: "target_id must be a different source"}})
+		return
+	}
+
+	for _, sourceID := range []int64{id, req.TargetID} {
+		source, err := s.db.GetSource(sourceID)
+		if err != nil {
+			s.logger.Error("failed to get source", "error", err, "id", sourceID)
+			RespondInternalError(w, "Failed to retrieve source")
+			return
+		}
+		if source == nil {
+			RespondNotFound(w, "Source", strconv.FormatInt(sourceID, 10))
+			return
+		}
+	}
+
+	items, err := s.db.MigrateSpeciesSources(id, req.TargetID, req.Move, req.DryRun)
+	if err != nil {
+		s.logger.Error("failed to migrate source", "error", err, "id", id, "target", req.TargetID)
+		RespondInternalError(w, "Failed to migrate source")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, MigrateSourceResponse{
+		SourceID: id,
+		TargetID: req.TargetID,
+		Move:     req.Move,
+		DryRun:   req.DryRun,
+		Items:    items,
+	})
+}
+
+// warnIfSuperseded adds a Warning header when data is written against a superseded source
+func warnIfSuperseded(w http.ResponseWriter, source *models.Source) {
+	if source == nil || source.SupersededBy == nil {
+		return
+	}
+	w.Header().Add("Warning", fmt.Sprintf(`299 oak-api "source %d is superseded by source %d"`, source.ID, *source.SupersededBy))
+}
+
+// maxSupersedeChain bounds the walk along superseded_by links when checking for cycles
+const maxSupersedeChain = 100
+
+// validateSupersededBy checks that a superseding source exists and that linking
+// source id to it would not create a cycle. id is 0 for a new source.
+// Returns false if an error response was written.
+func (s *Server) validateSupersededBy(w http.ResponseWriter, id int64, supersededBy *int64) bool {
+	if supersededBy == nil {
+		return true
+	}
+	if *supersededBy == id {
+		RespondValidationError(w, []ValidationError{{Field: "superseded_by", Message: "a source cannot supersede itself"}})
+		return false
+	}
+
+	next := *supersededBy
+	for i := 0; i < maxSupersedeChain; i++ {
+		target, err := s.db.GetSource(next)
+		if err != nil {
+			s.logger.Error("failed to get superseding source", "error", err, "id", next)
+			RespondInternalError(w, "Failed to retrieve source")
+			return false
+		}
+		if target == nil {
+			if next == *supersededBy {
+				RespondValidationError(w, []ValidationError{{Field: "superseded_by", Message: fmt.Sprintf("source %d not found", next)}})
+				return false
+			}
+			return true
+		}
+		if target.SupersededBy == nil {
+			return true
+		}
+		if *target.SupersededBy == id && id != 0 {
+			RespondValidationError(w, []ValidationError{{Field: "superseded_by", Message: "superseded_by would create a cycle"}})
+			return false
+		}
+		next = *target.SupersededBy
+	}
+	return true
+}
+
+// handleGetSourceUsage handles GET /api/v1/sources/{id}/usage
+// Lists what deleting the source would affect.
+func (s *Server) handleGetSourceUsage(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "Invalid source ID")
+		return
+	}
+
+	existing, err := s.db.GetSource(id)
+	if err != nil {
+		s.logger.Error("failed to get source for usage", "error", err, "id", id)
+		RespondInternalError(w, "Failed to retrieve source")
+		return
+	}
+	if existing == nil {
+		RespondNotFound(w, "Source", idParam)
+		return
+	}
+
+	usage, err := s.db.GetSourceUsage(id)
+	if err != nil {
+		s.logger.Error("failed to get source usage", "error", err, "id", id)
+		RespondInternalError(w, "Failed to retrieve source usage")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, usage)
+}
+
 // handleDeleteSource handles DELETE /api/v1/sources/{id}
+// A source still in use is refused with 409 and its usage; ?force=true
+// deletes it along with everything that refers to it.
 func (s *Server) handleDeleteSource(w http.ResponseWriter, r *http.Request) {
 	idParam := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid source ID")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "Invalid source ID")
 		return
 	}
 
+	force := false
+	if forceParam := r.URL.Query().Get("force"); forceParam != "" {
+		force, err = strconv.ParseBool(forceParam)
+		if err != nil {
+			RespondValidationError(w, []ValidationError{{Field: "force", Message: "must be true or false"}})
+			return
+		}
+	}
+
 	// Check if source exists first
 	existing, err := s.db.GetSource(id)
 	if err != nil {
@@ -202,6 +531,34 @@ func (s *Server) handleDeleteSource(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if force {
+		usage, err := s.db.DeleteSourceCascade(id)
+		if err != nil {
+			s.logger.Error("failed to delete source", "error", err, "id", id)
+			RespondInternalError(w, "Failed to delete source")
+			return
+		}
+		s.logger.Info("deleted source with its references", "id", id,
+			"species", len(usage.Species), "superseded_sources", len(usage.SupersededSources), "templates", len(usage.Templates))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	usage, err := s.db.GetSourceUsage(id)
+	if err != nil {
+		s.logger.Error("failed to get source usage", "error", err, "id", id)
+		RespondInternalError(w, "Failed to retrieve source usage")
+		return
+	}
+	if usage.InUse() {
+		message := fmt.Sprintf("Cannot delete: source %d is used by %d species, %d superseded sources, and %d templates",
+			id, len(usage.Species), len(usage.SupersededSources), len(usage.Templates))
+		RespondJSON(w, http.StatusConflict, ErrorResponse{
+			Error: NewAPIErrorWithDetails(apierror.CodeConflict, message, usage),
+		})
+		return
+	}
+
 	if err := s.db.DeleteSource(id); err != nil {
 		s.logger.Error("failed to delete source", "error", err, "id", id)
 		RespondInternalError(w, "Failed to delete source")
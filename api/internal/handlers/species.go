@@ -1,9 +1,11 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -11,26 +13,35 @@ import (
 
 	"github.com/jeff/oaks/api/internal/db"
 	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/api/internal/names"
+	"github.com/jeff/oaks/api/internal/units"
+	"github.com/jeff/oaks/pkg/apierror"
 )
 
 // SpeciesListParams contains query parameters for species list endpoint
 type SpeciesListParams struct {
 	Limit      int
 	Offset     int
+	Genus      *string
 	Subgenus   *string
 	Section    *string
 	Subsection *string
 	Complex    *string
 	Hybrid     *bool
 	SourceID   *int64
+	HasSource  *bool
+
+	ConservationStatus []string
 }
 
 // SpeciesRequest represents the request body for creating/updating a species
 type SpeciesRequest struct {
 	ScientificName       string   `json:"scientific_name"`
 	Author               *string  `json:"author,omitempty"`
+	Pronunciation        *string  `json:"pronunciation,omitempty"`
 	IsHybrid             bool     `json:"is_hybrid"`
 	ConservationStatus   *string  `json:"conservation_status,omitempty"`
+	Genus                string   `json:"genus,omitempty"` // Defaults to Quercus on create
 	Subgenus             *string  `json:"subgenus,omitempty"`
 	Section              *string  `json:"section,omitempty"`
 	Subsection           *string  `json:"subsection,omitempty"`
@@ -41,6 +52,8 @@ type SpeciesRequest struct {
 	CloselyRelatedTo     []string `json:"closely_related_to,omitempty"`
 	SubspeciesVarieties  []string `json:"subspecies_varieties,omitempty"`
 	Synonyms             []string `json:"synonyms,omitempty"`
+	ExternalLinks        []models.ExternalLink `json:"external_links,omitempty"`
+	Visibility           string   `json:"visibility,omitempty"` // draft or published
 }
 
 const (
@@ -48,62 +61,67 @@ const (
 	maxLimit     = 500
 )
 
-// Valid subgenera for validation
-var validSubgenera = map[string]bool{
-	"Quercus":         true,
-	"Cerris":          true,
-	"Cyclobalanopsis": true,
-}
+// threatenedStatuses are the IUCN threatened categories selected by ?threatened=true
+var threatenedStatuses = []string{"CR", "EN", "VU"}
 
-// Valid IUCN conservation status codes
-var validConservationStatus = map[string]bool{
-	"EX": true, // Extinct
-	"EW": true, // Extinct in the Wild
-	"CR": true, // Critically Endangered
-	"EN": true, // Endangered
-	"VU": true, // Vulnerable
-	"NT": true, // Near Threatened
-	"LC": true, // Least Concern
-	"DD": true, // Data Deficient
-	"NE": true, // Not Evaluated
-}
-
-// parseSpeciesListParams extracts and validates query parameters for list endpoint
-func parseSpeciesListParams(query url.Values) (*SpeciesListParams, []ValidationError) {
-	params := &SpeciesListParams{
-		Limit:  defaultLimit,
-		Offset: 0,
-	}
+// parseConservationStatusParams reads the comma-separated ?conservation_status
+// filter and the ?threatened=true shorthand for CR,EN,VU. Statuses must be
+// among terms, the conservation status vocabulary, unless it is nil. Returns
+// nil when neither is set.
+func parseConservationStatusParams(query url.Values, terms []string) ([]string, []ValidationError) {
+	var statuses []string
 	var errors []ValidationError
+	seen := make(map[string]bool)
+	add := func(status string) {
+		if !seen[status] {
+			seen[status] = true
+			statuses = append(statuses, status)
+		}
+	}
 
-	// Parse limit
-	if limitStr := query.Get("limit"); limitStr != "" {
-		limit, err := strconv.Atoi(limitStr)
-		if err != nil || limit < 1 {
-			errors = append(errors, ValidationError{
-				Field:   "limit",
-				Message: "must be a positive integer",
-			})
-		} else if limit > maxLimit {
-			params.Limit = maxLimit
-		} else {
-			params.Limit = limit
+	if value := query.Get("conservation_status"); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			status := strings.ToUpper(strings.TrimSpace(part))
+			if terms != nil && !slices.Contains(terms, status) {
+				errors = append(errors, ValidationError{
+					Field:   "conservation_status",
+					Message: "must be comma-separated terms of the conservation status vocabulary (" + strings.Join(terms, ", ") + ")",
+				})
+				return nil, errors
+			}
+			add(status)
 		}
 	}
 
-	// Parse offset
-	if offsetStr := query.Get("offset"); offsetStr != "" {
-		offset, err := strconv.Atoi(offsetStr)
-		if err != nil || offset < 0 {
+	if value := query.Get("threatened"); value != "" {
+		if threatened, err := strconv.ParseBool(value); err != nil || !threatened {
 			errors = append(errors, ValidationError{
-				Field:   "offset",
-				Message: "must be a non-negative integer",
+				Field:   "threatened",
+				Message: "must be true",
 			})
 		} else {
-			params.Offset = offset
+			for _, status := range threatenedStatuses {
+				add(status)
+			}
 		}
 	}
 
+	return statuses, errors
+}
+
+// parseSpeciesListParams extracts and validates query parameters for list
+// endpoint. statuses are the conservation status terms, as for
+// parseConservationStatusParams.
+func parseSpeciesListParams(query url.Values, statuses []string) (*SpeciesListParams, []ValidationError) {
+	params := &SpeciesListParams{}
+	var errors []ValidationError
+	params.Limit, params.Offset, errors = parsePageParams(query, defaultLimit)
+
+	// Parse genus filter
+	if genus := query.Get("genus"); genus != "" {
+		params.Genus = &genus
+	}
+
 	// Parse subgenus filter
 	if subgenus := query.Get("subgenus"); subgenus != "" {
 		params.Subgenus = &subgenus
@@ -143,11 +161,59 @@ func parseSpeciesListParams(query url.Values) (*SpeciesListParams, []ValidationE
 		}
 	}
 
+	// Parse conservation_status and threatened filters
+	conservationStatuses, statusErrors := parseConservationStatusParams(query, statuses)
+	params.ConservationStatus = conservationStatuses
+	errors = append(errors, statusErrors...)
+
+	// Parse has_source filter
+	if hasSourceStr := query.Get("has_source"); hasSourceStr != "" {
+		hasSource, err := strconv.ParseBool(hasSourceStr)
+		if err != nil {
+			errors = append(errors, ValidationError{
+				Field:   "has_source",
+				Message: "must be true or false",
+			})
+		} else {
+			params.HasSource = &hasSource
+		}
+	}
+
 	return params, errors
 }
 
-// validateSpeciesRequest validates a species create/update request
-func validateSpeciesRequest(req *SpeciesRequest, isCreate bool) []ValidationError {
+// resolveSpeciesName rewrites the {name} URL parameter to the stored name
+// when it differs from it only in case, diacritics, or spacing, so
+// /species/Alba reaches alba. Unknown names pass through to 404 as before.
+func (s *Server) resolveSpeciesName(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			for i, key := range rctx.URLParams.Keys {
+				if key != "name" {
+					continue
+				}
+				name, err := url.PathUnescape(rctx.URLParams.Values[i])
+				if err != nil {
+					break // The handler reports the bad encoding
+				}
+				stored, err := s.db.ResolveOakEntryName(name)
+				if err != nil {
+					s.logger.Error("failed to resolve species name", "name", name, "error", err)
+					RespondInternalError(w, "")
+					return
+				}
+				if stored != "" && stored != name {
+					rctx.URLParams.Values[i] = stored
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validateSpeciesRequest validates a species create/update request. statuses
+// are the conservation status terms; with none bound, any status is accepted.
+func validateSpeciesRequest(req *SpeciesRequest, isCreate bool, statuses []string) []ValidationError {
 	var errors []ValidationError
 
 	// Validate scientific_name
@@ -162,51 +228,93 @@ func validateSpeciesRequest(req *SpeciesRequest, isCreate bool) []ValidationErro
 				Field:   "scientific_name",
 				Message: "must be between 2 and 100 characters",
 			})
-		}
-	}
-
-	// Validate subgenus if provided
-	if req.Subgenus != nil && *req.Subgenus != "" {
-		if !validSubgenera[*req.Subgenus] {
+		} else if problem := names.Problem(req.ScientificName); problem != "" {
+			if suggestion := names.Suggest(req.ScientificName); suggestion != "" {
+				problem += fmt.Sprintf(" (did you mean %q?)", suggestion)
+			}
 			errors = append(errors, ValidationError{
-				Field:   "subgenus",
-				Message: "must be one of: Quercus, Cerris, Cyclobalanopsis",
+				Field:   "scientific_name",
+				Message: problem,
 			})
 		}
 	}
 
+	if req.Visibility != "" && req.Visibility != models.VisibilityDraft && req.Visibility != models.VisibilityPublished {
+		errors = append(errors, ValidationError{
+			Field:   "visibility",
+			Message: "must be draft or published",
+		})
+	}
+
 	// Validate conservation_status if provided
 	if req.ConservationStatus != nil && *req.ConservationStatus != "" {
-		if !validConservationStatus[*req.ConservationStatus] {
+		if statuses != nil && !slices.Contains(statuses, *req.ConservationStatus) {
 			errors = append(errors, ValidationError{
 				Field:   "conservation_status",
-				Message: "must be a valid IUCN code (EX, EW, CR, EN, VU, NT, LC, DD, NE)",
+				Message: "must be a term of the conservation status vocabulary (" + strings.Join(statuses, ", ") + ")",
 			})
 		}
 	}
 
+	errors = append(errors, validateExternalLinks(req.ExternalLinks)...)
+
+	return errors
+}
+
+// validateExternalLinks checks that each link has a name, an absolute http(s)
+// URL, and a known logo if any
+func validateExternalLinks(links []models.ExternalLink) []ValidationError {
+	var errors []ValidationError
+	for i, link := range links {
+		field := fmt.Sprintf("external_links[%d]", i)
+		if strings.TrimSpace(link.Name) == "" {
+			errors = append(errors, ValidationError{Field: field + ".name", Message: "is required"})
+		}
+		if u, err := url.Parse(link.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errors = append(errors, ValidationError{Field: field + ".url", Message: "must be an absolute http or https URL"})
+		}
+		if link.Logo != "" && !slices.Contains(models.ExternalLinkLogos, link.Logo) {
+			errors = append(errors, ValidationError{
+				Field:   field + ".logo",
+				Message: "must be one of: " + strings.Join(models.ExternalLinkLogos, ", "),
+			})
+		}
+	}
 	return errors
 }
 
 // handleListSpecies handles GET /api/v1/species
 func (s *Server) handleListSpecies(w http.ResponseWriter, r *http.Request) {
-	params, validationErrors := parseSpeciesListParams(r.URL.Query())
+	statuses, err := s.fieldTerms(models.VocabularyFieldConservationStatus)
+	if err != nil {
+		s.logger.Error("failed to get conservation status vocabulary", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	params, validationErrors := parseSpeciesListParams(r.URL.Query(), statuses)
 	if len(validationErrors) > 0 {
 		RespondValidationError(w, validationErrors)
 		return
 	}
 
 	filter := &db.OakEntryFilter{
-		Subgenus:   params.Subgenus,
-		Section:    params.Section,
-		Subsection: params.Subsection,
-		Complex:    params.Complex,
-		Hybrid:     params.Hybrid,
-		SourceID:   params.SourceID,
+		Genus:              params.Genus,
+		Subgenus:           params.Subgenus,
+		Section:            params.Section,
+		Subsection:         params.Subsection,
+		Complex:            params.Complex,
+		Hybrid:             params.Hybrid,
+		SourceID:           params.SourceID,
+		HasSource:          params.HasSource,
+		ConservationStatus: params.ConservationStatus,
+
+		IncludeDrafts: s.isAuthenticated(r),
 	}
 
+	reads := s.readDB(r)
+
 	// Get total count
-	total, err := s.db.CountOakEntries(filter)
+	total, err := reads.CountOakEntries(filter)
 	if err != nil {
 		s.logger.Error("failed to count species", "error", err)
 		RespondInternalError(w, "")
@@ -214,7 +322,7 @@ func (s *Server) handleListSpecies(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get paginated entries
-	entries, err := s.db.ListOakEntriesPaginated(params.Limit, params.Offset, filter)
+	entries, err := reads.ListOakEntriesPaginated(params.Limit, params.Offset, filter)
 	if err != nil {
 		s.logger.Error("failed to list species", "error", err)
 		RespondInternalError(w, "")
@@ -231,54 +339,101 @@ func (s *Server) handleListSpecies(w http.ResponseWriter, r *http.Request) {
 }
 
 // handleGetSpecies handles GET /api/v1/species/{name}
+// Sends Last-Modified and honors If-Modified-Since.
 func (s *Server) handleGetSpecies(w http.ResponseWriter, r *http.Request) {
 	nameEncoded := chi.URLParam(r, "name")
 	if nameEncoded == "" {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "species name is required")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "species name is required")
 		return
 	}
 	name, err := url.PathUnescape(nameEncoded)
 	if err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid species name encoding")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid species name encoding")
 		return
 	}
 
-	entry, err := s.db.GetOakEntry(name)
+	s.respondSpecies(w, r, name)
+}
+
+// respondSpecies writes the species named name, honoring conditional
+// requests, or 404 if it does not exist or is a draft hidden from r
+func (s *Server) respondSpecies(w http.ResponseWriter, r *http.Request, name string) {
+	reads := s.readDB(r)
+	entry, err := reads.GetOakEntry(name)
 	if err != nil {
 		s.logger.Error("failed to get species", "name", name, "error", err)
 		RespondInternalError(w, "")
 		return
 	}
 
-	if entry == nil {
+	if entry == nil || s.hiddenDraft(r, entry.Visibility) {
 		RespondNotFound(w, "Species", name)
 		return
 	}
 
+	updatedAt, err := reads.SpeciesUpdatedAt(name)
+	if err != nil {
+		s.logger.Error("failed to get species updated_at", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !s.setETag(w, db.HashKindSpecies, name) {
+		return
+	}
+	if notModified(w, r, updatedAt) {
+		return
+	}
+	if s.respondHashOnly(w, r, db.HashKindSpecies, name) {
+		return
+	}
+
 	RespondJSON(w, http.StatusOK, entry)
 }
 
 // handleGetSpeciesFull handles GET /api/v1/species/{name}/full
-// Returns species with all source data embedded, including source metadata
+// Returns species with all source data embedded, including source metadata,
+// and the author abbreviations expanded to full names and dates.
+// ?units=metric|imperial|dual converts measurements in the source text.
 func (s *Server) handleGetSpeciesFull(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 	if name == "" {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "species name is required")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "species name is required")
+		return
+	}
+
+	system, ok := unitsParam(w, r)
+	if !ok {
 		return
 	}
 
-	entry, err := s.db.GetOakEntryWithSources(name)
+	entry, err := s.readDB(r).GetOakEntryWithSources(name)
 	if err != nil {
 		s.logger.Error("failed to get full species", "name", name, "error", err)
 		RespondInternalError(w, "")
 		return
 	}
 
-	if entry == nil {
+	if entry == nil || s.hiddenDraft(r, entry.Visibility) {
 		RespondNotFound(w, "Species", name)
 		return
 	}
 
+	for i := range entry.Sources {
+		s.redactSpeciesSources(r, &entry.Sources[i].SpeciesSource)
+		if system != "" {
+			units.ConvertSpeciesSource(&entry.Sources[i].SpeciesSource, system)
+		}
+	}
+	if entry.Author != nil {
+		entry.AuthorDetails, err = s.db.ExpandAuthor(*entry.Author)
+		if err != nil {
+			s.logger.Error("failed to expand author", "name", name, "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+	}
+
+	s.recordSpeciesView(r, &entry.OakEntry)
 	RespondJSON(w, http.StatusOK, entry)
 }
 
@@ -286,7 +441,7 @@ func (s *Server) handleGetSpeciesFull(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleSearchSpecies(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 	if query == "" {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "query parameter 'q' is required")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "query parameter 'q' is required")
 		return
 	}
 
@@ -298,7 +453,7 @@ func (s *Server) handleSearchSpecies(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	entries, err := s.db.SearchOakEntriesFull(query, limit)
+	entries, err := s.readDB(r).SearchOakEntriesFull(query, limit, s.isAuthenticated(r))
 	if err != nil {
 		s.logger.Error("failed to search species", "query", query, "error", err)
 		RespondInternalError(w, "")
@@ -319,28 +474,49 @@ func (s *Server) handleSearchSpecies(w http.ResponseWriter, r *http.Request) {
 // handleCreateSpecies handles POST /api/v1/species
 func (s *Server) handleCreateSpecies(w http.ResponseWriter, r *http.Request) {
 	var req SpeciesRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid JSON body")
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
 		return
 	}
 
 	// Validate request
-	if errors := validateSpeciesRequest(&req, true); len(errors) > 0 {
+	statuses, err := s.fieldTerms(models.VocabularyFieldConservationStatus)
+	if err != nil {
+		s.logger.Error("failed to get conservation status vocabulary", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if errors := validateSpeciesRequest(&req, true, statuses); len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+	if req.Genus == "" {
+		req.Genus = models.DefaultGenus
+	}
+	if errors, err := s.checkGenus(req.Genus, req.Subgenus); err != nil {
+		s.logger.Error("failed to check genus", "genus", req.Genus, "error", err)
+		RespondInternalError(w, "")
+		return
+	} else if len(errors) > 0 {
 		RespondValidationError(w, errors)
 		return
 	}
 
-	// Check if species already exists
-	exists, err := s.db.OakEntryExists(req.ScientificName)
+	// Check if species already exists, ignoring case and diacritics
+	existing, err := s.db.OakEntryNamesLike(req.ScientificName)
 	if err != nil {
 		s.logger.Error("failed to check species existence", "name", req.ScientificName, "error", err)
 		RespondInternalError(w, "")
 		return
 	}
-	if exists {
+	if slices.Contains(existing, req.ScientificName) {
 		RespondConflict(w, "species already exists: "+req.ScientificName)
 		return
 	}
+	if len(existing) > 0 {
+		RespondConflict(w, fmt.Sprintf("species already exists as %q, which differs only in case or diacritics", existing[0]))
+		return
+	}
 
 	// Create the entry
 	entry := requestToOakEntry(&req)
@@ -357,23 +533,29 @@ func (s *Server) handleCreateSpecies(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleUpdateSpecies(w http.ResponseWriter, r *http.Request) {
 	nameEncoded := chi.URLParam(r, "name")
 	if nameEncoded == "" {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "species name is required")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "species name is required")
 		return
 	}
 	name, err := url.PathUnescape(nameEncoded)
 	if err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid species name encoding")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid species name encoding")
 		return
 	}
 
 	var req SpeciesRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid JSON body")
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
 		return
 	}
 
 	// Validate request (not a create)
-	if errors := validateSpeciesRequest(&req, false); len(errors) > 0 {
+	statuses, err := s.fieldTerms(models.VocabularyFieldConservationStatus)
+	if err != nil {
+		s.logger.Error("failed to get conservation status vocabulary", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if errors := validateSpeciesRequest(&req, false, statuses); len(errors) > 0 {
 		RespondValidationError(w, errors)
 		return
 	}
@@ -389,10 +571,28 @@ func (s *Server) handleUpdateSpecies(w http.ResponseWriter, r *http.Request) {
 		RespondNotFound(w, "Species", name)
 		return
 	}
+	hash, failed := s.checkIfMatch(w, r, db.HashKindSpecies, name)
+	if failed {
+		return
+	}
 
 	// Merge updates into existing entry
 	entry := mergeOakEntry(existing, &req)
-	if err := s.db.SaveOakEntry(entry); err != nil {
+	if req.Genus != "" || req.Subgenus != nil {
+		if errors, err := s.checkGenus(entry.Genus, entry.Subgenus); err != nil {
+			s.logger.Error("failed to check genus", "genus", entry.Genus, "error", err)
+			RespondInternalError(w, "")
+			return
+		} else if len(errors) > 0 {
+			RespondValidationError(w, errors)
+			return
+		}
+	}
+	if err := s.db.SaveOakEntryIfHash(entry, hash); err != nil {
+		if errors.Is(err, db.ErrEntityChanged) {
+			s.respondRecordChanged(w, db.HashKindSpecies, name)
+			return
+		}
 		s.logger.Error("failed to update species", "name", name, "error", err)
 		RespondInternalError(w, "")
 		return
@@ -402,15 +602,17 @@ func (s *Server) handleUpdateSpecies(w http.ResponseWriter, r *http.Request) {
 }
 
 // handleDeleteSpecies handles DELETE /api/v1/species/{name}
+// ?redirect_to={name} keeps the deleted species' permalink working by
+// redirecting it to the species it was renamed to or merged into.
 func (s *Server) handleDeleteSpecies(w http.ResponseWriter, r *http.Request) {
 	nameEncoded := chi.URLParam(r, "name")
 	if nameEncoded == "" {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "species name is required")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "species name is required")
 		return
 	}
 	name, err := url.PathUnescape(nameEncoded)
 	if err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid species name encoding")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid species name encoding")
 		return
 	}
 
@@ -438,8 +640,28 @@ func (s *Server) handleDeleteSpecies(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A species renamed or merged into another leaves its permalink behind
+	var target string
+	if redirectTo := r.URL.Query().Get("redirect_to"); redirectTo != "" {
+		target, err = s.db.ResolveOakEntryName(redirectTo)
+		if err != nil {
+			s.logger.Error("failed to resolve redirect target for delete", "name", name, "target", redirectTo, "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+		if target == "" || target == name {
+			RespondValidationError(w, []ValidationError{{Field: "redirect_to", Message: "redirect_to must name another existing species"}})
+			return
+		}
+	}
+
 	// Delete the entry (cascades to species_sources via ON DELETE CASCADE)
-	if err := s.db.DeleteOakEntry(name); err != nil {
+	if target != "" {
+		err = s.db.DeleteOakEntryRedirecting(name, target)
+	} else {
+		err = s.db.DeleteOakEntry(name)
+	}
+	if err != nil {
 		s.logger.Error("failed to delete species", "name", name, "error", err)
 		RespondInternalError(w, "")
 		return
@@ -452,8 +674,13 @@ func (s *Server) handleDeleteSpecies(w http.ResponseWriter, r *http.Request) {
 func requestToOakEntry(req *SpeciesRequest) *models.OakEntry {
 	entry := models.NewOakEntry(req.ScientificName)
 	entry.Author = req.Author
+	entry.Pronunciation = req.Pronunciation
 	entry.IsHybrid = req.IsHybrid
 	entry.ConservationStatus = req.ConservationStatus
+	entry.Genus = req.Genus
+	if entry.Genus == "" {
+		entry.Genus = models.DefaultGenus
+	}
 	entry.Subgenus = req.Subgenus
 	entry.Section = req.Section
 	entry.Subsection = req.Subsection
@@ -472,6 +699,13 @@ func requestToOakEntry(req *SpeciesRequest) *models.OakEntry {
 	if req.Synonyms != nil {
 		entry.Synonyms = req.Synonyms
 	}
+	if req.ExternalLinks != nil {
+		entry.ExternalLinks = req.ExternalLinks
+	}
+	entry.Visibility = req.Visibility
+	if entry.Visibility == "" {
+		entry.Visibility = models.VisibilityPublished
+	}
 	return entry
 }
 
@@ -484,10 +718,16 @@ func mergeOakEntry(existing *models.OakEntry, req *SpeciesRequest) *models.OakEn
 	if req.Author != nil {
 		entry.Author = req.Author
 	}
+	if req.Pronunciation != nil {
+		entry.Pronunciation = req.Pronunciation
+	}
 	entry.IsHybrid = req.IsHybrid
 	if req.ConservationStatus != nil {
 		entry.ConservationStatus = req.ConservationStatus
 	}
+	if req.Genus != "" {
+		entry.Genus = req.Genus
+	}
 	if req.Subgenus != nil {
 		entry.Subgenus = req.Subgenus
 	}
@@ -518,6 +758,81 @@ func mergeOakEntry(existing *models.OakEntry, req *SpeciesRequest) *models.OakEn
 	if req.Synonyms != nil {
 		entry.Synonyms = req.Synonyms
 	}
+	if req.ExternalLinks != nil {
+		entry.ExternalLinks = req.ExternalLinks
+	}
+	if req.Visibility != "" {
+		entry.Visibility = req.Visibility
+	}
 
 	return &entry
 }
+
+// hiddenDraft reports whether an entry with this visibility must be hidden from the request
+func (s *Server) hiddenDraft(r *http.Request, visibility string) bool {
+	return visibility == models.VisibilityDraft && !s.isAuthenticated(r)
+}
+
+// readDB returns the database to serve a public read from, a replica when
+// there are any. Authenticated requests read the primary so curators see
+// their own writes at once.
+func (s *Server) readDB(r *http.Request) *db.Database {
+	if s.isAuthenticated(r) {
+		return s.db
+	}
+	return s.db.ForReads()
+}
+
+// speciesVisible reports whether a species exists and may be shown to the request
+func (s *Server) speciesVisible(r *http.Request, name string) (bool, error) {
+	visibility, err := s.db.GetOakEntryVisibility(name)
+	if err != nil || visibility == "" {
+		return false, err
+	}
+	return !s.hiddenDraft(r, visibility), nil
+}
+
+// VisibilityRequest is the request body for publishing or unpublishing a species.
+type VisibilityRequest struct {
+	Visibility string `json:"visibility"`
+}
+
+// handleSetSpeciesVisibility handles PUT /api/v1/species/{name}/visibility
+// Flips a species between draft and published.
+func (s *Server) handleSetSpeciesVisibility(w http.ResponseWriter, r *http.Request) {
+	name, err := url.PathUnescape(chi.URLParam(r, "name"))
+	if err != nil || name == "" {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid species name encoding")
+		return
+	}
+
+	var req VisibilityRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+	if req.Visibility != models.VisibilityDraft && req.Visibility != models.VisibilityPublished {
+		RespondValidationError(w, []ValidationError{{Field: "visibility", Message: "must be draft or published"}})
+		return
+	}
+
+	found, err := s.db.SetOakEntryVisibility(name, req.Visibility)
+	if err != nil {
+		s.logger.Error("failed to set species visibility", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !found {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+
+	entry, err := s.db.GetOakEntry(name)
+	if err != nil {
+		s.logger.Error("failed to get species", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, entry)
+}
@@ -1,34 +1,62 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/jeff/oaks/api/internal/db"
 	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/api/internal/units"
+	"github.com/jeff/oaks/pkg/apierror"
 )
 
+// ContentHashHeader carries a client-computed hash of a species-source's content.
+// Automated importers send it on writes; if the stored hash matches, the write is
+// skipped and 304 Not Modified is returned.
+const ContentHashHeader = "X-Content-Hash"
+
 // SpeciesSourceRequest represents the request body for creating/updating a species-source.
 type SpeciesSourceRequest struct {
-	SourceID         int64    `json:"source_id"`
-	LocalNames       []string `json:"local_names,omitempty"`
-	Range            *string  `json:"range,omitempty"`
-	GrowthHabit      *string  `json:"growth_habit,omitempty"`
-	Leaves           *string  `json:"leaves,omitempty"`
-	Flowers          *string  `json:"flowers,omitempty"`
-	Fruits           *string  `json:"fruits,omitempty"`
-	Bark             *string  `json:"bark,omitempty"`
-	Twigs            *string  `json:"twigs,omitempty"`
-	Buds             *string  `json:"buds,omitempty"`
-	HardinessHabitat *string  `json:"hardiness_habitat,omitempty"`
-	Miscellaneous    *string  `json:"miscellaneous,omitempty"`
-	URL              *string  `json:"url,omitempty"`
-	IsPreferred      bool     `json:"is_preferred"`
+	SourceID               int64    `json:"source_id"`
+	LocalNames             []string `json:"local_names,omitempty"`
+	Range                  *string  `json:"range,omitempty"`
+	GrowthHabit            *string  `json:"growth_habit,omitempty"`
+	Leaves                 *string  `json:"leaves,omitempty"`
+	Flowers                *string  `json:"flowers,omitempty"`
+	Fruits                 *string  `json:"fruits,omitempty"`
+	Bark                   *string  `json:"bark,omitempty"`
+	Twigs                  *string  `json:"twigs,omitempty"`
+	Buds                   *string  `json:"buds,omitempty"`
+	HardinessHabitat       *string  `json:"hardiness_habitat,omitempty"`
+	Miscellaneous          *string  `json:"miscellaneous,omitempty"`
+	DistinguishingFeatures *string  `json:"distinguishing_features,omitempty"`
+	URL                    *string  `json:"url,omitempty"`
+	// Pages and FieldPages locate the data in a printed source; on update an
+	// empty field_pages entry removes that field's locator
+	Pages      *string           `json:"pages,omitempty"`
+	FieldPages map[string]string `json:"field_pages,omitempty"`
+	// FieldVisibility sets text fields public or internal; on update fields
+	// not listed keep their visibility
+	FieldVisibility map[string]string `json:"field_visibility,omitempty"`
+	IsPreferred     bool              `json:"is_preferred"`
+	// Transcription provenance; changing the method or OCR confidence of a
+	// verified record makes it unverified again
+	Transcriber         *string  `json:"transcriber,omitempty"`
+	TranscriptionMethod *string  `json:"transcription_method,omitempty"`
+	OCRConfidence       *float64 `json:"ocr_confidence,omitempty"`
 }
 
+// maxLocatorLength bounds page locators ("pp. 112-114, pl. 23")
+const maxLocatorLength = 100
+
 // validateSpeciesSourceRequest validates a species-source request.
 func validateSpeciesSourceRequest(req SpeciesSourceRequest) []ValidationError {
 	var errors []ValidationError
@@ -39,25 +67,153 @@ func validateSpeciesSourceRequest(req SpeciesSourceRequest) []ValidationError {
 			Message: "source_id must be a positive integer",
 		})
 	}
+	errors = append(errors, validateSpeciesSourceFields("", &req)...)
+
+	return errors
+}
+
+// validateSpeciesSourceFields checks the fields every species source write
+// validates, alone or as an item of a bulk upsert. prefix is prepended to
+// error fields.
+func validateSpeciesSourceFields(prefix string, req *SpeciesSourceRequest) []ValidationError {
+	errors := validateLocators(prefix, req.Pages, req.FieldPages)
+	errors = append(errors, validateFieldVisibility(prefix, req.FieldVisibility)...)
+	return append(errors, validateTranscription(prefix, req)...)
+}
+
+// validateTranscription checks a request's transcription provenance. prefix
+// is prepended to error fields.
+func validateTranscription(prefix string, req *SpeciesSourceRequest) []ValidationError {
+	var errors []ValidationError
+	if req.TranscriptionMethod != nil && !slices.Contains(models.TranscriptionMethods, *req.TranscriptionMethod) {
+		errors = append(errors, ValidationError{
+			Field:   prefix + "transcription_method",
+			Message: "transcription_method must be one of: " + strings.Join(models.TranscriptionMethods, ", "),
+		})
+	}
+	if req.OCRConfidence != nil && (*req.OCRConfidence < 0 || *req.OCRConfidence > 1) {
+		errors = append(errors, ValidationError{Field: prefix + "ocr_confidence", Message: "ocr_confidence must be between 0 and 1"})
+	}
+	return errors
+}
 
+// validateLocators checks a request's page locators. field_pages keys must
+// name species source text fields. prefix is prepended to error fields.
+func validateLocators(prefix string, pages *string, fieldPages map[string]string) []ValidationError {
+	var errors []ValidationError
+	if pages != nil && len(*pages) > maxLocatorLength {
+		errors = append(errors, ValidationError{
+			Field:   prefix + "pages",
+			Message: fmt.Sprintf("pages must be at most %d characters", maxLocatorLength),
+		})
+	}
+	fields := make([]string, 0, len(fieldPages))
+	for field := range fieldPages {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		switch {
+		case !models.IsTextField(field):
+			errors = append(errors, ValidationError{
+				Field:   prefix + "field_pages." + field,
+				Message: "not a species source text field",
+			})
+		case len(fieldPages[field]) > maxLocatorLength:
+			errors = append(errors, ValidationError{
+				Field:   prefix + "field_pages." + field,
+				Message: fmt.Sprintf("locator must be at most %d characters", maxLocatorLength),
+			})
+		}
+	}
 	return errors
 }
 
+// validateFieldVisibility checks a request's field visibility levels. Keys
+// must name species source text fields. prefix is prepended to error fields.
+func validateFieldVisibility(prefix string, visibility map[string]string) []ValidationError {
+	var errors []ValidationError
+	fields := make([]string, 0, len(visibility))
+	for field := range visibility {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		switch {
+		case !models.IsTextField(field):
+			errors = append(errors, ValidationError{
+				Field:   prefix + "field_visibility." + field,
+				Message: "not a species source text field",
+			})
+		case !slices.Contains(models.FieldVisibilities, visibility[field]):
+			errors = append(errors, ValidationError{
+				Field:   prefix + "field_visibility." + field,
+				Message: "visibility must be one of: " + strings.Join(models.FieldVisibilities, ", "),
+			})
+		}
+	}
+	return errors
+}
+
+// mergeFieldVisibility applies updates to a set of field visibilities. Only
+// internal fields are kept, so a field updated to public is removed. The
+// result is never nil, telling a bulk upsert the visibility was given.
+func mergeFieldVisibility(existing, updates map[string]string) map[string]string {
+	merged := make(map[string]string, len(existing)+len(updates))
+	for field, visibility := range existing {
+		merged[field] = visibility
+	}
+	for field, visibility := range updates {
+		if visibility == models.FieldInternal {
+			merged[field] = visibility
+		} else {
+			delete(merged, field)
+		}
+	}
+	return merged
+}
+
+// mergeFieldPages applies updates to a set of field locators, removing those
+// updated to "". Returns nil when none are left.
+func mergeFieldPages(existing, updates map[string]string) map[string]string {
+	merged := make(map[string]string, len(existing)+len(updates))
+	for field, locator := range existing {
+		merged[field] = locator
+	}
+	for field, locator := range updates {
+		if locator = strings.TrimSpace(locator); locator == "" {
+			delete(merged, field)
+		} else {
+			merged[field] = locator
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
 // handleListSpeciesSources handles GET /api/v1/species/{name}/sources
+// ?units=metric|imperial|dual converts measurements in the text fields.
 func (s *Server) handleListSpeciesSources(w http.ResponseWriter, r *http.Request) {
 	nameEncoded := chi.URLParam(r, "name")
 	if nameEncoded == "" {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "species name is required")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "species name is required")
 		return
 	}
 	name, err := url.PathUnescape(nameEncoded)
 	if err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid species name encoding")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid species name encoding")
 		return
 	}
 
-	// Check if species exists
-	exists, err := s.db.OakEntryExists(name)
+	system, ok := unitsParam(w, r)
+	if !ok {
+		return
+	}
+
+	// Check if species exists (drafts are hidden from the public)
+	exists, err := s.speciesVisible(r, name)
 	if err != nil {
 		s.logger.Error("failed to check species existence", "name", name, "error", err)
 		RespondInternalError(w, "")
@@ -79,6 +235,12 @@ func (s *Server) handleListSpeciesSources(w http.ResponseWriter, r *http.Request
 	if sources == nil {
 		sources = []*models.SpeciesSource{}
 	}
+	s.redactSpeciesSources(r, sources...)
+	if system != "" {
+		for _, ss := range sources {
+			units.ConvertSpeciesSource(ss, system)
+		}
+	}
 
 	RespondJSON(w, http.StatusOK, sources)
 }
@@ -87,24 +249,29 @@ func (s *Server) handleListSpeciesSources(w http.ResponseWriter, r *http.Request
 func (s *Server) handleGetSpeciesSource(w http.ResponseWriter, r *http.Request) {
 	nameEncoded := chi.URLParam(r, "name")
 	if nameEncoded == "" {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "species name is required")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "species name is required")
 		return
 	}
 	name, err := url.PathUnescape(nameEncoded)
 	if err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid species name encoding")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid species name encoding")
 		return
 	}
 
 	sourceIDParam := chi.URLParam(r, "sourceId")
 	sourceID, parseErr := strconv.ParseInt(sourceIDParam, 10, 64)
 	if parseErr != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid source ID")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid source ID")
 		return
 	}
 
-	// Check if species exists
-	exists, err := s.db.OakEntryExists(name)
+	system, ok := unitsParam(w, r)
+	if !ok {
+		return
+	}
+
+	// Check if species exists (drafts are hidden from the public)
+	exists, err := s.speciesVisible(r, name)
 	if err != nil {
 		s.logger.Error("failed to check species existence", "name", name, "error", err)
 		RespondInternalError(w, "")
@@ -125,6 +292,17 @@ func (s *Server) handleGetSpeciesSource(w http.ResponseWriter, r *http.Request)
 		RespondNotFound(w, "SpeciesSource", sourceIDParam)
 		return
 	}
+	key := name + "/" + strconv.FormatInt(sourceID, 10)
+	if !s.setETag(w, db.HashKindSpeciesSources, key) {
+		return
+	}
+	if s.respondHashOnly(w, r, db.HashKindSpeciesSources, key) {
+		return
+	}
+	s.redactSpeciesSources(r, speciesSource)
+	if system != "" {
+		units.ConvertSpeciesSource(speciesSource, system)
+	}
 
 	RespondJSON(w, http.StatusOK, speciesSource)
 }
@@ -133,18 +311,18 @@ func (s *Server) handleGetSpeciesSource(w http.ResponseWriter, r *http.Request)
 func (s *Server) handleCreateSpeciesSource(w http.ResponseWriter, r *http.Request) {
 	nameEncoded := chi.URLParam(r, "name")
 	if nameEncoded == "" {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "species name is required")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "species name is required")
 		return
 	}
 	name, err := url.PathUnescape(nameEncoded)
 	if err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid species name encoding")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid species name encoding")
 		return
 	}
 
 	var req SpeciesSourceRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid JSON body")
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
 		return
 	}
 
@@ -176,6 +354,7 @@ func (s *Server) handleCreateSpeciesSource(w http.ResponseWriter, r *http.Reques
 		RespondNotFound(w, "Source", strconv.FormatInt(req.SourceID, 10))
 		return
 	}
+	warnIfSuperseded(w, source)
 
 	// Check if species-source combination already exists
 	existing, err := s.db.GetSpeciesSourceBySourceID(name, req.SourceID)
@@ -185,6 +364,9 @@ func (s *Server) handleCreateSpeciesSource(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	if existing != nil {
+		if s.respondIfContentUnchanged(w, r, name, req.SourceID) {
+			return
+		}
 		RespondConflict(w, "species-source combination already exists")
 		return
 	}
@@ -195,6 +377,11 @@ func (s *Server) handleCreateSpeciesSource(w http.ResponseWriter, r *http.Reques
 		RespondInternalError(w, "")
 		return
 	}
+	if !s.recordContentHash(w, r, name, req.SourceID) {
+		return
+	}
+	s.refreshMentions(name)
+	s.refreshMeasurements(name)
 
 	RespondJSON(w, http.StatusCreated, speciesSource)
 }
@@ -203,25 +390,29 @@ func (s *Server) handleCreateSpeciesSource(w http.ResponseWriter, r *http.Reques
 func (s *Server) handleUpdateSpeciesSource(w http.ResponseWriter, r *http.Request) {
 	nameEncoded := chi.URLParam(r, "name")
 	if nameEncoded == "" {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "species name is required")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "species name is required")
 		return
 	}
 	name, err := url.PathUnescape(nameEncoded)
 	if err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid species name encoding")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid species name encoding")
 		return
 	}
 
 	sourceIDParam := chi.URLParam(r, "sourceId")
 	sourceID, parseErr := strconv.ParseInt(sourceIDParam, 10, 64)
 	if parseErr != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid source ID")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid source ID")
 		return
 	}
 
 	var req SpeciesSourceRequest
-	if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid JSON body")
+	if decodeErr := decodeRequestBody(r, &req); decodeErr != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+	if errors := validateSpeciesSourceFields("", &req); len(errors) > 0 {
+		RespondValidationError(w, errors)
 		return
 	}
 
@@ -248,14 +439,38 @@ func (s *Server) handleUpdateSpeciesSource(w http.ResponseWriter, r *http.Reques
 		RespondNotFound(w, "SpeciesSource", sourceIDParam)
 		return
 	}
+	source, err := s.db.GetSource(sourceID)
+	if err != nil {
+		s.logger.Error("failed to get source", "sourceId", sourceID, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	hashKey := name + "/" + strconv.FormatInt(sourceID, 10)
+	hash, failed := s.checkIfMatch(w, r, db.HashKindSpeciesSources, hashKey)
+	if failed {
+		return
+	}
+	warnIfSuperseded(w, source)
+	if s.respondIfContentUnchanged(w, r, name, sourceID) {
+		return
+	}
 
 	// Merge updates into existing record
 	speciesSource := mergeSpeciesSource(existing, &req)
-	if err := s.db.SaveSpeciesSource(speciesSource); err != nil {
+	if err := s.db.SaveSpeciesSourceIfHash(speciesSource, hash); err != nil {
+		if errors.Is(err, db.ErrEntityChanged) {
+			s.respondRecordChanged(w, db.HashKindSpeciesSources, hashKey)
+			return
+		}
 		s.logger.Error("failed to update species source", "name", name, "sourceId", sourceID, "error", err)
 		RespondInternalError(w, "")
 		return
 	}
+	if !s.recordContentHash(w, r, name, sourceID) {
+		return
+	}
+	s.refreshMentions(name)
+	s.refreshMeasurements(name)
 
 	RespondJSON(w, http.StatusOK, speciesSource)
 }
@@ -264,19 +479,19 @@ func (s *Server) handleUpdateSpeciesSource(w http.ResponseWriter, r *http.Reques
 func (s *Server) handleDeleteSpeciesSource(w http.ResponseWriter, r *http.Request) {
 	nameEncoded := chi.URLParam(r, "name")
 	if nameEncoded == "" {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "species name is required")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "species name is required")
 		return
 	}
 	name, err := url.PathUnescape(nameEncoded)
 	if err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid species name encoding")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid species name encoding")
 		return
 	}
 
 	sourceIDParam := chi.URLParam(r, "sourceId")
 	sourceID, parseErr := strconv.ParseInt(sourceIDParam, 10, 64)
 	if parseErr != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid source ID")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid source ID")
 		return
 	}
 
@@ -309,10 +524,249 @@ func (s *Server) handleDeleteSpeciesSource(w http.ResponseWriter, r *http.Reques
 		RespondInternalError(w, "")
 		return
 	}
+	s.refreshMentions(name)
+	s.refreshMeasurements(name)
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// PreferredSourceRequest is the request body for setting a species' preferred source.
+type PreferredSourceRequest struct {
+	SourceID int64 `json:"source_id"`
+}
+
+// handleSetPreferredSource handles PUT /api/v1/species/{name}/preferred-source
+// Marks one source as preferred and clears the flag on the species' other sources.
+func (s *Server) handleSetPreferredSource(w http.ResponseWriter, r *http.Request) {
+	nameEncoded := chi.URLParam(r, "name")
+	if nameEncoded == "" {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "species name is required")
+		return
+	}
+	name, err := url.PathUnescape(nameEncoded)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid species name encoding")
+		return
+	}
+
+	var req PreferredSourceRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+	if req.SourceID <= 0 {
+		RespondValidationError(w, []ValidationError{{Field: "source_id", Message: "source_id must be a positive integer"}})
+		return
+	}
+
+	exists, err := s.db.OakEntryExists(name)
+	if err != nil {
+		s.logger.Error("failed to check species existence", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !exists {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+
+	found, err := s.db.SetPreferredSource(name, req.SourceID)
+	if err != nil {
+		s.logger.Error("failed to set preferred source", "name", name, "sourceId", req.SourceID, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !found {
+		RespondNotFound(w, "SpeciesSource", strconv.FormatInt(req.SourceID, 10))
+		return
+	}
+
+	sources, err := s.db.GetSpeciesSources(name)
+	if err != nil {
+		s.logger.Error("failed to get species sources", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, sources)
+}
+
+// BulkSpeciesSourceItem is one species' data in a bulk species-source upsert.
+// SourceID is taken from the URL and may be omitted.
+type BulkSpeciesSourceItem struct {
+	ScientificName string `json:"scientific_name"`
+	ContentHash    string `json:"content_hash,omitempty"`
+	SpeciesSourceRequest
+}
+
+// BulkSpeciesSourcesResponse reports the outcome of a bulk species-source upsert.
+type BulkSpeciesSourcesResponse struct {
+	SourceID  int64                          `json:"source_id"`
+	Created   int                            `json:"created"`
+	Updated   int                            `json:"updated"`
+	Unchanged int                            `json:"unchanged"`
+	Results   []db.SpeciesSourceUpsertResult `json:"results"`
+}
+
+// validateBulkSpeciesSourceItems validates a bulk upsert body against the URL's source ID.
+func validateBulkSpeciesSourceItems(sourceID int64, items []BulkSpeciesSourceItem) []ValidationError {
+	var errors []ValidationError
+	seen := make(map[string]bool, len(items))
+
+	for i, item := range items {
+		field := fmt.Sprintf("items[%d]", i)
+		switch {
+		case item.ScientificName == "":
+			errors = append(errors, ValidationError{Field: field + ".scientific_name", Message: "scientific_name is required"})
+		case seen[item.ScientificName]:
+			errors = append(errors, ValidationError{Field: field + ".scientific_name", Message: fmt.Sprintf("duplicate species '%s'", item.ScientificName)})
+		}
+		seen[item.ScientificName] = true
+
+		if item.SourceID != 0 && item.SourceID != sourceID {
+			errors = append(errors, ValidationError{Field: field + ".source_id", Message: "source_id must match the source in the URL"})
+		}
+		errors = append(errors, validateSpeciesSourceFields(field+".", &item.SpeciesSourceRequest)...)
+	}
+
+	return errors
+}
+
+// maxBulkBodySize is the largest body the bulk upsert accepts (16MB), in
+// place of maxBodySize: one source's full set of species accounts fits
+const maxBulkBodySize = 16 << 20
+
+// handleBulkUpsertSpeciesSources handles PUT /api/v1/sources/{id}/species-sources
+// With an X-Import-Session header the upload is staged in that session instead.
+func (s *Server) handleBulkUpsertSpeciesSources(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	sourceID, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid source ID")
+		return
+	}
+
+	var items []BulkSpeciesSourceItem
+	if err := decodeRequestBody(r, &items); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			RespondError(w, http.StatusRequestEntityTooLarge, apierror.CodeValidation,
+				fmt.Sprintf("request body must be at most %dMB; split the upload into batches", maxBulkBodySize>>20))
+			return
+		}
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+
+	if errors := validateBulkSpeciesSourceItems(sourceID, items); len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+
+	source, err := s.db.GetSource(sourceID)
+	if err != nil {
+		s.logger.Error("failed to check source existence", "sourceId", sourceID, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if source == nil {
+		RespondNotFound(w, "Source", idParam)
+		return
+	}
+	warnIfSuperseded(w, source)
+
+	rows := make([]db.SpeciesSourceUpsert, len(items))
+	for i := range items {
+		rows[i] = db.SpeciesSourceUpsert{
+			SpeciesSource: requestToSpeciesSource(items[i].ScientificName, &items[i].SpeciesSourceRequest),
+			ContentHash:   items[i].ContentHash,
+		}
+	}
+
+	if r.Header.Get(ImportSessionHeader) != "" {
+		s.stageSpeciesSources(w, r, sourceID, rows)
+		return
+	}
+
+	results, applied, err := s.db.BulkUpsertSpeciesSources(sourceID, rows)
+	if err != nil {
+		s.logger.Error("failed to bulk upsert species sources", "sourceId", sourceID, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	if !applied {
+		// Nothing was written; report every failed row
+		var errors []ValidationError
+		for i, res := range results {
+			if res.Status == db.UpsertFailed {
+				errors = append(errors, ValidationError{Field: fmt.Sprintf("items[%d]", i), Message: res.Error})
+			}
+		}
+		RespondValidationError(w, errors)
+		return
+	}
+
+	resp := BulkSpeciesSourcesResponse{SourceID: sourceID, Results: results}
+	var changed []string
+	for _, res := range results {
+		switch res.Status {
+		case db.UpsertCreated:
+			resp.Created++
+			changed = append(changed, res.ScientificName)
+		case db.UpsertUpdated:
+			resp.Updated++
+			changed = append(changed, res.ScientificName)
+		case db.UpsertUnchanged:
+			resp.Unchanged++
+		}
+	}
+	s.refreshMentions(changed...)
+	s.refreshMeasurements(changed...)
+
+	RespondJSON(w, http.StatusOK, resp)
+}
+
+// respondIfContentUnchanged writes 304 Not Modified when the request's X-Content-Hash
+// matches the hash stored for the species-source. Returns true if a response was written.
+func (s *Server) respondIfContentUnchanged(w http.ResponseWriter, r *http.Request, name string, sourceID int64) bool {
+	hash := r.Header.Get(ContentHashHeader)
+	if hash == "" {
+		return false
+	}
+
+	stored, err := s.db.GetSpeciesSourceContentHash(name, sourceID)
+	if err != nil {
+		s.logger.Error("failed to get content hash", "name", name, "sourceId", sourceID, "error", err)
+		RespondInternalError(w, "")
+		return true
+	}
+	if stored != hash {
+		return false
+	}
+
+	w.Header().Set(ContentHashHeader, hash)
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// recordContentHash stores the request's X-Content-Hash after a successful write.
+// Returns false if an error response was written.
+func (s *Server) recordContentHash(w http.ResponseWriter, r *http.Request, name string, sourceID int64) bool {
+	hash := r.Header.Get(ContentHashHeader)
+	if hash == "" {
+		return true
+	}
+
+	if err := s.db.SetSpeciesSourceContentHash(name, sourceID, hash); err != nil {
+		s.logger.Error("failed to set content hash", "name", name, "sourceId", sourceID, "error", err)
+		RespondInternalError(w, "")
+		return false
+	}
+	w.Header().Set(ContentHashHeader, hash)
+	return true
+}
+
 // requestToSpeciesSource converts a request to a SpeciesSource model.
 func requestToSpeciesSource(scientificName string, req *SpeciesSourceRequest) *models.SpeciesSource {
 	ss := models.NewSpeciesSource(scientificName, req.SourceID)
@@ -326,8 +780,17 @@ func requestToSpeciesSource(scientificName string, req *SpeciesSourceRequest) *m
 	ss.Buds = req.Buds
 	ss.HardinessHabitat = req.HardinessHabitat
 	ss.Miscellaneous = req.Miscellaneous
+	ss.DistinguishingFeatures = req.DistinguishingFeatures
 	ss.URL = req.URL
+	ss.Pages = req.Pages
+	ss.FieldPages = mergeFieldPages(nil, req.FieldPages)
+	if req.FieldVisibility != nil {
+		ss.FieldVisibility = mergeFieldVisibility(nil, req.FieldVisibility)
+	}
 	ss.IsPreferred = req.IsPreferred
+	ss.Transcriber = req.Transcriber
+	ss.TranscriptionMethod = req.TranscriptionMethod
+	ss.OCRConfidence = req.OCRConfidence
 	if req.LocalNames != nil {
 		ss.LocalNames = req.LocalNames
 	}
@@ -371,10 +834,46 @@ func mergeSpeciesSource(existing *models.SpeciesSource, req *SpeciesSourceReques
 	if req.Miscellaneous != nil {
 		ss.Miscellaneous = req.Miscellaneous
 	}
+	if req.DistinguishingFeatures != nil {
+		ss.DistinguishingFeatures = req.DistinguishingFeatures
+	}
 	if req.URL != nil {
 		ss.URL = req.URL
 	}
+	if req.Pages != nil {
+		ss.Pages = req.Pages
+	}
+	if req.FieldPages != nil {
+		ss.FieldPages = mergeFieldPages(existing.FieldPages, req.FieldPages)
+	}
+	if req.FieldVisibility != nil {
+		ss.FieldVisibility = mergeFieldVisibility(existing.FieldVisibility, req.FieldVisibility)
+	}
+	if req.Transcriber != nil {
+		ss.Transcriber = req.Transcriber
+	}
+	retranscribed := false
+	if req.TranscriptionMethod != nil && !equalPtr(req.TranscriptionMethod, existing.TranscriptionMethod) {
+		ss.TranscriptionMethod = req.TranscriptionMethod
+		retranscribed = true
+	}
+	if req.OCRConfidence != nil && !equalPtr(req.OCRConfidence, existing.OCRConfidence) {
+		ss.OCRConfidence = req.OCRConfidence
+		retranscribed = true
+	}
+	if retranscribed {
+		// A new transcription needs checking again
+		ss.Verified, ss.VerifiedAt, ss.VerifiedBy = false, nil, nil
+	}
 	ss.IsPreferred = req.IsPreferred
 
 	return &ss
 }
+
+// equalPtr reports whether two optional values are both unset or equal
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -10,6 +10,8 @@ type StatsResponse struct {
 	HybridCount  int `json:"hybrid_count"`
 	TaxaCount    int `json:"taxa_count"`
 	SourceCount  int `json:"source_count"`
+
+	SourcesByType map[string]int `json:"sources_by_type"` // Source type -> count
 }
 
 // handleStats returns aggregate counts for the database
@@ -26,5 +28,7 @@ func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
 		HybridCount:  stats.HybridCount,
 		TaxaCount:    stats.TaxaCount,
 		SourceCount:  stats.SourceCount,
+
+		SourcesByType: stats.SourcesByType,
 	})
 }
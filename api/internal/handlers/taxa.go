@@ -1,21 +1,24 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/jeff/oaks/api/internal/db"
 	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/pkg/apierror"
 )
 
 // TaxonRequest is the request body for creating or updating a taxon.
 type TaxonRequest struct {
 	Name   string             `json:"name"`
 	Level  models.TaxonLevel  `json:"level"`
+	Genus  string             `json:"genus,omitempty"` // Defaults to Quercus on create
 	Parent *string            `json:"parent,omitempty"`
 	Author *string            `json:"author,omitempty"`
 	Notes  *string            `json:"notes,omitempty"`
@@ -26,10 +29,11 @@ type TaxonRequest struct {
 type TaxonResponse struct {
 	Name         string             `json:"name"`
 	Level        models.TaxonLevel  `json:"level"`
+	Genus        string             `json:"genus"`
 	Parent       *string            `json:"parent,omitempty"`
 	Author       *string            `json:"author,omitempty"`
 	Notes        *string            `json:"notes,omitempty"`
-	Links        []models.TaxonLink `json:"links,omitempty"`
+	Links        []models.TaxonLink `json:"links"`
 	SpeciesCount int                `json:"species_count"`
 }
 
@@ -38,42 +42,78 @@ func taxonToResponse(t *models.Taxon) TaxonResponse {
 	resp := TaxonResponse{
 		Name:         t.Name,
 		Level:        t.Level,
+		Genus:        t.Genus,
 		Parent:       t.Parent,
 		Author:       t.Author,
 		Notes:        t.Notes,
+		Links:        t.Links,
 		SpeciesCount: t.SpeciesCount,
 	}
-	if len(t.Links) > 0 {
-		resp.Links = t.Links
+	if resp.Links == nil {
+		resp.Links = []models.TaxonLink{}
 	}
 	return resp
 }
 
-// validTaxonLevels is the set of valid taxon levels.
-var validTaxonLevels = map[models.TaxonLevel]bool{
-	models.TaxonLevelSubgenus:   true,
-	models.TaxonLevelSection:    true,
-	models.TaxonLevelSubsection: true,
-	models.TaxonLevelComplex:    true,
+// parseTaxonLevel parses a taxon level string and checks it against the
+// configured taxon levels. A non-empty error list means the level is unknown.
+func (s *Server) parseTaxonLevel(raw string) (models.TaxonLevel, []ValidationError, error) {
+	level := models.TaxonLevel(strings.ToLower(raw))
+	levels, err := s.db.ListTaxonLevels()
+	if err != nil {
+		return level, nil, err
+	}
+	names := make([]string, 0, len(levels))
+	for _, l := range levels {
+		if l.Name == string(level) {
+			return level, nil, nil
+		}
+		names = append(names, l.Name)
+	}
+	return level, []ValidationError{
+		{Field: "level", Message: "must be one of: " + strings.Join(names, ", ")},
+	}, nil
 }
 
-// parseTaxonLevel parses and validates a taxon level string.
-func parseTaxonLevel(s string) (models.TaxonLevel, bool) {
-	level := models.TaxonLevel(strings.ToLower(s))
-	return level, validTaxonLevels[level]
+// parseFresh parses ?fresh=true, which counts species live instead of
+// reading the stored counts (for checking them)
+func parseFresh(r *http.Request) (bool, []ValidationError) {
+	v := r.URL.Query().Get("fresh")
+	if v == "" {
+		return false, nil
+	}
+	fresh, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, []ValidationError{{Field: "fresh", Message: "must be true or false"}}
+	}
+	return fresh, nil
 }
 
 // handleListTaxa handles GET /api/v1/taxa
+// Paged with ?limit and ?offset; without a limit, every matching taxon is returned.
+// Species counts are the stored ones unless ?fresh=true.
 func (s *Server) handleListTaxa(w http.ResponseWriter, r *http.Request) {
 	params := &db.TaxaListParams{}
+	var pageErrors []ValidationError
+	params.Limit, params.Offset, pageErrors = parsePageParams(r.URL.Query(), 0)
+	var freshErrors []ValidationError
+	params.Fresh, freshErrors = parseFresh(r)
+	pageErrors = append(pageErrors, freshErrors...)
+	if len(pageErrors) > 0 {
+		RespondValidationError(w, pageErrors)
+		return
+	}
 
 	// Check for optional level filter
 	if levelParam := r.URL.Query().Get("level"); levelParam != "" {
-		level, valid := parseTaxonLevel(levelParam)
-		if !valid {
-			RespondValidationError(w, []ValidationError{
-				{Field: "level", Message: "must be one of: subgenus, section, subsection, complex"},
-			})
+		level, errors, err := s.parseTaxonLevel(levelParam)
+		if err != nil {
+			s.logger.Error("failed to list taxon levels", "error", err)
+			RespondInternalError(w, "Failed to retrieve taxa")
+			return
+		}
+		if len(errors) > 0 {
+			RespondValidationError(w, errors)
 			return
 		}
 		params.Level = &level
@@ -83,6 +123,14 @@ func (s *Server) handleListTaxa(w http.ResponseWriter, r *http.Request) {
 	if parentParam := r.URL.Query().Get("parent"); parentParam != "" {
 		params.Parent = &parentParam
 	}
+	params.Genus = genusParam(r)
+
+	total, err := s.db.CountTaxa(params)
+	if err != nil {
+		s.logger.Error("failed to count taxa", "error", err)
+		RespondInternalError(w, "Failed to retrieve taxa")
+		return
+	}
 
 	taxa, err := s.db.ListTaxa(params)
 	if err != nil {
@@ -97,31 +145,47 @@ func (s *Server) handleListTaxa(w http.ResponseWriter, r *http.Request) {
 		data = append(data, taxonToResponse(t))
 	}
 
-	// Return paginated response (all results, no pagination needed for taxa)
-	resp := NewListResponse(data, len(data), len(data), 0)
-	RespondJSON(w, http.StatusOK, resp)
+	limit := params.Limit
+	if limit == 0 {
+		limit = len(data)
+	}
+	RespondJSON(w, http.StatusOK, NewListResponse(data, total, limit, params.Offset))
 }
 
 // handleGetTaxon handles GET /api/v1/taxa/{level}/{name}
+// Sends Last-Modified and honors If-Modified-Since. ?fresh=true counts species live.
 func (s *Server) handleGetTaxon(w http.ResponseWriter, r *http.Request) {
 	levelParam := chi.URLParam(r, "level")
 	nameEncoded := chi.URLParam(r, "name")
 
-	level, valid := parseTaxonLevel(levelParam)
-	if !valid {
-		RespondValidationError(w, []ValidationError{
-			{Field: "level", Message: "must be one of: subgenus, section, subsection, complex"},
-		})
+	level, errors, err := s.parseTaxonLevel(levelParam)
+	if err != nil {
+		s.logger.Error("failed to list taxon levels", "error", err)
+		RespondInternalError(w, "Failed to retrieve taxon")
+		return
+	}
+	if len(errors) > 0 {
+		RespondValidationError(w, errors)
 		return
 	}
 
 	name, err := url.PathUnescape(nameEncoded)
 	if err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid taxon name encoding")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid taxon name encoding")
 		return
 	}
 
-	taxon, err := s.db.GetTaxon(name, level)
+	fresh, freshErrors := parseFresh(r)
+	if len(freshErrors) > 0 {
+		RespondValidationError(w, freshErrors)
+		return
+	}
+
+	getTaxon := s.db.GetTaxon
+	if fresh {
+		getTaxon = s.db.GetTaxonFresh
+	}
+	taxon, err := getTaxon(name, level)
 	if err != nil {
 		s.logger.Error("failed to get taxon", "error", err, "name", name, "level", level)
 		RespondInternalError(w, "Failed to retrieve taxon")
@@ -133,15 +197,31 @@ func (s *Server) handleGetTaxon(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	updatedAt, err := s.db.TaxonUpdatedAt(name, level)
+	if err != nil {
+		s.logger.Error("failed to get taxon updated_at", "error", err, "name", name, "level", level)
+		RespondInternalError(w, "Failed to retrieve taxon")
+		return
+	}
+	if !s.setETag(w, db.HashKindTaxa, string(level)+"/"+name) {
+		return
+	}
+	if notModified(w, r, updatedAt) {
+		return
+	}
+	if s.respondHashOnly(w, r, db.HashKindTaxa, string(level)+"/"+name) {
+		return
+	}
+
 	RespondJSON(w, http.StatusOK, taxonToResponse(taxon))
 }
 
 // handleCreateTaxon handles POST /api/v1/taxa
 func (s *Server) handleCreateTaxon(w http.ResponseWriter, r *http.Request) {
 	var req TaxonRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeRequestBody(r, &req); err != nil {
 		RespondValidationError(w, []ValidationError{
-			{Field: "body", Message: "invalid JSON body"},
+			{Field: "body", Message: invalidBodyMessage(r)},
 		})
 		return
 	}
@@ -153,8 +233,25 @@ func (s *Server) handleCreateTaxon(w http.ResponseWriter, r *http.Request) {
 	}
 	if req.Level == "" {
 		errors = append(errors, ValidationError{Field: "level", Message: "is required"})
-	} else if !validTaxonLevels[req.Level] {
-		errors = append(errors, ValidationError{Field: "level", Message: "must be one of: subgenus, section, subsection, complex"})
+	} else {
+		level, levelErrors, err := s.parseTaxonLevel(string(req.Level))
+		if err != nil {
+			s.logger.Error("failed to list taxon levels", "error", err)
+			RespondInternalError(w, "Failed to create taxon")
+			return
+		}
+		req.Level = level
+		errors = append(errors, levelErrors...)
+	}
+	if req.Genus == "" {
+		req.Genus = models.DefaultGenus
+	}
+	if genus, err := s.db.GetGenus(req.Genus); err != nil {
+		s.logger.Error("failed to check genus", "error", err)
+		RespondInternalError(w, "Failed to create taxon")
+		return
+	} else if genus == nil {
+		errors = append(errors, ValidationError{Field: "genus", Message: "unknown genus: " + req.Genus})
 	}
 	if len(errors) > 0 {
 		RespondValidationError(w, errors)
@@ -172,11 +269,22 @@ func (s *Server) handleCreateTaxon(w http.ResponseWriter, r *http.Request) {
 		RespondConflict(w, "Taxon already exists: "+req.Name+" ["+string(req.Level)+"]")
 		return
 	}
+	similar, err := s.db.TaxonNamesLike(req.Name, req.Level)
+	if err != nil {
+		s.logger.Error("failed to check for similar taxon names", "error", err)
+		RespondInternalError(w, "Failed to create taxon")
+		return
+	}
+	if len(similar) > 0 {
+		RespondConflict(w, "Taxon already exists as "+similar[0]+" ["+string(req.Level)+"], which differs only in case or diacritics")
+		return
+	}
 
 	// Create the taxon
 	taxon := &models.Taxon{
 		Name:   req.Name,
 		Level:  req.Level,
+		Genus:  req.Genus,
 		Parent: req.Parent,
 		Author: req.Author,
 		Notes:  req.Notes,
@@ -200,17 +308,20 @@ func (s *Server) handleUpdateTaxon(w http.ResponseWriter, r *http.Request) {
 	levelParam := chi.URLParam(r, "level")
 	nameEncoded := chi.URLParam(r, "name")
 
-	level, valid := parseTaxonLevel(levelParam)
-	if !valid {
-		RespondValidationError(w, []ValidationError{
-			{Field: "level", Message: "must be one of: subgenus, section, subsection, complex"},
-		})
+	level, levelErrors, err := s.parseTaxonLevel(levelParam)
+	if err != nil {
+		s.logger.Error("failed to list taxon levels", "error", err)
+		RespondInternalError(w, "Failed to update taxon")
+		return
+	}
+	if len(levelErrors) > 0 {
+		RespondValidationError(w, levelErrors)
 		return
 	}
 
 	name, err := url.PathUnescape(nameEncoded)
 	if err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid taxon name encoding")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid taxon name encoding")
 		return
 	}
 
@@ -225,12 +336,17 @@ func (s *Server) handleUpdateTaxon(w http.ResponseWriter, r *http.Request) {
 		RespondNotFound(w, "Taxon", name+" ["+string(level)+"]")
 		return
 	}
+	hashKey := string(level) + "/" + name
+	hash, failed := s.checkIfMatch(w, r, db.HashKindTaxa, hashKey)
+	if failed {
+		return
+	}
 
 	// Parse request body
 	var req TaxonRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeRequestBody(r, &req); err != nil {
 		RespondValidationError(w, []ValidationError{
-			{Field: "body", Message: "invalid JSON body"},
+			{Field: "body", Message: invalidBodyMessage(r)},
 		})
 		return
 	}
@@ -243,7 +359,11 @@ func (s *Server) handleUpdateTaxon(w http.ResponseWriter, r *http.Request) {
 		existing.Links = req.Links
 	}
 
-	if err := s.db.UpdateTaxon(existing); err != nil {
+	if err := s.db.UpdateTaxonIfHash(existing, hash); err != nil {
+		if errors.Is(err, db.ErrEntityChanged) {
+			s.respondRecordChanged(w, db.HashKindTaxa, hashKey)
+			return
+		}
 		s.logger.Error("failed to update taxon", "error", err)
 		RespondInternalError(w, "Failed to update taxon")
 		return
@@ -257,17 +377,20 @@ func (s *Server) handleDeleteTaxon(w http.ResponseWriter, r *http.Request) {
 	levelParam := chi.URLParam(r, "level")
 	nameEncoded := chi.URLParam(r, "name")
 
-	level, valid := parseTaxonLevel(levelParam)
-	if !valid {
-		RespondValidationError(w, []ValidationError{
-			{Field: "level", Message: "must be one of: subgenus, section, subsection, complex"},
-		})
+	level, errors, err := s.parseTaxonLevel(levelParam)
+	if err != nil {
+		s.logger.Error("failed to list taxon levels", "error", err)
+		RespondInternalError(w, "Failed to delete taxon")
+		return
+	}
+	if len(errors) > 0 {
+		RespondValidationError(w, errors)
 		return
 	}
 
 	name, err := url.PathUnescape(nameEncoded)
 	if err != nil {
-		RespondError(w, http.StatusBadRequest, ErrCodeValidation, "invalid taxon name encoding")
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid taxon name encoding")
 		return
 	}
 
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// TaxonLevelRequest is the request body for creating or updating a taxon level.
+type TaxonLevelRequest struct {
+	Rank       int     `json:"rank"`
+	Plural     *string `json:"plural,omitempty"`
+	EntryField *string `json:"entry_field,omitempty"` // One of models.TaxonEntryFields, or empty
+}
+
+// taxonLevelNamePattern matches a lowercase rank name such as "series".
+var taxonLevelNamePattern = regexp.MustCompile(`^[a-z][a-z_-]{0,49}$`)
+
+// handleListTaxonLevels handles GET /api/v1/taxon-levels
+func (s *Server) handleListTaxonLevels(w http.ResponseWriter, r *http.Request) {
+	levels, err := s.db.ListTaxonLevels()
+	if err != nil {
+		s.logger.Error("failed to list taxon levels", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if levels == nil {
+		levels = []*models.TaxonLevelDef{}
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(levels, len(levels), len(levels), 0))
+}
+
+// handlePutTaxonLevel handles PUT /api/v1/taxon-levels/{name}
+// Creates the level if it does not exist (201) or replaces its definition (200).
+func (s *Server) handlePutTaxonLevel(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(chi.URLParam(r, "name"))
+
+	var req TaxonLevelRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+	if req.EntryField != nil && *req.EntryField == "" {
+		req.EntryField = nil
+	}
+
+	var errors []ValidationError
+	if !taxonLevelNamePattern.MatchString(name) {
+		errors = append(errors, ValidationError{Field: "name", Message: "must be a lowercase word (e.g. series)"})
+	}
+	if req.Rank < 1 {
+		errors = append(errors, ValidationError{Field: "rank", Message: "must be a positive integer"})
+	}
+	if req.EntryField != nil && !slices.Contains(models.TaxonEntryFields, *req.EntryField) {
+		errors = append(errors, ValidationError{
+			Field:   "entry_field",
+			Message: "must be one of: " + strings.Join(models.TaxonEntryFields, ", "),
+		})
+	}
+	if len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+
+	levels, err := s.db.ListTaxonLevels()
+	if err != nil {
+		s.logger.Error("failed to list taxon levels", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	exists := false
+	for _, l := range levels {
+		if l.Name == name {
+			exists = true
+			continue
+		}
+		if l.Rank == req.Rank {
+			RespondConflict(w, fmt.Sprintf("rank %d is already used by level %s", req.Rank, l.Name))
+			return
+		}
+	}
+
+	level := &models.TaxonLevelDef{Name: name, Rank: req.Rank, Plural: req.Plural, EntryField: req.EntryField}
+	if err := s.db.SaveTaxonLevel(level); err != nil {
+		s.logger.Error("failed to save taxon level", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	saved, err := s.db.GetTaxonLevel(name)
+	if err != nil || saved == nil {
+		s.logger.Error("failed to reload taxon level", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	status := http.StatusCreated
+	if exists {
+		status = http.StatusOK
+	}
+	RespondJSON(w, status, saved)
+}
+
+// handleDeleteTaxonLevel handles DELETE /api/v1/taxon-levels/{name}
+// Refuses while any taxa are still assigned to the level.
+func (s *Server) handleDeleteTaxonLevel(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(chi.URLParam(r, "name"))
+
+	level, err := s.db.GetTaxonLevel(name)
+	if err != nil {
+		s.logger.Error("failed to get taxon level", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if level == nil {
+		RespondNotFound(w, "Taxon level", name)
+		return
+	}
+	if level.TaxaCount > 0 {
+		RespondConflict(w, fmt.Sprintf("taxon level %s still has %d taxa", name, level.TaxaCount))
+		return
+	}
+
+	if _, err := s.db.DeleteTaxonLevel(name); err != nil {
+		s.logger.Error("failed to delete taxon level", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
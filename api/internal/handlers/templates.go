@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// TemplateRequest is the request body for creating or replacing a template.
+type TemplateRequest struct {
+	Description    *string  `json:"description,omitempty"`
+	Genus          string   `json:"genus,omitempty"` // Defaults to Quercus
+	Subgenus       *string  `json:"subgenus,omitempty"`
+	Section        *string  `json:"section,omitempty"`
+	Subsection     *string  `json:"subsection,omitempty"`
+	Complex        *string  `json:"complex,omitempty"`
+	SourceIDs      []int64  `json:"source_ids,omitempty"`
+	RequiredFields []string `json:"required_fields,omitempty"`
+}
+
+// templateNamePattern matches template names such as "lobatae" or "red-oaks".
+var templateNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// handleListTemplates handles GET /api/v1/templates
+func (s *Server) handleListTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := s.db.ListTemplates()
+	if err != nil {
+		s.logger.Error("failed to list templates", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if templates == nil {
+		templates = []*models.Template{}
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(templates, len(templates), len(templates), 0))
+}
+
+// handleGetTemplate handles GET /api/v1/templates/{name}
+func (s *Server) handleGetTemplate(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(chi.URLParam(r, "name"))
+
+	tmpl, err := s.db.GetTemplate(name)
+	if err != nil {
+		s.logger.Error("failed to get template", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if tmpl == nil {
+		RespondNotFound(w, "Template", name)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, tmpl)
+}
+
+// handlePutTemplate handles PUT /api/v1/templates/{name}
+// Creates the template if it does not exist (201) or replaces it (200).
+func (s *Server) handlePutTemplate(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(chi.URLParam(r, "name"))
+
+	var req TemplateRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+	if req.Genus == "" {
+		req.Genus = models.DefaultGenus
+	}
+
+	var errors []ValidationError
+	if !templateNamePattern.MatchString(name) {
+		errors = append(errors, ValidationError{Field: "name", Message: "must be lowercase letters, digits, '-' or '_' (e.g. lobatae)"})
+	}
+	for _, field := range req.RequiredFields {
+		if !slices.Contains(models.TemplateRequiredFields, field) {
+			errors = append(errors, ValidationError{
+				Field:   "required_fields",
+				Message: fmt.Sprintf("unknown field %q; must be one of: %s", field, strings.Join(models.TemplateRequiredFields, ", ")),
+			})
+		}
+	}
+	genusErrors, err := s.checkGenus(req.Genus, req.Subgenus)
+	if err != nil {
+		s.logger.Error("failed to check genus", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	errors = append(errors, genusErrors...)
+	for _, id := range req.SourceIDs {
+		source, err := s.db.GetSource(id)
+		if err != nil {
+			s.logger.Error("failed to check source", "source_id", id, "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+		if source == nil {
+			errors = append(errors, ValidationError{Field: "source_ids", Message: fmt.Sprintf("source %d does not exist", id)})
+		}
+	}
+	if len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+
+	existing, err := s.db.GetTemplate(name)
+	if err != nil {
+		s.logger.Error("failed to check template existence", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	tmpl := &models.Template{
+		Name:           name,
+		Description:    req.Description,
+		Genus:          req.Genus,
+		Subgenus:       req.Subgenus,
+		Section:        req.Section,
+		Subsection:     req.Subsection,
+		Complex:        req.Complex,
+		SourceIDs:      req.SourceIDs,
+		RequiredFields: req.RequiredFields,
+	}
+	if err := s.db.SaveTemplate(tmpl); err != nil {
+		s.logger.Error("failed to save template", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if tmpl.SourceIDs == nil {
+		tmpl.SourceIDs = []int64{}
+	}
+	if tmpl.RequiredFields == nil {
+		tmpl.RequiredFields = []string{}
+	}
+
+	status := http.StatusCreated
+	if existing != nil {
+		status = http.StatusOK
+	}
+	RespondJSON(w, status, tmpl)
+}
+
+// handleDeleteTemplate handles DELETE /api/v1/templates/{name}
+func (s *Server) handleDeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(chi.URLParam(r, "name"))
+
+	found, err := s.db.DeleteTemplate(name)
+	if err != nil {
+		s.logger.Error("failed to delete template", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !found {
+		RespondNotFound(w, "Template", name)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// VerifySpeciesSourceRequest is the optional body for marking a species
+// source's transcription verified.
+type VerifySpeciesSourceRequest struct {
+	VerifiedBy string `json:"verified_by,omitempty"`
+}
+
+// handleListTranscriptions handles GET /api/v1/transcriptions
+// Lists transcribed species sources awaiting verification, least confident
+// OCR first. ?verified=true|all, ?method=, ?source_id=, and
+// ?max_confidence= filter.
+func (s *Server) handleListTranscriptions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var errors []ValidationError
+	var filter db.TranscriptionFilter
+
+	switch verified := query.Get("verified"); verified {
+	case "", "false":
+		filter.Verified = new(bool)
+	case "true":
+		filter.Verified = new(bool)
+		*filter.Verified = true
+	case "all":
+	default:
+		errors = append(errors, ValidationError{Field: "verified", Message: "verified must be true, false, or all"})
+	}
+	filter.Method = query.Get("method")
+	if filter.Method != "" && !slices.Contains(models.TranscriptionMethods, filter.Method) {
+		errors = append(errors, ValidationError{
+			Field:   "method",
+			Message: "method must be one of: " + strings.Join(models.TranscriptionMethods, ", "),
+		})
+	}
+	if v := query.Get("source_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			errors = append(errors, ValidationError{Field: "source_id", Message: "source_id must be a positive integer"})
+		}
+		filter.SourceID = id
+	}
+	if v := query.Get("max_confidence"); v != "" {
+		c, err := strconv.ParseFloat(v, 64)
+		if err != nil || c < 0 || c > 1 {
+			errors = append(errors, ValidationError{Field: "max_confidence", Message: "max_confidence must be between 0 and 1"})
+		}
+		filter.MaxConfidence = &c
+	}
+	if len(errors) > 0 {
+		RespondValidationError(w, errors)
+		return
+	}
+
+	transcriptions, err := s.db.ListTranscriptions(filter)
+	if err != nil {
+		s.logger.Error("failed to list transcriptions", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if transcriptions == nil {
+		transcriptions = []*db.Transcription{}
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(transcriptions, len(transcriptions), len(transcriptions), 0))
+}
+
+// handleVerifySpeciesSource handles POST /api/v1/species/{name}/sources/{sourceId}/verify
+// Marks the species source's text as checked against the original and
+// responds with the record.
+func (s *Server) handleVerifySpeciesSource(w http.ResponseWriter, r *http.Request) {
+	name, ok := speciesNameParam(w, r)
+	if !ok {
+		return
+	}
+	sourceIDParam := chi.URLParam(r, "sourceId")
+	sourceID, err := strconv.ParseInt(sourceIDParam, 10, 64)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid source ID")
+		return
+	}
+
+	var req VerifySpeciesSourceRequest
+	if r.ContentLength != 0 {
+		if err := decodeRequestBody(r, &req); err != nil {
+			RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+			return
+		}
+	}
+
+	found, err := s.db.VerifySpeciesSource(name, sourceID, strings.TrimSpace(req.VerifiedBy))
+	if err != nil {
+		s.logger.Error("failed to verify species source", "name", name, "sourceId", sourceID, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if !found {
+		RespondNotFound(w, "SpeciesSource", sourceIDParam)
+		return
+	}
+
+	ss, err := s.db.GetSpeciesSourceBySourceID(name, sourceID)
+	if err != nil {
+		s.logger.Error("failed to get species source", "name", name, "sourceId", sourceID, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	RespondJSON(w, http.StatusOK, ss)
+}
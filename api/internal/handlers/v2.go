@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// API v2 coexists with v1 on the same store. Handlers here reuse the v1
+// data access and validation, and differ only in response shape:
+//   - list endpoints use cursor pagination by default (no total count)
+//   - synonyms are objects rather than bare strings
+//   - hybrid parents are a single "parents" array
+//
+// New breaking shape changes belong here; /api/v1 must keep serving the web app unchanged.
+
+// setupV2Routes registers the /api/v2 routes.
+func (s *Server) setupV2Routes(r chi.Router) {
+	r.Use(s.requireFeature(FlagAPIV2))
+	r.Use(s.clientVersionMiddleware)
+
+	r.Get("/health", s.handleHealth)
+
+	r.Get("/species", s.handleV2ListSpecies)
+	r.With(s.resolveSpeciesName).Get("/species/{name}", s.handleV2GetSpecies)
+}
+
+// CursorPagination contains pagination metadata for cursor-paginated lists.
+type CursorPagination struct {
+	Limit      int     `json:"limit"`
+	NextCursor *string `json:"next_cursor"`
+	HasMore    bool    `json:"has_more"`
+}
+
+// CursorListResponse is the v2 response type for list endpoints.
+type CursorListResponse[T any] struct {
+	Data       []T              `json:"data"`
+	Pagination CursorPagination `json:"pagination"`
+}
+
+// SynonymV2 is a synonym in v2 responses.
+type SynonymV2 struct {
+	Name string `json:"name"`
+}
+
+// SpeciesV2 is the v2 representation of an oak entry.
+type SpeciesV2 struct {
+	ScientificName      string                `json:"scientific_name"`
+	Author              *string               `json:"author"`
+	Pronunciation       *string               `json:"pronunciation"`
+	IsHybrid            bool                  `json:"is_hybrid"`
+	ConservationStatus  *string               `json:"conservation_status"`
+	Genus               string                `json:"genus"`
+	Subgenus            *string               `json:"subgenus"`
+	Section             *string               `json:"section"`
+	Subsection          *string               `json:"subsection"`
+	Complex             *string               `json:"complex"`
+	Parents             []string              `json:"parents"`
+	Hybrids             []string              `json:"hybrids"`
+	CloselyRelatedTo    []string              `json:"closely_related_to"`
+	SubspeciesVarieties []string              `json:"subspecies_varieties"`
+	Synonyms            []SynonymV2           `json:"synonyms"`
+	ExternalLinks       []models.ExternalLink `json:"external_links"`
+}
+
+// toSpeciesV2 converts a stored entry to its v2 shape. Array fields are never null.
+func toSpeciesV2(e *models.OakEntry) SpeciesV2 {
+	v := SpeciesV2{
+		ScientificName:      e.ScientificName,
+		Author:              e.Author,
+		Pronunciation:       e.Pronunciation,
+		IsHybrid:            e.IsHybrid,
+		ConservationStatus:  e.ConservationStatus,
+		Genus:               e.Genus,
+		Subgenus:            e.Subgenus,
+		Section:             e.Section,
+		Subsection:          e.Subsection,
+		Complex:             e.Complex,
+		Parents:             []string{},
+		Hybrids:             nonNilStrings(e.Hybrids),
+		CloselyRelatedTo:    nonNilStrings(e.CloselyRelatedTo),
+		SubspeciesVarieties: nonNilStrings(e.SubspeciesVarieties),
+		Synonyms:            make([]SynonymV2, 0, len(e.Synonyms)),
+		ExternalLinks:       e.ExternalLinks,
+	}
+	for _, p := range []*string{e.Parent1, e.Parent2} {
+		if p != nil && *p != "" {
+			v.Parents = append(v.Parents, *p)
+		}
+	}
+	for _, syn := range e.Synonyms {
+		v.Synonyms = append(v.Synonyms, SynonymV2{Name: syn})
+	}
+	if v.ExternalLinks == nil {
+		v.ExternalLinks = []models.ExternalLink{}
+	}
+	return v
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// encodeCursor makes an opaque cursor from the last name on a page
+func encodeCursor(name string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(name))
+}
+
+// decodeCursor reverses encodeCursor
+func decodeCursor(cursor string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// handleV2ListSpecies handles GET /api/v2/species
+func (s *Server) handleV2ListSpecies(w http.ResponseWriter, r *http.Request) {
+	statuses, err := s.fieldTerms(models.VocabularyFieldConservationStatus)
+	if err != nil {
+		s.logger.Error("failed to get conservation status vocabulary", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	query := r.URL.Query()
+	params, validationErrors := parseSpeciesListParams(query, statuses)
+	if query.Get("offset") != "" {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "offset",
+			Message: "is not supported in v2; use cursor",
+		})
+	}
+
+	var after *string
+	if cursor := query.Get("cursor"); cursor != "" {
+		name, err := decodeCursor(cursor)
+		if err != nil {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "cursor",
+				Message: "is invalid",
+			})
+		} else {
+			after = &name
+		}
+	}
+	if len(validationErrors) > 0 {
+		RespondValidationError(w, validationErrors)
+		return
+	}
+
+	filter := &db.OakEntryFilter{
+		Genus:              params.Genus,
+		Subgenus:           params.Subgenus,
+		Section:            params.Section,
+		Subsection:         params.Subsection,
+		Complex:            params.Complex,
+		Hybrid:             params.Hybrid,
+		SourceID:           params.SourceID,
+		HasSource:          params.HasSource,
+		ConservationStatus: params.ConservationStatus,
+		After:              after,
+
+		IncludeDrafts: s.isAuthenticated(r),
+	}
+
+	// Fetch one extra row to learn whether another page exists
+	entries, err := s.db.ListOakEntriesPaginated(params.Limit+1, 0, filter)
+	if err != nil {
+		s.logger.Error("failed to list species", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	hasMore := len(entries) > params.Limit
+	if hasMore {
+		entries = entries[:params.Limit]
+	}
+
+	data := make([]SpeciesV2, 0, len(entries))
+	for _, e := range entries {
+		data = append(data, toSpeciesV2(e))
+	}
+
+	pagination := CursorPagination{Limit: params.Limit, HasMore: hasMore}
+	if hasMore {
+		next := encodeCursor(entries[len(entries)-1].ScientificName)
+		pagination.NextCursor = &next
+	}
+
+	RespondJSON(w, http.StatusOK, CursorListResponse[SpeciesV2]{Data: data, Pagination: pagination})
+}
+
+// handleV2GetSpecies handles GET /api/v2/species/{name}
+func (s *Server) handleV2GetSpecies(w http.ResponseWriter, r *http.Request) {
+	name, err := url.PathUnescape(chi.URLParam(r, "name"))
+	if err != nil || name == "" {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, "invalid species name encoding")
+		return
+	}
+
+	entry, err := s.db.GetOakEntry(name)
+	if err != nil {
+		s.logger.Error("failed to get species", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if entry == nil || s.hiddenDraft(r, entry.Visibility) {
+		RespondNotFound(w, "Species", name)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, toSpeciesV2(entry))
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+func TestV2ListSpeciesCursorPagination(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	for _, name := range []string{"alba", "bicolor", "coccinea", "douglasii", "ellipsoidalis"} {
+		if err := server.db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatalf("SaveOakEntry(%s) failed: %v", name, err)
+		}
+	}
+
+	var names []string
+	path := "/api/v2/species?limit=2"
+	for pages := 0; pages < 5; pages++ {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		server.Router().ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+		}
+
+		var resp CursorListResponse[SpeciesV2]
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		for _, sp := range resp.Data {
+			names = append(names, sp.ScientificName)
+		}
+		if !resp.Pagination.HasMore {
+			if resp.Pagination.NextCursor != nil {
+				t.Error("next_cursor set on last page")
+			}
+			break
+		}
+		path = "/api/v2/species?limit=2&cursor=" + *resp.Pagination.NextCursor
+	}
+
+	want := []string{"alba", "bicolor", "coccinea", "douglasii", "ellipsoidalis"}
+	if len(names) != len(want) {
+		t.Fatalf("got %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("names[%d] = %s, want %s", i, names[i], want[i])
+		}
+	}
+}
+
+func TestV2ListSpeciesRejectsOffset(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/species?offset=10", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestV2GetSpeciesShape(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	for _, name := range []string{"alba", "macrocarpa"} {
+		if err := server.db.SaveOakEntry(models.NewOakEntry(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p1, p2 := "alba", "macrocarpa"
+	hybrid := models.NewOakEntry("× bebbiana")
+	hybrid.IsHybrid = true
+	hybrid.Parent1 = &p1
+	hybrid.Parent2 = &p2
+	hybrid.Synonyms = []string{"Quercus bebbiana"}
+	if err := server.db.SaveOakEntry(hybrid); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/species/%C3%97%20bebbiana", nil)
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	var sp SpeciesV2
+	if err := json.NewDecoder(w.Body).Decode(&sp); err != nil {
+		t.Fatal(err)
+	}
+	if len(sp.Parents) != 2 || sp.Parents[0] != "alba" || sp.Parents[1] != "macrocarpa" {
+		t.Errorf("parents = %v", sp.Parents)
+	}
+	if len(sp.Synonyms) != 1 || sp.Synonyms[0].Name != "Quercus bebbiana" {
+		t.Errorf("synonyms = %+v", sp.Synonyms)
+	}
+
+	// v1 shape is unchanged
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/species/%C3%97%20bebbiana", nil)
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	var v1 map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&v1); err != nil {
+		t.Fatal(err)
+	}
+	if v1["parent1"] != "alba" {
+		t.Errorf("v1 parent1 = %v, want alba", v1["parent1"])
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClientVersionHeader carries the CLI version, e.g. "oak-cli/1.2.0" or "1.2.0".
+// Requests without it (browsers, curl) are never rejected.
+const ClientVersionHeader = "X-Oak-Client"
+
+// clientVersionMiddleware rejects requests from clients older than MinClient
+// with 426 Upgrade Required. Health endpoints are exempt so outdated clients
+// can still discover the required version.
+func (s *Server) clientVersionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get(ClientVersionHeader)
+		if header == "" || s.version.MinClient == "" || isHealthEndpoint(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		clientVersion := header
+		if i := strings.LastIndex(header, "/"); i >= 0 {
+			clientVersion = header[i+1:]
+		}
+
+		if compareVersions(clientVersion, s.version.MinClient) < 0 {
+			s.logger.Info("rejected outdated client", "client", header, "min_client", s.version.MinClient)
+			RespondUpgradeRequired(w, clientVersion, s.version.MinClient)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// speciesSearchSunset is when GET /api/v1/species/search is removed in favor
+// of GET /api/v1/search, which also matches authors, synonyms, and local names
+var speciesSearchSunset = time.Date(2027, 10, 1, 0, 0, 0, 0, time.UTC)
+
+// deprecated marks routes slated for removal. Responses carry a Deprecation
+// header, a Sunset header with the removal date, and a Link to the successor
+// route when there is one. Usage in setupRoutes:
+//
+//	r.With(deprecated(sunsetDate, "/api/v1/search")).Get("/old", s.handleOld)
+func deprecated(sunset time.Time, successor string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			if !sunset.IsZero() {
+				w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+			if successor != "" {
+				w.Header().Set("Link", "<"+successor+`>; rel="successor-version"`)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// compareVersions compares two semantic versions.
+// Returns -1 if a < b, 0 if a == b, 1 if a > b. Non-numeric parts count as 0.
+func compareVersions(a, b string) int {
+	aParts := parseVersion(a)
+	bParts := parseVersion(b)
+
+	for i := 0; i < 3; i++ {
+		if aParts[i] < bParts[i] {
+			return -1
+		}
+		if aParts[i] > bParts[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// parseVersion parses a semantic version string into [major, minor, patch].
+func parseVersion(v string) [3]int {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i] // Drop pre-release/build metadata
+	}
+	parts := strings.Split(v, ".")
+	var result [3]int
+	for i := 0; i < len(parts) && i < 3; i++ {
+		n, _ := strconv.Atoi(parts[i])
+		result[i] = n
+	}
+	return result
+}
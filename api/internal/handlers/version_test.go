@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+func TestClientVersionMiddleware(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	server.version.MinClient = "1.2.0"
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		want   int
+	}{
+		{"no header", "/api/v1/species", "", http.StatusOK},
+		{"current client", "/api/v1/species", "oak-cli/1.2.0", http.StatusOK},
+		{"newer client", "/api/v1/species", "1.10.0", http.StatusOK},
+		{"old client", "/api/v1/species", "oak-cli/1.1.9", http.StatusUpgradeRequired},
+		{"old client health exempt", "/api/v1/health", "oak-cli/0.9.0", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(ClientVersionHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			server.Router().ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d. Body: %s", w.Code, tt.want, w.Body.String())
+			}
+			if w.Code == http.StatusUpgradeRequired {
+				var resp ErrorResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp.Error.Code != apierror.CodeClientTooOld {
+					t.Errorf("code = %s, want %s", resp.Error.Code, apierror.CodeClientTooOld)
+				}
+			}
+		})
+	}
+}
+
+func TestDeprecatedHeaders(t *testing.T) {
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	handler := deprecated(sunset, "/api/v1/search")(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/species/search", nil))
+
+	if got := w.Header().Get("Deprecation"); got != "true" {
+		t.Errorf("Deprecation = %q, want true", got)
+	}
+	if got := w.Header().Get("Sunset"); got != "Fri, 01 Jan 2027 00:00:00 GMT" {
+		t.Errorf("Sunset = %q", got)
+	}
+	if got := w.Header().Get("Link"); got != `</api/v1/search>; rel="successor-version"` {
+		t.Errorf("Link = %q", got)
+	}
+}
+
+func TestDeprecatedRoutes(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/species/search?q=alba", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got := w.Header().Get("Deprecation"); got != "true" {
+		t.Errorf("Deprecation = %q, want true", got)
+	}
+	if got := w.Header().Get("Sunset"); got != "Fri, 01 Oct 2027 00:00:00 GMT" {
+		t.Errorf("Sunset = %q", got)
+	}
+	if got := w.Header().Get("Link"); got != `</api/v1/search>; rel="successor-version"` {
+		t.Errorf("Link = %q", got)
+	}
+
+	// Its successor is not deprecated
+	w = httptest.NewRecorder()
+	server.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/search?q=alba", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Deprecation") != "" {
+		t.Errorf("successor status = %d, Deprecation = %q; want 200 and none", w.Code, w.Header().Get("Deprecation"))
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.0", "1.0.1", -1},
+		{"v2.0.0", "1.9.9", 1},
+		{"1.10.0", "1.9.0", 1},
+		{"1.2.0-rc1", "1.2.0", 0},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
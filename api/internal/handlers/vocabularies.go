@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/jeff/oaks/api/internal/models"
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// VocabularyRequest is the request body for creating or replacing a
+// vocabulary, in JSON or YAML. Terms replace the vocabulary's terms, in order.
+type VocabularyRequest struct {
+	Description *string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Field       *string                 `json:"field,omitempty" yaml:"field,omitempty"` // One of models.VocabularyFields
+	Terms       []models.VocabularyTerm `json:"terms" yaml:"terms"`
+}
+
+// vocabularyNamePattern matches vocabulary names such as "conservation_status".
+var vocabularyNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// builtinVocabularies are the data dictionary's vocabularies that are read
+// from their own tables, so no managed vocabulary may take their names
+var builtinVocabularies = []string{"genus", "taxon_level", "external_link_logo"}
+
+// fieldTerms returns the terms a field may take: those of the vocabulary
+// bound to it, or nil if none is, in which case any value is accepted.
+func (s *Server) fieldTerms(field string) ([]string, error) {
+	v, err := s.db.FieldVocabulary(field)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	terms := make([]string, len(v.Terms))
+	for i, t := range v.Terms {
+		terms[i] = t.Term
+	}
+	return terms, nil
+}
+
+// handleListVocabularies handles GET /api/v1/vocabularies
+func (s *Server) handleListVocabularies(w http.ResponseWriter, r *http.Request) {
+	vocabularies, err := s.db.ListVocabularies()
+	if err != nil {
+		s.logger.Error("failed to list vocabularies", "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if vocabularies == nil {
+		vocabularies = []*models.Vocabulary{}
+	}
+
+	RespondJSON(w, http.StatusOK, NewListResponse(vocabularies, len(vocabularies), len(vocabularies), 0))
+}
+
+// handleGetVocabulary handles GET /api/v1/vocabularies/{name}
+func (s *Server) handleGetVocabulary(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(chi.URLParam(r, "name"))
+
+	v, err := s.db.GetVocabulary(name)
+	if err != nil {
+		s.logger.Error("failed to get vocabulary", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if v == nil {
+		RespondNotFound(w, "Vocabulary", name)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, v)
+}
+
+// handlePutVocabulary handles PUT /api/v1/vocabularies/{name}
+// Creates the vocabulary if it does not exist (201) or replaces it (200).
+// Binding it to a field that another vocabulary is bound to, or leaving out
+// a term that records of its field still use, is a conflict (409).
+func (s *Server) handlePutVocabulary(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(chi.URLParam(r, "name"))
+
+	var req VocabularyRequest
+	if err := decodeRequestBody(r, &req); err != nil {
+		RespondError(w, http.StatusBadRequest, apierror.CodeValidation, invalidBodyMessage(r))
+		return
+	}
+	if req.Field != nil && *req.Field == "" {
+		req.Field = nil
+	}
+
+	var errs []ValidationError
+	if !vocabularyNamePattern.MatchString(name) {
+		errs = append(errs, ValidationError{Field: "name", Message: "must be lowercase letters, digits, '-' or '_' (e.g. conservation_status)"})
+	} else if slices.Contains(builtinVocabularies, name) {
+		errs = append(errs, ValidationError{Field: "name", Message: fmt.Sprintf("%s is a built-in vocabulary", name)})
+	}
+	if req.Field != nil && !slices.Contains(models.VocabularyFields, *req.Field) {
+		errs = append(errs, ValidationError{Field: "field", Message: "must be one of: " + strings.Join(models.VocabularyFields, ", ")})
+	}
+	if len(req.Terms) == 0 {
+		errs = append(errs, ValidationError{Field: "terms", Message: "at least one term is required"})
+	}
+	seen := make(map[string]bool)
+	for i := range req.Terms {
+		term := strings.TrimSpace(req.Terms[i].Term)
+		if req.Field != nil && *req.Field == models.VocabularyFieldSourceType {
+			term = models.SourceTypeKey(term) // The form source types are stored in
+		}
+		switch {
+		case term == "":
+			errs = append(errs, ValidationError{Field: "terms", Message: "every term needs a value"})
+		case seen[term]:
+			errs = append(errs, ValidationError{Field: "terms", Message: fmt.Sprintf("term %q is listed twice", term)})
+		}
+		seen[term] = true
+		req.Terms[i].Term = term
+	}
+	if len(errs) > 0 {
+		RespondValidationError(w, errs)
+		return
+	}
+
+	existing, err := s.db.GetVocabulary(name)
+	if err != nil {
+		s.logger.Error("failed to check vocabulary existence", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	if req.Field != nil {
+		bound, err := s.db.FieldVocabulary(*req.Field)
+		if err != nil {
+			s.logger.Error("failed to get field vocabulary", "field", *req.Field, "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+		if bound != nil && bound.Name != name {
+			RespondConflict(w, fmt.Sprintf("%s is already bound to vocabulary %s", *req.Field, bound.Name))
+			return
+		}
+
+		usage, err := s.db.VocabularyFieldUsage(*req.Field)
+		if err != nil {
+			s.logger.Error("failed to count field values", "field", *req.Field, "error", err)
+			RespondInternalError(w, "")
+			return
+		}
+		var missing []string
+		for value, count := range usage {
+			if !seen[value] {
+				missing = append(missing, fmt.Sprintf("%s (%d)", value, count))
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			RespondConflict(w, fmt.Sprintf("%s values are in use but not terms: %s", *req.Field, strings.Join(missing, ", ")))
+			return
+		}
+	}
+
+	v := &models.Vocabulary{
+		Name:        name,
+		Description: req.Description,
+		Field:       req.Field,
+		Terms:       req.Terms,
+	}
+	if err := s.db.SaveVocabulary(v); err != nil {
+		s.logger.Error("failed to save vocabulary", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	status := http.StatusCreated
+	if existing != nil {
+		status = http.StatusOK
+	}
+	RespondJSON(w, status, v)
+}
+
+// handleDeleteVocabulary handles DELETE /api/v1/vocabularies/{name}
+// A vocabulary bound to a field cannot be deleted (409); unbind it first by
+// saving it without a field.
+func (s *Server) handleDeleteVocabulary(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(chi.URLParam(r, "name"))
+
+	v, err := s.db.GetVocabulary(name)
+	if err != nil {
+		s.logger.Error("failed to get vocabulary", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+	if v == nil {
+		RespondNotFound(w, "Vocabulary", name)
+		return
+	}
+	if v.Field != nil {
+		RespondConflict(w, fmt.Sprintf("vocabulary %s is bound to %s; save it without a field first", name, *v.Field))
+		return
+	}
+
+	if _, err := s.db.DeleteVocabulary(name); err != nil {
+		s.logger.Error("failed to delete vocabulary", "name", name, "error", err)
+		RespondInternalError(w, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
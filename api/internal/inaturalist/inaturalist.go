@@ -0,0 +1,178 @@
+// Package inaturalist looks species up in the iNaturalist taxa API
+// (https://api.inaturalist.org/v1/docs): the taxon a name matches and its
+// representative photos.
+package inaturalist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultURL is iNaturalist's public API
+const DefaultURL = "https://api.inaturalist.org/v1"
+
+// defaultInterval spaces requests as iNaturalist's API recommendations ask
+// (about one per second)
+const defaultInterval = time.Second
+
+// Ranks a species entry can match: species, hybrids, and infraspecific taxa
+var speciesRanks = map[string]bool{
+	"species": true, "hybrid": true, "subspecies": true, "variety": true, "form": true,
+}
+
+// Taxon is an iNaturalist taxon
+type Taxon struct {
+	ID                int64  `json:"id"`
+	Name              string `json:"name"` // Scientific name, with genus
+	Rank              string `json:"rank"`
+	CommonName        string `json:"preferred_common_name,omitempty"`
+	ObservationsCount int    `json:"observations_count"`
+}
+
+// Photo is a representative photo of a taxon
+type Photo struct {
+	ID          int64  `json:"id"`
+	URL         string `json:"medium_url"`
+	SquareURL   string `json:"square_url"`
+	Attribution string `json:"attribution"`
+	License     string `json:"license_code"` // Such as cc-by-nc; "" for all rights reserved
+}
+
+// Client calls the iNaturalist API
+type Client struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+
+	// Interval is the least time between requests
+	Interval time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// New creates a client for the iNaturalist API at baseURL, normally
+// DefaultURL. userAgent identifies the application.
+func New(baseURL, userAgent string) *Client {
+	return &Client{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		userAgent: userAgent,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		Interval:  defaultInterval,
+	}
+}
+
+// TaxonURL is the iNaturalist page of the taxon with this ID
+func TaxonURL(id int64) string {
+	return "https://www.inaturalist.org/taxa/" + strconv.FormatInt(id, 10)
+}
+
+// Match returns the active taxon at species rank or below whose name is
+// exactly name, such as "Quercus alba" or "Quercus × bebbiana", or nil if
+// there is none.
+func (c *Client) Match(ctx context.Context, name string) (*Taxon, error) {
+	var page struct {
+		Results []Taxon `json:"results"`
+	}
+	query := url.Values{"q": {name}, "is_active": {"true"}, "per_page": {"30"}}
+	if err := c.get(ctx, "/taxa?"+query.Encode(), &page); err != nil {
+		return nil, err
+	}
+	for i := range page.Results {
+		t := &page.Results[i]
+		if speciesRanks[t.Rank] && strings.EqualFold(normalizeName(t.Name), normalizeName(name)) {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+// Photos returns up to limit of the taxon's photos, in iNaturalist's order
+func (c *Client) Photos(ctx context.Context, id int64, limit int) ([]Photo, error) {
+	var page struct {
+		Results []struct {
+			TaxonPhotos []struct {
+				Photo Photo `json:"photo"`
+			} `json:"taxon_photos"`
+		} `json:"results"`
+	}
+	if err := c.get(ctx, "/taxa/"+strconv.FormatInt(id, 10), &page); err != nil {
+		return nil, err
+	}
+	var photos []Photo
+	for _, r := range page.Results {
+		for _, tp := range r.TaxonPhotos {
+			if len(photos) == limit {
+				return photos, nil
+			}
+			if tp.Photo.URL != "" {
+				photos = append(photos, tp.Photo)
+			}
+		}
+	}
+	return photos, nil
+}
+
+// normalizeName writes the hybrid sign one way, so "Quercus x bebbiana" and
+// "Quercus ×bebbiana" match "Quercus × bebbiana"
+func normalizeName(name string) string {
+	fields := strings.Fields(strings.ReplaceAll(name, "×", " × "))
+	for i, f := range fields {
+		if f == "x" || f == "X" {
+			fields[i] = "×"
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// get decodes the JSON response to path into v
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create iNaturalist request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("iNaturalist request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("iNaturalist returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode iNaturalist response: %w", err)
+	}
+	return nil
+}
+
+// wait blocks until Interval has passed since the last request
+func (c *Client) wait(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d := c.Interval - time.Since(c.last); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	c.last = time.Now()
+	return nil
+}
@@ -0,0 +1,65 @@
+package inaturalist
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/taxa":
+			switch r.URL.Query().Get("q") {
+			case "Quercus alba":
+				// The genus and a near match come first
+				fmt.Fprint(w, `{"results": [
+					{"id": 47851, "name": "Quercus", "rank": "genus"},
+					{"id": 49011, "name": "Quercus albicaulis", "rank": "species"},
+					{"id": 54779, "name": "Quercus alba", "rank": "species", "preferred_common_name": "white oak", "observations_count": 52000}]}`)
+			case "Quercus x bebbiana":
+				fmt.Fprint(w, `{"results": [{"id": 86270, "name": "Quercus × bebbiana", "rank": "hybrid"}]}`)
+			default:
+				fmt.Fprint(w, `{"results": []}`)
+			}
+		case "/taxa/54779":
+			fmt.Fprint(w, `{"results": [{"id": 54779, "taxon_photos": [
+				{"photo": {"id": 1, "medium_url": "https://example.org/1/medium.jpg", "square_url": "https://example.org/1/square.jpg", "attribution": "(c) A", "license_code": "cc-by"}},
+				{"photo": {"id": 2, "medium_url": "https://example.org/2/medium.jpg", "attribution": "(c) B", "license_code": null}},
+				{"photo": {"id": 3, "medium_url": "https://example.org/3/medium.jpg"}}]}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	c := New(srv.URL, "test")
+	c.Interval = 0
+	ctx := context.Background()
+
+	taxon, err := c.Match(ctx, "Quercus alba")
+	if err != nil {
+		t.Fatalf("Match failed: %v", err)
+	}
+	if taxon == nil || taxon.ID != 54779 || taxon.CommonName != "white oak" {
+		t.Fatalf("Match(Quercus alba) = %+v, want 54779", taxon)
+	}
+	if taxon, err := c.Match(ctx, "Quercus x bebbiana"); err != nil || taxon == nil || taxon.ID != 86270 {
+		t.Errorf("Match(Quercus x bebbiana) = %+v, %v; want the hybrid", taxon, err)
+	}
+	if taxon, err := c.Match(ctx, "Quercus nowhere"); err != nil || taxon != nil {
+		t.Errorf("Match(Quercus nowhere) = %+v, %v; want nil", taxon, err)
+	}
+
+	photos, err := c.Photos(ctx, 54779, 2)
+	if err != nil {
+		t.Fatalf("Photos failed: %v", err)
+	}
+	if len(photos) != 2 || photos[0].License != "cc-by" || photos[1].License != "" {
+		t.Errorf("Photos = %+v, want the first two", photos)
+	}
+	if _, err := c.Photos(ctx, 1, 5); err == nil {
+		t.Error("Photos(unknown taxon) succeeded, want the 404 error")
+	}
+}
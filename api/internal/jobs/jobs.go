@@ -0,0 +1,271 @@
+// Package jobs runs background work for the API server.
+//
+// Jobs are rows in the jobs table, so queued work survives restarts. A Runner
+// owns a pool of worker goroutines that claim due jobs, call the Handler
+// registered for the job's kind, and record the outcome. Failed jobs are
+// retried with backoff until they run out of attempts. Anything a handler
+// writes to its Log is stored with the job and served by /api/v1/jobs.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/db"
+)
+
+// DefaultMaxAttempts is used when a job is enqueued without an explicit limit.
+const DefaultMaxAttempts = 3
+
+// Handler performs one job. ctx is canceled if the server shuts down before
+// the job finishes. Returning an error schedules a retry.
+type Handler func(ctx context.Context, job *db.Job, log *Log) error
+
+// Log captures a job's output alongside the server log.
+type Log struct {
+	db     *db.Database
+	jobID  int64
+	logger *slog.Logger
+}
+
+// Printf appends a formatted line to the job's log.
+func (l *Log) Printf(format string, args ...interface{}) {
+	line := fmt.Sprintf(format, args...)
+	l.logger.Debug("job log", "job", l.jobID, "line", line)
+	if err := l.db.AppendJobLog(l.jobID, line); err != nil {
+		l.logger.Error("failed to capture job log", "job", l.jobID, "error", err)
+	}
+}
+
+// Runner dispatches queued jobs to registered handlers on a pool of workers.
+type Runner struct {
+	db     *db.Database
+	logger *slog.Logger
+
+	// PollInterval is how long idle workers wait before checking for due jobs.
+	PollInterval time.Duration
+	// RetryDelay returns the wait before retrying a job that failed its nth attempt.
+	RetryDelay func(attempt int) time.Duration
+	// OnFailed, if set, is called when a job has used all of its attempts.
+	OnFailed func(job *db.Job, err error)
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+
+	wake       chan struct{}
+	stop       chan struct{}
+	jobCtx     context.Context
+	cancelJobs context.CancelFunc
+	wg         sync.WaitGroup
+	started    bool
+}
+
+// NewRunner creates a Runner with no handlers registered.
+func NewRunner(database *db.Database, logger *slog.Logger) *Runner {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Runner{
+		db:           database,
+		logger:       logger,
+		PollInterval: 5 * time.Second,
+		RetryDelay:   defaultRetryDelay,
+		handlers:     make(map[string]Handler),
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// defaultRetryDelay backs off quadratically: 10s, 40s, 90s, ...
+func defaultRetryDelay(attempt int) time.Duration {
+	return time.Duration(attempt*attempt) * 10 * time.Second
+}
+
+// Register sets the handler for a job kind, replacing any existing one.
+func (r *Runner) Register(kind string, h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[kind] = h
+}
+
+// Kinds returns the registered job kinds in sorted order.
+func (r *Runner) Kinds() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	kinds := make([]string, 0, len(r.handlers))
+	for k := range r.handlers {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Has reports whether a handler is registered for kind.
+func (r *Runner) Has(kind string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.handlers[kind]
+	return ok
+}
+
+// Enqueue queues a job of a registered kind. payload is encoded as JSON; it may be nil.
+func (r *Runner) Enqueue(kind string, payload interface{}, maxAttempts int) (*db.Job, error) {
+	if !r.Has(kind) {
+		return nil, fmt.Errorf("unknown job kind: %s", kind)
+	}
+	var raw json.RawMessage
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal job payload: %w", err)
+		}
+		raw = data
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	job, err := r.db.EnqueueJob(kind, raw, maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wake an idle worker without blocking
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
+
+// Start requeues jobs interrupted by a previous shutdown and starts workers.
+func (r *Runner) Start(workers int) error {
+	if r.started {
+		return fmt.Errorf("job runner already started")
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	requeued, err := r.db.RequeueRunningJobs()
+	if err != nil {
+		return err
+	}
+	if requeued > 0 {
+		r.logger.Info("requeued interrupted jobs", "count", requeued)
+	}
+
+	r.stop = make(chan struct{})
+	r.jobCtx, r.cancelJobs = context.WithCancel(context.Background())
+	r.started = true
+	for i := 0; i < workers; i++ {
+		r.wg.Add(1)
+		go r.work()
+	}
+	return nil
+}
+
+// Stop stops claiming new jobs and waits for running ones to finish. If ctx
+// expires first, running jobs are canceled and Stop returns ctx's error once
+// they have returned.
+func (r *Runner) Stop(ctx context.Context) error {
+	if !r.started {
+		return nil
+	}
+	r.started = false
+	close(r.stop)
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		r.cancelJobs()
+		return nil
+	case <-ctx.Done():
+		r.cancelJobs()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// work claims and runs jobs until the runner stops
+func (r *Runner) work() {
+	defer r.wg.Done()
+	for {
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+
+		job, err := r.db.ClaimNextJob(time.Now())
+		if err != nil {
+			r.logger.Error("failed to claim job", "error", err)
+		}
+		if job != nil {
+			r.run(job)
+			continue
+		}
+
+		select {
+		case <-r.stop:
+			return
+		case <-r.wake:
+		case <-time.After(r.PollInterval):
+		}
+	}
+}
+
+// run executes one claimed job and records the result
+func (r *Runner) run(job *db.Job) {
+	log := &Log{db: r.db, jobID: job.ID, logger: r.logger}
+	start := time.Now()
+
+	r.mu.RLock()
+	h, ok := r.handlers[job.Kind]
+	r.mu.RUnlock()
+
+	var err error
+	if !ok {
+		err = fmt.Errorf("no handler registered for job kind %s", job.Kind)
+	} else {
+		err = r.call(h, job, log)
+	}
+
+	if err == nil {
+		if cerr := r.db.CompleteJob(job.ID); cerr != nil {
+			r.logger.Error("failed to complete job", "job", job.ID, "error", cerr)
+		}
+		r.logger.Info("job succeeded", "job", job.ID, "kind", job.Kind, "duration_ms", time.Since(start).Milliseconds())
+		return
+	}
+
+	log.Printf("attempt %d failed: %v", job.Attempts, err)
+	status, ferr := r.db.FailJob(job.ID, err, time.Now().Add(r.RetryDelay(job.Attempts)))
+	if ferr != nil {
+		r.logger.Error("failed to record job failure", "job", job.ID, "error", ferr)
+		return
+	}
+	r.logger.Warn("job attempt failed", "job", job.ID, "kind", job.Kind, "attempt", job.Attempts, "status", status, "error", err)
+	if status == db.JobFailed && r.OnFailed != nil {
+		r.OnFailed(job, err)
+	}
+}
+
+// call runs a handler, turning a panic into an error so one bad job cannot kill a worker
+func (r *Runner) call(h Handler, job *db.Job, log *Log) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job panicked: %v", p)
+		}
+	}()
+	return h(r.jobCtx, job, log)
+}
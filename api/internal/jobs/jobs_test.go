@@ -0,0 +1,164 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeff/oaks/api/internal/db"
+)
+
+func testRunner(t *testing.T) (*Runner, *db.Database) {
+	t.Helper()
+
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	r := NewRunner(database, logger)
+	r.PollInterval = 10 * time.Millisecond
+	r.RetryDelay = func(int) time.Duration { return 0 }
+	return r, database
+}
+
+// waitForStatus polls until the job reaches status or the test times out
+func waitForStatus(t *testing.T, database *db.Database, id int64, status string) *db.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := database.GetJob(id)
+		if err != nil {
+			t.Fatalf("GetJob failed: %v", err)
+		}
+		if job.Status == status {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	job, _ := database.GetJob(id)
+	t.Fatalf("job %d status = %s, want %s", id, job.Status, status)
+	return nil
+}
+
+func TestRunnerRetriesAndCapturesLog(t *testing.T) {
+	r, database := testRunner(t)
+
+	var calls int32
+	r.Register("flaky", func(ctx context.Context, job *db.Job, log *Log) error {
+		n := atomic.AddInt32(&calls, 1)
+		log.Printf("run %d payload %s", n, job.Payload)
+		if n == 1 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	r.Register("broken", func(ctx context.Context, job *db.Job, log *Log) error {
+		panic("boom")
+	})
+	gaveUp := make(chan int64, 4)
+	r.OnFailed = func(job *db.Job, err error) { gaveUp <- job.ID }
+
+	if err := r.Start(2); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer r.Stop(context.Background())
+
+	flaky, err := r.Enqueue("flaky", map[string]string{"url": "https://example.org"}, 0)
+	if err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	broken, err := r.Enqueue("broken", nil, 2)
+	if err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	done := waitForStatus(t, database, flaky.ID, db.JobSucceeded)
+	if done.Attempts != 2 {
+		t.Errorf("attempts = %d, want 2", done.Attempts)
+	}
+	for _, want := range []string{"run 1", "attempt 1 failed: transient", "run 2", "example.org"} {
+		if !strings.Contains(done.Log, want) {
+			t.Errorf("log missing %q:\n%s", want, done.Log)
+		}
+	}
+
+	failed := waitForStatus(t, database, broken.ID, db.JobFailed)
+	if failed.Attempts != 2 || failed.LastError == nil || !strings.Contains(*failed.LastError, "panicked") {
+		t.Errorf("broken job = %+v, want 2 attempts ending in a panic", failed)
+	}
+	select {
+	case id := <-gaveUp:
+		if id != broken.ID {
+			t.Errorf("OnFailed called for job %d, want %d", id, broken.ID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("OnFailed not called for the exhausted job")
+	}
+
+	if _, err := r.Enqueue("unknown", nil, 0); err == nil {
+		t.Error("Enqueue of an unregistered kind succeeded")
+	}
+}
+
+func TestRunnerStopCancelsRunningJobs(t *testing.T) {
+	r, database := testRunner(t)
+
+	started := make(chan struct{})
+	r.Register("slow", func(ctx context.Context, job *db.Job, log *Log) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err := r.Start(1); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	job, err := r.Enqueue("slow", nil, 1)
+	if err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := r.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop error = %v, want deadline exceeded", err)
+	}
+
+	got, _ := database.GetJob(job.ID)
+	if got.Status != db.JobFailed {
+		t.Errorf("status after forced stop = %s, want %s", got.Status, db.JobFailed)
+	}
+}
+
+func TestStartRequeuesInterruptedJobs(t *testing.T) {
+	r, database := testRunner(t)
+
+	// Simulate a job left running by a crashed process
+	job, err := database.EnqueueJob("resume", nil, 3)
+	if err != nil {
+		t.Fatalf("EnqueueJob failed: %v", err)
+	}
+	if _, err := database.ClaimNextJob(time.Now()); err != nil {
+		t.Fatalf("ClaimNextJob failed: %v", err)
+	}
+
+	r.Register("resume", func(ctx context.Context, job *db.Job, log *Log) error { return nil })
+	if err := r.Start(1); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer r.Stop(context.Background())
+
+	if done := waitForStatus(t, database, job.ID, db.JobSucceeded); done.Attempts != 2 {
+		t.Errorf("attempts = %d, want 2", done.Attempts)
+	}
+}
@@ -0,0 +1,359 @@
+// Package markdown renders the markdown subset used for long-form species accounts.
+//
+// Supported blocks are ATX headings, paragraphs, fenced code, blockquotes,
+// flat bullet and numbered lists, and horizontal rules. Inline markup covers
+// **strong**, *emphasis*, `code`, [links](url), ![images](src), and species
+// cross-links written as [[rubra]] or [[rubra|northern red oak]].
+// Raw HTML is always escaped.
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Options controls how links are rendered
+type Options struct {
+	// SpeciesURL maps a cross-linked species name to its page URL.
+	// Defaults to the web app's /species/{name}/ route.
+	SpeciesURL func(name string) string
+
+	// Linkify finds species mentioned in plain text, such as "Q. stellata".
+	// Each span is rendered as a species link. Text already inside a link
+	// or code span is not scanned.
+	Linkify func(text string) []Span
+}
+
+// Span is a species mention at text[Start:End]
+type Span struct {
+	Start int
+	End   int
+	Name  string // Scientific name of the mentioned species
+}
+
+// Result is a rendered document and the references found in it
+type Result struct {
+	HTML   string
+	Links  []string // Species linked by [[...]] or Options.Linkify, in first-seen order
+	Images []string // Image sources, in first-seen order
+}
+
+// SpeciesURL is the default species page URL for cross-links
+func SpeciesURL(name string) string {
+	return "/species/" + url.PathEscape(name) + "/"
+}
+
+var (
+	headingRe     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	hrRe          = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	bulletRe      = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedRe     = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	blockquoteRe  = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	fenceRe       = regexp.MustCompile("^\\s*(```|~~~)\\s*([A-Za-z0-9_+-]*)\\s*$")
+	safeSchemes   = []string{"http", "https", "mailto"}
+	escapableRune = "\\`*_[]()#+-.!|>~"
+)
+
+// Render converts markdown to HTML
+func Render(src string, opts Options) Result {
+	if opts.SpeciesURL == nil {
+		opts.SpeciesURL = SpeciesURL
+	}
+	r := &renderer{opts: opts, seenLinks: map[string]bool{}, seenImages: map[string]bool{}}
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	r.blocks(lines)
+	return Result{HTML: r.out.String(), Links: r.links, Images: r.images}
+}
+
+// References returns the species cross-links and image sources in src
+// without keeping the rendered HTML
+func References(src string) (links, images []string) {
+	res := Render(src, Options{})
+	return res.Links, res.Images
+}
+
+type renderer struct {
+	opts       Options
+	inLink     bool // Rendering link text, where mentions are not linked again
+	out        strings.Builder
+	links      []string
+	images     []string
+	seenLinks  map[string]bool
+	seenImages map[string]bool
+}
+
+// blocks renders a sequence of lines as block elements
+func (r *renderer) blocks(lines []string) {
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			r.out.WriteString("<p>" + r.inline(strings.Join(para, "\n")) + "</p>\n")
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+
+		case fenceRe.MatchString(line):
+			flush()
+			m := fenceRe.FindStringSubmatch(line)
+			var code []string
+			for i++; i < len(lines); i++ {
+				if strings.TrimSpace(lines[i]) == m[1] {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			if m[2] != "" {
+				r.out.WriteString(`<pre><code class="language-` + html.EscapeString(m[2]) + `">`)
+			} else {
+				r.out.WriteString("<pre><code>")
+			}
+			r.out.WriteString(html.EscapeString(strings.Join(code, "\n")))
+			r.out.WriteString("</code></pre>\n")
+
+		case headingRe.MatchString(line):
+			flush()
+			m := headingRe.FindStringSubmatch(line)
+			tag := "h" + string(rune('0'+len(m[1])))
+			r.out.WriteString("<" + tag + ">" + r.inline(m[2]) + "</" + tag + ">\n")
+
+		case hrRe.MatchString(line):
+			flush()
+			r.out.WriteString("<hr>\n")
+
+		case blockquoteRe.MatchString(line):
+			flush()
+			var quoted []string
+			for ; i < len(lines) && blockquoteRe.MatchString(lines[i]); i++ {
+				quoted = append(quoted, blockquoteRe.FindStringSubmatch(lines[i])[1])
+			}
+			i--
+			r.out.WriteString("<blockquote>\n")
+			r.blocks(quoted)
+			r.out.WriteString("</blockquote>\n")
+
+		case bulletRe.MatchString(line), orderedRe.MatchString(line):
+			flush()
+			i = r.list(lines, i) - 1
+
+		default:
+			para = append(para, strings.TrimSpace(line))
+		}
+	}
+	flush()
+}
+
+// list renders the list starting at lines[start] and returns the index of the
+// first line after it. Indented lines continue the previous item.
+func (r *renderer) list(lines []string, start int) int {
+	itemRe, tag := bulletRe, "ul"
+	if !bulletRe.MatchString(lines[start]) {
+		itemRe, tag = orderedRe, "ol"
+	}
+
+	var items []string
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if m := itemRe.FindStringSubmatch(line); m != nil {
+			items = append(items, m[1])
+			continue
+		}
+		if strings.TrimSpace(line) != "" && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			items[len(items)-1] += "\n" + strings.TrimSpace(line)
+			continue
+		}
+		break
+	}
+
+	r.out.WriteString("<" + tag + ">\n")
+	for _, item := range items {
+		r.out.WriteString("<li>" + r.inline(item) + "</li>\n")
+	}
+	r.out.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// inline renders inline markup, escaping everything else
+func (r *renderer) inline(s string) string {
+	var b strings.Builder
+	var plain strings.Builder // Pending plain text, flushed through text()
+	flush := func() {
+		b.WriteString(r.text(plain.String()))
+		plain.Reset()
+	}
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		if strings.IndexByte("\\`[!*_", rest[0]) >= 0 {
+			flush()
+		}
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.IndexByte(escapableRune, rest[1]) >= 0:
+			b.WriteString(html.EscapeString(rest[1:2]))
+			i += 2
+			continue
+
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				b.WriteString("<code>" + html.EscapeString(rest[1:1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+
+		case strings.HasPrefix(rest, "[["):
+			if end := strings.Index(rest, "]]"); end > 2 {
+				name, label, _ := strings.Cut(rest[2:end], "|")
+				name = strings.TrimSpace(name)
+				if label = strings.TrimSpace(label); label == "" {
+					label = name
+				}
+				if name != "" {
+					r.addLink(name)
+					b.WriteString(`<a class="species-link" href="` + html.EscapeString(r.opts.SpeciesURL(name)) + `">` +
+						html.EscapeString(label) + "</a>")
+					i += end + 2
+					continue
+				}
+			}
+
+		case strings.HasPrefix(rest, "!["):
+			if text, target, n, ok := linkParts(rest[1:]); ok {
+				if safeURL(target) {
+					r.addImage(target)
+					b.WriteString(`<img src="` + html.EscapeString(target) + `" alt="` + html.EscapeString(text) + `" loading="lazy">`)
+				} else {
+					b.WriteString(html.EscapeString(text))
+				}
+				i += n + 1
+				continue
+			}
+
+		case rest[0] == '[':
+			if text, target, n, ok := linkParts(rest); ok {
+				if safeURL(target) {
+					r.inLink = true
+					b.WriteString(`<a href="` + html.EscapeString(target) + `">` + r.inline(text) + "</a>")
+					r.inLink = false
+				} else {
+					b.WriteString(r.inline(text))
+				}
+				i += n
+				continue
+			}
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if end := strings.Index(rest[2:], rest[:2]); end > 0 {
+				b.WriteString("<strong>" + r.inline(rest[2:2+end]) + "</strong>")
+				i += end + 4
+				continue
+			}
+
+		case rest[0] == '*' || rest[0] == '_':
+			if end := strings.IndexByte(rest[1:], rest[0]); end > 0 && rest[1] != ' ' {
+				b.WriteString("<em>" + r.inline(rest[1:1+end]) + "</em>")
+				i += end + 2
+				continue
+			}
+		}
+
+		plain.WriteByte(rest[0])
+		i++
+	}
+	flush()
+	return b.String()
+}
+
+// text escapes plain text and links any species mentions found by Options.Linkify
+func (r *renderer) text(s string) string {
+	if s == "" || r.opts.Linkify == nil || r.inLink {
+		return html.EscapeString(s)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, span := range r.opts.Linkify(s) {
+		if span.Start < last || span.End > len(s) || span.Start >= span.End {
+			continue
+		}
+		r.addLink(span.Name)
+		b.WriteString(html.EscapeString(s[last:span.Start]))
+		b.WriteString(`<a class="species-link" href="` + html.EscapeString(r.opts.SpeciesURL(span.Name)) + `">` +
+			html.EscapeString(s[span.Start:span.End]) + "</a>")
+		last = span.End
+	}
+	b.WriteString(html.EscapeString(s[last:]))
+	return b.String()
+}
+
+func (r *renderer) addLink(name string) {
+	if !r.seenLinks[name] {
+		r.seenLinks[name] = true
+		r.links = append(r.links, name)
+	}
+}
+
+func (r *renderer) addImage(src string) {
+	if !r.seenImages[src] {
+		r.seenImages[src] = true
+		r.images = append(r.images, src)
+	}
+}
+
+// linkParts parses "[text](target)" at the start of s, returning the text,
+// the target, and the number of bytes consumed
+func linkParts(s string) (text, target string, n int, ok bool) {
+	closeText := strings.Index(s, "](")
+	if !strings.HasPrefix(s, "[") || closeText < 0 {
+		return "", "", 0, false
+	}
+	// Targets may contain balanced parentheses, as in Wikipedia URLs
+	closeTarget, depth := -1, 0
+	for j, c := range s[closeText+2:] {
+		if c == '(' {
+			depth++
+		} else if c == ')' {
+			if depth == 0 {
+				closeTarget = j
+				break
+			}
+			depth--
+		}
+	}
+	if closeTarget < 0 {
+		return "", "", 0, false
+	}
+	text = s[1:closeText]
+	target = strings.TrimSpace(s[closeText+2 : closeText+2+closeTarget])
+	return text, target, closeText + 3 + closeTarget, true
+}
+
+// safeURL rejects targets with schemes other than http, https, and mailto,
+// such as javascript: URLs. Relative paths are allowed.
+func safeURL(target string) bool {
+	if target == "" {
+		return false
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" {
+		// A colon before the first slash would be read as a scheme by browsers
+		colon := strings.IndexByte(target, ':')
+		return colon < 0 || strings.ContainsAny(target[:colon], "/?#")
+	}
+	scheme := strings.ToLower(u.Scheme)
+	for _, s := range safeSchemes {
+		if scheme == s {
+			return true
+		}
+	}
+	return false
+}
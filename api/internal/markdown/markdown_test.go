@@ -0,0 +1,69 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderBlocks(t *testing.T) {
+	src := "# Quercus rubra\n\nA large *deciduous* tree.\nFast growing.\n\n" +
+		"- leaves **lobed**\n- acorns\n  biennial\n\n1. one\n2. two\n\n" +
+		"> quoted\n> text\n\n---\n\n```go\nx := <b>\n```\n"
+	want := "<h1>Quercus rubra</h1>\n" +
+		"<p>A large <em>deciduous</em> tree.\nFast growing.</p>\n" +
+		"<ul>\n<li>leaves <strong>lobed</strong></li>\n<li>acorns\nbiennial</li>\n</ul>\n" +
+		"<ol>\n<li>one</li>\n<li>two</li>\n</ol>\n" +
+		"<blockquote>\n<p>quoted\ntext</p>\n</blockquote>\n" +
+		"<hr>\n" +
+		"<pre><code class=\"language-go\">x := &lt;b&gt;</code></pre>\n"
+
+	got := Render(src, Options{}).HTML
+	if got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderInline(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"escapes html", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"code", "use `a*b*c`", "<p>use <code>a*b*c</code></p>\n"},
+		{"link", "[USDA](https://plants.usda.gov)", `<p><a href="https://plants.usda.gov">USDA</a></p>` + "\n"},
+		{"unsafe link", "[click](javascript:alert(1))", "<p>click</p>\n"},
+		{"relative image", "![Leaf](images/rubra-leaf.jpg)", `<p><img src="images/rubra-leaf.jpg" alt="Leaf" loading="lazy"></p>` + "\n"},
+		{"cross-link", "See [[rubra]].", `<p>See <a class="species-link" href="/species/rubra/">rubra</a>.</p>` + "\n"},
+		{"cross-link label", "[[× bebbiana|Bebb's oak]]", `<p><a class="species-link" href="/species/%C3%97%20bebbiana/">Bebb&#39;s oak</a></p>` + "\n"},
+		{"escaped markup", `\*not em\*`, "<p>*not em*</p>\n"},
+		{"lone asterisk", "5 * 3", "<p>5 * 3</p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.src, Options{}).HTML; got != tt.want {
+				t.Errorf("Render(%q) = %q, want %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderSpeciesURL(t *testing.T) {
+	got := Render("[[alba]]", Options{SpeciesURL: func(name string) string { return "#" + name }}).HTML
+	if !strings.Contains(got, `href="#alba"`) {
+		t.Errorf("custom SpeciesURL not used: %s", got)
+	}
+}
+
+func TestReferences(t *testing.T) {
+	links, images := References("[[rubra]] and [[alba|white oak]], again [[rubra]].\n\n" +
+		"![a](a.jpg) ![b](https://example.com/b.jpg) ![a again](a.jpg) ![bad](javascript:x)")
+
+	if want := []string{"rubra", "alba"}; !reflect.DeepEqual(links, want) {
+		t.Errorf("links = %v, want %v", links, want)
+	}
+	if want := []string{"a.jpg", "https://example.com/b.jpg"}; !reflect.DeepEqual(images, want) {
+		t.Errorf("images = %v, want %v", images, want)
+	}
+}
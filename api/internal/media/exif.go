@@ -0,0 +1,206 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EXIF is what a photo's EXIF data says of where and when it was taken
+type EXIF struct {
+	CapturedAt  string   // As the camera recorded it: 2006-01-02T15:04:05, with an offset if it gave one; "" if unknown
+	Latitude    *float64 // Decimal degrees, south negative
+	Longitude   *float64 // Decimal degrees, west negative
+	Orientation int      // 1-8 as in EXIF; 0 if not given
+}
+
+// EXIF tags read
+const (
+	tagOrientation        = 0x0112
+	tagDateTime           = 0x0132
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+	tagGPSLatitudeRef     = 0x0001
+	tagGPSLatitude        = 0x0002
+	tagGPSLongitudeRef    = 0x0003
+	tagGPSLongitude       = 0x0004
+)
+
+// exifTimeLayout is how EXIF writes times
+const exifTimeLayout = "2006:01:02 15:04:05"
+
+// ReadEXIF reads the EXIF data of a JPEG. It reads what it can: data that
+// is missing or malformed leaves fields empty, and only a JPEG whose EXIF
+// block can't be parsed at all is an error.
+func ReadEXIF(data []byte) (EXIF, error) {
+	tiff := exifSegment(data)
+	if tiff == nil {
+		return EXIF{}, nil
+	}
+	ifd0, order, err := readTIFF(tiff)
+	if err != nil {
+		return EXIF{}, err
+	}
+
+	var e EXIF
+	if v, ok := ifd0[tagOrientation]; ok && len(v.ints) > 0 {
+		e.Orientation = int(v.ints[0])
+	}
+	taken, offset := "", ""
+	if v, ok := ifd0[tagExifIFD]; ok && len(v.ints) > 0 {
+		if exif, err := readIFD(tiff, order, v.ints[0]); err == nil {
+			taken, offset = exif[tagDateTimeOriginal].text, exif[tagOffsetTimeOriginal].text
+		}
+	}
+	if taken == "" {
+		taken = ifd0[tagDateTime].text
+	}
+	if t, err := time.Parse(exifTimeLayout, taken); err == nil {
+		e.CapturedAt = t.Format("2006-01-02T15:04:05")
+		if _, err := time.Parse("-07:00", offset); err == nil {
+			e.CapturedAt += offset
+		}
+	}
+	if v, ok := ifd0[tagGPSIFD]; ok && len(v.ints) > 0 {
+		if gps, err := readIFD(tiff, order, v.ints[0]); err == nil {
+			e.Latitude = gpsCoordinate(gps[tagGPSLatitude], gps[tagGPSLatitudeRef].text, "S", 90)
+			e.Longitude = gpsCoordinate(gps[tagGPSLongitude], gps[tagGPSLongitudeRef].text, "W", 180)
+		}
+	}
+	return e, nil
+}
+
+// exifSegment returns the TIFF data of a JPEG's EXIF APP1 segment, or nil
+func exifSegment(data []byte) []byte {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return nil
+		}
+		marker := data[i+1]
+		if marker == 0xD8 || (marker >= 0xD0 && marker <= 0xD7) || marker == 0x01 || marker == 0xFF {
+			i++ // Markers without a length, and fill bytes
+			continue
+		}
+		if marker == 0xDA || marker == 0xD9 {
+			return nil // Image data starts; metadata comes before it
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return nil
+		}
+		if segment := data[i+4 : end]; marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:]
+		}
+		i = end
+	}
+	return nil
+}
+
+// tiffValue is an IFD entry's value: numbers for integer types, numerator
+// and denominator pairs for rationals, and text for ASCII
+type tiffValue struct {
+	ints      []uint32
+	rationals [][2]uint32
+	text      string
+}
+
+// readTIFF reads the header and first IFD of TIFF data
+func readTIFF(tiff []byte) (map[uint16]tiffValue, binary.ByteOrder, error) {
+	if len(tiff) < 8 {
+		return nil, nil, fmt.Errorf("EXIF data too short")
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, nil, fmt.Errorf("EXIF data has unknown byte order %q", tiff[:2])
+	}
+	if order.Uint16(tiff[2:]) != 42 {
+		return nil, nil, fmt.Errorf("EXIF data is not TIFF")
+	}
+	ifd, err := readIFD(tiff, order, order.Uint32(tiff[4:]))
+	return ifd, order, err
+}
+
+// readIFD reads the entries of the IFD at offset, skipping types it
+// doesn't need and entries that point outside the data
+func readIFD(tiff []byte, order binary.ByteOrder, offset uint32) (map[uint16]tiffValue, error) {
+	if uint64(offset)+2 > uint64(len(tiff)) {
+		return nil, fmt.Errorf("EXIF IFD offset %d out of range", offset)
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	entries := tiff[offset+2:]
+	if count*12 > len(entries) {
+		return nil, fmt.Errorf("EXIF IFD at %d truncated", offset)
+	}
+
+	values := make(map[uint16]tiffValue, count)
+	for i := 0; i < count; i++ {
+		entry := entries[i*12 : i*12+12]
+		tag, typ, n := order.Uint16(entry), order.Uint16(entry[2:]), order.Uint32(entry[4:])
+		size := map[uint16]uint32{2: 1, 3: 2, 4: 4, 5: 8}[typ]
+		if size == 0 || n == 0 || n > 1<<16 {
+			continue
+		}
+		raw := entry[8:12]
+		if size*n > 4 {
+			start := uint64(order.Uint32(entry[8:]))
+			if start+uint64(size*n) > uint64(len(tiff)) {
+				continue
+			}
+			raw = tiff[start : start+uint64(size*n)]
+		}
+
+		var v tiffValue
+		for j := uint32(0); j < n; j++ {
+			switch typ {
+			case 3:
+				v.ints = append(v.ints, uint32(order.Uint16(raw[j*2:])))
+			case 4:
+				v.ints = append(v.ints, order.Uint32(raw[j*4:]))
+			case 5:
+				v.rationals = append(v.rationals, [2]uint32{order.Uint32(raw[j*8:]), order.Uint32(raw[j*8+4:])})
+			}
+		}
+		if typ == 2 {
+			v.text = strings.TrimSpace(strings.TrimRight(string(raw[:n]), "\x00"))
+		}
+		values[tag] = v
+	}
+	return values, nil
+}
+
+// gpsCoordinate converts degrees, minutes, and seconds to decimal degrees,
+// negative when ref is negativeRef. Returns nil if v isn't a coordinate
+// within limit.
+func gpsCoordinate(v tiffValue, ref, negativeRef string, limit float64) *float64 {
+	if len(v.rationals) != 3 {
+		return nil
+	}
+	var parts [3]float64
+	for i, r := range v.rationals {
+		if r[1] == 0 {
+			return nil
+		}
+		parts[i] = float64(r[0]) / float64(r[1])
+	}
+	degrees := parts[0] + parts[1]/60 + parts[2]/3600
+	if degrees > limit {
+		return nil
+	}
+	if strings.EqualFold(ref, negativeRef) {
+		degrees = -degrees
+	}
+	return &degrees
+}
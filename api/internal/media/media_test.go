@@ -0,0 +1,161 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"os"
+	"testing"
+)
+
+// testEXIF is big-endian TIFF data with orientation 6, a capture time, and
+// GPS coordinates 38°53'52.8"N 77°2'11.4"W
+func testEXIF() []byte {
+	var b bytes.Buffer
+	write := func(v ...any) {
+		for _, x := range v {
+			binary.Write(&b, binary.BigEndian, x)
+		}
+	}
+	entry := func(tag, typ uint16, count, value uint32) { write(tag, typ, count, value) }
+
+	write([]byte("MM"), uint16(42), uint32(8))
+	// IFD0 at 8: orientation, then pointers to the Exif IFD (50) and GPS IFD (88)
+	write(uint16(3))
+	entry(tagOrientation, 3, 1, 6<<16)
+	entry(tagExifIFD, 4, 1, 50)
+	entry(tagGPSIFD, 4, 1, 88)
+	write(uint32(0))
+	// Exif IFD at 50: DateTimeOriginal, its text at 68
+	write(uint16(1))
+	entry(tagDateTimeOriginal, 2, 20, 68)
+	write(uint32(0))
+	write([]byte("2024:05:03 14:22:10\x00"))
+	// GPS IFD at 88, rationals at 142 and 166
+	write(uint16(4))
+	entry(tagGPSLatitudeRef, 2, 2, uint32('N')<<24)
+	entry(tagGPSLatitude, 5, 3, 142)
+	entry(tagGPSLongitudeRef, 2, 2, uint32('W')<<24)
+	entry(tagGPSLongitude, 5, 3, 166)
+	write(uint32(0))
+	write([]uint32{38, 1, 53, 1, 528, 10})
+	write([]uint32{77, 1, 2, 1, 114, 10})
+	return b.Bytes()
+}
+
+// testJPEG encodes a w×h JPEG with tiff as its EXIF data, if not nil
+func testJPEG(t *testing.T, w, h int, tiff []byte) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 50, B: 50, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if tiff == nil {
+		return data
+	}
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1, byte((len(segment) + 2) >> 8), byte(len(segment) + 2)}
+	return append(append(append([]byte{}, data[:2]...), append(app1, segment...)...), data[2:]...)
+}
+
+func TestReadEXIF(t *testing.T) {
+	e, err := ReadEXIF(testJPEG(t, 8, 8, testEXIF()))
+	if err != nil {
+		t.Fatalf("ReadEXIF() error = %v", err)
+	}
+	if e.CapturedAt != "2024-05-03T14:22:10" || e.Orientation != 6 {
+		t.Errorf("EXIF = %+v, want captured 2024-05-03T14:22:10, orientation 6", e)
+	}
+	if e.Latitude == nil || math.Abs(*e.Latitude-38.898) > 1e-6 || e.Longitude == nil || math.Abs(*e.Longitude+77.0365) > 1e-6 {
+		t.Errorf("coordinates = %v, %v, want 38.898, -77.0365", e.Latitude, e.Longitude)
+	}
+
+	if e, err := ReadEXIF(testJPEG(t, 8, 8, nil)); err != nil || e != (EXIF{}) {
+		t.Errorf("ReadEXIF(no EXIF) = %+v, %v, want empty", e, err)
+	}
+	if _, err := ReadEXIF(testJPEG(t, 8, 8, []byte("XX\x00\x2a\x00\x00\x00\x08"))); err == nil {
+		t.Error("ReadEXIF(bad byte order) error = nil")
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 1000, 500))
+	thumb := Thumbnail(img, 320, 0)
+	if got := thumb.Bounds().Size(); got != image.Pt(320, 160) {
+		t.Errorf("size = %v, want 320x160", got)
+	}
+	if got := thumb.At(0, 0); got != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("transparent pixel = %v, want white", got)
+	}
+	if got := Thumbnail(img, 320, 6).Bounds().Size(); got != image.Pt(160, 320) {
+		t.Errorf("size turned upright = %v, want 160x320", got)
+	}
+	if got := Thumbnail(image.NewRGBA(image.Rect(0, 0, 40, 30)), 320, 0).Bounds().Size(); got != image.Pt(40, 30) {
+		t.Errorf("small image size = %v, want 40x30 unchanged", got)
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(t.TempDir() + "/media")
+	saved, err := store.Save(testJPEG(t, 640, 480, testEXIF()))
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if saved.ContentType != "image/jpeg" || saved.Width != 640 || saved.Height != 480 || saved.EXIF.Orientation != 6 {
+		t.Errorf("saved = %+v", saved)
+	}
+	if saved.File != saved.Hash+".jpg" || saved.ThumbnailFile != saved.Hash+"-thumb.jpg" {
+		t.Errorf("files = %s, %s, want named by hash", saved.File, saved.ThumbnailFile)
+	}
+	path, ok := store.Path(saved.ThumbnailFile)
+	if !ok {
+		t.Fatalf("Path(%s) not ok", saved.ThumbnailFile)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	thumb, err := jpeg.Decode(f)
+	if err != nil {
+		t.Fatalf("thumbnail is not a JPEG: %v", err)
+	}
+	if got := thumb.Bounds().Size(); got != image.Pt(240, 320) {
+		t.Errorf("thumbnail size = %v, want 240x320 upright", got)
+	}
+
+	var p bytes.Buffer
+	png.Encode(&p, image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	if saved, err := store.Save(p.Bytes()); err != nil || saved.File != saved.Hash+".png" {
+		t.Errorf("Save(png) = %+v, %v", saved, err)
+	}
+	if _, err := store.Save([]byte("%PDF-1.4")); err != ErrUnsupported {
+		t.Errorf("Save(pdf) error = %v, want ErrUnsupported", err)
+	}
+	if err := store.Remove(saved.File, saved.ThumbnailFile, "../oak_compendium.db"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("thumbnail after Remove: %v, want gone", err)
+	}
+	if err := store.Remove(saved.File); err != nil {
+		t.Errorf("Remove() of a removed file error = %v", err)
+	}
+
+	for _, name := range []string{"../oak_compendium.db", "abc.jpg", ""} {
+		if _, ok := store.Path(name); ok {
+			t.Errorf("Path(%q) ok, want rejected", name)
+		}
+	}
+}
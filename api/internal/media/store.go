@@ -0,0 +1,158 @@
+// Package media stores uploaded species photos: the image files, a JPEG
+// thumbnail of each, and what their EXIF data says of when and where they
+// were taken.
+package media
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // Register decoders for image.Decode
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// ErrUnsupported is returned for files that aren't JPEG, PNG, or GIF images
+var ErrUnsupported = errors.New("not a JPEG, PNG, or GIF image")
+
+// Extensions of the image types stored, by content type
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// maxPixels is the largest image Save decodes, in pixels; larger ones would
+// take too much memory
+const maxPixels = 100_000_000
+
+// fileNamePattern matches the names Save gives files
+var fileNamePattern = regexp.MustCompile(`^[0-9a-f]{64}(\.jpg|\.png|\.gif|-thumb\.jpg)$`)
+
+// Store keeps media files in a directory, named by the SHA-256 of their
+// content, so the same image is stored once however often it is saved
+type Store struct {
+	dir string
+}
+
+// Saved is an image Save stored
+type Saved struct {
+	File          string // File name in the store
+	ThumbnailFile string
+	ContentType   string
+	Hash          string // Hex SHA-256 of the content
+	Width, Height int    // As stored, before any EXIF orientation
+	EXIF          EXIF
+}
+
+// NewStore creates a store of files in dir, which is created on first save
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Save stores an image and a thumbnail of it, and reads its EXIF data
+func (s *Store) Save(data []byte) (*Saved, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d is too large", ErrUnsupported, config.Width, config.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+
+	sum := sha256.Sum256(data)
+	saved := &Saved{
+		Hash:        hex.EncodeToString(sum[:]),
+		ContentType: contentType,
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}
+	saved.File = saved.Hash + ext
+	saved.ThumbnailFile = saved.Hash + "-thumb.jpg"
+	if contentType == "image/jpeg" {
+		// A broken EXIF block shouldn't stop the photo being stored
+		saved.EXIF, _ = ReadEXIF(data)
+	}
+
+	var thumb bytes.Buffer
+	if err := jpeg.Encode(&thumb, Thumbnail(img, ThumbnailSize, saved.EXIF.Orientation), &jpeg.Options{Quality: 80}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %w", err)
+	}
+	if err := writeFile(filepath.Join(s.dir, saved.File), data); err != nil {
+		return nil, err
+	}
+	if err := writeFile(filepath.Join(s.dir, saved.ThumbnailFile), thumb.Bytes()); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// Path returns the path of a stored file, or false if name isn't one Save
+// could have given
+func (s *Store) Path(name string) (string, bool) {
+	if !fileNamePattern.MatchString(name) {
+		return "", false
+	}
+	return filepath.Join(s.dir, name), true
+}
+
+// Remove deletes stored files, ignoring names Save could not have given and
+// files already gone
+func (s *Store) Remove(names ...string) error {
+	for _, name := range names {
+		path, ok := s.Path(name)
+		if !ok {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove media file: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeFile writes data to path through a temporary file, so a reader never
+// sees a partial file. An existing file already has the same content.
+func writeFile(path string, data []byte) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create media file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write media file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write media file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write media file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write media file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package media
+
+import (
+	"image"
+	"image/color"
+)
+
+// ThumbnailSize is the longest side of a thumbnail, in pixels
+const ThumbnailSize = 320
+
+// maxSamples is the most source pixels averaged along each axis for one
+// thumbnail pixel; large photos are sampled rather than fully averaged
+const maxSamples = 4
+
+// Thumbnail scales img down so its longest side is at most size, turned
+// upright by an EXIF orientation (mirrored orientations are not flipped).
+// Transparent areas become white, since thumbnails are saved as JPEG.
+func Thumbnail(img image.Image, size, orientation int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, max(1, h*size/w)
+		} else {
+			tw, th = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+(y+1)*h/th
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+(x+1)*w/tw
+			dst.SetRGBA(x, y, averageOverWhite(img, x0, y0, max(x1, x0+1), max(y1, y0+1)))
+		}
+	}
+	return orient(dst, orientation)
+}
+
+// averageOverWhite averages up to maxSamples² pixels spread over the
+// rectangle [x0,x1)×[y0,y1), composited over white
+func averageOverWhite(img image.Image, x0, y0, x1, y1 int) color.RGBA {
+	xStep, yStep := max(1, (x1-x0)/maxSamples), max(1, (y1-y0)/maxSamples)
+	var r, g, b, n uint64
+	for y := y0; y < y1; y += yStep {
+		for x := x0; x < x1; x += xStep {
+			pr, pg, pb, pa := img.At(x, y).RGBA() // Premultiplied, 0-0xffff
+			white := 0xffff - uint64(pa)
+			r += uint64(pr) + white
+			g += uint64(pg) + white
+			b += uint64(pb) + white
+			n++
+		}
+	}
+	return color.RGBA{R: uint8(r / n >> 8), G: uint8(g / n >> 8), B: uint8(b / n >> 8), A: 0xff}
+}
+
+// orient turns img upright for EXIF orientations 3 (upside down), 6 (turned
+// left), and 8 (turned right), returning it unchanged otherwise
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation != 3 && orientation != 6 && orientation != 8 {
+		return img
+	}
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if orientation == 3 {
+		dst := image.NewRGBA(image.Rect(0, 0, w, h))
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				dst.SetRGBA(w-1-x, h-1-y, img.RGBAAt(x, y))
+			}
+		}
+		return dst
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, h, w))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if orientation == 6 {
+				dst.SetRGBA(h-1-y, x, img.RGBAAt(x, y)) // Rotate clockwise
+			} else {
+				dst.SetRGBA(y, w-1-x, img.RGBAAt(x, y)) // Rotate counterclockwise
+			}
+		}
+	}
+	return dst
+}
@@ -0,0 +1,117 @@
+// Package mentions detects species named in free text, such as "closely
+// resembles Q. stellata" or "Quercus × bebbiana", so they can be cross-linked.
+package mentions
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jeff/oaks/api/internal/markdown"
+)
+
+// Species is a species that mentions can resolve to
+type Species struct {
+	Name  string // Scientific name as stored, e.g. "stellata" or "× bebbiana"
+	Genus string
+}
+
+// Resolver finds mentions of a fixed set of species
+type Resolver struct {
+	re    *regexp.Regexp // nil when there are no species
+	genus map[string]string
+}
+
+// contextRadius is how many bytes of text Context keeps on each side of a mention
+const contextRadius = 60
+
+// NewResolver builds a resolver for the given species. A mention is the genus
+// name or its initial ("Quercus", "Q.") followed by the epithet, with an
+// optional hybrid sign ("×" or "x").
+func NewResolver(species []Species) *Resolver {
+	r := &Resolver{genus: make(map[string]string, len(species))}
+	prefixes := map[string]bool{}
+	for _, s := range species {
+		r.genus[s.Name] = s.Genus
+		if s.Genus != "" {
+			prefixes[regexp.QuoteMeta(s.Genus)] = true
+			prefixes[regexp.QuoteMeta(s.Genus[:1]+".")] = true
+		}
+	}
+	if len(prefixes) == 0 {
+		return r
+	}
+
+	// Longest first so "Quercus" wins over a shorter alternative
+	alts := make([]string, 0, len(prefixes))
+	for p := range prefixes {
+		alts = append(alts, p)
+	}
+	sort.Slice(alts, func(i, j int) bool {
+		if len(alts[i]) != len(alts[j]) {
+			return len(alts[i]) > len(alts[j])
+		}
+		return alts[i] < alts[j]
+	})
+	r.re = regexp.MustCompile(`\b(` + strings.Join(alts, "|") + `)\s+(?:(×)\s*|(x)\s+)?([a-z][a-z-]*[a-z])\b`)
+	return r
+}
+
+// Known reports whether name is one of the resolver's species
+func (r *Resolver) Known(name string) bool {
+	_, ok := r.genus[name]
+	return ok
+}
+
+// Find returns the species mentioned in text, in order of appearance.
+// Mentions of species the resolver doesn't know are ignored.
+func (r *Resolver) Find(text string) []markdown.Span {
+	if r.re == nil {
+		return nil
+	}
+
+	var spans []markdown.Span
+	for _, m := range r.re.FindAllStringSubmatchIndex(text, -1) {
+		prefix := text[m[2]:m[3]]
+		name := text[m[8]:m[9]]
+		if m[4] >= 0 || m[6] >= 0 {
+			name = "× " + name
+		}
+
+		genus, ok := r.genus[name]
+		if !ok {
+			continue
+		}
+		if strings.HasSuffix(prefix, ".") {
+			if genus == "" || genus[:1] != prefix[:1] {
+				continue
+			}
+		} else if prefix != genus {
+			continue
+		}
+
+		spans = append(spans, markdown.Span{Start: m[0], End: m[1], Name: name})
+	}
+	return spans
+}
+
+// Context returns the text around a mention, with whitespace collapsed and
+// an ellipsis where the text was cut
+func Context(text string, span markdown.Span) string {
+	start, end := span.Start-contextRadius, span.End+contextRadius
+	prefix, suffix := "…", "…"
+	if start <= 0 {
+		start, prefix = 0, ""
+	}
+	if end >= len(text) {
+		end, suffix = len(text), ""
+	}
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	return prefix + strings.Join(strings.Fields(text[start:end]), " ") + suffix
+}
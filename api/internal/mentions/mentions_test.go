@@ -0,0 +1,55 @@
+package mentions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeff/oaks/api/internal/markdown"
+)
+
+func TestFind(t *testing.T) {
+	r := NewResolver([]Species{
+		{Name: "stellata", Genus: "Quercus"},
+		{Name: "alba", Genus: "Quercus"},
+		{Name: "× bebbiana", Genus: "Quercus"},
+		{Name: "densiflorus", Genus: "Notholithocarpus"},
+		{Name: "xalapensis", Genus: "Quercus"},
+	})
+
+	text := "Closely resembles Q. stellata and Quercus alba; see also Q. x bebbiana, " +
+		"N. densiflorus, Q. densiflorus, Q. velutina, Q. albata, and Q. xalapensis."
+	var got []string
+	for _, span := range r.Find(text) {
+		got = append(got, span.Name+"="+text[span.Start:span.End])
+	}
+
+	want := []string{
+		"stellata=Q. stellata",
+		"alba=Quercus alba",
+		"× bebbiana=Q. x bebbiana",
+		"densiflorus=N. densiflorus",
+		"xalapensis=Q. xalapensis",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Find() = %v, want %v", got, want)
+	}
+}
+
+func TestFindNoSpecies(t *testing.T) {
+	if spans := NewResolver(nil).Find("Q. alba"); spans != nil {
+		t.Errorf("Find() = %v, want nil", spans)
+	}
+}
+
+func TestContext(t *testing.T) {
+	text := strings.Repeat("word ", 30) + "Q. alba\n\n" + strings.Repeat("more ", 30)
+	start := strings.Index(text, "Q. alba")
+	got := Context(text, markdown.Span{Start: start, End: start + len("Q. alba"), Name: "alba"})
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") || !strings.Contains(got, "word Q. alba more") {
+		t.Errorf("Context() = %q", got)
+	}
+
+	if got := Context("Q. alba", markdown.Span{Start: 0, End: 7}); got != "Q. alba" {
+		t.Errorf("Context() = %q, want %q", got, "Q. alba")
+	}
+}
@@ -1,5 +1,12 @@
 package models
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // TaxonLevel represents the hierarchical level of a taxon
 type TaxonLevel string
 
@@ -10,6 +17,19 @@ const (
 	TaxonLevelComplex    TaxonLevel = "complex"
 )
 
+// TaxonLevelDef defines one configurable rank in the taxonomy below genus.
+// Levels are ordered by Rank (lowest is closest to genus).
+type TaxonLevelDef struct {
+	Name       string  `json:"name" yaml:"name"`
+	Rank       int     `json:"rank" yaml:"rank"`
+	Plural     *string `json:"plural,omitempty" yaml:"plural,omitempty"`           // e.g., "subgenera"; used as the taxa import key
+	EntryField *string `json:"entry_field,omitempty" yaml:"entry_field,omitempty"` // Species column for this rank, if any
+	TaxaCount  int     `json:"taxa_count" yaml:"taxa_count"`
+}
+
+// TaxonEntryFields are the oak entry columns a taxon level can map to
+var TaxonEntryFields = []string{"subgenus", "section", "subsection", "complex"}
+
 // TaxonLink represents a labeled external link for a taxon
 type TaxonLink struct {
 	Label string `json:"label" yaml:"label"` // e.g., "iNaturalist", "Wikipedia"
@@ -23,16 +43,197 @@ type ExternalLink struct {
 	Logo string `json:"logo" yaml:"logo"` // Identifier for bundled SVG icon (e.g., "wikipedia", "inaturalist")
 }
 
+// ExternalLinkLogos are the bundled icons an external link's Logo may name
+var ExternalLinkLogos = []string{"wikipedia", "inaturalist", "usda", "gbif", "powo", "generic"}
+
+// DefaultGenus is the genus of entries and taxa created without one.
+const DefaultGenus = "Quercus"
+
+// Genus is a genus tracked by the database. Each genus has its own taxa hierarchy.
+type Genus struct {
+	Name         string   `json:"name" yaml:"name"`
+	CommonName   *string  `json:"common_name,omitempty" yaml:"common_name,omitempty"` // e.g., "Oaks"
+	Subgenera    []string `json:"subgenera" yaml:"subgenera"`                         // Valid subgenus names; empty allows any
+	SpeciesCount int      `json:"species_count" yaml:"species_count"`
+}
+
+// SpeciesAccount is a long-form markdown document about a species, stored
+// separately from the entry. HTML and the reference lists are derived from Markdown.
+type SpeciesAccount struct {
+	ScientificName string   `json:"scientific_name"`
+	Markdown       string   `json:"markdown"`
+	HTML           string   `json:"html"`
+	Links          []string `json:"links"`         // Species cross-linked with [[name]]
+	MissingLinks   []string `json:"missing_links"` // Cross-links to species not in the database
+	Images         []string `json:"images"`
+	UpdatedAt      string   `json:"updated_at"`
+}
+
+// CommonName is a vernacular name of a species in one language. Language is
+// a BCP 47 tag ("en", "fr", "zh-Hant", "es-MX"); "und" marks names whose
+// language is not known.
+type CommonName struct {
+	Name     string `json:"name"`
+	Language string `json:"language"`
+	SourceID *int64 `json:"source_id,omitempty"` // Source the name was taken from
+}
+
+// LanguageUndetermined is the BCP 47 tag for names in an unknown language
+const LanguageUndetermined = "und"
+
+// CanonicalLanguageTag checks that tag is a well-formed BCP 47 language tag
+// (language, optional script and region, variants; no extensions or private
+// use) and returns it in canonical case: "EN-us" becomes "en-US" and
+// "zh-hant" "zh-Hant". ok is false if it is not well formed.
+func CanonicalLanguageTag(tag string) (canonical string, ok bool) {
+	subtags := strings.Split(strings.TrimSpace(tag), "-")
+	if !isAlpha(subtags[0]) || len(subtags[0]) < 2 || len(subtags[0]) > 8 || len(subtags[0]) == 4 {
+		return tag, false
+	}
+	out := []string{strings.ToLower(subtags[0])}
+
+	i := 1
+	if i < len(subtags) && len(subtags[i]) == 4 && isAlpha(subtags[i]) {
+		out = append(out, strings.ToUpper(subtags[i][:1])+strings.ToLower(subtags[i][1:]))
+		i++
+	}
+	if i < len(subtags) && ((len(subtags[i]) == 2 && isAlpha(subtags[i])) || (len(subtags[i]) == 3 && isDigits(subtags[i]))) {
+		out = append(out, strings.ToUpper(subtags[i]))
+		i++
+	}
+	for ; i < len(subtags); i++ {
+		v := subtags[i]
+		if !isAlphanumeric(v) || !(len(v) >= 5 && len(v) <= 8 || len(v) == 4 && isDigits(v[:1])) {
+			return tag, false
+		}
+		out = append(out, strings.ToLower(v))
+	}
+	return strings.Join(out, "-"), true
+}
+
+// LanguageMatches reports whether a language tag serves a visitor asking for
+// language range want: when one is the other or a more specific form of it
+// ("en" serves "en-US" and "en-US" serves "en"), or want is "*". Tags
+// compare case-insensitively.
+func LanguageMatches(tag, want string) bool {
+	tag, want = strings.ToLower(tag), strings.ToLower(want)
+	return want == "*" || tag == want || strings.HasPrefix(tag, want+"-") || strings.HasPrefix(want, tag+"-")
+}
+
+func isAlpha(s string) bool {
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isAlphanumeric(s string) bool {
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// CrossReference records that one species' text mentions another, either as
+// a [[link]] in its account or by name ("Q. stellata") in account or source text.
+type CrossReference struct {
+	ScientificName string `json:"scientific_name"` // Species whose text contains the mention
+	MentionedName  string `json:"mentioned_name"`
+	Location       string `json:"location"` // "account" or "sources/{source_id}/{field}"
+	Context        string `json:"context"`  // Text around the mention
+}
+
+// Template pre-fills new species entries for a taxonomic group, such as
+// section Lobatae: taxonomy, sources to attach, and fields that must be set.
+type Template struct {
+	Name           string   `json:"name" yaml:"name"`
+	Description    *string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Genus          string   `json:"genus" yaml:"genus"`
+	Subgenus       *string  `json:"subgenus,omitempty" yaml:"subgenus,omitempty"`
+	Section        *string  `json:"section,omitempty" yaml:"section,omitempty"`
+	Subsection     *string  `json:"subsection,omitempty" yaml:"subsection,omitempty"`
+	Complex        *string  `json:"complex,omitempty" yaml:"complex,omitempty"`
+	SourceIDs      []int64  `json:"source_ids" yaml:"source_ids"`           // Attached to new species; the first is preferred
+	RequiredFields []string `json:"required_fields" yaml:"required_fields"` // From TemplateRequiredFields
+}
+
+// TemplateRequiredFields are the species fields a template can require
+var TemplateRequiredFields = []string{
+	"author", "conservation_status", "subgenus", "section", "subsection", "complex",
+	"parent1", "parent2", "synonyms", "closely_related_to", "external_links",
+}
+
+// Report is a saved read-only SQL query, run on demand as CSV or JSON
+type Report struct {
+	Name        string         `json:"name" yaml:"name"`
+	Title       string         `json:"title" yaml:"title"`
+	Description *string        `json:"description,omitempty" yaml:"description,omitempty"`
+	Query       string         `json:"query" yaml:"query"`
+	Columns     []ReportColumn `json:"columns" yaml:"columns"` // Empty for every column the query returns
+	Format      string         `json:"format" yaml:"format"`   // ReportFormatCSV or ReportFormatJSON, when run without ?format=
+}
+
+// ReportColumn picks a query result column for a report, with its heading
+type ReportColumn struct {
+	Name  string `json:"name" yaml:"name"`                       // Column name in the query's results
+	Title string `json:"title,omitempty" yaml:"title,omitempty"` // Heading; defaults to Name
+}
+
+// Report formats
+const (
+	ReportFormatCSV  = "csv"
+	ReportFormatJSON = "json"
+)
+
+// Vocabulary is a managed list of terms with definitions. One bound to a
+// categorical field (Field, from VocabularyFields) restricts the values
+// writes may set; unbound ones document terms used in prose fields.
+type Vocabulary struct {
+	Name        string           `json:"name" yaml:"name"`
+	Description *string          `json:"description,omitempty" yaml:"description,omitempty"`
+	Field       *string          `json:"field,omitempty" yaml:"field,omitempty"`
+	Terms       []VocabularyTerm `json:"terms" yaml:"terms"` // In display order
+}
+
+// VocabularyTerm is one permitted value and what it means
+type VocabularyTerm struct {
+	Term       string  `json:"term" yaml:"term"`
+	Definition *string `json:"definition,omitempty" yaml:"definition,omitempty"`
+}
+
+// Categorical fields a vocabulary can be bound to, named as in the data dictionary
+const (
+	VocabularyFieldConservationStatus = "species.conservation_status"
+	VocabularyFieldSourceType         = "source.source_type"
+)
+
+// VocabularyFields lists the fields a vocabulary can be bound to
+var VocabularyFields = []string{VocabularyFieldConservationStatus, VocabularyFieldSourceType}
+
 // Taxon represents a taxonomic rank in the reference table
-// Hierarchy: Genus (Quercus) -> Subgenus -> Section -> Subsection -> Complex -> Species
+// Hierarchy: Genus (e.g. Quercus) -> Subgenus -> Section -> Subsection -> Complex -> Species
 type Taxon struct {
 	Name         string      `json:"name" yaml:"name"`
 	Level        TaxonLevel  `json:"level" yaml:"level"`
+	Genus        string      `json:"genus,omitempty" yaml:"genus,omitempty"`   // Defaults to DefaultGenus
 	Parent       *string     `json:"parent,omitempty" yaml:"parent,omitempty"` // Parent taxon name
 	Author       *string     `json:"author,omitempty" yaml:"author,omitempty"` // Taxonomic authority
 	Notes        *string     `json:"notes,omitempty" yaml:"notes,omitempty"`
-	Links        []TaxonLink `json:"links,omitempty" yaml:"links,omitempty"`   // External reference links
-	SpeciesCount int         `json:"species_count" yaml:"species_count"`       // Count of species in this taxon
+	Links        []TaxonLink `json:"links" yaml:"links,omitempty"`       // External reference links
+	SpeciesCount int         `json:"species_count" yaml:"species_count"` // Count of species in this taxon
 }
 
 // SpeciesSource represents source-attributed descriptive data for a species
@@ -41,7 +242,7 @@ type SpeciesSource struct {
 	ID               int64    `json:"id" yaml:"id"`
 	ScientificName   string   `json:"scientific_name" yaml:"scientific_name"`
 	SourceID         int64    `json:"source_id" yaml:"source_id"`
-	LocalNames       []string `json:"local_names,omitempty" yaml:"local_names,omitempty"`
+	LocalNames       []string `json:"local_names" yaml:"local_names,omitempty"`
 	Range            *string  `json:"range,omitempty" yaml:"range,omitempty"`
 	GrowthHabit      *string  `json:"growth_habit,omitempty" yaml:"growth_habit,omitempty"`
 	Leaves           *string  `json:"leaves,omitempty" yaml:"leaves,omitempty"`
@@ -52,8 +253,184 @@ type SpeciesSource struct {
 	Buds             *string  `json:"buds,omitempty" yaml:"buds,omitempty"`
 	HardinessHabitat *string  `json:"hardiness_habitat,omitempty" yaml:"hardiness_habitat,omitempty"`
 	Miscellaneous    *string  `json:"miscellaneous,omitempty" yaml:"miscellaneous,omitempty"`
-	URL              *string  `json:"url,omitempty" yaml:"url,omitempty"`
-	IsPreferred      bool     `json:"is_preferred" yaml:"is_preferred"`
+	// DistinguishingFeatures is what sets the species apart from similar ones,
+	// confirmed by a curator (see the feature suggestion queue)
+	DistinguishingFeatures *string `json:"distinguishing_features,omitempty" yaml:"distinguishing_features,omitempty"`
+	URL                    *string `json:"url,omitempty" yaml:"url,omitempty"`
+	// Pages locates the description in a printed source, e.g. "pp. 112-114"
+	Pages *string `json:"pages,omitempty" yaml:"pages,omitempty"`
+	// FieldPages locates single text fields, keyed by TextField name, where
+	// they are on other pages than the rest, e.g. {"fruits": "pl. 23"}
+	FieldPages map[string]string `json:"field_pages,omitempty" yaml:"field_pages,omitempty"`
+	// FieldVisibility marks text fields, keyed by TextField name, withheld
+	// from the public, e.g. {"range": "internal"} for the localities of a
+	// threatened species. Fields not listed are public.
+	FieldVisibility map[string]string `json:"field_visibility,omitempty" yaml:"field_visibility,omitempty"`
+	IsPreferred     bool              `json:"is_preferred" yaml:"is_preferred"`
+
+	// Transcription provenance, for text typed or OCR'd from print. Records
+	// with a TranscriptionMethod stay unverified until a curator checks them
+	// against the original.
+	Transcriber         *string    `json:"transcriber,omitempty" yaml:"transcriber,omitempty"`
+	TranscriptionMethod *string    `json:"transcription_method,omitempty" yaml:"transcription_method,omitempty"` // See TranscriptionMethods
+	OCRConfidence       *float64   `json:"ocr_confidence,omitempty" yaml:"ocr_confidence,omitempty"`             // 0-1, as the OCR engine reported it
+	Verified            bool       `json:"verified" yaml:"verified"`
+	VerifiedAt          *time.Time `json:"verified_at,omitempty" yaml:"verified_at,omitempty"`
+	VerifiedBy          *string    `json:"verified_by,omitempty" yaml:"verified_by,omitempty"`
+}
+
+// Transcription methods
+const (
+	TranscriptionManual = "manual" // Typed from the printed source
+	TranscriptionOCR    = "ocr"    // Optical character recognition
+	TranscriptionImport = "import" // Taken from an existing digital text
+)
+
+// TranscriptionMethods lists the valid transcription methods
+var TranscriptionMethods = []string{TranscriptionManual, TranscriptionOCR, TranscriptionImport}
+
+// Locator returns where the named text field is found in the source: its
+// entry in FieldPages, else Pages, else ""
+func (ss *SpeciesSource) Locator(field string) string {
+	if p := ss.FieldPages[field]; p != "" {
+		return p
+	}
+	if ss.Pages != nil {
+		return *ss.Pages
+	}
+	return ""
+}
+
+// Field visibility levels. Public fields are published in reads without an
+// API key and in exports; internal fields are kept for curators only.
+const (
+	FieldPublic   = "public"
+	FieldInternal = "internal"
+)
+
+// FieldVisibilities lists the valid field visibility levels
+var FieldVisibilities = []string{FieldPublic, FieldInternal}
+
+// IsInternal reports whether the named text field is withheld from the public
+func (ss *SpeciesSource) IsInternal(field string) bool {
+	return ss.FieldVisibility[field] == FieldInternal
+}
+
+// InternalFields returns the names of the internal text fields, in display order
+func (ss *SpeciesSource) InternalFields() []string {
+	var fields []string
+	for _, f := range ss.TextFields() {
+		if ss.IsInternal(f.Name) {
+			fields = append(fields, f.Name)
+		}
+	}
+	return fields
+}
+
+// Redact clears the internal text fields and their locators, leaving what
+// the public may see. FieldVisibility is kept, so readers can tell that a
+// field was withheld rather than never recorded.
+func (ss *SpeciesSource) Redact() {
+	for _, field := range ss.InternalFields() {
+		delete(ss.FieldPages, field)
+		switch field {
+		case "range":
+			ss.Range = nil
+		case "growth_habit":
+			ss.GrowthHabit = nil
+		case "leaves":
+			ss.Leaves = nil
+		case "flowers":
+			ss.Flowers = nil
+		case "fruits":
+			ss.Fruits = nil
+		case "bark":
+			ss.Bark = nil
+		case "twigs":
+			ss.Twigs = nil
+		case "buds":
+			ss.Buds = nil
+		case "hardiness_habitat":
+			ss.HardinessHabitat = nil
+		case "miscellaneous":
+			ss.Miscellaneous = nil
+		case "distinguishing_features":
+			ss.DistinguishingFeatures = nil
+		}
+	}
+}
+
+// TextLocation returns where a species source text field is found, as
+// "sources/{source_id}/{field}" like text matches and mentions give it
+func TextLocation(sourceID int64, field string) string {
+	return "sources/" + strconv.FormatInt(sourceID, 10) + "/" + field
+}
+
+// IsTextField reports whether name is one of the species source text fields
+func IsTextField(name string) bool {
+	for _, f := range (&SpeciesSource{}).TextFields() {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Citation formats a reference to a source as "Author (Year). Name, locator",
+// leaving out the parts that are empty
+func Citation(author *string, year *int, name, locator string) string {
+	var b strings.Builder
+	if author != nil && *author != "" {
+		b.WriteString(*author)
+	}
+	if year != nil {
+		if b.Len() > 0 {
+			b.WriteString(" ")
+		}
+		fmt.Fprintf(&b, "(%d)", *year)
+	}
+	if b.Len() > 0 {
+		b.WriteString(". ")
+	}
+	b.WriteString(name)
+	if locator != "" {
+		b.WriteString(", " + locator)
+	}
+	return b.String()
+}
+
+// TextField is one of a species source's free-text descriptive fields
+type TextField struct {
+	Name  string  // JSON field name, e.g. "leaves"
+	Value *string // The field's value; nil when unset
+}
+
+// TextFields returns the species source's free-text descriptive fields in display order
+func (ss *SpeciesSource) TextFields() []TextField {
+	return []TextField{
+		{"range", ss.Range},
+		{"growth_habit", ss.GrowthHabit},
+		{"leaves", ss.Leaves},
+		{"flowers", ss.Flowers},
+		{"fruits", ss.Fruits},
+		{"bark", ss.Bark},
+		{"twigs", ss.Twigs},
+		{"buds", ss.Buds},
+		{"hardiness_habitat", ss.HardinessHabitat},
+		{"miscellaneous", ss.Miscellaneous},
+		{"distinguishing_features", ss.DistinguishingFeatures},
+	}
+}
+
+// Measurement is a length parsed from a species source's text, such as "5-12 cm"
+type Measurement struct {
+	ScientificName string   `json:"scientific_name"`
+	SourceID       int64    `json:"source_id"`
+	Field          string   `json:"field"`         // Text field it was found in, e.g. "leaves"
+	Text           string   `json:"text"`          // As written
+	Min            *float64 `json:"min,omitempty"` // Nil for open-ended values ("up to 30 m")
+	Max            float64  `json:"max"`
+	Unit           string   `json:"unit"` // mm, cm, m, in, or ft
 }
 
 // OakEntry represents an Oak taxonomic entry (species-intrinsic data)
@@ -61,9 +438,13 @@ type SpeciesSource struct {
 type OakEntry struct {
 	ScientificName     string  `json:"scientific_name" yaml:"scientific_name"`
 	Author             *string `json:"author,omitempty" yaml:"author,omitempty"`
+	Pronunciation      *string `json:"pronunciation,omitempty" yaml:"pronunciation,omitempty"` // e.g. "KWER-kus AL-buh"
 	IsHybrid           bool    `json:"is_hybrid" yaml:"is_hybrid"`
 	ConservationStatus *string `json:"conservation_status,omitempty" yaml:"conservation_status,omitempty"`
 
+	// Genus the entry belongs to; defaults to DefaultGenus
+	Genus string `json:"genus,omitempty" yaml:"genus,omitempty"`
+
 	// Taxonomy (flat columns, validated against taxa reference table)
 	Subgenus   *string `json:"subgenus,omitempty" yaml:"subgenus,omitempty"`
 	Section    *string `json:"section,omitempty" yaml:"section,omitempty"`
@@ -75,15 +456,32 @@ type OakEntry struct {
 	Parent2 *string `json:"parent2,omitempty" yaml:"parent2,omitempty"`
 
 	// Related species
-	Hybrids             []string `json:"hybrids,omitempty" yaml:"hybrids,omitempty"`
-	CloselyRelatedTo    []string `json:"closely_related_to,omitempty" yaml:"closely_related_to,omitempty"`
-	SubspeciesVarieties []string `json:"subspecies_varieties,omitempty" yaml:"subspecies_varieties,omitempty"`
-	Synonyms            []string `json:"synonyms,omitempty" yaml:"synonyms,omitempty"`
+	Hybrids             []string `json:"hybrids" yaml:"hybrids,omitempty"`
+	CloselyRelatedTo    []string `json:"closely_related_to" yaml:"closely_related_to,omitempty"`
+	SubspeciesVarieties []string `json:"subspecies_varieties" yaml:"subspecies_varieties,omitempty"`
+	Synonyms            []string `json:"synonyms" yaml:"synonyms,omitempty"`
 
 	// External reference links
-	ExternalLinks []ExternalLink `json:"external_links,omitempty" yaml:"external_links,omitempty"`
+	ExternalLinks []ExternalLink `json:"external_links" yaml:"external_links,omitempty"`
+
+	// Visibility is "draft" or "published"; drafts are hidden from public reads
+	Visibility string `json:"visibility,omitempty" yaml:"visibility,omitempty"`
+
+	// Slug is the name's form in URLs (names.Slug), e.g. "x-bebbiana". Set on
+	// read; never written.
+	Slug string `json:"slug,omitempty" yaml:"-"`
+
+	// DataError is set on read when a stored JSON column is corrupt; the
+	// affected fields are returned empty. Never written.
+	DataError string `json:"data_error,omitempty" yaml:"-"`
 }
 
+// Species visibility values
+const (
+	VisibilityPublished = "published"
+	VisibilityDraft     = "draft"
+)
+
 // NewOakEntry creates a new empty OakEntry with the given scientific name
 func NewOakEntry(scientificName string) *OakEntry {
 	return &OakEntry{
@@ -121,6 +519,58 @@ type Source struct {
 	Notes       *string `json:"notes,omitempty" yaml:"notes,omitempty"`
 	License     *string `json:"license,omitempty" yaml:"license,omitempty"`
 	LicenseURL  *string `json:"license_url,omitempty" yaml:"license_url,omitempty"`
+	// SupersededBy is the ID of the source that replaces this one (e.g. a newer edition)
+	SupersededBy *int64 `json:"superseded_by,omitempty" yaml:"superseded_by,omitempty"`
+}
+
+// Source types, the terms of the bundled source_type vocabulary.
+// NormalizeSourceType maps other spellings onto them.
+const (
+	SourceTypeBook                = "book"
+	SourceTypePaper               = "paper"
+	SourceTypeWebsite             = "website"
+	SourceTypeHerbarium           = "herbarium"
+	SourceTypePersonalObservation = "personal-observation"
+	SourceTypeDatabase            = "database"
+)
+
+// SourceTypes lists the valid source types
+var SourceTypes = []string{
+	SourceTypeBook, SourceTypePaper, SourceTypeWebsite,
+	SourceTypeHerbarium, SourceTypePersonalObservation, SourceTypeDatabase,
+}
+
+// sourceTypeAliases maps older free-text source types onto the canonical ones
+var sourceTypeAliases = map[string]string{
+	"article":     SourceTypePaper,
+	"journal":     SourceTypePaper,
+	"web":         SourceTypeWebsite,
+	"web-site":    SourceTypeWebsite,
+	"observation": SourceTypePersonalObservation,
+	"dataset":     SourceTypeDatabase,
+}
+
+// SourceTypeKey returns s in the form source types are stored in: lowercase,
+// with spaces and underscores as hyphens ("Personal Observation" is
+// personal-observation)
+func SourceTypeKey(s string) string {
+	key := strings.ToLower(strings.TrimSpace(s))
+	return strings.NewReplacer(" ", "-", "_", "-").Replace(key)
+}
+
+// NormalizeSourceType returns the canonical source type for s, compared as
+// SourceTypeKey. ok is false if s matches no source type.
+func NormalizeSourceType(s string) (canonical string, ok bool) {
+	key := SourceTypeKey(s)
+	if alias, found := sourceTypeAliases[key]; found {
+		return alias, true
+	}
+	for _, t := range SourceTypes {
+		if key == t {
+			return t, true
+		}
+	}
+	return s, false
 }
 
 // NewSource creates a new Source with the given type and name
@@ -136,12 +586,25 @@ type SpeciesSourceWithMeta struct {
 	SpeciesSource
 	SourceName string  `json:"source_name"`
 	SourceURL  *string `json:"source_url,omitempty"`
+	Citation   string  `json:"citation"` // See Citation; cites Pages when set
 }
 
 // SpeciesWithSources represents a species with all its source data embedded
 type SpeciesWithSources struct {
 	OakEntry
 	Sources []SpeciesSourceWithMeta `json:"sources"`
+
+	// AuthorDetails expands the abbreviations in Author, in order of appearance.
+	// Abbreviations missing from the authors table are left out.
+	AuthorDetails []Author `json:"author_details"`
+}
+
+// Author is a botanical author and their standard abbreviation, as listed by IPNI
+type Author struct {
+	Abbreviation string  `json:"abbreviation" yaml:"abbreviation"` // Standard form, e.g. "Münchh."
+	FullName     string  `json:"full_name" yaml:"full_name"`
+	Dates        *string `json:"dates,omitempty" yaml:"dates,omitempty"`     // e.g. "1716-1774"
+	IPNIID       *string `json:"ipni_id,omitempty" yaml:"ipni_id,omitempty"` // IPNI author ID
 }
 
 // SearchResultType indicates the type of search result
@@ -153,6 +616,35 @@ const (
 	SearchResultTypeSource  SearchResultType = "source"
 )
 
+// TextMatch is a line of account or source text containing a text search's
+// query. Text is the line, shortened to a window around the first match
+// when long, and Matches locate each occurrence in it.
+type TextMatch struct {
+	ScientificName string     `json:"scientific_name"`
+	Location       string     `json:"location"` // "account" or "sources/{source_id}/{field}"
+	Line           int        `json:"line"`     // 1-based line within the field
+	Text           string     `json:"text"`
+	Matches        []TextSpan `json:"matches"`
+}
+
+// TextSpan is a byte range [Start, End) of a string
+type TextSpan struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// FullTextMatch is a species source text field or an entry's synonyms
+// matching a full-text search, most relevant first. Text is a snippet of the
+// field around the matched terms, and Matches locate them in it.
+type FullTextMatch struct {
+	ScientificName string     `json:"scientific_name"`
+	Location       string     `json:"location"` // "synonyms" or "sources/{source_id}/{field}"
+	Field          string     `json:"field"`
+	Text           string     `json:"text"`
+	Matches        []TextSpan `json:"matches"`
+	Score          float64    `json:"score"` // BM25 relevance; higher is better
+}
+
 // UnifiedSearchResults contains grouped search results from all entity types
 type UnifiedSearchResults struct {
 	Species []OakEntry `json:"species"`
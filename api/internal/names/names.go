@@ -0,0 +1,128 @@
+// Package names checks species names against the form the compendium stores
+// them in: a lowercase specific epithet ("alba"), "× " before a nothospecies
+// ("× bebbiana"), or two epithets joined by " × " for a hybrid formula
+// ("alba × macrocarpa"). The genus is stored separately and never part of the
+// name.
+package names
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Hybrid is the multiplication sign written before and between hybrid names.
+const Hybrid = "×"
+
+const epithet = `[a-z](?:[a-z-]*[a-z])?`
+
+var canonicalPattern = regexp.MustCompile(`^(?:× ` + epithet + `|` + epithet + `(?: × ` + epithet + `)?)$`)
+
+// Canonical returns name with surrounding whitespace trimmed, inner runs of
+// whitespace collapsed to one space, a standalone "x" written as "×", and
+// letters lowercased. The result is not necessarily valid; see Problem.
+func Canonical(name string) string {
+	words := strings.Fields(name)
+	for i, w := range words {
+		if w == "x" || w == "X" {
+			words[i] = Hybrid
+		}
+	}
+	return strings.ToLower(strings.Join(words, " "))
+}
+
+// Problem returns why name is not in canonical form, or "" if it is.
+func Problem(name string) string {
+	switch {
+	case name == "":
+		return "is empty"
+	case name != strings.TrimSpace(name):
+		return "has leading or trailing whitespace"
+	case strings.Join(strings.Fields(name), " ") != name:
+		return "has repeated or non-space whitespace between words"
+	case name != strings.ToLower(name):
+		return "must be lowercase"
+	case name != Canonical(name):
+		return `must write the hybrid sign as "×", not "x"`
+	case !canonicalPattern.MatchString(name):
+		return `must be a specific epithet of lowercase letters and hyphens, optionally after "× " or joined to a second epithet by " × "`
+	}
+	return ""
+}
+
+// Suggest returns the canonical form of a non-conforming name, or "" if
+// canonicalizing does not make it valid.
+func Suggest(name string) string {
+	if c := Canonical(name); c != name && Problem(c) == "" {
+		return c
+	}
+	return ""
+}
+
+// Key returns the form names are compared in for lookups, search, and
+// uniqueness: Canonical, with diacritics removed, so "Alba", " alba", and
+// "albà" all have the key "alba".
+func Key(name string) string {
+	var b strings.Builder
+	for _, r := range Canonical(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks of decomposed letters ("a" + U+0300)
+		case foldedLetters[r] != "":
+			b.WriteString(foldedLetters[r])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// foldedLetters maps precomposed lowercase Latin letters to their base
+// letters. Canonical has lowercased the name already.
+var foldedLetters = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae",
+	'ç': "c", 'ć': "c", 'č': "c",
+	'ď': "d", 'đ': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ķ': "k",
+	'ĺ': "l", 'ľ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'œ': "oe",
+	'ŕ': "r", 'ř': "r",
+	'ś': "s", 'ş': "s", 'š': "s", 'ß': "ss",
+	'ţ': "t", 'ť': "t",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// Slug returns the form of name used in URLs: Key, with the hybrid sign
+// written "x", the dots of infraspecific ranks dropped, and every run of
+// other characters replaced by one hyphen. "× bebbiana" is "x-bebbiana",
+// "alba × macrocarpa" is "alba-x-macrocarpa", and the legacy name
+// "alba var. latiloba" is "alba-var-latiloba". A slug is its own slug.
+func Slug(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range Key(name) {
+		switch {
+		case r == '×':
+			r = 'x'
+		case r == '.':
+			continue
+		case (r < 'a' || r > 'z') && (r < '0' || r > '9'):
+			hyphen = b.Len() > 0
+			continue
+		}
+		if hyphen {
+			b.WriteByte('-')
+			hyphen = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
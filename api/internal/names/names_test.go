@@ -0,0 +1,97 @@
+package names
+
+import "testing"
+
+func TestProblem(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"alba", true},
+		{"novae-angliae", true},
+		{"× bebbiana", true},
+		{"alba × macrocarpa", true},
+		{"", false},
+		{" alba", false},
+		{"alba ", false},
+		{"alba  × macrocarpa", false},
+		{"Alba", false},
+		{"DROP TABLE", false},
+		{"x bebbiana", false},
+		{"alba x macrocarpa", false},
+		{"×bebbiana", false},
+		{"alba × ", false},
+		{"alba2", false},
+		{"-alba", false},
+		{"alba-", false},
+		{"alba; --", false},
+		{"alba var. latiloba", false},
+		{"× alba × macrocarpa", false},
+	}
+	for _, tt := range tests {
+		if got := Problem(tt.name); (got == "") != tt.valid {
+			t.Errorf("Problem(%q) = %q, want valid=%v", tt.name, got, tt.valid)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	tests := map[string]string{
+		"alba":                "",
+		"  Alba ":             "alba",
+		"x bebbiana":          "× bebbiana",
+		"Alba  X  Macrocarpa": "alba × macrocarpa",
+		"alba × macrocarpa":   "alba × macrocarpa",
+		"DROP TABLE":          "",
+		"alba2":               "",
+	}
+	for in, want := range tests {
+		if got := Suggest(in); got != want {
+			t.Errorf("Suggest(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := map[string]string{
+		"alba":              "alba",
+		"Alba":              "alba",
+		"  ALBA ":           "alba",
+		"albà":              "alba",
+		"alba\u0300":        "alba",
+		"X Bebbiana":        "× bebbiana",
+		"Alba x Macrocarpa": "alba × macrocarpa",
+		"Née":               "nee",
+		"Kotschyana Œrsted": "kotschyana oersted",
+		"× bebbiana":        "× bebbiana",
+	}
+	for in, want := range tests {
+		if got := Key(in); got != want {
+			t.Errorf("Key(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"alba":                    "alba",
+		"novae-angliae":           "novae-angliae",
+		"× bebbiana":              "x-bebbiana",
+		"alba × macrocarpa":       "alba-x-macrocarpa",
+		"alba x macrocarpa":       "alba-x-macrocarpa",
+		"alba var. latiloba":      "alba-var-latiloba",
+		"robur subsp. broteroana": "robur-subsp-broteroana",
+		" Álba ":                  "alba",
+		"alba--  -latiloba":       "alba-latiloba",
+		"¿?":                      "",
+	}
+	for name, want := range tests {
+		got := Slug(name)
+		if got != want {
+			t.Errorf("Slug(%q) = %q, want %q", name, got, want)
+		}
+		if again := Slug(got); again != got {
+			t.Errorf("Slug(%q) = %q, not its own slug", got, again)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+// Package notify sends alerts about important server events by email or Slack.
+//
+// Delivery is configured entirely from the environment (see ConfigFromEnv). A
+// Notifier with no channels configured, or a nil *Notifier, accepts events and
+// drops them, so callers never need to check whether notifications are on.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventType identifies a kind of event. Each type can be toggled separately.
+type EventType string
+
+// Event types
+const (
+	EventJobFailed     EventType = "job_failed"
+	EventAPIKeyCreated EventType = "api_key_created"
+)
+
+const (
+	defaultSMTPPort        = "587"
+	defaultDeliveryTimeout = 15 * time.Second
+)
+
+// AllEvents lists every event type in a stable order.
+var AllEvents = []EventType{EventJobFailed, EventAPIKeyCreated}
+
+// Environment variables read by ConfigFromEnv
+const (
+	EnvSlackWebhook = "OAK_NOTIFY_SLACK_WEBHOOK"
+	EnvSMTPHost     = "OAK_NOTIFY_SMTP_HOST"
+	EnvSMTPPort     = "OAK_NOTIFY_SMTP_PORT"
+	EnvSMTPUser     = "OAK_NOTIFY_SMTP_USER"
+	EnvSMTPPassword = "OAK_NOTIFY_SMTP_PASSWORD"
+	EnvEmailFrom    = "OAK_NOTIFY_EMAIL_FROM"
+	EnvEmailTo      = "OAK_NOTIFY_EMAIL_TO"
+	EnvEvents       = "OAK_NOTIFY_EVENTS"
+)
+
+// Event is a single notification.
+type Event struct {
+	Type    EventType
+	Subject string
+	Message string
+	Time    time.Time
+}
+
+// Config selects delivery channels and which events are sent.
+type Config struct {
+	SlackWebhookURL string
+
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	EmailFrom    string
+	EmailTo      []string
+
+	// Events enables individual event types. Nil enables all of them.
+	Events map[EventType]bool
+}
+
+// ConfigFromEnv builds a Config from OAK_NOTIFY_* environment variables.
+// OAK_NOTIFY_EVENTS is a comma-separated list of event types to send
+// ("all" or unset for every type, "none" to send nothing).
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		SlackWebhookURL: strings.TrimSpace(os.Getenv(EnvSlackWebhook)),
+		SMTPHost:        strings.TrimSpace(os.Getenv(EnvSMTPHost)),
+		SMTPPort:        strings.TrimSpace(os.Getenv(EnvSMTPPort)),
+		SMTPUsername:    os.Getenv(EnvSMTPUser),
+		SMTPPassword:    os.Getenv(EnvSMTPPassword),
+		EmailFrom:       strings.TrimSpace(os.Getenv(EnvEmailFrom)),
+		EmailTo:         splitList(os.Getenv(EnvEmailTo)),
+	}
+	if cfg.SMTPPort == "" {
+		cfg.SMTPPort = defaultSMTPPort
+	}
+
+	events, err := ParseEvents(os.Getenv(EnvEvents))
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Events = events
+
+	if cfg.SMTPHost != "" && (cfg.EmailFrom == "" || len(cfg.EmailTo) == 0) {
+		return Config{}, fmt.Errorf("%s requires %s and %s", EnvSMTPHost, EnvEmailFrom, EnvEmailTo)
+	}
+	return cfg, nil
+}
+
+// ParseEvents parses a comma-separated list of event types.
+// An empty string or "all" returns nil (every event enabled).
+func ParseEvents(s string) (map[EventType]bool, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.EqualFold(s, "all") {
+		return nil, nil
+	}
+	events := make(map[EventType]bool)
+	if strings.EqualFold(s, "none") {
+		return events, nil
+	}
+
+	known := make(map[EventType]bool, len(AllEvents))
+	for _, t := range AllEvents {
+		known[t] = true
+	}
+	for _, name := range splitList(s) {
+		t := EventType(strings.ToLower(name))
+		if !known[t] {
+			return nil, fmt.Errorf("unknown notification event %q (valid: %s)", name, eventNames())
+		}
+		events[t] = true
+	}
+	return events, nil
+}
+
+func eventNames() string {
+	names := make([]string, len(AllEvents))
+	for i, t := range AllEvents {
+		names[i] = string(t)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// Sender delivers events over one channel.
+type Sender interface {
+	Name() string
+	Send(ctx context.Context, ev Event) error
+}
+
+// Notifier fans events out to the configured senders.
+type Notifier struct {
+	senders []Sender
+	events  map[EventType]bool
+	logger  *slog.Logger
+	timeout time.Duration
+	wg      sync.WaitGroup
+}
+
+// New creates a Notifier with a sender for each channel configured in cfg.
+func New(cfg Config, logger *slog.Logger) *Notifier {
+	var senders []Sender
+	if cfg.SlackWebhookURL != "" {
+		senders = append(senders, NewSlackSender(cfg.SlackWebhookURL))
+	}
+	if cfg.SMTPHost != "" {
+		senders = append(senders, NewEmailSender(cfg))
+	}
+	return NewWithSenders(senders, cfg.Events, logger)
+}
+
+// NewWithSenders creates a Notifier for the given senders.
+// events enables individual types; nil enables all of them.
+func NewWithSenders(senders []Sender, events map[EventType]bool, logger *slog.Logger) *Notifier {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Notifier{
+		senders: senders,
+		events:  events,
+		logger:  logger,
+		timeout: defaultDeliveryTimeout,
+	}
+}
+
+// Channels returns the names of the configured senders.
+func (n *Notifier) Channels() []string {
+	if n == nil {
+		return nil
+	}
+	names := make([]string, len(n.senders))
+	for i, s := range n.senders {
+		names[i] = s.Name()
+	}
+	return names
+}
+
+// Enabled reports whether events of type t would be delivered anywhere.
+func (n *Notifier) Enabled(t EventType) bool {
+	if n == nil || len(n.senders) == 0 {
+		return false
+	}
+	return n.events == nil || n.events[t]
+}
+
+// Notify delivers an event in the background. Failures are logged, never returned,
+// so alerting cannot break the operation that raised the event.
+func (n *Notifier) Notify(ev Event) {
+	if !n.Enabled(ev.Type) {
+		return
+	}
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+		defer cancel()
+		if err := n.Send(ctx, ev); err != nil {
+			n.logger.Error("failed to send notification", "event", ev.Type, "error", err)
+		}
+	}()
+}
+
+// Send delivers an event to every sender and waits for the result.
+// It returns the first error; the remaining senders are still tried.
+func (n *Notifier) Send(ctx context.Context, ev Event) error {
+	if !n.Enabled(ev.Type) {
+		return nil
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now().UTC()
+	}
+	var firstErr error
+	for _, s := range n.senders {
+		if err := s.Send(ctx, ev); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", s.Name(), err)
+		}
+	}
+	return firstErr
+}
+
+// Wait blocks until notifications started by Notify have been delivered.
+func (n *Notifier) Wait() {
+	if n == nil {
+		return
+	}
+	n.wg.Wait()
+}
+
+// format renders an event as plain text for channels without rich formatting.
+func format(ev Event) string {
+	return fmt.Sprintf("[oak] %s\n\n%s\n\nEvent: %s\nTime:  %s\n",
+		ev.Subject, ev.Message, ev.Type, ev.Time.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,158 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type recordingSender struct {
+	mu     sync.Mutex
+	events []Event
+	err    error
+}
+
+func (s *recordingSender) Name() string { return "recording" }
+
+func (s *recordingSender) Send(ctx context.Context, ev Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, ev)
+	return s.err
+}
+
+func TestParseEvents(t *testing.T) {
+	all, err := ParseEvents("")
+	if err != nil || all != nil {
+		t.Errorf("ParseEvents(\"\") = %v, %v; want nil (all enabled)", all, err)
+	}
+
+	none, err := ParseEvents("none")
+	if err != nil || len(none) != 0 || none == nil {
+		t.Errorf("ParseEvents(none) = %v, %v; want empty set", none, err)
+	}
+
+	some, err := ParseEvents(" JOB_FAILED ")
+	if err != nil {
+		t.Fatalf("ParseEvents failed: %v", err)
+	}
+	if !some[EventJobFailed] || some[EventAPIKeyCreated] {
+		t.Errorf("ParseEvents = %v", some)
+	}
+
+	if _, err := ParseEvents("job_failed,bogus"); err == nil {
+		t.Error("ParseEvents accepted an unknown event type")
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvSMTPHost, "smtp.example.org")
+	t.Setenv(EnvEmailFrom, "oak@example.org")
+	t.Setenv(EnvEmailTo, "a@example.org, b@example.org")
+	t.Setenv(EnvEvents, "api_key_created")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv failed: %v", err)
+	}
+	if cfg.SMTPPort != "587" || len(cfg.EmailTo) != 2 || !cfg.Events[EventAPIKeyCreated] {
+		t.Errorf("config = %+v", cfg)
+	}
+
+	t.Setenv(EnvEmailTo, "")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Error("ConfigFromEnv accepted SMTP host without recipients")
+	}
+}
+
+func TestNotifierToggles(t *testing.T) {
+	rec := &recordingSender{}
+	n := NewWithSenders([]Sender{rec}, map[EventType]bool{EventJobFailed: true}, nil)
+
+	n.Notify(Event{Type: EventJobFailed, Subject: "job"})
+	n.Notify(Event{Type: EventAPIKeyCreated, Subject: "key"})
+	n.Wait()
+
+	if len(rec.events) != 1 || rec.events[0].Type != EventJobFailed {
+		t.Fatalf("delivered = %+v, want only job_failed", rec.events)
+	}
+	if rec.events[0].Time.IsZero() {
+		t.Error("event time was not set")
+	}
+
+	rec.err = errors.New("down")
+	if err := n.Send(context.Background(), Event{Type: EventJobFailed}); err == nil || !strings.Contains(err.Error(), "recording") {
+		t.Errorf("Send error = %v, want sender failure", err)
+	}
+
+	// A nil or unconfigured notifier drops events
+	var nilNotifier *Notifier
+	nilNotifier.Notify(Event{Type: EventJobFailed})
+	nilNotifier.Wait()
+	if New(Config{}, nil).Enabled(EventJobFailed) {
+		t.Error("notifier without channels reports events enabled")
+	}
+}
+
+func TestSlackSender(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	err := NewSlackSender(server.URL).Send(context.Background(), Event{Type: EventJobFailed, Subject: "Job 3 failed", Message: "boom"})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if !strings.Contains(got["text"], "Job 3 failed") || !strings.Contains(got["text"], "boom") {
+		t.Errorf("text = %q", got["text"])
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer failing.Close()
+	if err := NewSlackSender(failing.URL).Send(context.Background(), Event{}); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Send error = %v, want 403", err)
+	}
+}
+
+func TestEmailSender(t *testing.T) {
+	s := NewEmailSender(Config{
+		SMTPHost:  "smtp.example.org",
+		SMTPPort:  "25",
+		EmailFrom: "oak@example.org",
+		EmailTo:   []string{"a@example.org"},
+	})
+	var addr string
+	var msg []byte
+	s.sendMail = func(a string, auth smtp.Auth, from string, to []string, m []byte) error {
+		addr, msg = a, m
+		if auth != nil {
+			t.Error("auth set without a username")
+		}
+		return nil
+	}
+
+	err := s.Send(context.Background(), Event{Type: EventJobFailed, Subject: "Job\r\nBcc: x@evil", Message: "disk full"})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if addr != "smtp.example.org:25" {
+		t.Errorf("addr = %q", addr)
+	}
+	body := string(msg)
+	if !strings.Contains(body, "Subject: [oak] Job  Bcc: x@evil\r\n") || !strings.Contains(body, "disk full") {
+		t.Errorf("message = %q", body)
+	}
+}
@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+)
+
+// SlackSender posts events to a Slack incoming webhook.
+type SlackSender struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlackSender creates a sender for the given incoming webhook URL.
+func NewSlackSender(webhookURL string) *SlackSender {
+	return &SlackSender{webhookURL: webhookURL, client: &http.Client{}}
+}
+
+// Name implements Sender.
+func (s *SlackSender) Name() string { return "slack" }
+
+// Send implements Sender.
+func (s *SlackSender) Send(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s\n_%s_", ev.Subject, ev.Message, ev.Type),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post slack message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("slack webhook returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// EmailSender sends events as plain-text email over SMTP.
+type EmailSender struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+	to       []string
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailSender creates a sender from the SMTP settings in cfg.
+// Authentication is used only when a username is configured.
+func NewEmailSender(cfg Config) *EmailSender {
+	return &EmailSender{
+		addr:     net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort),
+		host:     cfg.SMTPHost,
+		username: cfg.SMTPUsername,
+		password: cfg.SMTPPassword,
+		from:     cfg.EmailFrom,
+		to:       cfg.EmailTo,
+		sendMail: smtp.SendMail,
+	}
+}
+
+// Name implements Sender.
+func (s *EmailSender) Name() string { return "email" }
+
+// Send implements Sender. net/smtp has no context support, so ctx only
+// stops the wait; the SMTP exchange itself finishes in the background.
+func (s *EmailSender) Send(ctx context.Context, ev Event) error {
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.sendMail(s.addr, auth, s.from, s.to, s.message(ev))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to send email: %w", ctx.Err())
+	}
+}
+
+// message builds the RFC 5322 message for an event.
+func (s *EmailSender) message(ev Event) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&b, "Subject: [oak] %s\r\n", headerSafe(ev.Subject))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(format(ev), "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// headerSafe strips line breaks so event text cannot inject headers.
+func headerSafe(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,57 @@
+package rangemap
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ThreatLevels are the IUCN Red List categories from least to most
+// threatened. DD and NE are not on the scale.
+var ThreatLevels = []string{"LC", "NT", "VU", "EN", "CR", "EW", "EX"}
+
+// Generalization coarsens the localities of threatened species before they
+// are published, so the map shows a species' range without leading
+// collectors to a population: coordinates are moved to the center of a
+// Grid-degree cell. The zero value generalizes nothing.
+type Generalization struct {
+	MinStatus string  // Least threatened category generalized, e.g. "VU"
+	Grid      float64 // Cell size in degrees
+}
+
+// NewGeneralization checks the threat level and grid of a generalization.
+// An empty minStatus turns it off.
+func NewGeneralization(minStatus string, grid float64) (Generalization, error) {
+	if minStatus == "" {
+		return Generalization{}, nil
+	}
+	if !slices.Contains(ThreatLevels, minStatus) {
+		return Generalization{}, fmt.Errorf("threat level %q is not one of %v", minStatus, ThreatLevels)
+	}
+	if grid <= 0 || grid > 10 || math.IsNaN(grid) {
+		return Generalization{}, fmt.Errorf("grid must be more than 0 and at most 10 degrees, got %v", grid)
+	}
+	return Generalization{MinStatus: minStatus, Grid: grid}, nil
+}
+
+// Applies reports whether the localities of a species with this conservation
+// status are generalized
+func (g Generalization) Applies(status *string) bool {
+	if g.MinStatus == "" || status == nil {
+		return false
+	}
+	rank := slices.Index(ThreatLevels, *status)
+	return rank >= 0 && rank >= slices.Index(ThreatLevels, g.MinStatus)
+}
+
+// Point moves a location to the center of its grid cell
+func (g Generalization) Point(latitude, longitude float64) (float64, float64) {
+	return g.snap(latitude, 90), g.snap(longitude, 180)
+}
+
+// snap moves a coordinate to the center of its cell, keeping it within ±limit
+func (g Generalization) snap(v, limit float64) float64 {
+	center := (math.Floor(v/g.Grid) + 0.5) * g.Grid
+	center = math.Max(-limit, math.Min(limit, center))
+	return math.Round(center*1e6) / 1e6
+}
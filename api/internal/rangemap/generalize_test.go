@@ -0,0 +1,36 @@
+package rangemap
+
+import "testing"
+
+func TestGeneralization(t *testing.T) {
+	g, err := NewGeneralization("VU", 0.1)
+	if err != nil {
+		t.Fatalf("NewGeneralization() error = %v", err)
+	}
+	status := func(s string) *string { return &s }
+	for s, want := range map[string]bool{"LC": false, "NT": false, "VU": true, "EN": true, "CR": true, "EX": true, "DD": false} {
+		if got := g.Applies(status(s)); got != want {
+			t.Errorf("Applies(%s) = %v, want %v", s, got, want)
+		}
+	}
+	if g.Applies(nil) || (Generalization{}).Applies(status("CR")) {
+		t.Error("generalized a species without a status, or with generalization off")
+	}
+
+	if lat, lon := g.Point(28.8931, -118.2876); lat != 28.85 || lon != -118.25 {
+		t.Errorf("Point() = %v, %v; want 28.85, -118.25", lat, lon)
+	}
+	if lat, _ := (Generalization{MinStatus: "VU", Grid: 1}).Point(89.9, 0); lat != 89.5 {
+		t.Errorf("Point() near the pole = %v, want 89.5", lat)
+	}
+
+	if _, err := NewGeneralization("XX", 0.1); err == nil {
+		t.Error("NewGeneralization accepted an unknown threat level")
+	}
+	if _, err := NewGeneralization("VU", 0); err == nil {
+		t.Error("NewGeneralization accepted a zero grid")
+	}
+	if g, err := NewGeneralization("", 0); err != nil || g.MinStatus != "" {
+		t.Errorf("NewGeneralization(off) = %+v, %v", g, err)
+	}
+}
@@ -0,0 +1,230 @@
+// Package rangemap builds GeoJSON for species range maps from geocoded range
+// localities: a point per locality and, with three or more distinct points, a
+// convex hull outlining the range. Lower detail levels round coordinates,
+// simplify the outline, and finally drop the points, for small-scale maps.
+package rangemap
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Detail is a simplification level
+type Detail string
+
+// Simplification levels, most detailed first
+const (
+	Full   Detail = "full"   // Points and outline as stored
+	Medium Detail = "medium" // Coordinates to ~10 m, outline simplified to ~5 km
+	Low    Detail = "low"    // Outline only, to ~1 km precision and ~25 km simplification
+)
+
+// levels holds each detail level's coordinate precision (decimal places) and
+// outline simplification tolerance (degrees)
+var levels = map[Detail]struct {
+	precision int
+	tolerance float64
+	points    bool
+}{
+	Full:   {precision: 6, tolerance: 0, points: true},
+	Medium: {precision: 4, tolerance: 0.05, points: true},
+	Low:    {precision: 2, tolerance: 0.25, points: false},
+}
+
+// ParseDetail parses a ?detail= value. The empty string is not a level.
+func ParseDetail(s string) (Detail, bool) {
+	d := Detail(strings.ToLower(s))
+	_, ok := levels[d]
+	return d, ok
+}
+
+// Point is a located place and the properties of its GeoJSON feature
+type Point struct {
+	Longitude  float64
+	Latitude   float64
+	Properties map[string]interface{}
+}
+
+// FeatureCollection is a GeoJSON (RFC 7946) feature collection
+type FeatureCollection struct {
+	Type     string     `json:"type"`
+	BBox     []float64  `json:"bbox,omitempty"`
+	Features []*Feature `json:"features"`
+}
+
+// Feature is a GeoJSON feature
+type Feature struct {
+	Type       string                 `json:"type"`
+	Geometry   *Geometry              `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Geometry is a GeoJSON Point or Polygon. Coordinates are [longitude, latitude]
+// for a Point and a list of closed rings for a Polygon.
+type Geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// Build returns the range map for points at a detail level. properties are
+// set on the outline feature, if there is one.
+func Build(points []Point, detail Detail, properties map[string]interface{}) *FeatureCollection {
+	level, ok := levels[detail]
+	if !ok {
+		level = levels[Full]
+	}
+	fc := &FeatureCollection{Type: "FeatureCollection", Features: []*Feature{}}
+	if len(points) == 0 {
+		return fc
+	}
+
+	coords := make([][2]float64, len(points))
+	minLon, minLat, maxLon, maxLat := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for i, p := range points {
+		coords[i] = [2]float64{p.Longitude, p.Latitude}
+		minLon, maxLon = math.Min(minLon, p.Longitude), math.Max(maxLon, p.Longitude)
+		minLat, maxLat = math.Min(minLat, p.Latitude), math.Max(maxLat, p.Latitude)
+	}
+	fc.BBox = []float64{
+		round(minLon, level.precision), round(minLat, level.precision),
+		round(maxLon, level.precision), round(maxLat, level.precision),
+	}
+
+	if ring := simplifyRing(convexHull(coords), level.tolerance); len(ring) >= 4 {
+		rounded := make([][]float64, len(ring))
+		for i, c := range ring {
+			rounded[i] = []float64{round(c[0], level.precision), round(c[1], level.precision)}
+		}
+		props := map[string]interface{}{"kind": "outline"}
+		for k, v := range properties {
+			props[k] = v
+		}
+		fc.Features = append(fc.Features, &Feature{
+			Type:       "Feature",
+			Geometry:   &Geometry{Type: "Polygon", Coordinates: [][][]float64{rounded}},
+			Properties: props,
+		})
+	}
+
+	if level.points {
+		for _, p := range points {
+			props := map[string]interface{}{"kind": "locality"}
+			for k, v := range p.Properties {
+				props[k] = v
+			}
+			fc.Features = append(fc.Features, &Feature{
+				Type: "Feature",
+				Geometry: &Geometry{
+					Type:        "Point",
+					Coordinates: []float64{round(p.Longitude, level.precision), round(p.Latitude, level.precision)},
+				},
+				Properties: props,
+			})
+		}
+	}
+	return fc
+}
+
+// convexHull returns the closed, counterclockwise convex hull of points
+// (Andrew's monotone chain), or nil if they are fewer than three distinct
+// points or all on a line.
+func convexHull(points [][2]float64) [][2]float64 {
+	ps := append([][2]float64(nil), points...)
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i][0] != ps[j][0] {
+			return ps[i][0] < ps[j][0]
+		}
+		return ps[i][1] < ps[j][1]
+	})
+
+	var hull [][2]float64
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, p := range ps {
+			for len(hull) >= start+2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, p)
+		}
+		hull = hull[:len(hull)-1] // Each chain's last point starts the other
+		for i, j := 0, len(ps)-1; i < j; i, j = i+1, j-1 {
+			ps[i], ps[j] = ps[j], ps[i]
+		}
+	}
+	if len(hull) < 3 {
+		return nil
+	}
+	return append(hull, hull[0])
+}
+
+// cross is the z component of (b-a) x (c-a): positive when a, b, c turn left
+func cross(a, b, c [2]float64) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+// simplifyRing drops the vertices of a closed ring that lie within tolerance
+// of the simplified outline (Douglas-Peucker). A ring that would collapse
+// below a triangle is returned unchanged.
+func simplifyRing(ring [][2]float64, tolerance float64) [][2]float64 {
+	if tolerance <= 0 || len(ring) <= 4 {
+		return ring
+	}
+	// Split at the vertex farthest from the first, so neither half is closed
+	far, farDist := 0, -1.0
+	for i, p := range ring {
+		if d := math.Hypot(p[0]-ring[0][0], p[1]-ring[0][1]); d > farDist {
+			far, farDist = i, d
+		}
+	}
+	simplified := append(douglasPeucker(ring[:far+1], tolerance), douglasPeucker(ring[far:], tolerance)[1:]...)
+	if len(simplified) < 4 {
+		return ring
+	}
+	return simplified
+}
+
+// douglasPeucker simplifies an open polyline, keeping its endpoints
+func douglasPeucker(line [][2]float64, tolerance float64) [][2]float64 {
+	keep := make([]bool, len(line))
+	keep[0], keep[len(line)-1] = true, true
+	var mark func(first, last int)
+	mark = func(first, last int) {
+		index, maxDist := 0, tolerance
+		for i := first + 1; i < last; i++ {
+			if d := segmentDistance(line[i], line[first], line[last]); d > maxDist {
+				index, maxDist = i, d
+			}
+		}
+		if index > 0 {
+			keep[index] = true
+			mark(first, index)
+			mark(index, last)
+		}
+	}
+	mark(0, len(line)-1)
+
+	var simplified [][2]float64
+	for i, p := range line {
+		if keep[i] {
+			simplified = append(simplified, p)
+		}
+	}
+	return simplified
+}
+
+// segmentDistance is the distance from p to the segment ab
+func segmentDistance(p, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	if dx == 0 && dy == 0 {
+		return math.Hypot(p[0]-a[0], p[1]-a[1])
+	}
+	t := math.Max(0, math.Min(1, ((p[0]-a[0])*dx+(p[1]-a[1])*dy)/(dx*dx+dy*dy)))
+	return math.Hypot(p[0]-(a[0]+t*dx), p[1]-(a[1]+t*dy))
+}
+
+// round rounds v to places decimal places
+func round(v float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(v*scale) / scale
+}
@@ -0,0 +1,67 @@
+package rangemap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConvexHull(t *testing.T) {
+	points := [][2]float64{{0, 0}, {2, 0}, {1, 1}, {2, 2}, {0, 2}, {1, 0}}
+	want := [][2]float64{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}
+	if got := convexHull(points); !reflect.DeepEqual(got, want) {
+		t.Errorf("convexHull() = %v, want %v", got, want)
+	}
+	if got := convexHull([][2]float64{{0, 0}, {1, 1}, {2, 2}}); got != nil {
+		t.Errorf("convexHull(collinear) = %v, want nil", got)
+	}
+}
+
+func TestSimplifyRing(t *testing.T) {
+	// A square with a slight bulge on its top edge
+	ring := [][2]float64{{0, 0}, {2, 0}, {2, 2}, {1, 2.01}, {0, 2}, {0, 0}}
+	want := [][2]float64{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}
+	if got := simplifyRing(ring, 0.05); !reflect.DeepEqual(got, want) {
+		t.Errorf("simplifyRing() = %v, want %v", got, want)
+	}
+	if got := simplifyRing(ring, 0); len(got) != len(ring) {
+		t.Errorf("simplifyRing(0) dropped vertices: %v", got)
+	}
+	if ring[3] != [2]float64{1, 2.01} {
+		t.Errorf("simplifyRing modified its input: %v", ring)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	points := []Point{
+		{Longitude: -100.123456789, Latitude: 30.5, Properties: map[string]interface{}{"locality": "Edwards Plateau"}},
+		{Longitude: -102, Latitude: 27.3},
+		{Longitude: -98, Latitude: 26},
+	}
+
+	full := Build(points, Full, map[string]interface{}{"scientific_name": "texana"})
+	if len(full.Features) != 4 || full.Features[0].Geometry.Type != "Polygon" || full.Features[0].Properties["scientific_name"] != "texana" {
+		t.Fatalf("full = %+v", full.Features)
+	}
+	if got := full.Features[1].Geometry.Coordinates; !reflect.DeepEqual(got, []float64{-100.123457, 30.5}) {
+		t.Errorf("full point = %v", got)
+	}
+	if full.Features[1].Properties["locality"] != "Edwards Plateau" || full.Features[1].Properties["kind"] != "locality" {
+		t.Errorf("point properties = %v", full.Features[1].Properties)
+	}
+	if !reflect.DeepEqual(full.BBox, []float64{-102, 26, -98, 30.5}) {
+		t.Errorf("bbox = %v", full.BBox)
+	}
+
+	low := Build(points, Low, nil)
+	if len(low.Features) != 1 {
+		t.Errorf("low detail has %d features, want the outline only", len(low.Features))
+	}
+
+	// Fewer than three points have no outline
+	if fc := Build(points[:2], Full, nil); len(fc.Features) != 2 {
+		t.Errorf("two points gave %d features, want 2", len(fc.Features))
+	}
+	if fc := Build(nil, Full, nil); fc.Features == nil || len(fc.Features) != 0 || fc.BBox != nil {
+		t.Errorf("empty = %+v", fc)
+	}
+}
@@ -0,0 +1,184 @@
+// Package units detects length measurements in description text, such as
+// "5–12 cm", "2-4 in. long", or "up to 30 m", and converts them between
+// metric and imperial units.
+package units
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jeff/oaks/api/internal/models"
+)
+
+// System is a unit system for displaying measurements
+type System string
+
+// Supported unit systems
+const (
+	Metric   System = "metric"   // mm, cm, m
+	Imperial System = "imperial" // in, ft
+	Dual     System = "dual"     // As written, followed by the other system in parentheses
+)
+
+// ParseSystem parses a ?units= value. The empty string is not a system.
+func ParseSystem(s string) (System, bool) {
+	switch System(strings.ToLower(s)) {
+	case Metric:
+		return Metric, true
+	case Imperial:
+		return Imperial, true
+	case Dual:
+		return Dual, true
+	}
+	return "", false
+}
+
+// Measurement is a length found in text at text[Start:End]
+type Measurement struct {
+	Start int
+	End   int
+	Min   *float64 // Nil for open-ended values such as "up to 30 m"
+	Max   float64
+	Unit  string // Canonical symbol: mm, cm, m, in, or ft
+
+	prefix string // "up to " or "to " as written
+}
+
+// mmPer is the length of each canonical unit in millimeters
+var mmPer = map[string]float64{"mm": 1, "cm": 10, "m": 1000, "in": 25.4, "ft": 304.8}
+
+// converted is the unit each unit converts to in the other system
+var converted = map[string]string{"mm": "in", "cm": "in", "m": "ft", "in": "cm", "ft": "m"}
+
+var (
+	measurementPattern = regexp.MustCompile(
+		`\b(?:([Uu]p to|to)\s+)?(\d+(?:\.\d+)?)(?:\s*(?:-|–|—|to)\s*(\d+(?:\.\d+)?))?\s*(mm|cm|m|inches|inch|in|feet|foot|ft)\b`)
+
+	// "in" only counts as inches where it can't be the preposition
+	inchesFollower     = regexp.MustCompile(`^(?:\.|[,;:)]|\s*$|\s+(?:long|wide|tall|high|across|thick|diameter|in diameter)\b)`)
+	abbreviationPeriod = regexp.MustCompile(`^\.(?:\s+[a-z(]|[,;:)])`)
+)
+
+// Find returns the measurements in text, in order of appearance
+func Find(text string) []Measurement {
+	var found []Measurement
+	for _, m := range measurementPattern.FindAllStringSubmatchIndex(text, -1) {
+		unit := canonicalUnit(text[m[8]:m[9]])
+		if text[m[8]:m[9]] == "in" && !inchesFollower.MatchString(text[m[1]:]) {
+			continue
+		}
+
+		first, err := strconv.ParseFloat(text[m[4]:m[5]], 64)
+		if err != nil {
+			continue
+		}
+		meas := Measurement{Start: m[0], End: m[1], Max: first, Unit: unit}
+		if unit == "in" && abbreviationPeriod.MatchString(text[m[1]:]) {
+			meas.End++ // "in." mid-sentence is the abbreviation, not a full stop
+		}
+		if m[2] >= 0 {
+			meas.prefix = text[m[2]:m[3]] + " "
+		}
+		if m[6] >= 0 {
+			second, err := strconv.ParseFloat(text[m[6]:m[7]], 64)
+			if err != nil || second < first {
+				continue
+			}
+			meas.Max = second
+		}
+		if meas.prefix == "" {
+			meas.Min = &first
+		}
+		found = append(found, meas)
+	}
+	return found
+}
+
+// Metric reports whether the measurement is in metric units
+func (m Measurement) Metric() bool {
+	return m.Unit == "mm" || m.Unit == "cm" || m.Unit == "m"
+}
+
+// To returns the measurement in another canonical unit
+func (m Measurement) To(unit string) Measurement {
+	factor := mmPer[m.Unit] / mmPer[unit]
+	out := m
+	out.Unit = unit
+	out.Max = m.Max * factor
+	if m.Min != nil {
+		min := *m.Min * factor
+		out.Min = &min
+	}
+	return out
+}
+
+// String formats the measurement, e.g. "5–12 cm" or "up to 98 ft"
+func (m Measurement) String() string {
+	value := formatValue(m.Max)
+	if m.Min != nil && *m.Min != m.Max {
+		value = formatValue(*m.Min) + "–" + value
+	}
+	return m.prefix + value + " " + m.Unit
+}
+
+// Convert rewrites the measurements in text for a unit system. Measurements
+// already in the system are left as written.
+func Convert(text string, system System) string {
+	found := Find(text)
+	if len(found) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range found {
+		b.WriteString(text[last:m.Start])
+		original := text[m.Start:m.End]
+		other := m.To(converted[m.Unit])
+		other.prefix = ""
+
+		switch {
+		case system == Dual:
+			b.WriteString(original + " (" + other.String() + ")")
+		case (system == Metric) == m.Metric():
+			b.WriteString(original)
+		default:
+			other.prefix = m.prefix
+			b.WriteString(other.String())
+		}
+		last = m.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// ConvertSpeciesSource rewrites the measurements in a species source's text
+// fields in place
+func ConvertSpeciesSource(ss *models.SpeciesSource, system System) {
+	for _, f := range ss.TextFields() {
+		if f.Value != nil {
+			*f.Value = Convert(*f.Value, system)
+		}
+	}
+}
+
+// canonicalUnit maps a unit as written to its canonical symbol
+func canonicalUnit(unit string) string {
+	switch unit {
+	case "inches", "inch":
+		return "in"
+	case "feet", "foot":
+		return "ft"
+	}
+	return unit
+}
+
+// formatValue rounds to a whole number from 10 up and to one decimal below
+func formatValue(v float64) string {
+	if v >= 10 {
+		return strconv.FormatFloat(math.Round(v), 'f', -1, 64)
+	}
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
@@ -0,0 +1,63 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	text := "Leaves 5-12 cm long, petioles 3 mm; acorns 1 to 1.5 inches, cups 2 in. across. " +
+		"Trees grow up to 30 m, rarely to 100 ft. Flowers 3 in the spring, 10 months."
+
+	var got []string
+	for _, m := range Find(text) {
+		min := "nil"
+		if m.Min != nil {
+			min = fmt.Sprint(*m.Min)
+		}
+		got = append(got, fmt.Sprintf("%s=%s..%v %s", text[m.Start:m.End], min, m.Max, m.Unit))
+	}
+
+	want := []string{
+		"5-12 cm=5..12 cm",
+		"3 mm=3..3 mm",
+		"1 to 1.5 inches=1..1.5 in",
+		"2 in.=2..2 in",
+		"up to 30 m=nil..30 m",
+		"to 100 ft=nil..100 ft",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Find() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	text := "Leaves 5–12 cm long; trees up to 30 m; acorns 1 in. long."
+	tests := []struct {
+		system System
+		want   string
+	}{
+		{Metric, "Leaves 5–12 cm long; trees up to 30 m; acorns 2.5 cm long."},
+		{Imperial, "Leaves 2–4.7 in long; trees up to 98 ft; acorns 1 in. long."},
+		{Dual, "Leaves 5–12 cm (2–4.7 in) long; trees up to 30 m (98 ft); acorns 1 in. (2.5 cm) long."},
+	}
+	for _, tt := range tests {
+		if got := Convert(text, tt.system); got != tt.want {
+			t.Errorf("Convert(%s) =\n%q\nwant\n%q", tt.system, got, tt.want)
+		}
+	}
+
+	if got := Convert("No measurements here.", Imperial); got != "No measurements here." {
+		t.Errorf("Convert() = %q", got)
+	}
+}
+
+func TestParseSystem(t *testing.T) {
+	if s, ok := ParseSystem("Imperial"); !ok || s != Imperial {
+		t.Errorf("ParseSystem(Imperial) = %q, %v", s, ok)
+	}
+	if _, ok := ParseSystem("furlongs"); ok {
+		t.Error("ParseSystem(furlongs) should fail")
+	}
+}
@@ -5,9 +5,42 @@
 //
 // Environment Variables:
 //
-//	OAK_DB_PATH   - Database path (default: ./oak_compendium.db)
-//	OAK_PORT      - Port to listen on (default: 8080)
-//	OAK_API_KEY   - API key (or reads from ~/.oak/api_key)
+//	OAK_DB_PATH         - Database path (default: ./oak_compendium.db)
+//	OAK_DB_REPLICAS     - Comma-separated read-only copies of the database, kept current by WAL
+//	                      shipping or restored backups, to serve public reads from
+//	OAK_BIND_ADDR       - Address to listen on (default: 0.0.0.0; e.g. 127.0.0.1 for local only)
+//	OAK_PORT            - Port to listen on (default: 8080; 0 picks a free port, printed at startup)
+//	OAK_REUSE_PORT      - Set to true to let several servers share the port (SO_REUSEPORT)
+//	OAK_API_KEY         - API key (takes precedence over the key files)
+//	OAK_API_KEY_FILE    - File holding the API key, e.g. a mounted secret (default: ~/.oak/api_key,
+//	                      generated on first run)
+//	OAK_EXPORT_MAPPINGS - Directory of YAML export mappings, served as /export?mapping=<file name>
+//	OAK_SITE_URL        - Public website linked from /sitemap.xml (default: https://oakcompendium.org)
+//	OAK_ANALYTICS       - Set to true to count species page views per day for /api/v1/stats/popular
+//	OAK_SLOW_QUERY_MS   - Log and count statements slower than this, in milliseconds (default: 200; 0 disables)
+//	OAK_FEATURES        - Feature flags to set, e.g. api_v2=off,species_timeline=on (see /api/v1/features)
+//	OAK_MEDIA_DIR       - Directory of uploaded species photos and thumbnails (default: media, beside the database)
+//
+// Notifications (all optional):
+//
+//	OAK_NOTIFY_SLACK_WEBHOOK - Slack incoming webhook URL
+//	OAK_NOTIFY_SMTP_HOST     - SMTP server for email alerts
+//	OAK_NOTIFY_SMTP_PORT     - SMTP port (default: 587)
+//	OAK_NOTIFY_SMTP_USER     - SMTP username (enables PLAIN auth)
+//	OAK_NOTIFY_SMTP_PASSWORD - SMTP password
+//	OAK_NOTIFY_EMAIL_FROM    - Sender address
+//	OAK_NOTIFY_EMAIL_TO      - Comma-separated recipients
+//	OAK_NOTIFY_EVENTS        - Comma-separated event types to send (default: all)
+//
+// Geocoding of range localities (optional; off unless OAK_GEOCODER is set):
+//
+//	OAK_GEOCODER           - nominatim or gazetteer
+//	OAK_GEOCODER_URL       - Nominatim base URL (default: https://nominatim.openstreetmap.org)
+//	OAK_GEOCODER_GAZETTEER - Gazetteer file of locality<TAB>latitude<TAB>longitude[<TAB>region] lines
+//
+// iNaturalist lookups (POST /api/v1/species/{name}/inaturalist):
+//
+//	OAK_INATURALIST_URL - iNaturalist API base URL (default: https://api.inaturalist.org/v1)
 package main
 
 import (
@@ -16,17 +49,29 @@ import (
 	"flag"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/jeff/oaks/api/internal/db"
+	"github.com/jeff/oaks/api/internal/export"
+	"github.com/jeff/oaks/api/internal/geocode"
 	"github.com/jeff/oaks/api/internal/handlers"
+	"github.com/jeff/oaks/api/internal/inaturalist"
+	"github.com/jeff/oaks/api/internal/notify"
+	"github.com/jeff/oaks/api/internal/rangemap"
+	"github.com/jeff/oaks/pkg/migrate"
 )
 
+// jobWorkers is the number of background job worker goroutines.
+const jobWorkers = 2
+
 // Version information set at build time.
 var (
 	Version   = "dev"
@@ -46,6 +91,19 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Setup structured logger
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+
+	// Configure notifications
+	notifyConfig, err := notify.ConfigFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid notification config: %v\n", err)
+		os.Exit(1)
+	}
+	notifier := notify.New(notifyConfig, logger)
+
 	// Handle generate-key flag
 	if *generateKey {
 		key, err := handlers.GenerateAPIKey()
@@ -61,49 +119,197 @@ func main() {
 
 		fmt.Printf("New API key generated and saved to %s\n", handlers.DefaultAPIKeyPath)
 		fmt.Printf("API Key: %s\n", key)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		if err := notifier.Send(ctx, apiKeyCreatedEvent(handlers.DefaultAPIKeyPath)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to send notification: %v\n", err)
+		}
+		cancel()
 		os.Exit(0)
 	}
 
-	// Setup structured logger
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
-
 	// Get configuration from environment
 	dbPath := getEnv("OAK_DB_PATH", "./oak_compendium.db")
+	bindAddr := getEnv("OAK_BIND_ADDR", "0.0.0.0")
 	port := getEnv("OAK_PORT", "8080")
+	reusePort, err := strconv.ParseBool(getEnv("OAK_REUSE_PORT", "false"))
+	if err != nil {
+		logger.Error("invalid OAK_REUSE_PORT, want true or false", "error", err)
+		os.Exit(1)
+	}
+	mappingDir := os.Getenv("OAK_EXPORT_MAPPINGS")
+	mediaDir := getEnv("OAK_MEDIA_DIR", filepath.Join(filepath.Dir(dbPath), "media"))
+	siteURL := getEnv("OAK_SITE_URL", handlers.DefaultSiteURL)
+	analytics, err := strconv.ParseBool(getEnv("OAK_ANALYTICS", "false"))
+	if err != nil {
+		logger.Error("invalid OAK_ANALYTICS, want true or false", "error", err)
+		os.Exit(1)
+	}
+	slowQueryMs, err := strconv.Atoi(getEnv("OAK_SLOW_QUERY_MS", "200"))
+	if err != nil || slowQueryMs < 0 {
+		logger.Error("invalid OAK_SLOW_QUERY_MS, want milliseconds (0 disables)", "value", os.Getenv("OAK_SLOW_QUERY_MS"))
+		os.Exit(1)
+	}
+
+	features, err := handlers.ParseFeatures(os.Getenv("OAK_FEATURES"))
+	if err != nil {
+		logger.Error("invalid OAK_FEATURES", "error", err)
+		os.Exit(1)
+	}
+
+	var trustedKeys []string
+	for _, id := range strings.Split(os.Getenv("OAK_TRUSTED_KEYS"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			trustedKeys = append(trustedKeys, id)
+		}
+	}
+
+	generalizeStatus := getEnv("OAK_GENERALIZE_STATUS", "VU")
+	if generalizeStatus == "off" {
+		generalizeStatus = ""
+	}
+	generalizeGrid, err := strconv.ParseFloat(getEnv("OAK_GENERALIZE_DEGREES", "0.1"), 64)
+	if err != nil {
+		logger.Error("invalid OAK_GENERALIZE_DEGREES, want degrees", "value", os.Getenv("OAK_GENERALIZE_DEGREES"))
+		os.Exit(1)
+	}
+	generalization, err := rangemap.NewGeneralization(strings.ToUpper(generalizeStatus), generalizeGrid)
+	if err != nil {
+		logger.Error("invalid locality generalization", "error", err)
+		os.Exit(1)
+	}
+
+	geocoder, err := geocode.ProviderFromEnv("oak-api/" + Version)
+	if err != nil {
+		logger.Error("invalid geocoder configuration", "error", err)
+		os.Exit(1)
+	}
 
 	// Load or generate API key
-	apiKey, err := handlers.EnsureAPIKey(handlers.DefaultAPIKeyPath)
+	apiKey, keySource, err := handlers.EnsureAPIKey(handlers.DefaultAPIKeyPath)
 	if err != nil {
-		logger.Error("failed to load API key", "error", err)
+		logger.Error("failed to load API key", "error", err, "source", keySource.Kind, "path", keySource.Path)
 		os.Exit(1)
 	}
+	logger.Info("loaded API key", "source", keySource.Kind, "path", keySource.Path)
+	if keySource.Kind == handlers.APIKeySourceGenerated {
+		notifier.Notify(apiKeyCreatedEvent(handlers.DefaultAPIKeyPath))
+	}
+
+	// Load export mappings for alternative frontends
+	var exportMappings map[string]*export.Mapping
+	if mappingDir != "" {
+		exportMappings, err = export.LoadMappings(mappingDir)
+		if err != nil {
+			logger.Error("failed to load export mappings", "error", err, "path", mappingDir)
+			os.Exit(1)
+		}
+	}
 
 	// Open database connection
+	// New applies pending schema migrations and refuses a schema migrated by
+	// a newer server, which this one could misread
 	database, err := db.New(dbPath)
+	if migrate.IsNewerSchema(err) {
+		logger.Error("database schema is newer than this server", "error", err, "path", dbPath)
+		os.Exit(1)
+	}
 	if err != nil {
 		logger.Error("failed to open database", "error", err, "path", dbPath)
 		os.Exit(1)
 	}
 	defer database.Close()
+	database.SetLogger(logger)
+	database.SetSlowQueryThreshold(time.Duration(slowQueryMs) * time.Millisecond)
+
+	var replicas []string
+	for _, path := range strings.Split(os.Getenv("OAK_DB_REPLICAS"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			replicas = append(replicas, path)
+		}
+	}
+	if err := database.AttachReplicas(replicas); err != nil {
+		logger.Error("failed to open database replica", "error", err)
+		os.Exit(1)
+	}
+	if len(replicas) > 0 {
+		logger.Info("serving public reads from replicas", "replicas", replicas)
+	}
 
 	// Create server instance with version info
 	versionInfo := handlers.VersionInfo{
 		API:       Version,
 		MinClient: "1.0.0", // Minimum compatible CLI version
 	}
-	server := handlers.New(database, apiKey, logger, versionInfo)
+	opts := []handlers.ServerOption{
+		handlers.WithNotifier(notifier),
+		handlers.WithExportMappings(exportMappings),
+		handlers.WithSiteURL(siteURL),
+		handlers.WithFeatures(features),
+		handlers.WithMediaDir(mediaDir),
+	}
+	if analytics {
+		opts = append(opts, handlers.WithAnalytics())
+	}
+	if reusePort {
+		opts = append(opts, handlers.WithReusePort())
+	}
+	if geocoder != nil {
+		opts = append(opts, handlers.WithGeocoder(geocoder))
+	}
+	if inatURL := os.Getenv("OAK_INATURALIST_URL"); inatURL != "" {
+		opts = append(opts, handlers.WithINaturalist(inaturalist.New(inatURL, "oak-api/"+Version)))
+	}
+	if len(trustedKeys) > 0 {
+		opts = append(opts, handlers.WithTrustedKeys(trustedKeys))
+	}
+	if generalization.MinStatus != "" {
+		opts = append(opts, handlers.WithLocalityGeneralization(generalization))
+	}
+	server := handlers.New(database, apiKey, logger, versionInfo, opts...)
 
-	// Build address
-	addr := fmt.Sprintf("0.0.0.0:%s", port)
+	// Listen before printing the banner so a chosen port (OAK_PORT=0) can be reported
+	listener, err := server.Listen(net.JoinHostPort(bindAddr, port))
+	if err != nil {
+		logger.Error("failed to listen", "error", err)
+		os.Exit(1)
+	}
 
 	// Print startup banner
 	fmt.Println("Oak Compendium API server")
 	fmt.Printf("Version:  %s\n", Version)
 	fmt.Printf("Database: %s\n", dbPath)
-	fmt.Printf("API Key:  %s\n", maskAPIKey(apiKey))
-	fmt.Printf("Listening on http://%s\n", addr)
+	if schema, err := database.SchemaStatus(); err == nil {
+		fmt.Printf("Schema:   version %d\n", schema.Version)
+	}
+	fmt.Printf("API Key:  %s (id %s)\n", maskAPIKey(apiKey), handlers.APIKeyID(apiKey))
+	if channels := notifier.Channels(); len(channels) > 0 {
+		fmt.Printf("Notify:   %s\n", strings.Join(channels, ", "))
+	}
+	if len(exportMappings) > 0 {
+		fmt.Printf("Mappings: %s\n", strings.Join(export.MappingNames(exportMappings), ", "))
+	}
+	if analytics {
+		fmt.Println("Analytics: counting species page views")
+	}
+	if generalization.MinStatus != "" {
+		fmt.Printf("Localities: generalized to %g° for %s and more threatened species\n", generalization.Grid, generalization.MinStatus)
+	}
+	fmt.Printf("Listening on http://%s\n", server.Addr())
+
+	// Start background job workers
+	if err := server.Jobs().Start(jobWorkers); err != nil {
+		logger.Error("failed to start job workers", "error", err)
+		os.Exit(1)
+	}
+
+	// Publish scheduled draft species in the background
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	go server.RunPublishScheduler(schedulerCtx, handlers.PublishSchedulerInterval)
+
+	// Build the full export now and again after writes, so requests for it are served from memory
+	go server.RunExportCache(schedulerCtx, handlers.ExportCacheDelay)
 
 	// Setup signal handlers for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -112,7 +318,7 @@ func main() {
 	// Start server in goroutine
 	errChan := make(chan error, 1)
 	go func() {
-		if err := server.Start(addr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errChan <- err
 		}
 	}()
@@ -131,12 +337,19 @@ func main() {
 	defer cancel()
 
 	fmt.Println("\nShutting down gracefully...")
+	stopScheduler()
 
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		logger.Error("shutdown error", "error", err)
 		os.Exit(1)
 	}
 
+	// Let running jobs finish; interrupted ones are requeued on next start
+	if err := server.Jobs().Stop(shutdownCtx); err != nil {
+		logger.Error("job shutdown error", "error", err)
+	}
+	notifier.Wait()
+
 	fmt.Println("Server stopped")
 }
 
@@ -148,6 +361,16 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// apiKeyCreatedEvent describes a newly generated API key without revealing it.
+func apiKeyCreatedEvent(path string) notify.Event {
+	host, _ := os.Hostname()
+	return notify.Event{
+		Type:    notify.EventAPIKeyCreated,
+		Subject: "New API key created",
+		Message: fmt.Sprintf("A new API key was generated on %s and saved to %s.", host, path),
+	}
+}
+
 // maskAPIKey returns a masked version of the API key for display.
 func maskAPIKey(key string) string {
 	if key == "" {
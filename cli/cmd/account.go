@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/editor"
+	"github.com/jeff/oaks/cli/internal/models"
+	"github.com/jeff/oaks/cli/internal/names"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var accountCmd = &cobra.Command{
+	Use:   "account <species>",
+	Short: "Write the long-form account for a species",
+	Long: `Open the long-form markdown account for a species in $EDITOR.
+
+An account is a free-form document stored separately from the species entry
+and rendered to HTML for the website. Link other species with [[rubra]] or
+[[rubra|northern red oak]], and reference images with ![alt](path).
+Links to species that don't exist are saved but reported as warnings.
+
+Examples:
+  oak account rubra
+  oak account show rubra --html
+  oak account delete rubra --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAccountEdit,
+}
+
+var accountShowHTML bool
+
+var accountShowCmd = &cobra.Command{
+	Use:   "show <species>",
+	Short: "Print a species' account",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		name := names.NormalizeHybridName(args[0])
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		account, err := apiClient.GetSpeciesAccount(ctx, name)
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("no account for species '%s'", name)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		if accountShowHTML {
+			fmt.Print(account.HTML)
+		} else {
+			fmt.Print(account.Markdown)
+			if !strings.HasSuffix(account.Markdown, "\n") {
+				fmt.Println()
+			}
+		}
+		printMissingLinks(account)
+		return nil
+	},
+}
+
+var accountDeleteCmd = &cobra.Command{
+	Use:   "delete <species>",
+	Short: "Delete a species' account",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		name := names.NormalizeHybridName(args[0])
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if isActualRemote() && !confirmRemoteOperation("Delete account", name) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		if err := apiClient.DeleteSpeciesAccount(ctx, name); err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("no account for species '%s'", name)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Deleted account for %s\n", name)
+		return nil
+	},
+}
+
+func runAccountEdit(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	name := names.NormalizeHybridName(args[0])
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	entry, err := apiClient.GetSpecies(ctx, name)
+	if err != nil {
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("species '%s' not found. Create it first with: oak new %s", name, name)
+		}
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	var original string
+	account, err := apiClient.GetSpeciesAccount(ctx, name)
+	switch {
+	case err == nil:
+		original = account.Markdown
+	case !oakclient.IsNotFoundError(err):
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	genus := entry.Genus
+	if genus == "" {
+		genus = models.DefaultGenus
+	}
+	edited, err := editor.EditAccount(genus+" "+name, original)
+	if err != nil {
+		return err
+	}
+	if edited == original {
+		fmt.Println("No changes")
+		return nil
+	}
+	if strings.TrimSpace(edited) == "" {
+		fmt.Printf("Account is empty; not saved. Remove it with: oak account delete %s\n", name)
+		return nil
+	}
+
+	if isActualRemote() && !confirmRemoteOperation("Save account", name) {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	saved, err := apiClient.SaveSpeciesAccount(ctx, name, edited)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	fmt.Printf("Saved account for %s (%d cross-links, %d images)\n", name, len(saved.Links), len(saved.Images))
+	printMissingLinks(saved)
+	return nil
+}
+
+// printMissingLinks warns about [[links]] to species that don't exist
+func printMissingLinks(account *oakclient.SpeciesAccount) {
+	for _, link := range account.MissingLinks {
+		fmt.Fprintf(os.Stderr, "Warning: [[%s]] links to a species that does not exist\n", link)
+	}
+}
+
+func init() {
+	accountShowCmd.Flags().BoolVar(&accountShowHTML, "html", false, "Print the rendered HTML instead of markdown")
+
+	accountCmd.AddCommand(accountShowCmd)
+	accountCmd.AddCommand(accountDeleteCmd)
+	rootCmd.AddCommand(accountCmd)
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var authorsCmd = &cobra.Command{
+	Use:   "authors",
+	Short: "Look up standard author abbreviations",
+	Long: `Commands for the author abbreviation table. Species authorities such as
+"(Münchh.) Sarg." are expanded to the authors' full names and dates in the
+detailed species view, using the standard abbreviations listed by IPNI.
+
+The API ships with a small bundled table covering the authors of the names in
+the compendium. Use 'oak authors refresh' to load a larger IPNI export.`,
+}
+
+var authorsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List known author abbreviations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		authors, err := apiClient.ListAuthors(ctx)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ABBREVIATION\tNAME\tDATES")
+		fmt.Fprintln(w, "------------\t----\t-----")
+		for _, a := range authors {
+			dates := ""
+			if a.Dates != nil {
+				dates = *a.Dates
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", a.Abbreviation, a.FullName, dates)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var authorsShowCmd = &cobra.Command{
+	Use:   "show <abbreviation>",
+	Short: "Show the author for an abbreviation",
+	Long: `Show the full name and dates for a standard author abbreviation.
+Spaces are ignored, so "A. Camus" finds "A.Camus".
+
+Examples:
+  oak authors show Münchh.
+  oak authors show "A. Camus"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		author, err := apiClient.GetAuthor(ctx, args[0])
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("author not found: %s", args[0])
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Abbreviation: %s\n", author.Abbreviation)
+		fmt.Printf("Name:         %s\n", author.FullName)
+		if author.Dates != nil {
+			fmt.Printf("Dates:        %s\n", *author.Dates)
+		}
+		if author.IPNIID != nil {
+			fmt.Printf("IPNI ID:      %s\n", *author.IPNIID)
+		}
+		return nil
+	},
+}
+
+var authorsRefreshCmd = &cobra.Command{
+	Use:   "refresh <file>",
+	Short: "Load author abbreviations from an IPNI export",
+	Long: `Create or replace authors from a delimited author export downloaded from
+IPNI (https://www.ipni.org). Tab, comma, pipe, and percent-delimited files are
+accepted; the first line must name the columns. Recognized columns are:
+
+  standard_form (or abbreviation)          the author abbreviation
+  default_author_name (or full_name)       the full name
+  default_author_forename, ..._surname     used when there is no full name
+  dates                                    e.g. 1716-1774
+  id (or ipni_id)                          the IPNI author ID
+
+Rows without a standard form are skipped. Authors not in the file are kept.
+Use "-" to read from stdin.
+
+Examples:
+  oak authors refresh ipni-authors.tsv
+  oak authors refresh ipni-authors.csv --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		var r io.Reader = os.Stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open author export: %w", err)
+			}
+			defer f.Close()
+			r = f
+		}
+
+		authors, skipped, err := parseAuthorExport(r)
+		if err != nil {
+			return err
+		}
+		if len(authors) == 0 {
+			return usageErrorf("no authors with a standard form found in %s", args[0])
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if isActualRemote() && !confirmRemoteOperation("Refresh authors", fmt.Sprintf("%d authors", len(authors))) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		result, err := apiClient.UpsertAuthors(ctx, authors)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Authors: %d created, %d updated", result.Created, result.Updated)
+		if skipped > 0 {
+			fmt.Printf(", %d rows skipped", skipped)
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+// parseAuthorExport reads a delimited IPNI author export. It returns the
+// authors found and the number of rows skipped for lacking a standard form or name.
+// Repeated abbreviations keep the last row.
+func parseAuthorExport(r io.Reader) ([]*oakclient.Author, int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read author export: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read author export header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		name = strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+		columns[name] = i
+	}
+	column := func(record []string, names ...string) string {
+		for _, name := range names {
+			if i, ok := columns[name]; ok && i < len(record) {
+				if v := strings.TrimSpace(record[i]); v != "" {
+					return v
+				}
+			}
+		}
+		return ""
+	}
+	if column(header, "standard_form", "standardform", "abbreviation") == "" {
+		return nil, 0, usageErrorf("author export has no standard_form or abbreviation column")
+	}
+
+	var authors []*oakclient.Author
+	index := map[string]int{}
+	skipped := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read author export: %w", err)
+		}
+
+		a := &oakclient.Author{
+			Abbreviation: column(record, "standard_form", "standardform", "abbreviation"),
+			FullName:     column(record, "default_author_name", "full_name", "name"),
+		}
+		if a.FullName == "" {
+			a.FullName = strings.TrimSpace(column(record, "default_author_forename", "forename") + " " +
+				column(record, "default_author_surname", "surname"))
+		}
+		if a.Abbreviation == "" || a.FullName == "" {
+			skipped++
+			continue
+		}
+		if dates := column(record, "dates"); dates != "" {
+			a.Dates = &dates
+		}
+		if id := column(record, "ipni_id", "id"); id != "" {
+			id = strings.TrimPrefix(id, "urn:lsid:ipni.org:authors:")
+			a.IPNIID = &id
+		}
+
+		if i, ok := index[a.Abbreviation]; ok {
+			authors[i] = a
+			continue
+		}
+		index[a.Abbreviation] = len(authors)
+		authors = append(authors, a)
+	}
+	return authors, skipped, nil
+}
+
+// detectDelimiter picks the most common delimiter on the first non-comment line
+func detectDelimiter(data []byte) rune {
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		best, bestCount := ',', 0
+		for _, d := range []rune{'\t', '%', '|', ','} {
+			if n := strings.Count(line, string(d)); n > bestCount {
+				best, bestCount = d, n
+			}
+		}
+		return best
+	}
+	return ','
+}
+
+func init() {
+	authorsCmd.AddCommand(authorsListCmd)
+	authorsCmd.AddCommand(authorsShowCmd)
+	authorsCmd.AddCommand(authorsRefreshCmd)
+	rootCmd.AddCommand(authorsCmd)
+}
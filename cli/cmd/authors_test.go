@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAuthorExport(t *testing.T) {
+	export := "id%standard_form%default_author_forename%default_author_surname%dates\n" +
+		"urn:lsid:ipni.org:authors:6754-1%Münchh.%Otto von%Münchhausen%1716-1774\n" +
+		"99999-1%%Anon%Ymous%\n" +
+		"8815-1%Sarg.%Charles Sprague%Sargent%1841-1927\n"
+
+	authors, skipped, err := parseAuthorExport(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("parseAuthorExport() error = %v", err)
+	}
+	if skipped != 1 || len(authors) != 2 {
+		t.Fatalf("got %d authors, %d skipped; want 2, 1", len(authors), skipped)
+	}
+	a := authors[0]
+	if a.Abbreviation != "Münchh." || a.FullName != "Otto von Münchhausen" ||
+		a.Dates == nil || *a.Dates != "1716-1774" || a.IPNIID == nil || *a.IPNIID != "6754-1" {
+		t.Errorf("author = %+v", a)
+	}
+}
+
+func TestParseAuthorExportTSV(t *testing.T) {
+	export := "# Bundled authors\nabbreviation\tfull_name\tdates\tipni_id\nL.\tCarl Linnaeus\t1707-1778\t12653-1\nL.\tC. Linnaeus\t\t\n"
+
+	authors, _, err := parseAuthorExport(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("parseAuthorExport() error = %v", err)
+	}
+	// Repeated abbreviations keep the last row
+	if len(authors) != 1 || authors[0].FullName != "C. Linnaeus" || authors[0].Dates != nil {
+		t.Errorf("authors = %+v", authors)
+	}
+
+	if _, _, err := parseAuthorExport(strings.NewReader("name,dates\nCarl Linnaeus,1707-1778\n")); err == nil {
+		t.Error("expected an error for an export without a standard_form column")
+	}
+}
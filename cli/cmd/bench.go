@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/bench"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var (
+	benchConcurrency int
+	benchDuration    time.Duration
+	benchSeed        int64
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Load-test read endpoints of an API profile",
+	Long: `Exercise the read endpoints with a realistic request mix and report
+throughput, latency percentiles, errors, and rate-limit (429) hits.
+
+Requests are not retried, so rate limiting is reported rather than hidden.
+Run without a profile (or with --local) to benchmark the embedded server,
+e.g. to compare local performance before and after an indexing change.
+
+Examples:
+  oak bench --local -d /tmp/load.db                     # Embedded server
+  oak bench --profile prod --concurrency 20 --duration 60s`,
+	Args: cobra.NoArgs,
+	RunE: runBench,
+}
+
+func init() {
+	benchCmd.Flags().IntVarP(&benchConcurrency, "concurrency", "c", 4, "Number of concurrent workers")
+	benchCmd.Flags().DurationVar(&benchDuration, "duration", 10*time.Second, "How long to generate load")
+	benchCmd.Flags().Int64Var(&benchSeed, "seed", 1, "Random seed for the request mix")
+	rootCmd.AddCommand(benchCmd)
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	if benchConcurrency < 1 {
+		return usageErrorf("--concurrency must be at least 1")
+	}
+	if benchDuration <= 0 {
+		return usageErrorf("--duration must be positive")
+	}
+
+	apiClient, err := getAPIClient(oakclient.WithMaxRetries(0))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Benchmarking [%s] with %d workers for %s...\n", apiClient.ProfileName(), benchConcurrency, benchDuration)
+
+	result, err := bench.Run(commandContext(), apiClient, bench.DefaultMix, bench.Options{
+		Concurrency: benchConcurrency,
+		Duration:    benchDuration,
+		Seed:        benchSeed,
+	})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\nENDPOINT\tREQS\tERRORS\t429s\tP50\tP90\tP99\tMAX")
+	for _, ep := range append(result.Endpoints, result.Total) {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\t%s\t%s\n",
+			ep.Name, ep.Requests, ep.Errors, ep.RateLimited,
+			formatLatency(ep.P50), formatLatency(ep.P90), formatLatency(ep.P99), formatLatency(ep.Max))
+	}
+	w.Flush()
+
+	fmt.Printf("\n%.1f req/s over %s\n", result.RequestsPerSecond(), result.Elapsed.Round(time.Millisecond))
+	if result.Total.RateLimited > 0 {
+		fmt.Printf("Rate limited %d times; lower --concurrency to measure unthrottled latency\n", result.Total.RateLimited)
+	}
+	return nil
+}
+
+func formatLatency(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return d.Round(10 * time.Microsecond).String()
+}
@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var checklistCmd = &cobra.Command{
+	Use:   "checklist",
+	Short: "Generate a printable field checklist",
+	Long: `Generate a field checklist for collecting trips: each species' name,
+common name, and a checkbox, grouped by section.
+
+--region keeps species whose range mentions a place, such as "Texas" or
+"Mexico". US state and Canadian province codes like TX are expanded to the
+name. --section keeps one section. Draft species are never included.
+
+Formats are markdown and html; the HTML page is laid out for printing, so
+use the browser's print dialog to save a PDF. If no output file is
+specified, writes to stdout.
+
+Examples:
+  oak checklist --region TX
+  oak checklist --region TX --section Lobatae --format html -o texas.html
+  oak checklist --remote --region "Baja California"`,
+	Args: cobra.NoArgs,
+	RunE: runChecklist,
+}
+
+var (
+	checklistRegion  string
+	checklistSection string
+	checklistFormat  string
+	checklistOutput  string
+)
+
+func init() {
+	rootCmd.AddCommand(checklistCmd)
+	checklistCmd.Flags().StringVar(&checklistRegion, "region", "", "Only species whose range mentions this place (US state codes like TX are expanded)")
+	checklistCmd.Flags().StringVar(&checklistSection, "section", "", "Only species in this section")
+	checklistCmd.Flags().StringVar(&checklistFormat, "format", "md", "Output format: md or html")
+	checklistCmd.Flags().StringVarP(&checklistOutput, "output", "o", "", "Output file path")
+}
+
+// checklistSpecies is the part of an export species the checklist uses
+type checklistSpecies struct {
+	Name     string `json:"name"`
+	Taxonomy struct {
+		Genus   string  `json:"genus"`
+		Section *string `json:"section"`
+	} `json:"taxonomy"`
+	Sources []struct {
+		IsPreferred bool     `json:"is_preferred"`
+		LocalNames  []string `json:"local_names"`
+		Range       *string  `json:"range"`
+	} `json:"sources"`
+}
+
+// checklistRow is one line of the checklist
+type checklistRow struct {
+	Section    string // Empty for species without a section
+	Name       string // Binomial, e.g. "Quercus alba"
+	CommonName string
+}
+
+func runChecklist(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	switch checklistFormat {
+	case "md", "markdown", "html":
+	case "pdf":
+		return usageErrorf("--format pdf is not supported: use --format html and print the page to PDF")
+	default:
+		return usageErrorf("invalid --format %q: must be md or html", checklistFormat)
+	}
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+	data, err := apiClient.Export(ctx, oakclient.ExportOptions{})
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	var export struct {
+		Species []checklistSpecies `json:"species"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return fmt.Errorf("failed to parse export: %w", err)
+	}
+	rows := buildChecklist(export.Species, checklistRegion, checklistSection)
+
+	title := checklistTitle(checklistRegion, checklistSection)
+	out := renderChecklistMarkdown(title, rows)
+	if checklistFormat == "html" {
+		out = renderChecklistHTML(title, rows)
+	}
+
+	var w io.Writer = os.Stdout
+	if checklistOutput != "" {
+		file, err := os.Create(checklistOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+	if _, err := io.WriteString(w, out); err != nil {
+		return err
+	}
+	if checklistOutput != "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %d species to %s\n", len(rows), checklistOutput)
+	}
+	return nil
+}
+
+// buildChecklist filters species by region and section and orders them by
+// section, then name. Species without a section sort last.
+func buildChecklist(species []checklistSpecies, region, section string) []checklistRow {
+	var regionPattern *regexp.Regexp
+	if region != "" {
+		place := region
+		if name, ok := regionCodes[strings.ToUpper(region)]; ok {
+			place = name
+		}
+		regionPattern = regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(place) + `\b`)
+	}
+
+	var rows []checklistRow
+	for _, sp := range species {
+		sec := ""
+		if sp.Taxonomy.Section != nil {
+			sec = *sp.Taxonomy.Section
+		}
+		if section != "" && !strings.EqualFold(sec, section) {
+			continue
+		}
+
+		inRegion := regionPattern == nil
+		common := ""
+		for _, src := range sp.Sources {
+			if regionPattern != nil && src.Range != nil && regionPattern.MatchString(*src.Range) {
+				inRegion = true
+			}
+			if len(src.LocalNames) > 0 && (common == "" || src.IsPreferred) {
+				common = src.LocalNames[0]
+			}
+		}
+		if !inRegion {
+			continue
+		}
+
+		genus := sp.Taxonomy.Genus
+		if genus == "" {
+			genus = "Quercus"
+		}
+		rows = append(rows, checklistRow{Section: sec, Name: genus + " " + sp.Name, CommonName: common})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.Section != b.Section {
+			if a.Section == "" || b.Section == "" {
+				return b.Section == ""
+			}
+			return a.Section < b.Section
+		}
+		return a.Name < b.Name
+	})
+	return rows
+}
+
+// checklistTitle describes the checklist's filters
+func checklistTitle(region, section string) string {
+	title := "Oak Checklist"
+	if name, ok := regionCodes[strings.ToUpper(region)]; ok {
+		region = name
+	}
+	switch {
+	case region != "" && section != "":
+		title += fmt.Sprintf(": %s, section %s", region, section)
+	case region != "":
+		title += ": " + region
+	case section != "":
+		title += ": section " + section
+	}
+	return title
+}
+
+// checklistSectionHeading is the heading for a group of rows
+func checklistSectionHeading(section string) string {
+	if section == "" {
+		return "Unplaced"
+	}
+	return "Section " + section
+}
+
+func renderChecklistMarkdown(title string, rows []checklistRow) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n%d species\n", title, len(rows))
+	for i, row := range rows {
+		if i == 0 || row.Section != rows[i-1].Section {
+			fmt.Fprintf(&b, "\n## %s\n\n", checklistSectionHeading(row.Section))
+		}
+		fmt.Fprintf(&b, "- [ ] *%s*", row.Name)
+		if row.CommonName != "" {
+			b.WriteString(" — " + row.CommonName)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func renderChecklistHTML(title string, rows []checklistRow) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: Georgia, serif; font-size: 11pt; margin: 1.5cm; }
+h2 { font-size: 12pt; margin: 1em 0 0.3em; break-after: avoid; }
+ul { list-style: none; padding: 0; columns: 2; }
+li { padding: 0.15em 0; break-inside: avoid; }
+li::before { content: "☐ "; }
+.common { color: #555; }
+@page { margin: 1.5cm; }
+</style>
+</head>
+<body>
+<h1>%s</h1>
+<p>%d species</p>
+`, html.EscapeString(title), html.EscapeString(title), len(rows))
+	for i, row := range rows {
+		if i == 0 || row.Section != rows[i-1].Section {
+			if i > 0 {
+				b.WriteString("</ul>\n")
+			}
+			fmt.Fprintf(&b, "<h2>%s</h2>\n<ul>\n", html.EscapeString(checklistSectionHeading(row.Section)))
+		}
+		fmt.Fprintf(&b, "<li><i>%s</i>", html.EscapeString(row.Name))
+		if row.CommonName != "" {
+			fmt.Fprintf(&b, ` <span class="common">%s</span>`, html.EscapeString(row.CommonName))
+		}
+		b.WriteString("</li>\n")
+	}
+	if len(rows) > 0 {
+		b.WriteString("</ul>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// regionCodes expands US state and Canadian province codes for --region
+var regionCodes = map[string]string{
+	"AL": "Alabama", "AK": "Alaska", "AZ": "Arizona", "AR": "Arkansas", "CA": "California",
+	"CO": "Colorado", "CT": "Connecticut", "DE": "Delaware", "DC": "District of Columbia",
+	"FL": "Florida", "GA": "Georgia", "HI": "Hawaii", "ID": "Idaho", "IL": "Illinois",
+	"IN": "Indiana", "IA": "Iowa", "KS": "Kansas", "KY": "Kentucky", "LA": "Louisiana",
+	"ME": "Maine", "MD": "Maryland", "MA": "Massachusetts", "MI": "Michigan", "MN": "Minnesota",
+	"MS": "Mississippi", "MO": "Missouri", "MT": "Montana", "NE": "Nebraska", "NV": "Nevada",
+	"NH": "New Hampshire", "NJ": "New Jersey", "NM": "New Mexico", "NY": "New York",
+	"NC": "North Carolina", "ND": "North Dakota", "OH": "Ohio", "OK": "Oklahoma", "OR": "Oregon",
+	"PA": "Pennsylvania", "RI": "Rhode Island", "SC": "South Carolina", "SD": "South Dakota",
+	"TN": "Tennessee", "TX": "Texas", "UT": "Utah", "VT": "Vermont", "VA": "Virginia",
+	"WA": "Washington", "WV": "West Virginia", "WI": "Wisconsin", "WY": "Wyoming",
+	"AB": "Alberta", "BC": "British Columbia", "MB": "Manitoba", "NB": "New Brunswick",
+	"NS": "Nova Scotia", "ON": "Ontario", "QC": "Quebec", "SK": "Saskatchewan",
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBuildChecklist(t *testing.T) {
+	data := `[
+		{"name": "stellata", "taxonomy": {"genus": "Quercus", "section": "Quercus"},
+		 "sources": [{"local_names": ["post oak"], "range": "Texas to Florida"}]},
+		{"name": "buckleyi", "taxonomy": {"genus": "Quercus", "section": "Lobatae"},
+		 "sources": [{"local_names": ["Spanish oak"], "range": "Oklahoma"},
+		             {"is_preferred": true, "local_names": ["Texas red oak"], "range": "central Texas"}]},
+		{"name": "× bebbiana", "taxonomy": {"genus": "Quercus"},
+		 "sources": [{"range": "Texas"}]},
+		{"name": "robur", "taxonomy": {"genus": "Quercus", "section": "Quercus"},
+		 "sources": [{"local_names": ["English oak"], "range": "Europe"}]}
+	]`
+	var species []checklistSpecies
+	if err := json.Unmarshal([]byte(data), &species); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := buildChecklist(species, "tx", "")
+	var got []string
+	for _, r := range rows {
+		got = append(got, r.Section+"|"+r.Name+"|"+r.CommonName)
+	}
+	want := "Lobatae|Quercus buckleyi|Texas red oak,Quercus|Quercus stellata|post oak,|Quercus × bebbiana|"
+	if strings.Join(got, ",") != want {
+		t.Errorf("rows = %v\nwant %s", got, want)
+	}
+
+	if rows := buildChecklist(species, "", "quercus"); len(rows) != 2 {
+		t.Errorf("section filter returned %d rows, want 2", len(rows))
+	}
+
+	md := renderChecklistMarkdown(checklistTitle("TX", ""), rows)
+	for _, want := range []string{"# Oak Checklist: Texas\n", "## Section Lobatae", "- [ ] *Quercus buckleyi* — Texas red oak", "## Unplaced"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if out := renderChecklistHTML("A & B", rows); !strings.Contains(out, "<title>A &amp; B</title>") || strings.Count(out, "<ul>") != 3 {
+		t.Errorf("html = %s", out)
+	}
+}
@@ -0,0 +1,371 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/embedded"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var compareBackendsCmd = &cobra.Command{
+	Use:   "compare-backends",
+	Short: "Report drift between the local database and a remote profile",
+	Long: `Compare the local database (-d) with a remote profile: counts of genera,
+taxa, sources, and species, a hash of every entity, and field-level
+differences for entities that exist on both sides but do not match.
+
+Species are compared as exported, with their source data, so drafts on
+either side are left out. Exits with code 1 when the backends differ.
+
+Examples:
+  oak compare-backends --profile prod
+  oak compare-backends --profile prod -d staging.db --max-diffs 50`,
+	Args: cobra.NoArgs,
+	RunE: runCompareBackends,
+}
+
+var compareMaxDiffs int
+
+func init() {
+	rootCmd.AddCommand(compareBackendsCmd)
+	compareBackendsCmd.Flags().IntVar(&compareMaxDiffs, "max-diffs", 10, "Entities to list per kind and drift type (0 for all)")
+}
+
+// compareKinds are the entity kinds compared, in report order
+var compareKinds = []string{"genera", "taxa", "sources", "species"}
+
+// backendSnapshot holds a backend's entities by kind, keyed by identity.
+// Entities are decoded JSON so both sides compare field by field.
+type backendSnapshot map[string]map[string]map[string]interface{}
+
+// kindDrift is how one kind of entity differs between the backends
+type kindDrift struct {
+	Kind       string
+	Local      int
+	Remote     int
+	OnlyLocal  []string
+	OnlyRemote []string
+	Different  []entityDrift
+}
+
+func (d *kindDrift) drifted() bool {
+	return len(d.OnlyLocal) > 0 || len(d.OnlyRemote) > 0 || len(d.Different) > 0
+}
+
+// entityDrift is an entity present on both sides with different content
+type entityDrift struct {
+	Key        string
+	LocalHash  string
+	RemoteHash string
+	Fields     []fieldDrift
+}
+
+// fieldDrift is one differing field, by dotted path; "" means absent
+type fieldDrift struct {
+	Path   string
+	Local  string
+	Remote string
+}
+
+func runCompareBackends(cmd *cobra.Command, args []string) error {
+	if !isActualRemote() {
+		return usageErrorf("compare-backends needs a remote profile; select one with --profile")
+	}
+
+	remoteClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	server, err := embedded.Start(embedded.Config{DBPath: dbPath, Quiet: true})
+	if err != nil {
+		return fmt.Errorf("failed to start embedded server: %w", err)
+	}
+	defer server.Shutdown()
+
+	localClient, err := oakclient.New(server.URL(),
+		oakclient.WithAPIKey(server.APIKey()),
+		oakclient.WithProfileName("local"),
+		oakclient.WithTransport(server.Transport()),
+		oakclient.WithSkipVersionCheck(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	local, err := snapshotBackend(localClient)
+	if err != nil {
+		return fmt.Errorf("failed to read local database: %w", err)
+	}
+	remote, err := snapshotBackend(remoteClient)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	fmt.Printf("Comparing local (%s) with [%s]\n\n", dbPath, resolvedProfile.Name)
+	drift := compareSnapshots(local, remote)
+	printDrift(drift, compareMaxDiffs)
+
+	for _, d := range drift {
+		if d.drifted() {
+			cmd.SilenceUsage = true // Drift is a finding, not a usage problem
+			return fmt.Errorf("local database and [%s] differ", resolvedProfile.Name)
+		}
+	}
+	fmt.Println("\nNo drift.")
+	return nil
+}
+
+// snapshotBackend reads every compared entity from an API
+func snapshotBackend(c *oakclient.Client) (backendSnapshot, error) {
+	ctx := commandContext()
+	snap := backendSnapshot{}
+	for _, kind := range compareKinds {
+		snap[kind] = map[string]map[string]interface{}{}
+	}
+
+	genera, err := c.ListGenera(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range genera {
+		fields, err := decodeFields(g)
+		if err != nil {
+			return nil, err
+		}
+		delete(fields, "species_count") // Follows the species, which are compared themselves
+		snap["genera"][g.Name] = fields
+	}
+
+	taxa, err := c.ListTaxa(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range taxa.Data {
+		fields, err := decodeFields(t)
+		if err != nil {
+			return nil, err
+		}
+		snap["taxa"][fmt.Sprintf("%s %s %s", t.Genus, t.Level, t.Name)] = fields
+	}
+
+	data, err := c.Export(ctx, oakclient.ExportOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var export struct {
+		Sources []map[string]interface{} `json:"sources"`
+		Species []map[string]interface{} `json:"species"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse export: %w", err)
+	}
+	for _, s := range export.Sources {
+		id, _ := s["id"].(float64)
+		snap["sources"][strconv.FormatInt(int64(id), 10)] = s
+	}
+	for _, s := range export.Species {
+		name, _ := s["name"].(string)
+		genus := ""
+		if taxonomy, ok := s["taxonomy"].(map[string]interface{}); ok {
+			genus, _ = taxonomy["genus"].(string)
+		}
+		// Source names and licenses are copied from the sources, which are compared themselves
+		if sources, ok := s["sources"].([]interface{}); ok {
+			for _, sd := range sources {
+				if sd, ok := sd.(map[string]interface{}); ok {
+					delete(sd, "source_name")
+					delete(sd, "source_url")
+					delete(sd, "license")
+					delete(sd, "license_url")
+				}
+			}
+		}
+		snap["species"][genus+" "+name] = s
+	}
+	return snap, nil
+}
+
+// decodeFields converts a typed value to the decoded JSON snapshots hold
+func decodeFields(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// compareSnapshots reports, for each kind, which entities exist on only one
+// side and which differ
+func compareSnapshots(local, remote backendSnapshot) []kindDrift {
+	var drift []kindDrift
+	for _, kind := range compareKinds {
+		d := kindDrift{Kind: kind, Local: len(local[kind]), Remote: len(remote[kind])}
+		for _, key := range sortedKeys(local[kind]) {
+			remoteFields, ok := remote[kind][key]
+			if !ok {
+				d.OnlyLocal = append(d.OnlyLocal, key)
+				continue
+			}
+			localHash, remoteHash := entityHash(local[kind][key]), entityHash(remoteFields)
+			if localHash != remoteHash {
+				d.Different = append(d.Different, entityDrift{
+					Key:        key,
+					LocalHash:  localHash,
+					RemoteHash: remoteHash,
+					Fields:     diffFields(local[kind][key], remoteFields),
+				})
+			}
+		}
+		for _, key := range sortedKeys(remote[kind]) {
+			if _, ok := local[kind][key]; !ok {
+				d.OnlyRemote = append(d.OnlyRemote, key)
+			}
+		}
+		drift = append(drift, d)
+	}
+	return drift
+}
+
+// entityHash is a short digest of an entity's leaf fields. JSON encoding
+// sorts map keys, so equal content hashes equally whatever the order of its
+// fields or source data.
+func entityHash(fields map[string]interface{}) string {
+	leaves := map[string]string{}
+	flattenFields("", fields, leaves)
+	data, _ := json.Marshal(leaves)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// diffFields lists the leaf fields that differ between two entities
+func diffFields(local, remote map[string]interface{}) []fieldDrift {
+	localLeaves, remoteLeaves := map[string]string{}, map[string]string{}
+	flattenFields("", local, localLeaves)
+	flattenFields("", remote, remoteLeaves)
+
+	paths := map[string]bool{}
+	for p := range localLeaves {
+		paths[p] = true
+	}
+	for p := range remoteLeaves {
+		paths[p] = true
+	}
+
+	var diffs []fieldDrift
+	for _, p := range sortedKeys(paths) {
+		if localLeaves[p] != remoteLeaves[p] {
+			diffs = append(diffs, fieldDrift{Path: p, Local: localLeaves[p], Remote: remoteLeaves[p]})
+		}
+	}
+	return diffs
+}
+
+// flattenFields records an entity's leaf values as JSON by dotted path.
+// Lists of source data are keyed by source ID, so one source's changes do
+// not show up as a shifted list; other lists are compared whole.
+func flattenFields(prefix string, v interface{}, out map[string]string) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flattenFields(path, child, out)
+		}
+		return
+	case []interface{}:
+		if bySource, ok := keyBySourceID(v); ok {
+			for id, child := range bySource {
+				flattenFields(fmt.Sprintf("%s[%s]", prefix, id), child, out)
+			}
+			return
+		}
+	case nil:
+		return
+	}
+	data, _ := json.Marshal(v)
+	out[prefix] = string(data)
+}
+
+// keyBySourceID maps a non-empty list of objects with source IDs by that ID
+func keyBySourceID(list []interface{}) (map[string]interface{}, bool) {
+	if len(list) == 0 {
+		return nil, false
+	}
+	keyed := make(map[string]interface{}, len(list))
+	for _, item := range list {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		id, ok := obj["source_id"].(float64)
+		if !ok {
+			return nil, false
+		}
+		keyed[strconv.FormatInt(int64(id), 10)] = obj
+	}
+	return keyed, true
+}
+
+// printDrift prints the count table and then each kind's drift, listing at
+// most limit entities per drift type (0 lists all)
+func printDrift(drift []kindDrift, limit int) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tLOCAL\tREMOTE\tONLY LOCAL\tONLY REMOTE\tDIFFERENT")
+	fmt.Fprintln(w, "----\t-----\t------\t----------\t-----------\t---------")
+	for _, d := range drift {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\n",
+			d.Kind, d.Local, d.Remote, len(d.OnlyLocal), len(d.OnlyRemote), len(d.Different))
+	}
+	w.Flush()
+
+	for _, d := range drift {
+		if !d.drifted() {
+			continue
+		}
+		fmt.Printf("\n%s:\n", d.Kind)
+		printKeys("only local", d.OnlyLocal, limit)
+		printKeys("only remote", d.OnlyRemote, limit)
+		for i, e := range d.Different {
+			if limit > 0 && i == limit {
+				fmt.Printf("  ... and %d more different\n", len(d.Different)-limit)
+				break
+			}
+			fmt.Printf("  different: %s (local %s, remote %s)\n", e.Key, e.LocalHash, e.RemoteHash)
+			for _, f := range e.Fields {
+				fmt.Printf("    %s: %s -> %s\n", f.Path, orAbsent(f.Local), orAbsent(f.Remote))
+			}
+		}
+	}
+}
+
+func printKeys(label string, keys []string, limit int) {
+	for i, key := range keys {
+		if limit > 0 && i == limit {
+			fmt.Printf("  ... and %d more %s\n", len(keys)-limit, label)
+			return
+		}
+		fmt.Printf("  %s: %s\n", label, key)
+	}
+}
+
+func orAbsent(value string) string {
+	if value == "" {
+		return "(absent)"
+	}
+	return value
+}
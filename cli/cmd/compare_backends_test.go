@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCompareSnapshots(t *testing.T) {
+	decode := func(s string) map[string]interface{} {
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	snapshot := func(species map[string]string) backendSnapshot {
+		snap := backendSnapshot{}
+		for _, kind := range compareKinds {
+			snap[kind] = map[string]map[string]interface{}{}
+		}
+		for key, s := range species {
+			snap["species"][key] = decode(s)
+		}
+		return snap
+	}
+
+	local := snapshot(map[string]string{
+		"Quercus alba": `{"name": "alba", "synonyms": ["a"], "sources": [
+			{"source_id": 1, "leaves": "lobed"}, {"source_id": 2, "range": "east"}]}`,
+		"Quercus robur": `{"name": "robur"}`,
+		"Quercus rubra": `{"name": "rubra"}`,
+	})
+	remote := snapshot(map[string]string{
+		"Quercus alba": `{"name": "alba", "synonyms": ["a", "b"], "sources": [
+			{"source_id": 2, "range": "east"}, {"source_id": 1, "leaves": "deeply lobed"}]}`,
+		"Quercus robur":    `{"name": "robur"}`,
+		"Quercus stellata": `{"name": "stellata"}`,
+	})
+
+	drift := compareSnapshots(local, remote)
+	var species kindDrift
+	for _, d := range drift {
+		if d.Kind == "species" {
+			species = d
+		} else if d.drifted() {
+			t.Errorf("%s drifted with no entities", d.Kind)
+		}
+	}
+
+	if species.Local != 3 || species.Remote != 3 {
+		t.Errorf("counts = %d/%d, want 3/3", species.Local, species.Remote)
+	}
+	if len(species.OnlyLocal) != 1 || species.OnlyLocal[0] != "Quercus rubra" {
+		t.Errorf("only local = %v", species.OnlyLocal)
+	}
+	if len(species.OnlyRemote) != 1 || species.OnlyRemote[0] != "Quercus stellata" {
+		t.Errorf("only remote = %v", species.OnlyRemote)
+	}
+	if len(species.Different) != 1 {
+		t.Fatalf("different = %+v, want alba only", species.Different)
+	}
+
+	alba := species.Different[0]
+	if alba.LocalHash == alba.RemoteHash {
+		t.Error("differing entities hashed equally")
+	}
+	reordered := decode(`{"sources": [{"source_id": 2, "range": "east"}, {"source_id": 1, "leaves": "lobed"}]}`)
+	original := decode(`{"sources": [{"source_id": 1, "leaves": "lobed"}, {"source_id": 2, "range": "east"}]}`)
+	if entityHash(reordered) != entityHash(original) {
+		t.Error("source data order changed the hash")
+	}
+	want := []fieldDrift{
+		{Path: "sources[1].leaves", Local: `"lobed"`, Remote: `"deeply lobed"`},
+		{Path: "synonyms", Local: `["a"]`, Remote: `["a","b"]`},
+	}
+	if len(alba.Fields) != len(want) {
+		t.Fatalf("fields = %+v, want %+v", alba.Fields, want)
+	}
+	for i := range want {
+		if alba.Fields[i] != want[i] {
+			t.Errorf("field %d = %+v, want %+v", i, alba.Fields[i], want[i])
+		}
+	}
+}
@@ -1,12 +1,16 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
 	"sort"
 
 	"github.com/spf13/cobra"
 
 	"github.com/jeff/oaks/cli/internal/config"
+	"github.com/jeff/oaks/pkg/oakclient"
 )
 
 var configCmd = &cobra.Command{
@@ -37,7 +41,12 @@ the API URL, and the resolution source.`,
 		} else {
 			fmt.Printf("  Profile: %s\n", profile.Name)
 			fmt.Printf("  URL:     %s\n", profile.URL)
-			fmt.Printf("  Key:     %s\n", config.MaskKey(profile.Key))
+			if profile.Source == config.SourceEmbedded || profile.Source == config.SourceReplica {
+				// Session keys are held in memory only and never shown
+				fmt.Println("  Key:     (session key)")
+			} else {
+				fmt.Printf("  Key:     %s\n", config.MaskKey(profile.Key))
+			}
 			fmt.Printf("  Source:  %s\n", formatSource(profile.Source))
 		}
 
@@ -101,6 +110,72 @@ The default profile (if set) is marked with an asterisk (*).`,
 	},
 }
 
+var configLintCmd = &cobra.Command{
+	Use:   "lint [file]",
+	Short: "Check the config file for problems",
+	Long: `Check ~/.oak/config.yaml (or the given file) for problems and print each
+as file:line: severity: field: message.
+
+Errors are YAML syntax errors, profiles that are not a mapping, a missing
+or non-http(s) url, a key with whitespace or left as the example
+placeholder, and a default_profile that is not defined. A profile with
+errors cannot be used. Warnings are unknown keys, which are ignored (with
+a suggestion for likely typos), a profile without a key when there is no
+~/.oak/api_key either, and a key sent over plain http to a remote host.
+Other commands print the warnings too.
+
+Exits with code 4 if there are errors.
+
+Examples:
+  oak config lint
+  oak config lint staging-config.yaml`,
+	Args: cobra.MaximumNArgs(1),
+	// Linting reads the file only, and must work when loading it would fail
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return checkErrorFormat(cmd)
+	},
+	RunE: runConfigLint,
+}
+
+func runConfigLint(cmd *cobra.Command, args []string) error {
+	path := config.DefaultConfigPath()
+	if len(args) == 1 {
+		path = args[0]
+	}
+	cmd.SilenceUsage = true
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			if len(args) == 1 {
+				return notFoundErrorf("file not found: %s", path)
+			}
+			fmt.Printf("%s: no config file; commands use the local database\n", path)
+			return nil
+		}
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	problems := config.Lint(data)
+	var failures []oakclient.ValidationError
+	for _, p := range problems {
+		fmt.Printf("%s:%d: %s\n", path, p.Line, p)
+		if !p.Warning {
+			failures = append(failures, oakclient.ValidationError{
+				Field:   fmt.Sprintf("%s:%d:%s", path, p.Line, p.Field),
+				Message: p.Message,
+			})
+		}
+	}
+	if len(failures) > 0 {
+		return &validateError{files: 1, problems: &oakclient.MultiValidationError{Errors: failures}}
+	}
+	if len(problems) == 0 {
+		fmt.Printf("%s: ok\n", path)
+	}
+	return nil
+}
+
 // formatSource returns a human-readable description of the profile resolution source.
 func formatSource(source string) string {
 	switch source {
@@ -123,4 +198,5 @@ func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configShowCmd)
 	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configLintCmd)
 }
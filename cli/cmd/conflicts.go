@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/names"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var conflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "Review contradictions between sources",
+	Long: `The API reads a few structured values out of each species source's text:
+the maximum height (growth habit), whether acorns mature in one year or two
+(fruits), and whether leaves are evergreen, semi-evergreen, or deciduous. When
+two sources disagree (heights more than 25% apart), the pair is recorded as a
+conflict. Detection runs whenever species-source text is saved.
+
+Resolve a conflict by naming the source you side with, the value you settled
+on, or both, with a justification. A resolved conflict reopens if either
+source's value changes.`,
+}
+
+var conflictsDetectCmd = &cobra.Command{
+	Use:   "detect [species...]",
+	Short: "Compare sources' claims and record conflicts",
+	Long: `Compare the claims of each species' sources and record the ones that
+disagree. Without species, every species is compared. Saving species-source
+text already does this; run it after changing the detection rules or when
+conflicts look stale.
+
+Examples:
+  oak conflicts detect
+  oak conflicts detect texana --remote`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		species := make([]string, len(args))
+		for i, arg := range args {
+			species[i] = names.NormalizeHybridName(arg)
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		report, err := apiClient.DetectConflicts(ctx, species)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("%d new, %d reopened, %d cleared. See 'oak conflicts list'.\n", report.Detected, report.Reopened, report.Cleared)
+		return nil
+	},
+}
+
+var (
+	conflictsListSpecies  string
+	conflictsListStatus   string
+	conflictsListProperty string
+)
+
+var conflictsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List conflicts between sources",
+	Long: `List open conflicts. --status shows resolved or all conflicts instead.
+
+Examples:
+  oak conflicts list
+  oak conflicts list --species texana --property max_height --status all`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		species := ""
+		if conflictsListSpecies != "" {
+			species = names.NormalizeHybridName(conflictsListSpecies)
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		conflicts, err := apiClient.ListConflicts(ctx, conflictsListStatus, species, conflictsListProperty)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+		if len(conflicts) == 0 {
+			fmt.Println("No conflicts")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSPECIES\tPROPERTY\tSOURCE A\tVALUE A\tSOURCE B\tVALUE B\tSTATUS")
+		fmt.Fprintln(w, "--\t-------\t--------\t--------\t-------\t--------\t-------\t------")
+		for _, c := range conflicts {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%d\t%s\t%s\n",
+				c.ID, c.ScientificName, c.Property, c.SourceA, c.ValueA, c.SourceB, c.ValueB, c.Status)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var conflictsShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a conflict with its evidence",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return usageErrorf("invalid conflict ID %q", args[0])
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		c, err := apiClient.GetConflict(ctx, id)
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("conflict %d not found", id)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Conflict %d: %s, %s (%s)\n", c.ID, c.ScientificName, c.Property, c.Status)
+		fmt.Printf("  Source %d: %s  (%q)\n", c.SourceA, c.ValueA, c.EvidenceA)
+		fmt.Printf("  Source %d: %s  (%q)\n", c.SourceB, c.ValueB, c.EvidenceB)
+		if c.Status == "resolved" {
+			if c.PreferredSourceID != nil {
+				fmt.Printf("Preferred: source %d\n", *c.PreferredSourceID)
+			}
+			if c.Resolution != nil {
+				fmt.Printf("Resolution: %s\n", *c.Resolution)
+			}
+			if c.Justification != nil {
+				fmt.Printf("Justification: %s\n", *c.Justification)
+			}
+		}
+		return nil
+	},
+}
+
+var (
+	conflictsResolvePrefer        int64
+	conflictsResolveValue         string
+	conflictsResolveJustification string
+)
+
+var conflictsResolveCmd = &cobra.Command{
+	Use:   "resolve <id>",
+	Short: "Resolve a conflict with a justification",
+	Long: `Resolve a conflict by naming the source you side with (--prefer), the value
+you settled on (--value), or both. --justification is required.
+
+Examples:
+  oak conflicts resolve 4 --prefer 2 --justification "Source 1 describes a shrubby form"
+  oak conflicts resolve 5 --value "25 m" --justification "Both round the same measurement"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return usageErrorf("invalid conflict ID %q", args[0])
+		}
+		req := &oakclient.ResolveConflictRequest{
+			Resolution:    strings.TrimSpace(conflictsResolveValue),
+			Justification: strings.TrimSpace(conflictsResolveJustification),
+		}
+		if cmd.Flags().Changed("prefer") {
+			req.PreferredSourceID = &conflictsResolvePrefer
+		}
+		if req.Justification == "" {
+			return usageErrorf("--justification is required")
+		}
+		if req.PreferredSourceID == nil && req.Resolution == "" {
+			return usageErrorf("give --prefer, --value, or both")
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if isActualRemote() && !confirmRemoteOperation("Resolve conflict", args[0]) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		c, err := apiClient.ResolveConflict(ctx, id, req)
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("conflict %d not found", id)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+		fmt.Printf("Resolved conflict %d (%s, %s)\n", c.ID, c.ScientificName, c.Property)
+		return nil
+	},
+}
+
+func init() {
+	conflictsListCmd.Flags().StringVar(&conflictsListSpecies, "species", "", "Only conflicts for this species")
+	conflictsListCmd.Flags().StringVar(&conflictsListStatus, "status", "", "open (default), resolved, or all")
+	conflictsListCmd.Flags().StringVar(&conflictsListProperty, "property", "", "max_height, acorn_maturation, or leaf_persistence")
+	conflictsResolveCmd.Flags().Int64Var(&conflictsResolvePrefer, "prefer", 0, "ID of the source you side with")
+	conflictsResolveCmd.Flags().StringVar(&conflictsResolveValue, "value", "", "Value you settled on")
+	conflictsResolveCmd.Flags().StringVar(&conflictsResolveJustification, "justification", "", "Why (required)")
+
+	conflictsCmd.AddCommand(conflictsDetectCmd)
+	conflictsCmd.AddCommand(conflictsListCmd)
+	conflictsCmd.AddCommand(conflictsShowCmd)
+	conflictsCmd.AddCommand(conflictsResolveCmd)
+	rootCmd.AddCommand(conflictsCmd)
+}
@@ -0,0 +1,431 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Database maintenance commands",
+	Long:  `Maintenance operations on the oak database (local or remote).`,
+}
+
+var dbReindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild all derived data",
+	Long: `Rebuild all derived data from the source-of-truth columns: hybrid
+back-references on parent species, species mentioned in accounts and source
+notes, measurements parsed from source text, database indexes, and query
+planner statistics. Use this after manual
+database surgery or a large import.
+
+Examples:
+  oak db reindex            # Reindex the local database
+  oak db reindex --remote   # Reindex the remote API database`,
+	Args: cobra.NoArgs,
+	RunE: runDBReindex,
+}
+
+var dbRepairPreferredCmd = &cobra.Command{
+	Use:   "repair-preferred",
+	Short: "Fix species with more than one preferred source",
+	Long: `Each species may have at most one preferred source. Data written before
+this was enforced can have several; this keeps the earliest-added preferred
+source for each species and clears the rest.
+
+Use 'oak source prefer' to choose a different preferred source afterwards.
+
+Examples:
+  oak db repair-preferred            # Repair the local database
+  oak db repair-preferred --remote   # Repair the remote API database`,
+	Args: cobra.NoArgs,
+	RunE: runDBRepairPreferred,
+}
+
+var repairJSONDryRun bool
+
+var dbRepairJSONCmd = &cobra.Command{
+	Use:   "repair-json",
+	Short: "Reset corrupt JSON list columns",
+	Long: `Species' list fields (synonyms, hybrids, closely related species,
+subspecies, external links) are stored as JSON. A corrupt value no longer
+fails reads: the species is returned with those fields empty and a
+data_error marker, and the problem is logged. This resets each corrupt column
+to an empty list and prints the original value so it can be re-entered.
+
+Examples:
+  oak db repair-json --dry-run   # List corrupt columns without changing them
+  oak db repair-json             # Repair the local database
+  oak db repair-json --remote    # Repair the remote API database`,
+	Args: cobra.NoArgs,
+	RunE: runDBRepairJSON,
+}
+
+var rebuildHybridsDryRun bool
+
+var dbRebuildHybridsCmd = &cobra.Command{
+	Use:   "rebuild-hybrids",
+	Short: "Rewrite species' hybrids lists from hybrid parent links",
+	Long: `Each species' hybrids list is kept in step with the parent1/parent2 of
+the hybrids naming it, but databases imported before that was done can
+disagree. This derives every hybrids list purely from parent links across
+all species, prints each difference from the stored list, and rewrites the
+lists that differ. Hybrid names with no entry of their own are dropped,
+unlike 'oak db reindex', which keeps them.
+
+Examples:
+  oak db rebuild-hybrids --dry-run   # List differences without changing them
+  oak db rebuild-hybrids             # Rebuild the local database
+  oak db rebuild-hybrids --remote    # Rebuild the remote API database`,
+	Args: cobra.NoArgs,
+	RunE: runDBRebuildHybrids,
+}
+
+var dbCheckNamesCmd = &cobra.Command{
+	Use:   "check-names",
+	Short: "List species names that are not in canonical form",
+	Long: `New species names must be canonical: a lowercase epithet, "× " before a
+nothospecies, or two epithets joined by " × ". This lists stored names that
+predate the check, with the canonical form where one exists. Nothing is
+changed; fix a name by creating the species under the suggested name and
+deleting the old one.
+
+Exits with status 4 (validation) if any name needs fixing.
+
+Examples:
+  oak db check-names            # Check the local database
+  oak db check-names --remote   # Check the remote API database`,
+	Args: cobra.NoArgs,
+	RunE: runDBCheckNames,
+}
+
+var maintenanceMessage string
+
+var dbMaintenanceCmd = &cobra.Command{
+	Use:   "maintenance [on|off]",
+	Short: "Show or set the API's maintenance mode",
+	Long: `Maintenance mode makes the API refuse writes with 503 and a message, so
+the web app and other clients can show it while a large import or restore
+runs against the database. Reads keep working. Without an argument, shows
+the current state. The state is not kept across server restarts.
+
+Examples:
+  oak db maintenance --remote
+  oak db maintenance on --message "Restoring last night's backup" --remote
+  oak db maintenance off --remote`,
+	Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"on", "off"},
+	RunE:      runDBMaintenance,
+}
+
+var (
+	migrateTo     int
+	migrateStatus bool
+)
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Show, upgrade, or revert the database schema version",
+	Long: `The database schema is versioned: each change is a numbered migration,
+and the database records which have been applied. The API applies pending
+migrations when it starts and refuses a database migrated by a newer
+version, as do commands reading the database directly.
+
+Without flags, applies any pending migrations. --to reverts (or upgrades) to
+a version, e.g. before going back to an older oak or API server; a
+migration that cannot be undone stops the revert before anything changes.
+The API, and oak itself when it opens a local database, apply the
+migrations again the next time they start.
+
+Examples:
+  oak db migrate --status          # List migrations, applied and pending
+  oak db migrate                   # Apply pending migrations
+  oak db migrate --to 3 --remote   # Revert the remote database to version 3`,
+	Args: cobra.NoArgs,
+	RunE: runDBMigrate,
+}
+
+func init() {
+	dbMigrateCmd.Flags().IntVar(&migrateTo, "to", 0, "Schema version to upgrade or revert to (default latest)")
+	dbMigrateCmd.Flags().BoolVar(&migrateStatus, "status", false, "List migrations without changing anything")
+	dbMigrateCmd.MarkFlagsMutuallyExclusive("to", "status")
+	dbMaintenanceCmd.Flags().StringVar(&maintenanceMessage, "message", "", "Message returned for refused writes (with 'on')")
+
+	dbCmd.AddCommand(dbReindexCmd)
+	dbRepairJSONCmd.Flags().BoolVar(&repairJSONDryRun, "dry-run", false, "List corrupt columns without repairing them")
+	dbRebuildHybridsCmd.Flags().BoolVar(&rebuildHybridsDryRun, "dry-run", false, "List differences without rewriting them")
+
+	dbCmd.AddCommand(dbRepairPreferredCmd)
+	dbCmd.AddCommand(dbRepairJSONCmd)
+	dbCmd.AddCommand(dbRebuildHybridsCmd)
+	dbCmd.AddCommand(dbCheckNamesCmd)
+	dbCmd.AddCommand(dbMaintenanceCmd)
+	dbCmd.AddCommand(dbMigrateCmd)
+	rootCmd.AddCommand(dbCmd)
+}
+
+func runDBReindex(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	if isActualRemote() {
+		fmt.Printf("Reindexing [%s]...\n", apiClient.ProfileName())
+	} else {
+		fmt.Println("Reindexing...")
+	}
+
+	report, err := apiClient.Reindex(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to reindex: %w", err)
+	}
+
+	for _, step := range report.Steps {
+		fmt.Printf("  %-16s %6d items  %5dms\n", step.Name, step.Items, step.DurationMs)
+	}
+	fmt.Printf("Reindex complete in %dms\n", report.DurationMs)
+	return nil
+}
+
+func runDBRepairPreferred(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	if isActualRemote() && !confirmRemoteOperation("Repair preferred sources", "all species") {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	result, err := apiClient.RepairPreferredSources(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to repair preferred sources: %w", err)
+	}
+
+	if len(result.Repaired) == 0 {
+		fmt.Println("No species had more than one preferred source")
+		return nil
+	}
+	for _, name := range result.Repaired {
+		fmt.Printf("  %s\n", name)
+	}
+	fmt.Printf("Repaired %d species\n", len(result.Repaired))
+	return nil
+}
+
+func runDBRepairJSON(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	var result *oakclient.DataErrorsResponse
+	if repairJSONDryRun {
+		result, err = apiClient.ListDataErrors(ctx)
+	} else {
+		if isActualRemote() && !confirmRemoteOperation("Repair JSON columns", "all species") {
+			fmt.Println("Canceled")
+			return nil
+		}
+		result, err = apiClient.RepairJSONColumns(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to repair JSON columns: %w", err)
+	}
+
+	if len(result.Errors) == 0 {
+		fmt.Println("No corrupt JSON columns")
+		return nil
+	}
+	for _, e := range result.Errors {
+		fmt.Printf("  %s %s.%s: %s\n    was: %s\n", e.Key, e.Table, e.Column, e.Error, e.Value)
+	}
+	if repairJSONDryRun {
+		fmt.Printf("Found %d corrupt column(s); run without --dry-run to reset them\n", len(result.Errors))
+	} else {
+		fmt.Printf("Reset %d column(s) to empty lists\n", len(result.Errors))
+	}
+	return nil
+}
+
+func runDBRebuildHybrids(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	var result *oakclient.HybridsResponse
+	if rebuildHybridsDryRun {
+		result, err = apiClient.CheckHybrids(ctx)
+	} else {
+		if isActualRemote() && !confirmRemoteOperation("Rebuild hybrids lists", "all species") {
+			fmt.Println("Canceled")
+			return nil
+		}
+		result, err = apiClient.RebuildHybrids(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rebuild hybrids lists: %w", err)
+	}
+
+	if len(result.Species) == 0 {
+		fmt.Println("All hybrids lists match their parent links")
+		return nil
+	}
+	for _, d := range result.Species {
+		fmt.Printf("  %s\n", d.ScientificName)
+		if d.Error != "" {
+			fmt.Printf("    unreadable: %s\n", d.Error)
+		}
+		for _, h := range d.Missing {
+			fmt.Printf("    + %s\n", h)
+		}
+		for _, h := range d.Extra {
+			fmt.Printf("    - %s\n", h)
+		}
+	}
+	if rebuildHybridsDryRun {
+		fmt.Printf("Found %d species with differing hybrids lists; run without --dry-run to rewrite them\n", len(result.Species))
+	} else {
+		fmt.Printf("Rewrote hybrids lists of %d species\n", len(result.Species))
+	}
+	return nil
+}
+
+func runDBCheckNames(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	report, err := apiClient.GetNameReport(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check species names: %w", err)
+	}
+	cmd.SilenceUsage = true // Non-canonical names are a finding, not a usage problem
+
+	if len(report.Names) == 0 {
+		fmt.Println("All species names are canonical")
+		return nil
+	}
+	for _, p := range report.Names {
+		fmt.Printf("  %q: %s\n", p.ScientificName, p.Problem)
+		switch {
+		case p.Conflicts:
+			fmt.Printf("    canonical form %q is already taken by another species\n", p.Suggestion)
+		case p.Suggestion != "":
+			fmt.Printf("    rename to %q\n", p.Suggestion)
+		}
+	}
+	return &exitError{
+		code: ExitValidation,
+		err:  fmt.Errorf("%d species name(s) are not canonical", len(report.Names)),
+	}
+}
+
+func runDBMaintenance(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	var status *oakclient.MaintenanceStatus
+	if len(args) == 0 {
+		status, err = apiClient.GetMaintenance(ctx)
+	} else {
+		on := args[0] == "on"
+		if !on && maintenanceMessage != "" {
+			return usageErrorf("--message only applies to 'oak db maintenance on'")
+		}
+		if isActualRemote() && !confirmRemoteOperation("Turn maintenance mode "+args[0]+" for", apiClient.ProfileName()) {
+			fmt.Println("Canceled")
+			return nil
+		}
+		status, err = apiClient.SetMaintenance(ctx, on, maintenanceMessage)
+	}
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	if !status.Enabled {
+		fmt.Println("Maintenance mode is off")
+		return nil
+	}
+	fmt.Println("Maintenance mode is on: writes are refused")
+	fmt.Printf("  Message: %s\n", status.Message)
+	if status.Since != nil {
+		fmt.Printf("  Since:   %s\n", status.Since.Local().Format("2006-01-02 15:04 MST"))
+	}
+	return nil
+}
+
+func runDBMigrate(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	toSet := cmd.Flags().Changed("to")
+	if toSet && migrateTo < 0 {
+		return usageErrorf("--to must be 0 or more")
+	}
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	status, err := apiClient.GetSchemaStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+	if migrateStatus {
+		fmt.Printf("Schema version %d of %d\n", status.Version, status.Latest)
+		for _, m := range status.Migrations {
+			state := "pending"
+			if m.AppliedAt != "" {
+				state = "applied " + m.AppliedAt
+			}
+			if !m.Reversible {
+				state += " (irreversible)"
+			}
+			fmt.Printf("  %04d  %-32s %s\n", m.Version, m.Name, state)
+		}
+		return nil
+	}
+
+	var version *int
+	target := status.Latest
+	if toSet {
+		version, target = &migrateTo, migrateTo
+	}
+	if target == status.Version {
+		fmt.Printf("Schema is at version %d\n", status.Version)
+		return nil
+	}
+	if target < status.Version && isActualRemote() &&
+		!confirmRemoteOperation(fmt.Sprintf("Revert the schema to version %d of", target), apiClient.ProfileName()) {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	result, err := apiClient.MigrateSchema(ctx, version)
+	if err != nil {
+		return fmt.Errorf("failed to migrate: %w", err)
+	}
+	for _, name := range result.Applied {
+		fmt.Printf("  Applied  %s\n", name)
+	}
+	for _, name := range result.Reverted {
+		fmt.Printf("  Reverted %s\n", name)
+	}
+	fmt.Printf("Schema migrated from version %d to %d\n", result.From, result.To)
+	return nil
+}
@@ -8,12 +8,13 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/jeff/oaks/cli/internal/client"
 	"github.com/jeff/oaks/cli/internal/names"
+	"github.com/jeff/oaks/pkg/oakclient"
 )
 
 var (
-	forceDelete bool
+	forceDelete      bool
+	deleteRedirectTo string
 )
 
 var deleteCmd = &cobra.Command{
@@ -24,11 +25,16 @@ var deleteCmd = &cobra.Command{
 When connected to a remote API profile, shows the profile name in confirmation.
 Use --force to skip all confirmation prompts.
 
+When a species is renamed (created under the new name) or merged into
+another, delete the old one with --redirect-to so links to its page on the
+website and API lead to the species it became.
+
 Examples:
   oak delete alba             # Delete from local database (with confirmation)
   oak delete alba --remote    # Delete from remote API (with confirmation)
   oak delete alba --local     # Force local deletion (with confirmation)
-  oak delete alba --force     # Skip confirmation prompt`,
+  oak delete alba --force     # Skip confirmation prompt
+  oak delete "alba x bicolor" --redirect-to "x jackiana"  # Merged into × jackiana`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := names.NormalizeHybridName(args[0])
@@ -38,10 +44,12 @@ Examples:
 
 func init() {
 	deleteCmd.Flags().BoolVarP(&forceDelete, "force", "f", false, "Skip confirmation prompt")
+	deleteCmd.Flags().StringVar(&deleteRedirectTo, "redirect-to", "", "Species this one was renamed to or merged into; its page URL leads there")
 	rootCmd.AddCommand(deleteCmd)
 }
 
 func runDelete(name string) error {
+	ctx := commandContext()
 	apiClient, err := getAPIClient()
 	if err != nil {
 		return err
@@ -49,23 +57,33 @@ func runDelete(name string) error {
 
 	// Verify auth before doing any work (only for actual remote servers)
 	if isActualRemote() {
-		if err := apiClient.VerifyAuth(); err != nil {
+		if err := apiClient.VerifyAuth(ctx); err != nil {
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 	}
 
 	// Verify entry exists
-	_, err = apiClient.GetSpecies(name)
+	_, err = apiClient.GetSpecies(ctx, name)
 	if err != nil {
-		if client.IsNotFoundError(err) {
+		if oakclient.IsNotFoundError(err) {
 			if isActualRemote() {
-				return fmt.Errorf("oak entry '%s' not found on [%s]", name, apiClient.ProfileName())
+				return notFoundErrorf("oak entry '%s' not found on [%s]", name, apiClient.ProfileName())
 			}
-			return fmt.Errorf("oak entry '%s' not found", name)
+			return notFoundErrorf("oak entry '%s' not found", name)
 		}
 		return fmt.Errorf("failed to fetch entry: %w", err)
 	}
 
+	target := names.NormalizeHybridName(deleteRedirectTo)
+	if target != "" {
+		if _, err := apiClient.GetSpecies(ctx, target); err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("--redirect-to species '%s' not found", target)
+			}
+			return fmt.Errorf("failed to fetch entry: %w", err)
+		}
+	}
+
 	// Confirmation prompt
 	if !forceDelete {
 		var prompt string
@@ -87,7 +105,7 @@ func runDelete(name string) error {
 		}
 	}
 
-	if err := apiClient.DeleteSpecies(name); err != nil {
+	if err := apiClient.DeleteSpeciesRedirecting(ctx, name, target); err != nil {
 		return fmt.Errorf("failed to delete entry: %w", err)
 	}
 
@@ -96,5 +114,8 @@ func runDelete(name string) error {
 	} else {
 		fmt.Printf("Deleted oak entry: %s\n", name)
 	}
+	if target != "" {
+		fmt.Printf("Links to %s now lead to %s\n", name, target)
+	}
 	return nil
 }
@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
-	"github.com/jeff/oaks/cli/internal/client"
 	"github.com/jeff/oaks/cli/internal/editor"
 	"github.com/jeff/oaks/cli/internal/names"
+	"github.com/jeff/oaks/pkg/oakclient"
 )
 
 var editCmd = &cobra.Command{
@@ -18,22 +23,37 @@ var editCmd = &cobra.Command{
 When connected to a remote API profile, prompts for confirmation before
 saving changes. Local operations (default or --local) proceed without confirmation.
 
+If someone else saves the entry while you are editing it, nothing is
+overwritten: fields only one of you changed are merged, and fields you both
+changed open in $EDITOR as yours/base/server sections to resolve, as in a
+git merge. Canceling the merge writes your edits to a file and exits with
+code 5 so you can run the command again and reapply them.
+
 Examples:
   oak edit alba             # Edit in local database
   oak edit alba --remote    # Edit on remote API (with confirmation)
-  oak edit alba --local     # Force local edit`,
+  oak edit alba --local     # Force local edit
+  oak edit alba --file alba.yaml  # Replace the entry with a curated YAML file`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := names.NormalizeHybridName(args[0])
+		cmd.SilenceUsage = true // Failures from here on are about the records, not the arguments
 		return runEdit(name)
 	},
 }
 
 func init() {
+	addInputFlags(editCmd)
 	rootCmd.AddCommand(editCmd)
 }
 
 func runEdit(name string) error {
+	ctx := commandContext()
+	body, err := readInputBody()
+	if err != nil {
+		return err
+	}
+
 	apiClient, err := getAPIClient()
 	if err != nil {
 		return err
@@ -41,28 +61,50 @@ func runEdit(name string) error {
 
 	// Verify auth before doing any work (only for actual remote servers)
 	if isActualRemote() {
-		if err := apiClient.VerifyAuth(); err != nil {
+		if err := apiClient.VerifyAuth(ctx); err != nil {
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 	}
 
+	if body != nil {
+		if isActualRemote() && !confirmRemoteOperation("Update", name) {
+			fmt.Println("Canceled")
+			return nil
+		}
+		var updated oakclient.OakEntry
+		path := "/api/v1/species/" + url.PathEscape(name)
+		if err := sendInputBody(ctx, apiClient, http.MethodPut, path, body, &updated); err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("oak entry '%s' not found", name)
+			}
+			return fmt.Errorf("failed to update entry: %w", err)
+		}
+		fmt.Printf("Updated oak entry: %s\n", updated.ScientificName)
+		return nil
+	}
+
 	validator, err := getSchema()
 	if err != nil {
 		return err
 	}
 
-	// Fetch entry
-	remoteEntry, err := apiClient.GetSpecies(name)
+	// Fetch entry, with the version the save must still match
+	remoteEntry, etag, err := apiClient.GetSpeciesWithETag(ctx, name)
 	if err != nil {
-		if client.IsNotFoundError(err) {
+		if oakclient.IsNotFoundError(err) {
 			if isActualRemote() {
-				return fmt.Errorf("oak entry '%s' not found on [%s]", name, apiClient.ProfileName())
+				return notFoundErrorf("oak entry '%s' not found on [%s]", name, apiClient.ProfileName())
 			}
-			return fmt.Errorf("oak entry '%s' not found", name)
+			return notFoundErrorf("oak entry '%s' not found", name)
 		}
 		return fmt.Errorf("failed to fetch entry: %w", err)
 	}
 
+	if remoteEntry.DataError != "" {
+		fmt.Fprintf(os.Stderr, "Warning: stored data for '%s' is corrupt (%s); those fields are shown empty and saving replaces them\n",
+			name, remoteEntry.DataError)
+	}
+
 	// Convert to internal model for editing
 	existing := clientEntryToModel(remoteEntry)
 
@@ -77,11 +119,32 @@ func runEdit(name string) error {
 		return nil
 	}
 
-	// Convert to API request and update
-	req := modelToSpeciesRequest(entry)
-	_, err = apiClient.UpdateSpecies(name, req)
-	if err != nil {
-		return fmt.Errorf("failed to update entry: %w", err)
+	// Update, merging with whatever was saved meanwhile until the save sticks
+	what := fmt.Sprintf("oak entry '%s'", name)
+	for {
+		_, err = apiClient.UpdateSpeciesIfMatch(ctx, name, modelToSpeciesRequest(entry), etag)
+		if err == nil {
+			break
+		}
+		if !oakclient.IsPreconditionFailedError(err) {
+			return fmt.Errorf("failed to update entry: %w", err)
+		}
+
+		current, currentETag, err := apiClient.GetSpeciesWithETag(ctx, name)
+		if err != nil {
+			return editConflict(what, entry, "")
+		}
+		fmt.Fprintf(os.Stderr, "%s changed on the server while you were editing it; merging\n", what)
+		remote := clientEntryToModel(current)
+		merged, fromServer, err := editor.MergeOakEntry(existing, entry, remote, validator)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Merge failed: %v\n", err)
+		}
+		if merged == nil {
+			return editConflict(what, entry, "")
+		}
+		reportMergedFields(fromServer)
+		existing, entry, etag = remote, merged, currentETag
 	}
 
 	if isActualRemote() {
@@ -91,3 +154,23 @@ func runEdit(name string) error {
 	}
 	return nil
 }
+
+// reportMergedFields lists the fields a merge took from the server's copy
+func reportMergedFields(fields []string) {
+	if len(fields) > 0 {
+		fmt.Fprintf(os.Stderr, "Kept the server's changes to: %s\n", strings.Join(fields, ", "))
+	}
+}
+
+// editConflict reports a save the server refused because its copy of the
+// record changed while it was being edited. The edited document is written
+// to a file first, so the edits can be reapplied to the current copy.
+func editConflict(what string, doc interface{}, sourceName string) error {
+	msg := fmt.Sprintf("%s changed on the server while you were editing it; nothing was saved", what)
+	if path, err := editor.SaveEdits(doc, sourceName); err != nil {
+		msg += fmt.Sprintf(" and your edits could not be kept: %v", err)
+	} else {
+		msg += fmt.Sprintf(".\nYour edits are in %s; run the command again to edit the current copy and reapply them", path)
+	}
+	return &exitError{code: ExitConflict, err: errors.New(msg)}
+}
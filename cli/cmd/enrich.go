@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var enrichCmd = &cobra.Command{
+	Use:   "enrich",
+	Short: "Link species to outside databases through the API server",
+	Long: `Have the API server look species up in outside databases and store what
+it finds. Unlike 'oak sync gbif', the lookups run on the server.`,
+}
+
+var (
+	enrichINatAll    bool
+	enrichINatPhotos int
+	enrichINatDryRun bool
+)
+
+var enrichINatCmd = &cobra.Command{
+	Use:   "inat [species...]",
+	Short: "Link species to their iNaturalist taxa",
+	Long: `Match species against iNaturalist's taxa and store each matched taxon as the
+species' iNaturalist external link, replacing a link to another taxon.
+
+Names are matched exactly, with their genus ("Quercus alba"), at species
+rank or below. A species iNaturalist does not know is reported and left
+alone. --photos N also caches the URLs of up to N of the taxon's
+representative photos (at most 20), replacing those cached before; the
+photos stay on iNaturalist. --dry-run reports the matches without storing
+anything. The server sets OAK_INATURALIST_URL to use another iNaturalist
+API endpoint.
+
+Examples:
+  oak enrich inat alba
+  oak enrich inat --all --photos 5
+  oak enrich inat --all --dry-run --profile prod`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		if enrichINatAll == (len(args) > 0) {
+			return usageErrorf("name species to enrich, or use --all")
+		}
+		if enrichINatPhotos < 0 || enrichINatPhotos > 20 {
+			return usageErrorf("--photos must be from 0 to 20, got %d", enrichINatPhotos)
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+		if enrichINatAll && !enrichINatDryRun && !confirmRemoteOperation("Update", "all species from iNaturalist") {
+			return nil
+		}
+
+		e := &inatEnrich{
+			api:  apiClient,
+			opts: oakclient.INaturalistOptions{Photos: enrichINatPhotos, DryRun: enrichINatDryRun},
+		}
+		if enrichINatAll {
+			for entry, err := range apiClient.AllSpecies(ctx, nil) {
+				if err != nil {
+					return err
+				}
+				if err := e.enrich(ctx, entry.ScientificName); err != nil {
+					return err
+				}
+			}
+		} else {
+			for _, name := range args {
+				if err := e.enrich(ctx, name); err != nil {
+					return err
+				}
+			}
+		}
+
+		verb := "Linked"
+		if enrichINatDryRun {
+			verb = "Would link"
+		}
+		fmt.Printf("\n%s %d species; %d unchanged, %d not matched\n", verb, e.linked, e.unchanged, e.unmatched)
+		return nil
+	},
+}
+
+// inatEnrich links species to iNaturalist, counting the outcomes
+type inatEnrich struct {
+	api  *oakclient.Client
+	opts oakclient.INaturalistOptions
+
+	linked, unchanged, unmatched int
+}
+
+// enrich looks one species up and prints the outcome
+func (e *inatEnrich) enrich(ctx context.Context, name string) error {
+	taxon, err := e.api.EnrichINaturalist(ctx, name, e.opts)
+	if err != nil {
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("species %q not found", name)
+		}
+		return fmt.Errorf("%s: API error: %w", name, err)
+	}
+	if taxon.TaxonID == nil {
+		fmt.Printf("%s: no iNaturalist match\n", name)
+		e.unmatched++
+		return nil
+	}
+	fmt.Println(inatSummary(name, taxon, e.opts))
+	if taxon.LinkChanged {
+		e.linked++
+	} else {
+		e.unchanged++
+	}
+	return nil
+}
+
+// inatSummary describes a species' match, such as
+// "alba: iNaturalist 54779 (Quercus alba), link added, 3 photos"
+func inatSummary(name string, taxon *oakclient.INaturalistTaxon, opts oakclient.INaturalistOptions) string {
+	parts := []string{fmt.Sprintf("%s: iNaturalist %d (%s)", name, *taxon.TaxonID, taxon.Name)}
+	if taxon.LinkChanged {
+		parts = append(parts, "link updated")
+	} else {
+		parts = append(parts, "link up to date")
+	}
+	if opts.Photos > 0 {
+		parts = append(parts, fmt.Sprintf("%d photos", len(taxon.Photos)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	enrichINatCmd.Flags().BoolVar(&enrichINatAll, "all", false, "Enrich every species")
+	enrichINatCmd.Flags().IntVar(&enrichINatPhotos, "photos", 0, "Cache up to this many photo URLs per species (at most 20)")
+	enrichINatCmd.Flags().BoolVar(&enrichINatDryRun, "dry-run", false, "Report the matches without storing anything")
+	enrichCmd.AddCommand(enrichINatCmd)
+	rootCmd.AddCommand(enrichCmd)
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+func TestINatSummary(t *testing.T) {
+	id := int64(54779)
+	taxon := &oakclient.INaturalistTaxon{TaxonID: &id, Name: "Quercus alba", LinkChanged: true,
+		Photos: []*oakclient.SpeciesMedia{{URL: "https://example.org/1.jpg"}, {URL: "https://example.org/2.jpg"}}}
+
+	if got, want := inatSummary("alba", taxon, oakclient.INaturalistOptions{Photos: 5}), "alba: iNaturalist 54779 (Quercus alba), link updated, 2 photos"; got != want {
+		t.Errorf("inatSummary() = %q, want %q", got, want)
+	}
+	taxon.LinkChanged = false
+	if got, want := inatSummary("alba", taxon, oakclient.INaturalistOptions{}), "alba: iNaturalist 54779 (Quercus alba), link up to date"; got != want {
+		t.Errorf("inatSummary() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+// Exit codes returned by the oak binary. These are part of the CLI's public
+// contract: scripts branch on them, so existing values must never change.
+const (
+	ExitOK         = 0 // Success
+	ExitError      = 1 // Unclassified failure
+	ExitUsage      = 2 // Bad flags or arguments
+	ExitNotFound   = 3 // Requested species/taxon/source does not exist
+	ExitValidation = 4 // Data failed validation (locally or by the API)
+	ExitConflict   = 5 // Resource already exists, is still referenced, or changed mid-edit
+	ExitAuth       = 6 // Missing or invalid API key
+	ExitNetwork    = 7 // API server unreachable
+	ExitServer     = 8 // API server error or rate limit
+)
+
+// Error formats accepted by --error-format
+const (
+	errorFormatText = "text"
+	errorFormatJSON = "json"
+)
+
+var errorFormat string
+
+// errorCategories maps exit codes to the category names used in JSON output
+var errorCategories = map[int]string{
+	ExitError:      "error",
+	ExitUsage:      "usage",
+	ExitNotFound:   "not_found",
+	ExitValidation: "validation",
+	ExitConflict:   "conflict",
+	ExitAuth:       "auth",
+	ExitNetwork:    "network",
+	ExitServer:     "server",
+}
+
+// exitError attaches an exit code to an error produced by a command
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// notFoundErrorf formats an error that exits with ExitNotFound
+func notFoundErrorf(format string, args ...interface{}) error {
+	return &exitError{code: ExitNotFound, err: fmt.Errorf(format, args...)}
+}
+
+// usageErrorf formats an error that exits with ExitUsage
+func usageErrorf(format string, args ...interface{}) error {
+	return &exitError{code: ExitUsage, err: fmt.Errorf(format, args...)}
+}
+
+// usageArgs makes argument validation failures (cobra.ExactArgs and the
+// like) of cmd and its subcommands exit with ExitUsage, as flag errors do
+func usageArgs(cmd *cobra.Command) {
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			if err := validate(cmd, args); err != nil {
+				return &exitError{code: ExitUsage, err: err}
+			}
+			return nil
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		usageArgs(sub)
+	}
+}
+
+// ExitCode classifies an error returned by Execute into one of the Exit* codes
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var ee *exitError
+	if errors.As(err, &ee) {
+		return ee.code
+	}
+
+	switch {
+	case errors.Is(err, oakclient.ErrValidation):
+		return ExitValidation
+	case errors.Is(err, oakclient.ErrConnection):
+		return ExitNetwork
+	case errors.Is(err, oakclient.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, oakclient.ErrUnauthorized), errors.Is(err, oakclient.ErrForbidden):
+		return ExitAuth
+	case errors.Is(err, oakclient.ErrConflict), errors.Is(err, oakclient.ErrPreconditionFailed):
+		return ExitConflict
+	case errors.Is(err, oakclient.ErrRateLimited), errors.Is(err, oakclient.ErrMaintenance), errors.Is(err, oakclient.ErrServer):
+		return ExitServer
+	}
+
+	return ExitError
+}
+
+// errorReport is the JSON shape written to stderr with --error-format json
+type errorReport struct {
+	Error errorReportBody `json:"error"`
+}
+
+type errorReportBody struct {
+	Category string                      `json:"category"`
+	ExitCode int                         `json:"exit_code"`
+	Code     string                      `json:"code,omitempty"` // API error code, if the API refused the request
+	Message  string                      `json:"message"`
+	Details  []oakclient.ValidationError `json:"details,omitempty"`
+}
+
+// HandleError writes err to w in the format selected by --error-format
+// and returns the process exit code.
+func HandleError(w io.Writer, err error) int {
+	code := ExitCode(err)
+	if code == ExitOK {
+		return code
+	}
+
+	if errorFormat != errorFormatJSON {
+		fmt.Fprintln(w, err)
+		return code
+	}
+
+	report := errorReport{Error: errorReportBody{
+		Category: errorCategories[code],
+		ExitCode: code,
+		Code:     oakclient.ErrorCode(err),
+		Message:  err.Error(),
+	}}
+	var multiErr *oakclient.MultiValidationError
+	var apiErr *oakclient.APIError
+	switch {
+	case errors.As(err, &multiErr):
+		report.Error.Details = multiErr.Errors
+	case errors.As(err, &apiErr):
+		report.Error.Details = apiErr.Fields
+	}
+
+	enc := json.NewEncoder(w)
+	if encErr := enc.Encode(report); encErr != nil {
+		fmt.Fprintln(w, err)
+	}
+	return code
+}
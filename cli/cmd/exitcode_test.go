@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/apierror"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"plain error", errors.New("boom"), ExitError},
+		{"not found", notFoundErrorf("source with ID %d not found", 7), ExitNotFound},
+		{"usage", usageErrorf("invalid level: %s", "genus"), ExitUsage},
+		{"wrapped not found", fmt.Errorf("failed: %w", notFoundErrorf("missing")), ExitNotFound},
+		{"api 404", &oakclient.APIError{StatusCode: 404}, ExitNotFound},
+		{"api 401", &oakclient.APIError{StatusCode: 401}, ExitAuth},
+		{"api 409", &oakclient.APIError{StatusCode: 409}, ExitConflict},
+		{"api 412", &oakclient.APIError{StatusCode: 412}, ExitConflict},
+		{"api 422", &oakclient.APIError{StatusCode: 422}, ExitValidation},
+		{"api 429", &oakclient.APIError{StatusCode: 429}, ExitServer},
+		{"api 503", &oakclient.APIError{StatusCode: 503}, ExitServer},
+		{"api maintenance", &oakclient.APIError{StatusCode: 503, Code: apierror.CodeMaintenance}, ExitServer},
+		{"api 400 validation", &oakclient.APIError{StatusCode: 400, Code: apierror.CodeValidation}, ExitValidation},
+		{"api 426", &oakclient.APIError{StatusCode: 426, Code: apierror.CodeClientTooOld}, ExitError},
+		{"multi validation", &oakclient.MultiValidationError{}, ExitValidation},
+		{"connection", fmt.Errorf("API error: %w", &oakclient.ConnectionError{Err: errors.New("refused")}), ExitNetwork},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsageArgs(t *testing.T) {
+	root := &cobra.Command{Use: "oak"}
+	show := &cobra.Command{Use: "show <name>", Args: cobra.ExactArgs(1), RunE: func(*cobra.Command, []string) error { return nil }}
+	list := &cobra.Command{Use: "list", RunE: func(*cobra.Command, []string) error { return nil }}
+	group := &cobra.Command{Use: "species"}
+	group.AddCommand(show, list)
+	root.AddCommand(group)
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	usageArgs(root)
+
+	for _, tt := range []struct {
+		args []string
+		want int
+	}{
+		{[]string{"species", "show"}, ExitUsage},
+		{[]string{"species", "show", "alba", "rubra"}, ExitUsage},
+		{[]string{"species", "show", "alba"}, ExitOK},
+		{[]string{"species", "list"}, ExitOK},
+	} {
+		root.SetArgs(tt.args)
+		if got := ExitCode(root.Execute()); got != tt.want {
+			t.Errorf("oak %s: exit code = %d, want %d", strings.Join(tt.args, " "), got, tt.want)
+		}
+	}
+}
+
+func TestHandleError_JSON(t *testing.T) {
+	errorFormat = errorFormatJSON
+	defer func() { errorFormat = errorFormatText }()
+
+	err := &oakclient.MultiValidationError{Errors: []oakclient.ValidationError{
+		{Field: "scientific_name", Message: "required"},
+	}}
+
+	var buf bytes.Buffer
+	code := HandleError(&buf, err)
+	if code != ExitValidation {
+		t.Errorf("code = %d, want %d", code, ExitValidation)
+	}
+
+	var report errorReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("output is not JSON: %v (%s)", err, buf.String())
+	}
+	if report.Error.Category != "validation" || report.Error.ExitCode != ExitValidation {
+		t.Errorf("report = %+v", report.Error)
+	}
+	if len(report.Error.Details) != 1 || report.Error.Details[0].Field != "scientific_name" {
+		t.Errorf("details = %+v", report.Error.Details)
+	}
+}
+
+func TestHandleError_JSONFromAPI(t *testing.T) {
+	errorFormat = errorFormatJSON
+	defer func() { errorFormat = errorFormatText }()
+
+	err := fmt.Errorf("failed to list species: %w", &oakclient.APIError{
+		StatusCode: 400,
+		Code:       apierror.CodeValidation,
+		Message:    "Validation failed",
+		Fields:     []oakclient.ValidationError{{Field: "limit", Message: "must be at least 1"}},
+	})
+
+	var buf bytes.Buffer
+	HandleError(&buf, err)
+
+	var report errorReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("output is not JSON: %v (%s)", err, buf.String())
+	}
+	if report.Error.Code != apierror.CodeValidation {
+		t.Errorf("code = %q, want %q", report.Error.Code, apierror.CodeValidation)
+	}
+	if len(report.Error.Details) != 1 || report.Error.Details[0].Field != "limit" {
+		t.Errorf("details = %+v", report.Error.Details)
+	}
+}
+
+func TestHandleError_Text(t *testing.T) {
+	var buf bytes.Buffer
+	code := HandleError(&buf, notFoundErrorf("taxon not found: %s", "Lobatae"))
+	if code != ExitNotFound {
+		t.Errorf("code = %d, want %d", code, ExitNotFound)
+	}
+	if strings.TrimSpace(buf.String()) != "taxon not found: Lobatae" {
+		t.Errorf("output = %q", buf.String())
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
 )
 
 var exportCmd = &cobra.Command{
@@ -17,24 +19,50 @@ with taxonomy embedded in each species and data grouped by source.
 
 If no output file is specified, writes to stdout.
 
+Alternative frontends can get a tailored payload with --mapping, naming
+a YAML export mapping from the server's OAK_EXPORT_MAPPINGS directory.
+In local mode the directory is read from the same environment variable.
+
 Examples:
-  oak export                      # Export to stdout
-  oak export quercus_data.json    # Export to file
-  oak export -o data.json         # Export to file using flag
-  oak export --local data.json    # Export via embedded API
-  oak export --remote data.json   # Export from remote API`,
+  oak export                        # Export to stdout
+  oak export quercus_data.json      # Export to file
+  oak export -o data.json           # Export to file using flag
+  oak export --accounts data.json   # Include rendered species accounts
+  oak export --units dual data.json # Show measurements in both metric and imperial
+  oak export --mapping mobile       # Reshape with the server's "mobile" export mapping
+  oak export --threatened           # Only species assessed CR, EN, or VU
+  oak export --conservation-status EN,CR
+  oak export --lang en,fr           # Only English and French common names
+  oak export --local data.json      # Export via embedded API
+  oak export --remote data.json     # Export from remote API`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runExport,
 }
 
-var exportOutput string
+var (
+	exportOutput   string
+	exportAccounts bool
+	exportUnits    string
+	exportMapping  string
+
+	exportConservationStatus []string
+	exportThreatened         bool
+	exportLanguages          []string
+)
 
 func init() {
 	rootCmd.AddCommand(exportCmd)
 	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Output file path")
+	exportCmd.Flags().BoolVar(&exportAccounts, "accounts", false, "Include each species' rendered long-form account")
+	exportCmd.Flags().StringVar(&exportUnits, "units", "", "Convert measurements in source text: metric, imperial, or dual")
+	exportCmd.Flags().StringVar(&exportMapping, "mapping", "", "Name of an export mapping configured on the server")
+	exportCmd.Flags().StringSliceVar(&exportConservationStatus, "conservation-status", nil, "Only export species with these IUCN codes (comma-separated)")
+	exportCmd.Flags().BoolVar(&exportThreatened, "threatened", false, "Only export threatened species (CR, EN, VU)")
+	exportCmd.Flags().StringSliceVar(&exportLanguages, "lang", nil, "Only export common names in these BCP 47 languages (comma-separated)")
 }
 
 func runExport(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
 	// Determine output path
 	outputPath := exportOutput
 	if len(args) > 0 {
@@ -45,11 +73,20 @@ func runExport(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	opts := oakclient.ExportOptions{
+		Accounts: exportAccounts,
+		Units:    exportUnits,
+		Mapping:  exportMapping,
+
+		ConservationStatus: exportConservationStatus,
+		Threatened:         exportThreatened,
+		Languages:          exportLanguages,
+	}
 
 	// Write output
 	if outputPath == "" {
 		// Export directly to stdout
-		data, err := apiClient.Export()
+		data, err := apiClient.Export(ctx, opts)
 		if err != nil {
 			return fmt.Errorf("API error: %w", err)
 		}
@@ -62,7 +99,7 @@ func runExport(cmd *cobra.Command, args []string) error {
 		}
 		defer file.Close()
 
-		if err := apiClient.ExportToWriter(file); err != nil {
+		if err := apiClient.ExportToWriter(ctx, file, opts); err != nil {
 			return fmt.Errorf("API error: %w", err)
 		}
 		if isActualRemote() {
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var exportSpellDictionaryCmd = &cobra.Command{
+	Use:   "dictionary",
+	Short: "Export a spell-check word list of names and authors",
+	Long: `Export every word of the compendium's names as a spell-check dictionary, so
+editors stop flagging them: genera, species epithets, hybrids, varieties,
+and synonyms; subgenus, section, subsection, and complex names; taxon names;
+and author citations and standard abbreviations (Michx., Sarg.). Drafts are
+included. Re-run it as the database grows.
+
+A .dic output file gets a hunspell dictionary, whose first line is the word
+count; any other file, or stdout, gets a plain word list, one per line, as
+ispell and most editor spell-checkers read. --format overrides the choice.
+Words are UTF-8, so pair a hunspell dictionary with an .aff file that has
+SET UTF-8.
+
+Examples:
+  oak export dictionary -o quercus.dic
+  oak export dictionary --format words > ~/.config/words/oaks.txt
+  oak export dictionary --remote -o quercus.dic`,
+	Args: cobra.NoArgs,
+	RunE: runExportSpellDictionary,
+}
+
+// Spell-check dictionary formats
+const (
+	spellDictionaryHunspell = "hunspell"
+	spellDictionaryWords    = "words"
+)
+
+var (
+	spellDictionaryOutput string
+	spellDictionaryFormat string
+)
+
+func init() {
+	exportCmd.AddCommand(exportSpellDictionaryCmd)
+	exportSpellDictionaryCmd.Flags().StringVarP(&spellDictionaryOutput, "output", "o", "", "Output file path (.dic for hunspell)")
+	exportSpellDictionaryCmd.Flags().StringVar(&spellDictionaryFormat, "format", "", "hunspell or words (default from the output file's extension)")
+}
+
+func runExportSpellDictionary(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	format := spellDictionaryFormat
+	switch {
+	case format == "" && strings.EqualFold(filepath.Ext(spellDictionaryOutput), ".dic"):
+		format = spellDictionaryHunspell
+	case format == "":
+		format = spellDictionaryWords
+	case format != spellDictionaryHunspell && format != spellDictionaryWords:
+		return usageErrorf("--format must be hunspell or words, got %q", format)
+	}
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	var texts []string
+	genera, err := apiClient.ListGenera(ctx)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+	for _, g := range genera {
+		texts = append(texts, g.Name)
+		texts = append(texts, g.Subgenera...)
+	}
+	for entry, err := range apiClient.AllSpecies(ctx, nil) {
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+		texts = append(texts, speciesDictionaryTexts(entry)...)
+	}
+	for taxon, err := range apiClient.AllTaxa(ctx, nil) {
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+		texts = append(texts, taxon.Name)
+		if taxon.Author != nil {
+			texts = append(texts, *taxon.Author)
+		}
+	}
+	authors, err := apiClient.ListAuthors(ctx)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+	for _, a := range authors {
+		texts = append(texts, a.Abbreviation)
+	}
+	words := dictionaryWordList(texts)
+
+	var w io.Writer = os.Stdout
+	if spellDictionaryOutput != "" {
+		file, err := os.Create(spellDictionaryOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+	if err := writeDictionary(w, words, format); err != nil {
+		return fmt.Errorf("failed to write dictionary: %w", err)
+	}
+	if spellDictionaryOutput != "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d words to %s\n", len(words), spellDictionaryOutput)
+	}
+	return nil
+}
+
+// speciesDictionaryTexts returns the names and citations of an entry
+func speciesDictionaryTexts(e *oakclient.OakEntry) []string {
+	texts := []string{e.Genus, e.ScientificName}
+	for _, s := range []*string{e.Author, e.Subgenus, e.Section, e.Subsection, e.Complex, e.Parent1, e.Parent2} {
+		if s != nil {
+			texts = append(texts, *s)
+		}
+	}
+	texts = append(texts, e.Hybrids...)
+	texts = append(texts, e.CloselyRelatedTo...)
+	texts = append(texts, e.SubspeciesVarieties...)
+	return append(texts, e.Synonyms...)
+}
+
+// dictionaryWordList splits names and citations into sorted, distinct
+// words. Abbreviations keep their period; hybrid signs, brackets, and the
+// bare hybrid x are dropped.
+func dictionaryWordList(texts []string) []string {
+	seen := make(map[string]bool)
+	var words []string
+	for _, text := range texts {
+		tokens := strings.FieldsFunc(text, func(r rune) bool {
+			return unicode.IsSpace(r) || strings.ContainsRune("()[],;&\"×", r)
+		})
+		for _, token := range tokens {
+			word := strings.TrimLeftFunc(token, func(r rune) bool { return !unicode.IsLetter(r) })
+			word = strings.TrimRightFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && r != '.' })
+			if !strings.ContainsFunc(word, unicode.IsLetter) || word == "x" || seen[word] {
+				continue
+			}
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+	sort.Strings(words)
+	return words
+}
+
+// writeDictionary writes words one per line, after their count for hunspell
+func writeDictionary(w io.Writer, words []string, format string) error {
+	var b strings.Builder
+	if format == spellDictionaryHunspell {
+		fmt.Fprintf(&b, "%d\n", len(words))
+	}
+	for _, word := range words {
+		b.WriteString(word)
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
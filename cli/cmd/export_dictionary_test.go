@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+func TestDictionaryWordList(t *testing.T) {
+	author := "(Michx.) Sarg. ex Münchh."
+	section := "Lobatae"
+	texts := speciesDictionaryTexts(&oakclient.OakEntry{
+		ScientificName: "× bebbiana",
+		Genus:          "Quercus",
+		Author:         &author,
+		Section:        &section,
+		Synonyms:       []string{"Quercus alba x macrocarpa", "Q. ×bebbiana var. [nova]"},
+	})
+	words := dictionaryWordList(append(texts, "Sarg.", "Quercus"))
+
+	want := "Lobatae Michx. Münchh. Q. Quercus Sarg. alba bebbiana ex macrocarpa nova var."
+	if got := strings.Join(words, " "); got != want {
+		t.Errorf("words = %s\nwant %s", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := writeDictionary(&buf, words[:2], spellDictionaryHunspell); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "2\nLobatae\nMichx.\n" {
+		t.Errorf("hunspell = %q, want the count first", buf.String())
+	}
+}
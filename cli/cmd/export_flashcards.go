@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var exportFlashcardsCmd = &cobra.Command{
+	Use:   "flashcards",
+	Short: "Export a flashcard deck for learning species",
+	Long: `Export a flashcard deck as CSV for Anki import. Each card's front is the
+diagnostic description of a species (leaves, fruits, bark, twigs, and buds)
+from its preferred source; the back is the scientific and common name.
+Cards are tagged with the species' section. Species with no description
+are skipped, as are drafts.
+
+The CSV carries Anki's file headers, so File > Import picks up the
+separator, HTML fields, and tags column. Anki .apkg packages are not
+generated.
+
+Examples:
+  oak export flashcards -o oaks.csv
+  oak export flashcards --section Lobatae -o red-oaks.csv
+  oak export flashcards --units imperial -o oaks.csv`,
+	Args: cobra.NoArgs,
+	RunE: runExportFlashcards,
+}
+
+var (
+	flashcardsSection string
+	flashcardsOutput  string
+	flashcardsUnits   string
+)
+
+func init() {
+	exportCmd.AddCommand(exportFlashcardsCmd)
+	exportFlashcardsCmd.Flags().StringVar(&flashcardsSection, "section", "", "Only species in this section")
+	exportFlashcardsCmd.Flags().StringVarP(&flashcardsOutput, "output", "o", "", "Output file path (.csv)")
+	exportFlashcardsCmd.Flags().StringVar(&flashcardsUnits, "units", "", "Convert measurements in descriptions: metric, imperial, or dual")
+}
+
+// flashcardFields are the diagnostic source fields shown on a card's front, in order
+var flashcardFields = []struct {
+	label string
+	value func(*flashcardSource) *string
+}{
+	{"Leaves", func(s *flashcardSource) *string { return s.Leaves }},
+	{"Fruits", func(s *flashcardSource) *string { return s.Fruits }},
+	{"Bark", func(s *flashcardSource) *string { return s.Bark }},
+	{"Twigs", func(s *flashcardSource) *string { return s.Twigs }},
+	{"Buds", func(s *flashcardSource) *string { return s.Buds }},
+}
+
+// flashcardSource is the part of an export species source a card uses
+type flashcardSource struct {
+	IsPreferred bool     `json:"is_preferred"`
+	LocalNames  []string `json:"local_names"`
+	Leaves      *string  `json:"leaves"`
+	Fruits      *string  `json:"fruits"`
+	Bark        *string  `json:"bark"`
+	Twigs       *string  `json:"twigs"`
+	Buds        *string  `json:"buds"`
+}
+
+// flashcardSpecies is the part of an export species a card uses
+type flashcardSpecies struct {
+	Name     string `json:"name"`
+	Taxonomy struct {
+		Genus   string  `json:"genus"`
+		Section *string `json:"section"`
+	} `json:"taxonomy"`
+	Sources []flashcardSource `json:"sources"`
+}
+
+// flashcard is one card of the deck
+type flashcard struct {
+	Front string // HTML
+	Back  string // HTML
+	Tags  string // Space-separated Anki tags
+}
+
+func runExportFlashcards(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	if strings.EqualFold(filepath.Ext(flashcardsOutput), ".apkg") {
+		return usageErrorf("Anki .apkg packages are not supported: write a .csv and use File > Import in Anki")
+	}
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+	data, err := apiClient.Export(ctx, oakclient.ExportOptions{Units: flashcardsUnits})
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	var export struct {
+		Species []flashcardSpecies `json:"species"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return fmt.Errorf("failed to parse export: %w", err)
+	}
+	cards := buildFlashcards(export.Species, flashcardsSection)
+
+	var w io.Writer = os.Stdout
+	if flashcardsOutput != "" {
+		file, err := os.Create(flashcardsOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+	if err := writeFlashcardsCSV(w, cards); err != nil {
+		return fmt.Errorf("failed to write flashcards: %w", err)
+	}
+	if flashcardsOutput != "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d flashcards to %s\n", len(cards), flashcardsOutput)
+	}
+	return nil
+}
+
+// buildFlashcards makes a card for each species with a diagnostic
+// description, preferring the preferred source, ordered by name
+func buildFlashcards(species []flashcardSpecies, section string) []flashcard {
+	sorted := append([]flashcardSpecies(nil), species...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var cards []flashcard
+	for _, sp := range sorted {
+		sec := ""
+		if sp.Taxonomy.Section != nil {
+			sec = *sp.Taxonomy.Section
+		}
+		if section != "" && !strings.EqualFold(sec, section) {
+			continue
+		}
+
+		var source *flashcardSource
+		for i := range sp.Sources {
+			s := &sp.Sources[i]
+			if flashcardFront(s) != "" && (source == nil || s.IsPreferred) {
+				source = s
+			}
+		}
+		if source == nil {
+			continue
+		}
+
+		genus := sp.Taxonomy.Genus
+		if genus == "" {
+			genus = "Quercus"
+		}
+		back := "<i>" + html.EscapeString(genus+" "+sp.Name) + "</i>"
+		if len(source.LocalNames) > 0 {
+			back += "<br>" + html.EscapeString(source.LocalNames[0])
+		}
+		tags := strings.ToLower(genus)
+		if sec != "" {
+			tags += " section::" + strings.ReplaceAll(sec, " ", "_")
+		}
+		cards = append(cards, flashcard{Front: flashcardFront(source), Back: back, Tags: tags})
+	}
+	return cards
+}
+
+// flashcardFront renders a source's diagnostic fields, or "" if it has none
+func flashcardFront(s *flashcardSource) string {
+	var parts []string
+	for _, f := range flashcardFields {
+		if v := f.value(s); v != nil && strings.TrimSpace(*v) != "" {
+			parts = append(parts, "<b>"+f.label+":</b> "+html.EscapeString(strings.TrimSpace(*v)))
+		}
+	}
+	return strings.Join(parts, "<br>")
+}
+
+// writeFlashcardsCSV writes cards with Anki's import headers
+func writeFlashcardsCSV(w io.Writer, cards []flashcard) error {
+	if _, err := io.WriteString(w, "#separator:Comma\n#html:true\n#tags column:3\n"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	for _, c := range cards {
+		if err := cw.Write([]string{c.Front, c.Back, c.Tags}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestBuildFlashcards(t *testing.T) {
+	data := `[
+		{"name": "velutina", "taxonomy": {"genus": "Quercus", "section": "Lobatae"},
+		 "sources": [{"local_names": ["black oak"], "leaves": "Shiny above"},
+		             {"is_preferred": true, "local_names": ["eastern black oak"], "leaves": "Lobes < 7", "bark": "Dark"}]},
+		{"name": "alba", "taxonomy": {"genus": "Quercus", "section": "Quercus"},
+		 "sources": [{"local_names": ["white oak"], "fruits": "Acorns 1-2 cm"}]},
+		{"name": "bare", "taxonomy": {"genus": "Quercus"}, "sources": [{"local_names": ["nothing"]}]}
+	]`
+	var species []flashcardSpecies
+	if err := json.Unmarshal([]byte(data), &species); err != nil {
+		t.Fatal(err)
+	}
+
+	cards := buildFlashcards(species, "")
+	if len(cards) != 2 {
+		t.Fatalf("got %d cards, want 2 (species without descriptions skipped)", len(cards))
+	}
+	if cards[0].Back != "<i>Quercus alba</i><br>white oak" || cards[0].Tags != "quercus section::Quercus" {
+		t.Errorf("card 0 = %+v", cards[0])
+	}
+	if cards[1].Front != "<b>Leaves:</b> Lobes &lt; 7<br><b>Bark:</b> Dark" {
+		t.Errorf("card 1 front = %q, want the preferred source", cards[1].Front)
+	}
+
+	if cards := buildFlashcards(species, "lobatae"); len(cards) != 1 {
+		t.Errorf("section filter returned %d cards, want 1", len(cards))
+	}
+
+	var buf bytes.Buffer
+	if err := writeFlashcardsCSV(&buf, cards[:1]); err != nil {
+		t.Fatal(err)
+	}
+	want := "#separator:Comma\n#html:true\n#tags column:3\n" +
+		`<b>Fruits:</b> Acorns 1-2 cm,<i>Quercus alba</i><br>white oak,quercus section::Quercus` + "\n"
+	if buf.String() != want {
+		t.Errorf("csv =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
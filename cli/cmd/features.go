@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/names"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var featuresCmd = &cobra.Command{
+	Use:   "features",
+	Short: "Review suggested distinguishing features",
+	Long: `Each species source has a distinguishing_features field for what sets the
+species apart from similar ones. 'oak features suggest' scans the existing
+descriptions for sentences with diagnostic wording ("distinguished from",
+"unlike", "differs from", ...) and queues them for review. Accepting a
+suggestion appends the sentence to the source's distinguishing features;
+rejected sentences are not suggested again.
+
+The field can also be edited directly with 'oak note'.`,
+}
+
+var featuresSuggestCmd = &cobra.Command{
+	Use:   "suggest [species...]",
+	Short: "Queue candidate distinguishing features from source text",
+	Long: `Scan source text for sentences that likely state distinguishing features
+and queue them for review. Without species, every species is scanned.
+
+Examples:
+  oak features suggest
+  oak features suggest texana shumardii --remote`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		species := make([]string, len(args))
+		for i, arg := range args {
+			species[i] = names.NormalizeHybridName(arg)
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		target := "all species"
+		if len(species) > 0 {
+			target = strings.Join(species, ", ")
+		}
+		if isActualRemote() && !confirmRemoteOperation("Suggest distinguishing features for", target) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		queued, err := apiClient.GenerateFeatureSuggestions(ctx, species)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Queued %d suggestion(s) for review. See 'oak features list'.\n", queued)
+		return nil
+	},
+}
+
+var (
+	featuresListSpecies string
+	featuresListStatus  string
+)
+
+var featuresListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List distinguishing-feature suggestions",
+	Long: `List suggestions awaiting review. --status shows accepted, rejected, or all
+suggestions instead.
+
+Examples:
+  oak features list
+  oak features list --species texana --status all`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		species := ""
+		if featuresListSpecies != "" {
+			species = names.NormalizeHybridName(featuresListSpecies)
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		suggestions, err := apiClient.ListFeatureSuggestions(ctx, featuresListStatus, species)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+		if len(suggestions) == 0 {
+			fmt.Println("No suggestions")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSPECIES\tSOURCE\tFIELD\tSTATUS\tSENTENCE")
+		fmt.Fprintln(w, "--\t-------\t------\t-----\t------\t--------")
+		for _, s := range suggestions {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\t%s\n", s.ID, s.ScientificName, s.SourceID, s.Field, s.Status, s.Sentence)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var featuresAcceptCmd = &cobra.Command{
+	Use:   "accept <id>...",
+	Short: "Add suggested sentences to distinguishing features",
+	Long: `Accept suggestions, appending each sentence to its species source's
+distinguishing features.
+
+Examples:
+  oak features accept 12 14
+  oak features accept 12 --remote`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReviewFeatures(args, true)
+	},
+}
+
+var featuresRejectCmd = &cobra.Command{
+	Use:   "reject <id>...",
+	Short: "Dismiss suggested sentences",
+	Long: `Reject suggestions. Rejected sentences are kept so they are not suggested again.
+
+Examples:
+  oak features reject 13`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReviewFeatures(args, false)
+	},
+}
+
+func runReviewFeatures(args []string, accept bool) error {
+	ctx := commandContext()
+	ids := make([]int64, len(args))
+	for i, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return usageErrorf("invalid suggestion ID %q", arg)
+		}
+		ids[i] = id
+	}
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	action := "Reject"
+	if accept {
+		action = "Accept"
+	}
+	if isActualRemote() && !confirmRemoteOperation(action+" feature suggestion(s)", strings.Join(args, ", ")) {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	for _, id := range ids {
+		s, err := apiClient.ReviewFeatureSuggestion(ctx, id, accept)
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("feature suggestion %d not found", id)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+		fmt.Printf("%sed %d (%s, source %d): %s\n", action, s.ID, s.ScientificName, s.SourceID, s.Sentence)
+	}
+	return nil
+}
+
+func init() {
+	featuresListCmd.Flags().StringVar(&featuresListSpecies, "species", "", "Only suggestions for this species")
+	featuresListCmd.Flags().StringVar(&featuresListStatus, "status", "", "pending (default), accepted, rejected, or all")
+
+	featuresCmd.AddCommand(featuresSuggestCmd)
+	featuresCmd.AddCommand(featuresListCmd)
+	featuresCmd.AddCommand(featuresAcceptCmd)
+	featuresCmd.AddCommand(featuresRejectCmd)
+	rootCmd.AddCommand(featuresCmd)
+}
@@ -0,0 +1,366 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/editor"
+	"github.com/jeff/oaks/cli/internal/names"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var fieldsCmd = &cobra.Command{
+	Use:   "fields",
+	Short: "Manage custom fields",
+	Long: `Commands for custom fields: metadata a deployment defines for itself, such
+as an accession number or a herbarium sheet link, on species or sources.
+Each field has a key, a type (text, number, boolean, date, or url), a label,
+and the kind of record it applies to. Values are checked against the type,
+and fields defined with --export are included in the export under custom.
+
+Set values with 'oak species attributes' and 'oak source attributes'.
+Defining and deleting fields requires an admin key.`,
+}
+
+var fieldsListAppliesTo string
+
+var fieldsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List custom fields",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		fields, err := apiClient.ListCustomFields(commandContext(), fieldsListAppliesTo)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+		if len(fields) == 0 {
+			fmt.Println("No custom fields defined")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tTYPE\tAPPLIES TO\tEXPORT\tLABEL")
+		fmt.Fprintln(w, "---\t----\t----------\t------\t-----")
+		for _, f := range fields {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", f.Key, f.Type, f.AppliesTo, f.Export, f.Label)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var (
+	fieldsDefineType      string
+	fieldsDefineLabel     string
+	fieldsDefineAppliesTo string
+	fieldsDefineExport    bool
+)
+
+var fieldsDefineCmd = &cobra.Command{
+	Use:   "define <key>",
+	Short: "Define a custom field or change its label and export flag",
+	Long: `Define a custom field, or redefine an existing one. Keys are lowercase
+letters, digits, and underscores, starting with a letter. A field's type and
+applies-to can't change once records have values for it.
+
+Examples:
+  oak fields define accession --type text --label "Accession number"
+  oak fields define hardiness_zone --type number --label "USDA zone" --export
+  oak fields define digitized --type boolean --label Digitized --applies-to source --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key := args[0]
+		if fieldsDefineType == "" {
+			return usageErrorf("--type is required")
+		}
+		label := fieldsDefineLabel
+		if label == "" {
+			label = key
+		}
+		cmd.SilenceUsage = true
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+		if isActualRemote() && !confirmRemoteOperation("Define custom field", key) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		f, err := apiClient.SaveCustomField(commandContext(), key, &oakclient.CustomFieldRequest{
+			Type:      fieldsDefineType,
+			Label:     label,
+			AppliesTo: fieldsDefineAppliesTo,
+			Export:    fieldsDefineExport,
+		})
+		if err != nil {
+			if problems := apiFieldProblems(err); problems != "" {
+				return &exitError{code: ExitValidation, err: fmt.Errorf("custom field %s refused:\n%s", key, problems)}
+			}
+			if oakclient.IsConflictError(err) {
+				return fmt.Errorf("custom field %s cannot change: %w", key, err)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Defined %s field %s (%s) on %s\n", f.Type, f.Key, f.Label, f.AppliesTo)
+		return nil
+	},
+}
+
+var fieldsDeleteCmd = &cobra.Command{
+	Use:   "delete <key>",
+	Short: "Delete a custom field and every value of it",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+		if isActualRemote() && !confirmRemoteOperation("Delete custom field and its values", args[0]) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		if err := apiClient.DeleteCustomField(commandContext(), args[0]); err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("custom field '%s' not found", args[0])
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Deleted custom field %s\n", args[0])
+		return nil
+	},
+}
+
+var (
+	attributesUnset []string
+	attributesEdit  bool
+)
+
+var speciesAttributesCmd = &cobra.Command{
+	Use:   "attributes <name> [key=value...]",
+	Short: "Show or set a species' custom field values",
+	Long: `Show a species' custom field values, or set them with key=value arguments.
+--unset removes a value, and --edit opens every species field in $EDITOR.
+See 'oak fields' for defining the fields.
+
+Examples:
+  oak species attributes alba
+  oak species attributes alba hardiness_zone=3 accession=1987-0042
+  oak species attributes alba --unset accession --remote
+  oak species attributes alba --edit`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := names.NormalizeHybridName(args[0])
+		return runAttributes(oakclient.CustomEntitySpecies, name, args[1:], attributesStore{
+			get: func(ctx context.Context, c *oakclient.Client) (map[string]any, error) {
+				return c.GetSpeciesAttributes(ctx, name)
+			},
+			set: func(ctx context.Context, c *oakclient.Client, values map[string]any) (map[string]any, error) {
+				return c.SetSpeciesAttributes(ctx, name, values)
+			},
+			notFound: fmt.Sprintf("species '%s' not found", name),
+		})
+	},
+}
+
+var sourceAttributesCmd = &cobra.Command{
+	Use:   "attributes <id> [key=value...]",
+	Short: "Show or set a source's custom field values",
+	Long: `Show a source's custom field values, or set them with key=value arguments.
+--unset removes a value, and --edit opens every source field in $EDITOR.
+See 'oak fields' for defining the fields.
+
+Examples:
+  oak source attributes 3
+  oak source attributes 3 digitized=true --remote`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return usageErrorf("invalid source ID: %s", args[0])
+		}
+		return runAttributes(oakclient.CustomEntitySource, "source "+args[0], args[1:], attributesStore{
+			get: func(ctx context.Context, c *oakclient.Client) (map[string]any, error) {
+				return c.GetSourceAttributes(ctx, id)
+			},
+			set: func(ctx context.Context, c *oakclient.Client, values map[string]any) (map[string]any, error) {
+				return c.SetSourceAttributes(ctx, id, values)
+			},
+			notFound: fmt.Sprintf("source with ID %d not found", id),
+		})
+	},
+}
+
+// attributesStore reads and writes one record's custom field values
+type attributesStore struct {
+	get      func(context.Context, *oakclient.Client) (map[string]any, error)
+	set      func(context.Context, *oakclient.Client, map[string]any) (map[string]any, error)
+	notFound string
+}
+
+// runAttributes shows a record's values, or sets those given as key=value
+// arguments, --unset, or --edit, then shows the result
+func runAttributes(entity, title string, settings []string, store attributesStore) error {
+	ctx := commandContext()
+	if attributesEdit && (len(settings) > 0 || len(attributesUnset) > 0) {
+		return usageErrorf("--edit can't be combined with values or --unset")
+	}
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+	fields, err := apiClient.ListCustomFields(ctx, entity)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+	byKey := make(map[string]*oakclient.CustomField, len(fields))
+	for _, f := range fields {
+		byKey[f.Key] = f
+	}
+
+	changes := make(map[string]any)
+	for _, setting := range settings {
+		key, value, ok := strings.Cut(setting, "=")
+		if !ok {
+			return usageErrorf("values are key=value, got %q", setting)
+		}
+		f := byKey[key]
+		if f == nil {
+			return usageErrorf("%s is not a custom field of %s; see 'oak fields list'", key, entity)
+		}
+		if changes[key], err = parseAttributeValue(f.Type, value); err != nil {
+			return usageErrorf("%s %v", key, err)
+		}
+	}
+	for _, key := range attributesUnset {
+		if byKey[key] == nil {
+			return usageErrorf("%s is not a custom field of %s; see 'oak fields list'", key, entity)
+		}
+		changes[key] = nil
+	}
+
+	values, err := store.get(ctx, apiClient)
+	if err == nil && attributesEdit {
+		editorFields := make([]editor.AttributeField, len(fields))
+		for i, f := range fields {
+			editorFields[i] = editor.AttributeField{Key: f.Key, Type: f.Type, Label: f.Label}
+		}
+		if changes, err = editor.EditAttributes(title, editorFields, values); err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			fmt.Println("No changes")
+			return nil
+		}
+	}
+	if err == nil && len(changes) > 0 {
+		if isActualRemote() && !confirmRemoteOperation("Set custom field values of", title) {
+			fmt.Println("Canceled")
+			return nil
+		}
+		values, err = store.set(ctx, apiClient, changes)
+	}
+	if err != nil {
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("%s", store.notFound)
+		}
+		if problems := apiFieldProblems(err); problems != "" {
+			return &exitError{code: ExitValidation, err: fmt.Errorf("values refused:\n%s", problems)}
+		}
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	if len(values) == 0 {
+		fmt.Printf("No custom field values for %s\n", title)
+		return nil
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tLABEL")
+	fmt.Fprintln(w, "---\t-----\t-----")
+	for _, key := range keys {
+		label := "-"
+		if f := byKey[key]; f != nil {
+			label = f.Label
+		}
+		fmt.Fprintf(w, "%s\t%v\t%s\n", key, values[key], label)
+	}
+	w.Flush()
+	return nil
+}
+
+// parseAttributeValue converts a command-line value to the JSON type the
+// API expects for a field of typ. The API checks dates and URLs.
+func parseAttributeValue(typ, value string) (any, error) {
+	switch typ {
+	case oakclient.CustomFieldNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errors.New("must be a number")
+		}
+		return n, nil
+	case oakclient.CustomFieldBoolean:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("must be true or false")
+		}
+		return b, nil
+	}
+	return value, nil
+}
+
+// apiFieldProblems lists an API validation error's field problems one per
+// line, or returns "" for any other error
+func apiFieldProblems(err error) string {
+	var apiErr *oakclient.APIError
+	if !errors.As(err, &apiErr) || len(apiErr.Fields) == 0 {
+		return ""
+	}
+	problems := make([]string, len(apiErr.Fields))
+	for i, f := range apiErr.Fields {
+		problems[i] = "  " + f.Field + ": " + f.Message
+	}
+	return strings.Join(problems, "\n")
+}
+
+func init() {
+	fieldsListCmd.Flags().StringVar(&fieldsListAppliesTo, "applies-to", "", "Only fields of species or source")
+	fieldsDefineCmd.Flags().StringVar(&fieldsDefineType, "type", "", "Field type: "+strings.Join(oakclient.CustomFieldTypes, ", "))
+	fieldsDefineCmd.Flags().StringVar(&fieldsDefineLabel, "label", "", "Label shown for the field (default the key)")
+	fieldsDefineCmd.Flags().StringVar(&fieldsDefineAppliesTo, "applies-to", oakclient.CustomEntitySpecies, "species or source")
+	fieldsDefineCmd.Flags().BoolVar(&fieldsDefineExport, "export", false, "Include the field's values in the export")
+
+	for _, c := range []*cobra.Command{speciesAttributesCmd, sourceAttributesCmd} {
+		c.Flags().StringArrayVar(&attributesUnset, "unset", nil, "Remove this field's value (repeatable)")
+		c.Flags().BoolVar(&attributesEdit, "edit", false, "Edit every field in $EDITOR")
+	}
+
+	fieldsCmd.AddCommand(fieldsListCmd)
+	fieldsCmd.AddCommand(fieldsDefineCmd)
+	fieldsCmd.AddCommand(fieldsDeleteCmd)
+	rootCmd.AddCommand(fieldsCmd)
+	speciesCmd.AddCommand(speciesAttributesCmd)
+	sourceCmd.AddCommand(sourceAttributesCmd)
+}
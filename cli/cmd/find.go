@@ -41,6 +41,7 @@ func init() {
 }
 
 func runFind(query string) error {
+	ctx := commandContext()
 	apiClient, err := getAPIClient()
 	if err != nil {
 		return err
@@ -50,7 +51,7 @@ func runFind(query string) error {
 	searchSources := searchType == searchTypeBoth || searchType == "source"
 
 	if searchOaks {
-		result, err := apiClient.SearchSpecies(query, findLimit)
+		result, err := apiClient.SearchSpecies(ctx, query, findLimit)
 		if err != nil {
 			return fmt.Errorf("API error: %w", err)
 		}
@@ -68,10 +69,11 @@ func runFind(query string) error {
 	}
 
 	if searchSources {
-		sources, err := apiClient.ListSources()
+		resp, err := apiClient.ListSources(ctx, nil)
 		if err != nil {
 			return fmt.Errorf("API error: %w", err)
 		}
+		sources := resp.Data
 
 		// Filter sources by query (simple substring match for consistency with local)
 		var matched []int64
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var generaCmd = &cobra.Command{
+	Use:   "genera",
+	Short: "Manage the genera tracked by the database",
+	Long: `Commands for genera. Every species entry and taxon belongs to a genus;
+entries created without one are Quercus.
+
+A genus may list its valid subgenera. Species in that genus must then use one
+of them; a genus with no subgenera accepts any.`,
+}
+
+var generaListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List genera with their species counts",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		genera, err := apiClient.ListGenera(ctx)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "GENUS\tCOMMON NAME\tSPECIES\tSUBGENERA")
+		fmt.Fprintln(w, "-----\t-----------\t-------\t---------")
+		for _, g := range genera {
+			common := ""
+			if g.CommonName != nil {
+				common = *g.CommonName
+			}
+			subgenera := strings.Join(g.Subgenera, ", ")
+			if subgenera == "" {
+				subgenera = "(any)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", g.Name, common, g.SpeciesCount, subgenera)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var (
+	genusCommonName string
+	genusSubgenera  []string
+)
+
+var generaAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a genus",
+	Long: `Add a genus so species and taxa can be created in it.
+
+Examples:
+  oak genera add Carya --common-name Hickories
+  oak genera add Acer --common-name Maples --subgenera Acer --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if isActualRemote() && !confirmRemoteOperation("Add genus", args[0]) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		req := &oakclient.GenusRequest{Name: args[0], Subgenera: genusSubgenera}
+		if genusCommonName != "" {
+			req.CommonName = &genusCommonName
+		}
+		if _, err := apiClient.CreateGenus(ctx, req); err != nil {
+			if oakclient.IsConflictError(err) {
+				return fmt.Errorf("genus '%s' already exists", args[0])
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Added genus %s\n", args[0])
+		return nil
+	},
+}
+
+var generaDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete an empty genus",
+	Long: `Delete a genus. Fails while any species or taxa still belong to it.
+
+Examples:
+  oak genera delete Acer --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if isActualRemote() && !confirmRemoteOperation("Delete genus", args[0]) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		if err := apiClient.DeleteGenus(ctx, args[0]); err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("genus '%s' not found", args[0])
+			}
+			if oakclient.IsConflictError(err) {
+				return fmt.Errorf("genus '%s' cannot be deleted: it is the default genus or still has species or taxa", args[0])
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Deleted genus %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	generaAddCmd.Flags().StringVar(&genusCommonName, "common-name", "", "Common name for the genus (e.g. Hickories)")
+	generaAddCmd.Flags().StringSliceVar(&genusSubgenera, "subgenera", nil, "Valid subgenus names (comma-separated); omit to allow any")
+
+	generaCmd.AddCommand(generaListCmd)
+	generaCmd.AddCommand(generaAddCmd)
+	generaCmd.AddCommand(generaDeleteCmd)
+	rootCmd.AddCommand(generaCmd)
+}
@@ -51,7 +51,7 @@ func runGenerateBearNotes(cmd *cobra.Command, args []string) error {
 
 	// Check if Bear database exists
 	if _, err := os.Stat(bearDBPath); os.IsNotExist(err) {
-		return fmt.Errorf("Bear database not found at %s", bearDBPath)
+		return notFoundErrorf("Bear database not found at %s", bearDBPath)
 	}
 
 	// Open Bear database (read-only)
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/names"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var geocodeCmd = &cobra.Command{
+	Use:   "geocode",
+	Short: "Geocode and review range localities",
+	Long: `The API can look up the places named in species sources' range text
+("Edwards Plateau, Texas; Coahuila") and attach approximate coordinates to
+them for the range map. The coordinates are machine-derived, so each one is
+queued for review: accept it (optionally correcting the coordinates) or
+reject it. Rejected localities are not suggested again.
+
+Geocoding runs as a background job on the server and must be enabled there
+with OAK_GEOCODER.`,
+}
+
+var geocodeRunCmd = &cobra.Command{
+	Use:   "run [species...]",
+	Short: "Queue a geocoding job for range localities",
+	Long: `Queue a background job geocoding the localities in range text. Without
+species, every species with range text is geocoded. Lookups are cached on
+the server, so rerunning is cheap. The job's progress and log are at
+/api/v1/jobs/<id>.
+
+Examples:
+  oak geocode run
+  oak geocode run texana shumardii --remote`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		species := make([]string, len(args))
+		for i, arg := range args {
+			species[i] = names.NormalizeHybridName(arg)
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		target := "all species"
+		if len(species) > 0 {
+			target = strings.Join(species, ", ")
+		}
+		if isActualRemote() && !confirmRemoteOperation("Geocode range localities for", target) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		job, err := apiClient.GeocodeRanges(ctx, species)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Queued geocoding job %d. Review the results with 'oak geocode list'.\n", job.ID)
+		return nil
+	},
+}
+
+var (
+	geocodeListSpecies string
+	geocodeListStatus  string
+)
+
+var geocodeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List geocoded range localities",
+	Long: `List localities awaiting review. --status shows accepted, rejected, or all
+localities instead.
+
+Examples:
+  oak geocode list
+  oak geocode list --species texana --status all`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		species := ""
+		if geocodeListSpecies != "" {
+			species = names.NormalizeHybridName(geocodeListSpecies)
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		localities, err := apiClient.ListRangeLocalities(ctx, geocodeListStatus, species)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+		if len(localities) == 0 {
+			fmt.Println("No localities")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSPECIES\tSOURCE\tLOCALITY\tLAT\tLON\tSTATUS\tREGION")
+		fmt.Fprintln(w, "--\t-------\t------\t--------\t---\t---\t------\t------")
+		for _, l := range localities {
+			region := ""
+			if l.Region != nil {
+				region = *l.Region
+			}
+			fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%.4f\t%.4f\t%s\t%s\n",
+				l.ID, l.ScientificName, l.SourceID, l.Locality, l.Latitude, l.Longitude, l.Status, region)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var (
+	geocodeAcceptLat    float64
+	geocodeAcceptLon    float64
+	geocodeAcceptRegion string
+)
+
+var geocodeAcceptCmd = &cobra.Command{
+	Use:   "accept <id>...",
+	Short: "Accept geocoded localities",
+	Long: `Accept localities for the range map. --lat and --lon (together, with a
+single ID) replace the geocoder's coordinates; the locality is then no longer
+marked machine-derived.
+
+Examples:
+  oak geocode accept 12 14
+  oak geocode accept 15 --lat 30.42 --lon -99.81 --region "Edwards Plateau"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		latSet, lonSet := cmd.Flags().Changed("lat"), cmd.Flags().Changed("lon")
+		if latSet != lonSet {
+			return usageErrorf("--lat and --lon must be given together")
+		}
+		if cmd.Flags().Changed("region") && !latSet {
+			return usageErrorf("--region requires --lat and --lon")
+		}
+		if latSet && len(args) > 1 {
+			return usageErrorf("corrected coordinates apply to a single locality")
+		}
+		return runReviewLocalities(args, true, latSet)
+	},
+}
+
+var geocodeRejectCmd = &cobra.Command{
+	Use:   "reject <id>...",
+	Short: "Reject geocoded localities",
+	Long: `Reject localities the geocoder placed wrongly. Rejected localities are kept
+so they are not suggested again.
+
+Examples:
+  oak geocode reject 13`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReviewLocalities(args, false, false)
+	},
+}
+
+var (
+	geocodeMapDetail   string
+	geocodeMapReviewed bool
+	geocodeMapOutput   string
+)
+
+var geocodeMapCmd = &cobra.Command{
+	Use:   "map <species>",
+	Short: "Export a species' range map as GeoJSON",
+	Long: `Write a species' range map as a GeoJSON FeatureCollection: a point for each
+geocoded locality (rejected ones excluded) and an outline around them.
+--detail medium or low rounds and simplifies it for small-scale maps; low
+leaves only the outline. --reviewed leaves out localities not yet accepted.
+If no output file is specified, writes to stdout.
+
+Examples:
+  oak geocode map texana -o texana.geojson
+  oak geocode map texana --detail low --reviewed`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		name := names.NormalizeHybridName(args[0])
+		switch geocodeMapDetail {
+		case "", "full", "medium", "low":
+		default:
+			return usageErrorf("--detail must be full, medium, or low")
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		data, err := apiClient.SpeciesRangeGeoJSON(ctx, name, geocodeMapDetail, geocodeMapReviewed)
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("species '%s' not found", name)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		if geocodeMapOutput == "" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(geocodeMapOutput, data, 0o644); err != nil { //nolint:gosec // map data is public
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Wrote range map to %s\n", geocodeMapOutput)
+		return nil
+	},
+}
+
+func runReviewLocalities(args []string, accept, corrected bool) error {
+	ctx := commandContext()
+	ids := make([]int64, len(args))
+	for i, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return usageErrorf("invalid locality ID %q", arg)
+		}
+		ids[i] = id
+	}
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	action := "Reject"
+	if accept {
+		action = "Accept"
+	}
+	if isActualRemote() && !confirmRemoteOperation(action+" range localit(ies)", strings.Join(args, ", ")) {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	for _, id := range ids {
+		var l *oakclient.RangeLocality
+		switch {
+		case corrected:
+			l, err = apiClient.AcceptRangeLocality(ctx, id, &geocodeAcceptLat, &geocodeAcceptLon, geocodeAcceptRegion)
+		case accept:
+			l, err = apiClient.AcceptRangeLocality(ctx, id, nil, nil, "")
+		default:
+			l, err = apiClient.RejectRangeLocality(ctx, id)
+		}
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("range locality %d not found", id)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+		fmt.Printf("%sed %d (%s, source %d): %s at %.4f, %.4f\n",
+			action, l.ID, l.ScientificName, l.SourceID, l.Locality, l.Latitude, l.Longitude)
+	}
+	return nil
+}
+
+func init() {
+	geocodeListCmd.Flags().StringVar(&geocodeListSpecies, "species", "", "Only localities for this species")
+	geocodeListCmd.Flags().StringVar(&geocodeListStatus, "status", "", "pending (default), accepted, rejected, or all")
+	geocodeAcceptCmd.Flags().Float64Var(&geocodeAcceptLat, "lat", 0, "Corrected latitude")
+	geocodeAcceptCmd.Flags().Float64Var(&geocodeAcceptLon, "lon", 0, "Corrected longitude")
+	geocodeAcceptCmd.Flags().StringVar(&geocodeAcceptRegion, "region", "", "Corrected region name (with --lat and --lon)")
+	geocodeMapCmd.Flags().StringVar(&geocodeMapDetail, "detail", "", "full (default), medium, or low")
+	geocodeMapCmd.Flags().BoolVar(&geocodeMapReviewed, "reviewed", false, "Only accepted localities")
+	geocodeMapCmd.Flags().StringVarP(&geocodeMapOutput, "output", "o", "", "Output file path")
+
+	geocodeCmd.AddCommand(geocodeRunCmd)
+	geocodeCmd.AddCommand(geocodeListCmd)
+	geocodeCmd.AddCommand(geocodeAcceptCmd)
+	geocodeCmd.AddCommand(geocodeRejectCmd)
+	geocodeCmd.AddCommand(geocodeMapCmd)
+	rootCmd.AddCommand(geocodeCmd)
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+// ANSI escapes for grep output, in ripgrep's colors
+const (
+	ansiMagenta = "\x1b[35m"
+	ansiGreen   = "\x1b[32m"
+	ansiBoldRed = "\x1b[1;31m"
+	ansiReset   = "\x1b[0m"
+)
+
+var (
+	grepLimit int
+	grepColor string
+)
+
+var grepCmd = &cobra.Command{
+	Use:   "grep <text>",
+	Short: "Search all account and source text",
+	Long: `Search every species account and source text field for a phrase and
+print each matching line as species:location:line: text, with the matches
+highlighted. Locations are "account" or sources/<id>/<field>, as in
+'oak species mentions'. Matching ignores case, diacritics, and spacing;
+long lines are shortened around the first match.
+
+Color is used when writing to a terminal; --color always keeps it when
+piping to a pager (less -R), and NO_COLOR turns it off.
+
+Examples:
+  oak grep "stellate hairs"
+  oak grep tomentose --limit 500 --remote
+  oak grep "cupule" --color always | less -R`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGrep,
+}
+
+func init() {
+	grepCmd.Flags().IntVar(&grepLimit, "limit", 200, "Maximum number of matching lines to print (up to 500)")
+	grepCmd.Flags().StringVar(&grepColor, "color", "auto", "When to color output: auto, always, or never")
+	rootCmd.AddCommand(grepCmd)
+}
+
+func runGrep(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	color, err := useColor(grepColor, os.Stdout)
+	if err != nil {
+		return err
+	}
+	if grepLimit < 1 || grepLimit > 500 {
+		return usageErrorf("--limit must be from 1 to 500")
+	}
+	if strings.TrimSpace(args[0]) == "" {
+		return usageErrorf("search text is empty")
+	}
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	result, err := apiClient.SearchText(ctx, args[0], grepLimit)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+	if len(result.Data) == 0 {
+		fmt.Fprintf(os.Stderr, "No matches for %q\n", args[0])
+		return nil
+	}
+
+	for _, m := range result.Data {
+		fmt.Println(formatTextMatch(m, color))
+	}
+	if result.Pagination.Total > len(result.Data) {
+		fmt.Fprintf(os.Stderr, "Showing %d of %d matching lines; raise --limit to see more\n",
+			len(result.Data), result.Pagination.Total)
+	}
+	return nil
+}
+
+// useColor decides whether to color output written to w for a --color value
+func useColor(mode string, w io.Writer) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		f, ok := w.(*os.File)
+		if !ok {
+			return false, nil
+		}
+		info, err := f.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	}
+	return false, usageErrorf("--color must be auto, always, or never, got %q", mode)
+}
+
+// formatTextMatch renders a match as species:location:line: text, coloring
+// the prefix and highlighting the matches when color is set
+func formatTextMatch(m *oakclient.TextMatch, color bool) string {
+	var b strings.Builder
+	paint := func(code, s string) {
+		if color {
+			b.WriteString(code + s + ansiReset)
+		} else {
+			b.WriteString(s)
+		}
+	}
+
+	paint(ansiMagenta, m.ScientificName+":"+m.Location)
+	b.WriteString(":")
+	paint(ansiGreen, strconv.Itoa(m.Line))
+	b.WriteString(": ")
+	b.WriteString(highlightSpans(m.Text, m.Matches, color))
+	return b.String()
+}
+
+// highlightSpans returns text with the spans in bold red when color is set
+func highlightSpans(text string, spans []oakclient.TextSpan, color bool) string {
+	if !color {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, span := range spans {
+		if span.Start < last || span.End > len(text) {
+			continue
+		}
+		b.WriteString(text[last:span.Start])
+		b.WriteString(ansiBoldRed + text[span.Start:span.End] + ansiReset)
+		last = span.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
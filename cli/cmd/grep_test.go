@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+func TestFormatTextMatch(t *testing.T) {
+	m := &oakclient.TextMatch{
+		ScientificName: "stellata",
+		Location:       "sources/1/leaves",
+		Line:           2,
+		Text:           "Stellate hairs, stellate hairs.",
+		Matches:        []oakclient.TextSpan{{Start: 0, End: 14}, {Start: 16, End: 30}},
+	}
+
+	if got, want := formatTextMatch(m, false), "stellata:sources/1/leaves:2: Stellate hairs, stellate hairs."; got != want {
+		t.Errorf("plain = %q, want %q", got, want)
+	}
+	want := ansiMagenta + "stellata:sources/1/leaves" + ansiReset + ":" + ansiGreen + "2" + ansiReset + ": " +
+		ansiBoldRed + "Stellate hairs" + ansiReset + ", " + ansiBoldRed + "stellate hairs" + ansiReset + "."
+	if got := formatTextMatch(m, true); got != want {
+		t.Errorf("colored = %q, want %q", got, want)
+	}
+}
+
+func TestFormatFullTextMatch(t *testing.T) {
+	m := &oakclient.FullTextMatch{
+		ScientificName: "stellata",
+		Location:       "sources/1/leaves",
+		Text:           "…with stellate\nhairs beneath",
+		Matches:        []oakclient.TextSpan{{Start: 8, End: 16}, {Start: 17, End: 22}},
+		Score:          2.5,
+	}
+
+	if got, want := formatFullTextMatch(m, false), "  2.50  stellata:sources/1/leaves: …with stellate hairs beneath"; got != want {
+		t.Errorf("plain = %q, want %q", got, want)
+	}
+	want := "  2.50  " + ansiMagenta + "stellata:sources/1/leaves" + ansiReset + ": …with " +
+		ansiBoldRed + "stellate" + ansiReset + " " + ansiBoldRed + "hairs" + ansiReset + " beneath"
+	if got := formatFullTextMatch(m, true); got != want {
+		t.Errorf("colored = %q, want %q", got, want)
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	var buf bytes.Buffer
+	if on, _ := useColor("auto", &buf); on {
+		t.Error("auto colored output to a buffer")
+	}
+	if on, _ := useColor("always", &buf); !on {
+		t.Error("always did not color")
+	}
+	if _, err := useColor("sometimes", &buf); err == nil {
+		t.Error("useColor accepted sometimes")
+	}
+}
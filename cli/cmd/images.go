@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/models"
+	"github.com/jeff/oaks/cli/internal/names"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var imagesCmd = &cobra.Command{
+	Use:   "images",
+	Short: "Attach photos to species",
+}
+
+var (
+	imagesFromFilename bool
+	imagesSpecies      string
+	imagesMap          string
+	imagesUnmatched    string
+	imagesLicense      string
+	imagesAttribution  string
+	imagesCaption      string
+	imagesDryRun       bool
+)
+
+var imagesImportCmd = &cobra.Command{
+	Use:   "import <dir>",
+	Short: "Attach a directory of photos to species",
+	Long: `Upload the JPEG, PNG, and GIF files in a directory (not its subdirectories)
+and attach each to a species. The server keeps the file, makes a thumbnail,
+and reads the capture time and GPS location from its EXIF data.
+
+--species-from-filename finds the species in each file name: the longest
+run of words at its start that names a species, with or without the genus.
+Underscores and hyphens separate words and x marks a hybrid, so
+alba_bark_2.jpg, Quercus-alba.jpg, and x_bebbiana.jpg all match. --species
+attaches every file to one species instead.
+
+Files whose species isn't found are listed at the end for manual mapping:
+--unmatched writes them to a CSV of file,species rows to fill in, and --map
+reads such a CSV back, taking precedence over file names. A photo already
+attached to its species is skipped, so an import can be re-run.
+
+Examples:
+  oak images import ./photos --species-from-filename --license "CC BY 4.0"
+  oak images import ./photos --species-from-filename --unmatched unmatched.csv --dry-run
+  oak images import ./photos --species-from-filename --map unmatched.csv
+  oak images import ./bark --species alba --caption "Bark" --attribution "J. Smith"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImagesImport,
+}
+
+// imageExtensions are the files images import uploads
+var imageExtensions = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true}
+
+func init() {
+	imagesImportCmd.Flags().BoolVar(&imagesFromFilename, "species-from-filename", false, "Find each file's species in its name")
+	imagesImportCmd.Flags().StringVar(&imagesSpecies, "species", "", "Attach every file to this species")
+	imagesImportCmd.Flags().StringVar(&imagesMap, "map", "", "CSV of file,species rows naming files' species")
+	imagesImportCmd.Flags().StringVar(&imagesUnmatched, "unmatched", "", "Write unmatched files to this CSV, to fill in and pass to --map")
+	imagesImportCmd.Flags().StringVar(&imagesLicense, "license", "", "License of the photos, such as \"CC BY 4.0\"")
+	imagesImportCmd.Flags().StringVar(&imagesAttribution, "attribution", "", "Attribution of the photos")
+	imagesImportCmd.Flags().StringVar(&imagesCaption, "caption", "", "Caption of the photos")
+	imagesImportCmd.Flags().BoolVar(&imagesDryRun, "dry-run", false, "List each file's species without uploading")
+	imagesCmd.AddCommand(imagesImportCmd)
+	rootCmd.AddCommand(imagesCmd)
+}
+
+func runImagesImport(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	dir := args[0]
+	if imagesFromFilename == (imagesSpecies != "") {
+		return usageErrorf("use --species-from-filename or --species")
+	}
+
+	files, err := imageFiles(dir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return usageErrorf("no JPEG, PNG, or GIF files in %s", dir)
+	}
+	mapping := map[string]string{}
+	if imagesMap != "" {
+		if mapping, err = readImageMap(imagesMap); err != nil {
+			return err
+		}
+	}
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+	index := map[string]string{}
+	if imagesFromFilename || len(mapping) > 0 {
+		if index, err = speciesNameIndex(ctx, apiClient); err != nil {
+			return err
+		}
+	}
+	if imagesSpecies != "" {
+		entry, err := apiClient.GetSpecies(ctx, names.NormalizeHybridName(imagesSpecies))
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("species %q not found", imagesSpecies)
+		}
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+		imagesSpecies = entry.ScientificName
+	}
+
+	// Find every file's species before uploading anything
+	species := make(map[string]string, len(files))
+	var unmatched []string
+	for _, file := range files {
+		switch name, mapped := mapping[file]; {
+		case mapped:
+			species[file] = index[speciesNameKey(name)]
+		case imagesSpecies != "":
+			species[file] = imagesSpecies
+		default:
+			species[file] = speciesFromFilename(file, index)
+		}
+		if species[file] == "" {
+			unmatched = append(unmatched, file)
+		}
+	}
+	if !imagesDryRun && len(unmatched) < len(files) && isActualRemote() &&
+		!confirmRemoteOperation("Upload", fmt.Sprintf("%d photos", len(files)-len(unmatched))) {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	attached, existing, failed := 0, 0, 0
+	matchedSpecies := map[string]bool{}
+	for _, file := range files {
+		name := species[file]
+		if name == "" {
+			continue
+		}
+		matchedSpecies[name] = true
+		if imagesDryRun {
+			fmt.Printf("%s → %s\n", file, name)
+			attached++
+			continue
+		}
+		m, err := uploadImage(ctx, apiClient, filepath.Join(dir, file), name)
+		switch {
+		case oakclient.IsConflictError(err):
+			fmt.Printf("%s → %s: already attached\n", file, name)
+			existing++
+		case errors.Is(err, oakclient.ErrValidation):
+			fmt.Printf("%s → %s: %v\n", file, name, err)
+			failed++
+		case err != nil:
+			return fmt.Errorf("%s: API error: %w", file, err)
+		default:
+			fmt.Printf("%s → %s%s\n", file, name, imageDetails(m))
+			attached++
+		}
+	}
+
+	verb := "Attached"
+	if imagesDryRun {
+		verb = "Would attach"
+	}
+	fmt.Printf("\n%s %d photos to %d species; %d already attached, %d failed, %d unmatched\n",
+		verb, attached, len(matchedSpecies), existing, failed, len(unmatched))
+	if len(unmatched) > 0 {
+		fmt.Println("\nUnmatched:")
+		for _, file := range unmatched {
+			fmt.Printf("  %s\n", file)
+		}
+		if imagesUnmatched != "" {
+			if err := writeImageMap(imagesUnmatched, unmatched); err != nil {
+				return err
+			}
+			fmt.Printf("Name their species in %s and re-run with --map %s\n", imagesUnmatched, imagesUnmatched)
+		}
+	}
+	return nil
+}
+
+// imageFiles lists the image files in dir, sorted
+func imageFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	var files []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && imageExtensions[strings.ToLower(filepath.Ext(e.Name()))] {
+			files = append(files, e.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// speciesNameIndex maps the keys of species names, both the scientific name
+// and the name with its genus, to scientific names
+func speciesNameIndex(ctx context.Context, apiClient *oakclient.Client) (map[string]string, error) {
+	index := map[string]string{}
+	for entry, err := range apiClient.AllSpecies(ctx, nil) {
+		if err != nil {
+			return nil, fmt.Errorf("API error: %w", err)
+		}
+		index[speciesNameKey(entry.ScientificName)] = entry.ScientificName
+		genus := entry.Genus
+		if genus == "" {
+			genus = models.DefaultGenus
+		}
+		index[speciesNameKey(genus+" "+entry.ScientificName)] = entry.ScientificName
+	}
+	return index, nil
+}
+
+// speciesNameKey is how names are compared: lower case, words separated by
+// single spaces, with the hybrid sign for x
+func speciesNameKey(name string) string {
+	name = strings.ToLower(strings.ReplaceAll(name, "×", " × "))
+	return names.NormalizeHybridName(strings.Join(strings.Fields(name), " "))
+}
+
+// speciesFromFilename returns the species named by the longest run of
+// words at the start of a file name, or "" if none is in index
+func speciesFromFilename(file string, index map[string]string) string {
+	base := strings.TrimSuffix(file, filepath.Ext(file))
+	words := strings.Fields(strings.NewReplacer("_", " ", "-", " ").Replace(base))
+	for n := len(words); n > 0; n-- {
+		if name := index[speciesNameKey(strings.Join(words[:n], " "))]; name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// readImageMap reads a CSV of file,species rows, skipping a file,species
+// header and rows without a species
+func readImageMap(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open map: %w", err)
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	mapping := map[string]string{}
+	for line := 1; ; line++ {
+		row, err := r.Read()
+		if err == io.EOF {
+			return mapping, nil
+		}
+		if err != nil {
+			return nil, usageErrorf("%s: %v", path, err)
+		}
+		if len(row) < 2 || (line == 1 && strings.EqualFold(row[0], "file")) {
+			continue
+		}
+		if file, species := strings.TrimSpace(row[0]), strings.TrimSpace(row[1]); file != "" && species != "" {
+			mapping[file] = species
+		}
+	}
+}
+
+// writeImageMap writes files as a CSV of file,species rows with the species
+// left blank
+func writeImageMap(path string, files []string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	w.Write([]string{"file", "species"})
+	for _, file := range files {
+		w.Write([]string{file, ""})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// uploadImage attaches the file at path to a species
+func uploadImage(ctx context.Context, apiClient *oakclient.Client, path, species string) (*oakclient.SpeciesMedia, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return apiClient.UploadSpeciesMedia(ctx, species, oakclient.MediaUpload{
+		Filename:    filepath.Base(path),
+		Content:     f,
+		License:     imagesLicense,
+		Caption:     imagesCaption,
+		Attribution: imagesAttribution,
+	})
+}
+
+// imageDetails describes what the server read from a photo's EXIF data,
+// such as " (taken 2024-05-03T14:22:10 at 38.8980, -77.0365)"
+func imageDetails(m *oakclient.SpeciesMedia) string {
+	var parts []string
+	if m.CapturedAt != nil {
+		parts = append(parts, "taken "+*m.CapturedAt)
+	}
+	if m.Latitude != nil && m.Longitude != nil {
+		parts = append(parts, fmt.Sprintf("at %.4f, %.4f", *m.Latitude, *m.Longitude))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, " ") + ")"
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSpeciesFromFilename(t *testing.T) {
+	index := map[string]string{
+		"alba": "alba", "quercus alba": "alba",
+		"× bebbiana": "× bebbiana", "quercus × bebbiana": "× bebbiana",
+	}
+	tests := map[string]string{
+		"alba.jpg":                 "alba",
+		"alba_bark_2.JPG":          "alba",
+		"Quercus-alba-acorns.png":  "alba",
+		"x_bebbiana.jpg":           "× bebbiana",
+		"Quercus ×bebbiana 3.jpeg": "× bebbiana",
+		"IMG_2041.jpg":             "",
+		"albany-trip.jpg":          "",
+	}
+	for file, want := range tests {
+		if got := speciesFromFilename(file, index); got != want {
+			t.Errorf("speciesFromFilename(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestImageMap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "unmatched.csv")
+	if err := writeImageMap(path, []string{"IMG_2041.jpg", "IMG_2042.jpg"}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "file,species\nIMG_2041.jpg,\nIMG_2042.jpg,\n" {
+		t.Errorf("unmatched CSV = %q", data)
+	}
+
+	os.WriteFile(path, []byte("file,species\nIMG_2041.jpg,x bebbiana\nIMG_2042.jpg,\n"), 0644)
+	mapping, err := readImageMap(path)
+	if err != nil {
+		t.Fatalf("readImageMap() error = %v", err)
+	}
+	if len(mapping) != 1 || mapping["IMG_2041.jpg"] != "x bebbiana" {
+		t.Errorf("mapping = %v, want only IMG_2041.jpg", mapping)
+	}
+}
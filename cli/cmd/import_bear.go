@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/jeff/oaks/cli/internal/db"
 	"github.com/jeff/oaks/cli/internal/models"
 )
 
@@ -107,7 +108,7 @@ func runImportBear(cmd *cobra.Command, args []string) error {
 
 	// Check if Bear database exists
 	if _, err := os.Stat(bearDBPath); os.IsNotExist(err) {
-		return fmt.Errorf("Bear database not found at %s", bearDBPath)
+		return notFoundErrorf("Bear database not found at %s", bearDBPath)
 	}
 
 	// Open Bear database (read-only)
@@ -130,7 +131,7 @@ func runImportBear(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if source == nil {
-		return fmt.Errorf("source with ID %d not found", bearSourceID)
+		return notFoundErrorf("source with ID %d not found", bearSourceID)
 	}
 
 	fmt.Printf("Importing from Bear to source: %s (ID: %d)\n", source.Name, bearSourceID)
@@ -172,79 +173,82 @@ func runImportBear(cmd *cobra.Command, args []string) error {
 	skipped := 0
 	errors := 0
 
-	for _, note := range notes {
-		parsed := parseNoteContent(note)
-		if parsed.SpeciesName == "" {
-			fmt.Printf("  SKIP: %s (no species name from tag)\n", note.Title)
-			skipped++
-			continue
-		}
+	// The notes and the import timestamp are saved in one transaction, so an
+	// import that fails part way is retried in full next time
+	return database.WithTx(func(tx db.Store) error {
+		for _, note := range notes {
+			parsed := parseNoteContent(note)
+			if parsed.SpeciesName == "" {
+				fmt.Printf("  SKIP: %s (no species name from tag)\n", note.Title)
+				skipped++
+				continue
+			}
 
-		// Check if species exists in oak_entries
-		existing, err := database.GetOakEntry(parsed.SpeciesName)
-		if err != nil {
-			fmt.Printf("  ERROR: %s: %v\n", parsed.SpeciesName, err)
-			errors++
-			continue
-		}
+			// Check if species exists in oak_entries
+			existing, err := tx.GetOakEntry(parsed.SpeciesName)
+			if err != nil {
+				fmt.Printf("  ERROR: %s: %v\n", parsed.SpeciesName, err)
+				errors++
+				continue
+			}
 
-		if existing == nil {
-			// Try with × prefix for hybrids
-			if parsed.IsHybrid {
-				existing, err = database.GetOakEntry("× " + parsed.SpeciesName)
-				if err != nil {
-					fmt.Printf("  ERROR: %s: %v\n", parsed.SpeciesName, err)
-					errors++
-					continue
+			if existing == nil {
+				// Try with × prefix for hybrids
+				if parsed.IsHybrid {
+					existing, err = tx.GetOakEntry("× " + parsed.SpeciesName)
+					if err != nil {
+						fmt.Printf("  ERROR: %s: %v\n", parsed.SpeciesName, err)
+						errors++
+						continue
+					}
 				}
 			}
-		}
 
-		if existing == nil {
-			fmt.Printf("  SKIP: %s (not found in oak_entries)\n", parsed.SpeciesName)
-			skipped++
-			continue
-		}
+			if existing == nil {
+				fmt.Printf("  SKIP: %s (not found in oak_entries)\n", parsed.SpeciesName)
+				skipped++
+				continue
+			}
 
-		// Check if source has any content worth importing
-		if !hasContent(parsed) {
-			fmt.Printf("  SKIP: %s (no content to import)\n", parsed.SpeciesName)
-			skipped++
-			continue
-		}
+			// Check if source has any content worth importing
+			if !hasContent(parsed) {
+				fmt.Printf("  SKIP: %s (no content to import)\n", parsed.SpeciesName)
+				skipped++
+				continue
+			}
 
-		// Build SpeciesSource
-		speciesSource := buildSpeciesSource(existing.ScientificName, parsed, bearSourceID)
+			// Build SpeciesSource
+			speciesSource := buildSpeciesSource(existing.ScientificName, parsed, bearSourceID)
 
-		if bearDryRun {
-			fmt.Printf("  WOULD IMPORT: %s\n", existing.ScientificName)
-			printParsedContent(parsed)
-			imported++
-		} else {
-			if err := database.SaveSpeciesSource(speciesSource); err != nil {
-				fmt.Printf("  ERROR: %s: %v\n", existing.ScientificName, err)
-				errors++
-				continue
+			if bearDryRun {
+				fmt.Printf("  WOULD IMPORT: %s\n", existing.ScientificName)
+				printParsedContent(parsed)
+				imported++
+			} else {
+				if err := tx.SaveSpeciesSource(speciesSource); err != nil {
+					fmt.Printf("  ERROR: %s: %v\n", existing.ScientificName, err)
+					errors++
+					continue
+				}
+				fmt.Printf("  IMPORTED: %s\n", existing.ScientificName)
+				imported++
 			}
-			fmt.Printf("  IMPORTED: %s\n", existing.ScientificName)
-			imported++
 		}
-	}
 
-	fmt.Printf("\nImport complete:\n")
-	fmt.Printf("  Imported: %d\n", imported)
-	fmt.Printf("  Skipped:  %d\n", skipped)
-	fmt.Printf("  Errors:   %d\n", errors)
+		fmt.Printf("\nImport complete:\n")
+		fmt.Printf("  Imported: %d\n", imported)
+		fmt.Printf("  Skipped:  %d\n", skipped)
+		fmt.Printf("  Errors:   %d\n", errors)
 
-	// Save import timestamp (unless dry run)
-	if !bearDryRun && imported > 0 {
-		if err := database.SetMetadata(bearLastImportKey, strconv.FormatFloat(importTimeCoreData, 'f', -1, 64)); err != nil {
-			return fmt.Errorf("failed to save import timestamp: %w", err)
+		// Save import timestamp (unless dry run)
+		if !bearDryRun && imported > 0 {
+			if err := tx.SetMetadata(bearLastImportKey, strconv.FormatFloat(importTimeCoreData, 'f', -1, 64)); err != nil {
+				return fmt.Errorf("failed to save import timestamp: %w", err)
+			}
+			fmt.Printf("\nNext import will check for notes modified after %s\n", time.Now().Format("2006-01-02 15:04:05"))
 		}
-		fmt.Printf("\nNext import will check for notes modified after %s\n", time.Now().Format("2006-01-02 15:04:05"))
-	}
-
-	return nil
+		return nil
+	})
 }
 
 // coreDataToTime converts Core Data timestamp to Go time
@@ -12,11 +12,15 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/jeff/oaks/cli/internal/db"
+	"github.com/jeff/oaks/cli/internal/editor"
 	"github.com/jeff/oaks/cli/internal/models"
 	"github.com/jeff/oaks/cli/internal/schema"
 )
 
-var sourceID int64
+var (
+	sourceID        int64
+	bulkMissingRefs string
+)
 
 var importBulkCmd = &cobra.Command{
 	Use:   "import-bulk <file>",
@@ -24,11 +28,19 @@ var importBulkCmd = &cobra.Command{
 	Long: `Import oak entries from a YAML or JSON file.
 All imported data will be attributed to the specified source.
 
+Hybrid parents are imported before their hybrids. Parents and closely
+related species that are in neither the file nor the database are handled
+by --missing-refs: skip drops the reference, error refuses the import, and
+create adds a draft placeholder entry flagged needs_review.
+
 Note: This command imports OakEntry (species-intrinsic) data only.
 Source-attributed descriptive data should be imported via import-oaksoftheworld.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		filePath := args[0]
+		if err := validateMissingRefsPolicy(bulkMissingRefs); err != nil {
+			return err
+		}
 
 		database, err := getDB()
 		if err != nil {
@@ -47,14 +59,14 @@ Source-attributed descriptive data should be imported via import-oaksoftheworld.
 			return err
 		}
 		if source == nil {
-			return fmt.Errorf("source with ID %d not found. Create it first with 'oak source new'", sourceID)
+			return notFoundErrorf("source with ID %d not found. Create it first with 'oak source new'", sourceID)
 		}
 
-		return importBulk(database, validator, filePath, sourceID)
+		return importBulk(database, validator, filePath, sourceID, bulkMissingRefs)
 	},
 }
 
-func importBulk(database *db.Database, validator *schema.Validator, filePath string, srcID int64) error {
+func importBulk(database db.Store, validator *schema.Validator, filePath string, srcID int64, missingRefs string) error {
 	data, err := readImportFile(filePath)
 	if err != nil {
 		return err
@@ -81,6 +93,7 @@ func importBulk(database *db.Database, validator *schema.Validator, filePath str
 	imported := 0
 	skipped := 0
 
+	valid := make([]*models.OakEntry, 0, len(entries))
 	for i := range entries {
 		entry := &entries[i]
 		if err := validator.ValidateOakEntry(entry); err != nil {
@@ -88,41 +101,59 @@ func importBulk(database *db.Database, validator *schema.Validator, filePath str
 			skipped++
 			continue
 		}
+		valid = append(valid, entry)
+	}
 
-		existing, err := database.GetOakEntry(entry.ScientificName)
-		if err != nil {
-			return err
-		}
+	// The import is one transaction, so a failure part way leaves the
+	// database as it was. Parents are saved before their hybrids so the links
+	// resolve.
+	var report *importReport
+	err = database.WithTx(func(tx db.Store) error {
+		var err error
+		report, err = importInDependencyOrder(tx, valid, missingRefs, func(entry *models.OakEntry) {
+			existing, err := tx.GetOakEntry(entry.ScientificName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to check '%s': %v\n", entry.ScientificName, err)
+				skipped++
+				return
+			}
 
-		if existing != nil {
-			// Check for conflicts on intrinsic fields
-			conflicts := findConflicts(existing, entry)
-			if len(conflicts) > 0 {
-				resolved, skip := resolveConflicts(entry.ScientificName, conflicts)
-				if skip {
-					fmt.Printf("Skipping '%s'\n", entry.ScientificName)
-					skipped++
-					continue
+			if existing != nil {
+				// Check for conflicts on intrinsic fields
+				conflicts := findConflicts(existing, entry)
+				if len(conflicts) > 0 {
+					resolved, skip := resolveConflicts(existing, entry, conflicts)
+					if skip {
+						fmt.Printf("Skipping '%s'\n", entry.ScientificName)
+						skipped++
+						return
+					}
+					// Apply resolutions to the stored entry, as the merge below
+					// only fills in what it lacks
+					applyResolutions(existing, resolved)
 				}
-				// Apply resolutions
-				applyResolutions(entry, resolved)
-			}
 
-			// Merge with existing entry
-			mergeEntries(existing, entry)
-			*entry = *existing
-		}
+				// Merge with existing entry
+				mergeEntries(existing, entry)
+				*entry = *existing
+			}
 
-		if err := database.SaveOakEntry(entry); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to save '%s': %v\n", entry.ScientificName, err)
-			skipped++
-			continue
-		}
+			if err := tx.SaveOakEntry(entry); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to save '%s': %v\n", entry.ScientificName, err)
+				skipped++
+				return
+			}
 
-		imported++
+			imported++
+		})
+		return err
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("\nImport complete: %d imported, %d skipped\n", imported, skipped)
+	report.print(os.Stdout)
 	return nil
 }
 
@@ -156,16 +187,17 @@ func findConflicts(existing, imported *models.OakEntry) []conflict {
 	return conflicts
 }
 
-func resolveConflicts(name string, conflicts []conflict) (map[string]string, bool) {
+func resolveConflicts(existing, imported *models.OakEntry, conflicts []conflict) (map[string]string, bool) {
 	reader := bufio.NewReader(os.Stdin)
 	resolutions := make(map[string]string)
 
-	for _, c := range conflicts {
-		fmt.Printf("\nConflict for %s, field: %s\n", name, c.field)
+	for i, c := range conflicts {
+		fmt.Printf("\nConflict for %s, field: %s\n", imported.ScientificName, c.field)
 		fmt.Printf("[1] Database Value: '%s'\n", c.existingVal)
 		fmt.Printf("[2] Imported Value: '%s'\n", c.importedVal)
+		fmt.Printf("[E] Edit this and the remaining conflicts in $EDITOR\n")
 		fmt.Printf("[S] Skip this entry\n")
-		fmt.Print("> Enter choice (1/2/S): ")
+		fmt.Print("> Enter choice (1/2/E/S): ")
 
 		response, _ := reader.ReadString('\n')
 		response = strings.TrimSpace(strings.ToLower(response))
@@ -175,6 +207,8 @@ func resolveConflicts(name string, conflicts []conflict) (map[string]string, boo
 			resolutions[c.field] = c.existingVal
 		case "2":
 			resolutions[c.field] = c.importedVal
+		case "e":
+			return editConflicts(existing, imported, conflicts[i:], resolutions)
 		case "s":
 			return nil, true
 		default:
@@ -186,6 +220,34 @@ func resolveConflicts(name string, conflicts []conflict) (map[string]string, boo
 	return resolutions, false
 }
 
+// editConflicts resolves import conflicts in the merge view, adding them to
+// resolutions. Canceling the merge skips the entry, and a field cleared in
+// the merge keeps the database value, as import never clears fields.
+func editConflicts(existing, imported *models.OakEntry, conflicts []conflict, resolutions map[string]string) (map[string]string, bool) {
+	fields := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		fields[i] = c.field
+	}
+	merged, err := editor.ResolveImportConflicts(existing, imported, fields)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Merge failed: %v\n", err)
+		return nil, true
+	}
+	if merged == nil {
+		return nil, true
+	}
+
+	chosen := map[string]*string{"author": merged.Author, "conservation_status": merged.ConservationStatus}
+	for _, c := range conflicts {
+		if v := chosen[c.field]; v != nil && *v != "" {
+			resolutions[c.field] = *v
+		} else {
+			resolutions[c.field] = c.existingVal
+		}
+	}
+	return resolutions, false
+}
+
 func applyResolutions(entry *models.OakEntry, resolutions map[string]string) {
 	if val, ok := resolutions["author"]; ok {
 		entry.Author = &val
@@ -250,5 +312,6 @@ func mergeEntries(existing, imported *models.OakEntry) {
 func init() {
 	importBulkCmd.Flags().Int64Var(&sourceID, "source-id", 0, "Source ID to attribute the data to (required)")
 	_ = importBulkCmd.MarkFlagRequired("source-id")
+	importBulkCmd.Flags().StringVar(&bulkMissingRefs, "missing-refs", missingRefsSkip, "Referenced species not in the file or database: skip, error, or create")
 	rootCmd.AddCommand(importBulkCmd)
 }
@@ -0,0 +1,381 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/db"
+	"github.com/jeff/oaks/cli/internal/models"
+	"github.com/jeff/oaks/cli/internal/names"
+	"github.com/jeff/oaks/cli/internal/schema"
+)
+
+var (
+	speciesImportFormat      string
+	speciesImportMap         []string
+	speciesImportDryRun      bool
+	speciesImportMissingRefs string
+)
+
+var speciesImportCmd = &cobra.Command{
+	Use:   "import <file> --format csv",
+	Short: "Import species entries from a CSV file",
+	Long: `Create or update species entries from a CSV file with a header row.
+
+Columns are matched to entry fields by header, ignoring case and treating
+spaces as underscores ("Scientific Name" is scientific_name). --map renames a
+header to a field, or to - to ignore the column. Fields:
+
+  scientific_name (required), author, pronunciation, is_hybrid, genus,
+  conservation_status, subgenus, section, subsection, complex, parent1,
+  parent2, closely_related_to, subspecies_varieties, synonyms, visibility
+
+List fields take values separated by semicolons. is_hybrid takes true/false,
+yes/no, or 1/0. An empty cell leaves the field as it is, so a file can update
+a few fields of existing entries.
+
+Every row is checked against the schema and the taxa reference table before
+anything is written; any failure is reported by line and nothing is imported.
+The import itself runs in one transaction, so it is all or nothing. Hybrid
+parents and closely related species missing from both the file and the
+database are handled by --missing-refs, as with import-bulk.
+
+Examples:
+  oak species import --format csv species.csv --dry-run
+  oak species import --format csv species.csv --map "Species=scientific_name" --map "Notes=-"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if speciesImportFormat != "csv" {
+			return usageErrorf("unsupported --format %q (use csv; import YAML and JSON with 'oak import-bulk')", speciesImportFormat)
+		}
+		if err := validateMissingRefsPolicy(speciesImportMissingRefs); err != nil {
+			return err
+		}
+
+		database, err := getDB()
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		validator, err := getSchema()
+		if err != nil {
+			return err
+		}
+
+		data, err := readImportFile(args[0])
+		if err != nil {
+			return err
+		}
+		return importCSV(os.Stdout, database, validator, data, speciesImportMap, speciesImportMissingRefs, speciesImportDryRun)
+	},
+}
+
+// csvRow is a data row of an import file: its line and its non-empty cells
+// by field
+type csvRow struct {
+	Line  int
+	Cells map[string]string
+}
+
+// csvFields sets each importable entry field from a cell
+var csvFields = map[string]func(entry *models.OakEntry, value string) error{
+	"scientific_name": func(e *models.OakEntry, v string) error { e.ScientificName = names.NormalizeHybridName(v); return nil },
+	"author":          func(e *models.OakEntry, v string) error { e.Author = &v; return nil },
+	"pronunciation":   func(e *models.OakEntry, v string) error { e.Pronunciation = &v; return nil },
+	"is_hybrid": func(e *models.OakEntry, v string) error {
+		switch strings.ToLower(v) {
+		case "true", "yes", "1":
+			e.IsHybrid = true
+		case "false", "no", "0":
+			e.IsHybrid = false
+		default:
+			return fmt.Errorf("is_hybrid %q is not true or false", v)
+		}
+		return nil
+	},
+	"genus":               func(e *models.OakEntry, v string) error { e.Genus = v; return nil },
+	"conservation_status": func(e *models.OakEntry, v string) error { e.ConservationStatus = &v; return nil },
+	"subgenus":            func(e *models.OakEntry, v string) error { e.Subgenus = &v; return nil },
+	"section":             func(e *models.OakEntry, v string) error { e.Section = &v; return nil },
+	"subsection":          func(e *models.OakEntry, v string) error { e.Subsection = &v; return nil },
+	"complex":             func(e *models.OakEntry, v string) error { e.Complex = &v; return nil },
+	"parent1": func(e *models.OakEntry, v string) error {
+		v = names.NormalizeHybridName(v)
+		e.Parent1 = &v
+		return nil
+	},
+	"parent2": func(e *models.OakEntry, v string) error {
+		v = names.NormalizeHybridName(v)
+		e.Parent2 = &v
+		return nil
+	},
+	"closely_related_to": func(e *models.OakEntry, v string) error {
+		e.CloselyRelatedTo = splitCSVList(v, names.NormalizeHybridName)
+		return nil
+	},
+	"subspecies_varieties": func(e *models.OakEntry, v string) error {
+		e.SubspeciesVarieties = splitCSVList(v, nil)
+		return nil
+	},
+	"synonyms": func(e *models.OakEntry, v string) error { e.Synonyms = splitCSVList(v, nil); return nil },
+	"visibility": func(e *models.OakEntry, v string) error {
+		if v != models.VisibilityDraft && v != models.VisibilityPublished {
+			return fmt.Errorf("visibility %q is not draft or published", v)
+		}
+		e.Visibility = v
+		return nil
+	},
+}
+
+// splitCSVList splits a semicolon-separated cell, dropping empty items
+func splitCSVList(value string, normalize func(string) string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if normalize != nil {
+			item = normalize(item)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// csvColumnFields returns the field each column imports to, "" for an
+// ignored column. mappings are --map header=field values.
+func csvColumnFields(header, mappings []string) ([]string, error) {
+	key := func(h string) string {
+		return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(h)), " ", "_")
+	}
+	renamed := make(map[string]string, len(mappings))
+	for _, m := range mappings {
+		from, to, ok := strings.Cut(m, "=")
+		if !ok {
+			return nil, usageErrorf("invalid --map %q (use header=field, or header=- to ignore it)", m)
+		}
+		renamed[key(from)] = strings.TrimSpace(to)
+	}
+
+	fields := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, h := range header {
+		field := key(h)
+		if to, ok := renamed[field]; ok {
+			field = to
+		}
+		if field == "-" {
+			continue
+		}
+		if _, ok := csvFields[field]; !ok {
+			return nil, usageErrorf("column %q is not an entry field; rename it with --map %q, or ignore it with --map %q", h, h+"=<field>", h+"=-")
+		}
+		if seen[field] {
+			return nil, usageErrorf("more than one column imports to %s", field)
+		}
+		seen[field] = true
+		fields[i] = field
+	}
+	if !seen["scientific_name"] {
+		return nil, usageErrorf("no scientific_name column")
+	}
+	return fields, nil
+}
+
+// readCSVRows reads the rows of an import file
+func readCSVRows(data []byte, mappings []string) ([]csvRow, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff")))) // Spreadsheets often add a BOM
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, usageErrorf("the file is empty")
+	}
+	if err != nil {
+		return nil, usageErrorf("failed to parse CSV: %v", err)
+	}
+	fields, err := csvColumnFields(header, mappings)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []csvRow
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, usageErrorf("failed to parse CSV: %v", err)
+		}
+		line, _ := r.FieldPos(0)
+		row := csvRow{Line: line, Cells: map[string]string{}}
+		for i, value := range record {
+			if value = strings.TrimSpace(value); value != "" && fields[i] != "" {
+				row.Cells[fields[i]] = value
+			}
+		}
+		if len(row.Cells) > 0 {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// apply sets entry's fields from the row's cells
+func (row csvRow) apply(entry *models.OakEntry) error {
+	fields := make([]string, 0, len(row.Cells))
+	for field := range row.Cells {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if err := csvFields[field](entry, row.Cells[field]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCSVEntry validates an entry against the schema and its taxa against
+// the reference table
+func checkCSVEntry(database db.Store, validator *schema.Validator, entry *models.OakEntry) error {
+	if err := validator.ValidateOakEntry(entry); err != nil {
+		return err
+	}
+	taxa := []struct {
+		level models.TaxonLevel
+		name  *string
+	}{
+		{models.TaxonLevelSubgenus, entry.Subgenus},
+		{models.TaxonLevelSection, entry.Section},
+		{models.TaxonLevelSubsection, entry.Subsection},
+		{models.TaxonLevelComplex, entry.Complex},
+	}
+	for _, t := range taxa {
+		if t.name == nil || *t.name == "" {
+			continue
+		}
+		ok, err := database.ValidateTaxon(*t.name, t.level)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%s %q is not in the taxa table (add it with 'oak taxa add')", t.level, *t.name)
+		}
+	}
+	return nil
+}
+
+// importCSV imports species entries from CSV data: every row is checked
+// first, and then all are saved in one transaction
+func importCSV(w io.Writer, database db.Store, validator *schema.Validator, data []byte, mappings []string, missingRefs string, dryRun bool) error {
+	rows, err := readCSVRows(data, mappings)
+	if err != nil {
+		return err
+	}
+
+	// Check each row as it would be saved, reporting every failure
+	var rowErrors []string
+	entries := make([]*models.OakEntry, 0, len(rows))
+	byEntry := make(map[*models.OakEntry]csvRow, len(rows))
+	lineOf := make(map[string]int, len(rows))
+	created := 0
+	for _, row := range rows {
+		entry, isNew, err := csvRowEntry(database, validator, row)
+		if err == nil && lineOf[entry.ScientificName] > 0 {
+			err = fmt.Errorf("%s is also on line %d", entry.ScientificName, lineOf[entry.ScientificName])
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, fmt.Sprintf("line %d: %v", row.Line, err))
+			continue
+		}
+		lineOf[entry.ScientificName] = row.Line
+		entries = append(entries, entry)
+		byEntry[entry] = row
+		if isNew {
+			created++
+		}
+	}
+	if len(rowErrors) > 0 {
+		for _, e := range rowErrors {
+			fmt.Fprintln(os.Stderr, e)
+		}
+		return &exitError{code: ExitValidation, err: fmt.Errorf("%d of %d rows failed validation; nothing was imported", len(rowErrors), len(rows))}
+	}
+
+	if dryRun {
+		fmt.Fprintf(w, "%d rows are valid: %d new species, %d updates\n", len(entries), created, len(entries)-created)
+		fmt.Fprintln(w, "Dry run: nothing was imported")
+		return nil
+	}
+
+	// Parents are saved before their hybrids so the links resolve. A failure
+	// undoes the whole import.
+	var report *importReport
+	err = database.WithTx(func(tx db.Store) error {
+		var err, saveErr error
+		report, err = importInDependencyOrder(tx, entries, missingRefs, func(entry *models.OakEntry) {
+			if saveErr != nil {
+				return
+			}
+			if err := tx.SaveOakEntry(entry); err != nil {
+				saveErr = fmt.Errorf("line %d: failed to save %s: %w", byEntry[entry].Line, entry.ScientificName, err)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		return saveErr
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Import complete: %d new species, %d updated\n", created, len(entries)-created)
+	report.print(w)
+	return nil
+}
+
+// csvRowEntry returns the entry a row saves, its cells applied to the
+// stored entry if there is one, and whether it is new
+func csvRowEntry(database db.Store, validator *schema.Validator, row csvRow) (*models.OakEntry, bool, error) {
+	name := names.NormalizeHybridName(row.Cells["scientific_name"])
+	if name == "" {
+		return nil, false, errors.New("scientific_name is empty")
+	}
+	entry, err := database.GetOakEntry(name)
+	if err != nil {
+		return nil, false, err
+	}
+	isNew := entry == nil
+	if isNew {
+		entry = models.NewOakEntry(name)
+		entry.IsHybrid = strings.HasPrefix(name, "× ")
+	}
+	if err := row.apply(entry); err != nil {
+		return nil, false, err
+	}
+	if err := checkCSVEntry(database, validator, entry); err != nil {
+		return nil, false, err
+	}
+	return entry, isNew, nil
+}
+
+func init() {
+	speciesImportCmd.Flags().StringVar(&speciesImportFormat, "format", "csv", "Input format (csv)")
+	speciesImportCmd.Flags().StringArrayVar(&speciesImportMap, "map", nil, "Import a column as a field: header=field, or header=- to ignore it (repeatable)")
+	speciesImportCmd.Flags().BoolVar(&speciesImportDryRun, "dry-run", false, "Check every row without importing")
+	speciesImportCmd.Flags().StringVar(&speciesImportMissingRefs, "missing-refs", missingRefsSkip, "Referenced species not in the file or database: skip, error, or create")
+	speciesCmd.AddCommand(speciesImportCmd)
+}
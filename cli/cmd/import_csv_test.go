@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jeff/oaks/cli/internal/models"
+	"github.com/jeff/oaks/cli/internal/schema"
+)
+
+func TestImportCSV(t *testing.T) {
+	database := testImportDB(t)
+	validator, err := schema.FromFile("../schema/oak_schema.json")
+	if err != nil {
+		t.Fatalf("failed to load schema: %v", err)
+	}
+	if err := database.InsertTaxon(&models.Taxon{Name: "Quercus", Level: models.TaxonLevelSection}); err != nil {
+		t.Fatal(err)
+	}
+	author := "L."
+	if err := database.SaveOakEntry(&models.OakEntry{ScientificName: "alba", Author: &author}); err != nil {
+		t.Fatal(err)
+	}
+
+	// One bad row stops the whole file
+	bad := "Scientific Name,Section,Is Hybrid,Notes\n" +
+		"robur,Quercus,no,\n" +
+		"rubra,Nowhere,no,\n" +
+		"robur,,maybe,\n"
+	err = importCSV(io.Discard, database, validator, []byte(bad), []string{"Notes=-"}, missingRefsSkip, false)
+	var exitErr *exitError
+	if !errors.As(err, &exitErr) || exitErr.code != ExitValidation {
+		t.Fatalf("importCSV(bad rows) = %v, want a validation error", err)
+	}
+	if entry, _ := database.GetOakEntry("robur"); entry != nil {
+		t.Fatal("robur was saved although the file had bad rows")
+	}
+
+	if err := importCSV(io.Discard, database, validator, []byte("species\nrobur\n"), nil, missingRefsSkip, false); err == nil {
+		t.Error("importCSV(unknown column) succeeded, want an error")
+	}
+
+	good := "Species,section,synonyms,parent1,parent2\n" +
+		"x bebbiana,,,alba,robur\n" +
+		"robur,Quercus,Quercus pedunculata; Quercus femina,,\n" +
+		"alba,Quercus,,,\n"
+	mappings := []string{"Species=scientific_name"}
+	if err := importCSV(io.Discard, database, validator, []byte(good), mappings, missingRefsSkip, true); err != nil {
+		t.Fatalf("importCSV(dry run) failed: %v", err)
+	}
+	if entry, _ := database.GetOakEntry("robur"); entry != nil {
+		t.Fatal("a dry run saved robur")
+	}
+
+	if err := importCSV(io.Discard, database, validator, []byte(good), mappings, missingRefsSkip, false); err != nil {
+		t.Fatalf("importCSV failed: %v", err)
+	}
+	robur, _ := database.GetOakEntry("robur")
+	if robur == nil || robur.Section == nil || *robur.Section != "Quercus" || len(robur.Synonyms) != 2 {
+		t.Errorf("robur = %+v, want section Quercus and 2 synonyms", robur)
+	}
+	hybrid, _ := database.GetOakEntry("× bebbiana")
+	if hybrid == nil || !hybrid.IsHybrid || hybrid.Parent2 == nil || *hybrid.Parent2 != "robur" {
+		t.Errorf("× bebbiana = %+v, want a hybrid of alba and robur", hybrid)
+	}
+	// Empty cells leave existing values alone
+	alba, _ := database.GetOakEntry("alba")
+	if alba == nil || alba.Author == nil || *alba.Author != "L." || *alba.Section != "Quercus" {
+		t.Errorf("alba = %+v, want author L. kept and section Quercus added", alba)
+	}
+}
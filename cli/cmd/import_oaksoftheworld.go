@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/jeff/oaks/cli/internal/db"
 	"github.com/jeff/oaks/cli/internal/models"
 )
 
@@ -77,7 +78,10 @@ type ScraperData struct {
 	Species []ScraperSpecies `json:"species"`
 }
 
-var oaksSourceID int64
+var (
+	oaksSourceID    int64
+	oaksMissingRefs string
+)
 
 var importOaksCmd = &cobra.Command{
 	Use:   "import-oaksoftheworld <json-file>",
@@ -92,6 +96,10 @@ Data is split between:
 - oak_entries: species-intrinsic data (taxonomy, conservation status, etc.)
 - species_sources: source-attributed descriptive data (leaves, range, etc.)
 
+Hybrid parents are imported before their hybrids. Parents and closely
+related species that are in neither the file nor the database are handled
+by --missing-refs (skip, error, or create; see import-bulk).
+
 Examples:
   oak import-oaksoftheworld ../quercus_data.json --source-id 2`,
 	Args: cobra.ExactArgs(1),
@@ -101,11 +109,15 @@ Examples:
 func init() {
 	importOaksCmd.Flags().Int64Var(&oaksSourceID, "source-id", 0, "Source ID to attribute the data to (required)")
 	_ = importOaksCmd.MarkFlagRequired("source-id")
+	importOaksCmd.Flags().StringVar(&oaksMissingRefs, "missing-refs", missingRefsSkip, "Referenced species not in the file or database: skip, error, or create")
 	rootCmd.AddCommand(importOaksCmd)
 }
 
 func runImportOaks(cmd *cobra.Command, args []string) error {
 	filePath := args[0]
+	if err := validateMissingRefsPolicy(oaksMissingRefs); err != nil {
+		return err
+	}
 
 	database, err := getDB()
 	if err != nil {
@@ -119,7 +131,7 @@ func runImportOaks(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if source == nil {
-		return fmt.Errorf("source with ID %d not found", oaksSourceID)
+		return notFoundErrorf("source with ID %d not found", oaksSourceID)
 	}
 
 	// Read JSON file
@@ -141,45 +153,67 @@ func runImportOaks(cmd *cobra.Command, args []string) error {
 	sourcesImported := 0
 	errors := 0
 
+	// Keep each entry's scraper record for its species-source data
+	entries := make([]*models.OakEntry, len(scraperData.Species))
+	scraped := make(map[*models.OakEntry]*ScraperSpecies, len(entries))
 	for i := range scraperData.Species {
-		sp := &scraperData.Species[i]
-		// Convert to OakEntry (species-intrinsic data)
-		entry := convertToOakEntry(sp)
-
-		// Check if entry exists
-		existing, err := database.GetOakEntry(entry.ScientificName)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error checking %s: %v\n", entry.ScientificName, err)
-			errors++
-			continue
-		}
-
-		if existing != nil {
-			// Merge with existing entry
-			mergeOaksEntry(existing, entry)
-			if err := database.SaveOakEntry(existing); err != nil {
-				fmt.Fprintf(os.Stderr, "Error updating %s: %v\n", entry.ScientificName, err)
+		entries[i] = convertToOakEntry(&scraperData.Species[i])
+		scraped[entries[i]] = &scraperData.Species[i]
+	}
+
+	// The import is one transaction, so a failure part way leaves the
+	// database as it was. Parents are saved before their hybrids so the links
+	// resolve.
+	var report *importReport
+	err = database.WithTx(func(tx db.Store) error {
+		var err error
+		report, err = importInDependencyOrder(tx, entries, oaksMissingRefs, func(entry *models.OakEntry) {
+			// Each entry is saved with its species source or not at all
+			var updated bool
+			err := tx.WithTx(func(tx db.Store) error {
+				// Check if entry exists
+				existing, err := tx.GetOakEntry(entry.ScientificName)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error checking %s: %v\n", entry.ScientificName, err)
+					return err
+				}
+
+				if existing != nil {
+					// Merge with existing entry
+					mergeOaksEntry(existing, entry)
+					if err := tx.SaveOakEntry(existing); err != nil {
+						fmt.Fprintf(os.Stderr, "Error updating %s: %v\n", entry.ScientificName, err)
+						return err
+					}
+					updated = true
+				} else if err := tx.SaveOakEntry(entry); err != nil {
+					fmt.Fprintf(os.Stderr, "Error inserting %s: %v\n", entry.ScientificName, err)
+					return err
+				}
+
+				// Convert to SpeciesSource (source-attributed data)
+				speciesSource := convertToSpeciesSource(scraped[entry], oaksSourceID)
+				if err := tx.SaveSpeciesSource(speciesSource); err != nil {
+					fmt.Fprintf(os.Stderr, "Error saving species source for %s: %v\n", entry.ScientificName, err)
+					return err
+				}
+				return nil
+			})
+			if err != nil {
 				errors++
-				continue
+				return
 			}
-			entriesUpdated++
-		} else {
-			if err := database.SaveOakEntry(entry); err != nil {
-				fmt.Fprintf(os.Stderr, "Error inserting %s: %v\n", entry.ScientificName, err)
-				errors++
-				continue
+			if updated {
+				entriesUpdated++
+			} else {
+				entriesImported++
 			}
-			entriesImported++
-		}
-
-		// Convert to SpeciesSource (source-attributed data)
-		speciesSource := convertToSpeciesSource(sp, oaksSourceID)
-		if err := database.SaveSpeciesSource(speciesSource); err != nil {
-			fmt.Fprintf(os.Stderr, "Error saving species source for %s: %v\n", entry.ScientificName, err)
-			errors++
-			continue
-		}
-		sourcesImported++
+			sourcesImported++
+		})
+		return err
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("\nImport complete:\n")
@@ -187,6 +221,7 @@ func runImportOaks(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Updated entries:  %d\n", entriesUpdated)
 	fmt.Printf("  Species sources:  %d\n", sourcesImported)
 	fmt.Printf("  Errors:           %d\n", errors)
+	report.print(os.Stdout)
 
 	return nil
 }
@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jeff/oaks/cli/internal/db"
+	"github.com/jeff/oaks/cli/internal/models"
+)
+
+// Policies for species an import refers to (as a hybrid parent or closely
+// related species) that are in neither the file nor the database
+const (
+	missingRefsSkip   = "skip"   // Drop the reference and report it
+	missingRefsError  = "error"  // Refuse the import before anything is written
+	missingRefsCreate = "create" // Create a draft placeholder flagged needs_review
+)
+
+// importReport collects references an import could not resolve.
+type importReport struct {
+	Deferred     int                 // Hybrids saved after a retry because a parent was not yet in the database
+	Unlinked     map[string][]string // Hybrid -> parents that never appeared; saved anyway
+	MissingTaxa  map[string][]string // "level name" -> species referring to a taxon not in the database
+	Dropped      map[string][]string // Species -> missing references removed under the skip policy
+	Placeholders []string            // Placeholder entries created under the create policy
+	NeedsReview  int                 // Placeholders in the database still needing review
+}
+
+// validateMissingRefsPolicy checks a --missing-refs flag value.
+func validateMissingRefsPolicy(policy string) error {
+	switch policy {
+	case missingRefsSkip, missingRefsError, missingRefsCreate:
+		return nil
+	}
+	return usageErrorf("invalid --missing-refs %q (use skip, error, or create)", policy)
+}
+
+// hybridParents returns an entry's non-empty parent names.
+func hybridParents(entry *models.OakEntry) []string {
+	var parents []string
+	for _, p := range []*string{entry.Parent1, entry.Parent2} {
+		if p != nil && *p != "" && *p != entry.ScientificName {
+			parents = append(parents, *p)
+		}
+	}
+	return parents
+}
+
+// orderImportEntries orders entries so that a hybrid comes after any of its
+// parents in the same batch. Otherwise the input order is kept. Entries whose
+// parents form a cycle are placed last, in input order.
+func orderImportEntries(entries []*models.OakEntry) []*models.OakEntry {
+	index := make(map[string]int, len(entries))
+	for i, e := range entries {
+		if _, ok := index[e.ScientificName]; !ok {
+			index[e.ScientificName] = i
+		}
+	}
+
+	// Count each entry's unsaved in-batch parents; track who waits on whom
+	waiting := make([]int, len(entries))
+	children := make(map[int][]int)
+	for i, e := range entries {
+		for _, p := range hybridParents(e) {
+			if j, ok := index[p]; ok && j != i {
+				waiting[i]++
+				children[j] = append(children[j], i)
+			}
+		}
+	}
+
+	ordered := make([]*models.OakEntry, 0, len(entries))
+	placed := make([]bool, len(entries))
+	for progress := true; progress; {
+		progress = false
+		for i, e := range entries {
+			if placed[i] || waiting[i] > 0 {
+				continue
+			}
+			ordered = append(ordered, e)
+			placed[i] = true
+			progress = true
+			for _, c := range children[i] {
+				waiting[c]--
+			}
+		}
+	}
+	for i, e := range entries {
+		if !placed[i] {
+			ordered = append(ordered, e)
+		}
+	}
+	return ordered
+}
+
+// importInDependencyOrder calls save for each entry, parents before hybrids.
+// Parents and closely related species in neither the batch nor the database
+// are first handled by policy (see missingRefs*). A hybrid whose parent is not
+// in the database when its turn comes is deferred and retried once the rest
+// of the batch is saved. Hybrids whose parents never appear (say, a parent in
+// the batch failed to save) are saved last and reported as unlinked. Taxonomy
+// that refers to taxa missing from the database is reported but not blocked.
+func importInDependencyOrder(database db.Store, entries []*models.OakEntry, policy string, save func(*models.OakEntry)) (*importReport, error) {
+	report := &importReport{Unlinked: map[string][]string{}, MissingTaxa: map[string][]string{}, Dropped: map[string][]string{}}
+
+	if err := resolveMissingRefs(database, entries, policy, report); err != nil {
+		return nil, err
+	}
+
+	taxa := make(map[string]bool)
+	for _, e := range entries {
+		refs := []struct {
+			level models.TaxonLevel
+			name  *string
+		}{
+			{models.TaxonLevelSubgenus, e.Subgenus},
+			{models.TaxonLevelSection, e.Section},
+			{models.TaxonLevelSubsection, e.Subsection},
+			{models.TaxonLevelComplex, e.Complex},
+		}
+		for _, ref := range refs {
+			if ref.name == nil || *ref.name == "" {
+				continue
+			}
+			key := string(ref.level) + " " + *ref.name
+			known, checked := taxa[key]
+			if !checked {
+				taxon, err := database.GetTaxon(*ref.name, ref.level)
+				if err != nil {
+					return nil, err
+				}
+				known = taxon != nil
+				taxa[key] = known
+			}
+			if !known {
+				report.MissingTaxa[key] = append(report.MissingTaxa[key], e.ScientificName)
+			}
+		}
+	}
+
+	missingParents := func(e *models.OakEntry) ([]string, error) {
+		var missing []string
+		for _, p := range hybridParents(e) {
+			exists, err := database.OakEntryExists(p)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				missing = append(missing, p)
+			}
+		}
+		return missing, nil
+	}
+
+	pending := orderImportEntries(entries)
+	for retry := false; len(pending) > 0; retry = true {
+		var deferred []*models.OakEntry
+		for _, e := range pending {
+			missing, err := missingParents(e)
+			if err != nil {
+				return nil, err
+			}
+			if len(missing) > 0 {
+				deferred = append(deferred, e)
+				continue
+			}
+			if retry {
+				report.Deferred++
+			}
+			save(e)
+		}
+
+		if len(deferred) == len(pending) {
+			// No progress: these parents are not coming
+			for _, e := range deferred {
+				missing, err := missingParents(e)
+				if err != nil {
+					return nil, err
+				}
+				report.Unlinked[e.ScientificName] = missing
+				save(e)
+			}
+			break
+		}
+		pending = deferred
+	}
+
+	needsReview, err := database.ListNeedsReview()
+	if err != nil {
+		return nil, err
+	}
+	report.NeedsReview = len(needsReview)
+
+	return report, nil
+}
+
+// resolveMissingRefs applies the missing-reference policy to entries' hybrid
+// parents and closely related species.
+func resolveMissingRefs(database db.Store, entries []*models.OakEntry, policy string, report *importReport) error {
+	inBatch := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		inBatch[e.ScientificName] = true
+	}
+
+	known := make(map[string]bool)
+	isMissing := func(name string) (bool, error) {
+		if inBatch[name] {
+			return false, nil
+		}
+		exists, checked := known[name]
+		if !checked {
+			var err error
+			if exists, err = database.OakEntryExists(name); err != nil {
+				return false, err
+			}
+			known[name] = exists
+		}
+		return !exists, nil
+	}
+
+	// Collect every missing name, per entry, before changing anything
+	missing := make(map[*models.OakEntry][]string)
+	var names []string
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		refs := append(hybridParents(e), e.CloselyRelatedTo...)
+		checked := make(map[string]bool, len(refs))
+		for _, name := range refs {
+			if name == "" || name == e.ScientificName || checked[name] {
+				continue
+			}
+			checked[name] = true
+			m, err := isMissing(name)
+			if err != nil {
+				return err
+			}
+			if !m {
+				continue
+			}
+			missing[e] = append(missing[e], name)
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	switch policy {
+	case missingRefsError:
+		return &exitError{code: ExitValidation, err: fmt.Errorf(
+			"import refers to %d species not in the file or database: %s (use --missing-refs skip or create)",
+			len(names), strings.Join(names, ", "))}
+
+	case missingRefsCreate:
+		for _, name := range names {
+			created, err := database.CreatePlaceholderEntry(name)
+			if err != nil {
+				return err
+			}
+			if created {
+				report.Placeholders = append(report.Placeholders, name)
+			}
+		}
+
+	default:
+		for e, refs := range missing {
+			drop := make(map[string]bool, len(refs))
+			for _, name := range refs {
+				drop[name] = true
+			}
+			if e.Parent1 != nil && drop[*e.Parent1] {
+				e.Parent1 = nil
+			}
+			if e.Parent2 != nil && drop[*e.Parent2] {
+				e.Parent2 = nil
+			}
+			related := make([]string, 0, len(e.CloselyRelatedTo))
+			for _, name := range e.CloselyRelatedTo {
+				if !drop[name] {
+					related = append(related, name)
+				}
+			}
+			e.CloselyRelatedTo = related
+			report.Dropped[e.ScientificName] = refs
+		}
+	}
+	return nil
+}
+
+// print writes the report's unresolved references, if any.
+func (r *importReport) print(w io.Writer) {
+	if r.Deferred > 0 {
+		fmt.Fprintf(w, "Deferred %d hybrid(s) until their parents were imported\n", r.Deferred)
+	}
+	if len(r.Unlinked) > 0 {
+		fmt.Fprintf(w, "\nHybrids saved with parents missing from the file and database:\n")
+		for _, name := range sortedKeys(r.Unlinked) {
+			fmt.Fprintf(w, "  %s: missing %s\n", name, strings.Join(r.Unlinked[name], ", "))
+		}
+	}
+	if len(r.Dropped) > 0 {
+		fmt.Fprintf(w, "\nReferences dropped (species not in the file or database):\n")
+		for _, name := range sortedKeys(r.Dropped) {
+			fmt.Fprintf(w, "  %s: %s\n", name, strings.Join(r.Dropped[name], ", "))
+		}
+	}
+	if len(r.Placeholders) > 0 {
+		fmt.Fprintf(w, "\nCreated %d draft placeholder(s) flagged needs_review: %s\n",
+			len(r.Placeholders), strings.Join(r.Placeholders, ", "))
+	}
+	if r.NeedsReview > 0 {
+		fmt.Fprintf(w, "%d placeholder entries in the database need review\n", r.NeedsReview)
+	}
+	if len(r.MissingTaxa) > 0 {
+		fmt.Fprintf(w, "\nTaxa not in the database (import them first with 'oak taxa import'):\n")
+		for _, key := range sortedKeys(r.MissingTaxa) {
+			fmt.Fprintf(w, "  %s: used by %s\n", key, strings.Join(r.MissingTaxa[key], ", "))
+		}
+	}
+}
+
+// sortedKeys returns a map's keys in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jeff/oaks/cli/internal/db"
+	"github.com/jeff/oaks/cli/internal/models"
+)
+
+func hybridEntry(name, parent1, parent2 string) *models.OakEntry {
+	entry := &models.OakEntry{ScientificName: name, IsHybrid: true}
+	if parent1 != "" {
+		entry.Parent1 = &parent1
+	}
+	if parent2 != "" {
+		entry.Parent2 = &parent2
+	}
+	return entry
+}
+
+func entryNames(entries []*models.OakEntry) []string {
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.ScientificName
+	}
+	return names
+}
+
+func TestOrderImportEntries(t *testing.T) {
+	entries := []*models.OakEntry{
+		hybridEntry("x bebbiana", "alba", "macrocarpa"),
+		{ScientificName: "rubra"},
+		hybridEntry("x schuettei", "x bebbiana", "bicolor"), // Hybrid of a hybrid
+		{ScientificName: "macrocarpa"},
+		{ScientificName: "alba"},
+		hybridEntry("x loopy", "x knot", ""),
+		hybridEntry("x knot", "x loopy", ""),
+	}
+
+	got := entryNames(orderImportEntries(entries))
+	want := []string{"rubra", "macrocarpa", "alba", "x bebbiana", "x schuettei", "x loopy", "x knot"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("orderImportEntries() = %v, want %v", got, want)
+	}
+}
+
+func testImportDB(t *testing.T) *db.Database {
+	t.Helper()
+	database, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func dependencyTestEntries() []*models.OakEntry {
+	lobatae := "Lobatae"
+	return []*models.OakEntry{
+		hybridEntry("x bebbiana", "alba", "macrocarpa"),
+		hybridEntry("x orphan", "alba", "nowhere"),
+		{ScientificName: "alba", Section: &lobatae, CloselyRelatedTo: []string{"macrocarpa", "elsewhere"}},
+		{ScientificName: "macrocarpa"},
+	}
+}
+
+func TestImportInDependencyOrder(t *testing.T) {
+	database := testImportDB(t)
+
+	// macrocarpa fails to save, as if skipped on a conflict
+	var attempted []string
+	report, err := importInDependencyOrder(database, dependencyTestEntries(), missingRefsSkip, func(e *models.OakEntry) {
+		attempted = append(attempted, e.ScientificName)
+		if e.ScientificName == "macrocarpa" {
+			return
+		}
+		if err := database.SaveOakEntry(e); err != nil {
+			t.Fatalf("SaveOakEntry(%s) failed: %v", e.ScientificName, err)
+		}
+	})
+	if err != nil {
+		t.Fatalf("importInDependencyOrder() error = %v", err)
+	}
+
+	want := []string{"alba", "macrocarpa", "x orphan", "x bebbiana"}
+	if !reflect.DeepEqual(attempted, want) {
+		t.Errorf("saved %v, want %v", attempted, want)
+	}
+	if !reflect.DeepEqual(report.Unlinked, map[string][]string{"x bebbiana": {"macrocarpa"}}) {
+		t.Errorf("Unlinked = %v, want x bebbiana missing macrocarpa", report.Unlinked)
+	}
+	if !reflect.DeepEqual(report.MissingTaxa, map[string][]string{"section Lobatae": {"alba"}}) {
+		t.Errorf("MissingTaxa = %v, want section Lobatae used by alba", report.MissingTaxa)
+	}
+	wantDropped := map[string][]string{"x orphan": {"nowhere"}, "alba": {"elsewhere"}}
+	if !reflect.DeepEqual(report.Dropped, wantDropped) {
+		t.Errorf("Dropped = %v, want %v", report.Dropped, wantDropped)
+	}
+
+	// The parent saved first lists its hybrids; dropped references are not stored
+	alba, err := database.GetOakEntry("alba")
+	if err != nil {
+		t.Fatalf("GetOakEntry failed: %v", err)
+	}
+	if !reflect.DeepEqual(alba.Hybrids, []string{"x orphan", "x bebbiana"}) {
+		t.Errorf("alba hybrids = %v, want [x orphan x bebbiana]", alba.Hybrids)
+	}
+	if !reflect.DeepEqual(alba.CloselyRelatedTo, []string{"macrocarpa"}) {
+		t.Errorf("alba closely_related_to = %v, want [macrocarpa]", alba.CloselyRelatedTo)
+	}
+	orphan, err := database.GetOakEntry("x orphan")
+	if err != nil {
+		t.Fatalf("GetOakEntry failed: %v", err)
+	}
+	if orphan.Parent2 != nil {
+		t.Errorf("x orphan parent2 = %q, want dropped", *orphan.Parent2)
+	}
+}
+
+func TestImportMissingRefsPolicies(t *testing.T) {
+	save := func(database *db.Database) func(*models.OakEntry) {
+		return func(e *models.OakEntry) {
+			if err := database.SaveOakEntry(e); err != nil {
+				t.Fatalf("SaveOakEntry(%s) failed: %v", e.ScientificName, err)
+			}
+		}
+	}
+
+	// error refuses the import before anything is written
+	database := testImportDB(t)
+	_, err := importInDependencyOrder(database, dependencyTestEntries(), missingRefsError, save(database))
+	if err == nil || ExitCode(err) != ExitValidation {
+		t.Fatalf("error policy = %v, want a validation error", err)
+	}
+	if exists, _ := database.OakEntryExists("alba"); exists {
+		t.Error("error policy wrote alba, want nothing written")
+	}
+
+	// create adds draft placeholders flagged needs_review
+	database = testImportDB(t)
+	report, err := importInDependencyOrder(database, dependencyTestEntries(), missingRefsCreate, save(database))
+	if err != nil {
+		t.Fatalf("create policy error = %v", err)
+	}
+	if !reflect.DeepEqual(report.Placeholders, []string{"nowhere", "elsewhere"}) || report.NeedsReview != 2 {
+		t.Errorf("Placeholders = %v, NeedsReview = %d, want [nowhere elsewhere], 2", report.Placeholders, report.NeedsReview)
+	}
+	nowhere, err := database.GetOakEntry("nowhere")
+	if err != nil || nowhere == nil {
+		t.Fatalf("GetOakEntry(nowhere) = %v, %v, want placeholder", nowhere, err)
+	}
+	if !reflect.DeepEqual(nowhere.Hybrids, []string{"x orphan"}) {
+		t.Errorf("placeholder hybrids = %v, want [x orphan]", nowhere.Hybrids)
+	}
+
+	if err := validateMissingRefsPolicy("ignore"); ExitCode(err) != ExitUsage {
+		t.Errorf("validateMissingRefsPolicy(ignore) = %v, want usage error", err)
+	}
+}
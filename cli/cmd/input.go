@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+// Request body passthrough for the create and edit commands: with --file,
+// the file is sent to the API unchanged instead of opening $EDITOR.
+var (
+	inputFile   string
+	inputFormat string
+)
+
+// addInputFlags registers --file and --input on a create or edit command.
+func addInputFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&inputFile, "file", "f", "", "Send this file as the request body instead of opening $EDITOR (- for stdin)")
+	cmd.Flags().StringVar(&inputFormat, "input", "", "Format of --file: json or yaml (default from the file extension, else json)")
+}
+
+// readInputBody reads the --file request body, or returns nil without --file.
+func readInputBody() (*oakclient.RawBody, error) {
+	if inputFile == "" {
+		if inputFormat != "" {
+			return nil, usageErrorf("--input needs --file")
+		}
+		return nil, nil
+	}
+
+	contentType, err := inputContentType(inputFormat, inputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if inputFile == "-" {
+		if data, err = io.ReadAll(os.Stdin); err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+	} else if data, err = readImportFile(inputFile); err != nil {
+		return nil, err
+	}
+	return &oakclient.RawBody{Data: data, ContentType: contentType}, nil
+}
+
+// sendInputBody sends a --file request body and decodes the response into out.
+func sendInputBody(ctx context.Context, apiClient *oakclient.Client, method, path string, body *oakclient.RawBody, out interface{}) error {
+	data, err := apiClient.Raw(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// inputContentType resolves the --input format to a request Content-Type
+func inputContentType(format, file string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".yaml", ".yml":
+			format = "yaml"
+		default:
+			format = "json"
+		}
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		return oakclient.ContentTypeJSON, nil
+	case "yaml", "yml":
+		return oakclient.ContentTypeYAML, nil
+	default:
+		return "", usageErrorf("invalid --input %q (must be json or yaml)", format)
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+func TestReadInputBody(t *testing.T) {
+	defer func() { inputFile, inputFormat = "", "" }()
+
+	path := filepath.Join(t.TempDir(), "alba.yml")
+	if err := os.WriteFile(path, []byte("scientific_name: alba\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		format   string
+		wantType string
+		wantCode int
+	}{
+		{"no file", "", "", "", ExitOK},
+		{"type from extension", path, "", oakclient.ContentTypeYAML, ExitOK},
+		{"explicit format", path, "json", oakclient.ContentTypeJSON, ExitOK},
+		{"bad format", path, "toml", "", ExitUsage},
+		{"format without file", "", "yaml", "", ExitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputFile, inputFormat = tt.file, tt.format
+			body, err := readInputBody()
+			if code := ExitCode(err); code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d (err %v)", code, tt.wantCode, err)
+			}
+			switch {
+			case tt.wantType == "" && body != nil:
+				t.Errorf("body = %+v, want nil", body)
+			case tt.wantType != "" && (body == nil || body.ContentType != tt.wantType):
+				t.Errorf("body = %+v, want content type %s", body, tt.wantType)
+			case body != nil && string(body.Data) != "scientific_name: alba\n":
+				t.Errorf("data = %q, want the file unchanged", body.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var keyUsageDays int
+
+var keyRole string
+
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage API keys and show their usage",
+	Long: `Besides the server's configured key, which can do everything, the API
+accepts keys created for collaborators, each with a role:
+
+  read     authenticated reads: drafts, internal fields, curator queues
+  editor   reads and writes to the data
+  admin    everything, including administration and managing keys
+
+The API counts authenticated requests per key, by a fingerprint ID that
+does not reveal the key, and records when each key was last used. Revoked
+keys, and keys the server no longer accepts, stay listed while their usage
+is kept, so a key that has gone quiet stands out before it is revoked.
+
+Managing keys and viewing their usage needs an admin key.`,
+}
+
+var keysCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create an API key for a collaborator",
+	Long: `Create an API key with a name, to tell it apart later, and a role. The
+key is printed once and cannot be shown again; the server keeps only its
+hash.
+
+Examples:
+  oak keys create "Field team" --role editor --remote
+  oak keys create "Reviewer" --role read --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: runKeysCreate,
+}
+
+var keysRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Revoke a created API key",
+	Long: `Revoke a created API key by the ID 'oak keys list' shows. Requests with it
+fail from then on; its usage stays listed. The server's configured key
+cannot be revoked, only replaced where the server reads it.
+
+Examples:
+  oak keys revoke 0123456789ab --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: runKeysRevoke,
+}
+
+var keysListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API keys with request counts and last use",
+	Long: `List the server's current API key, every created key, and every key with
+recorded usage: current first, then most recently used, then created keys
+never used.
+
+Examples:
+  oak keys list --remote`,
+	Args: cobra.NoArgs,
+	RunE: runKeysList,
+}
+
+var keysUsageCmd = &cobra.Command{
+	Use:   "usage <id>",
+	Short: "Show a key's requests per day",
+	Long: `Show an API key's total requests, first and last use, and its requests
+per day over the last --days days.
+
+Examples:
+  oak keys usage 4c806362b613 --remote
+  oak keys usage 4c806362b613 --days 90 --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: runKeysUsage,
+}
+
+func init() {
+	keysUsageCmd.Flags().IntVar(&keyUsageDays, "days", 30, "Days of daily counts to show")
+	keysCreateCmd.Flags().StringVar(&keyRole, "role", "", "Role of the key: read, editor, or admin")
+	_ = keysCreateCmd.MarkFlagRequired("role")
+
+	keysCmd.AddCommand(keysListCmd)
+	keysCmd.AddCommand(keysUsageCmd)
+	keysCmd.AddCommand(keysCreateCmd)
+	keysCmd.AddCommand(keysRevokeCmd)
+	rootCmd.AddCommand(keysCmd)
+}
+
+func runKeysList(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	keys, err := apiClient.ListAPIKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tREQUESTS\tFIRST USED\tLAST USED")
+	fmt.Fprintln(w, "--\t----\t------\t--------\t----------\t---------")
+	for _, k := range keys {
+		name := k.Name
+		if name == "" {
+			name = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", k.ID, name, keyStatus(k), k.Requests, derefOr(k.FirstUsed, "-"), lastUsed(k))
+	}
+	w.Flush()
+	return nil
+}
+
+func runKeysUsage(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	if keyUsageDays < 1 {
+		return usageErrorf("--days must be at least 1")
+	}
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	usage, err := apiClient.GetAPIKeyUsage(ctx, args[0], keyUsageDays)
+	if oakclient.IsNotFoundError(err) {
+		return notFoundErrorf("no API key with ID %s", args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	fmt.Printf("Key:        %s (%s)\n", usage.ID, keyStatus(usage))
+	if usage.Name != "" {
+		fmt.Printf("Name:       %s\n", usage.Name)
+	}
+	fmt.Printf("Requests:   %d\n", usage.Requests)
+	fmt.Printf("First used: %s\n", derefOr(usage.FirstUsed, "never"))
+	fmt.Printf("Last used:  %s\n", lastUsed(usage))
+	if len(usage.Days) == 0 {
+		fmt.Printf("No requests in the last %d days\n", keyUsageDays)
+		return nil
+	}
+	fmt.Println()
+	for _, d := range usage.Days {
+		fmt.Printf("  %s  %6d\n", d.Day, d.Requests)
+	}
+	return nil
+}
+
+func runKeysCreate(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	name := strings.TrimSpace(args[0])
+	if name == "" {
+		return usageErrorf("key name is empty")
+	}
+	if !slices.Contains([]string{oakclient.RoleRead, oakclient.RoleEditor, oakclient.RoleAdmin}, keyRole) {
+		return usageErrorf("--role must be read, editor, or admin")
+	}
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+	if isActualRemote() && !confirmRemoteOperation("Create a "+keyRole+" API key on", apiClient.ProfileName()) {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	key, err := apiClient.CreateAPIKey(ctx, name, keyRole)
+	if err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Created %s key %s (%s). It will not be shown again:\n", key.Role, key.ID, key.Name)
+	fmt.Println(key.Key)
+	return nil
+}
+
+func runKeysRevoke(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+	if isActualRemote() && !confirmRemoteOperation("Revoke API key", args[0]) {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	err = apiClient.DeleteAPIKey(ctx, args[0])
+	if oakclient.IsNotFoundError(err) {
+		return notFoundErrorf("no created API key with ID %s", args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	fmt.Printf("Revoked API key %s\n", args[0])
+	return nil
+}
+
+// keyStatus describes whether the server still accepts a key, and its role
+func keyStatus(k *oakclient.APIKeyUsage) string {
+	switch {
+	case k.Current:
+		return "current (admin)"
+	case k.Role != "":
+		return k.Role
+	}
+	return "retired"
+}
+
+// lastUsed formats a key's last use with how long ago it was
+func lastUsed(k *oakclient.APIKeyUsage) string {
+	if k.LastUsedAt == nil {
+		return "never"
+	}
+	days := int(time.Since(*k.LastUsedAt).Hours() / 24)
+	return fmt.Sprintf("%s (%d days ago)", k.LastUsedAt.Local().Format("2006-01-02 15:04"), days)
+}
@@ -2,13 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"net/http"
+	"os"
 
 	"github.com/spf13/cobra"
 
-	"github.com/jeff/oaks/cli/internal/client"
 	"github.com/jeff/oaks/cli/internal/editor"
 	"github.com/jeff/oaks/cli/internal/models"
 	"github.com/jeff/oaks/cli/internal/names"
+	"github.com/jeff/oaks/pkg/oakclient"
 )
 
 var newCmd = &cobra.Command{
@@ -22,7 +24,11 @@ creating. Local operations (default or --local) proceed without confirmation.
 Examples:
   oak new alba             # Create in local database
   oak new alba --remote    # Create on remote API (with confirmation)
-  oak new alba --local     # Force local creation`,
+  oak new alba --local     # Force local creation
+  oak new alba --draft     # Create as a draft, hidden until 'oak species publish'
+  oak new ovata --genus Carya
+  oak new velutina --template lobatae  # Pre-fill from a template (see 'oak templates')
+  oak new alba --file alba.yaml        # Send a curated YAML file as-is`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := names.NormalizeHybridName(args[0])
@@ -30,11 +36,35 @@ Examples:
 	},
 }
 
+var (
+	newDraft    bool
+	newGenus    string
+	newTemplate string
+)
+
 func init() {
+	addNewFlags(newCmd)
 	rootCmd.AddCommand(newCmd)
 }
 
+// addNewFlags registers the entry creation flags shared by 'oak new' and 'oak species new'.
+func addNewFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&newDraft, "draft", false, "Create the entry as a draft visible only to curators")
+	cmd.Flags().StringVar(&newGenus, "genus", "", "Genus for the entry (default Quercus; see 'oak genera list')")
+	cmd.Flags().StringVar(&newTemplate, "template", "", "Pre-fill taxonomy, sources, and required fields from a template")
+	addInputFlags(cmd)
+}
+
 func runNew(name string) error {
+	ctx := commandContext()
+	body, err := readInputBody()
+	if err != nil {
+		return err
+	}
+	if body != nil && (newDraft || newGenus != "" || newTemplate != "") {
+		return usageErrorf("--draft, --genus, and --template cannot be used with --file; set them in the file")
+	}
+
 	apiClient, err := getAPIClient()
 	if err != nil {
 		return err
@@ -42,29 +72,58 @@ func runNew(name string) error {
 
 	// Verify auth before doing any work (only for actual remote servers)
 	if isActualRemote() {
-		if err := apiClient.VerifyAuth(); err != nil {
+		if err := apiClient.VerifyAuth(ctx); err != nil {
 			return fmt.Errorf("authentication failed: %w", err)
 		}
 	}
 
-	validator, err := getSchema()
-	if err != nil {
-		return err
-	}
-
 	// Check if entry already exists
-	_, err = apiClient.GetSpecies(name)
+	_, err = apiClient.GetSpecies(ctx, name)
 	if err == nil {
 		if isActualRemote() {
 			return fmt.Errorf("oak entry '%s' already exists on [%s]. Use 'oak edit' to modify it", name, apiClient.ProfileName())
 		}
 		return fmt.Errorf("oak entry '%s' already exists. Use 'oak edit' to modify it", name)
 	}
-	if !client.IsNotFoundError(err) {
+	if !oakclient.IsNotFoundError(err) {
 		return fmt.Errorf("failed to check existing entry: %w", err)
 	}
 
-	entry, err := editor.NewOakEntry(name, validator)
+	if body != nil {
+		if isActualRemote() && !confirmRemoteOperation("Create", name) {
+			fmt.Println("Canceled")
+			return nil
+		}
+		var created oakclient.OakEntry
+		if err := sendInputBody(ctx, apiClient, http.MethodPost, "/api/v1/species", body, &created); err != nil {
+			return fmt.Errorf("failed to create entry: %w", err)
+		}
+		fmt.Printf("Created oak entry: %s\n", created.ScientificName)
+		return nil
+	}
+
+	validator, err := getSchema()
+	if err != nil {
+		return err
+	}
+
+	var tmpl *oakclient.Template
+	if newTemplate != "" {
+		tmpl, err = apiClient.GetTemplate(ctx, newTemplate)
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("template '%s' not found", newTemplate)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+	}
+
+	var entry *models.OakEntry
+	if tmpl != nil {
+		entry, err = editor.NewOakEntryFromTemplate(templateEntry(name, tmpl), tmpl.RequiredFields, validator)
+	} else {
+		entry, err = editor.NewOakEntry(name, validator)
+	}
 	if err != nil {
 		return err
 	}
@@ -77,11 +136,30 @@ func runNew(name string) error {
 
 	// Convert to API request and create
 	req := modelToSpeciesRequest(entry)
-	_, err = apiClient.CreateSpecies(req)
+	if newDraft {
+		req.Visibility = models.VisibilityDraft
+	}
+	if newGenus != "" && req.Genus == "" {
+		req.Genus = newGenus
+	}
+	if tmpl != nil && req.Genus == "" {
+		req.Genus = tmpl.Genus
+	}
+	_, err = apiClient.CreateSpecies(ctx, req)
 	if err != nil {
 		return fmt.Errorf("failed to create entry: %w", err)
 	}
 
+	// Attach the template's default sources; the first becomes preferred
+	if tmpl != nil {
+		for i, sourceID := range tmpl.SourceIDs {
+			ss := &oakclient.SpeciesSource{SourceID: sourceID, IsPreferred: i == 0}
+			if _, err := apiClient.CreateSpeciesSource(ctx, entry.ScientificName, ss); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to attach source %d: %v\n", sourceID, err)
+			}
+		}
+	}
+
 	if isActualRemote() {
 		fmt.Printf("Created oak entry on [%s]: %s\n", apiClient.ProfileName(), entry.ScientificName)
 	} else {
@@ -90,13 +168,26 @@ func runNew(name string) error {
 	return nil
 }
 
+// templateEntry builds the starting entry for a new species from a template.
+func templateEntry(name string, tmpl *oakclient.Template) *models.OakEntry {
+	entry := models.NewOakEntry(name)
+	entry.Genus = tmpl.Genus
+	entry.Subgenus = tmpl.Subgenus
+	entry.Section = tmpl.Section
+	entry.Subsection = tmpl.Subsection
+	entry.Complex = tmpl.Complex
+	return entry
+}
+
 // modelToSpeciesRequest converts an internal OakEntry to an API SpeciesRequest.
-func modelToSpeciesRequest(e *models.OakEntry) *client.SpeciesRequest {
-	return &client.SpeciesRequest{
+func modelToSpeciesRequest(e *models.OakEntry) *oakclient.SpeciesRequest {
+	return &oakclient.SpeciesRequest{
 		ScientificName:     e.ScientificName,
 		Author:             e.Author,
+		Pronunciation:      e.Pronunciation,
 		IsHybrid:           e.IsHybrid,
 		ConservationStatus: e.ConservationStatus,
+		Genus:              e.Genus,
 		Subgenus:           e.Subgenus,
 		Section:            e.Section,
 		Subsection:         e.Subsection,
@@ -104,16 +195,20 @@ func modelToSpeciesRequest(e *models.OakEntry) *client.SpeciesRequest {
 		Parent1:            e.Parent1,
 		Parent2:            e.Parent2,
 		Synonyms:           e.Synonyms,
+		Visibility:         e.Visibility,
+		ExternalLinks:      modelLinksToClient(e.ExternalLinks),
 	}
 }
 
 // clientEntryToModel converts an API OakEntry to an internal OakEntry.
-func clientEntryToModel(e *client.OakEntry) *models.OakEntry {
+func clientEntryToModel(e *oakclient.OakEntry) *models.OakEntry {
 	return &models.OakEntry{
 		ScientificName:      e.ScientificName,
 		Author:              e.Author,
+		Pronunciation:       e.Pronunciation,
 		IsHybrid:            e.IsHybrid,
 		ConservationStatus:  e.ConservationStatus,
+		Genus:               e.Genus,
 		Subgenus:            e.Subgenus,
 		Section:             e.Section,
 		Subsection:          e.Subsection,
@@ -125,11 +220,28 @@ func clientEntryToModel(e *client.OakEntry) *models.OakEntry {
 		SubspeciesVarieties: e.SubspeciesVarieties,
 		Synonyms:            e.Synonyms,
 		ExternalLinks:       clientLinksToModel(e.ExternalLinks),
+		Visibility:          e.Visibility,
 	}
 }
 
+// modelLinksToClient converts internal ExternalLinks to API ExternalLinks.
+func modelLinksToClient(links []models.ExternalLink) []oakclient.ExternalLink {
+	if links == nil {
+		return nil
+	}
+	result := make([]oakclient.ExternalLink, len(links))
+	for i, l := range links {
+		result[i] = oakclient.ExternalLink{
+			Name: l.Name,
+			URL:  l.URL,
+			Logo: l.Logo,
+		}
+	}
+	return result
+}
+
 // clientLinksToModel converts API ExternalLinks to internal ExternalLinks.
-func clientLinksToModel(links []client.ExternalLink) []models.ExternalLink {
+func clientLinksToModel(links []oakclient.ExternalLink) []models.ExternalLink {
 	if links == nil {
 		return nil
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/jeff/oaks/cli/internal/editor"
 	"github.com/jeff/oaks/cli/internal/models"
 	"github.com/jeff/oaks/cli/internal/names"
+	"github.com/jeff/oaks/pkg/oakclient"
 )
 
 var (
@@ -33,6 +34,11 @@ be loaded for editing. Otherwise, a new blank template is created.
 The species must already exist in the database. Use 'oak new' first
 to create the species entry if needed.
 
+If someone else saves the same notes while you are editing them, nothing
+is overwritten: they are merged with yours as in 'oak edit', and fields
+you both changed open in $EDITOR to resolve. Canceling the merge writes
+your edits to a file and exits with code 5.
+
 Examples:
   oak note phellos --source-id 3
   oak note "× bebbiana" --source-id 2`,
@@ -74,42 +80,53 @@ func init() {
 }
 
 func runNote(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
 	speciesName := names.NormalizeHybridName(args[0])
+	cmd.SilenceUsage = true // Failures from here on are about the records, not the arguments
 
-	database, err := getDB()
+	apiClient, err := getAPIClient()
 	if err != nil {
 		return err
 	}
-	defer database.Close()
 
-	// Verify species exists
-	entry, err := database.GetOakEntry(speciesName)
-	if err != nil {
-		return err
+	// Verify auth before doing any work (only for actual remote servers)
+	if isActualRemote() {
+		if err := apiClient.VerifyAuth(ctx); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
 	}
-	if entry == nil {
-		return fmt.Errorf("species '%s' not found. Create it first with: oak new %s", speciesName, speciesName)
+
+	// Verify species exists
+	if _, err := apiClient.GetSpecies(ctx, speciesName); err != nil {
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("species '%s' not found. Create it first with: oak new %s", speciesName, speciesName)
+		}
+		return fmt.Errorf("failed to fetch species: %w", err)
 	}
 
 	// Verify source exists
-	source, err := database.GetSource(noteSourceID)
+	source, err := apiClient.GetSource(ctx, noteSourceID)
 	if err != nil {
-		return err
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("source with ID %d not found. Create it first with: oak source new", noteSourceID)
+		}
+		return fmt.Errorf("failed to fetch source: %w", err)
 	}
-	if source == nil {
-		return fmt.Errorf("source with ID %d not found. Create it first with: oak source new", noteSourceID)
+	if source.SupersededBy != nil {
+		fmt.Fprintf(os.Stderr, "Warning: source %d is superseded by source %d; consider 'oak source migrate %d %d'\n",
+			source.ID, *source.SupersededBy, source.ID, *source.SupersededBy)
 	}
 
-	// Check for existing notes
-	existing, err := database.GetSpeciesSourceBySourceID(speciesName, noteSourceID)
-	if err != nil {
-		return err
+	// Check for existing notes, with the version the save must still match
+	existing, etag, err := apiClient.GetSpeciesSourceWithETag(ctx, speciesName, noteSourceID)
+	if err != nil && !oakclient.IsNotFoundError(err) {
+		return fmt.Errorf("failed to fetch notes: %w", err)
 	}
 
 	var ss *models.SpeciesSource
 	isNew := false
 	if existing != nil {
-		ss = existing
+		ss = clientSpeciesSourceToModel(existing)
 		fmt.Printf("Editing existing notes for %s from %s\n", speciesName, source.Name)
 	} else {
 		ss = models.NewSpeciesSource(speciesName, noteSourceID)
@@ -123,9 +140,52 @@ func runNote(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Save
-	if err := database.SaveSpeciesSource(edited); err != nil {
-		return err
+	// Confirm only for actual remote servers
+	verb := "Update"
+	if isNew {
+		verb = "Create"
+	}
+	if isActualRemote() && !confirmRemoteOperation(verb, fmt.Sprintf("notes for %s from %s", speciesName, source.Name)) {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	// Save. Notes created or changed by someone else meanwhile are not
+	// overwritten but merged with the edit, until the save sticks.
+	what := fmt.Sprintf("notes for %s from %s", speciesName, source.Name)
+	var base *models.SpeciesSource
+	if !isNew {
+		base = ss
+	}
+	create := isNew
+	for {
+		if create {
+			_, err = apiClient.CreateSpeciesSource(ctx, speciesName, modelSpeciesSourceToClient(edited))
+		} else {
+			_, err = apiClient.UpdateSpeciesSourceIfMatch(ctx, speciesName, noteSourceID, modelSpeciesSourceToClient(edited), etag)
+		}
+		if err == nil {
+			break
+		}
+		if !oakclient.IsConflictError(err) && !oakclient.IsPreconditionFailedError(err) {
+			return fmt.Errorf("failed to save notes: %w", err)
+		}
+
+		current, currentETag, err := apiClient.GetSpeciesSourceWithETag(ctx, speciesName, noteSourceID)
+		if err != nil {
+			return editConflict(what, edited, source.Name)
+		}
+		fmt.Fprintf(os.Stderr, "%s changed on the server while you were editing them; merging\n", what)
+		remote := clientSpeciesSourceToModel(current)
+		merged, fromServer, err := editor.MergeSpeciesSource(base, edited, remote, source.Name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Merge failed: %v\n", err)
+		}
+		if merged == nil {
+			return editConflict(what, edited, source.Name)
+		}
+		reportMergedFields(fromServer)
+		base, edited, etag, create = remote, merged, currentETag, false
 	}
 
 	if isNew {
@@ -138,27 +198,21 @@ func runNote(cmd *cobra.Command, args []string) error {
 }
 
 func runNoteList(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
 	speciesName := names.NormalizeHybridName(args[0])
 
-	database, err := getDB()
-	if err != nil {
-		return err
-	}
-	defer database.Close()
-
-	// Verify species exists
-	entry, err := database.GetOakEntry(speciesName)
+	apiClient, err := getAPIClient()
 	if err != nil {
 		return err
 	}
-	if entry == nil {
-		return fmt.Errorf("species '%s' not found", speciesName)
-	}
 
 	// Get all sources for this species
-	sources, err := database.GetSpeciesSources(speciesName)
+	sources, err := apiClient.ListSpeciesSources(ctx, speciesName)
 	if err != nil {
-		return err
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("species '%s' not found", speciesName)
+		}
+		return fmt.Errorf("failed to fetch notes: %w", err)
 	}
 
 	if len(sources) == 0 {
@@ -170,9 +224,9 @@ func runNoteList(cmd *cobra.Command, args []string) error {
 
 	for _, ss := range sources {
 		// Get source name
-		source, err := database.GetSource(ss.SourceID)
+		source, err := apiClient.GetSource(ctx, ss.SourceID)
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to fetch source %d: %w", ss.SourceID, err)
 		}
 
 		preferred := ""
@@ -217,9 +271,27 @@ func runNoteList(cmd *cobra.Command, args []string) error {
 		if ss.Miscellaneous != nil && *ss.Miscellaneous != "" {
 			fmt.Fprintf(w, "Miscellaneous:\t%s\n", truncate(*ss.Miscellaneous))
 		}
+		if ss.DistinguishingFeatures != nil && *ss.DistinguishingFeatures != "" {
+			fmt.Fprintf(w, "Distinguishing:\t%s\n", truncate(*ss.DistinguishingFeatures))
+		}
 		if ss.URL != nil && *ss.URL != "" {
 			fmt.Fprintf(w, "URL:\t%s\n", *ss.URL)
 		}
+		if ss.Pages != nil && *ss.Pages != "" {
+			fmt.Fprintf(w, "Pages:\t%s\n", *ss.Pages)
+		}
+		for _, field := range sortedKeys(ss.FieldPages) {
+			fmt.Fprintf(w, "Pages (%s):\t%s\n", field, ss.FieldPages[field])
+		}
+		var internal []string
+		for _, field := range sortedKeys(ss.FieldVisibility) {
+			if ss.FieldVisibility[field] == "internal" {
+				internal = append(internal, field)
+			}
+		}
+		if len(internal) > 0 {
+			fmt.Fprintf(w, "Internal:\t%s\n", strings.Join(internal, ", "))
+		}
 
 		w.Flush()
 		fmt.Println()
@@ -237,44 +309,54 @@ func truncate(s string) string {
 }
 
 func runNoteDelete(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
 	speciesName := names.NormalizeHybridName(args[0])
 
-	database, err := getDB()
+	apiClient, err := getAPIClient()
 	if err != nil {
 		return err
 	}
-	defer database.Close()
 
-	// Verify species exists
-	entry, err := database.GetOakEntry(speciesName)
-	if err != nil {
-		return err
+	// Verify auth before doing any work (only for actual remote servers)
+	if isActualRemote() {
+		if err := apiClient.VerifyAuth(ctx); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
 	}
-	if entry == nil {
-		return fmt.Errorf("species '%s' not found", speciesName)
+
+	// Verify species exists
+	if _, err := apiClient.GetSpecies(ctx, speciesName); err != nil {
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("species '%s' not found", speciesName)
+		}
+		return fmt.Errorf("failed to fetch species: %w", err)
 	}
 
 	// Verify source exists
-	source, err := database.GetSource(noteSourceID)
+	source, err := apiClient.GetSource(ctx, noteSourceID)
 	if err != nil {
-		return err
-	}
-	if source == nil {
-		return fmt.Errorf("source with ID %d not found", noteSourceID)
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("source with ID %d not found", noteSourceID)
+		}
+		return fmt.Errorf("failed to fetch source: %w", err)
 	}
 
 	// Check notes exist
-	existing, err := database.GetSpeciesSourceBySourceID(speciesName, noteSourceID)
-	if err != nil {
-		return err
-	}
-	if existing == nil {
-		return fmt.Errorf("no notes found for %s from source %d (%s)", speciesName, noteSourceID, source.Name)
+	if _, err := apiClient.GetSpeciesSource(ctx, speciesName, noteSourceID); err != nil {
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("no notes found for %s from source %d (%s)", speciesName, noteSourceID, source.Name)
+		}
+		return fmt.Errorf("failed to fetch notes: %w", err)
 	}
 
 	// Confirm deletion unless --force
 	if !noteDeleteForce {
-		fmt.Printf("Delete notes for %s from %s (source %d)? (y/N): ", speciesName, source.Name, noteSourceID)
+		prompt := fmt.Sprintf("Delete notes for %s from %s (source %d)? (y/N): ", speciesName, source.Name, noteSourceID)
+		if isActualRemote() {
+			prompt = fmt.Sprintf("Delete notes for %s from %s (source %d) on [%s]? (y/N): ",
+				speciesName, source.Name, noteSourceID, apiClient.ProfileName())
+		}
+		fmt.Print(prompt)
 		reader := bufio.NewReader(os.Stdin)
 		response, err := reader.ReadString('\n')
 		if err != nil {
@@ -287,10 +369,22 @@ func runNoteDelete(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if err := database.DeleteSpeciesSource(speciesName, noteSourceID); err != nil {
-		return err
+	if err := apiClient.DeleteSpeciesSource(ctx, speciesName, noteSourceID); err != nil {
+		return fmt.Errorf("failed to delete notes: %w", err)
 	}
 
 	fmt.Printf("Deleted notes for %s (source: %s)\n", speciesName, source.Name)
 	return nil
 }
+
+// clientSpeciesSourceToModel converts a oakclient.SpeciesSource to models.SpeciesSource.
+func clientSpeciesSourceToModel(ss *oakclient.SpeciesSource) *models.SpeciesSource {
+	m := models.SpeciesSource(*ss)
+	return &m
+}
+
+// modelSpeciesSourceToClient converts a models.SpeciesSource to oakclient.SpeciesSource.
+func modelSpeciesSourceToClient(ss *models.SpeciesSource) *oakclient.SpeciesSource {
+	c := oakclient.SpeciesSource(*ss)
+	return &c
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/config"
+	"github.com/jeff/oaks/cli/internal/embedded"
+	"github.com/jeff/oaks/cli/internal/replica"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+// offlineOK lets read commands fall back to the profile's synced replica
+var offlineOK bool
+
+// offlineProbeTimeout bounds the health check deciding whether to fall back
+const offlineProbeTimeout = 5 * time.Second
+
+// offlineReadCommands are the commands that may read from a replica. They
+// only read, so answering from stale data is safe once it is labeled.
+var offlineReadCommands = map[string]bool{
+	"oak find":                 true,
+	"oak grep":                 true,
+	"oak export":               true,
+	"oak export flashcards":    true,
+	"oak checklist":            true,
+	"oak source list":          true,
+	"oak source show":          true,
+	"oak source usage":         true,
+	"oak source coverage":      true,
+	"oak taxa list":            true,
+	"oak taxa show":            true,
+	"oak taxa find":            true,
+	"oak genera list":          true,
+	"oak note list":            true,
+	"oak species mentions":     true,
+	"oak species measurements": true,
+}
+
+// useReplicaIfUnreachable switches a read command to the synced replica of
+// the resolved remote profile when --offline-ok is set and the remote does
+// not answer. The replica is served by the embedded server, so commands run
+// unchanged; a notice on stderr says the data is cached and how old it is.
+func useReplicaIfUnreachable(cmd *cobra.Command) error {
+	if !offlineOK || !offlineReadCommands[cmd.CommandPath()] || remoteReachable() {
+		return nil
+	}
+
+	name := resolvedProfile.Name
+	path := config.DefaultReplicaPath(name)
+	info, err := replica.Read(path)
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		return &exitError{code: ExitNetwork, err: fmt.Errorf(
+			"remote [%s] is unreachable and has no synced copy; run 'oak sync' while it is reachable", name)}
+	}
+
+	embeddedServer, err = embedded.Start(embedded.Config{
+		DBPath:            path,
+		Quiet:             true,
+		ExportMappingsDir: os.Getenv(config.EnvExportMappings),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start embedded server: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Remote [%s] unreachable; using cached data synced %s\n",
+		name, info.SyncedAt.Local().Format("2006-01-02 15:04 MST"))
+
+	resolvedProfile = &config.ResolvedProfile{
+		Name:   name,
+		URL:    embeddedServer.URL(),
+		Key:    embeddedServer.APIKey(),
+		Source: config.SourceReplica,
+	}
+	return nil
+}
+
+// remoteReachable reports whether the resolved profile's API answers at all.
+// Error responses count as reachable; only connection failures do not.
+func remoteReachable() bool {
+	ctx := commandContext()
+	c, err := newProfileClient(resolvedProfile,
+		oakclient.WithTimeout(offlineProbeTimeout),
+		oakclient.WithSkipVersionCheck(true),
+	)
+	if err != nil {
+		return true // Let the command report the problem
+	}
+	_, err = c.Health(ctx)
+	return !oakclient.IsConnectionError(err)
+}
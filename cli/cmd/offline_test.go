@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOfflineReadCommandsExist(t *testing.T) {
+	for path := range offlineReadCommands {
+		args := strings.Fields(path)[1:]
+		cmd, _, err := rootCmd.Find(args)
+		if err != nil || cmd.CommandPath() != path {
+			t.Errorf("offline read command %q does not resolve to a command", path)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var publishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Generate pages for the website",
+	Long:  `Commands that build content for the public website from the database.`,
+}
+
+var (
+	publishAttrFormat string
+	publishAttrOutput string
+)
+
+var publishAttributionsCmd = &cobra.Command{
+	Use:   "attributions",
+	Short: "Generate the source attribution page",
+	Long: `Generate the website's attribution page: every source with its author,
+license, URL, and the species it contributed data for.
+
+If no output file is specified, writes to stdout.
+
+Examples:
+  oak publish attributions                          # Markdown to stdout
+  oak publish attributions --format html -o attributions.html
+  oak publish attributions --remote -o attributions.md`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		switch publishAttrFormat {
+		case oakclient.AttributionFormatMarkdown, oakclient.AttributionFormatHTML, oakclient.AttributionFormatJSON:
+		default:
+			return usageErrorf("invalid --format %q: must be markdown, html, or json", publishAttrFormat)
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		data, err := apiClient.Attributions(ctx, publishAttrFormat)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		if publishAttrOutput == "" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(publishAttrOutput, data, 0o644); err != nil { //nolint:gosec // published page must be readable
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Wrote attributions to %s\n", publishAttrOutput)
+		return nil
+	},
+}
+
+var (
+	publishSitemapOutput  string
+	publishSitemapBaseURL string
+)
+
+var publishSitemapCmd = &cobra.Command{
+	Use:   "sitemap",
+	Short: "Generate sitemap.xml for the website",
+	Long: `Generate the website's sitemap.xml: every published species, taxon, and
+source page, with lastmod from when each was last updated. Draft species are
+left out.
+
+Links use the API server's site URL (OAK_SITE_URL) unless --base-url is set.
+If no output file is specified, writes to stdout.
+
+Examples:
+  oak publish sitemap -o web/static/sitemap.xml
+  oak publish sitemap --remote -o sitemap.xml
+  oak publish sitemap --base-url https://staging.oakcompendium.org`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		data, err := apiClient.Sitemap(ctx, publishSitemapBaseURL)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		if publishSitemapOutput == "" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(publishSitemapOutput, data, 0o644); err != nil { //nolint:gosec // published page must be readable
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Wrote sitemap to %s\n", publishSitemapOutput)
+		return nil
+	},
+}
+
+func init() {
+	publishAttributionsCmd.Flags().StringVar(&publishAttrFormat, "format", oakclient.AttributionFormatMarkdown, "Output format: markdown, html, or json")
+	publishAttributionsCmd.Flags().StringVarP(&publishAttrOutput, "output", "o", "", "Output file path")
+
+	publishSitemapCmd.Flags().StringVarP(&publishSitemapOutput, "output", "o", "", "Output file path")
+	publishSitemapCmd.Flags().StringVar(&publishSitemapBaseURL, "base-url", "", "Site URL for page links (default: the server's OAK_SITE_URL)")
+
+	publishCmd.AddCommand(publishAttributionsCmd)
+	publishCmd.AddCommand(publishSitemapCmd)
+	rootCmd.AddCommand(publishCmd)
+}
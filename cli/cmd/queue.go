@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/config"
+	"github.com/jeff/oaks/cli/internal/queue"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var (
+	queueDropFailed bool
+	queueDropAll    bool
+
+	// outboundTransport queues this run's writes to a remote profile (nil
+	// until the first API client for one is created, or if the queue failed)
+	outboundTransport *queue.Transport
+	outboundWarned    bool
+)
+
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Show writes waiting to be resent to remote profiles",
+	Long: `Show the writes to remote profiles that never reached the server.
+
+Every write to a remote profile is recorded in ~/.oak/queue.db before it is
+sent. If the server could not be reached, or answered 429 or 503, the write
+stays queued and is resent, oldest first, at the start of the next oak
+command against that profile. A write that may have been applied (the
+connection dropped after it was sent, or a proxy answered 502 or 504) is
+not queued, so it is never applied twice.
+After each failed attempt the next waits longer: 30s, then doubling up to
+an hour. A write the server refuses on resend (a 409 because it was applied
+before the connection dropped, say) is marked failed and kept for review.
+
+Examples:
+  oak queue                          # List queued writes for every profile
+  oak queue retry --profile prod     # Resend now, ignoring the wait
+  oak queue drop 12                  # Forget one write
+  oak queue drop --failed            # Forget the writes the server refused`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q, err := openQueue(false)
+		if err != nil {
+			return err
+		}
+		if q == nil {
+			fmt.Println("Queue is empty")
+			return nil
+		}
+		defer q.Close()
+
+		entries, err := q.List("")
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println("Queue is empty")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tPROFILE\tSTATUS\tATTEMPTS\tNEXT\tREQUEST\tLAST ERROR")
+		fmt.Fprintln(w, "--\t-------\t------\t--------\t----\t-------\t----------")
+		for _, e := range entries {
+			next := "-"
+			if e.Status == queue.StatusPending {
+				next = formatNextAttempt(e.NextAttempt)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%s %s\t%s\n",
+				e.ID, e.Profile, e.Status, e.Attempts, next, e.Method, e.Path, firstLine(e.LastError))
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var queueRetryCmd = &cobra.Command{
+	Use:   "retry",
+	Short: "Resend the active profile's queued writes now",
+	Long: `Resend the pending writes queued for the active remote profile, oldest
+first, without waiting for their backoff to expire. Resending stops at the
+first write that still cannot reach the server.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !isActualRemote() {
+			return usageErrorf("oak queue retry resends to a remote profile; select one with --profile")
+		}
+		result, err := flushQueue(commandContext(), true)
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			fmt.Printf("No writes queued for [%s]\n", resolvedProfile.Name)
+			return nil
+		}
+		fmt.Printf("[%s]: %d sent, %d refused, %d still queued\n",
+			resolvedProfile.Name, result.Sent, result.Failed, result.Waiting)
+		if result.Waiting > 0 {
+			return &exitError{code: ExitNetwork, err: fmt.Errorf("%d writes could not be sent; see 'oak queue'", result.Waiting)}
+		}
+		return nil
+	},
+}
+
+var queueDropCmd = &cobra.Command{
+	Use:   "drop [id...]",
+	Short: "Remove writes from the queue without sending them",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !queueDropFailed && !queueDropAll {
+			return usageErrorf("give queue IDs, --failed, or --all")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids := make([]int64, 0, len(args))
+		for _, arg := range args {
+			id, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				return usageErrorf("invalid queue ID: %s", arg)
+			}
+			ids = append(ids, id)
+		}
+
+		q, err := openQueue(false)
+		if err != nil {
+			return err
+		}
+		if q == nil {
+			fmt.Println("Queue is empty")
+			return nil
+		}
+		defer q.Close()
+
+		dropped := 0
+		switch {
+		case queueDropAll:
+			dropped, err = q.RemoveAll("", "")
+		case queueDropFailed:
+			dropped, err = q.RemoveAll("", queue.StatusFailed)
+		}
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := q.Remove(id); err != nil {
+				return err
+			}
+			dropped++
+		}
+		fmt.Printf("Dropped %d queued writes\n", dropped)
+		return nil
+	},
+}
+
+func init() {
+	queueDropCmd.Flags().BoolVar(&queueDropFailed, "failed", false, "Drop every write the server refused")
+	queueDropCmd.Flags().BoolVar(&queueDropAll, "all", false, "Drop every queued write, pending or failed")
+	queueCmd.AddCommand(queueRetryCmd, queueDropCmd)
+	rootCmd.AddCommand(queueCmd)
+}
+
+// openQueue opens the outbound queue. Unless create is set, it returns nil
+// without creating the file when nothing has ever been queued.
+func openQueue(create bool) (*queue.Queue, error) {
+	path := config.DefaultQueuePath()
+	if path == "" {
+		return nil, errors.New("cannot locate the write queue: no home directory")
+	}
+	if !create {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil, nil
+		}
+	}
+	return queue.Open(path)
+}
+
+// outboundOption routes a remote profile's client through the write queue.
+// A queue that cannot be opened is reported once and writes go unqueued.
+func outboundOption() oakclient.Option {
+	if outboundTransport == nil && !outboundWarned {
+		q, err := openQueue(true)
+		if err != nil {
+			outboundWarned = true
+			fmt.Fprintf(os.Stderr, "Warning: writes will not be queued for retry: %v\n", err)
+			return nil
+		}
+		outboundTransport = &queue.Transport{
+			Queue:   q,
+			BaseURL: strings.TrimSuffix(resolvedProfile.URL, "/"),
+			Profile: resolvedProfile.Name,
+		}
+	}
+	if outboundTransport == nil {
+		return nil
+	}
+	return oakclient.WithTransport(outboundTransport)
+}
+
+// resendQueuedWrites sends the active remote profile's due writes before a
+// command runs, so they reach the server before anything newer. Problems
+// are reported on stderr without failing the command.
+func resendQueuedWrites(cmd *cobra.Command) {
+	if !isActualRemote() || cmd == queueCmd || cmd.Parent() == queueCmd {
+		return
+	}
+	result, err := flushQueue(commandContext(), false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to resend queued writes: %v\n", err)
+		return
+	}
+	if result == nil || result.Sent+result.Failed == 0 {
+		return
+	}
+	msg := fmt.Sprintf("Resent %d queued writes to [%s]", result.Sent, resolvedProfile.Name)
+	if result.Failed > 0 {
+		msg += fmt.Sprintf("; %d refused (see 'oak queue')", result.Failed)
+	}
+	fmt.Fprintln(os.Stderr, msg)
+}
+
+// flushQueue resends the active profile's queued writes with a client that
+// bypasses the queue. It returns nil if nothing was ever queued.
+func flushQueue(ctx context.Context, all bool) (*queue.FlushResult, error) {
+	q, err := openQueue(false)
+	if err != nil || q == nil {
+		return nil, err
+	}
+	defer q.Close()
+
+	opts := []oakclient.Option{oakclient.WithDeprecationHandler(warnDeprecation)}
+	if skipVersionCheck {
+		opts = append(opts, oakclient.WithSkipVersionCheck(true))
+	}
+	client, err := newProfileClient(resolvedProfile, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return queue.Flush(ctx, q, resolvedProfile.Name, all, func(ctx context.Context, e *queue.Entry) (int, string, error) {
+		headers := map[string]string{"Content-Type": e.ContentType}
+		if e.IfMatch != "" {
+			headers["If-Match"] = e.IfMatch
+		}
+		resp, err := client.Replay(ctx, &oakclient.CapturedExchange{Request: oakclient.CapturedRequest{
+			Method:  e.Method,
+			Path:    e.Path,
+			Headers: headers,
+			Body:    e.Body,
+		}})
+		if err != nil {
+			return 0, "", err
+		}
+		return resp.StatusCode, resp.Body, nil
+	})
+}
+
+// reportQueuedWrites says on stderr how many of this run's writes were left
+// queued, after a command failed partway through a batch.
+func reportQueuedWrites() {
+	if outboundTransport == nil {
+		return
+	}
+	if n := outboundTransport.Left(); n > 0 {
+		fmt.Fprintf(os.Stderr, "%d writes did not reach [%s] and are queued; the next oak command against it resends them (see 'oak queue')\n",
+			n, outboundTransport.Profile)
+	}
+	outboundTransport.Queue.Close()
+	outboundTransport = nil
+}
+
+// formatNextAttempt describes when a pending write is next resent.
+func formatNextAttempt(t time.Time) string {
+	wait := time.Until(t)
+	if wait <= 0 {
+		return "now"
+	}
+	return "in " + wait.Round(time.Second).String()
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var replayWrites bool
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <capture-dir-or-file>",
+	Short: "Re-issue captured API requests against a profile",
+	Long: `Re-issue HTTP requests recorded with --debug-http-capture against the
+current profile and compare status codes with the original responses.
+Requests are sent with the current profile's API key; recorded keys are
+never stored.
+
+Only read requests (GET/HEAD) are replayed unless --writes is given.
+
+Examples:
+  oak --debug-http-capture ./cap edit alba --remote   # Record a session
+  oak replay ./cap --local                            # Replay reads locally
+  oak replay ./cap -p staging --writes                # Replay everything on staging`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	replayCmd.Flags().BoolVar(&replayWrites, "writes", false, "Also replay POST/PUT/DELETE requests")
+	rootCmd.AddCommand(replayCmd)
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	exchanges, err := oakclient.LoadCapturedExchanges(args[0])
+	if err != nil {
+		return usageErrorf("failed to load captures: %v", err)
+	}
+	if len(exchanges) == 0 {
+		fmt.Println("No captured requests found")
+		return nil
+	}
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	if replayWrites && isActualRemote() && !confirmRemoteOperation("Replay", fmt.Sprintf("%d captured requests", len(exchanges))) {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	replayed, mismatched, skipped := 0, 0, 0
+	for _, ex := range exchanges {
+		method := ex.Request.Method
+		if !replayWrites && method != http.MethodGet && method != http.MethodHead {
+			skipped++
+			continue
+		}
+
+		recorded := "error"
+		if ex.Response != nil {
+			recorded = fmt.Sprintf("%d", ex.Response.StatusCode)
+		}
+
+		resp, err := apiClient.Replay(ctx, ex)
+		if err != nil {
+			fmt.Printf("%-6s %s  recorded %s -> error: %v\n", method, ex.Request.Path, recorded, err)
+			mismatched++
+			continue
+		}
+		replayed++
+
+		marker := ""
+		if ex.Response == nil || ex.Response.StatusCode != resp.StatusCode {
+			marker = "  MISMATCH"
+			mismatched++
+		}
+		fmt.Printf("%-6s %s  recorded %s -> %d%s\n", method, ex.Request.Path, recorded, resp.StatusCode, marker)
+	}
+
+	fmt.Printf("\nReplayed %d requests against [%s]: %d mismatched, %d skipped\n",
+		replayed, apiClient.ProfileName(), mismatched, skipped)
+	return nil
+}
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var (
+	reportFormat string
+	reportLimit  int
+	reportOutput string
+	reportName   string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Run and manage saved reports",
+	Long: `Commands for saved reports: read-only SQL queries stored in the database,
+with the columns to show and their headings, run on demand as CSV or JSON.
+A few are bundled, such as species-by-section and hybrids-missing-parents.
+Reports need an API key, even to list them.
+
+Define a report in YAML and save it with 'oak report save':
+
+  name: species-by-author
+  title: Species by author
+  query: |
+    SELECT author, count(*) AS species FROM oak_entries
+    GROUP BY author ORDER BY species DESC
+  columns:
+    - {name: author, title: Author}
+    - {name: species, title: Species}
+  format: csv`,
+}
+
+var reportListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved reports",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		reports, err := apiClient.ListReports(commandContext())
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+		if len(reports) == 0 {
+			fmt.Println("No reports defined")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "REPORT\tFORMAT\tTITLE")
+		fmt.Fprintln(w, "------\t------\t-----")
+		for _, r := range reports {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", r.Name, r.Format, r.Title)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var reportShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Print a report's definition as YAML",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		report, err := apiClient.GetReport(commandContext(), args[0])
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("report '%s' not found", args[0])
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		// Prints in the form 'oak report save' reads back
+		data, err := yaml.Marshal(report)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+		return nil
+	},
+}
+
+var reportRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Run a saved report",
+	Long: `Run a saved report and print its rows as a table, or write them as CSV
+or JSON with --format (to a file with -o).
+
+Examples:
+  oak report run species-by-section
+  oak report run hybrids-missing-parents --format csv -o missing.csv
+  oak report run species-without-sources --format json --profile prod`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReport,
+}
+
+var reportSaveCmd = &cobra.Command{
+	Use:   "save <file.yaml>",
+	Short: "Create or replace a report from a YAML definition",
+	Long: `Create a report from a YAML definition, or replace the one with the same
+name. The server runs the query once to check it: SQL that would write,
+does not parse, or lacks a listed column is refused.
+
+Examples:
+  oak report save species-by-author.yaml
+  oak report save draft.yaml --name species-by-author --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := readImportFile(args[0])
+		if err != nil {
+			return err
+		}
+		var def oakclient.Report
+		if err := yaml.Unmarshal(data, &def); err != nil {
+			return usageErrorf("invalid report definition %s: %v", args[0], err)
+		}
+		if reportName != "" {
+			def.Name = reportName
+		}
+		if def.Name == "" {
+			return usageErrorf("%s has no name; add one or pass --name", args[0])
+		}
+		cmd.SilenceUsage = true // Failures from here on are about the definition, not the arguments
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+		if isActualRemote() && !confirmRemoteOperation("Save report", def.Name) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		report, err := apiClient.SaveReport(commandContext(), def.Name, &oakclient.ReportRequest{
+			Title:       def.Title,
+			Description: def.Description,
+			Query:       def.Query,
+			Columns:     def.Columns,
+			Format:      def.Format,
+		})
+		var apiErr *oakclient.APIError
+		if errors.As(err, &apiErr) && len(apiErr.Fields) > 0 {
+			problems := make([]string, len(apiErr.Fields))
+			for i, f := range apiErr.Fields {
+				problems[i] = f.Field + ": " + f.Message
+			}
+			return &exitError{code: ExitValidation, err: fmt.Errorf("report %s refused: %s", def.Name, strings.Join(problems, "; "))}
+		}
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Saved report %s (%s)\n", report.Name, report.Title)
+		return nil
+	},
+}
+
+var reportDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a saved report",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+		if isActualRemote() && !confirmRemoteOperation("Delete report", args[0]) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		if err := apiClient.DeleteReport(commandContext(), args[0]); err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("report '%s' not found", args[0])
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Deleted report %s\n", args[0])
+		return nil
+	},
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	switch reportFormat {
+	case "table", oakclient.ReportFormatCSV, oakclient.ReportFormatJSON:
+	default:
+		return usageErrorf("invalid --format %q (must be table, csv, or json)", reportFormat)
+	}
+	cmd.SilenceUsage = true // Failures from here on are about the report, not the arguments
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	format := reportFormat
+	if format == "table" {
+		format = oakclient.ReportFormatJSON
+	}
+	data, err := apiClient.RunReport(commandContext(), args[0], format, reportLimit)
+	if err != nil {
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("report '%s' not found", args[0])
+		}
+		return fmt.Errorf("report failed: %w", err)
+	}
+
+	if reportFormat != "table" {
+		if reportOutput == "" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(reportOutput, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", reportOutput, err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %s\n", reportOutput)
+		return nil
+	}
+
+	var run oakclient.ReportRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return fmt.Errorf("failed to parse report: %w", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(run.Columns, "\t"))
+	for _, row := range run.Rows {
+		fmt.Fprintln(w, strings.ReplaceAll(strings.Join(sqlRowStrings(row), "\t"), "\n", " "))
+	}
+	w.Flush()
+	if run.Truncated {
+		fmt.Fprintf(os.Stderr, "More rows matched; only the first %d are shown (raise --limit)\n", len(run.Rows))
+	}
+	return nil
+}
+
+func init() {
+	reportRunCmd.Flags().StringVar(&reportFormat, "format", "table", "Output format: table, csv, or json")
+	reportRunCmd.Flags().IntVar(&reportLimit, "limit", 0, "Most rows to return (default and most 10000)")
+	reportRunCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Output file path (csv and json)")
+	reportSaveCmd.Flags().StringVar(&reportName, "name", "", "Report name (overrides the definition's)")
+
+	reportCmd.AddCommand(reportListCmd)
+	reportCmd.AddCommand(reportShowCmd)
+	reportCmd.AddCommand(reportRunCmd)
+	reportCmd.AddCommand(reportSaveCmd)
+	reportCmd.AddCommand(reportDeleteCmd)
+	rootCmd.AddCommand(reportCmd)
+}
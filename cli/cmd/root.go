@@ -2,17 +2,19 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/spf13/cobra"
 
-	"github.com/jeff/oaks/cli/internal/client"
 	"github.com/jeff/oaks/cli/internal/config"
 	"github.com/jeff/oaks/cli/internal/db"
 	"github.com/jeff/oaks/cli/internal/embedded"
 	"github.com/jeff/oaks/cli/internal/schema"
+	"github.com/jeff/oaks/pkg/oakclient"
 )
 
 var (
@@ -22,6 +24,7 @@ var (
 	forceLocal       bool
 	forceRemote      bool
 	skipVersionCheck bool
+	httpCaptureDir   string
 
 	// Resolved configuration (loaded on init)
 	cfg             *config.Config
@@ -40,7 +43,15 @@ searching, and importing oak species data with source attribution.`,
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	usageArgs(rootCmd)
+	err := rootCmd.Execute()
+	// Cobra reports unknown subcommands with a plain error from its
+	// command lookup, before any command's argument validation runs
+	if err != nil && strings.HasPrefix(err.Error(), "unknown command ") {
+		err = &exitError{code: ExitUsage, err: err}
+	}
+	reportQueuedWrites()
+	return err
 }
 
 func init() {
@@ -50,12 +61,25 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&forceLocal, "local", false, "Use embedded API server for local database operations")
 	rootCmd.PersistentFlags().BoolVar(&forceRemote, "remote", false, "Force remote API mode (requires API profile)")
 	rootCmd.PersistentFlags().BoolVar(&skipVersionCheck, "skip-version-check", false, "Skip API version compatibility check")
+	rootCmd.PersistentFlags().StringVar(&httpCaptureDir, "debug-http-capture", "", "Record all API requests/responses to this directory (secrets redacted)")
+	rootCmd.PersistentFlags().BoolVar(&offlineOK, "offline-ok", false, "Let read commands use the last synced copy (oak sync) when the remote is unreachable")
+	rootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", errorFormatText, "Error output format: text or json")
+
+	// Errors are reported by HandleError so they can be rendered as JSON
+	rootCmd.SilenceErrors = true
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &exitError{code: ExitUsage, err: err}
+	})
 
 	// Load config and resolve profile before any command runs
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := checkErrorFormat(cmd); err != nil {
+			return err
+		}
+
 		// Validate --local and --remote are mutually exclusive
 		if forceLocal && forceRemote {
-			return fmt.Errorf("--local and --remote flags are mutually exclusive")
+			return usageErrorf("--local and --remote flags are mutually exclusive")
 		}
 
 		var err error
@@ -63,12 +87,16 @@ func init() {
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
+		for _, p := range cfg.Warnings() {
+			fmt.Fprintf(os.Stderr, "%s:%d: %s\n", config.DefaultConfigPath(), p.Line, p)
+		}
 
 		// If --local is set, always use embedded server (even if a profile is configured)
 		if forceLocal {
 			embeddedServer, err = embedded.Start(embedded.Config{
-				DBPath: dbPath,
-				Quiet:  true,
+				DBPath:            dbPath,
+				Quiet:             true,
+				ExportMappingsDir: os.Getenv(config.EnvExportMappings),
 			})
 			if err != nil {
 				return fmt.Errorf("failed to start embedded server: %w", err)
@@ -97,8 +125,9 @@ func init() {
 		// This allows all commands to use the unified API client path
 		if resolvedProfile.IsLocal() {
 			embeddedServer, err = embedded.Start(embedded.Config{
-				DBPath: dbPath,
-				Quiet:  true,
+				DBPath:            dbPath,
+				Quiet:             true,
+				ExportMappingsDir: os.Getenv(config.EnvExportMappings),
 			})
 			if err != nil {
 				return fmt.Errorf("failed to start embedded server: %w", err)
@@ -110,8 +139,13 @@ func init() {
 				Key:    embeddedServer.APIKey(),
 				Source: config.SourceEmbedded,
 			}
+			return nil
 		}
 
+		if err := useReplicaIfUnreachable(cmd); err != nil {
+			return err
+		}
+		resendQueuedWrites(cmd)
 		return nil
 	}
 
@@ -127,6 +161,18 @@ func init() {
 	}
 }
 
+// checkErrorFormat validates --error-format before a command runs
+func checkErrorFormat(cmd *cobra.Command) error {
+	if errorFormat != errorFormatText && errorFormat != errorFormatJSON {
+		return usageErrorf("invalid --error-format %q (must be text or json)", errorFormat)
+	}
+	if errorFormat == errorFormatJSON {
+		// Keep stderr machine-readable
+		cmd.SilenceUsage = true
+	}
+	return nil
+}
+
 // getDB creates a new database connection
 func getDB() (*db.Database, error) {
 	return db.New(dbPath)
@@ -143,7 +189,7 @@ func readImportFile(filePath string) ([]byte, error) {
 	info, err := os.Stat(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("file not found: %s", filePath)
+			return nil, notFoundErrorf("file not found: %s", filePath)
 		}
 		return nil, fmt.Errorf("cannot access file: %w", err)
 	}
@@ -172,24 +218,94 @@ func isRemoteMode() bool {
 }
 
 // isActualRemote returns true if operating against an actual remote server
-// (not the embedded local server). Use this for confirmation prompts.
+// (not the embedded local server or a synced replica). Use this for
+// confirmation prompts.
 func isActualRemote() bool {
-	return resolvedProfile != nil && resolvedProfile.Source != config.SourceEmbedded
+	return resolvedProfile != nil && resolvedProfile.Source != config.SourceEmbedded &&
+		resolvedProfile.Source != config.SourceReplica
+}
+
+// commandContext returns the context for the API calls of the running command.
+func commandContext() context.Context {
+	if ctx := rootCmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
 }
 
 // getAPIClient creates a new API client from the resolved profile.
+// Extra options are applied after the global flag options.
 // Returns an error if operating in local mode.
-func getAPIClient() (*client.Client, error) {
+func getAPIClient(extra ...oakclient.Option) (*oakclient.Client, error) {
 	if resolvedProfile == nil || resolvedProfile.IsLocal() {
 		return nil, fmt.Errorf("cannot create API client: operating in local mode")
 	}
 
-	opts := []client.Option{}
+	opts := []oakclient.Option{}
+	if embeddedServer != nil {
+		opts = append(opts, oakclient.WithTransport(embeddedServer.Transport()))
+	}
 	if skipVersionCheck {
-		opts = append(opts, client.WithSkipVersionCheck(true))
+		opts = append(opts, oakclient.WithSkipVersionCheck(true))
 	}
+	if httpCaptureDir != "" {
+		opts = append(opts, oakclient.WithCapture(httpCaptureDir))
+	}
+	if isActualRemote() {
+		if opt := outboundOption(); opt != nil {
+			opts = append(opts, opt)
+		}
+	}
+	opts = append(opts, oakclient.WithDeprecationHandler(warnDeprecation))
+	opts = append(opts, extra...)
+
+	return newProfileClient(resolvedProfile, opts...)
+}
 
-	return client.New(resolvedProfile, opts...)
+// newProfileClient creates an API client for a profile's server and key.
+func newProfileClient(profile *config.ResolvedProfile, opts ...oakclient.Option) (*oakclient.Client, error) {
+	if profile == nil || profile.IsLocal() {
+		return nil, fmt.Errorf("cannot create API client: profile is for local mode")
+	}
+	opts = append([]oakclient.Option{
+		oakclient.WithAPIKey(profile.Key),
+		oakclient.WithProfileName(profile.Name),
+	}, opts...)
+	return oakclient.New(profile.URL, opts...)
+}
+
+// warnedDeprecations tracks routes already warned about in this run. Bench
+// workers share a client, so it is guarded by warnedMu.
+var (
+	warnedDeprecations = map[string]bool{}
+	warnedMu           sync.Mutex
+)
+
+// warnDeprecation prints a one-time warning when the API reports a route as deprecated
+func warnDeprecation(d oakclient.Deprecation) {
+	key := d.Method + " " + d.Path
+	warnedMu.Lock()
+	warned := warnedDeprecations[key]
+	warnedDeprecations[key] = true
+	warnedMu.Unlock()
+	if warned {
+		return
+	}
+
+	msg := fmt.Sprintf("Warning: %s is deprecated by the API", key)
+	if d.Sunset != "" {
+		msg += " and will be removed after " + d.Sunset
+	}
+	fmt.Fprintln(os.Stderr, msg+". Upgrade the CLI: go install github.com/jeff/oaks/cli@latest")
+}
+
+// printMorePages notes on stderr when a paged list stopped short of the total
+func printMorePages(p oakclient.Pagination, shown int) {
+	if !p.HasMore {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Showing %d-%d of %d; use --offset %d for more\n",
+		p.Offset+1, p.Offset+shown, p.Total, p.Offset+shown)
 }
 
 // confirmRemoteOperation prompts the user to confirm a destructive operation
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+func TestWarnDeprecationConcurrent(t *testing.T) {
+	// Bench workers report deprecations from many goroutines at once
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			warnDeprecation(oakclient.Deprecation{Method: http.MethodGet, Path: "/api/v1/test-deprecated"})
+		}()
+	}
+	wg.Wait()
+
+	warnedMu.Lock()
+	defer warnedMu.Unlock()
+	if !warnedDeprecations["GET /api/v1/test-deprecated"] {
+		t.Error("route not recorded as warned")
+	}
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/config"
+	"github.com/jeff/oaks/cli/internal/editor"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "JSON Schemas for editor integration, and the data dictionary",
+	Long: `Commands for the JSON Schemas the API publishes at /api/v1/schemas for
+oak entries, species sources, sources, and taxa, and for the data dictionary
+describing the published dataset ('oak schema dictionary').
+
+Once the schemas are dumped, the markdown files opened by 'oak new', 'oak edit',
+'oak note', 'oak source', and 'oak taxa' carry a yaml-language-server modeline.
+Editors with YAML language support (e.g. VS Code with the Red Hat YAML
+extension) then validate the front matter as you type.`,
+}
+
+var schemaDumpDir string
+
+var schemaDumpCmd = &cobra.Command{
+	Use:   "dump [name...]",
+	Short: "Write the published JSON Schemas to disk",
+	Long: `Fetch the JSON Schemas from the API and write each to <dir>/<name>.schema.json.
+
+Without names, every published schema is written. Re-run after changing
+genera or taxon levels so the enumerations stay current. The editor modeline
+points at the default directory (~/.oak/schemas).
+
+Examples:
+  oak schema dump
+  oak schema dump taxon --dir .vscode/schemas`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		names := args
+		if len(names) == 0 {
+			schemas, err := apiClient.ListSchemas(ctx)
+			if err != nil {
+				return fmt.Errorf("API error: %w", err)
+			}
+			for _, s := range schemas {
+				names = append(names, s.Name)
+			}
+		}
+
+		if err := os.MkdirAll(schemaDumpDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create schema directory: %w", err)
+		}
+
+		for _, name := range names {
+			raw, err := apiClient.GetSchema(ctx, name)
+			if err != nil {
+				if oakclient.IsNotFoundError(err) {
+					return notFoundErrorf("schema '%s' not found", name)
+				}
+				return fmt.Errorf("API error: %w", err)
+			}
+
+			var pretty bytes.Buffer
+			if err := json.Indent(&pretty, raw, "", "  "); err != nil {
+				return fmt.Errorf("invalid schema %s: %w", name, err)
+			}
+			pretty.WriteByte('\n')
+
+			path := filepath.Join(schemaDumpDir, name+".schema.json")
+			if err := os.WriteFile(path, pretty.Bytes(), 0o644); err != nil { //nolint:gosec // schemas are not secret
+				return fmt.Errorf("failed to write %s: %w", path, err)
+			}
+			fmt.Printf("Wrote %s\n", path)
+		}
+		return nil
+	},
+}
+
+var (
+	dictionaryFormat string
+	dictionaryOutput string
+)
+
+var schemaDictionaryCmd = &cobra.Command{
+	Use:   "dictionary",
+	Short: "Print the data dictionary of the published dataset",
+	Long: `Fetch the data dictionary from /api/v1/schema/data-dictionary: every entity
+of the export and taxa, their fields with types and meanings, and the
+controlled vocabularies (conservation statuses, genera, taxon levels, source
+types) fields draw from. Markdown suits a README shipped with a download.
+
+Examples:
+  oak schema dictionary
+  oak schema dictionary --format json -o dictionary.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dictionaryFormat != "md" && dictionaryFormat != "json" {
+			return usageErrorf("invalid --format %q (must be md or json)", dictionaryFormat)
+		}
+		cmd.SilenceUsage = true // Failures from here on are not about the arguments
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+		dict, err := apiClient.GetDataDictionary(commandContext())
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		var out []byte
+		if dictionaryFormat == "json" {
+			if out, err = json.MarshalIndent(dict, "", "  "); err != nil {
+				return err
+			}
+			out = append(out, '\n')
+		} else {
+			out = []byte(dataDictionaryMarkdown(dict))
+		}
+
+		if dictionaryOutput == "" {
+			_, err := os.Stdout.Write(out)
+			return err
+		}
+		if err := os.WriteFile(dictionaryOutput, out, 0o644); err != nil { //nolint:gosec // the dictionary is public
+			return fmt.Errorf("failed to write %s: %w", dictionaryOutput, err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %s\n", dictionaryOutput)
+		return nil
+	},
+}
+
+// dataDictionaryMarkdown renders a data dictionary as a markdown document:
+// a table of fields per entity, then a table of values per vocabulary.
+func dataDictionaryMarkdown(dict *oakclient.DataDictionary) string {
+	cell := func(s string) string {
+		return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n%s\n", dict.Title, dict.Description)
+	for _, e := range dict.Entities {
+		fmt.Fprintf(&b, "\n## %s\n\n%s\n\n", e.Name, e.Description)
+		b.WriteString("| Field | Type | Required | Description |\n")
+		b.WriteString("|-------|------|----------|-------------|\n")
+		for _, f := range e.Fields {
+			required := ""
+			if f.Required {
+				required = "yes"
+			}
+			description := f.Description
+			if f.Vocabulary != "" {
+				description += " (values: [" + f.Vocabulary + "](#" + f.Vocabulary + "))"
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", f.Name, cell(f.Type), required, cell(description))
+		}
+	}
+
+	if len(dict.Vocabularies) > 0 {
+		b.WriteString("\n## Vocabularies\n")
+	}
+	for _, v := range dict.Vocabularies {
+		fmt.Fprintf(&b, "\n### %s\n\n%s\n\n", v.Name, v.Description)
+		b.WriteString("| Value | Meaning |\n")
+		b.WriteString("|-------|---------|\n")
+		for _, val := range v.Values {
+			fmt.Fprintf(&b, "| `%s` | %s |\n", val.Value, cell(val.Meaning))
+		}
+	}
+	return b.String()
+}
+
+// defaultSchemaDir is where schemas are dumped and where the editor looks for them.
+func defaultSchemaDir() string {
+	return filepath.Join(filepath.Dir(config.DefaultConfigPath()), "schemas")
+}
+
+func init() {
+	editor.SchemaDir = defaultSchemaDir()
+
+	schemaDumpCmd.Flags().StringVar(&schemaDumpDir, "dir", defaultSchemaDir(), "Directory to write schemas to")
+
+	schemaDictionaryCmd.Flags().StringVar(&dictionaryFormat, "format", "md", "Output format: md or json")
+	schemaDictionaryCmd.Flags().StringVarP(&dictionaryOutput, "output", "o", "", "Output file path")
+
+	schemaCmd.AddCommand(schemaDumpCmd)
+	schemaCmd.AddCommand(schemaDictionaryCmd)
+	rootCmd.AddCommand(schemaCmd)
+}
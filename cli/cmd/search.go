@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/names"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var (
+	searchFullText bool
+	searchLimit    int
+	searchColor    string
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search species by name, or all source text by relevance",
+	Long: `Search species by scientific name, or with --full-text search every
+species source text field (leaves, bark, fruits, range, ...) and synonyms,
+ranked by relevance. Full-text results print as score species:location: text,
+most relevant first, where text is a snippet around the matched words.
+
+In full-text queries every word must appear in a field, ignoring case and
+diacritics; "quoted phrases" must appear as written and word* matches words
+starting with word. For every line containing a phrase, use 'oak grep'.
+
+Examples:
+  oak search alba
+  oak search --full-text "stellate hairs" bark
+  oak search --full-text 'tomentos* "acorn cup"' --limit 50`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().BoolVar(&searchFullText, "full-text", false, "Search source text and synonyms, ranked by relevance")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 20, "Maximum number of results (up to 500)")
+	searchCmd.Flags().StringVar(&searchColor, "color", "auto", "When to color output: auto, always, or never")
+	rootCmd.AddCommand(searchCmd)
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	query := strings.Join(args, " ")
+	if strings.TrimSpace(query) == "" {
+		return usageErrorf("search text is empty")
+	}
+	if searchLimit < 1 || searchLimit > 500 {
+		return usageErrorf("--limit must be from 1 to 500")
+	}
+	color, err := useColor(searchColor, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	if !searchFullText {
+		result, err := apiClient.SearchSpecies(ctx, names.NormalizeHybridName(query), searchLimit)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+		if len(result.Data) == 0 {
+			fmt.Fprintf(os.Stderr, "No species match %q\n", query)
+		}
+		for _, entry := range result.Data {
+			fmt.Println(entry.ScientificName)
+		}
+		return nil
+	}
+
+	result, err := apiClient.SearchFullText(ctx, query, searchLimit)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+	if len(result.Data) == 0 {
+		fmt.Fprintf(os.Stderr, "No matches for %q\n", query)
+		return nil
+	}
+	for _, m := range result.Data {
+		fmt.Println(formatFullTextMatch(m, color))
+	}
+	if result.Pagination.Total > len(result.Data) {
+		fmt.Fprintf(os.Stderr, "Showing %d of %d matches; raise --limit to see more\n",
+			len(result.Data), result.Pagination.Total)
+	}
+	return nil
+}
+
+// formatFullTextMatch renders a match as score species:location: text in
+// grep's colors when color is set
+func formatFullTextMatch(m *oakclient.FullTextMatch, color bool) string {
+	prefix := m.ScientificName + ":" + m.Location
+	if color {
+		prefix = ansiMagenta + prefix + ansiReset
+	}
+	// Snippets may span lines; spaces keep them on one and the spans in place
+	text := strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		return r
+	}, m.Text)
+	return fmt.Sprintf("%6.2f  %s: %s", m.Score, prefix, highlightSpans(text, m.Matches, color))
+}
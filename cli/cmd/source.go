@@ -3,6 +3,7 @@ package cmd
 import (
 	"bufio"
 	"fmt"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
@@ -10,23 +11,33 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/jeff/oaks/cli/internal/client"
 	"github.com/jeff/oaks/cli/internal/editor"
 	"github.com/jeff/oaks/cli/internal/models"
+	"github.com/jeff/oaks/cli/internal/names"
+	"github.com/jeff/oaks/pkg/oakclient"
 )
 
 var sourceCmd = &cobra.Command{
-	Use:   "source",
-	Short: "Manage sources",
-	Long:  `Commands for managing source references.`,
+	Use:     "source",
+	Aliases: []string{"sources"},
+	Short:   "Manage sources",
+	Long:    `Commands for managing source references.`,
 }
 
 var (
-	srcNewType  string
-	srcNewName  string
-	srcNewURL   string
-	srcNewDesc  string
-	srcDelForce bool
+	srcNewType    string
+	srcNewName    string
+	srcNewURL     string
+	srcNewDesc    string
+	srcDelForce   bool
+	srcDelCascade bool
+	srcMigMove    bool
+	srcMigYes     bool
+
+	srcListType   string
+	srcListYear   int
+	srcListLimit  int
+	srcListOffset int
 )
 
 var sourceNewCmd = &cobra.Command{
@@ -39,19 +50,44 @@ Otherwise, opens $EDITOR for interactive creation.
 
 Examples:
   oak source new
-  oak source new --type database --name "iNaturalist" --url "https://www.inaturalist.org"`,
+  oak source new --type database --name "iNaturalist" --url "https://www.inaturalist.org"
+  oak source new --file flora.yaml  # Send a YAML file as-is`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		database, err := getDB()
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
 		if err != nil {
 			return err
 		}
-		defer database.Close()
+
+		body, err := readInputBody()
+		if err != nil {
+			return err
+		}
+		if body != nil {
+			if srcNewType != "" || srcNewName != "" || srcNewURL != "" || srcNewDesc != "" {
+				return usageErrorf("--type, --name, --url, and --description cannot be used with --file")
+			}
+			if isActualRemote() && !confirmRemoteOperation("Create", "source from "+inputFile) {
+				fmt.Println("Canceled")
+				return nil
+			}
+			var created oakclient.Source
+			if err := sendInputBody(ctx, apiClient, http.MethodPost, "/api/v1/sources", body, &created); err != nil {
+				return fmt.Errorf("failed to create source: %w", err)
+			}
+			fmt.Printf("Created source with ID: %d\n", created.ID)
+			return nil
+		}
 
 		var source *models.Source
 
 		// If required flags are provided, create non-interactively
 		if srcNewType != "" && srcNewName != "" {
-			source = models.NewSource(srcNewType, srcNewName)
+			sourceType, ok := models.NormalizeSourceType(srcNewType)
+			if !ok {
+				return usageErrorf("invalid --type %q (use %s)", srcNewType, strings.Join(models.SourceTypes, ", "))
+			}
+			source = models.NewSource(sourceType, srcNewName)
 			if srcNewURL != "" {
 				source.URL = &srcNewURL
 			}
@@ -69,12 +105,17 @@ Examples:
 			}
 		}
 
-		id, err := database.InsertSource(source)
+		if isActualRemote() && !confirmRemoteOperation("Create", "source "+source.Name) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		created, err := apiClient.CreateSource(ctx, modelSourceToRequest(source))
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to create source: %w", err)
 		}
 
-		fmt.Printf("Created source with ID: %d\n", id)
+		fmt.Printf("Created source with ID: %d\n", created.ID)
 		return nil
 	},
 }
@@ -82,29 +123,61 @@ Examples:
 var sourceEditCmd = &cobra.Command{
 	Use:   "edit <id>",
 	Short: "Edit an existing source",
-	Long:  `Edit an existing source by opening it in your $EDITOR.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Edit an existing source by opening it in your $EDITOR.
+
+If someone else saves the source while you are editing it, nothing is
+overwritten: your edits are written to a file and the command exits with
+code 5.
+
+With --file, the file replaces the source without opening $EDITOR.
+
+Examples:
+  oak source edit 12
+  oak source edit 12 --file flora.yaml`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
 		id, err := strconv.ParseInt(args[0], 10, 64)
 		if err != nil {
-			return fmt.Errorf("invalid source ID: %s", args[0])
+			return usageErrorf("invalid source ID: %s", args[0])
 		}
+		cmd.SilenceUsage = true // Failures from here on are about the records, not the arguments
 
-		database, err := getDB()
+		apiClient, err := getAPIClient()
 		if err != nil {
 			return err
 		}
-		defer database.Close()
 
-		existing, err := database.GetSource(id)
+		body, err := readInputBody()
 		if err != nil {
 			return err
 		}
-		if existing == nil {
-			return fmt.Errorf("source with ID %d not found", id)
+		if body != nil {
+			if isActualRemote() && !confirmRemoteOperation("Update", fmt.Sprintf("source %d", id)) {
+				fmt.Println("Canceled")
+				return nil
+			}
+			var updated oakclient.Source
+			if err := sendInputBody(ctx, apiClient, http.MethodPut, fmt.Sprintf("/api/v1/sources/%d", id), body, &updated); err != nil {
+				if oakclient.IsNotFoundError(err) {
+					return notFoundErrorf("source with ID %d not found", id)
+				}
+				return fmt.Errorf("failed to update source: %w", err)
+			}
+			fmt.Printf("Updated source: %d\n", updated.ID)
+			return nil
+		}
+
+		// Fetch the source, with the version the save must still match
+		existing, etag, err := apiClient.GetSourceWithETag(ctx, id)
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("source with ID %d not found", id)
+			}
+			return fmt.Errorf("failed to fetch source: %w", err)
 		}
 
-		edited, err := editor.EditSource(existing)
+		edited, err := editor.EditSource(clientSourceToModel(existing))
 		if err != nil {
 			return err
 		}
@@ -112,8 +185,16 @@ var sourceEditCmd = &cobra.Command{
 		// Preserve the ID (cannot be changed)
 		edited.ID = existing.ID
 
-		if err := database.UpdateSource(edited); err != nil {
-			return err
+		if isActualRemote() && !confirmRemoteOperation("Update", fmt.Sprintf("source %d", edited.ID)) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		if _, err := apiClient.UpdateSourceIfMatch(ctx, id, modelSourceToRequest(edited), etag); err != nil {
+			if oakclient.IsPreconditionFailedError(err) {
+				return editConflict(fmt.Sprintf("source %d", id), edited, "")
+			}
+			return fmt.Errorf("failed to update source: %w", err)
 		}
 
 		fmt.Printf("Updated source: %d\n", edited.ID)
@@ -124,30 +205,44 @@ var sourceEditCmd = &cobra.Command{
 var sourceListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all sources",
-	Long:  `Display all existing sources in a table format.`,
+	Long: `Display existing sources in a table format.
+
+Examples:
+  oak source list
+  oak source list --type book --year 2004
+  oak source list --limit 20 --offset 40`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runSourceList()
 	},
 }
 
 func runSourceList() error {
+	ctx := commandContext()
 	apiClient, err := getAPIClient()
 	if err != nil {
 		return err
 	}
 
-	sources, err := apiClient.ListSources()
+	params := &oakclient.SourceListParams{Limit: srcListLimit, Offset: srcListOffset}
+	if srcListType != "" {
+		params.SourceType = &srcListType
+	}
+	if srcListYear != 0 {
+		params.Year = &srcListYear
+	}
+	resp, err := apiClient.ListSources(ctx, params)
 	if err != nil {
 		return fmt.Errorf("API error: %w", err)
 	}
 
 	// Convert to models for printing
-	modelSources := make([]*models.Source, len(sources))
-	for i, s := range sources {
+	modelSources := make([]*models.Source, len(resp.Data))
+	for i, s := range resp.Data {
 		modelSources[i] = clientSourceToModel(s)
 	}
 
 	printSourceList(modelSources)
+	printMorePages(resp.Pagination, len(resp.Data))
 	return nil
 }
 
@@ -178,22 +273,23 @@ var sourceShowCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id, err := strconv.ParseInt(args[0], 10, 64)
 		if err != nil {
-			return fmt.Errorf("invalid source ID: %s", args[0])
+			return usageErrorf("invalid source ID: %s", args[0])
 		}
 		return runSourceShow(id)
 	},
 }
 
 func runSourceShow(id int64) error {
+	ctx := commandContext()
 	apiClient, err := getAPIClient()
 	if err != nil {
 		return err
 	}
 
-	source, err := apiClient.GetSource(id)
+	source, err := apiClient.GetSource(ctx, id)
 	if err != nil {
-		if client.IsNotFoundError(err) {
-			return fmt.Errorf("source with ID %d not found", id)
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("source with ID %d not found", id)
 		}
 		return fmt.Errorf("API error: %w", err)
 	}
@@ -207,30 +303,45 @@ var sourceDeleteCmd = &cobra.Command{
 	Short: "Delete a source",
 	Long: `Delete a source by ID.
 
-Note: This will fail if the source is referenced by any species notes.
-Delete those notes first, or use a different source.
+A source that species data, other sources (via superseded_by), or templates
+still refer to cannot be deleted. Run 'oak source usage <id>' to see what
+refers to it, then either migrate that data to another source
+('oak source migrate') or pass --cascade to delete the source together
+with its species data and remove it from superseded sources and templates.
 
 Examples:
-  oak source delete 5`,
+  oak source delete 5
+  oak source delete 5 --cascade --force`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
 		id, err := strconv.ParseInt(args[0], 10, 64)
 		if err != nil {
-			return fmt.Errorf("invalid source ID: %s", args[0])
+			return usageErrorf("invalid source ID: %s", args[0])
 		}
 
-		database, err := getDB()
+		apiClient, err := getAPIClient()
 		if err != nil {
 			return err
 		}
-		defer database.Close()
 
-		source, err := database.GetSource(id)
+		source, err := apiClient.GetSource(ctx, id)
 		if err != nil {
-			return err
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("source with ID %d not found", id)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		usage, err := apiClient.GetSourceUsage(ctx, id)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
 		}
-		if source == nil {
-			return fmt.Errorf("source with ID %d not found", id)
+		if usage.InUse() {
+			printSourceUsage(usage, 10)
+			if !srcDelCascade {
+				return &exitError{code: ExitConflict, err: fmt.Errorf("source %d is still in use; pass --cascade to delete it along with the references above", id)}
+			}
 		}
 
 		// Confirm deletion unless --force
@@ -248,8 +359,8 @@ Examples:
 			}
 		}
 
-		if err := database.DeleteSource(id); err != nil {
-			return err
+		if err := apiClient.DeleteSource(ctx, id, srcDelCascade); err != nil {
+			return fmt.Errorf("API error: %w", err)
 		}
 
 		fmt.Printf("Deleted source: %d\n", id)
@@ -257,6 +368,194 @@ Examples:
 	},
 }
 
+var sourceUsageCmd = &cobra.Command{
+	Use:   "usage <id>",
+	Short: "Show what refers to a source",
+	Long: `List the species with data from a source, the sources it supersedes,
+and the templates that list it. These are the references a forced
+'oak source delete --cascade' would remove.
+
+Examples:
+  oak source usage 2
+  oak sources usage 2 --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return usageErrorf("invalid source ID: %s", args[0])
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		source, err := apiClient.GetSource(ctx, id)
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("source with ID %d not found", id)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		usage, err := apiClient.GetSourceUsage(ctx, id)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Source %d: %s\n", id, source.Name)
+		if !usage.InUse() {
+			fmt.Println("Not in use; it can be deleted safely")
+			return nil
+		}
+		printSourceUsage(usage, 0)
+		return nil
+	},
+}
+
+// printSourceUsage lists everything that refers to a source, showing at
+// most max names per kind (0 for all)
+func printSourceUsage(usage *oakclient.SourceUsage, max int) {
+	ids := make([]string, len(usage.SupersededSources))
+	for i, sid := range usage.SupersededSources {
+		ids[i] = strconv.FormatInt(sid, 10)
+	}
+	for _, kind := range []struct {
+		label string
+		names []string
+	}{
+		{"Species", usage.Species},
+		{"Supersedes sources", ids},
+		{"Templates", usage.Templates},
+	} {
+		if len(kind.names) == 0 {
+			continue
+		}
+		names := kind.names
+		more := ""
+		if max > 0 && len(names) > max {
+			more = fmt.Sprintf(", and %d more", len(names)-max)
+			names = names[:max]
+		}
+		fmt.Printf("%s (%d): %s%s\n", kind.label, len(kind.names), strings.Join(names, ", "), more)
+	}
+}
+
+var sourceCoverageCmd = &cobra.Command{
+	Use:   "coverage <id>",
+	Short: "Report which species and fields a source covers",
+	Long: `Show how many species a source describes, how often it fills in each
+field, and which species in its scope it has no data for. A source's scope
+is every section it describes at least one species in.
+
+Examples:
+  oak source coverage 2
+  oak sources coverage 2 --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return usageErrorf("invalid source ID: %s", args[0])
+		}
+		return runSourceCoverage(id)
+	},
+}
+
+func runSourceCoverage(id int64) error {
+	ctx := commandContext()
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	source, err := apiClient.GetSource(ctx, id)
+	if err != nil {
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("source with ID %d not found", id)
+		}
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	cov, err := apiClient.GetSourceCoverage(ctx, id)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	fmt.Printf("Source %d: %s\n", id, source.Name)
+	fmt.Printf("Species described: %d\n", cov.SpeciesCount)
+	if cov.SpeciesCount == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FIELD\tSPECIES\tCOVERAGE")
+	fmt.Fprintln(w, "-----\t-------\t--------")
+	for _, f := range cov.Fields {
+		fmt.Fprintf(w, "%s\t%d\t%d%%\n", f.Field, f.Count, f.Count*100/cov.SpeciesCount)
+	}
+	w.Flush()
+
+	if len(cov.ScopeSections) == 0 {
+		return nil
+	}
+	fmt.Printf("\nScope: section %s (%d species)\n", strings.Join(cov.ScopeSections, ", "), cov.ScopeCount)
+	if len(cov.MissingSpecies) == 0 {
+		fmt.Println("No species missing in scope")
+		return nil
+	}
+	fmt.Printf("Missing %d species:\n", len(cov.MissingSpecies))
+	for _, name := range cov.MissingSpecies {
+		fmt.Printf("  %s\n", name)
+	}
+	return nil
+}
+
+var sourcePreferCmd = &cobra.Command{
+	Use:   "prefer <species> <source-id>",
+	Short: "Set a species' preferred source",
+	Long: `Mark one source as the preferred source for a species. The species'
+other sources are no longer preferred.
+
+Examples:
+  oak source prefer alba 3
+  oak source prefer "× bebbiana" 2 --remote`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		name := names.NormalizeHybridName(args[0])
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return usageErrorf("invalid source ID: %s", args[1])
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if isActualRemote() && !confirmRemoteOperation("Set preferred source for", name) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		if _, err := apiClient.SetPreferredSource(ctx, name, id); err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("%v", err)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		if isActualRemote() {
+			fmt.Printf("Preferred source for %s on [%s]: %d\n", name, apiClient.ProfileName(), id)
+		} else {
+			fmt.Printf("Preferred source for %s: %d\n", name, id)
+		}
+		return nil
+	},
+}
+
 func printSource(s *models.Source) {
 	fmt.Printf("ID:          %d\n", s.ID)
 	fmt.Printf("Type:        %s\n", s.SourceType)
@@ -282,39 +581,218 @@ func printSource(s *models.Source) {
 	if s.Notes != nil {
 		fmt.Printf("Notes:       %s\n", *s.Notes)
 	}
+	if s.SupersededBy != nil {
+		fmt.Printf("Superseded:  by source %d\n", *s.SupersededBy)
+	}
+}
+
+var sourceSupersedeCmd = &cobra.Command{
+	Use:   "supersede <old-id> <new-id>",
+	Short: "Mark a source as superseded by another",
+	Long: `Mark a source as replaced by a newer one, e.g. a revised edition.
+Editing species data attributed to a superseded source prints a warning.
+Use 'oak source migrate' to carry its species data over to the new source.
+
+Examples:
+  oak source supersede 2 7`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		oldID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return usageErrorf("invalid source ID: %s", args[0])
+		}
+		newID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return usageErrorf("invalid source ID: %s", args[1])
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		source, err := apiClient.GetSource(ctx, oldID)
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("source with ID %d not found", oldID)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		if isActualRemote() && !confirmRemoteOperation("Supersede source", source.Name) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		req := oakclient.SourceToRequest(source)
+		req.SupersededBy = &newID
+		if _, err := apiClient.UpdateSource(ctx, oldID, req); err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Source %d (%s) is now superseded by source %d\n", oldID, source.Name, newID)
+		return nil
+	},
+}
+
+var sourceMigrateCmd = &cobra.Command{
+	Use:   "migrate <old-id> <new-id>",
+	Short: "Copy or move species data from one source to another",
+	Long: `Copy every species' data from one source to another, typically from a
+superseded source to its replacement. Species the new source already
+describes are skipped. With --move the rows are removed from the old source.
+
+The planned changes are listed for review before anything is written.
+
+Examples:
+  oak source migrate 2 7
+  oak source migrate 2 7 --move --yes`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return usageErrorf("invalid source ID: %s", args[0])
+		}
+		newID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return usageErrorf("invalid source ID: %s", args[1])
+		}
+		return runSourceMigrate(oldID, newID)
+	},
+}
+
+func runSourceMigrate(oldID, newID int64) error {
+	ctx := commandContext()
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	plan, err := apiClient.MigrateSource(ctx, oldID, newID, srcMigMove, true)
+	if err != nil {
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("%v", err)
+		}
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	if len(plan.Items) == 0 {
+		fmt.Printf("Source %d has no species data to migrate\n", oldID)
+		return nil
+	}
+
+	pending := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SPECIES\tACTION")
+	fmt.Fprintln(w, "-------\t------")
+	for _, item := range plan.Items {
+		fmt.Fprintf(w, "%s\t%s\n", item.ScientificName, item.Action)
+		if item.Action != "skip" {
+			pending++
+		}
+	}
+	w.Flush()
+
+	if pending == 0 {
+		fmt.Printf("\nSource %d already has data for every species\n", newID)
+		return nil
+	}
+
+	if !srcMigYes {
+		fmt.Printf("\nApply %d change(s) from source %d to source %d? (y/N): ", pending, oldID, newID)
+		reader := bufio.NewReader(os.Stdin)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Canceled")
+			return nil
+		}
+	} else if isActualRemote() && !confirmRemoteOperation("Migrate species data from source", strconv.FormatInt(oldID, 10)) {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	if _, err := apiClient.MigrateSource(ctx, oldID, newID, srcMigMove, false); err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	verb := "Copied"
+	if srcMigMove {
+		verb = "Moved"
+	}
+	fmt.Printf("%s %d species from source %d to source %d\n", verb, pending, oldID, newID)
+	return nil
 }
 
-// clientSourceToModel converts a client.Source to models.Source.
-func clientSourceToModel(s *client.Source) *models.Source {
+// clientSourceToModel converts a oakclient.Source to models.Source.
+func clientSourceToModel(s *oakclient.Source) *models.Source {
 	return &models.Source{
-		ID:          s.ID,
-		SourceType:  s.SourceType,
-		Name:        s.Name,
-		Description: s.Description,
-		Author:      s.Author,
-		Year:        s.Year,
-		URL:         s.URL,
-		ISBN:        s.ISBN,
-		DOI:         s.DOI,
-		Notes:       s.Notes,
-		License:     s.License,
-		LicenseURL:  s.LicenseURL,
+		ID:           s.ID,
+		SourceType:   s.SourceType,
+		Name:         s.Name,
+		Description:  s.Description,
+		Author:       s.Author,
+		Year:         s.Year,
+		URL:          s.URL,
+		ISBN:         s.ISBN,
+		DOI:          s.DOI,
+		Notes:        s.Notes,
+		License:      s.License,
+		LicenseURL:   s.LicenseURL,
+		SupersededBy: s.SupersededBy,
+	}
+}
+
+// modelSourceToRequest converts a models.Source to a oakclient.SourceRequest.
+func modelSourceToRequest(s *models.Source) *oakclient.SourceRequest {
+	return &oakclient.SourceRequest{
+		SourceType:   s.SourceType,
+		Name:         s.Name,
+		Description:  s.Description,
+		Author:       s.Author,
+		Year:         s.Year,
+		URL:          s.URL,
+		ISBN:         s.ISBN,
+		DOI:          s.DOI,
+		Notes:        s.Notes,
+		License:      s.License,
+		LicenseURL:   s.LicenseURL,
+		SupersededBy: s.SupersededBy,
 	}
 }
 
 func init() {
-	sourceNewCmd.Flags().StringVar(&srcNewType, "type", "", "Source type (required for non-interactive)")
+	sourceNewCmd.Flags().StringVar(&srcNewType, "type", "", "Source type: book, paper, website, herbarium, personal-observation, or database (required for non-interactive)")
 	sourceNewCmd.Flags().StringVar(&srcNewName, "name", "", "Source name (required for non-interactive)")
 	sourceNewCmd.Flags().StringVar(&srcNewURL, "url", "", "Source URL (optional)")
 	sourceNewCmd.Flags().StringVar(&srcNewDesc, "description", "", "Source description (optional)")
+	addInputFlags(sourceNewCmd)
+	addInputFlags(sourceEditCmd)
 
 	sourceCmd.AddCommand(sourceNewCmd)
 	sourceCmd.AddCommand(sourceEditCmd)
 	sourceCmd.AddCommand(sourceListCmd)
 	sourceCmd.AddCommand(sourceShowCmd)
 	sourceCmd.AddCommand(sourceDeleteCmd)
+	sourceCmd.AddCommand(sourcePreferCmd)
+	sourceCmd.AddCommand(sourceCoverageCmd)
+	sourceCmd.AddCommand(sourceUsageCmd)
+	sourceCmd.AddCommand(sourceSupersedeCmd)
+	sourceCmd.AddCommand(sourceMigrateCmd)
+
+	sourceListCmd.Flags().StringVar(&srcListType, "type", "", "Only list sources of this type (e.g. book, website)")
+	sourceListCmd.Flags().IntVar(&srcListYear, "year", 0, "Only list sources published in this year")
+	sourceListCmd.Flags().IntVar(&srcListLimit, "limit", 0, "Maximum number of sources to list (default all)")
+	sourceListCmd.Flags().IntVar(&srcListOffset, "offset", 0, "Number of sources to skip")
 
 	sourceDeleteCmd.Flags().BoolVar(&srcDelForce, "force", false, "Skip confirmation prompt")
+	sourceDeleteCmd.Flags().BoolVar(&srcDelCascade, "cascade", false, "Also delete the source's species data and remove it from superseded sources and templates")
+	sourceMigrateCmd.Flags().BoolVar(&srcMigMove, "move", false, "Remove migrated rows from the old source")
+	sourceMigrateCmd.Flags().BoolVar(&srcMigYes, "yes", false, "Apply without reviewing the plan")
 
 	rootCmd.AddCommand(sourceCmd)
 }
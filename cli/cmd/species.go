@@ -0,0 +1,629 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/models"
+	"github.com/jeff/oaks/cli/internal/names"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var speciesCmd = &cobra.Command{
+	Use:   "species",
+	Short: "Create species and manage their publication state",
+	Long: `Commands for species entries as a whole.
+
+Draft entries are visible only to authenticated curators; they are left out
+of public lists, search, and the web export until published.`,
+}
+
+var speciesNewCmd = &cobra.Command{
+	Use:   "new <name>",
+	Short: "Create a new Oak entry (same as 'oak new')",
+	Long: `Creates a new Oak entry by opening your $EDITOR, optionally pre-filled
+from a template for its taxonomic group.
+
+Examples:
+  oak species new velutina --template lobatae
+  oak species new alba --draft`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runNew(names.NormalizeHybridName(args[0]))
+	},
+}
+
+var speciesPublishCmd = &cobra.Command{
+	Use:   "publish <name>",
+	Short: "Publish a draft species entry",
+	Long: `Make a draft species entry public.
+
+Examples:
+  oak species publish alba
+  oak species publish alba --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSetVisibility(names.NormalizeHybridName(args[0]), models.VisibilityPublished)
+	},
+}
+
+var speciesUnpublishCmd = &cobra.Command{
+	Use:   "unpublish <name>",
+	Short: "Return a species entry to draft",
+	Long: `Hide a species entry from the public by marking it a draft.
+
+Examples:
+  oak species unpublish alba --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSetVisibility(names.NormalizeHybridName(args[0]), models.VisibilityDraft)
+	},
+}
+
+func runSetVisibility(name, visibility string) error {
+	ctx := commandContext()
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	action := "Publish"
+	if visibility == models.VisibilityDraft {
+		action = "Unpublish"
+	}
+	if isActualRemote() && !confirmRemoteOperation(action, name) {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	if _, err := apiClient.SetSpeciesVisibility(ctx, name, visibility); err != nil {
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("oak entry '%s' not found", name)
+		}
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	if isActualRemote() {
+		fmt.Printf("%s is now %s on [%s]\n", name, visibility, apiClient.ProfileName())
+	} else {
+		fmt.Printf("%s is now %s\n", name, visibility)
+	}
+	return nil
+}
+
+var (
+	scheduleAt   string
+	scheduleNote string
+	scheduledAll bool
+)
+
+var speciesScheduleCmd = &cobra.Command{
+	Use:   "schedule <name>... --at <time>",
+	Short: "Schedule draft species to be published later",
+	Long: `Queue one or more draft species to be published together at a set time,
+for example to coincide with a blog post. The API server publishes them
+within a minute of the scheduled time.
+
+--at takes an RFC 3339 time; a time without a zone offset is read as local time.
+
+Examples:
+  oak species schedule alba rubra --at 2026-11-01T09:00:00Z --remote
+  oak species schedule velutina --at 2026-11-01T09:00 --note "Red oaks post"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		at, err := parseScheduleTime(scheduleAt)
+		if err != nil {
+			return err
+		}
+
+		species := make([]string, len(args))
+		for i, arg := range args {
+			species[i] = names.NormalizeHybridName(arg)
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if isActualRemote() && !confirmRemoteOperation("Schedule publication of", strings.Join(species, ", ")) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		pub, err := apiClient.SchedulePublication(ctx, species, at, scheduleNote)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Scheduled publication %d: %d species at %s\n",
+			pub.ID, len(pub.Species), pub.PublishAt.Local().Format("2006-01-02 15:04 MST"))
+		return nil
+	},
+}
+
+// parseScheduleTime accepts RFC 3339, or a zone-less date and time read as local time
+func parseScheduleTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, usageErrorf("--at is required")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, usageErrorf("invalid --at %q: use a time like 2026-11-01T09:00:00Z", value)
+}
+
+var speciesScheduledCmd = &cobra.Command{
+	Use:   "scheduled",
+	Short: "List scheduled publications",
+	Long: `List publications waiting to go live. Use --all to include ones already
+published or canceled.
+
+Examples:
+  oak species scheduled --remote
+  oak species scheduled --all`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		pubs, err := apiClient.ListScheduledPublications(ctx, scheduledAll)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+		if len(pubs) == 0 {
+			fmt.Println("No scheduled publications")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tPUBLISH AT\tSTATUS\tSPECIES\tNOTE")
+		fmt.Fprintln(w, "--\t----------\t------\t-------\t----")
+		for _, p := range pubs {
+			note := ""
+			if p.Note != nil {
+				note = *p.Note
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", p.ID, p.PublishAt.Local().Format("2006-01-02 15:04 MST"),
+				p.Status, strings.Join(p.Species, ", "), note)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var speciesMentionsCmd = &cobra.Command{
+	Use:   "mentions <name>",
+	Short: "List species whose text mentions this one",
+	Long: `List the species whose long-form accounts or source notes mention this
+one, either as a [[link]] or by name ("Q. stellata"). Mentions are detected
+when that text is saved; run 'oak db reindex' after creating species that
+older text already names.
+
+Examples:
+  oak species mentions stellata
+  oak species mentions "× bebbiana" --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		name := names.NormalizeHybridName(args[0])
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		refs, err := apiClient.ListSpeciesMentions(ctx, name)
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("species '%s' not found", name)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+		if len(refs) == 0 {
+			fmt.Printf("No species mention %s\n", name)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SPECIES\tLOCATION\tCONTEXT")
+		fmt.Fprintln(w, "-------\t--------\t-------")
+		for _, ref := range refs {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", ref.ScientificName, ref.Location, ref.Context)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var speciesMeasurementsCmd = &cobra.Command{
+	Use:   "measurements <name>",
+	Short: "List the measurements parsed from a species' source text",
+	Long: `List the lengths found in a species' source text, such as "5-12 cm" or
+"up to 30 m", with their parsed min/max values and units. Measurements are
+parsed when the text is saved; 'oak db reindex' re-parses all of them.
+
+Examples:
+  oak species measurements alba
+  oak species measurements alba --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		name := names.NormalizeHybridName(args[0])
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		measurements, err := apiClient.ListSpeciesMeasurements(ctx, name)
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("species '%s' not found", name)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+		if len(measurements) == 0 {
+			fmt.Printf("No measurements found for %s\n", name)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SOURCE\tFIELD\tTEXT\tMIN\tMAX\tUNIT")
+		fmt.Fprintln(w, "------\t-----\t----\t---\t---\t----")
+		for _, m := range measurements {
+			min := "-"
+			if m.Min != nil {
+				min = strconv.FormatFloat(*m.Min, 'f', -1, 64)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", m.SourceID, m.Field, m.Text, min,
+				strconv.FormatFloat(m.Max, 'f', -1, 64), m.Unit)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var speciesNamesSet []string
+
+var speciesNamesCmd = &cobra.Command{
+	Use:   "names <name>",
+	Short: "Show or replace a species' common names",
+	Long: `Show a species' common names by language, or replace them all with
+--set lang=name, repeated once per name. Languages are BCP 47 tags such as
+en, fr-CA, or zh-Hant; a name with no language (=name) is stored as und.
+Names keep their order within each language. The export groups them per
+language under common_names.
+
+Examples:
+  oak species names alba
+  oak species names alba --set "en=White oak" --set "fr=Chêne blanc" --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		name := names.NormalizeHybridName(args[0])
+
+		var commonNames []*oakclient.CommonName
+		for _, arg := range speciesNamesSet {
+			lang, value, ok := strings.Cut(arg, "=")
+			if !ok || strings.TrimSpace(value) == "" {
+				return usageErrorf("--set takes lang=name, got %q", arg)
+			}
+			commonNames = append(commonNames, &oakclient.CommonName{Name: value, Language: lang})
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if cmd.Flags().Changed("set") {
+			if isActualRemote() && !confirmRemoteOperation("Replace common names of", name) {
+				fmt.Println("Canceled")
+				return nil
+			}
+			commonNames, err = apiClient.SetCommonNames(ctx, name, commonNames)
+		} else {
+			commonNames, err = apiClient.GetCommonNames(ctx, name)
+		}
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("species '%s' not found", name)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+		if len(commonNames) == 0 {
+			fmt.Printf("No common names for %s\n", name)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "LANGUAGE\tNAME\tSOURCE")
+		fmt.Fprintln(w, "--------\t----\t------")
+		for _, n := range commonNames {
+			source := "-"
+			if n.SourceID != nil {
+				source = strconv.FormatInt(*n.SourceID, 10)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", n.Language, n.Name, source)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var (
+	popularDays  int
+	popularLimit int
+)
+
+var speciesPopularCmd = &cobra.Command{
+	Use:   "popular",
+	Short: "List the most-viewed species",
+	Long: `List the species whose public pages were viewed most over the last --days
+days, to help decide which entries to curate first. Views are counted per
+day only when the server runs with OAK_ANALYTICS=true; curator reads and
+drafts are not counted.
+
+Examples:
+  oak species popular --remote
+  oak species popular --days 90 --limit 50 --remote`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		popular, err := apiClient.PopularSpecies(ctx, popularDays, popularLimit)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+		if !popular.AnalyticsEnabled {
+			fmt.Fprintln(os.Stderr, "Note: analytics are off on this server (set OAK_ANALYTICS=true); counts may be stale")
+		}
+		if len(popular.Data) == 0 {
+			fmt.Printf("No species views since %s\n", popular.Since)
+			return nil
+		}
+
+		fmt.Printf("Views since %s (%d days)\n\n", popular.Since, popular.Days)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SPECIES\tVIEWS\tLAST VIEWED")
+		fmt.Fprintln(w, "-------\t-----\t-----------")
+		for _, v := range popular.Data {
+			fmt.Fprintf(w, "%s\t%d\t%s\n", v.ScientificName, v.Views, v.LastViewed)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var speciesUnscheduleCmd = &cobra.Command{
+	Use:   "unschedule <id>",
+	Short: "Cancel a scheduled publication",
+	Long: `Cancel a pending publication. Its species stay drafts.
+
+Examples:
+  oak species unschedule 3 --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return usageErrorf("invalid publication ID: %s", args[0])
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if isActualRemote() && !confirmRemoteOperation("Cancel scheduled publication", args[0]) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		if err := apiClient.CancelScheduledPublication(ctx, id); err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("scheduled publication %d not found", id)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Canceled scheduled publication %d\n", id)
+		return nil
+	},
+}
+
+var historyRestore int64
+
+var speciesHistoryCmd = &cobra.Command{
+	Use:   "history <name>",
+	Short: "List a species' earlier versions, or restore one",
+	Long: `List the earlier versions of a species entry, newest first, with the
+fields each change replaced. Every save that changes an entry keeps the
+version it replaced, so --restore <id> undoes an accidental edit by saving
+that version back. The restore is kept in the history as well, and a deleted
+species can be restored the same way. The entry's hybrids list and visibility
+are left as they are.
+
+Examples:
+  oak species history alba
+  oak species history alba --restore 12 --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		name := names.NormalizeHybridName(args[0])
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if cmd.Flags().Changed("restore") {
+			if isActualRemote() && !confirmRemoteOperation(fmt.Sprintf("Restore revision %d of", historyRestore), name) {
+				fmt.Println("Canceled")
+				return nil
+			}
+			if _, err := apiClient.RestoreSpeciesRevision(ctx, name, historyRestore); err != nil {
+				if oakclient.IsNotFoundError(err) {
+					return notFoundErrorf("species '%s' has no revision %d", name, historyRestore)
+				}
+				return fmt.Errorf("API error: %w", err)
+			}
+			fmt.Printf("Restored %s to revision %d\n", name, historyRestore)
+			return nil
+		}
+
+		revisions, err := apiClient.ListSpeciesRevisions(ctx, name)
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("species '%s' not found", name)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+		if len(revisions) == 0 {
+			fmt.Printf("No earlier versions of %s\n", name)
+			return nil
+		}
+
+		// Each revision was replaced by the one before it in the list, and
+		// the newest by the current entry, if there still is one
+		next, err := apiClient.GetSpecies(ctx, name)
+		if err != nil && !oakclient.IsNotFoundError(err) {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tREPLACED\tCHANGED")
+		fmt.Fprintln(w, "--\t--------\t-------")
+		for _, rev := range revisions {
+			changed := "(deleted)"
+			if next != nil {
+				changed = strings.Join(revisionChanges(rev.Entry, next), ", ")
+			}
+			if changed == "" {
+				changed = "-"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", rev.ID, rev.CreatedAt.Local().Format("2006-01-02 15:04 MST"), changed)
+			next = rev.Entry
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var speciesTimelineCmd = &cobra.Command{
+	Use:   "timeline <name>",
+	Short: "Show everything that happened to a species, oldest first",
+	Long: `Show a chronological view of a species: edits and status changes (from
+'oak species history'), source data added and verified, account saves,
+proposed distinguishing features and range localities and their review,
+conflicts between sources, and scheduled publication. The REF column is the
+revision, suggestion, locality, conflict, or publication ID.
+
+Examples:
+  oak species timeline alba
+  oak species timeline "× bebbiana" --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		name := names.NormalizeHybridName(args[0])
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		events, err := apiClient.SpeciesTimeline(ctx, name)
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("species '%s' not found", name)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+		if len(events) == 0 {
+			fmt.Printf("Nothing recorded for %s yet\n", name)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "WHEN\tEVENT\tREF\tSUMMARY")
+		fmt.Fprintln(w, "----\t-----\t---\t-------")
+		for _, e := range events {
+			ref := "-"
+			if e.RefID != nil {
+				ref = strconv.FormatInt(*e.RefID, 10)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.At.Local().Format("2006-01-02 15:04 MST"), e.Kind, ref, e.Summary)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+// revisionChanges lists the fields that differ between an earlier version
+// of an entry and the one that replaced it
+func revisionChanges(before, after *oakclient.OakEntry) []string {
+	fields := func(e *oakclient.OakEntry) map[string]interface{} {
+		var m map[string]interface{}
+		data, _ := json.Marshal(e)
+		_ = json.Unmarshal(data, &m)
+		delete(m, "slug")
+		return m
+	}
+	seen := map[string]bool{}
+	var changed []string
+	for _, d := range diffFields(fields(before), fields(after)) {
+		// Report a list or an object once, not each of its leaves
+		field, _, _ := strings.Cut(d.Path, ".")
+		if !seen[field] {
+			seen[field] = true
+			changed = append(changed, field)
+		}
+	}
+	return changed
+}
+
+func init() {
+	speciesScheduleCmd.Flags().StringVar(&scheduleAt, "at", "", "When to publish (RFC 3339, e.g. 2026-11-01T09:00:00Z)")
+	speciesScheduleCmd.Flags().StringVar(&scheduleNote, "note", "", "Why these species go live together")
+	speciesScheduledCmd.Flags().BoolVar(&scheduledAll, "all", false, "Include published and canceled publications")
+	speciesPopularCmd.Flags().IntVar(&popularDays, "days", 30, "Count views over this many days, including today")
+	speciesPopularCmd.Flags().IntVar(&popularLimit, "limit", 20, "Maximum number of species to list")
+	speciesNamesCmd.Flags().StringArrayVar(&speciesNamesSet, "set", nil, "Replace the common names with lang=name (repeatable)")
+	speciesHistoryCmd.Flags().Int64Var(&historyRestore, "restore", 0, "Restore the revision with this ID")
+
+	addNewFlags(speciesNewCmd)
+	speciesCmd.AddCommand(speciesNewCmd)
+	speciesCmd.AddCommand(speciesPublishCmd)
+	speciesCmd.AddCommand(speciesUnpublishCmd)
+	speciesCmd.AddCommand(speciesScheduleCmd)
+	speciesCmd.AddCommand(speciesScheduledCmd)
+	speciesCmd.AddCommand(speciesUnscheduleCmd)
+	speciesCmd.AddCommand(speciesMentionsCmd)
+	speciesCmd.AddCommand(speciesMeasurementsCmd)
+	speciesCmd.AddCommand(speciesNamesCmd)
+	speciesCmd.AddCommand(speciesPopularCmd)
+	speciesCmd.AddCommand(speciesHistoryCmd)
+	speciesCmd.AddCommand(speciesTimelineCmd)
+	rootCmd.AddCommand(speciesCmd)
+}
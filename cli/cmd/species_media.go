@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/names"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var speciesMediaCmd = &cobra.Command{
+	Use:   "media",
+	Short: "List, attach, and remove a species' photos",
+}
+
+var (
+	mediaKind         string
+	mediaSourceID     int64
+	mediaCaption      string
+	mediaLicense      string
+	mediaAttribution  string
+	mediaThumbnailURL string
+)
+
+var speciesMediaAddCmd = &cobra.Command{
+	Use:   "add <name> <file-or-url>",
+	Short: "Attach a photo to a species",
+	Long: `Attach a photo to a species. A local JPEG, PNG, or GIF file is uploaded:
+the server keeps it, makes a thumbnail, and reads when and where it was taken
+from its EXIF data. An http(s) URL is attached as a link to media kept
+elsewhere, with --thumbnail-url for a smaller version.
+
+--kind says what the photo shows: ` + strings.Join(oakclient.MediaKinds, ", ") + `.
+--source-id credits a source; the link is dropped if the source is deleted.
+
+Examples:
+  oak species media add alba ./alba_bark.jpg --kind bark --license "CC BY 4.0"
+  oak species media add alba https://example.org/alba.jpg --kind habit --attribution "J. Smith" --remote`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		name := names.NormalizeHybridName(args[0])
+		target := args[1]
+		if mediaKind != "" && !slices.Contains(oakclient.MediaKinds, mediaKind) {
+			return usageErrorf("--kind must be one of %s", strings.Join(oakclient.MediaKinds, ", "))
+		}
+		isURL := strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://")
+		if !isURL {
+			if _, err := os.Stat(target); err != nil {
+				return usageErrorf("%s is not a file or an http(s) URL", target)
+			}
+			if mediaThumbnailURL != "" {
+				return usageErrorf("--thumbnail-url is only for URLs; uploads get a thumbnail")
+			}
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if isActualRemote() && !confirmRemoteOperation("Attach a photo to", name) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		var m *oakclient.SpeciesMedia
+		if isURL {
+			m, err = apiClient.LinkSpeciesMedia(ctx, name, &oakclient.MediaLink{
+				URL:          target,
+				ThumbnailURL: optionalFlag(mediaThumbnailURL),
+				Kind:         optionalFlag(mediaKind),
+				Caption:      optionalFlag(mediaCaption),
+				License:      optionalFlag(mediaLicense),
+				Attribution:  optionalFlag(mediaAttribution),
+				SourceID:     optionalID(mediaSourceID),
+			})
+		} else {
+			var f *os.File
+			if f, err = os.Open(target); err != nil {
+				return err
+			}
+			defer f.Close()
+			m, err = apiClient.UploadSpeciesMedia(ctx, name, oakclient.MediaUpload{
+				Filename:    filepath.Base(target),
+				Content:     f,
+				Kind:        mediaKind,
+				SourceID:    mediaSourceID,
+				License:     mediaLicense,
+				Caption:     mediaCaption,
+				Attribution: mediaAttribution,
+			})
+		}
+		switch {
+		case oakclient.IsNotFoundError(err):
+			return notFoundErrorf("species '%s' not found", name)
+		case oakclient.IsConflictError(err), errors.Is(err, oakclient.ErrValidation):
+			return err
+		case err != nil:
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Attached media %d to %s%s\n", m.ID, m.ScientificName, imageDetails(m))
+		return nil
+	},
+}
+
+var speciesMediaListCmd = &cobra.Command{
+	Use:   "list <name>",
+	Short: "List a species' photos",
+	Long: `List a species' photos in order, only those of one --kind if given.
+Uploads show the file in the server's media directory; others their URL.
+
+Examples:
+  oak species media list alba
+  oak species media list alba --kind acorn`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		name := names.NormalizeHybridName(args[0])
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		media, err := apiClient.ListSpeciesMedia(ctx, name, mediaKind)
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("species '%s' not found", name)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+		if len(media) == 0 {
+			fmt.Printf("No media for %s\n", name)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tKIND\tORIGIN\tFILE/URL\tCAPTION\tLICENSE")
+		fmt.Fprintln(w, "--\t----\t------\t--------\t-------\t-------")
+		for _, m := range media {
+			location := m.URL
+			if m.File != nil {
+				location = *m.File
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+				m.ID, orDash(m.Kind), m.Origin, location, orDash(m.Caption), orDash(m.License))
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var speciesMediaRmCmd = &cobra.Command{
+	Use:   "rm <name> <id>",
+	Short: "Remove a photo from a species",
+	Long: `Remove a photo from a species. An uploaded file is deleted from the server
+once no other species uses it.
+
+Examples:
+  oak species media rm alba 12 --remote`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		name := names.NormalizeHybridName(args[0])
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return usageErrorf("invalid media ID: %s", args[1])
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if isActualRemote() && !confirmRemoteOperation("Remove media from", fmt.Sprintf("%s (%d)", name, id)) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		if err := apiClient.DeleteSpeciesMedia(ctx, name, id); err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("media %d of species '%s' not found", id, name)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Removed media %d from %s\n", id, name)
+		return nil
+	},
+}
+
+// optionalFlag returns nil for a string flag left empty
+func optionalFlag(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// optionalID returns nil for an ID flag left 0
+func optionalID(id int64) *int64 {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+// orDash returns "-" for a missing value, as tables show it
+func orDash(value *string) string {
+	if value == nil || *value == "" {
+		return "-"
+	}
+	return *value
+}
+
+func init() {
+	speciesMediaAddCmd.Flags().StringVar(&mediaKind, "kind", "", "What the photo shows: "+strings.Join(oakclient.MediaKinds, ", "))
+	speciesMediaAddCmd.Flags().Int64Var(&mediaSourceID, "source-id", 0, "ID of the source the photo comes from")
+	speciesMediaAddCmd.Flags().StringVar(&mediaCaption, "caption", "", "Caption of the photo")
+	speciesMediaAddCmd.Flags().StringVar(&mediaLicense, "license", "", "License of the photo, such as \"CC BY 4.0\"")
+	speciesMediaAddCmd.Flags().StringVar(&mediaAttribution, "attribution", "", "Attribution of the photo")
+	speciesMediaAddCmd.Flags().StringVar(&mediaThumbnailURL, "thumbnail-url", "", "URL of a smaller version, for linked photos")
+	speciesMediaListCmd.Flags().StringVar(&mediaKind, "kind", "", "Only list photos of this kind")
+
+	speciesMediaCmd.AddCommand(speciesMediaAddCmd)
+	speciesMediaCmd.AddCommand(speciesMediaListCmd)
+	speciesMediaCmd.AddCommand(speciesMediaRmCmd)
+	speciesCmd.AddCommand(speciesMediaCmd)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var (
+	sqlLimit   int
+	sqlTimeout time.Duration
+	sqlFormat  string
+)
+
+var sqlCmd = &cobra.Command{
+	Use:   "sql <query>",
+	Short: "Run a read-only SQL query against the database",
+	Long: `Run SQL against the current profile's database and print the rows, for
+reports no other command gives. Pass - to read the query from stdin.
+
+The server only lets the query read: INSERT, UPDATE, DELETE, schema
+changes, ATTACH, and PRAGMA are refused before anything runs, as are
+queries past --timeout. At most --limit rows come back; a note on stderr
+says when more matched. Requires an API key.
+
+Examples:
+  oak sql "SELECT subsection, count(*) FROM oak_entries GROUP BY 1 ORDER BY 2 DESC"
+  oak sql "SELECT scientific_name FROM oak_entries WHERE author IS NULL" --format csv
+  oak sql - --profile prod < report.sql`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSQL,
+}
+
+func init() {
+	sqlCmd.Flags().IntVar(&sqlLimit, "limit", 1000, "Most rows to return (up to 10000)")
+	sqlCmd.Flags().DurationVar(&sqlTimeout, "timeout", 5*time.Second, "Give up after this long (up to 30s)")
+	sqlCmd.Flags().StringVar(&sqlFormat, "format", "table", "Output format: table, csv, or json")
+	rootCmd.AddCommand(sqlCmd)
+}
+
+func runSQL(cmd *cobra.Command, args []string) error {
+	switch sqlFormat {
+	case "table", "csv", "json":
+	default:
+		return usageErrorf("invalid --format %q (must be table, csv, or json)", sqlFormat)
+	}
+
+	query := args[0]
+	if query == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+		query = string(data)
+	}
+	if strings.TrimSpace(query) == "" {
+		return usageErrorf("query is empty")
+	}
+	cmd.SilenceUsage = true // Failures from here on are about the query, not the arguments
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	result, err := apiClient.Query(commandContext(), &oakclient.QueryRequest{
+		SQL:       query,
+		Limit:     sqlLimit,
+		TimeoutMs: int(sqlTimeout.Milliseconds()),
+	})
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	switch sqlFormat {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		_ = w.Write(result.Columns)
+		for _, row := range result.Rows {
+			_ = w.Write(sqlRowStrings(row))
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return err
+		}
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(result.Columns, "\t"))
+		for _, row := range result.Rows {
+			fmt.Fprintln(w, strings.ReplaceAll(strings.Join(sqlRowStrings(row), "\t"), "\n", " "))
+		}
+		w.Flush()
+		fmt.Fprintf(os.Stderr, "(%d rows, %.1fms)\n", len(result.Rows), result.DurationMs)
+	}
+	if result.Truncated {
+		fmt.Fprintf(os.Stderr, "More rows matched; only the first %d are shown (raise --limit)\n", len(result.Rows))
+	}
+	return nil
+}
+
+// sqlRowStrings formats a result row for table and CSV output: NULL is
+// empty and whole numbers print without a decimal point.
+func sqlRowStrings(row []interface{}) []string {
+	out := make([]string, len(row))
+	for i, v := range row {
+		switch v := v.(type) {
+		case nil:
+			out[i] = ""
+		case float64:
+			if v == float64(int64(v)) {
+				out[i] = fmt.Sprintf("%d", int64(v))
+			} else {
+				out[i] = fmt.Sprintf("%g", v)
+			}
+		default:
+			out[i] = fmt.Sprint(v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/replica"
+)
+
+var (
+	subsetOutput string
+	subsetFilter replica.Filter
+)
+
+var subsetCmd = &cobra.Command{
+	Use:   "subset",
+	Short: "Write a smaller database holding one taxonomic group",
+	Long: `Write a new SQLite database holding only the published species in a
+taxonomic group, with the taxa above them, the sources they cite (and any
+sources replacing those), and their genera. The result is a complete oak
+database: open it with --database to browse, edit, or export it.
+
+Give at least one of --genus, --subgenus, --section, --subsection, or
+--complex; species must match all given. Hybrid lists only name species
+in the subset, while a hybrid's parents are kept by name even if left out.
+Drafts are not included. The output file must not exist.
+
+Examples:
+  oak subset --section Lobatae -o lobatae.db
+  oak subset --subsection Phellos -o phellos.db --profile prod
+  oak find phellos --database phellos.db`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if subsetOutput == "" {
+			return usageErrorf("give the database to write with -o")
+		}
+		if subsetFilter.IsEmpty() {
+			return usageErrorf("give at least one of --genus, --subgenus, --section, --subsection, or --complex")
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		stats, err := replica.Subset(commandContext(), apiClient, subsetOutput, subsetFilter)
+		if err != nil {
+			return fmt.Errorf("subset failed: %w", err)
+		}
+
+		fmt.Printf("Wrote %s (%s)\n", subsetOutput, subsetFilter.String())
+		fmt.Printf("  %d species (%d source records), %d sources, %d taxa, %d genera\n",
+			stats.Species, stats.SpeciesSources, stats.Sources, stats.Taxa, stats.Genera)
+		return nil
+	},
+}
+
+func init() {
+	subsetCmd.Flags().StringVarP(&subsetOutput, "output", "o", "", "Database file to write (required)")
+	subsetCmd.Flags().StringVar(&subsetFilter.Genus, "genus", "", "Only species in this genus")
+	subsetCmd.Flags().StringVar(&subsetFilter.Subgenus, "subgenus", "", "Only species in this subgenus")
+	subsetCmd.Flags().StringVar(&subsetFilter.Section, "section", "", "Only species in this section")
+	subsetCmd.Flags().StringVar(&subsetFilter.Subsection, "subsection", "", "Only species in this subsection")
+	subsetCmd.Flags().StringVar(&subsetFilter.Complex, "complex", "", "Only species in this complex")
+	rootCmd.AddCommand(subsetCmd)
+}
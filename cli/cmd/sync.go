@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/config"
+	"github.com/jeff/oaks/cli/internal/replica"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Copy a remote profile's data for offline reads",
+	Long: `Copy the published data of the active remote profile into a local
+database at ~/.oak/replicas/<profile>.db, replacing the previous copy.
+
+With --offline-ok, read commands such as find, export, and source list use
+this copy when the remote cannot be reached, and say how old it is. Drafts
+are not copied.
+
+'oak sync gbif' fills species data in from GBIF instead; see its help.
+
+Examples:
+  oak sync --profile prod
+  oak find alba --profile prod --offline-ok`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		if !isActualRemote() {
+			return usageErrorf("oak sync copies a remote profile; select one with --profile")
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		path := config.DefaultReplicaPath(resolvedProfile.Name)
+		stats, err := replica.Sync(ctx, apiClient, path, resolvedProfile.Name, resolvedProfile.URL)
+		if err != nil {
+			return fmt.Errorf("sync failed: %w", err)
+		}
+
+		fmt.Printf("Synced [%s] to %s\n", resolvedProfile.Name, path)
+		fmt.Printf("  %d species (%d source records), %d sources, %d taxa, %d genera\n",
+			stats.Species, stats.SpeciesSources, stats.Sources, stats.Taxa, stats.Genera)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/gbif"
+	"github.com/jeff/oaks/cli/internal/models"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var (
+	syncGBIFAll     bool
+	syncGBIFConfirm bool
+	syncGBIFDryRun  bool
+)
+
+var syncGBIFCmd = &cobra.Command{
+	Use:   "gbif [species...]",
+	Short: "Fill in species data from GBIF name matching",
+	Long: `Match species against the GBIF backbone taxonomy and fill in what GBIF
+knows:
+
+  conservation_status  the IUCN Red List category GBIF reports
+  synonyms             GBIF's synonyms of the name, added to the entry's
+  external_links       a GBIF link to the matched taxon key
+
+Names are matched strictly, with their genus ("Quercus alba"). A name GBIF
+treats as a synonym of another, or does not know, is reported and left
+alone. Use --all to run over every species, --confirm to approve each
+species' changes, and --dry-run to only list them. Set OAK_GBIF_URL to use
+another GBIF API endpoint.
+
+Examples:
+  oak sync gbif alba
+  oak sync gbif --all --confirm
+  oak sync gbif --all --dry-run --profile prod`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		if syncGBIFAll == (len(args) > 0) {
+			return usageErrorf("name species to sync, or use --all")
+		}
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+		if syncGBIFAll && !syncGBIFDryRun && !syncGBIFConfirm && !confirmRemoteOperation("Update", "all species from GBIF") {
+			return nil
+		}
+
+		baseURL := os.Getenv("OAK_GBIF_URL")
+		if baseURL == "" {
+			baseURL = gbif.DefaultURL
+		}
+		s := &gbifSync{
+			api:     apiClient,
+			gbif:    gbif.New(baseURL, "oak-cli/"+oakclient.Version),
+			confirm: syncGBIFConfirm,
+			dryRun:  syncGBIFDryRun,
+			input:   bufio.NewReader(os.Stdin),
+		}
+
+		if syncGBIFAll {
+			for entry, err := range apiClient.AllSpecies(ctx, nil) {
+				if err != nil {
+					return err
+				}
+				if stop, err := s.sync(ctx, entry); err != nil || stop {
+					return err
+				}
+			}
+		} else {
+			for _, name := range args {
+				entry, err := apiClient.GetSpecies(ctx, name)
+				if err != nil {
+					return err
+				}
+				if stop, err := s.sync(ctx, entry); err != nil || stop {
+					return err
+				}
+			}
+		}
+
+		verb := "Updated"
+		if syncGBIFDryRun {
+			verb = "Would update"
+		}
+		fmt.Printf("\n%s %d species; %d unchanged, %d skipped, %d not matched\n", verb, s.updated, s.unchanged, s.skipped, s.unmatched)
+		return nil
+	},
+}
+
+// gbifSync fills species in from GBIF, counting the outcomes
+type gbifSync struct {
+	api     *oakclient.Client
+	gbif    *gbif.Client
+	confirm bool
+	dryRun  bool
+	input   *bufio.Reader
+
+	updated, unchanged, skipped, unmatched int
+}
+
+// gbifData is what GBIF knows of a species
+type gbifData struct {
+	Key      int64
+	Name     string // The matched name with authorship
+	Status   string // IUCN Red List code, or ""
+	Synonyms []string
+}
+
+// sync matches one species and applies what GBIF adds. Reports true if the
+// user chose to stop.
+func (s *gbifSync) sync(ctx context.Context, entry *oakclient.OakEntry) (bool, error) {
+	genus := entry.Genus
+	if genus == "" {
+		genus = models.DefaultGenus
+	}
+	match, err := s.gbif.Match(ctx, genus+" "+entry.ScientificName)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", entry.ScientificName, err)
+	}
+	if match == nil {
+		fmt.Printf("%s: no GBIF match\n", entry.ScientificName)
+		s.unmatched++
+		return false, nil
+	}
+	if match.Status != gbif.StatusAccepted {
+		fmt.Printf("%s: GBIF has %s as %s (key %d); skipped\n", entry.ScientificName, match.ScientificName, strings.ToLower(match.Status), match.UsageKey)
+		s.skipped++
+		return false, nil
+	}
+
+	data := &gbifData{Key: match.UsageKey, Name: match.ScientificName}
+	if data.Status, err = s.gbif.RedListCategory(ctx, match.UsageKey); err != nil {
+		return false, fmt.Errorf("%s: %w", entry.ScientificName, err)
+	}
+	if data.Synonyms, err = s.gbif.Synonyms(ctx, match.UsageKey); err != nil {
+		return false, fmt.Errorf("%s: %w", entry.ScientificName, err)
+	}
+
+	updated := *entry
+	changes := applyGBIF(&updated, data)
+	fmt.Printf("%s: GBIF %d (%s)\n", entry.ScientificName, data.Key, data.Name)
+	if len(changes) == 0 {
+		fmt.Println("  up to date")
+		s.unchanged++
+		return false, nil
+	}
+	for _, c := range changes {
+		fmt.Printf("  %s\n", c)
+	}
+	if s.dryRun {
+		s.updated++
+		return false, nil
+	}
+
+	if s.confirm {
+		fmt.Print("Apply? (y/N/q): ")
+		response, _ := s.input.ReadString('\n')
+		switch strings.TrimSpace(strings.ToLower(response)) {
+		case "y", "yes":
+		case "q":
+			return true, nil
+		default:
+			s.skipped++
+			return false, nil
+		}
+	}
+
+	if _, err := s.api.UpdateSpecies(ctx, entry.ScientificName, oakclient.EntryToRequest(&updated)); err != nil {
+		return false, fmt.Errorf("failed to update %s: %w", entry.ScientificName, err)
+	}
+	s.updated++
+	return false, nil
+}
+
+// applyGBIF sets entry's conservation status and GBIF link from data and
+// adds the synonyms it lacks, describing each change
+func applyGBIF(entry *oakclient.OakEntry, data *gbifData) []string {
+	var changes []string
+
+	if data.Status != "" && slices.Contains(models.ConservationStatuses, data.Status) {
+		current := ""
+		if entry.ConservationStatus != nil {
+			current = *entry.ConservationStatus
+		}
+		if current != data.Status {
+			status := data.Status
+			entry.ConservationStatus = &status
+			changes = append(changes, fmt.Sprintf("conservation_status: %s → %s", valueOrDash(current), status))
+		}
+	}
+
+	have := make(map[string]bool, len(entry.Synonyms))
+	for _, s := range entry.Synonyms {
+		have[strings.ToLower(s)] = true
+	}
+	var added []string
+	for _, s := range data.Synonyms {
+		if !have[strings.ToLower(s)] {
+			have[strings.ToLower(s)] = true
+			added = append(added, s)
+		}
+	}
+	if len(added) > 0 {
+		entry.Synonyms = append(slices.Clone(entry.Synonyms), added...)
+		changes = append(changes, "synonyms: + "+strings.Join(added, ", + "))
+	}
+
+	link := oakclient.ExternalLink{Name: "GBIF", URL: gbif.SpeciesURL(data.Key), Logo: "gbif"}
+	i := slices.IndexFunc(entry.ExternalLinks, func(l oakclient.ExternalLink) bool {
+		return l.Logo == "gbif" || strings.EqualFold(l.Name, "GBIF")
+	})
+	switch {
+	case i < 0:
+		entry.ExternalLinks = append(slices.Clone(entry.ExternalLinks), link)
+		changes = append(changes, "external_links: + "+link.URL)
+	case entry.ExternalLinks[i].URL != link.URL:
+		changes = append(changes, fmt.Sprintf("external_links: %s → %s", entry.ExternalLinks[i].URL, link.URL))
+		entry.ExternalLinks = slices.Clone(entry.ExternalLinks)
+		entry.ExternalLinks[i] = link
+	}
+
+	return changes
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	syncGBIFCmd.Flags().BoolVar(&syncGBIFAll, "all", false, "Sync every species")
+	syncGBIFCmd.Flags().BoolVar(&syncGBIFConfirm, "confirm", false, "Show each species' changes and ask before applying them")
+	syncGBIFCmd.Flags().BoolVar(&syncGBIFDryRun, "dry-run", false, "List the changes without applying them")
+	syncCmd.AddCommand(syncGBIFCmd)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+func TestApplyGBIF(t *testing.T) {
+	status := "NT"
+	entry := &oakclient.OakEntry{
+		ScientificName:     "alba",
+		ConservationStatus: &status,
+		Synonyms:           []string{"Quercus alba var. repanda"},
+		ExternalLinks:      []oakclient.ExternalLink{{Name: "Wikipedia", URL: "https://en.wikipedia.org/wiki/Quercus_alba", Logo: "wikipedia"}},
+	}
+	data := &gbifData{Key: 2880539, Status: "LC", Synonyms: []string{"Quercus alba var. Repanda", "Quercus ramosa"}}
+
+	changes := applyGBIF(entry, data)
+	if len(changes) != 3 {
+		t.Errorf("changes = %q, want status, synonyms, and link", changes)
+	}
+	if *entry.ConservationStatus != "LC" {
+		t.Errorf("conservation_status = %s, want LC", *entry.ConservationStatus)
+	}
+	if len(entry.Synonyms) != 2 || entry.Synonyms[1] != "Quercus ramosa" {
+		t.Errorf("synonyms = %v, want Quercus ramosa added once", entry.Synonyms)
+	}
+	if len(entry.ExternalLinks) != 2 || entry.ExternalLinks[1].URL != "https://www.gbif.org/species/2880539" {
+		t.Errorf("external_links = %+v, want a GBIF link added", entry.ExternalLinks)
+	}
+
+	// Running it again changes nothing
+	if changes := applyGBIF(entry, data); len(changes) != 0 {
+		t.Errorf("second run changes = %q, want none", changes)
+	}
+}
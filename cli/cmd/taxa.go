@@ -3,26 +3,26 @@ package cmd
 import (
 	"bufio"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"sort"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
-	"github.com/jeff/oaks/cli/internal/client"
 	"github.com/jeff/oaks/cli/internal/db"
 	"github.com/jeff/oaks/cli/internal/editor"
 	"github.com/jeff/oaks/cli/internal/models"
+	"github.com/jeff/oaks/pkg/oakclient"
 )
 
-// TaxaFile represents the structure of the taxa YAML file
-type TaxaFile struct {
-	Subgenera   []TaxonEntry `yaml:"subgenera"`
-	Sections    []TaxonEntry `yaml:"sections"`
-	Subsections []TaxonEntry `yaml:"subsections"`
-	Complexes   []TaxonEntry `yaml:"complexes"`
-}
+// TaxaFile represents the structure of the taxa YAML file: one list of
+// entries per configured taxon level, keyed by the level's plural (e.g.
+// "subgenera") or its name.
+type TaxaFile map[string][]TaxonEntry
 
 // TaxonLink represents an external link in the YAML file
 type TaxonLinkEntry struct {
@@ -42,7 +42,10 @@ type TaxonEntry struct {
 var taxaCmd = &cobra.Command{
 	Use:   "taxa",
 	Short: "Manage taxonomy reference data",
-	Long:  `Commands for managing the taxonomy reference table (subgenera, sections, subsections, complexes).`,
+	Long: `Commands for managing the taxonomy reference table.
+
+The levels below genus are configurable (see 'oak taxa levels'); by default
+they are subgenus, section, subsection, and complex.`,
 }
 
 var taxaImportCmd = &cobra.Command{
@@ -50,8 +53,9 @@ var taxaImportCmd = &cobra.Command{
 	Short: "Import taxa from YAML file",
 	Long: `Import taxonomy reference data from a YAML file.
 
-The file should have sections for subgenera, sections, subsections, and complexes.
-Each entry can have: name, parent, author, notes.
+The file should have one section per configured level, keyed by the level's
+plural (subgenera, sections, subsections, complexes by default) or its name.
+Each entry can have: name, parent, author, notes, links.
 
 Example:
   oak taxa import data/taxa.yaml`,
@@ -62,14 +66,13 @@ Example:
 var taxaListCmd = &cobra.Command{
 	Use:   "list [level]",
 	Short: "List taxa",
-	Long: `List all taxa, optionally filtered by level.
-
-Levels: subgenus, section, subsection, complex
+	Long: `List taxa as a tree ordered by the configured taxon levels.
 
 Examples:
   oak taxa list
   oak taxa list subgenus
-  oak taxa list section`,
+  oak taxa list section --parent Quercus
+  oak taxa list section --limit 20 --offset 20`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runTaxaList,
 }
@@ -79,11 +82,12 @@ var taxaNewCmd = &cobra.Command{
 	Short: "Create a new taxon",
 	Long: `Create a new taxon entry by opening it in your $EDITOR.
 
-Levels: subgenus, section, subsection, complex
+The level must be one of the configured taxon levels (see 'oak taxa levels').
 
 Examples:
   oak taxa new Lobatae --level section
-  oak taxa new Albae --level subsection`,
+  oak taxa new Albae --level subsection
+  oak taxa new Lobatae --level section --file lobatae.yaml  # Send a YAML file as-is`,
 	Args: cobra.ExactArgs(1),
 	RunE: runTaxaNew,
 }
@@ -93,9 +97,14 @@ var taxaEditCmd = &cobra.Command{
 	Short: "Edit an existing taxon",
 	Long: `Edit an existing taxon by opening it in your $EDITOR.
 
+If someone else saves the taxon while you are editing it, nothing is
+overwritten: your edits are written to a file and the command exits with
+code 5.
+
 Examples:
   oak taxa edit Lobatae --level section
-  oak taxa edit Quercus --level subgenus`,
+  oak taxa edit Quercus --level subgenus
+  oak taxa edit Lobatae --level section --file lobatae.yaml`,
 	Args: cobra.ExactArgs(1),
 	RunE: runTaxaEdit,
 }
@@ -139,6 +148,9 @@ var (
 	taxaImportClear bool
 	taxaLevel       string
 	taxaDeleteForce bool
+	taxaListParent  string
+	taxaListLimit   int
+	taxaListOffset  int
 )
 
 func init() {
@@ -151,20 +163,26 @@ func init() {
 	taxaCmd.AddCommand(taxaShowCmd)
 	taxaCmd.AddCommand(taxaFindCmd)
 
+	taxaListCmd.Flags().StringVar(&taxaListParent, "parent", "", "Only list taxa under this parent")
+	taxaListCmd.Flags().IntVar(&taxaListLimit, "limit", 0, "Maximum number of taxa to list (default all)")
+	taxaListCmd.Flags().IntVar(&taxaListOffset, "offset", 0, "Number of taxa to skip")
+
 	taxaImportCmd.Flags().BoolVar(&taxaImportClear, "clear", false, "Clear existing taxa before import")
 
 	// Level flag for new, edit, delete, show
-	taxaNewCmd.Flags().StringVar(&taxaLevel, "level", "", "Taxon level (subgenus, section, subsection, complex)")
+	taxaNewCmd.Flags().StringVar(&taxaLevel, "level", "", "Taxon level (see 'oak taxa levels')")
 	_ = taxaNewCmd.MarkFlagRequired("level")
+	addInputFlags(taxaNewCmd)
 
-	taxaEditCmd.Flags().StringVar(&taxaLevel, "level", "", "Taxon level (subgenus, section, subsection, complex)")
+	taxaEditCmd.Flags().StringVar(&taxaLevel, "level", "", "Taxon level (see 'oak taxa levels')")
 	_ = taxaEditCmd.MarkFlagRequired("level")
+	addInputFlags(taxaEditCmd)
 
-	taxaDeleteCmd.Flags().StringVar(&taxaLevel, "level", "", "Taxon level (subgenus, section, subsection, complex)")
+	taxaDeleteCmd.Flags().StringVar(&taxaLevel, "level", "", "Taxon level (see 'oak taxa levels')")
 	_ = taxaDeleteCmd.MarkFlagRequired("level")
 	taxaDeleteCmd.Flags().BoolVar(&taxaDeleteForce, "force", false, "Skip confirmation prompt")
 
-	taxaShowCmd.Flags().StringVar(&taxaLevel, "level", "", "Taxon level (subgenus, section, subsection, complex)")
+	taxaShowCmd.Flags().StringVar(&taxaLevel, "level", "", "Taxon level (see 'oak taxa levels')")
 	_ = taxaShowCmd.MarkFlagRequired("level")
 }
 
@@ -212,6 +230,11 @@ func runTaxaImport(cmd *cobra.Command, args []string) error {
 		return links
 	}
 
+	levels, err := database.ListTaxonLevels()
+	if err != nil {
+		return err
+	}
+
 	// Helper to import a list of taxa at a given level
 	importLevel := func(entries []TaxonEntry, level models.TaxonLevel) {
 		for _, entry := range entries {
@@ -246,17 +269,29 @@ func runTaxaImport(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Fprintln(cmd.ErrOrStderr(), "Importing subgenera...")
-	importLevel(taxaFile.Subgenera, models.TaxonLevelSubgenus)
-
-	fmt.Fprintln(cmd.ErrOrStderr(), "Importing sections...")
-	importLevel(taxaFile.Sections, models.TaxonLevelSection)
-
-	fmt.Fprintln(cmd.ErrOrStderr(), "Importing subsections...")
-	importLevel(taxaFile.Subsections, models.TaxonLevelSubsection)
+	// Import in rank order so parents precede their children
+	known := make(map[string]bool)
+	for _, l := range levels {
+		key := l.Name
+		if l.Plural != nil && *l.Plural != "" {
+			key = *l.Plural
+		}
+		known[key] = true
+		known[l.Name] = true
 
-	fmt.Fprintln(cmd.ErrOrStderr(), "Importing complexes...")
-	importLevel(taxaFile.Complexes, models.TaxonLevelComplex)
+		entries := taxaFile[key]
+		if key != l.Name {
+			entries = append(entries, taxaFile[l.Name]...)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Importing %s...\n", key)
+		importLevel(entries, models.TaxonLevel(l.Name))
+	}
+	for key, entries := range taxaFile {
+		if !known[key] {
+			errors += len(entries)
+			fmt.Fprintf(cmd.ErrOrStderr(), "  Error: unknown taxon level %q (%d entries not imported)\n", key, len(entries))
+		}
+	}
 
 	fmt.Fprintf(cmd.ErrOrStderr(), "\nDone: %d imported, %d skipped, %d errors\n", imported, skipped, errors)
 
@@ -264,12 +299,26 @@ func runTaxaImport(cmd *cobra.Command, args []string) error {
 }
 
 func runTaxaList(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
 	apiClient, err := getAPIClient()
 	if err != nil {
 		return err
 	}
 
-	resp, err := apiClient.ListTaxa(nil)
+	params := &oakclient.TaxaListParams{Limit: taxaListLimit, Offset: taxaListOffset}
+	if len(args) == 1 {
+		level := oakclient.TaxonLevel(args[0])
+		params.Level = &level
+	}
+	if taxaListParent != "" {
+		params.Parent = &taxaListParent
+	}
+	resp, err := apiClient.ListTaxa(ctx, params)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	levels, err := apiClient.ListTaxonLevels(ctx)
 	if err != nil {
 		return fmt.Errorf("API error: %w", err)
 	}
@@ -280,46 +329,65 @@ func runTaxaList(cmd *cobra.Command, args []string) error {
 		taxa[i] = clientTaxonToModel(t)
 	}
 
-	printTaxaTree(cmd, taxa)
+	printTaxaTree(cmd, taxa, clientTaxonLevelsToModel(levels))
+	printMorePages(resp.Pagination, len(resp.Data))
 	return nil
 }
 
-func printTaxaTree(cmd *cobra.Command, taxa []*models.Taxon) {
+// printTaxaTree prints taxa as a tree per genus. A taxon's parent is the taxon
+// with the parent name at the nearest higher-ranked level, so names shared
+// across levels (e.g. subgenus and section Quercus) nest correctly.
+func printTaxaTree(cmd *cobra.Command, taxa []*models.Taxon, levels []*models.TaxonLevelDef) {
 	if len(taxa) == 0 {
 		fmt.Fprintln(cmd.ErrOrStderr(), "No taxa found")
 		return
 	}
 
-	// Organize by level
-	subgenera := []*models.Taxon{}
-	sectionsByParent := make(map[string][]*models.Taxon)
-	subsectionsByParent := make(map[string][]*models.Taxon)
-	complexesByParent := make(map[string][]*models.Taxon)
+	rank := make(map[models.TaxonLevel]int, len(levels))
+	for _, l := range levels {
+		rank[models.TaxonLevel(l.Name)] = l.Rank
+	}
 
-	for _, t := range taxa {
-		switch t.Level {
-		case models.TaxonLevelSubgenus:
-			subgenera = append(subgenera, t)
-		case models.TaxonLevelSection:
-			parent := ""
-			if t.Parent != nil {
-				parent = *t.Parent
-			}
-			sectionsByParent[parent] = append(sectionsByParent[parent], t)
-		case models.TaxonLevelSubsection:
-			parent := ""
-			if t.Parent != nil {
-				parent = *t.Parent
-			}
-			subsectionsByParent[parent] = append(subsectionsByParent[parent], t)
-		case models.TaxonLevelComplex:
-			parent := ""
-			if t.Parent != nil {
-				parent = *t.Parent
+	// Sort by rank, keeping the incoming (name) order within a level
+	sorted := make([]*models.Taxon, len(taxa))
+	copy(sorted, taxa)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return rank[sorted[i].Level] < rank[sorted[j].Level]
+	})
+
+	genusOf := func(t *models.Taxon) string {
+		if t.Genus == "" {
+			return models.DefaultGenus
+		}
+		return t.Genus
+	}
+
+	var genera []string
+	roots := make(map[string][]*models.Taxon)
+	children := make(map[*models.Taxon][]*models.Taxon)
+	for _, t := range sorted {
+		genus := genusOf(t)
+		if _, ok := roots[genus]; !ok {
+			genera = append(genera, genus)
+			roots[genus] = nil
+		}
+
+		var parent *models.Taxon
+		if t.Parent != nil {
+			for _, p := range sorted {
+				if p.Name == *t.Parent && genusOf(p) == genus && rank[p.Level] < rank[t.Level] &&
+					(parent == nil || rank[p.Level] > rank[parent.Level]) {
+					parent = p
+				}
 			}
-			complexesByParent[parent] = append(complexesByParent[parent], t)
+		}
+		if parent == nil {
+			roots[genus] = append(roots[genus], t)
+		} else {
+			children[parent] = append(children[parent], t)
 		}
 	}
+	sort.Strings(genera)
 
 	// Helper to format author
 	fmtAuthor := func(t *models.Taxon) string {
@@ -329,97 +397,83 @@ func printTaxaTree(cmd *cobra.Command, taxa []*models.Taxon) {
 		return ""
 	}
 
-	// Print hierarchical tree
-	fmt.Println("Quercus (genus)")
-	for _, sg := range subgenera {
-		fmt.Printf("├── %s (subgenus)%s\n", sg.Name, fmtAuthor(sg))
-
-		sections := sectionsByParent[sg.Name]
-		for i, sec := range sections {
-			secPrefix := "│   ├── "
-			secChildPrefix := "│   │   "
-			if i == len(sections)-1 {
-				secPrefix = "│   └── "
-				secChildPrefix = "│       "
-			}
-			fmt.Printf("%s%s (section)%s\n", secPrefix, sec.Name, fmtAuthor(sec))
-
-			subsections := subsectionsByParent[sec.Name]
-			sectionComplexes := complexesByParent[sec.Name]
-			totalChildren := len(subsections) + len(sectionComplexes)
-			childIdx := 0
-
-			for _, subsec := range subsections {
-				subsecPrefix := secChildPrefix + "├── "
-				subsecChildPrefix := secChildPrefix + "│   "
-				childIdx++
-				if childIdx == totalChildren {
-					subsecPrefix = secChildPrefix + "└── "
-					subsecChildPrefix = secChildPrefix + "    "
-				}
-				fmt.Printf("%s%s (subsection)%s\n", subsecPrefix, subsec.Name, fmtAuthor(subsec))
-
-				complexes := complexesByParent[subsec.Name]
-				for k, cpx := range complexes {
-					cpxPrefix := subsecChildPrefix + "├── "
-					if k == len(complexes)-1 {
-						cpxPrefix = subsecChildPrefix + "└── "
-					}
-					fmt.Printf("%s%s (complex)%s\n", cpxPrefix, cpx.Name, fmtAuthor(cpx))
-				}
-			}
+	var printNode func(t *models.Taxon, prefix string, last bool)
+	printNode = func(t *models.Taxon, prefix string, last bool) {
+		branch, childPrefix := "├── ", prefix+"│   "
+		if last {
+			branch, childPrefix = "└── ", prefix+"    "
+		}
+		fmt.Printf("%s%s%s (%s)%s\n", prefix, branch, t.Name, t.Level, fmtAuthor(t))
+		for i, c := range children[t] {
+			printNode(c, childPrefix, i == len(children[t])-1)
+		}
+	}
 
-			// Show complexes directly under section (no subsection)
-			for _, cpx := range sectionComplexes {
-				cpxPrefix := secChildPrefix + "├── "
-				childIdx++
-				if childIdx == totalChildren {
-					cpxPrefix = secChildPrefix + "└── "
-				}
-				fmt.Printf("%s%s (complex)%s\n", cpxPrefix, cpx.Name, fmtAuthor(cpx))
-			}
+	// Print hierarchical tree
+	for _, genus := range genera {
+		fmt.Printf("%s (genus)\n", genus)
+		for i, t := range roots[genus] {
+			printNode(t, "", i == len(roots[genus])-1)
 		}
 	}
 	fmt.Println()
 }
 
-// parseTaxonLevel converts a string to a TaxonLevel
-func parseTaxonLevel(s string) (models.TaxonLevel, error) {
-	switch strings.ToLower(s) {
-	case "subgenus":
-		return models.TaxonLevelSubgenus, nil
-	case "section":
-		return models.TaxonLevelSection, nil
-	case "subsection":
-		return models.TaxonLevelSubsection, nil
-	case "complex":
-		return models.TaxonLevelComplex, nil
-	default:
-		return "", fmt.Errorf("invalid level: %s (must be subgenus, section, subsection, or complex)", s)
+// parseTaxonLevel converts a string to a TaxonLevel, checking it against the
+// configured taxon levels
+func parseTaxonLevel(s string, levels []*models.TaxonLevelDef) (models.TaxonLevel, error) {
+	level := strings.ToLower(s)
+	names := make([]string, 0, len(levels))
+	for _, l := range levels {
+		if l.Name == level {
+			return models.TaxonLevel(level), nil
+		}
+		names = append(names, l.Name)
 	}
+	return "", usageErrorf("invalid level: %s (must be one of: %s)", s, strings.Join(names, ", "))
 }
 
 func runTaxaNew(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
 	name := args[0]
-
-	level, err := parseTaxonLevel(taxaLevel)
+	body, err := readInputBody()
 	if err != nil {
 		return err
 	}
 
-	database, err := db.New(dbPath)
+	apiClient, err := getAPIClient()
 	if err != nil {
-		return fmt.Errorf("failed to open database: %w", err)
+		return err
 	}
-	defer database.Close()
 
-	// Check if already exists
-	existing, err := database.GetTaxon(name, level)
+	levels, err := apiClient.ListTaxonLevels(ctx)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+	level, err := parseTaxonLevel(taxaLevel, clientTaxonLevelsToModel(levels))
 	if err != nil {
 		return err
 	}
-	if existing != nil {
-		return fmt.Errorf("taxon already exists: %s [%s]", name, level)
+	cmd.SilenceUsage = true // Failures from here on are about the records, not the arguments
+
+	// Check if already exists
+	if _, err := apiClient.GetTaxon(ctx, oakclient.TaxonLevel(level), name); err == nil {
+		return &exitError{code: ExitConflict, err: fmt.Errorf("taxon already exists: %s [%s]", name, level)}
+	} else if !oakclient.IsNotFoundError(err) {
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	if body != nil {
+		if isActualRemote() && !confirmRemoteOperation("Create", fmt.Sprintf("taxon %s [%s]", name, level)) {
+			fmt.Println("Canceled")
+			return nil
+		}
+		var created oakclient.Taxon
+		if err := sendInputBody(ctx, apiClient, http.MethodPost, "/api/v1/taxa", body, &created); err != nil {
+			return fmt.Errorf("failed to create taxon: %w", err)
+		}
+		fmt.Printf("Created taxon: %s [%s]\n", created.Name, created.Level)
+		return nil
 	}
 
 	taxon, err := editor.NewTaxon(name, level)
@@ -427,8 +481,13 @@ func runTaxaNew(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if err := database.InsertTaxon(taxon); err != nil {
-		return err
+	if isActualRemote() && !confirmRemoteOperation("Create", fmt.Sprintf("taxon %s [%s]", taxon.Name, taxon.Level)) {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	if _, err := apiClient.CreateTaxon(ctx, modelTaxonToRequest(taxon)); err != nil {
+		return fmt.Errorf("failed to create taxon: %w", err)
 	}
 
 	fmt.Printf("Created taxon: %s [%s]\n", taxon.Name, taxon.Level)
@@ -436,34 +495,66 @@ func runTaxaNew(cmd *cobra.Command, args []string) error {
 }
 
 func runTaxaEdit(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
 	name := args[0]
-
-	level, err := parseTaxonLevel(taxaLevel)
+	body, err := readInputBody()
 	if err != nil {
 		return err
 	}
 
-	database, err := db.New(dbPath)
+	apiClient, err := getAPIClient()
 	if err != nil {
-		return fmt.Errorf("failed to open database: %w", err)
+		return err
 	}
-	defer database.Close()
 
-	existing, err := database.GetTaxon(name, level)
+	levels, err := apiClient.ListTaxonLevels(ctx)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+	level, err := parseTaxonLevel(taxaLevel, clientTaxonLevelsToModel(levels))
 	if err != nil {
 		return err
 	}
-	if existing == nil {
-		return fmt.Errorf("taxon not found: %s [%s]", name, level)
+	cmd.SilenceUsage = true // Failures from here on are about the records, not the arguments
+
+	// Fetch the taxon, with the version the save must still match
+	existing, etag, err := apiClient.GetTaxonWithETag(ctx, oakclient.TaxonLevel(level), name)
+	if err != nil {
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("taxon not found: %s [%s]", name, level)
+		}
+		return fmt.Errorf("API error: %w", err)
+	}
+
+	if body != nil {
+		if isActualRemote() && !confirmRemoteOperation("Update", fmt.Sprintf("taxon %s [%s]", name, level)) {
+			fmt.Println("Canceled")
+			return nil
+		}
+		var updated oakclient.Taxon
+		path := "/api/v1/taxa/" + url.PathEscape(string(level)) + "/" + url.PathEscape(name)
+		if err := sendInputBody(ctx, apiClient, http.MethodPut, path, body, &updated); err != nil {
+			return fmt.Errorf("failed to update taxon: %w", err)
+		}
+		fmt.Printf("Updated taxon: %s [%s]\n", updated.Name, updated.Level)
+		return nil
 	}
 
-	edited, err := editor.EditTaxon(existing)
+	edited, err := editor.EditTaxon(clientTaxonToModel(existing))
 	if err != nil {
 		return err
 	}
 
-	if err := database.UpdateTaxon(edited); err != nil {
-		return err
+	if isActualRemote() && !confirmRemoteOperation("Update", fmt.Sprintf("taxon %s [%s]", edited.Name, edited.Level)) {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	if _, err := apiClient.UpdateTaxonIfMatch(ctx, oakclient.TaxonLevel(level), name, modelTaxonToRequest(edited), etag); err != nil {
+		if oakclient.IsPreconditionFailedError(err) {
+			return editConflict(fmt.Sprintf("taxon %s [%s]", name, level), edited, "")
+		}
+		return fmt.Errorf("failed to update taxon: %w", err)
 	}
 
 	fmt.Printf("Updated taxon: %s [%s]\n", edited.Name, edited.Level)
@@ -473,24 +564,28 @@ func runTaxaEdit(cmd *cobra.Command, args []string) error {
 func runTaxaDelete(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
-	level, err := parseTaxonLevel(taxaLevel)
-	if err != nil {
-		return err
-	}
-
 	database, err := db.New(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
 	defer database.Close()
 
+	levels, err := database.ListTaxonLevels()
+	if err != nil {
+		return err
+	}
+	level, err := parseTaxonLevel(taxaLevel, levels)
+	if err != nil {
+		return err
+	}
+
 	// Check if exists
 	existing, err := database.GetTaxon(name, level)
 	if err != nil {
 		return err
 	}
 	if existing == nil {
-		return fmt.Errorf("taxon not found: %s [%s]", name, level)
+		return notFoundErrorf("taxon not found: %s [%s]", name, level)
 	}
 
 	// Confirm deletion unless --force
@@ -517,23 +612,28 @@ func runTaxaDelete(cmd *cobra.Command, args []string) error {
 }
 
 func runTaxaShow(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
 	name := args[0]
 
-	level, err := parseTaxonLevel(taxaLevel)
+	apiClient, err := getAPIClient()
 	if err != nil {
 		return err
 	}
 
-	apiClient, err := getAPIClient()
+	levels, err := apiClient.ListTaxonLevels(ctx)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+	level, err := parseTaxonLevel(taxaLevel, clientTaxonLevelsToModel(levels))
 	if err != nil {
 		return err
 	}
 
-	// Note: client.GetTaxon takes level first, then name
-	taxon, err := apiClient.GetTaxon(client.TaxonLevel(level), name)
+	// Note: oakclient.GetTaxon takes level first, then name
+	taxon, err := apiClient.GetTaxon(ctx, oakclient.TaxonLevel(level), name)
 	if err != nil {
-		if client.IsNotFoundError(err) {
-			return fmt.Errorf("taxon not found: %s [%s]", name, level)
+		if oakclient.IsNotFoundError(err) {
+			return notFoundErrorf("taxon not found: %s [%s]", name, level)
 		}
 		return fmt.Errorf("API error: %w", err)
 	}
@@ -600,8 +700,8 @@ func runTaxaFind(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// clientTaxonToModel converts a client.Taxon to models.Taxon.
-func clientTaxonToModel(t *client.Taxon) *models.Taxon {
+// clientTaxonToModel converts a oakclient.Taxon to models.Taxon.
+func clientTaxonToModel(t *oakclient.Taxon) *models.Taxon {
 	// Convert links
 	var links []models.TaxonLink
 	if len(t.Links) > 0 {
@@ -614,9 +714,44 @@ func clientTaxonToModel(t *client.Taxon) *models.Taxon {
 	return &models.Taxon{
 		Name:   t.Name,
 		Level:  models.TaxonLevel(t.Level),
+		Genus:  t.Genus,
+		Parent: t.Parent,
+		Author: t.Author,
+		Notes:  t.Notes,
+		Links:  links,
+	}
+}
+
+// modelTaxonToRequest converts a models.Taxon to a oakclient.TaxonRequest. The
+// links are always sent, so removing them all clears them.
+func modelTaxonToRequest(t *models.Taxon) *oakclient.TaxonRequest {
+	links := make([]oakclient.TaxonLink, len(t.Links))
+	for i, l := range t.Links {
+		links[i] = oakclient.TaxonLink{Label: l.Label, URL: l.URL}
+	}
+
+	return &oakclient.TaxonRequest{
+		Name:   t.Name,
+		Level:  oakclient.TaxonLevel(t.Level),
+		Genus:  t.Genus,
 		Parent: t.Parent,
 		Author: t.Author,
 		Notes:  t.Notes,
 		Links:  links,
 	}
 }
+
+// clientTaxonLevelsToModel converts client taxon levels to models.
+func clientTaxonLevelsToModel(levels []*oakclient.TaxonLevelDef) []*models.TaxonLevelDef {
+	result := make([]*models.TaxonLevelDef, len(levels))
+	for i, l := range levels {
+		result[i] = &models.TaxonLevelDef{
+			Name:       l.Name,
+			Rank:       l.Rank,
+			Plural:     l.Plural,
+			EntryField: l.EntryField,
+			TaxaCount:  l.TaxaCount,
+		}
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var taxaLevelsCmd = &cobra.Command{
+	Use:   "levels",
+	Short: "List the configured taxon levels",
+	Long: `List the taxonomic levels below genus, in rank order.
+
+Levels are configurable so alternative rank systems can be used. Each level has
+a rank (lower ranks sit closer to genus), an optional plural used as its key in
+'oak taxa import' files, and an optional entry field: the species field
+(subgenus, section, subsection, or complex) that places species at that level
+and drives species counts.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		levels, err := apiClient.ListTaxonLevels(ctx)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RANK\tLEVEL\tPLURAL\tENTRY FIELD\tTAXA")
+		fmt.Fprintln(w, "----\t-----\t------\t-----------\t----")
+		for _, l := range levels {
+			plural, field := "", "-"
+			if l.Plural != nil {
+				plural = *l.Plural
+			}
+			if l.EntryField != nil {
+				field = *l.EntryField
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\n", l.Rank, l.Name, plural, field, l.TaxaCount)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var (
+	taxonLevelRank       int
+	taxonLevelPlural     string
+	taxonLevelEntryField string
+)
+
+var taxaLevelsSetCmd = &cobra.Command{
+	Use:   "set <name> --rank <n>",
+	Short: "Create or redefine a taxon level",
+	Long: `Create a taxon level, or replace the definition of an existing one.
+
+Examples:
+  oak taxa levels set series --rank 5 --plural series
+  oak taxa levels set section --rank 2 --plural sections --entry-field section --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if isActualRemote() && !confirmRemoteOperation("Set taxon level", args[0]) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		req := &oakclient.TaxonLevelRequest{Rank: taxonLevelRank}
+		if taxonLevelPlural != "" {
+			req.Plural = &taxonLevelPlural
+		}
+		if taxonLevelEntryField != "" {
+			req.EntryField = &taxonLevelEntryField
+		}
+		level, err := apiClient.SaveTaxonLevel(ctx, args[0], req)
+		if err != nil {
+			if oakclient.IsConflictError(err) {
+				return fmt.Errorf("rank %d is already used by another level", taxonLevelRank)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Saved taxon level %s (rank %d)\n", level.Name, level.Rank)
+		return nil
+	},
+}
+
+var taxaLevelsDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete an unused taxon level",
+	Long: `Delete a taxon level. Fails while any taxa are still assigned to it.
+
+Examples:
+  oak taxa levels delete series`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if isActualRemote() && !confirmRemoteOperation("Delete taxon level", args[0]) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		if err := apiClient.DeleteTaxonLevel(ctx, args[0]); err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("taxon level '%s' not found", args[0])
+			}
+			if oakclient.IsConflictError(err) {
+				return fmt.Errorf("taxon level '%s' cannot be deleted: taxa are still assigned to it", args[0])
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Deleted taxon level %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	taxaLevelsSetCmd.Flags().IntVar(&taxonLevelRank, "rank", 0, "Position below genus (1 = closest to genus)")
+	taxaLevelsSetCmd.Flags().StringVar(&taxonLevelPlural, "plural", "", "Plural used as the key in taxa import files")
+	taxaLevelsSetCmd.Flags().StringVar(&taxonLevelEntryField, "entry-field", "", "Species field for this level (subgenus, section, subsection, complex)")
+	_ = taxaLevelsSetCmd.MarkFlagRequired("rank")
+
+	taxaLevelsCmd.AddCommand(taxaLevelsSetCmd)
+	taxaLevelsCmd.AddCommand(taxaLevelsDeleteCmd)
+	taxaCmd.AddCommand(taxaLevelsCmd)
+}
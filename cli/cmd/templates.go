@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Manage entry templates for taxonomic groups",
+	Long: `Commands for entry templates. A template pre-fills new species for a
+taxonomic group (e.g. section Lobatae) with its genus and taxonomy, attaches
+default sources once the species is created, and lists fields that must be
+filled in before saving.
+
+Use a template with 'oak new <name> --template <template>' or
+'oak species new <name> --template <template>'.`,
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List entry templates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		templates, err := apiClient.ListTemplates(ctx)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+		if len(templates) == 0 {
+			fmt.Println("No templates defined")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TEMPLATE\tTAXONOMY\tSOURCES\tREQUIRED")
+		fmt.Fprintln(w, "--------\t--------\t-------\t--------")
+		for _, t := range templates {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", t.Name, templateTaxonomy(t), len(t.SourceIDs), strings.Join(t.RequiredFields, ", "))
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var templatesShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show an entry template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		t, err := apiClient.GetTemplate(ctx, args[0])
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("template '%s' not found", args[0])
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Template: %s\n", t.Name)
+		if t.Description != nil && *t.Description != "" {
+			fmt.Printf("Description: %s\n", *t.Description)
+		}
+		fmt.Printf("Taxonomy: %s\n", templateTaxonomy(t))
+		ids := make([]string, len(t.SourceIDs))
+		for i, id := range t.SourceIDs {
+			ids[i] = strconv.FormatInt(id, 10)
+		}
+		fmt.Printf("Sources:  %s\n", strings.Join(ids, ", "))
+		fmt.Printf("Required: %s\n", strings.Join(t.RequiredFields, ", "))
+		return nil
+	},
+}
+
+var (
+	templateDescription string
+	templateGenus       string
+	templateSubgenus    string
+	templateSection     string
+	templateSubsection  string
+	templateComplex     string
+	templateSources     []int64
+	templateRequired    []string
+)
+
+var templatesSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Create or replace an entry template",
+	Long: `Create an entry template, or replace an existing one with the given flags.
+
+Required fields: author, conservation_status, subgenus, section, subsection,
+complex, parent1, parent2, synonyms, closely_related_to, external_links.
+
+Examples:
+  oak templates set lobatae --subgenus Quercus --section Lobatae --source 1 --require author
+  oak templates set albae --subgenus Quercus --section Quercus --subsection Albae --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if isActualRemote() && !confirmRemoteOperation("Set template", args[0]) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		optional := func(s string) *string {
+			if s == "" {
+				return nil
+			}
+			return &s
+		}
+		req := &oakclient.TemplateRequest{
+			Description:    optional(templateDescription),
+			Genus:          templateGenus,
+			Subgenus:       optional(templateSubgenus),
+			Section:        optional(templateSection),
+			Subsection:     optional(templateSubsection),
+			Complex:        optional(templateComplex),
+			SourceIDs:      templateSources,
+			RequiredFields: templateRequired,
+		}
+		t, err := apiClient.SaveTemplate(ctx, args[0], req)
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Saved template %s (%s)\n", t.Name, templateTaxonomy(t))
+		return nil
+	},
+}
+
+var templatesDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete an entry template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext()
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		if isActualRemote() && !confirmRemoteOperation("Delete template", args[0]) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		if err := apiClient.DeleteTemplate(ctx, args[0]); err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("template '%s' not found", args[0])
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Deleted template %s\n", args[0])
+		return nil
+	},
+}
+
+// templateTaxonomy formats a template's pre-filled taxonomy, e.g. "Quercus > Quercus > Lobatae".
+func templateTaxonomy(t *oakclient.Template) string {
+	parts := []string{t.Genus}
+	for _, p := range []*string{t.Subgenus, t.Section, t.Subsection, t.Complex} {
+		if p != nil && *p != "" {
+			parts = append(parts, *p)
+		}
+	}
+	return strings.Join(parts, " > ")
+}
+
+func init() {
+	templatesSetCmd.Flags().StringVar(&templateDescription, "description", "", "What the template is for")
+	templatesSetCmd.Flags().StringVar(&templateGenus, "genus", "", "Genus (default Quercus)")
+	templatesSetCmd.Flags().StringVar(&templateSubgenus, "subgenus", "", "Subgenus to pre-fill")
+	templatesSetCmd.Flags().StringVar(&templateSection, "section", "", "Section to pre-fill")
+	templatesSetCmd.Flags().StringVar(&templateSubsection, "subsection", "", "Subsection to pre-fill")
+	templatesSetCmd.Flags().StringVar(&templateComplex, "complex", "", "Complex to pre-fill")
+	templatesSetCmd.Flags().Int64SliceVar(&templateSources, "source", nil, "Source ID to attach to new species (repeatable; the first is preferred)")
+	templatesSetCmd.Flags().StringSliceVar(&templateRequired, "require", nil, "Field that must be filled in (repeatable)")
+
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesShowCmd)
+	templatesCmd.AddCommand(templatesSetCmd)
+	templatesCmd.AddCommand(templatesDeleteCmd)
+	rootCmd.AddCommand(templatesCmd)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/editor"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var validateKind string
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <file.md|yaml>...",
+	Short: "Check documents the way the editor would, without opening it",
+	Long: `Parse and validate documents saved from 'oak new', 'oak edit', 'oak note',
+'oak source', or 'oak taxa' (or written by hand) using the same front matter
+parsing and checks as the editor loop, so you can edit in your own workflow
+and validate in a pre-commit hook.
+
+Markdown files are read as the editor writes them; .yaml and .yml files hold
+the front matter alone. The kind (oak-entry, species-source, source, taxon)
+is taken from the schema modeline or the document's fields unless --kind is
+given. Oak entries are checked against the schema file (-s).
+
+Prints one line per problem as file:field: message and exits with code 4 if
+any document fails. With --error-format json the problems are written to
+stderr as error details.
+
+Examples:
+  oak validate rubra.md
+  oak validate --kind taxon notes/*.yaml
+  git diff --cached --name-only -- '*.md' | xargs oak validate --error-format json`,
+	Args: cobra.MinimumNArgs(1),
+	// Validation reads files only; skip profile resolution and the embedded server
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return checkErrorFormat(cmd)
+	},
+	RunE: runValidate,
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().StringVar(&validateKind, "kind", "", "Document kind: "+strings.Join(editor.Kinds, ", ")+" (detected by default)")
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	if validateKind != "" && !validKind(validateKind) {
+		return usageErrorf("invalid --kind %q (must be one of: %s)", validateKind, strings.Join(editor.Kinds, ", "))
+	}
+
+	cmd.SilenceUsage = true // Arguments are checked; failures from here on are about the files
+
+	// The schema file is only needed for oak entries; report it if one comes up
+	validator, schemaErr := getSchema()
+
+	var failures []oakclient.ValidationError
+	failedFiles := 0
+	for _, path := range args {
+		kind, problems, err := editor.ValidateFile(path, validateKind, validator)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return notFoundErrorf("file not found: %s", path)
+			}
+			if kind == editor.KindOakEntry && schemaErr != nil {
+				err = schemaErr
+			}
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		if len(problems) == 0 {
+			fmt.Printf("%s: ok (%s)\n", path, kind)
+			continue
+		}
+		failedFiles++
+		for _, p := range problems {
+			field := path
+			if p.Field != "" {
+				field += ":" + p.Field
+			}
+			fmt.Printf("%s: %s\n", field, p.Message)
+			failures = append(failures, oakclient.ValidationError{Field: field, Message: p.Message})
+		}
+	}
+
+	if len(failures) > 0 {
+		return &validateError{files: failedFiles, problems: &oakclient.MultiValidationError{Errors: failures}}
+	}
+	return nil
+}
+
+// validateError summarizes the problems already printed, while carrying them
+// for the exit code and --error-format json details
+type validateError struct {
+	files    int
+	problems *oakclient.MultiValidationError
+}
+
+func (e *validateError) Error() string {
+	return fmt.Sprintf("%d problem(s) in %d file(s)", len(e.problems.Errors), e.files)
+}
+
+func (e *validateError) Unwrap() error {
+	return e.problems
+}
+
+func validKind(kind string) bool {
+	for _, k := range editor.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+func TestValidateErrorReport(t *testing.T) {
+	err := &validateError{files: 1, problems: &oakclient.MultiValidationError{Errors: []oakclient.ValidationError{
+		{Field: "s.md:name", Message: "cannot be empty"},
+	}}}
+
+	if got := ExitCode(err); got != ExitValidation {
+		t.Errorf("ExitCode() = %d, want %d", got, ExitValidation)
+	}
+
+	errorFormat = errorFormatJSON
+	defer func() { errorFormat = errorFormatText }()
+
+	var buf bytes.Buffer
+	HandleError(&buf, err)
+	var report errorReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON report: %v", err)
+	}
+	if report.Error.Message != "1 problem(s) in 1 file(s)" {
+		t.Errorf("message = %q", report.Error.Message)
+	}
+	if len(report.Error.Details) != 1 || report.Error.Details[0].Field != "s.md:name" {
+		t.Errorf("details = %+v", report.Error.Details)
+	}
+}
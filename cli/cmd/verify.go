@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jeff/oaks/cli/internal/editor"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var (
+	verifyMethod   string
+	verifySourceID int64
+	verifyBelow    float64
+	verifyBy       string
+	verifyStatus   string
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check transcribed source text against the originals",
+	Long: `Species-source text typed or OCR'd from print records how it was
+transcribed (transcription_method manual, ocr, or import), by whom, and for
+OCR the engine's confidence. Such text is unverified until a curator has
+compared it with the original; changing the method or confidence, as a new
+OCR run does, makes it unverified again. Text without a transcription method
+is not tracked.`,
+}
+
+var verifyNextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Walk unverified transcriptions, least confident first",
+	Long: `Show each unverified transcription in turn, least confident OCR first,
+with its page locators. For each, verify it, edit it in $EDITOR to correct
+the text (then decide again), skip it, or quit.
+
+Examples:
+  oak verify next
+  oak verify next --method ocr --below 0.9 --by "J. Clark"
+  oak verify next --source-id 4 --remote`,
+	Args: cobra.NoArgs,
+	RunE: runVerifyNext,
+}
+
+var verifyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List transcriptions awaiting verification",
+	Long: `List unverified transcriptions, least confident OCR first. --status
+lists verified or all transcriptions instead.
+
+Examples:
+  oak verify list
+  oak verify list --method ocr --below 0.8
+  oak verify list --status all --source-id 4`,
+	Args: cobra.NoArgs,
+	RunE: runVerifyList,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{verifyNextCmd, verifyListCmd} {
+		c.Flags().StringVar(&verifyMethod, "method", "", "Only this transcription method (manual, ocr, import)")
+		c.Flags().Int64Var(&verifySourceID, "source-id", 0, "Only this source")
+		c.Flags().Float64Var(&verifyBelow, "below", 0, "Only OCR'd text at or below this confidence (0-1)")
+	}
+	verifyNextCmd.Flags().StringVar(&verifyBy, "by", "", "Name to record as the verifier")
+	verifyListCmd.Flags().StringVar(&verifyStatus, "status", "", "unverified (default), verified, or all")
+
+	verifyCmd.AddCommand(verifyNextCmd)
+	verifyCmd.AddCommand(verifyListCmd)
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// transcriptionFilter builds the list filter from the command's flags
+func transcriptionFilter(cmd *cobra.Command) (oakclient.TranscriptionFilter, error) {
+	filter := oakclient.TranscriptionFilter{Method: verifyMethod, SourceID: verifySourceID}
+	if cmd.Flags().Changed("below") {
+		if verifyBelow < 0 || verifyBelow > 1 {
+			return filter, usageErrorf("--below must be between 0 and 1")
+		}
+		filter.MaxConfidence = &verifyBelow
+	}
+	switch verifyStatus {
+	case "", "unverified":
+	case "verified":
+		filter.Verified = "true"
+	case "all":
+		filter.Verified = "all"
+	default:
+		return filter, usageErrorf("--status must be unverified, verified, or all")
+	}
+	return filter, nil
+}
+
+func runVerifyList(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	filter, err := transcriptionFilter(cmd)
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	transcriptions, err := apiClient.ListTranscriptions(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+	if len(transcriptions) == 0 {
+		fmt.Println("No transcriptions")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SPECIES\tSOURCE\tPAGES\tMETHOD\tCONFIDENCE\tTRANSCRIBER\tVERIFIED")
+	fmt.Fprintln(w, "-------\t------\t-----\t------\t----------\t-----------\t--------")
+	for _, t := range transcriptions {
+		confidence := "-"
+		if t.OCRConfidence != nil {
+			confidence = fmt.Sprintf("%.2f", *t.OCRConfidence)
+		}
+		verified := "no"
+		if t.VerifiedAt != nil {
+			verified = t.VerifiedAt.Format("2006-01-02")
+			if t.VerifiedBy != nil {
+				verified += " by " + *t.VerifiedBy
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s (%d)\t%s\t%s\t%s\t%s\t%s\n", t.ScientificName, t.SourceName, t.SourceID,
+			derefOr(t.Pages, "-"), t.TranscriptionMethod, confidence, derefOr(t.Transcriber, "-"), verified)
+	}
+	w.Flush()
+	return nil
+}
+
+func runVerifyNext(cmd *cobra.Command, args []string) error {
+	ctx := commandContext()
+	filter, err := transcriptionFilter(cmd)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	apiClient, err := getAPIClient()
+	if err != nil {
+		return err
+	}
+
+	queue, err := apiClient.ListTranscriptions(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("API error: %w", err)
+	}
+	if len(queue) == 0 {
+		fmt.Println("Nothing to verify")
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	verified := 0
+	defer func() { fmt.Printf("Verified %d of %d\n", verified, len(queue)) }()
+
+	for i, t := range queue {
+		ss, etag, err := apiClient.GetSpeciesSourceWithETag(ctx, t.ScientificName, t.SourceID)
+		if oakclient.IsNotFoundError(err) {
+			continue // Deleted since the queue was listed
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s from %s: %w", t.ScientificName, t.SourceName, err)
+		}
+
+	decide:
+		for {
+			printTranscription(os.Stdout, i+1, len(queue), t, ss)
+			fmt.Print("[v]erify, [e]dit, [s]kip, [q]uit: ")
+			answer, err := reader.ReadString('\n')
+			if err != nil && answer == "" {
+				return nil // End of input
+			}
+
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "v", "verify":
+				if _, err := apiClient.VerifySpeciesSource(ctx, t.ScientificName, t.SourceID, verifyBy); err != nil {
+					return fmt.Errorf("failed to verify %s from %s: %w", t.ScientificName, t.SourceName, err)
+				}
+				verified++
+				break decide
+			case "e", "edit":
+				edited, err := editor.EditSpeciesSource(clientSpeciesSourceToModel(ss), t.SourceName)
+				if err != nil {
+					return err
+				}
+				what := fmt.Sprintf("notes for %s from %s", t.ScientificName, t.SourceName)
+				_, err = apiClient.UpdateSpeciesSourceIfMatch(ctx, t.ScientificName, t.SourceID, modelSpeciesSourceToClient(edited), etag)
+				if oakclient.IsPreconditionFailedError(err) {
+					return editConflict(what, edited, t.SourceName)
+				}
+				if err != nil {
+					return fmt.Errorf("failed to save %s: %w", what, err)
+				}
+				if ss, etag, err = apiClient.GetSpeciesSourceWithETag(ctx, t.ScientificName, t.SourceID); err != nil {
+					return fmt.Errorf("failed to fetch %s: %w", what, err)
+				}
+			case "s", "skip", "":
+				break decide
+			case "q", "quit":
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// printTranscription shows a transcription's provenance and full text, with
+// the locator of each field that has its own
+func printTranscription(w io.Writer, n, total int, t *oakclient.Transcription, ss *oakclient.SpeciesSource) {
+	fmt.Fprintf(w, "\n[%d/%d] %s, %s", n, total, t.ScientificName, t.SourceName)
+	if ss.Pages != nil {
+		fmt.Fprintf(w, ", %s", *ss.Pages)
+	}
+	fmt.Fprintf(w, "\nTranscribed by %s (%s", derefOr(ss.Transcriber, "unknown"), derefOr(ss.TranscriptionMethod, "unknown method"))
+	if ss.OCRConfidence != nil {
+		fmt.Fprintf(w, ", confidence %.2f", *ss.OCRConfidence)
+	}
+	fmt.Fprintln(w, ")")
+
+	fields := []struct {
+		label, name string
+		value       *string
+	}{
+		{"Range", "range", ss.Range},
+		{"Growth habit", "growth_habit", ss.GrowthHabit},
+		{"Leaves", "leaves", ss.Leaves},
+		{"Flowers", "flowers", ss.Flowers},
+		{"Fruits", "fruits", ss.Fruits},
+		{"Bark", "bark", ss.Bark},
+		{"Twigs", "twigs", ss.Twigs},
+		{"Buds", "buds", ss.Buds},
+		{"Hardiness/habitat", "hardiness_habitat", ss.HardinessHabitat},
+		{"Miscellaneous", "miscellaneous", ss.Miscellaneous},
+		{"Distinguishing", "distinguishing_features", ss.DistinguishingFeatures},
+	}
+	for _, f := range fields {
+		if f.value == nil || *f.value == "" {
+			continue
+		}
+		if locator := ss.FieldPages[f.name]; locator != "" {
+			fmt.Fprintf(w, "\n%s (%s):\n%s\n", f.label, locator, *f.value)
+		} else {
+			fmt.Fprintf(w, "\n%s:\n%s\n", f.label, *f.value)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// derefOr returns *s, or fallback when s is nil or empty
+func derefOr(s *string, fallback string) string {
+	if s == nil || *s == "" {
+		return fallback
+	}
+	return *s
+}
@@ -5,7 +5,7 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/jeff/oaks/cli/internal/client"
+	"github.com/jeff/oaks/pkg/oakclient"
 )
 
 var versionCmd = &cobra.Command{
@@ -18,7 +18,8 @@ Examples:
   oak version              # Show CLI version (and API version if configured)
   oak version --remote     # Force connection to API to show server version`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Printf("CLI version: %s\n", client.CLIVersion)
+		ctx := commandContext()
+		fmt.Printf("CLI version: %s\n", oakclient.Version)
 
 		// If we have an API profile, try to get the API version
 		if isRemoteMode() {
@@ -28,9 +29,9 @@ Examples:
 				return nil
 			}
 
-			health, err := apiClient.Health()
+			health, err := apiClient.Health(ctx)
 			if err != nil {
-				fmt.Printf("API [%s]: connection error: %v\n", resolvedProfile.Name, err)
+				fmt.Printf("API [%s]: %v\n", resolvedProfile.Name, err)
 				return nil
 			}
 
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+var vocabularyName string
+
+var vocabularyCmd = &cobra.Command{
+	Use:     "vocabulary",
+	Aliases: []string{"vocab"},
+	Short:   "Manage controlled vocabularies",
+	Long: `Commands for managed vocabularies: lists of terms with definitions. A
+vocabulary bound to a field restricts the values the API accepts for it:
+conservation_status is bound to species.conservation_status and source_type to
+source.source_type. Unbound vocabularies document terms, and all of them are
+listed in the data dictionary ('oak schema dictionary').
+
+Define a vocabulary in YAML and save it with 'oak vocabulary save':
+
+  name: conservation_status
+  description: IUCN Red List categories
+  field: species.conservation_status
+  terms:
+    - {term: EX, definition: Extinct}
+    - {term: RE, definition: Regionally Extinct}`,
+}
+
+var vocabularyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List vocabularies",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		vocabularies, err := apiClient.ListVocabularies(commandContext())
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+		if len(vocabularies) == 0 {
+			fmt.Println("No vocabularies defined")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VOCABULARY\tFIELD\tTERMS")
+		fmt.Fprintln(w, "----------\t-----\t-----")
+		for _, v := range vocabularies {
+			field := "-"
+			if v.Field != nil {
+				field = *v.Field
+			}
+			terms := make([]string, len(v.Terms))
+			for i, t := range v.Terms {
+				terms[i] = t.Term
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", v.Name, field, strings.Join(terms, ", "))
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var vocabularyShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Print a vocabulary as YAML",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+
+		v, err := apiClient.GetVocabulary(commandContext(), args[0])
+		if err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("vocabulary '%s' not found", args[0])
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		// Prints in the form 'oak vocabulary save' reads back
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+		return nil
+	},
+}
+
+var vocabularySaveCmd = &cobra.Command{
+	Use:   "save <file.yaml>",
+	Short: "Create or replace a vocabulary from YAML",
+	Long: `Create a vocabulary from YAML, or replace the one with the same name and
+all of its terms. Binding a field another vocabulary is bound to, or leaving
+out a term that species or sources still use, is refused.
+
+Examples:
+  oak vocabulary show conservation_status > status.yaml
+  oak vocabulary save status.yaml --remote`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := readImportFile(args[0])
+		if err != nil {
+			return err
+		}
+		var def oakclient.ManagedVocabulary
+		if err := yaml.Unmarshal(data, &def); err != nil {
+			return usageErrorf("invalid vocabulary %s: %v", args[0], err)
+		}
+		if vocabularyName != "" {
+			def.Name = vocabularyName
+		}
+		if def.Name == "" {
+			return usageErrorf("%s has no name; add one or pass --name", args[0])
+		}
+		cmd.SilenceUsage = true // Failures from here on are about the vocabulary, not the arguments
+
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+		if isActualRemote() && !confirmRemoteOperation("Save vocabulary", def.Name) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		v, err := apiClient.SaveVocabulary(commandContext(), def.Name, &oakclient.VocabularyRequest{
+			Description: def.Description,
+			Field:       def.Field,
+			Terms:       def.Terms,
+		})
+		var apiErr *oakclient.APIError
+		if errors.As(err, &apiErr) && len(apiErr.Fields) > 0 {
+			problems := make([]string, len(apiErr.Fields))
+			for i, f := range apiErr.Fields {
+				problems[i] = f.Field + ": " + f.Message
+			}
+			return &exitError{code: ExitValidation, err: fmt.Errorf("vocabulary %s refused: %s", def.Name, strings.Join(problems, "; "))}
+		}
+		if oakclient.IsConflictError(err) {
+			return fmt.Errorf("vocabulary %s refused: %w", def.Name, err)
+		}
+		if err != nil {
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Saved vocabulary %s (%d terms)\n", v.Name, len(v.Terms))
+		return nil
+	},
+}
+
+var vocabularyDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a vocabulary that is not bound to a field",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiClient, err := getAPIClient()
+		if err != nil {
+			return err
+		}
+		if isActualRemote() && !confirmRemoteOperation("Delete vocabulary", args[0]) {
+			fmt.Println("Canceled")
+			return nil
+		}
+
+		if err := apiClient.DeleteVocabulary(commandContext(), args[0]); err != nil {
+			if oakclient.IsNotFoundError(err) {
+				return notFoundErrorf("vocabulary '%s' not found", args[0])
+			}
+			if oakclient.IsConflictError(err) {
+				return fmt.Errorf("vocabulary '%s' cannot be deleted: %w", args[0], err)
+			}
+			return fmt.Errorf("API error: %w", err)
+		}
+
+		fmt.Printf("Deleted vocabulary %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	vocabularySaveCmd.Flags().StringVar(&vocabularyName, "name", "", "Vocabulary name (overrides the file's)")
+
+	vocabularyCmd.AddCommand(vocabularyListCmd)
+	vocabularyCmd.AddCommand(vocabularyShowCmd)
+	vocabularyCmd.AddCommand(vocabularySaveCmd)
+	vocabularyCmd.AddCommand(vocabularyDeleteCmd)
+	rootCmd.AddCommand(vocabularyCmd)
+}
@@ -15,3 +15,9 @@ require (
 )
 
 replace github.com/jeff/oaks/api => ../api
+
+replace github.com/jeff/oaks/pkg/apierror => ../pkg/apierror
+
+replace github.com/jeff/oaks/pkg/migrate => ../pkg/migrate
+
+replace github.com/jeff/oaks/pkg/oakclient => ../pkg/oakclient
@@ -9,8 +9,9 @@ import (
 	"testing"
 
 	"github.com/jeff/oaks/api/embed"
-	"github.com/jeff/oaks/cli/internal/client"
 	"github.com/jeff/oaks/cli/internal/config"
+	"github.com/jeff/oaks/cli/internal/replica"
+	"github.com/jeff/oaks/pkg/oakclient"
 )
 
 // Integration tests for CLI embedded and remote modes.
@@ -40,14 +41,14 @@ func TestEmbeddedAPI_CRUD(t *testing.T) {
 		Key:    server.APIKey(),
 		Source: config.SourceEmbedded,
 	}
-	c, err := client.New(profile, client.WithSkipVersionCheck(true))
+	c, err := oakclient.New(profile.URL, oakclient.WithAPIKey(profile.Key), oakclient.WithProfileName(profile.Name), oakclient.WithSkipVersionCheck(true))
 	if err != nil {
 		t.Fatalf("failed to create client: %v", err)
 	}
 
 	// Test species CRUD
 	t.Run("Species_Create", func(t *testing.T) {
-		species, err := c.CreateSpecies(&client.SpeciesRequest{
+		species, err := c.CreateSpecies(t.Context(), &oakclient.SpeciesRequest{
 			ScientificName: "alba",
 			IsHybrid:       false,
 		})
@@ -60,7 +61,7 @@ func TestEmbeddedAPI_CRUD(t *testing.T) {
 	})
 
 	t.Run("Species_Get", func(t *testing.T) {
-		species, err := c.GetSpecies("alba")
+		species, err := c.GetSpecies(t.Context(), "alba")
 		if err != nil {
 			t.Fatalf("GetSpecies failed: %v", err)
 		}
@@ -70,7 +71,7 @@ func TestEmbeddedAPI_CRUD(t *testing.T) {
 	})
 
 	t.Run("Species_List", func(t *testing.T) {
-		resp, err := c.ListSpecies(nil)
+		resp, err := c.ListSpecies(t.Context(), nil)
 		if err != nil {
 			t.Fatalf("ListSpecies failed: %v", err)
 		}
@@ -81,7 +82,7 @@ func TestEmbeddedAPI_CRUD(t *testing.T) {
 
 	t.Run("Species_Update", func(t *testing.T) {
 		author := "L. 1753"
-		species, err := c.UpdateSpecies("alba", &client.SpeciesRequest{
+		species, err := c.UpdateSpecies(t.Context(), "alba", &oakclient.SpeciesRequest{
 			ScientificName: "alba",
 			Author:         &author,
 		})
@@ -94,13 +95,13 @@ func TestEmbeddedAPI_CRUD(t *testing.T) {
 	})
 
 	t.Run("Species_Delete", func(t *testing.T) {
-		if err := c.DeleteSpecies("alba"); err != nil {
+		if err := c.DeleteSpecies(t.Context(), "alba"); err != nil {
 			t.Fatalf("DeleteSpecies failed: %v", err)
 		}
 
 		// Verify deletion
-		_, err := c.GetSpecies("alba")
-		if !client.IsNotFoundError(err) {
+		_, err := c.GetSpecies(t.Context(), "alba")
+		if !oakclient.IsNotFoundError(err) {
 			t.Errorf("expected not found error, got %v", err)
 		}
 	})
@@ -126,13 +127,13 @@ func TestEmbeddedAPI_Taxa(t *testing.T) {
 		Key:    server.APIKey(),
 		Source: config.SourceEmbedded,
 	}
-	c, err := client.New(profile, client.WithSkipVersionCheck(true))
+	c, err := oakclient.New(profile.URL, oakclient.WithAPIKey(profile.Key), oakclient.WithProfileName(profile.Name), oakclient.WithSkipVersionCheck(true))
 	if err != nil {
 		t.Fatalf("failed to create client: %v", err)
 	}
 
 	t.Run("Taxa_Create", func(t *testing.T) {
-		taxon, err := c.CreateTaxon(&client.TaxonRequest{
+		taxon, err := c.CreateTaxon(t.Context(), &oakclient.TaxonRequest{
 			Name:  "Lobatae",
 			Level: "section",
 		})
@@ -145,7 +146,7 @@ func TestEmbeddedAPI_Taxa(t *testing.T) {
 	})
 
 	t.Run("Taxa_Get", func(t *testing.T) {
-		taxon, err := c.GetTaxon("section", "Lobatae")
+		taxon, err := c.GetTaxon(t.Context(), "section", "Lobatae")
 		if err != nil {
 			t.Fatalf("GetTaxon failed: %v", err)
 		}
@@ -155,7 +156,7 @@ func TestEmbeddedAPI_Taxa(t *testing.T) {
 	})
 
 	t.Run("Taxa_List", func(t *testing.T) {
-		resp, err := c.ListTaxa(nil)
+		resp, err := c.ListTaxa(t.Context(), nil)
 		if err != nil {
 			t.Fatalf("ListTaxa failed: %v", err)
 		}
@@ -185,13 +186,13 @@ func TestEmbeddedAPI_Sources(t *testing.T) {
 		Key:    server.APIKey(),
 		Source: config.SourceEmbedded,
 	}
-	c, err := client.New(profile, client.WithSkipVersionCheck(true))
+	c, err := oakclient.New(profile.URL, oakclient.WithAPIKey(profile.Key), oakclient.WithProfileName(profile.Name), oakclient.WithSkipVersionCheck(true))
 	if err != nil {
 		t.Fatalf("failed to create client: %v", err)
 	}
 
 	t.Run("Sources_Create", func(t *testing.T) {
-		source, err := c.CreateSource(&client.SourceRequest{
+		source, err := c.CreateSource(t.Context(), &oakclient.SourceRequest{
 			SourceType: "Website",
 			Name:       "Test Source",
 		})
@@ -207,12 +208,12 @@ func TestEmbeddedAPI_Sources(t *testing.T) {
 	})
 
 	t.Run("Sources_List", func(t *testing.T) {
-		sources, err := c.ListSources()
+		resp, err := c.ListSources(t.Context(), nil)
 		if err != nil {
 			t.Fatalf("ListSources failed: %v", err)
 		}
-		if len(sources) != 1 {
-			t.Errorf("got %d sources, want 1", len(sources))
+		if len(resp.Data) != 1 {
+			t.Errorf("got %d sources, want 1", len(resp.Data))
 		}
 	})
 }
@@ -238,7 +239,7 @@ func TestEmbeddedAPI_BidirectionalHybridRelationships(t *testing.T) {
 		Key:    server.APIKey(),
 		Source: config.SourceEmbedded,
 	}
-	c, err := client.New(profile, client.WithSkipVersionCheck(true))
+	c, err := oakclient.New(profile.URL, oakclient.WithAPIKey(profile.Key), oakclient.WithProfileName(profile.Name), oakclient.WithSkipVersionCheck(true))
 	if err != nil {
 		t.Fatalf("failed to create client: %v", err)
 	}
@@ -246,7 +247,7 @@ func TestEmbeddedAPI_BidirectionalHybridRelationships(t *testing.T) {
 	// Create parent species
 	t.Run("Setup_Parents", func(t *testing.T) {
 		for _, name := range []string{"alba", "macrocarpa", "rubra"} {
-			_, err := c.CreateSpecies(&client.SpeciesRequest{
+			_, err := c.CreateSpecies(t.Context(), &oakclient.SpeciesRequest{
 				ScientificName: name,
 				IsHybrid:       false,
 			})
@@ -260,7 +261,7 @@ func TestEmbeddedAPI_BidirectionalHybridRelationships(t *testing.T) {
 	t.Run("Create_Hybrid", func(t *testing.T) {
 		parent1 := "alba"
 		parent2 := "macrocarpa"
-		_, err := c.CreateSpecies(&client.SpeciesRequest{
+		_, err := c.CreateSpecies(t.Context(), &oakclient.SpeciesRequest{
 			ScientificName: "× bebbiana",
 			IsHybrid:       true,
 			Parent1:        &parent1,
@@ -273,7 +274,7 @@ func TestEmbeddedAPI_BidirectionalHybridRelationships(t *testing.T) {
 
 	// Verify parents have the hybrid in their hybrids list
 	t.Run("Verify_ParentHybrids", func(t *testing.T) {
-		alba, err := c.GetSpecies("alba")
+		alba, err := c.GetSpecies(t.Context(), "alba")
 		if err != nil {
 			t.Fatalf("GetSpecies(alba) failed: %v", err)
 		}
@@ -281,7 +282,7 @@ func TestEmbeddedAPI_BidirectionalHybridRelationships(t *testing.T) {
 			t.Errorf("alba.Hybrids = %v, want to contain '× bebbiana'", alba.Hybrids)
 		}
 
-		macrocarpa, err := c.GetSpecies("macrocarpa")
+		macrocarpa, err := c.GetSpecies(t.Context(), "macrocarpa")
 		if err != nil {
 			t.Fatalf("GetSpecies(macrocarpa) failed: %v", err)
 		}
@@ -294,7 +295,7 @@ func TestEmbeddedAPI_BidirectionalHybridRelationships(t *testing.T) {
 	t.Run("Update_HybridParent", func(t *testing.T) {
 		parent1 := "alba"
 		parent2 := "rubra"
-		_, err := c.UpdateSpecies("× bebbiana", &client.SpeciesRequest{
+		_, err := c.UpdateSpecies(t.Context(), "× bebbiana", &oakclient.SpeciesRequest{
 			ScientificName: "× bebbiana",
 			IsHybrid:       true,
 			Parent1:        &parent1,
@@ -307,7 +308,7 @@ func TestEmbeddedAPI_BidirectionalHybridRelationships(t *testing.T) {
 
 	// Verify macrocarpa no longer has the hybrid
 	t.Run("Verify_OldParentRemoved", func(t *testing.T) {
-		macrocarpa, err := c.GetSpecies("macrocarpa")
+		macrocarpa, err := c.GetSpecies(t.Context(), "macrocarpa")
 		if err != nil {
 			t.Fatalf("GetSpecies(macrocarpa) failed: %v", err)
 		}
@@ -318,7 +319,7 @@ func TestEmbeddedAPI_BidirectionalHybridRelationships(t *testing.T) {
 
 	// Verify rubra now has the hybrid
 	t.Run("Verify_NewParentAdded", func(t *testing.T) {
-		rubra, err := c.GetSpecies("rubra")
+		rubra, err := c.GetSpecies(t.Context(), "rubra")
 		if err != nil {
 			t.Fatalf("GetSpecies(rubra) failed: %v", err)
 		}
@@ -329,7 +330,7 @@ func TestEmbeddedAPI_BidirectionalHybridRelationships(t *testing.T) {
 
 	// Verify alba still has the hybrid (unchanged)
 	t.Run("Verify_UnchangedParent", func(t *testing.T) {
-		alba, err := c.GetSpecies("alba")
+		alba, err := c.GetSpecies(t.Context(), "alba")
 		if err != nil {
 			t.Fatalf("GetSpecies(alba) failed: %v", err)
 		}
@@ -353,23 +354,23 @@ func TestRemoteAPI_MockServer(t *testing.T) {
 		switch {
 		case r.URL.Path == "/api/v1/species" && r.Method == http.MethodGet:
 			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(client.SpeciesListResponse{
-				Data: []*client.OakEntry{
+			json.NewEncoder(w).Encode(oakclient.SpeciesListResponse{
+				Data: []*oakclient.OakEntry{
 					{ScientificName: "alba", IsHybrid: false},
 				},
-				Pagination: client.Pagination{Total: 1, Limit: 50, Offset: 0},
+				Pagination: oakclient.Pagination{Total: 1, Limit: 50, Offset: 0},
 			})
 		case r.URL.Path == "/api/v1/species/alba" && r.Method == http.MethodGet:
 			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(client.OakEntry{
+			json.NewEncoder(w).Encode(oakclient.OakEntry{
 				ScientificName: "alba",
 				IsHybrid:       false,
 			})
 		case r.URL.Path == "/api/v1/health" && r.Method == http.MethodGet:
 			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(client.HealthResponse{
+			json.NewEncoder(w).Encode(oakclient.HealthResponse{
 				Status: "ok",
-				Version: client.VersionInfo{
+				Version: oakclient.VersionInfo{
 					API:       "1.0.0",
 					MinClient: "1.0.0",
 				},
@@ -387,13 +388,13 @@ func TestRemoteAPI_MockServer(t *testing.T) {
 		Key:    "test-api-key",
 		Source: config.SourceConfig,
 	}
-	c, err := client.New(profile, client.WithSkipVersionCheck(true))
+	c, err := oakclient.New(profile.URL, oakclient.WithAPIKey(profile.Key), oakclient.WithProfileName(profile.Name), oakclient.WithSkipVersionCheck(true))
 	if err != nil {
 		t.Fatalf("failed to create client: %v", err)
 	}
 
 	t.Run("Remote_ListSpecies", func(t *testing.T) {
-		resp, err := c.ListSpecies(nil)
+		resp, err := c.ListSpecies(t.Context(), nil)
 		if err != nil {
 			t.Fatalf("ListSpecies failed: %v", err)
 		}
@@ -403,7 +404,7 @@ func TestRemoteAPI_MockServer(t *testing.T) {
 	})
 
 	t.Run("Remote_GetSpecies", func(t *testing.T) {
-		species, err := c.GetSpecies("alba")
+		species, err := c.GetSpecies(t.Context(), "alba")
 		if err != nil {
 			t.Fatalf("GetSpecies failed: %v", err)
 		}
@@ -430,16 +431,16 @@ func TestRemoteAPI_AuthFailure(t *testing.T) {
 		Key:    "wrong-key",
 		Source: config.SourceConfig,
 	}
-	c, err := client.New(profile, client.WithSkipVersionCheck(true))
+	c, err := oakclient.New(profile.URL, oakclient.WithAPIKey(profile.Key), oakclient.WithProfileName(profile.Name), oakclient.WithSkipVersionCheck(true))
 	if err != nil {
 		t.Fatalf("failed to create client: %v", err)
 	}
 
-	_, err = c.ListSpecies(nil)
+	_, err = c.ListSpecies(t.Context(), nil)
 	if err == nil {
 		t.Fatal("expected auth error")
 	}
-	if !client.IsAuthError(err) {
+	if !oakclient.IsAuthError(err) {
 		t.Errorf("expected auth error, got %v", err)
 	}
 }
@@ -484,7 +485,7 @@ func TestModeSwitch_ProfileResolution(t *testing.T) {
 		profile := &config.ResolvedProfile{
 			Source: config.SourceLocal,
 		}
-		_, err := client.New(profile)
+		_, err := oakclient.New(profile.URL)
 		if err == nil {
 			t.Error("expected error when creating client with local profile")
 		}
@@ -511,24 +512,24 @@ func TestEmbeddedAPI_Export(t *testing.T) {
 		Key:    server.APIKey(),
 		Source: config.SourceEmbedded,
 	}
-	c, err := client.New(profile, client.WithSkipVersionCheck(true))
+	c, err := oakclient.New(profile.URL, oakclient.WithAPIKey(profile.Key), oakclient.WithProfileName(profile.Name), oakclient.WithSkipVersionCheck(true))
 	if err != nil {
 		t.Fatalf("failed to create client: %v", err)
 	}
 
 	// Create some test data
-	_, _ = c.CreateSpecies(&client.SpeciesRequest{
+	_, _ = c.CreateSpecies(t.Context(), &oakclient.SpeciesRequest{
 		ScientificName: "alba",
 		IsHybrid:       false,
 	})
 
-	_, _ = c.CreateSource(&client.SourceRequest{
+	_, _ = c.CreateSource(t.Context(), &oakclient.SourceRequest{
 		SourceType: "Website",
 		Name:       "Test Source",
 	})
 
 	t.Run("Export", func(t *testing.T) {
-		exportData, err := c.Export()
+		exportData, err := c.Export(t.Context(), oakclient.ExportOptions{})
 		if err != nil {
 			t.Fatalf("Export failed: %v", err)
 		}
@@ -620,25 +621,25 @@ func TestEmbeddedAPI_SpeciesSources(t *testing.T) {
 		Key:    server.APIKey(),
 		Source: config.SourceEmbedded,
 	}
-	c, err := client.New(profile, client.WithSkipVersionCheck(true))
+	c, err := oakclient.New(profile.URL, oakclient.WithAPIKey(profile.Key), oakclient.WithProfileName(profile.Name), oakclient.WithSkipVersionCheck(true))
 	if err != nil {
 		t.Fatalf("failed to create client: %v", err)
 	}
 
 	// Setup: create species and source
-	_, _ = c.CreateSpecies(&client.SpeciesRequest{
+	_, _ = c.CreateSpecies(t.Context(), &oakclient.SpeciesRequest{
 		ScientificName: "alba",
 		IsHybrid:       false,
 	})
 
-	source, _ := c.CreateSource(&client.SourceRequest{
+	source, _ := c.CreateSource(t.Context(), &oakclient.SourceRequest{
 		SourceType: "Website",
 		Name:       "Test Source",
 	})
 
 	t.Run("CreateSpeciesSource", func(t *testing.T) {
 		leaves := "Large lobed leaves"
-		ss, err := c.CreateSpeciesSource("alba", &client.SpeciesSource{
+		ss, err := c.CreateSpeciesSource(t.Context(), "alba", &oakclient.SpeciesSource{
 			SourceID: source.ID,
 			Leaves:   &leaves,
 		})
@@ -654,7 +655,7 @@ func TestEmbeddedAPI_SpeciesSources(t *testing.T) {
 	})
 
 	t.Run("ListSpeciesSources", func(t *testing.T) {
-		sources, err := c.ListSpeciesSources("alba")
+		sources, err := c.ListSpeciesSources(t.Context(), "alba")
 		if err != nil {
 			t.Fatalf("ListSpeciesSources failed: %v", err)
 		}
@@ -664,7 +665,7 @@ func TestEmbeddedAPI_SpeciesSources(t *testing.T) {
 	})
 
 	t.Run("GetSpeciesWithSources", func(t *testing.T) {
-		entry, sources, err := c.GetSpeciesWithSources("alba")
+		entry, sources, err := c.GetSpeciesWithSources(t.Context(), "alba")
 		if err != nil {
 			t.Fatalf("GetSpeciesWithSources failed: %v", err)
 		}
@@ -677,6 +678,84 @@ func TestEmbeddedAPI_SpeciesSources(t *testing.T) {
 	})
 }
 
+// TestReplicaSync verifies that a synced replica serves the remote's
+// published data, keeping source IDs so species data still points at them.
+func TestReplicaSync(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	server, err := embed.Start(embed.Config{
+		DBPath: filepath.Join(tmpDir, "remote.db"),
+		Quiet:  true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start embedded server: %v", err)
+	}
+	defer server.Shutdown()
+
+	profile := &config.ResolvedProfile{Name: "prod", URL: server.URL(), Key: server.APIKey(), Source: config.SourceConfig}
+	c, err := oakclient.New(profile.URL, oakclient.WithAPIKey(profile.Key), oakclient.WithProfileName(profile.Name), oakclient.WithSkipVersionCheck(true))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// Delete the first source so the one in use has ID 2
+	first, _ := c.CreateSource(t.Context(), &oakclient.SourceRequest{SourceType: "Website", Name: "Gone"})
+	source, _ := c.CreateSource(t.Context(), &oakclient.SourceRequest{SourceType: "Website", Name: "Kept"})
+	if err := c.DeleteSource(t.Context(), first.ID, false); err != nil {
+		t.Fatalf("DeleteSource failed: %v", err)
+	}
+	_, _ = c.CreateSpecies(t.Context(), &oakclient.SpeciesRequest{ScientificName: "alba"})
+	_, _ = c.CreateSpecies(t.Context(), &oakclient.SpeciesRequest{ScientificName: "unfinished", Visibility: "draft"})
+	leaves := "Large lobed leaves"
+	if _, err := c.CreateSpeciesSource(t.Context(), "alba", &oakclient.SpeciesSource{SourceID: source.ID, Leaves: &leaves}); err != nil {
+		t.Fatalf("CreateSpeciesSource failed: %v", err)
+	}
+
+	replicaPath := filepath.Join(tmpDir, "replicas", "prod.db")
+	stats, err := replica.Sync(t.Context(), c, replicaPath, profile.Name, profile.URL)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if stats.Species != 1 || stats.Sources != 1 || stats.SpeciesSources != 1 {
+		t.Errorf("stats = %+v, want 1 species, 1 source, 1 source record", stats)
+	}
+
+	info, err := replica.Read(replicaPath)
+	if err != nil || info == nil {
+		t.Fatalf("Read = %v, %v", info, err)
+	}
+	if info.Profile != "prod" || info.URL != server.URL() || info.SyncedAt.IsZero() {
+		t.Errorf("info = %+v", info)
+	}
+
+	cached, err := embed.Start(embed.Config{DBPath: replicaPath, Quiet: true})
+	if err != nil {
+		t.Fatalf("failed to serve replica: %v", err)
+	}
+	defer cached.Shutdown()
+
+	rc, err := oakclient.New(cached.URL(), oakclient.WithAPIKey(cached.APIKey()), oakclient.WithProfileName("prod"),
+		oakclient.WithSkipVersionCheck(true))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, sources, err := rc.GetSpeciesWithSources(t.Context(), "alba")
+	if err != nil {
+		t.Fatalf("GetSpeciesWithSources failed: %v", err)
+	}
+	if len(sources) != 1 || sources[0].SourceID != source.ID || sources[0].Leaves == nil || *sources[0].Leaves != leaves {
+		t.Errorf("replica source data = %+v, want leaves from source %d", sources, source.ID)
+	}
+	if _, err := rc.GetSpecies(t.Context(), "unfinished"); !oakclient.IsNotFoundError(err) {
+		t.Errorf("draft species in replica: err = %v", err)
+	}
+
+	if info, err := replica.Read(filepath.Join(tmpDir, "missing.db")); err != nil || info != nil {
+		t.Errorf("Read(missing) = %v, %v; want nil, nil", info, err)
+	}
+}
+
 // sliceContains checks if a string slice contains a value.
 func sliceContains(slice []string, value string) bool {
 	for _, s := range slice {
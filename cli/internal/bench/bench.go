@@ -0,0 +1,251 @@
+// Package bench runs a read-only load test against an Oak Compendium API
+// and summarizes latency per endpoint.
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+// Options controls a benchmark run.
+type Options struct {
+	Concurrency int           // Number of concurrent workers
+	Duration    time.Duration // How long to generate load
+	Seed        int64         // Random seed for the request mix
+}
+
+// Operation is one kind of read request in the workload mix.
+type Operation struct {
+	Name   string
+	Weight int // Relative frequency in the mix
+	Run    func(ctx context.Context, c *oakclient.Client, rng *rand.Rand, names []string) error
+}
+
+// DefaultMix approximates real traffic: mostly species detail pages and
+// browsing, with some search and reference lookups.
+var DefaultMix = []Operation{
+	{Name: "GET /species/{name}", Weight: 35, Run: func(ctx context.Context, c *oakclient.Client, rng *rand.Rand, names []string) error {
+		_, err := c.GetSpecies(ctx, pickName(rng, names))
+		return err
+	}},
+	{Name: "GET /species/{name}/full", Weight: 20, Run: func(ctx context.Context, c *oakclient.Client, rng *rand.Rand, names []string) error {
+		_, _, err := c.GetSpeciesWithSources(ctx, pickName(rng, names))
+		return err
+	}},
+	{Name: "GET /species", Weight: 20, Run: func(ctx context.Context, c *oakclient.Client, rng *rand.Rand, names []string) error {
+		offset := 0
+		if len(names) > 0 {
+			offset = rng.Intn(len(names))
+		}
+		_, err := c.ListSpecies(ctx, &oakclient.SpeciesListParams{Limit: 50, Offset: offset})
+		return err
+	}},
+	{Name: "GET /search", Weight: 15, Run: func(ctx context.Context, c *oakclient.Client, rng *rand.Rand, names []string) error {
+		name := pickName(rng, names)
+		if len(name) > 3 {
+			name = name[:3]
+		}
+		_, err := c.SearchSpecies(ctx, name, 20)
+		return err
+	}},
+	{Name: "GET /taxa", Weight: 5, Run: func(ctx context.Context, c *oakclient.Client, _ *rand.Rand, _ []string) error {
+		_, err := c.ListTaxa(ctx, nil)
+		return err
+	}},
+	{Name: "GET /sources", Weight: 5, Run: func(ctx context.Context, c *oakclient.Client, _ *rand.Rand, _ []string) error {
+		_, err := c.ListSources(ctx, nil)
+		return err
+	}},
+}
+
+// EndpointResult summarizes the requests made to one operation.
+type EndpointResult struct {
+	Name        string
+	Requests    int
+	Errors      int // Failures other than rate limiting
+	RateLimited int
+	P50         time.Duration
+	P90         time.Duration
+	P99         time.Duration
+	Max         time.Duration
+}
+
+// Result is the outcome of a benchmark run.
+type Result struct {
+	Elapsed   time.Duration
+	Endpoints []EndpointResult
+	Total     EndpointResult
+}
+
+// RequestsPerSecond returns the overall throughput of the run.
+func (r *Result) RequestsPerSecond() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Total.Requests) / r.Elapsed.Seconds()
+}
+
+type sample struct {
+	op          int
+	latency     time.Duration
+	failed      bool
+	rateLimited bool
+}
+
+// Run generates load with the given operation mix until opts.Duration elapses
+// or ctx is canceled. The client should be created with retries disabled so
+// that rate limiting and errors are observed rather than retried away.
+func Run(ctx context.Context, c *oakclient.Client, mix []Operation, opts Options) (*Result, error) {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	if len(mix) == 0 {
+		return nil, fmt.Errorf("empty operation mix")
+	}
+
+	// Verify the server is reachable (and version-check once, before workers share the client)
+	if err := c.CheckCompatibility(ctx); err != nil {
+		return nil, err
+	}
+
+	// Sample real names so detail requests hit existing species
+	list, err := c.ListSpecies(ctx, &oakclient.SpeciesListParams{Limit: 500})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample species: %w", err)
+	}
+	names := make([]string, 0, len(list.Data))
+	for _, sp := range list.Data {
+		names = append(names, sp.ScientificName)
+	}
+
+	totalWeight := 0
+	for _, op := range mix {
+		totalWeight += op.Weight
+	}
+
+	// Requests in flight when the time is up finish; only new ones stop
+	reqCtx := ctx
+	ctx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	var mu sync.Mutex
+	var samples []sample
+	var wg sync.WaitGroup
+
+	start := time.Now()
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(opts.Seed + int64(worker))) //nolint:gosec // workload mix, not security sensitive
+			var local []sample
+			for ctx.Err() == nil {
+				i := pickOperation(rng, mix, totalWeight)
+				t0 := time.Now()
+				err := mix[i].Run(reqCtx, c, rng, names)
+				s := sample{op: i, latency: time.Since(t0)}
+				if err != nil {
+					if isRateLimited(err) {
+						s.rateLimited = true
+					} else if !oakclient.IsNotFoundError(err) {
+						s.failed = true
+					}
+				}
+				local = append(local, s)
+			}
+			mu.Lock()
+			samples = append(samples, local...)
+			mu.Unlock()
+		}(w)
+	}
+	wg.Wait()
+
+	return summarize(mix, samples, time.Since(start)), nil
+}
+
+func summarize(mix []Operation, samples []sample, elapsed time.Duration) *Result {
+	perOp := make([][]time.Duration, len(mix))
+	result := &Result{Elapsed: elapsed, Endpoints: make([]EndpointResult, len(mix))}
+	var all []time.Duration
+
+	for i, op := range mix {
+		result.Endpoints[i].Name = op.Name
+	}
+	for _, s := range samples {
+		ep := &result.Endpoints[s.op]
+		ep.Requests++
+		switch {
+		case s.rateLimited:
+			ep.RateLimited++
+		case s.failed:
+			ep.Errors++
+		}
+		perOp[s.op] = append(perOp[s.op], s.latency)
+		all = append(all, s.latency)
+	}
+
+	for i := range result.Endpoints {
+		fillPercentiles(&result.Endpoints[i], perOp[i])
+		result.Total.Requests += result.Endpoints[i].Requests
+		result.Total.Errors += result.Endpoints[i].Errors
+		result.Total.RateLimited += result.Endpoints[i].RateLimited
+	}
+	result.Total.Name = "total"
+	fillPercentiles(&result.Total, all)
+
+	return result
+}
+
+func fillPercentiles(r *EndpointResult, latencies []time.Duration) {
+	if len(latencies) == 0 {
+		return
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	r.P50 = percentile(latencies, 0.50)
+	r.P90 = percentile(latencies, 0.90)
+	r.P99 = percentile(latencies, 0.99)
+	r.Max = latencies[len(latencies)-1]
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(p*float64(len(sorted))+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+func pickOperation(rng *rand.Rand, mix []Operation, totalWeight int) int {
+	n := rng.Intn(totalWeight)
+	for i, op := range mix {
+		if n < op.Weight {
+			return i
+		}
+		n -= op.Weight
+	}
+	return len(mix) - 1
+}
+
+func pickName(rng *rand.Rand, names []string) string {
+	if len(names) == 0 {
+		return "alba"
+	}
+	return names[rng.Intn(len(names))]
+}
+
+func isRateLimited(err error) bool {
+	var apiErr *oakclient.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,93 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeff/oaks/cli/internal/config"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+func TestRun(t *testing.T) {
+	var calls int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/species":
+			json.NewEncoder(w).Encode(oakclient.SpeciesListResponse{
+				Data: []*oakclient.OakEntry{{ScientificName: "alba"}, {ScientificName: "rubra"}},
+			})
+		case "/api/v1/species/alba":
+			// Every third detail request is rate limited
+			if atomic.AddInt64(&calls, 1)%3 == 0 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			json.NewEncoder(w).Encode(oakclient.OakEntry{ScientificName: "alba"})
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	profile := &config.ResolvedProfile{Name: "test", URL: server.URL, Key: "k", Source: config.SourceFlag}
+	c, err := oakclient.New(profile.URL, oakclient.WithAPIKey(profile.Key), oakclient.WithProfileName(profile.Name), oakclient.WithSkipVersionCheck(true), oakclient.WithMaxRetries(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mix := []Operation{
+		{Name: "alba", Weight: 1, Run: func(ctx context.Context, c *oakclient.Client, _ *rand.Rand, _ []string) error {
+			_, err := c.GetSpecies(ctx, "alba")
+			return err
+		}},
+		{Name: "broken", Weight: 1, Run: func(ctx context.Context, c *oakclient.Client, _ *rand.Rand, _ []string) error {
+			_, err := c.GetSpecies(ctx, "broken")
+			return err
+		}},
+	}
+
+	result, err := Run(context.Background(), c, mix, Options{Concurrency: 2, Duration: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	alba, broken := result.Endpoints[0], result.Endpoints[1]
+	if alba.Requests == 0 || broken.Requests == 0 {
+		t.Fatalf("expected requests to both endpoints, got %+v", result.Endpoints)
+	}
+	if alba.RateLimited == 0 {
+		t.Error("expected rate-limited requests to be counted")
+	}
+	if alba.Errors != 0 {
+		t.Errorf("rate limiting counted as errors: %+v", alba)
+	}
+	if broken.Errors != broken.Requests {
+		t.Errorf("broken errors = %d, want %d", broken.Errors, broken.Requests)
+	}
+	if result.Total.Requests != alba.Requests+broken.Requests {
+		t.Errorf("total = %d, want %d", result.Total.Requests, alba.Requests+broken.Requests)
+	}
+	if result.Total.P50 > result.Total.P99 || result.Total.P99 > result.Total.Max {
+		t.Errorf("percentiles out of order: %+v", result.Total)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+	if got := percentile(sorted, 0.50); got != 50*time.Millisecond {
+		t.Errorf("p50 = %s", got)
+	}
+	if got := percentile(sorted, 0.99); got != 99*time.Millisecond {
+		t.Errorf("p99 = %s", got)
+	}
+}
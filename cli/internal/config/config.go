@@ -22,6 +22,10 @@ type Profile struct {
 type Config struct {
 	Profiles       map[string]Profile `yaml:"profiles"`
 	DefaultProfile string             `yaml:"default_profile"`
+
+	// Problems are what Lint found in the file. Profiles with errors
+	// cannot be resolved; warnings are for the caller to show.
+	Problems []Problem `yaml:"-"`
 }
 
 // ResolvedProfile contains the active profile after resolution.
@@ -29,7 +33,7 @@ type ResolvedProfile struct {
 	Name   string // Profile name (empty if local mode)
 	URL    string // API URL
 	Key    string // API key
-	Source string // Where the profile came from: "flag", "env", "config", "legacy-env", "local"
+	Source string // Where the profile came from: "flag", "env", "config", "legacy-env", "local", "embedded", "replica"
 }
 
 // IsLocal returns true if operating in local database mode.
@@ -45,6 +49,7 @@ const (
 	SourceLegacyEnv = "legacy-env"
 	SourceLocal     = "local"
 	SourceEmbedded  = "embedded"
+	SourceReplica   = "replica" // Embedded server reading a synced copy of an unreachable remote
 )
 
 // Environment variable names
@@ -52,6 +57,9 @@ const (
 	EnvProfile = "OAK_PROFILE"
 	EnvAPIURL  = "OAK_API_URL"
 	EnvAPIKey  = "OAK_API_KEY" //nolint:gosec // This is an env var name, not a credential
+
+	// EnvExportMappings is a directory of YAML export mappings for local mode
+	EnvExportMappings = "OAK_EXPORT_MAPPINGS"
 )
 
 // DefaultConfigPath returns the default configuration file path.
@@ -72,6 +80,25 @@ func DefaultAPIKeyPath() string {
 	return filepath.Join(home, ".oak", "api_key")
 }
 
+// DefaultReplicaPath returns where the synced copy of a profile's data is kept.
+func DefaultReplicaPath(profile string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".oak", "replicas", profile+".db")
+}
+
+// DefaultQueuePath returns where writes to remote profiles are queued until
+// the server confirms them.
+func DefaultQueuePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".oak", "queue.db")
+}
+
 // readAPIKeyFile reads the API key from ~/.oak/api_key if it exists.
 func readAPIKeyFile() string {
 	path := DefaultAPIKeyPath()
@@ -94,7 +121,7 @@ func countTrailingNewlines(data []byte) int {
 	return count
 }
 
-// Load reads the configuration from the specified path.
+// Load reads the configuration from the specified path, linting it.
 // Returns an empty config (not an error) if the file doesn't exist.
 func Load(path string) (*Config, error) {
 	if path == "" {
@@ -114,6 +141,7 @@ func Load(path string) (*Config, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
+	cfg.Problems = Lint(data)
 
 	if cfg.Profiles == nil {
 		cfg.Profiles = make(map[string]Profile)
@@ -145,6 +173,9 @@ func Resolve(cfg *Config, profileFlag string) (*ResolvedProfile, error) {
 		if !ok {
 			return nil, fmt.Errorf("profile %q not found in config", profileFlag)
 		}
+		if err := cfg.usable(profileFlag); err != nil {
+			return nil, err
+		}
 		key := profile.Key
 		if key == "" {
 			key = readAPIKeyFile() // Fallback to ~/.oak/api_key
@@ -163,6 +194,9 @@ func Resolve(cfg *Config, profileFlag string) (*ResolvedProfile, error) {
 		if !ok {
 			return nil, fmt.Errorf("profile %q (from %s) not found in config", envProfile, EnvProfile)
 		}
+		if err := cfg.usable(envProfile); err != nil {
+			return nil, err
+		}
 		key := profile.Key
 		if key == "" {
 			key = readAPIKeyFile() // Fallback to ~/.oak/api_key
@@ -181,6 +215,9 @@ func Resolve(cfg *Config, profileFlag string) (*ResolvedProfile, error) {
 		if !ok {
 			return nil, fmt.Errorf("default profile %q not found in config", cfg.DefaultProfile)
 		}
+		if err := cfg.usable(cfg.DefaultProfile); err != nil {
+			return nil, err
+		}
 		key := profile.Key
 		if key == "" {
 			key = readAPIKeyFile() // Fallback to ~/.oak/api_key
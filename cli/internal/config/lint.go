@@ -0,0 +1,330 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Known keys of the config file and of each profile
+var (
+	configKeys  = []string{"profiles", "default_profile"}
+	profileKeys = []string{"url", "key"}
+)
+
+// placeholderKeys are the example keys from the docs and 'oak config list'
+var placeholderKeys = map[string]bool{
+	"your-api-key":      true,
+	"your-api-key-here": true,
+	"api-key":           true,
+	"changeme":          true,
+	"xxx":               true,
+}
+
+// Problem is something wrong with the config file
+type Problem struct {
+	Line    int    // 1-based line in the file, or 0 if not tied to one
+	Field   string // Dotted path to the field, e.g. profiles.prod.url
+	Message string
+	Warning bool // Warnings leave the config usable
+
+	profile string // Profile the problem makes unusable, if any
+}
+
+// String formats the problem as severity: field: message
+func (p Problem) String() string {
+	s := "error: "
+	if p.Warning {
+		s = "warning: "
+	}
+	if p.Field != "" {
+		s += p.Field + ": "
+	}
+	return s + p.Message
+}
+
+// Lint checks config file data against the expected structure: known keys
+// only, a mapping of profiles each with an http(s) URL and a plausible key,
+// and a default profile that exists. Unknown keys and missing keys are
+// warnings; everything else is an error.
+func Lint(data []byte) []Problem {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []Problem{{Line: yamlErrorLine(err), Message: err.Error()}}
+	}
+	if len(doc.Content) == 0 {
+		return nil // An empty file is an empty config
+	}
+
+	l := &linter{}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		l.errorf(root, "", "the config must be a mapping of settings, got %s", nodeKind(root))
+		return l.problems
+	}
+
+	var profileNames map[string]bool
+	var defaultProfile *yaml.Node
+	for _, kv := range l.mapping(root, "", configKeys) {
+		switch kv.key {
+		case "profiles":
+			profileNames = l.profiles(kv.value)
+		case "default_profile":
+			if kv.value.Kind != yaml.ScalarNode {
+				l.errorf(kv.value, "default_profile", "must be a profile name, got %s", nodeKind(kv.value))
+			} else {
+				defaultProfile = kv.value
+			}
+		}
+	}
+
+	if defaultProfile != nil && defaultProfile.Value != "" && !profileNames[defaultProfile.Value] {
+		l.errorf(defaultProfile, "default_profile", "profile %q is not defined under profiles", defaultProfile.Value)
+	}
+	sort.SliceStable(l.problems, func(i, j int) bool { return l.problems[i].Line < l.problems[j].Line })
+	return l.problems
+}
+
+// linter collects the problems found walking a config document
+type linter struct {
+	problems []Problem
+}
+
+func (l *linter) add(n *yaml.Node, field, profile string, warning bool, format string, args ...interface{}) {
+	l.problems = append(l.problems, Problem{
+		Line:    n.Line,
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+		Warning: warning,
+		profile: profile,
+	})
+}
+
+func (l *linter) errorf(n *yaml.Node, field, format string, args ...interface{}) {
+	l.add(n, field, "", false, format, args...)
+}
+
+// keyValue is a key of a mapping node with its value
+type keyValue struct {
+	key   string
+	node  *yaml.Node
+	value *yaml.Node
+}
+
+// mapping returns the entries of a mapping node with known keys, reporting
+// duplicates and warning about unknown keys
+func (l *linter) mapping(n *yaml.Node, path string, known []string) []keyValue {
+	var entries []keyValue
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		field := joinField(path, k.Value)
+		if seen[k.Value] {
+			l.errorf(k, field, "duplicate key")
+			continue
+		}
+		seen[k.Value] = true
+		if known != nil && !contains(known, k.Value) {
+			msg := "unknown key, ignored"
+			if s := suggestKey(k.Value, known); s != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", s)
+			}
+			l.add(k, field, "", true, "%s", msg)
+			continue
+		}
+		entries = append(entries, keyValue{key: k.Value, node: k, value: v})
+	}
+	return entries
+}
+
+// profiles checks the profiles mapping and returns the profile names
+func (l *linter) profiles(n *yaml.Node) map[string]bool {
+	names := make(map[string]bool)
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return names
+	}
+	if n.Kind != yaml.MappingNode {
+		l.errorf(n, "profiles", "must be a mapping of profile names to profiles, got %s", nodeKind(n))
+		return names
+	}
+
+	for _, kv := range l.mapping(n, "profiles", nil) {
+		names[kv.key] = true
+		l.profile(kv.key, kv.node, kv.value)
+	}
+	return names
+}
+
+// profile checks one profile's url and key
+func (l *linter) profile(name string, keyNode, n *yaml.Node) {
+	path := joinField("profiles", name)
+	if strings.TrimSpace(name) == "" {
+		l.add(keyNode, path, name, false, "profile name is empty")
+	}
+	if n.Kind != yaml.MappingNode {
+		l.add(n, path, name, false, "must be a mapping with url and key, got %s", nodeKind(n))
+		return
+	}
+
+	var rawURL, key *yaml.Node
+	for _, kv := range l.mapping(n, path, profileKeys) {
+		field := joinField(path, kv.key)
+		if kv.value.Kind != yaml.ScalarNode {
+			l.add(kv.value, field, name, false, "must be a string, got %s", nodeKind(kv.value))
+			continue
+		}
+		switch kv.key {
+		case "url":
+			rawURL = kv.value
+		case "key":
+			key = kv.value
+		}
+	}
+
+	var u *url.URL
+	if rawURL == nil || rawURL.Value == "" {
+		l.add(n, joinField(path, "url"), name, false, "is required")
+	} else if u = checkURL(rawURL.Value); u == nil {
+		l.add(rawURL, joinField(path, "url"), name, false,
+			"%q is not an http or https URL with a host, like https://api.example.com", rawURL.Value)
+	}
+
+	field := joinField(path, "key")
+	switch {
+	case key == nil || key.Value == "":
+		if readAPIKeyFile() == "" {
+			l.add(n, field, "", true, "no key, and %s does not exist; writes will be refused", DefaultAPIKeyPath())
+		}
+	case strings.IndexFunc(key.Value, unicode.IsSpace) >= 0:
+		l.add(key, field, name, false, "contains whitespace, which keys never do; copy the key as issued")
+	case placeholderKeys[strings.ToLower(key.Value)] || strings.HasPrefix(key.Value, "<"):
+		l.add(key, field, name, false, "%q is the example placeholder, not a real key", key.Value)
+	case u != nil && u.Scheme == "http" && !isLoopback(u.Hostname()):
+		l.add(key, field, "", true, "the key is sent unencrypted to %s; use https", u.Host)
+	}
+}
+
+// checkURL parses a profile URL, returning nil unless it is http(s) with a host
+func checkURL(raw string) *url.URL {
+	if strings.TrimSpace(raw) != raw {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil
+	}
+	return u
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// suggestKey returns the known key closest to an unknown one, if it is
+// plausibly a typo of it
+func suggestKey(key string, known []string) string {
+	best, bestDist := "", 3
+	for _, k := range known {
+		if d := editDistance(strings.ToLower(key), k); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func joinField(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeKind names a YAML node's kind for messages
+func nodeKind(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	case yaml.AliasNode:
+		return "an alias"
+	}
+	if n.Tag == "!!null" {
+		return "nothing"
+	}
+	return fmt.Sprintf("%q", n.Value)
+}
+
+// yamlErrorLine extracts the line number from a YAML syntax error, or 0
+func yamlErrorLine(err error) int {
+	var line int
+	msg := err.Error()
+	if i := strings.Index(msg, "line "); i >= 0 {
+		fmt.Sscanf(msg[i:], "line %d", &line) //nolint:errcheck // 0 when there is none
+	}
+	return line
+}
+
+// usable reports why the named profile cannot be used, or nil
+func (c *Config) usable(name string) error {
+	var msgs []string
+	for _, p := range c.Problems {
+		if p.profile == name && !p.Warning {
+			msgs = append(msgs, fmt.Sprintf("%s (line %d)", strings.TrimPrefix(p.Field+": "+p.Message, "profiles."+name+"."), p.Line))
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("profile %q is invalid: %s; run 'oak config lint' for details", name, strings.Join(msgs, "; "))
+}
+
+// Warnings returns the problems that leave the config usable
+func (c *Config) Warnings() []Problem {
+	var warnings []Problem
+	for _, p := range c.Problems {
+		if p.Warning {
+			warnings = append(warnings, p)
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // No ~/.oak/api_key
+
+	data := `profiles:
+  prod:
+    ur: https://api.oakcompendium.com
+    key: your-api-key
+  staging:
+    url: staging.example.com
+    key: abc def
+  lan:
+    url: http://192.168.1.5:8080
+    key: lan-key-123456
+  local-server:
+    url: http://localhost:8080
+  good:
+    url: https://api.example.com
+    key: good-key-123456
+default_prfile: prod
+default_profile: missing
+`
+	var got []string
+	for _, p := range Lint([]byte(data)) {
+		severity := "E"
+		if p.Warning {
+			severity = "W"
+		}
+		got = append(got, fmt.Sprintf("%s %d %s", severity, p.Line, p.Field))
+	}
+	want := []string{
+		"W 3 profiles.prod.ur",
+		"E 3 profiles.prod.url",
+		"E 4 profiles.prod.key",
+		"E 6 profiles.staging.url",
+		"E 7 profiles.staging.key",
+		"W 10 profiles.lan.key",
+		"W 12 profiles.local-server.key",
+		"W 16 default_prfile",
+		"E 17 default_profile",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Lint() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if problems := Lint([]byte("profiles:\n  prod:\n    url: https://a.example.com\n    key: k1\n    key: k2\n")); len(problems) != 1 || problems[0].Field != "profiles.prod.key" || problems[0].Line != 5 {
+		t.Errorf("duplicate key problems = %+v", problems)
+	}
+	if problems := Lint([]byte("profiles: [prod]\n")); len(problems) != 1 || problems[0].Warning {
+		t.Errorf("list of profiles problems = %+v", problems)
+	}
+	if problems := Lint([]byte("profiles:\n  a: [1\n")); len(problems) != 1 || problems[0].Line == 0 {
+		t.Errorf("syntax error problems = %+v", problems)
+	}
+	if problems := Lint(nil); len(problems) != 0 {
+		t.Errorf("empty file problems = %+v", problems)
+	}
+	if problems := Lint([]byte("profiles:\n  p:\n    url: https://a.example.com\n    ky: k\n")); !strings.Contains(problems[len(problems)-1].Message, `did you mean "key"`) {
+		t.Error("no suggestion for a misspelled key")
+	}
+}
+
+func TestResolve_InvalidProfile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := "profiles:\n  prod:\n    url: api.example.com\n    key: k\n  ok:\n    url: https://api.example.com\n    key: k\n    extra: 1\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if _, err := Resolve(cfg, "prod"); err == nil || !strings.Contains(err.Error(), `profile "prod" is invalid: url:`) {
+		t.Errorf("Resolve(prod) error = %v, want the url problem", err)
+	}
+	// Unknown keys are warnings; the profile still resolves
+	if resolved, err := Resolve(cfg, "ok"); err != nil || resolved.URL != "https://api.example.com" {
+		t.Errorf("Resolve(ok) = %+v, %v", resolved, err)
+	}
+	if warnings := cfg.Warnings(); len(warnings) != 1 || warnings[0].Field != "profiles.ok.extra" {
+		t.Errorf("Warnings() = %+v", warnings)
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jeff/oaks/cli/internal/models"
+	"github.com/jeff/oaks/pkg/migrate"
 
 	_ "github.com/mattn/go-sqlite3" // SQLite driver
 )
@@ -45,6 +47,8 @@ func sliceRemove(slice []string, value string) []string {
 // Database wraps the SQLite connection
 type Database struct {
 	conn *sql.DB
+	q    querier // conn, or the transaction of a Store from WithTx
+	tx   *sql.Tx // The transaction of a Store from WithTx
 }
 
 // New creates a new database connection and initializes schema
@@ -54,7 +58,7 @@ func New(dbPath string) (*Database, error) {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	db := &Database{conn: conn}
+	db := &Database{conn: conn, q: conn}
 	if err := db.initializeSchema(); err != nil {
 		conn.Close()
 		return nil, err
@@ -73,17 +77,66 @@ func (db *Database) Ping() error {
 	return db.conn.Ping()
 }
 
+// legacyColumns are the columns added to the schema before it was versioned.
+// The CREATE TABLE statements include them; older databases get them here.
+var legacyColumns = []string{
+	`ALTER TABLE oak_entries ADD COLUMN external_links TEXT`,
+	`ALTER TABLE sources ADD COLUMN superseded_by INTEGER REFERENCES sources(id)`,
+	`ALTER TABLE oak_entries ADD COLUMN visibility TEXT NOT NULL DEFAULT 'published'`,
+	`ALTER TABLE oak_entries ADD COLUMN genus TEXT NOT NULL DEFAULT 'Quercus'`,
+	`ALTER TABLE taxa ADD COLUMN genus TEXT NOT NULL DEFAULT 'Quercus'`,
+	`ALTER TABLE oak_entries ADD COLUMN pronunciation TEXT`,
+	`ALTER TABLE species_sources ADD COLUMN distinguishing_features TEXT`,
+	`ALTER TABLE oak_entries ADD COLUMN needs_review INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE species_sources ADD COLUMN pages TEXT`,
+	`ALTER TABLE species_sources ADD COLUMN field_pages TEXT`,
+	`ALTER TABLE species_sources ADD COLUMN transcriber TEXT`,
+	`ALTER TABLE species_sources ADD COLUMN transcription_method TEXT`,
+	`ALTER TABLE species_sources ADD COLUMN ocr_confidence REAL`,
+	`ALTER TABLE species_sources ADD COLUMN verified_at TEXT`,
+	`ALTER TABLE species_sources ADD COLUMN verified_by TEXT`,
+}
+
 func (db *Database) initializeSchema() error {
+	// Refuse a schema migrated by a newer build before changing anything
+	version, err := migrate.New(db.conn, migrate.Schema).Check()
+	if err != nil {
+		return err
+	}
+
 	statements := []string{
+		// Genera tracked by the database; every entry and taxon belongs to one
+		`CREATE TABLE IF NOT EXISTS genera (
+			name TEXT PRIMARY KEY,
+			common_name TEXT,
+			subgenera TEXT
+		)`,
+		`INSERT OR IGNORE INTO genera (name, common_name, subgenera)
+			VALUES ('Quercus', 'Oaks', '["Quercus","Cerris","Cyclobalanopsis"]')`,
+
+		// Ordered taxonomic ranks below genus (shared with the API server)
+		`CREATE TABLE IF NOT EXISTS taxon_levels (
+			name TEXT PRIMARY KEY,
+			rank INTEGER NOT NULL UNIQUE,
+			plural TEXT,
+			entry_field TEXT
+		)`,
+		`INSERT OR IGNORE INTO taxon_levels (name, rank, plural, entry_field) VALUES
+			('subgenus', 1, 'subgenera', 'subgenus'),
+			('section', 2, 'sections', 'section'),
+			('subsection', 3, 'subsections', 'subsection'),
+			('complex', 4, 'complexes', 'complex')`,
+
 		// Taxa reference table for validation
-		// Hierarchy: Genus (Quercus) → Subgenus → Section → Subsection → Complex → Species
+		// Default hierarchy: Genus → Subgenus → Section → Subsection → Complex → Species
 		`CREATE TABLE IF NOT EXISTS taxa (
 			name TEXT NOT NULL,
-			level TEXT NOT NULL CHECK(level IN ('subgenus', 'section', 'subsection', 'complex')),
+			level TEXT NOT NULL,
 			parent TEXT,
 			author TEXT,
 			notes TEXT,
 			links TEXT,
+			genus TEXT NOT NULL DEFAULT 'Quercus',
 			PRIMARY KEY (name, level)
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_taxa_level ON taxa(level)`,
@@ -102,7 +155,8 @@ func (db *Database) initializeSchema() error {
 			doi TEXT,
 			notes TEXT,
 			license TEXT,
-			license_url TEXT
+			license_url TEXT,
+			superseded_by INTEGER REFERENCES sources(id)
 		)`,
 
 		// Oak entries with taxonomy and hybrid support
@@ -121,11 +175,16 @@ func (db *Database) initializeSchema() error {
 			closely_related_to TEXT,
 			subspecies_varieties TEXT,
 			synonyms TEXT,
-			external_links TEXT
+			external_links TEXT,
+			visibility TEXT NOT NULL DEFAULT 'published',
+			genus TEXT NOT NULL DEFAULT 'Quercus',
+			pronunciation TEXT,
+			needs_review INTEGER NOT NULL DEFAULT 0
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_oak_entries_subgenus ON oak_entries(subgenus)`,
 		`CREATE INDEX IF NOT EXISTS idx_oak_entries_section ON oak_entries(section)`,
 		`CREATE INDEX IF NOT EXISTS idx_oak_entries_hybrid ON oak_entries(is_hybrid)`,
+		`CREATE INDEX IF NOT EXISTS idx_oak_entries_conservation ON oak_entries(conservation_status)`,
 
 		// Species-source junction table for source-attributed descriptive data
 		// One row = everything source X says about species Y
@@ -144,8 +203,16 @@ func (db *Database) initializeSchema() error {
 			buds TEXT,
 			hardiness_habitat TEXT,
 			miscellaneous TEXT,
+			distinguishing_features TEXT,
 			url TEXT,
 			is_preferred INTEGER NOT NULL DEFAULT 0,
+			pages TEXT,
+			field_pages TEXT, -- JSON object of text field name to locator
+			transcriber TEXT,
+			transcription_method TEXT,
+			ocr_confidence REAL,
+			verified_at TEXT,
+			verified_by TEXT,
 			FOREIGN KEY (scientific_name) REFERENCES oak_entries(scientific_name) ON DELETE CASCADE,
 			FOREIGN KEY (source_id) REFERENCES sources(id),
 			UNIQUE(scientific_name, source_id)
@@ -166,24 +233,147 @@ func (db *Database) initializeSchema() error {
 		}
 	}
 
-	// Run migrations for new columns (ignore errors if column already exists)
-	migrations := []string{
-		`ALTER TABLE oak_entries ADD COLUMN external_links TEXT`,
+	// Databases created before schema versioning may lack columns added since
+	// (ignore errors if column already exists). Later changes are migrations,
+	// which only the API applies: they may touch tables this package doesn't create.
+	if version == 0 {
+		for _, stmt := range legacyColumns {
+			_, _ = db.conn.Exec(stmt) // Ignore error - column may already exist
+		}
+	}
+	if err := db.normalizeSourceTypes(); err != nil {
+		return err
+	}
+
+	if err := db.dropTaxaLevelCheck(); err != nil {
+		return err
+	}
+	for _, stmt := range taxaLevelTriggers {
+		if _, err := db.conn.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to execute schema statement: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// normalizeSourceTypes rewrites stored source types in their canonical form
+// ("Website" becomes website). Types that match none are left as they are.
+func (db *Database) normalizeSourceTypes() error {
+	rows, err := db.conn.Query(`SELECT DISTINCT source_type FROM sources`)
+	if err != nil {
+		return fmt.Errorf("failed to list source types: %w", err)
 	}
-	for _, stmt := range migrations {
-		_, _ = db.conn.Exec(stmt) // Ignore error - column may already exist
+	var types []string
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan source type: %w", err)
+		}
+		types = append(types, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
 	}
 
+	for _, t := range types {
+		if canonical, ok := models.NormalizeSourceType(t); ok && canonical != t {
+			if _, err := db.conn.Exec(`UPDATE sources SET source_type = ? WHERE source_type = ?`, canonical, t); err != nil {
+				return fmt.Errorf("failed to normalize source type %q: %w", t, err)
+			}
+		}
+	}
 	return nil
 }
 
+// taxaLevelTriggers restrict taxa.level to the names in taxon_levels.
+var taxaLevelTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS trg_taxa_level_insert
+		BEFORE INSERT ON taxa
+		WHEN NOT EXISTS (SELECT 1 FROM taxon_levels WHERE name = NEW.level)
+		BEGIN SELECT RAISE(ABORT, 'unknown taxon level'); END`,
+	`CREATE TRIGGER IF NOT EXISTS trg_taxa_level_update
+		BEFORE UPDATE OF level ON taxa
+		WHEN NOT EXISTS (SELECT 1 FROM taxon_levels WHERE name = NEW.level)
+		BEGIN SELECT RAISE(ABORT, 'unknown taxon level'); END`,
+}
+
+// dropTaxaLevelCheck rebuilds a taxa table created with the original
+// CHECK(level IN (...)) constraint so that levels come from taxon_levels.
+func (db *Database) dropTaxaLevelCheck() error {
+	var tableSQL string
+	err := db.conn.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'taxa'`).Scan(&tableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to read taxa schema: %w", err)
+	}
+	if !strings.Contains(tableSQL, "CHECK(level IN") {
+		return nil
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmts := []string{
+		`CREATE TABLE taxa_new (
+			name TEXT NOT NULL,
+			level TEXT NOT NULL,
+			parent TEXT,
+			author TEXT,
+			notes TEXT,
+			links TEXT,
+			genus TEXT NOT NULL DEFAULT 'Quercus',
+			PRIMARY KEY (name, level)
+		)`,
+		`INSERT INTO taxa_new (name, level, parent, author, notes, links, genus)
+			SELECT name, level, parent, author, notes, links, genus FROM taxa`,
+		`DROP TABLE taxa`,
+		`ALTER TABLE taxa_new RENAME TO taxa`,
+		`CREATE INDEX IF NOT EXISTS idx_taxa_level ON taxa(level)`,
+		`CREATE INDEX IF NOT EXISTS idx_taxa_parent ON taxa(parent)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to rebuild taxa table: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListTaxonLevels returns the configured taxon levels ordered by rank
+func (db *Database) ListTaxonLevels() ([]*models.TaxonLevelDef, error) {
+	rows, err := db.q.Query(
+		`SELECT l.name, l.rank, l.plural, l.entry_field,
+		        (SELECT COUNT(*) FROM taxa t WHERE t.level = l.name) as taxa_count
+		 FROM taxon_levels l ORDER BY l.rank`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list taxon levels: %w", err)
+	}
+	defer rows.Close()
+
+	var levels []*models.TaxonLevelDef
+	for rows.Next() {
+		var l models.TaxonLevelDef
+		if err := rows.Scan(&l.Name, &l.Rank, &l.Plural, &l.EntryField, &l.TaxaCount); err != nil {
+			return nil, fmt.Errorf("failed to scan taxon level: %w", err)
+		}
+		levels = append(levels, &l)
+	}
+	return levels, rows.Err()
+}
+
 // InsertSource inserts a new source and returns its ID
 func (db *Database) InsertSource(source *models.Source) (int64, error) {
-	result, err := db.conn.Exec(
-		`INSERT INTO sources (source_type, name, description, author, year, url, isbn, doi, notes, license, license_url)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+	result, err := db.q.Exec(
+		`INSERT INTO sources (source_type, name, description, author, year, url, isbn, doi, notes, license, license_url, superseded_by)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		source.SourceType, source.Name, source.Description,
-		source.Author, source.Year, source.URL, source.ISBN, source.DOI, source.Notes, source.License, source.LicenseURL,
+		source.Author, source.Year, source.URL, source.ISBN, source.DOI, source.Notes, source.License, source.LicenseURL, source.SupersededBy,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("failed to insert source: %w", err)
@@ -198,14 +388,14 @@ func (db *Database) InsertSource(source *models.Source) (int64, error) {
 
 // GetSource gets a source by ID
 func (db *Database) GetSource(id int64) (*models.Source, error) {
-	row := db.conn.QueryRow(
-		`SELECT id, source_type, name, description, author, year, url, isbn, doi, notes, license, license_url
+	row := db.q.QueryRow(
+		`SELECT id, source_type, name, description, author, year, url, isbn, doi, notes, license, license_url, superseded_by
 		 FROM sources WHERE id = ?`,
 		id,
 	)
 
 	var s models.Source
-	err := row.Scan(&s.ID, &s.SourceType, &s.Name, &s.Description, &s.Author, &s.Year, &s.URL, &s.ISBN, &s.DOI, &s.Notes, &s.License, &s.LicenseURL)
+	err := row.Scan(&s.ID, &s.SourceType, &s.Name, &s.Description, &s.Author, &s.Year, &s.URL, &s.ISBN, &s.DOI, &s.Notes, &s.License, &s.LicenseURL, &s.SupersededBy)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -217,12 +407,12 @@ func (db *Database) GetSource(id int64) (*models.Source, error) {
 
 // UpdateSource updates an existing source
 func (db *Database) UpdateSource(source *models.Source) error {
-	_, err := db.conn.Exec(
+	_, err := db.q.Exec(
 		`UPDATE sources
-		 SET source_type = ?, name = ?, description = ?, author = ?, year = ?, url = ?, isbn = ?, doi = ?, notes = ?, license = ?, license_url = ?
+		 SET source_type = ?, name = ?, description = ?, author = ?, year = ?, url = ?, isbn = ?, doi = ?, notes = ?, license = ?, license_url = ?, superseded_by = ?
 		 WHERE id = ?`,
 		source.SourceType, source.Name, source.Description, source.Author, source.Year,
-		source.URL, source.ISBN, source.DOI, source.Notes, source.License, source.LicenseURL, source.ID,
+		source.URL, source.ISBN, source.DOI, source.Notes, source.License, source.LicenseURL, source.SupersededBy, source.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update source: %w", err)
@@ -232,7 +422,7 @@ func (db *Database) UpdateSource(source *models.Source) error {
 
 // DeleteSource deletes a source by ID
 func (db *Database) DeleteSource(id int64) error {
-	result, err := db.conn.Exec(`DELETE FROM sources WHERE id = ?`, id)
+	result, err := db.q.Exec(`DELETE FROM sources WHERE id = ?`, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete source: %w", err)
 	}
@@ -258,9 +448,14 @@ func (db *Database) InsertTaxon(taxon *models.Taxon) error {
 		linksJSON = &s
 	}
 
-	_, err := db.conn.Exec(
-		`INSERT INTO taxa (name, level, parent, author, notes, links) VALUES (?, ?, ?, ?, ?, ?)`,
-		taxon.Name, string(taxon.Level), taxon.Parent, taxon.Author, taxon.Notes, linksJSON,
+	genus := taxon.Genus
+	if genus == "" {
+		genus = models.DefaultGenus
+	}
+
+	_, err := db.q.Exec(
+		`INSERT INTO taxa (name, level, parent, author, notes, links, genus) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		taxon.Name, string(taxon.Level), taxon.Parent, taxon.Author, taxon.Notes, linksJSON, genus,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert taxon: %w", err)
@@ -280,7 +475,7 @@ func (db *Database) UpdateTaxon(taxon *models.Taxon) error {
 		linksJSON = &s
 	}
 
-	_, err := db.conn.Exec(
+	_, err := db.q.Exec(
 		`UPDATE taxa SET parent = ?, author = ?, notes = ?, links = ? WHERE name = ? AND level = ?`,
 		taxon.Parent, taxon.Author, taxon.Notes, linksJSON, taxon.Name, string(taxon.Level),
 	)
@@ -292,7 +487,7 @@ func (db *Database) UpdateTaxon(taxon *models.Taxon) error {
 
 // GetTaxon gets a taxon by name and level
 func (db *Database) GetTaxon(name string, level models.TaxonLevel) (*models.Taxon, error) {
-	row := db.conn.QueryRow(
+	row := db.q.QueryRow(
 		`SELECT name, level, parent, author, notes, links FROM taxa WHERE name = ? AND level = ?`,
 		name, string(level),
 	)
@@ -324,7 +519,7 @@ func (db *Database) GetTaxon(name string, level models.TaxonLevel) (*models.Taxo
 // ValidateTaxon checks if a taxon exists in the reference table
 func (db *Database) ValidateTaxon(name string, level models.TaxonLevel) (bool, error) {
 	var count int
-	err := db.conn.QueryRow(
+	err := db.q.QueryRow(
 		`SELECT COUNT(*) FROM taxa WHERE name = ? AND level = ?`,
 		name, string(level),
 	).Scan(&count)
@@ -336,7 +531,7 @@ func (db *Database) ValidateTaxon(name string, level models.TaxonLevel) (bool, e
 
 // ClearTaxa removes all taxa from the reference table
 func (db *Database) ClearTaxa() error {
-	_, err := db.conn.Exec(`DELETE FROM taxa`)
+	_, err := db.q.Exec(`DELETE FROM taxa`)
 	if err != nil {
 		return fmt.Errorf("failed to clear taxa: %w", err)
 	}
@@ -345,7 +540,7 @@ func (db *Database) ClearTaxa() error {
 
 // DeleteTaxon deletes a taxon by name and level
 func (db *Database) DeleteTaxon(name string, level models.TaxonLevel) error {
-	result, err := db.conn.Exec(
+	result, err := db.q.Exec(
 		`DELETE FROM taxa WHERE name = ? AND level = ?`,
 		name, string(level),
 	)
@@ -365,7 +560,7 @@ func (db *Database) DeleteTaxon(name string, level models.TaxonLevel) error {
 // SearchTaxa searches taxa by name pattern (case-insensitive)
 func (db *Database) SearchTaxa(query string) ([]*models.Taxon, error) {
 	pattern := "%" + escapeLike(query) + "%"
-	rows, err := db.conn.Query(
+	rows, err := db.q.Query(
 		`SELECT name, level, parent, author, notes, links FROM taxa
 		 WHERE name LIKE ? ESCAPE '\' ORDER BY level, name`,
 		pattern,
@@ -404,7 +599,7 @@ func (db *Database) SearchTaxa(query string) ([]*models.Taxon, error) {
 // when a hybrid's parents are set/changed, the parents' hybrids lists are updated.
 func (db *Database) SaveOakEntry(entry *models.OakEntry) error {
 	// Start transaction for atomic updates
-	tx, err := db.conn.Begin()
+	tx, err := db.begin()
 	if err != nil {
 		return fmt.Errorf("failed to start transaction: %w", err)
 	}
@@ -463,11 +658,11 @@ func (db *Database) SaveOakEntry(entry *models.OakEntry) error {
 }
 
 // getOakEntryTx gets an oak entry within a transaction
-func (db *Database) getOakEntryTx(tx *sql.Tx, scientificName string) (*models.OakEntry, error) {
+func (db *Database) getOakEntryTx(tx querier, scientificName string) (*models.OakEntry, error) {
 	row := tx.QueryRow(
 		`SELECT scientific_name, author, is_hybrid, conservation_status,
 		        subgenus, section, subsection, complex,
-		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links
+		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links, pronunciation
 		 FROM oak_entries WHERE scientific_name = ?`,
 		scientificName,
 	)
@@ -479,7 +674,7 @@ func (db *Database) getOakEntryTx(tx *sql.Tx, scientificName string) (*models.Oa
 	if err := row.Scan(
 		&entry.ScientificName, &entry.Author, &isHybrid, &entry.ConservationStatus,
 		&entry.Subgenus, &entry.Section, &entry.Subsection, &entry.Complex,
-		&entry.Parent1, &entry.Parent2, &hybridsJSON, &relatedJSON, &subspeciesJSON, &synonymsJSON, &externalLinksJSON,
+		&entry.Parent1, &entry.Parent2, &hybridsJSON, &relatedJSON, &subspeciesJSON, &synonymsJSON, &externalLinksJSON, &entry.Pronunciation,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -539,7 +734,7 @@ func (db *Database) getOakEntryTx(tx *sql.Tx, scientificName string) (*models.Oa
 }
 
 // removeHybridFromParentTx removes a hybrid from a parent's hybrids list within a transaction
-func (db *Database) removeHybridFromParentTx(tx *sql.Tx, parentName, hybridName string) error {
+func (db *Database) removeHybridFromParentTx(tx querier, parentName, hybridName string) error {
 	// Get parent's current hybrids list
 	var hybridsJSON sql.NullString
 	err := tx.QueryRow(
@@ -578,7 +773,7 @@ func (db *Database) removeHybridFromParentTx(tx *sql.Tx, parentName, hybridName
 }
 
 // addHybridToParentTx adds a hybrid to a parent's hybrids list within a transaction
-func (db *Database) addHybridToParentTx(tx *sql.Tx, parentName, hybridName string) error {
+func (db *Database) addHybridToParentTx(tx querier, parentName, hybridName string) error {
 	// Get parent's current hybrids list
 	var hybridsJSON sql.NullString
 	err := tx.QueryRow(
@@ -619,7 +814,7 @@ func (db *Database) addHybridToParentTx(tx *sql.Tx, parentName, hybridName strin
 }
 
 // saveOakEntryTx saves an oak entry within a transaction
-func (db *Database) saveOakEntryTx(tx *sql.Tx, entry *models.OakEntry) error {
+func (db *Database) saveOakEntryTx(tx querier, entry *models.OakEntry) error {
 	// Marshal JSON arrays
 	synonymsJSON, err := json.Marshal(entry.Synonyms)
 	if err != nil {
@@ -652,12 +847,18 @@ func (db *Database) saveOakEntryTx(tx *sql.Tx, entry *models.OakEntry) error {
 		`INSERT OR REPLACE INTO oak_entries (
 			scientific_name, author, is_hybrid, conservation_status,
 			subgenus, section, subsection, complex,
-			parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links, visibility, genus, pronunciation
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?,
+			COALESCE(NULLIF(?, ''), (SELECT visibility FROM oak_entries WHERE scientific_name = ?), 'published'),
+			COALESCE(NULLIF(?, ''), (SELECT genus FROM oak_entries WHERE scientific_name = ?), 'Quercus'),
+			?)`,
 		entry.ScientificName, entry.Author, isHybrid, entry.ConservationStatus,
 		entry.Subgenus, entry.Section, entry.Subsection, entry.Complex,
 		entry.Parent1, entry.Parent2, string(hybridsJSON), string(relatedJSON),
 		string(subspeciesJSON), string(synonymsJSON), string(externalLinksJSON),
+		entry.Visibility, entry.ScientificName, // An empty visibility keeps the stored one
+		entry.Genus, entry.ScientificName, // Likewise an empty genus
+		entry.Pronunciation,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert oak entry: %w", err)
@@ -668,10 +869,10 @@ func (db *Database) saveOakEntryTx(tx *sql.Tx, entry *models.OakEntry) error {
 
 // GetOakEntry gets an oak entry by scientific name
 func (db *Database) GetOakEntry(scientificName string) (*models.OakEntry, error) {
-	row := db.conn.QueryRow(
+	row := db.q.QueryRow(
 		`SELECT scientific_name, author, is_hybrid, conservation_status,
 		        subgenus, section, subsection, complex,
-		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links
+		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links, pronunciation
 		 FROM oak_entries WHERE scientific_name = ?`,
 		scientificName,
 	)
@@ -683,7 +884,7 @@ func (db *Database) GetOakEntry(scientificName string) (*models.OakEntry, error)
 	if err := row.Scan(
 		&entry.ScientificName, &entry.Author, &isHybrid, &entry.ConservationStatus,
 		&entry.Subgenus, &entry.Section, &entry.Subsection, &entry.Complex,
-		&entry.Parent1, &entry.Parent2, &hybridsJSON, &relatedJSON, &subspeciesJSON, &synonymsJSON, &externalLinksJSON,
+		&entry.Parent1, &entry.Parent2, &hybridsJSON, &relatedJSON, &subspeciesJSON, &synonymsJSON, &externalLinksJSON, &entry.Pronunciation,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -744,7 +945,7 @@ func (db *Database) GetOakEntry(scientificName string) (*models.OakEntry, error)
 
 // DeleteOakEntry deletes an oak entry
 func (db *Database) DeleteOakEntry(scientificName string) error {
-	_, err := db.conn.Exec(
+	_, err := db.q.Exec(
 		`DELETE FROM oak_entries WHERE scientific_name = ?`,
 		scientificName,
 	)
@@ -765,7 +966,7 @@ type OakEntryFilter struct {
 func (db *Database) ListOakEntriesPaginated(limit, offset int, filter *OakEntryFilter) ([]*models.OakEntry, error) {
 	query := `SELECT scientific_name, author, is_hybrid, conservation_status,
 		        subgenus, section, subsection, complex,
-		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links
+		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links, pronunciation
 		 FROM oak_entries`
 
 	var args []interface{}
@@ -797,7 +998,7 @@ func (db *Database) ListOakEntriesPaginated(limit, offset int, filter *OakEntryF
 	query += " ORDER BY scientific_name LIMIT ? OFFSET ?"
 	args = append(args, limit, offset)
 
-	rows, err := db.conn.Query(query, args...)
+	rows, err := db.q.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list oak entries: %w", err)
 	}
@@ -837,7 +1038,7 @@ func (db *Database) CountOakEntries(filter *OakEntryFilter) (int, error) {
 	}
 
 	var count int
-	if err := db.conn.QueryRow(query, args...).Scan(&count); err != nil {
+	if err := db.q.QueryRow(query, args...).Scan(&count); err != nil {
 		return 0, fmt.Errorf("failed to count oak entries: %w", err)
 	}
 	return count, nil
@@ -846,10 +1047,10 @@ func (db *Database) CountOakEntries(filter *OakEntryFilter) (int, error) {
 // SearchOakEntriesFull searches for oak entries by name pattern and returns full entries
 func (db *Database) SearchOakEntriesFull(query string, limit int) ([]*models.OakEntry, error) {
 	pattern := "%" + escapeLike(query) + "%"
-	rows, err := db.conn.Query(
+	rows, err := db.q.Query(
 		`SELECT scientific_name, author, is_hybrid, conservation_status,
 		        subgenus, section, subsection, complex,
-		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links
+		        parent1, parent2, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links, pronunciation
 		 FROM oak_entries
 		 WHERE scientific_name LIKE ? ESCAPE '\'
 		 ORDER BY scientific_name LIMIT ?`,
@@ -866,7 +1067,7 @@ func (db *Database) SearchOakEntriesFull(query string, limit int) ([]*models.Oak
 // OakEntryExists checks if an oak entry exists by scientific name
 func (db *Database) OakEntryExists(scientificName string) (bool, error) {
 	var count int
-	err := db.conn.QueryRow(
+	err := db.q.QueryRow(
 		`SELECT COUNT(*) FROM oak_entries WHERE scientific_name = ?`,
 		scientificName,
 	).Scan(&count)
@@ -876,6 +1077,46 @@ func (db *Database) OakEntryExists(scientificName string) (bool, error) {
 	return count > 0, nil
 }
 
+// CreatePlaceholderEntry creates a minimal draft entry for a name an import
+// refers to but does not describe, flagged needs_review. Saving the entry in
+// full clears the flag. Returns false if the name already exists.
+func (db *Database) CreatePlaceholderEntry(scientificName string) (bool, error) {
+	result, err := db.q.Exec(
+		`INSERT OR IGNORE INTO oak_entries (
+			scientific_name, hybrids, closely_related_to, subspecies_varieties, synonyms, external_links,
+			visibility, needs_review
+		) VALUES (?, '[]', '[]', '[]', '[]', '[]', ?, 1)`,
+		scientificName, models.VisibilityDraft,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create placeholder entry: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to create placeholder entry: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ListNeedsReview returns the names of placeholder entries not yet filled in
+func (db *Database) ListNeedsReview() ([]string, error) {
+	rows, err := db.q.Query(`SELECT scientific_name FROM oak_entries WHERE needs_review = 1 ORDER BY scientific_name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entries needing review: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan entry name: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
 // scanOakEntries is a helper that scans rows into OakEntry objects
 func scanOakEntries(rows *sql.Rows) ([]*models.OakEntry, error) {
 	var entries []*models.OakEntry
@@ -887,7 +1128,7 @@ func scanOakEntries(rows *sql.Rows) ([]*models.OakEntry, error) {
 		if err := rows.Scan(
 			&entry.ScientificName, &entry.Author, &isHybrid, &entry.ConservationStatus,
 			&entry.Subgenus, &entry.Section, &entry.Subsection, &entry.Complex,
-			&entry.Parent1, &entry.Parent2, &hybridsJSON, &relatedJSON, &subspeciesJSON, &synonymsJSON, &externalLinksJSON,
+			&entry.Parent1, &entry.Parent2, &hybridsJSON, &relatedJSON, &subspeciesJSON, &synonymsJSON, &externalLinksJSON, &entry.Pronunciation,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan oak entry: %w", err)
 		}
@@ -957,21 +1198,51 @@ func (db *Database) SaveSpeciesSource(ss *models.SpeciesSource) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal local_names: %w", err)
 	}
+	var verifiedAt *string
+	if ss.VerifiedAt != nil {
+		ts := ss.VerifiedAt.UTC().Format(time.RFC3339)
+		verifiedAt = &ts
+	}
+	var fieldPagesJSON *string
+	if len(ss.FieldPages) > 0 {
+		data, err := json.Marshal(ss.FieldPages)
+		if err != nil {
+			return fmt.Errorf("failed to marshal field_pages: %w", err)
+		}
+		fieldPagesJSON = new(string)
+		*fieldPagesJSON = string(data)
+	}
+
+	tx, err := db.begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
 
 	isPreferred := 0
 	if ss.IsPreferred {
 		isPreferred = 1
+		// Only one source may be preferred per species
+		if _, err := tx.Exec(
+			`UPDATE species_sources SET is_preferred = 0
+			 WHERE scientific_name = ? AND source_id != ? AND is_preferred = 1`,
+			ss.ScientificName, ss.SourceID,
+		); err != nil {
+			return fmt.Errorf("failed to clear preferred source: %w", err)
+		}
 	}
 
-	result, err := db.conn.Exec(
+	result, err := tx.Exec(
 		`INSERT OR REPLACE INTO species_sources (
 			scientific_name, source_id, local_names, range, growth_habit,
 			leaves, flowers, fruits, bark, twigs, buds, hardiness_habitat,
-			miscellaneous, url, is_preferred
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			miscellaneous, distinguishing_features, url, is_preferred, pages, field_pages,
+			transcriber, transcription_method, ocr_confidence, verified_at, verified_by
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		ss.ScientificName, ss.SourceID, string(localNamesJSON), ss.Range, ss.GrowthHabit,
 		ss.Leaves, ss.Flowers, ss.Fruits, ss.Bark, ss.Twigs, ss.Buds, ss.HardinessHabitat,
-		ss.Miscellaneous, ss.URL, isPreferred,
+		ss.Miscellaneous, ss.DistinguishingFeatures, ss.URL, isPreferred, ss.Pages, fieldPagesJSON,
+		ss.Transcriber, ss.TranscriptionMethod, ss.OCRConfidence, verifiedAt, ss.VerifiedBy,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to save species source: %w", err)
@@ -984,15 +1255,16 @@ func (db *Database) SaveSpeciesSource(ss *models.SpeciesSource) error {
 		}
 		ss.ID = id
 	}
-	return nil
+	return tx.Commit()
 }
 
 // GetSpeciesSources returns all source data for a species
 func (db *Database) GetSpeciesSources(scientificName string) ([]*models.SpeciesSource, error) {
-	rows, err := db.conn.Query(
+	rows, err := db.q.Query(
 		`SELECT id, scientific_name, source_id, local_names, range, growth_habit,
 		        leaves, flowers, fruits, bark, twigs, buds, hardiness_habitat,
-		        miscellaneous, url, is_preferred
+		        miscellaneous, distinguishing_features, url, is_preferred, pages, field_pages,
+		        transcriber, transcription_method, ocr_confidence, verified_at, verified_by
 		 FROM species_sources WHERE scientific_name = ? ORDER BY is_preferred DESC, source_id`,
 		scientificName,
 	)
@@ -1014,22 +1286,24 @@ func (db *Database) GetSpeciesSources(scientificName string) ([]*models.SpeciesS
 
 // GetSpeciesSourceBySourceID returns source data for a specific species+source combination
 func (db *Database) GetSpeciesSourceBySourceID(scientificName string, sourceID int64) (*models.SpeciesSource, error) {
-	row := db.conn.QueryRow(
+	row := db.q.QueryRow(
 		`SELECT id, scientific_name, source_id, local_names, range, growth_habit,
 		        leaves, flowers, fruits, bark, twigs, buds, hardiness_habitat,
-		        miscellaneous, url, is_preferred
+		        miscellaneous, distinguishing_features, url, is_preferred, pages, field_pages,
+		        transcriber, transcription_method, ocr_confidence, verified_at, verified_by
 		 FROM species_sources WHERE scientific_name = ? AND source_id = ?`,
 		scientificName, sourceID,
 	)
 
 	ss := &models.SpeciesSource{}
-	var localNamesJSON sql.NullString
+	var localNamesJSON, fieldPagesJSON, verifiedAt sql.NullString
 	var isPreferred int
 
 	err := row.Scan(
 		&ss.ID, &ss.ScientificName, &ss.SourceID, &localNamesJSON, &ss.Range, &ss.GrowthHabit,
 		&ss.Leaves, &ss.Flowers, &ss.Fruits, &ss.Bark, &ss.Twigs, &ss.Buds, &ss.HardinessHabitat,
-		&ss.Miscellaneous, &ss.URL, &isPreferred,
+		&ss.Miscellaneous, &ss.DistinguishingFeatures, &ss.URL, &isPreferred, &ss.Pages, &fieldPagesJSON,
+		&ss.Transcriber, &ss.TranscriptionMethod, &ss.OCRConfidence, &verifiedAt, &ss.VerifiedBy,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -1047,28 +1321,40 @@ func (db *Database) GetSpeciesSourceBySourceID(scientificName string, sourceID i
 	if ss.LocalNames == nil {
 		ss.LocalNames = []string{}
 	}
+	if ss.FieldPages, err = unmarshalFieldPages(fieldPagesJSON, ss.ScientificName); err != nil {
+		return nil, err
+	}
+	if verifiedAt.Valid {
+		t, err := time.Parse(time.RFC3339, verifiedAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse verified_at for %s: %w", ss.ScientificName, err)
+		}
+		ss.VerifiedAt, ss.Verified = &t, true
+	}
 
 	return ss, nil
 }
 
 // GetPreferredSpeciesSource returns the preferred source data for a species
 func (db *Database) GetPreferredSpeciesSource(scientificName string) (*models.SpeciesSource, error) {
-	row := db.conn.QueryRow(
+	row := db.q.QueryRow(
 		`SELECT id, scientific_name, source_id, local_names, range, growth_habit,
 		        leaves, flowers, fruits, bark, twigs, buds, hardiness_habitat,
-		        miscellaneous, url, is_preferred
+		        miscellaneous, distinguishing_features, url, is_preferred, pages, field_pages,
+		        transcriber, transcription_method, ocr_confidence, verified_at, verified_by
 		 FROM species_sources WHERE scientific_name = ? ORDER BY is_preferred DESC LIMIT 1`,
 		scientificName,
 	)
 
 	ss := &models.SpeciesSource{}
-	var localNamesJSON sql.NullString
+	var localNamesJSON, fieldPagesJSON, verifiedAt sql.NullString
 	var isPreferred int
 
 	err := row.Scan(
 		&ss.ID, &ss.ScientificName, &ss.SourceID, &localNamesJSON, &ss.Range, &ss.GrowthHabit,
 		&ss.Leaves, &ss.Flowers, &ss.Fruits, &ss.Bark, &ss.Twigs, &ss.Buds, &ss.HardinessHabitat,
-		&ss.Miscellaneous, &ss.URL, &isPreferred,
+		&ss.Miscellaneous, &ss.DistinguishingFeatures, &ss.URL, &isPreferred, &ss.Pages, &fieldPagesJSON,
+		&ss.Transcriber, &ss.TranscriptionMethod, &ss.OCRConfidence, &verifiedAt, &ss.VerifiedBy,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -1086,6 +1372,16 @@ func (db *Database) GetPreferredSpeciesSource(scientificName string) (*models.Sp
 	if ss.LocalNames == nil {
 		ss.LocalNames = []string{}
 	}
+	if ss.FieldPages, err = unmarshalFieldPages(fieldPagesJSON, ss.ScientificName); err != nil {
+		return nil, err
+	}
+	if verifiedAt.Valid {
+		t, err := time.Parse(time.RFC3339, verifiedAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse verified_at for %s: %w", ss.ScientificName, err)
+		}
+		ss.VerifiedAt, ss.Verified = &t, true
+	}
 
 	return ss, nil
 }
@@ -1093,13 +1389,14 @@ func (db *Database) GetPreferredSpeciesSource(scientificName string) (*models.Sp
 // scanSpeciesSource scans a row into a SpeciesSource
 func scanSpeciesSource(rows *sql.Rows) (*models.SpeciesSource, error) {
 	ss := &models.SpeciesSource{}
-	var localNamesJSON sql.NullString
+	var localNamesJSON, fieldPagesJSON, verifiedAt sql.NullString
 	var isPreferred int
 
 	err := rows.Scan(
 		&ss.ID, &ss.ScientificName, &ss.SourceID, &localNamesJSON, &ss.Range, &ss.GrowthHabit,
 		&ss.Leaves, &ss.Flowers, &ss.Fruits, &ss.Bark, &ss.Twigs, &ss.Buds, &ss.HardinessHabitat,
-		&ss.Miscellaneous, &ss.URL, &isPreferred,
+		&ss.Miscellaneous, &ss.DistinguishingFeatures, &ss.URL, &isPreferred, &ss.Pages, &fieldPagesJSON,
+		&ss.Transcriber, &ss.TranscriptionMethod, &ss.OCRConfidence, &verifiedAt, &ss.VerifiedBy,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan species source: %w", err)
@@ -1114,16 +1411,39 @@ func scanSpeciesSource(rows *sql.Rows) (*models.SpeciesSource, error) {
 	if ss.LocalNames == nil {
 		ss.LocalNames = []string{}
 	}
+	if ss.FieldPages, err = unmarshalFieldPages(fieldPagesJSON, ss.ScientificName); err != nil {
+		return nil, err
+	}
+	if verifiedAt.Valid {
+		t, err := time.Parse(time.RFC3339, verifiedAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse verified_at for %s: %w", ss.ScientificName, err)
+		}
+		ss.VerifiedAt, ss.Verified = &t, true
+	}
 
 	return ss, nil
 }
 
+// unmarshalFieldPages decodes a species source's field_pages column
+func unmarshalFieldPages(data sql.NullString, scientificName string) (map[string]string, error) {
+	if !data.Valid || data.String == "" {
+		return nil, nil
+	}
+	var pages map[string]string
+	if err := json.Unmarshal([]byte(data.String), &pages); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal field_pages for %s: %w", scientificName, err)
+	}
+	return pages, nil
+}
+
 // ListAllSpeciesSources returns all species_sources records (for export)
 func (db *Database) ListAllSpeciesSources() ([]*models.SpeciesSource, error) {
-	rows, err := db.conn.Query(
+	rows, err := db.q.Query(
 		`SELECT id, scientific_name, source_id, local_names, range, growth_habit,
 		        leaves, flowers, fruits, bark, twigs, buds, hardiness_habitat,
-		        miscellaneous, url, is_preferred
+		        miscellaneous, distinguishing_features, url, is_preferred, pages, field_pages,
+		        transcriber, transcription_method, ocr_confidence, verified_at, verified_by
 		 FROM species_sources ORDER BY scientific_name, is_preferred DESC`,
 	)
 	if err != nil {
@@ -1144,7 +1464,7 @@ func (db *Database) ListAllSpeciesSources() ([]*models.SpeciesSource, error) {
 
 // DeleteSpeciesSource deletes a species-source record by scientific name and source ID
 func (db *Database) DeleteSpeciesSource(scientificName string, sourceID int64) error {
-	result, err := db.conn.Exec(
+	result, err := db.q.Exec(
 		`DELETE FROM species_sources WHERE scientific_name = ? AND source_id = ?`,
 		scientificName, sourceID,
 	)
@@ -1164,7 +1484,7 @@ func (db *Database) DeleteSpeciesSource(scientificName string, sourceID int64) e
 // GetMetadata retrieves a metadata value by key
 func (db *Database) GetMetadata(key string) (string, error) {
 	var value sql.NullString
-	err := db.conn.QueryRow(
+	err := db.q.QueryRow(
 		`SELECT value FROM import_metadata WHERE key = ?`,
 		key,
 	).Scan(&value)
@@ -1182,7 +1502,7 @@ func (db *Database) GetMetadata(key string) (string, error) {
 
 // SetMetadata sets a metadata key-value pair
 func (db *Database) SetMetadata(key, value string) error {
-	_, err := db.conn.Exec(
+	_, err := db.q.Exec(
 		`INSERT OR REPLACE INTO import_metadata (key, value) VALUES (?, ?)`,
 		key, value,
 	)
@@ -1194,7 +1514,7 @@ func (db *Database) SetMetadata(key, value string) error {
 
 // DeleteMetadata removes a metadata key
 func (db *Database) DeleteMetadata(key string) error {
-	_, err := db.conn.Exec(
+	_, err := db.q.Exec(
 		`DELETE FROM import_metadata WHERE key = ?`,
 		key,
 	)
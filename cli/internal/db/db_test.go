@@ -1,11 +1,13 @@
 package db
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/jeff/oaks/cli/internal/models"
+	"github.com/jeff/oaks/pkg/migrate"
 )
 
 // testDB creates a temporary database for testing
@@ -48,6 +50,25 @@ func TestNewWithInvalidPath(t *testing.T) {
 	}
 }
 
+func TestNewRefusesNewerSchema(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, err := db.conn.Exec(`CREATE TABLE schema_version (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at TEXT NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.conn.Exec(`INSERT INTO schema_version VALUES (?, 'future', '2030-01-01T00:00:00Z')`, len(migrate.Schema)+1); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	if _, err := New(dbPath); !migrate.IsNewerSchema(err) {
+		t.Errorf("New on a newer schema error = %v, want NewerSchemaError", err)
+	}
+}
+
 // Source tests
 
 func TestSourceCRUD(t *testing.T) {
@@ -110,6 +131,30 @@ func TestSourceCRUD(t *testing.T) {
 	}
 }
 
+func TestNormalizeSourceTypes(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	for _, sourceType := range []string{"Website", "Journal", "Scroll"} {
+		if _, err := db.conn.Exec(`INSERT INTO sources (source_type, name) VALUES (?, ?)`, sourceType, sourceType+" source"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.initializeSchema(); err != nil {
+		t.Fatalf("initializeSchema failed: %v", err)
+	}
+
+	for id, want := range map[int64]string{1: "website", 2: "paper", 3: "Scroll"} {
+		source, err := db.GetSource(id)
+		if err != nil {
+			t.Fatalf("GetSource(%d) failed: %v", id, err)
+		}
+		if source.SourceType != want {
+			t.Errorf("source %d type = %q, want %q", id, source.SourceType, want)
+		}
+	}
+}
+
 func TestGetSourceNotFound(t *testing.T) {
 	db, cleanup := testDB(t)
 	defer cleanup()
@@ -228,6 +273,38 @@ func TestClearTaxa(t *testing.T) {
 	}
 }
 
+func TestTaxonLevels(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	levels, err := db.ListTaxonLevels()
+	if err != nil {
+		t.Fatalf("ListTaxonLevels failed: %v", err)
+	}
+	if len(levels) != 4 || levels[0].Name != "subgenus" || levels[3].Name != "complex" {
+		t.Fatalf("default levels = %+v", levels)
+	}
+
+	// Taxa are limited to configured levels
+	if err := db.InsertTaxon(&models.Taxon{Name: "Glandulosae", Level: "series"}); err == nil {
+		t.Error("expected insert at unknown level to fail")
+	}
+	if _, err := db.conn.Exec(`INSERT INTO taxon_levels (name, rank) VALUES ('series', 5)`); err != nil {
+		t.Fatalf("failed to add level: %v", err)
+	}
+	if err := db.InsertTaxon(&models.Taxon{Name: "Glandulosae", Level: "series"}); err != nil {
+		t.Errorf("insert at configured level failed: %v", err)
+	}
+
+	levels, err = db.ListTaxonLevels()
+	if err != nil {
+		t.Fatalf("ListTaxonLevels failed: %v", err)
+	}
+	if len(levels) != 5 || levels[4].Name != "series" || levels[4].TaxaCount != 1 {
+		t.Errorf("levels after add = %+v", levels[len(levels)-1])
+	}
+}
+
 // OakEntry tests
 
 func TestOakEntryCRUD(t *testing.T) {
@@ -349,6 +426,42 @@ func TestOakEntryHybrid(t *testing.T) {
 	}
 }
 
+func TestPlaceholderEntry(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	created, err := db.CreatePlaceholderEntry("alba")
+	if err != nil || !created {
+		t.Fatalf("CreatePlaceholderEntry = %v, %v, want true", created, err)
+	}
+	if created, _ := db.CreatePlaceholderEntry("alba"); created {
+		t.Error("CreatePlaceholderEntry created alba twice")
+	}
+
+	var visibility string
+	if err := db.conn.QueryRow(`SELECT visibility FROM oak_entries WHERE scientific_name = 'alba'`).Scan(&visibility); err != nil {
+		t.Fatalf("failed to read visibility: %v", err)
+	}
+	if visibility != models.VisibilityDraft {
+		t.Errorf("visibility = %q, want draft", visibility)
+	}
+	names, err := db.ListNeedsReview()
+	if err != nil {
+		t.Fatalf("ListNeedsReview failed: %v", err)
+	}
+	if len(names) != 1 || names[0] != "alba" {
+		t.Errorf("ListNeedsReview = %v, want [alba]", names)
+	}
+
+	// Saving the entry in full clears the flag
+	if err := db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+	if names, _ := db.ListNeedsReview(); len(names) != 0 {
+		t.Errorf("ListNeedsReview after save = %v, want none", names)
+	}
+}
+
 func TestBidirectionalHybridParentRelationship(t *testing.T) {
 	db, cleanup := testDB(t)
 	defer cleanup()
@@ -655,3 +768,73 @@ func TestBeginTx(t *testing.T) {
 	// Rollback to clean up
 	tx.Rollback()
 }
+
+func TestWithTx(t *testing.T) {
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	if err := db.SaveOakEntry(models.NewOakEntry("alba")); err != nil {
+		t.Fatalf("SaveOakEntry failed: %v", err)
+	}
+
+	// A source with its species data, as an import writes them
+	var sourceID int64
+	rng := "Eastern North America"
+	write := func(tx Store) error {
+		id, err := tx.InsertSource(&models.Source{SourceType: "website", Name: "Imported"})
+		if err != nil {
+			return err
+		}
+		sourceID = id
+		return tx.SaveSpeciesSource(&models.SpeciesSource{ScientificName: "alba", SourceID: id, Range: &rng})
+	}
+
+	failed := errors.New("import failed")
+	err := db.WithTx(func(tx Store) error {
+		if err := write(tx); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("WithTx() error = %v, want %v", err, failed)
+	}
+	if source, _ := db.GetSource(sourceID); source != nil {
+		t.Error("source was kept after the transaction failed")
+	}
+	if sources, _ := db.GetSpeciesSources("alba"); len(sources) != 0 {
+		t.Errorf("species sources kept after the transaction failed: %d", len(sources))
+	}
+
+	// A failed nested transaction is undone alone
+	var kept, dropped int64
+	err = db.WithTx(func(tx Store) error {
+		if err := write(tx); err != nil {
+			return err
+		}
+		kept = sourceID
+		if err := tx.WithTx(func(tx Store) error {
+			if err := write(tx); err != nil {
+				return err
+			}
+			dropped = sourceID
+			return failed
+		}); !errors.Is(err, failed) {
+			t.Errorf("nested WithTx() error = %v, want %v", err, failed)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+	if source, err := db.GetSource(kept); err != nil || source == nil {
+		t.Errorf("GetSource(%d) = %v, %v; want the committed source", kept, source, err)
+	}
+	if source, _ := db.GetSource(dropped); source != nil {
+		t.Error("source from the failed nested transaction was kept")
+	}
+	sources, err := db.GetSpeciesSources("alba")
+	if err != nil || len(sources) != 1 || sources[0].SourceID != kept {
+		t.Errorf("GetSpeciesSources() = %d sources, %v; want the committed one", len(sources), err)
+	}
+}
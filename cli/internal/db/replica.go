@@ -0,0 +1,71 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jeff/oaks/cli/internal/models"
+)
+
+// ReplaceGenera replaces the tracked genera with a copy of another database's
+func (db *Database) ReplaceGenera(genera []*models.Genus) error {
+	tx, err := db.begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM genera`); err != nil {
+		return fmt.Errorf("failed to clear genera: %w", err)
+	}
+	for _, g := range genera {
+		subgenera, err := json.Marshal(g.Subgenera)
+		if err != nil {
+			return fmt.Errorf("failed to marshal subgenera: %w", err)
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO genera (name, common_name, subgenera) VALUES (?, ?, ?)`,
+			g.Name, g.CommonName, string(subgenera),
+		); err != nil {
+			return fmt.Errorf("failed to insert genus %s: %w", g.Name, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ReplaceTaxonLevels replaces the configured taxon levels with a copy of another database's
+func (db *Database) ReplaceTaxonLevels(levels []*models.TaxonLevelDef) error {
+	tx, err := db.begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM taxon_levels`); err != nil {
+		return fmt.Errorf("failed to clear taxon levels: %w", err)
+	}
+	for _, l := range levels {
+		if _, err := tx.Exec(
+			`INSERT INTO taxon_levels (name, rank, plural, entry_field) VALUES (?, ?, ?, ?)`,
+			l.Name, l.Rank, l.Plural, l.EntryField,
+		); err != nil {
+			return fmt.Errorf("failed to insert taxon level %s: %w", l.Name, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// RestoreSource inserts a source keeping its ID, so species data copied from
+// another database still points at it
+func (db *Database) RestoreSource(source *models.Source) error {
+	_, err := db.q.Exec(
+		`INSERT INTO sources (id, source_type, name, description, author, year, url, isbn, doi, notes, license, license_url, superseded_by)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		source.ID, source.SourceType, source.Name, source.Description,
+		source.Author, source.Year, source.URL, source.ISBN, source.DOI, source.Notes, source.License, source.LicenseURL, source.SupersededBy,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore source %d: %w", source.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jeff/oaks/cli/internal/models"
+)
+
+// querier runs statements on the connection or in a transaction
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// txn is a transaction a method runs its statements in: its own, or a
+// savepoint in the transaction of a Store from WithTx
+type txn interface {
+	querier
+	Commit() error
+	Rollback() error
+}
+
+// Store is the data access a Database gives, whether it runs each method on
+// its own or, from WithTx, every method in one transaction. Code that may do
+// either takes a Store.
+type Store interface {
+	ListTaxonLevels() ([]*models.TaxonLevelDef, error)
+	InsertSource(source *models.Source) (int64, error)
+	GetSource(id int64) (*models.Source, error)
+	UpdateSource(source *models.Source) error
+	DeleteSource(id int64) error
+	RestoreSource(source *models.Source) error
+	InsertTaxon(taxon *models.Taxon) error
+	UpdateTaxon(taxon *models.Taxon) error
+	GetTaxon(name string, level models.TaxonLevel) (*models.Taxon, error)
+	ValidateTaxon(name string, level models.TaxonLevel) (bool, error)
+	ClearTaxa() error
+	DeleteTaxon(name string, level models.TaxonLevel) error
+	SearchTaxa(query string) ([]*models.Taxon, error)
+	SaveOakEntry(entry *models.OakEntry) error
+	GetOakEntry(scientificName string) (*models.OakEntry, error)
+	DeleteOakEntry(scientificName string) error
+	ListOakEntriesPaginated(limit, offset int, filter *OakEntryFilter) ([]*models.OakEntry, error)
+	CountOakEntries(filter *OakEntryFilter) (int, error)
+	SearchOakEntriesFull(query string, limit int) ([]*models.OakEntry, error)
+	OakEntryExists(scientificName string) (bool, error)
+	CreatePlaceholderEntry(scientificName string) (bool, error)
+	ListNeedsReview() ([]string, error)
+	SaveSpeciesSource(ss *models.SpeciesSource) error
+	GetSpeciesSources(scientificName string) ([]*models.SpeciesSource, error)
+	GetSpeciesSourceBySourceID(scientificName string, sourceID int64) (*models.SpeciesSource, error)
+	GetPreferredSpeciesSource(scientificName string) (*models.SpeciesSource, error)
+	ListAllSpeciesSources() ([]*models.SpeciesSource, error)
+	DeleteSpeciesSource(scientificName string, sourceID int64) error
+	ReplaceGenera(genera []*models.Genus) error
+	ReplaceTaxonLevels(levels []*models.TaxonLevelDef) error
+	GetMetadata(key string) (string, error)
+	SetMetadata(key, value string) error
+	DeleteMetadata(key string) error
+	WithTx(fn func(tx Store) error) error
+}
+
+// WithTx runs fn with a Store whose methods all run in one transaction,
+// committed if fn returns nil and rolled back if it returns an error or
+// panics. Multi-entity operations such as an import use it so a failure
+// part way leaves nothing behind.
+//
+// A method that fails inside fn undoes its own statements, so fn may report
+// the failure and go on. WithTx on the Store nests: the inner fn's changes
+// are undone alone if it fails.
+func (db *Database) WithTx(fn func(tx Store) error) (err error) {
+	tx, err := db.begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	scoped := &Database{conn: db.conn, q: tx, tx: db.tx}
+	if scoped.tx == nil {
+		scoped.tx = tx.(*sql.Tx)
+	}
+	if err := fn(scoped); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// begin starts a transaction, or a savepoint in the transaction of a Store
+// from WithTx
+func (db *Database) begin() (txn, error) {
+	if db.tx == nil {
+		return db.conn.Begin()
+	}
+	if _, err := db.tx.Exec(`SAVEPOINT store`); err != nil {
+		return nil, fmt.Errorf("failed to start savepoint: %w", err)
+	}
+	return &savepoint{Tx: db.tx}, nil
+}
+
+// savepoint is a txn nested in a transaction. Commit releases it into the
+// transaction; Rollback undoes what was run since it began.
+type savepoint struct {
+	*sql.Tx
+	done bool
+}
+
+func (sp *savepoint) Commit() error {
+	if sp.done {
+		return sql.ErrTxDone
+	}
+	sp.done = true
+	if _, err := sp.Exec(`RELEASE store`); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
+
+func (sp *savepoint) Rollback() error {
+	if sp.done {
+		return sql.ErrTxDone
+	}
+	sp.done = true
+	if _, err := sp.Exec(`ROLLBACK TO store`); err != nil {
+		return fmt.Errorf("failed to roll back savepoint: %w", err)
+	}
+	_, err := sp.Exec(`RELEASE store`)
+	return err
+}
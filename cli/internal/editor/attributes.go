@@ -0,0 +1,135 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AttributeField is a custom field as the attributes editor shows it
+type AttributeField struct {
+	Key   string
+	Type  string // text, number, boolean, date, or url
+	Label string
+}
+
+// EditAttributes opens a record's custom field values in the editor as
+// YAML, one key per field with its label and type in a comment. Returns
+// the values that changed; a value blanked out is returned as nil, to
+// remove it.
+func EditAttributes(title string, fields []AttributeField, values map[string]any) (map[string]any, error) {
+	content := formatAttributes(title, fields, values)
+	for {
+		editedContent, err := openEditorWithExt(content, ".yaml")
+		if err != nil {
+			return nil, err
+		}
+
+		changes, problems := parseAttributes(editedContent, fields, values)
+		if len(problems) > 0 {
+			fmt.Fprintf(os.Stderr, "\n%s\n", formatProblems(problems))
+			fmt.Fprintln(os.Stderr, "Press Enter to re-open the editor and fix the error...")
+			waitForEnter()
+			content = editedContent
+			continue
+		}
+		return changes, nil
+	}
+}
+
+// formatAttributes writes the document EditAttributes opens
+func formatAttributes(title string, fields []AttributeField, values map[string]any) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Custom fields of %s. Leave a value empty to remove it.\n", title)
+	for _, f := range fields {
+		fmt.Fprintf(&b, "\n# %s (%s)\n", f.Label, f.Type)
+		v, ok := values[f.Key]
+		if !ok || v == nil {
+			fmt.Fprintf(&b, "%s:\n", f.Key)
+			continue
+		}
+		data, err := yaml.Marshal(map[string]any{f.Key: v})
+		if err != nil {
+			fmt.Fprintf(&b, "%s:\n", f.Key)
+			continue
+		}
+		b.Write(data)
+	}
+	return b.String()
+}
+
+// parseAttributes reads an edited document back, returning the values that
+// differ from values
+func parseAttributes(content string, fields []AttributeField, values map[string]any) (map[string]any, []Problem) {
+	var edited map[string]any
+	if err := yaml.Unmarshal([]byte(content), &edited); err != nil {
+		return nil, []Problem{{Message: fmt.Sprintf("invalid YAML: %v", err)}}
+	}
+
+	types := make(map[string]string, len(fields))
+	for _, f := range fields {
+		types[f.Key] = f.Type
+	}
+
+	changes := make(map[string]any)
+	var problems []Problem
+	for key, v := range edited {
+		typ, ok := types[key]
+		if !ok {
+			problems = append(problems, Problem{Field: key, Message: "is not a custom field"})
+			continue
+		}
+		if v == nil || v == "" {
+			if values[key] != nil {
+				changes[key] = nil
+			}
+			continue
+		}
+		value, err := attributeValue(typ, v)
+		if err != nil {
+			problems = append(problems, Problem{Field: key, Message: err.Error()})
+			continue
+		}
+		if value != values[key] {
+			changes[key] = value
+		}
+	}
+	// A key deleted outright removes its value too
+	for key, v := range values {
+		if _, ok := edited[key]; !ok && v != nil {
+			changes[key] = nil
+		}
+	}
+	return changes, problems
+}
+
+// attributeValue converts a YAML value to the JSON type the API expects for
+// a field of typ. The API checks dates and URLs.
+func attributeValue(typ string, v any) (any, error) {
+	switch typ {
+	case "number":
+		switch n := v.(type) {
+		case int:
+			return float64(n), nil
+		case float64:
+			return n, nil
+		}
+		return nil, fmt.Errorf("must be a number")
+	case "boolean":
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("must be true or false")
+	case "date":
+		if t, ok := v.(time.Time); ok {
+			return t.Format(time.DateOnly), nil
+		}
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(v), nil
+}
@@ -0,0 +1,50 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttributesRoundTrip(t *testing.T) {
+	fields := []AttributeField{
+		{Key: "accession", Type: "text", Label: "Accession"},
+		{Key: "collected", Type: "date", Label: "Collected"},
+		{Key: "digitized", Type: "boolean", Label: "Digitized"},
+		{Key: "hardiness_zone", Type: "number", Label: "USDA zone"},
+	}
+	values := map[string]any{"accession": "1987-0042", "hardiness_zone": 3.0}
+
+	content := formatAttributes("Quercus alba", fields, values)
+	if !strings.Contains(content, "# USDA zone (number)\nhardiness_zone: 3\n") {
+		t.Errorf("content = %q, want the zone with its label", content)
+	}
+	changes, problems := parseAttributes(content, fields, values)
+	if len(changes) != 0 || len(problems) != 0 {
+		t.Fatalf("unedited: changes = %v, problems = %v, want none", changes, problems)
+	}
+
+	edited := strings.NewReplacer(
+		"accession: 1987-0042", "accession:",
+		"collected:", "collected: 2019-05-04",
+		"digitized:", "digitized: true",
+		"hardiness_zone: 3", "hardiness_zone: 4.5",
+	).Replace(content)
+	changes, problems = parseAttributes(edited, fields, values)
+	if len(problems) != 0 {
+		t.Fatalf("problems = %v", problems)
+	}
+	want := map[string]any{"accession": nil, "collected": "2019-05-04", "digitized": true, "hardiness_zone": 4.5}
+	if len(changes) != len(want) {
+		t.Errorf("changes = %v, want %v", changes, want)
+	}
+	for key, v := range want {
+		if got, ok := changes[key]; !ok || got != v {
+			t.Errorf("changes[%s] = %v, want %v", key, got, v)
+		}
+	}
+
+	_, problems = parseAttributes("hardiness_zone: warm\nunknown: 1\n", fields, values)
+	if len(problems) != 2 {
+		t.Errorf("problems = %v, want a type error and an unknown field", problems)
+	}
+}
@@ -0,0 +1,44 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jeff/oaks/cli/internal/models"
+)
+
+// SaveEdits writes an edited document to a new file in the temp directory,
+// as the editor would show it, for a save the server refused because its copy
+// changed mid-edit. The edits survive for the curator to reapply, and the file
+// can be checked with 'oak validate'. doc is a *models.OakEntry,
+// *models.SpeciesSource, *models.Source, or *models.Taxon; sourceName labels
+// species sources. Returns the file's path.
+func SaveEdits(doc interface{}, sourceName string) (string, error) {
+	var content string
+	var err error
+	switch d := doc.(type) {
+	case *models.OakEntry:
+		content, err = oakEntryToMarkdown(d)
+	case *models.SpeciesSource:
+		content, err = speciesSourceToMarkdown(d, sourceName)
+	case *models.Source:
+		content, err = sourceToMarkdown(d)
+	case *models.Taxon:
+		content, err = taxonToMarkdown(d)
+	default:
+		return "", fmt.Errorf("cannot save edits of %T", doc)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.CreateTemp("", "oak-edits-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create edits file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		return "", fmt.Errorf("failed to write edits file: %w", err)
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,46 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jeff/oaks/cli/internal/models"
+)
+
+func TestSaveEdits(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	leaves := "Lobed: deeply"
+	ss := models.NewSpeciesSource("× bebbiana", 3)
+	ss.Leaves = &leaves
+	ss.LocalNames = []string{"Bebb's oak"}
+
+	path, err := SaveEdits(ss, "Oaks of the World")
+	if err != nil {
+		t.Fatalf("SaveEdits() error = %v", err)
+	}
+	if filepath.Dir(path) != os.Getenv("TMPDIR") {
+		t.Errorf("path = %s, want a file in TMPDIR", path)
+	}
+
+	kind, problems, err := ValidateFile(path, "", nil)
+	if err != nil || kind != KindSpeciesSource || len(problems) > 0 {
+		t.Fatalf("ValidateFile() = %s, %v, %v; want a valid species source", kind, problems, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := parseSpeciesSourceMarkdown(string(data), ss)
+	if err != nil {
+		t.Fatalf("parseSpeciesSourceMarkdown() error = %v", err)
+	}
+	if parsed.Leaves == nil || *parsed.Leaves != leaves || len(parsed.LocalNames) != 1 {
+		t.Errorf("saved edits read back as %+v", parsed)
+	}
+
+	if _, err := SaveEdits("alba", ""); err == nil {
+		t.Error("SaveEdits(string) error = nil, want an error")
+	}
+}
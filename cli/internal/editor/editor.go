@@ -158,72 +158,88 @@ func openEditorMarkdown(initialContent string) (string, error) {
 	return openEditorWithExt(initialContent, ".md")
 }
 
+// SchemaDir is where 'oak schema dump' writes JSON Schemas. When the schema
+// for a document exists there, its front matter gets a yaml-language-server
+// modeline so editors such as VS Code validate fields while curators type.
+var SchemaDir string
+
+// schemaModeline returns the modeline comment for the named schema, or "" if
+// the schema has not been dumped.
+func schemaModeline(name string) string {
+	if SchemaDir == "" {
+		return ""
+	}
+	path, err := filepath.Abs(filepath.Join(SchemaDir, name+".schema.json"))
+	if err != nil {
+		return ""
+	}
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return fmt.Sprintf("# yaml-language-server: $schema=%s\n", path)
+}
+
 // waitForEnter waits for the user to press Enter
 func waitForEnter() {
 	reader := bufio.NewReader(os.Stdin)
 	_, _ = reader.ReadString('\n')
 }
 
-// oakEntryToMarkdown generates a markdown string for editing an oak entry
-func oakEntryToMarkdown(e *models.OakEntry) string {
-	deref := func(p *string) string {
-		if p == nil {
-			return ""
-		}
-		return *p
-	}
+// oakEntryFrontmatter is the front matter written for an oak entry, in the
+// order fields appear in the editor
+type oakEntryFrontmatter struct {
+	ScientificName     string  `yaml:"scientific_name"`
+	Author             *string `yaml:"author"`
+	Pronunciation      *string `yaml:"pronunciation"`
+	IsHybrid           bool    `yaml:"is_hybrid"`
+	ConservationStatus *string `yaml:"conservation_status"`
+
+	Genus      string  `yaml:"genus"`
+	Subgenus   *string `yaml:"subgenus"`
+	Section    *string `yaml:"section"`
+	Subsection *string `yaml:"subsection"`
+	Complex    *string `yaml:"complex"`
+
+	Parent1 *string `yaml:"parent1"`
+	Parent2 *string `yaml:"parent2"`
+
+	Hybrids             []string `yaml:"hybrids"`
+	CloselyRelatedTo    []string `yaml:"closely_related_to"`
+	SubspeciesVarieties []string `yaml:"subspecies_varieties"`
+	Synonyms            []string `yaml:"synonyms"`
+
+	ExternalLinks []models.ExternalLink `yaml:"external_links"`
+}
 
-	formatArray := func(arr []string) string {
-		if len(arr) == 0 {
-			return "[]"
-		}
-		var sb strings.Builder
-		sb.WriteString("\n")
-		for _, v := range arr {
-			sb.WriteString(fmt.Sprintf("  - %s\n", v))
-		}
-		return strings.TrimSuffix(sb.String(), "\n")
-	}
-
-	formatExternalLinks := func(links []models.ExternalLink) string {
-		if len(links) == 0 {
-			return "[]"
-		}
-		var sb strings.Builder
-		sb.WriteString("\n")
-		for _, link := range links {
-			sb.WriteString(fmt.Sprintf("  - name: %s\n", link.Name))
-			sb.WriteString(fmt.Sprintf("    url: %s\n", link.URL))
-			sb.WriteString(fmt.Sprintf("    logo: %s\n", link.Logo))
-		}
-		return strings.TrimSuffix(sb.String(), "\n")
-	}
-
-	var fm strings.Builder
-	fm.WriteString("---\n")
-	fm.WriteString(fmt.Sprintf("scientific_name: %s\n", e.ScientificName))
-	fm.WriteString(fmt.Sprintf("author: %s\n", deref(e.Author)))
-	fm.WriteString(fmt.Sprintf("is_hybrid: %t\n", e.IsHybrid))
-	fm.WriteString(fmt.Sprintf("conservation_status: %s\n", deref(e.ConservationStatus)))
-	fm.WriteString("\n")
-	fm.WriteString(fmt.Sprintf("subgenus: %s\n", deref(e.Subgenus)))
-	fm.WriteString(fmt.Sprintf("section: %s\n", deref(e.Section)))
-	fm.WriteString(fmt.Sprintf("subsection: %s\n", deref(e.Subsection)))
-	fm.WriteString(fmt.Sprintf("complex: %s\n", deref(e.Complex)))
-	fm.WriteString("\n")
-	fm.WriteString(fmt.Sprintf("parent1: %s\n", deref(e.Parent1)))
-	fm.WriteString(fmt.Sprintf("parent2: %s\n", deref(e.Parent2)))
-	fm.WriteString("\n")
-	fm.WriteString(fmt.Sprintf("hybrids: %s\n", formatArray(e.Hybrids)))
-	fm.WriteString(fmt.Sprintf("closely_related_to: %s\n", formatArray(e.CloselyRelatedTo)))
-	fm.WriteString(fmt.Sprintf("subspecies_varieties: %s\n", formatArray(e.SubspeciesVarieties)))
-	fm.WriteString(fmt.Sprintf("synonyms: %s\n", formatArray(e.Synonyms)))
-	fm.WriteString("\n")
-	fm.WriteString("# External links: name (display label), url, logo (icon id: wikipedia, inaturalist, usda, gbif, powo, generic)\n")
-	fm.WriteString(fmt.Sprintf("external_links: %s\n", formatExternalLinks(e.ExternalLinks)))
-	fm.WriteString("---\n")
-
-	return fm.String()
+// oakEntryToMarkdown generates a markdown string for editing an oak entry
+func oakEntryToMarkdown(e *models.OakEntry) (string, error) {
+	return writeFrontmatter(schemaModeline("oak-entry"), oakEntryFrontmatter{
+		ScientificName:      e.ScientificName,
+		Author:              e.Author,
+		Pronunciation:       e.Pronunciation,
+		IsHybrid:            e.IsHybrid,
+		ConservationStatus:  e.ConservationStatus,
+		Genus:               e.Genus,
+		Subgenus:            e.Subgenus,
+		Section:             e.Section,
+		Subsection:          e.Subsection,
+		Complex:             e.Complex,
+		Parent1:             e.Parent1,
+		Parent2:             e.Parent2,
+		Hybrids:             e.Hybrids,
+		CloselyRelatedTo:    e.CloselyRelatedTo,
+		SubspeciesVarieties: e.SubspeciesVarieties,
+		Synonyms:            e.Synonyms,
+		ExternalLinks:       e.ExternalLinks,
+	}, frontmatterLayout{
+		groups: []string{"genus", "parent1", "hybrids", "external_links"},
+		comments: map[string]string{
+			"conservation_status": "IUCN code: " + strings.Join(models.ConservationStatuses, ", "),
+			"genus":               "Genus; blank keeps the current one (" + models.DefaultGenus + " for new entries)",
+			"external_links": "External links: name (display label), url (http or https), logo (icon id: " +
+				strings.Join(models.ExternalLinkLogos, ", ") + ")",
+		},
+	})
 }
 
 // parseOakEntryMarkdown parses markdown content back into an OakEntry
@@ -243,7 +259,16 @@ func parseOakEntryMarkdown(content string) (*models.OakEntry, error) {
 
 // EditOakEntry edits an Oak entry with validation loop
 func EditOakEntry(entry *models.OakEntry, validator *schema.Validator) (*models.OakEntry, error) {
-	content := oakEntryToMarkdown(entry)
+	return editOakEntry(entry, validator, nil)
+}
+
+// editOakEntry runs the edit/validate loop, also re-opening the editor until
+// every field in required is filled in
+func editOakEntry(entry *models.OakEntry, validator *schema.Validator, required []string) (*models.OakEntry, error) {
+	content, err := oakEntryToMarkdown(entry)
+	if err != nil {
+		return nil, err
+	}
 
 	for {
 		editedContent, err := openEditorMarkdown(content)
@@ -268,6 +293,22 @@ func EditOakEntry(entry *models.OakEntry, validator *schema.Validator) (*models.
 			continue
 		}
 
+		if problems := oakEntryProblems(editedEntry); len(problems) > 0 {
+			fmt.Fprintf(os.Stderr, "\nValidation failed:\n%s\n", formatProblems(problems))
+			fmt.Fprintln(os.Stderr, "\nPress Enter to re-open the editor and fix the errors...")
+			waitForEnter()
+			content = editedContent
+			continue
+		}
+
+		if missing := missingFields(editedEntry, required); len(missing) > 0 {
+			fmt.Fprintf(os.Stderr, "\nRequired fields are empty: %s\n", strings.Join(missing, ", "))
+			fmt.Fprintln(os.Stderr, "Press Enter to re-open the editor and fill them in...")
+			waitForEnter()
+			content = editedContent
+			continue
+		}
+
 		return editedEntry, nil
 	}
 }
@@ -278,9 +319,57 @@ func NewOakEntry(scientificName string, validator *schema.Validator) (*models.Oa
 	return EditOakEntry(template, validator)
 }
 
+// NewOakEntryFromTemplate creates a new Oak entry starting from a pre-filled
+// entry. The editor re-opens until every field in required is filled in.
+func NewOakEntryFromTemplate(entry *models.OakEntry, required []string, validator *schema.Validator) (*models.OakEntry, error) {
+	return editOakEntry(entry, validator, required)
+}
+
+// missingFields returns the names in fields that are empty on the entry.
+// Unknown field names are ignored.
+func missingFields(e *models.OakEntry, fields []string) []string {
+	empty := func(p *string) bool { return p == nil || strings.TrimSpace(*p) == "" }
+
+	var missing []string
+	for _, field := range fields {
+		var isEmpty bool
+		switch field {
+		case "author":
+			isEmpty = empty(e.Author)
+		case "conservation_status":
+			isEmpty = empty(e.ConservationStatus)
+		case "subgenus":
+			isEmpty = empty(e.Subgenus)
+		case "section":
+			isEmpty = empty(e.Section)
+		case "subsection":
+			isEmpty = empty(e.Subsection)
+		case "complex":
+			isEmpty = empty(e.Complex)
+		case "parent1":
+			isEmpty = empty(e.Parent1)
+		case "parent2":
+			isEmpty = empty(e.Parent2)
+		case "synonyms":
+			isEmpty = len(e.Synonyms) == 0
+		case "closely_related_to":
+			isEmpty = len(e.CloselyRelatedTo) == 0
+		case "external_links":
+			isEmpty = len(e.ExternalLinks) == 0
+		}
+		if isEmpty {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
+
 // EditSource edits a Source entry
 func EditSource(source *models.Source) (*models.Source, error) {
-	content := sourceToMarkdown(source)
+	content, err := sourceToMarkdown(source)
+	if err != nil {
+		return nil, err
+	}
 	originalID := source.ID
 
 	for {
@@ -307,28 +396,21 @@ func EditSource(source *models.Source) (*models.Source, error) {
 			continue
 		}
 
-		if editedSource.Name == "" {
-			fmt.Fprintln(os.Stderr, "\nname cannot be empty")
-			fmt.Fprintln(os.Stderr, "Press Enter to re-open the editor and fix the error...")
-			waitForEnter()
-			content = editedContent
-			continue
-		}
-
-		if editedSource.SourceType == "" {
-			fmt.Fprintln(os.Stderr, "\nsource_type cannot be empty")
+		if problems := sourceProblems(editedSource); len(problems) > 0 {
+			fmt.Fprintf(os.Stderr, "\n%s\n", formatProblems(problems))
 			fmt.Fprintln(os.Stderr, "Press Enter to re-open the editor and fix the error...")
 			waitForEnter()
 			content = editedContent
 			continue
 		}
 
+		editedSource.SourceType, _ = models.NormalizeSourceType(editedSource.SourceType)
 		return editedSource, nil
 	}
 }
 
 // sourceToMarkdown generates a markdown string for editing a source
-func sourceToMarkdown(s *models.Source) string {
+func sourceToMarkdown(s *models.Source) (string, error) {
 	deref := func(p *string) string {
 		if p == nil {
 			return ""
@@ -336,43 +418,43 @@ func sourceToMarkdown(s *models.Source) string {
 		return *p
 	}
 
-	var fm strings.Builder
-	fm.WriteString("---\n")
-	fm.WriteString(fmt.Sprintf("id: %d\n", s.ID))
-	fm.WriteString(fmt.Sprintf("source_type: %s\n", s.SourceType))
-	fm.WriteString(fmt.Sprintf("name: %s\n", s.Name))
-	fm.WriteString(fmt.Sprintf("author: %s\n", deref(s.Author)))
-	if s.Year != nil {
-		fm.WriteString(fmt.Sprintf("year: %d\n", *s.Year))
-	} else {
-		fm.WriteString("year:\n")
-	}
-	fm.WriteString(fmt.Sprintf("url: %s\n", deref(s.URL)))
-	fm.WriteString(fmt.Sprintf("isbn: %s\n", deref(s.ISBN)))
-	fm.WriteString(fmt.Sprintf("doi: %s\n", deref(s.DOI)))
-	fm.WriteString(fmt.Sprintf("license: %s\n", deref(s.License)))
-	fm.WriteString(fmt.Sprintf("license_url: %s\n", deref(s.LicenseURL)))
-	fm.WriteString("---\n\n")
+	fm, err := writeFrontmatter(schemaModeline("source"), sourceFrontmatter{
+		ID:           s.ID,
+		SourceType:   s.SourceType,
+		Name:         s.Name,
+		Author:       deref(s.Author),
+		Year:         s.Year,
+		URL:          deref(s.URL),
+		ISBN:         deref(s.ISBN),
+		DOI:          deref(s.DOI),
+		License:      deref(s.License),
+		LicenseURL:   deref(s.LicenseURL),
+		SupersededBy: s.SupersededBy,
+	}, frontmatterLayout{})
+	if err != nil {
+		return "", err
+	}
 
 	var body strings.Builder
 	body.WriteString(fmt.Sprintf("# Description\n\n%s\n\n", deref(s.Description)))
 	body.WriteString(fmt.Sprintf("# Notes\n\n%s\n", deref(s.Notes)))
 
-	return fm.String() + body.String()
+	return fm + "\n" + body.String(), nil
 }
 
 // sourceFrontmatter is the structured data from source frontmatter
 type sourceFrontmatter struct {
-	ID         int64  `yaml:"id"`
-	SourceType string `yaml:"source_type"`
-	Name       string `yaml:"name"`
-	Author     string `yaml:"author"`
-	Year       *int   `yaml:"year"`
-	URL        string `yaml:"url"`
-	ISBN       string `yaml:"isbn"`
-	DOI        string `yaml:"doi"`
-	License    string `yaml:"license"`
-	LicenseURL string `yaml:"license_url"`
+	ID           int64  `yaml:"id"`
+	SourceType   string `yaml:"source_type"`
+	Name         string `yaml:"name"`
+	Author       string `yaml:"author"`
+	Year         *int   `yaml:"year"`
+	URL          string `yaml:"url"`
+	ISBN         string `yaml:"isbn"`
+	DOI          string `yaml:"doi"`
+	License      string `yaml:"license"`
+	LicenseURL   string `yaml:"license_url"`
+	SupersededBy *int64 `yaml:"superseded_by,omitempty"` // Only written for superseded sources
 }
 
 // parseSourceMarkdown parses markdown content back into a Source
@@ -388,10 +470,11 @@ func parseSourceMarkdown(content string) (*models.Source, error) {
 	}
 
 	result := &models.Source{
-		ID:         fmData.ID,
-		SourceType: fmData.SourceType,
-		Name:       fmData.Name,
-		Year:       fmData.Year,
+		ID:           fmData.ID,
+		SourceType:   fmData.SourceType,
+		Name:         fmData.Name,
+		Year:         fmData.Year,
+		SupersededBy: fmData.SupersededBy,
 	}
 
 	setIfNotEmpty := func(field **string, value string) {
@@ -420,7 +503,10 @@ func parseSourceMarkdown(content string) (*models.Source, error) {
 
 // EditSpeciesSource edits source-attributed data for a species
 func EditSpeciesSource(ss *models.SpeciesSource, sourceName string) (*models.SpeciesSource, error) {
-	content := speciesSourceToMarkdown(ss, sourceName)
+	content, err := speciesSourceToMarkdown(ss, sourceName)
+	if err != nil {
+		return nil, err
+	}
 
 	for {
 		editedContent, err := openEditorMarkdown(content)
@@ -442,7 +528,7 @@ func EditSpeciesSource(ss *models.SpeciesSource, sourceName string) (*models.Spe
 }
 
 // speciesSourceToMarkdown generates a markdown string for editing species source data
-func speciesSourceToMarkdown(ss *models.SpeciesSource, sourceName string) string {
+func speciesSourceToMarkdown(ss *models.SpeciesSource, sourceName string) (string, error) {
 	deref := func(p *string) string {
 		if p == nil {
 			return ""
@@ -450,35 +536,25 @@ func speciesSourceToMarkdown(ss *models.SpeciesSource, sourceName string) string
 		return *p
 	}
 
-	// Build frontmatter for structured data
-	var fm strings.Builder
-	fm.WriteString("---\n")
-	fm.WriteString(fmt.Sprintf("species: %s\n", ss.ScientificName))
-	fm.WriteString(fmt.Sprintf("source: \"%s (ID: %d)\"\n", sourceName, ss.SourceID))
-
-	// Always use inline array format for consistency
-	if len(ss.LocalNames) == 0 {
-		fm.WriteString("local_names: []\n")
-	} else {
-		// Quote names that contain special YAML characters
-		quotedNames := make([]string, len(ss.LocalNames))
-		for i, ln := range ss.LocalNames {
-			if strings.ContainsAny(ln, ",:[]{}#&*!|>'\"%@`") || strings.HasPrefix(ln, "-") || strings.HasPrefix(ln, " ") {
-				quotedNames[i] = fmt.Sprintf("%q", ln)
-			} else {
-				quotedNames[i] = ln
-			}
-		}
-		fm.WriteString(fmt.Sprintf("local_names: [%s]\n", strings.Join(quotedNames, ", ")))
-	}
-
-	fm.WriteString(fmt.Sprintf("is_preferred: %t\n", ss.IsPreferred))
-	if url := deref(ss.URL); url != "" {
-		fm.WriteString(fmt.Sprintf("url: %s\n", url))
-	} else {
-		fm.WriteString("url:\n")
+	fm, err := writeFrontmatter(schemaModeline("species-source"), speciesSourceFrontmatter{
+		Species:     ss.ScientificName,
+		Source:      fmt.Sprintf("%s (ID: %d)", sourceName, ss.SourceID),
+		LocalNames:  ss.LocalNames,
+		IsPreferred: ss.IsPreferred,
+		URL:         deref(ss.URL),
+		Pages:       deref(ss.Pages),
+		FieldPages:  ss.FieldPages,
+		Visibility:  ss.FieldVisibility,
+	}, frontmatterLayout{
+		inline: []string{"local_names"},
+		comments: map[string]string{
+			"field_pages":      "Pages of single sections found elsewhere, e.g. fruits: pl. 23",
+			"field_visibility": "Sections withheld from the public, e.g. range: internal",
+		},
+	})
+	if err != nil {
+		return "", err
 	}
-	fm.WriteString("---\n\n")
 
 	// Build markdown body for text content
 	var body strings.Builder
@@ -497,22 +573,26 @@ func speciesSourceToMarkdown(ss *models.SpeciesSource, sourceName string) string
 		{"Buds", deref(ss.Buds)},
 		{"Hardiness & Habitat", deref(ss.HardinessHabitat)},
 		{"Notes", deref(ss.Miscellaneous)},
+		{"Distinguishing Features", deref(ss.DistinguishingFeatures)},
 	}
 
 	for _, s := range sections {
 		body.WriteString(fmt.Sprintf("# %s\n\n%s\n\n", s.heading, s.content))
 	}
 
-	return fm.String() + body.String()
+	return fm + "\n" + body.String(), nil
 }
 
 // speciesSourceFrontmatter is the structured data from frontmatter
 type speciesSourceFrontmatter struct {
-	Species     string   `yaml:"species"`
-	Source      string   `yaml:"source"`
-	LocalNames  []string `yaml:"local_names"`
-	IsPreferred bool     `yaml:"is_preferred"`
-	URL         string   `yaml:"url"`
+	Species     string            `yaml:"species"`
+	Source      string            `yaml:"source"`
+	LocalNames  []string          `yaml:"local_names"`
+	IsPreferred bool              `yaml:"is_preferred"`
+	URL         string            `yaml:"url"`
+	Pages       string            `yaml:"pages"`
+	FieldPages  map[string]string `yaml:"field_pages"`
+	Visibility  map[string]string `yaml:"field_visibility"`
 }
 
 // parseSpeciesSourceMarkdown parses markdown content back into a SpeciesSource
@@ -540,6 +620,25 @@ func parseSpeciesSourceMarkdown(content string, original *models.SpeciesSource)
 	if fmData.URL != "" {
 		result.URL = &fmData.URL
 	}
+	if fmData.Pages != "" {
+		result.Pages = &fmData.Pages
+	}
+	if len(fmData.FieldPages) > 0 {
+		result.FieldPages = fmData.FieldPages
+	}
+	if len(fmData.Visibility) > 0 {
+		result.FieldVisibility = fmData.Visibility
+	}
+	// The API only changes the visibility of fields it is sent, so sections
+	// removed from field_visibility are made public explicitly
+	for field := range original.FieldVisibility {
+		if _, ok := fmData.Visibility[field]; !ok {
+			if result.FieldVisibility == nil {
+				result.FieldVisibility = make(map[string]string)
+			}
+			result.FieldVisibility[field] = "public"
+		}
+	}
 
 	// Extract text sections from body
 	setIfNotEmpty := func(field **string, heading string) {
@@ -558,6 +657,7 @@ func parseSpeciesSourceMarkdown(content string, original *models.SpeciesSource)
 	setIfNotEmpty(&result.Buds, "Buds")
 	setIfNotEmpty(&result.HardinessHabitat, "Hardiness & Habitat")
 	setIfNotEmpty(&result.Miscellaneous, "Notes")
+	setIfNotEmpty(&result.DistinguishingFeatures, "Distinguishing Features")
 
 	return result, nil
 }
@@ -584,10 +684,14 @@ func NewSource() (*models.Source, error) {
 
 	fmt.Println("Creating new source...")
 
-	sourceType, err := prompt("Source Type (Book, Paper, Website, Observation, etc.)")
+	sourceType, err := prompt("Source Type (" + strings.Join(models.SourceTypes, ", ") + ")")
 	if err != nil {
 		return nil, err
 	}
+	sourceType, ok := models.NormalizeSourceType(sourceType)
+	if !ok {
+		return nil, fmt.Errorf("source type must be one of: %s", strings.Join(models.SourceTypes, ", "))
+	}
 
 	name, err := prompt("Name/Title")
 	if err != nil {
@@ -676,7 +780,7 @@ func NewSource() (*models.Source, error) {
 }
 
 // taxonToMarkdown generates a markdown string for editing a taxon
-func taxonToMarkdown(t *models.Taxon) string {
+func taxonToMarkdown(t *models.Taxon) (string, error) {
 	deref := func(p *string) string {
 		if p == nil {
 			return ""
@@ -684,34 +788,24 @@ func taxonToMarkdown(t *models.Taxon) string {
 		return *p
 	}
 
-	formatLinks := func(links []models.TaxonLink) string {
-		if len(links) == 0 {
-			return "[]"
-		}
-		var sb strings.Builder
-		sb.WriteString("\n")
-		for _, link := range links {
-			sb.WriteString(fmt.Sprintf("  - label: %s\n", link.Label))
-			sb.WriteString(fmt.Sprintf("    url: %s\n", link.URL))
-		}
-		return strings.TrimSuffix(sb.String(), "\n")
+	fm, err := writeFrontmatter(schemaModeline("taxon"), taxonFrontmatter{
+		Name:   t.Name,
+		Level:  string(t.Level),
+		Parent: deref(t.Parent),
+		Author: deref(t.Author),
+		Links:  t.Links,
+	}, frontmatterLayout{
+		groups:   []string{"links"},
+		comments: map[string]string{"links": "External links (label + url)"},
+	})
+	if err != nil {
+		return "", err
 	}
 
-	var fm strings.Builder
-	fm.WriteString("---\n")
-	fm.WriteString(fmt.Sprintf("name: %s\n", t.Name))
-	fm.WriteString(fmt.Sprintf("level: %s\n", string(t.Level)))
-	fm.WriteString(fmt.Sprintf("parent: %s\n", deref(t.Parent)))
-	fm.WriteString(fmt.Sprintf("author: %s\n", deref(t.Author)))
-	fm.WriteString("\n")
-	fm.WriteString("# External links (label + url)\n")
-	fm.WriteString(fmt.Sprintf("links: %s\n", formatLinks(t.Links)))
-	fm.WriteString("---\n\n")
-
 	var body strings.Builder
 	body.WriteString(fmt.Sprintf("# Notes\n\n%s\n", deref(t.Notes)))
 
-	return fm.String() + body.String()
+	return fm + "\n" + body.String(), nil
 }
 
 // taxonFrontmatter is the structured data from taxon frontmatter
@@ -735,14 +829,10 @@ func parseTaxonMarkdown(content string) (*models.Taxon, error) {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
 
-	// Validate level
-	level := models.TaxonLevel(fmData.Level)
-	switch level {
-	case models.TaxonLevelSubgenus, models.TaxonLevelSection,
-		models.TaxonLevelSubsection, models.TaxonLevelComplex:
-		// Valid
-	default:
-		return nil, fmt.Errorf("invalid level: %s (must be subgenus, section, subsection, or complex)", fmData.Level)
+	// Level names are configurable; the database rejects unknown levels on save
+	level := models.TaxonLevel(strings.ToLower(strings.TrimSpace(fmData.Level)))
+	if level == "" {
+		return nil, fmt.Errorf("level is required")
 	}
 
 	result := &models.Taxon{
@@ -768,7 +858,10 @@ func parseTaxonMarkdown(content string) (*models.Taxon, error) {
 
 // EditTaxon edits a taxon with validation loop
 func EditTaxon(taxon *models.Taxon) (*models.Taxon, error) {
-	content := taxonToMarkdown(taxon)
+	content, err := taxonToMarkdown(taxon)
+	if err != nil {
+		return nil, err
+	}
 	originalName := taxon.Name
 	originalLevel := taxon.Level
 
@@ -814,7 +907,10 @@ func NewTaxon(name string, level models.TaxonLevel) (*models.Taxon, error) {
 		Level: level,
 		Links: []models.TaxonLink{},
 	}
-	content := taxonToMarkdown(template)
+	content, err := taxonToMarkdown(template)
+	if err != nil {
+		return nil, err
+	}
 
 	for {
 		editedContent, err := openEditorMarkdown(content)
@@ -831,8 +927,8 @@ func NewTaxon(name string, level models.TaxonLevel) (*models.Taxon, error) {
 			continue
 		}
 
-		if edited.Name == "" {
-			fmt.Fprintln(os.Stderr, "\nname cannot be empty")
+		if problems := taxonProblems(edited); len(problems) > 0 {
+			fmt.Fprintf(os.Stderr, "\n%s\n", formatProblems(problems))
 			fmt.Fprintln(os.Stderr, "Press Enter to re-open the editor and fix the error...")
 			waitForEnter()
 			content = editedContent
@@ -842,3 +938,13 @@ func NewTaxon(name string, level models.TaxonLevel) (*models.Taxon, error) {
 		return edited, nil
 	}
 }
+
+// EditAccount opens a species' long-form account in the editor. The file is
+// plain markdown with no front matter; an empty account gets a starter outline
+// headed by title, such as "Quercus rubra".
+func EditAccount(title, markdown string) (string, error) {
+	if markdown == "" {
+		markdown = fmt.Sprintf("# %s\n\n## Description\n\n## Identification\n\n## Distribution\n", title)
+	}
+	return openEditorMarkdown(markdown)
+}
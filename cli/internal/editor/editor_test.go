@@ -1,6 +1,9 @@
 package editor
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jeff/oaks/cli/internal/models"
@@ -104,7 +107,10 @@ func TestOakEntryRoundTrip(t *testing.T) {
 		Synonyms:            []string{},
 	}
 
-	md := oakEntryToMarkdown(original)
+	md, err := oakEntryToMarkdown(original)
+	if err != nil {
+		t.Fatalf("oakEntryToMarkdown() error = %v", err)
+	}
 	parsed, err := parseOakEntryMarkdown(md)
 	if err != nil {
 		t.Fatalf("parseOakEntryMarkdown() error = %v", err)
@@ -128,19 +134,26 @@ func TestSpeciesSourceRoundTrip(t *testing.T) {
 	rng := "Eastern North America"
 	leaves := "8-20 cm long"
 	url := "https://example.com"
+	pages := "pp. 230-232"
 
 	original := &models.SpeciesSource{
-		ID:             1,
-		ScientificName: "alba",
-		SourceID:       3,
-		LocalNames:     []string{"white oak", "eastern white oak"},
-		Range:          &rng,
-		Leaves:         &leaves,
-		URL:            &url,
-		IsPreferred:    true,
+		ID:              1,
+		ScientificName:  "alba",
+		SourceID:        3,
+		LocalNames:      []string{"white oak", "eastern white oak"},
+		Range:           &rng,
+		Leaves:          &leaves,
+		URL:             &url,
+		Pages:           &pages,
+		FieldPages:      map[string]string{"fruits": "pl. 14"},
+		FieldVisibility: map[string]string{"range": "internal"},
+		IsPreferred:     true,
 	}
 
-	md := speciesSourceToMarkdown(original, "Oak Compendium")
+	md, err := speciesSourceToMarkdown(original, "Oak Compendium")
+	if err != nil {
+		t.Fatalf("speciesSourceToMarkdown() error = %v", err)
+	}
 	parsed, err := parseSpeciesSourceMarkdown(md, original)
 	if err != nil {
 		t.Fatalf("parseSpeciesSourceMarkdown() error = %v", err)
@@ -164,6 +177,22 @@ func TestSpeciesSourceRoundTrip(t *testing.T) {
 	if parsed.IsPreferred != original.IsPreferred {
 		t.Errorf("IsPreferred = %v, want %v", parsed.IsPreferred, original.IsPreferred)
 	}
+	if parsed.Pages == nil || *parsed.Pages != pages || parsed.FieldPages["fruits"] != "pl. 14" {
+		t.Errorf("Pages = %v, FieldPages = %v; want %q and fruits: pl. 14", parsed.Pages, parsed.FieldPages, pages)
+	}
+	if parsed.FieldVisibility["range"] != "internal" {
+		t.Errorf("FieldVisibility = %v, want range: internal", parsed.FieldVisibility)
+	}
+
+	// Removing a field from field_visibility makes it public
+	i := strings.LastIndex(md, "range: internal")
+	md = md[:i] + md[i+len("range: internal"):]
+	if parsed, err = parseSpeciesSourceMarkdown(md, original); err != nil {
+		t.Fatalf("parseSpeciesSourceMarkdown() error = %v", err)
+	}
+	if parsed.FieldVisibility["range"] != "public" {
+		t.Errorf("FieldVisibility after removal = %v, want range: public", parsed.FieldVisibility)
+	}
 }
 
 func TestSourceRoundTrip(t *testing.T) {
@@ -172,19 +201,24 @@ func TestSourceRoundTrip(t *testing.T) {
 	author := "Le Hardÿ de Beaulieu"
 	year := 2023
 	url := "https://oaksoftheworld.fr"
+	successor := int64(7)
 
 	original := &models.Source{
-		ID:          2,
-		SourceType:  "Website",
-		Name:        "Oaks of the World",
-		Description: &desc,
-		Author:      &author,
-		Year:        &year,
-		URL:         &url,
-		Notes:       &notes,
+		ID:           2,
+		SourceType:   "Website",
+		Name:         "Oaks of the World",
+		Description:  &desc,
+		Author:       &author,
+		Year:         &year,
+		URL:          &url,
+		Notes:        &notes,
+		SupersededBy: &successor,
 	}
 
-	md := sourceToMarkdown(original)
+	md, err := sourceToMarkdown(original)
+	if err != nil {
+		t.Fatalf("sourceToMarkdown() error = %v", err)
+	}
 	parsed, err := parseSourceMarkdown(md)
 	if err != nil {
 		t.Fatalf("parseSourceMarkdown() error = %v", err)
@@ -205,4 +239,187 @@ func TestSourceRoundTrip(t *testing.T) {
 	if *parsed.Notes != *original.Notes {
 		t.Errorf("Notes = %q, want %q", *parsed.Notes, *original.Notes)
 	}
+	if parsed.SupersededBy == nil || *parsed.SupersededBy != successor {
+		t.Errorf("SupersededBy = %v, want %d", parsed.SupersededBy, successor)
+	}
+
+	// A source that isn't superseded has no superseded_by line
+	original.SupersededBy = nil
+	if md, err = sourceToMarkdown(original); err != nil {
+		t.Fatalf("sourceToMarkdown() error = %v", err)
+	}
+	if strings.Contains(md, "superseded_by") {
+		t.Errorf("markdown of a current source has superseded_by:\n%s", md)
+	}
+	if parsed, err = parseSourceMarkdown(md); err != nil || parsed.SupersededBy != nil {
+		t.Errorf("parseSourceMarkdown() SupersededBy = %v, %v; want nil", parsed.SupersededBy, err)
+	}
+}
+
+func TestMissingFields(t *testing.T) {
+	author := "Michx."
+	blank := "  "
+	entry := &models.OakEntry{
+		ScientificName: "velutina",
+		Author:         &author,
+		Section:        &blank,
+		Synonyms:       []string{},
+	}
+
+	missing := missingFields(entry, []string{"author", "section", "synonyms", "unknown"})
+	if len(missing) != 2 || missing[0] != "section" || missing[1] != "synonyms" {
+		t.Errorf("missingFields = %v, want [section synonyms]", missing)
+	}
+	if missing := missingFields(entry, nil); len(missing) != 0 {
+		t.Errorf("missingFields(nil) = %v, want none", missing)
+	}
+}
+
+func TestSchemaModeline(t *testing.T) {
+	entry := models.NewOakEntry("alba")
+
+	SchemaDir = t.TempDir()
+	defer func() { SchemaDir = "" }()
+
+	// No modeline until the schema has been dumped
+	if md, _ := oakEntryToMarkdown(entry); strings.Contains(md, "yaml-language-server") {
+		t.Error("unexpected modeline before schema exists")
+	}
+
+	if err := os.WriteFile(filepath.Join(SchemaDir, "oak-entry.schema.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatalf("write schema: %v", err)
+	}
+	md, err := oakEntryToMarkdown(entry)
+	if err != nil {
+		t.Fatalf("oakEntryToMarkdown() error = %v", err)
+	}
+	if !strings.Contains(md, "# yaml-language-server: $schema=") {
+		t.Fatalf("expected modeline, got:\n%s", md)
+	}
+
+	// The modeline is a YAML comment and does not affect parsing
+	parsed, err := parseOakEntryMarkdown(md)
+	if err != nil {
+		t.Fatalf("parseOakEntryMarkdown failed: %v", err)
+	}
+	if parsed.ScientificName != "alba" {
+		t.Errorf("scientific_name = %q, want alba", parsed.ScientificName)
+	}
+}
+
+func TestOakEntryRoundTripTrickyValues(t *testing.T) {
+	author := "(Engelm.) Sarg.: emend. \"Trel.\""
+	pronunciation := "- KWER-kus"
+	parent := "× bebbiana 'Taco'"
+	original := &models.OakEntry{
+		ScientificName: "× bebbiana 'Taco'",
+		Author:         &author,
+		Pronunciation:  &pronunciation,
+		IsHybrid:       true,
+		Parent1:        &parent,
+		Synonyms:       []string{"Q. alba: var. latiloba", "#2", "'quoted'", "[bracketed]", "yes", "123"},
+		ExternalLinks: []models.ExternalLink{
+			{Name: "Wikipedia: English", URL: "https://en.wikipedia.org/wiki/Quercus_#alba", Logo: "wikipedia"},
+		},
+	}
+
+	md, err := oakEntryToMarkdown(original)
+	if err != nil {
+		t.Fatalf("oakEntryToMarkdown() error = %v", err)
+	}
+	parsed, err := parseOakEntryMarkdown(md)
+	if err != nil {
+		t.Fatalf("parseOakEntryMarkdown() error = %v\n%s", err, md)
+	}
+
+	if parsed.ScientificName != original.ScientificName {
+		t.Errorf("ScientificName = %q, want %q", parsed.ScientificName, original.ScientificName)
+	}
+	if parsed.Author == nil || *parsed.Author != author {
+		t.Errorf("Author = %v, want %q", parsed.Author, author)
+	}
+	if parsed.Pronunciation == nil || *parsed.Pronunciation != pronunciation {
+		t.Errorf("Pronunciation = %v, want %q", parsed.Pronunciation, pronunciation)
+	}
+	if parsed.Parent1 == nil || *parsed.Parent1 != parent {
+		t.Errorf("Parent1 = %v, want %q", parsed.Parent1, parent)
+	}
+	if strings.Join(parsed.Synonyms, "|") != strings.Join(original.Synonyms, "|") {
+		t.Errorf("Synonyms = %q, want %q", parsed.Synonyms, original.Synonyms)
+	}
+	if len(parsed.ExternalLinks) != 1 || parsed.ExternalLinks[0] != original.ExternalLinks[0] {
+		t.Errorf("ExternalLinks = %+v, want %+v", parsed.ExternalLinks, original.ExternalLinks)
+	}
+
+	// Genus is editable
+	original.Genus = "Lithocarpus"
+	if md, err = oakEntryToMarkdown(original); err != nil {
+		t.Fatalf("oakEntryToMarkdown() error = %v", err)
+	}
+	if parsed, err = parseOakEntryMarkdown(md); err != nil {
+		t.Fatalf("parseOakEntryMarkdown() error = %v", err)
+	}
+	if parsed.Genus != "Lithocarpus" {
+		t.Errorf("Genus = %q, want Lithocarpus", parsed.Genus)
+	}
+
+	// Unset fields stay unset rather than becoming empty strings
+	if parsed.Section != nil || parsed.ConservationStatus != nil {
+		t.Errorf("unset fields came back set: section %v, conservation_status %v", parsed.Section, parsed.ConservationStatus)
+	}
+}
+
+func TestSourceAndTaxonRoundTripTrickyValues(t *testing.T) {
+	author := "Nixon, K.C. & Muller: \"Flora\""
+	source := &models.Source{ID: 4, SourceType: "book", Name: "Oaks: A Guide — 'Vol. 2'", Author: &author}
+	md, err := sourceToMarkdown(source)
+	if err != nil {
+		t.Fatalf("sourceToMarkdown() error = %v", err)
+	}
+	parsedSource, err := parseSourceMarkdown(md)
+	if err != nil {
+		t.Fatalf("parseSourceMarkdown() error = %v\n%s", err, md)
+	}
+	if parsedSource.Name != source.Name || parsedSource.Author == nil || *parsedSource.Author != author {
+		t.Errorf("source = %q by %v, want %q by %q", parsedSource.Name, parsedSource.Author, source.Name, author)
+	}
+	if parsedSource.URL != nil {
+		t.Errorf("URL = %q, want unset", *parsedSource.URL)
+	}
+
+	parent := "Quercus: sect. *Lobatae*"
+	taxon := &models.Taxon{Name: "& Co.", Level: models.TaxonLevel("section"), Parent: &parent,
+		Links: []models.TaxonLink{{Label: "POWO: record", URL: "https://powo.science.kew.org/?q=a:b"}}}
+	md, err = taxonToMarkdown(taxon)
+	if err != nil {
+		t.Fatalf("taxonToMarkdown() error = %v", err)
+	}
+	parsedTaxon, err := parseTaxonMarkdown(md)
+	if err != nil {
+		t.Fatalf("parseTaxonMarkdown() error = %v\n%s", err, md)
+	}
+	if parsedTaxon.Name != taxon.Name || parsedTaxon.Parent == nil || *parsedTaxon.Parent != parent {
+		t.Errorf("taxon = %q under %v, want %q under %q", parsedTaxon.Name, parsedTaxon.Parent, taxon.Name, parent)
+	}
+	if len(parsedTaxon.Links) != 1 || parsedTaxon.Links[0] != taxon.Links[0] {
+		t.Errorf("Links = %+v, want %+v", parsedTaxon.Links, taxon.Links)
+	}
+}
+
+func TestSpeciesSourceLocalNamesInline(t *testing.T) {
+	ss := &models.SpeciesSource{ScientificName: "alba", SourceID: 3, LocalNames: []string{"white oak", "chêne: blanc", "-x"}}
+	md, err := speciesSourceToMarkdown(ss, "Oaks \"of\" the World")
+	if err != nil {
+		t.Fatalf("speciesSourceToMarkdown() error = %v", err)
+	}
+	if !strings.Contains(md, "local_names: [") {
+		t.Errorf("expected inline local_names, got:\n%s", md)
+	}
+	parsed, err := parseSpeciesSourceMarkdown(md, ss)
+	if err != nil {
+		t.Fatalf("parseSpeciesSourceMarkdown() error = %v\n%s", err, md)
+	}
+	if strings.Join(parsed.LocalNames, "|") != strings.Join(ss.LocalNames, "|") {
+		t.Errorf("LocalNames = %q, want %q", parsed.LocalNames, ss.LocalNames)
+	}
 }
@@ -0,0 +1,68 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// frontmatterLayout arranges the front matter generated from a typed struct
+// for people to edit. Keys are the YAML field names.
+type frontmatterLayout struct {
+	groups   []string          // Keys that start a group, set off by a blank line
+	comments map[string]string // Comment line written above a key, without "# "
+	inline   []string          // Lists written inline, as [a, b]
+}
+
+// writeFrontmatter encodes v, a struct whose field order is the order of the
+// keys, as a front matter block between --- lines. The YAML encoder quotes
+// whatever needs it, so names with colons, quotes, or leading symbols read
+// back unchanged. Empty and null values are left blank so they parse back as
+// unset. modeline, if any, is written first.
+func writeFrontmatter(modeline string, v interface{}, layout frontmatterLayout) (string, error) {
+	var doc yaml.Node
+	if err := doc.Encode(v); err != nil {
+		return "", fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		key, value := doc.Content[i], doc.Content[i+1]
+		if value.Kind == yaml.ScalarNode && value.Value == "" || value.Tag == "!!null" {
+			value.Tag, value.Value, value.Style = "!!null", "", 0
+		}
+		if contains(layout.inline, key.Value) && value.Kind == yaml.SequenceNode {
+			value.Style = yaml.FlowStyle
+		}
+
+		var head string
+		if contains(layout.groups, key.Value) {
+			head = "\n"
+		}
+		if comment, ok := layout.comments[key.Value]; ok {
+			head += "# " + comment
+		}
+		key.HeadComment = head
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return "", fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+
+	return "---\n" + modeline + buf.String() + "---\n", nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
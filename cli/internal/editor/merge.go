@@ -0,0 +1,344 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jeff/oaks/cli/internal/models"
+	"github.com/jeff/oaks/cli/internal/schema"
+)
+
+// Conflict markers in the merge view, as git writes them
+const (
+	markerLocal  = "<<<<<<<"
+	markerBase   = "|||||||"
+	markerSplit  = "======="
+	markerRemote = ">>>>>>>"
+)
+
+// oakEntryMergeFields are the oak entry fields a merge compares: the ones
+// 'oak edit' shows. The rest always take the server's value.
+var oakEntryMergeFields = yamlFieldNames(oakEntryFrontmatter{})
+
+// speciesSourceMergeFields are the species source fields 'oak note' edits
+var speciesSourceMergeFields = []string{
+	"local_names", "is_preferred", "url", "pages", "field_pages", "field_visibility",
+	"range", "growth_habit", "leaves", "flowers", "fruits", "bark", "twigs", "buds",
+	"hardiness_habitat", "miscellaneous", "distinguishing_features",
+}
+
+// mergeLabels name the sides of a merge in the merge view. An empty base
+// means there is no common ancestor, as when importing.
+type mergeLabels struct {
+	local, base, remote string
+}
+
+var editMergeLabels = mergeLabels{
+	local:  "yours (your edit)",
+	base:   "base (before your edit)",
+	remote: "server (saved while you were editing)",
+}
+
+var importMergeLabels = mergeLabels{
+	local:  "database",
+	remote: "imported",
+}
+
+// fieldConflict is a field both sides changed, to different values
+type fieldConflict struct {
+	field               string
+	base, local, remote interface{}
+}
+
+// MergeOakEntry merges an edit of an oak entry with changes saved on the
+// server meanwhile. base is the copy the edit started from, local the edited
+// copy, and remote the server's current one. A field changed on one side
+// only keeps that change; fields both sides changed differently open in
+// $EDITOR as yours/base/server sections to resolve. Returns the merged entry
+// and the fields taken from the server, or a nil entry if the curator
+// canceled by emptying the merge view.
+func MergeOakEntry(base, local, remote *models.OakEntry, validator *schema.Validator) (*models.OakEntry, []string, error) {
+	check := func(e *models.OakEntry) error {
+		if err := validator.ValidateOakEntry(e); err != nil {
+			return err
+		}
+		if problems := oakEntryProblems(e); len(problems) > 0 {
+			return fmt.Errorf("%s", formatProblems(problems))
+		}
+		return nil
+	}
+	return mergeDocument(fmt.Sprintf("oak entry '%s'", remote.ScientificName), oakEntryMergeFields,
+		editMergeLabels, base, local, remote, check)
+}
+
+// MergeSpeciesSource merges an edit of a species' source data with changes
+// saved on the server meanwhile, as MergeOakEntry does. base is nil when the
+// edit created the record and someone else created it first.
+func MergeSpeciesSource(base, local, remote *models.SpeciesSource, sourceName string) (*models.SpeciesSource, []string, error) {
+	if base == nil {
+		base = models.NewSpeciesSource(remote.ScientificName, remote.SourceID)
+	}
+	what := fmt.Sprintf("notes for %s from %s", remote.ScientificName, sourceName)
+	return mergeDocument(what, speciesSourceMergeFields, editMergeLabels, base, local, remote, nil)
+}
+
+// ResolveImportConflicts opens the fields where an imported oak entry
+// disagrees with the stored one in $EDITOR as database/imported sections,
+// and returns the imported entry with the chosen values, or nil if the
+// curator canceled. There is no base, so every differing field is shown.
+func ResolveImportConflicts(existing, imported *models.OakEntry, fields []string) (*models.OakEntry, error) {
+	merged, _, err := mergeDocument(fmt.Sprintf("imported oak entry '%s'", existing.ScientificName), fields,
+		importMergeLabels, nil, existing, imported, nil)
+	return merged, err
+}
+
+// mergeDocument three-way merges the named fields of three copies of a
+// document, starting from remote, and has the curator resolve conflicts in
+// the merge view until check, if any, accepts the result
+func mergeDocument[T any](what string, fields []string, labels mergeLabels, base, local, remote *T, check func(*T) error) (*T, []string, error) {
+	var baseDoc map[string]interface{}
+	if base != nil {
+		var err error
+		if baseDoc, err = toDocument(base); err != nil {
+			return nil, nil, err
+		}
+	}
+	localDoc, err := toDocument(local)
+	if err != nil {
+		return nil, nil, err
+	}
+	remoteDoc, err := toDocument(remote)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged, fromRemote, conflicts := mergeFields(fields, baseDoc, localDoc, remoteDoc)
+	if len(conflicts) == 0 {
+		result, err := fromDocument[T](merged)
+		if err == nil && check != nil {
+			err = check(result)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("merged %s is invalid: %w", what, err)
+		}
+		return result, fromRemote, nil
+	}
+
+	content, err := renderMerge(what, labels, conflicts)
+	if err != nil {
+		return nil, nil, err
+	}
+	for {
+		edited, err := openEditorWithExt(content, ".yaml")
+		if err != nil {
+			return nil, nil, err
+		}
+		if mergeCanceled(edited) {
+			return nil, nil, nil
+		}
+
+		result, err := applyResolutions[T](edited, merged, conflicts)
+		if err == nil && check != nil {
+			err = check(result)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "\nMerge not resolved: %v\n", err)
+			fmt.Fprintln(os.Stderr, "Press Enter to re-open the editor and fix it...")
+			waitForEnter()
+			content = edited
+			continue
+		}
+		return result, fromRemote, nil
+	}
+}
+
+// mergeFields merges each named field of local and remote, JSON-decoded
+// documents, against base. A field only one side changed takes that side's
+// value, and fields not named take remote's. Returns the merged document,
+// the fields whose remote change was taken over local, and the conflicts.
+func mergeFields(fields []string, base, local, remote map[string]interface{}) (merged map[string]interface{}, fromRemote []string, conflicts []fieldConflict) {
+	merged = make(map[string]interface{}, len(remote))
+	for k, v := range remote {
+		merged[k] = v
+	}
+
+	for _, f := range fields {
+		b, l, r := normalizeValue(base[f]), normalizeValue(local[f]), normalizeValue(remote[f])
+		switch {
+		case reflect.DeepEqual(l, r):
+		case base != nil && reflect.DeepEqual(l, b):
+			fromRemote = append(fromRemote, f)
+		case base != nil && reflect.DeepEqual(r, b):
+			merged[f] = local[f]
+		default:
+			conflicts = append(conflicts, fieldConflict{field: f, base: b, local: l, remote: r})
+		}
+	}
+	return merged, fromRemote, conflicts
+}
+
+// normalizeValue makes empty values equal: null, "", and empty lists and
+// maps all read as unset in the editor
+func normalizeValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case string:
+		if x == "" {
+			return nil
+		}
+	case []interface{}:
+		if len(x) == 0 {
+			return nil
+		}
+	case map[string]interface{}:
+		if len(x) == 0 {
+			return nil
+		}
+	}
+	return v
+}
+
+// renderMerge writes the merge view: a YAML document with each conflicting
+// field's values between git-style conflict markers
+func renderMerge(what string, labels mergeLabels, conflicts []fieldConflict) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Merge conflicts in %s\n", what)
+	b.WriteString("#\n")
+	if labels.base != "" {
+		b.WriteString("# Both sides changed these fields. Other changes were merged already.\n")
+	} else {
+		b.WriteString("# These fields differ. Other fields were merged already.\n")
+	}
+	b.WriteString("# For each field, keep the value you want, or write a new one, and delete\n")
+	b.WriteString("# the other values and the marker lines. Values are YAML; null clears a\n")
+	b.WriteString("# field. Delete everything to cancel the merge.\n")
+
+	for _, c := range conflicts {
+		fmt.Fprintf(&b, "\n%s:\n", c.field)
+		sections := []struct {
+			marker, label string
+			value         interface{}
+		}{
+			{markerLocal, labels.local, c.local},
+			{markerBase, labels.base, c.base},
+			{markerSplit, "", c.remote},
+		}
+		for _, s := range sections {
+			if s.marker == markerBase && labels.base == "" {
+				continue
+			}
+			b.WriteString(strings.TrimSpace(s.marker + " " + s.label))
+			b.WriteString("\n")
+			value, err := yaml.Marshal(s.value)
+			if err != nil {
+				return "", fmt.Errorf("failed to encode %s: %w", c.field, err)
+			}
+			for _, line := range strings.Split(strings.TrimRight(string(value), "\n"), "\n") {
+				b.WriteString("  " + line + "\n")
+			}
+		}
+		b.WriteString(markerRemote + " " + labels.remote + "\n")
+	}
+	return b.String(), nil
+}
+
+// mergeCanceled reports whether the merge view was emptied, comments aside
+func mergeCanceled(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+// applyResolutions parses the edited merge view and sets each conflicting
+// field of merged to the value the curator kept
+func applyResolutions[T any](content string, merged map[string]interface{}, conflicts []fieldConflict) (*T, error) {
+	field := ""
+	for _, line := range strings.Split(content, "\n") {
+		if line != "" && line[0] != ' ' && line[0] != '#' && strings.HasSuffix(line, ":") {
+			field = strings.TrimSuffix(line, ":")
+		}
+		for _, marker := range []string{markerLocal, markerBase, markerSplit, markerRemote} {
+			if strings.HasPrefix(line, marker) {
+				return nil, fmt.Errorf("conflict markers remain in %s", field)
+			}
+		}
+	}
+
+	var resolved map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content), &resolved); err != nil {
+		return nil, fmt.Errorf("failed to parse merge: %w", err)
+	}
+
+	want := make(map[string]bool, len(conflicts))
+	for _, c := range conflicts {
+		want[c.field] = true
+	}
+	var unknown []string
+	for f := range resolved {
+		if !want[f] {
+			unknown = append(unknown, f)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%s not in conflict; edit other fields after the merge", strings.Join(unknown, ", "))
+	}
+
+	result := make(map[string]interface{}, len(merged))
+	for k, v := range merged {
+		result[k] = v
+	}
+	for _, c := range conflicts {
+		v, ok := resolved[c.field]
+		if !ok {
+			return nil, fmt.Errorf("%s is missing; write null to clear it", c.field)
+		}
+		result[c.field] = v
+	}
+	return fromDocument[T](result)
+}
+
+// toDocument decodes v's JSON encoding into a generic document
+func toDocument(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %T: %w", v, err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode %T: %w", v, err)
+	}
+	return doc, nil
+}
+
+// fromDocument decodes a generic document back into a T
+func fromDocument[T any](doc map[string]interface{}) (*T, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode merge: %w", err)
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("a value has the wrong type: %w", err)
+	}
+	return &v, nil
+}
+
+// yamlFieldNames lists the YAML keys of a struct's fields, in order
+func yamlFieldNames(v interface{}) []string {
+	t := reflect.TypeOf(v)
+	fields := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		fields = append(fields, name)
+	}
+	return fields
+}
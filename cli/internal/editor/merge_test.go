@@ -0,0 +1,91 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeff/oaks/cli/internal/models"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestMergeFields(t *testing.T) {
+	base := &models.OakEntry{ScientificName: "alba", Author: strPtr("L."), Synonyms: []string{"old"}, Visibility: "published"}
+	local := &models.OakEntry{ScientificName: "alba", Author: strPtr("L. 1753"), Synonyms: []string{"old"}, Section: strPtr("Quercus")}
+	remote := &models.OakEntry{ScientificName: "alba", Author: strPtr("(L.) Sm."), Synonyms: []string{"old", "new"}, Visibility: "draft"}
+
+	baseDoc, _ := toDocument(base)
+	localDoc, _ := toDocument(local)
+	remoteDoc, _ := toDocument(remote)
+	merged, fromRemote, conflicts := mergeFields(oakEntryMergeFields, baseDoc, localDoc, remoteDoc)
+
+	if len(conflicts) != 1 || conflicts[0].field != "author" || conflicts[0].local != "L. 1753" || conflicts[0].remote != "(L.) Sm." {
+		t.Fatalf("conflicts = %+v, want author only", conflicts)
+	}
+	if strings.Join(fromRemote, ",") != "synonyms" {
+		t.Errorf("fromRemote = %v, want [synonyms]", fromRemote)
+	}
+	if merged["section"] != "Quercus" {
+		t.Errorf("section = %v, want the local change", merged["section"])
+	}
+	// Fields the editor does not show keep the server's value
+	if merged["visibility"] != "draft" {
+		t.Errorf("visibility = %v, want draft", merged["visibility"])
+	}
+}
+
+func TestMergeViewRoundTrip(t *testing.T) {
+	conflicts := []fieldConflict{
+		{field: "author", base: "L.", local: "L. 1753", remote: "(L.) Sm."},
+		{field: "synonyms", base: nil, local: []interface{}{"a"}, remote: []interface{}{"b", "c"}},
+	}
+	content, err := renderMerge("oak entry 'alba'", editMergeLabels, conflicts)
+	if err != nil {
+		t.Fatalf("renderMerge failed: %v", err)
+	}
+	for _, want := range []string{"<<<<<<< yours", "||||||| base", "=======", ">>>>>>> server", "  L. 1753", "  - b"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("merge view lacks %q:\n%s", want, content)
+		}
+	}
+
+	merged := map[string]interface{}{"scientific_name": "alba", "author": "(L.) Sm.", "synonyms": []interface{}{"b", "c"}}
+	if _, err := applyResolutions[models.OakEntry](content, merged, conflicts); err == nil || !strings.Contains(err.Error(), "markers remain in author") {
+		t.Errorf("unedited merge error = %v, want markers remaining in author", err)
+	}
+
+	resolved := `# Merge conflicts
+author:
+  L. 1753
+synonyms:
+  - a
+  - c
+`
+	entry, err := applyResolutions[models.OakEntry](resolved, merged, conflicts)
+	if err != nil {
+		t.Fatalf("applyResolutions failed: %v", err)
+	}
+	if entry.Author == nil || *entry.Author != "L. 1753" || strings.Join(entry.Synonyms, ",") != "a,c" {
+		t.Errorf("entry = %+v, want author L. 1753 and synonyms a, c", entry)
+	}
+
+	if _, err := applyResolutions[models.OakEntry]("author: x\n", merged, conflicts); err == nil || !strings.Contains(err.Error(), "synonyms is missing") {
+		t.Errorf("missing field error = %v", err)
+	}
+	if _, err := applyResolutions[models.OakEntry]("author: x\nsynonyms: null\ngenus: Lithocarpus\n", merged, conflicts); err == nil {
+		t.Error("applyResolutions accepted a field not in conflict")
+	}
+	if !mergeCanceled("# Merge conflicts\n#\n\n") || mergeCanceled(resolved) {
+		t.Error("mergeCanceled misjudged an emptied merge view")
+	}
+}
+
+func TestImportMergeViewHasNoBase(t *testing.T) {
+	content, err := renderMerge("imported oak entry 'alba'", importMergeLabels, []fieldConflict{{field: "author", local: "L.", remote: "Michx."}})
+	if err != nil {
+		t.Fatalf("renderMerge failed: %v", err)
+	}
+	if strings.Contains(content, markerBase) || !strings.Contains(content, "<<<<<<< database") || !strings.Contains(content, ">>>>>>> imported") {
+		t.Errorf("import merge view:\n%s", content)
+	}
+}
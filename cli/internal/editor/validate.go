@@ -0,0 +1,232 @@
+package editor
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jeff/oaks/cli/internal/models"
+	"github.com/jeff/oaks/cli/internal/schema"
+)
+
+// Document kinds, named after the schemas in their modelines
+const (
+	KindOakEntry      = "oak-entry"
+	KindSpeciesSource = "species-source"
+	KindSource        = "source"
+	KindTaxon         = "taxon"
+)
+
+// Kinds lists every document kind ValidateFile accepts
+var Kinds = []string{KindOakEntry, KindSpeciesSource, KindSource, KindTaxon}
+
+// Problem is one reason a document would be rejected by the editor
+type Problem struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	if p.Field == "" {
+		return p.Message
+	}
+	return p.Field + " " + p.Message
+}
+
+// formatProblems joins problems one per line, as the editor loops print them
+func formatProblems(problems []Problem) string {
+	lines := make([]string, len(problems))
+	for i, p := range problems {
+		lines[i] = p.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// oakEntryProblems checks the fields of an oak entry the schema file does
+// not: the conservation status code and each external link, as the API does
+func oakEntryProblems(e *models.OakEntry) []Problem {
+	var problems []Problem
+	if e.ConservationStatus != nil && *e.ConservationStatus != "" &&
+		!slices.Contains(models.ConservationStatuses, *e.ConservationStatus) {
+		problems = append(problems, Problem{
+			Field:   "conservation_status",
+			Message: "must be a valid IUCN code (" + strings.Join(models.ConservationStatuses, ", ") + ")",
+		})
+	}
+
+	for i, link := range e.ExternalLinks {
+		field := fmt.Sprintf("external_links[%d]", i)
+		if strings.TrimSpace(link.Name) == "" {
+			problems = append(problems, Problem{Field: field + ".name", Message: "is required"})
+		}
+		if u, err := url.Parse(link.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, Problem{Field: field + ".url", Message: "must be an absolute http or https URL"})
+		}
+		if link.Logo != "" && !slices.Contains(models.ExternalLinkLogos, link.Logo) {
+			problems = append(problems, Problem{
+				Field:   field + ".logo",
+				Message: "must be one of: " + strings.Join(models.ExternalLinkLogos, ", "),
+			})
+		}
+	}
+	return problems
+}
+
+// sourceProblems checks the fields EditSource requires
+func sourceProblems(s *models.Source) []Problem {
+	var problems []Problem
+	if s.Name == "" {
+		problems = append(problems, Problem{Field: "name", Message: "cannot be empty"})
+	}
+	if _, ok := models.NormalizeSourceType(s.SourceType); !ok {
+		problems = append(problems, Problem{
+			Field:   "source_type",
+			Message: "must be one of: " + strings.Join(models.SourceTypes, ", "),
+		})
+	}
+	return problems
+}
+
+// taxonProblems checks the fields NewTaxon requires
+func taxonProblems(t *models.Taxon) []Problem {
+	if t.Name == "" {
+		return []Problem{{Field: "name", Message: "cannot be empty"}}
+	}
+	return nil
+}
+
+// sourceRefPattern matches the "Name (ID: 12)" source line of a species source document
+var sourceRefPattern = regexp.MustCompile(`\(ID:\s*(\d+)\)\s*$`)
+
+// schemaModelinePattern matches the schema name in a yaml-language-server modeline
+var schemaModelinePattern = regexp.MustCompile(`(?m)^#\s*yaml-language-server:\s*\$schema=\S*?([a-z-]+)\.schema\.json\s*$`)
+
+// ValidateFile runs the parsing and validation of the editor loop on a
+// document saved to disk, without opening the editor. Markdown files are
+// parsed as the editor writes them; .yaml and .yml files hold the front
+// matter alone. An empty kind is detected from the schema modeline or the
+// document's fields. The validator is needed for oak entries only.
+//
+// Returns the document kind and its problems; the error is for files that
+// cannot be read or whose kind cannot be told.
+func ValidateFile(path, kind string, validator *schema.Validator) (string, []Problem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	content := string(data)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		content = "---\n" + strings.TrimPrefix(strings.TrimSpace(content), "---") + "\n---\n"
+	}
+
+	fm, _, err := parseFrontmatter(content)
+	if err != nil {
+		return kind, []Problem{{Field: "frontmatter", Message: err.Error()}}, nil
+	}
+	if kind == "" {
+		if kind, err = detectKind(fm); err != nil {
+			return "", nil, err
+		}
+	}
+
+	switch kind {
+	case KindOakEntry:
+		if validator == nil {
+			return kind, nil, fmt.Errorf("a schema is required to validate oak entries")
+		}
+		return kind, validateOakEntryDocument(content, validator), nil
+	case KindSpeciesSource:
+		return kind, validateSpeciesSourceDocument(content, fm), nil
+	case KindSource:
+		source, err := parseSourceMarkdown(content)
+		if err != nil {
+			return kind, []Problem{{Field: "frontmatter", Message: err.Error()}}, nil
+		}
+		return kind, sourceProblems(source), nil
+	case KindTaxon:
+		taxon, err := parseTaxonMarkdown(content)
+		if err != nil {
+			return kind, []Problem{{Field: "frontmatter", Message: err.Error()}}, nil
+		}
+		return kind, taxonProblems(taxon), nil
+	default:
+		return "", nil, fmt.Errorf("unknown document kind %q (must be one of: %s)", kind, strings.Join(Kinds, ", "))
+	}
+}
+
+// detectKind tells a document's kind from its modeline, or failing that
+// from the fields only that kind has
+func detectKind(fm string) (string, error) {
+	if m := schemaModelinePattern.FindStringSubmatch(fm); m != nil {
+		for _, kind := range Kinds {
+			if m[1] == kind {
+				return kind, nil
+			}
+		}
+	}
+
+	var fields map[string]interface{}
+	if err := yaml.Unmarshal([]byte(fm), &fields); err == nil {
+		switch {
+		case fields["scientific_name"] != nil:
+			return KindOakEntry, nil
+		case fields["species"] != nil && fields["source"] != nil:
+			return KindSpeciesSource, nil
+		case fields["source_type"] != nil:
+			return KindSource, nil
+		case fields["level"] != nil:
+			return KindTaxon, nil
+		}
+	}
+	return "", fmt.Errorf("cannot tell the document kind; pass one of: %s", strings.Join(Kinds, ", "))
+}
+
+func validateOakEntryDocument(content string, validator *schema.Validator) []Problem {
+	entry, err := parseOakEntryMarkdown(content)
+	if err != nil {
+		return []Problem{{Field: "frontmatter", Message: err.Error()}}
+	}
+	if err := validator.ValidateOakEntry(entry); err != nil {
+		var problems []Problem
+		for _, fe := range schema.FieldErrors(err) {
+			problems = append(problems, Problem{Field: fe.Field, Message: fe.Message})
+		}
+		return problems
+	}
+	return oakEntryProblems(entry)
+}
+
+// validateSpeciesSourceDocument checks a species source document. The editor
+// takes the species and source from the record being edited; on disk they
+// must name one.
+func validateSpeciesSourceDocument(content, fm string) []Problem {
+	var fmData speciesSourceFrontmatter
+	if err := yaml.Unmarshal([]byte(fm), &fmData); err != nil {
+		return []Problem{{Field: "frontmatter", Message: fmt.Sprintf("failed to parse frontmatter: %v", err)}}
+	}
+
+	var problems []Problem
+	if strings.TrimSpace(fmData.Species) == "" {
+		problems = append(problems, Problem{Field: "species", Message: "cannot be empty"})
+	}
+	original := &models.SpeciesSource{ScientificName: fmData.Species}
+	if m := sourceRefPattern.FindStringSubmatch(fmData.Source); m != nil {
+		original.SourceID, _ = strconv.ParseInt(m[1], 10, 64)
+	} else {
+		problems = append(problems, Problem{Field: "source", Message: `must end with the source ID, as in "Name (ID: 12)"`})
+	}
+
+	if _, err := parseSpeciesSourceMarkdown(content, original); err != nil {
+		problems = append(problems, Problem{Field: "frontmatter", Message: err.Error()})
+	}
+	return problems
+}
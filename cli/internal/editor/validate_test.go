@@ -0,0 +1,151 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jeff/oaks/cli/internal/models"
+	"github.com/jeff/oaks/cli/internal/schema"
+)
+
+func writeDoc(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestValidateFile(t *testing.T) {
+	validator, err := schema.FromFile(filepath.Join("..", "..", "schema", "oak_schema.json"))
+	if err != nil {
+		t.Fatalf("FromFile failed: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		file         string
+		content      string
+		kind         string
+		wantKind     string
+		wantProblems []string // Fields with problems
+	}{
+		{
+			name:     "valid oak entry",
+			file:     "rubra.md",
+			content:  "---\nscientific_name: rubra\nis_hybrid: false\nsynonyms: []\n---\n",
+			wantKind: KindOakEntry,
+		},
+		{
+			name:         "oak entry with empty name",
+			file:         "empty.md",
+			content:      "---\nscientific_name: \"\"\n---\n",
+			wantKind:     KindOakEntry,
+			wantProblems: []string{"scientific_name"},
+		},
+		{
+			name:         "oak entry with a relative link",
+			file:         "links.md",
+			content:      "---\nscientific_name: alba\nexternal_links:\n  - name: Wikipedia\n    url: en.wikipedia.org/wiki/Quercus_alba\n---\n",
+			wantKind:     KindOakEntry,
+			wantProblems: []string{"external_links[0].url"},
+		},
+		{
+			name:         "unclosed frontmatter",
+			file:         "broken.md",
+			content:      "---\nscientific_name: rubra\n",
+			kind:         KindOakEntry,
+			wantKind:     KindOakEntry,
+			wantProblems: []string{"frontmatter"},
+		},
+		{
+			name:         "source missing name and type",
+			file:         "source.md",
+			content:      "---\nid: 3\nsource_type: pamphlet\nname:\n---\n\n# Notes\n",
+			wantKind:     KindSource,
+			wantProblems: []string{"name", "source_type"},
+		},
+		{
+			name:     "taxon yaml file",
+			file:     "section.yaml",
+			content:  "name: Lobatae\nlevel: section\nparent: Quercus\n",
+			wantKind: KindTaxon,
+		},
+		{
+			name:         "species source without source ID",
+			file:         "ss.md",
+			content:      "---\nspecies: alba\nsource: Oaks of the World\nlocal_names: []\n---\n\n# Range\n\nEastern US\n",
+			wantKind:     KindSpeciesSource,
+			wantProblems: []string{"source"},
+		},
+		{
+			name:     "kind from modeline",
+			file:     "modeline.md",
+			content:  "---\n# yaml-language-server: $schema=/home/x/.oak/schemas/taxon.schema.json\nname: Lobatae\nlevel: section\n---\n",
+			wantKind: KindTaxon,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeDoc(t, tt.file, tt.content)
+			kind, problems, err := ValidateFile(path, tt.kind, validator)
+			if err != nil {
+				t.Fatalf("ValidateFile() error = %v", err)
+			}
+			if kind != tt.wantKind {
+				t.Errorf("kind = %q, want %q", kind, tt.wantKind)
+			}
+			if len(problems) != len(tt.wantProblems) {
+				t.Fatalf("problems = %v, want fields %v", problems, tt.wantProblems)
+			}
+			for i, p := range problems {
+				if p.Field != tt.wantProblems[i] {
+					t.Errorf("problem %d field = %q, want %q", i, p.Field, tt.wantProblems[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateFileUnknownKind(t *testing.T) {
+	path := writeDoc(t, "mystery.md", "---\nfoo: bar\n---\n")
+	if _, _, err := ValidateFile(path, "", nil); err == nil {
+		t.Error("expected an error for a document of unknown kind")
+	}
+}
+
+func TestOakEntryProblems(t *testing.T) {
+	status := "XX"
+	entry := &models.OakEntry{
+		ScientificName:     "alba",
+		ConservationStatus: &status,
+		ExternalLinks: []models.ExternalLink{
+			{Name: "Wikipedia", URL: "https://en.wikipedia.org/wiki/Quercus_alba", Logo: "wikipedia"},
+			{Name: " ", URL: "ftp://example.com/alba", Logo: "myspace"},
+			{Name: "Relative", URL: "/wiki/Quercus_alba"},
+		},
+	}
+
+	var fields []string
+	for _, p := range oakEntryProblems(entry) {
+		fields = append(fields, p.Field)
+	}
+	want := []string{
+		"conservation_status",
+		"external_links[1].name", "external_links[1].url", "external_links[1].logo",
+		"external_links[2].url",
+	}
+	if strings.Join(fields, " ") != strings.Join(want, " ") {
+		t.Errorf("problem fields = %v, want %v", fields, want)
+	}
+
+	status = "VU"
+	entry.ExternalLinks = entry.ExternalLinks[:1]
+	if problems := oakEntryProblems(entry); len(problems) != 0 {
+		t.Errorf("unexpected problems: %v", problems)
+	}
+}
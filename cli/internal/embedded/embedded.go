@@ -4,6 +4,8 @@
 package embedded
 
 import (
+	"runtime"
+
 	"github.com/jeff/oaks/api/embed"
 )
 
@@ -13,8 +15,14 @@ type Server = embed.Server
 // Config is an alias for the API's embedded config.
 type Config = embed.Config
 
-// Start creates and starts an embedded API server on a random localhost port.
+// Start creates and starts an embedded API server. Where the platform has
+// unix sockets it serves on one in a private directory, so other users of a
+// shared machine cannot reach it; elsewhere it uses a random localhost port.
+// Connect with client.WithTransport(server.Transport()).
 // This is a convenience wrapper around embed.Start.
 func Start(cfg Config) (*Server, error) {
+	if runtime.GOOS != "windows" {
+		cfg.UnixSocket = true
+	}
 	return embed.Start(cfg)
 }
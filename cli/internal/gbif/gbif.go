@@ -0,0 +1,141 @@
+// Package gbif looks species up in the GBIF backbone taxonomy
+// (https://www.gbif.org): the accepted name and its key, its synonyms, and
+// its IUCN Red List category.
+package gbif
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultURL is GBIF's public API
+const DefaultURL = "https://api.gbif.org/v1"
+
+// Taxonomic statuses of a match
+const (
+	StatusAccepted = "ACCEPTED"
+	StatusSynonym  = "SYNONYM"
+	StatusDoubtful = "DOUBTFUL"
+)
+
+// Match is the backbone name a species name matched
+type Match struct {
+	UsageKey         int64  `json:"usageKey"`
+	AcceptedUsageKey int64  `json:"acceptedUsageKey"` // For a synonym, the accepted name's key
+	ScientificName   string `json:"scientificName"`   // With authorship
+	CanonicalName    string `json:"canonicalName"`
+	Rank             string `json:"rank"`
+	Status           string `json:"status"`
+	Confidence       int    `json:"confidence"`
+	MatchType        string `json:"matchType"` // EXACT or FUZZY; see Client.Match
+}
+
+// Client calls the GBIF API
+type Client struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+}
+
+// New creates a client for the GBIF API at baseURL, normally DefaultURL
+func New(baseURL, userAgent string) *Client {
+	return &Client{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		userAgent: userAgent,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SpeciesURL is the GBIF page of the species with this key
+func SpeciesURL(key int64) string {
+	return "https://www.gbif.org/species/" + strconv.FormatInt(key, 10)
+}
+
+// Match matches a full scientific name, such as "Quercus alba", strictly
+// against the backbone. Returns nil if there is no match at species rank or
+// below.
+func (c *Client) Match(ctx context.Context, name string) (*Match, error) {
+	var m Match
+	query := url.Values{"name": {name}, "strict": {"true"}}
+	if _, err := c.get(ctx, "/species/match?"+query.Encode(), &m); err != nil {
+		return nil, err
+	}
+	if m.UsageKey == 0 || m.MatchType == "NONE" || m.MatchType == "HIGHERRANK" {
+		return nil, nil
+	}
+	return &m, nil
+}
+
+// Synonyms returns the canonical names of the synonyms of the name with
+// this key
+func (c *Client) Synonyms(ctx context.Context, key int64) ([]string, error) {
+	var names []string
+	for offset := 0; ; {
+		var page struct {
+			Results []struct {
+				CanonicalName string `json:"canonicalName"`
+			} `json:"results"`
+			EndOfRecords bool `json:"endOfRecords"`
+		}
+		path := fmt.Sprintf("/species/%d/synonyms?limit=100&offset=%d", key, offset)
+		if _, err := c.get(ctx, path, &page); err != nil {
+			return nil, err
+		}
+		for _, r := range page.Results {
+			if r.CanonicalName != "" {
+				names = append(names, r.CanonicalName)
+			}
+		}
+		if page.EndOfRecords || len(page.Results) == 0 {
+			return names, nil
+		}
+		offset += len(page.Results)
+	}
+}
+
+// RedListCategory returns the IUCN Red List code, such as "LC", of the
+// name with this key, or "" if it has not been assessed
+func (c *Client) RedListCategory(ctx context.Context, key int64) (string, error) {
+	var category struct {
+		Code string `json:"code"`
+	}
+	found, err := c.get(ctx, fmt.Sprintf("/species/%d/iucnRedListCategory", key), &category)
+	if err != nil || !found {
+		return "", err
+	}
+	return category.Code, nil
+}
+
+// get decodes the JSON response to path into v. Reports false, leaving v
+// alone, if GBIF has nothing there.
+func (c *Client) get(ctx context.Context, path string, v any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create GBIF request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("GBIF request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNoContent:
+		return false, nil
+	case resp.StatusCode != http.StatusOK:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return false, fmt.Errorf("GBIF returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, fmt.Errorf("failed to decode GBIF response: %w", err)
+	}
+	return true, nil
+}
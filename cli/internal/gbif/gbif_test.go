@@ -0,0 +1,66 @@
+package gbif
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/species/match":
+			if r.URL.Query().Get("strict") != "true" {
+				t.Errorf("match is not strict: %s", r.URL.RawQuery)
+			}
+			switch r.URL.Query().Get("name") {
+			case "Quercus alba":
+				fmt.Fprint(w, `{"usageKey": 2880539, "scientificName": "Quercus alba L.", "canonicalName": "Quercus alba",
+					"rank": "SPECIES", "status": "ACCEPTED", "confidence": 99, "matchType": "EXACT"}`)
+			default:
+				fmt.Fprint(w, `{"confidence": 100, "matchType": "NONE"}`)
+			}
+		case "/species/2880539/synonyms":
+			if r.URL.Query().Get("offset") == "0" {
+				fmt.Fprint(w, `{"results": [{"canonicalName": "Quercus alba var. repanda"}], "endOfRecords": false}`)
+			} else {
+				fmt.Fprint(w, `{"results": [{"canonicalName": "Quercus ramosa"}], "endOfRecords": true}`)
+			}
+		case "/species/2880539/iucnRedListCategory":
+			fmt.Fprint(w, `{"category": "LEAST_CONCERN", "code": "LC"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	c := New(srv.URL, "test")
+	ctx := context.Background()
+
+	m, err := c.Match(ctx, "Quercus alba")
+	if err != nil {
+		t.Fatalf("Match failed: %v", err)
+	}
+	if m == nil || m.UsageKey != 2880539 || m.Status != StatusAccepted {
+		t.Fatalf("Match(Quercus alba) = %+v", m)
+	}
+	if m, err := c.Match(ctx, "Quercus nowhere"); err != nil || m != nil {
+		t.Errorf("Match(Quercus nowhere) = %+v, %v; want nil", m, err)
+	}
+
+	synonyms, err := c.Synonyms(ctx, m.UsageKey)
+	if err != nil {
+		t.Fatalf("Synonyms failed: %v", err)
+	}
+	if len(synonyms) != 2 || synonyms[1] != "Quercus ramosa" {
+		t.Errorf("Synonyms() = %v, want both pages", synonyms)
+	}
+
+	if code, err := c.RedListCategory(ctx, m.UsageKey); err != nil || code != "LC" {
+		t.Errorf("RedListCategory() = %q, %v; want LC", code, err)
+	}
+	if code, err := c.RedListCategory(ctx, 1); err != nil || code != "" {
+		t.Errorf("RedListCategory(unassessed) = %q, %v; want none", code, err)
+	}
+}
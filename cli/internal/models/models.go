@@ -1,5 +1,10 @@
 package models
 
+import (
+	"strings"
+	"time"
+)
+
 // TaxonLevel represents the hierarchical level of a taxon
 type TaxonLevel string
 
@@ -10,6 +15,16 @@ const (
 	TaxonLevelComplex    TaxonLevel = "complex"
 )
 
+// TaxonLevelDef defines one configurable rank in the taxonomy below genus.
+// Levels are ordered by Rank (lowest is closest to genus).
+type TaxonLevelDef struct {
+	Name       string  `json:"name" yaml:"name"`
+	Rank       int     `json:"rank" yaml:"rank"`
+	Plural     *string `json:"plural,omitempty" yaml:"plural,omitempty"`           // e.g., "subgenera"; used as the taxa import key
+	EntryField *string `json:"entry_field,omitempty" yaml:"entry_field,omitempty"` // Species column for this rank, if any
+	TaxaCount  int     `json:"taxa_count" yaml:"taxa_count"`
+}
+
 // TaxonLink represents a labeled external link for a taxon
 type TaxonLink struct {
 	Label string `json:"label" yaml:"label"` // e.g., "iNaturalist", "Wikipedia"
@@ -23,11 +38,28 @@ type ExternalLink struct {
 	Logo string `json:"logo" yaml:"logo"` // Identifier for bundled SVG icon (e.g., "wikipedia", "inaturalist")
 }
 
+// ExternalLinkLogos are the bundled icons an external link's Logo may name
+var ExternalLinkLogos = []string{"wikipedia", "inaturalist", "usda", "gbif", "powo", "generic"}
+
+// ConservationStatuses are the IUCN Red List codes accepted for conservation_status
+var ConservationStatuses = []string{"EX", "EW", "CR", "EN", "VU", "NT", "LC", "DD", "NE"}
+
+// DefaultGenus is the genus of entries and taxa created without one
+const DefaultGenus = "Quercus"
+
+// Genus is a genus tracked by the database
+type Genus struct {
+	Name       string   `json:"name" yaml:"name"`
+	CommonName *string  `json:"common_name,omitempty" yaml:"common_name,omitempty"`
+	Subgenera  []string `json:"subgenera" yaml:"subgenera"` // Valid subgenus names; empty allows any
+}
+
 // Taxon represents a taxonomic rank in the reference table
-// Hierarchy: Genus (Quercus) → Subgenus → Section → Subsection → Complex → Species
+// Hierarchy: Genus (e.g. Quercus) → Subgenus → Section → Subsection → Complex → Species
 type Taxon struct {
 	Name   string      `json:"name" yaml:"name"`
 	Level  TaxonLevel  `json:"level" yaml:"level"`
+	Genus  string      `json:"genus,omitempty" yaml:"genus,omitempty"`   // Defaults to DefaultGenus
 	Parent *string     `json:"parent,omitempty" yaml:"parent,omitempty"` // Parent taxon name
 	Author *string     `json:"author,omitempty" yaml:"author,omitempty"` // Taxonomic authority
 	Notes  *string     `json:"notes,omitempty" yaml:"notes,omitempty"`
@@ -37,22 +69,38 @@ type Taxon struct {
 // SpeciesSource represents source-attributed descriptive data for a species
 // One row = everything source X says about species Y
 type SpeciesSource struct {
-	ID               int64    `json:"id" yaml:"id"`
-	ScientificName   string   `json:"scientific_name" yaml:"scientific_name"`
-	SourceID         int64    `json:"source_id" yaml:"source_id"`
-	LocalNames       []string `json:"local_names,omitempty" yaml:"local_names,omitempty"`
-	Range            *string  `json:"range,omitempty" yaml:"range,omitempty"`
-	GrowthHabit      *string  `json:"growth_habit,omitempty" yaml:"growth_habit,omitempty"`
-	Leaves           *string  `json:"leaves,omitempty" yaml:"leaves,omitempty"`
-	Flowers          *string  `json:"flowers,omitempty" yaml:"flowers,omitempty"`
-	Fruits           *string  `json:"fruits,omitempty" yaml:"fruits,omitempty"`
-	Bark             *string  `json:"bark,omitempty" yaml:"bark,omitempty"`
-	Twigs            *string  `json:"twigs,omitempty" yaml:"twigs,omitempty"`
-	Buds             *string  `json:"buds,omitempty" yaml:"buds,omitempty"`
-	HardinessHabitat *string  `json:"hardiness_habitat,omitempty" yaml:"hardiness_habitat,omitempty"`
-	Miscellaneous    *string  `json:"miscellaneous,omitempty" yaml:"miscellaneous,omitempty"`
-	URL              *string  `json:"url,omitempty" yaml:"url,omitempty"`
-	IsPreferred      bool     `json:"is_preferred" yaml:"is_preferred"`
+	ID                     int64    `json:"id" yaml:"id"`
+	ScientificName         string   `json:"scientific_name" yaml:"scientific_name"`
+	SourceID               int64    `json:"source_id" yaml:"source_id"`
+	LocalNames             []string `json:"local_names,omitempty" yaml:"local_names,omitempty"`
+	Range                  *string  `json:"range,omitempty" yaml:"range,omitempty"`
+	GrowthHabit            *string  `json:"growth_habit,omitempty" yaml:"growth_habit,omitempty"`
+	Leaves                 *string  `json:"leaves,omitempty" yaml:"leaves,omitempty"`
+	Flowers                *string  `json:"flowers,omitempty" yaml:"flowers,omitempty"`
+	Fruits                 *string  `json:"fruits,omitempty" yaml:"fruits,omitempty"`
+	Bark                   *string  `json:"bark,omitempty" yaml:"bark,omitempty"`
+	Twigs                  *string  `json:"twigs,omitempty" yaml:"twigs,omitempty"`
+	Buds                   *string  `json:"buds,omitempty" yaml:"buds,omitempty"`
+	HardinessHabitat       *string  `json:"hardiness_habitat,omitempty" yaml:"hardiness_habitat,omitempty"`
+	Miscellaneous          *string  `json:"miscellaneous,omitempty" yaml:"miscellaneous,omitempty"`
+	DistinguishingFeatures *string  `json:"distinguishing_features,omitempty" yaml:"distinguishing_features,omitempty"`
+	URL                    *string  `json:"url,omitempty" yaml:"url,omitempty"`
+	// Pages and FieldPages locate the data in a printed source; FieldPages is
+	// keyed by text field name, e.g. {"fruits": "pl. 23"}
+	Pages       *string           `json:"pages,omitempty" yaml:"pages,omitempty"`
+	FieldPages  map[string]string `json:"field_pages,omitempty" yaml:"field_pages,omitempty"`
+	IsPreferred bool              `json:"is_preferred" yaml:"is_preferred"`
+	// FieldVisibility lists the text fields withheld from the public, e.g.
+	// {"range": "internal"}; fields not listed are public
+	FieldVisibility map[string]string `json:"field_visibility,omitempty" yaml:"field_visibility,omitempty"`
+
+	// Transcription provenance, for text typed or OCR'd from print
+	Transcriber         *string    `json:"transcriber,omitempty" yaml:"transcriber,omitempty"`
+	TranscriptionMethod *string    `json:"transcription_method,omitempty" yaml:"transcription_method,omitempty"` // manual, ocr, or import
+	OCRConfidence       *float64   `json:"ocr_confidence,omitempty" yaml:"ocr_confidence,omitempty"`             // 0-1
+	Verified            bool       `json:"verified" yaml:"verified"`
+	VerifiedAt          *time.Time `json:"verified_at,omitempty" yaml:"verified_at,omitempty"`
+	VerifiedBy          *string    `json:"verified_by,omitempty" yaml:"verified_by,omitempty"`
 }
 
 // OakEntry represents an Oak taxonomic entry (species-intrinsic data)
@@ -60,9 +108,13 @@ type SpeciesSource struct {
 type OakEntry struct {
 	ScientificName     string  `json:"scientific_name" yaml:"scientific_name"`
 	Author             *string `json:"author,omitempty" yaml:"author,omitempty"`
+	Pronunciation      *string `json:"pronunciation,omitempty" yaml:"pronunciation,omitempty"`
 	IsHybrid           bool    `json:"is_hybrid" yaml:"is_hybrid"`
 	ConservationStatus *string `json:"conservation_status,omitempty" yaml:"conservation_status,omitempty"`
 
+	// Genus the entry belongs to; defaults to DefaultGenus
+	Genus string `json:"genus,omitempty" yaml:"genus,omitempty"`
+
 	// Taxonomy (flat columns, validated against taxa reference table)
 	Subgenus   *string `json:"subgenus,omitempty" yaml:"subgenus,omitempty"`
 	Section    *string `json:"section,omitempty" yaml:"section,omitempty"`
@@ -81,8 +133,17 @@ type OakEntry struct {
 
 	// External reference links
 	ExternalLinks []ExternalLink `json:"external_links,omitempty" yaml:"external_links,omitempty"`
+
+	// Visibility is "draft" or "published"; drafts are hidden from public reads
+	Visibility string `json:"visibility,omitempty" yaml:"visibility,omitempty"`
 }
 
+// Species visibility values
+const (
+	VisibilityPublished = "published"
+	VisibilityDraft     = "draft"
+)
+
 // NewOakEntry creates a new empty OakEntry with the given scientific name
 func NewOakEntry(scientificName string) *OakEntry {
 	return &OakEntry{
@@ -108,18 +169,63 @@ func NewSpeciesSource(scientificName string, sourceID int64) *SpeciesSource {
 
 // Source represents a source reference
 type Source struct {
-	ID          int64   `json:"id" yaml:"id"`
-	SourceType  string  `json:"source_type" yaml:"source_type"`
-	Name        string  `json:"name" yaml:"name"`
-	Description *string `json:"description,omitempty" yaml:"description,omitempty"`
-	Author      *string `json:"author,omitempty" yaml:"author,omitempty"`
-	Year        *int    `json:"year,omitempty" yaml:"year,omitempty"`
-	URL         *string `json:"url,omitempty" yaml:"url,omitempty"`
-	ISBN        *string `json:"isbn,omitempty" yaml:"isbn,omitempty"`
-	DOI         *string `json:"doi,omitempty" yaml:"doi,omitempty"`
-	Notes       *string `json:"notes,omitempty" yaml:"notes,omitempty"`
-	License     *string `json:"license,omitempty" yaml:"license,omitempty"`
-	LicenseURL  *string `json:"license_url,omitempty" yaml:"license_url,omitempty"`
+	ID           int64   `json:"id" yaml:"id"`
+	SourceType   string  `json:"source_type" yaml:"source_type"`
+	Name         string  `json:"name" yaml:"name"`
+	Description  *string `json:"description,omitempty" yaml:"description,omitempty"`
+	Author       *string `json:"author,omitempty" yaml:"author,omitempty"`
+	Year         *int    `json:"year,omitempty" yaml:"year,omitempty"`
+	URL          *string `json:"url,omitempty" yaml:"url,omitempty"`
+	ISBN         *string `json:"isbn,omitempty" yaml:"isbn,omitempty"`
+	DOI          *string `json:"doi,omitempty" yaml:"doi,omitempty"`
+	Notes        *string `json:"notes,omitempty" yaml:"notes,omitempty"`
+	License      *string `json:"license,omitempty" yaml:"license,omitempty"`
+	LicenseURL   *string `json:"license_url,omitempty" yaml:"license_url,omitempty"`
+	SupersededBy *int64  `json:"superseded_by,omitempty" yaml:"superseded_by,omitempty"` // Source that replaces this one
+}
+
+// Source types. Writes must use one of these; NormalizeSourceType maps
+// other spellings onto them.
+const (
+	SourceTypeBook                = "book"
+	SourceTypePaper               = "paper"
+	SourceTypeWebsite             = "website"
+	SourceTypeHerbarium           = "herbarium"
+	SourceTypePersonalObservation = "personal-observation"
+	SourceTypeDatabase            = "database"
+)
+
+// SourceTypes lists the valid source types
+var SourceTypes = []string{
+	SourceTypeBook, SourceTypePaper, SourceTypeWebsite,
+	SourceTypeHerbarium, SourceTypePersonalObservation, SourceTypeDatabase,
+}
+
+// sourceTypeAliases maps older free-text source types onto the canonical ones
+var sourceTypeAliases = map[string]string{
+	"article":     SourceTypePaper,
+	"journal":     SourceTypePaper,
+	"web":         SourceTypeWebsite,
+	"web-site":    SourceTypeWebsite,
+	"observation": SourceTypePersonalObservation,
+	"dataset":     SourceTypeDatabase,
+}
+
+// NormalizeSourceType returns the canonical source type for s, ignoring case
+// and treating spaces and underscores as hyphens ("Personal Observation" is
+// personal-observation). ok is false if s matches no source type.
+func NormalizeSourceType(s string) (canonical string, ok bool) {
+	key := strings.ToLower(strings.TrimSpace(s))
+	key = strings.NewReplacer(" ", "-", "_", "-").Replace(key)
+	if alias, found := sourceTypeAliases[key]; found {
+		return alias, true
+	}
+	for _, t := range SourceTypes {
+		if key == t {
+			return t, true
+		}
+	}
+	return s, false
 }
 
 // NewSource creates a new Source with the given type and name
@@ -0,0 +1,81 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBody is how much of a refusal's response body is kept as the
+// entry's LastError.
+const maxErrorBody = 500
+
+// SendFunc sends a queued write and returns the response status and body,
+// or an error if the server could not be reached.
+type SendFunc func(ctx context.Context, e *Entry) (status int, body string, err error)
+
+// FlushResult counts what Flush did.
+type FlushResult struct {
+	Sent    int // Accepted by the server and removed
+	Failed  int // Refused by the server and marked failed
+	Waiting int // Still pending: unreachable, or not due yet
+}
+
+// Flush resends the pending writes for profile in the order they were
+// queued, stopping at the first whose backoff has not expired unless all is
+// set. A 2xx response removes the write, as does a 404 to a DELETE, since
+// the record is already gone; a Retryable status or an unreachable server
+// postpones it and stops, so later writes are never applied before earlier
+// ones; any other status marks it failed.
+func Flush(ctx context.Context, q *Queue, profile string, all bool, send SendFunc) (*FlushResult, error) {
+	pending, err := q.Pending(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FlushResult{Waiting: len(pending)}
+	now := q.now()
+	for _, e := range pending {
+		if !all && e.NextAttempt.After(now) {
+			break
+		}
+		status, body, err := send(ctx, e)
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			return result, q.Postpone(e.ID, err.Error())
+		}
+		if Retryable(status) {
+			return result, q.Postpone(e.ID, http.StatusText(status))
+		}
+
+		result.Waiting--
+		if status/100 == 2 || (e.Method == http.MethodDelete && status == http.StatusNotFound) {
+			if err := q.Remove(e.ID); err != nil {
+				return result, err
+			}
+			result.Sent++
+			continue
+		}
+		if err := q.Fail(e.ID, refusal(status, body)); err != nil {
+			return result, err
+		}
+		result.Failed++
+	}
+	return result, nil
+}
+
+// refusal describes a response refusing a queued write.
+func refusal(status int, body string) string {
+	body = strings.TrimSpace(body)
+	if len(body) > maxErrorBody {
+		body = body[:maxErrorBody] + "..."
+	}
+	msg := fmt.Sprintf("%d %s", status, http.StatusText(status))
+	if body != "" {
+		msg += ": " + body
+	}
+	return msg
+}
@@ -0,0 +1,208 @@
+// Package queue records writes to remote profiles in a local SQLite
+// database before they are sent, so a write cut off by a dropped connection
+// is retried on a later run instead of lost.
+//
+// Transport does the recording. A write stays queued only when the server
+// could not be reached or answered 429 or 502-504; any other response, success or
+// not, was seen by the command that sent it and clears the entry. Flush
+// resends what is left, oldest first, with exponential backoff between
+// attempts.
+package queue
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // SQLite driver
+)
+
+// Status is where a queued write stands.
+type Status string
+
+// Queued write statuses
+const (
+	StatusPending Status = "pending" // Not confirmed by the server; retried after NextAttempt
+	StatusFailed  Status = "failed"  // Refused by the server on retry; kept until dropped
+)
+
+// Backoff limits
+const (
+	BackoffBase = 30 * time.Second
+	BackoffMax  = time.Hour
+)
+
+// Entry is one queued write. Path is relative to the profile's URL; the API
+// key is never stored and the profile's current key is used on retry.
+type Entry struct {
+	ID          int64
+	Profile     string
+	Method      string
+	Path        string
+	ContentType string
+	IfMatch     string
+	Body        string
+	Status      Status
+	Attempts    int
+	NextAttempt time.Time
+	LastError   string
+	CreatedAt   time.Time
+}
+
+// Queue is the outbound write queue database.
+type Queue struct {
+	conn *sql.DB
+	now  func() time.Time
+}
+
+// Open opens the queue database at path, creating it if needed.
+func Open(path string) (*Queue, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create queue directory: %w", err)
+	}
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open queue: %w", err)
+	}
+	_, err = conn.Exec(`CREATE TABLE IF NOT EXISTS pending_writes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		profile TEXT NOT NULL,
+		method TEXT NOT NULL,
+		path TEXT NOT NULL,
+		content_type TEXT NOT NULL DEFAULT '',
+		if_match TEXT NOT NULL DEFAULT '',
+		body TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL DEFAULT 'pending',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at TEXT NOT NULL,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL
+	)`)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to initialize queue: %w", err)
+	}
+	return &Queue{conn: conn, now: time.Now}, nil
+}
+
+// Close closes the queue database.
+func (q *Queue) Close() error {
+	return q.conn.Close()
+}
+
+// Add records a pending write, due immediately, and returns its ID.
+func (q *Queue) Add(e *Entry) (int64, error) {
+	now := q.now().UTC()
+	res, err := q.conn.Exec(
+		`INSERT INTO pending_writes (profile, method, path, content_type, if_match, body, status, next_attempt_at, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.Profile, e.Method, e.Path, e.ContentType, e.IfMatch, e.Body, StatusPending,
+		now.Format(time.RFC3339), now.Format(time.RFC3339),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue write: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// Remove deletes a write from the queue.
+func (q *Queue) Remove(id int64) error {
+	if _, err := q.conn.Exec(`DELETE FROM pending_writes WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to remove queued write: %w", err)
+	}
+	return nil
+}
+
+// RemoveAll deletes every write for profile ("" for all profiles) with the
+// given status ("" for any) and returns how many were deleted.
+func (q *Queue) RemoveAll(profile string, status Status) (int, error) {
+	res, err := q.conn.Exec(
+		`DELETE FROM pending_writes WHERE (? = '' OR profile = ?) AND (? = '' OR status = ?)`,
+		profile, profile, status, status,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove queued writes: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// Postpone counts a failed attempt to send a write and schedules the next
+// one after Backoff.
+func (q *Queue) Postpone(id int64, reason string) error {
+	var attempts int
+	if err := q.conn.QueryRow(`SELECT attempts FROM pending_writes WHERE id = ?`, id).Scan(&attempts); err != nil {
+		return fmt.Errorf("failed to read queued write: %w", err)
+	}
+	attempts++
+	next := q.now().UTC().Add(Backoff(attempts))
+	_, err := q.conn.Exec(
+		`UPDATE pending_writes SET attempts = ?, next_attempt_at = ?, last_error = ? WHERE id = ?`,
+		attempts, next.Format(time.RFC3339), reason, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to postpone queued write: %w", err)
+	}
+	return nil
+}
+
+// Fail marks a write as refused by the server so it is no longer retried.
+func (q *Queue) Fail(id int64, reason string) error {
+	_, err := q.conn.Exec(
+		`UPDATE pending_writes SET status = ?, attempts = attempts + 1, last_error = ? WHERE id = ?`,
+		StatusFailed, reason, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark queued write failed: %w", err)
+	}
+	return nil
+}
+
+// List returns the writes queued for profile ("" for all profiles), oldest
+// first.
+func (q *Queue) List(profile string) ([]*Entry, error) {
+	return q.query(`WHERE ? = '' OR profile = ?`, profile, profile)
+}
+
+// Pending returns the pending writes for profile, oldest first.
+func (q *Queue) Pending(profile string) ([]*Entry, error) {
+	return q.query(`WHERE profile = ? AND status = ?`, profile, StatusPending)
+}
+
+func (q *Queue) query(where string, args ...interface{}) ([]*Entry, error) {
+	rows, err := q.conn.Query(
+		`SELECT id, profile, method, path, content_type, if_match, body, status, attempts, next_attempt_at, last_error, created_at
+		 FROM pending_writes `+where+` ORDER BY id`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queued writes: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*Entry
+	for rows.Next() {
+		var e Entry
+		var next, created string
+		if err := rows.Scan(&e.ID, &e.Profile, &e.Method, &e.Path, &e.ContentType, &e.IfMatch, &e.Body,
+			&e.Status, &e.Attempts, &next, &e.LastError, &created); err != nil {
+			return nil, fmt.Errorf("failed to scan queued write: %w", err)
+		}
+		e.NextAttempt, _ = time.Parse(time.RFC3339, next)
+		e.CreatedAt, _ = time.Parse(time.RFC3339, created)
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// Backoff is the wait before the next attempt after the given number of
+// failed ones: BackoffBase doubling per attempt, up to BackoffMax.
+func Backoff(attempts int) time.Duration {
+	d := BackoffBase
+	for i := 1; i < attempts && d < BackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, BackoffMax)
+}
@@ -0,0 +1,214 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+func testQueue(t *testing.T) *Queue {
+	t.Helper()
+	q, err := Open(filepath.Join(t.TempDir(), "queue.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { q.Close() })
+	return q
+}
+
+// flakyServer answers with status while down is set and 201 once it is
+// cleared.
+type flakyServer struct {
+	mu     sync.Mutex
+	down   bool
+	status int
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.down {
+		w.WriteHeader(s.status)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func TestTransportKeepsUnsentWrites(t *testing.T) {
+	q := testQueue(t)
+	srv := &flakyServer{down: true, status: http.StatusServiceUnavailable}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	tr := &Transport{Queue: q, BaseURL: ts.URL, Profile: "prod"}
+	client := &http.Client{Transport: tr}
+	post := func(ctx context.Context, path string) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, ts.URL+path, strings.NewReader(`{"a":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("POST %s failed: %v", path, err)
+		}
+		resp.Body.Close()
+	}
+
+	// The same request retried reuses its entry; reads are not queued
+	post(t.Context(), "/api/v1/species")
+	post(t.Context(), "/api/v1/species")
+	post(oakclient.WithReadOnly(t.Context()), "/api/v1/admin/query")
+	if resp, err := client.Get(ts.URL + "/api/v1/species"); err == nil {
+		resp.Body.Close()
+	}
+
+	entries, err := q.List("")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(entries) != 1 || tr.Left() != 1 {
+		t.Fatalf("queued %d entries (transport left %d), want 1", len(entries), tr.Left())
+	}
+	e := entries[0]
+	if e.Profile != "prod" || e.Method != http.MethodPost || e.Path != "/api/v1/species" ||
+		e.Body != `{"a":1}` || e.ContentType != "application/json" || e.Attempts != 2 || e.Status != StatusPending {
+		t.Errorf("entry = %+v", e)
+	}
+	if !e.NextAttempt.After(time.Now()) {
+		t.Errorf("NextAttempt = %v, want in the future", e.NextAttempt)
+	}
+
+	// An answered write is cleared
+	srv.mu.Lock()
+	srv.down = false
+	srv.mu.Unlock()
+	post(t.Context(), "/api/v1/species")
+	entries, _ = q.List("")
+	if len(entries) != 0 || tr.Left() != 0 {
+		t.Errorf("after success queued %d entries (transport left %d), want none", len(entries), tr.Left())
+	}
+}
+
+func TestTransportDropsWritesThatMayHaveApplied(t *testing.T) {
+	q := testQueue(t)
+
+	// The connection drops after the request was sent
+	hangUp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer hangUp.Close()
+	// A proxy gives up waiting for the server
+	gatewayTimeout := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer gatewayTimeout.Close()
+	// Nothing listens, so the request is never sent
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	for _, tt := range []struct {
+		url      string
+		wantKept bool
+	}{
+		{hangUp.URL, false},
+		{gatewayTimeout.URL, false},
+		{unreachable.URL, true},
+	} {
+		tr := &Transport{Queue: q, BaseURL: tt.url, Profile: "prod"}
+		req, _ := http.NewRequestWithContext(t.Context(), http.MethodPut, tt.url+"/api/v1/species/alba", strings.NewReader(`{}`))
+		if resp, err := (&http.Client{Transport: tr}).Do(req); err == nil {
+			resp.Body.Close()
+		}
+		if kept := tr.Left() == 1; kept != tt.wantKept {
+			t.Errorf("%s: kept = %v, want %v", tt.url, kept, tt.wantKept)
+		}
+	}
+
+	if entries, _ := q.List(""); len(entries) != 1 {
+		t.Errorf("queued %d entries, want only the unsent write", len(entries))
+	}
+}
+
+func TestFlush(t *testing.T) {
+	q := testQueue(t)
+	for _, e := range []*Entry{
+		{Profile: "prod", Method: http.MethodPut, Path: "/api/v1/species/alba", Body: "{}"},
+		{Profile: "prod", Method: http.MethodDelete, Path: "/api/v1/species/gone"},
+		{Profile: "prod", Method: http.MethodPost, Path: "/api/v1/species", Body: `{"scientific_name":"rubra"}`},
+		{Profile: "staging", Method: http.MethodPost, Path: "/api/v1/sources"},
+	} {
+		if _, err := q.Add(e); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	// Unreachable: the first write is postponed and the rest wait behind it
+	var sent []string
+	unreachable := func(ctx context.Context, e *Entry) (int, string, error) {
+		sent = append(sent, e.Method+" "+e.Path)
+		return 0, "", errors.New("connection refused")
+	}
+	result, err := Flush(t.Context(), q, "prod", false, unreachable)
+	if err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if len(sent) != 1 || result.Waiting != 3 || result.Sent != 0 {
+		t.Errorf("unreachable flush sent %v, result %+v", sent, result)
+	}
+
+	// Backoff holds the postponed write, and those behind it, until all is set
+	sent = nil
+	if _, err := Flush(t.Context(), q, "prod", false, unreachable); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if len(sent) != 0 {
+		t.Errorf("flush before backoff expired sent %v, want nothing", sent)
+	}
+
+	answers := map[string]int{
+		"PUT /api/v1/species/alba":    http.StatusOK,
+		"DELETE /api/v1/species/gone": http.StatusNotFound,
+		"POST /api/v1/species":        http.StatusConflict,
+	}
+	result, err = Flush(t.Context(), q, "prod", true, func(ctx context.Context, e *Entry) (int, string, error) {
+		return answers[e.Method+" "+e.Path], `{"error":"exists"}`, nil
+	})
+	if err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if result.Sent != 2 || result.Failed != 1 || result.Waiting != 0 {
+		t.Errorf("flush result = %+v, want 2 sent, 1 failed", result)
+	}
+
+	entries, _ := q.List("prod")
+	if len(entries) != 1 || entries[0].Status != StatusFailed || !strings.HasPrefix(entries[0].LastError, "409 Conflict") {
+		t.Errorf("prod queue = %+v, want the refused POST marked failed", entries)
+	}
+	if entries, _ := q.List("staging"); len(entries) != 1 || entries[0].Status != StatusPending {
+		t.Errorf("staging queue = %+v, want its write untouched", entries)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		8:  time.Hour,
+		50: time.Hour,
+	}
+	for attempts, want := range tests {
+		if got := Backoff(attempts); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package queue
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+// Transport is an http.RoundTripper that queues each write (POST, PUT,
+// PATCH, DELETE) before passing it to Next, and keeps it only if it surely
+// never took effect: the request failed before it was written to a
+// connection, or the server answered with a Retryable status. Otherwise the
+// entry is cleared, so a write the server may have applied is never sent
+// twice. Queueing is best-effort: if the queue cannot be written, the
+// request is sent anyway.
+type Transport struct {
+	Next    http.RoundTripper // Defaults to http.DefaultTransport
+	Queue   *Queue
+	BaseURL string // Stripped from request URLs to give queued paths
+	Profile string
+
+	mu sync.Mutex
+	// Writes queued by this transport and not yet answered, by requestKey,
+	// so a client retrying the same request reuses its entry
+	inflight map[string]int64
+}
+
+// Left returns how many writes sent through the transport are still queued
+// because they never reached the server.
+func (t *Transport) Left() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.inflight)
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if !isWrite(req) {
+		return next.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	entry := &Entry{
+		Profile:     t.Profile,
+		Method:      req.Method,
+		Path:        strings.TrimPrefix(req.URL.String(), t.BaseURL),
+		ContentType: req.Header.Get("Content-Type"),
+		IfMatch:     req.Header.Get("If-Match"),
+		Body:        string(body),
+	}
+	key := requestKey(entry)
+	id := t.enqueue(key, entry)
+
+	// Until the headers are written, the server cannot have seen the request
+	var wrote atomic.Bool
+	trace := &httptrace.ClientTrace{WroteHeaders: func() { wrote.Store(true) }}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	resp, err := next.RoundTrip(req)
+	if id == 0 {
+		return resp, err
+	}
+	switch {
+	case err != nil && !wrote.Load():
+		_ = t.Queue.Postpone(id, err.Error())
+	case err == nil && Retryable(resp.StatusCode):
+		_ = t.Queue.Postpone(id, resp.Status)
+	default:
+		if t.Queue.Remove(id) == nil {
+			t.mu.Lock()
+			delete(t.inflight, key)
+			t.mu.Unlock()
+		}
+	}
+	return resp, err
+}
+
+// enqueue returns the queue ID of entry, adding it unless this transport
+// already queued the same request. It returns 0 if the queue failed.
+func (t *Transport) enqueue(key string, entry *Entry) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if id, ok := t.inflight[key]; ok {
+		return id
+	}
+	id, err := t.Queue.Add(entry)
+	if err != nil {
+		return 0
+	}
+	if t.inflight == nil {
+		t.inflight = make(map[string]int64)
+	}
+	t.inflight[key] = id
+	return id
+}
+
+func requestKey(e *Entry) string {
+	return e.Method + " " + e.Path + "\n" + e.IfMatch + "\n" + e.Body
+}
+
+// isWrite reports whether req may change data. Requests the client marks
+// read-only, such as SQL queries, are sent as POSTs but are not writes.
+func isWrite(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return !oakclient.IsReadOnly(req)
+	}
+	return false
+}
+
+// Retryable reports whether a response status means the write did not take
+// effect and may succeed later: rate limiting, or the server being
+// unavailable. A 502 or 504 from a proxy is not: the server behind it may
+// have applied the write before the proxy gave up.
+func Retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,341 @@
+// Package replica keeps a local copy of a remote profile's published data so
+// read commands can fall back to it when the remote is unreachable.
+package replica
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jeff/oaks/cli/internal/db"
+	"github.com/jeff/oaks/cli/internal/models"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+// Metadata keys recording where and when the replica was synced
+const (
+	metaSyncedAt = "replica_synced_at"
+	metaProfile  = "replica_profile"
+	metaURL      = "replica_url"
+)
+
+// Info describes a synced replica
+type Info struct {
+	Profile  string
+	URL      string
+	SyncedAt time.Time
+}
+
+// Stats counts what a sync copied
+type Stats struct {
+	Genera         int
+	TaxonLevels    int
+	Taxa           int
+	Sources        int
+	Species        int
+	SpeciesSources int
+}
+
+// exportFile is the part of the API export a replica is built from
+type exportFile struct {
+	Sources []*models.Source `json:"sources"`
+	Species []exportSpecies  `json:"species"`
+}
+
+type exportSpecies struct {
+	Name                string                `json:"name"`
+	Author              *string               `json:"author"`
+	Pronunciation       *string               `json:"pronunciation"`
+	IsHybrid            bool                  `json:"is_hybrid"`
+	ConservationStatus  *string               `json:"conservation_status"`
+	Taxonomy            exportTaxonomy        `json:"taxonomy"`
+	Parent1             *string               `json:"parent1"`
+	Parent2             *string               `json:"parent2"`
+	Hybrids             []string              `json:"hybrids"`
+	CloselyRelatedTo    []string              `json:"closely_related_to"`
+	SubspeciesVarieties []string              `json:"subspecies_varieties"`
+	Synonyms            []string              `json:"synonyms"`
+	ExternalLinks       []models.ExternalLink `json:"external_links"`
+	Sources             []exportSourceData    `json:"sources"`
+}
+
+type exportTaxonomy struct {
+	Genus      string  `json:"genus"`
+	Subgenus   *string `json:"subgenus"`
+	Section    *string `json:"section"`
+	Subsection *string `json:"subsection"`
+	Complex    *string `json:"complex"`
+}
+
+type exportSourceData struct {
+	SourceID               int64             `json:"source_id"`
+	IsPreferred            bool              `json:"is_preferred"`
+	LocalNames             []string          `json:"local_names"`
+	Range                  *string           `json:"range"`
+	GrowthHabit            *string           `json:"growth_habit"`
+	Leaves                 *string           `json:"leaves"`
+	Flowers                *string           `json:"flowers"`
+	Fruits                 *string           `json:"fruits"`
+	Bark                   *string           `json:"bark"`
+	Twigs                  *string           `json:"twigs"`
+	Buds                   *string           `json:"buds"`
+	HardinessHabitat       *string           `json:"hardiness_habitat"`
+	Miscellaneous          *string           `json:"miscellaneous"`
+	DistinguishingFeatures *string           `json:"distinguishing_features"`
+	URL                    *string           `json:"url"`
+	Pages                  *string           `json:"pages"`
+	FieldPages             map[string]string `json:"field_pages"`
+	TranscriptionMethod    *string           `json:"transcription_method"`
+	OCRConfidence          *float64          `json:"ocr_confidence"`
+}
+
+// Sync copies the published data of the API behind c into a fresh database
+// at path, replacing any earlier replica only once the copy is complete.
+// Drafts are not copied since the export leaves them out.
+func Sync(ctx context.Context, c *oakclient.Client, path, profile, url string) (*Stats, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create replica directory: %w", err)
+	}
+
+	tmpPath := path + ".sync"
+	_ = os.Remove(tmpPath)
+	defer os.Remove(tmpPath)
+
+	stats, err := build(ctx, c, tmpPath, profile, url)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return nil, fmt.Errorf("failed to replace replica: %w", err)
+	}
+	return stats, nil
+}
+
+func build(ctx context.Context, c *oakclient.Client, path, profile, url string) (*Stats, error) {
+	snap, err := fetch(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return snap.write(path, map[string]string{
+		metaSyncedAt: time.Now().UTC().Format(time.RFC3339),
+		metaProfile:  profile,
+		metaURL:      url,
+	})
+}
+
+// snapshot is the published data of an API, as copied into a replica or,
+// filtered, a subset
+type snapshot struct {
+	genera []*models.Genus
+	levels []*models.TaxonLevelDef
+	taxa   []*models.Taxon
+	export exportFile
+}
+
+// fetch reads everything a replica holds from the API behind c
+func fetch(ctx context.Context, c *oakclient.Client) (*snapshot, error) {
+	snap := &snapshot{}
+
+	genera, err := c.ListGenera(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch genera: %w", err)
+	}
+	for _, g := range genera {
+		snap.genera = append(snap.genera, &models.Genus{Name: g.Name, CommonName: g.CommonName, Subgenera: g.Subgenera})
+	}
+
+	levels, err := c.ListTaxonLevels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch taxon levels: %w", err)
+	}
+	for _, l := range levels {
+		snap.levels = append(snap.levels, &models.TaxonLevelDef{Name: l.Name, Rank: l.Rank, Plural: l.Plural, EntryField: l.EntryField})
+	}
+
+	taxa, err := c.ListTaxa(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch taxa: %w", err)
+	}
+	for _, t := range taxa.Data {
+		links := make([]models.TaxonLink, len(t.Links))
+		for i, l := range t.Links {
+			links[i] = models.TaxonLink{Label: l.Label, URL: l.URL}
+		}
+		snap.taxa = append(snap.taxa, &models.Taxon{
+			Name:   t.Name,
+			Level:  models.TaxonLevel(t.Level),
+			Genus:  t.Genus,
+			Parent: t.Parent,
+			Author: t.Author,
+			Notes:  t.Notes,
+			Links:  links,
+		})
+	}
+
+	data, err := c.Export(ctx, oakclient.ExportOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch export: %w", err)
+	}
+	if err := json.Unmarshal(data, &snap.export); err != nil {
+		return nil, fmt.Errorf("failed to parse export: %w", err)
+	}
+	return snap, nil
+}
+
+// write creates a database at path holding the snapshot and the given
+// metadata
+func (snap *snapshot) write(path string, metadata map[string]string) (*Stats, error) {
+	database, err := db.New(path)
+	if err != nil {
+		return nil, err
+	}
+	defer database.Close()
+
+	// One transaction rather than one per row, which is much faster
+	var stats *Stats
+	err = database.WithTx(func(tx db.Store) error {
+		var err error
+		stats, err = snap.copy(tx, metadata)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// copy saves the snapshot and the given metadata to a database
+func (snap *snapshot) copy(database db.Store, metadata map[string]string) (*Stats, error) {
+	stats := &Stats{}
+	if err := database.ReplaceGenera(snap.genera); err != nil {
+		return nil, err
+	}
+	stats.Genera = len(snap.genera)
+
+	if err := database.ReplaceTaxonLevels(snap.levels); err != nil {
+		return nil, err
+	}
+	stats.TaxonLevels = len(snap.levels)
+
+	for _, t := range snap.taxa {
+		if err := database.InsertTaxon(t); err != nil {
+			return nil, fmt.Errorf("failed to copy taxon %s: %w", t.Name, err)
+		}
+	}
+	stats.Taxa = len(snap.taxa)
+
+	for _, source := range snap.export.Sources {
+		if err := database.RestoreSource(source); err != nil {
+			return nil, err
+		}
+	}
+	stats.Sources = len(snap.export.Sources)
+
+	for _, s := range snap.export.Species {
+		if err := database.SaveOakEntry(s.entry()); err != nil {
+			return nil, fmt.Errorf("failed to copy species %s: %w", s.Name, err)
+		}
+		for _, sd := range s.Sources {
+			if err := database.SaveSpeciesSource(sd.speciesSource(s.Name)); err != nil {
+				return nil, fmt.Errorf("failed to copy source data for %s: %w", s.Name, err)
+			}
+		}
+		stats.SpeciesSources += len(s.Sources)
+	}
+	stats.Species = len(snap.export.Species)
+
+	for key, value := range metadata {
+		if err := database.SetMetadata(key, value); err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+func (s *exportSpecies) entry() *models.OakEntry {
+	return &models.OakEntry{
+		ScientificName:      s.Name,
+		Author:              s.Author,
+		Pronunciation:       s.Pronunciation,
+		IsHybrid:            s.IsHybrid,
+		ConservationStatus:  s.ConservationStatus,
+		Genus:               s.Taxonomy.Genus,
+		Subgenus:            s.Taxonomy.Subgenus,
+		Section:             s.Taxonomy.Section,
+		Subsection:          s.Taxonomy.Subsection,
+		Complex:             s.Taxonomy.Complex,
+		Parent1:             s.Parent1,
+		Parent2:             s.Parent2,
+		Hybrids:             s.Hybrids,
+		CloselyRelatedTo:    s.CloselyRelatedTo,
+		SubspeciesVarieties: s.SubspeciesVarieties,
+		Synonyms:            s.Synonyms,
+		ExternalLinks:       s.ExternalLinks,
+		Visibility:          models.VisibilityPublished,
+	}
+}
+
+func (sd *exportSourceData) speciesSource(name string) *models.SpeciesSource {
+	return &models.SpeciesSource{
+		ScientificName:         name,
+		SourceID:               sd.SourceID,
+		LocalNames:             sd.LocalNames,
+		Range:                  sd.Range,
+		GrowthHabit:            sd.GrowthHabit,
+		Leaves:                 sd.Leaves,
+		Flowers:                sd.Flowers,
+		Fruits:                 sd.Fruits,
+		Bark:                   sd.Bark,
+		Twigs:                  sd.Twigs,
+		Buds:                   sd.Buds,
+		HardinessHabitat:       sd.HardinessHabitat,
+		Miscellaneous:          sd.Miscellaneous,
+		DistinguishingFeatures: sd.DistinguishingFeatures,
+		URL:                    sd.URL,
+		Pages:                  sd.Pages,
+		FieldPages:             sd.FieldPages,
+		TranscriptionMethod:    sd.TranscriptionMethod,
+		OCRConfidence:          sd.OCRConfidence,
+		IsPreferred:            sd.IsPreferred,
+	}
+}
+
+// Read returns when and from where the replica at path was synced, or nil if
+// there is no replica there
+func Read(path string) (*Info, error) {
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to access replica: %w", err)
+	}
+
+	database, err := db.New(path)
+	if err != nil {
+		return nil, err
+	}
+	defer database.Close()
+
+	syncedAt, err := database.GetMetadata(metaSyncedAt)
+	if err != nil {
+		return nil, err
+	}
+	if syncedAt == "" {
+		return nil, nil
+	}
+	info := &Info{}
+	if info.SyncedAt, err = time.Parse(time.RFC3339, syncedAt); err != nil {
+		return nil, fmt.Errorf("failed to parse replica sync time %q: %w", syncedAt, err)
+	}
+	if info.Profile, err = database.GetMetadata(metaProfile); err != nil {
+		return nil, err
+	}
+	if info.URL, err = database.GetMetadata(metaURL); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
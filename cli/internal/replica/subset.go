@@ -0,0 +1,224 @@
+package replica
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jeff/oaks/cli/internal/models"
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+// Metadata keys recording what a subset was built from
+const (
+	metaSubsetFilter  = "subset_filter"
+	metaSubsetBuiltAt = "subset_built_at"
+	metaSubsetURL     = "subset_url"
+)
+
+// Filter selects the species in a subset by taxonomy. A species matches if
+// every set field equals its own (ignoring case); unset fields match any.
+type Filter struct {
+	Genus      string
+	Subgenus   string
+	Section    string
+	Subsection string
+	Complex    string
+}
+
+// IsEmpty reports whether no field is set, which would select everything.
+func (f *Filter) IsEmpty() bool {
+	return *f == Filter{}
+}
+
+// String describes the filter as "section=Lobatae subsection=Phellos".
+func (f *Filter) String() string {
+	var parts []string
+	for _, p := range f.fields() {
+		if p.value != "" {
+			parts = append(parts, p.name+"="+p.value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+type filterField struct {
+	name  string
+	value string
+}
+
+func (f *Filter) fields() []filterField {
+	return []filterField{
+		{"genus", f.Genus}, {"subgenus", f.Subgenus}, {"section", f.Section},
+		{"subsection", f.Subsection}, {"complex", f.Complex},
+	}
+}
+
+func (f *Filter) matches(t *exportTaxonomy) bool {
+	match := func(want string, got *string) bool {
+		return want == "" || (got != nil && strings.EqualFold(want, *got))
+	}
+	return match(f.Genus, &t.Genus) && match(f.Subgenus, t.Subgenus) && match(f.Section, t.Section) &&
+		match(f.Subsection, t.Subsection) && match(f.Complex, t.Complex)
+}
+
+// Subset writes a new database at path holding the published species of the
+// API behind c that match f, together with the taxa above them, the sources
+// they cite, and their genera. It opens like any oak database. Hybrid lists
+// only name species in the subset; parent names are kept even when the
+// parent is left out. Path must not exist yet.
+func Subset(ctx context.Context, c *oakclient.Client, path string, f Filter) (*Stats, error) {
+	if f.IsEmpty() {
+		return nil, errors.New("a subset needs at least one taxonomy filter")
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("%s already exists", path)
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	snap, err := fetch(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	sub := snap.subset(&f)
+	if len(sub.export.Species) == 0 {
+		return nil, fmt.Errorf("no published species match %s", f.String())
+	}
+
+	tmpPath := path + ".build"
+	_ = os.Remove(tmpPath)
+	defer os.Remove(tmpPath)
+
+	stats, err := sub.write(tmpPath, map[string]string{
+		metaSubsetFilter:  f.String(),
+		metaSubsetBuiltAt: time.Now().UTC().Format(time.RFC3339),
+		metaSubsetURL:     c.BaseURL(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return nil, fmt.Errorf("failed to write subset: %w", err)
+	}
+	return stats, nil
+}
+
+// subset returns the part of snap that f selects. Taxon levels are kept
+// whole since they define the hierarchy.
+func (snap *snapshot) subset(f *Filter) *snapshot {
+	sub := &snapshot{levels: snap.levels}
+
+	names := make(map[string]bool)
+	for _, s := range snap.export.Species {
+		if f.matches(&s.Taxonomy) {
+			names[s.Name] = true
+		}
+	}
+
+	// Taxa placing a selected species, keyed by entry field, then their ancestors
+	entryLevels := make(map[string]models.TaxonLevel)
+	for _, l := range snap.levels {
+		if l.EntryField != nil {
+			entryLevels[*l.EntryField] = models.TaxonLevel(l.Name)
+		}
+	}
+	type taxonKey struct {
+		level models.TaxonLevel
+		name  string
+	}
+	wantTaxa := make(map[taxonKey]bool)
+	genera := make(map[string]bool)
+	sourceIDs := make(map[int64]bool)
+	for _, s := range snap.export.Species {
+		if !names[s.Name] {
+			continue
+		}
+		genera[s.Taxonomy.Genus] = true
+		for field, value := range map[string]*string{
+			"subgenus": s.Taxonomy.Subgenus, "section": s.Taxonomy.Section,
+			"subsection": s.Taxonomy.Subsection, "complex": s.Taxonomy.Complex,
+		} {
+			if level, ok := entryLevels[field]; ok && value != nil && *value != "" {
+				wantTaxa[taxonKey{level, *value}] = true
+			}
+		}
+		for _, sd := range s.Sources {
+			sourceIDs[sd.SourceID] = true
+		}
+
+		s.Hybrids = keepNames(s.Hybrids, names)
+		sub.export.Species = append(sub.export.Species, s)
+	}
+
+	// A taxon's parent is the taxon of that name at the nearest rank above it
+	ranks := make(map[models.TaxonLevel]int)
+	for _, l := range snap.levels {
+		ranks[models.TaxonLevel(l.Name)] = l.Rank
+	}
+	parentOf := func(t *models.Taxon) *models.Taxon {
+		var parent *models.Taxon
+		for _, p := range snap.taxa {
+			if t.Parent != nil && p.Name == *t.Parent && ranks[p.Level] < ranks[t.Level] &&
+				(parent == nil || ranks[p.Level] > ranks[parent.Level]) {
+				parent = p
+			}
+		}
+		return parent
+	}
+	for _, t := range snap.taxa {
+		if !wantTaxa[taxonKey{t.Level, t.Name}] {
+			continue
+		}
+		for p := parentOf(t); p != nil && !wantTaxa[taxonKey{p.Level, p.Name}]; p = parentOf(p) {
+			wantTaxa[taxonKey{p.Level, p.Name}] = true
+		}
+	}
+	for _, t := range snap.taxa {
+		if wantTaxa[taxonKey{t.Level, t.Name}] {
+			sub.taxa = append(sub.taxa, t)
+			genera[t.Genus] = true
+		}
+	}
+
+	for _, g := range snap.genera {
+		if genera[g.Name] {
+			sub.genera = append(sub.genera, g)
+		}
+	}
+
+	// Sources replacing a cited one come along so superseded_by resolves
+	byID := make(map[int64]*models.Source)
+	for _, source := range snap.export.Sources {
+		byID[source.ID] = source
+	}
+	for id := range sourceIDs {
+		for source := byID[id]; source != nil && source.SupersededBy != nil && !sourceIDs[*source.SupersededBy]; source = byID[*source.SupersededBy] {
+			sourceIDs[*source.SupersededBy] = true
+		}
+	}
+	for _, source := range snap.export.Sources {
+		if sourceIDs[source.ID] {
+			sub.export.Sources = append(sub.export.Sources, source)
+		}
+	}
+	return sub
+}
+
+// keepNames returns the names in list that are in keep.
+func keepNames(list []string, keep map[string]bool) []string {
+	kept := []string{}
+	for _, name := range list {
+		if keep[name] {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
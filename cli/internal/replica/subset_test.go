@@ -0,0 +1,99 @@
+package replica
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jeff/oaks/cli/internal/models"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestSnapshotSubset(t *testing.T) {
+	level := func(name string, rank int) *models.TaxonLevelDef {
+		return &models.TaxonLevelDef{Name: name, Rank: rank, EntryField: strPtr(name)}
+	}
+	superseding := int64(3)
+	snap := &snapshot{
+		genera: []*models.Genus{{Name: "Quercus"}, {Name: "Lithocarpus"}},
+		levels: []*models.TaxonLevelDef{level("subgenus", 1), level("section", 2), level("subsection", 3)},
+		taxa: []*models.Taxon{
+			{Name: "Quercus", Level: models.TaxonLevelSubgenus, Genus: "Quercus"},
+			{Name: "Quercus", Level: models.TaxonLevelSection, Genus: "Quercus", Parent: strPtr("Quercus")},
+			{Name: "Lobatae", Level: models.TaxonLevelSection, Genus: "Quercus", Parent: strPtr("Quercus")},
+			{Name: "Phellos", Level: models.TaxonLevelSubsection, Genus: "Quercus", Parent: strPtr("Lobatae")},
+			{Name: "Albae", Level: models.TaxonLevelSubsection, Genus: "Quercus", Parent: strPtr("Quercus")},
+		},
+		export: exportFile{
+			Sources: []*models.Source{
+				{ID: 1, Name: "cited"},
+				{ID: 2, Name: "cited, superseded", SupersededBy: &superseding},
+				{ID: 3, Name: "superseding"},
+				{ID: 4, Name: "uncited"},
+			},
+			Species: []exportSpecies{
+				{
+					Name:     "phellos",
+					Taxonomy: exportTaxonomy{Genus: "Quercus", Subgenus: strPtr("Quercus"), Section: strPtr("Lobatae"), Subsection: strPtr("Phellos")},
+					Hybrids:  []string{"× heterophylla", "× bebbiana"},
+					Sources:  []exportSourceData{{SourceID: 1}, {SourceID: 2}},
+				},
+				{
+					Name:     "× heterophylla",
+					Taxonomy: exportTaxonomy{Genus: "Quercus", Section: strPtr("Lobatae")},
+					Parent1:  strPtr("phellos"),
+					Parent2:  strPtr("rubra"),
+				},
+				{
+					Name:     "alba",
+					Taxonomy: exportTaxonomy{Genus: "Quercus", Subgenus: strPtr("Quercus"), Section: strPtr("Quercus"), Subsection: strPtr("Albae")},
+					Hybrids:  []string{"× bebbiana"},
+					Sources:  []exportSourceData{{SourceID: 4}},
+				},
+			},
+		},
+	}
+
+	sub := snap.subset(&Filter{Section: "lobatae"})
+
+	var species []string
+	for _, s := range sub.export.Species {
+		species = append(species, s.Name)
+	}
+	if !slices.Equal(species, []string{"phellos", "× heterophylla"}) {
+		t.Errorf("species = %v, want phellos and × heterophylla", species)
+	}
+	if hybrids := sub.export.Species[0].Hybrids; !slices.Equal(hybrids, []string{"× heterophylla"}) {
+		t.Errorf("phellos hybrids = %v, want only the one in the subset", hybrids)
+	}
+	if p := sub.export.Species[1].Parent2; p == nil || *p != "rubra" {
+		t.Errorf("× heterophylla parent2 = %v, want rubra kept by name", p)
+	}
+	if len(snap.export.Species[0].Hybrids) != 2 {
+		t.Errorf("subset changed the snapshot's hybrid list")
+	}
+
+	var taxa []string
+	for _, tx := range sub.taxa {
+		taxa = append(taxa, string(tx.Level)+" "+tx.Name)
+	}
+	// Section Quercus shares the subgenus's name but is not Lobatae's parent
+	if !slices.Equal(taxa, []string{"subgenus Quercus", "section Lobatae", "subsection Phellos"}) {
+		t.Errorf("taxa = %v", taxa)
+	}
+
+	var sources []int64
+	for _, s := range sub.export.Sources {
+		sources = append(sources, s.ID)
+	}
+	if !slices.Equal(sources, []int64{1, 2, 3}) {
+		t.Errorf("sources = %v, want the cited ones and the one superseding them", sources)
+	}
+
+	if len(sub.genera) != 1 || sub.genera[0].Name != "Quercus" {
+		t.Errorf("genera = %v, want Quercus", sub.genera)
+	}
+	if len(sub.levels) != 3 {
+		t.Errorf("levels = %d, want all 3", len(sub.levels))
+	}
+}
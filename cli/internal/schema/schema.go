@@ -2,6 +2,7 @@ package schema
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -75,6 +76,37 @@ func (v *Validator) ValidateOakEntry(entry *models.OakEntry) error {
 	return v.validateEnumerations(entry)
 }
 
+// FieldError is a schema violation at one field of an entry
+type FieldError struct {
+	Field   string // Dotted path, such as "external_links.0.url"; "" for the entry itself
+	Message string
+}
+
+// FieldErrors breaks an error from ValidateOakEntry into one FieldError per
+// violation. Errors that are not schema violations come back as one
+// FieldError for the entry itself.
+func FieldErrors(err error) []FieldError {
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return []FieldError{{Message: err.Error()}}
+	}
+
+	var fields []FieldError
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			field := strings.ReplaceAll(strings.TrimPrefix(e.InstanceLocation, "/"), "/", ".")
+			fields = append(fields, FieldError{Field: field, Message: e.Message})
+			return
+		}
+		for _, cause := range e.Causes {
+			walk(cause)
+		}
+	}
+	walk(ve)
+	return fields
+}
+
 // validateEnumerations checks field values against allowed enumerations
 // Note: Previously validated DataPoint fields on OakEntry, but those moved to SpeciesSource.
 // This is now a no-op for OakEntry. Add ValidateSpeciesSource if enumeration validation needed.
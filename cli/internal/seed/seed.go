@@ -0,0 +1,253 @@
+// Package seed generates realistic fake oak data for load testing and
+// end-to-end fixtures. Output is deterministic for a given Options.Seed so
+// that datasets can be regenerated exactly.
+package seed
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+// Default generation parameters.
+const (
+	DefaultSpecies     = 500
+	DefaultHybridRatio = 0.1
+	DefaultSources     = 3
+	DefaultSeed        = 1
+)
+
+// Options controls the size and shape of a generated dataset.
+type Options struct {
+	Species     int     // Total number of species entries, including hybrids
+	HybridRatio float64 // Fraction of Species that are hybrids (0-1)
+	Sources     int     // Number of sources to attribute descriptive data to
+	Seed        int64   // Random seed; the same seed always yields the same data
+}
+
+// SpeciesSourceData is descriptive data for one species from one generated source.
+// SourceIndex refers to Dataset.Sources, since IDs are assigned by the server.
+type SpeciesSourceData struct {
+	ScientificName string
+	SourceIndex    int
+	Data           *oakclient.SpeciesSource
+}
+
+// Dataset is a complete generated dataset, ordered so that it can be loaded
+// front to back: taxa parents before children, hybrid parents before hybrids.
+type Dataset struct {
+	Sources        []*oakclient.SourceRequest
+	Taxa           []*oakclient.TaxonRequest
+	Species        []*oakclient.SpeciesRequest
+	SpeciesSources []*SpeciesSourceData
+}
+
+// Real subgenus/section structure, so filters behave as they do on real data
+var sectionsBySubgenus = map[string][]string{
+	"Quercus":         {"Quercus", "Lobatae", "Protobalanus", "Ponticae", "Virentes"},
+	"Cerris":          {"Cerris", "Ilex"},
+	"Cyclobalanopsis": {"Cyclobalanopsis"},
+}
+
+var subgenusOrder = []string{"Quercus", "Cerris", "Cyclobalanopsis"}
+
+var (
+	epithetStarts  = []string{"al", "ru", "ma", "pa", "ve", "co", "fa", "ga", "lo", "mi", "ne", "pu", "sa", "te", "vi", "bi", "ca", "de", "gl", "ob"}
+	epithetMiddles = []string{"b", "cr", "lt", "n", "rr", "st", "l", "m", "nd", "ph", "r", "s", "t", "v", "x"}
+	epithetEnds    = []string{"a", "ensis", "iana", "ifolia", "oides", "ata", "ica", "ina", "osa", "ella", "ilis", "ra"}
+
+	authors = []string{"L.", "Michx.", "Trel.", "Nutt.", "Engelm.", "Sarg.", "Liebm.", "C.H.Mull.", "Buckley", "Benth."}
+
+	conservationStatuses = []string{"LC", "LC", "LC", "NT", "VU", "EN", "CR", "DD"}
+
+	leafShapes   = []string{"obovate", "elliptic", "lanceolate", "oblong", "ovate"}
+	leafMargins  = []string{"entire", "shallowly lobed", "deeply lobed", "serrate", "bristle-tipped"}
+	barkTextures = []string{"scaly", "furrowed", "smooth", "blocky", "ridged"}
+	habits       = []string{"large tree", "medium tree", "shrub", "multi-stemmed shrub", "small tree"}
+	regions      = []string{"eastern North America", "Mexico", "the Mediterranean", "southern China", "Central America", "the Himalayas", "California"}
+)
+
+// Generate builds a dataset according to opts. Zero-valued options fall back
+// to the package defaults.
+func Generate(opts Options) *Dataset {
+	if opts.Species <= 0 {
+		opts.Species = DefaultSpecies
+	}
+	if opts.HybridRatio < 0 || opts.HybridRatio > 1 {
+		opts.HybridRatio = DefaultHybridRatio
+	}
+	if opts.Sources <= 0 {
+		opts.Sources = DefaultSources
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed)) //nolint:gosec // deterministic fake data, not security sensitive
+	ds := &Dataset{}
+
+	for i := 0; i < opts.Sources; i++ {
+		year := 1950 + rng.Intn(75)
+		author := pick(rng, authors)
+		ds.Sources = append(ds.Sources, &oakclient.SourceRequest{
+			SourceType: pick(rng, []string{"book", "website", "paper", "personal-observation"}),
+			Name:       fmt.Sprintf("Seed Source %d", i+1),
+			Author:     &author,
+			Year:       &year,
+		})
+	}
+
+	// Taxa: subgenera, their sections, and a few subsections per section
+	type placement struct {
+		subgenus, section string
+		subsection        *string
+	}
+	var placements []placement
+	usedTaxa := make(map[string]bool)
+	for _, sg := range subgenusOrder {
+		ds.Taxa = append(ds.Taxa, &oakclient.TaxonRequest{Name: sg, Level: oakclient.TaxonLevelSubgenus})
+		for _, sec := range sectionsBySubgenus[sg] {
+			parent := sg
+			ds.Taxa = append(ds.Taxa, &oakclient.TaxonRequest{Name: sec, Level: oakclient.TaxonLevelSection, Parent: &parent})
+			placements = append(placements, placement{subgenus: sg, section: sec})
+			for j := rng.Intn(3); j > 0; j-- {
+				secParent := sec
+				sub := subsectionName(rng, usedTaxa)
+				ds.Taxa = append(ds.Taxa, &oakclient.TaxonRequest{Name: sub, Level: oakclient.TaxonLevelSubsection, Parent: &secParent})
+				placements = append(placements, placement{subgenus: sg, section: sec, subsection: &sub})
+			}
+		}
+	}
+
+	hybridCount := int(float64(opts.Species) * opts.HybridRatio)
+	speciesCount := opts.Species - hybridCount
+	if speciesCount < 2 && hybridCount > 0 {
+		// Hybrids need at least two parents; fewer than two species get none
+		speciesCount = min(2, opts.Species)
+		hybridCount = opts.Species - speciesCount
+	}
+
+	used := make(map[string]bool)
+	var speciesNames []string
+	speciesPlacement := make(map[string]placement)
+
+	for i := 0; i < speciesCount; i++ {
+		name := uniqueEpithet(rng, used)
+		speciesNames = append(speciesNames, name)
+
+		p := placements[rng.Intn(len(placements))]
+		speciesPlacement[name] = p
+		subgenus, section := p.subgenus, p.section
+		author := pick(rng, authors)
+		status := pick(rng, conservationStatuses)
+
+		ds.Species = append(ds.Species, &oakclient.SpeciesRequest{
+			ScientificName:     name,
+			Author:             &author,
+			ConservationStatus: &status,
+			Subgenus:           &subgenus,
+			Section:            &section,
+			Subsection:         p.subsection,
+		})
+	}
+
+	for i := 0; i < hybridCount; i++ {
+		// Hybrids cross species from the same section where possible, as they do in nature
+		parent1 := speciesNames[rng.Intn(len(speciesNames))]
+		var candidates []string
+		for _, n := range speciesNames {
+			if n != parent1 && speciesPlacement[n].section == speciesPlacement[parent1].section {
+				candidates = append(candidates, n)
+			}
+		}
+		if len(candidates) == 0 {
+			for _, n := range speciesNames {
+				if n != parent1 {
+					candidates = append(candidates, n)
+				}
+			}
+		}
+		parent2 := pick(rng, candidates)
+
+		name := "× " + uniqueEpithet(rng, used)
+		p := speciesPlacement[parent1]
+		subgenus, section := p.subgenus, p.section
+		author := pick(rng, authors)
+		p1, p2 := parent1, parent2
+
+		ds.Species = append(ds.Species, &oakclient.SpeciesRequest{
+			ScientificName: name,
+			Author:         &author,
+			IsHybrid:       true,
+			Subgenus:       &subgenus,
+			Section:        &section,
+			Parent1:        &p1,
+			Parent2:        &p2,
+		})
+	}
+
+	// Each species gets descriptive data from one or more sources
+	for _, sp := range ds.Species {
+		n := 1 + rng.Intn(opts.Sources)
+		for j, srcIdx := range rng.Perm(opts.Sources)[:n] {
+			ds.SpeciesSources = append(ds.SpeciesSources, &SpeciesSourceData{
+				ScientificName: sp.ScientificName,
+				SourceIndex:    srcIdx,
+				Data:           describe(rng, j == 0),
+			})
+		}
+	}
+
+	return ds
+}
+
+// describe generates plausible descriptive text for a species source
+func describe(rng *rand.Rand, preferred bool) *oakclient.SpeciesSource {
+	leaves := fmt.Sprintf("Leaves %s, %d-%d cm, margins %s.",
+		pick(rng, leafShapes), 3+rng.Intn(5), 8+rng.Intn(12), pick(rng, leafMargins))
+	bark := fmt.Sprintf("Bark %s, gray to dark brown.", pick(rng, barkTextures))
+	habit := fmt.Sprintf("A %s to %d m.", pick(rng, habits), 5+rng.Intn(30))
+	rangeText := fmt.Sprintf("Native to %s; %d-%d m elevation.", pick(rng, regions), rng.Intn(500), 500+rng.Intn(2500))
+	fruits := fmt.Sprintf("Acorns maturing in %d year(s), cup covering 1/%d of nut.", 1+rng.Intn(2), 2+rng.Intn(3))
+
+	return &oakclient.SpeciesSource{
+		Leaves:      &leaves,
+		Bark:        &bark,
+		GrowthHabit: &habit,
+		Range:       &rangeText,
+		Fruits:      &fruits,
+		IsPreferred: preferred,
+	}
+}
+
+// uniqueEpithet builds a Latin-looking specific epithet not already in used
+func uniqueEpithet(rng *rand.Rand, used map[string]bool) string {
+	for {
+		name := pick(rng, epithetStarts) + pick(rng, epithetMiddles) + pick(rng, epithetEnds)
+		if !used[name] {
+			used[name] = true
+			return name
+		}
+		// Space is ~3600 names; add a syllable once collisions start
+		name = pick(rng, epithetStarts) + pick(rng, epithetMiddles) + pick(rng, epithetStarts) + pick(rng, epithetMiddles) + pick(rng, epithetEnds)
+		if !used[name] {
+			used[name] = true
+			return name
+		}
+	}
+}
+
+func pick(rng *rand.Rand, values []string) string {
+	return values[rng.Intn(len(values))]
+}
+
+// subsectionName builds a capitalized plural name in the style of real subsections (e.g. "Albae")
+func subsectionName(rng *rand.Rand, used map[string]bool) string {
+	for {
+		stem := pick(rng, epithetStarts) + pick(rng, epithetMiddles)
+		name := strings.ToUpper(stem[:1]) + stem[1:] + "ae"
+		if !used[name] {
+			used[name] = true
+			return name
+		}
+	}
+}
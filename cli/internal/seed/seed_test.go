@@ -0,0 +1,118 @@
+package seed
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGenerate_Counts(t *testing.T) {
+	ds := Generate(Options{Species: 200, HybridRatio: 0.1, Sources: 4, Seed: 42})
+
+	if len(ds.Species) != 200 {
+		t.Fatalf("got %d species, want 200", len(ds.Species))
+	}
+	if len(ds.Sources) != 4 {
+		t.Errorf("got %d sources, want 4", len(ds.Sources))
+	}
+
+	hybrids := 0
+	for _, sp := range ds.Species {
+		if sp.IsHybrid {
+			hybrids++
+		}
+	}
+	if hybrids != 20 {
+		t.Errorf("got %d hybrids, want 20", hybrids)
+	}
+}
+
+func TestGenerate_CountsAtExtremes(t *testing.T) {
+	tests := []struct {
+		species     int
+		hybridRatio float64
+		wantHybrids int
+	}{
+		{1, 1, 0},
+		{2, 1, 0},
+		{3, 1, 1},
+		{5, 0.5, 2},
+		{10, 0, 0},
+	}
+	for _, tt := range tests {
+		ds := Generate(Options{Species: tt.species, HybridRatio: tt.hybridRatio, Seed: 1})
+		if len(ds.Species) != tt.species {
+			t.Errorf("Species %d, HybridRatio %g: got %d species, want %d", tt.species, tt.hybridRatio, len(ds.Species), tt.species)
+		}
+		hybrids := 0
+		for _, sp := range ds.Species {
+			if sp.IsHybrid {
+				hybrids++
+			}
+		}
+		if hybrids != tt.wantHybrids {
+			t.Errorf("Species %d, HybridRatio %g: got %d hybrids, want %d", tt.species, tt.hybridRatio, hybrids, tt.wantHybrids)
+		}
+	}
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	a := Generate(Options{Species: 50, Seed: 7})
+	b := Generate(Options{Species: 50, Seed: 7})
+	if !reflect.DeepEqual(a, b) {
+		t.Error("same seed produced different datasets")
+	}
+
+	c := Generate(Options{Species: 50, Seed: 8})
+	if reflect.DeepEqual(a.Species, c.Species) {
+		t.Error("different seeds produced identical species")
+	}
+}
+
+func TestGenerate_LoadOrder(t *testing.T) {
+	ds := Generate(Options{Species: 300, HybridRatio: 0.2, Seed: 3})
+
+	// Taxa parents must precede children
+	seenTaxa := make(map[string]bool)
+	for _, taxon := range ds.Taxa {
+		if taxon.Parent != nil && !seenTaxa[*taxon.Parent] {
+			t.Errorf("taxon %s appears before its parent %s", taxon.Name, *taxon.Parent)
+		}
+		seenTaxa[taxon.Name] = true
+	}
+
+	// Names are unique and hybrid parents are earlier, non-hybrid species
+	seen := make(map[string]bool)
+	for _, sp := range ds.Species {
+		if seen[sp.ScientificName] {
+			t.Errorf("duplicate species name %s", sp.ScientificName)
+		}
+		if sp.IsHybrid {
+			if sp.Parent1 == nil || sp.Parent2 == nil {
+				t.Fatalf("hybrid %s missing parents", sp.ScientificName)
+			}
+			if *sp.Parent1 == *sp.Parent2 {
+				t.Errorf("hybrid %s has identical parents", sp.ScientificName)
+			}
+			if !seen[*sp.Parent1] || !seen[*sp.Parent2] {
+				t.Errorf("hybrid %s appears before its parents", sp.ScientificName)
+			}
+		}
+		seen[sp.ScientificName] = true
+	}
+
+	// Every species has exactly one preferred source
+	preferred := make(map[string]int)
+	for _, ss := range ds.SpeciesSources {
+		if !seen[ss.ScientificName] {
+			t.Errorf("species source for unknown species %s", ss.ScientificName)
+		}
+		if ss.Data.IsPreferred {
+			preferred[ss.ScientificName]++
+		}
+	}
+	for name := range seen {
+		if preferred[name] != 1 {
+			t.Errorf("species %s has %d preferred sources, want 1", name, preferred[name])
+		}
+	}
+}
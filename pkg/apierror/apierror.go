@@ -0,0 +1,101 @@
+// Package apierror defines the error responses of the Oak Compendium API. The
+// server writes them and the Go client (pkg/oakclient) reads them, so both
+// agree on the codes and the shape:
+//
+//	{"error": {"code": "NOT_FOUND", "message": "Species 'alba' not found",
+//	           "entity": "species", "id": "alba"}}
+//
+// Every error response the API sends has this form.
+package apierror
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Error codes. Each maps to one HTTP status (see Status).
+const (
+	CodeValidation         = "VALIDATION_ERROR"    // 400: the request is malformed or fails validation
+	CodeUnauthorized       = "UNAUTHORIZED"        // 401: missing or invalid API key
+	CodeForbidden          = "FORBIDDEN"           // 403: the caller may not use this route
+	CodeNotFound           = "NOT_FOUND"           // 404: no such record or route
+	CodeMethodNotAllowed   = "METHOD_NOT_ALLOWED"  // 405: the route does not accept the method
+	CodeConflict           = "CONFLICT"            // 409: the record exists or is still referenced
+	CodePreconditionFailed = "PRECONDITION_FAILED" // 412: the record changed since the client read it
+	CodeClientTooOld       = "CLIENT_TOO_OLD"      // 426: the client must be upgraded
+	CodeRateLimited        = "RATE_LIMITED"        // 429: too many requests
+	CodeInternal           = "INTERNAL_ERROR"      // 500: the server failed
+	CodeMaintenance        = "MAINTENANCE"         // 503: writes are disabled for maintenance
+)
+
+// Status returns the HTTP status for an error code. Unknown codes are 500.
+func Status(code string) int {
+	switch code {
+	case CodeValidation:
+		return http.StatusBadRequest
+	case CodeUnauthorized:
+		return http.StatusUnauthorized
+	case CodeForbidden:
+		return http.StatusForbidden
+	case CodeNotFound:
+		return http.StatusNotFound
+	case CodeMethodNotAllowed:
+		return http.StatusMethodNotAllowed
+	case CodeConflict:
+		return http.StatusConflict
+	case CodePreconditionFailed:
+		return http.StatusPreconditionFailed
+	case CodeClientTooOld:
+		return http.StatusUpgradeRequired
+	case CodeRateLimited:
+		return http.StatusTooManyRequests
+	case CodeMaintenance:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error is an API error, as sent under the response's "error" key.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	// Field names the request field at fault when there is exactly one;
+	// validation errors list every field in Details.
+	Field string `json:"field,omitempty"`
+
+	// Entity and ID identify the record the error is about, such as
+	// "species" and "alba".
+	Entity string `json:"entity,omitempty"`
+	ID     string `json:"id,omitempty"`
+
+	// Details carries code-specific data: FieldErrors for validation errors,
+	// the blocking references of a conflict, and so on.
+	Details interface{} `json:"details,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// New creates an Error with the given code and message.
+func New(code, message string) Error {
+	return Error{Code: code, Message: message}
+}
+
+// Response is the JSON body of every API error response.
+type Response struct {
+	Error Error `json:"error"`
+}
+
+// FieldError is a validation failure of one request field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FieldErrors is the Details of a validation error.
+type FieldErrors struct {
+	Errors []FieldError `json:"errors"`
+}
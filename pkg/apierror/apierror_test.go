@@ -0,0 +1,42 @@
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestStatus(t *testing.T) {
+	tests := map[string]int{
+		CodeValidation:         http.StatusBadRequest,
+		CodeUnauthorized:       http.StatusUnauthorized,
+		CodeForbidden:          http.StatusForbidden,
+		CodeNotFound:           http.StatusNotFound,
+		CodeMethodNotAllowed:   http.StatusMethodNotAllowed,
+		CodeConflict:           http.StatusConflict,
+		CodePreconditionFailed: http.StatusPreconditionFailed,
+		CodeClientTooOld:       http.StatusUpgradeRequired,
+		CodeRateLimited:        http.StatusTooManyRequests,
+		CodeInternal:           http.StatusInternalServerError,
+		CodeMaintenance:        http.StatusServiceUnavailable,
+		"SOMETHING_NEW":        http.StatusInternalServerError,
+	}
+	for code, want := range tests {
+		if got := Status(code); got != want {
+			t.Errorf("Status(%q) = %d, want %d", code, got, want)
+		}
+	}
+}
+
+func TestResponse_JSON(t *testing.T) {
+	resp := Response{Error: New(CodeNotFound, "Species 'alba' not found")}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unset optional fields are left out
+	want := `{"error":{"code":"NOT_FOUND","message":"Species 'alba' not found"}}`
+	if string(data) != want {
+		t.Errorf("JSON = %s, want %s", data, want)
+	}
+}
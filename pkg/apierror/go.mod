@@ -0,0 +1,3 @@
+module github.com/jeff/oaks/pkg/apierror
+
+go 1.24.0
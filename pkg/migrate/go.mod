@@ -0,0 +1,5 @@
+module github.com/jeff/oaks/pkg/migrate
+
+go 1.24.0
+
+require github.com/mattn/go-sqlite3 v1.14.32
@@ -0,0 +1,305 @@
+// Package migrate versions the schema of the Oak Compendium SQLite database.
+// The API server (api/internal/db) and the CLI's direct database access
+// (cli/internal/db) open the same file, so they share one ordered list of
+// migrations, Schema, and agree on what each version means.
+//
+// Each migration is a pair of SQL files in migrations/:
+//
+//	0002_species_redirects.up.sql    applied when upgrading to version 2
+//	0002_species_redirects.down.sql  reverts it; omit if it cannot be undone
+//
+// The schema_version table records every migration applied. A database
+// without it is at version 0: the tables each package creates with CREATE
+// TABLE IF NOT EXISTS, as they were before versioning. New schema changes go
+// in a new migration, never in those CREATE statements.
+package migrate
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var files embed.FS
+
+// Schema is the Oak Compendium database's migrations in version order.
+var Schema = mustLoad(files, "migrations")
+
+// ErrIrreversible is returned when reverting would undo a migration with no
+// down file.
+var ErrIrreversible = errors.New("migration cannot be reverted")
+
+// Migration is one versioned schema change.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string // SQL applied when upgrading to Version
+	Down    string // SQL reverting Up; empty if the change cannot be undone
+}
+
+// Reversible reports whether the migration can be reverted.
+func (m Migration) Reversible() bool {
+	return m.Down != ""
+}
+
+// String returns the migration as it is named on disk, e.g. 0001_genus_index.
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// fileName matches a migration file: version, name, and direction
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Load reads the migrations in dir of fsys, ordered by version. Every
+// migration needs an up file; versions start at 1 and have no gaps.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	byVersion := make(map[int]*Migration)
+	for _, e := range entries {
+		parts := fileName.FindStringSubmatch(e.Name())
+		if parts == nil {
+			return nil, fmt.Errorf("migration file %s is not named NNNN_name.up.sql or NNNN_name.down.sql", e.Name())
+		}
+		version, _ := strconv.Atoi(parts[1])
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: parts[2]}
+			byVersion[version] = m
+		} else if m.Name != parts[2] {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, m.Name, parts[2])
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", e.Name(), err)
+		}
+		if parts[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for version := 1; version <= len(byVersion); version++ {
+		m := byVersion[version]
+		if m == nil {
+			return nil, fmt.Errorf("migration %d is missing", version)
+		}
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %s has no up file", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	return migrations, nil
+}
+
+// mustLoad loads migrations embedded in the binary, which tests check
+func mustLoad(fsys fs.FS, dir string) []Migration {
+	migrations, err := Load(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return migrations
+}
+
+// NewerSchemaError is returned when a database has been migrated past the
+// latest version this build knows, by a newer server or CLI. Running against
+// it could misread or damage data.
+type NewerSchemaError struct {
+	Version int // The database's schema version
+	Latest  int // The latest version this build can migrate to
+}
+
+func (e *NewerSchemaError) Error() string {
+	return fmt.Sprintf("database schema version %d is newer than this build supports (%d); upgrade, or revert the schema with a newer build's 'oak db migrate --to %d'",
+		e.Version, e.Latest, e.Latest)
+}
+
+// Status is one migration and when it was applied.
+type Status struct {
+	Version    int    `json:"version"`
+	Name       string `json:"name"`
+	AppliedAt  string `json:"applied_at,omitempty"` // RFC 3339; empty if pending
+	Reversible bool   `json:"reversible"`
+}
+
+// Migrator applies and reverts migrations on a database.
+type Migrator struct {
+	conn       *sql.DB
+	migrations []Migration
+}
+
+// New returns a Migrator for conn with migrations in version order,
+// normally Schema.
+func New(conn *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{conn: conn, migrations: migrations}
+}
+
+// Latest returns the version the newest migration upgrades to.
+func (m *Migrator) Latest() int {
+	return len(m.migrations)
+}
+
+// Version returns the database's schema version: the newest migration
+// applied, or 0 if none has been.
+func (m *Migrator) Version() (int, error) {
+	var exists bool
+	err := m.conn.QueryRow(`SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_version')`).Scan(&exists)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if !exists {
+		return 0, nil
+	}
+	var version int
+	if err := m.conn.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// Check returns the database's schema version, or a *NewerSchemaError if
+// it is newer than Latest.
+func (m *Migrator) Check() (int, error) {
+	version, err := m.Version()
+	if err != nil {
+		return 0, err
+	}
+	if version > m.Latest() {
+		return version, &NewerSchemaError{Version: version, Latest: m.Latest()}
+	}
+	return version, nil
+}
+
+// Status lists every migration, applied or pending, in version order.
+// Migrations recorded by a newer build are included under the name they
+// were applied with.
+func (m *Migrator) Status() ([]Status, error) {
+	applied := make(map[int]Status)
+	version, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+	if version > 0 {
+		rows, err := m.conn.Query(`SELECT version, name, applied_at FROM schema_version ORDER BY version`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var s Status
+			if err := rows.Scan(&s.Version, &s.Name, &s.AppliedAt); err != nil {
+				return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+			}
+			applied[s.Version] = s
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	status := make([]Status, 0, max(len(m.migrations), version))
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name, Reversible: mig.Reversible()}
+		if a, ok := applied[mig.Version]; ok {
+			s.AppliedAt = a.AppliedAt
+		}
+		status = append(status, s)
+	}
+	for v := m.Latest() + 1; v <= version; v++ {
+		if a, ok := applied[v]; ok {
+			status = append(status, a)
+		}
+	}
+	return status, nil
+}
+
+// Up applies every pending migration and returns them.
+func (m *Migrator) Up() ([]Migration, error) {
+	return m.To(m.Latest())
+}
+
+// To upgrades or reverts the schema to version, one migration per
+// transaction, and returns the migrations applied or reverted in the order
+// run. Reverting stops before anything runs if a migration on the way has
+// no down file.
+func (m *Migrator) To(version int) ([]Migration, error) {
+	if version < 0 || version > m.Latest() {
+		return nil, fmt.Errorf("schema version must be from 0 to %d, got %d", m.Latest(), version)
+	}
+	current, err := m.Check()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.conn.Exec(`CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TEXT NOT NULL
+	)`); err != nil {
+		return nil, fmt.Errorf("failed to create schema_version: %w", err)
+	}
+
+	var run []Migration
+	if version >= current {
+		run = m.migrations[current:version]
+	} else {
+		run = slices.Clone(m.migrations[version:current])
+		slices.Reverse(run)
+		for _, mig := range run {
+			if !mig.Reversible() {
+				return nil, fmt.Errorf("%w: %s", ErrIrreversible, mig)
+			}
+		}
+	}
+
+	done := make([]Migration, 0, len(run))
+	for _, mig := range run {
+		if err := m.run(mig, version > current); err != nil {
+			return done, err
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// run applies (up) or reverts one migration and records it in schema_version
+func (m *Migrator) run(mig Migration, up bool) error {
+	tx, err := m.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %s: %w", mig, err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	script, record, args := mig.Down, `DELETE FROM schema_version WHERE version = ?`, []interface{}{mig.Version}
+	if up {
+		script, record = mig.Up, `INSERT INTO schema_version (version, name, applied_at) VALUES (?, ?, ?)`
+		args = append(args, mig.Name, time.Now().UTC().Format(time.RFC3339))
+	}
+	if _, err := tx.Exec(script); err != nil {
+		return fmt.Errorf("migration %s failed: %w", mig, err)
+	}
+	if _, err := tx.Exec(record, args...); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", mig, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", mig, err)
+	}
+	return nil
+}
+
+// IsNewerSchema reports whether err is or wraps a *NewerSchemaError.
+func IsNewerSchema(err error) bool {
+	var newer *NewerSchemaError
+	return errors.As(err, &newer)
+}
@@ -0,0 +1,156 @@
+package migrate
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	conn, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	if _, err := conn.Exec(`CREATE TABLE oak_entries (scientific_name TEXT PRIMARY KEY, genus TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "add_author", Up: `ALTER TABLE oak_entries ADD COLUMN author TEXT`, Down: `ALTER TABLE oak_entries DROP COLUMN author`},
+	{Version: 2, Name: "add_notes", Up: `ALTER TABLE oak_entries ADD COLUMN notes TEXT; CREATE INDEX idx_notes ON oak_entries(notes)`, Down: `DROP INDEX idx_notes; ALTER TABLE oak_entries DROP COLUMN notes`},
+	{Version: 3, Name: "backfill", Up: `UPDATE oak_entries SET notes = ''`},
+}
+
+func TestMigrateUpAndDown(t *testing.T) {
+	conn := testDB(t)
+	m := New(conn, testMigrations)
+
+	if v, err := m.Version(); err != nil || v != 0 {
+		t.Fatalf("Version() of a new database = %d, %v; want 0", v, err)
+	}
+	done, err := m.Up()
+	if err != nil || len(done) != 3 {
+		t.Fatalf("Up() = %v, %v; want 3 migrations", done, err)
+	}
+	if v, _ := m.Version(); v != 3 {
+		t.Errorf("Version() after Up = %d, want 3", v)
+	}
+	if _, err := conn.Exec(`INSERT INTO oak_entries (scientific_name, author, notes) VALUES ('alba', 'L.', '')`); err != nil {
+		t.Errorf("migrated columns missing: %v", err)
+	}
+	if done, err := m.Up(); err != nil || len(done) != 0 {
+		t.Errorf("Up() when current = %v, %v; want nothing", done, err)
+	}
+
+	// Migration 3 has no down file, so reverting past it changes nothing
+	if _, err := m.To(1); !errors.Is(err, ErrIrreversible) || !strings.Contains(err.Error(), "0003_backfill") {
+		t.Errorf("To(1) error = %v, want ErrIrreversible for 0003_backfill", err)
+	}
+	if v, _ := m.Version(); v != 3 {
+		t.Errorf("Version() after refused revert = %d, want 3", v)
+	}
+
+	m = New(conn, testMigrations[:2])
+	if _, err := m.Check(); !IsNewerSchema(err) {
+		t.Fatalf("Check() with fewer migrations = %v, want NewerSchemaError", err)
+	}
+	if _, err := m.Up(); !IsNewerSchema(err) {
+		t.Errorf("Up() on a newer schema = %v, want NewerSchemaError", err)
+	}
+	status, err := m.Status()
+	if err != nil || len(status) != 3 || status[2].Name != "backfill" || status[2].AppliedAt == "" {
+		t.Errorf("Status() on a newer schema = %+v, %v; want the unknown migration 3 listed", status, err)
+	}
+
+	if _, err := conn.Exec(`DELETE FROM schema_version WHERE version = 3`); err != nil {
+		t.Fatal(err)
+	}
+	done, err = m.To(0)
+	if err != nil || len(done) != 2 || done[0].Version != 2 || done[1].Version != 1 {
+		t.Fatalf("To(0) = %v, %v; want migrations 2 then 1", done, err)
+	}
+	if _, err := conn.Exec(`SELECT author FROM oak_entries`); err == nil {
+		t.Error("author column still present after reverting")
+	}
+	status, _ = m.Status()
+	for _, s := range status {
+		if s.AppliedAt != "" {
+			t.Errorf("migration %d still applied after To(0)", s.Version)
+		}
+	}
+}
+
+func TestMigrateFailureRollsBack(t *testing.T) {
+	conn := testDB(t)
+	m := New(conn, []Migration{
+		testMigrations[0],
+		{Version: 2, Name: "broken", Up: `ALTER TABLE oak_entries ADD COLUMN extra TEXT; SELECT * FROM missing`},
+	})
+	done, err := m.Up()
+	if err == nil || len(done) != 1 {
+		t.Fatalf("Up() = %v, %v; want migration 1 then an error", done, err)
+	}
+	if v, _ := m.Version(); v != 1 {
+		t.Errorf("Version() after failed migration = %d, want 1", v)
+	}
+	if _, err := conn.Exec(`SELECT extra FROM oak_entries`); err == nil {
+		t.Error("failed migration was partly applied")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0002_second.up.sql":   {Data: []byte("SELECT 2")},
+		"m/0001_first.up.sql":    {Data: []byte("SELECT 1")},
+		"m/0001_first.down.sql":  {Data: []byte("SELECT -1")},
+		"m/0003_third.up.sql":    {Data: []byte("SELECT 3")},
+		"m/0003_third.down.sql":  {Data: []byte("SELECT -3")},
+		"other/0001_x.up.sql":    {Data: []byte("SELECT 1")},
+		"other/0003_x.up.sql":    {Data: []byte("SELECT 3")},
+		"bad/0001_x.sql":         {Data: []byte("SELECT 1")},
+		"nameless/0001_a.up.sql": {Data: []byte("SELECT 1")},
+		"nameless/0001_b.up.sql": {Data: []byte("SELECT 1")},
+	}
+	migrations, err := Load(fsys, "m")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(migrations) != 3 || migrations[0].String() != "0001_first" || migrations[1].Reversible() || migrations[2].Down != "SELECT -3" {
+		t.Errorf("Load() = %+v", migrations)
+	}
+	for _, dir := range []string{"other", "bad", "nameless"} {
+		if _, err := Load(fsys, dir); err == nil {
+			t.Errorf("Load(%s) succeeded, want error", dir)
+		}
+	}
+}
+
+func TestSchema(t *testing.T) {
+	if len(Schema) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+	for i, m := range Schema {
+		if m.Version != i+1 {
+			t.Errorf("Schema[%d] is version %d", i, m.Version)
+		}
+	}
+}
+
+func TestNewerSchemaError(t *testing.T) {
+	err := error(&NewerSchemaError{Version: 5, Latest: 3})
+	if !IsNewerSchema(errors.Join(errors.New("open"), err)) {
+		t.Error("IsNewerSchema does not see a wrapped NewerSchemaError")
+	}
+	if !strings.Contains(err.Error(), "version 5 is newer") {
+		t.Errorf("Error() = %s", err)
+	}
+}
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_oak_entries_genus;
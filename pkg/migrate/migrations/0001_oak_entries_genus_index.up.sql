@@ -0,0 +1,2 @@
+-- Species are listed and exported by genus
+CREATE INDEX IF NOT EXISTS idx_oak_entries_genus ON oak_entries(genus);
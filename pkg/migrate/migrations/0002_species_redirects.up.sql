@@ -0,0 +1,8 @@
+-- Slugs of species that were renamed or merged into another, so links to
+-- their old pages lead to the species they became
+CREATE TABLE species_redirects (
+	slug TEXT PRIMARY KEY,
+	scientific_name TEXT NOT NULL,
+	created_at TEXT NOT NULL
+);
+CREATE INDEX idx_species_redirects_name ON species_redirects(scientific_name);
@@ -0,0 +1,9 @@
+-- API keys given to collaborators, each with a role. Only a key's SHA-256 is
+-- stored; id is the start of it, as key usage is tracked under.
+CREATE TABLE api_keys (
+	id TEXT PRIMARY KEY,
+	key_hash TEXT NOT NULL UNIQUE,
+	name TEXT NOT NULL,
+	role TEXT NOT NULL CHECK (role IN ('read', 'editor', 'admin')),
+	created_at TEXT NOT NULL
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS oak_entry_revisions;
@@ -0,0 +1,9 @@
+-- Earlier versions of oak entries, each saved as the JSON of the entry when
+-- a change replaced it, so an accidental edit can be undone
+CREATE TABLE oak_entry_revisions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	scientific_name TEXT NOT NULL,
+	entry TEXT NOT NULL,
+	created_at TEXT NOT NULL
+);
+CREATE INDEX idx_oak_entry_revisions_name ON oak_entry_revisions(scientific_name, id);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS trg_species_sources_added;
+DROP TABLE IF EXISTS species_source_additions;
@@ -0,0 +1,15 @@
+-- When each source was first added to each species, for the species
+-- timeline. A trigger records it whatever writes the row; rows stay after the
+-- source data is removed, and a replaced row keeps its first time.
+CREATE TABLE species_source_additions (
+	scientific_name TEXT NOT NULL,
+	source_id INTEGER NOT NULL,
+	created_at TEXT NOT NULL,
+	PRIMARY KEY (scientific_name, source_id)
+);
+CREATE TRIGGER trg_species_sources_added
+	AFTER INSERT ON species_sources
+	BEGIN
+		INSERT OR IGNORE INTO species_source_additions (scientific_name, source_id, created_at)
+		VALUES (NEW.scientific_name, NEW.source_id, strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+	END;
@@ -0,0 +1,7 @@
+-- Per-deployment overrides of feature flags, set by an admin at runtime.
+-- A flag with no row here takes its value from OAK_FEATURES or its default.
+CREATE TABLE feature_flags (
+	name TEXT PRIMARY KEY,
+	enabled INTEGER NOT NULL,
+	updated_at TEXT NOT NULL
+);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trg_sources_custom_attributes;
+DROP TRIGGER IF EXISTS trg_oak_entries_custom_attributes;
+DROP TABLE IF EXISTS custom_attributes;
+DROP TABLE IF EXISTS custom_fields;
@@ -0,0 +1,30 @@
+-- Custom fields a deployment defines for its own metadata, such as an
+-- accession policy, and their values per species or source. Values are
+-- stored as text and typed by their field. Triggers drop a record's values
+-- when it is deleted (a replaced row keeps them).
+CREATE TABLE custom_fields (
+	key TEXT PRIMARY KEY,
+	type TEXT NOT NULL CHECK (type IN ('text', 'number', 'boolean', 'date', 'url')),
+	label TEXT NOT NULL,
+	applies_to TEXT NOT NULL CHECK (applies_to IN ('species', 'source')),
+	export INTEGER NOT NULL DEFAULT 0,
+	created_at TEXT NOT NULL
+);
+CREATE TABLE custom_attributes (
+	entity TEXT NOT NULL,
+	entity_id TEXT NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	PRIMARY KEY (entity, entity_id, key)
+);
+CREATE INDEX idx_custom_attributes_key ON custom_attributes (key);
+CREATE TRIGGER trg_oak_entries_custom_attributes
+	AFTER DELETE ON oak_entries
+	BEGIN
+		DELETE FROM custom_attributes WHERE entity = 'species' AND entity_id = OLD.scientific_name;
+	END;
+CREATE TRIGGER trg_sources_custom_attributes
+	AFTER DELETE ON sources
+	BEGIN
+		DELETE FROM custom_attributes WHERE entity = 'source' AND entity_id = CAST(OLD.id AS TEXT);
+	END;
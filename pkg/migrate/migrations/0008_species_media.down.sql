@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS trg_oak_entries_species_media;
+DROP TABLE IF EXISTS species_media;
@@ -0,0 +1,24 @@
+-- Photos and other media of a species. Each row is a URL with its license
+-- and attribution, and the origin it came from: "inaturalist" for photo URLs
+-- cached from iNaturalist, whose ID there is origin_id. A trigger drops a
+-- species' media when it is deleted (a replaced row keeps them).
+CREATE TABLE species_media (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	scientific_name TEXT NOT NULL,
+	url TEXT NOT NULL,
+	thumbnail_url TEXT,
+	caption TEXT,
+	license TEXT,
+	attribution TEXT,
+	origin TEXT NOT NULL,
+	origin_id TEXT,
+	position INTEGER NOT NULL DEFAULT 0,
+	created_at TEXT NOT NULL,
+	UNIQUE (scientific_name, origin, origin_id)
+);
+CREATE INDEX idx_species_media_name ON species_media (scientific_name, position);
+CREATE TRIGGER trg_oak_entries_species_media
+	AFTER DELETE ON oak_entries
+	BEGIN
+		DELETE FROM species_media WHERE scientific_name = OLD.scientific_name;
+	END;
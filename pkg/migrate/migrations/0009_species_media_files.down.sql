@@ -0,0 +1,8 @@
+ALTER TABLE species_media DROP COLUMN longitude;
+ALTER TABLE species_media DROP COLUMN latitude;
+ALTER TABLE species_media DROP COLUMN captured_at;
+ALTER TABLE species_media DROP COLUMN height;
+ALTER TABLE species_media DROP COLUMN width;
+ALTER TABLE species_media DROP COLUMN filename;
+ALTER TABLE species_media DROP COLUMN thumbnail_file;
+ALTER TABLE species_media DROP COLUMN file;
@@ -0,0 +1,15 @@
+-- Uploaded photos. file and thumbnail_file name the image and its JPEG
+-- thumbnail in the server's media directory (OAK_MEDIA_DIR), where url and
+-- thumbnail_url serve them; an upload's origin is "upload" and its origin_id
+-- the SHA-256 of the file, so the same photo is attached to a species once.
+-- filename is the name it was uploaded under, and captured_at, latitude, and
+-- longitude come from its EXIF data: captured_at as the camera recorded it,
+-- local time unless it carries an offset.
+ALTER TABLE species_media ADD COLUMN file TEXT;
+ALTER TABLE species_media ADD COLUMN thumbnail_file TEXT;
+ALTER TABLE species_media ADD COLUMN filename TEXT;
+ALTER TABLE species_media ADD COLUMN width INTEGER;
+ALTER TABLE species_media ADD COLUMN height INTEGER;
+ALTER TABLE species_media ADD COLUMN captured_at TEXT;
+ALTER TABLE species_media ADD COLUMN latitude REAL;
+ALTER TABLE species_media ADD COLUMN longitude REAL;
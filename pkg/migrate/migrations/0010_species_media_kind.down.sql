@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_sources_species_media;
+ALTER TABLE species_media DROP COLUMN source_id;
+ALTER TABLE species_media DROP COLUMN kind;
@@ -0,0 +1,10 @@
+-- What each photo shows (kind: leaf, bark, acorn, ...) and the source it
+-- comes from. Deleting a source keeps its photos, uncited. Media linked by
+-- URL rather than uploaded has origin "link" and its URL as origin_id.
+ALTER TABLE species_media ADD COLUMN kind TEXT;
+ALTER TABLE species_media ADD COLUMN source_id INTEGER REFERENCES sources(id);
+CREATE TRIGGER trg_sources_species_media
+	AFTER DELETE ON sources
+	BEGIN
+		UPDATE species_media SET source_id = NULL WHERE source_id = OLD.id;
+	END;
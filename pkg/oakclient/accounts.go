@@ -0,0 +1,75 @@
+package oakclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// AccountRequest represents the request body for saving a species account.
+type AccountRequest struct {
+	Markdown string `json:"markdown"`
+}
+
+// GetSpeciesAccount retrieves a species' long-form account.
+func (c *Client) GetSpeciesAccount(ctx context.Context, name string) (*SpeciesAccount, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/species/"+url.PathEscape(name)+"/account", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var account SpeciesAccount
+	if err := c.parseResponse(resp, &account); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// SaveSpeciesAccount creates or replaces a species' long-form account.
+func (c *Client) SaveSpeciesAccount(ctx context.Context, name, markdown string) (*SpeciesAccount, error) {
+	resp, err := c.doRequest(ctx, http.MethodPut, "/api/v1/species/"+url.PathEscape(name)+"/account", &AccountRequest{Markdown: markdown})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var account SpeciesAccount
+	if err := c.parseResponse(resp, &account); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// DeleteSpeciesAccount deletes a species' long-form account.
+func (c *Client) DeleteSpeciesAccount(ctx context.Context, name string) error {
+	resp, err := c.doRequest(ctx, http.MethodDelete, "/api/v1/species/"+url.PathEscape(name)+"/account", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
+
+// ListSpeciesMentions retrieves the species whose accounts or source notes mention this one.
+func (c *Client) ListSpeciesMentions(ctx context.Context, name string) ([]*CrossReference, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/species/"+url.PathEscape(name)+"/mentions", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var refs []*CrossReference
+	if err := c.parseResponse(resp, &refs); err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
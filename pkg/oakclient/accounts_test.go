@@ -0,0 +1,91 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSaveSpeciesAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.EscapedPath() != "/api/v1/species/%C3%97%20bebbiana/account" {
+			t.Errorf("request = %s %s, want PUT /api/v1/species/%%C3%%97%%20bebbiana/account", r.Method, r.URL.EscapedPath())
+		}
+		var req AccountRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(SpeciesAccount{
+			ScientificName: "× bebbiana",
+			Markdown:       req.Markdown,
+			Links:          []string{"alba", "macrocarpa"},
+			MissingLinks:   []string{"macrocarpa"},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	account, err := c.SaveSpeciesAccount(t.Context(), "× bebbiana", "Hybrid of [[alba]] and [[macrocarpa]].")
+	if err != nil {
+		t.Fatalf("SaveSpeciesAccount() error = %v", err)
+	}
+	if account.Markdown != "Hybrid of [[alba]] and [[macrocarpa]]." || len(account.MissingLinks) != 1 {
+		t.Errorf("account = %+v", account)
+	}
+}
+
+func TestGetSpeciesAccount_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": map[string]string{"code": "NOT_FOUND", "message": "Account not found: alba"},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	if _, err := c.GetSpeciesAccount(t.Context(), "alba"); !IsNotFoundError(err) {
+		t.Errorf("GetSpeciesAccount() error = %v, want not found", err)
+	}
+}
+
+func TestDeleteSpeciesAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/api/v1/species/alba/account" {
+			t.Errorf("request = %s %s, want DELETE /api/v1/species/alba/account", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	if err := c.DeleteSpeciesAccount(t.Context(), "alba"); err != nil {
+		t.Errorf("DeleteSpeciesAccount() error = %v", err)
+	}
+}
+
+func TestListSpeciesMentions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/species/stellata/mentions" {
+			t.Errorf("request = %s %s, want GET /api/v1/species/stellata/mentions", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]CrossReference{
+			{ScientificName: "alba", MentionedName: "stellata", Location: "sources/2/leaves", Context: "resembles Q. stellata"},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	refs, err := c.ListSpeciesMentions(t.Context(), "stellata")
+	if err != nil {
+		t.Fatalf("ListSpeciesMentions() error = %v", err)
+	}
+	if len(refs) != 1 || refs[0].ScientificName != "alba" || refs[0].Location != "sources/2/leaves" {
+		t.Errorf("refs = %+v", refs)
+	}
+}
@@ -0,0 +1,440 @@
+package oakclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ReindexStep reports the outcome of one step of a reindex.
+type ReindexStep struct {
+	Name       string `json:"name"`
+	Items      int    `json:"items"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// ReindexReport is the response from the admin reindex endpoint.
+type ReindexReport struct {
+	Steps      []ReindexStep `json:"steps"`
+	DurationMs int64         `json:"duration_ms"`
+}
+
+// Reindex rebuilds all derived data on the server (hybrid back-references,
+// indexes, planner statistics).
+func (c *Client) Reindex(ctx context.Context) (*ReindexReport, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/admin/reindex", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var report ReindexReport
+	if err := c.parseResponse(resp, &report); err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// RepairPreferredResponse lists species whose duplicate preferred flags were cleared.
+type RepairPreferredResponse struct {
+	Repaired []string `json:"repaired"`
+}
+
+// RepairPreferredSources clears extra preferred-source flags so each species
+// has at most one preferred source.
+func (c *Client) RepairPreferredSources(ctx context.Context) (*RepairPreferredResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/admin/repair-preferred-sources", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result RepairPreferredResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DataError is a stored JSON column that does not decode.
+type DataError struct {
+	Table  string `json:"table"`
+	Key    string `json:"key"`
+	Column string `json:"column"`
+	Error  string `json:"error"`
+	Value  string `json:"value"`
+}
+
+// DataErrorsResponse lists corrupt JSON columns found or repaired.
+type DataErrorsResponse struct {
+	Errors []DataError `json:"errors"`
+}
+
+// ListDataErrors lists stored JSON columns that do not decode. Reads return
+// such rows with a data_error marker rather than failing.
+func (c *Client) ListDataErrors(ctx context.Context) (*DataErrorsResponse, error) {
+	return c.dataErrors(ctx, http.MethodGet, "/api/v1/admin/data-errors")
+}
+
+// RepairJSONColumns resets corrupt JSON columns to empty lists and returns
+// what was reset, with the original values.
+func (c *Client) RepairJSONColumns(ctx context.Context) (*DataErrorsResponse, error) {
+	return c.dataErrors(ctx, http.MethodPost, "/api/v1/admin/repair-json")
+}
+
+func (c *Client) dataErrors(ctx context.Context, method, path string) (*DataErrorsResponse, error) {
+	resp, err := c.doRequest(ctx, method, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result DataErrorsResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// HybridsDiscrepancy is a species whose stored hybrids list differs from the
+// one derived from the parent links of every entry.
+type HybridsDiscrepancy struct {
+	ScientificName string   `json:"scientific_name"`
+	Hybrids        []string `json:"hybrids"`           // The derived list
+	Missing        []string `json:"missing,omitempty"` // Derived but not stored
+	Extra          []string `json:"extra,omitempty"`   // Stored but not derived
+	Error          string   `json:"error,omitempty"`   // Why the stored list could not be read
+}
+
+// HybridsResponse lists species whose hybrids lists differ from their
+// parent links.
+type HybridsResponse struct {
+	Species []HybridsDiscrepancy `json:"species"`
+}
+
+// CheckHybrids compares every species' hybrids list with the one derived
+// from parent1/parent2 across all entries, changing nothing. Requires an API
+// key.
+func (c *Client) CheckHybrids(ctx context.Context) (*HybridsResponse, error) {
+	return c.hybrids(ctx, http.MethodGet, "/api/v1/admin/hybrids")
+}
+
+// RebuildHybrids rewrites every hybrids list that differs from the one
+// derived from parent links and returns the differences.
+func (c *Client) RebuildHybrids(ctx context.Context) (*HybridsResponse, error) {
+	return c.hybrids(ctx, http.MethodPost, "/api/v1/admin/rebuild-hybrids")
+}
+
+func (c *Client) hybrids(ctx context.Context, method, path string) (*HybridsResponse, error) {
+	resp, err := c.doRequest(ctx, method, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result HybridsResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// NameProblem is a stored species name that is not in canonical form.
+type NameProblem struct {
+	ScientificName string `json:"scientific_name"`
+	Problem        string `json:"problem"`
+	Suggestion     string `json:"suggestion,omitempty"` // Canonical form, when canonicalizing fixes the name
+	Conflicts      bool   `json:"conflicts,omitempty"`  // Suggestion is already another species' name
+}
+
+// NameReportResponse lists species whose names are not in canonical form.
+type NameReportResponse struct {
+	Names []NameProblem `json:"names"`
+}
+
+// GetNameReport lists species, drafts included, whose names were stored
+// before names were validated and are not in canonical form. Requires an
+// API key.
+func (c *Client) GetNameReport(ctx context.Context) (*NameReportResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/admin/name-report", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result NameReportResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// QueryRequest is ad-hoc SQL for the admin query endpoint. Zero Limit and
+// TimeoutMs use the server's defaults (1000 rows, 5 seconds).
+type QueryRequest struct {
+	SQL       string `json:"sql"`
+	Limit     int    `json:"limit,omitempty"`
+	TimeoutMs int    `json:"timeout_ms,omitempty"`
+}
+
+// QueryResult holds the rows an ad-hoc query returned, in column order.
+type QueryResult struct {
+	Columns    []string        `json:"columns"`
+	Rows       [][]interface{} `json:"rows"`
+	Truncated  bool            `json:"truncated"` // More rows matched than the limit allowed
+	DurationMs float64         `json:"duration_ms"`
+}
+
+// Query runs read-only SQL on the server's database. The server refuses
+// anything that would write, with a validation error, as it does syntax
+// errors and queries that run past the timeout. Requires an API key.
+func (c *Client) Query(ctx context.Context, req *QueryRequest) (*QueryResult, error) {
+	resp, err := c.doRequest(WithReadOnly(ctx), http.MethodPost, "/api/v1/admin/query", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result QueryResult
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// MaintenanceStatus reports whether the server refuses writes for maintenance.
+type MaintenanceStatus struct {
+	Enabled bool       `json:"enabled"`
+	Message string     `json:"message,omitempty"`
+	Since   *time.Time `json:"since,omitempty"`
+}
+
+// GetMaintenance returns the server's maintenance state.
+func (c *Client) GetMaintenance(ctx context.Context) (*MaintenanceStatus, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/admin/maintenance", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var status MaintenanceStatus
+	if err := c.parseResponse(resp, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// SetMaintenance turns maintenance mode on, refusing writes with message
+// (empty for the server's default), or off.
+func (c *Client) SetMaintenance(ctx context.Context, on bool, message string) (*MaintenanceStatus, error) {
+	mode := "off"
+	if on {
+		mode = "on"
+	}
+	body := map[string]string{"mode": mode}
+	if message != "" {
+		body["message"] = message
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/admin/maintenance", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var status MaintenanceStatus
+	if err := c.parseResponse(resp, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// SchemaStatus is the database's schema version and every migration the
+// server knows, applied or pending.
+type SchemaStatus struct {
+	Version    int                `json:"version"`
+	Latest     int                `json:"latest"` // The newest version the server can migrate to
+	Migrations []*SchemaMigration `json:"migrations"`
+}
+
+// SchemaMigration is one versioned schema change.
+type SchemaMigration struct {
+	Version    int    `json:"version"`
+	Name       string `json:"name"`
+	AppliedAt  string `json:"applied_at,omitempty"` // Empty if pending
+	Reversible bool   `json:"reversible"`
+}
+
+// SchemaMigrateResponse lists the migrations run by MigrateSchema, named
+// NNNN_name, in the order run.
+type SchemaMigrateResponse struct {
+	From     int      `json:"from"`
+	To       int      `json:"to"`
+	Applied  []string `json:"applied"`
+	Reverted []string `json:"reverted"`
+}
+
+// GetSchemaStatus returns the database's schema version and migrations.
+func (c *Client) GetSchemaStatus(ctx context.Context) (*SchemaStatus, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/admin/schema", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var status SchemaStatus
+	if err := c.parseResponse(resp, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// MigrateSchema upgrades or reverts the schema to version, or to the latest
+// if version is nil.
+func (c *Client) MigrateSchema(ctx context.Context, version *int) (*SchemaMigrateResponse, error) {
+	body := map[string]*int{"version": version}
+	if version == nil {
+		body = map[string]*int{}
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/admin/schema", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result SchemaMigrateResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// API key roles, from least to most permitted
+const (
+	RoleRead   = "read"   // Authenticated reads: drafts, internal fields, curator queues
+	RoleEditor = "editor" // Reads and writes to the data
+	RoleAdmin  = "admin"  // Everything, including administration and managing keys
+)
+
+// APIKeyUsage is an API key, identified by a fingerprint, with its
+// authenticated request count and last use. Days holds daily counts when
+// fetched with GetAPIKeyUsage.
+type APIKeyUsage struct {
+	ID         string           `json:"id"`
+	Current    bool             `json:"current"`        // The server's configured key, which has the admin role
+	Name       string           `json:"name,omitempty"` // Given when the key was created
+	Role       string           `json:"role,omitempty"` // Empty if the key no longer authenticates
+	CreatedAt  *time.Time       `json:"created_at,omitempty"`
+	Requests   int              `json:"requests"`
+	FirstUsed  *string          `json:"first_used,omitempty"` // UTC day
+	LastUsedAt *time.Time       `json:"last_used_at,omitempty"`
+	Days       []*DailyKeyUsage `json:"days,omitempty"`
+}
+
+// DailyKeyUsage is an API key's request count on one UTC day.
+type DailyKeyUsage struct {
+	Day      string `json:"day"`
+	Requests int    `json:"requests"`
+}
+
+// APIKeysListResponse is the paginated list wrapper for API keys.
+type APIKeysListResponse struct {
+	Data []*APIKeyUsage `json:"data"`
+}
+
+// ListAPIKeys lists the server's current key, every created key, and every
+// key with recorded usage: current first, then most recently used, then
+// created keys never used. Requires an admin key.
+func (c *Client) ListAPIKeys(ctx context.Context) ([]*APIKeyUsage, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/admin/keys", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result APIKeysListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// GetAPIKeyUsage returns a key's usage with daily counts over the last days
+// days (0 for the server's default of 30). Requires an admin key.
+func (c *Client) GetAPIKeyUsage(ctx context.Context, id string, days int) (*APIKeyUsage, error) {
+	path := "/api/v1/admin/keys/" + url.PathEscape(id) + "/usage"
+	if days > 0 {
+		path += fmt.Sprintf("?days=%d", days)
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var usage APIKeyUsage
+	if err := c.parseResponse(resp, &usage); err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
+// CreatedAPIKey is a new API key. Key is the only time the key itself is
+// returned; the server keeps only its hash.
+type CreatedAPIKey struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	Key       string    `json:"key"`
+}
+
+// CreateAPIKey creates a key for a collaborator with a name and one of the
+// roles RoleRead, RoleEditor, or RoleAdmin. Requires an admin key.
+func (c *Client) CreateAPIKey(ctx context.Context, name, role string) (*CreatedAPIKey, error) {
+	body := map[string]string{"name": name, "role": role}
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/keys", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var key CreatedAPIKey
+	if err := c.parseResponse(resp, &key); err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// DeleteAPIKey revokes a created key by ID; its usage stays listed. The
+// server's configured key cannot be revoked. Requires an admin key.
+func (c *Client) DeleteAPIKey(ctx context.Context, id string) error {
+	resp, err := c.doRequest(ctx, http.MethodDelete, "/api/v1/keys/"+url.PathEscape(id), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
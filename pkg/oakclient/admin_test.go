@@ -0,0 +1,316 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReindex_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if r.URL.Path != "/api/v1/admin/reindex" {
+			t.Errorf("path = %s, want /api/v1/admin/reindex", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ReindexReport{
+			Steps: []ReindexStep{
+				{Name: "hybrids", Items: 3, DurationMs: 5},
+				{Name: "indexes", Items: 4, DurationMs: 1},
+			},
+			DurationMs: 6,
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	report, err := c.Reindex(t.Context())
+	if err != nil {
+		t.Fatalf("Reindex() error = %v", err)
+	}
+	if len(report.Steps) != 2 {
+		t.Fatalf("got %d steps, want 2", len(report.Steps))
+	}
+	if report.Steps[0].Name != "hybrids" || report.Steps[0].Items != 3 {
+		t.Errorf("first step = %+v", report.Steps[0])
+	}
+}
+
+func TestReindex_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	_, err := c.Reindex(t.Context())
+	if !IsAuthError(err) {
+		t.Errorf("expected auth error, got %v", err)
+	}
+}
+
+func TestRepairPreferredSources_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if r.URL.Path != "/api/v1/admin/repair-preferred-sources" {
+			t.Errorf("path = %s, want /api/v1/admin/repair-preferred-sources", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(RepairPreferredResponse{Repaired: []string{"alba"}})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	result, err := c.RepairPreferredSources(t.Context())
+	if err != nil {
+		t.Fatalf("RepairPreferredSources() error = %v", err)
+	}
+	if len(result.Repaired) != 1 || result.Repaired[0] != "alba" {
+		t.Errorf("Repaired = %v, want [alba]", result.Repaired)
+	}
+}
+
+func TestRepairJSONColumns(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/admin/repair-json" {
+			t.Errorf("request = %s %s, want POST /api/v1/admin/repair-json", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(DataErrorsResponse{Errors: []DataError{
+			{Table: "oak_entries", Key: "alba", Column: "synonyms", Error: "unexpected end of JSON input", Value: `["x"`},
+		}})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	result, err := c.RepairJSONColumns(t.Context())
+	if err != nil {
+		t.Fatalf("RepairJSONColumns() error = %v", err)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Key != "alba" || result.Errors[0].Value != `["x"` {
+		t.Errorf("Errors = %+v", result.Errors)
+	}
+}
+
+func TestRebuildHybrids(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/admin/rebuild-hybrids" {
+			t.Errorf("request = %s %s, want POST /api/v1/admin/rebuild-hybrids", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(HybridsResponse{Species: []HybridsDiscrepancy{
+			{ScientificName: "alba", Hybrids: []string{"× bebbiana"}, Missing: []string{"× bebbiana"}},
+		}})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	result, err := c.RebuildHybrids(t.Context())
+	if err != nil {
+		t.Fatalf("RebuildHybrids() error = %v", err)
+	}
+	if len(result.Species) != 1 || result.Species[0].ScientificName != "alba" || len(result.Species[0].Missing) != 1 {
+		t.Errorf("Species = %+v", result.Species)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/admin/query" {
+			t.Errorf("request = %s %s, want POST /api/v1/admin/query", r.Method, r.URL.Path)
+		}
+		var req QueryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.SQL != "SELECT 1 AS one" || req.Limit != 5 {
+			t.Errorf("body = %+v (err %v)", req, err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(QueryResult{Columns: []string{"one"}, Rows: [][]interface{}{{1}}})
+	}))
+	defer server.Close()
+
+	// The POST is marked read-only for transports that act on writes
+	transport := &readOnlyRecorder{next: http.DefaultTransport}
+	c, err := New(server.URL, WithAPIKey("test-api-key"), WithProfileName("test"), WithSkipVersionCheck(true), WithTransport(transport))
+	if err != nil {
+		t.Fatalf("failed to create test client: %v", err)
+	}
+	result, err := c.Query(t.Context(), &QueryRequest{SQL: "SELECT 1 AS one", Limit: 5})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(result.Rows) != 1 || result.Rows[0][0] != float64(1) || result.Columns[0] != "one" {
+		t.Errorf("result = %+v", result)
+	}
+	if !transport.readOnly {
+		t.Error("IsReadOnly() = false for the query request")
+	}
+}
+
+// readOnlyRecorder is a transport recording whether the last request was
+// read-only.
+type readOnlyRecorder struct {
+	next     http.RoundTripper
+	readOnly bool
+}
+
+func (r *readOnlyRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.readOnly = IsReadOnly(req)
+	return r.next.RoundTrip(req)
+}
+
+func TestSetMaintenance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/admin/maintenance" {
+			t.Errorf("request = %s %s, want POST /api/v1/admin/maintenance", r.Method, r.URL.Path)
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["mode"] != "on" || body["message"] != "Restoring backup" {
+			t.Errorf("body = %v", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(MaintenanceStatus{Enabled: true, Message: body["message"]})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	status, err := c.SetMaintenance(t.Context(), true, "Restoring backup")
+	if err != nil {
+		t.Fatalf("SetMaintenance() error = %v", err)
+	}
+	if !status.Enabled || status.Message != "Restoring backup" {
+		t.Errorf("status = %+v", status)
+	}
+}
+
+func TestMigrateSchema(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/admin/schema" {
+			t.Errorf("request = %s %s, want POST /api/v1/admin/schema", r.Method, r.URL.Path)
+		}
+		var body map[string]int
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["version"] != 0 {
+			t.Errorf("body = %v", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SchemaMigrateResponse{From: 1, To: 0, Reverted: []string{"0001_oak_entries_genus_index"}})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	version := 0
+	result, err := c.MigrateSchema(t.Context(), &version)
+	if err != nil {
+		t.Fatalf("MigrateSchema() error = %v", err)
+	}
+	if result.To != 0 || len(result.Reverted) != 1 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestMaintenanceErrorNotRetried(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":{"code":"MAINTENANCE","message":"Restoring backup"}}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	_, err := c.Reindex(t.Context())
+	if !IsMaintenanceError(err) {
+		t.Fatalf("expected maintenance error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Restoring backup") {
+		t.Errorf("error = %v, want the server's message", err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1 (no retries)", requests)
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/admin/keys":
+			w.Write([]byte(`{"data":[{"id":"4c806362b613","current":true,"requests":12,"last_used_at":"2026-10-16T07:58:47Z"},
+				{"id":"0123456789ab","current":false,"requests":3,"first_used":"2026-04-01","last_used_at":"2026-04-16T10:00:00Z"}]}`))
+		case "/api/v1/admin/keys/4c806362b613/usage":
+			if r.URL.Query().Get("days") != "7" {
+				t.Errorf("days = %q, want 7", r.URL.Query().Get("days"))
+			}
+			w.Write([]byte(`{"id":"4c806362b613","current":true,"requests":12,"days":[{"day":"2026-10-16","requests":5}]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	keys, err := c.ListAPIKeys(t.Context())
+	if err != nil {
+		t.Fatalf("ListAPIKeys() error = %v", err)
+	}
+	if len(keys) != 2 || !keys[0].Current || keys[1].Current || keys[1].FirstUsed == nil {
+		t.Errorf("keys = %+v", keys)
+	}
+
+	usage, err := c.GetAPIKeyUsage(t.Context(), "4c806362b613", 7)
+	if err != nil {
+		t.Fatalf("GetAPIKeyUsage() error = %v", err)
+	}
+	if usage.Requests != 12 || len(usage.Days) != 1 || usage.Days[0].Requests != 5 {
+		t.Errorf("usage = %+v", usage)
+	}
+}
+
+func TestCreateAndDeleteAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/keys":
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["name"] != "Collaborator" || body["role"] != RoleEditor {
+				t.Errorf("body = %v (err %v)", body, err)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"0123456789ab","name":"Collaborator","role":"editor","created_at":"2026-10-16T09:00:00Z","key":"secret"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/keys/0123456789ab":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	key, err := c.CreateAPIKey(t.Context(), "Collaborator", RoleEditor)
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	if key.ID != "0123456789ab" || key.Key != "secret" || key.Role != RoleEditor {
+		t.Errorf("key = %+v", key)
+	}
+	if err := c.DeleteAPIKey(t.Context(), key.ID); err != nil {
+		t.Errorf("DeleteAPIKey() error = %v", err)
+	}
+}
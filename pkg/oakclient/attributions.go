@@ -0,0 +1,33 @@
+package oakclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Attribution page formats accepted by Attributions
+const (
+	AttributionFormatJSON     = "json"
+	AttributionFormatMarkdown = "markdown"
+	AttributionFormatHTML     = "html"
+)
+
+// Attributions retrieves the attribution page listing every source with its
+// license, URL, and the species it contributed to, rendered in format.
+func (c *Client) Attributions(ctx context.Context, format string) ([]byte, error) {
+	path := "/api/v1/attributions?format=" + url.QueryEscape(format)
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,30 @@
+package oakclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAttributions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/attributions" {
+			t.Errorf("path = %s, want /api/v1/attributions", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("format"); got != AttributionFormatMarkdown {
+			t.Errorf("format = %q, want markdown", got)
+		}
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		w.Write([]byte("# Sources and Attributions\n"))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	data, err := c.Attributions(t.Context(), AttributionFormatMarkdown)
+	if err != nil {
+		t.Fatalf("Attributions() error = %v", err)
+	}
+	if string(data) != "# Sources and Attributions\n" {
+		t.Errorf("data = %q", data)
+	}
+}
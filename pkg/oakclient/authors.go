@@ -0,0 +1,75 @@
+package oakclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Author is a botanical author and their standard abbreviation, as listed by IPNI.
+type Author struct {
+	Abbreviation string  `json:"abbreviation"`
+	FullName     string  `json:"full_name"`
+	Dates        *string `json:"dates,omitempty"`
+	IPNIID       *string `json:"ipni_id,omitempty"`
+}
+
+// AuthorsListResponse contains the list of authors.
+type AuthorsListResponse struct {
+	Data       []*Author  `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// AuthorsUpsertResult reports how many authors a bulk upsert created and replaced.
+type AuthorsUpsertResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// ListAuthors retrieves every author ordered by abbreviation.
+func (c *Client) ListAuthors(ctx context.Context) ([]*Author, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/authors", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result AuthorsListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// GetAuthor retrieves an author by standard abbreviation, e.g. "Münchh.".
+func (c *Client) GetAuthor(ctx context.Context, abbreviation string) (*Author, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/authors/"+url.PathEscape(abbreviation), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var author Author
+	if err := c.parseResponse(resp, &author); err != nil {
+		return nil, err
+	}
+
+	return &author, nil
+}
+
+// UpsertAuthors creates or replaces the given authors. Authors not listed are kept.
+func (c *Client) UpsertAuthors(ctx context.Context, authors []*Author) (*AuthorsUpsertResult, error) {
+	resp, err := c.doRequest(ctx, http.MethodPut, "/api/v1/authors", authors)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result AuthorsUpsertResult
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
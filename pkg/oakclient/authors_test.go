@@ -0,0 +1,53 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetAuthor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.EscapedPath() != "/api/v1/authors/M%C3%BCnchh." {
+			t.Errorf("request = %s %s, want GET /api/v1/authors/M%%C3%%BCnchh.", r.Method, r.URL.EscapedPath())
+		}
+		dates := "1716-1774"
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Author{Abbreviation: "Münchh.", FullName: "Otto von Münchhausen", Dates: &dates})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	author, err := c.GetAuthor(t.Context(), "Münchh.")
+	if err != nil {
+		t.Fatalf("GetAuthor() error = %v", err)
+	}
+	if author.FullName != "Otto von Münchhausen" || author.Dates == nil || *author.Dates != "1716-1774" {
+		t.Errorf("author = %+v", author)
+	}
+}
+
+func TestUpsertAuthors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/authors" {
+			t.Errorf("request = %s %s, want PUT /api/v1/authors", r.Method, r.URL.Path)
+		}
+		var authors []*Author
+		if err := json.NewDecoder(r.Body).Decode(&authors); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(AuthorsUpsertResult{Created: len(authors)})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	result, err := c.UpsertAuthors(t.Context(), []*Author{{Abbreviation: "L.", FullName: "Carl Linnaeus"}, {Abbreviation: "Sarg.", FullName: "Charles Sprague Sargent"}})
+	if err != nil {
+		t.Fatalf("UpsertAuthors() error = %v", err)
+	}
+	if result.Created != 2 {
+		t.Errorf("result = %+v, want 2 created", result)
+	}
+}
@@ -0,0 +1,314 @@
+package oakclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCapturedBody is the largest body stored in a capture file. Larger bodies
+// (typically exports) are truncated and marked as such.
+const maxCapturedBody = 1 << 20
+
+// redactedValue replaces secret header and body values in capture files.
+const redactedValue = "[REDACTED]"
+
+// sensitiveBodyFields are JSON body fields never written to capture files in
+// the clear, at any depth.
+var sensitiveBodyFields = map[string]bool{
+	"token":    true,
+	"password": true,
+	"secret":   true,
+	"api_key":  true,
+}
+
+// keysPath is where API keys are created; its bodies' "key" fields are the
+// keys themselves, where elsewhere (custom fields, hashes) "key" is no secret.
+const keysPath = "/api/v1/keys"
+
+// sensitiveHeaders are never written to capture files in the clear.
+var sensitiveHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+	"X-Api-Key":     true,
+}
+
+// CapturedRequest is the request half of a captured exchange.
+type CapturedRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"` // Path and query, relative to the profile URL
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// CapturedResponse is the response half of a captured exchange.
+type CapturedResponse struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	Truncated  bool              `json:"truncated,omitempty"`
+}
+
+// CapturedExchange is one HTTP request/response pair as written by WithCapture.
+type CapturedExchange struct {
+	Sequence   int               `json:"sequence"`
+	Time       time.Time         `json:"time"`
+	Profile    string            `json:"profile"`
+	DurationMs int64             `json:"duration_ms"`
+	Request    CapturedRequest   `json:"request"`
+	Response   *CapturedResponse `json:"response,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// WithCapture records every HTTP exchange made by the client as a JSON file in dir.
+// Secret headers (Authorization, cookies) and secret JSON body fields (tokens,
+// passwords, created API keys) are redacted before writing.
+func WithCapture(dir string) Option {
+	return func(c *Client) {
+		c.captureDir = dir
+	}
+}
+
+// captureTransport is an http.RoundTripper that records exchanges to disk.
+type captureTransport struct {
+	next    http.RoundTripper
+	dir     string
+	baseURL string
+	profile string
+	prefix  string // Run timestamp, keeps files from separate runs ordered and distinct
+
+	mu  sync.Mutex
+	seq int
+}
+
+func newCaptureTransport(next http.RoundTripper, dir, baseURL, profile string) (*captureTransport, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create capture directory: %w", err)
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &captureTransport{
+		next:    next,
+		dir:     dir,
+		baseURL: baseURL,
+		profile: profile,
+		prefix:  time.Now().UTC().Format("20060102T150405"),
+	}, nil
+}
+
+func (t *captureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.seq++
+	seq := t.seq
+	t.mu.Unlock()
+
+	exchange := CapturedExchange{
+		Sequence: seq,
+		Time:     time.Now().UTC(),
+		Profile:  t.profile,
+		Request: CapturedRequest{
+			Method:  req.Method,
+			Path:    strings.TrimPrefix(req.URL.String(), t.baseURL),
+			Headers: redactHeaders(req.Header),
+		},
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		exchange.Request.Body = redactBody(exchange.Request.Path, data)
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	exchange.DurationMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		exchange.Error = err.Error()
+	} else {
+		data, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			return nil, readErr
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+
+		captured := &CapturedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    redactHeaders(resp.Header),
+		}
+		if len(data) > maxCapturedBody {
+			captured.Body = redactBody(exchange.Request.Path, data[:maxCapturedBody])
+			captured.Truncated = true
+		} else {
+			captured.Body = redactBody(exchange.Request.Path, data)
+		}
+		exchange.Response = captured
+	}
+
+	// Capture is best-effort; never fail the real request because of it
+	_ = t.write(&exchange)
+
+	return resp, err
+}
+
+func (t *captureTransport) write(exchange *CapturedExchange) error {
+	data, err := json.MarshalIndent(exchange, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%04d-%s.json", t.prefix, exchange.Sequence, exchange.Request.Method)
+	return os.WriteFile(filepath.Join(t.dir, name), data, 0o600)
+}
+
+// redactHeaders flattens headers for storage, masking secret values.
+func redactHeaders(h http.Header) map[string]string {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(h))
+	for name, values := range h {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			out[name] = redactedValue
+			continue
+		}
+		out[name] = strings.Join(values, ", ")
+	}
+	return out
+}
+
+// redactBody returns a body for storage with the values of secret JSON fields
+// masked. Bodies that aren't JSON, such as truncated ones, are kept as they
+// are, except on the keys path, where they are dropped rather than risk
+// storing a key.
+func redactBody(path string, data []byte) string {
+	onKeysPath := strings.HasPrefix(path, keysPath)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var body any
+	if err := decoder.Decode(&body); err != nil {
+		if onKeysPath {
+			return redactedValue
+		}
+		return string(data)
+	}
+	if !redactValue(body, onKeysPath) {
+		return string(data)
+	}
+	redacted, err := json.Marshal(body)
+	if err != nil {
+		return redactedValue
+	}
+	return string(redacted)
+}
+
+// redactValue masks secret fields in a decoded JSON value in place, reporting
+// whether it masked any
+func redactValue(v any, redactKey bool) bool {
+	redacted := false
+	switch v := v.(type) {
+	case map[string]any:
+		for name, value := range v {
+			if sensitiveBodyFields[strings.ToLower(name)] || (redactKey && name == "key") {
+				v[name] = redactedValue
+				redacted = true
+			} else if redactValue(value, redactKey) {
+				redacted = true
+			}
+		}
+	case []any:
+		for _, value := range v {
+			if redactValue(value, redactKey) {
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
+
+// LoadCapturedExchanges reads capture files from a directory or a single file,
+// returned in the order they were recorded.
+func LoadCapturedExchanges(path string) ([]*CapturedExchange, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	} else {
+		files = []string{path}
+	}
+
+	exchanges := make([]*CapturedExchange, 0, len(files))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		var ex CapturedExchange
+		if err := json.Unmarshal(data, &ex); err != nil {
+			return nil, fmt.Errorf("failed to parse capture file %s: %w", f, err)
+		}
+		exchanges = append(exchanges, &ex)
+	}
+
+	return exchanges, nil
+}
+
+// Replay re-issues a captured request against this client's profile, using the
+// client's own credentials. The recorded Content-Type and If-Match headers
+// are sent again, so a conditional write still fails if the record has
+// changed. No retries are attempted so the first response is reported as-is.
+func (c *Client) Replay(ctx context.Context, ex *CapturedExchange) (*CapturedResponse, error) {
+	var bodyData []byte
+	headers := http.Header{}
+	if etag := ex.Request.Headers["If-Match"]; etag != "" {
+		headers.Set("If-Match", etag)
+	}
+	if ex.Request.Body != "" {
+		bodyData = []byte(ex.Request.Body)
+		contentType := ex.Request.Headers["Content-Type"]
+		if contentType == "" {
+			contentType = ContentTypeJSON
+		}
+		headers.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.executeRequest(ctx, ex.Request.Method, ex.Request.Path, bodyData, headers)
+	if err != nil {
+		return nil, c.wrapConnectionError(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CapturedResponse{
+		StatusCode: resp.StatusCode,
+		Headers:    redactHeaders(resp.Header),
+		Body:       string(data),
+	}, nil
+}
@@ -0,0 +1,159 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCapture_RecordsAndRedacts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(Source{ID: 9, Name: "Field notes"})
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "capture")
+	c, err := New(server.URL, WithAPIKey("secret-key"), WithProfileName("test"), WithSkipVersionCheck(true), WithCapture(dir))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	src, err := c.CreateSource(t.Context(), &SourceRequest{SourceType: "book", Name: "Field notes"})
+	if err != nil {
+		t.Fatalf("CreateSource() error = %v", err)
+	}
+	if src.ID != 9 {
+		t.Errorf("response body not passed through: %+v", src)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("got %d capture files, want 1", len(files))
+	}
+	raw, _ := os.ReadFile(files[0])
+	if strings.Contains(string(raw), "secret-key") {
+		t.Error("capture file contains the API key")
+	}
+
+	exchanges, err := LoadCapturedExchanges(dir)
+	if err != nil {
+		t.Fatalf("LoadCapturedExchanges() error = %v", err)
+	}
+	ex := exchanges[0]
+	if ex.Request.Method != http.MethodPost || ex.Request.Path != "/api/v1/sources" {
+		t.Errorf("request = %s %s", ex.Request.Method, ex.Request.Path)
+	}
+	if ex.Request.Headers["Authorization"] != redactedValue {
+		t.Errorf("Authorization = %q, want redacted", ex.Request.Headers["Authorization"])
+	}
+	if !strings.Contains(ex.Request.Body, "Field notes") {
+		t.Errorf("request body = %q", ex.Request.Body)
+	}
+	if ex.Response == nil || ex.Response.StatusCode != http.StatusCreated {
+		t.Errorf("response = %+v", ex.Response)
+	}
+}
+
+func TestCapture_RedactsSecretBodyFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/keys":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"3f2a","name":"Collaborator","role":"editor","key":"plaintext-new-key"}`))
+		default:
+			w.Write([]byte(`{"key":"wood_color","label":"Wood color","type":"text"}`))
+		}
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "capture")
+	c, err := New(server.URL, WithAPIKey("secret-key"), WithProfileName("test"), WithSkipVersionCheck(true), WithCapture(dir))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	created, err := c.CreateAPIKey(t.Context(), "Collaborator", "editor")
+	if err != nil || created.Key != "plaintext-new-key" {
+		t.Fatalf("CreateAPIKey() = %+v, %v; want the key passed through", created, err)
+	}
+	for _, req := range []struct {
+		method, path string
+		body         any
+	}{
+		{http.MethodPost, "/api/v1/auth/login", map[string]string{"user": "jeff", "password": "hunter2"}},
+		{http.MethodGet, "/api/v1/custom-fields/wood_color", nil},
+	} {
+		resp, err := c.doRequest(t.Context(), req.method, req.path, req.body)
+		if err != nil {
+			t.Fatalf("%s %s error = %v", req.method, req.path, err)
+		}
+		resp.Body.Close()
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 3 {
+		t.Fatalf("got %d capture files, want 3", len(files))
+	}
+	for _, f := range files {
+		raw, _ := os.ReadFile(f)
+		for _, secret := range []string{"plaintext-new-key", "hunter2"} {
+			if strings.Contains(string(raw), secret) {
+				t.Errorf("%s contains %q:\n%s", filepath.Base(f), secret, raw)
+			}
+		}
+	}
+
+	exchanges, err := LoadCapturedExchanges(dir)
+	if err != nil {
+		t.Fatalf("LoadCapturedExchanges() error = %v", err)
+	}
+	var key map[string]string
+	if err := json.Unmarshal([]byte(exchanges[0].Response.Body), &key); err != nil || key["key"] != redactedValue || key["name"] != "Collaborator" {
+		t.Errorf("captured key response = %s, %v; want the key alone redacted", exchanges[0].Response.Body, err)
+	}
+	if !strings.Contains(exchanges[2].Response.Body, `"key":"wood_color"`) {
+		t.Errorf("captured custom field = %s, want its key kept", exchanges[2].Response.Body)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	var gotAuth, gotIfMatch, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotIfMatch = r.Header.Get("If-Match")
+		data, _ := io.ReadAll(r.Body)
+		gotBody = string(data)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	resp, err := c.Replay(t.Context(), &CapturedExchange{Request: CapturedRequest{
+		Method:  http.MethodPut,
+		Path:    "/api/v1/species/alba",
+		Headers: map[string]string{"Authorization": redactedValue, "If-Match": `"v1"`},
+		Body:    `{"scientific_name":"alba"}`,
+	}})
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+	if gotAuth != "Bearer test-api-key" {
+		t.Errorf("replay used Authorization %q, want the client's own key", gotAuth)
+	}
+	if gotIfMatch != `"v1"` {
+		t.Errorf("If-Match = %q, want the recorded one", gotIfMatch)
+	}
+	if gotBody != `{"scientific_name":"alba"}` {
+		t.Errorf("body = %q", gotBody)
+	}
+}
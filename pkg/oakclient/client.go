@@ -0,0 +1,715 @@
+// Package oakclient is a Go client for the Oak Compendium API.
+//
+// Create a client with New and the API's base URL. Every call takes a
+// context, which cancels the request and any retries. API failures are
+// returned as *APIError or *MultiValidationError and unreachable servers as
+// *ConnectionError; all of them match the sentinel errors (ErrNotFound,
+// ErrConflict, ErrValidation, ...) with errors.Is. List endpoints that page
+// have All* methods returning iterators over every record.
+//
+// The oak CLI is built on this package, so it covers every route the CLI uses.
+package oakclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// Version is the client version sent to the API, which rejects clients
+// older than its minimum supported version (see ErrClientTooOld).
+// This should be updated when the client changes.
+const Version = "1.0.0"
+
+// ClientVersionHeader identifies the client version to the API so it can reject
+// clients older than its minimum supported version.
+const ClientVersionHeader = "X-Oak-Client"
+
+// clientVersionValue is sent in ClientVersionHeader on every request.
+const clientVersionValue = "oak-cli/" + Version
+
+// Default retry configuration values.
+const (
+	DefaultMaxRetries     = 3
+	DefaultRetryBaseDelay = 1 * time.Second
+	DefaultRetryMaxDelay  = 10 * time.Second
+)
+
+// Client is an HTTP client for the Oak Compendium API.
+type Client struct {
+	baseURL     string
+	apiKey      string
+	httpClient  *http.Client
+	profileName string
+
+	// Version check state
+	versionChecked bool
+	skipVersion    bool
+
+	// Retry configuration
+	maxRetries     int
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+
+	// Debug capture directory (empty disables capture)
+	captureDir string
+
+	// Called when the API marks a route as deprecated
+	onDeprecation DeprecationHandler
+}
+
+// Deprecation describes a deprecated route, from the API's response headers.
+type Deprecation struct {
+	Method string
+	Path   string
+	Sunset string // HTTP date after which the route may be removed (may be empty)
+	Link   string // Successor route link header (may be empty)
+}
+
+// DeprecationHandler receives deprecation notices for routes the client calls.
+type DeprecationHandler func(Deprecation)
+
+// VersionInfo contains version information from the API server.
+type VersionInfo struct {
+	API       string `json:"api"`
+	MinClient string `json:"min_client"`
+}
+
+// HealthResponse is the response from the health endpoint.
+type HealthResponse struct {
+	Status      string             `json:"status"`
+	Version     VersionInfo        `json:"version"`
+	Maintenance *MaintenanceStatus `json:"maintenance,omitempty"` // Set while writes are disabled
+}
+
+// Option is a functional option for configuring the client.
+type Option func(*Client)
+
+// WithAPIKey sets the API key sent as a bearer token. Reads need none;
+// writes do.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithProfileName names the server in error messages, such as the oak CLI
+// profile the base URL and key came from. Defaults to "default".
+func WithProfileName(name string) Option {
+	return func(c *Client) {
+		if name != "" {
+			c.profileName = name
+		}
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithTransport sets the transport of the client's HTTP client, such as one
+// dialing an embedded server's unix socket. A nil transport is ignored.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		if transport != nil {
+			c.httpClient.Transport = transport
+		}
+	}
+}
+
+// WithSkipVersionCheck disables API version compatibility checking.
+func WithSkipVersionCheck(skip bool) Option {
+	return func(c *Client) {
+		c.skipVersion = skip
+	}
+}
+
+// WithMaxRetries sets the maximum number of retry attempts for transient failures.
+func WithMaxRetries(retries int) Option {
+	return func(c *Client) {
+		if retries >= 0 {
+			c.maxRetries = retries
+		}
+	}
+}
+
+// WithRetryDelay sets the base delay for exponential backoff.
+func WithRetryDelay(base, maxDelay time.Duration) Option {
+	return func(c *Client) {
+		if base > 0 {
+			c.retryBaseDelay = base
+		}
+		if maxDelay > 0 {
+			c.retryMaxDelay = maxDelay
+		}
+	}
+}
+
+// WithDeprecationHandler registers a callback for responses carrying a Deprecation header.
+func WithDeprecationHandler(handler DeprecationHandler) Option {
+	return func(c *Client) {
+		c.onDeprecation = handler
+	}
+}
+
+// WithTimeout sets the HTTP client timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		if timeout > 0 {
+			c.httpClient.Timeout = timeout
+		}
+	}
+}
+
+// New creates a client for the API at baseURL, such as
+// "https://api.example.org" (without the /api/v1 prefix).
+func New(baseURL string, opts ...Option) (*Client, error) {
+	if baseURL == "" {
+		return nil, errors.New("cannot create API client: base URL is required")
+	}
+
+	c := &Client{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		profileName: "default",
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		maxRetries:     DefaultMaxRetries,
+		retryBaseDelay: DefaultRetryBaseDelay,
+		retryMaxDelay:  DefaultRetryMaxDelay,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.captureDir != "" {
+		transport, err := newCaptureTransport(c.httpClient.Transport, c.captureDir, c.baseURL, c.profileName)
+		if err != nil {
+			return nil, err
+		}
+		// Copy so a caller-supplied http.Client is not modified
+		httpClient := *c.httpClient
+		httpClient.Transport = transport
+		c.httpClient = &httpClient
+	}
+
+	return c, nil
+}
+
+// BaseURL returns the base URL of the API the client calls.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// ProfileName returns the name set with WithProfileName, for display purposes.
+func (c *Client) ProfileName() string {
+	return c.profileName
+}
+
+// CheckCompatibility checks if the client version is compatible with the API.
+// This is called automatically on first API request unless skipped.
+// Failing to reach the health endpoint is not an error here; the request
+// that follows reports it.
+func (c *Client) CheckCompatibility(ctx context.Context) error {
+	if c.versionChecked || c.skipVersion {
+		return nil
+	}
+
+	health, err := c.Health(ctx)
+	c.versionChecked = true
+	if err != nil {
+		// Version check failure is a warning, not a hard error.
+		// Continue without version check - we intentionally ignore this error.
+		return nil //nolint:nilerr // Intentionally ignoring version check errors
+	}
+
+	// Check minimum client version
+	if health.Version.MinClient != "" {
+		cmp := compareVersions(Version, health.Version.MinClient)
+		if cmp < 0 {
+			return &APIError{
+				StatusCode: http.StatusUpgradeRequired,
+				Code:       apierror.CodeClientTooOld,
+				Message:    fmt.Sprintf("client version %s is too old for the API (requires >= %s)", Version, health.Version.MinClient),
+			}
+		}
+	}
+
+	// Note: client newer than API is just informational, not an error.
+	// Could log a warning if health.Version.API != "" && compareVersions(Version, health.Version.API) > 0
+
+	return nil
+}
+
+// Health fetches the API health status and version info.
+func (c *Client) Health(ctx context.Context) (*HealthResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/health", http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set(ClientVersionHeader, clientVersionValue)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, c.wrapConnectionError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var health HealthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return nil, fmt.Errorf("failed to parse health response: %w", err)
+	}
+
+	return &health, nil
+}
+
+// VerifyAuth verifies the API key is valid for write operations.
+// Call this before attempting write operations to fail fast on auth issues.
+func (c *Client) VerifyAuth(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/auth/verify", http.NoBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set(ClientVersionHeader, clientVersionValue)
+
+	// Must include auth header
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return c.wrapConnectionError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
+
+// doRequest performs an HTTP request with authentication, retry logic, and error handling.
+// It automatically retries on transient failures (5xx errors, timeouts, connection errors)
+// with exponential backoff.
+func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	return c.doRequestWithHeaders(ctx, method, path, body, nil)
+}
+
+// readOnlyKey is the context key marking a request as read-only
+type readOnlyKey struct{}
+
+// WithReadOnly marks requests made with ctx as only reading, although they
+// use a method that usually writes.
+func WithReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+// IsReadOnly reports whether req was made with a WithReadOnly context, such
+// as the POST that Query sends. Transports acting on writes, such as one
+// queueing them for retry, pass these through.
+func IsReadOnly(req *http.Request) bool {
+	readOnly, _ := req.Context().Value(readOnlyKey{}).(bool)
+	return readOnly
+}
+
+// getWithETag is a GET that also returns the response's ETag: the version of
+// the record to send back to putIfMatch when saving an edit of it.
+func (c *Client) getWithETag(ctx context.Context, path string, target interface{}) (string, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if err := c.parseResponse(resp, target); err != nil {
+		return "", err
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// putIfMatch PUTs body to path with If-Match, so the server saves it only if
+// the record is still the version etag names and returns 412 otherwise (see
+// IsPreconditionFailedError). An empty etag saves unconditionally.
+func (c *Client) putIfMatch(ctx context.Context, path string, body interface{}, etag string, target interface{}) error {
+	var headers http.Header
+	if etag != "" {
+		headers = http.Header{"If-Match": {etag}}
+	}
+
+	resp, err := c.doRequestWithHeaders(ctx, http.MethodPut, path, body, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return c.parseResponse(resp, target)
+}
+
+// doRequestWithHeaders is doRequest with extra request headers.
+func (c *Client) doRequestWithHeaders(ctx context.Context, method, path string, body interface{}, headers http.Header) (*http.Response, error) {
+	if err := c.CheckCompatibility(ctx); err != nil {
+		return nil, err
+	}
+
+	bodyData, err := c.marshalBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	headers = headers.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	if raw, ok := body.(*RawBody); ok {
+		headers.Set("Content-Type", raw.ContentType)
+	} else if body != nil {
+		headers.Set("Content-Type", ContentTypeJSON)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(c.calculateBackoff(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		resp, err := c.executeRequest(ctx, method, path, bodyData, headers)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = c.wrapConnectionError(err)
+			if c.isRetryableError(err) {
+				continue
+			}
+			return nil, lastErr
+		}
+
+		// Maintenance lasts longer than a retry; report the server's message
+		if resp.StatusCode == http.StatusServiceUnavailable {
+			if err := maintenanceError(resp); err != nil {
+				return nil, err
+			}
+		}
+
+		if c.isRetryableStatusCode(resp.StatusCode) {
+			resp.Body.Close()
+			lastErr = &APIError{
+				StatusCode: resp.StatusCode,
+				Code:       apierror.CodeInternal,
+				Message:    fmt.Sprintf("server error (attempt %d/%d)", attempt+1, c.maxRetries+1),
+			}
+			continue
+		}
+
+		if c.onDeprecation != nil && resp.Header.Get("Deprecation") != "" {
+			c.onDeprecation(Deprecation{
+				Method: method,
+				Path:   path,
+				Sunset: resp.Header.Get("Sunset"),
+				Link:   resp.Header.Get("Link"),
+			})
+		}
+
+		return resp, nil
+	}
+
+	if lastErr != nil {
+		return nil, fmt.Errorf("request failed after %d attempts: %w", c.maxRetries+1, lastErr)
+	}
+	return nil, fmt.Errorf("request failed after %d attempts", c.maxRetries+1)
+}
+
+// marshalBody serializes the request body to JSON if present.
+// A *RawBody is sent as-is.
+func (c *Client) marshalBody(body interface{}) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	if raw, ok := body.(*RawBody); ok {
+		return raw.Data, nil
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	return data, nil
+}
+
+// executeRequest creates and executes a single HTTP request with the given extra headers.
+func (c *Client) executeRequest(ctx context.Context, method, path string, bodyData []byte, headers http.Header) (*http.Response, error) {
+	var bodyReader io.Reader
+	if bodyData != nil {
+		bodyReader = bytes.NewReader(bodyData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for name, values := range headers {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+	req.Header.Set(ClientVersionHeader, clientVersionValue)
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// calculateBackoff returns the delay for the given retry attempt using exponential backoff.
+// The delay doubles with each attempt: base, 2*base, 4*base, etc., capped at maxDelay.
+func (c *Client) calculateBackoff(attempt int) time.Duration {
+	// Exponential backoff: base * 2^(attempt-1)
+	delay := c.retryBaseDelay * time.Duration(1<<(attempt-1))
+	if delay > c.retryMaxDelay {
+		delay = c.retryMaxDelay
+	}
+	return delay
+}
+
+// isRetryableError returns true if the error is a transient failure that should be retried.
+func (c *Client) isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// Check for timeout errors
+	var netErr interface{ Timeout() bool }
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	// Check for connection refused and other network errors
+	errStr := err.Error()
+	retryablePatterns := []string{
+		"connection refused",
+		"connection reset",
+		"no such host",
+		"network is unreachable",
+		"i/o timeout",
+		"EOF",
+	}
+	for _, pattern := range retryablePatterns {
+		if strings.Contains(strings.ToLower(errStr), strings.ToLower(pattern)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isRetryableStatusCode returns true if the HTTP status code indicates a transient failure.
+func (c *Client) isRetryableStatusCode(statusCode int) bool {
+	// Retry on 5xx server errors and 429 (rate limited)
+	return statusCode >= 500 || statusCode == http.StatusTooManyRequests
+}
+
+// maintenanceError returns the API's error if a 503 response says writes are
+// disabled for maintenance, or nil for other 503s. It consumes and closes the body.
+func maintenanceError(resp *http.Response) error {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	apiErr := decodeAPIError(resp.StatusCode, body)
+	if apiErr == nil || apiErr.Code != apierror.CodeMaintenance {
+		return nil
+	}
+	return apiErr
+}
+
+// wrapConnectionError wraps a connection error with additional context.
+func (c *Client) wrapConnectionError(err error) error {
+	return &ConnectionError{
+		URL:     c.baseURL,
+		Profile: c.profileName,
+		Err:     err,
+	}
+}
+
+// decodeAPIError decodes a body in the API's error model (see pkg/apierror),
+// or returns nil if the body is not one.
+func decodeAPIError(statusCode int, body []byte) *APIError {
+	var wrapper struct {
+		Error struct {
+			apierror.Error
+			Details json.RawMessage `json:"details"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &wrapper) != nil || wrapper.Error.Code == "" {
+		return nil
+	}
+	e := wrapper.Error
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Code:       e.Code,
+		Message:    e.Message,
+		Field:      e.Field,
+		Entity:     e.Entity,
+		ID:         e.ID,
+		Details:    e.Details,
+	}
+	if e.Code == apierror.CodeValidation && len(e.Details) > 0 {
+		var fields apierror.FieldErrors
+		if json.Unmarshal(e.Details, &fields) == nil {
+			apiErr.Fields = fields.Errors
+		}
+	}
+	return apiErr
+}
+
+// parseError parses an error response from the API. Responses in the API's
+// error model keep the server's code, message, and details; anything else
+// (a proxy's error page, say) gets the code for its status.
+func (c *Client) parseError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		var wrapper struct {
+			Errors []ValidationError `json:"errors"`
+		}
+		if json.Unmarshal(body, &wrapper) == nil && len(wrapper.Errors) > 0 {
+			return &MultiValidationError{Errors: wrapper.Errors}
+		}
+	}
+
+	apiErr := decodeAPIError(resp.StatusCode, body)
+	if apiErr == nil {
+		apiErr = &APIError{StatusCode: resp.StatusCode}
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		// Name the profile so the user knows which key to fix
+		apiErr.Message = fmt.Sprintf("invalid API key for profile [%s]", c.profileName)
+	}
+	if apiErr.Code == "" {
+		apiErr.Code = codeForStatus(resp.StatusCode)
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = messageForStatus(resp.StatusCode, body)
+	}
+	return apiErr
+}
+
+// codeForStatus returns the API error code for a status, for responses that
+// carry none.
+func codeForStatus(statusCode int) string {
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return apierror.CodeValidation
+	case http.StatusUnauthorized:
+		return apierror.CodeUnauthorized
+	case http.StatusForbidden:
+		return apierror.CodeForbidden
+	case http.StatusNotFound:
+		return apierror.CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return apierror.CodeMethodNotAllowed
+	case http.StatusConflict:
+		return apierror.CodeConflict
+	case http.StatusPreconditionFailed:
+		return apierror.CodePreconditionFailed
+	case http.StatusUpgradeRequired:
+		return apierror.CodeClientTooOld
+	case http.StatusTooManyRequests:
+		return apierror.CodeRateLimited
+	}
+	if statusCode >= 500 {
+		return apierror.CodeInternal
+	}
+	return ""
+}
+
+// messageForStatus returns a message for an error response that carries none.
+func messageForStatus(statusCode int, body []byte) string {
+	switch statusCode {
+	case http.StatusForbidden:
+		return "access denied"
+	case http.StatusNotFound:
+		return "resource not found"
+	case http.StatusConflict:
+		return "resource already exists"
+	case http.StatusPreconditionFailed:
+		return "the record changed on the server since it was read"
+	case http.StatusUpgradeRequired:
+		return "client is too old for this API; upgrade it (for the oak CLI: go install github.com/jeff/oaks/cli@latest)"
+	case http.StatusTooManyRequests:
+		return "rate limit exceeded, please try again later"
+	}
+	if statusCode >= 500 {
+		return "server error, please try again later"
+	}
+	return string(body)
+}
+
+// parseResponse reads and parses a JSON response into the target.
+func (c *Client) parseResponse(resp *http.Response, target interface{}) error {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return c.parseError(resp)
+	}
+
+	if target == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// compareVersions compares two semantic versions.
+// Returns -1 if a < b, 0 if a == b, 1 if a > b.
+func compareVersions(a, b string) int {
+	aParts := parseVersion(a)
+	bParts := parseVersion(b)
+
+	for i := 0; i < 3; i++ {
+		if aParts[i] < bParts[i] {
+			return -1
+		}
+		if aParts[i] > bParts[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// parseVersion parses a semantic version string into [major, minor, patch].
+func parseVersion(v string) [3]int {
+	v = strings.TrimPrefix(v, "v")
+	parts := strings.Split(v, ".")
+	var result [3]int
+	for i := 0; i < len(parts) && i < 3; i++ {
+		n, _ := strconv.Atoi(parts[i])
+		result[i] = n
+	}
+	return result
+}
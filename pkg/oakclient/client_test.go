@@ -1,6 +1,7 @@
-package client
+package oakclient
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,50 +12,28 @@ import (
 	"testing"
 	"time"
 
-	"github.com/jeff/oaks/cli/internal/config"
+	"github.com/jeff/oaks/pkg/apierror"
 )
 
 // newTestClient creates a client pointing at a test server.
 func newTestClient(t *testing.T, server *httptest.Server) *Client {
 	t.Helper()
-	profile := &config.ResolvedProfile{
-		Name:   "test",
-		URL:    server.URL,
-		Key:    "test-api-key",
-		Source: config.SourceFlag,
-	}
-	c, err := New(profile, WithSkipVersionCheck(true))
+	c, err := New(server.URL, WithAPIKey("test-api-key"), WithProfileName("test"), WithSkipVersionCheck(true))
 	if err != nil {
 		t.Fatalf("failed to create test client: %v", err)
 	}
 	return c
 }
 
-func TestNew_LocalProfileError(t *testing.T) {
-	profile := &config.ResolvedProfile{
-		Source: config.SourceLocal,
-	}
-	_, err := New(profile)
+func TestNew_EmptyURLError(t *testing.T) {
+	_, err := New("")
 	if err == nil {
-		t.Error("expected error for local profile, got nil")
-	}
-}
-
-func TestNew_NilProfileError(t *testing.T) {
-	_, err := New(nil)
-	if err == nil {
-		t.Error("expected error for nil profile, got nil")
+		t.Error("expected error for empty base URL, got nil")
 	}
 }
 
 func TestNew_Success(t *testing.T) {
-	profile := &config.ResolvedProfile{
-		Name:   "prod",
-		URL:    "https://api.example.com",
-		Key:    "test-key",
-		Source: config.SourceFlag,
-	}
-	c, err := New(profile)
+	c, err := New("https://api.example.com", WithAPIKey("test-key"), WithProfileName("prod"))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -67,13 +46,7 @@ func TestNew_Success(t *testing.T) {
 }
 
 func TestNew_TrimsTrailingSlash(t *testing.T) {
-	profile := &config.ResolvedProfile{
-		Name:   "prod",
-		URL:    "https://api.example.com/",
-		Key:    "test-key",
-		Source: config.SourceFlag,
-	}
-	c, err := New(profile)
+	c, err := New("https://api.example.com/", WithAPIKey("test-key"), WithProfileName("prod"))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -83,12 +56,7 @@ func TestNew_TrimsTrailingSlash(t *testing.T) {
 }
 
 func TestClient_ProfileName(t *testing.T) {
-	profile := &config.ResolvedProfile{
-		Name:   "staging",
-		URL:    "https://staging.example.com",
-		Source: config.SourceFlag,
-	}
-	c, err := New(profile)
+	c, err := New("https://staging.example.com", WithProfileName("staging"))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -116,7 +84,7 @@ func TestHealth_Success(t *testing.T) {
 	c := newTestClient(t, server)
 	c.skipVersion = false // Enable for this test
 
-	health, err := c.Health()
+	health, err := c.Health(t.Context())
 	if err != nil {
 		t.Fatalf("Health() error = %v", err)
 	}
@@ -135,7 +103,7 @@ func TestHealth_ServerError(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	_, err := c.Health()
+	_, err := c.Health(t.Context())
 	if err == nil {
 		t.Error("expected error for server error response")
 	}
@@ -209,7 +177,7 @@ func TestCheckCompatibility_VersionTooOld(t *testing.T) {
 	c.skipVersion = false
 	c.versionChecked = false
 
-	err := c.CheckCompatibility()
+	err := c.CheckCompatibility(t.Context())
 	if err == nil {
 		t.Error("expected error for old CLI version")
 	}
@@ -224,7 +192,7 @@ func TestCheckCompatibility_SkipEnabled(t *testing.T) {
 	c := newTestClient(t, server)
 	c.skipVersion = true
 
-	err := c.CheckCompatibility()
+	err := c.CheckCompatibility(t.Context())
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -250,9 +218,9 @@ func TestCheckCompatibility_AlreadyChecked(t *testing.T) {
 	c.versionChecked = false
 
 	// First call
-	_ = c.CheckCompatibility()
+	_ = c.CheckCompatibility(t.Context())
 	// Second call should not hit server
-	_ = c.CheckCompatibility()
+	_ = c.CheckCompatibility(t.Context())
 
 	if callCount != 1 {
 		t.Errorf("server called %d times, want 1", callCount)
@@ -265,8 +233,8 @@ func TestAPIError_Error(t *testing.T) {
 		want string
 	}{
 		{
-			APIError{StatusCode: 404, Code: "not_found", Message: "resource not found"},
-			"API error (404 not_found): resource not found",
+			APIError{StatusCode: 404, Code: apierror.CodeNotFound, Message: "resource not found"},
+			"API error (404 NOT_FOUND): resource not found",
 		},
 		{
 			APIError{StatusCode: 500, Message: "server error"},
@@ -315,12 +283,12 @@ func TestMultiValidationError_Error(t *testing.T) {
 }
 
 func TestIsNotFoundError(t *testing.T) {
-	notFound := &APIError{StatusCode: 404, Code: "not_found"}
+	notFound := &APIError{StatusCode: 404, Code: apierror.CodeNotFound}
 	if !IsNotFoundError(notFound) {
 		t.Error("IsNotFoundError(404 error) = false, want true")
 	}
 
-	conflict := &APIError{StatusCode: 409, Code: "conflict"}
+	conflict := &APIError{StatusCode: 409, Code: apierror.CodeConflict}
 	if IsNotFoundError(conflict) {
 		t.Error("IsNotFoundError(409 error) = true, want false")
 	}
@@ -331,24 +299,24 @@ func TestIsNotFoundError(t *testing.T) {
 }
 
 func TestIsConflictError(t *testing.T) {
-	conflict := &APIError{StatusCode: 409, Code: "conflict"}
+	conflict := &APIError{StatusCode: 409, Code: apierror.CodeConflict}
 	if !IsConflictError(conflict) {
 		t.Error("IsConflictError(409 error) = false, want true")
 	}
 
-	notFound := &APIError{StatusCode: 404, Code: "not_found"}
+	notFound := &APIError{StatusCode: 404, Code: apierror.CodeNotFound}
 	if IsConflictError(notFound) {
 		t.Error("IsConflictError(404 error) = true, want false")
 	}
 }
 
 func TestIsAuthError(t *testing.T) {
-	auth := &APIError{StatusCode: 401, Code: "unauthorized"}
+	auth := &APIError{StatusCode: 401, Code: apierror.CodeUnauthorized}
 	if !IsAuthError(auth) {
 		t.Error("IsAuthError(401 error) = false, want true")
 	}
 
-	notFound := &APIError{StatusCode: 404, Code: "not_found"}
+	notFound := &APIError{StatusCode: 404, Code: apierror.CodeNotFound}
 	if IsAuthError(notFound) {
 		t.Error("IsAuthError(404 error) = true, want false")
 	}
@@ -421,7 +389,7 @@ func TestDoRequest_SetsAuthHeader(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	resp, err := c.doRequest(http.MethodGet, "/test", nil)
+	resp, err := c.doRequest(t.Context(), http.MethodGet, "/test", nil)
 	if err != nil {
 		t.Fatalf("doRequest error: %v", err)
 	}
@@ -445,7 +413,7 @@ func TestDoRequest_SetsContentType(t *testing.T) {
 	c := newTestClient(t, server)
 
 	// Request with body should have Content-Type
-	resp, err := c.doRequest(http.MethodPost, "/test", map[string]string{"key": "value"})
+	resp, err := c.doRequest(t.Context(), http.MethodPost, "/test", map[string]string{"key": "value"})
 	if err != nil {
 		t.Fatalf("doRequest error: %v", err)
 	}
@@ -458,13 +426,7 @@ func TestDoRequest_SetsContentType(t *testing.T) {
 
 func TestWithHTTPClient(t *testing.T) {
 	customClient := &http.Client{}
-	profile := &config.ResolvedProfile{
-		Name:   "test",
-		URL:    "https://example.com",
-		Source: config.SourceFlag,
-	}
-
-	c, err := New(profile, WithHTTPClient(customClient))
+	c, err := New("https://example.com", WithProfileName("test"), WithHTTPClient(customClient))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -474,14 +436,27 @@ func TestWithHTTPClient(t *testing.T) {
 	}
 }
 
-func TestWithSkipVersionCheck(t *testing.T) {
-	profile := &config.ResolvedProfile{
-		Name:   "test",
-		URL:    "https://example.com",
-		Source: config.SourceFlag,
+func TestWithTransport(t *testing.T) {
+	transport := &http.Transport{}
+	c, err := New("https://example.com", WithProfileName("test"), WithTransport(transport))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.httpClient.Transport != transport {
+		t.Error("transport was not set")
 	}
 
-	c, err := New(profile, WithSkipVersionCheck(true))
+	c, err = New("https://example.com", WithProfileName("test"), WithTransport(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.httpClient.Transport != nil {
+		t.Error("nil transport should leave the default")
+	}
+}
+
+func TestWithSkipVersionCheck(t *testing.T) {
+	c, err := New("https://example.com", WithProfileName("test"), WithSkipVersionCheck(true))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -492,13 +467,7 @@ func TestWithSkipVersionCheck(t *testing.T) {
 }
 
 func TestWithMaxRetries(t *testing.T) {
-	profile := &config.ResolvedProfile{
-		Name:   "test",
-		URL:    "https://example.com",
-		Source: config.SourceFlag,
-	}
-
-	c, err := New(profile, WithMaxRetries(5))
+	c, err := New("https://example.com", WithProfileName("test"), WithMaxRetries(5))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -509,13 +478,7 @@ func TestWithMaxRetries(t *testing.T) {
 }
 
 func TestWithRetryDelay(t *testing.T) {
-	profile := &config.ResolvedProfile{
-		Name:   "test",
-		URL:    "https://example.com",
-		Source: config.SourceFlag,
-	}
-
-	c, err := New(profile, WithRetryDelay(2*time.Second, 20*time.Second))
+	c, err := New("https://example.com", WithProfileName("test"), WithRetryDelay(2*time.Second, 20*time.Second))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -529,13 +492,7 @@ func TestWithRetryDelay(t *testing.T) {
 }
 
 func TestWithTimeout(t *testing.T) {
-	profile := &config.ResolvedProfile{
-		Name:   "test",
-		URL:    "https://example.com",
-		Source: config.SourceFlag,
-	}
-
-	c, err := New(profile, WithTimeout(60*time.Second))
+	c, err := New("https://example.com", WithProfileName("test"), WithTimeout(60*time.Second))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -546,12 +503,7 @@ func TestWithTimeout(t *testing.T) {
 }
 
 func TestCalculateBackoff(t *testing.T) {
-	profile := &config.ResolvedProfile{
-		Name:   "test",
-		URL:    "https://example.com",
-		Source: config.SourceFlag,
-	}
-	c, _ := New(profile, WithRetryDelay(1*time.Second, 10*time.Second))
+	c, _ := New("https://example.com", WithProfileName("test"), WithRetryDelay(1*time.Second, 10*time.Second))
 
 	tests := []struct {
 		attempt int
@@ -574,12 +526,7 @@ func TestCalculateBackoff(t *testing.T) {
 }
 
 func TestIsRetryableError(t *testing.T) {
-	profile := &config.ResolvedProfile{
-		Name:   "test",
-		URL:    "https://example.com",
-		Source: config.SourceFlag,
-	}
-	c, _ := New(profile)
+	c, _ := New("https://example.com", WithProfileName("test"))
 
 	tests := []struct {
 		err  error
@@ -605,12 +552,7 @@ func TestIsRetryableError(t *testing.T) {
 }
 
 func TestIsRetryableStatusCode(t *testing.T) {
-	profile := &config.ResolvedProfile{
-		Name:   "test",
-		URL:    "https://example.com",
-		Source: config.SourceFlag,
-	}
-	c, _ := New(profile)
+	c, _ := New("https://example.com", WithProfileName("test"))
 
 	tests := []struct {
 		code int
@@ -656,7 +598,7 @@ func TestRetryOnServerError(t *testing.T) {
 	c.retryBaseDelay = 1 * time.Millisecond
 	c.retryMaxDelay = 10 * time.Millisecond
 
-	resp, err := c.doRequest(http.MethodGet, "/test", nil)
+	resp, err := c.doRequest(t.Context(), http.MethodGet, "/test", nil)
 	if err != nil {
 		t.Fatalf("doRequest error: %v", err)
 	}
@@ -683,7 +625,7 @@ func TestRetryExhausted(t *testing.T) {
 	c.retryBaseDelay = 1 * time.Millisecond
 	c.retryMaxDelay = 10 * time.Millisecond
 
-	resp, err := c.doRequest(http.MethodGet, "/test", nil)
+	resp, err := c.doRequest(t.Context(), http.MethodGet, "/test", nil)
 	if err == nil {
 		t.Error("expected error when retries exhausted")
 	}
@@ -710,7 +652,7 @@ func TestNoRetryOnClientError(t *testing.T) {
 	c.maxRetries = 3
 	c.retryBaseDelay = 1 * time.Millisecond
 
-	resp, err := c.doRequest(http.MethodGet, "/test", nil)
+	resp, err := c.doRequest(t.Context(), http.MethodGet, "/test", nil)
 	if err != nil {
 		t.Fatalf("doRequest error: %v", err)
 	}
@@ -740,7 +682,7 @@ func TestRetryOnRateLimit(t *testing.T) {
 	c.retryBaseDelay = 1 * time.Millisecond
 	c.retryMaxDelay = 10 * time.Millisecond
 
-	resp, err := c.doRequest(http.MethodGet, "/test", nil)
+	resp, err := c.doRequest(t.Context(), http.MethodGet, "/test", nil)
 	if err != nil {
 		t.Fatalf("doRequest error: %v", err)
 	}
@@ -801,13 +743,7 @@ func TestIsConnectionError(t *testing.T) {
 }
 
 func TestDefaultRetryConfiguration(t *testing.T) {
-	profile := &config.ResolvedProfile{
-		Name:   "test",
-		URL:    "https://example.com",
-		Source: config.SourceFlag,
-	}
-
-	c, err := New(profile)
+	c, err := New("https://example.com", WithProfileName("test"))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -846,7 +782,7 @@ func TestRetryWithRequestBody(t *testing.T) {
 	c.retryMaxDelay = 10 * time.Millisecond
 
 	body := map[string]string{"key": "value"}
-	resp, err := c.doRequest(http.MethodPost, "/test", body)
+	resp, err := c.doRequest(t.Context(), http.MethodPost, "/test", body)
 	if err != nil {
 		t.Fatalf("doRequest error: %v", err)
 	}
@@ -863,3 +799,168 @@ func TestRetryWithRequestBody(t *testing.T) {
 		}
 	}
 }
+
+func TestRequestsSendClientVersion(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(ClientVersionHeader)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	if _, err := c.ListSources(t.Context(), nil); err != nil {
+		t.Fatalf("ListSources() error = %v", err)
+	}
+	if got != "oak-cli/"+Version {
+		t.Errorf("%s = %q, want oak-cli/%s", ClientVersionHeader, got, Version)
+	}
+}
+
+func TestParseError_UpgradeRequired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUpgradeRequired)
+		w.Write([]byte(`{"error":{"code":"CLIENT_TOO_OLD","message":"Client version 1.0.0 is no longer supported"}}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	_, err := c.ListSources(t.Context(), nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusUpgradeRequired || apiErr.Code != "CLIENT_TOO_OLD" {
+		t.Errorf("error = %+v", apiErr)
+	}
+	if !strings.Contains(apiErr.Message, "no longer supported") {
+		t.Errorf("message = %q", apiErr.Message)
+	}
+}
+
+func TestParseError_ErrorModel(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   APIError
+		is     error
+	}{
+		{
+			"not found names the record", http.StatusNotFound,
+			`{"error":{"code":"NOT_FOUND","message":"Species 'nosuch' not found","entity":"species","id":"nosuch"}}`,
+			APIError{StatusCode: 404, Code: apierror.CodeNotFound, Message: "Species 'nosuch' not found", Entity: "species", ID: "nosuch"},
+			ErrNotFound,
+		},
+		{
+			"validation lists the fields", http.StatusBadRequest,
+			`{"error":{"code":"VALIDATION_ERROR","message":"Validation failed","field":"limit","details":{"errors":[{"field":"limit","message":"must be at least 1"}]}}}`,
+			APIError{StatusCode: 400, Code: apierror.CodeValidation, Message: "Validation failed", Field: "limit",
+				Fields: []ValidationError{{Field: "limit", Message: "must be at least 1"}}},
+			ErrValidation,
+		},
+		{
+			"conflict keeps the server's message", http.StatusConflict,
+			`{"error":{"code":"CONFLICT","message":"Species 'alba' already exists"}}`,
+			APIError{StatusCode: 409, Code: apierror.CodeConflict, Message: "Species 'alba' already exists"},
+			ErrConflict,
+		},
+		{
+			"body outside the model", http.StatusNotFound,
+			`<html>404</html>`,
+			APIError{StatusCode: 404, Code: apierror.CodeNotFound, Message: "resource not found"},
+			ErrNotFound,
+		},
+		{
+			"empty bad request", http.StatusBadRequest, ``,
+			APIError{StatusCode: 400, Code: apierror.CodeValidation},
+			ErrValidation,
+		},
+	}
+	c, err := New("http://localhost:8080")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Body: io.NopCloser(strings.NewReader(tt.body))}
+			err := c.parseError(resp)
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected *APIError, got %T: %v", err, err)
+			}
+			apiErr.Details = nil
+			if fmt.Sprint(*apiErr) != fmt.Sprint(tt.want) {
+				t.Errorf("error = %+v, want %+v", *apiErr, tt.want)
+			}
+			if !errors.Is(err, tt.is) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.is)
+			}
+			if got := ErrorCode(err); got != tt.want.Code {
+				t.Errorf("ErrorCode = %q, want %q", got, tt.want.Code)
+			}
+		})
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	if got := ErrorCode(&MultiValidationError{}); got != apierror.CodeValidation {
+		t.Errorf("ErrorCode(MultiValidationError) = %q", got)
+	}
+	if got := ErrorCode(errors.New("plain")); got != "" {
+		t.Errorf("ErrorCode(plain error) = %q, want empty", got)
+	}
+}
+
+func TestDeprecationHandler(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Sunset", "Fri, 01 Jan 2027 00:00:00 GMT")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	var notices []Deprecation
+	c, err := New(server.URL, WithAPIKey("k"), WithProfileName("test"), WithSkipVersionCheck(true), WithDeprecationHandler(func(d Deprecation) {
+		notices = append(notices, d)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.ListSources(t.Context(), nil); err != nil {
+		t.Fatalf("ListSources() error = %v", err)
+	}
+	if len(notices) != 1 || notices[0].Path != "/api/v1/sources" || notices[0].Sunset == "" {
+		t.Errorf("notices = %+v", notices)
+	}
+}
+
+func TestDoRequest_ContextCanceledStopsRetries(t *testing.T) {
+	attemptCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attemptCount++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	c.maxRetries = 3
+	c.retryBaseDelay = time.Hour
+	c.retryMaxDelay = time.Hour
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.doRequest(ctx, http.MethodGet, "/test", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if attemptCount != 1 {
+		t.Errorf("attemptCount = %d, want 1 (no retry after the deadline)", attemptCount)
+	}
+}
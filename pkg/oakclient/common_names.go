@@ -0,0 +1,61 @@
+package oakclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// CommonName is a vernacular name for a species in one language.
+type CommonName struct {
+	Name     string `json:"name"`
+	Language string `json:"language"` // BCP 47 tag, such as en or fr-CA; "und" when unknown
+	SourceID *int64 `json:"source_id,omitempty"`
+}
+
+// CommonNamesRequest is the request body for replacing a species' common names.
+type CommonNamesRequest struct {
+	CommonNames []*CommonName `json:"common_names"`
+}
+
+// CommonNamesListResponse is the list wrapper for a species' common names.
+type CommonNamesListResponse struct {
+	Data []*CommonName `json:"data"`
+}
+
+// GetCommonNames returns a species' common names, by language and then in
+// the order they were given.
+func (c *Client) GetCommonNames(ctx context.Context, name string) ([]*CommonName, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/species/"+url.PathEscape(name)+"/common-names", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result CommonNamesListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// SetCommonNames replaces a species' common names and returns them as
+// stored, with language tags in canonical case. Requires an API key.
+func (c *Client) SetCommonNames(ctx context.Context, name string, names []*CommonName) ([]*CommonName, error) {
+	if names == nil {
+		names = []*CommonName{}
+	}
+	resp, err := c.doRequest(ctx, http.MethodPut, "/api/v1/species/"+url.PathEscape(name)+"/common-names", &CommonNamesRequest{CommonNames: names})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result CommonNamesListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
@@ -0,0 +1,48 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetCommonNames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/species/alba/common-names" {
+			t.Errorf("request = %s %s, want PUT /api/v1/species/alba/common-names", r.Method, r.URL.Path)
+		}
+		var req CommonNamesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if len(req.CommonNames) != 2 || req.CommonNames[0].Language != "EN-us" {
+			t.Errorf("request names = %+v", req.CommonNames)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(CommonNamesListResponse{Data: []*CommonName{
+			{Name: "White oak", Language: "en-US"},
+			{Name: "Chêne blanc", Language: "fr"},
+		}})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	names, err := c.SetCommonNames(t.Context(), "alba", []*CommonName{
+		{Name: "White oak", Language: "EN-us"},
+		{Name: "Chêne blanc", Language: "fr"},
+	})
+	if err != nil {
+		t.Fatalf("SetCommonNames() error = %v", err)
+	}
+	if len(names) != 2 || names[0].Language != "en-US" {
+		t.Errorf("names = %+v", names)
+	}
+}
+
+func TestExportOptionsLanguages(t *testing.T) {
+	opts := ExportOptions{Languages: []string{"en", "fr-CA"}}
+	if got, want := opts.path(), "/api/v1/export?lang=en%2Cfr-CA"; got != want {
+		t.Errorf("path() = %q, want %q", got, want)
+	}
+}
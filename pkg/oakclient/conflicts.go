@@ -0,0 +1,135 @@
+package oakclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Conflict is a pair of sources making contradictory claims about a species
+// property, such as different maximum heights.
+type Conflict struct {
+	ID                int64      `json:"id"`
+	ScientificName    string     `json:"scientific_name"`
+	Property          string     `json:"property"` // max_height, acorn_maturation, or leaf_persistence
+	SourceA           int64      `json:"source_a"`
+	ValueA            string     `json:"value_a"`
+	EvidenceA         string     `json:"evidence_a"`
+	SourceB           int64      `json:"source_b"`
+	ValueB            string     `json:"value_b"`
+	EvidenceB         string     `json:"evidence_b"`
+	Status            string     `json:"status"` // open or resolved
+	PreferredSourceID *int64     `json:"preferred_source_id,omitempty"`
+	Resolution        *string    `json:"resolution,omitempty"`
+	Justification     *string    `json:"justification,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ResolvedAt        *time.Time `json:"resolved_at,omitempty"`
+}
+
+// ConflictsListResponse is the paginated list wrapper for conflicts.
+type ConflictsListResponse struct {
+	Data []*Conflict `json:"data"`
+}
+
+// ConflictReport counts what a conflict detection run did.
+type ConflictReport struct {
+	Detected int `json:"detected"`
+	Reopened int `json:"reopened"`
+	Cleared  int `json:"cleared"`
+}
+
+// ResolveConflictRequest resolves a conflict. At least one of
+// PreferredSourceID and Resolution is required, with a justification.
+type ResolveConflictRequest struct {
+	PreferredSourceID *int64 `json:"preferred_source_id,omitempty"`
+	Resolution        string `json:"resolution,omitempty"`
+	Justification     string `json:"justification"`
+}
+
+// DetectConflicts compares the sources' claims for the given species, or
+// every species, and records the ones that disagree.
+func (c *Client) DetectConflicts(ctx context.Context, species []string) (*ConflictReport, error) {
+	body := map[string]interface{}{}
+	if len(species) > 0 {
+		body["species"] = species
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/conflicts/detect", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var report ConflictReport
+	if err := c.parseResponse(resp, &report); err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// ListConflicts lists conflicts with a status (open, resolved, or all; empty
+// means open), optionally for one species and property.
+func (c *Client) ListConflicts(ctx context.Context, status, species, property string) ([]*Conflict, error) {
+	query := url.Values{}
+	if status != "" {
+		query.Set("status", status)
+	}
+	if species != "" {
+		query.Set("species", species)
+	}
+	if property != "" {
+		query.Set("property", property)
+	}
+	path := "/api/v1/conflicts"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ConflictsListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// GetConflict retrieves a conflict by ID.
+func (c *Client) GetConflict(ctx context.Context, id int64) (*Conflict, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/v1/conflicts/%d", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var conflict Conflict
+	if err := c.parseResponse(resp, &conflict); err != nil {
+		return nil, err
+	}
+
+	return &conflict, nil
+}
+
+// ResolveConflict records a curator's resolution of an open conflict.
+func (c *Client) ResolveConflict(ctx context.Context, id int64, req *ResolveConflictRequest) (*Conflict, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, fmt.Sprintf("/api/v1/conflicts/%d/resolve", id), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var conflict Conflict
+	if err := c.parseResponse(resp, &conflict); err != nil {
+		return nil, err
+	}
+
+	return &conflict, nil
+}
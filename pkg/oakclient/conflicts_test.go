@@ -0,0 +1,50 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListConflicts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/conflicts" || r.URL.RawQuery != "property=max_height&species=texana&status=all" {
+			t.Errorf("request = %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ConflictsListResponse{Data: []*Conflict{{ID: 2, ScientificName: "texana"}}})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	conflicts, err := c.ListConflicts(t.Context(), "all", "texana", "max_height")
+	if err != nil {
+		t.Fatalf("ListConflicts() error = %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0].ID != 2 {
+		t.Errorf("conflicts = %+v", conflicts)
+	}
+}
+
+func TestResolveConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ResolveConflictRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/conflicts/2/resolve" || req.Justification != "checked herbarium sheets" {
+			t.Errorf("request = %s %s %+v", r.Method, r.URL.Path, req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Conflict{ID: 2, Status: "resolved", Justification: &req.Justification})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	conflict, err := c.ResolveConflict(t.Context(), 2, &ResolveConflictRequest{Resolution: "30 m", Justification: "checked herbarium sheets"})
+	if err != nil {
+		t.Fatalf("ResolveConflict() error = %v", err)
+	}
+	if conflict.Status != "resolved" {
+		t.Errorf("conflict = %+v", conflict)
+	}
+}
@@ -0,0 +1,158 @@
+package oakclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Custom field types
+const (
+	CustomFieldText    = "text"
+	CustomFieldNumber  = "number"
+	CustomFieldBoolean = "boolean"
+	CustomFieldDate    = "date" // YYYY-MM-DD
+	CustomFieldURL     = "url"
+)
+
+// CustomFieldTypes lists the custom field types.
+var CustomFieldTypes = []string{CustomFieldText, CustomFieldNumber, CustomFieldBoolean, CustomFieldDate, CustomFieldURL}
+
+// Records custom fields apply to
+const (
+	CustomEntitySpecies = "species"
+	CustomEntitySource  = "source"
+)
+
+// CustomField is a field a deployment defines for its own species or
+// source metadata.
+type CustomField struct {
+	Key       string    `json:"key"`
+	Type      string    `json:"type"`
+	Label     string    `json:"label"`
+	AppliesTo string    `json:"applies_to"` // species or source
+	Export    bool      `json:"export"`     // Included in the export
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CustomFieldRequest is the request body for defining a custom field.
+type CustomFieldRequest struct {
+	Type      string `json:"type"`
+	Label     string `json:"label"`
+	AppliesTo string `json:"applies_to"`
+	Export    bool   `json:"export"`
+}
+
+// CustomFieldsResponse is the list wrapper for custom fields.
+type CustomFieldsResponse struct {
+	Fields []*CustomField `json:"fields"`
+}
+
+// AttributesRequest sets custom field values. A nil value removes one, and
+// keys left out are kept.
+type AttributesRequest struct {
+	Attributes map[string]any `json:"attributes"`
+}
+
+// AttributesResponse holds a record's custom field values by key.
+type AttributesResponse struct {
+	Attributes map[string]any `json:"attributes"`
+}
+
+// ListCustomFields returns the custom fields applying to entity ("species"
+// or "source"), or all of them if entity is empty, by key.
+func (c *Client) ListCustomFields(ctx context.Context, entity string) ([]*CustomField, error) {
+	path := "/api/v1/custom-fields"
+	if entity != "" {
+		path += "?applies_to=" + url.QueryEscape(entity)
+	}
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result CustomFieldsResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Fields, nil
+}
+
+// SaveCustomField defines a custom field or changes its label and export
+// flag. Its type and applies_to can't change once records have values for
+// it; the server refuses with a conflict error. Requires an admin key.
+func (c *Client) SaveCustomField(ctx context.Context, key string, req *CustomFieldRequest) (*CustomField, error) {
+	resp, err := c.doRequest(ctx, http.MethodPut, "/api/v1/custom-fields/"+url.PathEscape(key), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var f CustomField
+	if err := c.parseResponse(resp, &f); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// DeleteCustomField deletes a custom field and every record's value for
+// it. Requires an admin key.
+func (c *Client) DeleteCustomField(ctx context.Context, key string) error {
+	resp, err := c.doRequest(ctx, http.MethodDelete, "/api/v1/custom-fields/"+url.PathEscape(key), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
+
+// GetSpeciesAttributes returns a species' custom field values by key.
+func (c *Client) GetSpeciesAttributes(ctx context.Context, name string) (map[string]any, error) {
+	return c.attributes(ctx, http.MethodGet, "/api/v1/species/"+url.PathEscape(name)+"/attributes", nil)
+}
+
+// SetSpeciesAttributes sets a species' custom field values, removing those
+// given as nil, and returns all of its values. Requires an API key.
+func (c *Client) SetSpeciesAttributes(ctx context.Context, name string, values map[string]any) (map[string]any, error) {
+	return c.attributes(ctx, http.MethodPut, "/api/v1/species/"+url.PathEscape(name)+"/attributes", &AttributesRequest{Attributes: values})
+}
+
+// GetSourceAttributes returns a source's custom field values by key.
+func (c *Client) GetSourceAttributes(ctx context.Context, id int64) (map[string]any, error) {
+	return c.attributes(ctx, http.MethodGet, "/api/v1/sources/"+strconv.FormatInt(id, 10)+"/attributes", nil)
+}
+
+// SetSourceAttributes sets a source's custom field values, removing those
+// given as nil, and returns all of its values. Requires an API key.
+func (c *Client) SetSourceAttributes(ctx context.Context, id int64, values map[string]any) (map[string]any, error) {
+	return c.attributes(ctx, http.MethodPut, "/api/v1/sources/"+strconv.FormatInt(id, 10)+"/attributes", &AttributesRequest{Attributes: values})
+}
+
+func (c *Client) attributes(ctx context.Context, method, path string, req *AttributesRequest) (map[string]any, error) {
+	var body any
+	if req != nil {
+		body = req
+	}
+	resp, err := c.doRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result AttributesResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Attributes, nil
+}
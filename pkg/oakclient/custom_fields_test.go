@@ -0,0 +1,55 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetSpeciesAttributes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/species/alba/attributes" {
+			t.Errorf("request = %s %s, want PUT /api/v1/species/alba/attributes", r.Method, r.URL.Path)
+		}
+		var req map[string]map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if v, ok := req["attributes"]["herbarium_sheet"]; !ok || v != nil {
+			t.Errorf("herbarium_sheet = %v, want null", v)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(AttributesResponse{Attributes: map[string]any{"hardiness_zone": 3}})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	values, err := c.SetSpeciesAttributes(t.Context(), "alba", map[string]any{"hardiness_zone": 3, "herbarium_sheet": nil})
+	if err != nil {
+		t.Fatalf("SetSpeciesAttributes() error = %v", err)
+	}
+	if values["hardiness_zone"] != 3.0 {
+		t.Errorf("values = %v", values)
+	}
+}
+
+func TestListCustomFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.RequestURI(); got != "/api/v1/custom-fields?applies_to=source" {
+			t.Errorf("request = %s, want /api/v1/custom-fields?applies_to=source", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(CustomFieldsResponse{Fields: []*CustomField{{Key: "digitized", Type: CustomFieldBoolean, AppliesTo: "source"}}})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	fields, err := c.ListCustomFields(t.Context(), "source")
+	if err != nil {
+		t.Fatalf("ListCustomFields() error = %v", err)
+	}
+	if len(fields) != 1 || fields[0].Key != "digitized" {
+		t.Errorf("fields = %+v", fields)
+	}
+}
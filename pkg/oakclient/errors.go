@@ -0,0 +1,177 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+// Sentinel errors for the kinds of failure callers branch on. Every error the
+// client returns for an API response or an unreachable server matches one of
+// them with errors.Is; errors.As gives the *APIError, *MultiValidationError,
+// or *ConnectionError with the details.
+var (
+	ErrNotFound           = errors.New("not found")                        // 404
+	ErrUnauthorized       = errors.New("unauthorized")                     // 401: missing or invalid API key
+	ErrForbidden          = errors.New("forbidden")                        // 403
+	ErrConflict           = errors.New("conflict")                         // 409: exists or still referenced
+	ErrPreconditionFailed = errors.New("precondition failed")              // 412: changed since read (If-Match)
+	ErrValidation         = errors.New("validation failed")                // 400 or 422
+	ErrClientTooOld       = errors.New("client too old")                   // 426
+	ErrRateLimited        = errors.New("rate limited")                     // 429
+	ErrMaintenance        = errors.New("writes disabled for maintenance")  // 503 MAINTENANCE
+	ErrServer             = errors.New("server error")                     // Other 5xx
+	ErrConnection         = errors.New("cannot connect to the API server") // No response
+)
+
+// APIError represents an error response from the API. Code is one of the
+// apierror codes (apierror.CodeNotFound, ...); Field, Entity, and ID are set
+// when the server names the request field or record at fault.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Field      string
+	Entity     string
+	ID         string
+
+	// Fields lists every field that failed validation, for ErrValidation.
+	Fields []ValidationError
+
+	// Details is the raw "details" of the response, for codes that carry
+	// more, such as the hybrids blocking a species delete.
+	Details json.RawMessage
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("API error (%d %s): %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("API error (%d): %s", e.StatusCode, e.Message)
+}
+
+// Is matches the sentinel error for the response's code, or for its status
+// when the response carried no code the client knows.
+func (e *APIError) Is(target error) bool {
+	status := e.StatusCode
+	if s := apierror.Status(e.Code); s != http.StatusInternalServerError {
+		status = s
+	}
+	maintenance := e.Code == apierror.CodeMaintenance
+	switch target {
+	case ErrNotFound:
+		return status == http.StatusNotFound
+	case ErrUnauthorized:
+		return status == http.StatusUnauthorized
+	case ErrForbidden:
+		return status == http.StatusForbidden
+	case ErrConflict:
+		return status == http.StatusConflict
+	case ErrPreconditionFailed:
+		return status == http.StatusPreconditionFailed
+	case ErrValidation:
+		return status == http.StatusBadRequest || status == http.StatusUnprocessableEntity
+	case ErrClientTooOld:
+		return status == http.StatusUpgradeRequired
+	case ErrRateLimited:
+		return status == http.StatusTooManyRequests
+	case ErrMaintenance:
+		return maintenance
+	case ErrServer:
+		return status >= 500 && !maintenance
+	}
+	return false
+}
+
+// ValidationError is a validation failure of one request field.
+type ValidationError = apierror.FieldError
+
+// MultiValidationError wraps multiple validation errors from the API.
+type MultiValidationError struct {
+	Errors []ValidationError `json:"errors"`
+}
+
+func (e *MultiValidationError) Error() string {
+	if len(e.Errors) == 0 {
+		return "validation failed"
+	}
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", err.Field, err.Message))
+	}
+	return fmt.Sprintf("validation errors: %s", strings.Join(msgs, "; "))
+}
+
+// Is matches ErrValidation.
+func (e *MultiValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// ConnectionError represents a connection failure to the API server.
+type ConnectionError struct {
+	URL     string
+	Profile string
+	Err     error
+}
+
+func (e *ConnectionError) Error() string {
+	return fmt.Sprintf("failed to connect to API server at %s (profile: %s): %s", e.URL, e.Profile, e.Err)
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches ErrConnection.
+func (e *ConnectionError) Is(target error) bool {
+	return target == ErrConnection
+}
+
+// ErrorCode returns the API error code of err (apierror.CodeNotFound, ...),
+// or "" if err is not an API error.
+func ErrorCode(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	var multi *MultiValidationError
+	if errors.As(err, &multi) {
+		return apierror.CodeValidation
+	}
+	return ""
+}
+
+// IsConnectionError returns true if the error is a connection failure.
+func IsConnectionError(err error) bool {
+	return errors.Is(err, ErrConnection)
+}
+
+// IsNotFoundError returns true if the error is a 404 Not Found.
+func IsNotFoundError(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflictError returns true if the error is a 409 Conflict.
+func IsConflictError(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsPreconditionFailedError returns true if the error is a 412 Precondition
+// Failed: an If-Match write refused because the record changed since it was read.
+func IsPreconditionFailedError(err error) bool {
+	return errors.Is(err, ErrPreconditionFailed)
+}
+
+// IsMaintenanceError returns true if the server refused a write because it is in maintenance mode.
+func IsMaintenanceError(err error) bool {
+	return errors.Is(err, ErrMaintenance)
+}
+
+// IsAuthError returns true if the error is a 401 Unauthorized.
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
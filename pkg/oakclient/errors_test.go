@@ -0,0 +1,66 @@
+package oakclient
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jeff/oaks/pkg/apierror"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	sentinels := []error{
+		ErrNotFound, ErrUnauthorized, ErrForbidden, ErrConflict, ErrPreconditionFailed,
+		ErrValidation, ErrClientTooOld, ErrRateLimited, ErrMaintenance, ErrServer, ErrConnection,
+	}
+	tests := []struct {
+		err  *APIError
+		want error
+	}{
+		{&APIError{StatusCode: 400, Code: apierror.CodeValidation}, ErrValidation},
+		{&APIError{StatusCode: 401}, ErrUnauthorized},
+		{&APIError{StatusCode: 403}, ErrForbidden},
+		{&APIError{StatusCode: 404, Code: apierror.CodeNotFound}, ErrNotFound},
+		{&APIError{StatusCode: 409, Code: apierror.CodeConflict}, ErrConflict},
+		{&APIError{StatusCode: 412, Code: apierror.CodePreconditionFailed}, ErrPreconditionFailed},
+		{&APIError{StatusCode: 422}, ErrValidation},
+		{&APIError{StatusCode: 426, Code: apierror.CodeClientTooOld}, ErrClientTooOld},
+		{&APIError{StatusCode: 429}, ErrRateLimited},
+		{&APIError{StatusCode: 500}, ErrServer},
+		{&APIError{StatusCode: 503, Code: apierror.CodeMaintenance}, ErrMaintenance},
+		{&APIError{StatusCode: 503}, ErrServer},
+	}
+	for _, tt := range tests {
+		// Wrapped, as the retry loop and callers return them
+		err := fmt.Errorf("request failed: %w", tt.err)
+		for _, sentinel := range sentinels {
+			if got, want := errors.Is(err, sentinel), sentinel == tt.want; got != want {
+				t.Errorf("errors.Is(%v, %v) = %v, want %v", tt.err, sentinel, got, want)
+			}
+		}
+	}
+}
+
+func TestMultiValidationError_Is(t *testing.T) {
+	err := &MultiValidationError{Errors: []ValidationError{{Field: "name", Message: "is required"}}}
+	if !errors.Is(err, ErrValidation) {
+		t.Error("MultiValidationError should match ErrValidation")
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Error("MultiValidationError should not match ErrNotFound")
+	}
+}
+
+func TestConnectionError_Is(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("wrapped: %w", &ConnectionError{URL: "http://localhost:8080", Profile: "test", Err: cause})
+	if !errors.Is(err, ErrConnection) {
+		t.Error("ConnectionError should match ErrConnection")
+	}
+	if !errors.Is(err, cause) {
+		t.Error("ConnectionError should unwrap to its cause")
+	}
+	if errors.Is(err, ErrServer) {
+		t.Error("ConnectionError should not match ErrServer")
+	}
+}
@@ -0,0 +1,129 @@
+package oakclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/jeff/oaks/pkg/oakclient"
+)
+
+// exampleServer stands in for an Oak Compendium API with two species.
+func exampleServer() *httptest.Server {
+	species := []*oakclient.OakEntry{
+		{ScientificName: "alba", Author: strPtr("L.")},
+		{ScientificName: "robur", Author: strPtr("L.")},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v1/species":
+			json.NewEncoder(w).Encode(oakclient.SpeciesListResponse{
+				Data:       species,
+				Pagination: oakclient.Pagination{Total: len(species), Limit: oakclient.DefaultPageSize},
+			})
+		case strings.HasPrefix(r.URL.Path, "/api/v1/species/"):
+			name := strings.TrimPrefix(r.URL.Path, "/api/v1/species/")
+			for _, sp := range species {
+				if sp.ScientificName == name {
+					w.Header().Set("ETag", `"abc123"`)
+					json.NewEncoder(w).Encode(sp)
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func strPtr(s string) *string { return &s }
+
+func Example() {
+	server := exampleServer()
+	defer server.Close()
+
+	c, err := oakclient.New(server.URL, oakclient.WithSkipVersionCheck(true))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sp, err := c.GetSpecies(context.Background(), "alba")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Quercus", sp.ScientificName, *sp.Author)
+	// Output: Quercus alba L.
+}
+
+func ExampleClient_AllSpecies() {
+	server := exampleServer()
+	defer server.Close()
+
+	c, err := oakclient.New(server.URL, oakclient.WithSkipVersionCheck(true))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for sp, err := range c.AllSpecies(context.Background(), nil) {
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(sp.ScientificName)
+	}
+	// Output:
+	// alba
+	// robur
+}
+
+func ExampleErrNotFound() {
+	server := exampleServer()
+	defer server.Close()
+
+	c, err := oakclient.New(server.URL, oakclient.WithSkipVersionCheck(true))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = c.GetSpecies(context.Background(), "imaginaria")
+	if errors.Is(err, oakclient.ErrNotFound) {
+		fmt.Println("no such species")
+	}
+
+	var apiErr *oakclient.APIError
+	if errors.As(err, &apiErr) {
+		fmt.Println("status", apiErr.StatusCode)
+	}
+	// Output:
+	// no such species
+	// status 404
+}
+
+func ExampleClient_UpdateSpeciesIfMatch() {
+	server := exampleServer()
+	defer server.Close()
+
+	c, err := oakclient.New(server.URL, oakclient.WithAPIKey("my-key"), oakclient.WithSkipVersionCheck(true))
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// Read the species with its ETag, then write back only if nobody
+	// changed it in between.
+	sp, etag, err := c.GetSpeciesWithETag(ctx, "robur")
+	if err != nil {
+		log.Fatal(err)
+	}
+	req := oakclient.EntryToRequest(sp)
+	req.ConservationStatus = strPtr("LC")
+	if _, err := c.UpdateSpeciesIfMatch(ctx, "robur", req, etag); errors.Is(err, oakclient.ErrPreconditionFailed) {
+		fmt.Println("robur changed since it was read; re-read and retry")
+	}
+}
@@ -0,0 +1,91 @@
+package oakclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ExportOptions selects optional export content.
+type ExportOptions struct {
+	Accounts bool   // Embed each species' rendered long-form account
+	Units    string // Convert measurements in source text: metric, imperial, or dual
+	Mapping  string // Reshape the payload with a server-configured export mapping
+
+	// ConservationStatus limits the export to species with these IUCN codes;
+	// Threatened adds CR, EN, and VU.
+	ConservationStatus []string
+	Threatened         bool
+
+	// Languages limits common names to these BCP 47 language ranges, such
+	// as "en" or "fr-CA"; "auto" uses the client's Accept-Language.
+	Languages []string
+}
+
+// path returns the export route with the options as query parameters
+func (o ExportOptions) path() string {
+	query := url.Values{}
+	if o.Accounts {
+		query.Set("accounts", "true")
+	}
+	if o.Units != "" {
+		query.Set("units", o.Units)
+	}
+	if o.Mapping != "" {
+		query.Set("mapping", o.Mapping)
+	}
+	if len(o.ConservationStatus) > 0 {
+		query.Set("conservation_status", strings.Join(o.ConservationStatus, ","))
+	}
+	if o.Threatened {
+		query.Set("threatened", "true")
+	}
+	if len(o.Languages) > 0 {
+		query.Set("lang", strings.Join(o.Languages, ","))
+	}
+	if len(query) == 0 {
+		return "/api/v1/export"
+	}
+	return "/api/v1/export?" + query.Encode()
+}
+
+// Export retrieves the full export from the API.
+// The response is a JSON object containing all species data.
+func (c *Client) Export(ctx context.Context, opts ExportOptions) (json.RawMessage, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, opts.path(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(data), nil
+}
+
+// ExportToWriter writes the export directly to a writer.
+// This is more efficient for large exports as it doesn't buffer the entire response.
+func (c *Client) ExportToWriter(ctx context.Context, w io.Writer, opts ExportOptions) error {
+	resp, err := c.doRequest(ctx, http.MethodGet, opts.path(), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
@@ -1,4 +1,4 @@
-package client
+package oakclient
 
 import (
 	"bytes"
@@ -30,7 +30,7 @@ func TestExport_Success(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	data, err := c.Export()
+	data, err := c.Export(t.Context(), ExportOptions{})
 	if err != nil {
 		t.Fatalf("Export() error = %v", err)
 	}
@@ -57,7 +57,7 @@ func TestExport_ServerError(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	_, err := c.Export()
+	_, err := c.Export(t.Context(), ExportOptions{})
 	if err == nil {
 		t.Fatal("expected error for server error response")
 	}
@@ -81,7 +81,7 @@ func TestExportToWriter_Success(t *testing.T) {
 
 	c := newTestClient(t, server)
 	var buf bytes.Buffer
-	err := c.ExportToWriter(&buf)
+	err := c.ExportToWriter(t.Context(), &buf, ExportOptions{})
 	if err != nil {
 		t.Fatalf("ExportToWriter() error = %v", err)
 	}
@@ -99,7 +99,7 @@ func TestExportToWriter_ServerError(t *testing.T) {
 
 	c := newTestClient(t, server)
 	var buf bytes.Buffer
-	err := c.ExportToWriter(&buf)
+	err := c.ExportToWriter(t.Context(), &buf, ExportOptions{})
 	if err == nil {
 		t.Fatal("expected error for server error response")
 	}
@@ -123,7 +123,7 @@ func TestExport_LargeResponse(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	data, err := c.Export()
+	data, err := c.Export(t.Context(), ExportOptions{})
 	if err != nil {
 		t.Fatalf("Export() error = %v", err)
 	}
@@ -149,7 +149,7 @@ func TestExport_Unauthorized(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	_, err := c.Export()
+	_, err := c.Export(t.Context(), ExportOptions{})
 	if err == nil {
 		t.Fatal("expected error for unauthorized response")
 	}
@@ -157,3 +157,55 @@ func TestExport_Unauthorized(t *testing.T) {
 		t.Errorf("expected auth error, got %v", err)
 	}
 }
+
+func TestExport_Accounts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("accounts") != "true" {
+			t.Errorf("query = %q, want accounts=true", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"species":[]}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	if _, err := c.Export(t.Context(), ExportOptions{Accounts: true}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+}
+
+func TestExport_Units(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "accounts=true&mapping=mobile&units=imperial" {
+			t.Errorf("query = %q, want accounts=true&mapping=mobile&units=imperial", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"species":[]}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	if _, err := c.Export(t.Context(), ExportOptions{Accounts: true, Units: "imperial", Mapping: "mobile"}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+}
+
+func TestExport_ConservationFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("conservation_status") != "EN,CR" {
+			t.Errorf("conservation_status = %q, want EN,CR", q.Get("conservation_status"))
+		}
+		if q.Get("threatened") != "true" {
+			t.Errorf("threatened = %q, want true", q.Get("threatened"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"species":[]}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	if _, err := c.Export(t.Context(), ExportOptions{ConservationStatus: []string{"EN", "CR"}, Threatened: true}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package oakclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// FeatureSuggestion is a source-text sentence queued for review as one of a
+// species' distinguishing features.
+type FeatureSuggestion struct {
+	ID             int64      `json:"id"`
+	ScientificName string     `json:"scientific_name"`
+	SourceID       int64      `json:"source_id"`
+	Field          string     `json:"field"`
+	Sentence       string     `json:"sentence"`
+	Status         string     `json:"status"` // pending, accepted, or rejected
+	CreatedAt      time.Time  `json:"created_at"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// FeatureSuggestionsListResponse is the paginated list wrapper for suggestions.
+type FeatureSuggestionsListResponse struct {
+	Data []*FeatureSuggestion `json:"data"`
+}
+
+// GenerateFeatureSuggestions scans source text for distinguishing-feature
+// sentences, for the given species or every species, and returns how many were queued.
+func (c *Client) GenerateFeatureSuggestions(ctx context.Context, species []string) (int, error) {
+	body := map[string]interface{}{}
+	if len(species) > 0 {
+		body["species"] = species
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/feature-suggestions/generate", body)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Queued int `json:"queued"`
+	}
+	if err := c.parseResponse(resp, &result); err != nil {
+		return 0, err
+	}
+
+	return result.Queued, nil
+}
+
+// ListFeatureSuggestions lists suggestions with a status (pending, accepted,
+// rejected, or all; empty means pending), optionally for one species.
+func (c *Client) ListFeatureSuggestions(ctx context.Context, status, species string) ([]*FeatureSuggestion, error) {
+	query := url.Values{}
+	if status != "" {
+		query.Set("status", status)
+	}
+	if species != "" {
+		query.Set("species", species)
+	}
+	path := "/api/v1/feature-suggestions"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result FeatureSuggestionsListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// ReviewFeatureSuggestion accepts or rejects a pending suggestion. Accepting
+// adds the sentence to the species source's distinguishing features.
+func (c *Client) ReviewFeatureSuggestion(ctx context.Context, id int64, accept bool) (*FeatureSuggestion, error) {
+	action := "reject"
+	if accept {
+		action = "accept"
+	}
+	path := fmt.Sprintf("/api/v1/feature-suggestions/%d/%s", id, action)
+
+	resp, err := c.doRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var suggestion FeatureSuggestion
+	if err := c.parseResponse(resp, &suggestion); err != nil {
+		return nil, err
+	}
+
+	return &suggestion, nil
+}
@@ -0,0 +1,48 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListFeatureSuggestions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/feature-suggestions" || r.URL.RawQuery != "species=texana&status=all" {
+			t.Errorf("request = %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(FeatureSuggestionsListResponse{Data: []*FeatureSuggestion{{ID: 3, ScientificName: "texana"}}})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	suggestions, err := c.ListFeatureSuggestions(t.Context(), "all", "texana")
+	if err != nil {
+		t.Fatalf("ListFeatureSuggestions() error = %v", err)
+	}
+	if len(suggestions) != 1 || suggestions[0].ID != 3 {
+		t.Errorf("suggestions = %+v", suggestions)
+	}
+}
+
+func TestReviewFeatureSuggestion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/feature-suggestions/3/accept" {
+			t.Errorf("request = %s %s, want POST /api/v1/feature-suggestions/3/accept", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(FeatureSuggestion{ID: 3, Status: "accepted"})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	s, err := c.ReviewFeatureSuggestion(t.Context(), 3, true)
+	if err != nil {
+		t.Fatalf("ReviewFeatureSuggestion() error = %v", err)
+	}
+	if s.Status != "accepted" {
+		t.Errorf("suggestion = %+v", s)
+	}
+}
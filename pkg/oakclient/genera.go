@@ -0,0 +1,67 @@
+package oakclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// GenusRequest represents the request body for creating/updating a genus.
+type GenusRequest struct {
+	Name       string   `json:"name"`
+	CommonName *string  `json:"common_name,omitempty"`
+	Subgenera  []string `json:"subgenera,omitempty"`
+}
+
+// GeneraListResponse contains the list of genera.
+type GeneraListResponse struct {
+	Data       []*Genus   `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// ListGenera retrieves every genus with its species count.
+func (c *Client) ListGenera(ctx context.Context) ([]*Genus, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/genera", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result GeneraListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// CreateGenus creates a new genus.
+func (c *Client) CreateGenus(ctx context.Context, req *GenusRequest) (*Genus, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/genera", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var genus Genus
+	if err := c.parseResponse(resp, &genus); err != nil {
+		return nil, err
+	}
+
+	return &genus, nil
+}
+
+// DeleteGenus deletes a genus. The API refuses while species or taxa belong to it.
+func (c *Client) DeleteGenus(ctx context.Context, name string) error {
+	resp, err := c.doRequest(ctx, http.MethodDelete, "/api/v1/genera/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
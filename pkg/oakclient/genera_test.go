@@ -0,0 +1,53 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListGenera(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/genera" {
+			t.Errorf("request = %s %s, want GET /api/v1/genera", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(GeneraListResponse{
+			Data: []*Genus{
+				{Name: "Carya", Subgenera: []string{}, SpeciesCount: 1},
+				{Name: "Quercus", Subgenera: []string{"Quercus", "Cerris", "Cyclobalanopsis"}, SpeciesCount: 3},
+			},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	genera, err := c.ListGenera(t.Context())
+	if err != nil {
+		t.Fatalf("ListGenera() error = %v", err)
+	}
+	if len(genera) != 2 || genera[1].Name != "Quercus" || len(genera[1].Subgenera) != 3 {
+		t.Errorf("genera = %+v", genera)
+	}
+}
+
+func TestDeleteGenus_InUse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/api/v1/genera/Carya" {
+			t.Errorf("request = %s %s, want DELETE /api/v1/genera/Carya", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": map[string]string{"code": "CONFLICT", "message": "genus Carya still has 1 species and 0 taxa"},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	err := c.DeleteGenus(t.Context(), "Carya")
+	if !IsConflictError(err) {
+		t.Errorf("DeleteGenus() error = %v, want conflict", err)
+	}
+}
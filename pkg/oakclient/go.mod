@@ -0,0 +1,5 @@
+module github.com/jeff/oaks/pkg/oakclient
+
+go 1.24.0
+
+require github.com/jeff/oaks/pkg/apierror v0.1.0
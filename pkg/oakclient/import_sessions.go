@@ -0,0 +1,139 @@
+package oakclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ImportSessionHeader attaches a bulk species-source upload to an import session.
+const ImportSessionHeader = "X-Import-Session"
+
+// ImportSession groups bulk uploads made over several requests so they are
+// applied together on commit, or not at all.
+type ImportSession struct {
+	ID        int64      `json:"id"`
+	Status    string     `json:"status"` // open, committed, or discarded
+	Batches   int        `json:"batches"`
+	Items     int        `json:"items"`
+	CreatedAt time.Time  `json:"created_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+}
+
+// StagedImport is the server's acknowledgement of an upload staged in a session.
+type StagedImport struct {
+	SessionID int64 `json:"session_id"`
+	Batch     int   `json:"batch"`
+	Staged    int   `json:"staged"`
+}
+
+// ImportBatchResult is the outcome of one staged upload after commit.
+type ImportBatchResult struct {
+	Batch    int                       `json:"batch"`
+	SourceID int64                     `json:"source_id"`
+	Results  []BulkSpeciesSourceResult `json:"results"`
+}
+
+// ImportSessionCommit summarizes a committed import session.
+type ImportSessionCommit struct {
+	Session   *ImportSession      `json:"session"`
+	Created   int                 `json:"created"`
+	Updated   int                 `json:"updated"`
+	Unchanged int                 `json:"unchanged"`
+	Batches   []ImportBatchResult `json:"batches"`
+}
+
+// CreateImportSession opens a new import session.
+func (c *Client) CreateImportSession(ctx context.Context) (*ImportSession, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/import/sessions", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var session ImportSession
+	if err := c.parseResponse(resp, &session); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// GetImportSession retrieves an import session and its staged counts.
+func (c *Client) GetImportSession(ctx context.Context, id int64) (*ImportSession, error) {
+	path := fmt.Sprintf("/api/v1/import/sessions/%d", id)
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var session ImportSession
+	if err := c.parseResponse(resp, &session); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// StageSpeciesSources validates a bulk species-source upload and stages it in
+// an open import session. Nothing is written until the session is committed.
+func (c *Client) StageSpeciesSources(ctx context.Context, sessionID, sourceID int64, items []*BulkSpeciesSource) (*StagedImport, error) {
+	headers := http.Header{}
+	headers.Set(ImportSessionHeader, strconv.FormatInt(sessionID, 10))
+
+	path := fmt.Sprintf("/api/v1/sources/%d/species-sources", sourceID)
+	resp, err := c.doRequestWithHeaders(ctx, http.MethodPut, path, items, headers)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var staged StagedImport
+	if err := c.parseResponse(resp, &staged); err != nil {
+		return nil, err
+	}
+
+	return &staged, nil
+}
+
+// CommitImportSession applies every upload staged in a session in a single
+// transaction. If any row fails, nothing is written, the session stays open,
+// and a validation error lists the failed rows.
+func (c *Client) CommitImportSession(ctx context.Context, id int64) (*ImportSessionCommit, error) {
+	path := fmt.Sprintf("/api/v1/import/sessions/%d/commit", id)
+
+	resp, err := c.doRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ImportSessionCommit
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DiscardImportSession drops a session's staged uploads without applying them.
+func (c *Client) DiscardImportSession(ctx context.Context, id int64) (*ImportSession, error) {
+	path := fmt.Sprintf("/api/v1/import/sessions/%d/discard", id)
+
+	resp, err := c.doRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var session ImportSession
+	if err := c.parseResponse(resp, &session); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
@@ -0,0 +1,73 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestImportSession_StageAndCommit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/import/sessions":
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(ImportSession{ID: 7, Status: "open"})
+		case "/api/v1/sources/3/species-sources":
+			if got := r.Header.Get(ImportSessionHeader); got != "7" {
+				t.Errorf("%s = %q, want 7", ImportSessionHeader, got)
+			}
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(StagedImport{SessionID: 7, Batch: 1, Staged: 1})
+		case "/api/v1/import/sessions/7/commit":
+			if r.Method != http.MethodPost {
+				t.Errorf("method = %s, want POST", r.Method)
+			}
+			json.NewEncoder(w).Encode(ImportSessionCommit{
+				Session: &ImportSession{ID: 7, Status: "committed", Batches: 1, Items: 1},
+				Created: 1,
+				Batches: []ImportBatchResult{{Batch: 1, SourceID: 3, Results: []BulkSpeciesSourceResult{{ScientificName: "alba", Status: "created"}}}},
+			})
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	session, err := c.CreateImportSession(t.Context())
+	if err != nil {
+		t.Fatalf("CreateImportSession() error = %v", err)
+	}
+	staged, err := c.StageSpeciesSources(t.Context(), session.ID, 3, []*BulkSpeciesSource{{SpeciesSource: SpeciesSource{ScientificName: "alba"}}})
+	if err != nil {
+		t.Fatalf("StageSpeciesSources() error = %v", err)
+	}
+	if staged.Batch != 1 || staged.Staged != 1 {
+		t.Errorf("staged = %+v, want batch 1 with 1 row", staged)
+	}
+	result, err := c.CommitImportSession(t.Context(), session.ID)
+	if err != nil {
+		t.Fatalf("CommitImportSession() error = %v", err)
+	}
+	if result.Created != 1 || result.Session.Status != "committed" || len(result.Batches) != 1 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestDiscardImportSession_Closed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": map[string]string{"code": "CONFLICT", "message": "import session 7 is already committed"},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	if _, err := c.DiscardImportSession(t.Context(), 7); err == nil {
+		t.Fatal("DiscardImportSession() error = nil, want conflict")
+	}
+}
@@ -0,0 +1,65 @@
+package oakclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// INaturalistTaxon is the iNaturalist taxon a species is linked to or
+// matched, and its cached photos.
+type INaturalistTaxon struct {
+	TaxonID     *int64          `json:"taxon_id"` // nil when not linked or not matched
+	URL         *string         `json:"url,omitempty"`
+	Name        string          `json:"name,omitempty"`        // iNaturalist's name; only from a lookup
+	CommonName  string          `json:"common_name,omitempty"` // iNaturalist's preferred common name; only from a lookup
+	LinkChanged bool            `json:"link_changed"`          // The lookup added or changed the species' link
+	Photos      []*SpeciesMedia `json:"photos"`
+}
+
+// INaturalistOptions controls an iNaturalist lookup.
+type INaturalistOptions struct {
+	Photos int  // Caches up to this many photo URLs, replacing those cached before (at most 20)
+	DryRun bool // Reports the match without storing anything
+}
+
+// GetINaturalist returns the iNaturalist taxon a species is linked to and
+// its cached photos. The server does not contact iNaturalist.
+func (c *Client) GetINaturalist(ctx context.Context, name string) (*INaturalistTaxon, error) {
+	return c.inaturalist(ctx, http.MethodGet, "/api/v1/species/"+url.PathEscape(name)+"/inaturalist")
+}
+
+// EnrichINaturalist has the server match a species against iNaturalist's
+// taxa and store the taxon as the species' iNaturalist link, and with
+// opts.Photos cache representative photo URLs. A species iNaturalist does
+// not know has a nil TaxonID. Requires an API key.
+func (c *Client) EnrichINaturalist(ctx context.Context, name string, opts INaturalistOptions) (*INaturalistTaxon, error) {
+	query := url.Values{}
+	if opts.Photos > 0 {
+		query.Set("photos", strconv.Itoa(opts.Photos))
+	}
+	if opts.DryRun {
+		query.Set("dry_run", "true")
+	}
+	path := "/api/v1/species/" + url.PathEscape(name) + "/inaturalist"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.inaturalist(ctx, http.MethodPost, path)
+}
+
+func (c *Client) inaturalist(ctx context.Context, method, path string) (*INaturalistTaxon, error) {
+	resp, err := c.doRequest(ctx, method, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var taxon INaturalistTaxon
+	if err := c.parseResponse(resp, &taxon); err != nil {
+		return nil, err
+	}
+
+	return &taxon, nil
+}
@@ -0,0 +1,30 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEnrichINaturalist(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.RequestURI() != "/api/v1/species/alba/inaturalist?dry_run=true&photos=3" {
+			t.Errorf("request = %s %s, want POST /api/v1/species/alba/inaturalist?dry_run=true&photos=3", r.Method, r.URL.RequestURI())
+		}
+		id := int64(54779)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(INaturalistTaxon{TaxonID: &id, Name: "Quercus alba", LinkChanged: true,
+			Photos: []*SpeciesMedia{{URL: "https://example.org/1/medium.jpg", Origin: "inaturalist"}}})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	taxon, err := c.EnrichINaturalist(t.Context(), "alba", INaturalistOptions{Photos: 3, DryRun: true})
+	if err != nil {
+		t.Fatalf("EnrichINaturalist() error = %v", err)
+	}
+	if taxon.TaxonID == nil || *taxon.TaxonID != 54779 || !taxon.LinkChanged || len(taxon.Photos) != 1 {
+		t.Errorf("taxon = %+v", taxon)
+	}
+}
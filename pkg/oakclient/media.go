@@ -0,0 +1,172 @@
+package oakclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Species media origins
+const (
+	MediaOriginINaturalist = "inaturalist" // Photo URLs cached from iNaturalist
+	MediaOriginUpload      = "upload"      // Photos uploaded to the server
+	MediaOriginLink        = "link"        // Media kept elsewhere, attached by URL
+)
+
+// MediaKinds are what a species photo can show
+var MediaKinds = []string{"leaf", "bark", "acorn", "twig", "bud", "flower", "habit", "specimen", "other"}
+
+// SpeciesMedia is a photo or other media of a species. Uploaded photos are
+// served by the API at URL and ThumbnailURL, which are paths on the server.
+type SpeciesMedia struct {
+	ID             int64     `json:"id"`
+	ScientificName string    `json:"scientific_name"`
+	URL            string    `json:"url"`
+	ThumbnailURL   *string   `json:"thumbnail_url,omitempty"`
+	Caption        *string   `json:"caption,omitempty"`
+	License        *string   `json:"license,omitempty"`
+	Attribution    *string   `json:"attribution,omitempty"`
+	Origin         string    `json:"origin"`              // Where it came from, such as inaturalist
+	OriginID       *string   `json:"origin_id,omitempty"` // Its ID there; an upload's SHA-256
+	File           *string   `json:"file,omitempty"`      // Uploads: the file in the server's media directory
+	ThumbnailFile  *string   `json:"thumbnail_file,omitempty"`
+	Filename       *string   `json:"filename,omitempty"` // Uploads: the name it was uploaded under
+	Width          *int      `json:"width,omitempty"`
+	Height         *int      `json:"height,omitempty"`
+	CapturedAt     *string   `json:"captured_at,omitempty"` // From EXIF, as the camera recorded it
+	Latitude       *float64  `json:"latitude,omitempty"`    // From EXIF
+	Longitude      *float64  `json:"longitude,omitempty"`
+	Kind           *string   `json:"kind,omitempty"` // One of MediaKinds
+	SourceID       *int64    `json:"source_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	Generalized    float64   `json:"generalized,omitempty"` // Grid size the coordinates were generalized to, for the public
+}
+
+// SpeciesMediaListResponse is the response of the species media list
+type SpeciesMediaListResponse struct {
+	Data []*SpeciesMedia `json:"data"`
+}
+
+// MediaLink attaches media kept elsewhere by its URL
+type MediaLink struct {
+	URL          string  `json:"url"`
+	ThumbnailURL *string `json:"thumbnail_url,omitempty"`
+	Kind         *string `json:"kind,omitempty"`
+	Caption      *string `json:"caption,omitempty"`
+	License      *string `json:"license,omitempty"`
+	Attribution  *string `json:"attribution,omitempty"`
+	SourceID     *int64  `json:"source_id,omitempty"`
+}
+
+// MediaUpload is a photo to attach to a species
+type MediaUpload struct {
+	Filename    string
+	Content     io.Reader // JPEG, PNG, or GIF, at most 25MB
+	Kind        string    // Optional fields; Kind is one of MediaKinds
+	SourceID    int64
+	License     string
+	Caption     string
+	Attribution string
+}
+
+// ListSpeciesMedia returns a species' media in order, only of one kind if
+// kind is not empty.
+func (c *Client) ListSpeciesMedia(ctx context.Context, name, kind string) ([]*SpeciesMedia, error) {
+	path := "/api/v1/species/" + url.PathEscape(name) + "/media"
+	if kind != "" {
+		path += "?kind=" + url.QueryEscape(kind)
+	}
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result SpeciesMediaListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// LinkSpeciesMedia attaches media kept elsewhere to a species by its URL.
+// A URL already attached to the species is a conflict. Requires an API key.
+func (c *Client) LinkSpeciesMedia(ctx context.Context, name string, link *MediaLink) (*SpeciesMedia, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/species/"+url.PathEscape(name)+"/media", link)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var m SpeciesMedia
+	if err := c.parseResponse(resp, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// DeleteSpeciesMedia removes media from a species. The server deletes an
+// uploaded file once no media uses it. Requires an API key.
+func (c *Client) DeleteSpeciesMedia(ctx context.Context, name string, id int64) error {
+	resp, err := c.doRequest(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/species/%s/media/%d", url.PathEscape(name), id), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
+
+// UploadSpeciesMedia attaches a photo to a species. The server stores it
+// with a thumbnail and reads its capture time and location from EXIF. A
+// photo already attached to the species is a conflict (see
+// IsConflictError). Requires an API key.
+func (c *Client) UploadSpeciesMedia(ctx context.Context, name string, upload MediaUpload) (*SpeciesMedia, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", upload.Filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build upload: %w", err)
+	}
+	if _, err := io.Copy(part, upload.Content); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", upload.Filename, err)
+	}
+	fields := map[string]string{"kind": upload.Kind, "license": upload.License, "caption": upload.Caption, "attribution": upload.Attribution}
+	if upload.SourceID != 0 {
+		fields["source_id"] = strconv.FormatInt(upload.SourceID, 10)
+	}
+	for field, value := range fields {
+		if value != "" {
+			if err := form.WriteField(field, value); err != nil {
+				return nil, fmt.Errorf("failed to build upload: %w", err)
+			}
+		}
+	}
+	if err := form.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build upload: %w", err)
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/species/"+url.PathEscape(name)+"/media",
+		&RawBody{Data: body.Bytes(), ContentType: form.FormDataContentType()})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var m SpeciesMedia
+	if err := c.parseResponse(resp, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
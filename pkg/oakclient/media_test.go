@@ -0,0 +1,71 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUploadSpeciesMedia(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/species/alba/media" {
+			t.Errorf("request = %s %s, want POST /api/v1/species/alba/media", r.Method, r.URL.Path)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("FormFile() error = %v", err)
+		}
+		content, _ := io.ReadAll(file)
+		if header.Filename != "alba.jpg" || string(content) != "jpeg data" || r.FormValue("license") != "CC BY 4.0" || r.Form.Has("caption") {
+			t.Errorf("upload = %s %q license %q", header.Filename, content, r.FormValue("license"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(SpeciesMedia{ID: 7, ScientificName: "alba", URL: "/api/v1/media/abc.jpg", Origin: MediaOriginUpload})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	m, err := c.UploadSpeciesMedia(t.Context(), "alba", MediaUpload{Filename: "alba.jpg", Content: strings.NewReader("jpeg data"), License: "CC BY 4.0"})
+	if err != nil {
+		t.Fatalf("UploadSpeciesMedia() error = %v", err)
+	}
+	if m.ID != 7 || m.Origin != MediaOriginUpload {
+		t.Errorf("media = %+v", m)
+	}
+}
+
+func TestSpeciesMediaListAndDelete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.RequestURI() {
+		case "GET /api/v1/species/alba/media?kind=bark":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(SpeciesMediaListResponse{Data: []*SpeciesMedia{{ID: 3, URL: "https://example.org/bark.jpg", Origin: MediaOriginLink}}})
+		case "DELETE /api/v1/species/alba/media/3":
+			w.WriteHeader(http.StatusNoContent)
+		case "DELETE /api/v1/species/alba/media/4":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"NOT_FOUND","message":"Media not found"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.RequestURI())
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	media, err := c.ListSpeciesMedia(t.Context(), "alba", "bark")
+	if err != nil || len(media) != 1 || media[0].ID != 3 {
+		t.Fatalf("ListSpeciesMedia() = %+v, %v", media, err)
+	}
+	if err := c.DeleteSpeciesMedia(t.Context(), "alba", 3); err != nil {
+		t.Errorf("DeleteSpeciesMedia() error = %v", err)
+	}
+	if err := c.DeleteSpeciesMedia(t.Context(), "alba", 4); !IsNotFoundError(err) {
+		t.Errorf("DeleteSpeciesMedia(missing) error = %v, want not found", err)
+	}
+}
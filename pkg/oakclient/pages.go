@@ -0,0 +1,31 @@
+package oakclient
+
+import "iter"
+
+// DefaultPageSize is the page size All* iterators request when their params
+// set no Limit.
+const DefaultPageSize = 100
+
+// paginate yields the items of successive pages, starting at offset, until a
+// page reports no more. A failed fetch is yielded as the final pair.
+func paginate[T any](offset int, fetch func(offset int) ([]T, Pagination, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			items, page, err := fetch(offset)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if !page.HasMore || len(items) == 0 {
+				return
+			}
+			offset += len(items)
+		}
+	}
+}
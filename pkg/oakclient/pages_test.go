@@ -0,0 +1,128 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// pagedSpeciesServer serves total species, honoring ?limit and ?offset.
+func pagedSpeciesServer(t *testing.T, total int, requests *[]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.RawQuery)
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var data []*OakEntry
+		for i := offset; i < total && i < offset+limit; i++ {
+			data = append(data, &OakEntry{ScientificName: "sp" + strconv.Itoa(i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SpeciesListResponse{
+			Data:       data,
+			Pagination: Pagination{Total: total, Limit: limit, Offset: offset, HasMore: offset+len(data) < total},
+		})
+	}))
+}
+
+func TestAllSpecies_PagesThroughEverything(t *testing.T) {
+	var requests []string
+	server := pagedSpeciesServer(t, 5, &requests)
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	var names []string
+	for sp, err := range c.AllSpecies(t.Context(), &SpeciesListParams{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("AllSpecies() error = %v", err)
+		}
+		names = append(names, sp.ScientificName)
+	}
+
+	if len(names) != 5 || names[0] != "sp0" || names[4] != "sp4" {
+		t.Errorf("names = %v, want sp0..sp4", names)
+	}
+	want := []string{"limit=2", "limit=2&offset=2", "limit=2&offset=4"}
+	if len(requests) != len(want) {
+		t.Fatalf("requests = %v, want %v", requests, want)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("request %d = %q, want %q", i, requests[i], want[i])
+		}
+	}
+}
+
+func TestAllSpecies_DefaultPageSizeAndStop(t *testing.T) {
+	var requests []string
+	server := pagedSpeciesServer(t, 2*DefaultPageSize+1, &requests)
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	count := 0
+	for _, err := range c.AllSpecies(t.Context(), nil) {
+		if err != nil {
+			t.Fatalf("AllSpecies() error = %v", err)
+		}
+		count++
+		if count == DefaultPageSize+1 {
+			break
+		}
+	}
+
+	if len(requests) != 2 {
+		t.Errorf("requests = %v, want 2 pages fetched before the break", requests)
+	}
+	if requests[0] != "limit="+strconv.Itoa(DefaultPageSize) {
+		t.Errorf("first request = %q, want the default page size", requests[0])
+	}
+}
+
+func TestAllSources_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": map[string]string{"code": "VALIDATION_ERROR", "message": "bad year"},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	var errs []error
+	for src, err := range c.AllSources(t.Context(), nil) {
+		if src != nil {
+			t.Errorf("unexpected source %v", src)
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrValidation) {
+		t.Errorf("errors = %v, want one ErrValidation", errs)
+	}
+}
+
+func TestAllTaxa_SinglePage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TaxaListResponse{
+			Data:       []*Taxon{{Name: "Quercus", Level: "subgenus"}, {Name: "Lobatae", Level: "section"}},
+			Pagination: Pagination{Total: 2, Limit: DefaultPageSize},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	count := 0
+	for _, err := range c.AllTaxa(t.Context(), nil) {
+		if err != nil {
+			t.Fatalf("AllTaxa() error = %v", err)
+		}
+		count++
+	}
+	if count != 2 {
+		t.Errorf("got %d taxa, want 2", count)
+	}
+}
@@ -0,0 +1,81 @@
+package oakclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ScheduledPublication is a set of draft species queued to go live together.
+type ScheduledPublication struct {
+	ID          int64      `json:"id"`
+	Species     []string   `json:"species"`
+	PublishAt   time.Time  `json:"publish_at"`
+	Note        *string    `json:"note,omitempty"`
+	Status      string     `json:"status"` // pending, published, or canceled
+	CreatedAt   time.Time  `json:"created_at"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+// SchedulePublication queues species to be published at the given time.
+func (c *Client) SchedulePublication(ctx context.Context, species []string, publishAt time.Time, note string) (*ScheduledPublication, error) {
+	body := map[string]interface{}{
+		"species":    species,
+		"publish_at": publishAt.UTC().Format(time.RFC3339),
+	}
+	if note != "" {
+		body["note"] = note
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/publications", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var pub ScheduledPublication
+	if err := c.parseResponse(resp, &pub); err != nil {
+		return nil, err
+	}
+
+	return &pub, nil
+}
+
+// ListScheduledPublications lists pending publications, or all of them with all set.
+func (c *Client) ListScheduledPublications(ctx context.Context, all bool) ([]*ScheduledPublication, error) {
+	path := "/api/v1/publications"
+	if all {
+		path += "?all=true"
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var pubs []*ScheduledPublication
+	if err := c.parseResponse(resp, &pubs); err != nil {
+		return nil, err
+	}
+
+	return pubs, nil
+}
+
+// CancelScheduledPublication cancels a pending publication.
+func (c *Client) CancelScheduledPublication(ctx context.Context, id int64) error {
+	path := fmt.Sprintf("/api/v1/publications/%d", id)
+
+	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
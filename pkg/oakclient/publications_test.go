@@ -0,0 +1,54 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSchedulePublication(t *testing.T) {
+	at := time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/publications" {
+			t.Errorf("request = %s %s, want POST /api/v1/publications", r.Method, r.URL.Path)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		if body["publish_at"] != "2026-11-01T09:00:00Z" || body["note"] != "blog" {
+			t.Errorf("body = %v", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(ScheduledPublication{ID: 4, Species: []string{"alba"}, PublishAt: at, Status: "pending"})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	pub, err := c.SchedulePublication(t.Context(), []string{"alba"}, at, "blog")
+	if err != nil {
+		t.Fatalf("SchedulePublication() error = %v", err)
+	}
+	if pub.ID != 4 || !pub.PublishAt.Equal(at) {
+		t.Errorf("pub = %+v", pub)
+	}
+}
+
+func TestCancelScheduledPublication(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/api/v1/publications/4" {
+			t.Errorf("request = %s %s, want DELETE /api/v1/publications/4", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	if err := c.CancelScheduledPublication(t.Context(), 4); err != nil {
+		t.Fatalf("CancelScheduledPublication() error = %v", err)
+	}
+}
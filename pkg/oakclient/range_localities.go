@@ -0,0 +1,155 @@
+package oakclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RangeLocality is a place named in a species source's range, with the
+// approximate coordinates a geocoder found for it.
+type RangeLocality struct {
+	ID             int64      `json:"id"`
+	ScientificName string     `json:"scientific_name"`
+	SourceID       int64      `json:"source_id"`
+	Locality       string     `json:"locality"`
+	Latitude       float64    `json:"latitude"`
+	Longitude      float64    `json:"longitude"`
+	Region         *string    `json:"region,omitempty"`
+	Provider       string     `json:"provider"`
+	MachineDerived bool       `json:"machine_derived"` // False once a curator corrects the coordinates
+	Status         string     `json:"status"`          // pending, accepted, or rejected
+	CreatedAt      time.Time  `json:"created_at"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	// Generalized is the grid cell size in degrees the coordinates were
+	// coarsened to; only set on reads of threatened species without an API key
+	Generalized float64 `json:"generalized,omitempty"`
+}
+
+// RangeLocalitiesListResponse is the paginated list wrapper for range localities.
+type RangeLocalitiesListResponse struct {
+	Data []*RangeLocality `json:"data"`
+}
+
+// SpeciesRangeGeoJSON retrieves a species' range map as a GeoJSON
+// FeatureCollection. detail is full (or empty), medium, or low; reviewed
+// leaves out pending, machine-derived localities.
+func (c *Client) SpeciesRangeGeoJSON(ctx context.Context, name, detail string, reviewed bool) ([]byte, error) {
+	query := url.Values{}
+	if detail != "" {
+		query.Set("detail", detail)
+	}
+	if reviewed {
+		query.Set("reviewed", "true")
+	}
+	path := "/api/v1/species/" + url.PathEscape(name) + "/range.geojson"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// GeocodeJob is the background job geocoding range localities.
+type GeocodeJob struct {
+	ID     int64  `json:"id"`
+	Kind   string `json:"kind"`
+	Status string `json:"status"`
+}
+
+// GeocodeRanges queues a job geocoding the range localities of the given
+// species, or every species. It fails with a validation error when the
+// server has no geocoder configured.
+func (c *Client) GeocodeRanges(ctx context.Context, species []string) (*GeocodeJob, error) {
+	body := map[string]interface{}{}
+	if len(species) > 0 {
+		body["species"] = species
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/range-localities/geocode", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var job GeocodeJob
+	if err := c.parseResponse(resp, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// ListRangeLocalities lists range localities with a status (pending,
+// accepted, rejected, or all; empty means pending), optionally for one species.
+func (c *Client) ListRangeLocalities(ctx context.Context, status, species string) ([]*RangeLocality, error) {
+	query := url.Values{}
+	if status != "" {
+		query.Set("status", status)
+	}
+	if species != "" {
+		query.Set("species", species)
+	}
+	path := "/api/v1/range-localities"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result RangeLocalitiesListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// AcceptRangeLocality accepts a pending range locality. Non-nil latitude and
+// longitude replace the geocoder's coordinates (region too, if not empty).
+func (c *Client) AcceptRangeLocality(ctx context.Context, id int64, latitude, longitude *float64, region string) (*RangeLocality, error) {
+	var body interface{}
+	if latitude != nil || longitude != nil {
+		body = map[string]interface{}{"latitude": latitude, "longitude": longitude, "region": region}
+	}
+	return c.reviewRangeLocality(ctx, id, "accept", body)
+}
+
+// RejectRangeLocality rejects a pending range locality.
+func (c *Client) RejectRangeLocality(ctx context.Context, id int64) (*RangeLocality, error) {
+	return c.reviewRangeLocality(ctx, id, "reject", nil)
+}
+
+func (c *Client) reviewRangeLocality(ctx context.Context, id int64, action string, body interface{}) (*RangeLocality, error) {
+	path := fmt.Sprintf("/api/v1/range-localities/%d/%s", id, action)
+
+	resp, err := c.doRequest(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var locality RangeLocality
+	if err := c.parseResponse(resp, &locality); err != nil {
+		return nil, err
+	}
+
+	return &locality, nil
+}
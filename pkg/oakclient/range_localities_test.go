@@ -0,0 +1,78 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGeocodeRanges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Species []string `json:"species"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/api/v1/range-localities/geocode" || len(body.Species) != 1 || body.Species[0] != "texana" {
+			t.Errorf("request = %s %+v", r.URL.Path, body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(GeocodeJob{ID: 7, Kind: "geocode-ranges", Status: "queued"})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	job, err := c.GeocodeRanges(t.Context(), []string{"texana"})
+	if err != nil {
+		t.Fatalf("GeocodeRanges() error = %v", err)
+	}
+	if job.ID != 7 || job.Status != "queued" {
+		t.Errorf("job = %+v", job)
+	}
+}
+
+func TestAcceptRangeLocality(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Latitude *float64 `json:"latitude"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/api/v1/range-localities/4/accept" || body.Latitude == nil || *body.Latitude != 30.4 {
+			t.Errorf("request = %s %+v", r.URL.Path, body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(RangeLocality{ID: 4, Status: "accepted", Latitude: 30.4})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	lat, lon := 30.4, -99.8
+	l, err := c.AcceptRangeLocality(t.Context(), 4, &lat, &lon, "")
+	if err != nil {
+		t.Fatalf("AcceptRangeLocality() error = %v", err)
+	}
+	if l.Status != "accepted" || l.Latitude != 30.4 {
+		t.Errorf("locality = %+v", l)
+	}
+}
+
+func TestSpeciesRangeGeoJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/species/texana/range.geojson" || r.URL.RawQuery != "detail=low&reviewed=true" {
+			t.Errorf("request = %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/geo+json")
+		w.Write([]byte(`{"type":"FeatureCollection","features":[]}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	data, err := c.SpeciesRangeGeoJSON(t.Context(), "texana", "low", true)
+	if err != nil {
+		t.Fatalf("SpeciesRangeGeoJSON() error = %v", err)
+	}
+	if string(data) != `{"type":"FeatureCollection","features":[]}` {
+		t.Errorf("data = %s", data)
+	}
+}
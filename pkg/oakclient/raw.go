@@ -0,0 +1,39 @@
+package oakclient
+
+import (
+	"context"
+	"io"
+)
+
+// Content types accepted by the API for request bodies.
+const (
+	ContentTypeJSON = "application/json"
+	ContentTypeYAML = "application/yaml"
+)
+
+// RawBody is a pre-encoded request body, sent without re-marshaling.
+type RawBody struct {
+	Data        []byte
+	ContentType string
+}
+
+// Raw sends a request with an optional pre-encoded body and returns the raw
+// response body. Non-2xx responses are returned as errors, as with other methods.
+func (c *Client) Raw(ctx context.Context, method, path string, body *RawBody) ([]byte, error) {
+	var reqBody interface{}
+	if body != nil {
+		reqBody = body
+	}
+
+	resp, err := c.doRequest(ctx, method, path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, c.parseError(resp)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,55 @@
+package oakclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRaw_SendsBodyWithContentType(t *testing.T) {
+	var gotType, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/species" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		gotType = r.Header.Get("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		gotBody = string(data)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"scientific_name":"alba"}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	data, err := c.Raw(t.Context(), http.MethodPost, "/api/v1/species", &RawBody{
+		Data:        []byte("scientific_name: alba\n"),
+		ContentType: ContentTypeYAML,
+	})
+	if err != nil {
+		t.Fatalf("Raw() error = %v", err)
+	}
+	if gotType != ContentTypeYAML {
+		t.Errorf("Content-Type = %q, want %q", gotType, ContentTypeYAML)
+	}
+	if gotBody != "scientific_name: alba\n" {
+		t.Errorf("body = %q", gotBody)
+	}
+	if string(data) != `{"scientific_name":"alba"}` {
+		t.Errorf("response = %s", data)
+	}
+}
+
+func TestRaw_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	_, err := c.Raw(t.Context(), http.MethodGet, "/api/v1/species/nope", nil)
+	if !IsNotFoundError(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
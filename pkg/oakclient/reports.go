@@ -0,0 +1,133 @@
+package oakclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Report formats accepted by RunReport
+const (
+	ReportFormatCSV  = "csv"
+	ReportFormatJSON = "json"
+)
+
+// ReportRequest is the request body for creating or replacing a report.
+type ReportRequest struct {
+	Title       string         `json:"title" yaml:"title"`
+	Description *string        `json:"description,omitempty" yaml:"description,omitempty"`
+	Query       string         `json:"query" yaml:"query"`
+	Columns     []ReportColumn `json:"columns,omitempty" yaml:"columns,omitempty"`
+	Format      string         `json:"format,omitempty" yaml:"format,omitempty"`
+}
+
+// ReportRun is a report's rows, as returned by RunReport in ReportFormatJSON.
+type ReportRun struct {
+	Report     string          `json:"report"`
+	Columns    []string        `json:"columns"`
+	Rows       [][]interface{} `json:"rows"`
+	Truncated  bool            `json:"truncated"`
+	DurationMs float64         `json:"duration_ms"`
+}
+
+// ReportsListResponse contains the list of reports.
+type ReportsListResponse struct {
+	Data       []*Report  `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// ListReports retrieves every saved report. Requires an API key.
+func (c *Client) ListReports(ctx context.Context) ([]*Report, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/reports", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ReportsListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// GetReport retrieves a report's definition by name. Requires an API key.
+func (c *Client) GetReport(ctx context.Context, name string) (*Report, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/reports/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var report Report
+	if err := c.parseResponse(resp, &report); err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// SaveReport creates or replaces a report. The server runs the query once
+// and refuses it with a validation error if it would write, does not parse,
+// or lacks a listed column.
+func (c *Client) SaveReport(ctx context.Context, name string, req *ReportRequest) (*Report, error) {
+	resp, err := c.doRequest(ctx, http.MethodPut, "/api/v1/reports/"+url.PathEscape(name), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var report Report
+	if err := c.parseResponse(resp, &report); err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// DeleteReport deletes a report.
+func (c *Client) DeleteReport(ctx context.Context, name string) error {
+	resp, err := c.doRequest(ctx, http.MethodDelete, "/api/v1/reports/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
+
+// RunReport runs a saved report and returns its output: CSV, or a ReportRun
+// as JSON. An empty format uses the report's own; a zero limit the server's
+// (10000 rows).
+func (c *Client) RunReport(ctx context.Context, name, format string, limit int) ([]byte, error) {
+	query := url.Values{}
+	if format != "" {
+		query.Set("format", format)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	path := "/api/v1/reports/" + url.PathEscape(name) + "/run"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	return io.ReadAll(resp.Body)
+}
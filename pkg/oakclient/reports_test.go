@@ -0,0 +1,67 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSaveReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/reports/species-by-section" {
+			t.Errorf("request = %s %s, want PUT /api/v1/reports/species-by-section", r.Method, r.URL.Path)
+		}
+		var req ReportRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Query != "SELECT section FROM oak_entries" {
+			t.Errorf("body = %+v (err %v)", req, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(Report{Name: "species-by-section", Title: req.Title, Query: req.Query, Format: "csv"})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	report, err := c.SaveReport(t.Context(), "species-by-section", &ReportRequest{Title: "By section", Query: "SELECT section FROM oak_entries"})
+	if err != nil {
+		t.Fatalf("SaveReport() error = %v", err)
+	}
+	if report.Name != "species-by-section" || report.Format != "csv" {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestRunReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/reports/names/run" || r.URL.Query().Get("format") != "csv" || r.URL.Query().Get("limit") != "5" {
+			t.Errorf("request = %s, want /api/v1/reports/names/run?format=csv&limit=5", r.URL)
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Write([]byte("Species\nalba\n"))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	data, err := c.RunReport(t.Context(), "names", ReportFormatCSV, 5)
+	if err != nil {
+		t.Fatalf("RunReport() error = %v", err)
+	}
+	if string(data) != "Species\nalba\n" {
+		t.Errorf("data = %q", data)
+	}
+}
+
+func TestRunReport_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":"NOT_FOUND","message":"Report not found: nope"}}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	if _, err := c.RunReport(t.Context(), "nope", "", 0); !IsNotFoundError(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
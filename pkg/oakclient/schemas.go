@@ -0,0 +1,109 @@
+package oakclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// SchemaInfo describes one JSON Schema published by the API.
+type SchemaInfo struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// SchemasListResponse contains the list of published schemas.
+type SchemasListResponse struct {
+	Data       []*SchemaInfo `json:"data"`
+	Pagination Pagination    `json:"pagination"`
+}
+
+// ListSchemas retrieves the JSON Schemas the API publishes.
+func (c *Client) ListSchemas(ctx context.Context) ([]*SchemaInfo, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/schemas", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result SchemasListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// GetSchema retrieves one JSON Schema document by name (e.g. "oak-entry").
+func (c *Client) GetSchema(ctx context.Context, name string) (json.RawMessage, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/schemas/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var schema json.RawMessage
+	if err := c.parseResponse(resp, &schema); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// DataDictionary describes the published dataset's entities, fields, and
+// controlled vocabularies.
+type DataDictionary struct {
+	Title        string             `json:"title"`
+	Description  string             `json:"description"`
+	Entities     []DictionaryEntity `json:"entities"`
+	Vocabularies []Vocabulary       `json:"vocabularies"`
+}
+
+// DictionaryEntity is one kind of record and its fields.
+type DictionaryEntity struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Fields      []DictionaryField `json:"fields"`
+}
+
+// DictionaryField describes one field. Nested fields are dotted
+// (taxonomy.genus) and fields of list items follow the list name and []
+// (external_links[].url).
+type DictionaryField struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required"`
+	Description string `json:"description"`
+	Vocabulary  string `json:"vocabulary,omitempty"`
+}
+
+// Vocabulary is a controlled list of values.
+type Vocabulary struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Values      []VocabularyValue `json:"values"`
+}
+
+// VocabularyValue is one permitted value and what it means.
+type VocabularyValue struct {
+	Value   string `json:"value"`
+	Meaning string `json:"meaning,omitempty"`
+}
+
+// GetDataDictionary retrieves the data dictionary of the published dataset.
+func (c *Client) GetDataDictionary(ctx context.Context) (*DataDictionary, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/schema/data-dictionary", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var dict DataDictionary
+	if err := c.parseResponse(resp, &dict); err != nil {
+		return nil, err
+	}
+
+	return &dict, nil
+}
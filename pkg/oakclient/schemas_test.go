@@ -0,0 +1,74 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetSchema(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/schemas/taxon" {
+			t.Errorf("request = %s %s, want GET /api/v1/schemas/taxon", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"$schema":"http://json-schema.org/draft-07/schema#","title":"Taxon","type":"object"}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	raw, err := c.GetSchema(t.Context(), "taxon")
+	if err != nil {
+		t.Fatalf("GetSchema() error = %v", err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("schema is not JSON: %v", err)
+	}
+	if schema["title"] != "Taxon" {
+		t.Errorf("title = %v, want Taxon", schema["title"])
+	}
+}
+
+func TestListSchemas(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SchemasListResponse{
+			Data: []*SchemaInfo{{Name: "oak-entry", Title: "Oak Entry", URL: "/api/v1/schemas/oak-entry"}},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	schemas, err := c.ListSchemas(t.Context())
+	if err != nil {
+		t.Fatalf("ListSchemas() error = %v", err)
+	}
+	if len(schemas) != 1 || schemas[0].Name != "oak-entry" {
+		t.Errorf("schemas = %+v", schemas)
+	}
+}
+
+func TestGetDataDictionary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/schema/data-dictionary" {
+			t.Errorf("path = %s, want /api/v1/schema/data-dictionary", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(DataDictionary{
+			Title:    "Oak Compendium data dictionary",
+			Entities: []DictionaryEntity{{Name: "species", Fields: []DictionaryField{{Name: "name", Type: "string", Required: true}}}},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	dict, err := c.GetDataDictionary(t.Context())
+	if err != nil {
+		t.Fatalf("GetDataDictionary() error = %v", err)
+	}
+	if len(dict.Entities) != 1 || dict.Entities[0].Fields[0].Name != "name" {
+		t.Errorf("dictionary = %+v", dict)
+	}
+}
@@ -0,0 +1,96 @@
+package oakclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// TextMatch is a line of species account or source text containing a text
+// search's query.
+type TextMatch struct {
+	ScientificName string     `json:"scientific_name"`
+	Location       string     `json:"location"` // "account" or "sources/{source_id}/{field}"
+	Line           int        `json:"line"`     // 1-based line within the field
+	Text           string     `json:"text"`     // The line, shortened around the first match when long
+	Matches        []TextSpan `json:"matches"`
+}
+
+// TextSpan is a byte range [Start, End) of a TextMatch's Text.
+type TextSpan struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// TextSearchResponse is the paginated response from a text search.
+type TextSearchResponse struct {
+	Data       []*TextMatch `json:"data"`
+	Pagination Pagination   `json:"pagination"`
+}
+
+// SearchText finds the lines of species accounts and source text that
+// contain query, ignoring case, diacritics, and spacing. Pagination.Total
+// counts every match, even past limit (0 for the server default of 50).
+func (c *Client) SearchText(ctx context.Context, query string, limit int) (*TextSearchResponse, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/search/text?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result TextSearchResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// FullTextMatch is a species source text field or an entry's synonyms
+// matching a full-text search.
+type FullTextMatch struct {
+	ScientificName string     `json:"scientific_name"`
+	Location       string     `json:"location"` // "synonyms" or "sources/{source_id}/{field}"
+	Field          string     `json:"field"`
+	Text           string     `json:"text"` // A snippet of the field around the matched words
+	Matches        []TextSpan `json:"matches"`
+	Score          float64    `json:"score"` // BM25 relevance; higher is better
+}
+
+// FullTextSearchResponse is the paginated response from a full-text search.
+type FullTextSearchResponse struct {
+	Data       []*FullTextMatch `json:"data"`
+	Pagination Pagination       `json:"pagination"`
+}
+
+// SearchFullText ranks species source text fields and synonyms by relevance
+// to query, most relevant first. Every word must appear; "quoted phrases"
+// must appear as written and word* matches by prefix. Pagination.Total counts
+// every match, even past limit (0 for the server default of 50).
+func (c *Client) SearchFullText(ctx context.Context, query string, limit int) (*FullTextSearchResponse, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/search/fulltext?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result FullTextSearchResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,67 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearchText(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/search/text" || r.URL.Query().Get("q") != "stellate hairs" || r.URL.Query().Get("limit") != "200" {
+			t.Errorf("request = %s, want /api/v1/search/text?q=stellate+hairs&limit=200", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TextSearchResponse{
+			Data: []*TextMatch{{
+				ScientificName: "stellata",
+				Location:       "sources/1/leaves",
+				Line:           1,
+				Text:           "With stellate hairs.",
+				Matches:        []TextSpan{{Start: 5, End: 19}},
+			}},
+			Pagination: Pagination{Total: 1, Limit: 200},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	result, err := c.SearchText(t.Context(), "stellate hairs", 200)
+	if err != nil {
+		t.Fatalf("SearchText() error = %v", err)
+	}
+	if len(result.Data) != 1 || result.Data[0].Matches[0].End != 19 || result.Pagination.Total != 1 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestSearchFullText(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/search/fulltext" || r.URL.Query().Get("q") != `"stellate hairs" bark` || r.URL.Query().Get("limit") != "20" {
+			t.Errorf("request = %s, want /api/v1/search/fulltext with q and limit=20", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(FullTextSearchResponse{
+			Data: []*FullTextMatch{{
+				ScientificName: "stellata",
+				Location:       "sources/1/bark",
+				Field:          "bark",
+				Text:           "Bark with stellate hairs.",
+				Matches:        []TextSpan{{Start: 0, End: 4}, {Start: 10, End: 24}},
+				Score:          1.25,
+			}},
+			Pagination: Pagination{Total: 1, Limit: 20},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	result, err := c.SearchFullText(t.Context(), `"stellate hairs" bark`, 20)
+	if err != nil {
+		t.Fatalf("SearchFullText() error = %v", err)
+	}
+	if len(result.Data) != 1 || result.Data[0].Score != 1.25 || len(result.Data[0].Matches) != 2 {
+		t.Errorf("result = %+v", result)
+	}
+}
@@ -0,0 +1,29 @@
+package oakclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Sitemap retrieves the public site's sitemap.xml. A non-empty baseURL
+// replaces the server's configured site URL in the page links.
+func (c *Client) Sitemap(ctx context.Context, baseURL string) ([]byte, error) {
+	path := "/sitemap.xml"
+	if baseURL != "" {
+		path += "?base_url=" + url.QueryEscape(baseURL)
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,30 @@
+package oakclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSitemap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sitemap.xml" {
+			t.Errorf("path = %s, want /sitemap.xml", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("base_url"); got != "https://staging.example.org" {
+			t.Errorf("base_url = %q", got)
+		}
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.Write([]byte("<urlset></urlset>\n"))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	data, err := c.Sitemap(t.Context(), "https://staging.example.org")
+	if err != nil {
+		t.Fatalf("Sitemap() error = %v", err)
+	}
+	if string(data) != "<urlset></urlset>\n" {
+		t.Errorf("data = %q", data)
+	}
+}
@@ -0,0 +1,333 @@
+package oakclient
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// SourceRequest represents the request body for creating/updating a source.
+type SourceRequest struct {
+	SourceType   string  `json:"source_type"`
+	Name         string  `json:"name"`
+	Description  *string `json:"description,omitempty"`
+	Author       *string `json:"author,omitempty"`
+	Year         *int    `json:"year,omitempty"`
+	URL          *string `json:"url,omitempty"`
+	ISBN         *string `json:"isbn,omitempty"`
+	DOI          *string `json:"doi,omitempty"`
+	Notes        *string `json:"notes,omitempty"`
+	License      *string `json:"license,omitempty"`
+	LicenseURL   *string `json:"license_url,omitempty"`
+	SupersededBy *int64  `json:"superseded_by,omitempty"`
+}
+
+// SourceListParams contains parameters for listing sources.
+type SourceListParams struct {
+	SourceType *string
+	Year       *int
+	Limit      int // 0 returns every matching source
+	Offset     int
+}
+
+// SourcesListResponse contains the list of sources.
+type SourcesListResponse struct {
+	Data       []*Source  `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// ListSources retrieves sources, optionally filtered and paged.
+func (c *Client) ListSources(ctx context.Context, params *SourceListParams) (*SourcesListResponse, error) {
+	path := "/api/v1/sources"
+	if params != nil {
+		query := url.Values{}
+		if params.SourceType != nil {
+			query.Set("source_type", *params.SourceType)
+		}
+		if params.Year != nil {
+			query.Set("year", strconv.Itoa(*params.Year))
+		}
+		if params.Limit > 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset > 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result SourcesListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// AllSources iterates over every source matching params, fetching DefaultPageSize
+// at a time unless params sets a Limit; params.Offset is where it starts.
+// A failed fetch ends the iteration with its error.
+func (c *Client) AllSources(ctx context.Context, params *SourceListParams) iter.Seq2[*Source, error] {
+	var p SourceListParams
+	if params != nil {
+		p = *params
+	}
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageSize
+	}
+	return paginate(p.Offset, func(offset int) ([]*Source, Pagination, error) {
+		p.Offset = offset
+		resp, err := c.ListSources(ctx, &p)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		return resp.Data, resp.Pagination, nil
+	})
+}
+
+// GetSource retrieves a single source by ID.
+func (c *Client) GetSource(ctx context.Context, id int64) (*Source, error) {
+	source, _, err := c.GetSourceWithETag(ctx, id)
+	return source, err
+}
+
+// GetSourceWithETag retrieves a single source by ID, along with the version
+// to pass to UpdateSourceIfMatch.
+func (c *Client) GetSourceWithETag(ctx context.Context, id int64) (*Source, string, error) {
+	var source Source
+	etag, err := c.getWithETag(ctx, fmt.Sprintf("/api/v1/sources/%d", id), &source)
+	if err != nil {
+		return nil, "", err
+	}
+	return &source, etag, nil
+}
+
+// CreateSource creates a new source.
+func (c *Client) CreateSource(ctx context.Context, req *SourceRequest) (*Source, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/sources", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var source Source
+	if err := c.parseResponse(resp, &source); err != nil {
+		return nil, err
+	}
+
+	return &source, nil
+}
+
+// UpdateSource updates an existing source.
+func (c *Client) UpdateSource(ctx context.Context, id int64, req *SourceRequest) (*Source, error) {
+	return c.UpdateSourceIfMatch(ctx, id, req, "")
+}
+
+// UpdateSourceIfMatch updates an existing source only if it is still the
+// version etag names, as returned by GetSourceWithETag.
+func (c *Client) UpdateSourceIfMatch(ctx context.Context, id int64, req *SourceRequest, etag string) (*Source, error) {
+	var source Source
+	if err := c.putIfMatch(ctx, fmt.Sprintf("/api/v1/sources/%d", id), req, etag, &source); err != nil {
+		return nil, err
+	}
+	return &source, nil
+}
+
+// DeleteSource deletes a source by ID. The API refuses to delete a source
+// that is still in use unless force is set, in which case everything that
+// refers to it is removed too.
+func (c *Client) DeleteSource(ctx context.Context, id int64, force bool) error {
+	path := fmt.Sprintf("/api/v1/sources/%d", id)
+	if force {
+		path += "?force=true"
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
+
+// SourceUsage lists the species, sources, and templates that refer to a source.
+type SourceUsage struct {
+	SourceID          int64    `json:"source_id"`
+	Species           []string `json:"species"`
+	SupersededSources []int64  `json:"superseded_sources"`
+	Templates         []string `json:"templates"`
+}
+
+// InUse reports whether anything refers to the source.
+func (u *SourceUsage) InUse() bool {
+	return len(u.Species) > 0 || len(u.SupersededSources) > 0 || len(u.Templates) > 0
+}
+
+// GetSourceUsage retrieves what would be affected by deleting a source.
+func (c *Client) GetSourceUsage(ctx context.Context, id int64) (*SourceUsage, error) {
+	path := fmt.Sprintf("/api/v1/sources/%d/usage", id)
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var usage SourceUsage
+	if err := c.parseResponse(resp, &usage); err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
+// FieldCoverage is the number of a source's species records that populate a field.
+type FieldCoverage struct {
+	Field string `json:"field"`
+	Count int    `json:"count"`
+}
+
+// SourceCoverage reports how completely a source describes the species in its scope
+// (the sections it describes at least one species in).
+type SourceCoverage struct {
+	SourceID       int64           `json:"source_id"`
+	SpeciesCount   int             `json:"species_count"`
+	Fields         []FieldCoverage `json:"fields"`
+	ScopeSections  []string        `json:"scope_sections"`
+	ScopeCount     int             `json:"scope_count"`
+	MissingSpecies []string        `json:"missing_species"`
+}
+
+// GetSourceCoverage retrieves the coverage report for a source.
+func (c *Client) GetSourceCoverage(ctx context.Context, id int64) (*SourceCoverage, error) {
+	path := fmt.Sprintf("/api/v1/sources/%d/coverage", id)
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var coverage SourceCoverage
+	if err := c.parseResponse(resp, &coverage); err != nil {
+		return nil, err
+	}
+
+	return &coverage, nil
+}
+
+// BulkSpeciesSource is one species' data in a bulk species-source upsert.
+// ContentHash is optional; rows whose hash matches the stored one are left unchanged.
+type BulkSpeciesSource struct {
+	SpeciesSource
+	ContentHash string `json:"content_hash,omitempty"`
+}
+
+// BulkSpeciesSourceResult is the outcome for one row of a bulk upsert.
+type BulkSpeciesSourceResult struct {
+	ScientificName string `json:"scientific_name"`
+	Status         string `json:"status"` // created, updated, or unchanged
+}
+
+// BulkSpeciesSourcesResponse summarizes a bulk species-source upsert.
+type BulkSpeciesSourcesResponse struct {
+	SourceID  int64                     `json:"source_id"`
+	Created   int                       `json:"created"`
+	Updated   int                       `json:"updated"`
+	Unchanged int                       `json:"unchanged"`
+	Results   []BulkSpeciesSourceResult `json:"results"`
+}
+
+// BulkUpsertSpeciesSources creates or replaces many species' data for one source
+// in a single transaction. If any row is invalid, nothing is written and a
+// validation error lists the failed rows.
+func (c *Client) BulkUpsertSpeciesSources(ctx context.Context, sourceID int64, items []*BulkSpeciesSource) (*BulkSpeciesSourcesResponse, error) {
+	path := fmt.Sprintf("/api/v1/sources/%d/species-sources", sourceID)
+
+	resp, err := c.doRequest(ctx, http.MethodPut, path, items)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result BulkSpeciesSourcesResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SourceMigrationItem is the planned or applied action for one species in a
+// source migration: copy, move, or skip (the target already has data).
+type SourceMigrationItem struct {
+	ScientificName string `json:"scientific_name"`
+	Action         string `json:"action"`
+}
+
+// MigrateSourceResponse reports a source migration.
+type MigrateSourceResponse struct {
+	SourceID int64                 `json:"source_id"`
+	TargetID int64                 `json:"target_id"`
+	Move     bool                  `json:"move"`
+	DryRun   bool                  `json:"dry_run"`
+	Items    []SourceMigrationItem `json:"items"`
+}
+
+// MigrateSource copies (or, with move, moves) a source's species data to the
+// target source. With dryRun the server returns the plan without writing.
+func (c *Client) MigrateSource(ctx context.Context, id, targetID int64, move, dryRun bool) (*MigrateSourceResponse, error) {
+	path := fmt.Sprintf("/api/v1/sources/%d/migrate", id)
+	body := map[string]interface{}{
+		"target_id": targetID,
+		"move":      move,
+		"dry_run":   dryRun,
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result MigrateSourceResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SourceToRequest converts a Source to a SourceRequest.
+func SourceToRequest(source *Source) *SourceRequest {
+	return &SourceRequest{
+		SourceType:   source.SourceType,
+		Name:         source.Name,
+		Description:  source.Description,
+		Author:       source.Author,
+		Year:         source.Year,
+		URL:          source.URL,
+		ISBN:         source.ISBN,
+		DOI:          source.DOI,
+		Notes:        source.Notes,
+		License:      source.License,
+		LicenseURL:   source.LicenseURL,
+		SupersededBy: source.SupersededBy,
+	}
+}
@@ -0,0 +1,474 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListSources_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("method = %s, want GET", r.Method)
+		}
+		if r.URL.Path != "/api/v1/sources" {
+			t.Errorf("path = %s, want /api/v1/sources", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SourcesListResponse{
+			Data: []*Source{
+				{ID: 1, Name: "iNaturalist", SourceType: "website"},
+				{ID: 2, Name: "Oaks of the World", SourceType: "website"},
+			},
+			Pagination: Pagination{Total: 2, Limit: 2},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	resp, err := c.ListSources(t.Context(), nil)
+	if err != nil {
+		t.Fatalf("ListSources() error = %v", err)
+	}
+
+	sources := resp.Data
+	if len(sources) != 2 {
+		t.Errorf("got %d sources, want 2", len(sources))
+	}
+	if sources[0].Name != "iNaturalist" {
+		t.Errorf("sources[0].Name = %s, want iNaturalist", sources[0].Name)
+	}
+}
+
+func TestListSources_WithParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.RawQuery, "limit=10&offset=20&source_type=book&year=2004"; got != want {
+			t.Errorf("query = %s, want %s", got, want)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SourcesListResponse{
+			Data:       []*Source{{ID: 3, Name: "Oaks of Asia", SourceType: "book"}},
+			Pagination: Pagination{Total: 31, Limit: 10, Offset: 20, HasMore: true},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	sourceType, year := "book", 2004
+	resp, err := c.ListSources(t.Context(), &SourceListParams{SourceType: &sourceType, Year: &year, Limit: 10, Offset: 20})
+	if err != nil {
+		t.Fatalf("ListSources() error = %v", err)
+	}
+
+	if len(resp.Data) != 1 || resp.Pagination.Total != 31 || !resp.Pagination.HasMore {
+		t.Errorf("got %d sources, pagination %+v, want 1 of 31 with more", len(resp.Data), resp.Pagination)
+	}
+}
+
+func TestGetSource_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("method = %s, want GET", r.Method)
+		}
+		if r.URL.Path != "/api/v1/sources/1" {
+			t.Errorf("path = %s, want /api/v1/sources/1", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		desc := "A community-driven biodiversity database"
+		json.NewEncoder(w).Encode(Source{
+			ID:          1,
+			Name:        "iNaturalist",
+			SourceType:  "website",
+			Description: &desc,
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	source, err := c.GetSource(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("GetSource() error = %v", err)
+	}
+
+	if source.ID != 1 {
+		t.Errorf("ID = %d, want 1", source.ID)
+	}
+	if source.Name != "iNaturalist" {
+		t.Errorf("Name = %s, want iNaturalist", source.Name)
+	}
+}
+
+func TestGetSource_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	_, err := c.GetSource(t.Context(), 999)
+	if err == nil {
+		t.Fatal("expected error for not found source")
+	}
+	if !IsNotFoundError(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestCreateSource_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if r.URL.Path != "/api/v1/sources" {
+			t.Errorf("path = %s, want /api/v1/sources", r.URL.Path)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var req SourceRequest
+		json.Unmarshal(body, &req)
+
+		if req.Name != "New Source" {
+			t.Errorf("Name = %s, want 'New Source'", req.Name)
+		}
+		if req.SourceType != "book" {
+			t.Errorf("SourceType = %s, want book", req.SourceType)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(Source{
+			ID:         3,
+			Name:       req.Name,
+			SourceType: req.SourceType,
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	source, err := c.CreateSource(t.Context(), &SourceRequest{
+		Name:       "New Source",
+		SourceType: "book",
+	})
+	if err != nil {
+		t.Fatalf("CreateSource() error = %v", err)
+	}
+
+	if source.ID != 3 {
+		t.Errorf("ID = %d, want 3", source.ID)
+	}
+	if source.Name != "New Source" {
+		t.Errorf("Name = %s, want 'New Source'", source.Name)
+	}
+}
+
+func TestUpdateSource_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("method = %s, want PUT", r.Method)
+		}
+		if r.URL.Path != "/api/v1/sources/1" {
+			t.Errorf("path = %s, want /api/v1/sources/1", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		notes := "Updated notes"
+		json.NewEncoder(w).Encode(Source{
+			ID:         1,
+			Name:       "iNaturalist",
+			SourceType: "website",
+			Notes:      &notes,
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	notes := "Updated notes"
+	source, err := c.UpdateSource(t.Context(), 1, &SourceRequest{
+		Name:       "iNaturalist",
+		SourceType: "website",
+		Notes:      &notes,
+	})
+	if err != nil {
+		t.Fatalf("UpdateSource() error = %v", err)
+	}
+
+	if source.Notes == nil || *source.Notes != "Updated notes" {
+		t.Errorf("Notes = %v, want 'Updated notes'", source.Notes)
+	}
+}
+
+func TestDeleteSource_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("method = %s, want DELETE", r.Method)
+		}
+		if r.URL.Path != "/api/v1/sources/1" {
+			t.Errorf("path = %s, want /api/v1/sources/1", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	err := c.DeleteSource(t.Context(), 1, false)
+	if err != nil {
+		t.Fatalf("DeleteSource() error = %v", err)
+	}
+}
+
+func TestDeleteSource_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	err := c.DeleteSource(t.Context(), 999, false)
+	if err == nil {
+		t.Fatal("expected error for not found source")
+	}
+	if !IsNotFoundError(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestDeleteSource_Force(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("force"); got != "true" {
+			t.Errorf("force = %q, want true", got)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	if err := c.DeleteSource(t.Context(), 1, true); err != nil {
+		t.Fatalf("DeleteSource() error = %v", err)
+	}
+}
+
+func TestDeleteSource_InUse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"error":{"code":"CONFLICT","message":"Cannot delete: source 1 is used by 2 species, 0 superseded sources, and 0 templates"}}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	err := c.DeleteSource(t.Context(), 1, false)
+	if !IsConflictError(err) {
+		t.Errorf("expected conflict error, got %v", err)
+	}
+}
+
+func TestGetSourceUsage_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/sources/1/usage" {
+			t.Errorf("path = %s, want /api/v1/sources/1/usage", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SourceUsage{
+			SourceID:          1,
+			Species:           []string{"alba", "rubra"},
+			SupersededSources: []int64{},
+			Templates:         []string{"field-guide"},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	usage, err := c.GetSourceUsage(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("GetSourceUsage() error = %v", err)
+	}
+	if !usage.InUse() {
+		t.Error("InUse() = false, want true")
+	}
+	if len(usage.Species) != 2 || usage.Templates[0] != "field-guide" {
+		t.Errorf("usage = %+v", usage)
+	}
+}
+
+func TestGetSourceCoverage_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/sources/2/coverage" {
+			t.Errorf("path = %s, want /api/v1/sources/2/coverage", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SourceCoverage{
+			SourceID:       2,
+			SpeciesCount:   1,
+			Fields:         []FieldCoverage{{Field: "leaves", Count: 1}},
+			ScopeSections:  []string{"Quercus"},
+			ScopeCount:     2,
+			MissingSpecies: []string{"macrocarpa"},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	cov, err := c.GetSourceCoverage(t.Context(), 2)
+	if err != nil {
+		t.Fatalf("GetSourceCoverage() error = %v", err)
+	}
+	if cov.SpeciesCount != 1 || len(cov.MissingSpecies) != 1 || cov.Fields[0].Field != "leaves" {
+		t.Errorf("coverage = %+v", cov)
+	}
+}
+
+func TestBulkUpsertSpeciesSources_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("method = %s, want PUT", r.Method)
+		}
+		if r.URL.Path != "/api/v1/sources/3/species-sources" {
+			t.Errorf("path = %s, want /api/v1/sources/3/species-sources", r.URL.Path)
+		}
+
+		var items []map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		if len(items) != 1 || items[0]["scientific_name"] != "alba" || items[0]["content_hash"] != "h1" {
+			t.Errorf("items = %v", items)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(BulkSpeciesSourcesResponse{
+			SourceID: 3,
+			Created:  1,
+			Results:  []BulkSpeciesSourceResult{{ScientificName: "alba", Status: "created"}},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	resp, err := c.BulkUpsertSpeciesSources(t.Context(), 3, []*BulkSpeciesSource{
+		{SpeciesSource: SpeciesSource{ScientificName: "alba"}, ContentHash: "h1"},
+	})
+	if err != nil {
+		t.Fatalf("BulkUpsertSpeciesSources() error = %v", err)
+	}
+	if resp.Created != 1 || len(resp.Results) != 1 {
+		t.Errorf("Created = %d, Results = %d, want 1, 1", resp.Created, len(resp.Results))
+	}
+}
+
+func TestMigrateSource_DryRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if r.URL.Path != "/api/v1/sources/2/migrate" {
+			t.Errorf("path = %s, want /api/v1/sources/2/migrate", r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+		if body["target_id"] != float64(5) || body["move"] != true || body["dry_run"] != true {
+			t.Errorf("body = %v", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(MigrateSourceResponse{
+			SourceID: 2,
+			TargetID: 5,
+			Move:     true,
+			DryRun:   true,
+			Items:    []SourceMigrationItem{{ScientificName: "alba", Action: "move"}},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	resp, err := c.MigrateSource(t.Context(), 2, 5, true, true)
+	if err != nil {
+		t.Fatalf("MigrateSource() error = %v", err)
+	}
+	if !resp.DryRun || len(resp.Items) != 1 || resp.Items[0].Action != "move" {
+		t.Errorf("resp = %+v", resp)
+	}
+}
+
+func TestSourceToRequest(t *testing.T) {
+	desc := "A biodiversity database"
+	author := "California Academy of Sciences"
+	year := 2008
+	url := "https://www.inaturalist.org"
+	notes := "Primary source for taxonomy"
+	license := "CC-BY-NC"
+	licenseURL := "https://creativecommons.org/licenses/by-nc/4.0/"
+
+	source := &Source{
+		ID:          1,
+		SourceType:  "website",
+		Name:        "iNaturalist",
+		Description: &desc,
+		Author:      &author,
+		Year:        &year,
+		URL:         &url,
+		Notes:       &notes,
+		License:     &license,
+		LicenseURL:  &licenseURL,
+	}
+
+	req := SourceToRequest(source)
+
+	if req.SourceType != "website" {
+		t.Errorf("SourceType = %s, want website", req.SourceType)
+	}
+	if req.Name != "iNaturalist" {
+		t.Errorf("Name = %s, want iNaturalist", req.Name)
+	}
+	if *req.Description != desc {
+		t.Errorf("Description = %s, want %s", *req.Description, desc)
+	}
+	if *req.Author != author {
+		t.Errorf("Author = %s, want %s", *req.Author, author)
+	}
+	if *req.Year != year {
+		t.Errorf("Year = %d, want %d", *req.Year, year)
+	}
+	if *req.URL != url {
+		t.Errorf("URL = %s, want %s", *req.URL, url)
+	}
+	if *req.Notes != notes {
+		t.Errorf("Notes = %s, want %s", *req.Notes, notes)
+	}
+	if *req.License != license {
+		t.Errorf("License = %s, want %s", *req.License, license)
+	}
+	if *req.LicenseURL != licenseURL {
+		t.Errorf("LicenseURL = %s, want %s", *req.LicenseURL, licenseURL)
+	}
+}
+
+func TestSourceToRequest_NilFields(t *testing.T) {
+	source := &Source{
+		ID:         1,
+		SourceType: "personal",
+		Name:       "Field Notes",
+	}
+
+	req := SourceToRequest(source)
+
+	if req.Description != nil {
+		t.Error("Description should be nil")
+	}
+	if req.Author != nil {
+		t.Error("Author should be nil")
+	}
+	if req.Year != nil {
+		t.Error("Year should be nil")
+	}
+	if req.URL != nil {
+		t.Error("URL should be nil")
+	}
+}
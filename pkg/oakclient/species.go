@@ -0,0 +1,583 @@
+package oakclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SpeciesListParams contains parameters for listing species.
+type SpeciesListParams struct {
+	Limit    int
+	Offset   int
+	Genus    *string
+	Subgenus *string
+	Section  *string
+	Hybrid   *bool
+
+	// SourceID limits results to species with data from this source.
+	SourceID *int64
+	// HasSource limits results to species with (true) or without (false)
+	// source data; with SourceID set it applies to that source only.
+	HasSource *bool
+
+	// ConservationStatus limits results to species with these IUCN codes.
+	ConservationStatus []string
+}
+
+// SpeciesListResponse contains the paginated list of species.
+type SpeciesListResponse struct {
+	Data       []*OakEntry `json:"data"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+// Pagination contains pagination metadata.
+type Pagination struct {
+	Total   int  `json:"total"`
+	Limit   int  `json:"limit"`
+	Offset  int  `json:"offset"`
+	HasMore bool `json:"hasMore"`
+}
+
+// SpeciesSearchResponse contains search results.
+type SpeciesSearchResponse struct {
+	Data  []*OakEntry `json:"data"`
+	Query string      `json:"query"`
+	Count int         `json:"count"`
+}
+
+// SpeciesRequest represents the request body for creating/updating a species.
+type SpeciesRequest struct {
+	ScientificName     string   `json:"scientific_name"`
+	Author             *string  `json:"author,omitempty"`
+	Pronunciation      *string  `json:"pronunciation,omitempty"`
+	IsHybrid           bool     `json:"is_hybrid"`
+	ConservationStatus *string  `json:"conservation_status,omitempty"`
+	Genus              string   `json:"genus,omitempty"` // Quercus on create; empty keeps the current value
+	Subgenus           *string  `json:"subgenus,omitempty"`
+	Section            *string  `json:"section,omitempty"`
+	Subsection         *string  `json:"subsection,omitempty"`
+	Complex            *string  `json:"complex,omitempty"`
+	Parent1            *string  `json:"parent1,omitempty"`
+	Parent2            *string  `json:"parent2,omitempty"`
+	Synonyms           []string `json:"synonyms,omitempty"`
+	Visibility         string   `json:"visibility,omitempty"` // draft or published; empty keeps the current value
+
+	// ExternalLinks replaces the entry's links; nil keeps them and an empty list clears them
+	ExternalLinks []ExternalLink `json:"external_links"`
+}
+
+// ListSpecies retrieves a paginated list of species.
+func (c *Client) ListSpecies(ctx context.Context, params *SpeciesListParams) (*SpeciesListResponse, error) {
+	path := "/api/v1/species"
+	if params != nil {
+		query := url.Values{}
+		if params.Limit > 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset > 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+		if params.Genus != nil {
+			query.Set("genus", *params.Genus)
+		}
+		if params.Subgenus != nil {
+			query.Set("subgenus", *params.Subgenus)
+		}
+		if params.Section != nil {
+			query.Set("section", *params.Section)
+		}
+		if params.Hybrid != nil {
+			query.Set("hybrid", strconv.FormatBool(*params.Hybrid))
+		}
+		if params.SourceID != nil {
+			query.Set("source_id", strconv.FormatInt(*params.SourceID, 10))
+		}
+		if params.HasSource != nil {
+			query.Set("has_source", strconv.FormatBool(*params.HasSource))
+		}
+		if len(params.ConservationStatus) > 0 {
+			query.Set("conservation_status", strings.Join(params.ConservationStatus, ","))
+		}
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result SpeciesListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// AllSpecies iterates over every species matching params, fetching DefaultPageSize
+// at a time unless params sets a Limit; params.Offset is where it starts.
+// A failed fetch ends the iteration with its error.
+func (c *Client) AllSpecies(ctx context.Context, params *SpeciesListParams) iter.Seq2[*OakEntry, error] {
+	var p SpeciesListParams
+	if params != nil {
+		p = *params
+	}
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageSize
+	}
+	return paginate(p.Offset, func(offset int) ([]*OakEntry, Pagination, error) {
+		p.Offset = offset
+		resp, err := c.ListSpecies(ctx, &p)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		return resp.Data, resp.Pagination, nil
+	})
+}
+
+// GetSpecies retrieves a single species by name.
+func (c *Client) GetSpecies(ctx context.Context, name string) (*OakEntry, error) {
+	entry, _, err := c.GetSpeciesWithETag(ctx, name)
+	return entry, err
+}
+
+// GetSpeciesWithETag retrieves a single species by name, along with the
+// version to pass to UpdateSpeciesIfMatch.
+func (c *Client) GetSpeciesWithETag(ctx context.Context, name string) (*OakEntry, string, error) {
+	var entry OakEntry
+	etag, err := c.getWithETag(ctx, "/api/v1/species/"+url.PathEscape(name), &entry)
+	if err != nil {
+		return nil, "", err
+	}
+	return &entry, etag, nil
+}
+
+// GetSpeciesBySlug retrieves the species a page URL slug leads to: the
+// species with that slug, or the one a renamed species' old slug redirects to.
+func (c *Client) GetSpeciesBySlug(ctx context.Context, slug string) (*OakEntry, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/species/by-slug/"+url.PathEscape(slug), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var entry OakEntry
+	if err := c.parseResponse(resp, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// SearchSpecies searches for species whose names, authors, synonyms, or
+// local names match the query. It uses the unified search, whose species
+// results it returns; GET /api/v1/species/search is deprecated.
+func (c *Client) SearchSpecies(ctx context.Context, query string, limit int) (*SpeciesSearchResponse, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	path := "/api/v1/search?" + params.Encode()
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Species []*OakEntry `json:"species"`
+		Query   string      `json:"query"`
+	}
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &SpeciesSearchResponse{Data: result.Species, Query: result.Query, Count: len(result.Species)}, nil
+}
+
+// CreateSpecies creates a new species.
+func (c *Client) CreateSpecies(ctx context.Context, req *SpeciesRequest) (*OakEntry, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/species", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var entry OakEntry
+	if err := c.parseResponse(resp, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// UpdateSpecies updates an existing species.
+func (c *Client) UpdateSpecies(ctx context.Context, name string, req *SpeciesRequest) (*OakEntry, error) {
+	return c.UpdateSpeciesIfMatch(ctx, name, req, "")
+}
+
+// UpdateSpeciesIfMatch updates an existing species only if it is still the
+// version etag names, as returned by GetSpeciesWithETag.
+func (c *Client) UpdateSpeciesIfMatch(ctx context.Context, name string, req *SpeciesRequest, etag string) (*OakEntry, error) {
+	var entry OakEntry
+	if err := c.putIfMatch(ctx, "/api/v1/species/"+url.PathEscape(name), req, etag, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// DeleteSpecies deletes a species by name.
+func (c *Client) DeleteSpecies(ctx context.Context, name string) error {
+	return c.DeleteSpeciesRedirecting(ctx, name, "")
+}
+
+// DeleteSpeciesRedirecting deletes a species renamed to or merged into
+// target, so its page URL leads to target's. An empty target redirects nothing.
+func (c *Client) DeleteSpeciesRedirecting(ctx context.Context, name, target string) error {
+	path := "/api/v1/species/" + url.PathEscape(name)
+	if target != "" {
+		path += "?redirect_to=" + url.QueryEscape(target)
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
+
+// EntryToRequest converts an OakEntry to a SpeciesRequest.
+func EntryToRequest(entry *OakEntry) *SpeciesRequest {
+	return &SpeciesRequest{
+		ScientificName:     entry.ScientificName,
+		Author:             entry.Author,
+		Pronunciation:      entry.Pronunciation,
+		IsHybrid:           entry.IsHybrid,
+		ConservationStatus: entry.ConservationStatus,
+		Subgenus:           entry.Subgenus,
+		Section:            entry.Section,
+		Subsection:         entry.Subsection,
+		Complex:            entry.Complex,
+		Parent1:            entry.Parent1,
+		Parent2:            entry.Parent2,
+		Synonyms:           entry.Synonyms,
+		ExternalLinks:      entry.ExternalLinks,
+	}
+}
+
+// GetSpeciesWithSources retrieves a species along with its source data.
+func (c *Client) GetSpeciesWithSources(ctx context.Context, name string) (*OakEntry, []*SpeciesSource, error) {
+	entry, err := c.GetSpecies(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sources, err := c.ListSpeciesSources(ctx, name)
+	if err != nil {
+		return entry, nil, fmt.Errorf("failed to get species sources: %w", err)
+	}
+
+	return entry, sources, nil
+}
+
+// ListSpeciesSources retrieves all source data for a species.
+func (c *Client) ListSpeciesSources(ctx context.Context, name string) ([]*SpeciesSource, error) {
+	path := "/api/v1/species/" + url.PathEscape(name) + "/sources"
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var sources []*SpeciesSource
+	if err := c.parseResponse(resp, &sources); err != nil {
+		return nil, err
+	}
+
+	return sources, nil
+}
+
+// Measurement is a length parsed from a species' source text, such as "5-12 cm".
+type Measurement struct {
+	ScientificName string   `json:"scientific_name"`
+	SourceID       int64    `json:"source_id"`
+	Field          string   `json:"field"`
+	Text           string   `json:"text"`
+	Min            *float64 `json:"min,omitempty"` // Nil for open-ended values ("up to 30 m")
+	Max            float64  `json:"max"`
+	Unit           string   `json:"unit"`
+}
+
+// ListSpeciesMeasurements retrieves the lengths parsed from a species' source text.
+func (c *Client) ListSpeciesMeasurements(ctx context.Context, name string) ([]*Measurement, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/species/"+url.PathEscape(name)+"/measurements", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var measurements []*Measurement
+	if err := c.parseResponse(resp, &measurements); err != nil {
+		return nil, err
+	}
+
+	return measurements, nil
+}
+
+// GetSpeciesSource retrieves a specific source entry for a species.
+func (c *Client) GetSpeciesSource(ctx context.Context, name string, sourceID int64) (*SpeciesSource, error) {
+	source, _, err := c.GetSpeciesSourceWithETag(ctx, name, sourceID)
+	return source, err
+}
+
+// GetSpeciesSourceWithETag retrieves a specific source entry for a species,
+// along with the version to pass to UpdateSpeciesSourceIfMatch.
+func (c *Client) GetSpeciesSourceWithETag(ctx context.Context, name string, sourceID int64) (*SpeciesSource, string, error) {
+	path := fmt.Sprintf("/api/v1/species/%s/sources/%d", url.PathEscape(name), sourceID)
+
+	var source SpeciesSource
+	etag, err := c.getWithETag(ctx, path, &source)
+	if err != nil {
+		return nil, "", err
+	}
+	return &source, etag, nil
+}
+
+// CreateSpeciesSource creates a new source entry for a species.
+func (c *Client) CreateSpeciesSource(ctx context.Context, name string, source *SpeciesSource) (*SpeciesSource, error) {
+	path := "/api/v1/species/" + url.PathEscape(name) + "/sources"
+
+	resp, err := c.doRequest(ctx, http.MethodPost, path, source)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result SpeciesSource
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// UpdateSpeciesSource updates a source entry for a species.
+func (c *Client) UpdateSpeciesSource(ctx context.Context, name string, sourceID int64, source *SpeciesSource) (*SpeciesSource, error) {
+	return c.UpdateSpeciesSourceIfMatch(ctx, name, sourceID, source, "")
+}
+
+// UpdateSpeciesSourceIfMatch updates a source entry for a species only if it
+// is still the version etag names, as returned by GetSpeciesSourceWithETag.
+func (c *Client) UpdateSpeciesSourceIfMatch(ctx context.Context, name string, sourceID int64, source *SpeciesSource, etag string) (*SpeciesSource, error) {
+	path := fmt.Sprintf("/api/v1/species/%s/sources/%d", url.PathEscape(name), sourceID)
+
+	var result SpeciesSource
+	if err := c.putIfMatch(ctx, path, source, etag, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SetPreferredSource makes sourceID the species' only preferred source and
+// returns the species' updated source list.
+func (c *Client) SetPreferredSource(ctx context.Context, name string, sourceID int64) ([]*SpeciesSource, error) {
+	path := "/api/v1/species/" + url.PathEscape(name) + "/preferred-source"
+
+	resp, err := c.doRequest(ctx, http.MethodPut, path, map[string]int64{"source_id": sourceID})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var sources []*SpeciesSource
+	if err := c.parseResponse(resp, &sources); err != nil {
+		return nil, err
+	}
+
+	return sources, nil
+}
+
+// SetSpeciesVisibility sets a species to draft or published.
+func (c *Client) SetSpeciesVisibility(ctx context.Context, name, visibility string) (*OakEntry, error) {
+	path := "/api/v1/species/" + url.PathEscape(name) + "/visibility"
+
+	resp, err := c.doRequest(ctx, http.MethodPut, path, map[string]string{"visibility": visibility})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var entry OakEntry
+	if err := c.parseResponse(resp, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// SpeciesRevision is an earlier version of a species entry, kept when a
+// change replaced it.
+type SpeciesRevision struct {
+	ID             int64     `json:"id"`
+	ScientificName string    `json:"scientific_name"`
+	Entry          *OakEntry `json:"entry"`
+	CreatedAt      time.Time `json:"created_at"` // When the change replaced it
+}
+
+// ListSpeciesRevisions returns a species' earlier versions, newest first.
+// A deleted species keeps its revisions. Requires an API key.
+func (c *Client) ListSpeciesRevisions(ctx context.Context, name string) ([]*SpeciesRevision, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/species/"+url.PathEscape(name)+"/revisions", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Revisions []*SpeciesRevision `json:"revisions"`
+	}
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Revisions, nil
+}
+
+// RestoreSpeciesRevision saves an earlier version of a species over the
+// current one, keeping the current one as a revision, and returns the
+// restored entry. Its hybrids list and visibility are left as they are.
+func (c *Client) RestoreSpeciesRevision(ctx context.Context, name string, id int64) (*OakEntry, error) {
+	path := fmt.Sprintf("/api/v1/species/%s/revisions/%d/restore", url.PathEscape(name), id)
+
+	resp, err := c.doRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var entry OakEntry
+	if err := c.parseResponse(resp, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// TimelineEvent is one thing that happened to a species. Kind is edited,
+// conservation_status, published, unpublished, publication_scheduled,
+// source_added, source_verified, account_updated, feature_suggested,
+// feature_reviewed, locality_proposed, locality_reviewed, conflict_detected,
+// or conflict_resolved.
+type TimelineEvent struct {
+	At       time.Time `json:"at"`
+	Kind     string    `json:"kind"`
+	Summary  string    `json:"summary"`
+	RefID    *int64    `json:"ref_id,omitempty"`    // The revision, suggestion, locality, conflict, or publication
+	SourceID *int64    `json:"source_id,omitempty"` // The source it concerns, if any
+	Fields   []string  `json:"fields,omitempty"`    // For edits, the fields changed
+}
+
+// SpeciesTimeline returns everything recorded as happening to a species,
+// oldest first. Requires an API key.
+func (c *Client) SpeciesTimeline(ctx context.Context, name string) ([]*TimelineEvent, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/species/"+url.PathEscape(name)+"/timeline", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Events []*TimelineEvent `json:"events"`
+	}
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Events, nil
+}
+
+// ContentHashHeader is sent with conditional species-source writes.
+const ContentHashHeader = "X-Content-Hash"
+
+// SpeciesSourceContentHash returns a stable hash of a species-source's content,
+// for use with UpsertSpeciesSource.
+func SpeciesSourceContentHash(source *SpeciesSource) (string, error) {
+	data, err := json.Marshal(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal species source: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// UpsertSpeciesSource creates or updates a species' entry for source.SourceID,
+// sending contentHash so the server can skip the write when the stored data was
+// written with the same hash. Returns unchanged=true (and a nil result) when skipped.
+func (c *Client) UpsertSpeciesSource(ctx context.Context, name string, source *SpeciesSource, contentHash string) (result *SpeciesSource, unchanged bool, err error) {
+	headers := http.Header{}
+	headers.Set(ContentHashHeader, contentHash)
+
+	path := fmt.Sprintf("/api/v1/species/%s/sources/%d", url.PathEscape(name), source.SourceID)
+	resp, err := c.doRequestWithHeaders(ctx, http.MethodPut, path, source, headers)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// No entry yet for this source; create it
+		resp.Body.Close()
+		resp, err = c.doRequestWithHeaders(ctx, http.MethodPost, "/api/v1/species/"+url.PathEscape(name)+"/sources", source, headers)
+		if err != nil {
+			return nil, false, err
+		}
+		defer resp.Body.Close()
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, true, nil
+	}
+
+	result = &SpeciesSource{}
+	if err := c.parseResponse(resp, result); err != nil {
+		return nil, false, err
+	}
+	return result, false, nil
+}
+
+// DeleteSpeciesSource deletes a source entry for a species.
+func (c *Client) DeleteSpeciesSource(ctx context.Context, name string, sourceID int64) error {
+	path := fmt.Sprintf("/api/v1/species/%s/sources/%d", url.PathEscape(name), sourceID)
+
+	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
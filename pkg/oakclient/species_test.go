@@ -1,4 +1,4 @@
-package client
+package oakclient
 
 import (
 	"encoding/json"
@@ -29,7 +29,7 @@ func TestListSpecies_Success(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	resp, err := c.ListSpecies(nil)
+	resp, err := c.ListSpecies(t.Context(), nil)
 	if err != nil {
 		t.Fatalf("ListSpecies() error = %v", err)
 	}
@@ -58,6 +58,12 @@ func TestListSpecies_WithParams(t *testing.T) {
 		if q.Get("hybrid") != "true" {
 			t.Errorf("hybrid = %s, want true", q.Get("hybrid"))
 		}
+		if q.Get("source_id") != "12" {
+			t.Errorf("source_id = %s, want 12", q.Get("source_id"))
+		}
+		if q.Get("has_source") != "false" {
+			t.Errorf("has_source = %s, want false", q.Get("has_source"))
+		}
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(SpeciesListResponse{
@@ -70,11 +76,15 @@ func TestListSpecies_WithParams(t *testing.T) {
 	c := newTestClient(t, server)
 	subgenus := "Quercus"
 	hybrid := true
-	_, err := c.ListSpecies(&SpeciesListParams{
-		Limit:    10,
-		Offset:   20,
-		Subgenus: &subgenus,
-		Hybrid:   &hybrid,
+	sourceID := int64(12)
+	hasSource := false
+	_, err := c.ListSpecies(t.Context(), &SpeciesListParams{
+		Limit:     10,
+		Offset:    20,
+		Subgenus:  &subgenus,
+		Hybrid:    &hybrid,
+		SourceID:  &sourceID,
+		HasSource: &hasSource,
 	})
 	if err != nil {
 		t.Fatalf("ListSpecies() error = %v", err)
@@ -99,7 +109,7 @@ func TestGetSpecies_Success(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	entry, err := c.GetSpecies("alba")
+	entry, err := c.GetSpecies(t.Context(), "alba")
 	if err != nil {
 		t.Fatalf("GetSpecies() error = %v", err)
 	}
@@ -116,7 +126,7 @@ func TestGetSpecies_NotFound(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	_, err := c.GetSpecies("nonexistent")
+	_, err := c.GetSpecies(t.Context(), "nonexistent")
 	if err == nil {
 		t.Fatal("expected error for not found species")
 	}
@@ -127,8 +137,8 @@ func TestGetSpecies_NotFound(t *testing.T) {
 
 func TestSearchSpecies_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			t.Errorf("method = %s, want GET", r.Method)
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/search" {
+			t.Errorf("request = %s %s, want GET /api/v1/search", r.Method, r.URL.Path)
 		}
 		if r.URL.Query().Get("q") != "alba" {
 			t.Errorf("query = %s, want alba", r.URL.Query().Get("q"))
@@ -138,22 +148,18 @@ func TestSearchSpecies_Success(t *testing.T) {
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(SpeciesSearchResponse{
-			Data:  []*OakEntry{{ScientificName: "alba"}},
-			Query: "alba",
-			Count: 1,
-		})
+		w.Write([]byte(`{"species":[{"scientific_name":"alba"}],"taxa":[],"sources":[],"query":"alba","counts":{"species":1,"total":1}}`))
 	}))
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	resp, err := c.SearchSpecies("alba", 5)
+	resp, err := c.SearchSpecies(t.Context(), "alba", 5)
 	if err != nil {
 		t.Fatalf("SearchSpecies() error = %v", err)
 	}
 
-	if resp.Count != 1 {
-		t.Errorf("Count = %d, want 1", resp.Count)
+	if resp.Count != 1 || len(resp.Data) != 1 || resp.Data[0].ScientificName != "alba" {
+		t.Errorf("response = %+v, want alba", resp)
 	}
 	if resp.Query != "alba" {
 		t.Errorf("Query = %s, want alba", resp.Query)
@@ -187,7 +193,7 @@ func TestCreateSpecies_Success(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	entry, err := c.CreateSpecies(&SpeciesRequest{
+	entry, err := c.CreateSpecies(t.Context(), &SpeciesRequest{
 		ScientificName: "newspecies",
 		IsHybrid:       false,
 	})
@@ -212,7 +218,7 @@ func TestCreateSpecies_Conflict(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	_, err := c.CreateSpecies(&SpeciesRequest{ScientificName: "existing"})
+	_, err := c.CreateSpecies(t.Context(), &SpeciesRequest{ScientificName: "existing"})
 	if err == nil {
 		t.Fatal("expected error for conflict")
 	}
@@ -239,7 +245,7 @@ func TestUpdateSpecies_Success(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	entry, err := c.UpdateSpecies("alba", &SpeciesRequest{ScientificName: "alba"})
+	entry, err := c.UpdateSpecies(t.Context(), "alba", &SpeciesRequest{ScientificName: "alba"})
 	if err != nil {
 		t.Fatalf("UpdateSpecies() error = %v", err)
 	}
@@ -262,12 +268,51 @@ func TestDeleteSpecies_Success(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	err := c.DeleteSpecies("alba")
+	err := c.DeleteSpecies(t.Context(), "alba")
 	if err != nil {
 		t.Fatalf("DeleteSpecies() error = %v", err)
 	}
 }
 
+func TestDeleteSpeciesRedirecting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/api/v1/species/alba" || r.URL.Query().Get("redirect_to") != "× bebbiana" {
+			t.Errorf("request = %s %s, want DELETE /api/v1/species/alba?redirect_to=× bebbiana", r.Method, r.URL)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	if err := c.DeleteSpeciesRedirecting(t.Context(), "alba", "× bebbiana"); err != nil {
+		t.Fatalf("DeleteSpeciesRedirecting() error = %v", err)
+	}
+}
+
+func TestGetSpeciesBySlug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/species/by-slug/alba" {
+			http.Redirect(w, r, "/api/v1/species/by-slug/x-bebbiana", http.StatusMovedPermanently)
+			return
+		}
+		if r.URL.Path != "/api/v1/species/by-slug/x-bebbiana" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(OakEntry{ScientificName: "× bebbiana", Slug: "x-bebbiana"})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	entry, err := c.GetSpeciesBySlug(t.Context(), "alba")
+	if err != nil {
+		t.Fatalf("GetSpeciesBySlug() error = %v", err)
+	}
+	if entry.ScientificName != "× bebbiana" || entry.Slug != "x-bebbiana" {
+		t.Errorf("entry = %+v, want the species alba redirects to", entry)
+	}
+}
+
 func TestDeleteSpecies_NotFound(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
@@ -275,7 +320,7 @@ func TestDeleteSpecies_NotFound(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	err := c.DeleteSpecies("nonexistent")
+	err := c.DeleteSpecies(t.Context(), "nonexistent")
 	if err == nil {
 		t.Fatal("expected error for not found species")
 	}
@@ -299,7 +344,7 @@ func TestListSpeciesSources_Success(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	sources, err := c.ListSpeciesSources("alba")
+	sources, err := c.ListSpeciesSources(t.Context(), "alba")
 	if err != nil {
 		t.Fatalf("ListSpeciesSources() error = %v", err)
 	}
@@ -325,7 +370,7 @@ func TestGetSpeciesSource_Success(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	source, err := c.GetSpeciesSource("alba", 1)
+	source, err := c.GetSpeciesSource(t.Context(), "alba", 1)
 	if err != nil {
 		t.Fatalf("GetSpeciesSource() error = %v", err)
 	}
@@ -355,7 +400,7 @@ func TestCreateSpeciesSource_Success(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	source, err := c.CreateSpeciesSource("alba", &SpeciesSource{SourceID: 2})
+	source, err := c.CreateSpeciesSource(t.Context(), "alba", &SpeciesSource{SourceID: 2})
 	if err != nil {
 		t.Fatalf("CreateSpeciesSource() error = %v", err)
 	}
@@ -384,7 +429,7 @@ func TestUpdateSpeciesSource_Success(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	source, err := c.UpdateSpeciesSource("alba", 1, &SpeciesSource{SourceID: 1})
+	source, err := c.UpdateSpeciesSource(t.Context(), "alba", 1, &SpeciesSource{SourceID: 1})
 	if err != nil {
 		t.Fatalf("UpdateSpeciesSource() error = %v", err)
 	}
@@ -394,6 +439,172 @@ func TestUpdateSpeciesSource_Success(t *testing.T) {
 	}
 }
 
+func TestUpdateSpeciesSourceIfMatch(t *testing.T) {
+	current := `"v2"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", current)
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(SpeciesSource{ScientificName: "alba", SourceID: 1})
+		case http.MethodPut:
+			if r.Header.Get("If-Match") != current {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			json.NewEncoder(w).Encode(SpeciesSource{ScientificName: "alba", SourceID: 1})
+		}
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	_, etag, err := c.GetSpeciesSourceWithETag(t.Context(), "alba", 1)
+	if err != nil {
+		t.Fatalf("GetSpeciesSourceWithETag() error = %v", err)
+	}
+	if etag != current {
+		t.Errorf("etag = %s, want %s", etag, current)
+	}
+
+	if _, err := c.UpdateSpeciesSourceIfMatch(t.Context(), "alba", 1, &SpeciesSource{SourceID: 1}, etag); err != nil {
+		t.Errorf("UpdateSpeciesSourceIfMatch(current) error = %v", err)
+	}
+
+	current = `"v3"` // Saved by someone else in between
+	_, err = c.UpdateSpeciesSourceIfMatch(t.Context(), "alba", 1, &SpeciesSource{SourceID: 1}, etag)
+	if !IsPreconditionFailedError(err) {
+		t.Errorf("UpdateSpeciesSourceIfMatch(stale) error = %v, want precondition failed", err)
+	}
+	if IsConflictError(err) {
+		t.Error("412 should not be reported as a 409 conflict")
+	}
+}
+
+func TestSetPreferredSource_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("method = %s, want PUT", r.Method)
+		}
+		if r.URL.Path != "/api/v1/species/alba/preferred-source" {
+			t.Errorf("path = %s, want /api/v1/species/alba/preferred-source", r.URL.Path)
+		}
+		var body map[string]int64
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["source_id"] != 2 {
+			t.Errorf("body = %v, err = %v, want source_id 2", body, err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]SpeciesSource{
+			{ScientificName: "alba", SourceID: 2, IsPreferred: true},
+			{ScientificName: "alba", SourceID: 1},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	sources, err := c.SetPreferredSource(t.Context(), "alba", 2)
+	if err != nil {
+		t.Fatalf("SetPreferredSource() error = %v", err)
+	}
+	if len(sources) != 2 || !sources[0].IsPreferred {
+		t.Errorf("sources = %+v", sources)
+	}
+}
+
+func TestSetSpeciesVisibility(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/species/alba/visibility" {
+			t.Errorf("request = %s %s, want PUT /api/v1/species/alba/visibility", r.Method, r.URL.Path)
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["visibility"] != "published" {
+			t.Errorf("body = %v, err = %v, want visibility published", body, err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(OakEntry{ScientificName: "alba", Visibility: "published"})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	entry, err := c.SetSpeciesVisibility(t.Context(), "alba", "published")
+	if err != nil {
+		t.Fatalf("SetSpeciesVisibility() error = %v", err)
+	}
+	if entry.Visibility != "published" {
+		t.Errorf("Visibility = %q, want published", entry.Visibility)
+	}
+}
+
+func TestUpsertSpeciesSource_Unchanged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("method = %s, want PUT", r.Method)
+		}
+		if got := r.Header.Get(ContentHashHeader); got != "abc" {
+			t.Errorf("%s = %q, want abc", ContentHashHeader, got)
+		}
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	result, unchanged, err := c.UpsertSpeciesSource(t.Context(), "alba", &SpeciesSource{SourceID: 2}, "abc")
+	if err != nil {
+		t.Fatalf("UpsertSpeciesSource() error = %v", err)
+	}
+	if !unchanged || result != nil {
+		t.Errorf("got result=%v unchanged=%v, want nil, true", result, unchanged)
+	}
+}
+
+func TestUpsertSpeciesSource_CreatesWhenMissing(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if r.Header.Get(ContentHashHeader) != "abc" {
+			t.Errorf("missing %s on %s", ContentHashHeader, r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(SpeciesSource{ID: 1, ScientificName: "alba", SourceID: 2})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	result, unchanged, err := c.UpsertSpeciesSource(t.Context(), "alba", &SpeciesSource{SourceID: 2}, "abc")
+	if err != nil {
+		t.Fatalf("UpsertSpeciesSource() error = %v", err)
+	}
+	if unchanged || result == nil || result.SourceID != 2 {
+		t.Errorf("got result=%v unchanged=%v, want created entry", result, unchanged)
+	}
+	if len(methods) != 2 || methods[0] != http.MethodPut || methods[1] != http.MethodPost {
+		t.Errorf("methods = %v, want [PUT POST]", methods)
+	}
+}
+
+func TestSpeciesSourceContentHash_Stable(t *testing.T) {
+	leaves := "Lobed"
+	a, err := SpeciesSourceContentHash(&SpeciesSource{SourceID: 1, Leaves: &leaves})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := SpeciesSourceContentHash(&SpeciesSource{SourceID: 1, Leaves: &leaves})
+	other := "Entire"
+	c, _ := SpeciesSourceContentHash(&SpeciesSource{SourceID: 1, Leaves: &other})
+	if a != b {
+		t.Errorf("hash not stable: %s != %s", a, b)
+	}
+	if a == c {
+		t.Error("different content produced the same hash")
+	}
+}
+
 func TestDeleteSpeciesSource_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodDelete {
@@ -407,7 +618,7 @@ func TestDeleteSpeciesSource_Success(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	err := c.DeleteSpeciesSource("alba", 1)
+	err := c.DeleteSpeciesSource(t.Context(), "alba", 1)
 	if err != nil {
 		t.Fatalf("DeleteSpeciesSource() error = %v", err)
 	}
@@ -431,7 +642,7 @@ func TestGetSpeciesWithSources_Success(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	entry, sources, err := c.GetSpeciesWithSources("alba")
+	entry, sources, err := c.GetSpeciesWithSources(t.Context(), "alba")
 	if err != nil {
 		t.Fatalf("GetSpeciesWithSources() error = %v", err)
 	}
@@ -491,7 +702,7 @@ func TestSpecies_URLEscaping(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	entry, err := c.GetSpecies("×bebbiana")
+	entry, err := c.GetSpecies(t.Context(), "×bebbiana")
 	if err != nil {
 		t.Fatalf("GetSpecies() error = %v", err)
 	}
@@ -500,3 +711,76 @@ func TestSpecies_URLEscaping(t *testing.T) {
 		t.Errorf("ScientificName = %s, want '×bebbiana'", entry.ScientificName)
 	}
 }
+
+func TestListSpeciesMeasurements(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/species/alba/measurements" {
+			t.Errorf("path = %s, want /api/v1/species/alba/measurements", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"scientific_name":"alba","source_id":1,"field":"leaves","text":"10-20 cm","min":10,"max":20,"unit":"cm"}]`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	measurements, err := c.ListSpeciesMeasurements(t.Context(), "alba")
+	if err != nil {
+		t.Fatalf("ListSpeciesMeasurements() error = %v", err)
+	}
+	if len(measurements) != 1 || measurements[0].Min == nil || *measurements[0].Min != 10 || measurements[0].Unit != "cm" {
+		t.Errorf("measurements = %+v", measurements)
+	}
+}
+
+func TestSpeciesRevisions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/species/alba/revisions":
+			w.Write([]byte(`{"revisions":[{"id":3,"scientific_name":"alba","entry":{"scientific_name":"alba","author":"L."},"created_at":"2026-10-01T12:00:00Z"}]}`))
+		case "POST /api/v1/species/alba/revisions/3/restore":
+			json.NewEncoder(w).Encode(OakEntry{ScientificName: "alba"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	revisions, err := c.ListSpeciesRevisions(t.Context(), "alba")
+	if err != nil {
+		t.Fatalf("ListSpeciesRevisions() error = %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].ID != 3 || *revisions[0].Entry.Author != "L." || revisions[0].CreatedAt.IsZero() {
+		t.Errorf("revisions = %+v", revisions)
+	}
+
+	entry, err := c.RestoreSpeciesRevision(t.Context(), "alba", 3)
+	if err != nil {
+		t.Fatalf("RestoreSpeciesRevision() error = %v", err)
+	}
+	if entry.ScientificName != "alba" {
+		t.Errorf("ScientificName = %q, want alba", entry.ScientificName)
+	}
+}
+
+func TestSpeciesTimeline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/species/alba/timeline" {
+			t.Errorf("request = %s %s, want GET /api/v1/species/alba/timeline", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"events":[{"at":"2026-10-01T12:00:00Z","kind":"edited","summary":"Edited author","ref_id":3,"fields":["author"]}]}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	events, err := c.SpeciesTimeline(t.Context(), "alba")
+	if err != nil {
+		t.Fatalf("SpeciesTimeline() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Kind != "edited" || *events[0].RefID != 3 || len(events[0].Fields) != 1 {
+		t.Errorf("events = %+v", events)
+	}
+}
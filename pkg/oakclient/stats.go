@@ -0,0 +1,52 @@
+package oakclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// SpeciesViews is a species' public page views over a period.
+type SpeciesViews struct {
+	ScientificName string `json:"scientific_name"`
+	Views          int    `json:"views"`
+	LastViewed     string `json:"last_viewed"` // UTC day, e.g. "2026-10-16"
+}
+
+// PopularSpeciesResponse lists the most-viewed species since a day.
+type PopularSpeciesResponse struct {
+	AnalyticsEnabled bool            `json:"analytics_enabled"`
+	Since            string          `json:"since"`
+	Days             int             `json:"days"`
+	Data             []*SpeciesViews `json:"data"`
+}
+
+// PopularSpecies returns the most-viewed species over the last days days
+// (0 for the server default), up to limit species (0 for the server default).
+func (c *Client) PopularSpecies(ctx context.Context, days, limit int) (*PopularSpeciesResponse, error) {
+	query := url.Values{}
+	if days > 0 {
+		query.Set("days", strconv.Itoa(days))
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	path := "/api/v1/stats/popular"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result PopularSpeciesResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,33 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPopularSpecies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/stats/popular" || r.URL.RawQuery != "days=7&limit=5" {
+			t.Errorf("request = %s?%s, want /api/v1/stats/popular?days=7&limit=5", r.URL.Path, r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(PopularSpeciesResponse{
+			AnalyticsEnabled: true,
+			Since:            "2026-10-10",
+			Days:             7,
+			Data:             []*SpeciesViews{{ScientificName: "alba", Views: 12, LastViewed: "2026-10-16"}},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	popular, err := c.PopularSpecies(t.Context(), 7, 5)
+	if err != nil {
+		t.Fatalf("PopularSpecies() error = %v", err)
+	}
+	if !popular.AnalyticsEnabled || len(popular.Data) != 1 || popular.Data[0].Views != 12 {
+		t.Errorf("popular = %+v", popular)
+	}
+}
@@ -0,0 +1,173 @@
+package oakclient
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// TaxonRequest represents the request body for creating/updating a taxon.
+type TaxonRequest struct {
+	Name   string      `json:"name"`
+	Level  TaxonLevel  `json:"level"`
+	Genus  string      `json:"genus,omitempty"` // Quercus if empty
+	Parent *string     `json:"parent,omitempty"`
+	Author *string     `json:"author,omitempty"`
+	Notes  *string     `json:"notes,omitempty"`
+	Links  []TaxonLink `json:"links"` // Left as is when null; an empty list clears
+}
+
+// TaxaListParams contains parameters for listing taxa.
+type TaxaListParams struct {
+	Level  *TaxonLevel
+	Parent *string
+	Limit  int // 0 returns every matching taxon
+	Offset int
+}
+
+// TaxaListResponse contains the list of taxa.
+type TaxaListResponse struct {
+	Data       []*Taxon   `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// ListTaxa retrieves taxa, optionally filtered and paged.
+func (c *Client) ListTaxa(ctx context.Context, params *TaxaListParams) (*TaxaListResponse, error) {
+	path := "/api/v1/taxa"
+	if params != nil {
+		query := url.Values{}
+		if params.Level != nil {
+			query.Set("level", string(*params.Level))
+		}
+		if params.Parent != nil {
+			query.Set("parent", *params.Parent)
+		}
+		if params.Limit > 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset > 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result TaxaListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// AllTaxa iterates over every taxon matching params, fetching DefaultPageSize
+// at a time unless params sets a Limit; params.Offset is where it starts.
+// A failed fetch ends the iteration with its error.
+func (c *Client) AllTaxa(ctx context.Context, params *TaxaListParams) iter.Seq2[*Taxon, error] {
+	var p TaxaListParams
+	if params != nil {
+		p = *params
+	}
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageSize
+	}
+	return paginate(p.Offset, func(offset int) ([]*Taxon, Pagination, error) {
+		p.Offset = offset
+		resp, err := c.ListTaxa(ctx, &p)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		return resp.Data, resp.Pagination, nil
+	})
+}
+
+// GetTaxon retrieves a single taxon by level and name.
+func (c *Client) GetTaxon(ctx context.Context, level TaxonLevel, name string) (*Taxon, error) {
+	taxon, _, err := c.GetTaxonWithETag(ctx, level, name)
+	return taxon, err
+}
+
+// GetTaxonWithETag retrieves a single taxon by level and name, along with
+// the version to pass to UpdateTaxonIfMatch.
+func (c *Client) GetTaxonWithETag(ctx context.Context, level TaxonLevel, name string) (*Taxon, string, error) {
+	path := "/api/v1/taxa/" + url.PathEscape(string(level)) + "/" + url.PathEscape(name)
+
+	var taxon Taxon
+	etag, err := c.getWithETag(ctx, path, &taxon)
+	if err != nil {
+		return nil, "", err
+	}
+	return &taxon, etag, nil
+}
+
+// CreateTaxon creates a new taxon.
+func (c *Client) CreateTaxon(ctx context.Context, req *TaxonRequest) (*Taxon, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/taxa", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var taxon Taxon
+	if err := c.parseResponse(resp, &taxon); err != nil {
+		return nil, err
+	}
+
+	return &taxon, nil
+}
+
+// UpdateTaxon updates an existing taxon.
+func (c *Client) UpdateTaxon(ctx context.Context, level TaxonLevel, name string, req *TaxonRequest) (*Taxon, error) {
+	return c.UpdateTaxonIfMatch(ctx, level, name, req, "")
+}
+
+// UpdateTaxonIfMatch updates an existing taxon only if it is still the
+// version etag names, as returned by GetTaxonWithETag.
+func (c *Client) UpdateTaxonIfMatch(ctx context.Context, level TaxonLevel, name string, req *TaxonRequest, etag string) (*Taxon, error) {
+	path := "/api/v1/taxa/" + url.PathEscape(string(level)) + "/" + url.PathEscape(name)
+
+	var taxon Taxon
+	if err := c.putIfMatch(ctx, path, req, etag, &taxon); err != nil {
+		return nil, err
+	}
+	return &taxon, nil
+}
+
+// DeleteTaxon deletes a taxon by level and name.
+func (c *Client) DeleteTaxon(ctx context.Context, level TaxonLevel, name string) error {
+	path := "/api/v1/taxa/" + url.PathEscape(string(level)) + "/" + url.PathEscape(name)
+
+	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
+
+// TaxonToRequest converts a Taxon to a TaxonRequest.
+func TaxonToRequest(taxon *Taxon) *TaxonRequest {
+	return &TaxonRequest{
+		Name:   taxon.Name,
+		Level:  taxon.Level,
+		Genus:  taxon.Genus,
+		Parent: taxon.Parent,
+		Author: taxon.Author,
+		Notes:  taxon.Notes,
+		Links:  taxon.Links,
+	}
+}
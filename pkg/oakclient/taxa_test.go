@@ -1,4 +1,4 @@
-package client
+package oakclient
 
 import (
 	"encoding/json"
@@ -29,7 +29,7 @@ func TestListTaxa_Success(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	resp, err := c.ListTaxa(nil)
+	resp, err := c.ListTaxa(t.Context(), nil)
 	if err != nil {
 		t.Fatalf("ListTaxa() error = %v", err)
 	}
@@ -56,7 +56,7 @@ func TestListTaxa_WithLevelFilter(t *testing.T) {
 
 	c := newTestClient(t, server)
 	level := TaxonLevelSection
-	resp, err := c.ListTaxa(&level)
+	resp, err := c.ListTaxa(t.Context(), &TaxaListParams{Level: &level})
 	if err != nil {
 		t.Fatalf("ListTaxa() error = %v", err)
 	}
@@ -86,7 +86,7 @@ func TestGetTaxon_Success(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	taxon, err := c.GetTaxon(TaxonLevelSection, "Lobatae")
+	taxon, err := c.GetTaxon(t.Context(), TaxonLevelSection, "Lobatae")
 	if err != nil {
 		t.Fatalf("GetTaxon() error = %v", err)
 	}
@@ -106,7 +106,7 @@ func TestGetTaxon_NotFound(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	_, err := c.GetTaxon(TaxonLevelSection, "Nonexistent")
+	_, err := c.GetTaxon(t.Context(), TaxonLevelSection, "Nonexistent")
 	if err == nil {
 		t.Fatal("expected error for not found taxon")
 	}
@@ -147,7 +147,7 @@ func TestCreateTaxon_Success(t *testing.T) {
 
 	c := newTestClient(t, server)
 	parent := "Quercus"
-	taxon, err := c.CreateTaxon(&TaxonRequest{
+	taxon, err := c.CreateTaxon(t.Context(), &TaxonRequest{
 		Name:   "Virentes",
 		Level:  TaxonLevelSection,
 		Parent: &parent,
@@ -182,7 +182,7 @@ func TestUpdateTaxon_Success(t *testing.T) {
 
 	c := newTestClient(t, server)
 	notes := "Updated notes"
-	taxon, err := c.UpdateTaxon(TaxonLevelSection, "Lobatae", &TaxonRequest{
+	taxon, err := c.UpdateTaxon(t.Context(), TaxonLevelSection, "Lobatae", &TaxonRequest{
 		Name:  "Lobatae",
 		Level: TaxonLevelSection,
 		Notes: &notes,
@@ -209,7 +209,7 @@ func TestDeleteTaxon_Success(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	err := c.DeleteTaxon(TaxonLevelSubsection, "Test")
+	err := c.DeleteTaxon(t.Context(), TaxonLevelSubsection, "Test")
 	if err != nil {
 		t.Fatalf("DeleteTaxon() error = %v", err)
 	}
@@ -222,7 +222,7 @@ func TestDeleteTaxon_NotFound(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	err := c.DeleteTaxon(TaxonLevelSection, "Nonexistent")
+	err := c.DeleteTaxon(t.Context(), TaxonLevelSection, "Nonexistent")
 	if err == nil {
 		t.Fatal("expected error for not found taxon")
 	}
@@ -279,7 +279,7 @@ func TestTaxa_URLEscaping(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(t, server)
-	taxon, err := c.GetTaxon(TaxonLevelComplex, "Red Oaks")
+	taxon, err := c.GetTaxon(t.Context(), TaxonLevelComplex, "Red Oaks")
 	if err != nil {
 		t.Fatalf("GetTaxon() error = %v", err)
 	}
@@ -0,0 +1,67 @@
+package oakclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// TaxonLevelRequest represents the request body for creating/updating a taxon level.
+type TaxonLevelRequest struct {
+	Rank       int     `json:"rank"`
+	Plural     *string `json:"plural,omitempty"`
+	EntryField *string `json:"entry_field,omitempty"`
+}
+
+// TaxonLevelsListResponse contains the list of taxon levels.
+type TaxonLevelsListResponse struct {
+	Data       []*TaxonLevelDef `json:"data"`
+	Pagination Pagination       `json:"pagination"`
+}
+
+// ListTaxonLevels retrieves the configured taxon levels ordered by rank.
+func (c *Client) ListTaxonLevels(ctx context.Context) ([]*TaxonLevelDef, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/taxon-levels", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result TaxonLevelsListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// SaveTaxonLevel creates or replaces a taxon level.
+func (c *Client) SaveTaxonLevel(ctx context.Context, name string, req *TaxonLevelRequest) (*TaxonLevelDef, error) {
+	resp, err := c.doRequest(ctx, http.MethodPut, "/api/v1/taxon-levels/"+url.PathEscape(name), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var level TaxonLevelDef
+	if err := c.parseResponse(resp, &level); err != nil {
+		return nil, err
+	}
+
+	return &level, nil
+}
+
+// DeleteTaxonLevel deletes a taxon level. The API refuses while taxa use it.
+func (c *Client) DeleteTaxonLevel(ctx context.Context, name string) error {
+	resp, err := c.doRequest(ctx, http.MethodDelete, "/api/v1/taxon-levels/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
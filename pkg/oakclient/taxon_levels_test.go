@@ -0,0 +1,58 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListTaxonLevels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/taxon-levels" {
+			t.Errorf("request = %s %s, want GET /api/v1/taxon-levels", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TaxonLevelsListResponse{
+			Data: []*TaxonLevelDef{
+				{Name: "subgenus", Rank: 1},
+				{Name: "series", Rank: 5, TaxaCount: 2},
+			},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	levels, err := c.ListTaxonLevels(t.Context())
+	if err != nil {
+		t.Fatalf("ListTaxonLevels() error = %v", err)
+	}
+	if len(levels) != 2 || levels[1].Name != "series" || levels[1].TaxaCount != 2 {
+		t.Errorf("levels = %+v", levels)
+	}
+}
+
+func TestSaveTaxonLevel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/taxon-levels/series" {
+			t.Errorf("request = %s %s, want PUT /api/v1/taxon-levels/series", r.Method, r.URL.Path)
+		}
+		var req TaxonLevelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(TaxonLevelDef{Name: "series", Rank: req.Rank})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	level, err := c.SaveTaxonLevel(t.Context(), "series", &TaxonLevelRequest{Rank: 5})
+	if err != nil {
+		t.Fatalf("SaveTaxonLevel() error = %v", err)
+	}
+	if level.Rank != 5 {
+		t.Errorf("rank = %d, want 5", level.Rank)
+	}
+}
@@ -0,0 +1,88 @@
+package oakclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// TemplateRequest represents the request body for creating/replacing a template.
+type TemplateRequest struct {
+	Description    *string  `json:"description,omitempty"`
+	Genus          string   `json:"genus,omitempty"`
+	Subgenus       *string  `json:"subgenus,omitempty"`
+	Section        *string  `json:"section,omitempty"`
+	Subsection     *string  `json:"subsection,omitempty"`
+	Complex        *string  `json:"complex,omitempty"`
+	SourceIDs      []int64  `json:"source_ids,omitempty"`
+	RequiredFields []string `json:"required_fields,omitempty"`
+}
+
+// TemplatesListResponse contains the list of templates.
+type TemplatesListResponse struct {
+	Data       []*Template `json:"data"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+// ListTemplates retrieves every entry template.
+func (c *Client) ListTemplates(ctx context.Context) ([]*Template, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/templates", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result TemplatesListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// GetTemplate retrieves an entry template by name.
+func (c *Client) GetTemplate(ctx context.Context, name string) (*Template, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/templates/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var tmpl Template
+	if err := c.parseResponse(resp, &tmpl); err != nil {
+		return nil, err
+	}
+
+	return &tmpl, nil
+}
+
+// SaveTemplate creates or replaces an entry template.
+func (c *Client) SaveTemplate(ctx context.Context, name string, req *TemplateRequest) (*Template, error) {
+	resp, err := c.doRequest(ctx, http.MethodPut, "/api/v1/templates/"+url.PathEscape(name), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var tmpl Template
+	if err := c.parseResponse(resp, &tmpl); err != nil {
+		return nil, err
+	}
+
+	return &tmpl, nil
+}
+
+// DeleteTemplate deletes an entry template.
+func (c *Client) DeleteTemplate(ctx context.Context, name string) error {
+	resp, err := c.doRequest(ctx, http.MethodDelete, "/api/v1/templates/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetTemplate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/templates/lobatae" {
+			t.Errorf("request = %s %s, want GET /api/v1/templates/lobatae", r.Method, r.URL.Path)
+		}
+		section := "Lobatae"
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Template{
+			Name:           "lobatae",
+			Genus:          "Quercus",
+			Section:        &section,
+			SourceIDs:      []int64{3},
+			RequiredFields: []string{"author"},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	tmpl, err := c.GetTemplate(t.Context(), "lobatae")
+	if err != nil {
+		t.Fatalf("GetTemplate() error = %v", err)
+	}
+	if tmpl.Section == nil || *tmpl.Section != "Lobatae" || len(tmpl.SourceIDs) != 1 || tmpl.RequiredFields[0] != "author" {
+		t.Errorf("template = %+v", tmpl)
+	}
+}
+
+func TestGetTemplate_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": map[string]string{"code": "NOT_FOUND", "message": "Template not found: quercus"},
+		})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	if _, err := c.GetTemplate(t.Context(), "quercus"); !IsNotFoundError(err) {
+		t.Errorf("GetTemplate() error = %v, want not found", err)
+	}
+}
+
+func TestSaveTemplate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/templates/albae" {
+			t.Errorf("request = %s %s, want PUT /api/v1/templates/albae", r.Method, r.URL.Path)
+		}
+		var req TemplateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(Template{Name: "albae", Genus: "Quercus", Subsection: req.Subsection})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	subsection := "Albae"
+	tmpl, err := c.SaveTemplate(t.Context(), "albae", &TemplateRequest{Subsection: &subsection})
+	if err != nil {
+		t.Fatalf("SaveTemplate() error = %v", err)
+	}
+	if tmpl.Subsection == nil || *tmpl.Subsection != "Albae" {
+		t.Errorf("template = %+v", tmpl)
+	}
+}
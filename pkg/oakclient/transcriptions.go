@@ -0,0 +1,95 @@
+package oakclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Transcription is the provenance of a species source's transcribed text.
+type Transcription struct {
+	ScientificName      string     `json:"scientific_name"`
+	SourceID            int64      `json:"source_id"`
+	SourceName          string     `json:"source_name"`
+	Pages               *string    `json:"pages,omitempty"`
+	Transcriber         *string    `json:"transcriber,omitempty"`
+	TranscriptionMethod string     `json:"transcription_method"` // manual, ocr, or import
+	OCRConfidence       *float64   `json:"ocr_confidence,omitempty"`
+	Verified            bool       `json:"verified"`
+	VerifiedAt          *time.Time `json:"verified_at,omitempty"`
+	VerifiedBy          *string    `json:"verified_by,omitempty"`
+}
+
+// TranscriptionsListResponse is the paginated list wrapper for transcriptions.
+type TranscriptionsListResponse struct {
+	Data []*Transcription `json:"data"`
+}
+
+// TranscriptionFilter narrows ListTranscriptions. Verified is true, false,
+// or all (empty means false); other zero values match any.
+type TranscriptionFilter struct {
+	Verified      string
+	Method        string
+	SourceID      int64
+	MaxConfidence *float64
+}
+
+// ListTranscriptions lists transcribed species sources, least confident OCR
+// first. With no filter it lists those awaiting verification.
+func (c *Client) ListTranscriptions(ctx context.Context, filter TranscriptionFilter) ([]*Transcription, error) {
+	query := url.Values{}
+	if filter.Verified != "" {
+		query.Set("verified", filter.Verified)
+	}
+	if filter.Method != "" {
+		query.Set("method", filter.Method)
+	}
+	if filter.SourceID != 0 {
+		query.Set("source_id", strconv.FormatInt(filter.SourceID, 10))
+	}
+	if filter.MaxConfidence != nil {
+		query.Set("max_confidence", strconv.FormatFloat(*filter.MaxConfidence, 'f', -1, 64))
+	}
+	path := "/api/v1/transcriptions"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result TranscriptionsListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// VerifySpeciesSource marks a species source's text as checked against the
+// original, crediting verifiedBy if it is set.
+func (c *Client) VerifySpeciesSource(ctx context.Context, name string, sourceID int64, verifiedBy string) (*SpeciesSource, error) {
+	body := map[string]string{}
+	if verifiedBy != "" {
+		body["verified_by"] = verifiedBy
+	}
+	path := fmt.Sprintf("/api/v1/species/%s/sources/%d/verify", url.PathEscape(name), sourceID)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var source SpeciesSource
+	if err := c.parseResponse(resp, &source); err != nil {
+		return nil, err
+	}
+
+	return &source, nil
+}
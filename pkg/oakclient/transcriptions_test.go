@@ -0,0 +1,51 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListTranscriptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/transcriptions" || r.URL.RawQuery != "max_confidence=0.9&method=ocr&source_id=3" {
+			t.Errorf("request = %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TranscriptionsListResponse{Data: []*Transcription{{ScientificName: "alba", SourceID: 3}}})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	threshold := 0.9
+	transcriptions, err := c.ListTranscriptions(t.Context(), TranscriptionFilter{Method: "ocr", SourceID: 3, MaxConfidence: &threshold})
+	if err != nil {
+		t.Fatalf("ListTranscriptions() error = %v", err)
+	}
+	if len(transcriptions) != 1 || transcriptions[0].ScientificName != "alba" {
+		t.Errorf("transcriptions = %+v", transcriptions)
+	}
+}
+
+func TestVerifySpeciesSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/species/× bebbiana/sources/3/verify" || req["verified_by"] != "jeff" {
+			t.Errorf("request = %s %s %+v", r.Method, r.URL.Path, req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SpeciesSource{ScientificName: "× bebbiana", SourceID: 3, Verified: true})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	ss, err := c.VerifySpeciesSource(t.Context(), "× bebbiana", 3, "jeff")
+	if err != nil {
+		t.Fatalf("VerifySpeciesSource() error = %v", err)
+	}
+	if !ss.Verified {
+		t.Errorf("species source = %+v, want verified", ss)
+	}
+}
@@ -0,0 +1,214 @@
+// Package client types mirror the API models for serialization.
+// These are separate from the CLI's internal models to avoid import cycles
+// and to allow the CLI client to work independently of the API module.
+package oakclient
+
+import "time"
+
+// TaxonLevel represents the hierarchical level of a taxon.
+type TaxonLevel string
+
+const (
+	TaxonLevelSubgenus   TaxonLevel = "subgenus"
+	TaxonLevelSection    TaxonLevel = "section"
+	TaxonLevelSubsection TaxonLevel = "subsection"
+	TaxonLevelComplex    TaxonLevel = "complex"
+)
+
+// TaxonLink represents a labeled external link for a taxon.
+type TaxonLink struct {
+	Label string `json:"label" yaml:"label"`
+	URL   string `json:"url" yaml:"url"`
+}
+
+// ExternalLink represents an external reference link for a species.
+type ExternalLink struct {
+	Name string `json:"name" yaml:"name"`
+	URL  string `json:"url" yaml:"url"`
+	Logo string `json:"logo" yaml:"logo"`
+}
+
+// Taxon represents a taxonomic rank.
+type Taxon struct {
+	Name   string      `json:"name" yaml:"name"`
+	Level  TaxonLevel  `json:"level" yaml:"level"`
+	Genus  string      `json:"genus,omitempty" yaml:"genus,omitempty"`
+	Parent *string     `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Author *string     `json:"author,omitempty" yaml:"author,omitempty"`
+	Notes  *string     `json:"notes,omitempty" yaml:"notes,omitempty"`
+	Links  []TaxonLink `json:"links,omitempty" yaml:"links,omitempty"`
+}
+
+// SpeciesSource represents source-attributed descriptive data for a species.
+type SpeciesSource struct {
+	ID                     int64    `json:"id" yaml:"id"`
+	ScientificName         string   `json:"scientific_name" yaml:"scientific_name"`
+	SourceID               int64    `json:"source_id" yaml:"source_id"`
+	LocalNames             []string `json:"local_names,omitempty" yaml:"local_names,omitempty"`
+	Range                  *string  `json:"range,omitempty" yaml:"range,omitempty"`
+	GrowthHabit            *string  `json:"growth_habit,omitempty" yaml:"growth_habit,omitempty"`
+	Leaves                 *string  `json:"leaves,omitempty" yaml:"leaves,omitempty"`
+	Flowers                *string  `json:"flowers,omitempty" yaml:"flowers,omitempty"`
+	Fruits                 *string  `json:"fruits,omitempty" yaml:"fruits,omitempty"`
+	Bark                   *string  `json:"bark,omitempty" yaml:"bark,omitempty"`
+	Twigs                  *string  `json:"twigs,omitempty" yaml:"twigs,omitempty"`
+	Buds                   *string  `json:"buds,omitempty" yaml:"buds,omitempty"`
+	HardinessHabitat       *string  `json:"hardiness_habitat,omitempty" yaml:"hardiness_habitat,omitempty"`
+	Miscellaneous          *string  `json:"miscellaneous,omitempty" yaml:"miscellaneous,omitempty"`
+	DistinguishingFeatures *string  `json:"distinguishing_features,omitempty" yaml:"distinguishing_features,omitempty"`
+	URL                    *string  `json:"url,omitempty" yaml:"url,omitempty"`
+	// Pages and FieldPages locate the data in a printed source; FieldPages is
+	// keyed by text field name, e.g. {"fruits": "pl. 23"}
+	Pages       *string           `json:"pages,omitempty" yaml:"pages,omitempty"`
+	FieldPages  map[string]string `json:"field_pages,omitempty" yaml:"field_pages,omitempty"`
+	IsPreferred bool              `json:"is_preferred" yaml:"is_preferred"`
+	// FieldVisibility lists the text fields withheld from the public, e.g.
+	// {"range": "internal"}; fields not listed are public
+	FieldVisibility map[string]string `json:"field_visibility,omitempty" yaml:"field_visibility,omitempty"`
+
+	// Transcription provenance, for text typed or OCR'd from print
+	Transcriber         *string    `json:"transcriber,omitempty" yaml:"transcriber,omitempty"`
+	TranscriptionMethod *string    `json:"transcription_method,omitempty" yaml:"transcription_method,omitempty"` // manual, ocr, or import
+	OCRConfidence       *float64   `json:"ocr_confidence,omitempty" yaml:"ocr_confidence,omitempty"`             // 0-1
+	Verified            bool       `json:"verified" yaml:"verified"`
+	VerifiedAt          *time.Time `json:"verified_at,omitempty" yaml:"verified_at,omitempty"`
+	VerifiedBy          *string    `json:"verified_by,omitempty" yaml:"verified_by,omitempty"`
+}
+
+// OakEntry represents an Oak taxonomic entry (species-intrinsic data).
+type OakEntry struct {
+	ScientificName     string  `json:"scientific_name" yaml:"scientific_name"`
+	Author             *string `json:"author,omitempty" yaml:"author,omitempty"`
+	Pronunciation      *string `json:"pronunciation,omitempty" yaml:"pronunciation,omitempty"`
+	IsHybrid           bool    `json:"is_hybrid" yaml:"is_hybrid"`
+	ConservationStatus *string `json:"conservation_status,omitempty" yaml:"conservation_status,omitempty"`
+
+	// Genus the entry belongs to (Quercus unless set)
+	Genus string `json:"genus,omitempty" yaml:"genus,omitempty"`
+
+	// Taxonomy
+	Subgenus   *string `json:"subgenus,omitempty" yaml:"subgenus,omitempty"`
+	Section    *string `json:"section,omitempty" yaml:"section,omitempty"`
+	Subsection *string `json:"subsection,omitempty" yaml:"subsection,omitempty"`
+	Complex    *string `json:"complex,omitempty" yaml:"complex,omitempty"`
+
+	// Hybrid parents
+	Parent1 *string `json:"parent1,omitempty" yaml:"parent1,omitempty"`
+	Parent2 *string `json:"parent2,omitempty" yaml:"parent2,omitempty"`
+
+	// Related species
+	Hybrids             []string `json:"hybrids,omitempty" yaml:"hybrids,omitempty"`
+	CloselyRelatedTo    []string `json:"closely_related_to,omitempty" yaml:"closely_related_to,omitempty"`
+	SubspeciesVarieties []string `json:"subspecies_varieties,omitempty" yaml:"subspecies_varieties,omitempty"`
+	Synonyms            []string `json:"synonyms,omitempty" yaml:"synonyms,omitempty"`
+
+	// External reference links
+	ExternalLinks []ExternalLink `json:"external_links,omitempty" yaml:"external_links,omitempty"`
+
+	// Visibility is "draft" or "published"
+	Visibility string `json:"visibility,omitempty" yaml:"visibility,omitempty"`
+
+	// Slug is the name's form in the species page URL, e.g. "x-bebbiana"; set by the API
+	Slug string `json:"slug,omitempty" yaml:"-"`
+
+	// DataError is set when a stored list field is corrupt; those fields come back empty
+	DataError string `json:"data_error,omitempty" yaml:"-"`
+}
+
+// Genus is a genus tracked by the database.
+type Genus struct {
+	Name         string   `json:"name" yaml:"name"`
+	CommonName   *string  `json:"common_name,omitempty" yaml:"common_name,omitempty"`
+	Subgenera    []string `json:"subgenera" yaml:"subgenera"` // Valid subgenus names; empty allows any
+	SpeciesCount int      `json:"species_count" yaml:"species_count"`
+}
+
+// TaxonLevelDef is one configured rank in the taxonomy, ordered by Rank.
+type TaxonLevelDef struct {
+	Name       string  `json:"name" yaml:"name"`
+	Rank       int     `json:"rank" yaml:"rank"`
+	Plural     *string `json:"plural,omitempty" yaml:"plural,omitempty"`
+	EntryField *string `json:"entry_field,omitempty" yaml:"entry_field,omitempty"`
+	TaxaCount  int     `json:"taxa_count" yaml:"taxa_count"`
+}
+
+// Template pre-fills new species entries for a taxonomic group.
+type Template struct {
+	Name           string   `json:"name" yaml:"name"`
+	Description    *string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Genus          string   `json:"genus" yaml:"genus"`
+	Subgenus       *string  `json:"subgenus,omitempty" yaml:"subgenus,omitempty"`
+	Section        *string  `json:"section,omitempty" yaml:"section,omitempty"`
+	Subsection     *string  `json:"subsection,omitempty" yaml:"subsection,omitempty"`
+	Complex        *string  `json:"complex,omitempty" yaml:"complex,omitempty"`
+	SourceIDs      []int64  `json:"source_ids" yaml:"source_ids"`
+	RequiredFields []string `json:"required_fields" yaml:"required_fields"`
+}
+
+// Report is a saved read-only SQL query, run on demand as CSV or JSON.
+type Report struct {
+	Name        string         `json:"name" yaml:"name"`
+	Title       string         `json:"title" yaml:"title"`
+	Description *string        `json:"description,omitempty" yaml:"description,omitempty"`
+	Query       string         `json:"query" yaml:"query"`
+	Columns     []ReportColumn `json:"columns" yaml:"columns"` // Empty for every column the query returns
+	Format      string         `json:"format" yaml:"format"`
+}
+
+// ReportColumn picks a query result column for a report, with its heading.
+type ReportColumn struct {
+	Name  string `json:"name" yaml:"name"`
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+}
+
+// ManagedVocabulary is a managed list of terms. One bound to a field
+// (VocabularyFieldConservationStatus or VocabularyFieldSourceType) restricts
+// the values writes may set.
+type ManagedVocabulary struct {
+	Name        string           `json:"name" yaml:"name"`
+	Description *string          `json:"description,omitempty" yaml:"description,omitempty"`
+	Field       *string          `json:"field,omitempty" yaml:"field,omitempty"`
+	Terms       []VocabularyTerm `json:"terms" yaml:"terms"`
+}
+
+// VocabularyTerm is one permitted value of a vocabulary and what it means.
+type VocabularyTerm struct {
+	Term       string  `json:"term" yaml:"term"`
+	Definition *string `json:"definition,omitempty" yaml:"definition,omitempty"`
+}
+
+// SpeciesAccount is a species' long-form markdown account and its rendered HTML.
+type SpeciesAccount struct {
+	ScientificName string   `json:"scientific_name" yaml:"scientific_name"`
+	Markdown       string   `json:"markdown" yaml:"markdown"`
+	HTML           string   `json:"html" yaml:"html"`
+	Links          []string `json:"links" yaml:"links"`
+	MissingLinks   []string `json:"missing_links,omitempty" yaml:"missing_links,omitempty"`
+	Images         []string `json:"images" yaml:"images"`
+	UpdatedAt      string   `json:"updated_at" yaml:"updated_at"`
+}
+
+// CrossReference records that one species' account or source notes mention another.
+type CrossReference struct {
+	ScientificName string `json:"scientific_name" yaml:"scientific_name"`
+	MentionedName  string `json:"mentioned_name" yaml:"mentioned_name"`
+	Location       string `json:"location" yaml:"location"`
+	Context        string `json:"context" yaml:"context"`
+}
+
+// Source represents a source reference.
+type Source struct {
+	ID           int64   `json:"id" yaml:"id"`
+	SourceType   string  `json:"source_type" yaml:"source_type"`
+	Name         string  `json:"name" yaml:"name"`
+	Description  *string `json:"description,omitempty" yaml:"description,omitempty"`
+	Author       *string `json:"author,omitempty" yaml:"author,omitempty"`
+	Year         *int    `json:"year,omitempty" yaml:"year,omitempty"`
+	URL          *string `json:"url,omitempty" yaml:"url,omitempty"`
+	ISBN         *string `json:"isbn,omitempty" yaml:"isbn,omitempty"`
+	DOI          *string `json:"doi,omitempty" yaml:"doi,omitempty"`
+	Notes        *string `json:"notes,omitempty" yaml:"notes,omitempty"`
+	License      *string `json:"license,omitempty" yaml:"license,omitempty"`
+	LicenseURL   *string `json:"license_url,omitempty" yaml:"license_url,omitempty"`
+	SupersededBy *int64  `json:"superseded_by,omitempty" yaml:"superseded_by,omitempty"` // Source that replaces this one
+}
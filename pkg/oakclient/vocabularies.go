@@ -0,0 +1,93 @@
+package oakclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Fields a vocabulary can be bound to
+const (
+	VocabularyFieldConservationStatus = "species.conservation_status"
+	VocabularyFieldSourceType         = "source.source_type"
+)
+
+// VocabularyRequest is the request body for creating or replacing a
+// vocabulary. Terms replace the vocabulary's terms, in order.
+type VocabularyRequest struct {
+	Description *string          `json:"description,omitempty" yaml:"description,omitempty"`
+	Field       *string          `json:"field,omitempty" yaml:"field,omitempty"`
+	Terms       []VocabularyTerm `json:"terms" yaml:"terms"`
+}
+
+// VocabulariesListResponse contains the list of vocabularies.
+type VocabulariesListResponse struct {
+	Data       []*ManagedVocabulary `json:"data"`
+	Pagination Pagination           `json:"pagination"`
+}
+
+// ListVocabularies retrieves every managed vocabulary with its terms.
+func (c *Client) ListVocabularies(ctx context.Context) ([]*ManagedVocabulary, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/vocabularies", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result VocabulariesListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// GetVocabulary retrieves a vocabulary and its terms by name.
+func (c *Client) GetVocabulary(ctx context.Context, name string) (*ManagedVocabulary, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/vocabularies/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var v ManagedVocabulary
+	if err := c.parseResponse(resp, &v); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// SaveVocabulary creates or replaces a vocabulary. The server refuses with
+// a conflict error to bind a field another vocabulary is bound to, or to
+// leave out a term that records still use.
+func (c *Client) SaveVocabulary(ctx context.Context, name string, req *VocabularyRequest) (*ManagedVocabulary, error) {
+	resp, err := c.doRequest(ctx, http.MethodPut, "/api/v1/vocabularies/"+url.PathEscape(name), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var v ManagedVocabulary
+	if err := c.parseResponse(resp, &v); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// DeleteVocabulary deletes a vocabulary. One bound to a field is refused
+// with a conflict error.
+func (c *Client) DeleteVocabulary(ctx context.Context, name string) error {
+	resp, err := c.doRequest(ctx, http.MethodDelete, "/api/v1/vocabularies/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
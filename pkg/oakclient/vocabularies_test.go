@@ -0,0 +1,48 @@
+package oakclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSaveVocabulary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/vocabularies/conservation_status" {
+			t.Errorf("request = %s %s, want PUT /api/v1/vocabularies/conservation_status", r.Method, r.URL.Path)
+		}
+		var req VocabularyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Terms) != 1 || req.Terms[0].Term != "LC" {
+			t.Errorf("body = %+v (err %v)", req, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ManagedVocabulary{Name: "conservation_status", Field: req.Field, Terms: req.Terms})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	field := VocabularyFieldConservationStatus
+	v, err := c.SaveVocabulary(t.Context(), "conservation_status", &VocabularyRequest{Field: &field, Terms: []VocabularyTerm{{Term: "LC"}}})
+	if err != nil {
+		t.Fatalf("SaveVocabulary() error = %v", err)
+	}
+	if v.Field == nil || *v.Field != field || len(v.Terms) != 1 {
+		t.Errorf("vocabulary = %+v", v)
+	}
+}
+
+func TestDeleteVocabulary_Conflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"error":{"code":"CONFLICT","message":"vocabulary source_type is bound to source.source_type"}}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server)
+	err := c.DeleteVocabulary(t.Context(), "source_type")
+	if !IsConflictError(err) {
+		t.Errorf("DeleteVocabulary() error = %v, want a conflict error", err)
+	}
+}
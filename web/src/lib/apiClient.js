@@ -242,6 +242,16 @@ export async function fetchSpeciesByName(name) {
   return fetchApi(`/api/v1/species/${encodeURIComponent(name)}`);
 }
 
+/**
+ * Resolve a species page URL: a slug, the old slug of a renamed or merged
+ * species (the API redirects it), or a species name
+ * @param {string} slug - URL slug (e.g. "x-bebbiana")
+ * @returns {Promise<Object>} Species object (basic info) with its current slug
+ */
+export async function fetchSpeciesBySlug(slug) {
+  return fetchApi(`/api/v1/species/by-slug/${encodeURIComponent(slug)}`);
+}
+
 /**
  * Fetch a single species with all source data embedded
  * @param {string} name - Species name (epithet)
@@ -262,13 +272,13 @@ export async function fetchSpeciesBySource(sourceId) {
 }
 
 /**
- * Search species by query (species-only search)
+ * Search species by query (the species results of the unified search)
  * @param {string} query - Search query
  * @returns {Promise<Array>} Matching species
  */
 export async function searchSpecies(query) {
-  const response = await fetchApi(`/api/v1/species/search?q=${encodeURIComponent(query)}`);
-  return response.data || response.species || response;
+  const response = await fetchApi(`/api/v1/search?q=${encodeURIComponent(query)}`);
+  return response.species || [];
 }
 
 /**
@@ -730,3 +740,17 @@ export async function deleteSpeciesSource(speciesName, sourceId) {
     method: 'DELETE'
   });
 }
+
+// =============================================================================
+// Admin Operations
+// =============================================================================
+
+/**
+ * Fetch API keys with their names, roles, and usage: request counts and last use
+ * @returns {Promise<Array>} Keys, the server's current key first
+ * @throws {ApiError} If not authenticated with an admin key
+ */
+export async function fetchApiKeys() {
+  const response = await fetchApiAuthenticated('/api/v1/admin/keys');
+  return response.data || [];
+}